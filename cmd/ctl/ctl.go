@@ -0,0 +1,137 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+// Package ctl provides the commands to operate the resources managed by the operator from the command line, without
+// having to know the annotations and status fields the operator relies on.
+package ctl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	agentv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/agent/v1alpha1"
+	apmv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/apm/v1"
+	beatv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/beat/v1beta1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	entv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/enterprisesearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	lsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+	emsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/maps/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/agent"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/apmserver"
+	beatcommon "github.com/elastic/cloud-on-k8s/v2/pkg/controller/beat/common"
+	eslabel "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/enterprisesearch"
+	kblabel "github.com/elastic/cloud-on-k8s/v2/pkg/controller/kibana/label"
+	lslabels "github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash/labels"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/maps"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+// resourceKind describes a kind of resource that can be operated with the ctl commands.
+type resourceKind struct {
+	// names are the names of the kind accepted on the command line, the first one is the canonical name.
+	names []string
+	gvk   schema.GroupVersionKind
+	// nameLabel is the label holding the name of the resource on its Pods.
+	nameLabel string
+	// restartable is true if the Pods of the resource can be restarted with the restart annotation.
+	restartable bool
+}
+
+var resourceKinds = []resourceKind{
+	{names: []string{"elasticsearch", "es"}, gvk: esv1.GroupVersion.WithKind(esv1.Kind), nameLabel: eslabel.ClusterNameLabelName, restartable: true},
+	{names: []string{"kibana", "kb"}, gvk: kbv1.GroupVersion.WithKind(kbv1.Kind), nameLabel: kblabel.KibanaNameLabelName, restartable: true},
+	{names: []string{"logstash", "ls"}, gvk: lsv1alpha1.GroupVersion.WithKind(lsv1alpha1.Kind), nameLabel: lslabels.NameLabelName, restartable: true},
+	{names: []string{"apmserver", "apm"}, gvk: apmv1.GroupVersion.WithKind(apmv1.Kind), nameLabel: apmserver.ApmServerNameLabelName},
+	{names: []string{"enterprisesearch", "ent"}, gvk: entv1.GroupVersion.WithKind(entv1.Kind), nameLabel: enterprisesearch.EnterpriseSearchNameLabelName},
+	{names: []string{"beat"}, gvk: beatv1beta1.GroupVersion.WithKind(beatv1beta1.Kind), nameLabel: beatcommon.NameLabelName},
+	{names: []string{"agent"}, gvk: agentv1alpha1.GroupVersion.WithKind(agentv1alpha1.Kind), nameLabel: agent.NameLabelName},
+	{names: []string{"elasticmapsserver", "ems"}, gvk: emsv1alpha1.GroupVersion.WithKind(emsv1alpha1.Kind), nameLabel: maps.NameLabelName},
+}
+
+// Command returns the ctl command, grouping the commands to operate the resources managed by the operator.
+func Command() *cobra.Command {
+	var namespace string
+	cmd := &cobra.Command{
+		Use:   "ctl",
+		Short: "Operate the Elastic resources managed by the operator",
+		Long: "Operate the Elastic resources managed by the operator using the current Kubernetes context: show the " +
+			"progress of the orchestration, restart the Pods, pause and resume the reconciliation, collect diagnostics " +
+			"and list the pending upgrades. Resources are referenced as <kind>/<name>, for example elasticsearch/quickstart " +
+			"or es/quickstart.",
+	}
+	cmd.PersistentFlags().StringVarP(&namespace, "namespace", "n", "default", "Kubernetes namespace of the resource")
+
+	cmd.AddCommand(
+		statusCommand(&namespace),
+		restartCommand(&namespace),
+		managedCommand(&namespace, "pause", "Pause the reconciliation of a resource by the operator", false),
+		managedCommand(&namespace, "resume", "Resume the reconciliation of a paused resource by the operator", true),
+		diagnosticsCommand(&namespace),
+		pendingUpgradesCommand(&namespace),
+	)
+	return cmd
+}
+
+// newClient returns a client for the current Kubernetes context.
+func newClient() (k8s.Client, error) {
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain client configuration: %w", err)
+	}
+	return client.New(cfg, client.Options{Scheme: k8s.Scheme()})
+}
+
+// parseResource parses a <kind>/<name> reference to a resource.
+func parseResource(ref string) (resourceKind, string, error) {
+	kindName, name, found := strings.Cut(ref, "/")
+	if !found || name == "" {
+		return resourceKind{}, "", fmt.Errorf("invalid resource %q, expected <kind>/<name>", ref)
+	}
+	for _, kind := range resourceKinds {
+		for _, n := range kind.names {
+			if strings.EqualFold(n, kindName) {
+				return kind, name, nil
+			}
+		}
+	}
+	return resourceKind{}, "", fmt.Errorf("unsupported kind %q", kindName)
+}
+
+// getResource retrieves the referenced resource.
+func getResource(cmd *cobra.Command, c k8s.Client, namespace string, ref string) (resourceKind, *unstructured.Unstructured, error) {
+	kind, name, err := parseResource(ref)
+	if err != nil {
+		return resourceKind{}, nil, err
+	}
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(kind.gvk)
+	if err := c.Get(cmd.Context(), types.NamespacedName{Namespace: namespace, Name: name}, obj); err != nil {
+		return resourceKind{}, nil, err
+	}
+	return kind, obj, nil
+}
+
+// runWithResource returns a cobra run function retrieving the resource referenced by the single argument of the command.
+func runWithResource(namespace *string, run func(cmd *cobra.Command, c k8s.Client, kind resourceKind, obj *unstructured.Unstructured) error) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		c, err := newClient()
+		if err != nil {
+			return err
+		}
+		kind, obj, err := getResource(cmd, c, *namespace, args[0])
+		if err != nil {
+			return err
+		}
+		return run(cmd, c, kind, obj)
+	}
+}
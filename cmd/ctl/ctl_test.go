@@ -0,0 +1,155 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package ctl
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/annotation"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func esFixture(specVersion, statusVersion string, annotations map[string]string) *esv1.Elasticsearch {
+	return &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es", Annotations: annotations},
+		Spec:       esv1.ElasticsearchSpec{Version: specVersion},
+		Status: esv1.ElasticsearchStatus{
+			Version: statusVersion,
+			Health:  esv1.ElasticsearchGreenHealth,
+			Phase:   esv1.ElasticsearchApplyingChangesPhase,
+			InProgressOperations: esv1.InProgressOperations{
+				UpgradeOperation: esv1.UpgradeOperation{Nodes: []esv1.UpgradedNode{{Name: "es-es-default-2", Status: "PENDING"}}},
+			},
+		},
+	}
+}
+
+func Test_parseResource(t *testing.T) {
+	kind, name, err := parseResource("es/quickstart")
+	require.NoError(t, err)
+	require.Equal(t, esv1.Kind, kind.gvk.Kind)
+	require.Equal(t, "quickstart", name)
+
+	kind, _, err = parseResource("Kibana/quickstart")
+	require.NoError(t, err)
+	require.Equal(t, kbv1.Kind, kind.gvk.Kind)
+
+	_, _, err = parseResource("quickstart")
+	require.EqualError(t, err, `invalid resource "quickstart", expected <kind>/<name>`)
+	_, _, err = parseResource("pod/quickstart")
+	require.EqualError(t, err, `unsupported kind "pod"`)
+}
+
+func Test_printStatus(t *testing.T) {
+	c := k8s.NewFakeClient(esFixture("8.16.0", "8.15.0", map[string]string{commonv1.RestartAnnotation: "rolling"}))
+	kind, obj, err := getResource(&cobra.Command{}, c, "ns", "es/es")
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, printStatus(&out, kind, obj))
+	require.Equal(t, `Elasticsearch         ns/es
+Managed:              true
+Version:              8.15.0 (upgrading to 8.16.0)
+Health:               green
+Phase:                ApplyingChanges
+Observed generation:  - (generation 0)
+Restart:              rolling restart requested, -/- Pods restarted
+In progress upgrade:  es-es-default-2 (PENDING)
+`, out.String())
+}
+
+func Test_requestRestart(t *testing.T) {
+	c := k8s.NewFakeClient(esFixture("8.16.0", "8.16.0", nil))
+	kind, obj, err := getResource(&cobra.Command{}, c, "ns", "es/es")
+	require.NoError(t, err)
+
+	require.EqualError(t, requestRestart(context.Background(), c, kind, obj, "partial"), `invalid restart type "partial", expected rolling or full`)
+	require.NoError(t, requestRestart(context.Background(), c, kind, obj, commonv1.FullRestart))
+
+	var es esv1.Elasticsearch
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "es"}, &es))
+	require.Equal(t, "full", es.Annotations[commonv1.RestartAnnotation])
+
+	// a restart is already in progress
+	require.EqualError(t, requestRestart(context.Background(), c, kind, obj, commonv1.RollingRestart), "a full restart is already in progress")
+
+	// APM Server Pods cannot be restarted with the annotation
+	apmKind, _, err := parseResource("apm/apm")
+	require.NoError(t, err)
+	require.EqualError(t, requestRestart(context.Background(), c, apmKind, obj, commonv1.RollingRestart), "the Pods of ApmServer resources cannot be restarted by the operator")
+}
+
+func Test_setManaged(t *testing.T) {
+	c := k8s.NewFakeClient(esFixture("8.16.0", "8.16.0", map[string]string{annotation.LegacyPauseAnnotation: "true"}))
+	_, obj, err := getResource(&cobra.Command{}, c, "ns", "es/es")
+	require.NoError(t, err)
+
+	var es esv1.Elasticsearch
+	require.NoError(t, setManaged(context.Background(), c, obj, false))
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "es"}, &es))
+	require.Equal(t, "false", es.Annotations[annotation.ManagedAnnotation])
+
+	require.NoError(t, setManaged(context.Background(), c, obj, true))
+	es = esv1.Elasticsearch{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "es"}, &es))
+	require.Empty(t, es.Annotations)
+}
+
+func Test_dumpDiagnostics(t *testing.T) {
+	c := k8s.NewFakeClient(
+		esFixture("8.16.0", "8.16.0", nil),
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es-es-default-0", Labels: map[string]string{label.ClusterNameLabelName: "es"}}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "other", Labels: map[string]string{label.ClusterNameLabelName: "other"}}},
+		&corev1.Event{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "event"}, InvolvedObject: corev1.ObjectReference{Name: "es-es-default-0"}, Reason: "Started"},
+	)
+	kind, obj, err := getResource(&cobra.Command{}, c, "ns", "es/es")
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	files, err := dumpDiagnostics(context.Background(), c, kind, obj, dir)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(dir, "elasticsearch-ns-es.yaml"),
+		filepath.Join(dir, "elasticsearch-ns-es-pods.yaml"),
+		filepath.Join(dir, "elasticsearch-ns-es-events.yaml"),
+	}, files)
+
+	pods, err := os.ReadFile(files[1])
+	require.NoError(t, err)
+	require.Contains(t, string(pods), "es-es-default-0")
+	require.NotContains(t, string(pods), "other")
+	events, err := os.ReadFile(files[2])
+	require.NoError(t, err)
+	require.Contains(t, string(events), "reason: Started")
+}
+
+func Test_printPendingUpgrades(t *testing.T) {
+	upgrading := esFixture("8.16.0", "8.15.0", nil)
+	upToDate := esFixture("8.16.0", "8.16.0", nil)
+	upToDate.Name = "up-to-date"
+	creating := esFixture("8.16.0", "", nil)
+	creating.Name = "creating"
+	c := k8s.NewFakeClient(upgrading, upToDate, creating)
+
+	var out bytes.Buffer
+	require.NoError(t, printPendingUpgrades(context.Background(), &out, c, ""))
+	require.Equal(t, `NAMESPACE  KIND           NAME  CURRENT  TARGET  PHASE
+ns         Elasticsearch  es    8.15.0   8.16.0  ApplyingChanges
+`, out.String())
+}
@@ -0,0 +1,148 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package ctl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+// diagnosticsCommand dumps a resource, its Pods and the related events for troubleshooting.
+func diagnosticsCommand(namespace *string) *cobra.Command {
+	var outputDir string
+	cmd := &cobra.Command{
+		Use:   "diagnostics <kind>/<name>",
+		Short: "Dump a resource, its Pods and the related events",
+		Long: "Dump a resource, its Pods and the Kubernetes events related to them as YAML files in the output directory, " +
+			"to troubleshoot the orchestration of the resource. Secrets are never dumped.",
+		Args: cobra.ExactArgs(1),
+		RunE: runWithResource(namespace, func(cmd *cobra.Command, c k8s.Client, kind resourceKind, obj *unstructured.Unstructured) error {
+			files, err := dumpDiagnostics(cmd.Context(), c, kind, obj, outputDir)
+			if err != nil {
+				return err
+			}
+			for _, file := range files {
+				cmd.Println("Wrote", file)
+			}
+			return nil
+		}),
+	}
+	cmd.Flags().StringVar(&outputDir, "output-dir", ".", "Directory to write the diagnostics to")
+	_ = cmd.MarkFlagDirname("output-dir")
+	return cmd
+}
+
+// dumpDiagnostics writes the given resource, its Pods and the events related to them in the output directory, and
+// returns the paths of the written files.
+func dumpDiagnostics(ctx context.Context, c k8s.Client, kind resourceKind, obj *unstructured.Unstructured, outputDir string) ([]string, error) {
+	var pods corev1.PodList
+	if err := c.List(ctx, &pods, client.InNamespace(obj.GetNamespace()), client.MatchingLabels{kind.nameLabel: obj.GetName()}); err != nil {
+		return nil, err
+	}
+	involvedObjects := map[string]bool{obj.GetName(): true}
+	for _, pod := range pods.Items {
+		involvedObjects[pod.Name] = true
+	}
+	var allEvents corev1.EventList
+	if err := c.List(ctx, &allEvents, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil, err
+	}
+	events := make([]corev1.Event, 0, len(allEvents.Items))
+	for _, event := range allEvents.Items {
+		if involvedObjects[event.InvolvedObject.Name] {
+			events = append(events, event)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].LastTimestamp.Before(&events[j].LastTimestamp)
+	})
+
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return nil, err
+	}
+	prefix := fmt.Sprintf("%s-%s-%s", kind.names[0], obj.GetNamespace(), obj.GetName())
+	contents := []struct {
+		suffix string
+		obj    interface{}
+	}{
+		{suffix: "", obj: obj.Object},
+		{suffix: "-pods", obj: pods.Items},
+		{suffix: "-events", obj: events},
+	}
+	files := make([]string, 0, len(contents))
+	for _, content := range contents {
+		data, err := yaml.Marshal(content.obj)
+		if err != nil {
+			return nil, err
+		}
+		file := filepath.Join(outputDir, prefix+content.suffix+".yaml")
+		if err := os.WriteFile(file, data, 0o600); err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// pendingUpgradesCommand lists the resources whose version is being upgraded.
+func pendingUpgradesCommand(namespace *string) *cobra.Command {
+	var allNamespaces bool
+	cmd := &cobra.Command{
+		Use:   "pending-upgrades",
+		Short: "List the resources running a different version than the one in their specification",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			c, err := newClient()
+			if err != nil {
+				return err
+			}
+			ns := *namespace
+			if allNamespaces {
+				ns = ""
+			}
+			return printPendingUpgrades(cmd.Context(), cmd.OutOrStdout(), c, ns)
+		},
+	}
+	cmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "List the pending upgrades in all namespaces")
+	return cmd
+}
+
+// printPendingUpgrades prints the resources of the given namespace, or of all namespaces if empty, whose version in the
+// status differs from the one in the specification.
+func printPendingUpgrades(ctx context.Context, out io.Writer, c k8s.Client, namespace string) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tKIND\tNAME\tCURRENT\tTARGET\tPHASE")
+	for _, kind := range resourceKinds {
+		var list unstructured.UnstructuredList
+		list.SetGroupVersionKind(kind.gvk.GroupVersion().WithKind(kind.gvk.Kind + "List"))
+		if err := c.List(ctx, &list, client.InNamespace(namespace)); err != nil {
+			return fmt.Errorf("failed to list %s resources: %w", kind.gvk.Kind, err)
+		}
+		for _, item := range list.Items {
+			expected, _, _ := unstructured.NestedString(item.Object, "spec", "version")
+			current, _, _ := unstructured.NestedString(item.Object, "status", "version")
+			// resources being created are not upgraded
+			if current == "" || current == expected {
+				continue
+			}
+			phase, _, _ := unstructured.NestedString(item.Object, "status", "phase")
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", item.GetNamespace(), kind.gvk.Kind, item.GetName(), current, expected, phase)
+		}
+	}
+	return w.Flush()
+}
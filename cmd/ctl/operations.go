@@ -0,0 +1,170 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package ctl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/annotation"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+// statusCommand shows the progress of the orchestration of a resource.
+func statusCommand(namespace *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status <kind>/<name>",
+		Short: "Show the orchestration status of a resource",
+		Args:  cobra.ExactArgs(1),
+		RunE: runWithResource(namespace, func(cmd *cobra.Command, _ k8s.Client, kind resourceKind, obj *unstructured.Unstructured) error {
+			return printStatus(cmd.OutOrStdout(), kind, obj)
+		}),
+	}
+}
+
+// printStatus prints the status of the given resource, as reported by the operator.
+func printStatus(out io.Writer, kind resourceKind, obj *unstructured.Unstructured) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	field := func(fields ...string) string {
+		value, _, _ := unstructured.NestedFieldNoCopy(obj.Object, fields...)
+		if value == nil || value == "" {
+			return "-"
+		}
+		return fmt.Sprint(value)
+	}
+
+	fmt.Fprintf(w, "%s\t%s/%s\n", kind.gvk.Kind, obj.GetNamespace(), obj.GetName())
+	fmt.Fprintf(w, "Managed:\t%t\n", !isPaused(obj))
+	fmt.Fprintf(w, "Version:\t%s\n", versionSummary(obj))
+	fmt.Fprintf(w, "Health:\t%s\n", field("status", "health"))
+	fmt.Fprintf(w, "Phase:\t%s\n", field("status", "phase"))
+	fmt.Fprintf(w, "Observed generation:\t%s (generation %d)\n", field("status", "observedGeneration"), obj.GetGeneration())
+	if requested, exists := obj.GetAnnotations()[commonv1.RestartAnnotation]; exists {
+		fmt.Fprintf(w, "Restart:\t%s restart requested, %s/%s Pods restarted\n",
+			requested, field("status", "restart", "restartedPods"), field("status", "restart", "expectedPods"))
+	}
+
+	// in progress operations are only reported by Elasticsearch
+	for _, operation := range []string{"upscale", "upgrade", "downscale"} {
+		nodes, _, _ := unstructured.NestedSlice(obj.Object, "status", "inProgressOperations", operation, "nodes")
+		for _, node := range nodes {
+			nodeFields, ok := node.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			status := nodeFields["status"]
+			if status == nil {
+				status = nodeFields["shutdownStatus"]
+			}
+			fmt.Fprintf(w, "In progress %s:\t%v (%v)\n", operation, nodeFields["name"], status)
+		}
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if len(conditions) > 0 {
+		fmt.Fprintln(w, "Conditions:")
+		for _, condition := range conditions {
+			conditionFields, ok := condition.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			message := conditionFields["message"]
+			if message == nil {
+				message = ""
+			}
+			fmt.Fprintf(w, "  %v\t%v\t%v\n", conditionFields["type"], conditionFields["status"], message)
+		}
+	}
+	return w.Flush()
+}
+
+// versionSummary returns the version the resource is running, along with the version it is being upgraded to.
+func versionSummary(obj *unstructured.Unstructured) string {
+	expected, _, _ := unstructured.NestedString(obj.Object, "spec", "version")
+	current, _, _ := unstructured.NestedString(obj.Object, "status", "version")
+	switch {
+	case current == "":
+		return expected
+	case current != expected:
+		return fmt.Sprintf("%s (upgrading to %s)", current, expected)
+	default:
+		return current
+	}
+}
+
+// isPaused returns true if the reconciliation of the given resource is paused.
+func isPaused(obj *unstructured.Unstructured) bool {
+	annotations := obj.GetAnnotations()
+	return annotations[annotation.ManagedAnnotation] == "false" || annotations[annotation.LegacyPauseAnnotation] == "true"
+}
+
+// restartCommand requests a restart of the Pods of a resource.
+func restartCommand(namespace *string) *cobra.Command {
+	var restartType string
+	cmd := &cobra.Command{
+		Use:   "restart <kind>/<name>",
+		Short: "Restart the Pods of a resource",
+		Long: "Restart the Pods of an Elasticsearch, Kibana or Logstash resource. The restart is orchestrated by the operator, " +
+			"use the status command to follow its progress.",
+		Args: cobra.ExactArgs(1),
+		RunE: runWithResource(namespace, func(cmd *cobra.Command, c k8s.Client, kind resourceKind, obj *unstructured.Unstructured) error {
+			if err := requestRestart(cmd.Context(), c, kind, obj, commonv1.RestartType(restartType)); err != nil {
+				return err
+			}
+			cmd.Printf("%s restart of %s/%s requested\n", restartType, obj.GetNamespace(), obj.GetName())
+			return nil
+		}),
+	}
+	cmd.Flags().StringVar(&restartType, "type", string(commonv1.RollingRestart),
+		fmt.Sprintf("Type of the restart, %s or %s", commonv1.RollingRestart, commonv1.FullRestart))
+	return cmd
+}
+
+// requestRestart sets the restart annotation on the given resource, unless a restart is already in progress.
+func requestRestart(ctx context.Context, c k8s.Client, kind resourceKind, obj *unstructured.Unstructured, restartType commonv1.RestartType) error {
+	if !kind.restartable {
+		return fmt.Errorf("the Pods of %s resources cannot be restarted by the operator", kind.gvk.Kind)
+	}
+	if restartType != commonv1.RollingRestart && restartType != commonv1.FullRestart {
+		return fmt.Errorf("invalid restart type %q, expected %s or %s", restartType, commonv1.RollingRestart, commonv1.FullRestart)
+	}
+	if requested, exists := obj.GetAnnotations()[commonv1.RestartAnnotation]; exists {
+		return fmt.Errorf("a %s restart is already in progress", requested)
+	}
+	return k8s.PatchAnnotations(ctx, c, obj, map[string]*string{commonv1.RestartAnnotation: ptr.To(string(restartType))})
+}
+
+// managedCommand pauses or resumes the reconciliation of a resource.
+func managedCommand(namespace *string, use string, short string, managed bool) *cobra.Command {
+	return &cobra.Command{
+		Use:   use + " <kind>/<name>",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: runWithResource(namespace, func(cmd *cobra.Command, c k8s.Client, _ resourceKind, obj *unstructured.Unstructured) error {
+			if err := setManaged(cmd.Context(), c, obj, managed); err != nil {
+				return err
+			}
+			cmd.Printf("Reconciliation of %s/%s %sd\n", obj.GetNamespace(), obj.GetName(), use)
+			return nil
+		}),
+	}
+}
+
+// setManaged pauses or resumes the reconciliation of the given resource. Resuming also removes the deprecated pause
+// annotation.
+func setManaged(ctx context.Context, c k8s.Client, obj *unstructured.Unstructured, managed bool) error {
+	annotations := map[string]*string{annotation.ManagedAnnotation: ptr.To("false")}
+	if managed {
+		annotations = map[string]*string{annotation.ManagedAnnotation: nil, annotation.LegacyPauseAnnotation: nil}
+	}
+	return k8s.PatchAnnotations(ctx, c, obj, annotations)
+}
@@ -5,8 +5,11 @@
 package main
 
 import (
+	"os"
+
 	"github.com/spf13/cobra"
 
+	"github.com/elastic/cloud-on-k8s/v2/cmd/ctl"
 	"github.com/elastic/cloud-on-k8s/v2/cmd/manager"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/about"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/dev"
@@ -21,11 +24,13 @@ func main() {
 		Version:      buildInfo.VersionString(),
 		SilenceUsage: true,
 	}
-	rootCmd.AddCommand(manager.Command())
+	rootCmd.AddCommand(manager.Command(), ctl.Command())
 
 	// development mode is only available as a command line flag to avoid accidentally enabling it
 	rootCmd.PersistentFlags().BoolVar(&dev.Enabled, "development", false, "turns on development mode")
 	_ = rootCmd.PersistentFlags().MarkHidden("development")
 
-	_ = rootCmd.Execute()
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,344 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+)
+
+// configMigration describes a flag or configuration key which was renamed or removed.
+type configMigration struct {
+	// key is the deprecated flag or configuration key.
+	key string
+	// replacement is the key replacing the deprecated one, empty if the key was removed without replacement.
+	replacement string
+	// since is the version of the operator in which the key was deprecated.
+	since string
+}
+
+// message explains what to do with the deprecated key.
+func (m configMigration) message() string {
+	if m.replacement == "" {
+		return fmt.Sprintf("%s was removed in %s and has no effect", m.key, m.since)
+	}
+	return fmt.Sprintf("%s is deprecated since %s, use %s instead", m.key, m.since, m.replacement)
+}
+
+// configMigrations lists the flags and configuration keys which are still accepted for backward compatibility.
+// Renamed keys are mapped to their replacement, removed keys are ignored. In both cases a warning is logged.
+var configMigrations = []configMigration{
+	{key: "namespace", replacement: operator.NamespacesFlag, since: "1.0.0"},
+	{key: "operator-roles", since: "1.0.0"},
+	{key: "webhook-pods-label", since: "1.0.0"},
+}
+
+// registerDeprecatedFlags registers the deprecated flags so that existing command lines keep working. They are
+// hidden from the usage message, and pflag prints a warning when they are used.
+func registerDeprecatedFlags(flags *pflag.FlagSet) {
+	for _, m := range configMigrations {
+		valueType := "string"
+		if replacement := flags.Lookup(m.replacement); replacement != nil {
+			valueType = replacement.Value.Type()
+		}
+		switch valueType {
+		case "bool":
+			flags.Bool(m.key, false, m.message())
+		case "stringSlice":
+			flags.StringSlice(m.key, nil, m.message())
+		default:
+			flags.String(m.key, "", m.message())
+		}
+		_ = flags.MarkDeprecated(m.key, m.message())
+	}
+}
+
+// migrateConfig maps the deprecated keys set through flags, environment variables or the configuration file to their
+// replacement. It returns a warning for each deprecated key in use, and an error if both a deprecated key and its
+// replacement are set.
+func migrateConfig(v *viper.Viper) ([]string, error) {
+	var warnings []string
+	for _, m := range configMigrations {
+		if !v.IsSet(m.key) {
+			continue
+		}
+		warnings = append(warnings, m.message())
+		if m.replacement == "" {
+			continue
+		}
+		if v.IsSet(m.replacement) {
+			return warnings, fmt.Errorf("must not combine the deprecated %s and its replacement %s", m.key, m.replacement)
+		}
+		v.Set(m.replacement, v.Get(m.key))
+	}
+	return warnings, nil
+}
+
+// unknownConfigKeys returns the keys of the given configuration which do not match any flag of the operator.
+func unknownConfigKeys(v *viper.Viper, flags *pflag.FlagSet) []string {
+	var unknown []string
+	for _, key := range v.AllKeys() {
+		if flags.Lookup(key) == nil {
+			unknown = append(unknown, key)
+		}
+	}
+	slices.Sort(unknown)
+	return unknown
+}
+
+// invalidConfigValues returns an error for each value of the given configuration which cannot be parsed into the type
+// of the matching flag. The values are parsed into the given flags, which must not be used afterwards.
+func invalidConfigValues(v *viper.Viper, flags *pflag.FlagSet) []error {
+	var errs []error
+	keys := v.AllKeys()
+	slices.Sort(keys)
+	for _, key := range keys {
+		flag := flags.Lookup(key)
+		if flag == nil {
+			continue
+		}
+		if err := flag.Value.Set(configValueString(v.Get(key))); err != nil {
+			errs = append(errs, fmt.Errorf("invalid value for %s: %w", key, err))
+		}
+	}
+	return errs
+}
+
+// configValueString formats a configuration value the same way it would be passed as a command line flag.
+func configValueString(value any) string {
+	if values, isList := value.([]any); isList {
+		items := make([]string, 0, len(values))
+		for _, item := range values {
+			items = append(items, fmt.Sprint(item))
+		}
+		return strings.Join(items, ",")
+	}
+	return fmt.Sprint(value)
+}
+
+// loadConfigFile reads the operator configuration file at the given path.
+func loadConfigFile(path string) (*viper.Viper, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+	return v, nil
+}
+
+// loadConfigData reads an operator configuration in the YAML format.
+func loadConfigData(data string) (*viper.Viper, error) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(strings.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("failed to parse configuration: %w", err)
+	}
+	return v, nil
+}
+
+// validateConfigFile reports the problems of the given configuration file: unknown keys and invalid values are errors,
+// deprecated keys are warnings.
+func validateConfigFile(path string) (warnings []string, errs []error) {
+	v, err := loadConfigFile(path)
+	if err != nil {
+		return nil, []error{err}
+	}
+	// use pristine flags to not alter the configuration of the running command
+	flags := Command().Flags()
+	for _, key := range unknownConfigKeys(v, flags) {
+		errs = append(errs, fmt.Errorf("unknown configuration key %s", key))
+	}
+	errs = append(errs, invalidConfigValues(v, flags)...)
+	warnings, err = migrateConfig(v)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return warnings, errs
+}
+
+// effectiveConfig returns the value of every flag of the operator, deprecated flags aside, once the given
+// configuration is applied on top of the default values. Unknown keys are ignored, as they are by the operator.
+func effectiveConfig(v *viper.Viper) (map[string]string, error) {
+	if _, err := migrateConfig(v); err != nil {
+		return nil, err
+	}
+	flags := Command().Flags()
+	for _, key := range v.AllKeys() {
+		flag := flags.Lookup(key)
+		if flag == nil || flag.Deprecated != "" {
+			continue
+		}
+		if err := flag.Value.Set(configValueString(v.Get(key))); err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+	}
+	effective := make(map[string]string)
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if flag.Deprecated == "" {
+			effective[flag.Name] = flag.Value.String()
+		}
+	})
+	return effective, nil
+}
+
+// configDiff returns the differences between two effective configurations, one line per changed value, sorted by key.
+func configDiff(running, desired map[string]string) []string {
+	var diff []string
+	for key, value := range desired {
+		if runningValue := running[key]; runningValue != value {
+			diff = append(diff, fmt.Sprintf("%s: %s -> %s", key, runningValue, value))
+		}
+	}
+	slices.Sort(diff)
+	return diff
+}
+
+// runningConfig retrieves the configuration of a running operator from its ConfigMap.
+func runningConfig(ctx context.Context, clientset kubernetes.Interface, namespace, name, key string) (*viper.Viper, error) {
+	configMap, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve the configuration of the running operator: %w", err)
+	}
+	data, exists := configMap.Data[key]
+	if !exists {
+		return nil, fmt.Errorf("key %s not found in ConfigMap %s/%s", key, namespace, name)
+	}
+	return loadConfigData(data)
+}
+
+// diffRunningConfig prints the changes of the effective configuration of the running operator that applying the given
+// configuration file would cause.
+func diffRunningConfig(ctx context.Context, out io.Writer, clientset kubernetes.Interface, path, namespace, name, key string) error {
+	desiredConfig, err := loadConfigFile(path)
+	if err != nil {
+		return err
+	}
+	desired, err := effectiveConfig(desiredConfig)
+	if err != nil {
+		return err
+	}
+	runningConf, err := runningConfig(ctx, clientset, namespace, name, key)
+	if err != nil {
+		return err
+	}
+	running, err := effectiveConfig(runningConf)
+	if err != nil {
+		return fmt.Errorf("invalid configuration of the running operator: %w", err)
+	}
+	diff := configDiff(running, desired)
+	if len(diff) == 0 {
+		_, err := fmt.Fprintln(out, "No change to the effective configuration of the running operator")
+		return err
+	}
+	for _, line := range diff {
+		if _, err := fmt.Fprintln(out, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printValidation validates the given configuration file and prints the outcome. It returns an error if the
+// configuration is invalid.
+func printValidation(cmd *cobra.Command, path string) error {
+	warnings, errs := validateConfigFile(path)
+	for _, warning := range warnings {
+		cmd.PrintErrln("Warning:", warning)
+	}
+	for _, err := range errs {
+		cmd.PrintErrln("Error:", err)
+	}
+	if len(errs) > 0 {
+		return errors.New("invalid configuration")
+	}
+	return nil
+}
+
+// validateConfigCommand validates a configuration file offline, and optionally compares it to the configuration of a
+// running operator to review a change before rolling it out.
+func validateConfigCommand() *cobra.Command {
+	var (
+		path, namespace, configMap, key string
+		diffRunning                     bool
+	)
+	cmd := &cobra.Command{
+		Use:   "validate-config",
+		Short: "Validate an operator configuration file and compare it to the configuration of a running operator",
+		Long: "Validate an operator configuration file before rolling it out: unknown keys and invalid values are reported " +
+			"as errors, deprecated keys as warnings along with their replacement. With --diff-running, the effective " +
+			"configuration resulting from the file is compared to the one of the running operator, read from its ConfigMap " +
+			"using the current Kubernetes context. Flags and environment variables set on the operator Pod are not compared.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := printValidation(cmd, path); err != nil {
+				return err
+			}
+			if !diffRunning {
+				cmd.Println("Configuration is valid")
+				return nil
+			}
+			cfg, err := ctrl.GetConfig()
+			if err != nil {
+				return fmt.Errorf("failed to obtain client configuration: %w", err)
+			}
+			clientset, err := kubernetes.NewForConfig(cfg)
+			if err != nil {
+				return err
+			}
+			return diffRunningConfig(cmd.Context(), cmd.OutOrStdout(), clientset, path, namespace, configMap, key)
+		},
+	}
+	cmd.Flags().StringVar(&path, "file", "", "Path to the file containing the operator configuration")
+	cmd.Flags().BoolVar(&diffRunning, "diff-running", false, "Show the changes to the effective configuration of the running operator")
+	cmd.Flags().StringVar(&namespace, operator.OperatorNamespaceFlag, "elastic-system", "Kubernetes namespace the running operator runs in")
+	cmd.Flags().StringVar(&configMap, "configmap", "elastic-operator", "Name of the ConfigMap holding the configuration of the running operator")
+	cmd.Flags().StringVar(&key, "configmap-key", "eck.yaml", "Key of the configuration file in the ConfigMap of the running operator")
+	_ = cmd.MarkFlagRequired("file")
+	_ = cmd.MarkFlagFilename("file")
+	return cmd
+}
+
+// configCommand groups the subcommands helping to manage the configuration of the operator.
+func configCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage the configuration of the operator",
+	}
+
+	var path string
+	validateCmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate an operator configuration file",
+		Long: "Validate an operator configuration file before rolling it out: unknown keys and invalid values are reported " +
+			"as errors, deprecated keys as warnings along with their replacement.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := printValidation(cmd, path); err != nil {
+				return err
+			}
+			cmd.Println("Configuration is valid")
+			return nil
+		},
+	}
+	validateCmd.Flags().StringVar(&path, operator.ConfigFlag, "", "Path to the file containing the operator configuration")
+	_ = validateCmd.MarkFlagRequired(operator.ConfigFlag)
+	_ = validateCmd.MarkFlagFilename(operator.ConfigFlag)
+
+	cmd.AddCommand(validateCmd)
+	return cmd
+}
@@ -0,0 +1,189 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package manager
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+)
+
+func Test_migrateConfig(t *testing.T) {
+	tests := []struct {
+		name           string
+		settings       map[string]any
+		wantWarnings   []string
+		wantErr        bool
+		wantNamespaces []string
+	}{
+		{
+			name:           "no deprecated key",
+			settings:       map[string]any{operator.NamespacesFlag: []string{"ns1", "ns2"}},
+			wantNamespaces: []string{"ns1", "ns2"},
+		},
+		{
+			name:           "renamed key mapped to its replacement",
+			settings:       map[string]any{"namespace": "ns1"},
+			wantWarnings:   []string{"namespace is deprecated since 1.0.0, use namespaces instead"},
+			wantNamespaces: []string{"ns1"},
+		},
+		{
+			name:         "removed key ignored",
+			settings:     map[string]any{"operator-roles": "all"},
+			wantWarnings: []string{"operator-roles was removed in 1.0.0 and has no effect"},
+		},
+		{
+			name:         "both deprecated key and replacement set",
+			settings:     map[string]any{"namespace": "ns1", operator.NamespacesFlag: []string{"ns2"}},
+			wantWarnings: []string{"namespace is deprecated since 1.0.0, use namespaces instead"},
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := viper.New()
+			require.NoError(t, v.MergeConfigMap(tt.settings))
+			warnings, err := migrateConfig(v)
+			require.Equal(t, tt.wantErr, err != nil)
+			require.Equal(t, tt.wantWarnings, warnings)
+			if !tt.wantErr {
+				require.Equal(t, tt.wantNamespaces, v.GetStringSlice(operator.NamespacesFlag))
+			}
+		})
+	}
+}
+
+func Test_registerDeprecatedFlags(t *testing.T) {
+	cmd := Command()
+	cmd.RunE = func(_ *cobra.Command, _ []string) error { return nil }
+	cmd.PreRunE = nil
+	cmd.SetArgs([]string{"--namespace=ns1", "--operator-roles=all"})
+	require.NoError(t, cmd.Execute())
+
+	namespace := cmd.Flags().Lookup("namespace")
+	require.NotNil(t, namespace)
+	// deprecated flags have the type of their replacement
+	require.Equal(t, "stringSlice", namespace.Value.Type())
+	require.Equal(t, "[ns1]", namespace.Value.String())
+	require.NotEmpty(t, namespace.Deprecated)
+	require.True(t, namespace.Hidden)
+}
+
+func Test_validateConfigFile(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		wantWarnings []string
+		wantErrs     []string
+	}{
+		{
+			name:    "valid configuration",
+			content: "log-verbosity: 2\nmetrics-port: 6060\nnamespaces: [ns1, ns2]\nenable-webhook: true\nkube-client-timeout: 30s\n",
+		},
+		{
+			name:         "deprecated key",
+			content:      "namespace: ns1\nwebhook-pods-label: foo\n",
+			wantWarnings: []string{"namespace is deprecated since 1.0.0, use namespaces instead", "webhook-pods-label was removed in 1.0.0 and has no effect"},
+		},
+		{
+			name:    "unknown keys and invalid values",
+			content: "metrics-port: abc\nenable-webhok: true\nkube-client-timeout: 30\n",
+			wantErrs: []string{
+				"unknown configuration key enable-webhok",
+				`invalid value for kube-client-timeout: time: missing unit in duration "30"`,
+				`invalid value for metrics-port: strconv.ParseInt: parsing "abc": invalid syntax`,
+			},
+		},
+		{
+			name:         "deprecated key combined with its replacement",
+			content:      "namespace: ns1\nnamespaces: [ns2]\n",
+			wantWarnings: []string{"namespace is deprecated since 1.0.0, use namespaces instead"},
+			wantErrs:     []string{"must not combine the deprecated namespace and its replacement namespaces"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "eck.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+			warnings, errs := validateConfigFile(path)
+			require.Equal(t, tt.wantWarnings, warnings)
+			require.Equal(t, tt.wantErrs, errorStrings(errs))
+		})
+	}
+}
+
+func errorStrings(errs []error) []string {
+	var messages []string
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	return messages
+}
+
+func Test_validateConfigFile_MissingFile(t *testing.T) {
+	_, errs := validateConfigFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.Len(t, errs, 1)
+	require.ErrorContains(t, errs[0], "failed to read config file")
+}
+
+func Test_diffRunningConfig(t *testing.T) {
+	runningConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "elastic-system", Name: "elastic-operator"},
+		Data: map[string]string{
+			"eck.yaml": "log-verbosity: 0\nmetrics-port: 0\nnamespace: ns1\nkube-client-timeout: 60s\n",
+		},
+	}
+	tests := []struct {
+		name    string
+		content string
+		key     string
+		wantOut string
+		wantErr string
+	}{
+		{
+			name:    "no change in the effective configuration",
+			content: "log-verbosity: 0\nnamespaces: [ns1]\nkube-client-timeout: 1m\n",
+			key:     "eck.yaml",
+			wantOut: "No change to the effective configuration of the running operator\n",
+		},
+		{
+			name:    "changes in the effective configuration",
+			content: "log-verbosity: 1\nmetrics-port: 6060\nnamespaces: [ns1, ns2]\n",
+			key:     "eck.yaml",
+			wantOut: "log-verbosity: 0 -> 1\nmetrics-port: 0 -> 6060\nnamespaces: [ns1] -> [ns1,ns2]\n",
+		},
+		{
+			name:    "configuration key not found",
+			content: "log-verbosity: 1\n",
+			key:     "config.yaml",
+			wantErr: "key config.yaml not found in ConfigMap elastic-system/elastic-operator",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "eck.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+			var out bytes.Buffer
+			err := diffRunningConfig(context.Background(), &out, fake.NewSimpleClientset(runningConfigMap), path, "elastic-system", "elastic-operator", tt.key)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantOut, out.String())
+		})
+	}
+}
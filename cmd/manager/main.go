@@ -21,8 +21,10 @@ import (
 	"github.com/spf13/viper"
 	"go.elastic.co/apm/v2"
 	"go.uber.org/automaxprocs/maxprocs"
+	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -51,11 +53,17 @@ import (
 	esv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1beta1"
 	entv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/enterprisesearch/v1"
 	entv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/enterprisesearch/v1beta1"
+	esclonev1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/esclone/v1alpha1"
+	esconfigv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/esconfig/v1"
+	estaskv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/estask/v1alpha1"
+	itcv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/indextemplateclaim/v1alpha1"
 	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
 	kbv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1beta1"
 	logstashv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
 	emsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/maps/v1alpha1"
+	otelv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/otel/v1alpha1"
 	policyv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/stackconfigpolicy/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/clusterinfo"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/agent"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/apmserver"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/association"
@@ -65,30 +73,46 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/beat"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/container"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/effectivespec"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
+	commonlabels "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/labels"
 	commonlicense "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/license"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/safemode"
 	controllerscheme "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/scheme"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/secretbackend"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing/apmclientgo"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	commonwebhook "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/webhook"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch"
 	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	esdriver "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/driver"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/restartpolicy"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/settings"
 	esvalidation "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/validation"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/enterprisesearch"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/esclone"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/esconfig"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/estask"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/healthsummary"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/indextemplateclaim"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/kibana"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/license"
 	licensetrial "github.com/elastic/cloud-on-k8s/v2/pkg/controller/license/trial"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash"
 	lsvalidation "github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash/validation"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/maps"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/otel"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/remotecluster"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/stackconfigpolicy"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/trustbundle"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/webhook"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/dev"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/dev/portforward"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/diagnostics"
 	licensing "github.com/elastic/cloud-on-k8s/v2/pkg/license"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/telemetry"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/cryptutil"
@@ -136,20 +160,45 @@ func Command() *cobra.Command {
 				}
 			}
 
+			if err := logconf.SetLogFile(viper.GetString(logconf.LogFileFlag)); err != nil {
+				return fmt.Errorf("failed to open log file: %w", err)
+			}
 			logconf.ChangeVerbosity(viper.GetInt(logconf.FlagName))
 			log = logf.Log.WithName("manager")
 
+			// map the deprecated flags and configuration keys to their replacement
+			warnings, err := migrateConfig(viper.GetViper())
+			for _, warning := range warnings {
+				log.Info("Warning: " + warning)
+			}
+			if err != nil {
+				return err
+			}
+			for _, key := range unknownConfigKeys(viper.GetViper(), cmd.Flags()) {
+				log.Info("Warning: ignoring unknown configuration key", "key", key)
+			}
+
 			return nil
 		},
 		RunE: doRun,
 	}
 
+	cmd.Flags().String(
+		operator.AssociationTrustBundleSelectorFlag,
+		"",
+		"Label selector of the ClusterTrustBundles whose certificates are trusted by the associated resources in addition to the CA of the referenced resource. Requires the ClusterTrustBundle API",
+	)
 	cmd.Flags().Bool(
 		operator.AutoPortForwardFlag,
 		false,
 		"Enables automatic port-forwarding "+
 			"(for dev use only as it exposes k8s resources on ephemeral ports to localhost)",
 	)
+	cmd.Flags().String(
+		operator.AWSSecretsManagerRegionFlag,
+		"",
+		"AWS region of the AWS Secrets Manager secrets from which secure settings can be read. Enables the aws-secrets-manager secret backend",
+	)
 	cmd.Flags().String(
 		operator.CADirFlag,
 		"",
@@ -196,11 +245,26 @@ func Command() *cobra.Command {
 		"",
 		fmt.Sprintf("Suffix to be appended to container images by default. Cannot be combined with %s", operator.UBIOnlyFlag),
 	)
+	cmd.Flags().StringSlice(
+		operator.CredentialsSecretAnnotationsFlag,
+		[]string{},
+		"Comma separated list of key=value annotations added to all the Secrets holding credentials created by the operator, for example to let external tools replicate them",
+	)
+	cmd.Flags().StringSlice(
+		operator.CredentialsSecretLabelsFlag,
+		[]string{},
+		"Comma separated list of key=value labels added to all the Secrets holding credentials created by the operator, for example to let external tools replicate them",
+	)
 	cmd.Flags().String(
 		operator.DebugHTTPListenFlag,
 		"localhost:6060",
 		"Listen address for debug HTTP server (only available in development mode)",
 	)
+	cmd.Flags().StringSlice(
+		operator.DefaultImagePullSecretsFlag,
+		[]string{},
+		"Comma separated list of the names of the image pull Secrets of the Pods of all the managed workloads, unless set in their pod template. The Secrets must exist in the namespace of each resource",
+	)
 	cmd.Flags().Bool(
 		operator.DisableConfigWatch,
 		false,
@@ -216,6 +280,11 @@ func Command() *cobra.Command {
 		10*time.Second,
 		"Interval between observations of Elasticsearch health, non-positive values disable asynchronous observation",
 	)
+	cmd.Flags().Duration(
+		operator.ElasticsearchReconcileBudgetFlag,
+		0,
+		"Maximum time a single reconciliation of an Elasticsearch cluster can spend between two of its phases before yielding to other resources, non-positive values disable the budget",
+	)
 	cmd.Flags().Bool(
 		operator.DisableTelemetryFlag,
 		false,
@@ -231,6 +300,21 @@ func Command() *cobra.Command {
 		false, // Set to false for backward compatibility
 		"Restrict cross-namespace resource association through RBAC (eg. referencing Elasticsearch from Kibana)",
 	)
+	cmd.Flags().Bool(
+		operator.EnableClusterInfoAPIFlag,
+		false,
+		fmt.Sprintf("Serve a read-only API returning the endpoint, the CA certificate and a short-lived read-only API key of the managed Elasticsearch clusters to the Kubernetes users allowed to get their cluster-info subresource. Served by the webhook server, requires %s", operator.EnableWebhookFlag),
+	)
+	cmd.Flags().Bool(
+		operator.EnableDiagnosticsAPIFlag,
+		false,
+		fmt.Sprintf("Serve an API returning a support diagnostics bundle of a namespace to the Kubernetes users allowed to list its Secrets. Served by the webhook server, requires %s", operator.EnableWebhookFlag),
+	)
+	cmd.Flags().Bool(
+		operator.EnableHealthSummaryFlag,
+		false,
+		"Maintain a ConfigMap summarizing the version, health and phase of the Elastic resources of each managed namespace",
+	)
 	cmd.Flags().Bool(
 		operator.EnableLeaderElection,
 		true,
@@ -245,6 +329,11 @@ func Command() *cobra.Command {
 		false,
 		"Enables a validating webhook server in the operator process.",
 	)
+	cmd.Flags().Int(
+		operator.ExpectedResourcesCacheSizeFlag,
+		10000,
+		"Sets the maximum number of reconciled resources remembered to skip the update of the resources which did not change since their last reconciliation. Caching is disabled if set to 0 or any negative value.",
+	)
 	cmd.Flags().StringSlice(
 		operator.ExposedNodeLabels,
 		[]string{},
@@ -258,6 +347,16 @@ func Command() *cobra.Command {
 			operator.MaxConcurrentReconcilesFlag,
 		),
 	)
+	cmd.Flags().String(
+		operator.ImageCatalogFlag,
+		"",
+		"Path to a YAML file listing the digests of the Elastic Stack images by image name and version. When set, images are pinned by digest and unlisted versions cannot be deployed",
+	)
+	cmd.Flags().Bool(
+		operator.InPlacePodResizeFlag,
+		true,
+		"Resize the containers of the Elasticsearch and Kibana Pods without restarting them when only their resources change. Ignored if the Kubernetes cluster does not support in-place Pod resize",
+	)
 	cmd.Flags().String(
 		operator.IPFamilyFlag,
 		"",
@@ -273,11 +372,21 @@ func Command() *cobra.Command {
 		0,
 		"Maximum number of queries per second to the Kubernetes API.",
 	)
+	cmd.Flags().Duration(
+		operator.LicenseExpiryWarningPeriodFlag,
+		30*24*time.Hour,
+		"Period before the expiration of the license of an Elasticsearch cluster during which the operator emits warning events, non-positive values disable the warnings",
+	)
 	cmd.Flags().Bool(
 		operator.ManageWebhookCertsFlag,
 		true,
 		"Enables automatic certificates management for the webhook. The Secret and the ValidatingWebhookConfiguration must be created before running the operator",
 	)
+	cmd.Flags().Bool(
+		operator.ExternalWebhookCertsFlag,
+		false,
+		fmt.Sprintf("Uses the webhook certificates issued in the Secret designated by %s by a third party such as cert-manager, and keeps the CA bundle of the ValidatingWebhookConfiguration up to date with its ca.crt entry. Must not be combined with %s", operator.WebhookSecretFlag, operator.ManageWebhookCertsFlag),
+	)
 	cmd.Flags().Int(
 		operator.MaxConcurrentReconcilesFlag,
 		3,
@@ -309,11 +418,66 @@ func Command() *cobra.Command {
 		nil,
 		"Comma-separated list of namespaces in which this operator should manage resources (defaults to all namespaces)",
 	)
+	cmd.Flags().Int(
+		operator.NamespaceQuotaMaxClustersFlag,
+		0,
+		"Maximum number of Elasticsearch clusters per namespace (0 = unlimited)",
+	)
+	cmd.Flags().String(
+		operator.NamespaceQuotaMaxMemoryFlag,
+		"",
+		"Maximum total memory of the Elasticsearch nodes per namespace, as a Kubernetes quantity (empty = unlimited)",
+	)
+	cmd.Flags().String(
+		operator.NamespaceQuotaMaxStorageFlag,
+		"",
+		"Maximum total storage requested by the Elasticsearch volume claims per namespace, as a Kubernetes quantity (empty = unlimited)",
+	)
 	cmd.Flags().String(
 		operator.OperatorNamespaceFlag,
 		"",
 		"Kubernetes namespace the operator runs in",
 	)
+	cmd.Flags().Bool(
+		operator.OrchestrateNodeDrainsFlag,
+		false,
+		"Call the Elasticsearch node shutdown API for the Pods scheduled on cordoned Kubernetes nodes, to relocate their data before they are evicted. Requires permissions to watch Kubernetes nodes",
+	)
+	cmd.Flags().String(
+		operator.PodDNSPolicyFlag,
+		"",
+		"DNS policy of the Pods of all the managed workloads, unless set in their pod template. Possible values: ClusterFirst, ClusterFirstWithHostNet, Default, None, \"\" (= Kubernetes default)",
+	)
+	cmd.Flags().StringSlice(
+		operator.PodDNSNameserversFlag,
+		[]string{},
+		fmt.Sprintf("Comma separated list of nameservers of the Pods of all the managed workloads, unless set in their pod template. Required if %s is None", operator.PodDNSPolicyFlag),
+	)
+	cmd.Flags().StringSlice(
+		operator.PodDNSSearchesFlag,
+		[]string{},
+		fmt.Sprintf("Comma separated list of DNS search domains of the Pods of all the managed workloads, unless set in their pod template. Only the given search domains are used if %s is None", operator.PodDNSPolicyFlag),
+	)
+	cmd.Flags().Int(
+		operator.PodDNSNdotsFlag,
+		0,
+		"Value of the ndots DNS option of the Pods of all the managed workloads, unless set in their pod template. A low value such as 1 avoids walking the search domains to resolve fully qualified names. 0 leaves the option unset",
+	)
+	cmd.Flags().String(
+		operator.PodMutationPolicyFlag,
+		"",
+		"Path to a YAML file listing the sidecars, init containers, environment variables, tolerations and runtime class injected into the Pods of the managed workloads, selected by kind and namespace",
+	)
+	cmd.Flags().Bool(
+		operator.SafeModeFlag,
+		false,
+		"Run the operator in read-only mode: resources statuses, metrics and health observations are still updated but changes to the managed resources are only logged, not applied",
+	)
+	cmd.Flags().Duration(
+		operator.SecretBackendRefreshIntervalFlag,
+		5*time.Minute,
+		"Interval at which the secure settings read from external secret backends are refreshed",
+	)
 	cmd.Flags().Duration(
 		operator.TelemetryIntervalFlag,
 		1*time.Hour,
@@ -329,6 +493,21 @@ func Command() *cobra.Command {
 		true,
 		"Specifies whether the operator should retrieve storage classes to verify volume expansion support. Can be disabled if cluster-wide storage class RBAC access is not available.",
 	)
+	cmd.Flags().String(
+		operator.VaultAddressFlag,
+		"",
+		"Address of the HashiCorp Vault server from which secure settings can be read. Enables the vault secret backend",
+	)
+	cmd.Flags().String(
+		operator.VaultAuthMountPathFlag,
+		secretbackend.DefaultVaultAuthMountPath,
+		"Path of the Kubernetes auth method the operator logs into HashiCorp Vault with",
+	)
+	cmd.Flags().String(
+		operator.VaultAuthRoleFlag,
+		"",
+		"Role of the Kubernetes auth method the operator logs into HashiCorp Vault with. The VAULT_TOKEN environment variable is used instead if empty",
+	)
 	cmd.Flags().String(
 		operator.WebhookCertDirFlag,
 		// this is controller-runtime's own default, copied here for making the default explicit when using `--help`
@@ -350,6 +529,16 @@ func Command() *cobra.Command {
 		WebhookPort,
 		"Port is the port that the webhook server serves at.",
 	)
+	cmd.Flags().Bool(
+		operator.PublishClusterTrustBundlesFlag,
+		false,
+		"Publish the CA certificates of the managed resources as ClusterTrustBundles. Requires the ClusterTrustBundle API",
+	)
+	cmd.Flags().Bool(
+		operator.PublishEffectiveSpecFlag,
+		false,
+		"Publish the effective specification of the managed resources, once the operator defaults and policies are applied, in a companion ConfigMap",
+	)
 	cmd.Flags().String(
 		operator.SetDefaultSecurityContextFlag,
 		"auto-detect",
@@ -368,9 +557,16 @@ func Command() *cobra.Command {
 
 	// configure filename auto-completion for the config flag
 	_ = cmd.MarkFlagFilename(operator.ConfigFlag)
+	_ = cmd.MarkFlagFilename(operator.ImageCatalogFlag)
+	_ = cmd.MarkFlagFilename(operator.PodMutationPolicyFlag)
 
 	logconf.BindFlags(cmd.Flags())
 
+	// keep accepting the flags which were renamed or removed
+	registerDeprecatedFlags(cmd.Flags())
+
+	cmd.AddCommand(configCommand(), validateConfigCommand())
+
 	return cmd
 }
 
@@ -386,6 +582,16 @@ func doRun(_ *cobra.Command, _ []string) error {
 		toWatch = append(toWatch, configFile)
 	}
 
+	// watch for image catalog changes
+	if imageCatalog := viper.GetString(operator.ImageCatalogFlag); !viper.GetBool(operator.DisableConfigWatch) && imageCatalog != "" {
+		toWatch = append(toWatch, imageCatalog)
+	}
+
+	// watch for Pod mutation policy changes
+	if podMutationPolicy := viper.GetString(operator.PodMutationPolicyFlag); !viper.GetBool(operator.DisableConfigWatch) && podMutationPolicy != "" {
+		toWatch = append(toWatch, podMutationPolicy)
+	}
+
 	// watch for CA files if configured
 	caDir := viper.GetString(operator.CADirFlag)
 	if caDir != "" {
@@ -521,6 +727,100 @@ func startOperator(ctx context.Context) error {
 		container.SetContainerSuffix(suffix)
 	}
 
+	// pin the stack images by digest and restrict the deployable versions in air-gapped environments
+	if imageCatalogFile := viper.GetString(operator.ImageCatalogFlag); imageCatalogFile != "" {
+		imageCatalog, err := container.LoadImageCatalog(imageCatalogFile)
+		if err != nil {
+			log.Error(err, "Invalid image catalog", "path", imageCatalogFile)
+			return err
+		}
+		log.Info("Setting image catalog", "path", imageCatalogFile, "images", len(imageCatalog))
+		container.SetImageCatalog(imageCatalog)
+	}
+
+	// set the default DNS settings of the Pods
+	dnsPolicy, dnsConfig, err := defaults.NewPodDNSDefaults(
+		viper.GetString(operator.PodDNSPolicyFlag),
+		viper.GetStringSlice(operator.PodDNSNameserversFlag),
+		viper.GetStringSlice(operator.PodDNSSearchesFlag),
+		viper.GetInt(operator.PodDNSNdotsFlag),
+	)
+	if err != nil {
+		log.Error(err, "Invalid Pod DNS settings")
+		return err
+	}
+	if dnsPolicy != "" || dnsConfig != nil {
+		log.Info("Setting default Pod DNS settings", "dns_policy", dnsPolicy, "dns_config", dnsConfig)
+		defaults.SetPodDNSDefaults(dnsPolicy, dnsConfig)
+	}
+
+	// set the default image pull Secrets of the Pods
+	if imagePullSecrets := viper.GetStringSlice(operator.DefaultImagePullSecretsFlag); len(imagePullSecrets) > 0 {
+		log.Info("Setting default image pull Secrets", "image_pull_secrets", imagePullSecrets)
+		defaults.SetImagePullSecrets(imagePullSecrets)
+	}
+
+	// inject the sidecars and the company-wide constraints of the Pod mutation policy
+	if podMutationPolicyFile := viper.GetString(operator.PodMutationPolicyFlag); podMutationPolicyFile != "" {
+		podMutationPolicy, err := defaults.LoadPodMutationPolicy(podMutationPolicyFile)
+		if err != nil {
+			log.Error(err, "Invalid Pod mutation policy", "path", podMutationPolicyFile)
+			return err
+		}
+		log.Info("Setting Pod mutation policy", "path", podMutationPolicyFile, "rules", len(podMutationPolicy.Rules))
+		defaults.SetPodMutationPolicy(podMutationPolicy)
+	}
+
+	// publish the effective specification of the managed resources
+	if viper.GetBool(operator.PublishEffectiveSpecFlag) {
+		log.Info("Publishing the effective specification of the managed resources")
+		effectivespec.SetPublish(true)
+	}
+
+	// set the labels and annotations of the Secrets holding credentials
+	credentialsLabels, credentialsAnnotations, err := commonlabels.NewCredentialsSecretMetadata(
+		viper.GetStringSlice(operator.CredentialsSecretLabelsFlag),
+		viper.GetStringSlice(operator.CredentialsSecretAnnotationsFlag),
+	)
+	if err != nil {
+		log.Error(err, "Invalid credentials Secret metadata")
+		return err
+	}
+	if len(credentialsLabels) > 0 || len(credentialsAnnotations) > 0 {
+		log.Info("Setting credentials Secret metadata", "labels", credentialsLabels, "annotations", credentialsAnnotations)
+		commonlabels.SetCredentialsSecretMetadata(credentialsLabels, credentialsAnnotations)
+	}
+
+	// set the external secret backends from which secure settings can be read
+	secretBackends, err := newSecretBackends()
+	if err != nil {
+		log.Error(err, "Invalid secret backends configuration")
+		return err
+	}
+	if len(secretBackends) > 0 {
+		refreshInterval := viper.GetDuration(operator.SecretBackendRefreshIntervalFlag)
+		log.Info("Setting secure settings secret backends", "refresh_interval", refreshInterval)
+		keystore.SetSecretBackends(secretBackends, refreshInterval)
+	}
+
+	if viper.GetBool(operator.ExternalWebhookCertsFlag) && viper.IsSet(operator.ManageWebhookCertsFlag) && viper.GetBool(operator.ManageWebhookCertsFlag) {
+		err := fmt.Errorf("must not combine %s and %s flags", operator.ExternalWebhookCertsFlag, operator.ManageWebhookCertsFlag)
+		log.Error(err, "Illegal flag combination")
+		return err
+	}
+
+	if viper.GetBool(operator.EnableClusterInfoAPIFlag) && !viper.GetBool(operator.EnableWebhookFlag) {
+		err := fmt.Errorf("%s requires %s", operator.EnableClusterInfoAPIFlag, operator.EnableWebhookFlag)
+		log.Error(err, "Illegal flag combination")
+		return err
+	}
+
+	if viper.GetBool(operator.EnableDiagnosticsAPIFlag) && !viper.GetBool(operator.EnableWebhookFlag) {
+		err := fmt.Errorf("%s requires %s", operator.EnableDiagnosticsAPIFlag, operator.EnableWebhookFlag)
+		log.Error(err, "Illegal flag combination")
+		return err
+	}
+
 	// enforce UBI stack images if requested
 	ubiOnly := viper.GetBool(operator.UBIOnlyFlag)
 	if ubiOnly {
@@ -590,6 +890,10 @@ func startOperator(ctx context.Context) error {
 	for _, ns := range managedNamespaces {
 		opts.Cache.DefaultNamespaces[ns] = cache.Config{}
 	}
+	// only cache the Leases materializing the restart slots of the namespace restart policies
+	opts.Cache.ByObject = map[client.Object]cache.ByObject{
+		&coordinationv1.Lease{}: {Label: labels.SelectorFromSet(labels.Set{restartpolicy.SlotLabelName: "true"})},
+	}
 
 	// only expose prometheus metrics if provided a non-zero port
 	metricsPort := viper.GetInt(operator.MetricsPortFlag)
@@ -616,6 +920,18 @@ func startOperator(ctx context.Context) error {
 		CertDir: webhookCertDir,
 	})
 
+	if viper.GetBool(operator.SafeModeFlag) {
+		log.Info("Operator configured in safe mode, changes to the managed resources are logged but not applied")
+		safemode.Enable()
+		opts.NewClient = func(config *rest.Config, options client.Options) (client.Client, error) {
+			c, err := client.New(config, options)
+			if err != nil {
+				return nil, err
+			}
+			return safemode.NewClient(c), nil
+		}
+	}
+
 	mgr, err := ctrl.NewManager(cfg, opts)
 	if err != nil {
 		log.Error(err, "Failed to create controller manager")
@@ -653,12 +969,23 @@ func startOperator(ctx context.Context) error {
 	}
 
 	// Setup a client to set the operator uuid config map
-	clientset, err := kubernetes.NewForConfig(cfg)
+	clientsetCfg := cfg
+	if safemode.Enabled {
+		// the clientset also manages the webhook certificates and configuration
+		clientsetCfg = safemode.NewConfig(cfg)
+	}
+	clientset, err := kubernetes.NewForConfig(clientsetCfg)
 	if err != nil {
 		log.Error(err, "Failed to create Kubernetes client")
 		return err
 	}
 
+	// access to Secrets may be granted per managed namespace rather than cluster-wide, fail early if a Role is missing
+	if err := rbac.CheckSecretsAccess(ctx, clientset, managedNamespaces); err != nil {
+		log.Error(err, "Insufficient RBAC permissions on Secrets", "namespaces", managedNamespaces)
+		return err
+	}
+
 	distributionChannel := viper.GetString(operator.DistributionChannelFlag)
 	operatorInfo, err := about.GetOperatorInfo(clientset, operatorNamespace, distributionChannel)
 	if err != nil {
@@ -674,12 +1001,38 @@ func startOperator(ctx context.Context) error {
 		return err
 	}
 
+	namespaceQuota, err := esvalidation.NewNamespaceQuota(
+		viper.GetInt(operator.NamespaceQuotaMaxClustersFlag),
+		viper.GetString(operator.NamespaceQuotaMaxMemoryFlag),
+		viper.GetString(operator.NamespaceQuotaMaxStorageFlag),
+	)
+	if err != nil {
+		log.Error(err, "Failed to parse namespace quota")
+		return err
+	}
+
 	setDefaultSecurityContext, err := determineSetDefaultSecurityContext(viper.GetString(operator.SetDefaultSecurityContextFlag), clientset)
 	if err != nil {
 		log.Error(err, "failed to determine how to set default security context")
 		return err
 	}
 
+	publishTrustBundles, trustBundleSelector, err := determineTrustBundleSettings(
+		viper.GetBool(operator.PublishClusterTrustBundlesFlag),
+		viper.GetString(operator.AssociationTrustBundleSelectorFlag),
+		clientset,
+	)
+	if err != nil {
+		log.Error(err, "Failed to determine the cluster trust bundle settings")
+		return err
+	}
+
+	inPlacePodResize, err := determineInPlacePodResize(viper.GetBool(operator.InPlacePodResizeFlag), clientset)
+	if err != nil {
+		log.Error(err, "Failed to determine whether in-place Pod resize is supported")
+		return err
+	}
+
 	// default hash cache is arbitrarily set to 5 x MaxConcurrentReconcilesFlag
 	hashCacheSize := viper.GetInt(operator.MaxConcurrentReconcilesFlag) * 5
 	if viper.IsSet(operator.PasswordHashCacheSize) {
@@ -691,12 +1044,24 @@ func startOperator(ctx context.Context) error {
 		return err
 	}
 
+	if err := reconciler.EnableResultsCache(viper.GetInt(operator.ExpectedResourcesCacheSizeFlag)); err != nil {
+		log.Error(err, "failed to create expected resources cache")
+		return err
+	}
+
 	params := operator.Parameters{
+		AssociationTrustBundleSelector:   trustBundleSelector,
 		Dialer:                           dialer,
 		ElasticsearchObservationInterval: viper.GetDuration(operator.ElasticsearchObservationIntervalFlag),
+		ElasticsearchReconcileBudget:     viper.GetDuration(operator.ElasticsearchReconcileBudgetFlag),
+		EnableHealthSummary:              viper.GetBool(operator.EnableHealthSummaryFlag),
 		ExposedNodeLabels:                exposedNodeLabels,
+		InPlacePodResize:                 inPlacePodResize,
+		LicenseExpiryWarningPeriod:       viper.GetDuration(operator.LicenseExpiryWarningPeriodFlag),
+		NamespaceQuota:                   namespaceQuota,
 		IPFamily:                         ipFamily,
 		OperatorNamespace:                operatorNamespace,
+		OrchestrateNodeDrains:            viper.GetBool(operator.OrchestrateNodeDrainsFlag),
 		OperatorInfo:                     operatorInfo,
 		GlobalCA:                         ca,
 		CACertRotation: certificates.RotationParams{
@@ -707,15 +1072,27 @@ func startOperator(ctx context.Context) error {
 			Validity:     certValidity,
 			RotateBefore: certRotateBefore,
 		},
-		PasswordHasher:            passwordHasher,
-		MaxConcurrentReconciles:   viper.GetInt(operator.MaxConcurrentReconcilesFlag),
-		SetDefaultSecurityContext: setDefaultSecurityContext,
-		ValidateStorageClass:      viper.GetBool(operator.ValidateStorageClassFlag),
-		Tracer:                    tracer,
+		PasswordHasher:             passwordHasher,
+		MaxConcurrentReconciles:    viper.GetInt(operator.MaxConcurrentReconcilesFlag),
+		PublishClusterTrustBundles: publishTrustBundles,
+		SetDefaultSecurityContext:  setDefaultSecurityContext,
+		ValidateStorageClass:       viper.GetBool(operator.ValidateStorageClassFlag),
+		Tracer:                     tracer,
 	}
 
 	if viper.GetBool(operator.EnableWebhookFlag) {
 		setupWebhook(ctx, mgr, params, webhookCertDir, clientset, exposedNodeLabels, managedNamespaces, tracer)
+		if viper.GetBool(operator.EnableClusterInfoAPIFlag) {
+			mgr.GetWebhookServer().Register(clusterinfo.Path, clusterinfo.NewHandler(mgr.GetClient(), clientset, params.Dialer))
+		}
+		if viper.GetBool(operator.EnableDiagnosticsAPIFlag) {
+			// the name of the operator Pod is its hostname
+			operatorPod, err := os.Hostname()
+			if err != nil {
+				log.Error(err, "Failed to get the name of the operator Pod, the operator logs are not included in the diagnostics")
+			}
+			mgr.GetWebhookServer().Register(diagnostics.Path, diagnostics.NewHandler(mgr.GetClient(), clientset, params.Dialer, operatorNamespace, operatorPod))
+		}
 	}
 
 	enforceRbacOnRefs := viper.GetBool(operator.EnforceRBACOnRefsFlag)
@@ -726,6 +1103,8 @@ func startOperator(ctx context.Context) error {
 	} else {
 		accessReviewer = rbac.NewPermissiveAccessReviewer()
 	}
+	// secure settings Secrets in other namespaces give access to their content, RBAC is always enforced on them
+	keystore.SetAccessReviewer(rbac.NewSubjectAccessReviewer(clientset))
 
 	if err := registerControllers(mgr, params, accessReviewer); err != nil {
 		return err
@@ -774,6 +1153,33 @@ func startOperator(ctx context.Context) error {
 	}
 }
 
+// newSecretBackends returns the external secret backends from which secure settings can be read, by name, as configured
+// by the operator flags.
+func newSecretBackends() (map[string]keystore.SecretBackend, error) {
+	backends := map[string]keystore.SecretBackend{}
+	if address := viper.GetString(operator.VaultAddressFlag); address != "" {
+		vault, err := secretbackend.NewVault(secretbackend.VaultConfig{
+			Address:       address,
+			AuthMountPath: viper.GetString(operator.VaultAuthMountPathFlag),
+			AuthRole:      viper.GetString(operator.VaultAuthRoleFlag),
+		})
+		if err != nil {
+			return nil, err
+		}
+		log.Info("Enabling secret backend", "backend", secretbackend.VaultName, "address", address)
+		backends[secretbackend.VaultName] = vault
+	}
+	if region := viper.GetString(operator.AWSSecretsManagerRegionFlag); region != "" {
+		secretsManager, err := secretbackend.NewAWSSecretsManager(secretbackend.AWSSecretsManagerConfig{Region: region})
+		if err != nil {
+			return nil, err
+		}
+		log.Info("Enabling secret backend", "backend", secretbackend.AWSSecretsManagerName, "region", region)
+		backends[secretbackend.AWSSecretsManagerName] = secretsManager
+	}
+	return backends, nil
+}
+
 func readOptionalCA(caDir string) (*certificates.CA, error) {
 	if caDir == "" {
 		return nil, nil
@@ -854,6 +1260,53 @@ func determineSetDefaultSecurityContext(setDefaultSecurityContext string, client
 	return strconv.ParseBool(setDefaultSecurityContext)
 }
 
+// determineTrustBundleSettings returns whether the CA certificates should be published as ClusterTrustBundles and the
+// selector of the ClusterTrustBundles trusted by the associations. Both are disabled if the ClusterTrustBundle API is
+// not available.
+func determineTrustBundleSettings(publish bool, selector string, clientset kubernetes.Interface) (bool, labels.Selector, error) {
+	if !publish && selector == "" {
+		return false, nil, nil
+	}
+	var trustBundleSelector labels.Selector
+	if selector != "" {
+		parsed, err := labels.Parse(selector)
+		if err != nil {
+			return false, nil, fmt.Errorf("invalid %s: %w", operator.AssociationTrustBundleSelectorFlag, err)
+		}
+		trustBundleSelector = parsed
+	}
+	available, err := trustbundle.IsAPIAvailable(clientset)
+	if err != nil {
+		return false, nil, err
+	}
+	if !available {
+		log.Info("ClusterTrustBundle API not available, ignoring the cluster trust bundle settings",
+			"flags", []string{operator.PublishClusterTrustBundlesFlag, operator.AssociationTrustBundleSelectorFlag})
+		return false, nil, nil
+	}
+	return publish, trustBundleSelector, nil
+}
+
+// determineInPlacePodResize returns whether the containers of the Pods can be resized in place. It is disabled if the
+// Kubernetes API does not expose the resize subresource of the Pods, served when the InPlacePodVerticalScaling feature
+// is enabled (by default from Kubernetes 1.33).
+func determineInPlacePodResize(enabled bool, clientset kubernetes.Interface) (bool, error) {
+	if !enabled {
+		return false, nil
+	}
+	resources, err := clientset.Discovery().ServerResourcesForGroupVersion(corev1.SchemeGroupVersion.String())
+	if err != nil {
+		return false, err
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Name == "pods/resize" {
+			return true, nil
+		}
+	}
+	log.Info("In-place Pod resize not supported, Pods are restarted to change their resources", "flag", operator.InPlacePodResizeFlag)
+	return false, nil
+}
+
 // isOpenShift detects whether we are running on OpenShift. Detection inspired by kubevirt:
 // - https://github.com/kubevirt/kubevirt/blob/f71e9c9615a6c36178169d66814586a93ba515b5/pkg/util/cluster/cluster.go#L21
 func isOpenShift(clientset kubernetes.Interface) (bool, error) {
@@ -906,6 +1359,9 @@ func registerControllers(mgr manager.Manager, params operator.Parameters, access
 		{name: "Maps", registerFunc: maps.Add},
 		{name: "StackConfigPolicy", registerFunc: stackconfigpolicy.Add},
 		{name: "Logstash", registerFunc: logstash.Add},
+		{name: "ElasticsearchClone", registerFunc: esclone.Add},
+		{name: "ElasticsearchTask", registerFunc: estask.Add},
+		{name: "OpenTelemetryCollector", registerFunc: otel.Add},
 	}
 
 	for _, c := range controllers {
@@ -915,11 +1371,27 @@ func registerControllers(mgr manager.Manager, params operator.Parameters, access
 		}
 	}
 
+	if params.EnableHealthSummary {
+		if err := healthsummary.Add(mgr, params); err != nil {
+			log.Error(err, "Failed to register controller", "controller", "HealthSummary")
+			return fmt.Errorf("failed to register HealthSummary controller: %w", err)
+		}
+	}
+
+	if params.PublishClusterTrustBundles {
+		if err := trustbundle.Add(mgr, params); err != nil {
+			log.Error(err, "Failed to register controller", "controller", "TrustBundle")
+			return fmt.Errorf("failed to register TrustBundle controller: %w", err)
+		}
+	}
+
 	assocControllers := []struct {
 		name         string
 		registerFunc func(manager.Manager, rbac.AccessReviewer, operator.Parameters) error
 	}{
 		{name: "RemoteCA", registerFunc: remotecluster.Add},
+		{name: "IndexTemplateClaim", registerFunc: indextemplateclaim.Add},
+		{name: "ElasticsearchConfig", registerFunc: esconfig.Add},
 		{name: "APM-ES", registerFunc: associationctl.AddApmES},
 		{name: "APM-KB", registerFunc: associationctl.AddApmKibana},
 		{name: "KB-ES", registerFunc: associationctl.AddKibanaES},
@@ -927,11 +1399,14 @@ func registerControllers(mgr manager.Manager, params operator.Parameters, access
 		{name: "ENT-ES", registerFunc: associationctl.AddEntES},
 		{name: "BEAT-ES", registerFunc: associationctl.AddBeatES},
 		{name: "BEAT-KB", registerFunc: associationctl.AddBeatKibana},
+		{name: "BEAT-LS", registerFunc: associationctl.AddBeatLogstash},
 		{name: "AGENT-ES", registerFunc: associationctl.AddAgentES},
 		{name: "AGENT-KB", registerFunc: associationctl.AddAgentKibana},
 		{name: "AGENT-FS", registerFunc: associationctl.AddAgentFleetServer},
 		{name: "EMS-ES", registerFunc: associationctl.AddMapsES},
 		{name: "LOGSTASH-ES", registerFunc: associationctl.AddLogstashES},
+		{name: "OTEL-ES", registerFunc: associationctl.AddOTelES},
+		{name: "OTEL-APM", registerFunc: associationctl.AddOTelApm},
 		{name: "ES-MONITORING", registerFunc: associationctl.AddEsMonitoring},
 		{name: "KB-MONITORING", registerFunc: associationctl.AddKbMonitoring},
 		{name: "BEAT-MONITORING", registerFunc: associationctl.AddBeatMonitoring},
@@ -975,6 +1450,7 @@ func garbageCollectUsers(ctx context.Context, cfg *rest.Config, managedNamespace
 		For(&agentv1alpha1.AgentList{}, associationctl.AgentAssociationLabelNamespace, associationctl.AgentAssociationLabelName).
 		For(&emsv1alpha1.ElasticMapsServerList{}, associationctl.MapsESAssociationLabelNamespace, associationctl.MapsESAssociationLabelName).
 		For(&logstashv1alpha1.LogstashList{}, associationctl.LogstashAssociationLabelNamespace, associationctl.LogstashAssociationLabelName).
+		For(&otelv1alpha1.OpenTelemetryCollectorList{}, associationctl.OTelAssociationLabelNamespace, associationctl.OTelAssociationLabelName).
 		DoGarbageCollection(ctx)
 	if err != nil {
 		return fmt.Errorf("user garbage collector failed: %w", err)
@@ -996,6 +1472,7 @@ func garbageCollectSoftOwnedSecrets(ctx context.Context, k8sClient k8s.Client) {
 		emsv1alpha1.Kind:      &emsv1alpha1.ElasticMapsServer{},
 		policyv1alpha1.Kind:   &policyv1alpha1.StackConfigPolicy{},
 		logstashv1alpha1.Kind: &logstashv1alpha1.Logstash{},
+		otelv1alpha1.Kind:     &otelv1alpha1.OpenTelemetryCollector{},
 	}); err != nil {
 		log.Error(err, "Orphan secrets garbage collection failed, will be attempted again at next operator restart.")
 		return
@@ -1012,9 +1489,10 @@ func setupWebhook(
 	exposedNodeLabels esvalidation.NodeLabels,
 	managedNamespaces []string,
 	tracer *apm.Tracer) {
+	externalWebhookCerts := viper.GetBool(operator.ExternalWebhookCertsFlag)
 	manageWebhookCerts := viper.GetBool(operator.ManageWebhookCertsFlag)
-	if manageWebhookCerts {
-		if err := reconcileWebhookCertsAndAddController(ctx, mgr, params.CertRotation, clientset, tracer); err != nil {
+	if externalWebhookCerts || manageWebhookCerts {
+		if err := reconcileWebhookCertsAndAddController(ctx, mgr, params.CertRotation, externalWebhookCerts, clientset, tracer); err != nil {
 			log.Error(err, "unable to setup the webhook certificates")
 			os.Exit(1)
 		}
@@ -1038,6 +1516,11 @@ func setupWebhook(
 		&kbv1beta1.Kibana{},
 		&emsv1alpha1.ElasticMapsServer{},
 		&policyv1alpha1.StackConfigPolicy{},
+		&itcv1alpha1.IndexTemplateClaim{},
+		&esconfigv1.ElasticsearchConfig{},
+		&esclonev1alpha1.ElasticsearchClone{},
+		&estaskv1alpha1.ElasticsearchTask{},
+		&otelv1alpha1.OpenTelemetryCollector{},
 	}
 	for _, obj := range webhookObjects {
 		if err := commonwebhook.SetupValidatingWebhookWithConfig(&commonwebhook.Config{
@@ -1053,7 +1536,7 @@ func setupWebhook(
 	}
 
 	// Logstash, Elasticsearch and ElasticsearchAutoscaling validating webhooks are wired up differently, in order to access the k8s client
-	esvalidation.RegisterWebhook(mgr, params.ValidateStorageClass, exposedNodeLabels, checker, managedNamespaces)
+	esvalidation.RegisterWebhook(mgr, params.ValidateStorageClass, exposedNodeLabels, params.NamespaceQuota, checker, managedNamespaces, esdriver.NewImpactPreview(mgr.GetClient(), params))
 	esavalidation.RegisterWebhook(mgr, params.ValidateStorageClass, checker, managedNamespaces)
 	lsvalidation.RegisterWebhook(mgr, params.ValidateStorageClass, managedNamespaces)
 
@@ -1083,16 +1566,21 @@ func setupWebhook(
 	}
 }
 
-func reconcileWebhookCertsAndAddController(ctx context.Context, mgr manager.Manager, certRotation certificates.RotationParams, clientset kubernetes.Interface, tracer *apm.Tracer) error {
+func reconcileWebhookCertsAndAddController(ctx context.Context, mgr manager.Manager, certRotation certificates.RotationParams, externalCerts bool, clientset kubernetes.Interface, tracer *apm.Tracer) error {
 	ctx = tracing.NewContextTransaction(ctx, tracer, tracing.ReconciliationTxType, webhook.ControllerName, nil)
 	defer tracing.EndContextTransaction(ctx)
-	log.Info("Automatic management of the webhook certificates enabled")
+	if externalCerts {
+		log.Info("Automatic management of the webhook CA bundle enabled for externally issued certificates")
+	} else {
+		log.Info("Automatic management of the webhook certificates enabled")
+	}
 	// Ensure that all the certificates needed by the webhook server are already created
 	webhookParams := webhook.Params{
-		Name:       viper.GetString(operator.WebhookNameFlag),
-		Namespace:  viper.GetString(operator.OperatorNamespaceFlag),
-		SecretName: viper.GetString(operator.WebhookSecretFlag),
-		Rotation:   certRotation,
+		Name:          viper.GetString(operator.WebhookNameFlag),
+		Namespace:     viper.GetString(operator.OperatorNamespaceFlag),
+		SecretName:    viper.GetString(operator.WebhookSecretFlag),
+		Rotation:      certRotation,
+		ExternalCerts: externalCerts,
 	}
 
 	// retrieve the current webhook configuration interface
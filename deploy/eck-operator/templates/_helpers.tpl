@@ -129,6 +129,74 @@ Determine the metrics port
 {{- end -}}
 {{- end -}}
 
+{{/*
+Path of the log file shipped to the monitoring cluster
+*/}}
+{{- define "eck-operator.logFile" -}}
+/var/log/eck-operator/operator.log
+{{- end -}}
+
+{{/*
+Environment variables holding the connection settings of the monitoring cluster
+*/}}
+{{- define "eck-operator.monitoringEnv" -}}
+- name: MONITORING_URL
+  valueFrom:
+    secretKeyRef:
+      name: {{ .Values.monitoringRef.secretName }}
+      key: url
+- name: MONITORING_USERNAME
+  valueFrom:
+    secretKeyRef:
+      name: {{ .Values.monitoringRef.secretName }}
+      key: username
+- name: MONITORING_PASSWORD
+  valueFrom:
+    secretKeyRef:
+      name: {{ .Values.monitoringRef.secretName }}
+      key: password
+{{- end -}}
+
+{{/*
+Elasticsearch output of the Beats shipping the operator metrics and logs
+*/}}
+{{- define "eck-operator.monitoringOutput" -}}
+output.elasticsearch:
+  hosts: ["${MONITORING_URL}"]
+  username: ${MONITORING_USERNAME}
+  password: ${MONITORING_PASSWORD}
+  {{- if .Values.monitoringRef.caCertProvided }}
+  ssl.certificate_authorities: ["/mnt/elastic-internal/monitoring-ca/ca.crt"]
+  {{- end }}
+{{- end -}}
+
+{{/*
+Determine whether the access to Secrets is granted through namespaced Roles in the managed namespaces rather than through the operator ClusterRole
+*/}}
+{{- define "eck-operator.restrictSecretsAccess" -}}
+{{- if and .Values.createClusterScopedResources .Values.secretsAccess.restrictToManagedNamespaces -}}
+true
+{{- end -}}
+{{- end -}}
+
+{{/*
+RBAC permissions on Secrets
+*/}}
+{{- define "eck-operator.secretsRbacRules" -}}
+- apiGroups:
+  - ""
+  resources:
+  - secrets
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+  - delete
+{{- end -}}
+
 {{/*
 RBAC permissions
 NOTE - any changes made to RBAC permissions below require
@@ -157,6 +225,16 @@ updating docs/operating-eck/eck-permissions.asciidoc file.
   - get
   - watch
   - update
+- apiGroups:
+  - coordination.k8s.io
+  resources:
+  - leases
+  verbs:
+  - get
+  - list
+  - watch
+  - update
+  - delete
 - apiGroups:
   - ""
   resources:
@@ -165,13 +243,20 @@ updating docs/operating-eck/eck-permissions.asciidoc file.
   - get
   - list
   - watch
+- apiGroups:
+  - discovery.k8s.io
+  resources:
+  - endpointslices
+  verbs:
+  - get
+  - list
+  - watch
 - apiGroups:
   - ""
   resources:
   - pods
   - events
   - persistentvolumeclaims
-  - secrets
   - services
   - configmaps
   verbs:
@@ -182,6 +267,15 @@ updating docs/operating-eck/eck-permissions.asciidoc file.
   - update
   - patch
   - delete
+- apiGroups:
+  - ""
+  resources:
+  - pods/resize
+  verbs:
+  - patch
+{{- if not (include "eck-operator.restrictSecretsAccess" .) }}
+{{ include "eck-operator.secretsRbacRules" . }}
+{{- end }}
 - apiGroups:
   - apps
   resources:
@@ -196,6 +290,23 @@ updating docs/operating-eck/eck-permissions.asciidoc file.
   - update
   - patch
   - delete
+- apiGroups:
+  - apps
+  resources:
+  - controllerrevisions
+  verbs:
+  - get
+  - list
+  - watch
+- apiGroups:
+  - apps
+  resources:
+  - replicasets
+  verbs:
+  - get
+  - list
+  - watch
+  - patch
 - apiGroups:
   - policy
   resources:
@@ -208,6 +319,101 @@ updating docs/operating-eck/eck-permissions.asciidoc file.
   - update
   - patch
   - delete
+- apiGroups:
+  - networking.k8s.io
+  resources:
+  - networkpolicies
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+  - delete
+- apiGroups:
+  - cilium.io
+  resources:
+  - ciliumnetworkpolicies
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+  - delete
+- apiGroups:
+  - batch
+  resources:
+  - jobs
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+  - delete
+- apiGroups:
+  - cert-manager.io
+  resources:
+  - certificates
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+  - delete
+- apiGroups:
+  - monitoring.coreos.com
+  resources:
+  - servicemonitors
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+  - delete
+- apiGroups:
+  - gateway.networking.k8s.io
+  resources:
+  - httproutes
+  - tlsroutes
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+  - delete
+- apiGroups:
+  - security.istio.io
+  resources:
+  - peerauthentications
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+  - delete
+- apiGroups:
+  - snapshot.storage.k8s.io
+  resources:
+  - volumesnapshots
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - delete
 - apiGroups:
   - elasticsearch.k8s.elastic.co
   resources:
@@ -325,6 +531,65 @@ updating docs/operating-eck/eck-permissions.asciidoc file.
   - create
   - update
   - patch
+- apiGroups:
+  - indextemplateclaim.k8s.elastic.co
+  resources:
+  - indextemplateclaims
+  - indextemplateclaims/status
+  verbs:
+  - get
+  - list
+  - watch
+  - update
+  - patch
+- apiGroups:
+  - esconfig.k8s.elastic.co
+  resources:
+  - elasticsearchconfigs
+  - elasticsearchconfigs/status
+  verbs:
+  - get
+  - list
+  - watch
+  - update
+  - patch
+- apiGroups:
+  - esclone.k8s.elastic.co
+  resources:
+  - elasticsearchclones
+  - elasticsearchclones/status
+  verbs:
+  - get
+  - list
+  - watch
+  - update
+  - patch
+  - delete
+- apiGroups:
+  - estask.k8s.elastic.co
+  resources:
+  - elasticsearchtasks
+  - elasticsearchtasks/status
+  verbs:
+  - get
+  - list
+  - watch
+  - update
+  - patch
+  - delete
+- apiGroups:
+  - security.k8s.elastic.co
+  resources:
+  - elasticsearchusers
+  - elasticsearchusers/status
+  - elasticsearchroles
+  - elasticsearchroles/status
+  verbs:
+  - get
+  - list
+  - watch
+  - update
+  - patch
 - apiGroups:
   - logstash.k8s.elastic.co
   resources:
@@ -338,6 +603,19 @@ updating docs/operating-eck/eck-permissions.asciidoc file.
   - create
   - update
   - patch
+- apiGroups:
+  - otel.k8s.elastic.co
+  resources:
+  - opentelemetrycollectors
+  - opentelemetrycollectors/status
+  - opentelemetrycollectors/finalizers # needed for ownerReferences with blockOwnerDeletion on OCP
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
 {{- end -}}
 
 {{/*
@@ -352,6 +630,18 @@ RBAC permissions on non-namespaced resources
   - get
   - list
   - watch
+- apiGroups:
+  - certificates.k8s.io
+  resources:
+  - clustertrustbundles
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+  - delete
 - apiGroups:
   - admissionregistration.k8s.io
   resources:
@@ -379,3 +669,27 @@ RBAC permissions to read node labels
   - list
   - watch
 {{- end -}}
+
+{{/*
+RBAC permission to authenticate the callers of the cluster info and diagnostics APIs
+*/}}
+{{- define "eck-operator.tokenReviewRbacRule" -}}
+- apiGroups:
+  - authentication.k8s.io
+  resources:
+  - tokenreviews
+  verbs:
+  - create
+{{- end -}}
+
+{{/*
+RBAC permission to include the operator logs in the bundles returned by the diagnostics API
+*/}}
+{{- define "eck-operator.podLogsRbacRule" -}}
+- apiGroups:
+  - ""
+  resources:
+  - pods/log
+  verbs:
+  - get
+{{- end -}}
@@ -0,0 +1,127 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// schema is a JSON schema, represented as a map to be serialized with sorted keys.
+type schema map[string]interface{}
+
+// loadCRDSpecs returns the schema of the spec of the storage version of each CRD in the given multi-document YAML
+// file, indexed by CRD name.
+func loadCRDSpecs(path string) (map[string]apiextv1.JSONSchemaProps, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	specs := make(map[string]apiextv1.JSONSchemaProps)
+	for _, doc := range bytes.Split(content, []byte("\n---\n")) {
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+		var crd apiextv1.CustomResourceDefinition
+		if err := yaml.Unmarshal(doc, &crd); err != nil {
+			return nil, fmt.Errorf("while parsing CRD in %s: %w", path, err)
+		}
+		for _, version := range crd.Spec.Versions {
+			if !version.Storage || version.Schema == nil || version.Schema.OpenAPIV3Schema == nil {
+				continue
+			}
+			spec, exists := version.Schema.OpenAPIV3Schema.Properties["spec"]
+			if !exists {
+				return nil, fmt.Errorf("CRD %s has no spec", crd.Name)
+			}
+			specs[crd.Name] = spec
+		}
+	}
+	return specs, nil
+}
+
+// toJSONSchema converts an OpenAPI v3 schema of a CRD to a JSON schema as understood by Helm:
+//   - Kubernetes extensions are translated to their JSON schema equivalent, or dropped,
+//   - OpenAPI formats such as int32 are dropped as they are not JSON schema formats,
+//   - objects with known properties do not accept additional properties, unless they preserve unknown fields.
+func toJSONSchema(props apiextv1.JSONSchemaProps) schema {
+	s := schema{}
+	if props.Description != "" {
+		s["description"] = props.Description
+	}
+
+	var types []interface{}
+	switch {
+	case props.XIntOrString:
+		types = []interface{}{"integer", "string"}
+	case props.Type != "":
+		types = []interface{}{props.Type}
+	}
+	if props.Nullable && len(types) > 0 {
+		types = append(types, "null")
+	}
+	switch len(types) {
+	case 0:
+	case 1:
+		s["type"] = types[0]
+	default:
+		s["type"] = types
+	}
+
+	if len(props.Enum) > 0 {
+		enum := make([]interface{}, 0, len(props.Enum))
+		for _, value := range props.Enum {
+			var v interface{}
+			if err := yaml.Unmarshal(value.Raw, &v); err == nil {
+				enum = append(enum, v)
+			}
+		}
+		s["enum"] = enum
+	}
+	if props.Pattern != "" {
+		s["pattern"] = props.Pattern
+	}
+	setIfNotNil(s, "minimum", props.Minimum)
+	setIfNotNil(s, "maximum", props.Maximum)
+	setIfNotNil(s, "minLength", props.MinLength)
+	setIfNotNil(s, "maxLength", props.MaxLength)
+	setIfNotNil(s, "minItems", props.MinItems)
+	setIfNotNil(s, "maxItems", props.MaxItems)
+	setIfNotNil(s, "minProperties", props.MinProperties)
+	setIfNotNil(s, "maxProperties", props.MaxProperties)
+	if len(props.Required) > 0 {
+		s["required"] = props.Required
+	}
+
+	if props.Items != nil && props.Items.Schema != nil {
+		s["items"] = toJSONSchema(*props.Items.Schema)
+	}
+
+	preserveUnknownFields := props.XPreserveUnknownFields != nil && *props.XPreserveUnknownFields
+	if len(props.Properties) > 0 {
+		properties := make(map[string]interface{}, len(props.Properties))
+		for name, property := range props.Properties {
+			properties[name] = toJSONSchema(property)
+		}
+		s["properties"] = properties
+		if !preserveUnknownFields && props.AdditionalProperties == nil {
+			s["additionalProperties"] = false
+		}
+	}
+	if props.AdditionalProperties != nil && props.AdditionalProperties.Schema != nil {
+		s["additionalProperties"] = toJSONSchema(*props.AdditionalProperties.Schema)
+	}
+	return s
+}
+
+func setIfNotNil[T int64 | float64](s schema, key string, value *T) {
+	if value != nil {
+		s[key] = *value
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+// values-schema generates the values.schema.json files of the eck-operator and eck-stack Helm charts, which Helm uses to
+// validate the values at install and upgrade time. The schemas of the eck-stack charts are derived from the CRDs generated
+// from the Go types of the resources, the schema of the eck-operator chart from the operator flags.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	crdsFile       = "config/crds/v1/all-crds.yaml"
+	operatorChart  = "deploy/eck-operator"
+	stackChartsDir = "deploy/eck-stack/charts"
+	schemaFile     = "values.schema.json"
+)
+
+func main() {
+	root := flag.String("root", ".", "Root directory of the repository")
+	flag.Parse()
+
+	schemas, err := generate(*root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating Helm values schemas: %v\n", err)
+		os.Exit(1)
+	}
+	for path, content := range schemas {
+		if err := os.WriteFile(path, content, 0644); err != nil { //nolint:gosec // generated files are not sensitive
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", path, err)
+			os.Exit(1)
+		}
+	}
+}
+
+// generate returns the content of the values schema files, indexed by path.
+func generate(root string) (map[string][]byte, error) {
+	specs, err := loadCRDSpecs(filepath.Join(root, crdsFile))
+	if err != nil {
+		return nil, err
+	}
+
+	schemas := make(map[string]schema, len(stackCharts)+1)
+	s, err := operatorValuesSchema()
+	if err != nil {
+		return nil, err
+	}
+	schemas[filepath.Join(root, operatorChart, schemaFile)] = s
+
+	for _, chart := range stackCharts {
+		spec, exists := specs[chart.crd]
+		if !exists {
+			return nil, fmt.Errorf("chart %s: CRD %s not found in %s", chart.name, chart.crd, crdsFile)
+		}
+		s, err := chart.valuesSchema(spec)
+		if err != nil {
+			return nil, err
+		}
+		schemas[filepath.Join(root, stackChartsDir, chart.name, schemaFile)] = s
+	}
+
+	files := make(map[string][]byte, len(schemas))
+	for path, s := range schemas {
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(s); err != nil {
+			return nil, fmt.Errorf("while encoding %s: %w", path, err)
+		}
+		files[path] = buf.Bytes()
+	}
+	return files, nil
+}
@@ -0,0 +1,317 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	repoRoot       = "../../.."
+	stackChartRoot = "deploy/eck-stack"
+)
+
+func TestGenerate_UpToDate(t *testing.T) {
+	files, err := generate(repoRoot)
+	require.NoError(t, err)
+	for path, expected := range files {
+		actual, err := os.ReadFile(path)
+		require.NoError(t, err)
+		if !bytes.Equal(expected, actual) {
+			t.Errorf("%s is not up to date, run 'make generate-helm-values-schema'", path)
+		}
+	}
+}
+
+// TestStackCharts_TemplateValues checks that all the values used in the templates of the eck-stack charts are part of
+// their schema, unless they are forbidden.
+func TestStackCharts_TemplateValues(t *testing.T) {
+	valueRef := regexp.MustCompile(`\.Values\.([a-zA-Z0-9_]+)`)
+	for _, chart := range stackCharts {
+		t.Run(chart.name, func(t *testing.T) {
+			s := loadSchema(t, filepath.Join(repoRoot, stackChartsDir, chart.name))
+			templates, err := filepath.Glob(filepath.Join(repoRoot, stackChartsDir, chart.name, "templates", "*.*"))
+			require.NoError(t, err)
+			for _, template := range templates {
+				content, err := os.ReadFile(template)
+				require.NoError(t, err)
+				for _, match := range valueRef.FindAllStringSubmatch(string(content), -1) {
+					if _, exists := s.Properties[match[1]]; !exists && !slices.Contains(chart.forbidden, match[1]) {
+						t.Errorf("%s: value %s is not in the schema", template, match[1])
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestCharts_Values checks that the default values of the charts, merged with the values of their examples, profiles
+// and unit tests which do not expect a failure, are valid against the schemas.
+func TestCharts_Values(t *testing.T) {
+	chartDirs := []string{operatorChart}
+	for _, chart := range stackCharts {
+		chartDirs = append(chartDirs, filepath.Join(stackChartsDir, chart.name))
+	}
+	for _, dir := range chartDirs {
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			dir := filepath.Join(repoRoot, dir)
+			s := loadSchema(t, dir)
+			for name, values := range chartValues(t, dir) {
+				assert.NoError(t, validateValues(s, values), name)
+			}
+		})
+	}
+}
+
+// TestStackChart_Values checks the values of the eck-stack sub-charts set in the eck-stack chart.
+func TestStackChart_Values(t *testing.T) {
+	dir := filepath.Join(repoRoot, stackChartRoot)
+	for name, values := range chartValues(t, dir) {
+		for _, chart := range stackCharts {
+			subChartValues, exists := values[chart.name].(map[string]interface{})
+			if !exists {
+				continue
+			}
+			chartDir := filepath.Join(repoRoot, stackChartsDir, chart.name)
+			merged := mergeValues(loadValues(t, filepath.Join(chartDir, "values.yaml")), subChartValues)
+			assert.NoError(t, validateValues(loadSchema(t, chartDir), merged), "%s: %s", name, chart.name)
+		}
+	}
+}
+
+// TestSchemas_ValidValues checks values which are accepted by the schemas and left to the chart templates.
+func TestSchemas_ValidValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		chart  string
+		values string
+	}{
+		{
+			name:   "unknown top-level value",
+			chart:  filepath.Join(stackChartsDir, "eck-elasticsearch"),
+			values: "nodeSet: []",
+		},
+		{
+			name:   "value validated by the templates",
+			chart:  filepath.Join(stackChartsDir, "eck-elasticsearch"),
+			values: "volumeClaimDeletePolicy: invalid",
+		},
+		{
+			name:   "list unset with an empty map",
+			chart:  filepath.Join(stackChartsDir, "eck-elasticsearch"),
+			values: "remoteClusters: {}",
+		},
+		{
+			name:   "unknown ingress value",
+			chart:  filepath.Join(stackChartsDir, "eck-kibana"),
+			values: "ingress: {enabled: true, hosts: [{host: kibana.local}], path: /}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(repoRoot, tt.chart)
+			var values map[string]interface{}
+			require.NoError(t, yaml.Unmarshal([]byte(tt.values), &values))
+			merged := mergeValues(loadValues(t, filepath.Join(dir, "values.yaml")), values)
+			require.NoError(t, validateValues(loadSchema(t, dir), merged))
+		})
+	}
+}
+
+func TestSchemas_InvalidValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		chart  string
+		values string
+	}{
+		{
+			name:   "invalid enum value",
+			chart:  filepath.Join(stackChartsDir, "eck-elasticsearch"),
+			values: "ingress: {enabled: true, pathType: Invalid}",
+		},
+		{
+			name:   "unknown field in the spec of a nodeSet",
+			chart:  filepath.Join(stackChartsDir, "eck-elasticsearch"),
+			values: "nodeSets: [{name: default, replicas: 3}]",
+		},
+		{
+			name:   "unknown field in an ingress host",
+			chart:  filepath.Join(stackChartsDir, "eck-kibana"),
+			values: "ingress: {enabled: true, hosts: [{host: kibana.local, paths: [/]}]}",
+		},
+		{
+			name:   "forbidden value",
+			chart:  filepath.Join(stackChartsDir, "eck-fleet-server"),
+			values: "spec: {mode: standalone}",
+		},
+		{
+			name:   "invalid type",
+			chart:  filepath.Join(stackChartsDir, "eck-logstash"),
+			values: "count: three",
+		},
+		{
+			name:   "unknown operator configuration value",
+			chart:  operatorChart,
+			values: "config: {maxConcurrentReconcile: 3}",
+		},
+		{
+			name:   "invalid operator duration",
+			chart:  operatorChart,
+			values: "config: {caValidity: 365d}",
+		},
+		{
+			name:   "invalid operator integer",
+			chart:  operatorChart,
+			values: "config: {maxConcurrentReconciles: three}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(repoRoot, tt.chart)
+			var values map[string]interface{}
+			require.NoError(t, yaml.Unmarshal([]byte(tt.values), &values))
+			merged := mergeValues(loadValues(t, filepath.Join(dir, "values.yaml")), values)
+			require.Error(t, validateValues(loadSchema(t, dir), merged))
+		})
+	}
+}
+
+func loadSchema(t *testing.T, chartDir string) *spec.Schema {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join(chartDir, schemaFile))
+	require.NoError(t, err)
+	var s spec.Schema
+	require.NoError(t, json.Unmarshal(content, &s))
+	return &s
+}
+
+func validateValues(s *spec.Schema, values map[string]interface{}) error {
+	// round trip through JSON to only deal with JSON types
+	content, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	var data interface{}
+	if err := json.Unmarshal(content, &data); err != nil {
+		return err
+	}
+	return validate.AgainstSchema(s, data, strfmt.Default)
+}
+
+func loadValues(t *testing.T, path string) map[string]interface{} {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	values := map[string]interface{}{}
+	require.NoError(t, yaml.Unmarshal(content, &values), path)
+	return values
+}
+
+// chartValues returns the values of the chart to validate, indexed by a description of their origin.
+func chartValues(t *testing.T, dir string) map[string]map[string]interface{} {
+	t.Helper()
+	defaultsFile := filepath.Join(dir, "values.yaml")
+	all := map[string]map[string]interface{}{defaultsFile: loadValues(t, defaultsFile)}
+
+	var files []string
+	for _, pattern := range []string{"examples/*.yaml", "examples/*/*.yaml", "profile-*.yaml"} {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		require.NoError(t, err)
+		files = append(files, matches...)
+	}
+	for _, file := range files {
+		all[file] = mergeValues(loadValues(t, defaultsFile), loadValues(t, file))
+	}
+
+	testFiles, err := filepath.Glob(filepath.Join(dir, "templates", "tests", "*_test.yaml"))
+	require.NoError(t, err)
+	for _, testFile := range testFiles {
+		for name, values := range unitTestValues(t, testFile, defaultsFile) {
+			all[name] = values
+		}
+	}
+	return all
+}
+
+type unitTestSuite struct {
+	Tests []struct {
+		It      string                   `json:"it"`
+		Values  []string                 `json:"values"`
+		Set     map[string]interface{}   `json:"set"`
+		Asserts []map[string]interface{} `json:"asserts"`
+	} `json:"tests"`
+}
+
+// unitTestValues returns the values of the Helm unit tests of the given suite which do not expect a failure.
+func unitTestValues(t *testing.T, testFile, defaultsFile string) map[string]map[string]interface{} {
+	t.Helper()
+	content, err := os.ReadFile(testFile)
+	require.NoError(t, err)
+	var suite unitTestSuite
+	require.NoError(t, yaml.Unmarshal(content, &suite), testFile)
+
+	all := map[string]map[string]interface{}{}
+tests:
+	for _, test := range suite.Tests {
+		for _, assert := range test.Asserts {
+			if _, exists := assert["failedTemplate"]; exists {
+				continue tests
+			}
+		}
+		values := loadValues(t, defaultsFile)
+		for _, file := range test.Values {
+			values = mergeValues(values, loadValues(t, filepath.Join(filepath.Dir(testFile), file)))
+		}
+		for path, value := range test.Set {
+			values = mergeValues(values, nestedValue(strings.Split(path, "."), value))
+		}
+		all[testFile+": "+test.It] = values
+	}
+	return all
+}
+
+// nestedValue returns the value at the given path, as set by --set.
+func nestedValue(path []string, value interface{}) map[string]interface{} {
+	if len(path) == 1 {
+		return map[string]interface{}{path[0]: value}
+	}
+	return map[string]interface{}{path[0]: nestedValue(path[1:], value)}
+}
+
+// mergeValues merges src into dst as Helm does: nested maps are merged, null values delete the existing value, and other
+// values are replaced.
+func mergeValues(dst, src map[string]interface{}) map[string]interface{} {
+	for key, value := range src {
+		if value == nil {
+			delete(dst, key)
+			continue
+		}
+		srcMap, srcIsMap := value.(map[string]interface{})
+		dstMap, dstIsMap := dst[key].(map[string]interface{})
+		if srcIsMap {
+			if !dstIsMap {
+				dstMap = map[string]interface{}{}
+			}
+			dst[key] = mergeValues(dstMap, srcMap)
+			continue
+		}
+		dst[key] = value
+	}
+	return dst
+}
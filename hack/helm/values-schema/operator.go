@@ -0,0 +1,235 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"github.com/elastic/cloud-on-k8s/v2/cmd/manager"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	logconf "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// operatorFlagValues maps the values of the eck-operator chart to the operator flags they are rendered to in the
+// operator configuration file by the configmap.yaml template.
+var operatorFlagValues = map[string]string{
+	"config.caDir":                                   operator.CADirFlag,
+	"config.caRotateBefore":                          operator.CACertRotateBeforeFlag,
+	"config.caValidity":                              operator.CACertValidityFlag,
+	"config.certificatesRotateBefore":                operator.CertRotateBeforeFlag,
+	"config.certificatesValidity":                    operator.CertValidityFlag,
+	"config.containerRegistry":                       operator.ContainerRegistryFlag,
+	"config.containerRepository":                     operator.ContainerRepositoryFlag,
+	"config.containerSuffix":                         operator.ContainerSuffixFlag,
+	"config.defaultImagePullSecrets":                 operator.DefaultImagePullSecretsFlag,
+	"config.disableConfigWatch":                      operator.DisableConfigWatch,
+	"config.elasticsearchClientTimeout":              operator.ElasticsearchClientTimeout,
+	"config.elasticsearchObservationInterval":        operator.ElasticsearchObservationIntervalFlag,
+	"config.enableClusterInfoAPI":                    operator.EnableClusterInfoAPIFlag,
+	"config.enableDiagnosticsAPI":                    operator.EnableDiagnosticsAPIFlag,
+	"config.enableLeaderElection":                    operator.EnableLeaderElection,
+	"config.exposedNodeLabels":                       operator.ExposedNodeLabels,
+	"config.inPlacePodResize":                        operator.InPlacePodResizeFlag,
+	"config.ipFamily":                                operator.IPFamilyFlag,
+	"config.kubeClientQPS":                           operator.KubeClientQPS,
+	"config.kubeClientTimeout":                       operator.KubeClientTimeout,
+	"config.logVerbosity":                            logconf.FlagName,
+	"config.maxConcurrentReconciles":                 operator.MaxConcurrentReconcilesFlag,
+	"config.metrics.port":                            operator.MetricsPortFlag,
+	"config.metrics.secureMode.enabled":              operator.MetricsSecureFlag,
+	"config.metricsPort":                             operator.MetricsPortFlag,
+	"config.namespaceQuota.maxClusters":              operator.NamespaceQuotaMaxClustersFlag,
+	"config.namespaceQuota.maxMemory":                operator.NamespaceQuotaMaxMemoryFlag,
+	"config.namespaceQuota.maxStorage":               operator.NamespaceQuotaMaxStorageFlag,
+	"config.orchestrateNodeDrains":                   operator.OrchestrateNodeDrainsFlag,
+	"config.passwordHashCacheSize":                   operator.PasswordHashCacheSize,
+	"config.podDNS.nameservers":                      operator.PodDNSNameserversFlag,
+	"config.podDNS.ndots":                            operator.PodDNSNdotsFlag,
+	"config.podDNS.policy":                           operator.PodDNSPolicyFlag,
+	"config.podDNS.searches":                         operator.PodDNSSearchesFlag,
+	"config.publishEffectiveSpec":                    operator.PublishEffectiveSpecFlag,
+	"config.secretBackends.awsSecretsManager.region": operator.AWSSecretsManagerRegionFlag,
+	"config.secretBackends.refreshInterval":          operator.SecretBackendRefreshIntervalFlag,
+	"config.secretBackends.vault.address":            operator.VaultAddressFlag,
+	"config.secretBackends.vault.authMountPath":      operator.VaultAuthMountPathFlag,
+	"config.secretBackends.vault.authRole":           operator.VaultAuthRoleFlag,
+	"config.setDefaultSecurityContext":               operator.SetDefaultSecurityContextFlag,
+	"config.ubiOnly":                                 operator.UBIOnlyFlag,
+	"config.validateStorageClass":                    operator.ValidateStorageClassFlag,
+	"managedNamespaces":                              operator.NamespacesFlag,
+	"refs.enforceRBAC":                               operator.EnforceRBACOnRefsFlag,
+	"telemetry.disabled":                             operator.DisableTelemetryFlag,
+	"telemetry.distributionChannel":                  operator.DistributionChannelFlag,
+	"telemetry.interval":                             operator.TelemetryIntervalFlag,
+	"tracing.enabled":                                operator.EnableTracingFlag,
+	"webhook.certsDir":                               operator.WebhookCertDirFlag,
+	"webhook.certsSecret":                            operator.WebhookSecretFlag,
+	"webhook.enabled":                                operator.EnableWebhookFlag,
+	"webhook.manageCerts":                            operator.ManageWebhookCertsFlag,
+	"webhook.port":                                   operator.WebhookPortFlag,
+}
+
+// operatorChartValues are the values of the eck-operator chart which are not rendered to an operator flag, within the
+// objects containing values mapped to flags, and the constraints on values mapped to flags which are more specific than
+// the type of the flag.
+var operatorChartValues = map[string]schema{
+	"config.credentialsSecrets": {
+		"description": "Extra labels and annotations set on all the Secrets holding credentials created by the operator. Rendered to the credentials-secret-labels and credentials-secret-annotations flags.",
+		"type":        "object",
+		"properties": map[string]interface{}{
+			"labels":      schema{"type": "object", "additionalProperties": schema{"type": "string"}},
+			"annotations": schema{"type": "object", "additionalProperties": schema{"type": "string"}},
+		},
+		"additionalProperties": false,
+	},
+	"config.imageCatalog": {
+		"description": "Digests of the Elastic Stack images by image name and version. Rendered to the image-catalog.yaml file referenced by the image-catalog flag.",
+		"type":        "object",
+		"additionalProperties": schema{
+			"type":                 "object",
+			"additionalProperties": schema{"type": "string", "pattern": "^sha256:[a-f0-9]{64}$"},
+		},
+	},
+	"config.metrics.secureMode.tls": {
+		"description": "TLS configuration of the metrics endpoint, a self-signed certificate is used if not set.",
+		"type":        "object",
+		"properties": map[string]interface{}{
+			"certificateSecret":  schema{"type": "string"},
+			"caSecret":           schema{"type": "string"},
+			"caMountDirectory":   schema{"type": "string"},
+			"insecureSkipVerify": schema{"type": "boolean"},
+		},
+		"additionalProperties": false,
+	},
+	"config.podMutationPolicy": {
+		"description": "Sidecars, init containers, environment variables, tolerations and runtime class injected into the Pods of the managed workloads. Rendered to the pod-mutation-policy.yaml file referenced by the pod-mutation-policy flag.",
+		"type":        "object",
+		"properties": map[string]interface{}{
+			"rules": schema{
+				"type": "array",
+				"items": schema{
+					"type":     "object",
+					"required": []string{"name"},
+					"properties": map[string]interface{}{
+						"name":                    schema{"type": "string"},
+						"kinds":                   schema{"type": "array", "items": schema{"type": "string"}},
+						"namespaces":              schema{"type": "array", "items": schema{"type": "string"}},
+						"initContainers":          schema{"type": "array", "items": schema{"type": "object"}},
+						"containers":              schema{"type": "array", "items": schema{"type": "object"}},
+						"env":                     schema{"type": "array", "items": schema{"type": "object"}},
+						"applyEnvToAllContainers": schema{"type": "boolean"},
+						"tolerations":             schema{"type": "array", "items": schema{"type": "object"}},
+						"runtimeClassName":        schema{"type": "string"},
+					},
+					"additionalProperties": false,
+				},
+			},
+		},
+		"additionalProperties": false,
+	},
+	"config.setDefaultSecurityContext": {
+		"type": []interface{}{"string", "boolean"},
+		"enum": []interface{}{"auto-detect", "true", "false", true, false},
+	},
+	"tracing.config":          {"type": "object", "additionalProperties": schema{"type": "string"}},
+	"webhook.caBundle":        {"type": "string"},
+	"webhook.certManagerCert": {"type": []interface{}{"string", "null"}},
+	"webhook.failurePolicy":   {"type": "string", "enum": []interface{}{"Ignore", "Fail"}},
+	"webhook.namespaceSelector": {
+		"type": "object",
+	},
+	"webhook.objectSelector": {
+		"type": "object",
+	},
+}
+
+// durationPattern matches the durations accepted by time.ParseDuration.
+const durationPattern = `^[-+]?(([0-9]+(\.[0-9]*)?|\.[0-9]+)(ns|us|µs|ms|s|m|h))+$|^0$`
+
+// operatorValuesSchema returns the schema of the values of the eck-operator chart mapped to operator flags. The values
+// not related to the operator configuration are not constrained.
+func operatorValuesSchema() (schema, error) {
+	flags := manager.Command().Flags()
+	root := schema{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"title":   "Values of the eck-operator chart",
+		"type":    "object",
+	}
+	for path, name := range operatorFlagValues {
+		flag := flags.Lookup(name)
+		if flag == nil {
+			return nil, fmt.Errorf("value %s: unknown operator flag %s", path, name)
+		}
+		s, err := flagSchema(flag)
+		if err != nil {
+			return nil, fmt.Errorf("value %s: %w", path, err)
+		}
+		setValueSchema(root, path, s)
+	}
+	for path, s := range operatorChartValues {
+		setValueSchema(root, path, s)
+	}
+	return root, nil
+}
+
+// flagSchema returns the schema of a value rendered to the given flag in the operator configuration file. Numbers may be
+// given as strings, as the templates convert them to integers.
+func flagSchema(flag *pflag.Flag) (schema, error) {
+	s := schema{"description": flag.Usage}
+	switch flag.Value.Type() {
+	case "bool":
+		s["type"] = "boolean"
+	case "int", "int32", "int64", "uint", "uint32", "uint64":
+		s["type"] = []interface{}{"integer", "string"}
+		s["pattern"] = "^-?[0-9]+$"
+	case "float32", "float64":
+		s["type"] = []interface{}{"number", "string"}
+		s["pattern"] = `^[0-9]+(\.[0-9]+)?$`
+	case "duration":
+		s["type"] = "string"
+		s["pattern"] = durationPattern
+	case "string":
+		s["type"] = "string"
+	case "stringSlice":
+		s["type"] = "array"
+		s["items"] = schema{"type": "string"}
+	default:
+		return nil, fmt.Errorf("unsupported type %s of flag %s", flag.Value.Type(), flag.Name)
+	}
+	return s, nil
+}
+
+// setValueSchema sets the schema of the value at the given dotted path, creating the intermediate objects, which do not
+// accept unknown values. The given schema is merged into the existing schema of the value, if any.
+func setValueSchema(root schema, path string, s schema) {
+	parent := root
+	keys := strings.Split(path, ".")
+	for i, key := range keys {
+		properties, ok := parent["properties"].(map[string]interface{})
+		if !ok {
+			properties = map[string]interface{}{}
+			parent["properties"] = properties
+		}
+		if i == len(keys)-1 {
+			if existing, ok := properties[key].(schema); ok {
+				for k, v := range s {
+					existing[k] = v
+				}
+				return
+			}
+			properties[key] = s
+			return
+		}
+		child, ok := properties[key].(schema)
+		if !ok {
+			child = schema{"type": "object", "additionalProperties": false}
+			properties[key] = child
+		}
+		parent = child
+	}
+}
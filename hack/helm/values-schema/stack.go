@@ -0,0 +1,361 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package main
+
+import (
+	"fmt"
+	"slices"
+
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// stackChart describes how the values of an eck-stack sub-chart are mapped to the spec of the resource it deploys.
+type stackChart struct {
+	// name of the chart directory in deploy/eck-stack/charts.
+	name string
+	// crd is the name of the CRD of the resource deployed by the chart.
+	crd string
+	// specValues are the top-level values copied as is into the spec of the resource.
+	specValues []string
+	// withSpec is true if the whole spec of the resource can also be set in the spec value.
+	withSpec bool
+	// forbidden are the spec fields that are set by the chart and cannot be set in the values.
+	forbidden []string
+	// looseValues are the values copied as is into the spec of the resource which accept unknown fields, they may not
+	// be fields of its CRD.
+	looseValues []string
+	// templateValidated are the top-level values whose allowed values are checked by the chart templates, with their
+	// own error message, only their type is constrained.
+	templateValidated []string
+	// values are the top-level values specific to the chart.
+	values func(spec apiextv1.JSONSchemaProps) map[string]schema
+}
+
+var stackCharts = []stackChart{
+	{
+		name: "eck-agent",
+		crd:  "agents.agent.k8s.elastic.co",
+		specValues: []string{
+			"config", "configRef", "daemonSet", "deployment", "elasticsearchRefs", "fleetServerEnabled", "fleetServerRef",
+			"http", "image", "kibanaRef", "mode", "policyID", "revisionHistoryLimit", "secureSettings", "statefulSet", "version",
+		},
+		withSpec: true,
+		values:   rbacValues,
+	},
+	{
+		name: "eck-apm-server",
+		crd:  "apmservers.apm.k8s.elastic.co",
+		specValues: []string{
+			"config", "count", "elasticsearchRef", "http", "image", "kibanaRef", "podTemplate", "revisionHistoryLimit",
+			"secureSettings", "serviceAccountName", "version",
+		},
+	},
+	{
+		name: "eck-beats",
+		crd:  "beats.beat.k8s.elastic.co",
+		specValues: []string{
+			"config", "configRef", "daemonSet", "deployment", "elasticsearchRef", "image", "kibanaRef", "monitoring",
+			"revisionHistoryLimit", "secureSettings", "type", "version",
+		},
+		looseValues: []string{"http"},
+		withSpec:    true,
+		values:      rbacValues,
+	},
+	{
+		name: "eck-elasticsearch",
+		crd:  "elasticsearches.elasticsearch.k8s.elastic.co",
+		specValues: []string{
+			"auth", "http", "image", "monitoring", "nodeSets", "remoteClusters", "revisionHistoryLimit", "secureSettings",
+			"serviceAccountName", "transport", "updateStrategy", "version", "volumeClaimDeletePolicy",
+		},
+		templateValidated: []string{"volumeClaimDeletePolicy"},
+		values: func(spec apiextv1.JSONSchemaProps) map[string]schema {
+			return map[string]schema{
+				"ingress": ingressSchema(),
+				"podDisruptionBudget": {
+					"description": "PodDisruptionBudget of the Elasticsearch cluster, a default one is created if not set.",
+					"type":        []interface{}{"object", "null"},
+					"properties": map[string]interface{}{
+						"disabled": schema{"type": "boolean", "description": "Disables the default PodDisruptionBudget."},
+						"spec":     toJSONSchema(spec.Properties["podDisruptionBudget"].Properties["spec"]),
+					},
+					"additionalProperties": false,
+				},
+			}
+		},
+	},
+	{
+		name: "eck-enterprise-search",
+		crd:  "enterprisesearches.enterprisesearch.k8s.elastic.co",
+		specValues: []string{
+			"config", "configRef", "count", "elasticsearchRef", "http", "image", "podTemplate", "revisionHistoryLimit",
+			"serviceAccountName", "version",
+		},
+		values: func(apiextv1.JSONSchemaProps) map[string]schema {
+			return map[string]schema{"serviceAccount": serviceAccountSchema()}
+		},
+	},
+	{
+		name: "eck-fleet-server",
+		crd:  "agents.agent.k8s.elastic.co",
+		specValues: []string{
+			"deployment", "elasticsearchRefs", "http", "image", "kibanaRef", "policyID", "revisionHistoryLimit",
+			"statefulSet", "version",
+		},
+		withSpec:  true,
+		forbidden: []string{"fleetServerEnabled", "mode"},
+		values:    rbacValues,
+	},
+	{
+		name: "eck-kibana",
+		crd:  "kibanas.kibana.k8s.elastic.co",
+		specValues: []string{
+			"config", "count", "elasticsearchRef", "enterpriseSearchRef", "http", "image", "monitoring", "podTemplate",
+			"revisionHistoryLimit", "secureSettings", "serviceAccountName", "version",
+		},
+		withSpec: true,
+		values: func(apiextv1.JSONSchemaProps) map[string]schema {
+			return map[string]schema{"ingress": ingressSchema()}
+		},
+	},
+	{
+		name: "eck-logstash",
+		crd:  "logstashes.logstash.k8s.elastic.co",
+		specValues: []string{
+			"config", "configRef", "count", "elasticsearchRefs", "image", "monitoring", "pipelines", "pipelinesRef",
+			"podTemplate", "revisionHistoryLimit", "secureSettings", "serviceAccountName", "services", "version",
+			"volumeClaimTemplates",
+		},
+		looseValues: []string{"configRef"},
+	},
+}
+
+// valuesSchema returns the schema of the values of the chart, given the schema of the spec of the resource it deploys.
+func (c stackChart) valuesSchema(spec apiextv1.JSONSchemaProps) (schema, error) {
+	properties := map[string]interface{}{
+		"enabled":          schema{"type": "boolean", "description": "Enables the chart when installed as part of eck-stack."},
+		"global":           schema{"type": "object", "description": "Global values shared with the parent chart."},
+		"nameOverride":     schema{"type": "string", "description": "Overrides the name of the chart."},
+		"fullnameOverride": schema{"type": "string", "description": "Overrides the full name of the resource."},
+		"labels":           stringMapSchema("Labels added to the resource."),
+		"annotations":      stringMapSchema("Annotations added to the resource."),
+	}
+
+	for _, name := range c.specValues {
+		property, exists := spec.Properties[name]
+		if !exists {
+			return nil, fmt.Errorf("chart %s: %s is not a field of the spec of %s", c.name, name, c.crd)
+		}
+		s := toJSONSchema(property)
+		if slices.Contains(c.templateValidated, name) {
+			delete(s, "enum")
+		}
+		properties[name] = unsetAllowed(s)
+	}
+	for _, name := range c.looseValues {
+		properties[name] = unsetAllowed(looseSchema(spec, name))
+	}
+
+	values := map[string]schema{}
+	if c.values != nil {
+		values = c.values(spec)
+	}
+	for name, value := range values {
+		properties[name] = value
+	}
+
+	if c.withSpec {
+		specSchema := *spec.DeepCopy()
+		// required fields may be set as top-level values instead
+		specSchema.Required = nil
+		for _, name := range c.forbidden {
+			delete(specSchema.Properties, name)
+		}
+		s := toJSONSchema(specSchema)
+		for _, name := range c.looseValues {
+			s["properties"].(map[string]interface{})[name] = looseSchema(spec, name)
+		}
+		// the ServiceAccount created by the chart can also be set in the spec value
+		if serviceAccount, exists := values["serviceAccount"]; exists {
+			s["properties"].(map[string]interface{})["serviceAccount"] = serviceAccount
+		}
+		properties["spec"] = s
+	}
+
+	// other top-level values are not constrained, they may be used by the templates of a parent chart or passed through
+	return schema{
+		"$schema":    "http://json-schema.org/draft-07/schema#",
+		"title":      fmt.Sprintf("Values of the %s chart", c.name),
+		"type":       "object",
+		"properties": properties,
+	}, nil
+}
+
+// looseSchema returns the schema of the given field of the spec which accepts unknown fields, or of any object if it is
+// not a field of the spec.
+func looseSchema(spec apiextv1.JSONSchemaProps, name string) schema {
+	property, exists := spec.Properties[name]
+	if !exists {
+		return schema{"type": "object"}
+	}
+	s := toJSONSchema(property)
+	delete(s, "additionalProperties")
+	return s
+}
+
+// unsetAllowed returns the schema of a top-level value which the chart templates ignore if it is null or empty.
+func unsetAllowed(s schema) schema {
+	if s["type"] == "array" {
+		// lists are commonly unset with an empty map in the default values
+		description := s["description"]
+		delete(s, "description")
+		return schema{
+			"description": description,
+			"anyOf":       []interface{}{s, schema{"type": []interface{}{"object", "null"}, "maxProperties": 0}},
+		}
+	}
+	if _, hasPattern := s["pattern"]; hasPattern {
+		description := s["description"]
+		delete(s, "description")
+		return schema{
+			"description": description,
+			"anyOf":       []interface{}{s, schema{"enum": []interface{}{"", nil}}},
+		}
+	}
+	switch t := s["type"].(type) {
+	case string:
+		s["type"] = []interface{}{t, "null"}
+	case []interface{}:
+		s["type"] = append(t, "null")
+	}
+	if enum, ok := s["enum"].([]interface{}); ok {
+		s["enum"] = append(enum, "", nil)
+	}
+	return s
+}
+
+func stringMapSchema(description string) schema {
+	return schema{
+		"description":          description,
+		"type":                 []interface{}{"object", "null"},
+		"additionalProperties": schema{"type": "string"},
+	}
+}
+
+func ingressSchema() schema {
+	return schema{
+		"description": "Ingress exposing the HTTP endpoint of the resource.",
+		"type":        "object",
+		"properties": map[string]interface{}{
+			"enabled":     schema{"type": "boolean"},
+			"annotations": stringMapSchema("Annotations added to the Ingress."),
+			"labels":      stringMapSchema("Labels added to the Ingress."),
+			"className":   schema{"type": "string"},
+			"pathType":    schema{"type": "string", "enum": []interface{}{"Exact", "Prefix", "ImplementationSpecific"}},
+			"hosts": schema{
+				"type": "array",
+				"items": schema{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"host": schema{"type": "string"},
+						"path": schema{"type": "string"},
+					},
+					"required":             []string{"host"},
+					"additionalProperties": false,
+				},
+			},
+			"tls": schema{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"enabled":    schema{"type": "boolean"},
+					"secretName": schema{"type": "string"},
+				},
+				"additionalProperties": false,
+			},
+		},
+	}
+}
+
+func serviceAccountSchema() schema {
+	return schema{
+		"description": "ServiceAccount used by the Pods.",
+		"type":        []interface{}{"object", "null"},
+		"properties": map[string]interface{}{
+			"create":      schema{"type": "boolean"},
+			"name":        schema{"type": "string"},
+			"namespace":   schema{"type": "string"},
+			"labels":      stringMapSchema("Labels added to the ServiceAccount."),
+			"annotations": stringMapSchema("Annotations added to the ServiceAccount."),
+		},
+		"additionalProperties": false,
+	}
+}
+
+// rbacValues returns the schema of the values to create the ServiceAccount, ClusterRole and ClusterRoleBinding used by
+// the Pods of Beats and Elastic Agents.
+func rbacValues(apiextv1.JSONSchemaProps) map[string]schema {
+	stringArray := schema{"type": "array", "items": schema{"type": "string"}}
+	return map[string]schema{
+		"serviceAccount": serviceAccountSchema(),
+		"clusterRole": {
+			"description": "ClusterRole bound to the ServiceAccount.",
+			"type":        []interface{}{"object", "null"},
+			"properties": map[string]interface{}{
+				"name":        schema{"type": "string"},
+				"labels":      stringMapSchema("Labels added to the ClusterRole."),
+				"annotations": stringMapSchema("Annotations added to the ClusterRole."),
+				"rules": schema{
+					"type": "array",
+					"items": schema{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"apiGroups":       stringArray,
+							"resources":       stringArray,
+							"resourceNames":   stringArray,
+							"nonResourceURLs": stringArray,
+							"verbs":           stringArray,
+						},
+						"required":             []string{"verbs"},
+						"additionalProperties": false,
+					},
+				},
+			},
+			"additionalProperties": false,
+		},
+		"clusterRoleBinding": {
+			"description": "ClusterRoleBinding of the ClusterRole to the ServiceAccount.",
+			"type":        []interface{}{"object", "null"},
+			"properties": map[string]interface{}{
+				"name":        schema{"type": "string"},
+				"labels":      stringMapSchema("Labels added to the ClusterRoleBinding."),
+				"annotations": stringMapSchema("Annotations added to the ClusterRoleBinding."),
+				"subjects": schema{
+					"type": "array",
+					"items": schema{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"kind":      schema{"type": "string"},
+							"name":      schema{"type": "string"},
+							"namespace": schema{"type": "string"},
+						},
+						"required":             []string{"kind", "name"},
+						"additionalProperties": false,
+					},
+				},
+				"roleRef": schema{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"kind":     schema{"type": "string"},
+						"name":     schema{"type": "string"},
+						"apiGroup": schema{"type": "string"},
+					},
+					"required":             []string{"kind", "name"},
+					"additionalProperties": false,
+				},
+			},
+			"additionalProperties": false,
+		},
+	}
+}
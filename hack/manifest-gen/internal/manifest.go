@@ -29,6 +29,9 @@ type GenerateFlags struct {
 	Values            []string
 	ValueFiles        []string
 	OperatorNamespace string
+	// RestrictSecretsAccess grants access to Secrets through Roles generated for each managed namespace
+	// instead of through the operator ClusterRole.
+	RestrictSecretsAccess bool
 }
 
 // OptionsFlags holds flag values for the options operation.
@@ -58,6 +61,9 @@ func Generate(opts *GenerateFlags) error {
 
 	// set manifestGen flag
 	valueFlags := append(opts.Values, "global.manifestGen=true")
+	if opts.RestrictSecretsAccess {
+		valueFlags = append(valueFlags, "secretsAccess.restrictToManagedNamespaces=true")
+	}
 
 	valueOpts := &values.Options{
 		Values:     valueFlags,
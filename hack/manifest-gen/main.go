@@ -98,6 +98,9 @@ Restricted operator installed to and managing the single namespace named "namesp
 Restricted operator managing "elastic-system", "nsa" and "nsb":
     $ manifest-gen generate --profile=restricted --set=managedNamespaces='{elastic-system, nsa, nsb}'
 
+Global operator managing "nsa" and "nsb", with access to Secrets restricted to these namespaces and to "elastic-system":
+    $ manifest-gen generate --set=managedNamespaces='{nsa, nsb}' --restrict-secrets-access
+
 Restricted operator with tracing configured:
     $ manifest-gen generate --profile=restricted --set=tracing.enabled=true --set=tracing.config.ELASTIC_APM_SERVER_URL=http://apm:8200
 `
@@ -119,6 +122,7 @@ Restricted operator with tracing configured:
 	cmd.Flags().StringArrayVar(&generateFlags.Values, "set", []string{}, "Set additional options")
 	cmd.Flags().StringArrayVar(&generateFlags.ValueFiles, "values", []string{}, "Set additional options from file(s)")
 	cmd.Flags().StringVarP(&generateFlags.OperatorNamespace, "namespace", "n", "elastic-system", "Operator namespace")
+	cmd.Flags().BoolVar(&generateFlags.RestrictSecretsAccess, "restrict-secrets-access", false, "Grant access to Secrets through Roles in the managed namespaces only (requires managedNamespaces)")
 
 	return cmd
 }
@@ -39,6 +39,10 @@ check global
 $MG --profile=restricted > "${TEMP_DIR}/restricted.yaml"
 check restricted 
 
+# global profile with access to secrets restricted to the managed namespaces
+$MG --profile=global --set=managedNamespaces='{ns-a,ns-b}' --restrict-secrets-access > "${TEMP_DIR}/restricted-secrets-access.yaml"
+check restricted-secrets-access
+
 # soft-multi-tenancy profile
 $MG --profile=soft-multi-tenancy --set=kubeAPIServerIP=1.2.3.4 > "${TEMP_DIR}/soft-multi-tenancy.yaml"
 check soft-multi-tenancy
@@ -13,6 +13,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 )
 
@@ -26,6 +27,14 @@ const (
 
 var (
 	MandatoryPolicyIDVersion = version.MustParse("9.0.0-SNAPSHOT")
+	// FleetServerExternalURLMinVersion is the minimum version supporting the management of Fleet Server hosts through
+	// the Fleet API.
+	FleetServerExternalURLMinVersion = version.MustParse("8.5.0")
+	// PolicyMinVersion is the minimum version supporting the management of agent policies through the Fleet API.
+	PolicyMinVersion = version.MustParse("8.8.0")
+	// DownloadSourceMinVersion is the minimum version supporting the management of agent binary download sources
+	// through the Fleet API.
+	DownloadSourceMinVersion = version.MustParse("8.8.0")
 )
 
 // AgentSpec defines the desired state of the Agent
@@ -96,11 +105,21 @@ type AgentSpec struct {
 	// +kubebuilder:validation:Optional
 	FleetServerEnabled bool `json:"fleetServerEnabled,omitempty"`
 
+	// FleetServer holds settings specific to Fleet Server. Don't set unless `fleetServerEnabled` is set to true.
+	// +kubebuilder:validation:Optional
+	FleetServer *FleetServerSpec `json:"fleetServer,omitempty"`
+
 	// PolicyID determines into which Agent Policy this Agent will be enrolled.
 	// This field will become mandatory in a future release, default policies are deprecated since 8.1.0.
 	// +kubebuilder:validation:Optional
 	PolicyID string `json:"policyID,omitempty"`
 
+	// Policy declares an agent policy managed by ECK through the Fleet API, in which this Agent is enrolled.
+	// The agent policy, its integrations and its outputs are kept in sync with this specification. Cannot be combined
+	// with `policyID`. Don't set unless `mode` is set to `fleet`.
+	// +kubebuilder:validation:Optional
+	Policy *AgentPolicy `json:"policy,omitempty"`
+
 	// KibanaRef is a reference to Kibana where Fleet should be set up and this Agent should be enrolled. Don't set
 	// unless `mode` is set to `fleet`.
 	// +kubebuilder:validation:Optional
@@ -110,11 +129,116 @@ type AgentSpec struct {
 	// Don't set unless `mode` is set to `fleet`.
 	// +kubebuilder:validation:Optional
 	FleetServerRef commonv1.ObjectSelector `json:"fleetServerRef,omitempty"`
+
+	// Download configures the artifact mirror the Elastic Agents download their binaries from when they are upgraded,
+	// for example in air-gapped environments. In `standalone` mode, it sets `agent.download.sourceURI` in the Agent
+	// configuration. In `fleet` mode, the mirror is registered as an agent binary download source in Fleet and used by
+	// the agent policy declared in `policy`, which is then required.
+	// +kubebuilder:validation:Optional
+	Download *AgentDownloadSpec `json:"download,omitempty"`
+}
+
+// AgentDownloadSpec holds the settings of the artifact mirror the Elastic Agents download their binaries from.
+type AgentDownloadSpec struct {
+	// SourceURI is the URL of the artifact mirror, for example `https://artifacts.example.com/downloads/`. The binaries
+	// must be available under the same paths as on `https://artifacts.elastic.co/downloads/`.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	SourceURI string `json:"sourceURI"`
+}
+
+// FleetServerSpec holds settings specific to Fleet Server.
+type FleetServerSpec struct {
+	// ExternalURL is the URL under which Fleet Server is reachable from outside the Kubernetes cluster, for example
+	// through a load balancer with a custom domain. The host of the URL is added to the subject alternative names of
+	// the Fleet Server HTTP certificate, and the URL is registered as a Fleet Server host in Kibana so that Elastic
+	// Agents running outside Kubernetes can be enrolled through it.
+	// +kubebuilder:validation:Optional
+	ExternalURL string `json:"externalURL,omitempty"`
+}
+
+// AgentPolicy declares an agent policy managed by ECK in Fleet.
+type AgentPolicy struct {
+	// Name of the agent policy in Fleet. Defaults to `<namespace>/<name>` of the Agent resource.
+	// +kubebuilder:validation:Optional
+	Name string `json:"name,omitempty"`
+
+	// Description of the agent policy.
+	// +kubebuilder:validation:Optional
+	Description string `json:"description,omitempty"`
+
+	// Namespace is the default data stream namespace of the integrations of the policy. Defaults to `default`.
+	// +kubebuilder:validation:Optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// MonitoringEnabled lists the monitoring data the Agents of this policy collect about themselves.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:items:Enum=logs;metrics
+	MonitoringEnabled []string `json:"monitoringEnabled,omitempty"`
+
+	// DataOutputID is the ID of the Fleet output receiving the data collected by the integrations.
+	// Defaults to the default output of Fleet.
+	// +kubebuilder:validation:Optional
+	DataOutputID string `json:"dataOutputID,omitempty"`
+
+	// MonitoringOutputID is the ID of the Fleet output receiving the monitoring data of the Agents.
+	// Defaults to the default monitoring output of Fleet.
+	// +kubebuilder:validation:Optional
+	MonitoringOutputID string `json:"monitoringOutputID,omitempty"`
+
+	// PackagePolicies are the integrations of the agent policy. Integrations added to the policy outside of ECK are
+	// left untouched.
+	// +kubebuilder:validation:Optional
+	PackagePolicies []PackagePolicy `json:"packagePolicies,omitempty"`
+}
+
+// PackagePolicy declares an integration of an agent policy.
+type PackagePolicy struct {
+	// Name of the integration, unique within the agent policy.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Package is the integration package, installed in Kibana if necessary.
+	// +kubebuilder:validation:Required
+	Package PackageReference `json:"package"`
+
+	// Namespace is the data stream namespace of the integration. Defaults to the namespace of the agent policy.
+	// +kubebuilder:validation:Optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Vars are the package level variables of the integration.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Optional
+	Vars *commonv1.Config `json:"vars,omitempty"`
+
+	// Inputs configure the inputs and streams of the integration, in the simplified format of the Fleet package
+	// policies API. Inputs and streams which are not specified keep their default configuration.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Optional
+	Inputs *commonv1.Config `json:"inputs,omitempty"`
+}
+
+// PackageReference identifies an integration package.
+type PackageReference struct {
+	// Name of the package, for example `system` or `kubernetes`.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Version of the package.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Version string `json:"version"`
 }
 
 type Output struct {
 	commonv1.ObjectSelector `json:",omitempty,inline"`
 	OutputName              string `json:"outputName,omitempty"`
+	// OutputSettings holds tuning settings of the Elasticsearch output generated for this reference in standalone mode.
+	// These settings cannot be set in the Agent configuration at the same time.
+	// +kubebuilder:validation:Optional
+	OutputSettings *commonv1.ElasticsearchOutputSettings `json:"outputSettings,omitempty"`
 }
 
 type DaemonSetSpec struct {
@@ -187,6 +311,10 @@ type AgentStatus struct {
 	// If the generation observed in status diverges from the generation in metadata, the Elastic
 	// Agent controller has not yet processed the changes contained in the Elastic Agent specification.
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions holds the current service state of the resource.
+	// +kubebuilder:validation:Optional
+	Conditions commonv1alpha1.Conditions `json:"conditions,omitempty"`
 }
 
 type AgentHealth string
@@ -223,6 +351,24 @@ func (a AgentSpec) FleetModeEnabled() bool {
 	return a.Mode == AgentFleetMode
 }
 
+// FleetServerExternalURL returns the URL under which Fleet Server is reachable from outside the Kubernetes cluster,
+// or an empty string if none is specified.
+func (a AgentSpec) FleetServerExternalURL() string {
+	if a.FleetServer == nil {
+		return ""
+	}
+	return a.FleetServer.ExternalURL
+}
+
+// DownloadSourceURI returns the URL of the artifact mirror the Elastic Agents download their binaries from, or an
+// empty string if none is specified.
+func (a AgentSpec) DownloadSourceURI() string {
+	if a.Download == nil {
+		return ""
+	}
+	return a.Download.SourceURI
+}
+
 // StandaloneModeEnabled returns true iff the Agent is running in standalone mode. Takes into the account the default.
 func (a AgentSpec) StandaloneModeEnabled() bool {
 	return a.Mode == "" || a.Mode == AgentStandaloneMode
@@ -238,6 +384,9 @@ func (a AgentSpec) StandaloneModeEnabled() bool {
 // +kubebuilder:printcolumn:name="expected",type="integer",JSONPath=".status.expectedNodes",description="Expected nodes"
 // +kubebuilder:printcolumn:name="version",type="string",JSONPath=".status.version",description="Agent version"
 // +kubebuilder:printcolumn:name="age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="kibana",type="string",JSONPath=".status.kibanaAssociationStatus",description="Kibana association status",priority=1
+// +kubebuilder:printcolumn:name="fleet-server",type="string",JSONPath=".status.fleetServerAssociationStatus",description="Fleet Server association status",priority=1
+// +kubebuilder:printcolumn:name="reconciled",type="date",JSONPath=".status.conditions[?(@.type=='Reconciling')].lastTransitionTime",description="Time of the last transition of the Reconciling condition",priority=1
 // +kubebuilder:storageversion
 type Agent struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -6,6 +6,7 @@ package v1alpha1
 
 import (
 	"fmt"
+	"net/url"
 	"reflect"
 	"strings"
 
@@ -19,6 +20,7 @@ import (
 var (
 	defaultChecks = []func(*Agent) field.ErrorList{
 		checkPolicyID,
+		checkPolicy,
 		checkNoUnknownFields,
 		checkNameLength,
 		checkSupportedVersion,
@@ -30,9 +32,12 @@ var (
 		checkEmptyConfigForFleetMode,
 		checkFleetServerOnlyInFleetMode,
 		checkHTTPConfigOnlyForFleetServer,
+		checkFleetServerExternalURL,
+		checkDownload,
 		checkFleetServerOrFleetServerRef,
 		checkReferenceSetForMode,
 		checkSingleESRefInFleetMode,
+		checkOutputSettings,
 		checkAssociations,
 	}
 
@@ -67,7 +72,7 @@ func checkPolicyID(a *Agent) field.ErrorList {
 	if err != nil {
 		return err
 	}
-	if v.GTE(MandatoryPolicyIDVersion) && len(a.Spec.PolicyID) == 0 {
+	if v.GTE(MandatoryPolicyIDVersion) && len(a.Spec.PolicyID) == 0 && a.Spec.Policy == nil {
 		msg := "Agent policyID is mandatory"
 		return field.ErrorList{
 			field.Required(field.NewPath("spec").Child("policyID"), msg),
@@ -76,6 +81,40 @@ func checkPolicyID(a *Agent) field.ErrorList {
 	return nil
 }
 
+func checkPolicy(a *Agent) field.ErrorList {
+	if a.Spec.Policy == nil {
+		return nil
+	}
+	path := field.NewPath("spec").Child("policy")
+	if !a.Spec.FleetModeEnabled() {
+		return field.ErrorList{
+			field.Invalid(path, a.Spec.Policy, "don't specify an agent policy, it can't be set when fleet mode is not enabled"),
+		}
+	}
+	if a.Spec.PolicyID != "" {
+		return field.ErrorList{
+			field.Forbidden(path, "policy and policyID cannot be both specified, use one or the other"),
+		}
+	}
+	v, errs := commonv1.ParseVersion(a.Spec.Version)
+	if errs != nil {
+		return errs
+	}
+	if v.LT(PolicyMinVersion) {
+		return field.ErrorList{
+			field.Forbidden(path, fmt.Sprintf("policy requires version %s or above", PolicyMinVersion)),
+		}
+	}
+	names := make(map[string]struct{}, len(a.Spec.Policy.PackagePolicies))
+	for i, packagePolicy := range a.Spec.Policy.PackagePolicies {
+		if _, exists := names[packagePolicy.Name]; exists {
+			errs = append(errs, field.Duplicate(path.Child("packagePolicies").Index(i).Child("name"), packagePolicy.Name))
+		}
+		names[packagePolicy.Name] = struct{}{}
+	}
+	return errs
+}
+
 func checkAtMostOneDeploymentOption(a *Agent) field.ErrorList {
 	var enabledSpecsNames []string
 
@@ -256,6 +295,71 @@ func checkHTTPConfigOnlyForFleetServer(a *Agent) field.ErrorList {
 	return nil
 }
 
+func checkFleetServerExternalURL(a *Agent) field.ErrorList {
+	if a.Spec.FleetServer == nil {
+		return nil
+	}
+	path := field.NewPath("spec").Child("fleetServer")
+	if !a.Spec.FleetServerEnabled {
+		return field.ErrorList{
+			field.Invalid(path, a.Spec.FleetServer, "don't specify Fleet Server settings, they can't be set when Fleet Server is not enabled"),
+		}
+	}
+	externalURL := a.Spec.FleetServer.ExternalURL
+	if externalURL == "" {
+		return nil
+	}
+	v, errs := commonv1.ParseVersion(a.Spec.Version)
+	if errs != nil {
+		return errs
+	}
+	if v.LT(FleetServerExternalURLMinVersion) {
+		return field.ErrorList{
+			field.Forbidden(path.Child("externalURL"), fmt.Sprintf("externalURL requires version %s or above", FleetServerExternalURLMinVersion)),
+		}
+	}
+	u, err := url.Parse(externalURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return field.ErrorList{
+			field.Invalid(path.Child("externalURL"), externalURL, "externalURL must be an absolute http or https URL"),
+		}
+	}
+	return nil
+}
+
+func checkDownload(a *Agent) field.ErrorList {
+	if a.Spec.Download == nil {
+		return nil
+	}
+	path := field.NewPath("spec").Child("download")
+	sourceURI := a.Spec.Download.SourceURI
+	u, err := url.Parse(sourceURI)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return field.ErrorList{
+			field.Invalid(path.Child("sourceURI"), sourceURI, "sourceURI must be an absolute http or https URL"),
+		}
+	}
+	if !a.Spec.FleetModeEnabled() {
+		return nil
+	}
+	if a.Spec.Policy == nil {
+		return field.ErrorList{
+			field.Required(field.NewPath("spec").Child("policy"), "download requires an agent policy managed by ECK in fleet mode, "+
+				"the download source of other agent policies is configured in Fleet"),
+		}
+	}
+	v, errs := commonv1.ParseVersion(a.Spec.Version)
+	if errs != nil {
+		return errs
+	}
+	if v.LT(DownloadSourceMinVersion) {
+		return field.ErrorList{
+			field.Forbidden(path, fmt.Sprintf("download requires version %s or above in fleet mode", DownloadSourceMinVersion)),
+		}
+	}
+	return nil
+}
+
 func checkReferenceSetForMode(a *Agent) field.ErrorList {
 	var errors field.ErrorList
 	if a.Spec.StandaloneModeEnabled() {
@@ -300,6 +404,26 @@ func checkSingleESRefInFleetMode(a *Agent) field.ErrorList {
 	return nil
 }
 
+func checkOutputSettings(a *Agent) field.ErrorList {
+	var errs field.ErrorList
+	for i, ref := range a.Spec.ElasticsearchRefs {
+		if ref.OutputSettings == nil {
+			continue
+		}
+		path := field.NewPath("spec").Child("elasticsearchRefs").Index(i).Child("outputSettings")
+		if a.Spec.FleetModeEnabled() {
+			errs = append(errs, field.Forbidden(path, "outputSettings can't be set in fleet mode, outputs are managed by Fleet"))
+			continue
+		}
+		outputName := ref.OutputName
+		if outputName == "" {
+			outputName = "default"
+		}
+		errs = append(errs, ref.OutputSettings.Validate(path, field.NewPath("spec").Child("config"), a.Spec.Config, "outputs."+outputName)...)
+	}
+	return errs
+}
+
 func checkAssociations(a *Agent) field.ErrorList {
 	err1 := commonv1.CheckAssociationRefs(field.NewPath("spec").Child("elasticsearchRefs"), a.ElasticsearchRefs()...)
 	err2 := commonv1.CheckAssociationRefs(field.NewPath("spec").Child("kibanaRef"), a.Spec.KibanaRef)
@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 )
@@ -518,6 +519,56 @@ func Test_checkEmptyConfigForFleetMode(t *testing.T) {
 	}
 }
 
+func Test_checkOutputSettings(t *testing.T) {
+	outputSettings := &commonv1.ElasticsearchOutputSettings{CompressionLevel: ptr.To[int32](1)}
+	for _, tt := range []struct {
+		name    string
+		a       *Agent
+		wantErr bool
+	}{
+		{
+			name: "output settings in standalone mode: OK",
+			a: &Agent{Spec: AgentSpec{
+				Mode:              AgentStandaloneMode,
+				ElasticsearchRefs: []Output{{ObjectSelector: commonv1.ObjectSelector{Name: "es"}, OutputSettings: outputSettings}},
+			}},
+			wantErr: false,
+		},
+		{
+			name: "output settings in fleet mode: NOK",
+			a: &Agent{Spec: AgentSpec{
+				Mode:               AgentFleetMode,
+				FleetServerEnabled: true,
+				ElasticsearchRefs:  []Output{{ObjectSelector: commonv1.ObjectSelector{Name: "es"}, OutputSettings: outputSettings}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "output settings also set in the configuration of the default output: NOK",
+			a: &Agent{Spec: AgentSpec{
+				Mode:              AgentStandaloneMode,
+				ElasticsearchRefs: []Output{{ObjectSelector: commonv1.ObjectSelector{Name: "es"}, OutputSettings: outputSettings}},
+				Config:            &commonv1.Config{Data: map[string]interface{}{"outputs.default.compression_level": 5}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "output settings set in the configuration of another output: OK",
+			a: &Agent{Spec: AgentSpec{
+				Mode:              AgentStandaloneMode,
+				ElasticsearchRefs: []Output{{ObjectSelector: commonv1.ObjectSelector{Name: "es"}, OutputName: "es", OutputSettings: outputSettings}},
+				Config:            &commonv1.Config{Data: map[string]interface{}{"outputs.default.compression_level": 5}},
+			}},
+			wantErr: false,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkOutputSettings(tt.a)
+			assert.Equal(t, tt.wantErr, len(got) > 0)
+		})
+	}
+}
+
 func Test_checkFleetServerOnlyInFleetMode(t *testing.T) {
 	for _, tt := range []struct {
 		name    string
@@ -623,6 +674,165 @@ func Test_checkHTTPConfigOnlyForFleetServer(t *testing.T) {
 	}
 }
 
+func Test_checkFleetServerExternalURL(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		a       *Agent
+		wantErr bool
+	}{
+		{
+			name:    "no fleet server settings: OK",
+			a:       &Agent{Spec: AgentSpec{FleetServerEnabled: true, Version: "8.15.0"}},
+			wantErr: false,
+		},
+		{
+			name: "fleet server with external URL: OK",
+			a: &Agent{Spec: AgentSpec{
+				FleetServerEnabled: true,
+				Version:            "8.15.0",
+				FleetServer:        &FleetServerSpec{ExternalURL: "https://fleet.example.com:443"},
+			}},
+			wantErr: false,
+		},
+		{
+			name: "fleet server settings without fleet server: NOK",
+			a: &Agent{Spec: AgentSpec{
+				Version:     "8.15.0",
+				FleetServer: &FleetServerSpec{ExternalURL: "https://fleet.example.com"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "relative external URL: NOK",
+			a: &Agent{Spec: AgentSpec{
+				FleetServerEnabled: true,
+				Version:            "8.15.0",
+				FleetServer:        &FleetServerSpec{ExternalURL: "fleet.example.com"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "unsupported scheme: NOK",
+			a: &Agent{Spec: AgentSpec{
+				FleetServerEnabled: true,
+				Version:            "8.15.0",
+				FleetServer:        &FleetServerSpec{ExternalURL: "ftp://fleet.example.com"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "version too old: NOK",
+			a: &Agent{Spec: AgentSpec{
+				FleetServerEnabled: true,
+				Version:            "8.4.3",
+				FleetServer:        &FleetServerSpec{ExternalURL: "https://fleet.example.com"},
+			}},
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkFleetServerExternalURL(tt.a)
+			assert.Equal(t, tt.wantErr, len(got) > 0)
+		})
+	}
+}
+
+func Test_checkDownload(t *testing.T) {
+	download := &AgentDownloadSpec{SourceURI: "https://artifacts.example.com/downloads/"}
+	for _, tt := range []struct {
+		name    string
+		a       *Agent
+		wantErr bool
+	}{
+		{
+			name:    "no download settings: OK",
+			a:       &Agent{Spec: AgentSpec{Version: "8.15.0"}},
+			wantErr: false,
+		},
+		{
+			name:    "standalone mode: OK",
+			a:       &Agent{Spec: AgentSpec{Version: "8.6.0", Download: download}},
+			wantErr: false,
+		},
+		{
+			name:    "fleet mode with a managed policy: OK",
+			a:       &Agent{Spec: AgentSpec{Version: "8.15.0", Mode: AgentFleetMode, Policy: &AgentPolicy{}, Download: download}},
+			wantErr: false,
+		},
+		{
+			name:    "fleet mode without a managed policy: NOK",
+			a:       &Agent{Spec: AgentSpec{Version: "8.15.0", Mode: AgentFleetMode, PolicyID: "policy", Download: download}},
+			wantErr: true,
+		},
+		{
+			name:    "fleet mode, version too old: NOK",
+			a:       &Agent{Spec: AgentSpec{Version: "8.7.1", Mode: AgentFleetMode, Policy: &AgentPolicy{}, Download: download}},
+			wantErr: true,
+		},
+		{
+			name:    "relative source URI: NOK",
+			a:       &Agent{Spec: AgentSpec{Version: "8.15.0", Download: &AgentDownloadSpec{SourceURI: "artifacts.example.com/downloads/"}}},
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkDownload(tt.a)
+			assert.Equal(t, tt.wantErr, len(got) > 0)
+		})
+	}
+}
+
+func Test_checkPolicy(t *testing.T) {
+	systemPolicy := func(names ...string) *AgentPolicy {
+		policy := &AgentPolicy{}
+		for _, name := range names {
+			policy.PackagePolicies = append(policy.PackagePolicies, PackagePolicy{Name: name, Package: PackageReference{Name: "system", Version: "1.60.0"}})
+		}
+		return policy
+	}
+	for _, tt := range []struct {
+		name    string
+		a       *Agent
+		wantErr bool
+	}{
+		{
+			name:    "no policy: OK",
+			a:       &Agent{Spec: AgentSpec{Mode: AgentFleetMode, Version: "8.15.0", PolicyID: "a-policy-id"}},
+			wantErr: false,
+		},
+		{
+			name:    "policy with unique integration names: OK",
+			a:       &Agent{Spec: AgentSpec{Mode: AgentFleetMode, Version: "8.15.0", Policy: systemPolicy("system-1", "system-2")}},
+			wantErr: false,
+		},
+		{
+			name:    "policy in standalone mode: NOK",
+			a:       &Agent{Spec: AgentSpec{Version: "8.15.0", Policy: systemPolicy()}},
+			wantErr: true,
+		},
+		{
+			name:    "policy and policyID: NOK",
+			a:       &Agent{Spec: AgentSpec{Mode: AgentFleetMode, Version: "8.15.0", PolicyID: "a-policy-id", Policy: systemPolicy()}},
+			wantErr: true,
+		},
+		{
+			name:    "version too old: NOK",
+			a:       &Agent{Spec: AgentSpec{Mode: AgentFleetMode, Version: "8.7.1", Policy: systemPolicy()}},
+			wantErr: true,
+		},
+		{
+			name:    "duplicate integration names: NOK",
+			a:       &Agent{Spec: AgentSpec{Mode: AgentFleetMode, Version: "8.15.0", Policy: systemPolicy("system", "system")}},
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkPolicy(tt.a)
+			assert.Equal(t, tt.wantErr, len(got) > 0)
+		})
+	}
+}
+
 func Test_checkReferenceSetForMode(t *testing.T) {
 	for _, tt := range []struct {
 		name    string
@@ -10,6 +10,7 @@ package v1alpha1
 
 import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
@@ -58,6 +59,21 @@ func (in *Agent) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentDownloadSpec) DeepCopyInto(out *AgentDownloadSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentDownloadSpec.
+func (in *AgentDownloadSpec) DeepCopy() *AgentDownloadSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentDownloadSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AgentESAssociation) DeepCopyInto(out *AgentESAssociation) {
 	*out = *in
@@ -151,13 +167,42 @@ func (in *AgentList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentPolicy) DeepCopyInto(out *AgentPolicy) {
+	*out = *in
+	if in.MonitoringEnabled != nil {
+		in, out := &in.MonitoringEnabled, &out.MonitoringEnabled
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PackagePolicies != nil {
+		in, out := &in.PackagePolicies, &out.PackagePolicies
+		*out = make([]PackagePolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentPolicy.
+func (in *AgentPolicy) DeepCopy() *AgentPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AgentSpec) DeepCopyInto(out *AgentSpec) {
 	*out = *in
 	if in.ElasticsearchRefs != nil {
 		in, out := &in.ElasticsearchRefs, &out.ElasticsearchRefs
 		*out = make([]Output, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.Config != nil {
 		in, out := &in.Config, &out.Config
@@ -196,8 +241,23 @@ func (in *AgentSpec) DeepCopyInto(out *AgentSpec) {
 		**out = **in
 	}
 	in.HTTP.DeepCopyInto(&out.HTTP)
+	if in.FleetServer != nil {
+		in, out := &in.FleetServer, &out.FleetServer
+		*out = new(FleetServerSpec)
+		**out = **in
+	}
+	if in.Policy != nil {
+		in, out := &in.Policy, &out.Policy
+		*out = new(AgentPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	out.KibanaRef = in.KibanaRef
 	out.FleetServerRef = in.FleetServerRef
+	if in.Download != nil {
+		in, out := &in.Download, &out.Download
+		*out = new(AgentDownloadSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentSpec.
@@ -220,6 +280,13 @@ func (in *AgentStatus) DeepCopyInto(out *AgentStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(commonv1alpha1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentStatus.
@@ -271,10 +338,30 @@ func (in *DeploymentSpec) DeepCopy() *DeploymentSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FleetServerSpec) DeepCopyInto(out *FleetServerSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetServerSpec.
+func (in *FleetServerSpec) DeepCopy() *FleetServerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FleetServerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Output) DeepCopyInto(out *Output) {
 	*out = *in
 	out.ObjectSelector = in.ObjectSelector
+	if in.OutputSettings != nil {
+		in, out := &in.OutputSettings, &out.OutputSettings
+		*out = new(v1.ElasticsearchOutputSettings)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Output.
@@ -287,6 +374,45 @@ func (in *Output) DeepCopy() *Output {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackagePolicy) DeepCopyInto(out *PackagePolicy) {
+	*out = *in
+	out.Package = in.Package
+	if in.Vars != nil {
+		in, out := &in.Vars, &out.Vars
+		*out = (*in).DeepCopy()
+	}
+	if in.Inputs != nil {
+		in, out := &in.Inputs, &out.Inputs
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackagePolicy.
+func (in *PackagePolicy) DeepCopy() *PackagePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(PackagePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageReference) DeepCopyInto(out *PackageReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageReference.
+func (in *PackageReference) DeepCopy() *PackageReference {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StatefulSetSpec) DeepCopyInto(out *StatefulSetSpec) {
 	*out = *in
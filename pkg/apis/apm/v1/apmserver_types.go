@@ -94,8 +94,12 @@ type ApmServerStatus struct {
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="health",type="string",JSONPath=".status.health"
 // +kubebuilder:printcolumn:name="nodes",type="integer",JSONPath=".status.availableNodes",description="Available nodes"
+// +kubebuilder:printcolumn:name="expected",type="integer",JSONPath=".spec.count",description="Expected nodes"
 // +kubebuilder:printcolumn:name="version",type="string",JSONPath=".status.version",description="APM version"
 // +kubebuilder:printcolumn:name="age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="elasticsearch",type="string",JSONPath=".status.elasticsearchAssociationStatus",description="Elasticsearch association status",priority=1
+// +kubebuilder:printcolumn:name="kibana",type="string",JSONPath=".status.kibanaAssociationStatus",description="Kibana association status",priority=1
+// +kubebuilder:printcolumn:name="reconciled",type="date",JSONPath=".status.conditions[?(@.type=='Reconciling')].lastTransitionTime",description="Time of the last transition of the Reconciling condition",priority=1
 // +kubebuilder:subresource:scale:specpath=.spec.count,statuspath=.status.count,selectorpath=.status.selector
 // +kubebuilder:storageversion
 type ApmServer struct {
@@ -247,7 +251,7 @@ func (aes *ApmEsAssociation) SetAssociationConf(assocConf *commonv1.AssociationC
 }
 
 func (aes *ApmEsAssociation) SupportsAuthAPIKey() bool {
-	return false
+	return true
 }
 
 func (aes *ApmEsAssociation) AssociationID() string {
@@ -43,6 +43,19 @@ var (
 	updateChecks = []func(old, curr *ApmServer) field.ErrorList{
 		checkNoDowngrade,
 	}
+
+	warningChecks = []func(*ApmServer) field.ErrorList{
+		checkDeprecatedAuthSettings,
+	}
+
+	// deprecatedAuthSettingsVersion is the version from which the authorization settings are grouped under apm-server.auth.
+	deprecatedAuthSettingsVersion = version.From(7, 14, 0)
+
+	// deprecatedAuthSettings are the deprecated authorization settings along with their replacement.
+	deprecatedAuthSettings = [][2]string{
+		{"apm-server.secret_token", "apm-server.auth.secret_token"},
+		{"apm-server.api_key.enabled", "apm-server.auth.api_key.enabled"},
+	}
 )
 
 // +kubebuilder:webhook:path=/validate-apm-k8s-elastic-co-v1-apmserver,mutating=false,failurePolicy=ignore,groups=apm.k8s.elastic.co,resources=apmservers,verbs=create;update,versions=v1,name=elastic-apm-validation-v1.k8s.elastic.co,sideEffects=None,admissionReviewVersions=v1;v1beta1,matchPolicy=Exact
@@ -80,6 +93,18 @@ func (as *ApmServer) WebhookPath() string {
 	return webhookPath
 }
 
+// GetWarnings returns the admission warnings raised by the APM Server specification.
+func (as *ApmServer) GetWarnings() []string {
+	if as == nil {
+		return nil
+	}
+	var warnings field.ErrorList
+	for _, wc := range warningChecks {
+		warnings = append(warnings, wc(as)...)
+	}
+	return commonv1.ToWarnings(Kind, as, warnings)
+}
+
 func (as *ApmServer) validate(old *ApmServer) (admission.Warnings, error) {
 	var errors field.ErrorList
 	if old != nil {
@@ -152,3 +177,19 @@ func checkAssociations(as *ApmServer) field.ErrorList {
 	err2 := commonv1.CheckAssociationRefs(field.NewPath("spec").Child("kibanaRef"), as.Spec.KibanaRef)
 	return append(err1, err2...)
 }
+
+func checkDeprecatedAuthSettings(as *ApmServer) field.ErrorList {
+	apmVersion, err := commonv1.ParseVersion(as.EffectiveVersion())
+	if err != nil || !apmVersion.GTE(deprecatedAuthSettingsVersion) {
+		// invalid versions are reported by the validation checks
+		return nil
+	}
+	var errs field.ErrorList
+	for _, setting := range deprecatedAuthSettings {
+		errs = append(errs, commonv1.CheckConfigSettings(
+			field.NewPath("spec").Child("config"), as.Spec.Config, []string{setting[0]},
+			fmt.Sprintf("Setting is deprecated from version %s, use %s instead", deprecatedAuthSettingsVersion, setting[1]),
+		)...)
+	}
+	return errs
+}
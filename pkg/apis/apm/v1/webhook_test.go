@@ -257,3 +257,46 @@ func serialize(t *testing.T, apm *apmv1.ApmServer) []byte {
 
 	return objBytes
 }
+
+func TestApmServer_GetWarnings(t *testing.T) {
+	withConfig := func(version string, cfg map[string]interface{}) *apmv1.ApmServer {
+		apm := mkApmServer("uid")
+		apm.Namespace = "ns"
+		apm.Spec.Version = version
+		apm.Spec.Config = &commonv1.Config{Data: cfg}
+		return apm
+	}
+	tests := []struct {
+		name string
+		apm  *apmv1.ApmServer
+		want []string
+	}{
+		{
+			name: "no config",
+			apm:  mkApmServer("uid"),
+		},
+		{
+			name: "legacy settings before their deprecation",
+			apm:  withConfig("7.13.0", map[string]interface{}{"apm-server.secret_token": "token"}),
+		},
+		{
+			name: "auth settings",
+			apm:  withConfig("8.15.0", map[string]interface{}{"apm-server.auth.secret_token": "token"}),
+		},
+		{
+			name: "legacy settings after their deprecation",
+			apm: withConfig("8.15.0", map[string]interface{}{
+				"apm-server": map[string]interface{}{"secret_token": "token", "api_key.enabled": true},
+			}),
+			want: []string{
+				"ApmServer ns/webhook-test: spec.config.apm-server.secret_token: Setting is deprecated from version 7.14.0, use apm-server.auth.secret_token instead",
+				"ApmServer ns/webhook-test: spec.config.apm-server.api_key.enabled: Setting is deprecated from version 7.14.0, use apm-server.auth.api_key.enabled instead",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.apm.GetWarnings())
+		})
+	}
+}
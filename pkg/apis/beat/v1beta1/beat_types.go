@@ -12,6 +12,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/stackmon/monitoring"
 )
 
@@ -41,10 +42,21 @@ type BeatSpec struct {
 	// +kubebuilder:validation:Optional
 	ElasticsearchRef commonv1.ObjectSelector `json:"elasticsearchRef,omitempty"`
 
+	// ElasticsearchOutput holds tuning settings of the Elasticsearch output generated for ElasticsearchRef.
+	// These settings cannot be set in the Beat configuration at the same time.
+	// +kubebuilder:validation:Optional
+	ElasticsearchOutput *commonv1.ElasticsearchOutputSettings `json:"elasticsearchOutput,omitempty"`
+
 	// KibanaRef is a reference to a Kibana instance running in the same Kubernetes cluster.
 	// It allows automatic setup of dashboards and visualizations.
 	KibanaRef commonv1.ObjectSelector `json:"kibanaRef,omitempty"`
 
+	// LogstashRef is a reference to a Logstash instance running in the same Kubernetes cluster, to be used as the Beat
+	// output. ServiceName must be set to the name of the Logstash Service exposing the Beats input of the pipeline.
+	// Cannot be used together with ElasticsearchRef.
+	// +kubebuilder:validation:Optional
+	LogstashRef commonv1.ObjectSelector `json:"logstashRef,omitempty"`
+
 	// Image is the Beat Docker image to deploy. Version and Type have to match the Beat in the image.
 	// +kubebuilder:validation:Optional
 	Image string `json:"image,omitempty"`
@@ -126,6 +138,9 @@ type BeatStatus struct {
 	// +kubebuilder:validation:Optional
 	KibanaAssociationStatus commonv1.AssociationStatus `json:"kibanaAssociationStatus,omitempty"`
 
+	// +kubebuilder:validation:Optional
+	LogstashAssociationStatus commonv1.AssociationStatus `json:"logstashAssociationStatus,omitempty"`
+
 	// +kubebuilder:validation:Optional
 	MonitoringAssociationsStatus commonv1.AssociationStatusMap `json:"monitoringAssociationStatus,omitempty"`
 
@@ -135,6 +150,10 @@ type BeatStatus struct {
 	// controller has not yet processed the changes contained in the Beats specification.
 	// +kubebuilder:validation:Optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions holds the current service state of the resource.
+	// +kubebuilder:validation:Optional
+	Conditions commonv1alpha1.Conditions `json:"conditions,omitempty"`
 }
 
 type BeatHealth string
@@ -165,6 +184,9 @@ const (
 // +kubebuilder:printcolumn:name="type",type="string",JSONPath=".spec.type",description="Beat type"
 // +kubebuilder:printcolumn:name="version",type="string",JSONPath=".status.version",description="Beat version"
 // +kubebuilder:printcolumn:name="age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="elasticsearch",type="string",JSONPath=".status.elasticsearchAssociationStatus",description="Elasticsearch association status",priority=1
+// +kubebuilder:printcolumn:name="kibana",type="string",JSONPath=".status.kibanaAssociationStatus",description="Kibana association status",priority=1
+// +kubebuilder:printcolumn:name="reconciled",type="date",JSONPath=".status.conditions[?(@.type=='Reconciling')].lastTransitionTime",description="Time of the last transition of the Reconciling condition",priority=1
 // +kubebuilder:storageversion
 type Beat struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -174,6 +196,7 @@ type Beat struct {
 	Status      BeatStatus                `json:"status,omitempty"`
 	esAssocConf *commonv1.AssociationConf `json:"-"`
 	kbAssocConf *commonv1.AssociationConf `json:"-"`
+	lsAssocConf *commonv1.AssociationConf `json:"-"`
 	// monitoringAssocConf holds the configuration for the monitoring Elasticsearch clusters association
 	monitoringAssocConfs map[commonv1.ObjectSelector]commonv1.AssociationConf `json:"-"`
 }
@@ -188,6 +211,10 @@ func (b *Beat) AssociationStatusMap(typ commonv1.AssociationType) commonv1.Assoc
 		if b.Spec.KibanaRef.IsDefined() {
 			return commonv1.NewSingleAssociationStatusMap(b.Status.KibanaAssociationStatus)
 		}
+	case commonv1.LogstashAssociationType:
+		if b.Spec.LogstashRef.IsDefined() {
+			return commonv1.NewSingleAssociationStatusMap(b.Status.LogstashAssociationStatus)
+		}
 	case commonv1.BeatMonitoringAssociationType:
 		if monitoring.IsDefined(b) {
 			return b.Status.MonitoringAssociationsStatus
@@ -210,6 +237,9 @@ func (b *Beat) SetAssociationStatusMap(typ commonv1.AssociationType, status comm
 	case commonv1.KibanaAssociationType:
 		b.Status.KibanaAssociationStatus = single
 		return nil
+	case commonv1.LogstashAssociationType:
+		b.Status.LogstashAssociationStatus = single
+		return nil
 	case commonv1.BeatMonitoringAssociationType:
 		b.Status.MonitoringAssociationsStatus = status
 		return nil
@@ -237,6 +267,11 @@ func (b *Beat) GetAssociations() []commonv1.Association {
 			Beat: b,
 		})
 	}
+	if b.Spec.LogstashRef.IsDefined() {
+		associations = append(associations, &BeatLogstashAssociation{
+			Beat: b,
+		})
+	}
 	for _, ref := range b.Spec.Monitoring.Metrics.ElasticsearchRefs {
 		if ref.IsDefined() {
 			associations = append(associations, &BeatMonitoringAssociation{
@@ -369,6 +404,50 @@ func (b *BeatKibanaAssociation) AssociationID() string {
 	return commonv1.SingletonAssociationID
 }
 
+type BeatLogstashAssociation struct {
+	*Beat
+}
+
+var _ commonv1.Association = &BeatLogstashAssociation{}
+
+func (b *BeatLogstashAssociation) AssociationConf() (*commonv1.AssociationConf, error) {
+	return commonv1.GetAndSetAssociationConf(b, b.lsAssocConf)
+}
+
+func (b *BeatLogstashAssociation) SetAssociationConf(conf *commonv1.AssociationConf) {
+	b.lsAssocConf = conf
+}
+
+func (b *BeatLogstashAssociation) Associated() commonv1.Associated {
+	if b == nil {
+		return nil
+	}
+	if b.Beat == nil {
+		b.Beat = &Beat{}
+	}
+	return b.Beat
+}
+
+func (b *BeatLogstashAssociation) AssociationType() commonv1.AssociationType {
+	return commonv1.LogstashAssociationType
+}
+
+func (b *BeatLogstashAssociation) AssociationRef() commonv1.ObjectSelector {
+	return b.Spec.LogstashRef.WithDefaultNamespace(b.Namespace)
+}
+
+func (b *BeatLogstashAssociation) AssociationConfAnnotationName() string {
+	return commonv1.FormatNameWithID(commonv1.LogstashConfigAnnotationNameBase+"%s", b.AssociationID())
+}
+
+func (b *BeatLogstashAssociation) SupportsAuthAPIKey() bool {
+	return false
+}
+
+func (b *BeatLogstashAssociation) AssociationID() string {
+	return commonv1.SingletonAssociationID
+}
+
 func (b *Beat) SecureSettings() []commonv1.SecretSource {
 	return b.Spec.SecureSettings
 }
@@ -453,6 +532,10 @@ func (b *Beat) GetMonitoringMetricsRefs() []commonv1.ObjectSelector {
 	return b.Spec.Monitoring.Metrics.ElasticsearchRefs
 }
 
+func (b *Beat) GetMonitoringMetricsDestinations() []commonv1.MetricsDestination {
+	return b.Spec.Monitoring.Metrics.Destinations
+}
+
 func (b *Beat) GetMonitoringLogsRefs() []commonv1.ObjectSelector {
 	return b.Spec.Monitoring.Logs.ElasticsearchRefs
 }
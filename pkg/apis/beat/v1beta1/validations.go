@@ -5,6 +5,7 @@
 package v1beta1
 
 import (
+	"fmt"
 	"regexp"
 
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -25,6 +26,8 @@ var (
 		checkSingleConfigSource,
 		checkSpec,
 		checkAssociations,
+		checkLogstashRef,
+		checkElasticsearchOutput,
 		checkMonitoring,
 	}
 
@@ -32,6 +35,10 @@ var (
 		checkNoDowngrade,
 	}
 
+	warningChecks = []func(*Beat) field.ErrorList{
+		checkOverriddenOutputSettings,
+	}
+
 	typeRegex = regexp.MustCompile("^[a-zA-Z0-9-]+$")
 )
 
@@ -117,9 +124,64 @@ func checkAssociations(b *Beat) field.ErrorList {
 	err2 := commonv1.CheckAssociationRefs(field.NewPath("spec").Child("kibanaRef"), b.Spec.KibanaRef)
 	err3 := commonv1.CheckAssociationRefs(monitoringPath.Child("metrics"), b.GetMonitoringMetricsRefs()...)
 	err4 := commonv1.CheckAssociationRefs(monitoringPath.Child("logs"), b.GetMonitoringLogsRefs()...)
-	return append(err1, append(err2, append(err3, err4...)...)...)
+	err5 := commonv1.CheckAssociationRefs(field.NewPath("spec").Child("logstashRef"), b.Spec.LogstashRef)
+	return append(err1, append(err2, append(err3, append(err4, err5...)...)...)...)
+}
+
+func checkLogstashRef(b *Beat) field.ErrorList {
+	if !b.Spec.LogstashRef.IsDefined() {
+		return nil
+	}
+	var errs field.ErrorList
+	if b.Spec.ElasticsearchRef.IsDefined() {
+		msg := "Specify at most one of [`elasticsearchRef`, `logstashRef`] as the Beat output, not both"
+		errs = append(errs,
+			field.Forbidden(field.NewPath("spec").Child("elasticsearchRef"), msg),
+			field.Forbidden(field.NewPath("spec").Child("logstashRef"), msg),
+		)
+	}
+	if !b.Spec.LogstashRef.IsExternal() && b.Spec.LogstashRef.ServiceName == "" {
+		errs = append(errs, field.Required(
+			field.NewPath("spec").Child("logstashRef").Child("serviceName"),
+			"serviceName must reference the Logstash Service exposing the Beats input",
+		))
+	}
+	return errs
+}
+
+func checkElasticsearchOutput(b *Beat) field.ErrorList {
+	if b.Spec.ElasticsearchOutput == nil {
+		return nil
+	}
+	path := field.NewPath("spec").Child("elasticsearchOutput")
+	if !b.Spec.ElasticsearchRef.IsDefined() {
+		return field.ErrorList{
+			field.Forbidden(path, "elasticsearchOutput can only be set together with elasticsearchRef"),
+		}
+	}
+	return b.Spec.ElasticsearchOutput.Validate(path, field.NewPath("spec").Child("config"), b.Spec.Config, "output.elasticsearch")
 }
 
 func checkMonitoring(b *Beat) field.ErrorList {
 	return validations.Validate(b, b.Spec.Version, validations.MinStackVersion)
 }
+
+func checkOverriddenOutputSettings(b *Beat) field.ErrorList {
+	path := field.NewPath("spec").Child("config")
+	msg := "Setting overrides the output settings managed by the operator for the %s"
+	var errs field.ErrorList
+	if b.Spec.ElasticsearchRef.IsDefined() {
+		errs = append(errs, commonv1.CheckConfigSettings(path, b.Spec.Config, []string{
+			"output.elasticsearch.hosts",
+			"output.elasticsearch.username",
+			"output.elasticsearch.password",
+			"output.elasticsearch.api_key",
+		}, fmt.Sprintf(msg, "elasticsearchRef"))...)
+	}
+	if b.Spec.LogstashRef.IsDefined() {
+		errs = append(errs, commonv1.CheckConfigSettings(path, b.Spec.Config, []string{
+			"output.logstash.hosts",
+		}, fmt.Sprintf(msg, "logstashRef"))...)
+	}
+	return errs
+}
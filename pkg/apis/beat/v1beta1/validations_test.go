@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 )
@@ -195,6 +196,147 @@ func Test_checkAssociations(t *testing.T) {
 	}
 }
 
+func Test_checkLogstashRef(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    BeatSpec
+		wantErr bool
+	}{
+		{
+			name:    "no logstashRef: OK",
+			spec:    BeatSpec{ElasticsearchRef: commonv1.ObjectSelector{Name: "es"}},
+			wantErr: false,
+		},
+		{
+			name: "logstashRef with monitoring elasticsearchRefs: OK",
+			spec: BeatSpec{
+				LogstashRef: commonv1.ObjectSelector{Name: "ls", ServiceName: "ls-ls-beats"},
+				Monitoring: commonv1.Monitoring{
+					Metrics: commonv1.MetricsMonitoring{ElasticsearchRefs: []commonv1.ObjectSelector{{Name: "monitoring"}}},
+					Logs:    commonv1.LogsMonitoring{ElasticsearchRefs: []commonv1.ObjectSelector{{Name: "monitoring"}}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "secret named logstashRef without serviceName: OK",
+			spec:    BeatSpec{LogstashRef: commonv1.ObjectSelector{SecretName: "ls"}},
+			wantErr: false,
+		},
+		{
+			name: "logstashRef and elasticsearchRef: NOK",
+			spec: BeatSpec{
+				ElasticsearchRef: commonv1.ObjectSelector{Name: "es"},
+				LogstashRef:      commonv1.ObjectSelector{Name: "ls", ServiceName: "ls-ls-beats"},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "logstashRef without serviceName: NOK",
+			spec:    BeatSpec{LogstashRef: commonv1.ObjectSelector{Name: "ls"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := checkLogstashRef(&Beat{Spec: tt.spec})
+			if (len(errs) != 0) != tt.wantErr {
+				t.Errorf("checkLogstashRef() errors = %v, wantErr %v", errs, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_checkElasticsearchOutput(t *testing.T) {
+	esRef := commonv1.ObjectSelector{Name: "es"}
+	tests := []struct {
+		name string
+		spec BeatSpec
+		want []string
+	}{
+		{
+			name: "no output settings: OK",
+			spec: BeatSpec{ElasticsearchRef: esRef},
+		},
+		{
+			name: "output settings with elasticsearchRef: OK",
+			spec: BeatSpec{
+				ElasticsearchRef:    esRef,
+				ElasticsearchOutput: &commonv1.ElasticsearchOutputSettings{Workers: ptr.To[int32](2), ProxyURL: "socks5://proxy:1080"},
+				Config:              &commonv1.Config{Data: map[string]interface{}{"output.elasticsearch.ssl.verification_mode": "none"}},
+			},
+		},
+		{
+			name: "output settings without elasticsearchRef: NOK",
+			spec: BeatSpec{ElasticsearchOutput: &commonv1.ElasticsearchOutputSettings{Workers: ptr.To[int32](2)}},
+			want: []string{"spec.elasticsearchOutput"},
+		},
+		{
+			name: "invalid proxy URL: NOK",
+			spec: BeatSpec{ElasticsearchRef: esRef, ElasticsearchOutput: &commonv1.ElasticsearchOutputSettings{ProxyURL: "proxy:3128"}},
+			want: []string{"spec.elasticsearchOutput.proxyURL"},
+		},
+		{
+			name: "output settings also set in the configuration: NOK",
+			spec: BeatSpec{
+				ElasticsearchRef:    esRef,
+				ElasticsearchOutput: &commonv1.ElasticsearchOutputSettings{Workers: ptr.To[int32](2), BulkMaxSize: ptr.To[int32](1600)},
+				Config: &commonv1.Config{Data: map[string]interface{}{"output": map[string]interface{}{
+					"elasticsearch": map[string]interface{}{"workers": 4, "bulk_max_size": 100, "compression_level": 3},
+				}}},
+			},
+			want: []string{"spec.config.output.elasticsearch.bulk_max_size", "spec.config.output.elasticsearch.workers"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fields []string
+			for _, err := range checkElasticsearchOutput(&Beat{Spec: tt.spec}) {
+				fields = append(fields, err.Field)
+			}
+			require.Equal(t, tt.want, fields)
+		})
+	}
+}
+
+func Test_checkOverriddenOutputSettings(t *testing.T) {
+	esOutput := &commonv1.Config{Data: map[string]interface{}{"output.elasticsearch.hosts": []string{"https://es:9200"}}}
+	lsOutput := &commonv1.Config{Data: map[string]interface{}{"output.logstash": map[string]interface{}{"hosts": []string{"ls:5044"}}}}
+	tests := []struct {
+		name string
+		spec BeatSpec
+		want []string
+	}{
+		{
+			name: "output settings without association: OK",
+			spec: BeatSpec{Config: esOutput},
+		},
+		{
+			name: "other output settings with elasticsearchRef: OK",
+			spec: BeatSpec{ElasticsearchRef: commonv1.ObjectSelector{Name: "es"}, Config: lsOutput},
+		},
+		{
+			name: "elasticsearch output settings with elasticsearchRef: warning",
+			spec: BeatSpec{ElasticsearchRef: commonv1.ObjectSelector{Name: "es"}, Config: esOutput},
+			want: []string{"spec.config.output.elasticsearch.hosts"},
+		},
+		{
+			name: "logstash output settings with logstashRef: warning",
+			spec: BeatSpec{LogstashRef: commonv1.ObjectSelector{Name: "ls", ServiceName: "ls-ls-beats"}, Config: lsOutput},
+			want: []string{"spec.config.output.logstash.hosts"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fields []string
+			for _, err := range checkOverriddenOutputSettings(&Beat{Spec: tt.spec}) {
+				fields = append(fields, err.Field)
+			}
+			require.Equal(t, tt.want, fields)
+		})
+	}
+}
+
 func Test_checkNoDowngrade(t *testing.T) {
 	type args struct {
 		prev *Beat
@@ -14,6 +14,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
 )
 
@@ -62,6 +63,18 @@ func (b *Beat) WebhookPath() string {
 	return webhookPath
 }
 
+// GetWarnings returns the admission warnings raised by the Beat specification.
+func (b *Beat) GetWarnings() []string {
+	if b == nil {
+		return nil
+	}
+	var warnings field.ErrorList
+	for _, wc := range warningChecks {
+		warnings = append(warnings, wc(b)...)
+	}
+	return commonv1.ToWarnings(Kind, b, warnings)
+}
+
 func (b *Beat) validate(old *Beat) (admission.Warnings, error) {
 	var errors field.ErrorList
 	if old != nil {
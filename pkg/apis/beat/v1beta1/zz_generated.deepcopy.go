@@ -10,6 +10,7 @@ package v1beta1
 
 import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -30,6 +31,11 @@ func (in *Beat) DeepCopyInto(out *Beat) {
 		*out = new(v1.AssociationConf)
 		**out = **in
 	}
+	if in.lsAssocConf != nil {
+		in, out := &in.lsAssocConf, &out.lsAssocConf
+		*out = new(v1.AssociationConf)
+		**out = **in
+	}
 	if in.monitoringAssocConfs != nil {
 		in, out := &in.monitoringAssocConfs, &out.monitoringAssocConfs
 		*out = make(map[v1.ObjectSelector]v1.AssociationConf, len(*in))
@@ -129,6 +135,26 @@ func (in *BeatList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BeatLogstashAssociation) DeepCopyInto(out *BeatLogstashAssociation) {
+	*out = *in
+	if in.Beat != nil {
+		in, out := &in.Beat, &out.Beat
+		*out = new(Beat)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BeatLogstashAssociation.
+func (in *BeatLogstashAssociation) DeepCopy() *BeatLogstashAssociation {
+	if in == nil {
+		return nil
+	}
+	out := new(BeatLogstashAssociation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BeatMonitoringAssociation) DeepCopyInto(out *BeatMonitoringAssociation) {
 	*out = *in
@@ -154,7 +180,13 @@ func (in *BeatMonitoringAssociation) DeepCopy() *BeatMonitoringAssociation {
 func (in *BeatSpec) DeepCopyInto(out *BeatSpec) {
 	*out = *in
 	out.ElasticsearchRef = in.ElasticsearchRef
+	if in.ElasticsearchOutput != nil {
+		in, out := &in.ElasticsearchOutput, &out.ElasticsearchOutput
+		*out = new(v1.ElasticsearchOutputSettings)
+		(*in).DeepCopyInto(*out)
+	}
 	out.KibanaRef = in.KibanaRef
+	out.LogstashRef = in.LogstashRef
 	if in.Config != nil {
 		in, out := &in.Config, &out.Config
 		*out = (*in).DeepCopy()
@@ -209,6 +241,13 @@ func (in *BeatStatus) DeepCopyInto(out *BeatStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(v1alpha1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BeatStatus.
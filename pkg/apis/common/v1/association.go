@@ -110,8 +110,13 @@ const (
 	BeatAssociationType           = "beat"
 	BeatMonitoringAssociationType = "beat-monitoring"
 
+	LogstashConfigAnnotationNameBase  = "association.k8s.elastic.co/ls-conf"
+	LogstashAssociationType           = "logstash"
 	LogstashMonitoringAssociationType = "ls-monitoring"
 
+	ApmServerConfigAnnotationNameBase = "association.k8s.elastic.co/apm-conf"
+	ApmServerAssociationType          = "apm-server"
+
 	AssociationUnknown     AssociationStatus = ""
 	AssociationPending     AssociationStatus = "Pending"
 	AssociationEstablished AssociationStatus = "Established"
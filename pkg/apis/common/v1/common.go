@@ -15,6 +15,8 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
 )
 
 type DeploymentHealth string
@@ -38,6 +40,9 @@ type DeploymentStatus struct {
 	Version string `json:"version,omitempty"`
 	// Health of the deployment.
 	Health DeploymentHealth `json:"health,omitempty"`
+	// Conditions holds the current service state of the resource.
+	// +optional
+	Conditions commonv1alpha1.Conditions `json:"conditions,omitempty"`
 }
 
 // IsDegraded returns true if the current status is worse than the previous.
@@ -45,6 +50,11 @@ func (ds DeploymentStatus) IsDegraded(prev DeploymentStatus) bool {
 	return prev.Health == GreenHealth && ds.Health != GreenHealth
 }
 
+// ResourceState returns the state from which the standard conditions of the resource running the deployment are computed.
+func (ds DeploymentStatus) ResourceState(generation int64, desiredVersion string) commonv1alpha1.ResourceState {
+	return commonv1alpha1.NewResourceState(generation, string(ds.Health), ds.AvailableNodes, ds.Count, desiredVersion, ds.Version)
+}
+
 // ConfigMapRef is a reference to a config map that exists in the same namespace as the referring resource.
 type ConfigMapRef struct {
 	ConfigMapName string `json:"configMapName,omitempty"`
@@ -197,16 +207,76 @@ type HTTPConfig struct {
 	Service ServiceTemplate `json:"service,omitempty"`
 	// TLS defines options for configuring TLS for HTTP.
 	TLS TLSOptions `json:"tls,omitempty"`
+	// ServiceMesh integrates the HTTP layer with a service mesh. When set to istio, the operator delegates the encryption
+	// of the HTTP traffic to the Istio mutual TLS: the TLS options are ignored, the probes and the operator use plain HTTP,
+	// and the Pods are annotated for the Istio sidecar.
+	// +kubebuilder:validation:Optional
+	ServiceMesh ServiceMesh `json:"serviceMesh,omitempty"`
+	// Gateway configures the Gateway API route reconciled by the operator to expose the HTTP Service through Gateways:
+	// a TLSRoute passing the TLS connections through to the resource if TLS is enabled, an HTTPRoute otherwise.
+	// +kubebuilder:validation:Optional
+	Gateway *GatewayConfig `json:"gateway,omitempty"`
+}
+
+// GatewayConfig configures the Gateway API route exposing the HTTP Service of a resource.
+type GatewayConfig struct {
+	// ParentRefs are the Gateways, or the listeners of Gateways, the route is attached to.
+	// +kubebuilder:validation:MinItems=1
+	ParentRefs []GatewayParentRef `json:"parentRefs"`
+	// Hostnames matched by the route. With TLS enabled, the hostnames are matched against the SNI of the TLS connections
+	// and must be part of the certificate of the resource.
+	// +kubebuilder:validation:Optional
+	Hostnames []string `json:"hostnames,omitempty"`
 }
 
+// GatewayParentRef references a Gateway, or a listener of a Gateway, a route is attached to.
+type GatewayParentRef struct {
+	// Name of the Gateway.
+	Name string `json:"name"`
+	// Namespace of the Gateway. Defaults to the namespace of the resource.
+	// +kubebuilder:validation:Optional
+	Namespace string `json:"namespace,omitempty"`
+	// SectionName is the name of the listener of the Gateway the route is attached to. If empty, the route is attached
+	// to all the listeners of the Gateway accepting it.
+	// +kubebuilder:validation:Optional
+	SectionName string `json:"sectionName,omitempty"`
+	// Port of the listener of the Gateway the route is attached to.
+	// +kubebuilder:validation:Optional
+	Port *int32 `json:"port,omitempty"`
+}
+
+// ServiceMesh is the service mesh the HTTP layer of a resource is integrated with.
+// +kubebuilder:validation:Enum=istio
+type ServiceMesh string
+
+const (
+	// IstioServiceMesh delegates the encryption of the HTTP traffic to the Istio mutual TLS.
+	IstioServiceMesh ServiceMesh = "istio"
+)
+
 // Protocol returns the inferrred protocol (http or https) for this configuration.
 func (http HTTPConfig) Protocol() string {
-	if http.TLS.Enabled() {
+	if http.TLSEnabled() {
 		return "https"
 	}
 	return "http"
 }
 
+// TLSEnabled returns true when TLS is enabled on the HTTP layer by the operator, which is not the case if the encryption
+// is delegated to a service mesh.
+func (http HTTPConfig) TLSEnabled() bool {
+	return http.ServiceMesh == "" && http.TLS.Enabled()
+}
+
+// EffectiveTLS returns the TLS options applied by the operator, with TLS disabled if the encryption is delegated to a
+// service mesh.
+func (http HTTPConfig) EffectiveTLS() TLSOptions {
+	if http.ServiceMesh != "" {
+		return TLSOptions{SelfSignedCertificate: &SelfSignedCertificate{Disabled: true}}
+	}
+	return http.TLS
+}
+
 // TLSOptions holds TLS configuration options.
 type TLSOptions struct {
 	// SelfSignedCertificate allows configuring the self-signed certificate generated by the operator.
@@ -219,12 +289,33 @@ type TLSOptions struct {
 	// - `tls.crt`: The certificate (or a chain).
 	// - `tls.key`: The private key to the first certificate in the certificate chain.
 	Certificate SecretRef `json:"certificate,omitempty"`
+
+	// IssuerRef is a reference to a cert-manager Issuer or ClusterIssuer. When set, the operator creates a cert-manager
+	// Certificate for the HTTP endpoint and uses the certificate issued by cert-manager instead of a self-signed one.
+	// The operator-generated self-signed certificate is used until the certificate has been issued.
+	// Cannot be combined with Certificate.
+	// +kubebuilder:validation:Optional
+	IssuerRef *CertManagerIssuerRef `json:"issuerRef,omitempty"`
 }
 
 // Enabled returns true when TLS is enabled based on this option struct.
 func (tls TLSOptions) Enabled() bool {
 	selfSigned := tls.SelfSignedCertificate
-	return selfSigned == nil || !selfSigned.Disabled || tls.Certificate.SecretName != ""
+	return selfSigned == nil || !selfSigned.Disabled || tls.Certificate.SecretName != "" || tls.IssuerRef != nil
+}
+
+// CertManagerIssuerRef is a reference to a cert-manager Issuer or ClusterIssuer.
+type CertManagerIssuerRef struct {
+	// Name of the issuer.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// Kind of the issuer, Issuer or ClusterIssuer for the issuers built into cert-manager. Defaults to Issuer.
+	// An Issuer must be in the same namespace as the resource referencing it.
+	// +kubebuilder:validation:Optional
+	Kind string `json:"kind,omitempty"`
+	// Group of the issuer, to be set when using an external issuer. Defaults to cert-manager.io.
+	// +kubebuilder:validation:Optional
+	Group string `json:"group,omitempty"`
 }
 
 // SelfSignedCertificate holds configuration for the self-signed certificate generated by the operator.
@@ -292,6 +383,15 @@ type NamespacedSecretSource struct {
 type SecretSource struct {
 	// SecretName is the name of the secret.
 	SecretName string `json:"secretName"`
+	// Namespace is the namespace of the secret, defaults to the namespace of the resource referencing it.
+	// The ServiceAccount of the resource must be allowed to get the secret if it is in a different namespace.
+	// +kubebuilder:validation:Optional
+	Namespace string `json:"namespace,omitempty"`
+	// Backend is the external secret backend configured in the operator from which the secret is read, instead of a
+	// Kubernetes Secret. SecretName is then the path or the name of the secret in the backend, and Namespace is ignored.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=vault;aws-secrets-manager
+	Backend string `json:"backend,omitempty"`
 	// Entries define how to project each key-value pair in the secret to filesystem paths.
 	// If not defined, all keys will be projected to similarly named paths in the filesystem.
 	// If defined, only the specified keys will be projected to the corresponding paths.
@@ -381,3 +481,80 @@ func IsConfiguredToAllowDowngrades(o metav1.Object) bool {
 	val, exists := o.GetAnnotations()[DisableDowngradeValidationAnnotation]
 	return exists && val == "true"
 }
+
+const (
+	// RestartAnnotation can be set on Elasticsearch, Kibana and Logstash resources to request a restart of all their
+	// Pods, orchestrated by the operator. The value is the type of the restart. The annotation is removed by the
+	// operator once all the Pods are restarted.
+	RestartAnnotation = "eck.k8s.elastic.co/restart"
+	// RestartedAtAnnotation is set by the operator on the resource and on its Pod templates to the start time of the
+	// last requested restart.
+	RestartedAtAnnotation = "eck.k8s.elastic.co/restarted-at"
+)
+
+// RestartType is the type of a restart requested with the eck.k8s.elastic.co/restart annotation.
+type RestartType string
+
+const (
+	// RollingRestart restarts the Pods one at a time, following the same rules as for a rolling upgrade.
+	RollingRestart RestartType = "rolling"
+	// FullRestart restarts all the Pods at once.
+	FullRestart RestartType = "full"
+)
+
+// RestartStatus is the status of a restart requested with the eck.k8s.elastic.co/restart annotation.
+type RestartStatus struct {
+	// Type is the type of the restart.
+	Type RestartType `json:"type"`
+	// StartTime is the time the restart was started.
+	StartTime metav1.Time `json:"startTime"`
+	// RestartedPods is the number of Pods restarted so far.
+	RestartedPods int32 `json:"restartedPods"`
+	// ExpectedPods is the number of Pods to restart.
+	ExpectedPods int32 `json:"expectedPods"`
+}
+
+// Restartable is a resource whose Pods can be restarted with the eck.k8s.elastic.co/restart annotation.
+// +kubebuilder:object:generate=false
+type Restartable interface {
+	client.Object
+	// GetRestartStatus returns the status of the restart in progress, nil if there is none.
+	GetRestartStatus() *RestartStatus
+	// SetRestartStatus sets the status of the restart in progress.
+	SetRestartStatus(status *RestartStatus)
+}
+
+const (
+	// UpgradeGatesAnnotation can be set to true on Elasticsearch and Kibana resources to pause their version upgrades
+	// before each restart of their Pods, until an external analysis tool approves the step.
+	UpgradeGatesAnnotation = "eck.k8s.elastic.co/upgrade-gates"
+	// UpgradeGateApprovedAnnotation approves the step of a version upgrade identified by its value, as reported in the
+	// upgradeGate status of the resource.
+	UpgradeGateApprovedAnnotation = "eck.k8s.elastic.co/upgrade-gate-approved"
+)
+
+// UpgradeGateStatus is the status of a version upgrade paused until an external analysis tool approves its next step
+// with the eck.k8s.elastic.co/upgrade-gate-approved annotation.
+type UpgradeGateStatus struct {
+	// Step identifies the step waiting for approval, in the form <version>/<upgraded Pods>. It is approved by setting the
+	// eck.k8s.elastic.co/upgrade-gate-approved annotation to the same value.
+	Step string `json:"step"`
+	// TargetVersion is the version the resource is upgraded to.
+	TargetVersion string `json:"targetVersion"`
+	// UpgradedPods is the number of Pods already running the target version.
+	UpgradedPods int32 `json:"upgradedPods"`
+	// ExpectedPods is the number of Pods to upgrade.
+	ExpectedPods int32 `json:"expectedPods"`
+	// WaitingSince is the time the step started waiting for approval.
+	WaitingSince metav1.Time `json:"waitingSince"`
+}
+
+// UpgradeGated is a resource whose version upgrades can be paused with the eck.k8s.elastic.co/upgrade-gates annotation.
+// +kubebuilder:object:generate=false
+type UpgradeGated interface {
+	client.Object
+	// GetUpgradeGateStatus returns the status of the step waiting for approval, nil if there is none.
+	GetUpgradeGateStatus() *UpgradeGateStatus
+	// SetUpgradeGateStatus sets the status of the step waiting for approval.
+	SetUpgradeGateStatus(status *UpgradeGateStatus)
+}
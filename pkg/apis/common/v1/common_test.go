@@ -75,7 +75,8 @@ func TestTLSOptions_Enabled(t *testing.T) {
 
 func TestHTTPConfig_Scheme(t *testing.T) {
 	type fields struct {
-		TLS TLSOptions
+		TLS         TLSOptions
+		ServiceMesh ServiceMesh
 	}
 	tests := []struct {
 		name   string
@@ -125,11 +126,24 @@ func TestHTTPConfig_Scheme(t *testing.T) {
 			},
 			want: "https",
 		},
+		{
+			name: "istio service mesh",
+			fields: fields{
+				TLS: TLSOptions{
+					Certificate: SecretRef{
+						SecretName: "my-custom-certs",
+					},
+				},
+				ServiceMesh: IstioServiceMesh,
+			},
+			want: "http",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			http := HTTPConfig{
-				TLS: tt.fields.TLS,
+				TLS:         tt.fields.TLS,
+				ServiceMesh: tt.fields.ServiceMesh,
 			}
 			if got := http.Protocol(); got != tt.want {
 				t.Errorf("Protocol() = %v, want %v", got, tt.want)
@@ -138,6 +152,14 @@ func TestHTTPConfig_Scheme(t *testing.T) {
 	}
 }
 
+func TestHTTPConfig_EffectiveTLS(t *testing.T) {
+	tls := TLSOptions{Certificate: SecretRef{SecretName: "my-custom-certs"}}
+	assert.Equal(t, tls, HTTPConfig{TLS: tls}.EffectiveTLS())
+	effective := HTTPConfig{TLS: tls, ServiceMesh: IstioServiceMesh}.EffectiveTLS()
+	assert.False(t, effective.Enabled())
+	assert.Empty(t, effective.Certificate.SecretName)
+}
+
 func TestObjectSelector_WithDefaultNamespace(t *testing.T) {
 	type fields struct {
 		Name        string
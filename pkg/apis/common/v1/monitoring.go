@@ -23,9 +23,24 @@ type MetricsMonitoring struct {
 	// for more details.
 
 	// ElasticsearchRefs is a reference to a list of monitoring Elasticsearch clusters running in the same Kubernetes cluster.
-	// Due to existing limitations, only a single Elasticsearch cluster is currently supported.
+	// Multiple Elasticsearch clusters are only supported to monitor Elasticsearch, other resources support a single one.
 	// +kubebuilder:validation:Optional
 	ElasticsearchRefs []ObjectSelector `json:"elasticsearchRefs,omitempty"`
+
+	// Destinations optionally restricts the metric sets sent to some of the Elasticsearch clusters referenced in
+	// ElasticsearchRefs. Clusters without a destination receive all the metric sets.
+	// Only supported to monitor Elasticsearch.
+	// +kubebuilder:validation:Optional
+	Destinations []MetricsDestination `json:"destinations,omitempty"`
+}
+
+// MetricsDestination defines the metric sets sent to one of the monitoring Elasticsearch clusters.
+type MetricsDestination struct {
+	// ElasticsearchRef is a reference to one of the Elasticsearch clusters of ElasticsearchRefs.
+	ElasticsearchRef ObjectSelector `json:"elasticsearchRef"`
+	// MetricSets is the list of Metricbeat metric sets sent to this Elasticsearch cluster.
+	// +kubebuilder:validation:MinItems=1
+	MetricSets []string `json:"metricSets"`
 }
 
 // LogsMonitoring holds a list of Elasticsearch clusters which receive logs data from
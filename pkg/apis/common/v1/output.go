@@ -0,0 +1,98 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1
+
+import (
+	"net/url"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const (
+	outputWorkerKey           = "worker"
+	outputBulkMaxSizeKey      = "bulk_max_size"
+	outputCompressionLevelKey = "compression_level"
+	outputProxyURLKey         = "proxy_url"
+	outputLoadBalanceKey      = "loadbalance"
+)
+
+// ElasticsearchOutputSettings holds the tuning settings of an Elasticsearch output generated by the operator.
+type ElasticsearchOutputSettings struct {
+	// Workers is the number of workers per configured host publishing events to Elasticsearch.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	Workers *int32 `json:"workers,omitempty"`
+
+	// BulkMaxSize is the maximum number of events to bulk in a single Elasticsearch bulk API index request.
+	// A value of 0 or less disables the splitting of batches.
+	// +kubebuilder:validation:Optional
+	BulkMaxSize *int32 `json:"bulkMaxSize,omitempty"`
+
+	// CompressionLevel is the gzip compression level of the requests, from 0 (compression disabled) to 9.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=9
+	CompressionLevel *int32 `json:"compressionLevel,omitempty"`
+
+	// ProxyURL is the URL of the proxy used to connect to Elasticsearch, using the http, https or socks5 scheme.
+	// +kubebuilder:validation:Optional
+	ProxyURL string `json:"proxyURL,omitempty"`
+
+	// LoadBalance distributes the events across all the Elasticsearch hosts instead of sending them to a single host.
+	// +kubebuilder:validation:Optional
+	LoadBalance *bool `json:"loadBalance,omitempty"`
+}
+
+// Settings returns the output settings in the format of the Beats and Elastic Agent configuration.
+func (s *ElasticsearchOutputSettings) Settings() map[string]interface{} {
+	settings := map[string]interface{}{}
+	if s == nil {
+		return settings
+	}
+	if s.Workers != nil {
+		settings[outputWorkerKey] = *s.Workers
+	}
+	if s.BulkMaxSize != nil {
+		settings[outputBulkMaxSizeKey] = *s.BulkMaxSize
+	}
+	if s.CompressionLevel != nil {
+		settings[outputCompressionLevelKey] = *s.CompressionLevel
+	}
+	if s.ProxyURL != "" {
+		settings[outputProxyURLKey] = s.ProxyURL
+	}
+	if s.LoadBalance != nil {
+		settings[outputLoadBalanceKey] = *s.LoadBalance
+	}
+	return settings
+}
+
+// Validate checks the output settings, including that they are not also set in the raw configuration of the
+// resource under the given output prefix, as the raw configuration would silently take precedence.
+func (s *ElasticsearchOutputSettings) Validate(path *field.Path, configPath *field.Path, cfg *Config, outputPrefix string) field.ErrorList {
+	if s == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	if s.ProxyURL != "" {
+		u, err := url.Parse(s.ProxyURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5") || u.Host == "" {
+			errs = append(errs, field.Invalid(path.Child("proxyURL"), s.ProxyURL, "proxyURL must be an absolute http, https or socks5 URL"))
+		}
+	}
+	settings := s.Settings()
+	keys := make([]string, 0, len(settings)+1)
+	for key := range settings {
+		keys = append(keys, outputPrefix+"."+key)
+	}
+	if s.Workers != nil {
+		// workers is an alias of worker
+		keys = append(keys, outputPrefix+".workers")
+	}
+	sort.Strings(keys)
+	errs = append(errs, CheckConfigSettings(configPath, cfg, keys, "Setting conflicts with "+path.String()+", remove it from the configuration")...)
+	return errs
+}
@@ -0,0 +1,43 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
+)
+
+// ToWarnings formats the field errors returned by warning checks as admission warnings for the given object.
+func ToWarnings(kind string, obj metav1.Object, errs field.ErrorList) []string {
+	if len(errs) == 0 {
+		return nil
+	}
+	warnings := make([]string, 0, len(errs))
+	for _, err := range errs {
+		warnings = append(warnings, fmt.Sprintf("%s %s/%s: %s: %s", kind, obj.GetNamespace(), obj.GetName(), err.Field, err.Detail))
+	}
+	return warnings
+}
+
+// CheckConfigSettings returns an error with the given detail for each of the given settings set in cfg.
+// It is meant to be used by warning checks, invalid configurations are reported by the validation checks.
+func CheckConfigSettings(path *field.Path, cfg *Config, keys []string, detail string) field.ErrorList {
+	if cfg == nil {
+		return nil
+	}
+	canonicalConfig, err := settings.NewCanonicalConfigFrom(cfg.Data)
+	if err != nil {
+		return nil
+	}
+	var errs field.ErrorList
+	for _, key := range canonicalConfig.HasKeys(keys) {
+		errs = append(errs, field.Forbidden(path.Child(key), detail))
+	}
+	return errs
+}
@@ -0,0 +1,223 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+// Standard conditions reported in the status of all the resources orchestrated by the operator, which allows for
+// example to wait for a resource with `kubectl wait --for=condition=Ready`.
+const (
+	// ReadyCondition is True when the resource is available.
+	ReadyCondition ConditionType = "Ready"
+	// ReconciliationCompleteCondition is True when the operator has applied the whole specification of the resource.
+	ReconciliationCompleteCondition ConditionType = "ReconciliationComplete"
+	// UpgradeInProgressCondition is True while the resource is being upgraded to the version of its specification.
+	UpgradeInProgressCondition ConditionType = "UpgradeInProgress"
+	// DegradedCondition is True when the resource is not running with its full capacity or redundancy.
+	DegradedCondition ConditionType = "Degraded"
+)
+
+// Conditions following the kstatus conventions, from which tools such as Flux or Argo CD compute the health of a
+// resource: a resource whose observed generation is up to date is current unless it is Reconciling or Stalled.
+const (
+	// ReconcilingCondition is True while the operator makes progress towards the specification of the resource.
+	ReconcilingCondition ConditionType = "Reconciling"
+	// StalledCondition is True when the operator cannot make progress without a change of the specification of the
+	// resource or of its environment, for example because the specification is invalid.
+	StalledCondition ConditionType = "Stalled"
+)
+
+// Reasons of the standard conditions.
+const (
+	AvailableReason      = "Available"
+	UnavailableReason    = "Unavailable"
+	ReconciledReason     = "Reconciled"
+	ReconcilingReason    = "Reconciling"
+	UpgradingReason      = "Upgrading"
+	UpToDateReason       = "UpToDate"
+	VersionUnknownReason = "VersionUnknown"
+	HealthyReason        = "Healthy"
+	DegradedReason       = "Degraded"
+	ProgressingReason    = "Progressing"
+	StalledReason        = "Stalled"
+)
+
+// ResourceState is the observed state of a resource from which its standard conditions are computed.
+// +kubebuilder:object:generate=false
+type ResourceState struct {
+	// ObservedGeneration is the generation of the resource the state was observed for.
+	ObservedGeneration int64
+	// Ready is true if the resource is available, ReadyMessage explains why it is not.
+	Ready        bool
+	ReadyMessage string
+	// Degraded is true if the resource is not running with its full capacity or redundancy, DegradedMessage explains why.
+	Degraded        bool
+	DegradedMessage string
+	// Reconciled is true if the whole specification of the resource is applied, ReconciliationMessage explains why it is not.
+	Reconciled            bool
+	ReconciliationMessage string
+	// Stalled is true if the resource cannot be reconciled without a change of its specification or of its environment,
+	// StalledMessage explains why.
+	Stalled        bool
+	StalledMessage string
+	// DesiredVersion is the version in the specification of the resource.
+	DesiredVersion string
+	// RunningVersion is the lowest version currently running.
+	RunningVersion string
+}
+
+// NewResourceState returns the state of a resource from its health, reported as green, yellow or red, from its number
+// of available and expected instances, and from its versions. The resource is ready if its health is green or yellow,
+// and degraded if its health is not green or if some of its instances are not available.
+func NewResourceState(generation int64, health string, available, expected int32, desiredVersion, runningVersion string) ResourceState {
+	state := ResourceState{
+		ObservedGeneration: generation,
+		Ready:              health == "green" || health == "yellow",
+		DesiredVersion:     desiredVersion,
+		RunningVersion:     runningVersion,
+	}
+	if !state.Ready {
+		state.ReadyMessage = fmt.Sprintf("Health is %s", healthOrUnknown(health))
+	}
+	switch {
+	case health != "green":
+		state.Degraded = true
+		state.DegradedMessage = fmt.Sprintf("Health is %s", healthOrUnknown(health))
+	case available < expected:
+		state.Degraded = true
+		state.DegradedMessage = fmt.Sprintf("%d out of %d instances available", available, expected)
+	}
+	return state
+}
+
+// NewPhaseState returns the state of a resource which reports its progress as a phase. The resource is ready and
+// reconciled in its ready phase, and stalled in the phases which require a change of its specification or of its
+// environment. The message detailing the phase is reported in the conditions of a resource which is not ready.
+func NewPhaseState(generation int64, phase string, ready, stalled bool, message string) ResourceState {
+	state := ResourceState{
+		ObservedGeneration: generation,
+		Ready:              ready,
+		Reconciled:         ready,
+		Stalled:            stalled,
+	}
+	if ready {
+		return state
+	}
+	phaseMessage := fmt.Sprintf("Phase is %s", phase)
+	if phase == "" {
+		phaseMessage = "Phase is unknown"
+	}
+	if message != "" {
+		phaseMessage = fmt.Sprintf("%s: %s", phaseMessage, message)
+	}
+	state.ReadyMessage, state.ReconciliationMessage = phaseMessage, phaseMessage
+	if stalled {
+		state.StalledMessage = phaseMessage
+	}
+	return state
+}
+
+func healthOrUnknown(health string) string {
+	if health == "" {
+		return "unknown"
+	}
+	return health
+}
+
+// Set adds the given condition, or replaces the existing condition with the same type. The last transition time of
+// the existing condition is preserved if its status does not change.
+func (c Conditions) Set(condition Condition) Conditions {
+	cp := c.DeepCopy()
+	index := cp.Index(condition.Type)
+	if index < 0 {
+		return append(cp, condition)
+	}
+	if cp[index].Status == condition.Status {
+		condition.LastTransitionTime = cp[index].LastTransitionTime
+	}
+	cp[index] = condition
+	return cp
+}
+
+// WithStandardConditions returns a copy of the conditions in which the standard conditions, including the readiness
+// conditions, are set according to the given state of the resource.
+func (c Conditions) WithStandardConditions(state ResourceState, now metav1.Time) Conditions {
+	newCondition := conditionFactory(state, now)
+	cp := c.WithReadinessConditions(state, now)
+	cp = cp.Set(newCondition(ReconciliationCompleteCondition, state.Reconciled, ReconciledReason, ReconcilingReason, state.ReconciliationMessage))
+	cp = cp.Set(newCondition(DegradedCondition, state.Degraded, DegradedReason, HealthyReason, state.DegradedMessage))
+
+	upgrade := newCondition(UpgradeInProgressCondition, false, UpgradingReason, UpToDateReason, "")
+	upgrade.Status, upgrade.Reason, upgrade.Message = upgradeStatus(state.DesiredVersion, state.RunningVersion)
+	return cp.Set(upgrade)
+}
+
+// WithReadinessConditions returns a copy of the conditions in which the Ready, Reconciling and Stalled conditions are set
+// according to the given state of the resource. The resource is reconciling as long as it is not reconciled, not ready
+// or being upgraded, unless it is stalled.
+func (c Conditions) WithReadinessConditions(state ResourceState, now metav1.Time) Conditions {
+	newCondition := conditionFactory(state, now)
+	cp := c.Set(newCondition(ReadyCondition, state.Ready, AvailableReason, UnavailableReason, state.ReadyMessage))
+
+	reconciling := newCondition(ReconcilingCondition, false, ReconcilingReason, ReconciledReason, "")
+	switch upgrading, _, upgradeMessage := upgradeStatus(state.DesiredVersion, state.RunningVersion); {
+	case state.Stalled:
+		reconciling.Reason = StalledReason
+	case !state.Reconciled:
+		reconciling.Status, reconciling.Reason, reconciling.Message = corev1.ConditionTrue, ReconcilingReason, state.ReconciliationMessage
+	case upgrading == corev1.ConditionTrue:
+		reconciling.Status, reconciling.Reason, reconciling.Message = corev1.ConditionTrue, UpgradingReason, upgradeMessage
+	case !state.Ready:
+		reconciling.Status, reconciling.Reason, reconciling.Message = corev1.ConditionTrue, ReconcilingReason, state.ReadyMessage
+	}
+	cp = cp.Set(reconciling)
+	return cp.Set(newCondition(StalledCondition, state.Stalled, StalledReason, ProgressingReason, state.StalledMessage))
+}
+
+// conditionFactory returns a function creating the conditions observed for the given state.
+func conditionFactory(state ResourceState, now metav1.Time) func(ConditionType, bool, string, string, string) Condition {
+	return func(conditionType ConditionType, status bool, trueReason, falseReason, message string) Condition {
+		condition := Condition{
+			Type:               conditionType,
+			Status:             corev1.ConditionFalse,
+			ObservedGeneration: state.ObservedGeneration,
+			LastTransitionTime: now,
+			Reason:             falseReason,
+			Message:            message,
+		}
+		if status {
+			condition.Status = corev1.ConditionTrue
+			condition.Reason = trueReason
+		}
+		return condition
+	}
+}
+
+// upgradeStatus returns the status, the reason and the message of the UpgradeInProgress condition, which is True if the
+// running version is lower than the desired version.
+func upgradeStatus(desiredVersion, runningVersion string) (corev1.ConditionStatus, string, string) {
+	if runningVersion == "" {
+		return corev1.ConditionUnknown, VersionUnknownReason, "No running version reported"
+	}
+	desired, err := version.Parse(desiredVersion)
+	if err != nil {
+		return corev1.ConditionUnknown, VersionUnknownReason, fmt.Sprintf("Error while parsing desired version: %s", err.Error())
+	}
+	running, err := version.Parse(runningVersion)
+	if err != nil {
+		return corev1.ConditionUnknown, VersionUnknownReason, fmt.Sprintf("Error while parsing running version: %s", err.Error())
+	}
+	if desired.GT(running) {
+		return corev1.ConditionTrue, UpgradingReason, fmt.Sprintf("Upgrading from %s to %s", running, desired)
+	}
+	return corev1.ConditionFalse, UpToDateReason, ""
+}
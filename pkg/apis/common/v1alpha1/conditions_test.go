@@ -0,0 +1,216 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewResourceState(t *testing.T) {
+	tests := []struct {
+		name                string
+		health              string
+		available, expected int32
+		want                ResourceState
+	}{
+		{
+			name:   "green",
+			health: "green", available: 3, expected: 3,
+			want: ResourceState{Ready: true},
+		},
+		{
+			name:   "green with unavailable instances",
+			health: "green", available: 2, expected: 3,
+			want: ResourceState{Ready: true, Degraded: true, DegradedMessage: "2 out of 3 instances available"},
+		},
+		{
+			name:   "yellow",
+			health: "yellow", available: 3, expected: 3,
+			want: ResourceState{Ready: true, Degraded: true, DegradedMessage: "Health is yellow"},
+		},
+		{
+			name:   "red",
+			health: "red", available: 0, expected: 3,
+			want: ResourceState{ReadyMessage: "Health is red", Degraded: true, DegradedMessage: "Health is red"},
+		},
+		{
+			name:   "no health reported",
+			health: "", available: 0, expected: 3,
+			want: ResourceState{ReadyMessage: "Health is unknown", Degraded: true, DegradedMessage: "Health is unknown"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.want.ObservedGeneration = 2
+			tt.want.DesiredVersion = "8.15.0"
+			tt.want.RunningVersion = "8.14.0"
+			require.Equal(t, tt.want, NewResourceState(2, tt.health, tt.available, tt.expected, "8.15.0", "8.14.0"))
+		})
+	}
+}
+
+func TestConditions_Set(t *testing.T) {
+	before := metav1.NewTime(time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC))
+	now := metav1.NewTime(before.Add(time.Hour))
+	conditions := Conditions{
+		{Type: PausedCondition, Status: corev1.ConditionFalse, LastTransitionTime: before},
+		{Type: ReadyCondition, Status: corev1.ConditionFalse, LastTransitionTime: before, Message: "Health is red"},
+	}
+
+	// the status does not change: the last transition time is preserved
+	updated := conditions.Set(Condition{Type: ReadyCondition, Status: corev1.ConditionFalse, LastTransitionTime: now, Message: "Health is unknown"})
+	require.Equal(t, Condition{Type: ReadyCondition, Status: corev1.ConditionFalse, LastTransitionTime: before, Message: "Health is unknown"}, updated[1])
+	// the original conditions are not mutated
+	require.Equal(t, "Health is red", conditions[1].Message)
+
+	// the status changes
+	updated = conditions.Set(Condition{Type: ReadyCondition, Status: corev1.ConditionTrue, LastTransitionTime: now})
+	require.Equal(t, Condition{Type: ReadyCondition, Status: corev1.ConditionTrue, LastTransitionTime: now}, updated[1])
+
+	// new condition
+	updated = conditions.Set(Condition{Type: DegradedCondition, Status: corev1.ConditionTrue, LastTransitionTime: now})
+	require.Len(t, updated, 3)
+	require.Equal(t, DegradedCondition, updated[2].Type)
+}
+
+func TestConditions_WithStandardConditions(t *testing.T) {
+	now := metav1.NewTime(time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC))
+	condition := func(conditionType ConditionType, status corev1.ConditionStatus, reason, message string) Condition {
+		return Condition{Type: conditionType, Status: status, ObservedGeneration: 3, LastTransitionTime: now, Reason: reason, Message: message}
+	}
+	paused := Condition{Type: PausedCondition, Status: corev1.ConditionFalse, LastTransitionTime: now}
+	tests := []struct {
+		name  string
+		state ResourceState
+		want  Conditions
+	}{
+		{
+			name: "ready and reconciled",
+			state: ResourceState{
+				ObservedGeneration: 3, Ready: true, Reconciled: true, DesiredVersion: "8.15.0", RunningVersion: "8.15.0",
+			},
+			want: Conditions{
+				paused,
+				condition(ReadyCondition, corev1.ConditionTrue, AvailableReason, ""),
+				condition(ReconcilingCondition, corev1.ConditionFalse, ReconciledReason, ""),
+				condition(StalledCondition, corev1.ConditionFalse, ProgressingReason, ""),
+				condition(ReconciliationCompleteCondition, corev1.ConditionTrue, ReconciledReason, ""),
+				condition(DegradedCondition, corev1.ConditionFalse, HealthyReason, ""),
+				condition(UpgradeInProgressCondition, corev1.ConditionFalse, UpToDateReason, ""),
+			},
+		},
+		{
+			name: "degraded during an upgrade",
+			state: ResourceState{
+				ObservedGeneration: 3, Ready: true, Degraded: true, DegradedMessage: "2 out of 3 instances available",
+				ReconciliationMessage: "Requeue", DesiredVersion: "8.15.0", RunningVersion: "8.14.0",
+			},
+			want: Conditions{
+				paused,
+				condition(ReadyCondition, corev1.ConditionTrue, AvailableReason, ""),
+				condition(ReconcilingCondition, corev1.ConditionTrue, ReconcilingReason, "Requeue"),
+				condition(StalledCondition, corev1.ConditionFalse, ProgressingReason, ""),
+				condition(ReconciliationCompleteCondition, corev1.ConditionFalse, ReconcilingReason, "Requeue"),
+				condition(DegradedCondition, corev1.ConditionTrue, DegradedReason, "2 out of 3 instances available"),
+				condition(UpgradeInProgressCondition, corev1.ConditionTrue, UpgradingReason, "Upgrading from 8.14.0 to 8.15.0"),
+			},
+		},
+		{
+			name: "not running yet",
+			state: ResourceState{
+				ObservedGeneration: 3, ReadyMessage: "Health is red", Degraded: true, DegradedMessage: "Health is red",
+				Reconciled: true, DesiredVersion: "8.15.0",
+			},
+			want: Conditions{
+				paused,
+				condition(ReadyCondition, corev1.ConditionFalse, UnavailableReason, "Health is red"),
+				condition(ReconcilingCondition, corev1.ConditionTrue, ReconcilingReason, "Health is red"),
+				condition(StalledCondition, corev1.ConditionFalse, ProgressingReason, ""),
+				condition(ReconciliationCompleteCondition, corev1.ConditionTrue, ReconciledReason, ""),
+				condition(DegradedCondition, corev1.ConditionTrue, DegradedReason, "Health is red"),
+				condition(UpgradeInProgressCondition, corev1.ConditionUnknown, VersionUnknownReason, "No running version reported"),
+			},
+		},
+		{
+			name: "upgrading",
+			state: ResourceState{
+				ObservedGeneration: 3, Ready: true, Reconciled: true, DesiredVersion: "8.15.0", RunningVersion: "8.14.0",
+			},
+			want: Conditions{
+				paused,
+				condition(ReadyCondition, corev1.ConditionTrue, AvailableReason, ""),
+				condition(ReconcilingCondition, corev1.ConditionTrue, UpgradingReason, "Upgrading from 8.14.0 to 8.15.0"),
+				condition(StalledCondition, corev1.ConditionFalse, ProgressingReason, ""),
+				condition(ReconciliationCompleteCondition, corev1.ConditionTrue, ReconciledReason, ""),
+				condition(DegradedCondition, corev1.ConditionFalse, HealthyReason, ""),
+				condition(UpgradeInProgressCondition, corev1.ConditionTrue, UpgradingReason, "Upgrading from 8.14.0 to 8.15.0"),
+			},
+		},
+		{
+			name: "invalid specification",
+			state: ResourceState{
+				ObservedGeneration: 3, ReadyMessage: "Health is unknown", Degraded: true, DegradedMessage: "Health is unknown",
+				ReconciliationMessage: "spec.version: Invalid value", Stalled: true, StalledMessage: "spec.version: Invalid value",
+			},
+			want: Conditions{
+				paused,
+				condition(ReadyCondition, corev1.ConditionFalse, UnavailableReason, "Health is unknown"),
+				condition(ReconcilingCondition, corev1.ConditionFalse, StalledReason, ""),
+				condition(StalledCondition, corev1.ConditionTrue, StalledReason, "spec.version: Invalid value"),
+				condition(ReconciliationCompleteCondition, corev1.ConditionFalse, ReconcilingReason, "spec.version: Invalid value"),
+				condition(DegradedCondition, corev1.ConditionTrue, DegradedReason, "Health is unknown"),
+				condition(UpgradeInProgressCondition, corev1.ConditionUnknown, VersionUnknownReason, "No running version reported"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, Conditions{paused}.WithStandardConditions(tt.state, now))
+		})
+	}
+}
+
+func TestNewPhaseState(t *testing.T) {
+	tests := []struct {
+		name    string
+		phase   string
+		ready   bool
+		stalled bool
+		message string
+		want    ResourceState
+	}{
+		{
+			name:  "ready",
+			phase: "Ready",
+			ready: true,
+			want:  ResourceState{ObservedGeneration: 2, Ready: true, Reconciled: true},
+		},
+		{
+			name:  "unknown phase",
+			phase: "",
+			want:  ResourceState{ObservedGeneration: 2, ReadyMessage: "Phase is unknown", ReconciliationMessage: "Phase is unknown"},
+		},
+		{
+			name:    "stalled",
+			phase:   "Invalid",
+			stalled: true,
+			message: "reserved name",
+			want: ResourceState{
+				ObservedGeneration: 2, ReadyMessage: "Phase is Invalid: reserved name", ReconciliationMessage: "Phase is Invalid: reserved name",
+				Stalled: true, StalledMessage: "Phase is Invalid: reserved name",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, NewPhaseState(2, tt.phase, tt.ready, tt.stalled, tt.message))
+		})
+	}
+}
@@ -9,22 +9,33 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// Condition represents Elasticsearch resource's condition.
+// Condition represents a condition of a resource orchestrated by the operator. It follows the conventions of
+// metav1.Condition.
 // **This API is in technical preview and may be changed or removed in a future release.**
 type Condition struct {
 	Type   ConditionType          `json:"type"`
 	Status corev1.ConditionStatus `json:"status"`
+	// ObservedGeneration is the .metadata.generation of the resource the condition was set for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 	// +optional
 	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// Reason is a programmatic identifier, in CamelCase, of the reason for the last transition of the condition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
 	// +optional
 	Message string `json:"message,omitempty"`
 }
 
 type Conditions []Condition
 
-// ConditionType defines the condition of an Elasticsearch resource.
+// ConditionType defines the condition of a resource.
 type ConditionType string
 
+// PausedCondition is True while the reconciliation of a resource is suspended by the operator, because the resource is
+// annotated with eck.k8s.elastic.co/managed=false.
+const PausedCondition ConditionType = "Paused"
+
 func (c Conditions) Index(conditionType ConditionType) int {
 	for i, condition := range c {
 		if condition.Type == conditionType {
@@ -41,6 +52,7 @@ func (c Conditions) MergeWith(nextConditions ...Condition) Conditions {
 		if index := cp.Index(nextCondition.Type); index >= 0 {
 			currentCondition := c[index]
 			if currentCondition.Status != nextCondition.Status ||
+				currentCondition.Reason != nextCondition.Reason ||
 				currentCondition.Message != nextCondition.Message {
 				// Update condition
 				cp[index] = nextCondition
@@ -68,6 +68,16 @@ func (n *Node) CanContainData() bool {
 		n.HasRole(DataContentRole)
 }
 
+// IsCoordinatingOnly returns true if the node only coordinates requests: it is neither master-eligible, nor holds data,
+// nor runs ingest, machine learning or transform tasks.
+func (n *Node) IsCoordinatingOnly() bool {
+	return !n.HasRole(MasterRole) &&
+		!n.CanContainData() &&
+		!n.HasRole(IngestRole) &&
+		!n.HasRole(MLRole) &&
+		!n.HasRole(TransformRole)
+}
+
 // HasRole returns true if the node runs with the given role.
 func (n *Node) HasRole(role NodeRole) bool {
 	switch role {
@@ -229,6 +229,46 @@ func TestConfig_HasRole(t *testing.T) {
 	}
 }
 
+func TestConfig_IsCoordinatingOnly(t *testing.T) {
+	testCases := []struct {
+		name string
+		node *Node
+		want bool
+	}{
+		{
+			name: "default roles",
+			node: nil,
+			want: false,
+		},
+		{
+			name: "empty node.roles",
+			node: &Node{Roles: []string{}},
+			want: true,
+		},
+		{
+			name: "remote_cluster_client only",
+			node: &Node{Roles: []string{"remote_cluster_client"}},
+			want: true,
+		},
+		{
+			name: "voting-only master",
+			node: &Node{Roles: []string{"master", "voting_only"}},
+			want: false,
+		},
+		{
+			name: "legacy settings with all roles disabled",
+			node: &Node{Master: ptr.To(false), Data: ptr.To(false), Ingest: ptr.To(false), ML: ptr.To(false)},
+			want: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, tc.node.IsCoordinatingOnly())
+		})
+	}
+}
+
 func TestConfig_IsConfiguredWithRole(t *testing.T) {
 	testCases := []struct {
 		name      string
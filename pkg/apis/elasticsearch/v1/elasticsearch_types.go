@@ -5,7 +5,11 @@
 package v1
 
 import (
+	"net/http"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/blang/semver/v4"
 	corev1 "k8s.io/api/core/v1"
@@ -14,6 +18,7 @@ import (
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
 )
 
@@ -36,6 +41,30 @@ const (
 	// SuspendAnnotation allows users to annotate the Elasticsearch resource with the names of Pods they want to suspend
 	// for debugging purposes.
 	SuspendAnnotation = "eck.k8s.elastic.co/suspend"
+	// UnsafeBootstrapAnnotation allows users to annotate the Elasticsearch resource with the name of a surviving
+	// master-eligible Pod to form a new cluster from, when the majority of the master-eligible nodes are permanently lost.
+	// The procedure is only started if UnsafeBootstrapAcknowledgementAnnotation is also set.
+	UnsafeBootstrapAnnotation = "eck.k8s.elastic.co/unsafe-bootstrap"
+	// UnsafeBootstrapAcknowledgementAnnotation must be set to UnsafeBootstrapAcknowledgement to acknowledge that
+	// re-bootstrapping the cluster may result in arbitrary data loss.
+	UnsafeBootstrapAcknowledgementAnnotation = "eck.k8s.elastic.co/unsafe-bootstrap-acknowledgement"
+	// UnsafeBootstrapAcknowledgement is the expected value of UnsafeBootstrapAcknowledgementAnnotation.
+	UnsafeBootstrapAcknowledgement = "i-understand-this-may-lose-data"
+	// PreserveClusterIdentityAnnotation can be set to "true" to persist the identity of the cluster (cluster UUID and
+	// name) in a dedicated Secret which is not owned by the Elasticsearch resource, and survives its deletion.
+	PreserveClusterIdentityAnnotation = "eck.k8s.elastic.co/preserve-cluster-identity"
+	// ClusterIdentityAnnotation allows users to re-link a recreated Elasticsearch resource to the existing data of a
+	// previous cluster, by referencing the name of the Secret holding the identity of that cluster.
+	ClusterIdentityAnnotation = "eck.k8s.elastic.co/cluster-identity"
+	// PublishHTTPCertsAnnotation holds a comma-separated list of namespaces in which the operator maintains a copy of the
+	// public HTTP certificates of the cluster, so that applications running in these namespaces can trust Elasticsearch.
+	PublishHTTPCertsAnnotation = "eck.k8s.elastic.co/publish-http-certs-to"
+	// ChunkedTransportCertificatesAnnotation can be set to "true" to migrate the NodeSets created by earlier versions of
+	// the operator to transport certificates spread across several Secrets. The migration restarts the Pods.
+	ChunkedTransportCertificatesAnnotation = "eck.k8s.elastic.co/chunked-transport-certificates"
+	// VeleroBackupHooksAnnotation can be set to "true" to annotate the Pods with Velero backup hooks, which flush the
+	// indices before Velero takes a snapshot of the volumes of each Pod.
+	VeleroBackupHooksAnnotation = "eck.k8s.elastic.co/velero-backup-hooks"
 	// ElasticsearchAutoscalingSpecAnnotationName is the name of the annotation used to store the autoscaling specification.
 	// Deprecated: the autoscaling annotation has been deprecated in favor of the ElasticsearchAutoscaler custom resource.
 	ElasticsearchAutoscalingSpecAnnotationName = "elasticsearch.alpha.elastic.co/autoscaling-spec"
@@ -103,6 +132,12 @@ type ElasticsearchSpec struct {
 	// +kubebuilder:validation:Optional
 	UpdateStrategy UpdateStrategy `json:"updateStrategy,omitempty"`
 
+	// MaintenanceWindows restrict the restarts of the Elasticsearch nodes, for example for version upgrades or Pod
+	// template changes, to recurring time windows. Changes which do not require restarting nodes, such as configuration
+	// updates applied through the API or scale ups, are applied immediately. Nodes can be restarted at any time if empty.
+	// +kubebuilder:validation:Optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
 	// PodDisruptionBudget provides access to the default Pod disruption budget for the Elasticsearch cluster.
 	// The default budget doesn't allow any Pod to be removed in case the cluster is not green or if there is only one node of type `data` or `master`.
 	// In all other cases the default PodDisruptionBudget sets `minUnavailable` equal to the total number of nodes minus 1.
@@ -110,6 +145,24 @@ type ElasticsearchSpec struct {
 	// +kubebuilder:validation:Optional
 	PodDisruptionBudget *commonv1.PodDisruptionBudgetTemplate `json:"podDisruptionBudget,omitempty"`
 
+	// TopologySpread controls how the Pods of each NodeSet are spread by default across the Kubernetes topology.
+	// When not set, Pods of the cluster preferably avoid being scheduled on the same Kubernetes node.
+	// Affinity and topology spread constraints defined in the Pod template of a NodeSet take precedence.
+	// +kubebuilder:validation:Optional
+	TopologySpread *TopologySpreadPolicy `json:"topologySpread,omitempty"`
+
+	// ZoneAwareness sets the zone of each Elasticsearch node from the topology label of the Kubernetes node its Pod is
+	// scheduled on. The zone is set as a node attribute used for shard allocation awareness, along with the name of the
+	// Kubernetes node. The label is copied to the Pods by the operator before Elasticsearch starts, regardless of the
+	// node labels exposed through the operator configuration.
+	// +kubebuilder:validation:Optional
+	ZoneAwareness *ZoneAwareness `json:"zoneAwareness,omitempty"`
+
+	// NetworkPolicy controls the NetworkPolicy managed by the operator to restrict the network traffic of the
+	// Elasticsearch Pods to the flows required by the cluster.
+	// +kubebuilder:validation:Optional
+	NetworkPolicy *NetworkPolicySpec `json:"networkPolicy,omitempty"`
+
 	// Auth contains user authentication and authorization security settings for Elasticsearch.
 	// +kubebuilder:validation:Optional
 	Auth Auth `json:"auth,omitempty"`
@@ -118,6 +171,17 @@ type ElasticsearchSpec struct {
 	// +kubebuilder:validation:Optional
 	SecureSettings []commonv1.SecretSource `json:"secureSettings,omitempty"`
 
+	// LicenseSecretRef references a Secret, in the namespace of the cluster, holding an Enterprise license applied to
+	// this cluster only, in the same format as the operator license. When set, the operator license is not applied to
+	// the cluster. The cluster reverts to a basic license once the referenced license expires.
+	// +kubebuilder:validation:Optional
+	LicenseSecretRef *commonv1.SecretRef `json:"licenseSecretRef,omitempty"`
+
+	// Plugins lists the Elasticsearch plugins installed by the operator in every Elasticsearch node before it starts.
+	// Changes to the list of plugins trigger a rolling restart of the cluster.
+	// +kubebuilder:validation:Optional
+	Plugins *PluginsSpec `json:"plugins,omitempty"`
+
 	// ServiceAccountName is used to check access from the current resource to a resource (for ex. a remote Elasticsearch cluster) in a different namespace.
 	// Can only be used if ECK is enforcing RBAC on references.
 	// +optional
@@ -127,6 +191,35 @@ type ElasticsearchSpec struct {
 	// +optional
 	RemoteClusters []RemoteCluster `json:"remoteClusters,omitempty"`
 
+	// SnapshotRepositories are the snapshot repositories registered by the operator through the Elasticsearch snapshot API.
+	// Repositories removed from this list are also removed from Elasticsearch, repositories registered out-of-band are left untouched.
+	// +kubebuilder:validation:Optional
+	SnapshotRepositories []SnapshotRepository `json:"snapshotRepositories,omitempty"`
+
+	// SnapshotVerification enables the periodic verification, by the operator, of the latest snapshots taken by the
+	// snapshot lifecycle management (SLM) policies of the cluster. Results are reported in the status.
+	// +kubebuilder:validation:Optional
+	SnapshotVerification *SnapshotVerification `json:"snapshotVerification,omitempty"`
+
+	// VolumeSnapshots enables taking CSI VolumeSnapshots of the data volumes of the cluster before the operator restarts
+	// its nodes to apply a version upgrade or another change of the NodeSets, such as a Pod template change. Nodes are
+	// only restarted once all the VolumeSnapshots are ready to use.
+	// +kubebuilder:validation:Optional
+	VolumeSnapshots *VolumeSnapshotPolicy `json:"volumeSnapshots,omitempty"`
+
+	// HealthProbes are requests sent by the operator to Elasticsearch along with each observation of the cluster
+	// health, for example a search on a canary index. A failing probe marks the cluster as degraded, even if its
+	// health is green. Results are reported in the status.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=10
+	HealthProbes []HealthProbe `json:"healthProbes,omitempty"`
+
+	// ResourceRecommendations enables the computation, by the operator, of the resources recommended for the
+	// Elasticsearch containers of each NodeSet, from the heap, garbage collection, CPU throttling and indexing pressure
+	// statistics of their nodes. Recommendations are reported in the status and never applied automatically.
+	// +kubebuilder:validation:Optional
+	ResourceRecommendations *ResourceRecommendations `json:"resourceRecommendations,omitempty"`
+
 	// VolumeClaimDeletePolicy sets the policy for handling deletion of PersistentVolumeClaims for all NodeSets.
 	// Possible values are DeleteOnScaledownOnly and DeleteOnScaledownAndClusterDeletion. Defaults to DeleteOnScaledownAndClusterDeletion.
 	// +kubebuilder:validation:Optional
@@ -167,6 +260,11 @@ type TransportConfig struct {
 	Service commonv1.ServiceTemplate `json:"service,omitempty"`
 	// TLS defines options for configuring TLS on the transport layer.
 	TLS TransportTLSOptions `json:"tls,omitempty"`
+	// PublishIPFamily is the IP family of the transport address published by the Elasticsearch nodes when Pods have
+	// both an IPv4 and an IPv6 address. Defaults to the family of the primary Pod IP.
+	// +kubebuilder:validation:Enum=IPv4;IPv6
+	// +kubebuilder:validation:Optional
+	PublishIPFamily corev1.IPFamily `json:"publishIPFamily,omitempty"`
 }
 
 type TransportTLSOptions struct {
@@ -184,6 +282,11 @@ type TransportTLSOptions struct {
 	// - `ca.crt`: The CA certificate in PEM format.
 	// - `ca.key`: The private key for the CA certificate in PEM format.
 	Certificate commonv1.SecretRef `json:"certificate,omitempty"`
+	// IssuerRef is a reference to a cert-manager Issuer or ClusterIssuer. When set, the operator creates a cert-manager
+	// Certificate for a CA from which it generates the node transport certificates, instead of a self-signed CA.
+	// Cannot be combined with Certificate.
+	// +kubebuilder:validation:Optional
+	IssuerRef *commonv1.CertManagerIssuerRef `json:"issuerRef,omitempty"`
 	// CertificateAuthorities is a reference to a config map that contains one or more x509 certificates for
 	// trusted authorities in PEM format. The certificates need to be in a file called `ca.crt`.
 	CertificateAuthorities commonv1.ConfigMapRef `json:"certificateAuthorities,omitempty"`
@@ -220,10 +323,344 @@ type RemoteCluster struct {
 	// +kubebuilder:validation:Optional
 	APIKey *RemoteClusterAPIKey `json:"apiKey,omitempty"`
 
+	// Replication declares the indices to replicate from the remote cluster using cross-cluster replication.
+	// Cross-cluster replication requires a platinum or enterprise license on both clusters.
+	// +kubebuilder:validation:Optional
+	Replication *RemoteClusterReplication `json:"replication,omitempty"`
+
 	// TODO: Allow the user to specify some options (transport.compress, transport.ping_schedule)
 
 }
 
+// RemoteClusterReplication declares the cross-cluster replication of indices from a remote cluster.
+type RemoteClusterReplication struct {
+	// AutoFollowPatterns automatically create follower indices for the new indices of the remote cluster matching
+	// a pattern.
+	// +kubebuilder:validation:Optional
+	AutoFollowPatterns []AutoFollowPattern `json:"autoFollowPatterns,omitempty"`
+
+	// FollowerIndices replicate existing indices of the remote cluster.
+	// +kubebuilder:validation:Optional
+	FollowerIndices []FollowerIndex `json:"followerIndices,omitempty"`
+}
+
+// AutoFollowExclusionPatternsMinVersion is the minimum Elasticsearch version supporting leader index exclusion patterns
+// in auto-follow patterns.
+var AutoFollowExclusionPatternsMinVersion = version.MinFor(7, 14, 0)
+
+// AutoFollowPattern declares a cross-cluster replication auto-follow pattern.
+type AutoFollowPattern struct {
+	// Name of the auto-follow pattern.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// LeaderIndexPatterns are the patterns of the names of the remote indices to follow.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	LeaderIndexPatterns []string `json:"leaderIndexPatterns"`
+
+	// LeaderIndexExclusionPatterns are the patterns of the names of the remote indices not to follow, even if they
+	// match LeaderIndexPatterns. Requires Elasticsearch 7.14.0 or later.
+	// +kubebuilder:validation:Optional
+	LeaderIndexExclusionPatterns []string `json:"leaderIndexExclusionPatterns,omitempty"`
+
+	// FollowIndexPattern is the name of the follower indices. The `{{leader_index}}` placeholder refers to the name
+	// of the remote index. Defaults to `{{leader_index}}`.
+	// +kubebuilder:validation:Optional
+	FollowIndexPattern string `json:"followIndexPattern,omitempty"`
+}
+
+// FollowerIndex declares a local index which replicates a remote index.
+type FollowerIndex struct {
+	// Name of the follower index in the local cluster.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// LeaderIndex is the name of the remote index to replicate.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	LeaderIndex string `json:"leaderIndex"`
+}
+
+// SnapshotRepository declares an Elasticsearch snapshot repository.
+type SnapshotRepository struct {
+	// Name of the snapshot repository.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Type of the snapshot repository, for example s3, gcs, azure or fs.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Type string `json:"type"`
+
+	// Settings of the snapshot repository, as documented for the repository type.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Optional
+	Settings *commonv1.Config `json:"settings,omitempty"`
+
+	// SecureSettings is a list of references to Kubernetes secrets containing the credentials of the repository client,
+	// for example `s3.client.default.access_key`. They are added to the Elasticsearch keystore.
+	// +kubebuilder:validation:Optional
+	SecureSettings []commonv1.SecretSource `json:"secureSettings,omitempty"`
+}
+
+// DefaultSnapshotVerificationInterval is the default interval between two snapshot verifications.
+const DefaultSnapshotVerificationInterval = 24 * time.Hour
+
+// SnapshotVerificationMinVersion is the minimum Elasticsearch version supporting snapshot verification, which relies on
+// snapshot lifecycle management.
+var SnapshotVerificationMinVersion = version.MinFor(7, 4, 0)
+
+// SnapshotVerification configures the periodic verification of the snapshots of the cluster.
+// For each SLM policy, the operator checks that the latest snapshot succeeded, that its repository can be accessed by
+// all the nodes, and optionally that an index of the snapshot can be restored.
+type SnapshotVerification struct {
+	// Interval between two verifications. Defaults to 24h.
+	// +kubebuilder:validation:Optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// RestoreSample enables restoring one index of the latest successful snapshot of each policy into a temporary
+	// index, deleted once restored. Restoring consumes cluster resources, and can take time for large indices.
+	// +kubebuilder:validation:Optional
+	RestoreSample bool `json:"restoreSample,omitempty"`
+}
+
+// IntervalOrDefault returns the interval between two verifications.
+func (s SnapshotVerification) IntervalOrDefault() time.Duration {
+	if s.Interval == nil || s.Interval.Duration <= 0 {
+		return DefaultSnapshotVerificationInterval
+	}
+	return s.Interval.Duration
+}
+
+// DefaultVolumeSnapshotRetention is the default number of sets of VolumeSnapshots retained for a cluster.
+const DefaultVolumeSnapshotRetention = 3
+
+// VolumeSnapshotPolicy configures the CSI VolumeSnapshots taken before the nodes of the cluster are restarted.
+// The VolumeSnapshots of a change form a set, holding one VolumeSnapshot per PersistentVolumeClaim of the cluster.
+type VolumeSnapshotPolicy struct {
+	// VolumeSnapshotClassName is the name of the VolumeSnapshotClass used to snapshot the volumes. Defaults to the
+	// default VolumeSnapshotClass of the CSI driver of the volumes.
+	// +kubebuilder:validation:Optional
+	VolumeSnapshotClassName string `json:"volumeSnapshotClassName,omitempty"`
+
+	// Retention is the number of sets of VolumeSnapshots to keep. The oldest sets are deleted once a new set is ready
+	// to use. Defaults to 3.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	Retention *int32 `json:"retention,omitempty"`
+}
+
+// RetentionOrDefault returns the number of sets of VolumeSnapshots to keep.
+func (p VolumeSnapshotPolicy) RetentionOrDefault() int {
+	if p.Retention == nil || *p.Retention < 1 {
+		return DefaultVolumeSnapshotRetention
+	}
+	return int(*p.Retention)
+}
+
+// HealthProbe is a request sent to Elasticsearch to check that data can be read or written.
+type HealthProbe struct {
+	// Name identifies the probe in the status and in the metrics.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// Method is the HTTP method of the request. Defaults to GET.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=GET;HEAD;POST;PUT
+	Method string `json:"method,omitempty"`
+
+	// Path is the path of the request, including the query string, for example /canary/_search?size=1.
+	// +kubebuilder:validation:Pattern=`^/`
+	Path string `json:"path"`
+
+	// Body is the JSON body of the request.
+	// +kubebuilder:validation:Optional
+	Body string `json:"body,omitempty"`
+
+	// MaxLatency is the maximum duration of the request. The probe fails if Elasticsearch takes longer to respond.
+	// +kubebuilder:validation:Optional
+	MaxLatency *metav1.Duration `json:"maxLatency,omitempty"`
+}
+
+// MethodOrDefault returns the HTTP method of the request.
+func (p HealthProbe) MethodOrDefault() string {
+	if p.Method == "" {
+		return http.MethodGet
+	}
+	return p.Method
+}
+
+// DefaultResourceRecommendationsInterval is the default interval between two computations of the resource recommendations.
+const DefaultResourceRecommendationsInterval = 5 * time.Minute
+
+// ResourceRecommendations configures the computation of the resources recommended for the Elasticsearch containers.
+type ResourceRecommendations struct {
+	// Interval between two computations of the recommendations. Defaults to 5m.
+	// +kubebuilder:validation:Optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// IntervalOrDefault returns the interval between two computations of the recommendations.
+func (r ResourceRecommendations) IntervalOrDefault() time.Duration {
+	if r.Interval == nil || r.Interval.Duration <= 0 {
+		return DefaultResourceRecommendationsInterval
+	}
+	return r.Interval.Duration
+}
+
+// TopologySpreadEnforcement is the enforcement level of the topology spread constraints of the Elasticsearch Pods.
+type TopologySpreadEnforcement string
+
+const (
+	// SoftTopologySpread lets the scheduler place Pods in a skewed way if the constraints cannot be satisfied.
+	SoftTopologySpread TopologySpreadEnforcement = "soft"
+	// HardTopologySpread prevents Pods from being scheduled if the constraints cannot be satisfied.
+	HardTopologySpread TopologySpreadEnforcement = "hard"
+
+	// HostTopologyKey is the well-known node label used to spread Pods across Kubernetes nodes.
+	HostTopologyKey = "kubernetes.io/hostname"
+	// ZoneTopologyKey is the well-known node label used to spread Pods across availability zones.
+	ZoneTopologyKey = "topology.kubernetes.io/zone"
+)
+
+// DefaultZoneAttribute is the default name of the node attribute holding the zone of the Elasticsearch nodes.
+const DefaultZoneAttribute = "zone"
+
+// ZoneAwareness configures the node attribute holding the zone of each Elasticsearch node.
+type ZoneAwareness struct {
+	// Enabled sets the zone node attribute and the shard allocation awareness on all the nodes of the cluster.
+	Enabled bool `json:"enabled"`
+
+	// TopologyKey is the Kubernetes node label holding the zone of the Kubernetes nodes.
+	// Defaults to `topology.kubernetes.io/zone`.
+	// +kubebuilder:validation:Optional
+	TopologyKey string `json:"topologyKey,omitempty"`
+
+	// Attribute is the name of the Elasticsearch node attribute holding the zone. Defaults to `zone`.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=[a-zA-Z0-9_-]+
+	Attribute string `json:"attribute,omitempty"`
+}
+
+// IsEnabled returns true if the zone of the nodes is set from the topology label of their Kubernetes node.
+func (z *ZoneAwareness) IsEnabled() bool {
+	return z != nil && z.Enabled
+}
+
+// TopologyKeyOrDefault returns the Kubernetes node label holding the zone of the Kubernetes nodes.
+func (z ZoneAwareness) TopologyKeyOrDefault() string {
+	if z.TopologyKey == "" {
+		return ZoneTopologyKey
+	}
+	return z.TopologyKey
+}
+
+// AttributeOrDefault returns the name of the node attribute holding the zone.
+func (z ZoneAwareness) AttributeOrDefault() string {
+	if z.Attribute == "" {
+		return DefaultZoneAttribute
+	}
+	return z.Attribute
+}
+
+// TopologySpreadPolicy describes how the Pods of each NodeSet are spread across the Kubernetes topology.
+type TopologySpreadPolicy struct {
+	// Enforcement is the enforcement level of the spread constraints: `soft` constraints may be ignored by the scheduler
+	// if they cannot be satisfied, `hard` constraints prevent Pods from being scheduled. Defaults to soft.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=soft;hard
+	Enforcement TopologySpreadEnforcement `json:"enforcement,omitempty"`
+
+	// TopologyKeys are the node labels across the values of which the Pods of each NodeSet are evenly spread, for
+	// example `kubernetes.io/hostname` for hosts and `topology.kubernetes.io/zone` for zones.
+	// Defaults to [`kubernetes.io/hostname`].
+	// +kubebuilder:validation:Optional
+	TopologyKeys []string `json:"topologyKeys,omitempty"`
+
+	// MinDomains is the minimum number of topology domains, for example zones, the Pods of each NodeSet must be spread
+	// across. It can only be set with the hard enforcement, and cannot be greater than the count of a NodeSet.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	MinDomains *int32 `json:"minDomains,omitempty"`
+}
+
+// IsHard returns true if the topology spread constraints prevent Pods from being scheduled when they cannot be satisfied.
+func (p TopologySpreadPolicy) IsHard() bool {
+	return p.Enforcement == HardTopologySpread
+}
+
+// TopologyKeysOrDefault returns the node labels across the values of which Pods are spread.
+func (p TopologySpreadPolicy) TopologyKeysOrDefault() []string {
+	if len(p.TopologyKeys) == 0 {
+		return []string{HostTopologyKey}
+	}
+	return p.TopologyKeys
+}
+
+// NetworkPolicySpec controls the NetworkPolicy managed by the operator for the Elasticsearch Pods.
+type NetworkPolicySpec struct {
+	// Enabled makes the operator manage a NetworkPolicy which only allows the transport traffic between the Pods of the
+	// cluster and with its remote clusters, the HTTP traffic from the operator and from the associated resources, and
+	// DNS lookups. The NetworkPolicy is kept up to date as associations change. Defaults to false.
+	// +kubebuilder:validation:Optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Cilium makes the operator also manage a CiliumNetworkPolicy if the Cilium CRDs are installed, allowing the egress
+	// traffic to the external snapshot repositories, to the external monitoring clusters and to the EgressFQDNs, which
+	// cannot be expressed with a NetworkPolicy. Defaults to false.
+	// +kubebuilder:validation:Optional
+	Cilium bool `json:"cilium,omitempty"`
+
+	// EgressFQDNs are additional domain names, or patterns such as `*.example.com`, the Elasticsearch Pods are allowed to
+	// connect to over HTTPS, for example to download plugins. Only used with Cilium.
+	// +kubebuilder:validation:Optional
+	EgressFQDNs []string `json:"egressFQDNs,omitempty"`
+}
+
+// PluginsSpec holds the Elasticsearch plugins to install and how to download them.
+type PluginsSpec struct {
+	// Install is the list of plugins to install.
+	// +kubebuilder:validation:Optional
+	Install []Plugin `json:"install,omitempty"`
+
+	// Proxy is the URL of the HTTP proxy used to download the plugins, for example `http://proxy.example.com:3128`.
+	// +kubebuilder:validation:Optional
+	Proxy string `json:"proxy,omitempty"`
+}
+
+// Plugin is an Elasticsearch plugin to install.
+type Plugin struct {
+	// Name of the plugin, for example `analysis-icu`.
+	Name string `json:"name"`
+
+	// URL of the plugin bundle to install the plugin from, instead of the Elastic download service. It can be used to
+	// install custom plugins or to install plugins in air-gapped environments, for example
+	// `https://artifacts.example.com/analysis-icu-8.15.0.zip` or `file:///mnt/plugins/analysis-icu-8.15.0.zip`.
+	// +kubebuilder:validation:Optional
+	URL string `json:"url,omitempty"`
+}
+
+// Source returns the location of the plugin to install, as expected by the elasticsearch-plugin tool.
+func (p Plugin) Source() string {
+	if p.URL != "" {
+		return p.URL
+	}
+	return p.Name
+}
+
+// PluginsToInstall returns the list of plugins to install, or nil if there are none.
+func (es ElasticsearchSpec) PluginsToInstall() []Plugin {
+	if es.Plugins == nil {
+		return nil
+	}
+	return es.Plugins.Install
+}
+
 func (r RemoteCluster) ConfigHash() string {
 	return hash.HashObject(r)
 }
@@ -250,6 +687,10 @@ type Auth struct {
 	Roles []RoleSource `json:"roles,omitempty"`
 	// FileRealm to propagate to the Elasticsearch cluster.
 	FileRealm []FileRealmSource `json:"fileRealm,omitempty"`
+	// Realms are the OpenID Connect, SAML or LDAP realms to configure in the Elasticsearch cluster, in addition to the
+	// built-in file and native realms.
+	// +kubebuilder:validation:Optional
+	Realms []Realm `json:"realms,omitempty"`
 	// DisableElasticUser disables the default elastic user that is created by ECK.
 	DisableElasticUser bool `json:"disableElasticUser,omitempty"`
 }
@@ -314,6 +755,161 @@ type FileRealmSource struct {
 	commonv1.SecretRef `json:",inline"`
 }
 
+// Realm is an external realm of the Elasticsearch cluster. Exactly one of OIDC, SAML or LDAP must be set.
+type Realm struct {
+	// Name of the realm, unique among the realms of the cluster.
+	// +kubebuilder:validation:Pattern=^[a-zA-Z0-9_-]+$
+	Name string `json:"name"`
+	// Order of the realm in the realm chain. The built-in file and native realms are always ordered first.
+	Order int32 `json:"order"`
+	// OIDC configures an OpenID Connect realm.
+	// +kubebuilder:validation:Optional
+	OIDC *OIDCRealm `json:"oidc,omitempty"`
+	// SAML configures a SAML realm.
+	// +kubebuilder:validation:Optional
+	SAML *SAMLRealm `json:"saml,omitempty"`
+	// LDAP configures an LDAP realm.
+	// +kubebuilder:validation:Optional
+	LDAP *LDAPRealm `json:"ldap,omitempty"`
+}
+
+// Type returns the Elasticsearch type of the realm, or an empty string if no realm is configured.
+func (r Realm) Type() string {
+	switch {
+	case r.OIDC != nil:
+		return OIDCRealmType
+	case r.SAML != nil:
+		return SAMLRealmType
+	case r.LDAP != nil:
+		return LDAPRealmType
+	}
+	return ""
+}
+
+const (
+	OIDCRealmType = "oidc"
+	SAMLRealmType = "saml"
+	LDAPRealmType = "ldap"
+)
+
+// SecretKeyRef references a key of a Secret in the same namespace as the Elasticsearch resource.
+type SecretKeyRef struct {
+	// SecretName is the name of the secret.
+	SecretName string `json:"secretName"`
+	// Key is the key of the secret holding the value.
+	Key string `json:"key"`
+}
+
+// OIDCRealm is the configuration of an OpenID Connect realm.
+// See https://www.elastic.co/guide/en/elasticsearch/reference/current/oidc-guide.html.
+type OIDCRealm struct {
+	// Issuer is the identifier of the OpenID Connect Provider.
+	Issuer string `json:"issuer"`
+	// AuthorizationEndpoint is the URL of the authorization endpoint of the OpenID Connect Provider.
+	AuthorizationEndpoint string `json:"authorizationEndpoint"`
+	// TokenEndpoint is the URL of the token endpoint of the OpenID Connect Provider.
+	// +kubebuilder:validation:Optional
+	TokenEndpoint string `json:"tokenEndpoint,omitempty"`
+	// UserinfoEndpoint is the URL of the user info endpoint of the OpenID Connect Provider.
+	// +kubebuilder:validation:Optional
+	UserinfoEndpoint string `json:"userinfoEndpoint,omitempty"`
+	// EndSessionEndpoint is the URL of the end session endpoint of the OpenID Connect Provider.
+	// +kubebuilder:validation:Optional
+	EndSessionEndpoint string `json:"endSessionEndpoint,omitempty"`
+	// JWKSetPath is the URL of the JSON Web Key Set of the OpenID Connect Provider.
+	JWKSetPath string `json:"jwkSetPath"`
+	// ClientID is the client identifier of Elasticsearch at the OpenID Connect Provider.
+	ClientID string `json:"clientID"`
+	// ClientSecret references the client secret of Elasticsearch at the OpenID Connect Provider. It is added to the
+	// Elasticsearch keystore.
+	ClientSecret SecretKeyRef `json:"clientSecret"`
+	// RedirectURI is the URL the OpenID Connect Provider redirects the browser to after authentication, usually on Kibana.
+	RedirectURI string `json:"redirectURI"`
+	// PostLogoutRedirectURI is the URL the OpenID Connect Provider redirects the browser to after logout.
+	// +kubebuilder:validation:Optional
+	PostLogoutRedirectURI string `json:"postLogoutRedirectURI,omitempty"`
+	// ResponseType is the OAuth 2.0 response type, code by default.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=code;id_token;"id_token token"
+	ResponseType string `json:"responseType,omitempty"`
+	// RequestedScopes are the scopes requested in addition to openid.
+	// +kubebuilder:validation:Optional
+	RequestedScopes []string `json:"requestedScopes,omitempty"`
+	// Claims maps the claims of the OpenID Connect Provider to the user properties.
+	// +kubebuilder:validation:Optional
+	Claims RealmUserProperties `json:"claims,omitempty"`
+}
+
+// SAMLRealm is the configuration of a SAML realm.
+// See https://www.elastic.co/guide/en/elasticsearch/reference/current/saml-guide-stack.html.
+type SAMLRealm struct {
+	// IdPEntityID is the entity ID of the SAML Identity Provider.
+	IdPEntityID string `json:"idpEntityID"`
+	// IdPMetadataURL is the URL of the metadata of the SAML Identity Provider. Mutually exclusive with IdPMetadata.
+	// +kubebuilder:validation:Optional
+	IdPMetadataURL string `json:"idpMetadataURL,omitempty"`
+	// IdPMetadata references the metadata file of the SAML Identity Provider, which is mounted in the Elasticsearch
+	// configuration directory. Mutually exclusive with IdPMetadataURL.
+	// +kubebuilder:validation:Optional
+	IdPMetadata *SecretKeyRef `json:"idpMetadata,omitempty"`
+	// SPEntityID is the entity ID of Elasticsearch as a SAML Service Provider, usually the URL of Kibana.
+	SPEntityID string `json:"spEntityID"`
+	// SPACS is the Assertion Consumer Service URL, usually the /api/security/saml/callback endpoint of Kibana.
+	SPACS string `json:"spACS"`
+	// SPLogout is the logout URL, usually the /logout endpoint of Kibana.
+	// +kubebuilder:validation:Optional
+	SPLogout string `json:"spLogout,omitempty"`
+	// Attributes maps the SAML attributes to the user properties.
+	// +kubebuilder:validation:Optional
+	Attributes RealmUserProperties `json:"attributes,omitempty"`
+}
+
+// RealmUserProperties are the names of the claims or attributes of an identity provider holding the properties of the users.
+type RealmUserProperties struct {
+	// Principal is the claim or attribute holding the principal of the user.
+	// +kubebuilder:validation:Optional
+	Principal string `json:"principal,omitempty"`
+	// Groups is the claim or attribute holding the groups of the user.
+	// +kubebuilder:validation:Optional
+	Groups string `json:"groups,omitempty"`
+	// Name is the claim or attribute holding the full name of the user.
+	// +kubebuilder:validation:Optional
+	Name string `json:"name,omitempty"`
+	// Mail is the claim or attribute holding the email address of the user.
+	// +kubebuilder:validation:Optional
+	Mail string `json:"mail,omitempty"`
+}
+
+// LDAPRealm is the configuration of an LDAP realm.
+// See https://www.elastic.co/guide/en/elasticsearch/reference/current/ldap-realm.html.
+type LDAPRealm struct {
+	// URLs of the LDAP servers.
+	// +kubebuilder:validation:MinItems=1
+	URLs []string `json:"urls"`
+	// BindDN is the DN of the user used to search users, in user search mode.
+	// +kubebuilder:validation:Optional
+	BindDN string `json:"bindDN,omitempty"`
+	// BindPassword references the password of the bind user. It is added to the Elasticsearch keystore.
+	// +kubebuilder:validation:Optional
+	BindPassword *SecretKeyRef `json:"bindPassword,omitempty"`
+	// UserSearchBaseDN is the container DN to search for users, in user search mode.
+	// +kubebuilder:validation:Optional
+	UserSearchBaseDN string `json:"userSearchBaseDN,omitempty"`
+	// UserSearchFilter is the filter used to search for users, in user search mode.
+	// +kubebuilder:validation:Optional
+	UserSearchFilter string `json:"userSearchFilter,omitempty"`
+	// UserDNTemplates are the DN templates of the users, in user DN templates mode.
+	// +kubebuilder:validation:Optional
+	UserDNTemplates []string `json:"userDNTemplates,omitempty"`
+	// GroupSearchBaseDN is the container DN to search for groups of the users.
+	// +kubebuilder:validation:Optional
+	GroupSearchBaseDN string `json:"groupSearchBaseDN,omitempty"`
+	// CertificateAuthorities references the PEM encoded certificates of the authorities trusted to verify the LDAP
+	// servers, which are mounted in the Elasticsearch configuration directory.
+	// +kubebuilder:validation:Optional
+	CertificateAuthorities *SecretKeyRef `json:"certificateAuthorities,omitempty"`
+}
+
 // NodeSet is the specification for a group of Elasticsearch nodes sharing the same configuration and a Pod template.
 type NodeSet struct {
 	// Name of this set of nodes. Becomes a part of the Elasticsearch node.name setting.
@@ -340,11 +936,272 @@ type NodeSet struct {
 	// Items defined here take precedence over any default claims added by the operator with the same name.
 	// +kubebuilder:validation:Optional
 	VolumeClaimTemplates []corev1.PersistentVolumeClaim `json:"volumeClaimTemplates,omitempty"`
+
+	// Ephemeral indicates that the Elasticsearch data of this NodeSet is not persisted across Pod restarts.
+	// No persistent volume claim is created: the data volume defaults to an emptyDir volume and can be overridden in the
+	// PodTemplate with any other non-persistent volume, for example a local NVMe disk. During rolling upgrades, the data
+	// of each Pod is relocated to the other nodes of the cluster before the Pod is deleted.
+	// Cannot be combined with VolumeClaimTemplates and cannot be changed once the NodeSet has been created.
+	// +kubebuilder:validation:Optional
+	Ephemeral bool `json:"ephemeral,omitempty"`
+
+	// PodDisruptionBudget provides access to the Pod disruption budget of this NodeSet.
+	// Setting it on at least one NodeSet makes the operator create one PodDisruptionBudget per NodeSet instead of the
+	// default PodDisruptionBudget for the whole cluster, which then cannot be specified in `spec.podDisruptionBudget`.
+	// The selector defaults to the Pods of the NodeSet. If neither `maxUnavailable` nor `minAvailable` is specified,
+	// `maxUnavailable` defaults to 1 on a green cluster, unless the NodeSet holds the only master, data or ingest node of the cluster.
+	// To disable the PodDisruptionBudget of a NodeSet, set it to the empty value (`{}` in YAML).
+	// +kubebuilder:validation:Optional
+	PodDisruptionBudget *commonv1.PodDisruptionBudgetTemplate `json:"podDisruptionBudget,omitempty"`
+
+	// IngestAutoscaling lets the operator adjust the number of nodes of an ingest-only NodeSet between a minimum and a
+	// maximum, based on the rejections and the queue of the write thread pool of its nodes. It does not rely on the
+	// Elasticsearch autoscaling API. Count is then only used as the initial number of nodes.
+	// +kubebuilder:validation:Optional
+	IngestAutoscaling *IngestAutoscaling `json:"ingestAutoscaling,omitempty"`
+
+	// Tuning holds commonly tuned Elasticsearch settings of the nodes of this NodeSet: thread pools, circuit breakers
+	// and the maximum number of aggregation buckets. They are validated against the resources of the Elasticsearch
+	// container and cannot be specified in Config as well.
+	// +kubebuilder:validation:Optional
+	Tuning *NodeSetTuning `json:"tuning,omitempty"`
+
+	// ZoneSpread expands this NodeSet into one NodeSet per availability zone, named after the NodeSet and the zone.
+	// The nodes are evenly distributed across the zones, Count being the total number of nodes. The nodes of each zone
+	// are scheduled on the Kubernetes nodes of the zone, spread across hosts, and configured with a node attribute
+	// holding their zone, used for shard allocation awareness. The resulting NodeSets are scaled and upgraded as a unit.
+	// +kubebuilder:validation:Optional
+	ZoneSpread *ZoneSpread `json:"zoneSpread,omitempty"`
+}
+
+// ZoneSpread describes the availability zones across which the nodes of a NodeSet are spread.
+type ZoneSpread struct {
+	// Zones are the availability zones across which the nodes are spread.
+	// +kubebuilder:validation:MinItems=1
+	Zones []NodeSetZone `json:"zones"`
+
+	// TopologyKey is the Kubernetes node label holding the zone of the Kubernetes nodes.
+	// Defaults to `topology.kubernetes.io/zone`.
+	// +kubebuilder:validation:Optional
+	TopologyKey string `json:"topologyKey,omitempty"`
+
+	// AwarenessAttribute is the name of the Elasticsearch node attribute holding the zone of the nodes, set in
+	// `cluster.routing.allocation.awareness.attributes` on all the nodes of the cluster. Defaults to `zone`.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=[a-zA-Z0-9_-]+
+	AwarenessAttribute string `json:"awarenessAttribute,omitempty"`
+}
+
+// NodeSetZone is an availability zone of a NodeSet spread across zones.
+type NodeSetZone struct {
+	// Name of the zone, as set in the topology key label of the Kubernetes nodes.
+	Name string `json:"name"`
+
+	// Suffix is appended to the name of the NodeSet to name the NodeSet of this zone. Defaults to the name of the zone.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=[a-zA-Z0-9-]+
+	Suffix string `json:"suffix,omitempty"`
+
+	// StorageClassName overrides the storage class of the volume claim templates of the NodeSet in this zone,
+	// for storage classes bound to a single zone.
+	// +kubebuilder:validation:Optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+}
+
+// TopologyKeyOrDefault returns the Kubernetes node label holding the zone of the Kubernetes nodes.
+func (z ZoneSpread) TopologyKeyOrDefault() string {
+	if z.TopologyKey == "" {
+		return ZoneTopologyKey
+	}
+	return z.TopologyKey
+}
+
+// AwarenessAttributeOrDefault returns the name of the node attribute holding the zone of the nodes.
+func (z ZoneSpread) AwarenessAttributeOrDefault() string {
+	if z.AwarenessAttribute == "" {
+		return DefaultZoneAttribute
+	}
+	return z.AwarenessAttribute
+}
+
+// SuffixOrDefault returns the suffix appended to the name of the NodeSet to name the NodeSet of this zone.
+func (z NodeSetZone) SuffixOrDefault() string {
+	if z.Suffix == "" {
+		return z.Name
+	}
+	return z.Suffix
+}
+
+const (
+	// DefaultIngestAutoscalingQueueThreshold is the default average number of queued write tasks per node above which
+	// a node is added to an ingest autoscaled NodeSet.
+	DefaultIngestAutoscalingQueueThreshold = 100
+	// DefaultIngestAutoscalingInterval is the default interval between two evaluations of the write thread pool statistics.
+	DefaultIngestAutoscalingInterval = time.Minute
+	// DefaultIngestAutoscalingScaleDownDelay is the default duration without write pressure after which a node is removed.
+	DefaultIngestAutoscalingScaleDownDelay = 10 * time.Minute
+)
+
+// IngestAutoscaling configures the adjustment of the number of nodes of an ingest-only NodeSet. A node is added when
+// write requests are rejected or when the write queues exceed a threshold, and a node is removed when no write
+// pressure has been observed for a while.
+type IngestAutoscaling struct {
+	// MinCount is the minimum number of nodes of the NodeSet.
+	// +kubebuilder:validation:Minimum=1
+	MinCount int32 `json:"minCount"`
+
+	// MaxCount is the maximum number of nodes of the NodeSet.
+	// +kubebuilder:validation:Minimum=1
+	MaxCount int32 `json:"maxCount"`
+
+	// QueueThreshold is the average number of write tasks queued per node above which a node is added. Defaults to 100.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	QueueThreshold *int32 `json:"queueThreshold,omitempty"`
+
+	// Interval between two evaluations of the write thread pool statistics, which is also the minimum delay between
+	// two node additions. Defaults to 1m.
+	// +kubebuilder:validation:Optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// ScaleDownDelay is the duration without any write rejection or queued write task after which a node is removed.
+	// Defaults to 10m.
+	// +kubebuilder:validation:Optional
+	ScaleDownDelay *metav1.Duration `json:"scaleDownDelay,omitempty"`
+}
+
+// QueueThresholdOrDefault returns the average number of queued write tasks per node above which a node is added.
+func (a IngestAutoscaling) QueueThresholdOrDefault() int64 {
+	if a.QueueThreshold == nil || *a.QueueThreshold <= 0 {
+		return DefaultIngestAutoscalingQueueThreshold
+	}
+	return int64(*a.QueueThreshold)
+}
+
+// IntervalOrDefault returns the interval between two evaluations of the write thread pool statistics.
+func (a IngestAutoscaling) IntervalOrDefault() time.Duration {
+	if a.Interval == nil || a.Interval.Duration <= 0 {
+		return DefaultIngestAutoscalingInterval
+	}
+	return a.Interval.Duration
+}
+
+// ScaleDownDelayOrDefault returns the duration without write pressure after which a node is removed.
+func (a IngestAutoscaling) ScaleDownDelayOrDefault() time.Duration {
+	if a.ScaleDownDelay == nil || a.ScaleDownDelay.Duration <= 0 {
+		return DefaultIngestAutoscalingScaleDownDelay
+	}
+	return a.ScaleDownDelay.Duration
+}
+
+// Bound returns the given number of nodes within the bounds of the ingest autoscaling configuration.
+func (a IngestAutoscaling) Bound(count int32) int32 {
+	return max(a.MinCount, min(a.MaxCount, count))
+}
+
+// NodeSetTuning holds commonly tuned Elasticsearch node settings.
+type NodeSetTuning struct {
+	// ThreadPools sets the number of threads and the queue size of the fixed thread pools, by thread pool name.
+	// Supported thread pools are analyze, force_merge, get, search, search_throttled and write.
+	// +kubebuilder:validation:Optional
+	ThreadPools map[string]ThreadPoolSettings `json:"threadPools,omitempty"`
+
+	// CircuitBreakers sets the limits of the circuit breakers.
+	// +kubebuilder:validation:Optional
+	CircuitBreakers *CircuitBreakerSettings `json:"circuitBreakers,omitempty"`
+
+	// SearchMaxBuckets is the maximum number of aggregation buckets allowed in a single response (search.max_buckets).
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	SearchMaxBuckets *int32 `json:"searchMaxBuckets,omitempty"`
+}
+
+// ThreadPoolSettings sets the size of a fixed Elasticsearch thread pool.
+type ThreadPoolSettings struct {
+	// Size is the number of threads of the thread pool.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	Size *int32 `json:"size,omitempty"`
+
+	// QueueSize is the maximum number of pending requests of the thread pool, -1 for an unbounded queue.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=-1
+	QueueSize *int32 `json:"queueSize,omitempty"`
+}
+
+// CircuitBreakerSettings sets the limits of the Elasticsearch circuit breakers, either as a percentage of the JVM heap
+// (for example 70%) or as a byte size (for example 2gb).
+type CircuitBreakerSettings struct {
+	// Total is the limit of the parent circuit breaker (indices.breaker.total.limit).
+	// +kubebuilder:validation:Optional
+	Total string `json:"total,omitempty"`
+
+	// UseRealMemory sets whether the parent circuit breaker accounts for the real memory usage of the JVM
+	// (indices.breaker.total.use_real_memory).
+	// +kubebuilder:validation:Optional
+	UseRealMemory *bool `json:"useRealMemory,omitempty"`
+
+	// Request is the limit of the request circuit breaker (indices.breaker.request.limit).
+	// +kubebuilder:validation:Optional
+	Request string `json:"request,omitempty"`
+
+	// Fielddata is the limit of the field data circuit breaker (indices.breaker.fielddata.limit).
+	// +kubebuilder:validation:Optional
+	Fielddata string `json:"fielddata,omitempty"`
+
+	// InFlightRequests is the limit of the in flight requests circuit breaker (network.breaker.inflight_requests.limit).
+	// +kubebuilder:validation:Optional
+	InFlightRequests string `json:"inFlightRequests,omitempty"`
+}
+
+// Settings returns the Elasticsearch settings corresponding to the tuning fields, by setting name.
+func (t *NodeSetTuning) Settings() map[string]interface{} {
+	if t == nil {
+		return nil
+	}
+	settings := map[string]interface{}{}
+	for name, pool := range t.ThreadPools {
+		if pool.Size != nil {
+			settings[ThreadPoolSetting(name, "size")] = int(*pool.Size)
+		}
+		if pool.QueueSize != nil {
+			settings[ThreadPoolSetting(name, "queue_size")] = int(*pool.QueueSize)
+		}
+	}
+	if breakers := t.CircuitBreakers; breakers != nil {
+		for setting, limit := range map[string]string{
+			IndicesBreakerTotalLimit:            breakers.Total,
+			IndicesBreakerRequestLimit:          breakers.Request,
+			IndicesBreakerFielddataLimit:        breakers.Fielddata,
+			NetworkBreakerInflightRequestsLimit: breakers.InFlightRequests,
+		} {
+			if limit != "" {
+				settings[setting] = limit
+			}
+		}
+		if breakers.UseRealMemory != nil {
+			settings[IndicesBreakerTotalUseRealMemory] = *breakers.UseRealMemory
+		}
+	}
+	if t.SearchMaxBuckets != nil {
+		settings[SearchMaxBuckets] = int(*t.SearchMaxBuckets)
+	}
+	return settings
 }
 
 // +kubebuilder:object:generate=false
 type NodeSetList []NodeSet
 
+// HasPodDisruptionBudget returns true if at least one NodeSet specifies its own PodDisruptionBudget.
+func (nsl NodeSetList) HasPodDisruptionBudget() bool {
+	for _, nodeSet := range nsl {
+		if nodeSet.PodDisruptionBudget != nil {
+			return true
+		}
+	}
+	return false
+}
+
 func (nsl NodeSetList) Names() []string {
 	names := make([]string, len(nsl))
 	for i := range nsl {
@@ -367,6 +1224,29 @@ func (n NodeSet) GetESContainerTemplate() *corev1.Container {
 type UpdateStrategy struct {
 	// ChangeBudget defines the constraints to consider when applying changes to the Elasticsearch cluster.
 	ChangeBudget ChangeBudget `json:"changeBudget,omitempty"`
+
+	// Force allows version upgrades to proceed even if the upgrade pre-flight checks report blocking issues, such as
+	// critical deprecations or system features which must be migrated before upgrading to the next major version.
+	// Defaults to false.
+	Force bool `json:"force,omitempty"`
+}
+
+// MaintenanceWindow is a recurring time window during which the operator can restart the Elasticsearch nodes.
+type MaintenanceWindow struct {
+	// Schedule is a cron expression with five fields (minute, hour, day of month, month and day of week), or a
+	// predefined schedule such as @daily or @weekly, defining when the window opens. For example, "0 2 * * SAT,SUN"
+	// opens the window at 2am every Saturday and Sunday.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// Duration of the window, for example 4h.
+	// +kubebuilder:validation:Required
+	Duration metav1.Duration `json:"duration"`
+
+	// TimeZone is the IANA time zone of the schedule, for example Europe/Paris. Defaults to UTC.
+	// +kubebuilder:validation:Optional
+	TimeZone string `json:"timeZone,omitempty"`
 }
 
 // ChangeBudget defines the constraints to consider when applying changes to the Elasticsearch cluster.
@@ -380,6 +1260,32 @@ type ChangeBudget struct {
 	// the specification. MaxSurge is only taken into consideration when scaling up. Setting a negative value will
 	// disable the restriction. Defaults to unbounded if not specified.
 	MaxSurge *int32 `json:"maxSurge,omitempty"`
+
+	// RollingRestart allows restarting more than one Pod at a time during rolling upgrades, as long as the cluster health
+	// is green.
+	RollingRestart *RollingRestartBudget `json:"rollingRestart,omitempty"`
+}
+
+// RollingRestartBudget defines how many Pods can be restarted at the same time during rolling upgrades.
+type RollingRestartBudget struct {
+	// MaxConcurrentRestarts is the maximum number of Pods that can be restarted at the same time during a rolling upgrade.
+	// It replaces MaxUnavailable as the limit of unavailable Pods during rolling upgrades while the cluster health is
+	// green. MaxUnavailable applies otherwise. Pods holding copies of the same shards are never restarted at the same time.
+	// Defaults to MaxUnavailable if not specified.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	MaxConcurrentRestarts *int32 `json:"maxConcurrentRestarts,omitempty"`
+
+	// MaxConcurrentNodeSets is the maximum number of NodeSets whose Pods can be restarted at the same time, for example
+	// NodeSets deployed in different availability zones. The Pods of these NodeSets are restarted in turn.
+	// Defaults to unbounded if not specified.
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrentNodeSets *int32 `json:"maxConcurrentNodeSets,omitempty"`
+
+	// ZoneAttribute is the name of the Elasticsearch node attribute holding the zone of each node, usually the attribute
+	// used for shard allocation awareness. When set, the Pods of a NodeSet restarted at the same time all belong to the
+	// same zone, and Pods of different zones are only restarted at the same time if the nodes of another zone keep running.
+	ZoneAttribute string `json:"zoneAttribute,omitempty"`
 }
 
 // DefaultChangeBudget is used when no change budget is provided. It might not be the most effective, but should work in
@@ -421,6 +1327,15 @@ func (cb ChangeBudget) GetMaxUnavailableOrDefault() *int32 {
 	return maxUnavailable
 }
 
+// GetMaxConcurrentRestartsOrDefault returns the maximum number of Pods that can be restarted at the same time during
+// a rolling upgrade while the cluster health is green, which defaults to MaxUnavailable.
+func (cb ChangeBudget) GetMaxConcurrentRestartsOrDefault() *int32 {
+	if cb.RollingRestart != nil && cb.RollingRestart.MaxConcurrentRestarts != nil {
+		return cb.RollingRestart.MaxConcurrentRestarts
+	}
+	return cb.GetMaxUnavailableOrDefault()
+}
+
 // +kubebuilder:object:root=true
 
 // Elasticsearch represents an Elasticsearch resource in a Kubernetes cluster.
@@ -428,9 +1343,11 @@ func (cb ChangeBudget) GetMaxUnavailableOrDefault() *int32 {
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="health",type="string",JSONPath=".status.health"
 // +kubebuilder:printcolumn:name="nodes",type="integer",JSONPath=".status.availableNodes",description="Available nodes"
+// +kubebuilder:printcolumn:name="expected",type="integer",JSONPath=".status.expectedNodes",description="Expected nodes"
 // +kubebuilder:printcolumn:name="version",type="string",JSONPath=".status.version",description="Elasticsearch version"
 // +kubebuilder:printcolumn:name="phase",type="string",JSONPath=".status.phase"
 // +kubebuilder:printcolumn:name="age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="reconciled",type="date",JSONPath=".status.conditions[?(@.type=='Reconciling')].lastTransitionTime",description="Time of the last transition of the Reconciling condition",priority=1
 // +kubebuilder:storageversion
 type Elasticsearch struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -441,14 +1358,21 @@ type Elasticsearch struct {
 	AssocConfs map[commonv1.ObjectSelector]commonv1.AssociationConf `json:"-"`
 }
 
-// DownwardNodeLabels returns the set of expected node labels to be copied as annotations on the Elasticsearch Pods.
+// DownwardNodeLabels returns the set of expected node labels to be copied as annotations on the Elasticsearch Pods,
+// including the topology label holding the zone of the nodes if zone awareness is enabled.
 func (es Elasticsearch) DownwardNodeLabels() []string {
+	var nodeLabels []string
 	expectedAnnotations, exist := es.Annotations[DownwardNodeLabelsAnnotation]
 	expectedAnnotations = strings.TrimSpace(expectedAnnotations)
-	if !exist || expectedAnnotations == "" {
-		return nil
+	if exist && expectedAnnotations != "" {
+		nodeLabels = strings.Split(expectedAnnotations, ",")
+	}
+	if es.Spec.ZoneAwareness.IsEnabled() {
+		if topologyKey := es.Spec.ZoneAwareness.TopologyKeyOrDefault(); !slices.Contains(nodeLabels, topologyKey) {
+			nodeLabels = append(nodeLabels, topologyKey)
+		}
 	}
-	return strings.Split(expectedAnnotations, ",")
+	return nodeLabels
 }
 
 // HasDownwardNodeLabels returns true if some node labels are expected on the Elasticsearch Pods.
@@ -456,6 +1380,37 @@ func (es Elasticsearch) HasDownwardNodeLabels() bool {
 	return len(es.DownwardNodeLabels()) > 0
 }
 
+// PublishHTTPCertsNamespaces returns the sorted namespaces, other than its own, to which the public HTTP certificates
+// of the cluster are published.
+func (es Elasticsearch) PublishHTTPCertsNamespaces() []string {
+	var namespaces []string
+	for _, namespace := range strings.Split(es.Annotations[PublishHTTPCertsAnnotation], ",") {
+		namespace = strings.TrimSpace(namespace)
+		if namespace == "" || namespace == es.Namespace || slices.Contains(namespaces, namespace) {
+			continue
+		}
+		namespaces = append(namespaces, namespace)
+	}
+	slices.Sort(namespaces)
+	return namespaces
+}
+
+// HasChunkedTransportCertificatesMigration returns true if the NodeSets of the cluster must be migrated to transport
+// certificates spread across several Secrets.
+func (es Elasticsearch) HasChunkedTransportCertificatesMigration() bool {
+	return es.Annotations[ChunkedTransportCertificatesAnnotation] == "true"
+}
+
+// NetworkPolicyEnabled returns true if the operator manages a NetworkPolicy for the Elasticsearch Pods.
+func (es Elasticsearch) NetworkPolicyEnabled() bool {
+	return es.Spec.NetworkPolicy != nil && es.Spec.NetworkPolicy.Enabled
+}
+
+// CiliumNetworkPolicyEnabled returns true if the operator manages a CiliumNetworkPolicy for the Elasticsearch Pods.
+func (es Elasticsearch) CiliumNetworkPolicyEnabled() bool {
+	return es.NetworkPolicyEnabled() && es.Spec.NetworkPolicy.Cilium
+}
+
 // IsMarkedForDeletion returns true if the Elasticsearch is going to be deleted
 func (es Elasticsearch) IsMarkedForDeletion() bool {
 	return !es.DeletionTimestamp.IsZero()
@@ -481,14 +1436,80 @@ func (es Elasticsearch) IsAutoscalingAnnotationSet() bool {
 	return ok
 }
 
+// SecureSettings returns the secure settings of the cluster, including the ones of the snapshot repositories.
 func (es Elasticsearch) SecureSettings() []commonv1.SecretSource {
-	return es.Spec.SecureSettings
+	secureSettings := [][]commonv1.SecretSource{es.Spec.SecureSettings}
+	for _, repository := range es.Spec.SnapshotRepositories {
+		secureSettings = append(secureSettings, repository.SecureSettings)
+	}
+	return slices.Concat(secureSettings...)
+}
+
+// GetRestartStatus returns the status of the restart of the Pods in progress, nil if there is none.
+func (es *Elasticsearch) GetRestartStatus() *commonv1.RestartStatus {
+	return es.Status.Restart
+}
+
+// SetRestartStatus sets the status of the restart of the Pods in progress.
+func (es *Elasticsearch) SetRestartStatus(status *commonv1.RestartStatus) {
+	es.Status.Restart = status
+}
+
+// GetUpgradeGateStatus returns the status of the step of the version upgrade waiting for approval, nil if there is none.
+func (es *Elasticsearch) GetUpgradeGateStatus() *commonv1.UpgradeGateStatus {
+	return es.Status.UpgradeGate
+}
+
+// SetUpgradeGateStatus sets the status of the step of the version upgrade waiting for approval.
+func (es *Elasticsearch) SetUpgradeGateStatus(status *commonv1.UpgradeGateStatus) {
+	es.Status.UpgradeGate = status
 }
 
 func (es Elasticsearch) SuspendedPodNames() set.StringSet {
 	return setFromAnnotations(SuspendAnnotation, es.Annotations)
 }
 
+// UnsafeBootstrapPodName returns the name of the Pod the cluster must be re-bootstrapped from, and false if the
+// unsafe bootstrap procedure has not been requested and acknowledged.
+func (es Elasticsearch) UnsafeBootstrapPodName() (string, bool) {
+	podName := strings.TrimSpace(es.Annotations[UnsafeBootstrapAnnotation])
+	if podName == "" || es.Annotations[UnsafeBootstrapAcknowledgementAnnotation] != UnsafeBootstrapAcknowledgement {
+		return "", false
+	}
+	return podName, true
+}
+
+// PreservesClusterIdentity returns true if the identity of the cluster must be persisted in a Secret that survives
+// the deletion of the Elasticsearch resource.
+func (es Elasticsearch) PreservesClusterIdentity() bool {
+	preserve, err := strconv.ParseBool(es.Annotations[PreserveClusterIdentityAnnotation])
+	return err == nil && preserve
+}
+
+// ClusterIdentitySecretName returns the name of the Secret holding the identity of the cluster the Elasticsearch
+// resource must be re-linked to, and false if no re-linking has been requested.
+func (es Elasticsearch) ClusterIdentitySecretName() (string, bool) {
+	name := strings.TrimSpace(es.Annotations[ClusterIdentityAnnotation])
+	return name, name != ""
+}
+
+// HasVeleroBackupHooks returns true if the Pods must be annotated with the Velero backup hooks.
+func (es Elasticsearch) HasVeleroBackupHooks() bool {
+	enabled, err := strconv.ParseBool(es.Annotations[VeleroBackupHooksAnnotation])
+	return err == nil && enabled
+}
+
+// EphemeralStatefulSets returns the names of the StatefulSets of the NodeSets whose data is not persisted across Pod restarts.
+func (es Elasticsearch) EphemeralStatefulSets() set.StringSet {
+	names := set.Make()
+	for _, nodeSet := range es.Spec.NodeSets {
+		if nodeSet.Ephemeral {
+			names.Add(StatefulSet(es.Name, nodeSet.Name))
+		}
+	}
+	return names
+}
+
 // GetObservedGeneration will return the observed generation from the Elasticsearch status.
 func (es Elasticsearch) GetObservedGeneration() int64 {
 	return es.Status.ObservedGeneration
@@ -594,6 +1615,10 @@ func (es *Elasticsearch) GetMonitoringMetricsRefs() []commonv1.ObjectSelector {
 	return es.Spec.Monitoring.Metrics.ElasticsearchRefs
 }
 
+func (es *Elasticsearch) GetMonitoringMetricsDestinations() []commonv1.MetricsDestination {
+	return es.Spec.Monitoring.Metrics.Destinations
+}
+
 func (es *Elasticsearch) GetMonitoringLogsRefs() []commonv1.ObjectSelector {
 	return es.Spec.Monitoring.Logs.ElasticsearchRefs
 }
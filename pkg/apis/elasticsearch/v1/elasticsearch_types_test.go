@@ -259,6 +259,44 @@ func TestElasticsearch_SuspendedPodNames(t *testing.T) {
 	}
 }
 
+func TestElasticsearch_PublishHTTPCertsNamespaces(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        []string
+	}{
+		{
+			name:        "no annotation",
+			annotations: map[string]string{},
+			want:        nil,
+		},
+		{
+			name: "multi value with whitespace",
+			annotations: map[string]string{
+				PublishHTTPCertsAnnotation: " b , a,",
+			},
+			want: []string{"a", "b"},
+		},
+		{
+			name: "own namespace and duplicates are ignored",
+			annotations: map[string]string{
+				PublishHTTPCertsAnnotation: "a,ns,a",
+			},
+			want: []string{"a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Annotations: tt.annotations},
+			}
+			if got := es.PublishHTTPCertsNamespaces(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PublishHTTPCertsNamespaces() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestElasticsearch_DisabledPredicates(t *testing.T) {
 	tests := []struct {
 		name string
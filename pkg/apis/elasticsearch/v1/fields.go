@@ -35,6 +35,14 @@ const (
 	PathData = "path.data"
 	PathLogs = "path.logs"
 
+	IndicesBreakerTotalLimit            = "indices.breaker.total.limit"
+	IndicesBreakerTotalUseRealMemory    = "indices.breaker.total.use_real_memory"
+	IndicesBreakerRequestLimit          = "indices.breaker.request.limit"
+	IndicesBreakerFielddataLimit        = "indices.breaker.fielddata.limit"
+	NetworkBreakerInflightRequestsLimit = "network.breaker.inflight_requests.limit"
+
+	SearchMaxBuckets = "search.max_buckets"
+
 	ShardAwarenessAttributes = "cluster.routing.allocation.awareness.attributes"
 	NodeAttr                 = "node.attr"
 
@@ -87,3 +95,11 @@ var UnsupportedSettings = []string{
 	XPackSecurityTransportSslEnabled,
 	XPackSecurityTransportSslVerificationMode,
 }
+
+// FixedThreadPools are the names of the fixed thread pools whose size can be tuned.
+var FixedThreadPools = []string{"analyze", "force_merge", "get", "search", "search_throttled", "write"}
+
+// ThreadPoolSetting returns the name of a setting of the given thread pool.
+func ThreadPoolSetting(threadPool, setting string) string {
+	return "thread_pool." + threadPool + "." + setting
+}
@@ -30,9 +30,11 @@ const (
 	unicastHostsConfigMapSuffix                  = "unicast-hosts"
 	licenseSecretSuffix                          = "license"
 	defaultPodDisruptionBudget                   = "default"
+	networkPolicySuffix                          = "network-policy"
 	scriptsConfigMapSuffix                       = "scripts"
 	legacyTransportCertsSecretSuffix             = "transport-certificates"
 	statefulSetTransportCertificatesSecretSuffix = "transport-certs"
+	clusterIdentitySecretSuffix                  = "cluster-identity"
 
 	// calling this secret "xpack-file-realm" is conceptually wrong since it also holds the file-based roles which
 	// are not part of the file realm - let's still keep this legacy name for convenience
@@ -61,10 +63,12 @@ var (
 		unicastHostsConfigMapSuffix,
 		licenseSecretSuffix,
 		defaultPodDisruptionBudget,
+		networkPolicySuffix,
 		scriptsConfigMapSuffix,
 		statefulSetTransportCertificatesSecretSuffix,
 		remoteCaNameSuffix,
 		remoteAPIKeysNameSuffix,
+		clusterIdentitySecretSuffix,
 	}
 )
 
@@ -91,7 +95,11 @@ func ValidateNames(es Elasticsearch) error {
 		}
 
 		// length of the ordinal suffix that will be added to the pods of this sset (dash + ordinal)
-		podOrdinalSuffixLen := len(strconv.FormatInt(int64(nodeSet.Count), 10)) + 1
+		count := nodeSet.Count
+		if nodeSet.IngestAutoscaling != nil {
+			count = max(count, nodeSet.IngestAutoscaling.MaxCount)
+		}
+		podOrdinalSuffixLen := len(strconv.FormatInt(int64(count), 10)) + 1
 		// there should be enough space for the ordinal suffix and the controller revision hash
 		if utilvalidation.LabelValueMaxLength-len(ssetName) < podOrdinalSuffixLen+controllerRevisionHashLen {
 			return errors.Errorf("generated StatefulSet name '%s' exceeds allowed length of %d",
@@ -127,6 +135,30 @@ func StatefulSetTransportCertificatesSecret(ssetName string) string {
 	return ESNamer.Suffix(ssetName, statefulSetTransportCertificatesSecretSuffix)
 }
 
+// TransportCertificatesSecretChunks is the number of Secrets the transport certificates of the Pods of a StatefulSet
+// are spread across. It keeps each Secret well below the maximum size of a Kubernetes object, even for StatefulSets
+// with many replicas.
+const TransportCertificatesSecretChunks = 16
+
+// StatefulSetTransportCertificatesSecretChunks returns the names of all the Secrets holding the transport certificates
+// of a StatefulSet.
+func StatefulSetTransportCertificatesSecretChunks(ssetName string) []string {
+	names := make([]string, 0, TransportCertificatesSecretChunks)
+	for chunk := 0; chunk < TransportCertificatesSecretChunks; chunk++ {
+		names = append(names, StatefulSetTransportCertificatesSecretChunk(ssetName, chunk))
+	}
+	return names
+}
+
+// StatefulSetTransportCertificatesSecretChunk returns the name of the Secret holding the given chunk of the transport
+// certificates of a StatefulSet. The first chunk is the Secret which used to hold all of them.
+func StatefulSetTransportCertificatesSecretChunk(ssetName string, chunk int) string {
+	if chunk == 0 {
+		return StatefulSetTransportCertificatesSecret(ssetName)
+	}
+	return ESNamer.Suffix(ssetName, statefulSetTransportCertificatesSecretSuffix, strconv.Itoa(chunk))
+}
+
 // LegacyTransportCertsSecretSuffix returns the former name of the Secret which used to contain the transport certificates.
 // This function only exists to let the controller delete that Secret.
 func LegacyTransportCertsSecretSuffix(esName string) string {
@@ -161,6 +193,11 @@ func InternalUsersSecret(esName string) string {
 	return ESNamer.Suffix(esName, internalUsersSecretSuffix)
 }
 
+// ClusterIdentitySecret returns the name of the Secret that holds the identity of a given cluster.
+func ClusterIdentitySecret(esName string) string {
+	return ESNamer.Suffix(esName, clusterIdentitySecretSuffix)
+}
+
 // UnicastHostsConfigMap returns the name of the ConfigMap that holds the list of seed nodes for a given cluster.
 func UnicastHostsConfigMap(esName string) string {
 	return ESNamer.Suffix(esName, unicastHostsConfigMapSuffix)
@@ -178,6 +215,16 @@ func DefaultPodDisruptionBudget(esName string) string {
 	return ESNamer.Suffix(esName, defaultPodDisruptionBudget)
 }
 
+// NodeSetPodDisruptionBudget returns the name of the PodDisruptionBudget of a NodeSet.
+func NodeSetPodDisruptionBudget(esName string, nodeSetName string) string {
+	return ESNamer.Suffix(esName, nodeSetName, defaultPodDisruptionBudget)
+}
+
+// NetworkPolicy returns the name of the NetworkPolicy managed by the operator for the Pods of the cluster.
+func NetworkPolicy(esName string) string {
+	return ESNamer.Suffix(esName, networkPolicySuffix)
+}
+
 func RemoteCaSecretName(esName string) string {
 	return ESNamer.Suffix(esName, remoteCaNameSuffix)
 }
@@ -7,6 +7,7 @@ package v1
 import (
 	"fmt"
 
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
@@ -58,6 +59,9 @@ const (
 type ElasticsearchStatus struct {
 	// AvailableNodes is the number of available instances.
 	AvailableNodes int32 `json:"availableNodes,omitempty"`
+	// ExpectedNodes is the number of instances expected by the specification, including the instances added by the
+	// autoscaling of the ingest nodes.
+	ExpectedNodes int32 `json:"expectedNodes,omitempty"`
 	// Version of the stack resource currently running. During version upgrades, multiple versions may run
 	// in parallel: this value specifies the lowest version currently running.
 	Version string                          `json:"version,omitempty"`
@@ -76,6 +80,32 @@ type ElasticsearchStatus struct {
 	// **This API is in technical preview and may be changed or removed in a future release.**
 	InProgressOperations `json:"inProgressOperations"`
 
+	// SnapshotVerification holds the result of the last verification of the snapshots of the cluster.
+	// +optional
+	SnapshotVerification *SnapshotVerificationStatus `json:"snapshotVerification,omitempty"`
+
+	// HealthProbes holds the result of the last run of each health probe of the cluster.
+	// +optional
+	HealthProbes []HealthProbeStatus `json:"healthProbes,omitempty"`
+
+	// IngestAutoscaling holds the state of the ingest autoscaling of the NodeSets configured with it.
+	// +optional
+	IngestAutoscaling []IngestAutoscalingStatus `json:"ingestAutoscaling,omitempty"`
+
+	// ResourceRecommendations holds the resources recommended for the Elasticsearch containers of each NodeSet.
+	// +optional
+	ResourceRecommendations []ResourceRecommendation `json:"resourceRecommendations,omitempty"`
+
+	// Restart is the status of the restart of the Pods requested with the eck.k8s.elastic.co/restart annotation, if
+	// in progress.
+	// +optional
+	Restart *commonv1.RestartStatus `json:"restart,omitempty"`
+
+	// UpgradeGate is the status of the step of the version upgrade waiting for the approval of an external analysis tool,
+	// if the eck.k8s.elastic.co/upgrade-gates annotation is set.
+	// +optional
+	UpgradeGate *commonv1.UpgradeGateStatus `json:"upgradeGate,omitempty"`
+
 	// ObservedGeneration is the most recent generation observed for this Elasticsearch cluster.
 	// It corresponds to the metadata generation, which is updated on mutation by the API Server.
 	// If the generation observed in status diverges from the generation in metadata, the Elasticsearch
@@ -83,6 +113,94 @@ type ElasticsearchStatus struct {
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
+// SnapshotVerificationStatus is the result of a verification of the snapshots of the cluster.
+type SnapshotVerificationStatus struct {
+	// LastVerificationTime is the time of the last verification.
+	LastVerificationTime metav1.Time `json:"lastVerificationTime"`
+
+	// Policies holds the verification result of the latest snapshot of each snapshot lifecycle management policy.
+	// +optional
+	Policies []SnapshotPolicyVerification `json:"policies,omitempty"`
+}
+
+// SnapshotPolicyVerification is the verification result of the latest snapshot of a snapshot lifecycle management policy.
+type SnapshotPolicyVerification struct {
+	// Policy is the name of the snapshot lifecycle management policy.
+	Policy string `json:"policy"`
+	// Repository is the repository in which the policy stores snapshots.
+	Repository string `json:"repository,omitempty"`
+	// Snapshot is the name of the latest successful snapshot of the policy.
+	Snapshot string `json:"snapshot,omitempty"`
+	// Verified is true if the latest snapshot and its repository were verified successfully.
+	Verified bool `json:"verified"`
+	// Message explains why the verification failed.
+	Message string `json:"message,omitempty"`
+}
+
+// HealthProbeStatus is the result of the last run of a health probe.
+type HealthProbeStatus struct {
+	// Name is the name of the health probe.
+	Name string `json:"name"`
+	// Succeeded is true if Elasticsearch responded successfully to the request, within the maximum latency.
+	Succeeded bool `json:"succeeded"`
+	// Message explains why the probe failed.
+	Message string `json:"message,omitempty"`
+}
+
+// IngestAutoscalingStatus is the state of the ingest autoscaling of a NodeSet.
+type IngestAutoscalingStatus struct {
+	// NodeSet is the name of the NodeSet.
+	NodeSet string `json:"nodeSet"`
+	// Count is the number of nodes of the NodeSet decided by the operator.
+	Count int32 `json:"count"`
+	// WriteRejections is the total number of write requests rejected by the nodes of the NodeSet at the last evaluation.
+	WriteRejections int64 `json:"writeRejections,omitempty"`
+	// LastScaleTime is the time at which the number of nodes was last changed.
+	// +optional
+	LastScaleTime *metav1.Time `json:"lastScaleTime,omitempty"`
+	// LastPressureTime is the time at which write rejections or queued write tasks were last observed.
+	// +optional
+	LastPressureTime *metav1.Time `json:"lastPressureTime,omitempty"`
+}
+
+// IngestAutoscalingStatusFor returns the ingest autoscaling status of the given NodeSet, if any.
+func (es ElasticsearchStatus) IngestAutoscalingStatusFor(nodeSet string) *IngestAutoscalingStatus {
+	for i := range es.IngestAutoscaling {
+		if es.IngestAutoscaling[i].NodeSet == nodeSet {
+			return &es.IngestAutoscaling[i]
+		}
+	}
+	return nil
+}
+
+// ResourceRecommendation holds the resources recommended for the Elasticsearch containers of a NodeSet.
+type ResourceRecommendation struct {
+	// NodeSet is the name of the NodeSet.
+	NodeSet string `json:"nodeSet"`
+	// Memory is the recommended memory request and limit of the Elasticsearch containers.
+	// +optional
+	Memory *resource.Quantity `json:"memory,omitempty"`
+	// CPU is the recommended CPU request of the Elasticsearch containers.
+	// +optional
+	CPU *resource.Quantity `json:"cpu,omitempty"`
+	// Reasons explains why the recommended resources differ from the current ones.
+	// +optional
+	Reasons []string `json:"reasons,omitempty"`
+	// IndexingPressureRejections is the total number of indexing requests rejected by the nodes of the NodeSet because
+	// of indexing pressure at the last computation.
+	IndexingPressureRejections int64 `json:"indexingPressureRejections,omitempty"`
+}
+
+// ResourceRecommendationFor returns the resource recommendation of the given NodeSet, if any.
+func (es ElasticsearchStatus) ResourceRecommendationFor(nodeSet string) *ResourceRecommendation {
+	for i := range es.ResourceRecommendations {
+		if es.ResourceRecommendations[i].NodeSet == nodeSet {
+			return &es.ResourceRecommendations[i]
+		}
+	}
+	return nil
+}
+
 // IsDegraded returns true if the current status is worse than the previous.
 func (es ElasticsearchStatus) IsDegraded(prev ElasticsearchStatus) bool {
 	return es.Health.Less(prev.Health)
@@ -107,9 +225,16 @@ func (es *Elasticsearch) SetAssociationStatusMap(typ commonv1.AssociationType, s
 
 const (
 	ElasticsearchIsReachable v1alpha1.ConditionType = "ElasticsearchIsReachable"
-	ReconciliationComplete   v1alpha1.ConditionType = "ReconciliationComplete"
+	// LicenseActive reports whether the license of the cluster is active, and when it expires.
+	LicenseActive            v1alpha1.ConditionType = "LicenseActive"
+	ReconciliationComplete   v1alpha1.ConditionType = v1alpha1.ReconciliationCompleteCondition
 	ResourcesAwareManagement v1alpha1.ConditionType = "ResourcesAwareManagement"
 	RunningDesiredVersion    v1alpha1.ConditionType = "RunningDesiredVersion"
+	SnapshotsVerified        v1alpha1.ConditionType = "SnapshotsVerified"
+	// UpgradePreflightChecksPassed reports whether the cluster can be upgraded to the next major version, according
+	// to the deprecation info and system features migration APIs of Elasticsearch.
+	UpgradePreflightChecksPassed v1alpha1.ConditionType = "UpgradePreflightChecksPassed"
+	WithinNamespaceQuota         v1alpha1.ConditionType = "WithinNamespaceQuota"
 )
 
 // NewNodeStatus provides details about the status of nodes which are expected to be created and added to the Elasticsearch cluster.
@@ -12,6 +12,7 @@ import (
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -28,6 +29,13 @@ func (in *Auth) DeepCopyInto(out *Auth) {
 		*out = make([]FileRealmSource, len(*in))
 		copy(*out, *in)
 	}
+	if in.Realms != nil {
+		in, out := &in.Realms, &out.Realms
+		*out = make([]Realm, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Auth.
@@ -72,6 +80,31 @@ func (in AutoscaledNodeSets) DeepCopy() AutoscaledNodeSets {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoFollowPattern) DeepCopyInto(out *AutoFollowPattern) {
+	*out = *in
+	if in.LeaderIndexPatterns != nil {
+		in, out := &in.LeaderIndexPatterns, &out.LeaderIndexPatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LeaderIndexExclusionPatterns != nil {
+		in, out := &in.LeaderIndexExclusionPatterns, &out.LeaderIndexExclusionPatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoFollowPattern.
+func (in *AutoFollowPattern) DeepCopy() *AutoFollowPattern {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoFollowPattern)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChangeBudget) DeepCopyInto(out *ChangeBudget) {
 	*out = *in
@@ -85,6 +118,11 @@ func (in *ChangeBudget) DeepCopyInto(out *ChangeBudget) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.RollingRestart != nil {
+		in, out := &in.RollingRestart, &out.RollingRestart
+		*out = new(RollingRestartBudget)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChangeBudget.
@@ -97,6 +135,26 @@ func (in *ChangeBudget) DeepCopy() *ChangeBudget {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CircuitBreakerSettings) DeepCopyInto(out *CircuitBreakerSettings) {
+	*out = *in
+	if in.UseRealMemory != nil {
+		in, out := &in.UseRealMemory, &out.UseRealMemory
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CircuitBreakerSettings.
+func (in *CircuitBreakerSettings) DeepCopy() *CircuitBreakerSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(CircuitBreakerSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterSettings) DeepCopyInto(out *ClusterSettings) {
 	*out = *in
@@ -266,11 +324,31 @@ func (in *ElasticsearchSpec) DeepCopyInto(out *ElasticsearchSpec) {
 		}
 	}
 	in.UpdateStrategy.DeepCopyInto(&out.UpdateStrategy)
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
 	if in.PodDisruptionBudget != nil {
 		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
 		*out = new(commonv1.PodDisruptionBudgetTemplate)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TopologySpread != nil {
+		in, out := &in.TopologySpread, &out.TopologySpread
+		*out = new(TopologySpreadPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ZoneAwareness != nil {
+		in, out := &in.ZoneAwareness, &out.ZoneAwareness
+		*out = new(ZoneAwareness)
+		**out = **in
+	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(NetworkPolicySpec)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Auth.DeepCopyInto(&out.Auth)
 	if in.SecureSettings != nil {
 		in, out := &in.SecureSettings, &out.SecureSettings
@@ -279,6 +357,16 @@ func (in *ElasticsearchSpec) DeepCopyInto(out *ElasticsearchSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LicenseSecretRef != nil {
+		in, out := &in.LicenseSecretRef, &out.LicenseSecretRef
+		*out = new(commonv1.SecretRef)
+		**out = **in
+	}
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = new(PluginsSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.RemoteClusters != nil {
 		in, out := &in.RemoteClusters, &out.RemoteClusters
 		*out = make([]RemoteCluster, len(*in))
@@ -286,6 +374,35 @@ func (in *ElasticsearchSpec) DeepCopyInto(out *ElasticsearchSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.SnapshotRepositories != nil {
+		in, out := &in.SnapshotRepositories, &out.SnapshotRepositories
+		*out = make([]SnapshotRepository, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SnapshotVerification != nil {
+		in, out := &in.SnapshotVerification, &out.SnapshotVerification
+		*out = new(SnapshotVerification)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VolumeSnapshots != nil {
+		in, out := &in.VolumeSnapshots, &out.VolumeSnapshots
+		*out = new(VolumeSnapshotPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthProbes != nil {
+		in, out := &in.HealthProbes, &out.HealthProbes
+		*out = make([]HealthProbe, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ResourceRecommendations != nil {
+		in, out := &in.ResourceRecommendations, &out.ResourceRecommendations
+		*out = new(ResourceRecommendations)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Monitoring.DeepCopyInto(&out.Monitoring)
 	if in.RevisionHistoryLimit != nil {
 		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
@@ -322,6 +439,40 @@ func (in *ElasticsearchStatus) DeepCopyInto(out *ElasticsearchStatus) {
 		}
 	}
 	in.InProgressOperations.DeepCopyInto(&out.InProgressOperations)
+	if in.SnapshotVerification != nil {
+		in, out := &in.SnapshotVerification, &out.SnapshotVerification
+		*out = new(SnapshotVerificationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthProbes != nil {
+		in, out := &in.HealthProbes, &out.HealthProbes
+		*out = make([]HealthProbeStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.IngestAutoscaling != nil {
+		in, out := &in.IngestAutoscaling, &out.IngestAutoscaling
+		*out = make([]IngestAutoscalingStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ResourceRecommendations != nil {
+		in, out := &in.ResourceRecommendations, &out.ResourceRecommendations
+		*out = make([]ResourceRecommendation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Restart != nil {
+		in, out := &in.Restart, &out.Restart
+		*out = new(commonv1.RestartStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpgradeGate != nil {
+		in, out := &in.UpgradeGate, &out.UpgradeGate
+		*out = new(commonv1.UpgradeGateStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchStatus.
@@ -396,6 +547,56 @@ func (in *FileRealmSource) DeepCopy() *FileRealmSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FollowerIndex) DeepCopyInto(out *FollowerIndex) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FollowerIndex.
+func (in *FollowerIndex) DeepCopy() *FollowerIndex {
+	if in == nil {
+		return nil
+	}
+	out := new(FollowerIndex)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthProbe) DeepCopyInto(out *HealthProbe) {
+	*out = *in
+	if in.MaxLatency != nil {
+		in, out := &in.MaxLatency, &out.MaxLatency
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthProbe.
+func (in *HealthProbe) DeepCopy() *HealthProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthProbeStatus) DeepCopyInto(out *HealthProbeStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthProbeStatus.
+func (in *HealthProbeStatus) DeepCopy() *HealthProbeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthProbeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InProgressOperations) DeepCopyInto(out *InProgressOperations) {
 	*out = *in
@@ -414,6 +615,130 @@ func (in *InProgressOperations) DeepCopy() *InProgressOperations {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngestAutoscaling) DeepCopyInto(out *IngestAutoscaling) {
+	*out = *in
+	if in.QueueThreshold != nil {
+		in, out := &in.QueueThreshold, &out.QueueThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ScaleDownDelay != nil {
+		in, out := &in.ScaleDownDelay, &out.ScaleDownDelay
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngestAutoscaling.
+func (in *IngestAutoscaling) DeepCopy() *IngestAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(IngestAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngestAutoscalingStatus) DeepCopyInto(out *IngestAutoscalingStatus) {
+	*out = *in
+	if in.LastScaleTime != nil {
+		in, out := &in.LastScaleTime, &out.LastScaleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastPressureTime != nil {
+		in, out := &in.LastPressureTime, &out.LastPressureTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngestAutoscalingStatus.
+func (in *IngestAutoscalingStatus) DeepCopy() *IngestAutoscalingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IngestAutoscalingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LDAPRealm) DeepCopyInto(out *LDAPRealm) {
+	*out = *in
+	if in.URLs != nil {
+		in, out := &in.URLs, &out.URLs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BindPassword != nil {
+		in, out := &in.BindPassword, &out.BindPassword
+		*out = new(SecretKeyRef)
+		**out = **in
+	}
+	if in.UserDNTemplates != nil {
+		in, out := &in.UserDNTemplates, &out.UserDNTemplates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CertificateAuthorities != nil {
+		in, out := &in.CertificateAuthorities, &out.CertificateAuthorities
+		*out = new(SecretKeyRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPRealm.
+func (in *LDAPRealm) DeepCopy() *LDAPRealm {
+	if in == nil {
+		return nil
+	}
+	out := new(LDAPRealm)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicySpec) DeepCopyInto(out *NetworkPolicySpec) {
+	*out = *in
+	if in.EgressFQDNs != nil {
+		in, out := &in.EgressFQDNs, &out.EgressFQDNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicySpec.
+func (in *NetworkPolicySpec) DeepCopy() *NetworkPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NewNode) DeepCopyInto(out *NewNode) {
 	*out = *in
@@ -504,6 +829,26 @@ func (in *NodeSet) DeepCopyInto(out *NodeSet) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(commonv1.PodDisruptionBudgetTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IngestAutoscaling != nil {
+		in, out := &in.IngestAutoscaling, &out.IngestAutoscaling
+		*out = new(IngestAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tuning != nil {
+		in, out := &in.Tuning, &out.Tuning
+		*out = new(NodeSetTuning)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ZoneSpread != nil {
+		in, out := &in.ZoneSpread, &out.ZoneSpread
+		*out = new(ZoneSpread)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSet.
@@ -517,108 +862,344 @@ func (in *NodeSet) DeepCopy() *NodeSet {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RemoteCluster) DeepCopyInto(out *RemoteCluster) {
+func (in *NodeSetTuning) DeepCopyInto(out *NodeSetTuning) {
 	*out = *in
-	out.ElasticsearchRef = in.ElasticsearchRef
-	if in.APIKey != nil {
-		in, out := &in.APIKey, &out.APIKey
-		*out = new(RemoteClusterAPIKey)
+	if in.ThreadPools != nil {
+		in, out := &in.ThreadPools, &out.ThreadPools
+		*out = make(map[string]ThreadPoolSettings, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.CircuitBreakers != nil {
+		in, out := &in.CircuitBreakers, &out.CircuitBreakers
+		*out = new(CircuitBreakerSettings)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.SearchMaxBuckets != nil {
+		in, out := &in.SearchMaxBuckets, &out.SearchMaxBuckets
+		*out = new(int32)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteCluster.
-func (in *RemoteCluster) DeepCopy() *RemoteCluster {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSetTuning.
+func (in *NodeSetTuning) DeepCopy() *NodeSetTuning {
 	if in == nil {
 		return nil
 	}
-	out := new(RemoteCluster)
+	out := new(NodeSetTuning)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RemoteClusterAPIKey) DeepCopyInto(out *RemoteClusterAPIKey) {
+func (in *NodeSetZone) DeepCopyInto(out *NodeSetZone) {
 	*out = *in
-	in.Access.DeepCopyInto(&out.Access)
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteClusterAPIKey.
-func (in *RemoteClusterAPIKey) DeepCopy() *RemoteClusterAPIKey {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSetZone.
+func (in *NodeSetZone) DeepCopy() *NodeSetZone {
 	if in == nil {
 		return nil
 	}
-	out := new(RemoteClusterAPIKey)
+	out := new(NodeSetZone)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RemoteClusterAccess) DeepCopyInto(out *RemoteClusterAccess) {
+func (in *OIDCRealm) DeepCopyInto(out *OIDCRealm) {
 	*out = *in
-	if in.Search != nil {
-		in, out := &in.Search, &out.Search
-		*out = new(Search)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Replication != nil {
-		in, out := &in.Replication, &out.Replication
-		*out = new(Replication)
-		(*in).DeepCopyInto(*out)
+	out.ClientSecret = in.ClientSecret
+	if in.RequestedScopes != nil {
+		in, out := &in.RequestedScopes, &out.RequestedScopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
+	out.Claims = in.Claims
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteClusterAccess.
-func (in *RemoteClusterAccess) DeepCopy() *RemoteClusterAccess {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCRealm.
+func (in *OIDCRealm) DeepCopy() *OIDCRealm {
 	if in == nil {
 		return nil
 	}
-	out := new(RemoteClusterAccess)
+	out := new(OIDCRealm)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RemoteClusterServer) DeepCopyInto(out *RemoteClusterServer) {
+func (in *Plugin) DeepCopyInto(out *Plugin) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteClusterServer.
-func (in *RemoteClusterServer) DeepCopy() *RemoteClusterServer {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Plugin.
+func (in *Plugin) DeepCopy() *Plugin {
 	if in == nil {
 		return nil
 	}
-	out := new(RemoteClusterServer)
+	out := new(Plugin)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Replication) DeepCopyInto(out *Replication) {
+func (in *PluginsSpec) DeepCopyInto(out *PluginsSpec) {
 	*out = *in
-	if in.Names != nil {
-		in, out := &in.Names, &out.Names
-		*out = make([]string, len(*in))
+	if in.Install != nil {
+		in, out := &in.Install, &out.Install
+		*out = make([]Plugin, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Replication.
-func (in *Replication) DeepCopy() *Replication {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginsSpec.
+func (in *PluginsSpec) DeepCopy() *PluginsSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Replication)
+	out := new(PluginsSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RoleSource) DeepCopyInto(out *RoleSource) {
+func (in *Realm) DeepCopyInto(out *Realm) {
 	*out = *in
-	out.SecretRef = in.SecretRef
-}
-
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = new(OIDCRealm)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SAML != nil {
+		in, out := &in.SAML, &out.SAML
+		*out = new(SAMLRealm)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LDAP != nil {
+		in, out := &in.LDAP, &out.LDAP
+		*out = new(LDAPRealm)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Realm.
+func (in *Realm) DeepCopy() *Realm {
+	if in == nil {
+		return nil
+	}
+	out := new(Realm)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RealmUserProperties) DeepCopyInto(out *RealmUserProperties) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RealmUserProperties.
+func (in *RealmUserProperties) DeepCopy() *RealmUserProperties {
+	if in == nil {
+		return nil
+	}
+	out := new(RealmUserProperties)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteCluster) DeepCopyInto(out *RemoteCluster) {
+	*out = *in
+	out.ElasticsearchRef = in.ElasticsearchRef
+	if in.APIKey != nil {
+		in, out := &in.APIKey, &out.APIKey
+		*out = new(RemoteClusterAPIKey)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Replication != nil {
+		in, out := &in.Replication, &out.Replication
+		*out = new(RemoteClusterReplication)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteCluster.
+func (in *RemoteCluster) DeepCopy() *RemoteCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteClusterAPIKey) DeepCopyInto(out *RemoteClusterAPIKey) {
+	*out = *in
+	in.Access.DeepCopyInto(&out.Access)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteClusterAPIKey.
+func (in *RemoteClusterAPIKey) DeepCopy() *RemoteClusterAPIKey {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteClusterAPIKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteClusterAccess) DeepCopyInto(out *RemoteClusterAccess) {
+	*out = *in
+	if in.Search != nil {
+		in, out := &in.Search, &out.Search
+		*out = new(Search)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Replication != nil {
+		in, out := &in.Replication, &out.Replication
+		*out = new(Replication)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteClusterAccess.
+func (in *RemoteClusterAccess) DeepCopy() *RemoteClusterAccess {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteClusterAccess)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteClusterReplication) DeepCopyInto(out *RemoteClusterReplication) {
+	*out = *in
+	if in.AutoFollowPatterns != nil {
+		in, out := &in.AutoFollowPatterns, &out.AutoFollowPatterns
+		*out = make([]AutoFollowPattern, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.FollowerIndices != nil {
+		in, out := &in.FollowerIndices, &out.FollowerIndices
+		*out = make([]FollowerIndex, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteClusterReplication.
+func (in *RemoteClusterReplication) DeepCopy() *RemoteClusterReplication {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteClusterReplication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteClusterServer) DeepCopyInto(out *RemoteClusterServer) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteClusterServer.
+func (in *RemoteClusterServer) DeepCopy() *RemoteClusterServer {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteClusterServer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Replication) DeepCopyInto(out *Replication) {
+	*out = *in
+	if in.Names != nil {
+		in, out := &in.Names, &out.Names
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Replication.
+func (in *Replication) DeepCopy() *Replication {
+	if in == nil {
+		return nil
+	}
+	out := new(Replication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceRecommendation) DeepCopyInto(out *ResourceRecommendation) {
+	*out = *in
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.CPU != nil {
+		in, out := &in.CPU, &out.CPU
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Reasons != nil {
+		in, out := &in.Reasons, &out.Reasons
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceRecommendation.
+func (in *ResourceRecommendation) DeepCopy() *ResourceRecommendation {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceRecommendation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceRecommendations) DeepCopyInto(out *ResourceRecommendations) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceRecommendations.
+func (in *ResourceRecommendations) DeepCopy() *ResourceRecommendations {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceRecommendations)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoleSource) DeepCopyInto(out *RoleSource) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+}
+
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleSource.
 func (in *RoleSource) DeepCopy() *RoleSource {
 	if in == nil {
@@ -629,6 +1210,52 @@ func (in *RoleSource) DeepCopy() *RoleSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollingRestartBudget) DeepCopyInto(out *RollingRestartBudget) {
+	*out = *in
+	if in.MaxConcurrentRestarts != nil {
+		in, out := &in.MaxConcurrentRestarts, &out.MaxConcurrentRestarts
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxConcurrentNodeSets != nil {
+		in, out := &in.MaxConcurrentNodeSets, &out.MaxConcurrentNodeSets
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollingRestartBudget.
+func (in *RollingRestartBudget) DeepCopy() *RollingRestartBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(RollingRestartBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SAMLRealm) DeepCopyInto(out *SAMLRealm) {
+	*out = *in
+	if in.IdPMetadata != nil {
+		in, out := &in.IdPMetadata, &out.IdPMetadata
+		*out = new(SecretKeyRef)
+		**out = **in
+	}
+	out.Attributes = in.Attributes
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SAMLRealm.
+func (in *SAMLRealm) DeepCopy() *SAMLRealm {
+	if in == nil {
+		return nil
+	}
+	out := new(SAMLRealm)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Search) DeepCopyInto(out *Search) {
 	*out = *in
@@ -658,6 +1285,21 @@ func (in *Search) DeepCopy() *Search {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretKeyRef) DeepCopyInto(out *SecretKeyRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeyRef.
+func (in *SecretKeyRef) DeepCopy() *SecretKeyRef {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretKeyRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SelfSignedTransportCertificates) DeepCopyInto(out *SelfSignedTransportCertificates) {
 	*out = *in
@@ -673,6 +1315,138 @@ func (in *SelfSignedTransportCertificates) DeepCopy() *SelfSignedTransportCertif
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotPolicyVerification) DeepCopyInto(out *SnapshotPolicyVerification) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotPolicyVerification.
+func (in *SnapshotPolicyVerification) DeepCopy() *SnapshotPolicyVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotPolicyVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotRepository) DeepCopyInto(out *SnapshotRepository) {
+	*out = *in
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = (*in).DeepCopy()
+	}
+	if in.SecureSettings != nil {
+		in, out := &in.SecureSettings, &out.SecureSettings
+		*out = make([]commonv1.SecretSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotRepository.
+func (in *SnapshotRepository) DeepCopy() *SnapshotRepository {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotRepository)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotVerification) DeepCopyInto(out *SnapshotVerification) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotVerification.
+func (in *SnapshotVerification) DeepCopy() *SnapshotVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotVerificationStatus) DeepCopyInto(out *SnapshotVerificationStatus) {
+	*out = *in
+	in.LastVerificationTime.DeepCopyInto(&out.LastVerificationTime)
+	if in.Policies != nil {
+		in, out := &in.Policies, &out.Policies
+		*out = make([]SnapshotPolicyVerification, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotVerificationStatus.
+func (in *SnapshotVerificationStatus) DeepCopy() *SnapshotVerificationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotVerificationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThreadPoolSettings) DeepCopyInto(out *ThreadPoolSettings) {
+	*out = *in
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		*out = new(int32)
+		**out = **in
+	}
+	if in.QueueSize != nil {
+		in, out := &in.QueueSize, &out.QueueSize
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThreadPoolSettings.
+func (in *ThreadPoolSettings) DeepCopy() *ThreadPoolSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(ThreadPoolSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopologySpreadPolicy) DeepCopyInto(out *TopologySpreadPolicy) {
+	*out = *in
+	if in.TopologyKeys != nil {
+		in, out := &in.TopologyKeys, &out.TopologyKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MinDomains != nil {
+		in, out := &in.MinDomains, &out.MinDomains
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologySpreadPolicy.
+func (in *TopologySpreadPolicy) DeepCopy() *TopologySpreadPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(TopologySpreadPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TransportConfig) DeepCopyInto(out *TransportConfig) {
 	*out = *in
@@ -699,6 +1473,11 @@ func (in *TransportTLSOptions) DeepCopyInto(out *TransportTLSOptions) {
 		copy(*out, *in)
 	}
 	out.Certificate = in.Certificate
+	if in.IssuerRef != nil {
+		in, out := &in.IssuerRef, &out.IssuerRef
+		*out = new(commonv1.CertManagerIssuerRef)
+		**out = **in
+	}
 	out.CertificateAuthorities = in.CertificateAuthorities
 	if in.SelfSignedCertificates != nil {
 		in, out := &in.SelfSignedCertificates, &out.SelfSignedCertificates
@@ -803,3 +1582,60 @@ func (in *UpscaleOperation) DeepCopy() *UpscaleOperation {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeSnapshotPolicy) DeepCopyInto(out *VolumeSnapshotPolicy) {
+	*out = *in
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeSnapshotPolicy.
+func (in *VolumeSnapshotPolicy) DeepCopy() *VolumeSnapshotPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeSnapshotPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZoneAwareness) DeepCopyInto(out *ZoneAwareness) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneAwareness.
+func (in *ZoneAwareness) DeepCopy() *ZoneAwareness {
+	if in == nil {
+		return nil
+	}
+	out := new(ZoneAwareness)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZoneSpread) DeepCopyInto(out *ZoneSpread) {
+	*out = *in
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]NodeSetZone, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneSpread.
+func (in *ZoneSpread) DeepCopy() *ZoneSpread {
+	if in == nil {
+		return nil
+	}
+	out := new(ZoneSpread)
+	in.DeepCopyInto(out)
+	return out
+}
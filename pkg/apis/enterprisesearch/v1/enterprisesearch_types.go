@@ -180,8 +180,11 @@ func (ent *EnterpriseSearch) GetObservedGeneration() int64 {
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="health",type="string",JSONPath=".status.health"
 // +kubebuilder:printcolumn:name="nodes",type="integer",JSONPath=".status.availableNodes",description="Available nodes"
+// +kubebuilder:printcolumn:name="expected",type="integer",JSONPath=".spec.count",description="Expected nodes"
 // +kubebuilder:printcolumn:name="version",type="string",JSONPath=".status.version",description="Enterprise Search version"
 // +kubebuilder:printcolumn:name="age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="elasticsearch",type="string",JSONPath=".status.associationStatus",description="Elasticsearch association status",priority=1
+// +kubebuilder:printcolumn:name="reconciled",type="date",JSONPath=".status.conditions[?(@.type=='Reconciling')].lastTransitionTime",description="Time of the last transition of the Reconciling condition",priority=1
 // +kubebuilder:subresource:scale:specpath=.spec.count,statuspath=.status.count,selectorpath=.status.selector
 // +kubebuilder:storageversion
 type EnterpriseSearch struct {
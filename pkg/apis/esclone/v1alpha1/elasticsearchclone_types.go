@@ -0,0 +1,196 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
+)
+
+const (
+	// Kind is inferred from the struct name using reflection in SchemeBuilder.Register()
+	// we duplicate it as a constant here for practical purposes.
+	Kind = "ElasticsearchClone"
+
+	// DefaultTTL is the default time to live of a clone.
+	DefaultTTL = 24 * time.Hour
+	// DefaultNodeCount is the default number of nodes of each NodeSet of a clone.
+	DefaultNodeCount int32 = 1
+
+	// CloneNameLabelName is the label set on the Elasticsearch cluster of a clone, holding the name of the clone.
+	CloneNameLabelName = "esclone.k8s.elastic.co/name"
+)
+
+// DefaultIndices are the indices restored by default: all the indices and data streams of the snapshot except the
+// system and hidden ones starting with a dot.
+var DefaultIndices = []string{"*", "-.*"}
+
+func init() {
+	SchemeBuilder.Register(&ElasticsearchClone{}, &ElasticsearchCloneList{})
+}
+
+// +kubebuilder:object:root=true
+
+// ElasticsearchClone provisions an ephemeral Elasticsearch cluster restored from a snapshot of an existing cluster.
+// +kubebuilder:resource:categories=elastic,shortName=esclone
+// +kubebuilder:printcolumn:name="Source",type="string",JSONPath=".spec.sourceRef.name"
+// +kubebuilder:printcolumn:name="Snapshot",type="string",JSONPath=".status.snapshot"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Expiration",type="date",JSONPath=".status.expirationTime"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Reconciled",type="date",JSONPath=".status.conditions[?(@.type=='Reconciling')].lastTransitionTime",description="Time of the last transition of the Reconciling condition",priority=1
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+type ElasticsearchClone struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ElasticsearchCloneSpec   `json:"spec,omitempty"`
+	Status ElasticsearchCloneStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ElasticsearchCloneList contains a list of ElasticsearchClone resources.
+type ElasticsearchCloneList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ElasticsearchClone `json:"items"`
+}
+
+type ElasticsearchCloneSpec struct {
+	// SourceRef is a reference to the Elasticsearch cluster to clone, managed by ECK in the same namespace.
+	SourceRef SourceRef `json:"sourceRef"`
+
+	// Repository is the name of the snapshot repository holding the snapshots of the source cluster. It must be
+	// declared in the snapshotRepositories of the source cluster, and is registered as read-only in the clone.
+	// +kubebuilder:validation:MinLength=1
+	Repository string `json:"repository"`
+
+	// Snapshot is the name of the snapshot to restore. Defaults to the latest successful snapshot of the repository
+	// when the clone is created.
+	// +kubebuilder:validation:Optional
+	Snapshot string `json:"snapshot,omitempty"`
+
+	// Indices are the names or patterns of the indices and data streams to restore. Defaults to all of them except the
+	// system and hidden ones starting with a dot. The global state of the source cluster is never restored.
+	// +kubebuilder:validation:Optional
+	Indices []string `json:"indices,omitempty"`
+
+	// NodeCount is the number of nodes of each NodeSet of the clone, replacing the count of the NodeSets of the source
+	// cluster. NodeSets without nodes in the source cluster remain empty. Defaults to 1.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	NodeCount *int32 `json:"nodeCount,omitempty"`
+
+	// Resources are the compute resources of the Elasticsearch containers of the clone, replacing the ones of the
+	// source cluster.
+	// +kubebuilder:validation:Optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// StorageSize is the size of the data volume of each node of the clone, replacing the one of the source cluster.
+	// +kubebuilder:validation:Optional
+	StorageSize *resource.Quantity `json:"storageSize,omitempty"`
+
+	// TTL is the time to live of the clone, from its creation. The ElasticsearchClone and its Elasticsearch cluster are
+	// deleted once it expires. Defaults to 24h.
+	// +kubebuilder:validation:Optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+}
+
+// SourceRef is a reference to an Elasticsearch cluster managed by ECK in the same namespace.
+type SourceRef struct {
+	// Name of the Elasticsearch cluster.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+type ElasticsearchCloneStatus struct {
+	// Phase is the phase of the ElasticsearchClone.
+	Phase ClonePhase `json:"phase,omitempty"`
+	// Message gives details about the current phase.
+	Message string `json:"message,omitempty"`
+	// Snapshot is the name of the snapshot restored in the clone.
+	Snapshot string `json:"snapshot,omitempty"`
+	// ExpirationTime is the time at which the clone is deleted.
+	ExpirationTime *metav1.Time `json:"expirationTime,omitempty"`
+	// RestoreStartTime is the time at which the restore of the snapshot started.
+	RestoreStartTime *metav1.Time `json:"restoreStartTime,omitempty"`
+	// RestoreCompletionTime is the time at which the restore of the snapshot completed.
+	RestoreCompletionTime *metav1.Time `json:"restoreCompletionTime,omitempty"`
+	// ObservedGeneration is the most recent generation observed for this ElasticsearchClone.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions holds the Ready, Reconciling and Stalled conditions of the ElasticsearchClone.
+	// +kubebuilder:validation:Optional
+	Conditions commonv1alpha1.Conditions `json:"conditions,omitempty"`
+}
+
+// ResourceState returns the state of the ElasticsearchClone from which its conditions are computed. An invalid
+// ElasticsearchClone is stalled until it is updated.
+func (s ElasticsearchCloneStatus) ResourceState() commonv1alpha1.ResourceState {
+	return commonv1alpha1.NewPhaseState(s.ObservedGeneration, string(s.Phase), s.Phase == ReadyPhase, s.Phase == InvalidPhase, s.Message)
+}
+
+type ClonePhase string
+
+const (
+	// PendingPhase means the clone cannot be provisioned yet, for example because the source cluster is not ready.
+	PendingPhase ClonePhase = "Pending"
+	// ProvisioningPhase means the Elasticsearch cluster of the clone is being created.
+	ProvisioningPhase ClonePhase = "Provisioning"
+	// RestoringPhase means the snapshot is being restored in the clone.
+	RestoringPhase ClonePhase = "Restoring"
+	// ReadyPhase means the snapshot is restored in the clone.
+	ReadyPhase ClonePhase = "Ready"
+	// InvalidPhase means the resource does not pass validation.
+	InvalidPhase ClonePhase = "Invalid"
+	// ErrorPhase means the clone could not be provisioned or restored.
+	ErrorPhase ClonePhase = "Error"
+)
+
+// SourceRef returns the namespaced name of the source Elasticsearch cluster.
+func (c *ElasticsearchClone) SourceRef() types.NamespacedName {
+	return types.NamespacedName{Namespace: c.Namespace, Name: c.Spec.SourceRef.Name}
+}
+
+// IsMarkedForDeletion returns true if the ElasticsearchClone resource is going to be deleted.
+func (c *ElasticsearchClone) IsMarkedForDeletion() bool {
+	return !c.DeletionTimestamp.IsZero()
+}
+
+// ExpirationTime returns the time at which the clone expires.
+func (c *ElasticsearchClone) ExpirationTime() time.Time {
+	return c.CreationTimestamp.Add(c.Spec.TTLOrDefault())
+}
+
+// TTLOrDefault returns the time to live of the clone.
+func (s ElasticsearchCloneSpec) TTLOrDefault() time.Duration {
+	if s.TTL == nil || s.TTL.Duration <= 0 {
+		return DefaultTTL
+	}
+	return s.TTL.Duration
+}
+
+// NodeCountOrDefault returns the number of nodes of each NodeSet of the clone.
+func (s ElasticsearchCloneSpec) NodeCountOrDefault() int32 {
+	if s.NodeCount == nil {
+		return DefaultNodeCount
+	}
+	return *s.NodeCount
+}
+
+// IndicesOrDefault returns the indices to restore.
+func (s ElasticsearchCloneSpec) IndicesOrDefault() []string {
+	if len(s.Indices) == 0 {
+		return DefaultIndices
+	}
+	return s.Indices
+}
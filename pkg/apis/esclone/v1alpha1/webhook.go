@@ -0,0 +1,149 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"errors"
+	"slices"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+const (
+	// webhookPath is the HTTP path for the ElasticsearchClone validating webhook.
+	webhookPath = "/validate-esclone-k8s-elastic-co-v1alpha1-elasticsearchclones"
+)
+
+var (
+	groupKind     = schema.GroupKind{Group: GroupVersion.Group, Kind: Kind}
+	validationLog = ulog.Log.WithName("esclone-v1alpha1-validation")
+
+	defaultChecks = []func(*ElasticsearchClone) field.ErrorList{
+		checkNoUnknownFields,
+		checkNameLength,
+		checkSourceRef,
+		checkRepository,
+	}
+
+	updateChecks = []func(old, curr *ElasticsearchClone) field.ErrorList{
+		checkImmutableFields,
+	}
+)
+
+// +kubebuilder:webhook:path=/validate-esclone-k8s-elastic-co-v1alpha1-elasticsearchclones,mutating=false,failurePolicy=ignore,groups=esclone.k8s.elastic.co,resources=elasticsearchclones,verbs=create;update,versions=v1alpha1,name=elastic-esclone-validation-v1alpha1.k8s.elastic.co,sideEffects=None,admissionReviewVersions=v1;v1beta1,matchPolicy=Exact
+
+var _ webhook.Validator = &ElasticsearchClone{}
+
+// ValidateCreate is called by the validating webhook to validate the create operation.
+// Satisfies the webhook.Validator interface.
+func (c *ElasticsearchClone) ValidateCreate() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate create", "name", c.Name)
+	return nil, c.validate(nil)
+}
+
+// ValidateDelete is called by the validating webhook to validate the delete operation.
+// Satisfies the webhook.Validator interface.
+func (c *ElasticsearchClone) ValidateDelete() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate delete", "name", c.Name)
+	return nil, nil
+}
+
+// ValidateUpdate is called by the validating webhook to validate the update operation.
+// Satisfies the webhook.Validator interface.
+func (c *ElasticsearchClone) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate update", "name", c.Name)
+	oldObj, ok := old.(*ElasticsearchClone)
+	if !ok {
+		return nil, errors.New("cannot cast old object to ElasticsearchClone type")
+	}
+	return nil, c.validate(oldObj)
+}
+
+// WebhookPath returns the HTTP path used by the validating webhook.
+func (c *ElasticsearchClone) WebhookPath() string {
+	return webhookPath
+}
+
+// Validate runs the validation checks of the ElasticsearchClone, it is also used by the controller in case the
+// webhook is disabled.
+func (c *ElasticsearchClone) Validate() error {
+	return c.validate(nil)
+}
+
+func (c *ElasticsearchClone) validate(old *ElasticsearchClone) error {
+	var errs field.ErrorList
+	if old != nil {
+		for _, uc := range updateChecks {
+			if err := uc(old, c); err != nil {
+				errs = append(errs, err...)
+			}
+		}
+	}
+
+	for _, dc := range defaultChecks {
+		if err := dc(c); err != nil {
+			errs = append(errs, err...)
+		}
+	}
+
+	if len(errs) > 0 {
+		validationLog.V(1).Info("failed validation", "errors", errs)
+		return apierrors.NewInvalid(groupKind, c.Name, errs)
+	}
+	return nil
+}
+
+func checkNoUnknownFields(c *ElasticsearchClone) field.ErrorList {
+	return commonv1.NoUnknownFields(c, c.ObjectMeta)
+}
+
+func checkNameLength(c *ElasticsearchClone) field.ErrorList {
+	// the Elasticsearch cluster of the clone is named after the ElasticsearchClone
+	return commonv1.CheckNameLength(c)
+}
+
+func checkSourceRef(c *ElasticsearchClone) field.ErrorList {
+	path := field.NewPath("spec").Child("sourceRef").Child("name")
+	if c.Spec.SourceRef.Name == "" {
+		return field.ErrorList{field.Required(path, "sourceRef name is mandatory")}
+	}
+	if c.Spec.SourceRef.Name == c.Name {
+		return field.ErrorList{field.Invalid(path, c.Spec.SourceRef.Name, "the clone must have a different name than the source cluster")}
+	}
+	return nil
+}
+
+func checkRepository(c *ElasticsearchClone) field.ErrorList {
+	if c.Spec.Repository == "" {
+		return field.ErrorList{field.Required(field.NewPath("spec").Child("repository"), "repository is mandatory")}
+	}
+	return nil
+}
+
+func checkImmutableFields(old, curr *ElasticsearchClone) field.ErrorList {
+	var errs field.ErrorList
+	path := field.NewPath("spec")
+	if old.Spec.SourceRef != curr.Spec.SourceRef {
+		errs = append(errs, field.Forbidden(path.Child("sourceRef"), "sourceRef cannot be changed"))
+	}
+	if old.Spec.Repository != curr.Spec.Repository {
+		errs = append(errs, field.Forbidden(path.Child("repository"), "repository cannot be changed"))
+	}
+	if old.Spec.Snapshot != curr.Spec.Snapshot {
+		errs = append(errs, field.Forbidden(path.Child("snapshot"), "snapshot cannot be changed"))
+	}
+	if !slices.Equal(old.Spec.Indices, curr.Spec.Indices) {
+		errs = append(errs, field.Forbidden(path.Child("indices"), "indices cannot be changed"))
+	}
+	return errs
+}
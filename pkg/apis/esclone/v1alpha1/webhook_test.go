@@ -0,0 +1,134 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	esclonev1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/esclone/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/test"
+)
+
+func TestWebhook(t *testing.T) {
+	testCases := []test.ValidationWebhookTestCase{
+		{
+			Name:      "create-valid",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkElasticsearchClone(uid))
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "no-source",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkElasticsearchClone(uid)
+				m.Spec.SourceRef.Name = ""
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.sourceRef.name: Required value: sourceRef name is mandatory`,
+			),
+		},
+		{
+			Name:      "source-with-the-same-name",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkElasticsearchClone(uid)
+				m.Spec.SourceRef.Name = m.Name
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.sourceRef.name: Invalid value: "search-preview": the clone must have a different name than the source cluster`,
+			),
+		},
+		{
+			Name:      "no-repository",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkElasticsearchClone(uid)
+				m.Spec.Repository = ""
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.repository: Required value: repository is mandatory`,
+			),
+		},
+		{
+			Name:      "update-snapshot",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkElasticsearchClone(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkElasticsearchClone(uid)
+				m.Spec.Snapshot = "nightly-2024.10.02"
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.snapshot: Forbidden: snapshot cannot be changed`,
+			),
+		},
+		{
+			Name:      "update-ttl-and-sizing",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkElasticsearchClone(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkElasticsearchClone(uid)
+				m.Spec.TTL = &metav1.Duration{Duration: 48 * time.Hour}
+				m.Spec.NodeCount = ptr.To[int32](2)
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+	}
+
+	validator := &esclonev1alpha1.ElasticsearchClone{}
+	gvk := metav1.GroupVersionKind{Group: esclonev1alpha1.GroupVersion.Group, Version: esclonev1alpha1.GroupVersion.Version, Kind: esclonev1alpha1.Kind}
+	test.RunValidationWebhookTests(t, gvk, validator, testCases...)
+}
+
+func mkElasticsearchClone(uid string) *esclonev1alpha1.ElasticsearchClone {
+	return &esclonev1alpha1.ElasticsearchClone{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "search-preview",
+			Namespace: "default",
+			UID:       types.UID(uid),
+		},
+		Spec: esclonev1alpha1.ElasticsearchCloneSpec{
+			SourceRef:  esclonev1alpha1.SourceRef{Name: "search"},
+			Repository: "backups",
+			Snapshot:   "nightly-2024.10.01",
+		},
+	}
+}
+
+func serialize(t *testing.T, clone *esclonev1alpha1.ElasticsearchClone) []byte {
+	t.Helper()
+
+	objBytes, err := json.Marshal(clone)
+	require.NoError(t, err)
+
+	return objBytes
+}
@@ -0,0 +1,379 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
+)
+
+const (
+	// Kind is inferred from the struct name using reflection in SchemeBuilder.Register()
+	// we duplicate it as a constant here for practical purposes.
+	Kind = "ElasticsearchConfig"
+
+	// DefaultDriftDetectionInterval is the default interval at which the state of the operations is compared with their
+	// expected state in Elasticsearch.
+	DefaultDriftDetectionInterval = 5 * time.Minute
+
+	// ComponentTemplateOperationPrefix prefixes the name of the operations applying the component templates.
+	ComponentTemplateOperationPrefix = "component-template/"
+	// IndexTemplateOperationPrefix prefixes the name of the operations applying the composable index templates.
+	IndexTemplateOperationPrefix = "index-template/"
+	// AliasOperationPrefix prefixes the name of the operations applying the aliases.
+	AliasOperationPrefix = "alias/"
+)
+
+func init() {
+	SchemeBuilder.Register(&ElasticsearchConfig{}, &ElasticsearchConfigList{})
+}
+
+// +kubebuilder:object:root=true
+
+// ElasticsearchConfig declares Elasticsearch API resources to be managed by ECK in an Elasticsearch cluster through
+// idempotent requests.
+// +kubebuilder:resource:categories=elastic,shortName=esconfig
+// +kubebuilder:printcolumn:name="Elasticsearch",type="string",JSONPath=".spec.elasticsearchRef.name"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Reconciled",type="date",JSONPath=".status.conditions[?(@.type=='Reconciling')].lastTransitionTime",description="Time of the last transition of the Reconciling condition",priority=1
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+type ElasticsearchConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ElasticsearchConfigSpec   `json:"spec,omitempty"`
+	Status ElasticsearchConfigStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ElasticsearchConfigList contains a list of ElasticsearchConfig resources.
+type ElasticsearchConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ElasticsearchConfig `json:"items"`
+}
+
+type ElasticsearchConfigSpec struct {
+	// ElasticsearchRef is a reference to the Elasticsearch cluster in which the operations are applied. The
+	// Elasticsearch cluster must be managed by ECK.
+	ElasticsearchRef commonv1.ObjectSelector `json:"elasticsearchRef"`
+
+	// ServiceAccountName is used to check access from the current resource to an Elasticsearch resource in a different namespace.
+	// Can only be used if ECK is enforcing RBAC on references.
+	// +kubebuilder:validation:Optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// Operations are the requests applied to Elasticsearch, in the order in which they are declared unless a
+	// different order is required by their dependencies. They are applied after the component and index templates, and
+	// before the aliases.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinItems=1
+	Operations []Operation `json:"operations,omitempty"`
+
+	// IndexTemplates holds the component templates and the composable index templates to create or update, by name.
+	// Component templates are applied before the index templates composed of them.
+	// +kubebuilder:validation:Optional
+	IndexTemplates IndexTemplates `json:"indexTemplates,omitempty"`
+
+	// Aliases holds the definitions of the index aliases to create or update, by name, with the `index` or `indices`
+	// they point to. They are applied once the index templates and the operations are applied.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Aliases *commonv1.Config `json:"aliases,omitempty"`
+
+	// DriftDetectionInterval is the interval at which the state of the operations declaring an expected state is
+	// compared with the state of Elasticsearch, to re-apply the operations which drifted. Defaults to 5m.
+	// +kubebuilder:validation:Optional
+	DriftDetectionInterval *metav1.Duration `json:"driftDetectionInterval,omitempty"`
+}
+
+// IndexTemplates holds component templates and composable index templates, by name.
+type IndexTemplates struct {
+	// ComponentTemplates holds the Component Templates (/_component_template)
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	ComponentTemplates *commonv1.Config `json:"componentTemplates,omitempty"`
+	// ComposableIndexTemplates holds the Index Templates (/_index_template)
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	ComposableIndexTemplates *commonv1.Config `json:"composableIndexTemplates,omitempty"`
+}
+
+// Operation is an idempotent request to the Elasticsearch API.
+type Operation struct {
+	// Name identifies the operation in the status and in the dependencies of the other operations.
+	Name string `json:"name"`
+
+	// Method is the HTTP method of the request.
+	// +kubebuilder:validation:Enum=PUT;POST;DELETE
+	Method string `json:"method"`
+
+	// Path is the path of the request, including the query string if any, for example `/_ilm/policy/my-policy`.
+	Path string `json:"path"`
+
+	// Body is the JSON body of the request.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Body *commonv1.Config `json:"body,omitempty"`
+
+	// ExpectedState describes how to check whether the operation is applied in Elasticsearch. Operations with an
+	// expected state are only applied if Elasticsearch does not match it, and are re-applied if Elasticsearch drifts
+	// from it. Operations without an expected state are applied once, and again every time they are changed.
+	// +kubebuilder:validation:Optional
+	ExpectedState *ExpectedState `json:"expectedState,omitempty"`
+
+	// DependsOn lists the names of the operations which must be applied before this one.
+	// +kubebuilder:validation:Optional
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+// ExpectedState describes the state of Elasticsearch once an operation is applied.
+type ExpectedState struct {
+	// Path of the GET request returning the state of the resource. Defaults to the path of the operation.
+	// +kubebuilder:validation:Optional
+	Path string `json:"path,omitempty"`
+
+	// Body is the subset of the response of the GET request expected once the operation is applied. Only the given
+	// fields are compared. If not set, the operation is considered applied as soon as the resource exists. It is
+	// ignored for DELETE operations, which are considered applied once the resource does not exist.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Body *commonv1.Config `json:"body,omitempty"`
+}
+
+type ElasticsearchConfigStatus struct {
+	// Phase is the phase of the ElasticsearchConfig.
+	Phase ConfigPhase `json:"phase,omitempty"`
+	// Message gives details about the current phase.
+	Message string `json:"message,omitempty"`
+	// Operations is the status of each operation.
+	Operations []OperationStatus `json:"operations,omitempty"`
+	// ObservedGeneration is the most recent generation observed for this ElasticsearchConfig.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions holds the Ready, Reconciling and Stalled conditions of the ElasticsearchConfig.
+	// +kubebuilder:validation:Optional
+	Conditions commonv1alpha1.Conditions `json:"conditions,omitempty"`
+}
+
+// ResourceState returns the state of the ElasticsearchConfig from which its conditions are computed. An invalid
+// ElasticsearchConfig is stalled until it is updated.
+func (s ElasticsearchConfigStatus) ResourceState() commonv1alpha1.ResourceState {
+	return commonv1alpha1.NewPhaseState(s.ObservedGeneration, string(s.Phase), s.Phase == ReadyPhase, s.Phase == InvalidPhase, s.Message)
+}
+
+// OperationStatus is the status of an operation.
+type OperationStatus struct {
+	// Name of the operation.
+	Name string `json:"name"`
+	// Phase of the operation.
+	Phase OperationPhase `json:"phase"`
+	// Hash of the operation last applied.
+	Hash string `json:"hash,omitempty"`
+	// LastAppliedTime is the last time the operation was applied.
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+	// LastDriftTime is the last time Elasticsearch was detected to have drifted from the expected state of the operation.
+	LastDriftTime *metav1.Time `json:"lastDriftTime,omitempty"`
+	// Message gives details about the phase of the operation.
+	Message string `json:"message,omitempty"`
+}
+
+type ConfigPhase string
+
+const (
+	// PendingPhase means the operations cannot be applied yet, for example because the Elasticsearch cluster is not ready.
+	PendingPhase ConfigPhase = "Pending"
+	// ReadyPhase means all the operations are applied.
+	ReadyPhase ConfigPhase = "Ready"
+	// InvalidPhase means the resource does not pass validation or is not allowed to reference the Elasticsearch cluster.
+	InvalidPhase ConfigPhase = "Invalid"
+	// ErrorPhase means at least one of the operations could not be applied.
+	ErrorPhase ConfigPhase = "Error"
+)
+
+type OperationPhase string
+
+const (
+	// OperationAppliedPhase means the operation is applied.
+	OperationAppliedPhase OperationPhase = "Applied"
+	// OperationPendingPhase means the operation waits for the operations it depends on.
+	OperationPendingPhase OperationPhase = "Pending"
+	// OperationFailedPhase means the operation could not be applied.
+	OperationFailedPhase OperationPhase = "Failed"
+)
+
+// ElasticsearchRef returns the reference to the Elasticsearch cluster with the default namespace applied.
+func (c *ElasticsearchConfig) ElasticsearchRef() commonv1.ObjectSelector {
+	return c.Spec.ElasticsearchRef.WithDefaultNamespace(c.Namespace)
+}
+
+// IsMarkedForDeletion returns true if the ElasticsearchConfig resource is going to be deleted.
+func (c *ElasticsearchConfig) IsMarkedForDeletion() bool {
+	return !c.DeletionTimestamp.IsZero()
+}
+
+// DriftDetectionIntervalOrDefault returns the interval at which drifts are detected.
+func (s ElasticsearchConfigSpec) DriftDetectionIntervalOrDefault() time.Duration {
+	if s.DriftDetectionInterval == nil || s.DriftDetectionInterval.Duration <= 0 {
+		return DefaultDriftDetectionInterval
+	}
+	return s.DriftDetectionInterval.Duration
+}
+
+// AllOperations returns the operations applying the component templates, the index templates, the declared operations
+// and the aliases, in this order. The operations generated for the templates and the aliases are named after their
+// kind and name, for example `component-template/my-template`, so that declared operations can depend on them.
+func (s ElasticsearchConfigSpec) AllOperations() []Operation {
+	componentTemplates := configData(s.IndexTemplates.ComponentTemplates)
+	indexTemplates := configData(s.IndexTemplates.ComposableIndexTemplates)
+	aliases := configData(s.Aliases)
+	operations := make([]Operation, 0, len(componentTemplates)+len(indexTemplates)+len(s.Operations)+len(aliases))
+
+	for _, name := range sortedKeys(componentTemplates) {
+		operations = append(operations, Operation{
+			Name:   ComponentTemplateOperationPrefix + name,
+			Method: http.MethodPut,
+			Path:   "/_component_template/" + url.PathEscape(name),
+			Body:   definitionBody(componentTemplates[name]),
+		})
+	}
+
+	indexTemplateOperations := make([]string, 0, len(indexTemplates))
+	for _, name := range sortedKeys(indexTemplates) {
+		op := Operation{
+			Name:   IndexTemplateOperationPrefix + name,
+			Method: http.MethodPut,
+			Path:   "/_index_template/" + url.PathEscape(name),
+			Body:   definitionBody(indexTemplates[name]),
+		}
+		// the component templates declared along the index template are applied first
+		if op.Body != nil {
+			composedOf, _ := op.Body.Data["composed_of"].([]interface{})
+			for _, component := range composedOf {
+				componentName, ok := component.(string)
+				if _, declared := componentTemplates[componentName]; ok && declared {
+					op.DependsOn = append(op.DependsOn, ComponentTemplateOperationPrefix+componentName)
+				}
+			}
+		}
+		indexTemplateOperations = append(indexTemplateOperations, op.Name)
+		operations = append(operations, op)
+	}
+
+	operations = append(operations, s.Operations...)
+
+	for _, name := range sortedKeys(aliases) {
+		action := map[string]interface{}{"alias": name}
+		if definition, ok := aliases[name].(map[string]interface{}); ok {
+			for key, value := range definition {
+				action[key] = value
+			}
+		}
+		operations = append(operations, Operation{
+			Name:   AliasOperationPrefix + name,
+			Method: http.MethodPost,
+			Path:   "/_aliases",
+			Body: &commonv1.Config{Data: map[string]interface{}{
+				"actions": []interface{}{map[string]interface{}{"add": action}},
+			}},
+			// the indices and data streams targeted by the alias may be created from the index templates
+			DependsOn: slices.Clone(indexTemplateOperations),
+		})
+	}
+	return operations
+}
+
+// configData returns the data of the given config, or nil if there is none.
+func configData(config *commonv1.Config) map[string]interface{} {
+	if config == nil {
+		return nil
+	}
+	return config.Data
+}
+
+func sortedKeys(data map[string]interface{}) []string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// definitionBody returns the given definition as the body of a request, or nil if it is not an object.
+func definitionBody(definition interface{}) *commonv1.Config {
+	data, ok := definition.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	return &commonv1.Config{Data: data}
+}
+
+// OrderedOperations returns all the operations in the order in which they must be applied: in the order of
+// AllOperations, except for the operations which must be applied after the ones they depend on. An error is returned
+// if an operation depends on an unknown operation, or if the dependencies form a cycle.
+func (s ElasticsearchConfigSpec) OrderedOperations() ([]Operation, error) {
+	operations := s.AllOperations()
+	byName := make(map[string]Operation, len(operations))
+	for _, op := range operations {
+		byName[op.Name] = op
+	}
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int, len(operations))
+	ordered := make([]Operation, 0, len(operations))
+	var visit func(op Operation, path []string) error
+	visit = func(op Operation, path []string) error {
+		switch state[op.Name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("dependency cycle: %s", strings.Join(append(path, op.Name), " -> "))
+		}
+		state[op.Name] = visiting
+		path = append(slices.Clone(path), op.Name)
+		for _, dependency := range op.DependsOn {
+			dependsOn, exists := byName[dependency]
+			if !exists {
+				return fmt.Errorf("operation %s depends on unknown operation %s", op.Name, dependency)
+			}
+			if err := visit(dependsOn, path); err != nil {
+				return err
+			}
+		}
+		state[op.Name] = visited
+		ordered = append(ordered, op)
+		return nil
+	}
+	for _, op := range operations {
+		if err := visit(op, nil); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// OperationStatus returns the status of the operation with the given name, or nil if there is none.
+func (s ElasticsearchConfigStatus) OperationStatus(name string) *OperationStatus {
+	for i := range s.Operations {
+		if s.Operations[i].Name == name {
+			return &s.Operations[i]
+		}
+	}
+	return nil
+}
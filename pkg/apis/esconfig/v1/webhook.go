@@ -0,0 +1,247 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+const (
+	// webhookPath is the HTTP path for the ElasticsearchConfig validating webhook.
+	webhookPath = "/validate-esconfig-k8s-elastic-co-v1-elasticsearchconfigs"
+)
+
+var (
+	groupKind     = schema.GroupKind{Group: GroupVersion.Group, Kind: Kind}
+	validationLog = ulog.Log.WithName("esconfig-v1-validation")
+
+	supportedMethods = []string{http.MethodPut, http.MethodPost, http.MethodDelete}
+
+	reservedOperationPrefixes = []string{ComponentTemplateOperationPrefix, IndexTemplateOperationPrefix, AliasOperationPrefix}
+
+	defaultChecks = []func(*ElasticsearchConfig) field.ErrorList{
+		checkNoUnknownFields,
+		checkNameLength,
+		checkElasticsearchRef,
+		checkOperations,
+		checkIndexTemplates,
+		checkAliases,
+	}
+
+	updateChecks = []func(old, curr *ElasticsearchConfig) field.ErrorList{
+		checkImmutableFields,
+	}
+)
+
+// +kubebuilder:webhook:path=/validate-esconfig-k8s-elastic-co-v1-elasticsearchconfigs,mutating=false,failurePolicy=ignore,groups=esconfig.k8s.elastic.co,resources=elasticsearchconfigs,verbs=create;update,versions=v1,name=elastic-esconfig-validation-v1.k8s.elastic.co,sideEffects=None,admissionReviewVersions=v1;v1beta1,matchPolicy=Exact
+
+var _ webhook.Validator = &ElasticsearchConfig{}
+
+// ValidateCreate is called by the validating webhook to validate the create operation.
+// Satisfies the webhook.Validator interface.
+func (c *ElasticsearchConfig) ValidateCreate() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate create", "name", c.Name)
+	return nil, c.validate(nil)
+}
+
+// ValidateDelete is called by the validating webhook to validate the delete operation.
+// Satisfies the webhook.Validator interface.
+func (c *ElasticsearchConfig) ValidateDelete() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate delete", "name", c.Name)
+	return nil, nil
+}
+
+// ValidateUpdate is called by the validating webhook to validate the update operation.
+// Satisfies the webhook.Validator interface.
+func (c *ElasticsearchConfig) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate update", "name", c.Name)
+	oldObj, ok := old.(*ElasticsearchConfig)
+	if !ok {
+		return nil, errors.New("cannot cast old object to ElasticsearchConfig type")
+	}
+	return nil, c.validate(oldObj)
+}
+
+// WebhookPath returns the HTTP path used by the validating webhook.
+func (c *ElasticsearchConfig) WebhookPath() string {
+	return webhookPath
+}
+
+// Validate runs the validation checks of the ElasticsearchConfig, it is also used by the controller in case the
+// webhook is disabled.
+func (c *ElasticsearchConfig) Validate() error {
+	return c.validate(nil)
+}
+
+func (c *ElasticsearchConfig) validate(old *ElasticsearchConfig) error {
+	var errs field.ErrorList
+	if old != nil {
+		for _, uc := range updateChecks {
+			if err := uc(old, c); err != nil {
+				errs = append(errs, err...)
+			}
+		}
+	}
+
+	for _, dc := range defaultChecks {
+		if err := dc(c); err != nil {
+			errs = append(errs, err...)
+		}
+	}
+
+	if len(errs) > 0 {
+		validationLog.V(1).Info("failed validation", "errors", errs)
+		return apierrors.NewInvalid(groupKind, c.Name, errs)
+	}
+	return nil
+}
+
+func checkNoUnknownFields(c *ElasticsearchConfig) field.ErrorList {
+	return commonv1.NoUnknownFields(c, c.ObjectMeta)
+}
+
+func checkNameLength(c *ElasticsearchConfig) field.ErrorList {
+	return commonv1.CheckNameLength(c)
+}
+
+func checkElasticsearchRef(c *ElasticsearchConfig) field.ErrorList {
+	path := field.NewPath("spec").Child("elasticsearchRef")
+	if c.Spec.ElasticsearchRef.SecretName != "" {
+		return field.ErrorList{field.Forbidden(path.Child("secretName"), "ElasticsearchConfigs can only reference Elasticsearch clusters managed by ECK")}
+	}
+	if c.Spec.ElasticsearchRef.Name == "" {
+		return field.ErrorList{field.Required(path.Child("name"), "elasticsearchRef name is mandatory")}
+	}
+	return commonv1.CheckAssociationRefs(path, c.Spec.ElasticsearchRef)
+}
+
+func checkOperations(c *ElasticsearchConfig) field.ErrorList {
+	path := field.NewPath("spec").Child("operations")
+	if len(c.Spec.AllOperations()) == 0 {
+		return field.ErrorList{field.Required(path, "at least one operation, index template or alias is mandatory")}
+	}
+	var errs field.ErrorList
+	names := make(map[string]struct{}, len(c.Spec.Operations))
+	for i, op := range c.Spec.Operations {
+		opPath := path.Index(i)
+		if op.Name == "" {
+			errs = append(errs, field.Required(opPath.Child("name"), "operation name is mandatory"))
+		} else if _, exists := names[op.Name]; exists {
+			errs = append(errs, field.Duplicate(opPath.Child("name"), op.Name))
+		} else if isReservedOperationName(op.Name) {
+			errs = append(errs, field.Invalid(opPath.Child("name"), op.Name, fmt.Sprintf("operation names starting with %s are reserved",
+				strings.Join(reservedOperationPrefixes, ", "))))
+		}
+		names[op.Name] = struct{}{}
+		if !isSupportedMethod(op.Method) {
+			errs = append(errs, field.NotSupported(opPath.Child("method"), op.Method, supportedMethods))
+		}
+		errs = append(errs, checkPath(opPath.Child("path"), op.Path, true)...)
+		if op.Method == http.MethodDelete && op.Body != nil {
+			errs = append(errs, field.Forbidden(opPath.Child("body"), "DELETE operations cannot have a body"))
+		}
+		if op.ExpectedState != nil {
+			errs = append(errs, checkPath(opPath.Child("expectedState", "path"), op.ExpectedState.Path, false)...)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	// dependencies are only checked once all the operations are identified
+	if _, err := c.Spec.OrderedOperations(); err != nil {
+		errs = append(errs, field.Invalid(path, len(c.Spec.Operations), err.Error()))
+	}
+	return errs
+}
+
+func isReservedOperationName(name string) bool {
+	for _, prefix := range reservedOperationPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkIndexTemplates(c *ElasticsearchConfig) field.ErrorList {
+	path := field.NewPath("spec").Child("indexTemplates")
+	var errs field.ErrorList
+	errs = append(errs, checkDefinitions(path.Child("componentTemplates"), c.Spec.IndexTemplates.ComponentTemplates)...)
+	errs = append(errs, checkDefinitions(path.Child("composableIndexTemplates"), c.Spec.IndexTemplates.ComposableIndexTemplates)...)
+	return errs
+}
+
+func checkAliases(c *ElasticsearchConfig) field.ErrorList {
+	path := field.NewPath("spec").Child("aliases")
+	errs := checkDefinitions(path, c.Spec.Aliases)
+	if len(errs) > 0 {
+		return errs
+	}
+	for _, name := range sortedKeys(configData(c.Spec.Aliases)) {
+		definition, _ := c.Spec.Aliases.Data[name].(map[string]interface{})
+		_, hasIndex := definition["index"]
+		_, hasIndices := definition["indices"]
+		if !hasIndex && !hasIndices {
+			errs = append(errs, field.Required(path.Key(name), "the index or indices the alias points to are mandatory"))
+		}
+		if _, hasAlias := definition["alias"]; hasAlias {
+			errs = append(errs, field.Forbidden(path.Key(name).Child("alias"), "the name of the alias is its key"))
+		}
+	}
+	return errs
+}
+
+// checkDefinitions checks that the definitions of the given config, keyed by name, are objects.
+func checkDefinitions(path *field.Path, config *commonv1.Config) field.ErrorList {
+	var errs field.ErrorList
+	for _, name := range sortedKeys(configData(config)) {
+		if _, ok := config.Data[name].(map[string]interface{}); !ok {
+			errs = append(errs, field.Invalid(path.Key(name), config.Data[name], "definition must be an object"))
+		}
+	}
+	return errs
+}
+
+func checkPath(path *field.Path, value string, required bool) field.ErrorList {
+	if value == "" {
+		if required {
+			return field.ErrorList{field.Required(path, "path is mandatory")}
+		}
+		return nil
+	}
+	if !strings.HasPrefix(value, "/") {
+		return field.ErrorList{field.Invalid(path, value, "path must start with /")}
+	}
+	return nil
+}
+
+func isSupportedMethod(method string) bool {
+	for _, supported := range supportedMethods {
+		if method == supported {
+			return true
+		}
+	}
+	return false
+}
+
+func checkImmutableFields(old, curr *ElasticsearchConfig) field.ErrorList {
+	if old.ElasticsearchRef() != curr.ElasticsearchRef() {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec").Child("elasticsearchRef"), "elasticsearchRef cannot be changed")}
+	}
+	return nil
+}
@@ -0,0 +1,286 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esconfigv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/esconfig/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/test"
+)
+
+func TestWebhook(t *testing.T) {
+	testCases := []test.ValidationWebhookTestCase{
+		{
+			Name:      "create-valid",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkElasticsearchConfig(uid))
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "external-elasticsearch",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkElasticsearchConfig(uid)
+				m.Spec.ElasticsearchRef = commonv1.ObjectSelector{SecretName: "external-es"}
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.elasticsearchRef.secretName: Forbidden: ElasticsearchConfigs can only reference Elasticsearch clusters managed by ECK`,
+			),
+		},
+		{
+			Name:      "no-operations",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkElasticsearchConfig(uid)
+				m.Spec.Operations = nil
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.operations: Required value: at least one operation, index template or alias is mandatory`,
+			),
+		},
+		{
+			Name:      "invalid-operations",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkElasticsearchConfig(uid)
+				m.Spec.Operations = append(m.Spec.Operations,
+					esconfigv1.Operation{Name: "ilm-policy", Method: "GET", Path: "_ilm/policy/other"},
+					esconfigv1.Operation{Name: "delete", Method: "DELETE", Path: "/_template/legacy", Body: &commonv1.Config{Data: map[string]interface{}{}}},
+				)
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.operations\[2\].name: Duplicate value: "ilm-policy"`,
+				`spec.operations\[2\].method: Unsupported value: "GET"`,
+				`spec.operations\[2\].path: Invalid value: "_ilm/policy/other": path must start with /`,
+				`spec.operations\[3\].body: Forbidden: DELETE operations cannot have a body`,
+			),
+		},
+		{
+			Name:      "templates-and-aliases-only",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkElasticsearchConfig(uid)
+				m.Spec.Operations = nil
+				m.Spec.IndexTemplates.ComponentTemplates = &commonv1.Config{Data: map[string]interface{}{
+					"logs-mappings": map[string]interface{}{"template": map[string]interface{}{}},
+				}}
+				m.Spec.Aliases = &commonv1.Config{Data: map[string]interface{}{
+					"logs": map[string]interface{}{"indices": []interface{}{"logs-*"}},
+				}}
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "invalid-templates-and-aliases",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkElasticsearchConfig(uid)
+				m.Spec.Operations = append(m.Spec.Operations, esconfigv1.Operation{Name: "alias/logs", Method: "POST", Path: "/_aliases"})
+				m.Spec.IndexTemplates.ComposableIndexTemplates = &commonv1.Config{Data: map[string]interface{}{"logs": "logs-*"}}
+				m.Spec.Aliases = &commonv1.Config{Data: map[string]interface{}{
+					"logs":    map[string]interface{}{"is_write_index": true},
+					"metrics": map[string]interface{}{"index": "metrics-*", "alias": "other"},
+				}}
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.operations\[2\].name: Invalid value: "alias/logs": operation names starting with component-template/, index-template/, alias/ are reserved`,
+				`spec.indexTemplates.composableIndexTemplates\[logs\]: Invalid value: "logs-\*": definition must be an object`,
+				`spec.aliases\[logs\]: Required value: the index or indices the alias points to are mandatory`,
+				`spec.aliases\[metrics\].alias: Forbidden: the name of the alias is its key`,
+			),
+		},
+		{
+			Name:      "dependency-cycle",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkElasticsearchConfig(uid)
+				m.Spec.Operations[0].DependsOn = []string{"index-template"}
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookFailed(
+				`dependency cycle: ilm-policy -> index-template -> ilm-policy`,
+			),
+		},
+		{
+			Name:      "update-elasticsearch-ref",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkElasticsearchConfig(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkElasticsearchConfig(uid)
+				m.Spec.ElasticsearchRef.Name = "other"
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.elasticsearchRef: Forbidden: elasticsearchRef cannot be changed`,
+			),
+		},
+		{
+			Name:      "update-operations",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkElasticsearchConfig(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkElasticsearchConfig(uid)
+				m.Spec.Operations[0].Body.Data["policy"] = map[string]interface{}{}
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+	}
+
+	validator := &esconfigv1.ElasticsearchConfig{}
+	gvk := metav1.GroupVersionKind{Group: esconfigv1.GroupVersion.Group, Version: esconfigv1.GroupVersion.Version, Kind: esconfigv1.Kind}
+	test.RunValidationWebhookTests(t, gvk, validator, testCases...)
+}
+
+func TestElasticsearchConfigSpec_OrderedOperations(t *testing.T) {
+	names := func(ops []esconfigv1.Operation) []string {
+		result := make([]string, 0, len(ops))
+		for _, op := range ops {
+			result = append(result, op.Name)
+		}
+		return result
+	}
+	spec := esconfigv1.ElasticsearchConfigSpec{Operations: []esconfigv1.Operation{
+		{Name: "template", DependsOn: []string{"policy", "pipeline"}},
+		{Name: "pipeline"},
+		{Name: "policy"},
+		{Name: "other"},
+	}}
+	ordered, err := spec.OrderedOperations()
+	require.NoError(t, err)
+	require.Equal(t, []string{"policy", "pipeline", "template", "other"}, names(ordered))
+
+	spec.Operations[1].DependsOn = []string{"unknown"}
+	_, err = spec.OrderedOperations()
+	require.EqualError(t, err, "operation pipeline depends on unknown operation unknown")
+
+	spec.Operations[1].DependsOn = []string{"template"}
+	_, err = spec.OrderedOperations()
+	require.EqualError(t, err, "dependency cycle: template -> pipeline -> template")
+}
+
+func TestElasticsearchConfigSpec_AllOperations(t *testing.T) {
+	spec := esconfigv1.ElasticsearchConfigSpec{
+		Operations: []esconfigv1.Operation{{Name: "bootstrap", Method: "PUT", Path: "/logs-000001", DependsOn: []string{"index-template/logs"}}},
+		IndexTemplates: esconfigv1.IndexTemplates{
+			ComponentTemplates: &commonv1.Config{Data: map[string]interface{}{
+				"logs-settings": map[string]interface{}{"template": map[string]interface{}{}},
+				"unused":        map[string]interface{}{"template": map[string]interface{}{}},
+			}},
+			ComposableIndexTemplates: &commonv1.Config{Data: map[string]interface{}{
+				"logs": map[string]interface{}{"composed_of": []interface{}{"logs-settings", "builtin"}},
+			}},
+		},
+		Aliases: &commonv1.Config{Data: map[string]interface{}{
+			"logs-write": map[string]interface{}{"index": "logs-000001", "is_write_index": true},
+		}},
+	}
+	require.Equal(t, []esconfigv1.Operation{
+		{
+			Name:   "component-template/logs-settings",
+			Method: "PUT",
+			Path:   "/_component_template/logs-settings",
+			Body:   &commonv1.Config{Data: map[string]interface{}{"template": map[string]interface{}{}}},
+		},
+		{
+			Name:   "component-template/unused",
+			Method: "PUT",
+			Path:   "/_component_template/unused",
+			Body:   &commonv1.Config{Data: map[string]interface{}{"template": map[string]interface{}{}}},
+		},
+		{
+			Name:      "index-template/logs",
+			Method:    "PUT",
+			Path:      "/_index_template/logs",
+			Body:      &commonv1.Config{Data: map[string]interface{}{"composed_of": []interface{}{"logs-settings", "builtin"}}},
+			DependsOn: []string{"component-template/logs-settings"},
+		},
+		spec.Operations[0],
+		{
+			Name:   "alias/logs-write",
+			Method: "POST",
+			Path:   "/_aliases",
+			Body: &commonv1.Config{Data: map[string]interface{}{"actions": []interface{}{
+				map[string]interface{}{"add": map[string]interface{}{"alias": "logs-write", "index": "logs-000001", "is_write_index": true}},
+			}}},
+			DependsOn: []string{"index-template/logs"},
+		},
+	}, spec.AllOperations())
+
+	// the operations are already in the order of their dependencies
+	ordered, err := spec.OrderedOperations()
+	require.NoError(t, err)
+	require.Equal(t, spec.AllOperations(), ordered)
+}
+
+func mkElasticsearchConfig(uid string) *esconfigv1.ElasticsearchConfig {
+	return &esconfigv1.ElasticsearchConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "config-test",
+			Namespace: "default",
+			UID:       types.UID(uid),
+		},
+		Spec: esconfigv1.ElasticsearchConfigSpec{
+			ElasticsearchRef: commonv1.ObjectSelector{Name: "es"},
+			Operations: []esconfigv1.Operation{
+				{
+					Name:   "ilm-policy",
+					Method: "PUT",
+					Path:   "/_ilm/policy/logs",
+					Body: &commonv1.Config{Data: map[string]interface{}{
+						"policy": map[string]interface{}{"phases": map[string]interface{}{}},
+					}},
+				},
+				{
+					Name:          "index-template",
+					Method:        "PUT",
+					Path:          "/_index_template/logs",
+					Body:          &commonv1.Config{Data: map[string]interface{}{"index_patterns": []interface{}{"logs-*"}}},
+					ExpectedState: &esconfigv1.ExpectedState{},
+					DependsOn:     []string{"ilm-policy"},
+				},
+			},
+		},
+	}
+}
+
+func serialize(t *testing.T, config *esconfigv1.ElasticsearchConfig) []byte {
+	t.Helper()
+
+	objBytes, err := json.Marshal(config)
+	require.NoError(t, err)
+
+	return objBytes
+}
@@ -0,0 +1,156 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
+)
+
+const (
+	// Kind is inferred from the struct name using reflection in SchemeBuilder.Register()
+	// we duplicate it as a constant here for practical purposes.
+	Kind = "ElasticsearchTask"
+)
+
+func init() {
+	SchemeBuilder.Register(&ElasticsearchTask{}, &ElasticsearchTaskList{})
+}
+
+// +kubebuilder:object:root=true
+
+// ElasticsearchTask requests the operator to run a one-off maintenance operation on an Elasticsearch cluster.
+// +kubebuilder:resource:categories=elastic,shortName=estask
+// +kubebuilder:printcolumn:name="Elasticsearch",type="string",JSONPath=".spec.elasticsearchRef.name"
+// +kubebuilder:printcolumn:name="Type",type="string",JSONPath=".spec.type"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Completion",type="date",JSONPath=".status.completionTime"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Reconciled",type="date",JSONPath=".status.conditions[?(@.type=='Reconciling')].lastTransitionTime",description="Time of the last transition of the Reconciling condition",priority=1
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+type ElasticsearchTask struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ElasticsearchTaskSpec   `json:"spec,omitempty"`
+	Status ElasticsearchTaskStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ElasticsearchTaskList contains a list of ElasticsearchTask resources.
+type ElasticsearchTaskList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ElasticsearchTask `json:"items"`
+}
+
+type ElasticsearchTaskSpec struct {
+	// ElasticsearchRef is a reference to the Elasticsearch cluster to run the task on, managed by ECK in the same
+	// namespace.
+	ElasticsearchRef ElasticsearchRef `json:"elasticsearchRef"`
+
+	// Type is the type of the task.
+	// +kubebuilder:validation:Enum=RetryFailedAllocations;ClearCache;ForceMerge;SnapshotNow
+	Type TaskType `json:"type"`
+
+	// Indices are the names or patterns of the indices targeted by the ClearCache and ForceMerge tasks. Defaults to all
+	// the indices.
+	// +kubebuilder:validation:Optional
+	Indices []string `json:"indices,omitempty"`
+
+	// MaxNumSegments is the number of segments each shard is merged into by the ForceMerge task. Defaults to checking
+	// whether a merge is needed, and merging if so.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	MaxNumSegments *int32 `json:"maxNumSegments,omitempty"`
+
+	// Policy is the name of the snapshot lifecycle management policy executed by the SnapshotNow task.
+	// +kubebuilder:validation:Optional
+	Policy string `json:"policy,omitempty"`
+}
+
+// ElasticsearchRef is a reference to an Elasticsearch cluster managed by ECK in the same namespace.
+type ElasticsearchRef struct {
+	// Name of the Elasticsearch cluster.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// TaskType is the type of the operation run by an ElasticsearchTask.
+type TaskType string
+
+const (
+	// RetryFailedAllocationsTask retries the allocation of the shards which failed to be allocated too many times, for
+	// example after a node ran out of disk space.
+	RetryFailedAllocationsTask TaskType = "RetryFailedAllocations"
+	// ClearCacheTask clears the query, request and field data caches of the indices.
+	ClearCacheTask TaskType = "ClearCache"
+	// ForceMergeTask merges the segments of the indices. It runs in the background in Elasticsearch until completion.
+	ForceMergeTask TaskType = "ForceMerge"
+	// SnapshotNowTask takes a snapshot immediately with a snapshot lifecycle management policy, regardless of its
+	// schedule.
+	SnapshotNowTask TaskType = "SnapshotNow"
+)
+
+type ElasticsearchTaskStatus struct {
+	// Phase is the phase of the ElasticsearchTask.
+	Phase TaskPhase `json:"phase,omitempty"`
+	// Message gives details about the current phase.
+	Message string `json:"message,omitempty"`
+	// StartTime is the time at which the operator started the task.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is the time at which the task succeeded or failed.
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// ElasticsearchTaskID is the id of the Elasticsearch task running a ForceMerge task.
+	ElasticsearchTaskID string `json:"elasticsearchTaskID,omitempty"`
+	// Snapshot is the name of the snapshot taken by a SnapshotNow task.
+	Snapshot string `json:"snapshot,omitempty"`
+	// ObservedGeneration is the most recent generation observed for this ElasticsearchTask.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions holds the Ready, Reconciling and Stalled conditions of the ElasticsearchTask.
+	// +kubebuilder:validation:Optional
+	Conditions commonv1alpha1.Conditions `json:"conditions,omitempty"`
+}
+
+// ResourceState returns the state of the ElasticsearchTask from which its conditions are computed. A failed or invalid
+// ElasticsearchTask is stalled, a new task must be created to run the operation again.
+func (s ElasticsearchTaskStatus) ResourceState() commonv1alpha1.ResourceState {
+	return commonv1alpha1.NewPhaseState(s.ObservedGeneration, string(s.Phase), s.Phase == SucceededPhase,
+		s.Phase == FailedPhase || s.Phase == InvalidPhase, s.Message)
+}
+
+type TaskPhase string
+
+const (
+	// PendingPhase means the task cannot be started yet, for example because the Elasticsearch cluster is not available.
+	PendingPhase TaskPhase = "Pending"
+	// RunningPhase means the task is started and not completed yet.
+	RunningPhase TaskPhase = "Running"
+	// SucceededPhase means the task completed successfully.
+	SucceededPhase TaskPhase = "Succeeded"
+	// FailedPhase means the task completed with an error. It is not retried.
+	FailedPhase TaskPhase = "Failed"
+	// InvalidPhase means the resource does not pass validation.
+	InvalidPhase TaskPhase = "Invalid"
+)
+
+// ElasticsearchRef returns the namespaced name of the Elasticsearch cluster the task runs on.
+func (t *ElasticsearchTask) ElasticsearchRef() types.NamespacedName {
+	return types.NamespacedName{Namespace: t.Namespace, Name: t.Spec.ElasticsearchRef.Name}
+}
+
+// IsMarkedForDeletion returns true if the ElasticsearchTask resource is going to be deleted.
+func (t *ElasticsearchTask) IsMarkedForDeletion() bool {
+	return !t.DeletionTimestamp.IsZero()
+}
+
+// IsCompleted returns true if the task succeeded or failed. A completed task is never run again.
+func (s ElasticsearchTaskStatus) IsCompleted() bool {
+	return s.Phase == SucceededPhase || s.Phase == FailedPhase
+}
@@ -0,0 +1,148 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"errors"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+const (
+	// webhookPath is the HTTP path for the ElasticsearchTask validating webhook.
+	webhookPath = "/validate-estask-k8s-elastic-co-v1alpha1-elasticsearchtasks"
+)
+
+var (
+	groupKind     = schema.GroupKind{Group: GroupVersion.Group, Kind: Kind}
+	validationLog = ulog.Log.WithName("estask-v1alpha1-validation")
+
+	defaultChecks = []func(*ElasticsearchTask) field.ErrorList{
+		checkNoUnknownFields,
+		checkElasticsearchRef,
+		checkParameters,
+	}
+
+	updateChecks = []func(old, curr *ElasticsearchTask) field.ErrorList{
+		checkImmutableSpec,
+	}
+)
+
+// +kubebuilder:webhook:path=/validate-estask-k8s-elastic-co-v1alpha1-elasticsearchtasks,mutating=false,failurePolicy=ignore,groups=estask.k8s.elastic.co,resources=elasticsearchtasks,verbs=create;update,versions=v1alpha1,name=elastic-estask-validation-v1alpha1.k8s.elastic.co,sideEffects=None,admissionReviewVersions=v1;v1beta1,matchPolicy=Exact
+
+var _ webhook.Validator = &ElasticsearchTask{}
+
+// ValidateCreate is called by the validating webhook to validate the create operation.
+// Satisfies the webhook.Validator interface.
+func (t *ElasticsearchTask) ValidateCreate() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate create", "name", t.Name)
+	return nil, t.validate(nil)
+}
+
+// ValidateDelete is called by the validating webhook to validate the delete operation.
+// Satisfies the webhook.Validator interface.
+func (t *ElasticsearchTask) ValidateDelete() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate delete", "name", t.Name)
+	return nil, nil
+}
+
+// ValidateUpdate is called by the validating webhook to validate the update operation.
+// Satisfies the webhook.Validator interface.
+func (t *ElasticsearchTask) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate update", "name", t.Name)
+	oldObj, ok := old.(*ElasticsearchTask)
+	if !ok {
+		return nil, errors.New("cannot cast old object to ElasticsearchTask type")
+	}
+	return nil, t.validate(oldObj)
+}
+
+// WebhookPath returns the HTTP path used by the validating webhook.
+func (t *ElasticsearchTask) WebhookPath() string {
+	return webhookPath
+}
+
+// Validate runs the validation checks of the ElasticsearchTask, it is also used by the controller in case the
+// webhook is disabled.
+func (t *ElasticsearchTask) Validate() error {
+	return t.validate(nil)
+}
+
+func (t *ElasticsearchTask) validate(old *ElasticsearchTask) error {
+	var errs field.ErrorList
+	if old != nil {
+		for _, uc := range updateChecks {
+			if err := uc(old, t); err != nil {
+				errs = append(errs, err...)
+			}
+		}
+	}
+
+	for _, dc := range defaultChecks {
+		if err := dc(t); err != nil {
+			errs = append(errs, err...)
+		}
+	}
+
+	if len(errs) > 0 {
+		validationLog.V(1).Info("failed validation", "errors", errs)
+		return apierrors.NewInvalid(groupKind, t.Name, errs)
+	}
+	return nil
+}
+
+func checkNoUnknownFields(t *ElasticsearchTask) field.ErrorList {
+	return commonv1.NoUnknownFields(t, t.ObjectMeta)
+}
+
+func checkElasticsearchRef(t *ElasticsearchTask) field.ErrorList {
+	if t.Spec.ElasticsearchRef.Name == "" {
+		return field.ErrorList{field.Required(field.NewPath("spec").Child("elasticsearchRef").Child("name"), "elasticsearchRef name is mandatory")}
+	}
+	return nil
+}
+
+// checkParameters checks that only the parameters of the type of the task are set.
+func checkParameters(t *ElasticsearchTask) field.ErrorList {
+	var errs field.ErrorList
+	path := field.NewPath("spec")
+	switch t.Spec.Type {
+	case RetryFailedAllocationsTask, ClearCacheTask, ForceMergeTask, SnapshotNowTask:
+	default:
+		return field.ErrorList{field.NotSupported(path.Child("type"), t.Spec.Type,
+			[]string{string(RetryFailedAllocationsTask), string(ClearCacheTask), string(ForceMergeTask), string(SnapshotNowTask)})}
+	}
+	if len(t.Spec.Indices) > 0 && t.Spec.Type != ClearCacheTask && t.Spec.Type != ForceMergeTask {
+		errs = append(errs, field.Forbidden(path.Child("indices"), "indices can only be set for ClearCache and ForceMerge tasks"))
+	}
+	if t.Spec.MaxNumSegments != nil && t.Spec.Type != ForceMergeTask {
+		errs = append(errs, field.Forbidden(path.Child("maxNumSegments"), "maxNumSegments can only be set for ForceMerge tasks"))
+	}
+	switch {
+	case t.Spec.Type == SnapshotNowTask && t.Spec.Policy == "":
+		errs = append(errs, field.Required(path.Child("policy"), "policy is mandatory for SnapshotNow tasks"))
+	case t.Spec.Type != SnapshotNowTask && t.Spec.Policy != "":
+		errs = append(errs, field.Forbidden(path.Child("policy"), "policy can only be set for SnapshotNow tasks"))
+	}
+	return errs
+}
+
+// checkImmutableSpec forbids any change of the spec: a task is run once, a new task must be created to run another
+// operation.
+func checkImmutableSpec(old, curr *ElasticsearchTask) field.ErrorList {
+	if !equality.Semantic.DeepEqual(old.Spec, curr.Spec) {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec"), "spec cannot be changed, create a new ElasticsearchTask instead")}
+	}
+	return nil
+}
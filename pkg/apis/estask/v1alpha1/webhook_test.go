@@ -0,0 +1,166 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	estaskv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/estask/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/test"
+)
+
+func TestWebhook(t *testing.T) {
+	testCases := []test.ValidationWebhookTestCase{
+		{
+			Name:      "create-valid",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkElasticsearchTask(uid))
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "create-valid-snapshot-now",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkElasticsearchTask(uid)
+				m.Spec = estaskv1alpha1.ElasticsearchTaskSpec{
+					ElasticsearchRef: m.Spec.ElasticsearchRef,
+					Type:             estaskv1alpha1.SnapshotNowTask,
+					Policy:           "nightly",
+				}
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "no-elasticsearch",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkElasticsearchTask(uid)
+				m.Spec.ElasticsearchRef.Name = ""
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.elasticsearchRef.name: Required value: elasticsearchRef name is mandatory`,
+			),
+		},
+		{
+			Name:      "unknown-type",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkElasticsearchTask(uid)
+				m.Spec.Type = "DeleteIndices"
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.type: Unsupported value: "DeleteIndices"`,
+			),
+		},
+		{
+			Name:      "parameters-of-another-type",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkElasticsearchTask(uid)
+				m.Spec.Type = estaskv1alpha1.ClearCacheTask
+				m.Spec.Policy = "nightly"
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.maxNumSegments: Forbidden: maxNumSegments can only be set for ForceMerge tasks`,
+				`spec.policy: Forbidden: policy can only be set for SnapshotNow tasks`,
+			),
+		},
+		{
+			Name:      "snapshot-now-without-policy",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkElasticsearchTask(uid)
+				m.Spec = estaskv1alpha1.ElasticsearchTaskSpec{
+					ElasticsearchRef: m.Spec.ElasticsearchRef,
+					Type:             estaskv1alpha1.SnapshotNowTask,
+				}
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.policy: Required value: policy is mandatory for SnapshotNow tasks`,
+			),
+		},
+		{
+			Name:      "update-spec",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkElasticsearchTask(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkElasticsearchTask(uid)
+				m.Spec.Indices = []string{"metrics-*"}
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec: Forbidden: spec cannot be changed, create a new ElasticsearchTask instead`,
+			),
+		},
+		{
+			Name:      "update-metadata",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkElasticsearchTask(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkElasticsearchTask(uid)
+				m.Labels = map[string]string{"ticket": "OPS-1234"}
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+	}
+
+	validator := &estaskv1alpha1.ElasticsearchTask{}
+	gvk := metav1.GroupVersionKind{Group: estaskv1alpha1.GroupVersion.Group, Version: estaskv1alpha1.GroupVersion.Version, Kind: estaskv1alpha1.Kind}
+	test.RunValidationWebhookTests(t, gvk, validator, testCases...)
+}
+
+func mkElasticsearchTask(uid string) *estaskv1alpha1.ElasticsearchTask {
+	return &estaskv1alpha1.ElasticsearchTask{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "forcemerge-logs",
+			Namespace: "default",
+			UID:       types.UID(uid),
+		},
+		Spec: estaskv1alpha1.ElasticsearchTaskSpec{
+			ElasticsearchRef: estaskv1alpha1.ElasticsearchRef{Name: "search"},
+			Type:             estaskv1alpha1.ForceMergeTask,
+			Indices:          []string{"logs-*"},
+			MaxNumSegments:   ptr.To[int32](1),
+		},
+	}
+}
+
+func serialize(t *testing.T, task *estaskv1alpha1.ElasticsearchTask) []byte {
+	t.Helper()
+
+	objBytes, err := json.Marshal(task)
+	require.NoError(t, err)
+
+	return objBytes
+}
@@ -0,0 +1,137 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
+)
+
+const (
+	// Kind is inferred from the struct name using reflection in SchemeBuilder.Register()
+	// we duplicate it as a constant here for practical purposes.
+	Kind = "IndexTemplateClaim"
+
+	// MaxClaimsPerNamespaceAnnotation can be set on an Elasticsearch resource to limit the number of IndexTemplateClaims
+	// of a single namespace applied to that cluster.
+	MaxClaimsPerNamespaceAnnotation = "indextemplateclaim.k8s.elastic.co/max-claims-per-namespace"
+	// MaxShardsPerClaimAnnotation can be set on an Elasticsearch resource to limit the number of shards (primaries and
+	// replicas) of the backing indices created by a single IndexTemplateClaim.
+	MaxShardsPerClaimAnnotation = "indextemplateclaim.k8s.elastic.co/max-shards-per-claim"
+
+	// DefaultPriority is the default priority of the index templates created for IndexTemplateClaims. It is higher
+	// than the priority of the built-in index templates so that claims take precedence.
+	DefaultPriority int64 = 200
+)
+
+func init() {
+	SchemeBuilder.Register(&IndexTemplateClaim{}, &IndexTemplateClaimList{})
+}
+
+// +kubebuilder:object:root=true
+
+// IndexTemplateClaim represents a request from an application team to bootstrap a data stream and its index template
+// in an Elasticsearch cluster managed by ECK.
+// +kubebuilder:resource:categories=elastic,shortName=itc
+// +kubebuilder:printcolumn:name="Data stream",type="string",JSONPath=".spec.dataStream"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Reconciled",type="date",JSONPath=".status.conditions[?(@.type=='Reconciling')].lastTransitionTime",description="Time of the last transition of the Reconciling condition",priority=1
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+type IndexTemplateClaim struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   IndexTemplateClaimSpec   `json:"spec,omitempty"`
+	Status IndexTemplateClaimStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// IndexTemplateClaimList contains a list of IndexTemplateClaim resources.
+type IndexTemplateClaimList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IndexTemplateClaim `json:"items"`
+}
+
+type IndexTemplateClaimSpec struct {
+	// ElasticsearchRef is a reference to the Elasticsearch cluster in which the index template and the data stream
+	// are created. The Elasticsearch cluster must be managed by ECK.
+	ElasticsearchRef commonv1.ObjectSelector `json:"elasticsearchRef"`
+
+	// ServiceAccountName is used to check access from the current resource to an Elasticsearch resource in a different namespace.
+	// Can only be used if ECK is enforcing RBAC on references.
+	// +kubebuilder:validation:Optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// DataStream is the name of the data stream to create. It must be prefixed with the namespace of the
+	// IndexTemplateClaim followed by a dash, for example `my-namespace-logs`. Cannot be changed after creation.
+	DataStream string `json:"dataStream"`
+
+	// Priority of the index template created for the data stream. Defaults to 200.
+	// +kubebuilder:validation:Optional
+	Priority *int64 `json:"priority,omitempty"`
+
+	// Template holds the settings, mappings, aliases and lifecycle applied to the backing indices of the data stream.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Template *commonv1.Config `json:"template,omitempty"`
+}
+
+type IndexTemplateClaimStatus struct {
+	// Phase is the phase of the IndexTemplateClaim.
+	Phase ClaimPhase `json:"phase,omitempty"`
+	// Message gives details about the current phase.
+	Message string `json:"message,omitempty"`
+	// ObservedGeneration is the most recent generation observed for this IndexTemplateClaim.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions holds the Ready, Reconciling and Stalled conditions of the IndexTemplateClaim.
+	// +kubebuilder:validation:Optional
+	Conditions commonv1alpha1.Conditions `json:"conditions,omitempty"`
+}
+
+// ResourceState returns the state of the IndexTemplateClaim from which its conditions are computed. An invalid claim, or
+// a claim exceeding a quota, is stalled until it or the quota is updated.
+func (s IndexTemplateClaimStatus) ResourceState() commonv1alpha1.ResourceState {
+	stalled := s.Phase == InvalidPhase || s.Phase == QuotaExceededPhase
+	return commonv1alpha1.NewPhaseState(s.ObservedGeneration, string(s.Phase), s.Phase == ReadyPhase, stalled, s.Message)
+}
+
+type ClaimPhase string
+
+const (
+	// PendingPhase means the claim cannot be applied yet, for example because the Elasticsearch cluster is not ready.
+	PendingPhase ClaimPhase = "Pending"
+	// ReadyPhase means the index template and the data stream exist in Elasticsearch.
+	ReadyPhase ClaimPhase = "Ready"
+	// InvalidPhase means the claim does not pass validation or is not allowed to reference the Elasticsearch cluster.
+	InvalidPhase ClaimPhase = "Invalid"
+	// QuotaExceededPhase means the claim exceeds one of the quotas defined on the Elasticsearch cluster.
+	QuotaExceededPhase ClaimPhase = "QuotaExceeded"
+	// ErrorPhase means an error occurred while applying the claim to Elasticsearch.
+	ErrorPhase ClaimPhase = "Error"
+)
+
+// EffectivePriority returns the priority of the index template created for this claim.
+func (c *IndexTemplateClaim) EffectivePriority() int64 {
+	if c.Spec.Priority == nil {
+		return DefaultPriority
+	}
+	return *c.Spec.Priority
+}
+
+// ElasticsearchRef returns the reference to the Elasticsearch cluster with the default namespace applied.
+func (c *IndexTemplateClaim) ElasticsearchRef() commonv1.ObjectSelector {
+	return c.Spec.ElasticsearchRef.WithDefaultNamespace(c.Namespace)
+}
+
+// IsMarkedForDeletion returns true if the IndexTemplateClaim resource is going to be deleted.
+func (c *IndexTemplateClaim) IsMarkedForDeletion() bool {
+	return !c.DeletionTimestamp.IsZero()
+}
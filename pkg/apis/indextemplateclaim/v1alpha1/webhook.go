@@ -0,0 +1,195 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+const (
+	// webhookPath is the HTTP path for the IndexTemplateClaim validating webhook.
+	webhookPath = "/validate-itc-k8s-elastic-co-v1alpha1-indextemplateclaims"
+
+	// maxDataStreamNameLength is the maximum length of an index or data stream name in Elasticsearch.
+	maxDataStreamNameLength = 255
+	// invalidDataStreamNameChars are the characters not allowed in an index or data stream name in Elasticsearch.
+	invalidDataStreamNameChars = `\/*?"<>| ,#:`
+)
+
+var (
+	groupKind     = schema.GroupKind{Group: GroupVersion.Group, Kind: Kind}
+	validationLog = ulog.Log.WithName("itc-v1alpha1-validation")
+
+	// allowedTemplateKeys are the keys that can be set in the template of an IndexTemplateClaim. The index patterns
+	// and the data stream flag are managed by the operator.
+	allowedTemplateKeys = []string{"settings", "mappings", "aliases", "lifecycle"}
+
+	defaultChecks = []func(*IndexTemplateClaim) field.ErrorList{
+		checkNoUnknownFields,
+		checkNameLength,
+		checkElasticsearchRef,
+		checkDataStreamName,
+		checkTemplate,
+	}
+
+	updateChecks = []func(old, curr *IndexTemplateClaim) field.ErrorList{
+		checkImmutableFields,
+	}
+)
+
+// +kubebuilder:webhook:path=/validate-itc-k8s-elastic-co-v1alpha1-indextemplateclaims,mutating=false,failurePolicy=ignore,groups=indextemplateclaim.k8s.elastic.co,resources=indextemplateclaims,verbs=create;update,versions=v1alpha1,name=elastic-itc-validation-v1alpha1.k8s.elastic.co,sideEffects=None,admissionReviewVersions=v1;v1beta1,matchPolicy=Exact
+
+var _ webhook.Validator = &IndexTemplateClaim{}
+
+// ValidateCreate is called by the validating webhook to validate the create operation.
+// Satisfies the webhook.Validator interface.
+func (c *IndexTemplateClaim) ValidateCreate() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate create", "name", c.Name)
+	return nil, c.validate(nil)
+}
+
+// ValidateDelete is called by the validating webhook to validate the delete operation.
+// Satisfies the webhook.Validator interface.
+func (c *IndexTemplateClaim) ValidateDelete() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate delete", "name", c.Name)
+	return nil, nil
+}
+
+// ValidateUpdate is called by the validating webhook to validate the update operation.
+// Satisfies the webhook.Validator interface.
+func (c *IndexTemplateClaim) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate update", "name", c.Name)
+	oldObj, ok := old.(*IndexTemplateClaim)
+	if !ok {
+		return nil, errors.New("cannot cast old object to IndexTemplateClaim type")
+	}
+	return nil, c.validate(oldObj)
+}
+
+// WebhookPath returns the HTTP path used by the validating webhook.
+func (c *IndexTemplateClaim) WebhookPath() string {
+	return webhookPath
+}
+
+// Validate runs the validation checks of the IndexTemplateClaim, it is also used by the controller in case the
+// webhook is disabled.
+func (c *IndexTemplateClaim) Validate() error {
+	return c.validate(nil)
+}
+
+func (c *IndexTemplateClaim) validate(old *IndexTemplateClaim) error {
+	var errs field.ErrorList
+	if old != nil {
+		for _, uc := range updateChecks {
+			if err := uc(old, c); err != nil {
+				errs = append(errs, err...)
+			}
+		}
+	}
+
+	for _, dc := range defaultChecks {
+		if err := dc(c); err != nil {
+			errs = append(errs, err...)
+		}
+	}
+
+	if len(errs) > 0 {
+		validationLog.V(1).Info("failed validation", "errors", errs)
+		return apierrors.NewInvalid(groupKind, c.Name, errs)
+	}
+	return nil
+}
+
+func checkNoUnknownFields(c *IndexTemplateClaim) field.ErrorList {
+	return commonv1.NoUnknownFields(c, c.ObjectMeta)
+}
+
+func checkNameLength(c *IndexTemplateClaim) field.ErrorList {
+	return commonv1.CheckNameLength(c)
+}
+
+func checkElasticsearchRef(c *IndexTemplateClaim) field.ErrorList {
+	path := field.NewPath("spec").Child("elasticsearchRef")
+	if c.Spec.ElasticsearchRef.SecretName != "" {
+		return field.ErrorList{field.Forbidden(path.Child("secretName"), "IndexTemplateClaims can only reference Elasticsearch clusters managed by ECK")}
+	}
+	if c.Spec.ElasticsearchRef.Name == "" {
+		return field.ErrorList{field.Required(path.Child("name"), "elasticsearchRef name is mandatory")}
+	}
+	return commonv1.CheckAssociationRefs(path, c.Spec.ElasticsearchRef)
+}
+
+// DataStreamNamePrefix returns the prefix that data stream names must start with for claims in the given namespace.
+func DataStreamNamePrefix(namespace string) string {
+	return namespace + "-"
+}
+
+func checkDataStreamName(c *IndexTemplateClaim) field.ErrorList {
+	path := field.NewPath("spec").Child("dataStream")
+	name := c.Spec.DataStream
+	if name == "" {
+		return field.ErrorList{field.Required(path, "dataStream is mandatory")}
+	}
+	prefix := DataStreamNamePrefix(c.Namespace)
+	var errs field.ErrorList
+	if !strings.HasPrefix(name, prefix) || len(name) == len(prefix) {
+		errs = append(errs, field.Invalid(path, name, fmt.Sprintf("data stream name must start with %q followed by at least one character", prefix)))
+	}
+	if len(name) > maxDataStreamNameLength {
+		errs = append(errs, field.TooLong(path, name, maxDataStreamNameLength))
+	}
+	if strings.ToLower(name) != name {
+		errs = append(errs, field.Invalid(path, name, "data stream name must be lowercase"))
+	}
+	if strings.ContainsAny(name, invalidDataStreamNameChars) {
+		errs = append(errs, field.Invalid(path, name, fmt.Sprintf("data stream name must not contain any of %q", invalidDataStreamNameChars)))
+	}
+	return errs
+}
+
+func checkTemplate(c *IndexTemplateClaim) field.ErrorList {
+	if c.Spec.Template == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	for key := range c.Spec.Template.Data {
+		if !isAllowedTemplateKey(key) {
+			errs = append(errs, field.NotSupported(field.NewPath("spec").Child("template").Key(key), key, allowedTemplateKeys))
+		}
+	}
+	return errs
+}
+
+func isAllowedTemplateKey(key string) bool {
+	for _, allowed := range allowedTemplateKeys {
+		if key == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func checkImmutableFields(old, curr *IndexTemplateClaim) field.ErrorList {
+	var errs field.ErrorList
+	if old.Spec.DataStream != curr.Spec.DataStream {
+		errs = append(errs, field.Forbidden(field.NewPath("spec").Child("dataStream"), "dataStream cannot be changed"))
+	}
+	if old.ElasticsearchRef() != curr.ElasticsearchRef() {
+		errs = append(errs, field.Forbidden(field.NewPath("spec").Child("elasticsearchRef"), "elasticsearchRef cannot be changed"))
+	}
+	return errs
+}
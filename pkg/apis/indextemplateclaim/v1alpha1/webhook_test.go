@@ -0,0 +1,148 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	itcv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/indextemplateclaim/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/test"
+)
+
+func TestWebhook(t *testing.T) {
+	testCases := []test.ValidationWebhookTestCase{
+		{
+			Name:      "create-valid",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkIndexTemplateClaim(uid))
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "missing-namespace-prefix",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkIndexTemplateClaim(uid)
+				m.Spec.DataStream = "logs-app"
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.dataStream: Invalid value: "logs-app": data stream name must start with "team-a-" followed by at least one character`,
+			),
+		},
+		{
+			Name:      "invalid-data-stream-name",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkIndexTemplateClaim(uid)
+				m.Spec.DataStream = "team-a-Logs*"
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookFailed(
+				`data stream name must be lowercase`,
+				`data stream name must not contain any of`,
+			),
+		},
+		{
+			Name:      "external-elasticsearch",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkIndexTemplateClaim(uid)
+				m.Spec.ElasticsearchRef = commonv1.ObjectSelector{SecretName: "external-es"}
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.elasticsearchRef.secretName: Forbidden: IndexTemplateClaims can only reference Elasticsearch clusters managed by ECK`,
+			),
+		},
+		{
+			Name:      "unsupported-template-key",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkIndexTemplateClaim(uid)
+				m.Spec.Template.Data["index_patterns"] = []interface{}{"*"}
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.template\[index_patterns\]: Unsupported value: "index_patterns"`,
+			),
+		},
+		{
+			Name:      "update-data-stream",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkIndexTemplateClaim(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkIndexTemplateClaim(uid)
+				m.Spec.DataStream = "team-a-metrics"
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.dataStream: Forbidden: dataStream cannot be changed`,
+			),
+		},
+		{
+			Name:      "update-template",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				return serialize(t, mkIndexTemplateClaim(uid))
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkIndexTemplateClaim(uid)
+				m.Spec.Template.Data["mappings"] = map[string]interface{}{"dynamic": false}
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+	}
+
+	validator := &itcv1alpha1.IndexTemplateClaim{}
+	gvk := metav1.GroupVersionKind{Group: itcv1alpha1.GroupVersion.Group, Version: itcv1alpha1.GroupVersion.Version, Kind: itcv1alpha1.Kind}
+	test.RunValidationWebhookTests(t, gvk, validator, testCases...)
+}
+
+func mkIndexTemplateClaim(uid string) *itcv1alpha1.IndexTemplateClaim {
+	return &itcv1alpha1.IndexTemplateClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "claim-test",
+			Namespace: "team-a",
+			UID:       types.UID(uid),
+		},
+		Spec: itcv1alpha1.IndexTemplateClaimSpec{
+			ElasticsearchRef: commonv1.ObjectSelector{Name: "central", Namespace: "elastic"},
+			DataStream:       "team-a-logs",
+			Template: &commonv1.Config{Data: map[string]interface{}{
+				"settings": map[string]interface{}{"index.number_of_shards": 1},
+			}},
+		},
+	}
+}
+
+func serialize(t *testing.T, claim *itcv1alpha1.IndexTemplateClaim) []byte {
+	t.Helper()
+
+	objBytes, err := json.Marshal(claim)
+	require.NoError(t, err)
+
+	return objBytes
+}
@@ -31,8 +31,11 @@ const (
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="health",type="string",JSONPath=".status.health"
 // +kubebuilder:printcolumn:name="nodes",type="integer",JSONPath=".status.availableNodes",description="Available nodes"
+// +kubebuilder:printcolumn:name="expected",type="integer",JSONPath=".spec.count",description="Expected nodes"
 // +kubebuilder:printcolumn:name="version",type="string",JSONPath=".status.version",description="Kibana version"
 // +kubebuilder:printcolumn:name="age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="elasticsearch",type="string",JSONPath=".status.elasticsearchAssociationStatus",description="Elasticsearch association status",priority=1
+// +kubebuilder:printcolumn:name="reconciled",type="date",JSONPath=".status.conditions[?(@.type=='Reconciling')].lastTransitionTime",description="Time of the last transition of the Reconciling condition",priority=1
 // +kubebuilder:subresource:scale:specpath=.spec.count,statuspath=.status.count,selectorpath=.status.selector
 // +kubebuilder:storageversion
 type Kibana struct {
@@ -109,6 +112,70 @@ type KibanaSpec struct {
 	// Elasticsearch monitoring clusters running in the same Kubernetes cluster.
 	// +kubebuilder:validation:Optional
 	Monitoring commonv1.Monitoring `json:"monitoring,omitempty"`
+
+	// RemoteClusters declares additional Elasticsearch clusters to search from this Kibana through cross-cluster search.
+	// The operator configures them as remote clusters of the Elasticsearch cluster referenced in ElasticsearchRef.
+	// Remote clusters also declared in the spec of that Elasticsearch cluster take precedence.
+	// +kubebuilder:validation:Optional
+	RemoteClusters []RemoteCluster `json:"remoteClusters,omitempty"`
+
+	// Provisioning declares spaces, roles and saved objects imported into Kibana by the operator once Kibana is available.
+	// Requires an elasticsearchRef to an Elasticsearch cluster managed by ECK.
+	// +kubebuilder:validation:Optional
+	Provisioning *ProvisioningSpec `json:"provisioning,omitempty"`
+}
+
+// ProvisioningSpec references ConfigMaps and Secrets holding the content imported into Kibana.
+// Content is imported again whenever it changes. Content removed from the spec is not deleted from Kibana.
+type ProvisioningSpec struct {
+	// Spaces references ConfigMaps or Secrets where each entry is a Kibana space in the JSON format of the Kibana spaces API.
+	// +kubebuilder:validation:Optional
+	Spaces []ProvisioningSource `json:"spaces,omitempty"`
+
+	// Roles references ConfigMaps or Secrets where each entry is a Kibana role in the JSON format of the Kibana role API.
+	// The role is named after the entry key, without its .json extension. Kibana privileges of a role grant access to spaces.
+	// +kubebuilder:validation:Optional
+	Roles []ProvisioningSource `json:"roles,omitempty"`
+
+	// SavedObjects references ConfigMaps or Secrets where each entry is an NDJSON export of saved objects, such as
+	// dashboards and data views. Saved objects are imported after spaces and roles, and overwrite existing objects.
+	// +kubebuilder:validation:Optional
+	SavedObjects []SavedObjectsSource `json:"savedObjects,omitempty"`
+}
+
+// ProvisioningSource references a ConfigMap or a Secret in the namespace of the Kibana resource.
+type ProvisioningSource struct {
+	// ConfigMapName is the name of a ConfigMap. Mutually exclusive with SecretName.
+	ConfigMapName string `json:"configMapName,omitempty"`
+
+	// SecretName is the name of a Secret. Mutually exclusive with ConfigMapName.
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// SavedObjectsSource references saved objects to import into a Kibana space.
+type SavedObjectsSource struct {
+	ProvisioningSource `json:",inline"`
+
+	// Space is the identifier of the space the saved objects are imported into. Defaults to the default space.
+	// +kubebuilder:validation:Optional
+	Space string `json:"space,omitempty"`
+}
+
+// IsDefined returns true if the provisioning spec references content to import into Kibana.
+func (p *ProvisioningSpec) IsDefined() bool {
+	return p != nil && len(p.Spaces)+len(p.Roles)+len(p.SavedObjects) > 0
+}
+
+// RemoteCluster declares an Elasticsearch cluster searchable from Kibana through the Elasticsearch cluster Kibana is
+// associated with.
+type RemoteCluster struct {
+	// Name is the alias of the remote cluster. Data views target indices of the remote cluster with the <name>:<index> syntax.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// ElasticsearchRef is a reference to an Elasticsearch cluster running within the same k8s cluster.
+	ElasticsearchRef commonv1.LocalObjectSelector `json:"elasticsearchRef"`
 }
 
 // KibanaStatus defines the observed state of Kibana
@@ -128,6 +195,23 @@ type KibanaStatus struct {
 	// MonitoringAssociationStatus is the status of any auto-linking to monitoring Elasticsearch clusters.
 	MonitoringAssociationStatus commonv1.AssociationStatusMap `json:"monitoringAssociationStatus,omitempty"`
 
+	// RemoteClusters lists the aliases of the remote clusters declared in the spec. Data views can search them once
+	// connected, using index patterns such as <alias>:logs-*.
+	RemoteClusters []string `json:"remoteClusters,omitempty"`
+
+	// ProvisioningHash is the hash of the content declared in spec.provisioning last imported into Kibana.
+	ProvisioningHash string `json:"provisioningHash,omitempty"`
+
+	// Restart is the status of the restart of the Pods requested with the eck.k8s.elastic.co/restart annotation, if
+	// in progress.
+	// +optional
+	Restart *commonv1.RestartStatus `json:"restart,omitempty"`
+
+	// UpgradeGate is the status of the step of the version upgrade waiting for the approval of an external analysis tool,
+	// if the eck.k8s.elastic.co/upgrade-gates annotation is set.
+	// +optional
+	UpgradeGate *commonv1.UpgradeGateStatus `json:"upgradeGate,omitempty"`
+
 	// ObservedGeneration is the most recent generation observed for this Kibana instance.
 	// It corresponds to the metadata generation, which is updated on mutation by the API Server.
 	// If the generation observed in status diverges from the generation in metadata, the Kibana
@@ -144,6 +228,26 @@ func (k *Kibana) SecureSettings() []commonv1.SecretSource {
 	return k.Spec.SecureSettings
 }
 
+// GetRestartStatus returns the status of the restart of the Pods in progress, nil if there is none.
+func (k *Kibana) GetRestartStatus() *commonv1.RestartStatus {
+	return k.Status.Restart
+}
+
+// SetRestartStatus sets the status of the restart of the Pods in progress.
+func (k *Kibana) SetRestartStatus(status *commonv1.RestartStatus) {
+	k.Status.Restart = status
+}
+
+// GetUpgradeGateStatus returns the status of the step of the version upgrade waiting for approval, nil if there is none.
+func (k *Kibana) GetUpgradeGateStatus() *commonv1.UpgradeGateStatus {
+	return k.Status.UpgradeGate
+}
+
+// SetUpgradeGateStatus sets the status of the step of the version upgrade waiting for approval.
+func (k *Kibana) SetUpgradeGateStatus(status *commonv1.UpgradeGateStatus) {
+	k.Status.UpgradeGate = status
+}
+
 func (k *Kibana) ServiceAccountName() string {
 	return k.Spec.ServiceAccountName
 }
@@ -431,6 +535,10 @@ func (k *Kibana) GetMonitoringMetricsRefs() []commonv1.ObjectSelector {
 	return k.Spec.Monitoring.Metrics.ElasticsearchRefs
 }
 
+func (k *Kibana) GetMonitoringMetricsDestinations() []commonv1.MetricsDestination {
+	return k.Spec.Monitoring.Metrics.Destinations
+}
+
 func (k *Kibana) GetMonitoringLogsRefs() []commonv1.ObjectSelector {
 	return k.Spec.Monitoring.Logs.ElasticsearchRefs
 }
@@ -24,6 +24,12 @@ import (
 const (
 	// webhookPath is the HTTP path for the Kibana validating webhook.
 	webhookPath = "/validate-kibana-k8s-elastic-co-v1-kibana"
+
+	remoteClustersWithoutElasticsearchRefMsg = "remote clusters require an elasticsearchRef to a cluster managed by ECK"
+	duplicateRemoteClusterMsg                = "remote cluster names must be unique"
+	provisioningWithoutElasticsearchRefMsg   = "provisioning requires an elasticsearchRef to a cluster managed by ECK"
+	invalidProvisioningSourceMsg             = "exactly one of configMapName or secretName must be specified"
+	overriddenElasticsearchSettingMsg        = "setting overrides the connection settings managed by the operator for the elasticsearchRef"
 )
 
 var (
@@ -36,11 +42,26 @@ var (
 		checkSupportedVersion,
 		checkMonitoring,
 		checkAssociations,
+		checkRemoteClusters,
+		checkProvisioning,
 	}
 
 	updateChecks = []func(old, curr *Kibana) field.ErrorList{
 		checkNoDowngrade,
 	}
+
+	warningChecks = []func(*Kibana) field.ErrorList{
+		checkOverriddenElasticsearchSettings,
+	}
+
+	// elasticsearchConnectionSettings are the settings set by the operator when Kibana is associated to Elasticsearch.
+	elasticsearchConnectionSettings = []string{
+		"elasticsearch.hosts",
+		"elasticsearch.username",
+		"elasticsearch.password",
+		"elasticsearch.serviceAccountToken",
+		"elasticsearch.ssl.certificateAuthorities",
+	}
 )
 
 // +kubebuilder:webhook:path=/validate-kibana-k8s-elastic-co-v1-kibana,mutating=false,failurePolicy=ignore,groups=kibana.k8s.elastic.co,resources=kibanas,verbs=create;update,versions=v1,name=elastic-kb-validation-v1.k8s.elastic.co,sideEffects=None,admissionReviewVersions=v1;v1beta1,matchPolicy=Exact
@@ -78,6 +99,18 @@ func (k *Kibana) WebhookPath() string {
 	return webhookPath
 }
 
+// GetWarnings returns the admission warnings raised by the Kibana specification.
+func (k *Kibana) GetWarnings() []string {
+	if k == nil {
+		return nil
+	}
+	var warnings field.ErrorList
+	for _, wc := range warningChecks {
+		warnings = append(warnings, wc(k)...)
+	}
+	return commonv1.ToWarnings(Kind, k, warnings)
+}
+
 func (k *Kibana) validate(old *Kibana) (admission.Warnings, error) {
 	var errors field.ErrorList
 	if old != nil {
@@ -141,3 +174,55 @@ func checkAssociations(k *Kibana) field.ErrorList {
 	err4 := commonv1.CheckAssociationRefs(field.NewPath("spec").Child("enterpriseSearchRef"), k.Spec.EnterpriseSearchRef)
 	return append(err1, append(err2, append(err3, err4...)...)...)
 }
+
+func checkRemoteClusters(k *Kibana) field.ErrorList {
+	if len(k.Spec.RemoteClusters) == 0 {
+		return nil
+	}
+	path := field.NewPath("spec").Child("remoteClusters")
+	var errs field.ErrorList
+	if !k.Spec.ElasticsearchRef.IsDefined() || k.Spec.ElasticsearchRef.IsExternal() {
+		errs = append(errs, field.Invalid(path, len(k.Spec.RemoteClusters), remoteClustersWithoutElasticsearchRefMsg))
+	}
+	names := make(map[string]struct{}, len(k.Spec.RemoteClusters))
+	for i, remoteCluster := range k.Spec.RemoteClusters {
+		if _, exists := names[remoteCluster.Name]; exists {
+			errs = append(errs, field.Invalid(path.Index(i).Child("name"), remoteCluster.Name, duplicateRemoteClusterMsg))
+		}
+		names[remoteCluster.Name] = struct{}{}
+	}
+	return errs
+}
+
+func checkProvisioning(k *Kibana) field.ErrorList {
+	if !k.Spec.Provisioning.IsDefined() {
+		return nil
+	}
+	path := field.NewPath("spec").Child("provisioning")
+	var errs field.ErrorList
+	if !k.Spec.ElasticsearchRef.IsDefined() || k.Spec.ElasticsearchRef.IsExternal() {
+		errs = append(errs, field.Forbidden(path, provisioningWithoutElasticsearchRefMsg))
+	}
+	checkSource := func(path *field.Path, source ProvisioningSource) {
+		if (source.ConfigMapName == "") == (source.SecretName == "") {
+			errs = append(errs, field.Invalid(path, source, invalidProvisioningSourceMsg))
+		}
+	}
+	for i, source := range k.Spec.Provisioning.Spaces {
+		checkSource(path.Child("spaces").Index(i), source)
+	}
+	for i, source := range k.Spec.Provisioning.Roles {
+		checkSource(path.Child("roles").Index(i), source)
+	}
+	for i, source := range k.Spec.Provisioning.SavedObjects {
+		checkSource(path.Child("savedObjects").Index(i), source.ProvisioningSource)
+	}
+	return errs
+}
+
+func checkOverriddenElasticsearchSettings(k *Kibana) field.ErrorList {
+	if !k.Spec.ElasticsearchRef.IsDefined() {
+		return nil
+	}
+	return commonv1.CheckConfigSettings(field.NewPath("spec").Child("config"), k.Spec.Config, elasticsearchConnectionSettings, overriddenElasticsearchSettingMsg)
+}
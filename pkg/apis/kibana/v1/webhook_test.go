@@ -318,6 +318,97 @@ func TestWebhook(t *testing.T) {
 				`spec.monitoring.logs: Forbidden: Invalid association reference: serviceName or namespace can only be used in combination with name, not with secretName`,
 			),
 		},
+		{
+			Name:      "valid-remote-clusters",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				k := mkKibana(uid)
+				k.Spec.ElasticsearchRef = commonv1.ObjectSelector{Name: "central"}
+				k.Spec.RemoteClusters = []kbv1.RemoteCluster{
+					{Name: "spoke-1", ElasticsearchRef: commonv1.LocalObjectSelector{Name: "es1"}},
+					{Name: "spoke-2", ElasticsearchRef: commonv1.LocalObjectSelector{Name: "es2", Namespace: "ns2"}},
+				}
+				return serialize(t, k)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "remote-clusters-without-elasticsearch-ref",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				k := mkKibana(uid)
+				k.Spec.ElasticsearchRef = commonv1.ObjectSelector{SecretName: "external-es"}
+				k.Spec.RemoteClusters = []kbv1.RemoteCluster{{Name: "spoke-1", ElasticsearchRef: commonv1.LocalObjectSelector{Name: "es1"}}}
+				return serialize(t, k)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.remoteClusters: Invalid value: 1: remote clusters require an elasticsearchRef to a cluster managed by ECK`,
+			),
+		},
+		{
+			Name:      "duplicate-remote-clusters",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				k := mkKibana(uid)
+				k.Spec.ElasticsearchRef = commonv1.ObjectSelector{Name: "central"}
+				k.Spec.RemoteClusters = []kbv1.RemoteCluster{
+					{Name: "spoke", ElasticsearchRef: commonv1.LocalObjectSelector{Name: "es1"}},
+					{Name: "spoke", ElasticsearchRef: commonv1.LocalObjectSelector{Name: "es2"}},
+				}
+				return serialize(t, k)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.remoteClusters\[1\].name: Invalid value: "spoke": remote cluster names must be unique`,
+			),
+		},
+		{
+			Name:      "provisioning",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				k := mkKibana(uid)
+				k.Spec.ElasticsearchRef = commonv1.ObjectSelector{Name: "es"}
+				k.Spec.Provisioning = &kbv1.ProvisioningSpec{
+					Spaces:       []kbv1.ProvisioningSource{{ConfigMapName: "spaces"}},
+					Roles:        []kbv1.ProvisioningSource{{SecretName: "roles"}},
+					SavedObjects: []kbv1.SavedObjectsSource{{ProvisioningSource: kbv1.ProvisioningSource{ConfigMapName: "dashboards"}, Space: "team-a"}},
+				}
+				return serialize(t, k)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "provisioning-without-elasticsearch-ref",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				k := mkKibana(uid)
+				k.Spec.Provisioning = &kbv1.ProvisioningSpec{Spaces: []kbv1.ProvisioningSource{{ConfigMapName: "spaces"}}}
+				return serialize(t, k)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.provisioning: Forbidden: provisioning requires an elasticsearchRef to a cluster managed by ECK`,
+			),
+		},
+		{
+			Name:      "provisioning-invalid-source",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				k := mkKibana(uid)
+				k.Spec.ElasticsearchRef = commonv1.ObjectSelector{Name: "es"}
+				k.Spec.Provisioning = &kbv1.ProvisioningSpec{
+					SavedObjects: []kbv1.SavedObjectsSource{{ProvisioningSource: kbv1.ProvisioningSource{ConfigMapName: "dashboards", SecretName: "dashboards"}}},
+				}
+				return serialize(t, k)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.provisioning.savedObjects\[0\]: Invalid value: .*: exactly one of configMapName or secretName must be specified`,
+			),
+		},
 	}
 
 	validator := &kbv1.Kibana{}
@@ -345,3 +436,47 @@ func serialize(t *testing.T, k *kbv1.Kibana) []byte {
 
 	return objBytes
 }
+
+func TestKibana_GetWarnings(t *testing.T) {
+	withConfig := func(esRef commonv1.ObjectSelector, cfg map[string]interface{}) *kbv1.Kibana {
+		k := mkKibana("uid")
+		k.Namespace = "ns"
+		k.Spec.ElasticsearchRef = esRef
+		k.Spec.Config = &commonv1.Config{Data: cfg}
+		return k
+	}
+	esRef := commonv1.ObjectSelector{Name: "es"}
+	tests := []struct {
+		name string
+		kb   *kbv1.Kibana
+		want []string
+	}{
+		{
+			name: "no config",
+			kb:   mkKibana("uid"),
+		},
+		{
+			name: "connection settings without elasticsearchRef",
+			kb:   withConfig(commonv1.ObjectSelector{}, map[string]interface{}{"elasticsearch.hosts": []string{"https://es:9200"}}),
+		},
+		{
+			name: "unrelated settings with elasticsearchRef",
+			kb:   withConfig(esRef, map[string]interface{}{"server.name": "kibana"}),
+		},
+		{
+			name: "connection settings with elasticsearchRef",
+			kb: withConfig(esRef, map[string]interface{}{
+				"elasticsearch": map[string]interface{}{"hosts": []string{"https://es:9200"}, "username": "elastic"},
+			}),
+			want: []string{
+				"Kibana ns/webhook-test: spec.config.elasticsearch.hosts: setting overrides the connection settings managed by the operator for the elasticsearchRef",
+				"Kibana ns/webhook-test: spec.config.elasticsearch.username: setting overrides the connection settings managed by the operator for the elasticsearchRef",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.kb.GetWarnings())
+		})
+	}
+}
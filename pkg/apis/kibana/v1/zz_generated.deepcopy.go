@@ -174,6 +174,16 @@ func (in *KibanaSpec) DeepCopyInto(out *KibanaSpec) {
 		}
 	}
 	in.Monitoring.DeepCopyInto(&out.Monitoring)
+	if in.RemoteClusters != nil {
+		in, out := &in.RemoteClusters, &out.RemoteClusters
+		*out = make([]RemoteCluster, len(*in))
+		copy(*out, *in)
+	}
+	if in.Provisioning != nil {
+		in, out := &in.Provisioning, &out.Provisioning
+		*out = new(ProvisioningSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KibanaSpec.
@@ -189,7 +199,7 @@ func (in *KibanaSpec) DeepCopy() *KibanaSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KibanaStatus) DeepCopyInto(out *KibanaStatus) {
 	*out = *in
-	out.DeploymentStatus = in.DeploymentStatus
+	in.DeploymentStatus.DeepCopyInto(&out.DeploymentStatus)
 	if in.MonitoringAssociationStatus != nil {
 		in, out := &in.MonitoringAssociationStatus, &out.MonitoringAssociationStatus
 		*out = make(commonv1.AssociationStatusMap, len(*in))
@@ -197,6 +207,21 @@ func (in *KibanaStatus) DeepCopyInto(out *KibanaStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.RemoteClusters != nil {
+		in, out := &in.RemoteClusters, &out.RemoteClusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Restart != nil {
+		in, out := &in.Restart, &out.Restart
+		*out = new(commonv1.RestartStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpgradeGate != nil {
+		in, out := &in.UpgradeGate, &out.UpgradeGate
+		*out = new(commonv1.UpgradeGateStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KibanaStatus.
@@ -208,3 +233,80 @@ func (in *KibanaStatus) DeepCopy() *KibanaStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProvisioningSource) DeepCopyInto(out *ProvisioningSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvisioningSource.
+func (in *ProvisioningSource) DeepCopy() *ProvisioningSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ProvisioningSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProvisioningSpec) DeepCopyInto(out *ProvisioningSpec) {
+	*out = *in
+	if in.Spaces != nil {
+		in, out := &in.Spaces, &out.Spaces
+		*out = make([]ProvisioningSource, len(*in))
+		copy(*out, *in)
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]ProvisioningSource, len(*in))
+		copy(*out, *in)
+	}
+	if in.SavedObjects != nil {
+		in, out := &in.SavedObjects, &out.SavedObjects
+		*out = make([]SavedObjectsSource, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvisioningSpec.
+func (in *ProvisioningSpec) DeepCopy() *ProvisioningSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProvisioningSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteCluster) DeepCopyInto(out *RemoteCluster) {
+	*out = *in
+	out.ElasticsearchRef = in.ElasticsearchRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteCluster.
+func (in *RemoteCluster) DeepCopy() *RemoteCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SavedObjectsSource) DeepCopyInto(out *SavedObjectsSource) {
+	*out = *in
+	out.ProvisioningSource = in.ProvisioningSource
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SavedObjectsSource.
+func (in *SavedObjectsSource) DeepCopy() *SavedObjectsSource {
+	if in == nil {
+		return nil
+	}
+	out := new(SavedObjectsSource)
+	in.DeepCopyInto(out)
+	return out
+}
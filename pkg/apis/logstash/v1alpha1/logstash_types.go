@@ -12,6 +12,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
 )
 
@@ -44,6 +45,11 @@ type LogstashSpec struct {
 
 	Count int32 `json:"count,omitempty"`
 
+	// Autoscaling enables the horizontal autoscaling of the Logstash Pods, based on the backpressure of the pipelines
+	// reported by the Logstash monitoring API. When enabled, the operator adjusts Count within the given bounds.
+	// +kubebuilder:validation:Optional
+	Autoscaling *LogstashAutoscalingSpec `json:"autoscaling,omitempty"`
+
 	// Image is the Logstash Docker image to deploy. Version and Type have to match the Logstash in the image.
 	// +kubebuilder:validation:Optional
 	Image string `json:"image,omitempty"`
@@ -74,6 +80,11 @@ type LogstashSpec struct {
 	// +kubebuilder:validation:Optional
 	PipelinesRef *commonv1.ConfigSource `json:"pipelinesRef,omitempty"`
 
+	// Plugins lists the Logstash plugins installed by the operator in every Logstash Pod before it starts.
+	// Changes to the list of plugins trigger a rolling restart of the Pods.
+	// +kubebuilder:validation:Optional
+	Plugins *LogstashPluginsSpec `json:"plugins,omitempty"`
+
 	// Services contains details of services that Logstash should expose - similar to the HTTP layer configuration for the
 	// rest of the stack, but also applicable for more use cases than the metrics API, as logstash may need to
 	// be opened up for other services: Beats, TCP, UDP, etc, inputs.
@@ -83,8 +94,9 @@ type LogstashSpec struct {
 	// Monitoring enables you to collect and ship log and monitoring data of this Logstash.
 	// Metricbeat and Filebeat are deployed in the same Pod as sidecars and each one sends data to one or two different
 	// Elasticsearch monitoring clusters running in the same Kubernetes cluster.
+	// Alternatively, the Logstash metrics can be exposed in the Prometheus format by an exporter sidecar.
 	// +kubebuilder:validation:Optional
-	Monitoring commonv1.Monitoring `json:"monitoring,omitempty"`
+	Monitoring LogstashMonitoring `json:"monitoring,omitempty"`
 
 	// PodTemplate provides customisation options for the Logstash pods.
 	// +kubebuilder:pruning:PreserveUnknownFields
@@ -115,6 +127,46 @@ type LogstashSpec struct {
 	VolumeClaimTemplates []corev1.PersistentVolumeClaim `json:"volumeClaimTemplates,omitempty"`
 }
 
+// LogstashMonitoring holds the Stack Monitoring and the Prometheus monitoring configuration of Logstash.
+type LogstashMonitoring struct {
+	commonv1.Monitoring `json:",inline"`
+
+	// Prometheus deploys an exporter in a sidecar container to expose the Logstash metrics in the Prometheus format.
+	// +kubebuilder:validation:Optional
+	Prometheus *PrometheusMonitoring `json:"prometheus,omitempty"`
+}
+
+// PrometheusMonitoring configures the exporter which exposes the Logstash metrics in the Prometheus format.
+type PrometheusMonitoring struct {
+	// Image is the Logstash Prometheus exporter container image. The exporter reads the Logstash API on localhost.
+	// Defaults to kuskoman/logstash-exporter.
+	// +kubebuilder:validation:Optional
+	Image string `json:"image,omitempty"`
+
+	// Port on which the exporter exposes the metrics. Defaults to 9198.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port,omitempty"`
+
+	// ServiceMonitor creates a Prometheus operator ServiceMonitor selecting the metrics Service of this Logstash.
+	// Requires the Prometheus operator CRDs to be installed.
+	// +kubebuilder:validation:Optional
+	ServiceMonitor *ServiceMonitor `json:"serviceMonitor,omitempty"`
+}
+
+// ServiceMonitor configures the Prometheus operator ServiceMonitor reconciled for Logstash.
+type ServiceMonitor struct {
+	// Labels added to the ServiceMonitor, usually to match the serviceMonitorSelector of a Prometheus resource.
+	// +kubebuilder:validation:Optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Interval at which the metrics are scraped, for example 30s. Defaults to the Prometheus scrape interval.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^(0|(([0-9]+)y)?(([0-9]+)w)?(([0-9]+)d)?(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?)$`
+	Interval string `json:"interval,omitempty"`
+}
+
 type LogstashService struct {
 	Name string `json:"name,omitempty"`
 	// Service defines the template for the associated Kubernetes Service object.
@@ -123,6 +175,75 @@ type LogstashService struct {
 	TLS commonv1.TLSOptions `json:"tls,omitempty"`
 }
 
+// LogstashAutoscalingSpec holds the bounds and the targets used to automatically scale the Logstash Pods.
+// At least one of [`TargetQueueEvents`, `TargetEventLatency`] must be specified.
+type LogstashAutoscalingSpec struct {
+	// MinReplicas is the lower limit for the number of Logstash Pods.
+	// +kubebuilder:validation:Minimum=1
+	MinReplicas int32 `json:"minReplicas"`
+
+	// MaxReplicas is the upper limit for the number of Logstash Pods. It cannot be lower than MinReplicas.
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// TargetQueueEvents is the target average number of events waiting in the queues of the pipelines of a Pod.
+	// Only persisted queues report the number of events they hold.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	TargetQueueEvents *int64 `json:"targetQueueEvents,omitempty"`
+
+	// TargetEventLatency is the target average time spent by an event in the filters and outputs of the pipelines.
+	// +kubebuilder:validation:Optional
+	TargetEventLatency *metav1.Duration `json:"targetEventLatency,omitempty"`
+
+	// ScaleDownStabilizationWindow is the minimum duration since the last scaling operation before the number of
+	// Pods can be decreased. Defaults to 5m.
+	// +kubebuilder:validation:Optional
+	ScaleDownStabilizationWindow *metav1.Duration `json:"scaleDownStabilizationWindow,omitempty"`
+}
+
+// LogstashPluginsSpec holds the Logstash plugins to install and where to install them from.
+type LogstashPluginsSpec struct {
+	// Install is the list of plugins to install.
+	// +kubebuilder:validation:Optional
+	Install []LogstashPlugin `json:"install,omitempty"`
+
+	// OfflinePack is the path to an offline plugin pack, prepared with `bin/logstash-plugin prepare-offline-pack`, to
+	// install the plugins from in air-gapped environments, for example `file:///mnt/plugins/logstash-offline-plugins-8.15.0.zip`.
+	// All the plugins of the pack are installed. The pack must be made available in the Pods with a volume of the PodTemplate.
+	// +kubebuilder:validation:Optional
+	OfflinePack string `json:"offlinePack,omitempty"`
+}
+
+// LogstashPlugin is a Logstash plugin to install.
+type LogstashPlugin struct {
+	// Name of the plugin, for example `logstash-output-kafka`.
+	Name string `json:"name"`
+
+	// Version of the plugin to install. Defaults to the latest version compatible with the Logstash version.
+	// +kubebuilder:validation:Optional
+	Version string `json:"version,omitempty"`
+
+	// Path of a `.gem` file to install the plugin from, instead of the RubyGems repository, for example
+	// `/mnt/plugins/logstash-filter-custom-1.0.0.gem`. It can be used to install custom plugins. The file must be made
+	// available in the Pods with a volume of the PodTemplate. At most one of [`Version`, `Path`] can be specified.
+	// +kubebuilder:validation:Optional
+	Path string `json:"path,omitempty"`
+}
+
+// PluginsToInstall returns the list of plugins to install, or nil if there are none.
+func (ls LogstashSpec) PluginsToInstall() []LogstashPlugin {
+	if ls.Plugins == nil {
+		return nil
+	}
+	return ls.Plugins.Install
+}
+
+// HasPlugins returns true if plugins or an offline plugin pack must be installed.
+func (ls LogstashSpec) HasPlugins() bool {
+	return ls.Plugins != nil && (len(ls.Plugins.Install) > 0 || ls.Plugins.OfflinePack != "")
+}
+
 // ElasticsearchCluster is a named reference to an Elasticsearch cluster which can be used in a Logstash pipeline.
 type ElasticsearchCluster struct {
 	commonv1.ObjectSelector `json:",omitempty,inline"`
@@ -159,9 +280,40 @@ type LogstashStatus struct {
 	// MonitoringAssociationStatus is the status of any auto-linking to monitoring Elasticsearch clusters.
 	MonitoringAssociationStatus commonv1.AssociationStatusMap `json:"monitoringAssociationStatus,omitempty"`
 
+	// Autoscaling is the status of the horizontal autoscaling of the Logstash Pods, if enabled.
+	// +kubebuilder:validation:Optional
+	Autoscaling *LogstashAutoscalingStatus `json:"autoscaling,omitempty"`
+
+	// Restart is the status of the restart of the Pods requested with the eck.k8s.elastic.co/restart annotation, if
+	// in progress.
+	// +kubebuilder:validation:Optional
+	Restart *commonv1.RestartStatus `json:"restart,omitempty"`
+
+	// Conditions holds the current service state of the resource.
+	// +kubebuilder:validation:Optional
+	Conditions commonv1alpha1.Conditions `json:"conditions,omitempty"`
+
 	Selector string `json:"selector"`
 }
 
+// LogstashAutoscalingStatus is the status of the horizontal autoscaling of the Logstash Pods.
+type LogstashAutoscalingStatus struct {
+	// DesiredReplicas is the number of Pods recommended by the autoscaler.
+	DesiredReplicas int32 `json:"desiredReplicas"`
+
+	// QueueEvents is the last observed average number of events waiting in the queues of the pipelines of a Pod.
+	// +kubebuilder:validation:Optional
+	QueueEvents *int64 `json:"queueEvents,omitempty"`
+
+	// EventLatency is the last observed average time spent by an event in the filters and outputs of the pipelines.
+	// +kubebuilder:validation:Optional
+	EventLatency *metav1.Duration `json:"eventLatency,omitempty"`
+
+	// LastScaleTime is the last time the number of Pods was changed by the autoscaler.
+	// +kubebuilder:validation:Optional
+	LastScaleTime *metav1.Time `json:"lastScaleTime,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 
 // Logstash is the Schema for the logstashes API
@@ -173,6 +325,7 @@ type LogstashStatus struct {
 // +kubebuilder:printcolumn:name="expected",type="integer",JSONPath=".status.expectedNodes",description="Expected nodes"
 // +kubebuilder:printcolumn:name="age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="version",type="string",JSONPath=".status.version",description="Logstash version"
+// +kubebuilder:printcolumn:name="reconciled",type="date",JSONPath=".status.conditions[?(@.type=='Reconciling')].lastTransitionTime",description="Time of the last transition of the Reconciling condition",priority=1
 // +kubebuilder:subresource:scale:specpath=.spec.count,statuspath=.status.expectedNodes,selectorpath=.status.selector
 // +kubebuilder:storageversion
 type Logstash struct {
@@ -209,6 +362,16 @@ func (l *Logstash) SecureSettings() []commonv1.SecretSource {
 	return l.Spec.SecureSettings
 }
 
+// GetRestartStatus returns the status of the restart of the Pods in progress, nil if there is none.
+func (l *Logstash) GetRestartStatus() *commonv1.RestartStatus {
+	return l.Status.Restart
+}
+
+// SetRestartStatus sets the status of the restart of the Pods in progress.
+func (l *Logstash) SetRestartStatus(status *commonv1.RestartStatus) {
+	l.Status.Restart = status
+}
+
 // IsMarkedForDeletion returns true if the Logstash is going to be deleted
 func (l *Logstash) IsMarkedForDeletion() bool {
 	return !l.DeletionTimestamp.IsZero()
@@ -339,7 +502,7 @@ func (lses *LogstashESAssociation) SetAssociationConf(conf *commonv1.Association
 }
 
 func (lses *LogstashESAssociation) SupportsAuthAPIKey() bool {
-	return false
+	return true
 }
 
 func (lses *LogstashESAssociation) AssociationID() string {
@@ -407,6 +570,10 @@ func (l *Logstash) GetMonitoringMetricsRefs() []commonv1.ObjectSelector {
 	return l.Spec.Monitoring.Metrics.ElasticsearchRefs
 }
 
+func (l *Logstash) GetMonitoringMetricsDestinations() []commonv1.MetricsDestination {
+	return l.Spec.Monitoring.Metrics.Destinations
+}
+
 func (l *Logstash) GetMonitoringLogsRefs() []commonv1.ObjectSelector {
 	return l.Spec.Monitoring.Logs.ElasticsearchRefs
 }
@@ -9,9 +9,11 @@ import (
 )
 
 const (
-	apiServiceSuffix = "api"
-	configSuffix     = "config"
-	pipelineSuffix   = "pipeline"
+	apiServiceSuffix         = "api"
+	configSuffix             = "config"
+	pipelineSuffix           = "pipeline"
+	prometheusSuffix         = "prometheus"
+	prometheusExporterSuffix = "prometheus-exporter"
 )
 
 // Namer is a Namer that is configured with the defaults for resources related to a Logstash resource.
@@ -39,3 +41,14 @@ func UserServiceName(deployName string, name string) string {
 func PipelineSecretName(name string) string {
 	return Namer.Suffix(name, pipelineSuffix)
 }
+
+// PrometheusServiceName returns the name of the Service, and of the ServiceMonitor, exposing the Prometheus metrics of
+// a given Logstash name.
+func PrometheusServiceName(name string) string {
+	return Namer.Suffix(name, prometheusSuffix)
+}
+
+// PrometheusExporterSecretName returns the name of the Secret holding the Logstash API URL read by the Prometheus exporter.
+func PrometheusExporterSecretName(name string) string {
+	return Namer.Suffix(name, prometheusExporterSuffix)
+}
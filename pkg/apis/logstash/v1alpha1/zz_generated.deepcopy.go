@@ -10,7 +10,9 @@ package v1alpha1
 
 import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -71,6 +73,65 @@ func (in *Logstash) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogstashAutoscalingSpec) DeepCopyInto(out *LogstashAutoscalingSpec) {
+	*out = *in
+	if in.TargetQueueEvents != nil {
+		in, out := &in.TargetQueueEvents, &out.TargetQueueEvents
+		*out = new(int64)
+		**out = **in
+	}
+	if in.TargetEventLatency != nil {
+		in, out := &in.TargetEventLatency, &out.TargetEventLatency
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ScaleDownStabilizationWindow != nil {
+		in, out := &in.ScaleDownStabilizationWindow, &out.ScaleDownStabilizationWindow
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogstashAutoscalingSpec.
+func (in *LogstashAutoscalingSpec) DeepCopy() *LogstashAutoscalingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LogstashAutoscalingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogstashAutoscalingStatus) DeepCopyInto(out *LogstashAutoscalingStatus) {
+	*out = *in
+	if in.QueueEvents != nil {
+		in, out := &in.QueueEvents, &out.QueueEvents
+		*out = new(int64)
+		**out = **in
+	}
+	if in.EventLatency != nil {
+		in, out := &in.EventLatency, &out.EventLatency
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.LastScaleTime != nil {
+		in, out := &in.LastScaleTime, &out.LastScaleTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogstashAutoscalingStatus.
+func (in *LogstashAutoscalingStatus) DeepCopy() *LogstashAutoscalingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LogstashAutoscalingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LogstashESAssociation) DeepCopyInto(out *LogstashESAssociation) {
 	*out = *in
@@ -124,6 +185,27 @@ func (in *LogstashList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogstashMonitoring) DeepCopyInto(out *LogstashMonitoring) {
+	*out = *in
+	in.Monitoring.DeepCopyInto(&out.Monitoring)
+	if in.Prometheus != nil {
+		in, out := &in.Prometheus, &out.Prometheus
+		*out = new(PrometheusMonitoring)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogstashMonitoring.
+func (in *LogstashMonitoring) DeepCopy() *LogstashMonitoring {
+	if in == nil {
+		return nil
+	}
+	out := new(LogstashMonitoring)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LogstashMonitoringAssociation) DeepCopyInto(out *LogstashMonitoringAssociation) {
 	*out = *in
@@ -145,6 +227,41 @@ func (in *LogstashMonitoringAssociation) DeepCopy() *LogstashMonitoringAssociati
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogstashPlugin) DeepCopyInto(out *LogstashPlugin) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogstashPlugin.
+func (in *LogstashPlugin) DeepCopy() *LogstashPlugin {
+	if in == nil {
+		return nil
+	}
+	out := new(LogstashPlugin)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogstashPluginsSpec) DeepCopyInto(out *LogstashPluginsSpec) {
+	*out = *in
+	if in.Install != nil {
+		in, out := &in.Install, &out.Install
+		*out = make([]LogstashPlugin, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogstashPluginsSpec.
+func (in *LogstashPluginsSpec) DeepCopy() *LogstashPluginsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LogstashPluginsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LogstashService) DeepCopyInto(out *LogstashService) {
 	*out = *in
@@ -165,6 +282,11 @@ func (in *LogstashService) DeepCopy() *LogstashService {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LogstashSpec) DeepCopyInto(out *LogstashSpec) {
 	*out = *in
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(LogstashAutoscalingSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ElasticsearchRefs != nil {
 		in, out := &in.ElasticsearchRefs, &out.ElasticsearchRefs
 		*out = make([]ElasticsearchCluster, len(*in))
@@ -191,6 +313,11 @@ func (in *LogstashSpec) DeepCopyInto(out *LogstashSpec) {
 		*out = new(v1.ConfigSource)
 		**out = **in
 	}
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = new(LogstashPluginsSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Services != nil {
 		in, out := &in.Services, &out.Services
 		*out = make([]LogstashService, len(*in))
@@ -249,6 +376,23 @@ func (in *LogstashStatus) DeepCopyInto(out *LogstashStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(LogstashAutoscalingStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Restart != nil {
+		in, out := &in.Restart, &out.Restart
+		*out = new(v1.RestartStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(commonv1alpha1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogstashStatus.
@@ -260,3 +404,45 @@ func (in *LogstashStatus) DeepCopy() *LogstashStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusMonitoring) DeepCopyInto(out *PrometheusMonitoring) {
+	*out = *in
+	if in.ServiceMonitor != nil {
+		in, out := &in.ServiceMonitor, &out.ServiceMonitor
+		*out = new(ServiceMonitor)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusMonitoring.
+func (in *PrometheusMonitoring) DeepCopy() *PrometheusMonitoring {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusMonitoring)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceMonitor) DeepCopyInto(out *ServiceMonitor) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceMonitor.
+func (in *ServiceMonitor) DeepCopy() *ServiceMonitor {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceMonitor)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,284 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+const (
+	CollectorContainerName = "otel-collector"
+	// Kind is inferred from the struct name using reflection in SchemeBuilder.Register()
+	// we duplicate it as a constant here for practical purposes.
+	Kind = "OpenTelemetryCollector"
+)
+
+// CollectorSpec holds the specification of an OpenTelemetry collector.
+type CollectorSpec struct {
+	// Version of the Elastic Distribution of OpenTelemetry (EDOT) collector, which is the version of Elastic Agent.
+	Version string `json:"version"`
+
+	// Image is the Elastic Agent Docker image running the EDOT collector.
+	Image string `json:"image,omitempty"`
+
+	// Count of collector instances to deploy.
+	Count int32 `json:"count,omitempty"`
+
+	// ElasticsearchRef is a reference to the Elasticsearch cluster to which the telemetry data is exported.
+	// +kubebuilder:validation:Optional
+	ElasticsearchRef commonv1.ObjectSelector `json:"elasticsearchRef,omitempty"`
+
+	// ApmServerRef is a reference to the APM Server to which the telemetry data is exported over OTLP.
+	// +kubebuilder:validation:Optional
+	ApmServerRef commonv1.ObjectSelector `json:"apmServerRef,omitempty"`
+
+	// Config holds the OpenTelemetry collector configuration. It is merged with the configuration generated by the
+	// operator, which receives OTLP data and exports it to the referenced Elasticsearch cluster and APM Server.
+	// See: https://opentelemetry.io/docs/collector/configuration/
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Config *commonv1.Config `json:"config,omitempty"`
+
+	// Service defines the template for the Kubernetes Service exposing the OTLP receivers of the collector.
+	// +kubebuilder:validation:Optional
+	Service commonv1.ServiceTemplate `json:"service,omitempty"`
+
+	// PodTemplate provides customisation options (labels, annotations, affinity rules, resource requests, and so on) for the collector pods
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	PodTemplate corev1.PodTemplateSpec `json:"podTemplate,omitempty"`
+
+	// RevisionHistoryLimit is the number of revisions to retain to allow rollback in the underlying Deployment.
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+
+	// ServiceAccountName is used to check access from the current resource to a resource (for ex. Elasticsearch) in a different namespace.
+	// Can only be used if ECK is enforcing RBAC on references.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+}
+
+// CollectorStatus defines the observed state of an OpenTelemetry collector.
+type CollectorStatus struct {
+	commonv1.DeploymentStatus `json:",inline"`
+
+	// ElasticsearchAssociationStatus is the status of any auto-linking to Elasticsearch clusters.
+	ElasticsearchAssociationStatus commonv1.AssociationStatus `json:"elasticsearchAssociationStatus,omitempty"`
+
+	// ApmServerAssociationStatus is the status of any auto-linking to APM Servers.
+	ApmServerAssociationStatus commonv1.AssociationStatus `json:"apmServerAssociationStatus,omitempty"`
+
+	// ObservedGeneration is the most recent generation observed for this OpenTelemetry collector.
+	// It corresponds to the metadata generation, which is updated on mutation by the API Server.
+	// If the generation observed in status diverges from the generation in metadata, the OpenTelemetry
+	// collector controller has not yet processed the changes contained in the OpenTelemetry collector specification.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// IsMarkedForDeletion returns true if the OpenTelemetry collector is going to be deleted
+func (c *OpenTelemetryCollector) IsMarkedForDeletion() bool {
+	return !c.DeletionTimestamp.IsZero()
+}
+
+func (c *OpenTelemetryCollector) ServiceAccountName() string {
+	return c.Spec.ServiceAccountName
+}
+
+func (c *OpenTelemetryCollector) GetAssociations() []commonv1.Association {
+	associations := make([]commonv1.Association, 0)
+	if c.Spec.ElasticsearchRef.IsDefined() {
+		associations = append(associations, &CollectorEsAssociation{
+			OpenTelemetryCollector: c,
+		})
+	}
+	if c.Spec.ApmServerRef.IsDefined() {
+		associations = append(associations, &CollectorApmAssociation{
+			OpenTelemetryCollector: c,
+		})
+	}
+	return associations
+}
+
+func (c *OpenTelemetryCollector) AssociationStatusMap(typ commonv1.AssociationType) commonv1.AssociationStatusMap {
+	switch typ {
+	case commonv1.ElasticsearchAssociationType:
+		if c.Spec.ElasticsearchRef.IsDefined() {
+			return commonv1.NewSingleAssociationStatusMap(c.Status.ElasticsearchAssociationStatus)
+		}
+	case commonv1.ApmServerAssociationType:
+		if c.Spec.ApmServerRef.IsDefined() {
+			return commonv1.NewSingleAssociationStatusMap(c.Status.ApmServerAssociationStatus)
+		}
+	}
+
+	return commonv1.AssociationStatusMap{}
+}
+
+func (c *OpenTelemetryCollector) SetAssociationStatusMap(typ commonv1.AssociationType, status commonv1.AssociationStatusMap) error {
+	single, err := status.Single()
+	if err != nil {
+		return err
+	}
+
+	switch typ {
+	case commonv1.ElasticsearchAssociationType:
+		c.Status.ElasticsearchAssociationStatus = single
+		return nil
+	case commonv1.ApmServerAssociationType:
+		c.Status.ApmServerAssociationStatus = single
+		return nil
+	default:
+		return fmt.Errorf("association type %s not known", typ)
+	}
+}
+
+func (c *OpenTelemetryCollector) ElasticServiceAccount() (commonv1.ServiceAccountName, error) {
+	return "", nil
+}
+
+// GetObservedGeneration will return the observed generation from the OpenTelemetry collector status.
+func (c *OpenTelemetryCollector) GetObservedGeneration() int64 {
+	return c.Status.ObservedGeneration
+}
+
+// CollectorEsAssociation helps to manage the OpenTelemetry collector / Elasticsearch association.
+type CollectorEsAssociation struct {
+	*OpenTelemetryCollector
+}
+
+var _ commonv1.Association = &CollectorEsAssociation{}
+
+func NewCollectorEsAssociation(c *OpenTelemetryCollector) *CollectorEsAssociation {
+	return &CollectorEsAssociation{OpenTelemetryCollector: c}
+}
+
+func (ces *CollectorEsAssociation) Associated() commonv1.Associated {
+	if ces == nil {
+		return nil
+	}
+	if ces.OpenTelemetryCollector == nil {
+		ces.OpenTelemetryCollector = &OpenTelemetryCollector{}
+	}
+	return ces.OpenTelemetryCollector
+}
+
+func (ces *CollectorEsAssociation) AssociationConfAnnotationName() string {
+	return commonv1.ElasticsearchConfigAnnotationNameBase
+}
+
+func (ces *CollectorEsAssociation) AssociationType() commonv1.AssociationType {
+	return commonv1.ElasticsearchAssociationType
+}
+
+func (ces *CollectorEsAssociation) AssociationRef() commonv1.ObjectSelector {
+	return ces.Spec.ElasticsearchRef.WithDefaultNamespace(ces.Namespace)
+}
+
+func (ces *CollectorEsAssociation) AssociationConf() (*commonv1.AssociationConf, error) {
+	return commonv1.GetAndSetAssociationConf(ces, ces.esAssocConf)
+}
+
+func (ces *CollectorEsAssociation) SetAssociationConf(assocConf *commonv1.AssociationConf) {
+	ces.esAssocConf = assocConf
+}
+
+func (ces *CollectorEsAssociation) SupportsAuthAPIKey() bool {
+	return true
+}
+
+func (ces *CollectorEsAssociation) AssociationID() string {
+	return commonv1.SingletonAssociationID
+}
+
+// CollectorApmAssociation helps to manage the OpenTelemetry collector / APM Server association.
+type CollectorApmAssociation struct {
+	*OpenTelemetryCollector
+}
+
+var _ commonv1.Association = &CollectorApmAssociation{}
+
+func NewCollectorApmAssociation(c *OpenTelemetryCollector) *CollectorApmAssociation {
+	return &CollectorApmAssociation{OpenTelemetryCollector: c}
+}
+
+func (capm *CollectorApmAssociation) Associated() commonv1.Associated {
+	if capm == nil {
+		return nil
+	}
+	if capm.OpenTelemetryCollector == nil {
+		capm.OpenTelemetryCollector = &OpenTelemetryCollector{}
+	}
+	return capm.OpenTelemetryCollector
+}
+
+func (capm *CollectorApmAssociation) AssociationConfAnnotationName() string {
+	return commonv1.FormatNameWithID(commonv1.ApmServerConfigAnnotationNameBase+"%s", capm.AssociationID())
+}
+
+func (capm *CollectorApmAssociation) AssociationType() commonv1.AssociationType {
+	return commonv1.ApmServerAssociationType
+}
+
+func (capm *CollectorApmAssociation) AssociationRef() commonv1.ObjectSelector {
+	return capm.Spec.ApmServerRef.WithDefaultNamespace(capm.Namespace)
+}
+
+func (capm *CollectorApmAssociation) AssociationConf() (*commonv1.AssociationConf, error) {
+	return commonv1.GetAndSetAssociationConf(capm, capm.apmAssocConf)
+}
+
+func (capm *CollectorApmAssociation) SetAssociationConf(assocConf *commonv1.AssociationConf) {
+	capm.apmAssocConf = assocConf
+}
+
+// SupportsAuthAPIKey returns true as external APM Servers are authenticated with an API key sent over OTLP.
+func (capm *CollectorApmAssociation) SupportsAuthAPIKey() bool {
+	return true
+}
+
+func (capm *CollectorApmAssociation) AssociationID() string {
+	return commonv1.SingletonAssociationID
+}
+
+var _ commonv1.Associated = &OpenTelemetryCollector{}
+
+// +kubebuilder:object:root=true
+
+// OpenTelemetryCollector represents an Elastic Distribution of OpenTelemetry (EDOT) collector in a Kubernetes cluster.
+// +kubebuilder:resource:categories=elastic,shortName=otel
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="health",type="string",JSONPath=".status.health"
+// +kubebuilder:printcolumn:name="available",type="integer",JSONPath=".status.availableNodes",description="Available collectors"
+// +kubebuilder:printcolumn:name="expected",type="integer",JSONPath=".spec.count",description="Expected collectors"
+// +kubebuilder:printcolumn:name="version",type="string",JSONPath=".status.version",description="Collector version"
+// +kubebuilder:printcolumn:name="age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="elasticsearch",type="string",JSONPath=".status.elasticsearchAssociationStatus",description="Elasticsearch association status",priority=1
+// +kubebuilder:subresource:scale:specpath=.spec.count,statuspath=.status.count,selectorpath=.status.selector
+// +kubebuilder:storageversion
+type OpenTelemetryCollector struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec         CollectorSpec             `json:"spec,omitempty"`
+	Status       CollectorStatus           `json:"status,omitempty"`
+	esAssocConf  *commonv1.AssociationConf `json:"-"`
+	apmAssocConf *commonv1.AssociationConf `json:"-"`
+}
+
+// +kubebuilder:object:root=true
+
+// OpenTelemetryCollectorList contains a list of OpenTelemetryCollector
+type OpenTelemetryCollectorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpenTelemetryCollector `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpenTelemetryCollector{}, &OpenTelemetryCollectorList{})
+}
@@ -0,0 +1,100 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+const (
+	// webhookPath is the HTTP path for the OpenTelemetry collector validating webhook.
+	webhookPath = "/validate-otel-k8s-elastic-co-v1alpha1-opentelemetrycollectors"
+)
+
+var (
+	groupKind     = schema.GroupKind{Group: GroupVersion.Group, Kind: Kind}
+	validationLog = ulog.Log.WithName("otel-v1alpha1-validation")
+
+	defaultChecks = []func(*OpenTelemetryCollector) field.ErrorList{
+		checkNoUnknownFields,
+		checkNameLength,
+		checkSupportedVersion,
+		checkAssociations,
+	}
+)
+
+// +kubebuilder:webhook:path=/validate-otel-k8s-elastic-co-v1alpha1-opentelemetrycollectors,mutating=false,failurePolicy=ignore,groups=otel.k8s.elastic.co,resources=opentelemetrycollectors,verbs=create;update,versions=v1alpha1,name=elastic-otel-validation-v1alpha1.k8s.elastic.co,sideEffects=None,admissionReviewVersions=v1;v1beta1,matchPolicy=Exact
+
+var _ webhook.Validator = &OpenTelemetryCollector{}
+
+// ValidateCreate is called by the validating webhook to validate the create operation.
+// Satisfies the webhook.Validator interface.
+func (c *OpenTelemetryCollector) ValidateCreate() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate create", "name", c.Name)
+	return c.validate()
+}
+
+// ValidateDelete is called by the validating webhook to validate the delete operation.
+// Satisfies the webhook.Validator interface.
+func (c *OpenTelemetryCollector) ValidateDelete() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate delete", "name", c.Name)
+	return nil, nil
+}
+
+// ValidateUpdate is called by the validating webhook to validate the update operation.
+// Satisfies the webhook.Validator interface.
+func (c *OpenTelemetryCollector) ValidateUpdate(_ runtime.Object) (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate update", "name", c.Name)
+	return c.validate()
+}
+
+// WebhookPath returns the HTTP path used by the validating webhook.
+func (c *OpenTelemetryCollector) WebhookPath() string {
+	return webhookPath
+}
+
+func (c *OpenTelemetryCollector) validate() (admission.Warnings, error) {
+	var errors field.ErrorList
+
+	for _, dc := range defaultChecks {
+		if err := dc(c); err != nil {
+			errors = append(errors, err...)
+		}
+	}
+
+	if len(errors) > 0 {
+		validationLog.V(1).Info("failed validation", "errors", errors)
+		return nil, apierrors.NewInvalid(groupKind, c.Name, errors)
+	}
+	return nil, nil
+}
+
+func checkNoUnknownFields(c *OpenTelemetryCollector) field.ErrorList {
+	return commonv1.NoUnknownFields(c, c.ObjectMeta)
+}
+
+func checkNameLength(c *OpenTelemetryCollector) field.ErrorList {
+	return commonv1.CheckNameLength(c)
+}
+
+func checkSupportedVersion(c *OpenTelemetryCollector) field.ErrorList {
+	return commonv1.CheckSupportedStackVersion(c.Spec.Version, version.SupportedOTelCollectorVersions)
+}
+
+func checkAssociations(c *OpenTelemetryCollector) field.ErrorList {
+	var errs field.ErrorList
+	errs = append(errs, commonv1.CheckAssociationRefs(field.NewPath("spec").Child("elasticsearchRef"), c.Spec.ElasticsearchRef)...)
+	errs = append(errs, commonv1.CheckAssociationRefs(field.NewPath("spec").Child("apmServerRef"), c.Spec.ApmServerRef)...)
+	return errs
+}
@@ -0,0 +1,125 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	otelv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/otel/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/test"
+)
+
+func TestWebhook(t *testing.T) {
+	testCases := []test.ValidationWebhookTestCase{
+		{
+			Name:      "create-valid",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				c := mkCollector(uid)
+				c.Spec.ElasticsearchRef = commonv1.ObjectSelector{Name: "es"}
+				c.Spec.ApmServerRef = commonv1.ObjectSelector{Name: "apm"}
+				return serialize(t, c)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "long-name",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				c := mkCollector(uid)
+				c.SetName(strings.Repeat("x", 100))
+				return serialize(t, c)
+			},
+			Check: test.ValidationWebhookFailed(
+				`metadata.name: Too long: may not be more than 36 bytes`,
+			),
+		},
+		{
+			Name:      "unsupported-version-lower",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				c := mkCollector(uid)
+				c.Spec.Version = "8.15.3"
+				return serialize(t, c)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.version: Invalid value: "8.15.3": Unsupported version: version 8.15.3 is lower than the lowest supported version`,
+			),
+		},
+		{
+			Name:      "secret-apm-ref",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				c := mkCollector(uid)
+				c.Spec.ApmServerRef = commonv1.ObjectSelector{SecretName: "apm"}
+				return serialize(t, c)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "invalid-apm-ref",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				c := mkCollector(uid)
+				c.Spec.ApmServerRef = commonv1.ObjectSelector{SecretName: "apm", Name: "apm"}
+				return serialize(t, c)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.apmServerRef: Forbidden: Invalid association reference: specify name or secretName, not both`,
+			),
+		},
+		{
+			Name:      "invalid-es-ref",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				c := mkCollector(uid)
+				c.Spec.ElasticsearchRef = commonv1.ObjectSelector{SecretName: "es", Namespace: "ns"}
+				return serialize(t, c)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.elasticsearchRef: Forbidden: Invalid association reference: serviceName or namespace can only be used in combination with name, not with secretName`,
+			),
+		},
+	}
+
+	validator := &otelv1alpha1.OpenTelemetryCollector{}
+	gvk := metav1.GroupVersionKind{Group: otelv1alpha1.GroupVersion.Group, Version: otelv1alpha1.GroupVersion.Version, Kind: otelv1alpha1.Kind}
+	test.RunValidationWebhookTests(t, gvk, validator, testCases...)
+}
+
+func mkCollector(uid string) *otelv1alpha1.OpenTelemetryCollector {
+	return &otelv1alpha1.OpenTelemetryCollector{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "webhook-test",
+			UID:  types.UID(uid),
+		},
+		Spec: otelv1alpha1.CollectorSpec{
+			Version: "8.17.0",
+		},
+	}
+}
+
+func serialize(t *testing.T, c *otelv1alpha1.OpenTelemetryCollector) []byte {
+	t.Helper()
+
+	objBytes, err := json.Marshal(c)
+	require.NoError(t, err)
+
+	return objBytes
+}
@@ -0,0 +1,54 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
+)
+
+// ElasticsearchRef is a reference to an Elasticsearch cluster managed by ECK in the same namespace.
+type ElasticsearchRef struct {
+	// Name of the Elasticsearch cluster.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// NamespacedName returns the namespaced name of the referenced Elasticsearch cluster, given the namespace of the
+// referencing resource.
+func (r ElasticsearchRef) NamespacedName(namespace string) types.NamespacedName {
+	return types.NamespacedName{Namespace: namespace, Name: r.Name}
+}
+
+// SyncStatus reflects whether an ElasticsearchUser or an ElasticsearchRole is applied to the referenced
+// Elasticsearch cluster.
+type SyncStatus struct {
+	// Phase is the phase of the resource.
+	Phase SyncPhase `json:"phase,omitempty"`
+	// Message gives details about the current phase.
+	Message string `json:"message,omitempty"`
+	// ObservedGeneration is the most recent generation of the resource applied to the Elasticsearch cluster.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions holds the Ready, Reconciling and Stalled conditions of the resource.
+	// +kubebuilder:validation:Optional
+	Conditions commonv1alpha1.Conditions `json:"conditions,omitempty"`
+}
+
+// ResourceState returns the state of the resource from which its conditions are computed. An invalid resource is
+// stalled until it is updated.
+func (s SyncStatus) ResourceState() commonv1alpha1.ResourceState {
+	return commonv1alpha1.NewPhaseState(s.ObservedGeneration, string(s.Phase), s.Phase == ReadyPhase, s.Phase == InvalidPhase, s.Message)
+}
+
+type SyncPhase string
+
+const (
+	// ReadyPhase means the user or the role is part of the file realm of the Elasticsearch cluster.
+	ReadyPhase SyncPhase = "Ready"
+	// InvalidPhase means the user or the role cannot be applied to the Elasticsearch cluster, for example because its
+	// name is reserved or because the Secret holding the password does not exist.
+	InvalidPhase SyncPhase = "Invalid"
+)
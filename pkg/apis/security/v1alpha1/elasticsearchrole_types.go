@@ -0,0 +1,71 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+const (
+	// RoleKind is inferred from the struct name using reflection in SchemeBuilder.Register()
+	// we duplicate it as a constant here for practical purposes.
+	RoleKind = "ElasticsearchRole"
+)
+
+func init() {
+	SchemeBuilder.Register(&ElasticsearchRole{}, &ElasticsearchRoleList{})
+}
+
+// +kubebuilder:object:root=true
+
+// ElasticsearchRole represents a file-based role of an Elasticsearch cluster managed by ECK.
+// +kubebuilder:resource:categories=elastic,shortName=esrole
+// +kubebuilder:printcolumn:name="Elasticsearch",type="string",JSONPath=".spec.elasticsearchRef.name"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Reconciled",type="date",JSONPath=".status.conditions[?(@.type=='Reconciling')].lastTransitionTime",description="Time of the last transition of the Reconciling condition",priority=1
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+type ElasticsearchRole struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ElasticsearchRoleSpec `json:"spec,omitempty"`
+	Status SyncStatus            `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ElasticsearchRoleList contains a list of ElasticsearchRole resources.
+type ElasticsearchRoleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ElasticsearchRole `json:"items"`
+}
+
+type ElasticsearchRoleSpec struct {
+	// ElasticsearchRef is a reference to the Elasticsearch cluster in the same namespace in which the role is created.
+	ElasticsearchRef ElasticsearchRef `json:"elasticsearchRef"`
+
+	// RoleName is the name of the role in Elasticsearch. Defaults to the name of the ElasticsearchRole resource.
+	// +kubebuilder:validation:Optional
+	RoleName string `json:"roleName,omitempty"`
+
+	// Definition is the definition of the role, in the format of the Elasticsearch roles.yml file. For example:
+	// `cluster: ["monitor"]`, `indices: [{names: ["logs-*"], privileges: ["read"]}]`.
+	// See https://www.elastic.co/guide/en/elasticsearch/reference/current/defining-roles.html.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Definition commonv1.Config `json:"definition"`
+}
+
+// EffectiveRoleName returns the name of the role in Elasticsearch.
+func (r *ElasticsearchRole) EffectiveRoleName() string {
+	if r.Spec.RoleName == "" {
+		return r.Name
+	}
+	return r.Spec.RoleName
+}
@@ -0,0 +1,90 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// UserKind is inferred from the struct name using reflection in SchemeBuilder.Register()
+	// we duplicate it as a constant here for practical purposes.
+	UserKind = "ElasticsearchUser"
+)
+
+func init() {
+	SchemeBuilder.Register(&ElasticsearchUser{}, &ElasticsearchUserList{})
+}
+
+// +kubebuilder:object:root=true
+
+// ElasticsearchUser represents a user of the file realm of an Elasticsearch cluster managed by ECK.
+// +kubebuilder:resource:categories=elastic,shortName=esuser
+// +kubebuilder:printcolumn:name="Elasticsearch",type="string",JSONPath=".spec.elasticsearchRef.name"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Reconciled",type="date",JSONPath=".status.conditions[?(@.type=='Reconciling')].lastTransitionTime",description="Time of the last transition of the Reconciling condition",priority=1
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+type ElasticsearchUser struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ElasticsearchUserSpec `json:"spec,omitempty"`
+	Status SyncStatus            `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ElasticsearchUserList contains a list of ElasticsearchUser resources.
+type ElasticsearchUserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ElasticsearchUser `json:"items"`
+}
+
+type ElasticsearchUserSpec struct {
+	// ElasticsearchRef is a reference to the Elasticsearch cluster in the same namespace in which the user is created.
+	ElasticsearchRef ElasticsearchRef `json:"elasticsearchRef"`
+
+	// Username is the name of the user in Elasticsearch. Defaults to the name of the ElasticsearchUser resource.
+	// +kubebuilder:validation:Optional
+	Username string `json:"username,omitempty"`
+
+	// PasswordSecretRef references the Secret holding the password of the user.
+	PasswordSecretRef PasswordSecretRef `json:"passwordSecretRef"`
+
+	// Roles are the names of the roles granted to the user. They can be built-in roles, roles defined by
+	// ElasticsearchRole resources, or roles referenced in the spec.auth.roles of the Elasticsearch resource.
+	// +kubebuilder:validation:Optional
+	Roles []string `json:"roles,omitempty"`
+}
+
+// PasswordSecretRef references a key of a Secret in the same namespace.
+type PasswordSecretRef struct {
+	// SecretName is the name of the secret.
+	// +kubebuilder:validation:MinLength=1
+	SecretName string `json:"secretName"`
+	// Key is the key of the secret holding the password. Defaults to `password`.
+	// +kubebuilder:validation:Optional
+	Key string `json:"key,omitempty"`
+}
+
+// SecretKey returns the key of the Secret holding the password.
+func (r PasswordSecretRef) SecretKey() string {
+	if r.Key == "" {
+		return corev1.BasicAuthPasswordKey
+	}
+	return r.Key
+}
+
+// EffectiveUsername returns the name of the user in Elasticsearch.
+func (u *ElasticsearchUser) EffectiveUsername() string {
+	if u.Spec.Username == "" {
+		return u.Name
+	}
+	return u.Spec.Username
+}
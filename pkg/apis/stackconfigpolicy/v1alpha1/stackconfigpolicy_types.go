@@ -11,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
 	eslabel "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
 	kblabel "github.com/elastic/cloud-on-k8s/v2/pkg/controller/kibana/label"
 )
@@ -34,6 +35,7 @@ func init() {
 // +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.readyCount",description="Resources configured"
 // +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Reconciled",type="date",JSONPath=".status.conditions[?(@.type=='Reconciling')].lastTransitionTime",description="Time of the last transition of the Reconciling condition",priority=1
 // +kubebuilder:subresource:status
 // +kubebuilder:storageversion
 type StackConfigPolicy struct {
@@ -83,6 +85,10 @@ type ElasticsearchConfigPolicySpec struct {
 	// IndexTemplates holds the Index and Component Templates settings
 	// +kubebuilder:pruning:PreserveUnknownFields
 	IndexTemplates IndexTemplates `json:"indexTemplates,omitempty"`
+	// Aliases holds the index aliases to add, by name (/_aliases). They are added once the other settings are applied,
+	// and are not removed from Elasticsearch when they are removed from the policy.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Aliases *commonv1.Config `json:"aliases,omitempty"`
 	// Config holds the settings that go into elasticsearch.yml.
 	// +kubebuilder:pruning:PreserveUnknownFields
 	Config *commonv1.Config `json:"config,omitempty"`
@@ -137,6 +143,20 @@ type StackConfigPolicyStatus struct {
 	Phase PolicyPhase `json:"phase,omitempty"`
 	// ObservedGeneration is the most recent generation observed for this StackConfigPolicy.
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions holds the Ready, Reconciling and Stalled conditions of the StackConfigPolicy.
+	// +kubebuilder:validation:Optional
+	Conditions commonv1alpha1.Conditions `json:"conditions,omitempty"`
+}
+
+// ResourceState returns the state of the StackConfigPolicy from which its conditions are computed. An invalid policy, or
+// a policy conflicting with another one, is stalled until the policies are updated.
+func (s StackConfigPolicyStatus) ResourceState() commonv1alpha1.ResourceState {
+	var message string
+	if s.ReadyCount != "" {
+		message = fmt.Sprintf("%s resources configured", s.ReadyCount)
+	}
+	stalled := s.Phase == InvalidPhase || s.Phase == ConflictPhase
+	return commonv1alpha1.NewPhaseState(s.ObservedGeneration, string(s.Phase), s.Phase == ReadyPhase, stalled, message)
 }
 
 type PolicyPhase string
@@ -171,6 +191,26 @@ type ResourcePolicyStatus struct {
 	// This field does not apply to Kibana resources
 	ExpectedVersion int64             `json:"expectedVersion,omitempty"`
 	Error           PolicyStatusError `json:"error,omitempty"`
+	// SnapshotLifecyclePolicies reports the snapshot lifecycle policies found in the Elasticsearch cluster,
+	// when the policy declares snapshot lifecycle policies.
+	// This field does not apply to Kibana resources
+	SnapshotLifecyclePolicies *SnapshotLifecyclePoliciesStatus `json:"snapshotLifecyclePolicies,omitempty"`
+}
+
+// SnapshotLifecyclePoliciesStatus compares the snapshot lifecycle policies declared in the policy with the ones existing in
+// an Elasticsearch cluster.
+type SnapshotLifecyclePoliciesStatus struct {
+	// Managed is the number of declared snapshot lifecycle policies which exist in the Elasticsearch cluster.
+	Managed int `json:"managed,omitempty"`
+	// Missing lists the declared snapshot lifecycle policies which do not exist in the Elasticsearch cluster.
+	Missing []string `json:"missing,omitempty"`
+	// Unmanaged lists the snapshot lifecycle policies which exist in the Elasticsearch cluster but are not declared in the policy.
+	Unmanaged []string `json:"unmanaged,omitempty"`
+}
+
+// HasDrifted returns true if some declared snapshot lifecycle policies do not exist in the Elasticsearch cluster.
+func (s *SnapshotLifecyclePoliciesStatus) HasDrifted() bool {
+	return s != nil && len(s.Missing) > 0
 }
 
 type PolicyStatusError struct {
@@ -249,6 +289,10 @@ func (s *StackConfigPolicyStatus) UpdateResourceStatusPhase(resource types.Names
 
 		if status.CurrentVersion == status.ExpectedVersion {
 			status.Phase = ReadyPhase
+			if status.SnapshotLifecyclePolicies.HasDrifted() {
+				// settings are applied but some declared snapshot lifecycle policies cannot be found in the cluster
+				status.Phase = ErrorPhase
+			}
 			return nil
 		}
 		status.Phase = ApplyingChangesPhase
@@ -6,6 +6,7 @@ package v1alpha1
 
 import (
 	"fmt"
+	"slices"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -32,6 +33,9 @@ var (
 		checkNoUnknownFields,
 		checkNameLength,
 		validSettings,
+		validAliases,
+		noSecureSettingsNamespace,
+		noSecureSettingsBackend,
 	}
 )
 
@@ -125,6 +129,9 @@ func validSettings(policy *StackConfigPolicy) field.ErrorList {
 	if policy.Spec.Elasticsearch.IndexTemplates.ComposableIndexTemplates != nil {
 		settingsCount += len(policy.Spec.Elasticsearch.IndexTemplates.ComposableIndexTemplates.Data)
 	}
+	if policy.Spec.Elasticsearch.Aliases != nil {
+		settingsCount += len(policy.Spec.Elasticsearch.Aliases.Data)
+	}
 	if policy.Spec.Elasticsearch.Config != nil {
 		settingsCount += len(policy.Spec.Elasticsearch.Config.Data)
 	}
@@ -138,12 +145,78 @@ func validSettings(policy *StackConfigPolicy) field.ErrorList {
 	if policy.Spec.Kibana.Config != nil {
 		settingsCount += len(policy.Spec.Kibana.Config.Data)
 	}
+	// Kibana secure settings can be distributed without any Kibana config
+	settingsCount += len(policy.Spec.Kibana.SecureSettings)
 	if settingsCount == 0 {
 		return field.ErrorList{field.Required(field.NewPath("spec").Child("elasticsearch"), "One out of Elasticsearch or Kibana settings is mandatory, both must not be empty")}
 	}
 	return nil
 }
 
+// validAliases checks that each alias is defined by an object pointing to at least one index.
+func validAliases(policy *StackConfigPolicy) field.ErrorList {
+	if policy.Spec.Elasticsearch.Aliases == nil {
+		return nil
+	}
+	path := field.NewPath("spec").Child("elasticsearch").Child("aliases")
+	names := make([]string, 0, len(policy.Spec.Elasticsearch.Aliases.Data))
+	for name := range policy.Spec.Elasticsearch.Aliases.Data {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	var errs field.ErrorList
+	for _, name := range names {
+		definition, ok := policy.Spec.Elasticsearch.Aliases.Data[name].(map[string]interface{})
+		if !ok {
+			errs = append(errs, field.Invalid(path.Key(name), policy.Spec.Elasticsearch.Aliases.Data[name], "alias definition must be an object"))
+			continue
+		}
+		_, hasIndex := definition["index"]
+		_, hasIndices := definition["indices"]
+		if !hasIndex && !hasIndices {
+			errs = append(errs, field.Required(path.Key(name), "the index or indices the alias points to are mandatory"))
+		}
+		if _, hasAlias := definition["alias"]; hasAlias {
+			errs = append(errs, field.Forbidden(path.Key(name).Child("alias"), "the name of the alias is its key"))
+		}
+	}
+	return errs
+}
+
+// noSecureSettingsNamespace checks that the secure settings of the policy do not reference Secrets in other namespaces,
+// as they are always read from the namespace of the policy.
+func noSecureSettingsNamespace(policy *StackConfigPolicy) field.ErrorList {
+	var errs field.ErrorList
+	check := func(path *field.Path, sources []commonv1.SecretSource) {
+		for i, source := range sources {
+			if source.Namespace != "" && source.Namespace != policy.Namespace {
+				errs = append(errs, field.Forbidden(path.Index(i).Child("namespace"), "secure settings must be in the namespace of the policy"))
+			}
+		}
+	}
+	check(field.NewPath("spec").Child("secureSettings"), policy.Spec.SecureSettings) //nolint:staticcheck
+	check(field.NewPath("spec").Child("elasticsearch").Child("secureSettings"), policy.Spec.Elasticsearch.SecureSettings)
+	check(field.NewPath("spec").Child("kibana").Child("secureSettings"), policy.Spec.Kibana.SecureSettings)
+	return errs
+}
+
+// noSecureSettingsBackend checks that the secure settings of the policy are not read from external secret backends,
+// which are only supported in the secure settings of the resources.
+func noSecureSettingsBackend(policy *StackConfigPolicy) field.ErrorList {
+	var errs field.ErrorList
+	check := func(path *field.Path, sources []commonv1.SecretSource) {
+		for i, source := range sources {
+			if source.Backend != "" {
+				errs = append(errs, field.Forbidden(path.Index(i).Child("backend"), "secure settings of policies must be Kubernetes Secrets"))
+			}
+		}
+	}
+	check(field.NewPath("spec").Child("secureSettings"), policy.Spec.SecureSettings) //nolint:staticcheck
+	check(field.NewPath("spec").Child("elasticsearch").Child("secureSettings"), policy.Spec.Elasticsearch.SecureSettings)
+	check(field.NewPath("spec").Child("kibana").Child("secureSettings"), policy.Spec.Kibana.SecureSettings)
+	return errs
+}
+
 // uniqueSecretMountPaths returns true if all given mountpaths are unique
 func uniqueSecretMountPaths(secretMounts []SecretMount) bool {
 	mountPathMap := make(map[string]bool)
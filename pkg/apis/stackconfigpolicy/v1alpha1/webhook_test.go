@@ -56,6 +56,82 @@ func TestWebhook(t *testing.T) {
 			},
 			Check: test.ValidationWebhookSucceeded,
 		},
+		{
+			Name:      "create-valid-kibana-secure-settings-only",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkStackConfigPolicy(uid)
+				m.Spec.Elasticsearch = policyv1alpha1.ElasticsearchConfigPolicySpec{}
+				m.Spec.Kibana = policyv1alpha1.KibanaConfigPolicySpec{
+					SecureSettings: []commonv1.SecretSource{{SecretName: "kibana-secure-settings"}},
+				}
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "create-valid-aliases-only",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkStackConfigPolicy(uid)
+				m.Spec.Elasticsearch = policyv1alpha1.ElasticsearchConfigPolicySpec{
+					Aliases: &commonv1.Config{Data: map[string]interface{}{
+						"logs-current": map[string]interface{}{"index": "logs-2024.10", "is_write_index": true},
+					}},
+				}
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "invalid-aliases",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkStackConfigPolicy(uid)
+				m.Spec.Elasticsearch.Aliases = &commonv1.Config{Data: map[string]interface{}{
+					"a": "logs",
+					"b": map[string]interface{}{"filter": map[string]interface{}{}},
+					"c": map[string]interface{}{"alias": "c", "indices": []interface{}{"logs-*"}},
+				}}
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.elasticsearch.aliases\[a\]: Invalid value: "logs": alias definition must be an object`,
+				`spec.elasticsearch.aliases\[b\]: Required value: the index or indices the alias points to are mandatory`,
+				`spec.elasticsearch.aliases\[c\].alias: Forbidden: the name of the alias is its key`,
+			),
+		},
+		{
+			Name:      "secure-settings-in-other-namespace",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkStackConfigPolicy(uid)
+				m.Spec.Kibana = policyv1alpha1.KibanaConfigPolicySpec{
+					SecureSettings: []commonv1.SecretSource{{SecretName: "kibana-secure-settings", Namespace: "other"}},
+				}
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.kibana.secureSettings\[0\].namespace: Forbidden: secure settings must be in the namespace of the policy`,
+			),
+		},
+		{
+			Name:      "secure-settings-from-secret-backend",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkStackConfigPolicy(uid)
+				m.Spec.Elasticsearch.SecureSettings = []commonv1.SecretSource{{SecretName: "secret/data/es", Backend: "vault"}}
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.elasticsearch.secureSettings\[0\].backend: Forbidden: secure settings of policies must be Kubernetes Secrets`,
+			),
+		},
 		{
 			Name:      "unknown-field",
 			Operation: admissionv1beta1.Create,
@@ -10,6 +10,7 @@ package v1alpha1
 
 import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -41,6 +42,10 @@ func (in *ElasticsearchConfigPolicySpec) DeepCopyInto(out *ElasticsearchConfigPo
 		*out = (*in).DeepCopy()
 	}
 	in.IndexTemplates.DeepCopyInto(&out.IndexTemplates)
+	if in.Aliases != nil {
+		in, out := &in.Aliases, &out.Aliases
+		*out = (*in).DeepCopy()
+	}
 	if in.Config != nil {
 		in, out := &in.Config, &out.Config
 		*out = (*in).DeepCopy()
@@ -137,6 +142,11 @@ func (in *PolicyStatusError) DeepCopy() *PolicyStatusError {
 func (in *ResourcePolicyStatus) DeepCopyInto(out *ResourcePolicyStatus) {
 	*out = *in
 	out.Error = in.Error
+	if in.SnapshotLifecyclePolicies != nil {
+		in, out := &in.SnapshotLifecyclePolicies, &out.SnapshotLifecyclePolicies
+		*out = new(SnapshotLifecyclePoliciesStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourcePolicyStatus.
@@ -164,6 +174,31 @@ func (in *SecretMount) DeepCopy() *SecretMount {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotLifecyclePoliciesStatus) DeepCopyInto(out *SnapshotLifecyclePoliciesStatus) {
+	*out = *in
+	if in.Missing != nil {
+		in, out := &in.Missing, &out.Missing
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Unmanaged != nil {
+		in, out := &in.Unmanaged, &out.Unmanaged
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotLifecyclePoliciesStatus.
+func (in *SnapshotLifecyclePoliciesStatus) DeepCopy() *SnapshotLifecyclePoliciesStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotLifecyclePoliciesStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StackConfigPolicy) DeepCopyInto(out *StackConfigPolicy) {
 	*out = *in
@@ -255,7 +290,7 @@ func (in *StackConfigPolicyStatus) DeepCopyInto(out *StackConfigPolicyStatus) {
 		in, out := &in.ResourcesStatuses, &out.ResourcesStatuses
 		*out = make(map[string]ResourcePolicyStatus, len(*in))
 		for key, val := range *in {
-			(*out)[key] = val
+			(*out)[key] = *val.DeepCopy()
 		}
 	}
 	if in.Details != nil {
@@ -270,12 +305,19 @@ func (in *StackConfigPolicyStatus) DeepCopyInto(out *StackConfigPolicyStatus) {
 				in, out := &inVal, &outVal
 				*out = make(map[string]ResourcePolicyStatus, len(*in))
 				for key, val := range *in {
-					(*out)[key] = val
+					(*out)[key] = *val.DeepCopy()
 				}
 			}
 			(*out)[key] = outVal
 		}
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(commonv1alpha1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StackConfigPolicyStatus.
@@ -0,0 +1,43 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package clusterinfo
+
+import (
+	"context"
+	"net/http"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/rbac"
+)
+
+const (
+	elasticsearchResource = "elasticsearches"
+	// clusterInfoSubresource is a virtual subresource of the Elasticsearch resources, on which the callers must be
+	// granted the get verb. Being allowed to get an Elasticsearch resource is not enough to retrieve an API key.
+	clusterInfoSubresource = "cluster-info"
+)
+
+// authenticate reviews the bearer token of the request, and returns the Kubernetes user it belongs to.
+func (h *Handler) authenticate(ctx context.Context, r *http.Request) (authenticationv1.UserInfo, error) {
+	return rbac.AuthenticateRequest(ctx, h.clientset, r)
+}
+
+// authorize returns true if the given user is allowed to get the cluster-info subresource of the given Elasticsearch
+// resource.
+func (h *Handler) authorize(ctx context.Context, user authenticationv1.UserInfo, es types.NamespacedName) (bool, error) {
+	return rbac.UserAllowed(ctx, h.clientset, user, authorizationv1.ResourceAttributes{
+		Namespace:   es.Namespace,
+		Verb:        "get",
+		Group:       esv1.GroupVersion.Group,
+		Version:     esv1.GroupVersion.Version,
+		Resource:    elasticsearchResource,
+		Subresource: clusterInfoSubresource,
+		Name:        es.Name,
+	})
+}
@@ -0,0 +1,270 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+// Package clusterinfo implements a read-only API, served by the operator webhook server, through which other
+// controllers running in the Kubernetes cluster can retrieve the information required to connect to the Elasticsearch
+// clusters managed by ECK, without relying on the naming conventions of the Secrets and Services created by ECK.
+package clusterinfo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	commonesclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/esclient"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/services"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+const (
+	// Path is the path prefix under which the API is registered in the webhook server.
+	Path = "/cluster-info/"
+
+	// apiKeyExpiration is the lifetime of the API keys returned to the callers.
+	apiKeyExpiration = "1h"
+	// apiKeyRenewBefore is the remaining lifetime under which a cached API key is replaced by a new one, so that the
+	// callers always get an API key valid for at least that long.
+	apiKeyRenewBefore = 30 * time.Minute
+	// apiKeyRequestedByMetadata is the API key metadata holding the name of the Kubernetes user the key was issued to.
+	apiKeyRequestedByMetadata = "eck.k8s.elastic.co/requested-by"
+)
+
+// readOnlyRoleDescriptors restricts the API keys returned to the callers to the read-only monitoring of the cluster,
+// without access to the documents of its indices.
+var readOnlyRoleDescriptors = map[string]esclient.APIKeyRoleDescriptor{
+	"eck_cluster_info_reader": {
+		Cluster: []string{"monitor"},
+	},
+}
+
+// ElasticsearchInfo is the information returned for an Elasticsearch cluster.
+type ElasticsearchInfo struct {
+	// Namespace of the Elasticsearch resource.
+	Namespace string `json:"namespace"`
+	// Name of the Elasticsearch resource.
+	Name string `json:"name"`
+	// Version of Elasticsearch, as reported in the status of the resource.
+	Version string `json:"version,omitempty"`
+	// Health of the cluster, as reported in the status of the resource.
+	Health esv1.ElasticsearchHealth `json:"health,omitempty"`
+	// URL of the Elasticsearch HTTP Service.
+	URL string `json:"url"`
+	// CACert is the PEM encoded CA certificate to trust when connecting to the URL, if TLS is enabled.
+	CACert string `json:"caCert,omitempty"`
+	// APIKey is a short-lived API key restricted to the monitor cluster privilege.
+	APIKey APIKey `json:"apiKey"`
+}
+
+// APIKey is an Elasticsearch API key.
+type APIKey struct {
+	// ID of the API key.
+	ID string `json:"id"`
+	// Encoded is the base64 encoding of <id>:<key>, to be used in the Authorization: ApiKey header.
+	Encoded string `json:"encoded"`
+	// Expiration of the API key.
+	Expiration time.Time `json:"expiration"`
+}
+
+// Handler serves the cluster info API.
+type Handler struct {
+	client           k8s.Client
+	clientset        kubernetes.Interface
+	dialer           net.Dialer
+	esClientProvider commonesclient.Provider
+	apiKeys          *apiKeyCache
+	mux              *http.ServeMux
+}
+
+// NewHandler returns a new Handler. The clientset is used to review the tokens and the permissions of the callers.
+func NewHandler(client k8s.Client, clientset kubernetes.Interface, dialer net.Dialer) *Handler {
+	h := &Handler{
+		client:           client,
+		clientset:        clientset,
+		dialer:           dialer,
+		esClientProvider: commonesclient.NewClient,
+		apiKeys:          &apiKeyCache{keys: map[apiKeyCacheKey]APIKey{}},
+		mux:              http.NewServeMux(),
+	}
+	h.mux.HandleFunc("GET "+Path+"v1/namespaces/{namespace}/elasticsearches/{name}", h.getElasticsearch)
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// getElasticsearch returns the information of an Elasticsearch cluster to the callers allowed to get the cluster-info
+// subresource of the Elasticsearch resource.
+func (h *Handler) getElasticsearch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	key := types.NamespacedName{Namespace: r.PathValue("namespace"), Name: r.PathValue("name")}
+	log := ulog.FromContext(ctx).WithValues("namespace", key.Namespace, "es_name", key.Name)
+
+	user, err := h.authenticate(ctx, r)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, err)
+		return
+	}
+	allowed, err := h.authorize(ctx, user, key)
+	if err != nil {
+		log.Error(err, "Failed to review the access to the cluster info", "user", user.Username)
+		writeError(w, http.StatusInternalServerError, errors.New("failed to review access"))
+		return
+	}
+	if !allowed {
+		writeError(w, http.StatusForbidden, fmt.Errorf("user %s cannot get Elasticsearch %s", user.Username, key))
+		return
+	}
+
+	info, err := h.elasticsearchInfo(ctx, key, user.Username)
+	if err != nil {
+		var statusErr *statusError
+		if errors.As(err, &statusErr) {
+			writeError(w, statusErr.status, statusErr)
+			return
+		}
+		log.Error(err, "Failed to get the cluster info")
+		writeError(w, http.StatusInternalServerError, errors.New("failed to get the cluster info"))
+		return
+	}
+	log.V(1).Info("Serving cluster info", "user", user.Username, "api_key_id", info.APIKey.ID)
+	writeJSON(w, http.StatusOK, info)
+}
+
+// elasticsearchInfo retrieves the information of the given Elasticsearch cluster, and the API key of the given user,
+// which is only created if the user has no cached API key valid long enough.
+func (h *Handler) elasticsearchInfo(ctx context.Context, key types.NamespacedName, username string) (ElasticsearchInfo, error) {
+	var es esv1.Elasticsearch
+	if err := h.client.Get(ctx, key, &es); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ElasticsearchInfo{}, &statusError{status: http.StatusNotFound, msg: fmt.Sprintf("Elasticsearch %s not found", key)}
+		}
+		return ElasticsearchInfo{}, err
+	}
+	info := ElasticsearchInfo{
+		Namespace: es.Namespace,
+		Name:      es.Name,
+		Version:   es.Status.Version,
+		Health:    es.Status.Health,
+		URL:       services.ExternalServiceURL(es),
+	}
+
+	if es.Spec.HTTP.TLSEnabled() {
+		var caSecret corev1.Secret
+		caKey := types.NamespacedName{Namespace: es.Namespace, Name: certificates.PublicCertsSecretName(esv1.ESNamer, es.Name)}
+		if err := h.client.Get(ctx, caKey, &caSecret); err != nil {
+			if apierrors.IsNotFound(err) {
+				return ElasticsearchInfo{}, notReadyError(key)
+			}
+			return ElasticsearchInfo{}, err
+		}
+		// the CA certificate is not available if the HTTP certificate is provided by the user without its CA
+		caCert, ok := caSecret.Data[certificates.CAFileName]
+		if !ok {
+			caCert = caSecret.Data[certificates.CertFileName]
+		}
+		info.CACert = string(caCert)
+	}
+
+	if !services.NewElasticsearchURLProvider(es, h.client).HasEndpoints() {
+		return ElasticsearchInfo{}, notReadyError(key)
+	}
+	cacheKey := apiKeyCacheKey{es: es.UID, username: username}
+	if apiKey, ok := h.apiKeys.get(cacheKey, time.Now()); ok {
+		info.APIKey = apiKey
+		return info, nil
+	}
+	esClient, err := h.esClientProvider(ctx, h.client, h.dialer, es)
+	if err != nil {
+		return ElasticsearchInfo{}, err
+	}
+	defer esClient.Close()
+	apiKey, err := esClient.CreateAPIKey(ctx, esclient.APIKeyCreateRequest{
+		Name:            fmt.Sprintf("eck-cluster-info-%s", username),
+		Expiration:      apiKeyExpiration,
+		RoleDescriptors: readOnlyRoleDescriptors,
+		Metadata:        map[string]interface{}{apiKeyRequestedByMetadata: username},
+	})
+	if err != nil {
+		return ElasticsearchInfo{}, err
+	}
+	info.APIKey = APIKey{ID: apiKey.ID, Encoded: apiKey.Encoded, Expiration: time.UnixMilli(apiKey.Expiration).UTC()}
+	h.apiKeys.put(cacheKey, info.APIKey, time.Now())
+	return info, nil
+}
+
+// apiKeyCacheKey identifies the API key of a user for an Elasticsearch cluster. The UID of the Elasticsearch resource
+// prevents reusing the API keys of a deleted cluster for a new one with the same name.
+type apiKeyCacheKey struct {
+	es       types.UID
+	username string
+}
+
+// apiKeyCache holds the API keys issued to the callers, so that the repeated requests of a caller reuse the same API
+// key instead of creating a new one in Elasticsearch each time.
+type apiKeyCache struct {
+	mu   sync.Mutex
+	keys map[apiKeyCacheKey]APIKey
+}
+
+// get returns the cached API key of the given key if it remains valid for at least apiKeyRenewBefore.
+func (c *apiKeyCache) get(key apiKeyCacheKey, now time.Time) (APIKey, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	apiKey, ok := c.keys[key]
+	if !ok || now.Add(apiKeyRenewBefore).After(apiKey.Expiration) {
+		return APIKey{}, false
+	}
+	return apiKey, true
+}
+
+// put caches the given API key, and removes the expired ones.
+func (c *apiKeyCache) put(key apiKeyCacheKey, apiKey APIKey, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, cached := range c.keys {
+		if now.After(cached.Expiration) {
+			delete(c.keys, k)
+		}
+	}
+	c.keys[key] = apiKey
+}
+
+// statusError is an error returned to the caller with a specific HTTP status.
+type statusError struct {
+	status int
+	msg    string
+}
+
+func (e *statusError) Error() string {
+	return e.msg
+}
+
+func notReadyError(key types.NamespacedName) error {
+	return &statusError{status: http.StatusServiceUnavailable, msg: fmt.Sprintf("Elasticsearch %s is not ready yet", key)}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,215 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package clusterinfo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+const readerUser = "system:serviceaccount:apps:reader"
+
+// fakeAPIKeyExpiration is the expiration of the API keys created by the fake Elasticsearch client.
+var fakeAPIKeyExpiration = time.Now().Add(time.Hour).Truncate(time.Millisecond).UTC()
+
+type fakeESClient struct {
+	esclient.Client
+	request esclient.APIKeyCreateRequest
+	created int
+}
+
+func (f *fakeESClient) CreateAPIKey(_ context.Context, request esclient.APIKeyCreateRequest) (esclient.APIKeyCreateResponse, error) {
+	f.request = request
+	f.created++
+	return esclient.APIKeyCreateResponse{ID: "key-id", Name: request.Name, Expiration: fakeAPIKeyExpiration.UnixMilli(), Encoded: "a2V5LWlkOnNlY3JldA=="}, nil
+}
+
+func (f *fakeESClient) Close() {}
+
+// fakeClientset authenticates the "valid" token as the reader user, which is allowed to get all the Elasticsearch
+// resources but only the cluster-info subresource of the "es" cluster.
+func fakeClientset() *fake.Clientset {
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().DeepCopyObject().(*authenticationv1.TokenReview) //nolint:forcetypeassert
+		if review.Spec.Token == "valid" {
+			review.Status.Authenticated = true
+			review.Status.User = authenticationv1.UserInfo{Username: readerUser, Groups: []string{"system:serviceaccounts"}}
+		}
+		return true, review, nil
+	})
+	clientset.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().DeepCopyObject().(*authorizationv1.SubjectAccessReview) //nolint:forcetypeassert
+		attributes := review.Spec.ResourceAttributes
+		review.Status.Allowed = review.Spec.User == readerUser && attributes.Resource == "elasticsearches" &&
+			attributes.Group == "elasticsearch.k8s.elastic.co" && attributes.Verb == "get" &&
+			(attributes.Subresource == "" || attributes.Subresource == "cluster-info" && attributes.Name == "es")
+		return true, review, nil
+	})
+	return clientset
+}
+
+func TestHandler(t *testing.T) {
+	es := &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Status:     esv1.ElasticsearchStatus{Version: "8.15.0", Health: esv1.ElasticsearchGreenHealth},
+	}
+	caSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es-es-http-certs-public"},
+		Data:       map[string][]byte{"ca.crt": []byte("ca"), "tls.crt": []byte("tls")},
+	}
+	runningPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es-es-default-0", Labels: map[string]string{label.ClusterNameLabelName: "es"}},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	notAllowed := &esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "other"}}
+
+	tests := []struct {
+		name       string
+		objects    []crclient.Object
+		path       string
+		token      string
+		wantStatus int
+		wantInfo   *ElasticsearchInfo
+	}{
+		{
+			name:       "missing token",
+			path:       "/cluster-info/v1/namespaces/ns/elasticsearches/es",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "invalid token",
+			path:       "/cluster-info/v1/namespaces/ns/elasticsearches/es",
+			token:      "invalid",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "not allowed",
+			objects:    []crclient.Object{notAllowed},
+			path:       "/cluster-info/v1/namespaces/ns/elasticsearches/other",
+			token:      "valid",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "not found",
+			path:       "/cluster-info/v1/namespaces/ns/elasticsearches/es",
+			token:      "valid",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "not ready",
+			objects:    []crclient.Object{es, caSecret},
+			path:       "/cluster-info/v1/namespaces/ns/elasticsearches/es",
+			token:      "valid",
+			wantStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:       "unknown path",
+			objects:    []crclient.Object{es, caSecret, runningPod},
+			path:       "/cluster-info/v1/namespaces/ns/kibanas/kb",
+			token:      "valid",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "cluster info",
+			objects:    []crclient.Object{es, caSecret, runningPod},
+			path:       "/cluster-info/v1/namespaces/ns/elasticsearches/es",
+			token:      "valid",
+			wantStatus: http.StatusOK,
+			wantInfo: &ElasticsearchInfo{
+				Namespace: "ns",
+				Name:      "es",
+				Version:   "8.15.0",
+				Health:    esv1.ElasticsearchGreenHealth,
+				URL:       "https://es-es-http.ns.svc:9200",
+				CACert:    "ca",
+				APIKey:    APIKey{ID: "key-id", Encoded: "a2V5LWlkOnNlY3JldA==", Expiration: fakeAPIKeyExpiration},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			esClient := &fakeESClient{}
+			h := NewHandler(k8s.NewFakeClient(tt.objects...), fakeClientset(), nil)
+			h.esClientProvider = func(_ context.Context, _ k8s.Client, _ net.Dialer, _ esv1.Elasticsearch) (esclient.Client, error) {
+				return esClient, nil
+			}
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			if tt.wantInfo == nil {
+				return
+			}
+			var info ElasticsearchInfo
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &info))
+			require.Equal(t, *tt.wantInfo, info)
+			require.Equal(t, "eck-cluster-info-"+readerUser, esClient.request.Name)
+			require.Equal(t, "1h", esClient.request.Expiration)
+			require.Equal(t, readOnlyRoleDescriptors, esClient.request.RoleDescriptors)
+
+			// the API key is reused by the next requests of the same user
+			rec = httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &info))
+			require.Equal(t, *tt.wantInfo, info)
+			require.Equal(t, 1, esClient.created)
+		})
+	}
+}
+
+func Test_apiKeyCache(t *testing.T) {
+	now := time.Now()
+	key := apiKeyCacheKey{es: "uid", username: readerUser}
+	c := &apiKeyCache{keys: map[apiKeyCacheKey]APIKey{}}
+
+	_, ok := c.get(key, now)
+	require.False(t, ok)
+
+	c.put(key, APIKey{ID: "valid", Expiration: now.Add(time.Hour)}, now)
+	apiKey, ok := c.get(key, now)
+	require.True(t, ok)
+	require.Equal(t, "valid", apiKey.ID)
+	// API keys of other users or of other clusters are not shared
+	_, ok = c.get(apiKeyCacheKey{es: "uid", username: "other"}, now)
+	require.False(t, ok)
+	_, ok = c.get(apiKeyCacheKey{es: "other-uid", username: readerUser}, now)
+	require.False(t, ok)
+	// API keys close to expiring are renewed
+	_, ok = c.get(key, now.Add(45*time.Minute))
+	require.False(t, ok)
+
+	// expired API keys are removed
+	other := apiKeyCacheKey{es: "uid", username: "other"}
+	c.put(other, APIKey{ID: "other", Expiration: now.Add(2 * time.Hour)}, now.Add(90*time.Minute))
+	require.Len(t, c.keys, 1)
+	require.Contains(t, c.keys, other)
+}
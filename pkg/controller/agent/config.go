@@ -29,6 +29,9 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 )
 
+// DownloadSourceURIConfigKey is the setting of the artifact mirror the Elastic Agents download their binaries from.
+const DownloadSourceURIConfigKey = "agent.download.sourceURI"
+
 type connectionSettings struct {
 	host, caFileName, version string
 	credentials               association.Credentials
@@ -70,6 +73,17 @@ func buildConfig(params Params) ([]byte, error) {
 		return nil, err
 	}
 
+	if sourceURI := params.Agent.Spec.DownloadSourceURI(); sourceURI != "" && params.Agent.Spec.StandaloneModeEnabled() {
+		// in fleet mode the download source is set in the agent policy
+		downloadConfig, err := settings.NewSingleValue(DownloadSourceURIConfigKey, sourceURI)
+		if err != nil {
+			return nil, err
+		}
+		if err = cfg.MergeWith(downloadConfig); err != nil {
+			return nil, err
+		}
+	}
+
 	// get user config from `config` or `configRef`
 	userConfig, err := getUserConfig(params)
 	if err != nil {
@@ -132,6 +146,10 @@ func buildOutputConfig(params Params) (*settings.CanonicalConfig, error) {
 			output["ssl.certificate_authorities"] = []string{path.Join(certificatesDir(assoc), CAFileName)}
 		}
 
+		for k, v := range params.Agent.Spec.ElasticsearchRefs[i].OutputSettings.Settings() {
+			output[k] = v
+		}
+
 		outputName := params.Agent.Spec.ElasticsearchRefs[i].OutputName
 		if outputName == "" {
 			if len(esAssociations) > 1 {
@@ -18,6 +18,7 @@ import (
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/association"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 )
 
@@ -238,3 +239,46 @@ func Test_extractClientConnectionSettings(t *testing.T) {
 		})
 	}
 }
+
+func Test_buildConfig_download(t *testing.T) {
+	download := &agentv1alpha1.AgentDownloadSpec{SourceURI: "https://artifacts.example.com/downloads/"}
+	for _, tt := range []struct {
+		name string
+		spec agentv1alpha1.AgentSpec
+		want string
+	}{
+		{
+			name: "standalone mode",
+			spec: agentv1alpha1.AgentSpec{Download: download},
+			want: "agent:\n    download:\n        sourceURI: https://artifacts.example.com/downloads/\n",
+		},
+		{
+			name: "user config takes precedence",
+			spec: agentv1alpha1.AgentSpec{
+				Download: download,
+				Config: &commonv1.Config{Data: map[string]interface{}{
+					"agent.download.sourceURI": "https://other.example.com/downloads/",
+				}},
+			},
+			want: "agent:\n    download:\n        sourceURI: https://other.example.com/downloads/\n",
+		},
+		{
+			name: "fleet mode",
+			spec: agentv1alpha1.AgentSpec{Download: download, Mode: agentv1alpha1.AgentFleetMode},
+			want: "{}\n",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := agentFixture()
+			agent.Spec = tt.spec
+			got, err := buildConfig(Params{
+				Context: context.Background(),
+				Client:  k8s.NewFakeClient(),
+				Watches: watches.NewDynamicWatches(),
+				Agent:   *agent,
+			})
+			require.NoError(t, err)
+			require.Equal(t, tt.want, string(got))
+		})
+	}
+}
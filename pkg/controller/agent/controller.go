@@ -19,6 +19,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	agentv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/agent/v1alpha1"
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/association"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
@@ -29,6 +30,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	logconf "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
 )
 
 const (
@@ -146,7 +148,7 @@ func (r *ReconcileAgent) Reconcile(ctx context.Context, request reconcile.Reques
 	agent := &agentv1alpha1.Agent{}
 	if err := r.Client.Get(ctx, request.NamespacedName, agent); err != nil {
 		if apierrors.IsNotFound(err) {
-			r.onDelete(request.NamespacedName)
+			r.onDelete(ctx, request.NamespacedName)
 			return reconcile.Result{}, nil
 		}
 		return reconcile.Result{}, tracing.CaptureError(ctx, err)
@@ -154,7 +156,10 @@ func (r *ReconcileAgent) Reconcile(ctx context.Context, request reconcile.Reques
 
 	if common.IsUnmanaged(ctx, agent) {
 		logconf.FromContext(ctx).Info("Object is currently not managed by this controller. Skipping reconciliation")
-		return reconcile.Result{}, nil
+		return reconcile.Result{}, tracing.CaptureError(ctx, common.ReconcilePausedCondition(ctx, r.Client, agent, &agent.Status.Conditions, true))
+	}
+	if err := common.ReconcilePausedCondition(ctx, r.Client, agent, &agent.Status.Conditions, false); err != nil {
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
 	}
 
 	if agent.IsMarkedForDeletion() {
@@ -162,6 +167,9 @@ func (r *ReconcileAgent) Reconcile(ctx context.Context, request reconcile.Reques
 	}
 
 	results, status := r.doReconcile(ctx, *agent)
+	common.UpdateStandardConditions(&status.Conditions, commonv1alpha1.NewResourceState(
+		agent.Generation, string(status.Health), status.AvailableNodes, status.ExpectedNodes, agent.Spec.Version, status.Version,
+	), results)
 
 	if err := updateStatus(ctx, *agent, r.Client, status); err != nil {
 		if apierrors.IsConflict(err) {
@@ -218,7 +226,8 @@ func (r *ReconcileAgent) validate(ctx context.Context, agent agentv1alpha1.Agent
 	return nil
 }
 
-func (r *ReconcileAgent) onDelete(obj types.NamespacedName) {
+func (r *ReconcileAgent) onDelete(ctx context.Context, obj types.NamespacedName) {
+	metrics.DeleteResourceMetrics(ctx, agentv1alpha1.Kind, obj)
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(keystore.SecureSettingsWatchName(obj))
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(common.ConfigRefWatchName(obj))
 }
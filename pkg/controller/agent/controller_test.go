@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -20,6 +21,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	agentv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/agent/v1alpha1"
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/comparison"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
@@ -89,6 +91,7 @@ func TestReconcileAgent_Reconcile(t *testing.T) {
 				},
 				Status: agentv1alpha1.AgentStatus{
 					ObservedGeneration: 1,
+					Conditions:         commonv1alpha1.Conditions{{Type: commonv1alpha1.PausedCondition, Status: corev1.ConditionTrue, Message: "Reconciliation is suspended by the eck.k8s.elastic.co/managed annotation"}},
 				},
 			},
 			wantErr: false,
@@ -122,6 +125,14 @@ func TestReconcileAgent_Reconcile(t *testing.T) {
 				},
 				Status: agentv1alpha1.AgentStatus{
 					ObservedGeneration: 2,
+					Conditions: commonv1alpha1.Conditions{
+						{Type: commonv1alpha1.ReadyCondition, Status: corev1.ConditionFalse, ObservedGeneration: 2, Reason: commonv1alpha1.UnavailableReason},
+						{Type: commonv1alpha1.ReconcilingCondition, Status: corev1.ConditionFalse, ObservedGeneration: 2, Reason: commonv1alpha1.StalledReason},
+						{Type: commonv1alpha1.StalledCondition, Status: corev1.ConditionTrue, ObservedGeneration: 2, Reason: commonv1alpha1.StalledReason},
+						{Type: commonv1alpha1.ReconciliationCompleteCondition, Status: corev1.ConditionFalse, ObservedGeneration: 2, Reason: commonv1alpha1.ReconcilingReason},
+						{Type: commonv1alpha1.DegradedCondition, Status: corev1.ConditionTrue, ObservedGeneration: 2, Reason: commonv1alpha1.DegradedReason},
+						{Type: commonv1alpha1.UpgradeInProgressCondition, Status: corev1.ConditionUnknown, ObservedGeneration: 2, Reason: commonv1alpha1.VersionUnknownReason},
+					},
 				},
 			},
 			wantErr: true,
@@ -200,6 +211,14 @@ func TestReconcileAgent_Reconcile(t *testing.T) {
 					AvailableNodes:     1,
 					ObservedGeneration: 2,
 					Health:             agentv1alpha1.AgentGreenHealth,
+					Conditions: commonv1alpha1.Conditions{
+						{Type: commonv1alpha1.ReadyCondition, Status: corev1.ConditionTrue, ObservedGeneration: 2, Reason: commonv1alpha1.AvailableReason},
+						{Type: commonv1alpha1.ReconcilingCondition, Status: corev1.ConditionFalse, ObservedGeneration: 2, Reason: commonv1alpha1.ReconciledReason},
+						{Type: commonv1alpha1.StalledCondition, Status: corev1.ConditionFalse, ObservedGeneration: 2, Reason: commonv1alpha1.ProgressingReason},
+						{Type: commonv1alpha1.ReconciliationCompleteCondition, Status: corev1.ConditionTrue, ObservedGeneration: 2, Reason: commonv1alpha1.ReconciledReason},
+						{Type: commonv1alpha1.DegradedCondition, Status: corev1.ConditionFalse, ObservedGeneration: 2, Reason: commonv1alpha1.HealthyReason},
+						{Type: commonv1alpha1.UpgradeInProgressCondition, Status: corev1.ConditionFalse, ObservedGeneration: 2, Reason: commonv1alpha1.UpToDateReason},
+					},
 				},
 			},
 			wantErr: false,
@@ -223,7 +242,9 @@ func TestReconcileAgent_Reconcile(t *testing.T) {
 				return
 			}
 			// AllowUnexported required because of *AssocConf on the agent.
-			comparison.AssertEqual(t, &agent, &tt.expected, cmp.AllowUnexported(agentv1alpha1.Agent{}))
+			// Condition messages are ignored as they include the whole validation error.
+			comparison.AssertEqual(t, &agent, &tt.expected, cmp.AllowUnexported(agentv1alpha1.Agent{}),
+				cmpopts.IgnoreFields(commonv1alpha1.Condition{}, "Message"))
 		})
 	}
 }
@@ -8,6 +8,8 @@ import (
 	"context"
 	"fmt"
 	"hash/fnv"
+	"net"
+	"net/url"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
@@ -21,6 +23,7 @@ import (
 	commonassociation "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/association"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/gateway"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
@@ -107,16 +110,19 @@ func internalReconcile(params Params) (*reconciler.Results, agentv1alpha1.AgentS
 	if err != nil {
 		return results.WithError(err), params.Status
 	}
+	if err := reconcileRoute(params, svc); err != nil {
+		return results.WithError(err), params.Status
+	}
 
 	configHash := fnv.New32a()
 	var fleetCerts *certificates.CertificatesSecret
-	if params.Agent.Spec.FleetServerEnabled && params.Agent.Spec.HTTP.TLS.Enabled() {
+	if params.Agent.Spec.FleetServerEnabled && params.Agent.Spec.HTTP.TLSEnabled() {
 		var caResults *reconciler.Results
 		fleetCerts, caResults = certificates.Reconciler{
 			K8sClient:                   params.Client,
 			DynamicWatches:              params.Watches,
 			Owner:                       &params.Agent,
-			TLSOptions:                  params.Agent.Spec.HTTP.TLS,
+			TLSOptions:                  params.Agent.Spec.HTTP.EffectiveTLS(),
 			Namer:                       Namer,
 			Labels:                      params.Agent.GetIdentityLabels(),
 			Services:                    []corev1.Service{*svc},
@@ -125,7 +131,7 @@ func internalReconcile(params Params) (*reconciler.Results, agentv1alpha1.AgentS
 			CertRotation:                params.OperatorParams.CertRotation,
 			GarbageCollectSecrets:       true,
 			DisableInternalCADefaulting: true, // we do not want placeholder CAs in the internal certificates secret as FLEET_CA replaces otherwise all well known CAs
-			ExtraHTTPSANs:               []commonv1.SubjectAlternativeName{{DNS: fmt.Sprintf("*.%s.%s.svc", HTTPServiceName(params.Agent.Name), params.Agent.Namespace)}},
+			ExtraHTTPSANs:               fleetServerExtraHTTPSANs(params.Agent),
 		}.ReconcileCAAndHTTPCerts(params.Context)
 		if caResults.HasError() {
 			return results.WithResults(caResults), params.Status
@@ -154,6 +160,25 @@ func internalReconcile(params Params) (*reconciler.Results, agentv1alpha1.AgentS
 	return reconcilePodVehicle(params, podTemplate)
 }
 
+// fleetServerExtraHTTPSANs returns the subject alternative names to add to the Fleet Server HTTP certificate on top of
+// the ones derived from the Service: a wildcard for the Pods behind the Service, and the host of the external URL if any.
+func fleetServerExtraHTTPSANs(agent agentv1alpha1.Agent) []commonv1.SubjectAlternativeName {
+	sans := []commonv1.SubjectAlternativeName{{DNS: fmt.Sprintf("*.%s.%s.svc", HTTPServiceName(agent.Name), agent.Namespace)}}
+	externalURL := agent.Spec.FleetServerExternalURL()
+	if externalURL == "" {
+		return sans
+	}
+	u, err := url.Parse(externalURL)
+	if err != nil || u.Hostname() == "" {
+		// URL is validated by the webhook, ignore invalid values here
+		return sans
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil {
+		return append(sans, commonv1.SubjectAlternativeName{IP: ip.String()})
+	}
+	return append(sans, commonv1.SubjectAlternativeName{DNS: u.Hostname()})
+}
+
 func reconcileService(params Params) (*corev1.Service, error) {
 	svc := newService(params.Agent)
 
@@ -161,6 +186,9 @@ func reconcileService(params Params) (*corev1.Service, error) {
 	if !params.Agent.Spec.FleetServerEnabled {
 		// clean up if it was previously set up
 		if err := params.Client.Get(params.Context, k8s.ExtractNamespacedName(svc), svc); err == nil {
+			if err := gateway.DeleteRoute(params.Context, params.Client, &params.Agent, *svc); err != nil {
+				return nil, err
+			}
 			err := params.Client.Delete(params.Context, svc)
 			if err != nil && !apierrors.IsNotFound(err) {
 				return nil, err
@@ -173,6 +201,15 @@ func reconcileService(params Params) (*corev1.Service, error) {
 	return common.ReconcileService(params.Context, params.Client, svc, &params.Agent)
 }
 
+// reconcileRoute reconciles the Gateway API route exposing the Fleet Server Service. The route is deleted along with the
+// Service if Fleet Server is not enabled.
+func reconcileRoute(params Params, svc *corev1.Service) error {
+	if svc == nil {
+		return nil
+	}
+	return gateway.ReconcileRoute(params.Context, params.Client, &params.Agent, params.Agent.Spec.HTTP, *svc, params.Agent.GetIdentityLabels())
+}
+
 func newService(agent agentv1alpha1.Agent) *corev1.Service {
 	svc := corev1.Service{
 		ObjectMeta: agent.Spec.HTTP.Service.ObjectMeta,
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"reflect"
 	"strings"
 	"time"
 
@@ -28,6 +29,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
 	commonhttp "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/http"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/safemode"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
@@ -74,6 +76,19 @@ type Policy struct {
 	Status               string `json:"status"`
 }
 
+// FleetServerHostResult wrapper for a single Fleet Server host in the Fleet API.
+type FleetServerHostResult struct {
+	Item FleetServerHostItem `json:"item"`
+}
+
+// FleetServerHostItem is the representation of a Fleet Server host in the Fleet API.
+type FleetServerHostItem struct {
+	ID        string   `json:"id,omitempty"`
+	Name      string   `json:"name"`
+	HostURLs  []string `json:"host_urls"`
+	IsDefault bool     `json:"is_default"`
+}
+
 type fleetAPI struct {
 	client        *http.Client
 	endpoint      string
@@ -116,6 +131,9 @@ func (f fleetAPI) request(
 	if err != nil {
 		return err
 	}
+	if err := safemode.CheckRequest(ctx, method, request.URL.Redacted()); err != nil {
+		return err
+	}
 
 	// Sets headers allowing ES to distinguish between deprecated APIs used internally and by the user
 	if request.Header == nil {
@@ -229,6 +247,23 @@ func (f fleetAPI) defaultAgentPolicyID(ctx context.Context) (string, error) {
 	return policy.ID, nil
 }
 
+func (f fleetAPI) getFleetServerHost(ctx context.Context, id string) (FleetServerHostItem, error) {
+	var response FleetServerHostResult
+	err := f.request(ctx, http.MethodGet, fmt.Sprintf("fleet_server_hosts/%s", id), nil, &response)
+	return response.Item, err
+}
+
+func (f fleetAPI) createFleetServerHost(ctx context.Context, host FleetServerHostItem) error {
+	return f.request(ctx, http.MethodPost, "fleet_server_hosts", host, nil)
+}
+
+func (f fleetAPI) updateFleetServerHost(ctx context.Context, host FleetServerHostItem) error {
+	// the ID is part of the path and must not be repeated in the body
+	id := host.ID
+	host.ID = ""
+	return f.request(ctx, http.MethodPut, fmt.Sprintf("fleet_server_hosts/%s", id), host, nil)
+}
+
 func (f fleetAPI) setupFleet(ctx context.Context) error {
 	return f.request(ctx, http.MethodPost, "setup", nil, nil)
 }
@@ -260,13 +295,14 @@ func maybeReconcileFleetEnrollment(params Params, result *reconciler.Results) En
 		return EnrollmentAPIKey{}
 	}
 
-	token, err := reconcileEnrollmentToken(
-		params,
-		newFleetAPI(
-			params.OperatorParams.Dialer,
-			kbConnectionSettings,
-			log),
-	)
+	api := newFleetAPI(
+		params.OperatorParams.Dialer,
+		kbConnectionSettings,
+		log)
+	token, err := reconcileEnrollmentToken(params, api)
+	if err == nil {
+		err = reconcileFleetServerHost(params, api)
+	}
 	switch {
 	case commonhttp.IsUnauthorized(err):
 		message := "ECK cannot setup Fleet enrollment. Waiting for Kibana credentials. This should be a transient issue."
@@ -355,7 +391,48 @@ FindOrCreate:
 	return key, nil
 }
 
+// fleetServerHostID returns the ID of the Fleet Server host managed by ECK for the given Fleet Server.
+func fleetServerHostID(agent agentv1alpha1.Agent) string {
+	return fmt.Sprintf("eck-%s-%s", agent.Namespace, agent.Name)
+}
+
+// reconcileFleetServerHost registers the external URL of a Fleet Server as a Fleet Server host in Kibana, and keeps
+// it in sync with the URL declared in the spec.
+func reconcileFleetServerHost(params Params, api fleetAPI) error {
+	agent := params.Agent
+	externalURL := agent.Spec.FleetServerExternalURL()
+	if !agent.Spec.FleetServerEnabled || externalURL == "" {
+		return nil
+	}
+	defer api.client.CloseIdleConnections()
+	ctx := params.Context
+
+	expected := FleetServerHostItem{
+		ID:       fleetServerHostID(agent),
+		Name:     fmt.Sprintf("%s/%s (external)", agent.Namespace, agent.Name),
+		HostURLs: []string{externalURL},
+	}
+	actual, err := api.getFleetServerHost(ctx, expected.ID)
+	if err != nil && commonhttp.IsNotFound(err) {
+		ulog.FromContext(ctx).Info("Creating Fleet Server host", "id", expected.ID, "url", externalURL)
+		return api.createFleetServerHost(ctx, expected)
+	}
+	if err != nil {
+		return err
+	}
+	// preserve the default flag, users may have chosen to make this host the default one in Kibana
+	expected.IsDefault = actual.IsDefault
+	if actual.Name == expected.Name && reflect.DeepEqual(actual.HostURLs, expected.HostURLs) {
+		return nil
+	}
+	ulog.FromContext(ctx).Info("Updating Fleet Server host", "id", expected.ID, "url", externalURL)
+	return api.updateFleetServerHost(ctx, expected)
+}
+
 func findPolicyID(ctx context.Context, recorder record.EventRecorder, agent agentv1alpha1.Agent, api fleetAPI) (string, error) {
+	if agent.Spec.Policy != nil {
+		return reconcileAgentPolicy(ctx, agent, api)
+	}
 	if agent.Spec.PolicyID != "" {
 		return agent.Spec.PolicyID, nil
 	}
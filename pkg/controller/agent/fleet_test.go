@@ -264,6 +264,72 @@ func Test_reconcileEnrollmentToken(t *testing.T) {
 	}
 }
 
+func Test_reconcileFleetServerHost(t *testing.T) {
+	fleetServer := func(externalURL string) v1alpha1.Agent {
+		return v1alpha1.Agent{
+			ObjectMeta: metav1.ObjectMeta{Name: "fleet-server", Namespace: "ns"},
+			Spec: v1alpha1.AgentSpec{
+				FleetServerEnabled: true,
+				FleetServer:        &v1alpha1.FleetServerSpec{ExternalURL: externalURL},
+			},
+		}
+	}
+	tests := []struct {
+		name    string
+		agent   v1alpha1.Agent
+		api     *mockFleetAPI
+		wantErr bool
+	}{
+		{
+			name:  "no external URL",
+			agent: fleetServer(""),
+			api:   mockFleetResponses(map[request]response{}),
+		},
+		{
+			name:  "Fleet Server host does not exist yet",
+			agent: fleetServer("https://fleet.example.com"),
+			api: mockFleetResponses(map[request]response{
+				{"GET", "/api/fleet/fleet_server_hosts/eck-ns-fleet-server"}: {code: 404},
+				{"POST", "/api/fleet/fleet_server_hosts"}:                    {code: 200},
+			}),
+		},
+		{
+			name:  "Fleet Server host up to date",
+			agent: fleetServer("https://fleet.example.com"),
+			api: mockFleetResponses(map[request]response{
+				{"GET", "/api/fleet/fleet_server_hosts/eck-ns-fleet-server"}: {code: 200, body: `{"item":{"id":"eck-ns-fleet-server","name":"ns/fleet-server (external)","host_urls":["https://fleet.example.com"],"is_default":true}}`},
+			}),
+		},
+		{
+			name:  "Fleet Server host with outdated URL",
+			agent: fleetServer("https://fleet.example.com"),
+			api: mockFleetResponses(map[request]response{
+				{"GET", "/api/fleet/fleet_server_hosts/eck-ns-fleet-server"}: {code: 200, body: `{"item":{"id":"eck-ns-fleet-server","name":"ns/fleet-server (external)","host_urls":["https://old.example.com"],"is_default":false}}`},
+				{"PUT", "/api/fleet/fleet_server_hosts/eck-ns-fleet-server"}: {code: 200},
+			}),
+		},
+		{
+			name:  "Fleet API error",
+			agent: fleetServer("https://fleet.example.com"),
+			api: mockFleetResponses(map[request]response{
+				{"GET", "/api/fleet/fleet_server_hosts/eck-ns-fleet-server"}: {code: 500},
+			}),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := Params{
+				Context: context.Background(),
+				Agent:   tt.agent,
+			}
+			err := reconcileFleetServerHost(params, tt.api.fleetAPI)
+			require.Empty(t, tt.api.missingRequests())
+			require.Equal(t, tt.wantErr, err != nil, "reconcileFleetServerHost() error = %v", err)
+		})
+	}
+}
+
 type RoundTripFunc func(req *http.Request) *http.Response
 
 func (f RoundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
@@ -158,6 +158,9 @@ func buildPodTemplate(params Params, fleetCerts *certificates.CertificatesSecret
 	if err != nil {
 		return corev1.PodTemplateSpec{}, err // error unlikely and should have been caught during validation
 	}
+	if err := container.CheckImageCatalog(spec.Image, container.AgentImage, v); err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
 	// volume with agent data path if version > 7.15 (available since 7.13 but non-functional as agent tries to fork child
 	// processes in data path directory and hostPath volumes are always mounted non-exec)
 	if v.GTE(version.MinFor(7, 15, 0)) {
@@ -190,7 +193,9 @@ func buildPodTemplate(params Params, fleetCerts *certificates.CertificatesSecret
 					FieldPath: "spec.nodeName",
 				},
 			}},
-		)
+		).
+		WithServiceMesh(params.Agent.Spec.HTTP.ServiceMesh).
+		WithPodMutationPolicy(agentv1alpha1.Kind, params.Agent.Namespace)
 
 	return builder.PodTemplate, nil
 }
@@ -221,7 +226,7 @@ func amendBuilderForFleetMode(params Params, fleetCerts *certificates.Certificat
 		builder = builder.WithPorts([]corev1.ContainerPort{{Name: params.Agent.Spec.HTTP.Protocol(), ContainerPort: FleetServerPort, Protocol: corev1.ProtocolTCP}})
 
 		// Only add certificate volumes if TLS is enabled.
-		if params.Agent.Spec.HTTP.TLS.Enabled() {
+		if params.Agent.Spec.HTTP.TLSEnabled() {
 			// ECK creates CA and a certificate for Fleet Server to use. This volume contains those.
 			builder = builder.WithVolumeLikes(
 				volume.NewSecretVolumeWithMountPath(
@@ -535,7 +540,7 @@ func getFleetSetupFleetEnvVars(client k8s.Client, fleetToken EnrollmentAPIKey, f
 			}
 
 			fleetCfg[FleetURL] = fleetURL
-			if agent.Spec.HTTP.TLS.Enabled() && fleetCerts.HasCA() {
+			if agent.Spec.HTTP.TLSEnabled() && fleetCerts.HasCA() {
 				fleetCfg[FleetCA] = path.Join(FleetCertsMountPath, certificates.CAFileName)
 			}
 			// Fleet Server needs a policy ID to bootstrap itself unless a policy marked as default is used.
@@ -580,7 +585,7 @@ func getFleetSetupFleetServerEnvVars(ctx context.Context, client k8s.Client) fun
 			FleetServerEnable: "true",
 		}
 
-		if agent.Spec.HTTP.TLS.Enabled() {
+		if agent.Spec.HTTP.TLSEnabled() {
 			fleetServerCfg[FleetServerCert] = path.Join(FleetCertsMountPath, certificates.CertFileName)
 			fleetServerCfg[FleetServerCertKey] = path.Join(FleetCertsMountPath, certificates.KeyFileName)
 		} else {
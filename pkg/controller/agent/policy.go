@@ -0,0 +1,376 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	"k8s.io/utils/ptr"
+
+	agentv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/agent/v1alpha1"
+	commonhttp "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/http"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+const (
+	defaultPolicyNamespace = "default"
+	packagePoliciesPerPage = 100
+)
+
+// AgentPolicyResult wrapper for a single agent policy in the Fleet API.
+type AgentPolicyResult struct {
+	Item AgentPolicyItem `json:"item"`
+}
+
+// AgentPolicyItem is the representation of the agent policy settings managed by ECK in the Fleet API.
+type AgentPolicyItem struct {
+	ID                string   `json:"id,omitempty"`
+	Name              string   `json:"name"`
+	Namespace         string   `json:"namespace"`
+	Description       string   `json:"description"`
+	MonitoringEnabled []string `json:"monitoring_enabled"`
+	// output IDs are null when the default outputs are used
+	DataOutputID       *string `json:"data_output_id"`
+	MonitoringOutputID *string `json:"monitoring_output_id"`
+	// the download source ID is null when the default download source is used
+	DownloadSourceID *string `json:"download_source_id"`
+}
+
+// DownloadSourceResult wrapper for a single agent binary download source in the Fleet API.
+type DownloadSourceResult struct {
+	Item DownloadSourceItem `json:"item"`
+}
+
+// DownloadSourceItem is the representation of an agent binary download source in the Fleet API.
+type DownloadSourceItem struct {
+	ID        string `json:"id,omitempty"`
+	Name      string `json:"name"`
+	Host      string `json:"host"`
+	IsDefault bool   `json:"is_default"`
+}
+
+// PackagePolicyResult wrapper for a single package policy in the Fleet API.
+type PackagePolicyResult struct {
+	Item PackagePolicyItem `json:"item"`
+}
+
+// PackagePolicyList is a wrapper for a page of package policies as returned by the Fleet API.
+type PackagePolicyList struct {
+	Items []PackagePolicyItem `json:"items"`
+}
+
+// PackagePolicyItem is the representation of a package policy in the simplified format of the Fleet API.
+type PackagePolicyItem struct {
+	ID        string                 `json:"id,omitempty"`
+	Name      string                 `json:"name"`
+	Namespace string                 `json:"namespace,omitempty"`
+	PolicyID  string                 `json:"policy_id"`
+	Package   PackageItem            `json:"package"`
+	Vars      map[string]interface{} `json:"vars,omitempty"`
+	Inputs    map[string]interface{} `json:"inputs,omitempty"`
+}
+
+// PackageItem identifies the package of a package policy in the Fleet API.
+type PackageItem struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type deletePackagePoliciesRequest struct {
+	PackagePolicyIDs []string `json:"packagePolicyIds"`
+}
+
+func (f fleetAPI) getAgentPolicy(ctx context.Context, id string) (AgentPolicyItem, error) {
+	var response AgentPolicyResult
+	err := f.request(ctx, http.MethodGet, fmt.Sprintf("agent_policies/%s", id), nil, &response)
+	return response.Item, err
+}
+
+func (f fleetAPI) createAgentPolicy(ctx context.Context, policy AgentPolicyItem) error {
+	return f.request(ctx, http.MethodPost, "agent_policies", policy, nil)
+}
+
+func (f fleetAPI) updateAgentPolicy(ctx context.Context, policy AgentPolicyItem) error {
+	// the ID is part of the path and must not be repeated in the body
+	id := policy.ID
+	policy.ID = ""
+	return f.request(ctx, http.MethodPut, fmt.Sprintf("agent_policies/%s", id), policy, nil)
+}
+
+func (f fleetAPI) getDownloadSource(ctx context.Context, id string) (DownloadSourceItem, error) {
+	var response DownloadSourceResult
+	err := f.request(ctx, http.MethodGet, fmt.Sprintf("agent_download_sources/%s", id), nil, &response)
+	return response.Item, err
+}
+
+func (f fleetAPI) createDownloadSource(ctx context.Context, source DownloadSourceItem) error {
+	return f.request(ctx, http.MethodPost, "agent_download_sources", source, nil)
+}
+
+func (f fleetAPI) updateDownloadSource(ctx context.Context, source DownloadSourceItem) error {
+	// the ID is part of the path and must not be repeated in the body
+	id := source.ID
+	source.ID = ""
+	return f.request(ctx, http.MethodPut, fmt.Sprintf("agent_download_sources/%s", id), source, nil)
+}
+
+func (f fleetAPI) getPackagePolicy(ctx context.Context, id string) (PackagePolicyItem, error) {
+	var response PackagePolicyResult
+	err := f.request(ctx, http.MethodGet, fmt.Sprintf("package_policies/%s?format=simplified", id), nil, &response)
+	return response.Item, err
+}
+
+func (f fleetAPI) listPackagePolicies(ctx context.Context, filter func(policy PackagePolicyItem) bool) ([]PackagePolicyItem, error) {
+	var policies []PackagePolicyItem
+	for page := 1; ; page++ {
+		var list PackagePolicyList
+		path := fmt.Sprintf("package_policies?perPage=%d&page=%d", packagePoliciesPerPage, page)
+		if err := f.request(ctx, http.MethodGet, path, nil, &list); err != nil {
+			return nil, err
+		}
+		for _, p := range list.Items {
+			if filter(p) {
+				policies = append(policies, p)
+			}
+		}
+		if len(list.Items) < packagePoliciesPerPage {
+			return policies, nil
+		}
+	}
+}
+
+func (f fleetAPI) createPackagePolicy(ctx context.Context, policy PackagePolicyItem) error {
+	return f.request(ctx, http.MethodPost, "package_policies", policy, nil)
+}
+
+func (f fleetAPI) updatePackagePolicy(ctx context.Context, policy PackagePolicyItem) error {
+	// the ID is part of the path and must not be repeated in the body
+	id := policy.ID
+	policy.ID = ""
+	return f.request(ctx, http.MethodPut, fmt.Sprintf("package_policies/%s", id), policy, nil)
+}
+
+func (f fleetAPI) deletePackagePolicies(ctx context.Context, ids []string) error {
+	return f.request(ctx, http.MethodPost, "package_policies/delete", deletePackagePoliciesRequest{PackagePolicyIDs: ids}, nil)
+}
+
+// agentPolicyID returns the ID of the agent policy managed by ECK for the given Agent.
+func agentPolicyID(agent agentv1alpha1.Agent) string {
+	return fmt.Sprintf("eck-%s-%s", agent.Namespace, agent.Name)
+}
+
+// packagePolicyID returns the ID of the package policy managed by ECK for the given integration of the given Agent.
+// The ID is also used as name of the package policy, since package policy names must be unique across Fleet.
+func packagePolicyID(agent agentv1alpha1.Agent, name string) string {
+	return fmt.Sprintf("%s-%s", agentPolicyID(agent), name)
+}
+
+// downloadSourceID returns the ID of the agent binary download source managed by ECK for the given Agent.
+func downloadSourceID(agent agentv1alpha1.Agent) string {
+	return fmt.Sprintf("eck-%s-%s", agent.Namespace, agent.Name)
+}
+
+// expectedAgentPolicy returns the agent policy declared in the spec of the given Agent.
+func expectedAgentPolicy(agent agentv1alpha1.Agent) AgentPolicyItem {
+	spec := agent.Spec.Policy
+	policy := AgentPolicyItem{
+		ID:                agentPolicyID(agent),
+		Name:              spec.Name,
+		Namespace:         spec.Namespace,
+		Description:       spec.Description,
+		MonitoringEnabled: spec.MonitoringEnabled,
+	}
+	if policy.Name == "" {
+		policy.Name = fmt.Sprintf("%s/%s", agent.Namespace, agent.Name)
+	}
+	if policy.Namespace == "" {
+		policy.Namespace = defaultPolicyNamespace
+	}
+	if policy.MonitoringEnabled == nil {
+		policy.MonitoringEnabled = []string{}
+	}
+	if spec.DataOutputID != "" {
+		policy.DataOutputID = ptr.To(spec.DataOutputID)
+	}
+	if spec.MonitoringOutputID != "" {
+		policy.MonitoringOutputID = ptr.To(spec.MonitoringOutputID)
+	}
+	if agent.Spec.DownloadSourceURI() != "" {
+		policy.DownloadSourceID = ptr.To(downloadSourceID(agent))
+	}
+	return policy
+}
+
+// expectedPackagePolicies returns the package policies declared in the spec of the given Agent.
+func expectedPackagePolicies(agent agentv1alpha1.Agent) []PackagePolicyItem {
+	policies := make([]PackagePolicyItem, 0, len(agent.Spec.Policy.PackagePolicies))
+	for _, spec := range agent.Spec.Policy.PackagePolicies {
+		policy := PackagePolicyItem{
+			ID:        packagePolicyID(agent, spec.Name),
+			Name:      packagePolicyID(agent, spec.Name),
+			Namespace: spec.Namespace,
+			PolicyID:  agentPolicyID(agent),
+			Package:   PackageItem{Name: spec.Package.Name, Version: spec.Package.Version},
+		}
+		if spec.Vars != nil {
+			policy.Vars = spec.Vars.Data
+		}
+		if spec.Inputs != nil {
+			policy.Inputs = spec.Inputs.Data
+		}
+		policies = append(policies, policy)
+	}
+	return policies
+}
+
+// reconcileAgentPolicy creates or updates the agent policy and the package policies declared in the spec of the Agent,
+// deletes the package policies managed by ECK which have been removed from the spec, and returns the ID of the agent
+// policy. Package policies added to the agent policy outside of ECK are left untouched.
+func reconcileAgentPolicy(ctx context.Context, agent agentv1alpha1.Agent, api fleetAPI) (string, error) {
+	log := ulog.FromContext(ctx)
+	// the download source must exist before being referenced by the agent policy
+	if err := reconcileDownloadSource(ctx, agent, api); err != nil {
+		return "", err
+	}
+
+	expected := expectedAgentPolicy(agent)
+	actual, err := api.getAgentPolicy(ctx, expected.ID)
+	switch {
+	case commonhttp.IsNotFound(err):
+		log.Info("Creating agent policy", "id", expected.ID)
+		if err := api.createAgentPolicy(ctx, expected); err != nil {
+			return "", err
+		}
+	case err != nil:
+		return "", err
+	case agentPolicyNeedsUpdate(expected, actual):
+		log.Info("Updating agent policy", "id", expected.ID)
+		if err := api.updateAgentPolicy(ctx, expected); err != nil {
+			return "", err
+		}
+	}
+
+	expectedPackagePolicies := expectedPackagePolicies(agent)
+	for _, expectedPackagePolicy := range expectedPackagePolicies {
+		actual, err := api.getPackagePolicy(ctx, expectedPackagePolicy.ID)
+		switch {
+		case commonhttp.IsNotFound(err):
+			log.Info("Creating package policy", "id", expectedPackagePolicy.ID, "package", expectedPackagePolicy.Package.Name)
+			if err := api.createPackagePolicy(ctx, expectedPackagePolicy); err != nil {
+				return "", err
+			}
+		case err != nil:
+			return "", err
+		case packagePolicyNeedsUpdate(expectedPackagePolicy, actual):
+			log.Info("Updating package policy", "id", expectedPackagePolicy.ID, "package", expectedPackagePolicy.Package.Name)
+			if err := api.updatePackagePolicy(ctx, expectedPackagePolicy); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	// delete the package policies managed by ECK which are not in the spec anymore
+	managedPrefix := agentPolicyID(agent) + "-"
+	removed, err := api.listPackagePolicies(ctx, func(policy PackagePolicyItem) bool {
+		return policy.PolicyID == expected.ID && strings.HasPrefix(policy.ID, managedPrefix) &&
+			!slices.ContainsFunc(expectedPackagePolicies, func(p PackagePolicyItem) bool { return p.ID == policy.ID })
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(removed) > 0 {
+		ids := make([]string, 0, len(removed))
+		for _, policy := range removed {
+			ids = append(ids, policy.ID)
+		}
+		log.Info("Deleting package policies", "ids", ids)
+		if err := api.deletePackagePolicies(ctx, ids); err != nil {
+			return "", err
+		}
+	}
+	return expected.ID, nil
+}
+
+// reconcileDownloadSource registers the artifact mirror declared in the spec of the Agent as an agent binary download
+// source in Fleet, and keeps it in sync with the spec.
+func reconcileDownloadSource(ctx context.Context, agent agentv1alpha1.Agent, api fleetAPI) error {
+	sourceURI := agent.Spec.DownloadSourceURI()
+	if sourceURI == "" {
+		return nil
+	}
+	expected := DownloadSourceItem{
+		ID:   downloadSourceID(agent),
+		Name: fmt.Sprintf("%s/%s", agent.Namespace, agent.Name),
+		Host: sourceURI,
+	}
+	actual, err := api.getDownloadSource(ctx, expected.ID)
+	if err != nil && commonhttp.IsNotFound(err) {
+		ulog.FromContext(ctx).Info("Creating agent binary download source", "id", expected.ID, "host", sourceURI)
+		return api.createDownloadSource(ctx, expected)
+	}
+	if err != nil {
+		return err
+	}
+	// preserve the default flag, users may have chosen to make this source the default one in Kibana
+	expected.IsDefault = actual.IsDefault
+	if actual.Name == expected.Name && actual.Host == expected.Host {
+		return nil
+	}
+	ulog.FromContext(ctx).Info("Updating agent binary download source", "id", expected.ID, "host", sourceURI)
+	return api.updateDownloadSource(ctx, expected)
+}
+
+func agentPolicyNeedsUpdate(expected, actual AgentPolicyItem) bool {
+	sorted := func(values []string) []string {
+		values = slices.Clone(values)
+		slices.Sort(values)
+		return values
+	}
+	return expected.Name != actual.Name ||
+		expected.Namespace != actual.Namespace ||
+		expected.Description != actual.Description ||
+		!slices.Equal(sorted(expected.MonitoringEnabled), sorted(actual.MonitoringEnabled)) ||
+		ptr.Deref(expected.DataOutputID, "") != ptr.Deref(actual.DataOutputID, "") ||
+		ptr.Deref(expected.MonitoringOutputID, "") != ptr.Deref(actual.MonitoringOutputID, "") ||
+		ptr.Deref(expected.DownloadSourceID, "") != ptr.Deref(actual.DownloadSourceID, "")
+}
+
+// packagePolicyNeedsUpdate compares the expected package policy with the one in Fleet. Fleet returns all the variables,
+// inputs and streams of the package with their default values: only the ones specified by the user are compared.
+func packagePolicyNeedsUpdate(expected, actual PackagePolicyItem) bool {
+	return expected.Name != actual.Name ||
+		expected.PolicyID != actual.PolicyID ||
+		(expected.Namespace != "" && expected.Namespace != actual.Namespace) ||
+		expected.Package != actual.Package ||
+		!isSubset(expected.Vars, actual.Vars) ||
+		!isSubset(expected.Inputs, actual.Inputs)
+}
+
+// isSubset returns true if all the keys of expected exist in actual with the same values, recursively.
+func isSubset(expected, actual map[string]interface{}) bool {
+	for key, expectedValue := range expected {
+		actualValue, exists := actual[key]
+		if !exists {
+			return false
+		}
+		expectedMap, expectedIsMap := expectedValue.(map[string]interface{})
+		actualMap, actualIsMap := actualValue.(map[string]interface{})
+		if expectedIsMap && actualIsMap {
+			if !isSubset(expectedMap, actualMap) {
+				return false
+			}
+			continue
+		}
+		if !reflect.DeepEqual(expectedValue, actualValue) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,157 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/apis/agent/v1alpha1"
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+var (
+	agentPolicySample           = `{"item":{"id":"eck-ns-agent","name":"ns/agent","namespace":"default","description":"","monitoring_enabled":["metrics","logs"],"data_output_id":"logstash","monitoring_output_id":null,"revision":3,"status":"active"}}`
+	packagePolicySample         = `{"item":{"id":"eck-ns-agent-system","name":"eck-ns-agent-system","namespace":"","policy_id":"eck-ns-agent","package":{"name":"system","version":"1.60.0"},"inputs":{"system-logfile":{"enabled":true,"streams":{"system.auth":{"enabled":true,"vars":{"paths":["/var/log/auth.log*"]}}}},"system-system/metrics":{"enabled":true}}}}`
+	packagePolicyListSample     = `{"items":[{"id":"eck-ns-agent-system","policy_id":"eck-ns-agent"},{"id":"out-of-band","policy_id":"eck-ns-agent"},{"id":"eck-ns-agent-old","policy_id":"eck-ns-agent"},{"id":"eck-ns-agent-other","policy_id":"other"}],"total":4,"page":1,"perPage":100}`
+	packagePolicyListUpToDate   = `{"items":[{"id":"eck-ns-agent-system","policy_id":"eck-ns-agent"},{"id":"out-of-band","policy_id":"eck-ns-agent"}],"total":2,"page":1,"perPage":100}`
+	outdatedPackagePolicySample = `{"item":{"id":"eck-ns-agent-system","name":"eck-ns-agent-system","namespace":"","policy_id":"eck-ns-agent","package":{"name":"system","version":"1.59.0"},"inputs":{"system-logfile":{"enabled":true}}}}`
+	agentPolicyWithDownload     = `{"item":{"id":"eck-ns-agent","name":"ns/agent","namespace":"default","description":"","monitoring_enabled":["metrics","logs"],"data_output_id":"logstash","monitoring_output_id":null,"download_source_id":"eck-ns-agent","revision":4,"status":"active"}}`
+	downloadSourceSample        = `{"item":{"id":"eck-ns-agent","name":"ns/agent","host":"https://artifacts.example.com/downloads/","is_default":false}}`
+	outdatedDownloadSource      = `{"item":{"id":"eck-ns-agent","name":"ns/agent","host":"https://old.example.com/downloads/","is_default":true}}`
+)
+
+func Test_reconcileAgentPolicy(t *testing.T) {
+	inputs := commonv1.NewConfig(map[string]interface{}{
+		"system-logfile": map[string]interface{}{
+			"streams": map[string]interface{}{
+				"system.auth": map[string]interface{}{"vars": map[string]interface{}{"paths": []interface{}{"/var/log/auth.log*"}}},
+			},
+		},
+	})
+	agent := v1alpha1.Agent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "ns"},
+		Spec: v1alpha1.AgentSpec{
+			Mode: v1alpha1.AgentFleetMode,
+			Policy: &v1alpha1.AgentPolicy{
+				MonitoringEnabled: []string{"logs", "metrics"},
+				DataOutputID:      "logstash",
+				PackagePolicies: []v1alpha1.PackagePolicy{{
+					Name:    "system",
+					Package: v1alpha1.PackageReference{Name: "system", Version: "1.60.0"},
+					Inputs:  &inputs,
+				}},
+			},
+		},
+	}
+	outdatedAgent := *agent.DeepCopy()
+	outdatedAgent.Spec.Policy.Description = "updated"
+	agentWithDownload := *agent.DeepCopy()
+	agentWithDownload.Spec.Download = &v1alpha1.AgentDownloadSpec{SourceURI: "https://artifacts.example.com/downloads/"}
+
+	tests := []struct {
+		name    string
+		agent   v1alpha1.Agent
+		api     *mockFleetAPI
+		wantErr bool
+	}{
+		{
+			name:  "agent policy and package policy do not exist yet",
+			agent: agent,
+			api: mockFleetResponses(map[request]response{
+				{"GET", "/api/fleet/agent_policies/eck-ns-agent"}:          {code: 404},
+				{"POST", "/api/fleet/agent_policies"}:                      {code: 200},
+				{"GET", "/api/fleet/package_policies/eck-ns-agent-system"}: {code: 404},
+				{"POST", "/api/fleet/package_policies"}:                    {code: 200},
+				{"GET", "/api/fleet/package_policies"}:                     {code: 200, body: emptyList},
+			}),
+		},
+		{
+			name:  "agent policy and package policy up to date, out-of-band package policy left untouched",
+			agent: agent,
+			api: mockFleetResponses(map[request]response{
+				{"GET", "/api/fleet/agent_policies/eck-ns-agent"}:          {code: 200, body: agentPolicySample},
+				{"GET", "/api/fleet/package_policies/eck-ns-agent-system"}: {code: 200, body: packagePolicySample},
+				{"GET", "/api/fleet/package_policies"}:                     {code: 200, body: packagePolicyListUpToDate},
+			}),
+		},
+		{
+			name:  "outdated agent policy and package policy, package policy removed from the spec",
+			agent: outdatedAgent,
+			api: mockFleetResponses(map[request]response{
+				{"GET", "/api/fleet/agent_policies/eck-ns-agent"}:          {code: 200, body: agentPolicySample},
+				{"PUT", "/api/fleet/agent_policies/eck-ns-agent"}:          {code: 200},
+				{"GET", "/api/fleet/package_policies/eck-ns-agent-system"}: {code: 200, body: outdatedPackagePolicySample},
+				{"PUT", "/api/fleet/package_policies/eck-ns-agent-system"}: {code: 200},
+				{"GET", "/api/fleet/package_policies"}:                     {code: 200, body: packagePolicyListSample},
+				{"POST", "/api/fleet/package_policies/delete"}:             {code: 200},
+			}),
+		},
+		{
+			name:  "download source does not exist yet, agent policy updated to use it",
+			agent: agentWithDownload,
+			api: mockFleetResponses(map[request]response{
+				{"GET", "/api/fleet/agent_download_sources/eck-ns-agent"}:  {code: 404},
+				{"POST", "/api/fleet/agent_download_sources"}:              {code: 200},
+				{"GET", "/api/fleet/agent_policies/eck-ns-agent"}:          {code: 200, body: agentPolicySample},
+				{"PUT", "/api/fleet/agent_policies/eck-ns-agent"}:          {code: 200},
+				{"GET", "/api/fleet/package_policies/eck-ns-agent-system"}: {code: 200, body: packagePolicySample},
+				{"GET", "/api/fleet/package_policies"}:                     {code: 200, body: packagePolicyListUpToDate},
+			}),
+		},
+		{
+			name:  "download source and agent policy up to date",
+			agent: agentWithDownload,
+			api: mockFleetResponses(map[request]response{
+				{"GET", "/api/fleet/agent_download_sources/eck-ns-agent"}:  {code: 200, body: downloadSourceSample},
+				{"GET", "/api/fleet/agent_policies/eck-ns-agent"}:          {code: 200, body: agentPolicyWithDownload},
+				{"GET", "/api/fleet/package_policies/eck-ns-agent-system"}: {code: 200, body: packagePolicySample},
+				{"GET", "/api/fleet/package_policies"}:                     {code: 200, body: packagePolicyListUpToDate},
+			}),
+		},
+		{
+			name:  "outdated download source",
+			agent: agentWithDownload,
+			api: mockFleetResponses(map[request]response{
+				{"GET", "/api/fleet/agent_download_sources/eck-ns-agent"}:  {code: 200, body: outdatedDownloadSource},
+				{"PUT", "/api/fleet/agent_download_sources/eck-ns-agent"}:  {code: 200},
+				{"GET", "/api/fleet/agent_policies/eck-ns-agent"}:          {code: 200, body: agentPolicyWithDownload},
+				{"GET", "/api/fleet/package_policies/eck-ns-agent-system"}: {code: 200, body: packagePolicySample},
+				{"GET", "/api/fleet/package_policies"}:                     {code: 200, body: packagePolicyListUpToDate},
+			}),
+		},
+		{
+			name:  "download source removed from the spec, agent policy updated to use the default one",
+			agent: agent,
+			api: mockFleetResponses(map[request]response{
+				{"GET", "/api/fleet/agent_policies/eck-ns-agent"}:          {code: 200, body: agentPolicyWithDownload},
+				{"PUT", "/api/fleet/agent_policies/eck-ns-agent"}:          {code: 200},
+				{"GET", "/api/fleet/package_policies/eck-ns-agent-system"}: {code: 200, body: packagePolicySample},
+				{"GET", "/api/fleet/package_policies"}:                     {code: 200, body: packagePolicyListUpToDate},
+			}),
+		},
+		{
+			name:  "Fleet API error",
+			agent: agent,
+			api: mockFleetResponses(map[request]response{
+				{"GET", "/api/fleet/agent_policies/eck-ns-agent"}: {code: 500},
+			}),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := reconcileAgentPolicy(context.Background(), tt.agent, tt.api.fleetAPI)
+			require.Empty(t, tt.api.missingRequests())
+			require.Equal(t, tt.wantErr, err != nil, "reconcileAgentPolicy() error = %v", err)
+			if !tt.wantErr {
+				require.Equal(t, "eck-ns-agent", id)
+			}
+		})
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/daemonset"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/deployment"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/effectivespec"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/statefulset"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
@@ -136,6 +137,10 @@ func reconcileDeployment(rp ReconciliationParams) (int32, int32, error) {
 		return 0, 0, err
 	}
 
+	if err := effectivespec.Reconcile(rp.ctx, rp.client, &rp.agent, Namer.Suffix(rp.agent.Name, effectivespec.Suffix), rp.agent.Spec, &d); err != nil {
+		return 0, 0, err
+	}
+
 	reconciled, err := deployment.Reconcile(rp.ctx, rp.client, d, &rp.agent)
 	if err != nil {
 		return 0, 0, err
@@ -161,6 +166,10 @@ func reconcileStatefulSet(rp ReconciliationParams) (int32, int32, error) {
 		return 0, 0, err
 	}
 
+	if err := effectivespec.Reconcile(rp.ctx, rp.client, &rp.agent, Namer.Suffix(rp.agent.Name, effectivespec.Suffix), rp.agent.Spec, &d); err != nil {
+		return 0, 0, err
+	}
+
 	reconciled, err := statefulset.Reconcile(rp.ctx, rp.client, d, &rp.agent)
 	if err != nil {
 		return 0, 0, err
@@ -184,6 +193,10 @@ func reconcileDaemonSet(rp ReconciliationParams) (int32, int32, error) {
 		return 0, 0, err
 	}
 
+	if err := effectivespec.Reconcile(rp.ctx, rp.client, &rp.agent, Namer.Suffix(rp.agent.Name, effectivespec.Suffix), rp.agent.Spec, &ds); err != nil {
+		return 0, 0, err
+	}
+
 	reconciled, err := daemonset.Reconcile(rp.ctx, rp.client, ds, &rp.agent)
 	if err != nil {
 		return 0, 0, err
@@ -6,6 +6,7 @@ package apmserver
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"path"
 	"path/filepath"
@@ -123,16 +124,24 @@ func newElasticsearchConfigFromSpec(ctx context.Context, c k8s.Client, esAssocia
 		return settings.NewCanonicalConfig(), nil
 	}
 
-	// Get username and password
+	// Get the username and password, or the API key
 	credentials, err := association.ElasticsearchAuthSettings(ctx, c, &esAssociation)
 	if err != nil {
 		return nil, err
 	}
 
 	tmpOutputCfg := map[string]interface{}{
-		"output.elasticsearch.hosts":    []string{esAssocConf.GetURL()},
-		"output.elasticsearch.username": credentials.Username,
-		"output.elasticsearch.password": credentials.Password,
+		"output.elasticsearch.hosts": []string{esAssocConf.GetURL()},
+	}
+	if credentials.APIKey != "" {
+		decodedAPIKey, err := base64.StdEncoding.DecodeString(credentials.APIKey)
+		if err != nil {
+			return nil, fmt.Errorf("error at decoding apikey from secret %s: %w", esAssocConf.AuthSecretName, err)
+		}
+		tmpOutputCfg["output.elasticsearch.api_key"] = string(decodedAPIKey)
+	} else {
+		tmpOutputCfg["output.elasticsearch.username"] = credentials.Username
+		tmpOutputCfg["output.elasticsearch.password"] = credentials.Password
 	}
 	if esAssocConf.GetCACertProvided() {
 		tmpOutputCfg["output.elasticsearch.ssl.certificate_authorities"] = []string{filepath.Join(certificatesDir(esAssociation.AssociationType()), certificates.CAFileName)}
@@ -170,7 +179,7 @@ func newKibanaConfigFromSpec(ctx context.Context, c k8s.Client, kibanaAssociatio
 }
 
 func tlsSettings(as *apmv1.ApmServer) map[string]interface{} {
-	if !as.Spec.HTTP.TLS.Enabled() {
+	if !as.Spec.HTTP.TLSEnabled() {
 		return nil
 	}
 	return map[string]interface{}{
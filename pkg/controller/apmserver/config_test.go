@@ -6,6 +6,7 @@ package apmserver
 
 import (
 	"context"
+	"encoding/base64"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -90,6 +91,24 @@ func TestNewConfigFromSpec(t *testing.T) {
 			},
 			version: version.MinFor(8, 0, 0),
 		},
+		{
+			name: "with an API key to an unmanaged Elasticsearch",
+			esAssocConf: &commonv1.AssociationConf{
+				AuthSecretName: "external-es-ref",
+				AuthSecretKey:  "api-key",
+				IsAPIKey:       true,
+				CASecretName:   "external-es-ref",
+				CACertProvided: true,
+				URL:            "https://es.example.com:9243",
+			},
+			wantConf: map[string]interface{}{
+				"apm-server.auth.secret_token":                     "${SECRET_TOKEN}",
+				"output.elasticsearch.hosts":                       []string{"https://es.example.com:9243"},
+				"output.elasticsearch.api_key":                     "id:key",
+				"output.elasticsearch.ssl.certificate_authorities": []string{"config/elasticsearch-certs/ca.crt"},
+			},
+			version: version.MinFor(8, 0, 0),
+		},
 		{
 			name: "missing auth secret",
 			esAssocConf: &commonv1.AssociationConf{
@@ -209,6 +228,15 @@ func mkAuthSecrets() []client.Object {
 				"elastic": []byte("password"),
 			},
 		},
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "external-es-ref",
+			},
+			Data: map[string][]byte{
+				"url":     []byte("https://es.example.com:9243"),
+				"api-key": []byte(base64.StdEncoding.EncodeToString([]byte("id:key"))),
+			},
+		},
 		&v1.Secret{
 			ObjectMeta: metav1.ObjectMeta{
 				Name: "test-kb-elastic-user",
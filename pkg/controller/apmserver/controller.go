@@ -32,6 +32,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/driver"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/finalizer"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/gateway"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/labels"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
@@ -41,6 +42,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
 )
 
 const (
@@ -190,7 +192,10 @@ func (r *ReconcileApmServer) Reconcile(ctx context.Context, request reconcile.Re
 
 	if common.IsUnmanaged(ctx, &as) {
 		log.Info("Object currently not managed by this controller. Skipping reconciliation", "namespace", as.Namespace, "as_name", as.Name)
-		return reconcile.Result{}, nil
+		return reconcile.Result{}, tracing.CaptureError(ctx, common.ReconcilePausedCondition(ctx, r.Client, &as, &as.Status.Conditions, true))
+	}
+	if err := common.ReconcilePausedCondition(ctx, r.Client, &as, &as.Status.Conditions, false); err != nil {
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
 	}
 
 	// Remove any previous finalizer used in ECK v1.0.0-beta1 that we don't need anymore
@@ -204,6 +209,7 @@ func (r *ReconcileApmServer) Reconcile(ctx context.Context, request reconcile.Re
 	}
 
 	results, state := r.doReconcile(ctx, &as)
+	common.UpdateStandardConditions(&state.ApmServer.Status.Conditions, state.ApmServer.Status.ResourceState(as.Generation, as.Spec.Version), results)
 
 	return results.WithError(r.updateStatus(ctx, state)).Aggregate()
 }
@@ -229,12 +235,15 @@ func (r *ReconcileApmServer) doReconcile(ctx context.Context, as *apmv1.ApmServe
 	if err != nil {
 		return results.WithError(err), state
 	}
+	if err := gateway.ReconcileRoute(ctx, r.Client, as, as.Spec.HTTP, *svc, as.GetIdentityLabels()); err != nil {
+		return results.WithError(err), state
+	}
 
 	_, results = certificates.Reconciler{
 		K8sClient:             r.K8sClient(),
 		DynamicWatches:        r.DynamicWatches(),
 		Owner:                 as,
-		TLSOptions:            as.Spec.HTTP.TLS,
+		TLSOptions:            as.Spec.HTTP.EffectiveTLS(),
 		Namer:                 Namer,
 		Labels:                as.GetIdentityLabels(),
 		Services:              []corev1.Service{*svc},
@@ -273,6 +282,7 @@ func (r *ReconcileApmServer) doReconcile(ctx context.Context, as *apmv1.ApmServe
 	}
 
 	state.UpdateApmServerExternalService(*svc)
+	results.WithReconciliationState(keystore.RefreshResult(as))
 
 	_, err = results.WithError(err).Aggregate()
 	k8s.MaybeEmitErrorEvent(r.recorder, err, as, events.EventReconciliationError, "Reconciliation error: %v", err)
@@ -293,6 +303,7 @@ func (r *ReconcileApmServer) validate(ctx context.Context, as *apmv1.ApmServer)
 }
 
 func (r *ReconcileApmServer) onDelete(ctx context.Context, obj types.NamespacedName) error {
+	metrics.DeleteResourceMetrics(ctx, apmv1.Kind, obj)
 	// Clean up watches set on secure settings
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(keystore.SecureSettingsWatchName(obj))
 	// Clean up watches set on custom http tls certificates
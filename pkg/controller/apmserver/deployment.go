@@ -17,6 +17,7 @@ import (
 	apmv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/apm/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/deployment"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/effectivespec"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
@@ -65,6 +66,9 @@ func (r *ReconcileApmServer) reconcileApmServerDeployment(ctx context.Context, s
 	}
 
 	deploy := deployment.New(params)
+	if err := effectivespec.Reconcile(ctx, r.K8sClient(), as, Namer.Suffix(as.Name, effectivespec.Suffix), as.Spec, &deploy); err != nil {
+		return state, err
+	}
 	result, err := deployment.Reconcile(ctx, r.K8sClient(), deploy, as)
 	if err != nil {
 		return state, err
@@ -114,7 +118,7 @@ func buildConfigHash(c k8s.Client, as *apmv1.ApmServer, params PodSpecParams) (s
 	}
 
 	// - in the APMServer TLS certificates
-	if as.Spec.HTTP.TLS.Enabled() {
+	if as.Spec.HTTP.TLSEnabled() {
 		var tlsCertSecret corev1.Secret
 		tlsSecretKey := types.NamespacedName{Namespace: as.Namespace, Name: certificates.InternalCertsSecretName(Namer, as.Name)}
 		if err := c.Get(context.Background(), tlsSecretKey, &tlsCertSecret); err != nil {
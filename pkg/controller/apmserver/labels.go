@@ -7,6 +7,8 @@ package apmserver
 const (
 	// ApmServerNameLabelName used to represent an ApmServer in k8s resources
 	ApmServerNameLabelName = "apm.k8s.elastic.co/name"
+	// ApmServerNamespaceLabelName used to represent an ApmServer namespace in k8s resources
+	ApmServerNamespaceLabelName = "apm.k8s.elastic.co/namespace"
 	// Type represents the apm server type
 	Type = "apm-server"
 	// APMVersionLabelName used to propagate APMServer version from the spec to the pods
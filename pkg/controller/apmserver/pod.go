@@ -135,13 +135,16 @@ func newPodSpec(c k8s.Client, as *apmv1.ApmServer, p PodSpecParams) (corev1.PodT
 	if err != nil {
 		return corev1.PodTemplateSpec{}, err // error unlikely and should have been caught during validation
 	}
+	if err := container.CheckImageCatalog(p.CustomImageName, container.APMServerImage, v); err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
 
 	builder := defaults.NewPodTemplateBuilder(p.PodTemplate, apmv1.ApmServerContainerName).
 		WithLabels(labels).
 		WithAnnotations(annotations).
 		WithResources(DefaultResources).
 		WithDockerImage(p.CustomImageName, container.ImageRepository(container.APMServerImage, v)).
-		WithReadinessProbe(readinessProbe(as.Spec.HTTP.TLS.Enabled())).
+		WithReadinessProbe(readinessProbe(as.Spec.HTTP.TLSEnabled())).
 		WithPorts(ports).
 		WithCommand(command).
 		WithEnv(env...).
@@ -155,7 +158,7 @@ func newPodSpec(c k8s.Client, as *apmv1.ApmServer, p PodSpecParams) (corev1.PodT
 	}
 	builder = withHTTPCertsVolume(builder, *as)
 
-	return builder.WithInitContainerDefaults().PodTemplate, nil
+	return builder.WithInitContainerDefaults().WithServiceMesh(as.Spec.HTTP.ServiceMesh).WithPodMutationPolicy(apmv1.Kind, as.Namespace).PodTemplate, nil
 }
 
 func getDefaultContainerPorts(as apmv1.ApmServer) []corev1.ContainerPort {
@@ -163,7 +166,7 @@ func getDefaultContainerPorts(as apmv1.ApmServer) []corev1.ContainerPort {
 }
 
 func withHTTPCertsVolume(builder *defaults.PodTemplateBuilder, as apmv1.ApmServer) *defaults.PodTemplateBuilder {
-	if !as.Spec.HTTP.TLS.Enabled() {
+	if !as.Spec.HTTP.TLSEnabled() {
 		return builder
 	}
 	vol := certificates.HTTPCertSecretVolume(Namer, as.Name)
@@ -5,6 +5,7 @@
 package association
 
 import (
+	"bytes"
 	"context"
 
 	corev1 "k8s.io/api/core/v1"
@@ -16,6 +17,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/name"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/trustbundle"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 )
 
@@ -32,7 +34,8 @@ func CACertSecretName(association commonv1.Association, associationName string)
 	return commonv1.FormatNameWithID(associatedName+"-"+associationName+"%s-ca", association.AssociationID())
 }
 
-// ReconcileCASecret keeps in sync a copy of the target service CA.
+// ReconcileCASecret keeps in sync a copy of the target service CA, extended with the certificates of the
+// ClusterTrustBundles selected by the operator configuration if any.
 // It is the responsibility of the association controller to set a watch on this CA.
 func (r *Reconciler) ReconcileCASecret(ctx context.Context, association commonv1.Association, namer name.Namer, associatedResource types.NamespacedName) (CASecret, error) {
 	associatedPublicHTTPCertificatesNSN := certificates.PublicCertsSecretRef(namer, associatedResource)
@@ -46,6 +49,11 @@ func (r *Reconciler) ReconcileCASecret(ctx context.Context, association commonv1
 		return CASecret{}, err
 	}
 
+	data, err := r.withTrustedCertificates(ctx, associatedPublicHTTPCertificatesSecret.Data)
+	if err != nil {
+		return CASecret{}, err
+	}
+
 	labels := r.AssociationResourceLabels(k8s.ExtractNamespacedName(association), association.AssociationRef().NamespacedName())
 	// Certificate data should be copied over a secret in the association namespace
 	expectedSecret := corev1.Secret{
@@ -54,7 +62,7 @@ func (r *Reconciler) ReconcileCASecret(ctx context.Context, association commonv1
 			Name:      CACertSecretName(association, r.AssociationName),
 			Labels:    labels,
 		},
-		Data: associatedPublicHTTPCertificatesSecret.Data,
+		Data: data,
 	}
 	if _, err := reconciler.ReconcileSecret(ctx, r, expectedSecret, association.Associated()); err != nil {
 		return CASecret{}, err
@@ -63,3 +71,22 @@ func (r *Reconciler) ReconcileCASecret(ctx context.Context, association commonv1
 	caCertProvided := len(expectedSecret.Data[certificates.CAFileName]) > 0
 	return CASecret{Name: expectedSecret.Name, CACertProvided: caCertProvided}, nil
 }
+
+// withTrustedCertificates returns a copy of the given public certificates data in which the certificates of the
+// ClusterTrustBundles selected by the operator configuration are appended to the CA certificate.
+func (r *Reconciler) withTrustedCertificates(ctx context.Context, data map[string][]byte) (map[string][]byte, error) {
+	trusted, err := trustbundle.TrustedCertificates(ctx, r.Client, r.AssociationTrustBundleSelector)
+	if err != nil || len(trusted) == 0 {
+		return data, err
+	}
+	withTrusted := make(map[string][]byte, len(data)+1)
+	for k, v := range data {
+		withTrusted[k] = v
+	}
+	ca := bytes.TrimSpace(data[certificates.CAFileName])
+	if len(ca) > 0 {
+		ca = append(append([]byte{}, ca...), '\n')
+	}
+	withTrusted[certificates.CAFileName] = append(ca, trusted...)
+	return withTrusted, nil
+}
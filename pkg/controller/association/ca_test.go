@@ -9,8 +9,10 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	certificatesv1alpha1 "k8s.io/api/certificates/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
@@ -113,14 +115,42 @@ func TestReconcileAssociation_reconcileCASecret(t *testing.T) {
 			certificates.CAFileName:   {},
 		},
 	}
+	trustedBundle := func(name, pem string, labels map[string]string) *certificatesv1alpha1.ClusterTrustBundle {
+		return &certificatesv1alpha1.ClusterTrustBundle{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+			Spec:       certificatesv1alpha1.ClusterTrustBundleSpec{TrustBundle: pem},
+		}
+	}
+	trusted := map[string]string{"trusted": "true"}
+	kibanaTrustedEsCA := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: es.Namespace,
+			Name:      CACertSecretName(kibanaFixture.EsAssociation(), kibanaESAssociationName),
+		},
+		Data: map[string][]byte{
+			certificates.CertFileName: []byte("fake-cert"),
+			certificates.CAFileName:   []byte("fake-ca-cert\nbundle-a\nbundle-b\n"),
+		},
+	}
+	kibanaTrustedEmptyEsCA := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: es.Namespace,
+			Name:      CACertSecretName(kibanaFixture.EsAssociation(), kibanaESAssociationName),
+		},
+		Data: map[string][]byte{
+			certificates.CertFileName: []byte("fake-cert"),
+			certificates.CAFileName:   []byte("bundle-a\n"),
+		},
+	}
 	tests := []struct {
-		name               string
-		client             k8s.Client
-		kibana             kbv1.Kibana
-		es                 esv1.Elasticsearch
-		want               string
-		wantCA             *corev1.Secret
-		wantCACertProvided bool
+		name                string
+		client              k8s.Client
+		trustBundleSelector labels.Selector
+		kibana              kbv1.Kibana
+		es                  esv1.Elasticsearch
+		want                string
+		wantCA              *corev1.Secret
+		wantCACertProvided  bool
 	}{
 		{
 			name:               "create new CA in kibana namespace",
@@ -159,6 +189,30 @@ func TestReconcileAssociation_reconcileCASecret(t *testing.T) {
 			wantCA:             &kibanaEmptyEsCA,
 			wantCACertProvided: false,
 		},
+		{
+			name: "trusted cluster trust bundles are appended to the CA",
+			client: k8s.NewFakeClient(&es, &esCA,
+				trustedBundle("b", "bundle-b\n", trusted),
+				trustedBundle("a", "bundle-a", trusted),
+				trustedBundle("c", "not-trusted", nil),
+			),
+			trustBundleSelector: labels.SelectorFromSet(trusted),
+			kibana:              kibanaFixture,
+			es:                  esFixture,
+			want:                CACertSecretName(kibanaFixture.EsAssociation(), kibanaESAssociationName),
+			wantCA:              &kibanaTrustedEsCA,
+			wantCACertProvided:  true,
+		},
+		{
+			name:                "trusted cluster trust bundles are used as CA if the ES CA is empty",
+			client:              k8s.NewFakeClient(&es, &esEmptyCA, trustedBundle("a", "bundle-a", trusted)),
+			trustBundleSelector: labels.SelectorFromSet(trusted),
+			kibana:              kibanaFixture,
+			es:                  esFixture,
+			want:                CACertSecretName(kibanaFixture.EsAssociation(), kibanaESAssociationName),
+			wantCA:              &kibanaTrustedEmptyEsCA,
+			wantCACertProvided:  true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -173,7 +227,7 @@ func TestReconcileAssociation_reconcileCASecret(t *testing.T) {
 				},
 				Client:     tt.client,
 				watches:    watches.DynamicWatches{},
-				Parameters: operator.Parameters{},
+				Parameters: operator.Parameters{AssociationTrustBundleSelector: tt.trustBundleSelector},
 			}
 
 			// re-use the one used for ES association, but it could be anything else
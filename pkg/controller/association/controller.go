@@ -5,16 +5,27 @@
 package association
 
 import (
+	"context"
+
+	certificatesv1alpha1 "k8s.io/api/certificates/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/rbac"
 )
 
@@ -65,6 +76,55 @@ func addWatches(mgr manager.Manager, c controller.Controller, r *Reconciler) err
 		return err
 	}
 
+	// Watch the trusted ClusterTrustBundles, whose certificates are copied in the CA Secrets of all the associations
+	if r.AssociationTrustBundleSelector != nil {
+		if err := c.Watch(source.Kind(mgr.GetCache(), &certificatesv1alpha1.ClusterTrustBundle{}, handler.TypedEnqueueRequestsFromMapFunc(
+			func(ctx context.Context, bundle *certificatesv1alpha1.ClusterTrustBundle) []reconcile.Request {
+				if !r.AssociationTrustBundleSelector.Matches(labels.Set(bundle.Labels)) {
+					return nil
+				}
+				return r.allAssociatedRequests(ctx, mgr.GetScheme())
+			},
+		))); err != nil {
+			return err
+		}
+	}
+
 	// Dynamically watch Service objects for custom services setup by the user
 	return c.Watch(source.Kind(mgr.GetCache(), &corev1.Service{}, r.watches.Services))
 }
+
+// allAssociatedRequests returns a reconcile request for each associated resource.
+func (r *Reconciler) allAssociatedRequests(ctx context.Context, scheme *runtime.Scheme) []reconcile.Request {
+	log := ulog.FromContext(ctx)
+	gvk, err := apiutil.GVKForObject(r.AssociatedObjTemplate(), scheme)
+	if err != nil {
+		log.Error(err, "Failed to determine the kind of the associated resources")
+		return nil
+	}
+	obj, err := scheme.New(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err != nil {
+		log.Error(err, "Failed to create the list of associated resources", "kind", gvk.Kind)
+		return nil
+	}
+	list, ok := obj.(client.ObjectList)
+	if !ok {
+		return nil
+	}
+	if err := r.Client.List(ctx, list); err != nil {
+		log.Error(err, "Failed to list the associated resources", "kind", gvk.Kind)
+		return nil
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		log.Error(err, "Failed to extract the associated resources", "kind", gvk.Kind)
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(items))
+	for _, item := range items {
+		if o, ok := item.(client.Object); ok {
+			requests = append(requests, reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(o)})
+		}
+	}
+	return requests
+}
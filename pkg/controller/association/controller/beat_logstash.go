@@ -0,0 +1,86 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	beatv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/beat/v1beta1"
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	logstashv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/association"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	lslabels "github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash/labels"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/rbac"
+)
+
+func AddBeatLogstash(mgr manager.Manager, accessReviewer rbac.AccessReviewer, params operator.Parameters) error {
+	return association.AddAssociationController(mgr, accessReviewer, params, association.AssociationInfo{
+		AssociatedObjTemplate:     func() commonv1.Associated { return &beatv1beta1.Beat{} },
+		ReferencedObjTemplate:     func() client.Object { return &logstashv1alpha1.Logstash{} },
+		ExternalServiceURL:        getLogstashExternalURL,
+		ReferencedResourceVersion: referencedLogstashStatusVersion,
+		ReferencedResourceNamer:   logstashv1alpha1.Namer,
+		AssociationName:           "beat-ls",
+		AssociatedShortName:       "beat",
+		AssociationType:           commonv1.LogstashAssociationType,
+		Labels: func(associated types.NamespacedName) map[string]string {
+			return map[string]string{
+				BeatAssociationLabelName:      associated.Name,
+				BeatAssociationLabelNamespace: associated.Namespace,
+				BeatAssociationLabelType:      commonv1.LogstashAssociationType,
+			}
+		},
+		AssociationConfAnnotationNameBase:     commonv1.LogstashConfigAnnotationNameBase,
+		AssociationResourceNameLabelName:      lslabels.NameLabelName,
+		AssociationResourceNamespaceLabelName: lslabels.NamespaceLabelName,
+
+		// the Beats input of Logstash does not support authentication
+		ElasticsearchUserCreation: nil,
+	})
+}
+
+// getLogstashExternalURL returns the address of the Logstash Service referenced in the association, in the host:port
+// form expected by the Logstash output of the Beats. As Logstash Services are defined by the user, the first port of
+// the Service is expected to be the one of the Beats input.
+func getLogstashExternalURL(c k8s.Client, assoc commonv1.Association) (string, error) {
+	lsRef := assoc.AssociationRef()
+	if !lsRef.IsDefined() {
+		return "", nil
+	}
+	if lsRef.ServiceName == "" {
+		return "", fmt.Errorf("serviceName must be set in the Logstash reference %s", lsRef.NamespacedName())
+	}
+	var svc corev1.Service
+	if err := c.Get(context.Background(), types.NamespacedName{Namespace: lsRef.Namespace, Name: lsRef.ServiceName}, &svc); err != nil {
+		return "", err
+	}
+	if len(svc.Spec.Ports) == 0 {
+		return "", fmt.Errorf("service %s/%s does not expose any port", svc.Namespace, svc.Name)
+	}
+	return fmt.Sprintf("%s.%s.svc:%d", svc.Name, svc.Namespace, svc.Spec.Ports[0].Port), nil
+}
+
+// referencedLogstashStatusVersion returns the currently running version of Logstash reported in its status.
+func referencedLogstashStatusVersion(c k8s.Client, lsAssociation commonv1.Association) (string, bool, error) {
+	lsRef := lsAssociation.AssociationRef()
+	if lsRef.IsExternal() {
+		// the Beats input does not expose the version of Logstash
+		return association.UnknownVersion, false, nil
+	}
+
+	var ls logstashv1alpha1.Logstash
+	if err := c.Get(context.Background(), lsRef.NamespacedName(), &ls); err != nil {
+		return "", false, err
+	}
+	return ls.Status.Version, false, nil
+}
@@ -0,0 +1,93 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package controller
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	apmv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/apm/v1"
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	otelv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/otel/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/apmserver"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/association"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/rbac"
+)
+
+func AddOTelApm(mgr manager.Manager, accessReviewer rbac.AccessReviewer, params operator.Parameters) error {
+	return association.AddAssociationController(mgr, accessReviewer, params, association.AssociationInfo{
+		AssociatedObjTemplate:     func() commonv1.Associated { return &otelv1alpha1.OpenTelemetryCollector{} },
+		ReferencedObjTemplate:     func() client.Object { return &apmv1.ApmServer{} },
+		ExternalServiceURL:        getApmServerExternalURL,
+		ReferencedResourceVersion: referencedApmServerStatusVersion,
+		ReferencedResourceNamer:   apmserver.Namer,
+		AssociationName:           "otel-apm",
+		AssociatedShortName:       "otel",
+		AssociationType:           commonv1.ApmServerAssociationType,
+		// the secret token of the APM Server is copied to the namespace of the collector
+		AdditionalSecrets: apmServerSecretToken,
+		Labels: func(associated types.NamespacedName) map[string]string {
+			return map[string]string{
+				OTelAssociationLabelName:      associated.Name,
+				OTelAssociationLabelNamespace: associated.Namespace,
+				OTelAssociationLabelType:      commonv1.ApmServerAssociationType,
+			}
+		},
+		AssociationConfAnnotationNameBase:     commonv1.ApmServerConfigAnnotationNameBase,
+		AssociationResourceNameLabelName:      apmserver.ApmServerNameLabelName,
+		AssociationResourceNamespaceLabelName: apmserver.ApmServerNamespaceLabelName,
+
+		// the collector authenticates to the APM Server with its secret token
+		ElasticsearchUserCreation: nil,
+	})
+}
+
+func apmServerSecretToken(_ context.Context, _ k8s.Client, assoc commonv1.Association) ([]types.NamespacedName, error) {
+	apmRef := assoc.AssociationRef()
+	if !apmRef.IsDefined() || apmRef.IsExternal() {
+		return nil, nil
+	}
+	return []types.NamespacedName{{
+		Namespace: apmRef.Namespace,
+		Name:      apmserver.SecretToken(apmRef.Name),
+	}}, nil
+}
+
+func getApmServerExternalURL(c k8s.Client, assoc commonv1.Association) (string, error) {
+	apmRef := assoc.AssociationRef()
+	if !apmRef.IsDefined() {
+		return "", nil
+	}
+	as := apmv1.ApmServer{}
+	if err := c.Get(context.Background(), apmRef.NamespacedName(), &as); err != nil {
+		return "", err
+	}
+	serviceName := apmRef.ServiceName
+	if serviceName == "" {
+		serviceName = apmserver.HTTPService(as.Name)
+	}
+	nsn := types.NamespacedName{Namespace: as.Namespace, Name: serviceName}
+	return association.ServiceURL(c, nsn, as.Spec.HTTP.Protocol(), "")
+}
+
+// referencedApmServerStatusVersion returns the currently running version of the APM Server reported in its status.
+func referencedApmServerStatusVersion(c k8s.Client, apmAssociation commonv1.Association) (string, bool, error) {
+	apmRef := apmAssociation.AssociationRef()
+	if apmRef.IsExternal() {
+		// the version of an external APM Server is not needed to configure the collector
+		return association.UnknownVersion, false, nil
+	}
+
+	var as apmv1.ApmServer
+	if err := c.Get(context.Background(), apmRef.NamespacedName(), &as); err != nil {
+		return "", false, err
+	}
+	return as.Status.Version, false, nil
+}
@@ -16,6 +16,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/safemode"
 	esuser "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/user"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
@@ -51,6 +52,9 @@ func NewUsersGarbageCollector(cfg *rest.Config, managedNamespaces []string) (*Us
 	if err != nil {
 		return nil, err
 	}
+	if safemode.Enabled {
+		cl = safemode.NewClient(cl)
+	}
 	if len(managedNamespaces) == 0 {
 		managedNamespaces = []string{AllNamespaces}
 	}
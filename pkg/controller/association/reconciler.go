@@ -37,11 +37,15 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/rbac"
 )
 
 var (
 	defaultRequeue = reconcile.Result{Requeue: true, RequeueAfter: 10 * time.Second}
+	// unmanagedRequeue is the interval at which the endpoints of unmanaged resources are checked again, as they are
+	// not watched
+	unmanagedRequeue = reconcile.Result{RequeueAfter: 5 * time.Minute}
 )
 
 // AssociationInfo contains information specific to a particular associated resource (eg. Kibana, APMServer, etc.).
@@ -222,6 +226,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 	return results.
 		WithResult(RequeueRbacCheck(r.accessReviewer)).
 		WithResult(resultFromStatuses(newStatusMap)).
+		WithResult(resultFromUnmanagedAssociations(associations, newStatusMap)).
 		Aggregate()
 }
 
@@ -474,6 +479,14 @@ func (r *Reconciler) updateAssocConf(
 		return "", err
 	}
 	if !reflect.DeepEqual(expectedAssocConf, assocConf) {
+		// delay URL changes until the new Service can serve requests, to not restart the associated resource towards
+		// a Service refusing connections
+		if ready, err := newServiceReady(r.Client, assocConf, expectedAssocConf); err != nil || !ready {
+			if err == nil {
+				log.Info("Delaying association configuration update until the new service has ready endpoints", "url", expectedAssocConf.URL)
+			}
+			return commonv1.AssociationPending, err
+		}
 		log.Info("Updating association configuration")
 		if err := UpdateAssociationConf(ctx, r.Client, association, expectedAssocConf); err != nil {
 			if apierrors.IsConflict(err) {
@@ -487,6 +500,23 @@ func (r *Reconciler) updateAssocConf(
 	return commonv1.AssociationEstablished, nil
 }
 
+// newServiceReady returns true if the URL of the association configuration does not change, or if it targets a Service
+// with ready endpoints.
+func newServiceReady(c k8s.Client, current, expected *commonv1.AssociationConf) (bool, error) {
+	if current == nil || current.URL == "" || current.URL == expected.URL {
+		return true, nil
+	}
+	serviceNSN, isService := serviceFromURL(expected.URL)
+	if !isService {
+		return true, nil
+	}
+	ready, err := ServiceEndpointsReady(c, serviceNSN)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	return ready, err
+}
+
 // updateStatus updates the associated resource status.
 func (r *Reconciler) updateStatus(ctx context.Context, associated commonv1.Associated, newStatus commonv1.AssociationStatusMap) error {
 	span, _ := apm.StartSpan(ctx, "update_association_status", tracing.SpanTypeApp)
@@ -534,7 +564,20 @@ func resultFromStatuses(statusMap commonv1.AssociationStatusMap) reconcile.Resul
 	return reconcile.Result{} // we are done or there is not much we can do
 }
 
+// resultFromUnmanagedAssociations requeues the reconciliation to check again the endpoints of the established
+// unmanaged resources. Failed associations are already retried because of the reconciliation error.
+func resultFromUnmanagedAssociations(associations []commonv1.Association, statusMap commonv1.AssociationStatusMap) reconcile.Result {
+	for _, association := range filterUnmanagedElasticRef(associations) {
+		if statusMap[association.AssociationRef().NamespacedName().String()] == commonv1.AssociationEstablished {
+			return unmanagedRequeue
+		}
+	}
+	return reconcile.Result{}
+}
+
 func (r *Reconciler) onDelete(ctx context.Context, associated types.NamespacedName) {
+	metrics.ForgetReconciledResource(ctx)
+
 	// remove watches
 	r.removeWatches(associated)
 
@@ -12,6 +12,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -695,6 +696,41 @@ func TestReconciler_Reconcile_CustomServiceRef(t *testing.T) {
 	require.Equal(t, commonv1.AssociationEstablished, updatedKibana.Status.AssociationStatus)
 }
 
+func TestReconciler_Reconcile_ServiceRefChange_WaitsForEndpoints(t *testing.T) {
+	// Kibana is associated through the default service, then switches to a custom service with no ready endpoints yet
+	kb := sampleAssociatedKibana()
+	serviceName := "coordinating-only"
+	kb.Spec.ElasticsearchRef.ServiceName = serviceName
+	svc := esHTTPService()
+	svc.Name = serviceName
+	svc.Spec.Selector = map[string]string{"elasticsearch.k8s.elastic.co/node-master": "false"}
+	r := testReconciler(&kb, &sampleES, &kibanaUserInESNamespace, &kibanaUserInKibanaNamespace, &esHTTPPublicCertsSecret, &esCertsInKibanaNamespace, esHTTPService(), svc)
+
+	_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(&kb)})
+	require.NoError(t, err)
+
+	var updatedKibana kbv1.Kibana
+	require.NoError(t, r.Get(context.Background(), k8s.ExtractNamespacedName(&kb), &updatedKibana))
+	// association conf should still target the previous service
+	require.Equal(t, sampleAssociatedKibana().Annotations[kb.EsAssociation().AssociationConfAnnotationName()], updatedKibana.Annotations[kb.EsAssociation().AssociationConfAnnotationName()])
+	require.Equal(t, commonv1.AssociationPending, updatedKibana.Status.AssociationStatus)
+
+	// the new service gets a ready endpoint
+	require.NoError(t, r.Create(context.Background(), &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{Namespace: esNamespace, Name: serviceName + "-abcde", Labels: map[string]string{discoveryv1.LabelServiceName: serviceName}},
+		Endpoints:  []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.1"}}},
+	}))
+
+	results, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(&kb)})
+	require.NoError(t, err)
+	require.Equal(t, reconcile.Result{}, results)
+
+	require.NoError(t, r.Get(context.Background(), k8s.ExtractNamespacedName(&kb), &updatedKibana))
+	// association conf should now target the new service
+	require.Equal(t, sampleAssociatedKibana(serviceName).Annotations[kb.EsAssociation().AssociationConfAnnotationName()], updatedKibana.Annotations[kb.EsAssociation().AssociationConfAnnotationName()])
+	require.Equal(t, commonv1.AssociationEstablished, updatedKibana.Status.AssociationStatus)
+}
+
 func TestReconciler_Reconcile_ExistingAssociation_NoOp(t *testing.T) {
 	// association already established, reconciliation should be a no-op
 	kb := sampleAssociatedKibana()
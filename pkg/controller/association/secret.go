@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"hash"
 	"net/http"
+	neturl "net/url"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -106,6 +107,9 @@ func GetUnmanagedAssociationConnectionInfoFromSecret(c k8s.Client, association U
 	ref := UnmanagedAssociationConnectionInfo{}
 	caCert, ok := secretRef.Data[certificates.CAFileName]
 	if ok {
+		if _, err := certificates.ParsePEMCerts(caCert); err != nil {
+			return nil, fmt.Errorf("invalid %s in secret %s: %w", certificates.CAFileName, assocRef.SecretName, err)
+		}
 		ref.CaCert = string(caCert)
 	}
 
@@ -113,16 +117,22 @@ func GetUnmanagedAssociationConnectionInfoFromSecret(c k8s.Client, association U
 	if !ok {
 		return nil, fmt.Errorf("url secret key doesn't exist in secret %s", assocRef.SecretName)
 	}
+	if err := validateUnmanagedURL(string(url)); err != nil {
+		return nil, fmt.Errorf("invalid url in secret %s: %w", assocRef.SecretName, err)
+	}
 	ref.URL = string(url)
 
-	if association.SupportsAuthAPIKey() {
-		if apiKey, ok := secretRef.Data[authAPIKeyUnmanagedSecretKey]; ok {
-			ref.APIKey = string(apiKey)
-			return &ref, nil
-		}
+	apiKey, hasAPIKey := secretRef.Data[authAPIKeyUnmanagedSecretKey]
+	if hasAPIKey && association.SupportsAuthAPIKey() {
+		ref.APIKey = string(apiKey)
+		return &ref, nil
 	}
 
 	username, ok := secretRef.Data[authUsernameUnmanagedSecretKey]
+	if !ok && hasAPIKey {
+		return nil, fmt.Errorf("API key authentication is not supported for this association type, "+
+			"username and password secret keys must be set in secret %s", assocRef.SecretName)
+	}
 	if !ok {
 		return nil, fmt.Errorf("username secret key doesn't exist in secret %s", assocRef.SecretName)
 	}
@@ -137,6 +147,21 @@ func GetUnmanagedAssociationConnectionInfoFromSecret(c k8s.Client, association U
 	return &ref, nil
 }
 
+// validateUnmanagedURL checks that the given URL of an unmanaged resource is an absolute HTTP(S) URL.
+func validateUnmanagedURL(rawURL string) error {
+	u, err := neturl.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%q must use the http or https scheme", rawURL)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("%q has no host", rawURL)
+	}
+	return nil
+}
+
 // Version performs an HTTP GET request to the unmanaged Elastic resource at the given path and returns a string extracted
 // from the returned result using the given json path and validates it is a valid semver version.
 func (r UnmanagedAssociationConnectionInfo) Version(path string, versionPattern VersionPattern) (string, bool, error) {
@@ -103,6 +103,67 @@ func TestGetUnmanagedAssociationConnexionInfoFromSecret(t *testing.T) {
 				return UnmanagedAssociationConnectionInfo{URL: "https://es.io:9243", Username: "", Password: "", CaCert: "", APIKey: "elastic"}
 			},
 			wantErr: false,
+		}, {
+			name: "api-key with an association type which does not support it",
+			args: args{
+				c: func() k8s.Client {
+					return k8s.NewFakeClient(&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "a", Name: "b"},
+						Data: map[string][]byte{
+							"url":     []byte("https://es.io:9243"),
+							"api-key": []byte("elastic"),
+						},
+					})
+				},
+			},
+			association: mockUnmanagedAssociation{
+				objSelector:        refObjectSelector,
+				supportsAuthAPIKey: false,
+			},
+			wantErr: true,
+		}, {
+			name: "api-key along with username and password for an association type which does not support it",
+			args: args{
+				c: func() k8s.Client {
+					secretCopy := unmanagedRefSecretFixture.DeepCopy()
+					secretCopy.Data["api-key"] = []byte("elastic")
+					return k8s.NewFakeClient(secretCopy)
+				},
+			},
+			association: mockUnmanagedAssociation{
+				objSelector:        refObjectSelector,
+				supportsAuthAPIKey: false,
+			},
+			want:    func() UnmanagedAssociationConnectionInfo { return refObjectFixture },
+			wantErr: false,
+		}, {
+			name: "invalid secret: url without scheme",
+			args: args{
+				c: func() k8s.Client {
+					secretCopy := unmanagedRefSecretFixture.DeepCopy()
+					secretCopy.Data["url"] = []byte("es.io:9243")
+					return k8s.NewFakeClient(secretCopy)
+				},
+			},
+			association: mockUnmanagedAssociation{
+				objSelector:        refObjectSelector,
+				supportsAuthAPIKey: false,
+			},
+			wantErr: true,
+		}, {
+			name: "invalid secret: malformed ca",
+			args: args{
+				c: func() k8s.Client {
+					secretCopy := unmanagedRefSecretFixture.DeepCopy()
+					secretCopy.Data["ca.crt"] = []byte("-----BEGIN CERTIFICATE-----\nWFhY\n-----END CERTIFICATE-----\n")
+					return k8s.NewFakeClient(secretCopy)
+				},
+			},
+			association: mockUnmanagedAssociation{
+				objSelector:        refObjectSelector,
+				supportsAuthAPIKey: false,
+			},
+			wantErr: true,
 		}, {
 			name: "secret does not exist",
 			args: args{
@@ -7,9 +7,13 @@ package association
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
@@ -29,6 +33,49 @@ func ServiceURL(c k8s.Client, serviceNSN types.NamespacedName, protocol string,
 	return fmt.Sprintf("%s://%s.%s.svc:%d%s", protocol, svc.Name, svc.Namespace, port, basePath), nil
 }
 
+// serviceFromURL returns the Service targeted by a URL built with ServiceURL, or false if the URL does not target a
+// Kubernetes Service of the form <name>.<namespace>.svc.
+func serviceFromURL(rawURL string) (types.NamespacedName, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return types.NamespacedName{}, false
+	}
+	parts := strings.Split(u.Hostname(), ".")
+	if len(parts) != 3 || parts[2] != "svc" {
+		return types.NamespacedName{}, false
+	}
+	return types.NamespacedName{Namespace: parts[1], Name: parts[0]}, true
+}
+
+// ServiceEndpointsReady returns true if at least one ready endpoint backs the Service identified by serviceNSN, in which
+// case the Service name can be resolved and connections to it are not refused. Services without a selector are considered
+// ready as their endpoints are not managed by Kubernetes.
+func ServiceEndpointsReady(c k8s.Client, serviceNSN types.NamespacedName) (bool, error) {
+	var svc corev1.Service
+	if err := c.Get(context.Background(), serviceNSN, &svc); err != nil {
+		return false, fmt.Errorf("while fetching referenced service: %w", err)
+	}
+	if svc.Spec.Type == corev1.ServiceTypeExternalName || len(svc.Spec.Selector) == 0 {
+		return true, nil
+	}
+	var endpointSlices discoveryv1.EndpointSliceList
+	if err := c.List(context.Background(), &endpointSlices,
+		client.InNamespace(serviceNSN.Namespace),
+		client.MatchingLabels{discoveryv1.LabelServiceName: serviceNSN.Name},
+	); err != nil {
+		return false, fmt.Errorf("while listing endpoint slices of service [%s]: %w", serviceNSN, err)
+	}
+	for _, endpointSlice := range endpointSlices.Items {
+		for _, endpoint := range endpointSlice.Endpoints {
+			// a nil ready condition must be interpreted as ready
+			if len(endpoint.Addresses) > 0 && (endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
 // findPortFor returns the port with the name matching protocol.
 func findPortFor(protocol string, svc corev1.Service) (int32, error) {
 	for _, p := range svc.Spec.Ports {
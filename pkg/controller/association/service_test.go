@@ -7,9 +7,13 @@ package association
 import (
 	"testing"
 
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 )
@@ -143,3 +147,88 @@ func Test_findPortFor(t *testing.T) {
 		})
 	}
 }
+
+func Test_serviceFromURL(t *testing.T) {
+	tests := []struct {
+		url    string
+		want   types.NamespacedName
+		wantOk bool
+	}{
+		{url: "https://b.a.svc:9200", want: types.NamespacedName{Namespace: "a", Name: "b"}, wantOk: true},
+		{url: "https://b.a.svc:5601/monitoring/kibana", want: types.NamespacedName{Namespace: "a", Name: "b"}, wantOk: true},
+		{url: "https://es.example.com:9200", wantOk: false},
+		{url: "https://b.a.svc.cluster.local:9200", wantOk: false},
+		{url: "://invalid", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			got, ok := serviceFromURL(tt.url)
+			require.Equal(t, tt.wantOk, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestServiceEndpointsReady(t *testing.T) {
+	svcName := types.NamespacedName{Namespace: "a", Name: "b"}
+	svc := func(selector map[string]string) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "a", Name: "b"},
+			Spec:       corev1.ServiceSpec{Selector: selector},
+		}
+	}
+	endpointSlice := func(name string, ready *bool, addresses ...string) *discoveryv1.EndpointSlice {
+		return &discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "a", Name: name, Labels: map[string]string{discoveryv1.LabelServiceName: "b"}},
+			Endpoints:  []discoveryv1.Endpoint{{Addresses: addresses, Conditions: discoveryv1.EndpointConditions{Ready: ready}}},
+		}
+	}
+	selector := map[string]string{"app": "es"}
+	tests := []struct {
+		name    string
+		objs    []client.Object
+		want    bool
+		wantErr bool
+	}{
+		{
+			name:    "service does not exist",
+			wantErr: true,
+		},
+		{
+			name: "no endpoint slices",
+			objs: []client.Object{svc(selector)},
+			want: false,
+		},
+		{
+			name: "no ready endpoint",
+			objs: []client.Object{svc(selector), endpointSlice("b-1", ptr.To(false), "10.0.0.1"), endpointSlice("b-2", ptr.To(true))},
+			want: false,
+		},
+		{
+			name: "one ready endpoint",
+			objs: []client.Object{svc(selector), endpointSlice("b-1", ptr.To(false), "10.0.0.1"), endpointSlice("b-2", ptr.To(true), "10.0.0.2")},
+			want: true,
+		},
+		{
+			name: "unknown readiness is ready",
+			objs: []client.Object{svc(selector), endpointSlice("b-1", nil, "10.0.0.1")},
+			want: true,
+		},
+		{
+			name: "service without selector",
+			objs: []client.Object{svc(nil)},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ServiceEndpointsReady(k8s.NewFakeClient(tt.objs...), svcName)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -438,6 +438,60 @@ func Test_GetResources(t *testing.T) {
 			},
 			wantPolicyState: []v1alpha1.PolicyState{},
 		},
+		{
+			name: "Scale down frozen nodes if the shared cache fits on fewer nodes",
+			args: args{
+				currentNodeSets: defaultNodeSets,
+				nodeSetsStatus: v1alpha1.ElasticsearchAutoscalerStatus{AutoscalingPolicyStatuses: []v1alpha1.AutoscalingPolicyStatus{{
+					Name:                   "my-autoscaling-policy",
+					NodeSetNodeCount:       []v1alpha1.NodeSetNodeCount{{Name: "default", NodeCount: 3}},
+					ResourcesSpecification: v1alpha1.NodeResources{Requests: map[corev1.ResourceName]resource.Quantity{corev1.ResourceMemory: q("4Gi"), corev1.ResourceStorage: q("4Gi")}}}},
+				},
+				requiredCapacity: newAutoscalingPolicyResultBuilder().
+					currentNodeStorage("4Gi").currentTierStorage("12Gi").
+					// the frozen decider only requires 6Gi of shared cache, data is stored in the snapshot repository
+					requiredTierStorage("6Gi").
+					deciderTierStorage(client.FrozenStorageDecider, "6Gi").
+					observedNodes("default-0", "default-1", "default-2").
+					build(),
+				policy: NewAutoscalingSpecBuilder("my-autoscaling-policy").WithRoles("data_frozen").WithNodeCounts(1, 5).WithMemory("4Gi", "4Gi").WithStorage("4Gi", "4Gi").Build(),
+			},
+			want: v1alpha1.NodeSetsResources{
+				Name:             "my-autoscaling-policy",
+				NodeSetNodeCount: []v1alpha1.NodeSetNodeCount{{Name: "default", NodeCount: 2}}, // 6Gi of cache require 2 volumes of 4Gi
+				NodeResources: v1alpha1.NodeResources{
+					Requests: map[corev1.ResourceName]resource.Quantity{corev1.ResourceMemory: q("4Gi"), corev1.ResourceStorage: q("4Gi")},
+					Limits:   map[corev1.ResourceName]resource.Quantity{corev1.ResourceMemory: q("4Gi")},
+				},
+			},
+			wantPolicyState: []v1alpha1.PolicyState{},
+		},
+		{
+			name: "Do not scale down data nodes if the storage is stored locally",
+			args: args{
+				currentNodeSets: defaultNodeSets,
+				nodeSetsStatus: v1alpha1.ElasticsearchAutoscalerStatus{AutoscalingPolicyStatuses: []v1alpha1.AutoscalingPolicyStatus{{
+					Name:                   "my-autoscaling-policy",
+					NodeSetNodeCount:       []v1alpha1.NodeSetNodeCount{{Name: "default", NodeCount: 3}},
+					ResourcesSpecification: v1alpha1.NodeResources{Requests: map[corev1.ResourceName]resource.Quantity{corev1.ResourceMemory: q("4Gi"), corev1.ResourceStorage: q("4Gi")}}}},
+				},
+				requiredCapacity: newAutoscalingPolicyResultBuilder().
+					currentNodeStorage("4Gi").currentTierStorage("12Gi").
+					requiredTierStorage("6Gi").
+					observedNodes("default-0", "default-1", "default-2").
+					build(),
+				policy: NewAutoscalingSpecBuilder("my-autoscaling-policy").WithRoles("data_cold").WithNodeCounts(1, 5).WithMemory("4Gi", "4Gi").WithStorage("4Gi", "4Gi").Build(),
+			},
+			want: v1alpha1.NodeSetsResources{
+				Name:             "my-autoscaling-policy",
+				NodeSetNodeCount: []v1alpha1.NodeSetNodeCount{{Name: "default", NodeCount: 3}},
+				NodeResources: v1alpha1.NodeResources{
+					Requests: map[corev1.ResourceName]resource.Quantity{corev1.ResourceMemory: q("4Gi"), corev1.ResourceStorage: q("4Gi")},
+					Limits:   map[corev1.ResourceName]resource.Quantity{corev1.ResourceMemory: q("4Gi")},
+				},
+			},
+			wantPolicyState: []v1alpha1.PolicyState{},
+		},
 		{
 			name: "Adjust limits",
 			args: args{
@@ -516,6 +570,7 @@ func getPolicyStates(status v1alpha1.ElasticsearchAutoscalerStatus, policyName s
 
 type AutoscalingSpecBuilder struct {
 	name                       string
+	roles                      []string
 	nodeCountMin, nodeCountMax int32
 	cpu, memory, storage       *v1alpha1.QuantityRange
 }
@@ -524,6 +579,11 @@ func NewAutoscalingSpecBuilder(name string) *AutoscalingSpecBuilder {
 	return &AutoscalingSpecBuilder{name: name}
 }
 
+func (asb *AutoscalingSpecBuilder) WithRoles(roles ...string) *AutoscalingSpecBuilder {
+	asb.roles = roles
+	return asb
+}
+
 func (asb *AutoscalingSpecBuilder) WithNodeCounts(minCount, maxCount int) *AutoscalingSpecBuilder {
 	asb.nodeCountMin = int32(minCount)
 	asb.nodeCountMax = int32(maxCount)
@@ -576,6 +636,9 @@ func (asb *AutoscalingSpecBuilder) Build() v1alpha1.AutoscalingPolicySpec {
 	return v1alpha1.AutoscalingPolicySpec{
 		NamedAutoscalingPolicy: v1alpha1.NamedAutoscalingPolicy{
 			Name: asb.name,
+			AutoscalingPolicy: v1alpha1.AutoscalingPolicy{
+				Roles: asb.roles,
+			},
 		},
 		AutoscalingResources: v1alpha1.AutoscalingResources{
 			CPURange:     asb.cpu,
@@ -633,6 +696,16 @@ func (rcb *autoscalingPolicyResultBuilder) currentTierStorage(m string) *autosca
 	return rcb
 }
 
+func (rcb *autoscalingPolicyResultBuilder) deciderTierStorage(decider, m string) *autoscalingPolicyResultBuilder {
+	if rcb.Deciders == nil {
+		rcb.Deciders = make(map[string]client.AutoscalingDeciderResult)
+	}
+	result := rcb.Deciders[decider]
+	result.RequiredCapacity.Total.Storage = ptr(value(m))
+	rcb.Deciders[decider] = result
+	return rcb
+}
+
 func (rcb *autoscalingPolicyResultBuilder) observedNodes(nodes ...string) *autoscalingPolicyResultBuilder {
 	rcb.CurrentNodes = make([]client.AutoscalingNodeInfo, len(nodes))
 	for i := range nodes {
@@ -13,6 +13,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 
 	"github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
 )
@@ -23,6 +24,10 @@ type storage struct {
 	// hasZeroRequirement is true when ES returns a requirement set to 0, just return the min storage in that case
 	hasZeroRequirement bool
 
+	// sharedCache is true if the policy manages a tier of partially mounted searchable snapshots. The local storage of
+	// these nodes only holds the shared cache, the data being stored in the snapshot repository.
+	sharedCache bool
+
 	// All resources can be computed "from scratch", without knowing the previous values.
 	// This is however not true for storage. Storage can't be scaled down, current storage capacity must be considered
 	// as a hard min. limit. This storage limit must be taken into consideration when computing the desired resources.
@@ -57,8 +62,8 @@ func (s *storage) NodeResourceQuantity() resource.Quantity {
 			s.autoscalingSpec,
 			s.statusBuilder,
 			string(s.ManagedResource()),
-			adjustRequiredStorage(s.requiredNodeStorageCapacity),
-			adjustRequiredStorage(s.requiredTotalStorageCapacity),
+			s.adjustRequiredStorage(s.requiredNodeStorageCapacity),
+			s.adjustRequiredStorage(s.requiredTotalStorageCapacity),
 			*s.autoscalingSpec.StorageRange,
 		)
 	} else {
@@ -107,8 +112,14 @@ func (s *storage) shouldScaleUp() bool {
 			)
 		return true
 	}
-	return s.requiredNodeStorageCapacity.Value() > s.observedNodeStorageCapacity.Value() ||
-		s.requiredTotalStorageCapacity.Value() > s.observedTotalStorageCapacity.Value()
+	requiredNodeStorageCapacity, requiredTotalStorageCapacity := s.requiredNodeStorageCapacity, s.requiredTotalStorageCapacity
+	if s.sharedCache {
+		// The observed capacity is the one of the volumes, not the one of the shared cache they hold.
+		requiredNodeStorageCapacity = adjustRequiredCacheStorage(requiredNodeStorageCapacity)
+		requiredTotalStorageCapacity = adjustRequiredCacheStorage(requiredTotalStorageCapacity)
+	}
+	return requiredNodeStorageCapacity.Value() > s.observedNodeStorageCapacity.Value() ||
+		requiredTotalStorageCapacity.Value() > s.observedTotalStorageCapacity.Value()
 }
 
 func (s *storage) NodeCount(nodeCapacity v1alpha1.NodeResources) int32 {
@@ -119,10 +130,12 @@ func (s *storage) NodeCount(nodeCapacity v1alpha1.NodeResources) int32 {
 	}
 	// Elasticsearch does not support data nodes scale down, always check if we should scale up first.
 	// Otherwise return the current node count.
+	// Nodes of a searchable snapshots tier only hold a cache of the data, they can be removed if the shared cache
+	// required by Elasticsearch fits on fewer nodes.
 	currentResources, hasResources := s.currentAutoscalingStatus.CurrentResourcesForPolicy(s.autoscalingSpec.Name)
-	if !hasResources || s.requiredTotalStorageCapacity.Value() > s.observedTotalStorageCapacity.Value() {
+	if !hasResources || s.sharedCache || s.requiredTotalStorageCapacity.Value() > s.observedTotalStorageCapacity.Value() {
 		nodeStorage := nodeCapacity.GetRequest(corev1.ResourceStorage)
-		adjustedTotalRequiredCapacity := adjustRequiredStorage(s.requiredTotalStorageCapacity)
+		adjustedTotalRequiredCapacity := s.adjustRequiredStorage(s.requiredTotalStorageCapacity)
 		return getNodeCount(
 			s.log,
 			s.autoscalingSpec,
@@ -168,6 +181,15 @@ func NewStorageRecommender(
 		return nil, fmt.Errorf("min and max storage must be specified")
 	}
 
+	sharedCache := isSearchableSnapshotsTier(autoscalingSpec)
+	requiredCapacity := autoscalingPolicyResult.RequiredCapacity
+	if deciderCapacity, exists := autoscalingPolicyResult.DeciderRequiredCapacity(client.FrozenStorageDecider); sharedCache && exists &&
+		!deciderCapacity.Total.Storage.IsEmpty() {
+		// Only consider the shared cache required by the partially mounted indices.
+		requiredCapacity.Node.Storage = deciderCapacity.Node.Storage
+		requiredCapacity.Total.Storage = deciderCapacity.Total.Storage
+	}
+
 	storageRecommender := storage{
 		base: base{
 			log:                      log,
@@ -175,12 +197,13 @@ func NewStorageRecommender(
 			statusBuilder:            statusBuilder,
 			currentAutoscalingStatus: currentAutoscalingStatus,
 		},
-		hasZeroRequirement: autoscalingPolicyResult.RequiredCapacity.Node.Storage.IsZero() &&
-			autoscalingPolicyResult.RequiredCapacity.Total.Storage.IsZero(),
+		hasZeroRequirement: requiredCapacity.Node.Storage.IsZero() &&
+			requiredCapacity.Total.Storage.IsZero(),
+		sharedCache: sharedCache,
 		// In case of storage we must not scale down vertically the storage capacity
 		minNodeStorageSize:           getMinStorageQuantity(autoscalingSpec, currentAutoscalingStatus),
-		requiredTotalStorageCapacity: autoscalingPolicyResult.RequiredCapacity.Total.Storage,
-		requiredNodeStorageCapacity:  autoscalingPolicyResult.RequiredCapacity.Node.Storage,
+		requiredTotalStorageCapacity: requiredCapacity.Total.Storage,
+		requiredNodeStorageCapacity:  requiredCapacity.Node.Storage,
 		// Observed storage capacity is retrieved from the Elasticsearch autoscaling response.
 		observedNodeStorageCapacity:  *autoscalingPolicyResult.CurrentCapacity.Node.Storage,
 		observedTotalStorageCapacity: *autoscalingPolicyResult.CurrentCapacity.Total.Storage,
@@ -210,7 +233,48 @@ func getMinStorageQuantity(autoscalingSpec v1alpha1.AutoscalingPolicySpec, curre
 	return storage
 }
 
-var usableDiskPercent = 0.95
+// isSearchableSnapshotsTier returns true if the policy only manages frozen nodes. Partially mounted searchable snapshots
+// can only be allocated to these nodes, which store a cache of the data rather than the data itself.
+// Fully mounted searchable snapshots, for example in the cold tier, are copied to the local storage of the nodes.
+func isSearchableSnapshotsTier(autoscalingSpec v1alpha1.AutoscalingPolicySpec) bool {
+	if len(autoscalingSpec.Roles) == 0 {
+		return false
+	}
+	for _, role := range autoscalingSpec.Roles {
+		if role != string(esv1.DataFrozenRole) {
+			return false
+		}
+	}
+	return true
+}
+
+var (
+	usableDiskPercent = 0.95
+
+	// By default the shared cache of a dedicated frozen node uses 90% of the disk, but leaves at most 100GB of headroom.
+	sharedCacheDiskPercent = 0.90
+	sharedCacheMaxHeadroom = int64(100 * 1000 * 1000 * 1000)
+)
+
+// adjustRequiredStorage adjusts the storage required by Elasticsearch to the capacity of the volume which must be claimed.
+func (s *storage) adjustRequiredStorage(v *client.AutoscalingCapacity) *client.AutoscalingCapacity {
+	if s.sharedCache {
+		return adjustRequiredCacheStorage(v)
+	}
+	return adjustRequiredStorage(v)
+}
+
+// adjustRequiredCacheStorage adjusts the shared cache size required by Elasticsearch to the capacity of the volume
+// which provides this cache, given the default size of the shared cache on dedicated frozen nodes.
+func adjustRequiredCacheStorage(v *client.AutoscalingCapacity) *client.AutoscalingCapacity {
+	adjustedStorage := int64(math.Ceil(float64(v.Value()) / sharedCacheDiskPercent))
+	if withHeadroom := v.Value() + sharedCacheMaxHeadroom; withHeadroom < adjustedStorage {
+		adjustedStorage = withHeadroom
+	}
+	return &client.AutoscalingCapacity{
+		Quantity: *resource.NewQuantity(adjustedStorage, resource.DecimalSI),
+	}
+}
 
 // adjustRequiredStorage adjust the required capacity from Elasticsearch to account for the filesystem reserved space.
 // In the worst case we consider that Elasticsearch is only able to use 95% of the persistent volume capacity.
@@ -37,6 +37,7 @@ import (
 	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	logconf "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
 )
 
@@ -113,6 +114,7 @@ func (r *ReconcileElasticsearchAutoscaler) Reconcile(ctx context.Context, reques
 		if apierrors.IsNotFound(err) {
 			log.V(1).Info("ElasticsearchAutoscaler not found", "namespace", request.Namespace, "esa_name", request.Name)
 			r.Watches.ReferencedResources.RemoveHandlerForKey(dynamicWatchName(request))
+			metrics.ForgetReconciledResource(ctx)
 			return reconcile.Result{}, nil
 		}
 		return reconcile.Result{}, tracing.CaptureError(ctx, err)
@@ -131,8 +133,14 @@ func (r *ReconcileElasticsearchAutoscaler) Reconcile(ctx context.Context, reques
 	if common.IsUnmanaged(ctx, &esa) {
 		msg := "Object is currently not managed by this controller. Skipping reconciliation"
 		log.Info(msg, "namespace", request.Namespace, "esa_name", request.Name)
+		if err := common.ReconcilePausedCondition(ctx, r.Client, &esa, &esa.Status.Conditions, true); err != nil {
+			return reconcile.Result{}, tracing.CaptureError(ctx, err)
+		}
 		return r.reportAsInactive(ctx, log, esa, msg)
 	}
+	if err := common.ReconcilePausedCondition(ctx, r.Client, &esa, &esa.Status.Conditions, false); err != nil {
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
+	}
 
 	enabled, err := r.licenseChecker.EnterpriseFeaturesEnabled(ctx)
 	if err != nil {
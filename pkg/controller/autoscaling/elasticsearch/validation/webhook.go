@@ -18,6 +18,7 @@ import (
 
 	"github.com/elastic/cloud-on-k8s/v2/pkg/apis/autoscaling/v1alpha1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/license"
+	commonwebhook "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/webhook"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
@@ -119,12 +120,14 @@ func (wh *validatingWebhook) Handle(ctx context.Context, req admission.Request)
 		return admission.Allowed("")
 	}
 
+	warnings := commonwebhook.GetWarnings(esa)
+
 	if req.Operation == admissionv1.Create || req.Operation == admissionv1.Update {
 		err = wh.validate(ctx, *esa)
 		if err != nil {
-			return admission.Denied(err.Error())
+			return admission.Denied(err.Error()).WithWarnings(warnings...)
 		}
 	}
 
-	return admission.Allowed("")
+	return admission.Allowed("").WithWarnings(warnings...)
 }
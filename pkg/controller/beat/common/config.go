@@ -59,11 +59,44 @@ func buildOutputConfig(ctx context.Context, client k8s.Client, associated beatv1
 		output["ssl.certificate_authorities"] = []string{path.Join(certificatesDir(&associated), CAFileName)}
 	}
 
+	for k, v := range associated.Spec.ElasticsearchOutput.Settings() {
+		output[k] = v
+	}
+
 	return settings.NewCanonicalConfigFrom(map[string]interface{}{
 		"output.elasticsearch": output,
 	})
 }
 
+// buildLogstashOutputConfig will create the Logstash output section in Beat config according to the association
+// configuration.
+func buildLogstashOutputConfig(associated beatv1beta1.BeatLogstashAssociation) (*settings.CanonicalConfig, error) {
+	lsAssocConf, err := associated.AssociationConf()
+	if err != nil {
+		return nil, err
+	}
+	if !lsAssocConf.IsConfigured() {
+		return settings.NewCanonicalConfig(), nil
+	}
+
+	output := map[string]interface{}{
+		"hosts": []string{lsAssocConf.GetURL()},
+	}
+
+	if lsAssocConf.GetCACertProvided() {
+		output["ssl.certificate_authorities"] = []string{path.Join(certificatesDir(&associated), CAFileName)}
+		if !associated.AssociationRef().IsExternal() {
+			// ECK does not manage TLS for the Beats input of Logstash, TLS must be enabled explicitly in the Beat
+			// configuration if the input is configured with certificates issued by the Logstash CA.
+			output["ssl.enabled"] = false
+		}
+	}
+
+	return settings.NewCanonicalConfigFrom(map[string]interface{}{
+		"output.logstash": output,
+	})
+}
+
 // BuildKibanaConfig builds on optional Kibana configuration for dashboard setup and visualizations.
 func BuildKibanaConfig(ctx context.Context, client k8s.Client, associated beatv1beta1.BeatKibanaAssociation) (*settings.CanonicalConfig, error) {
 	kbAssocConf, err := associated.AssociationConf()
@@ -104,7 +137,11 @@ func buildBeatConfig(
 	if err != nil {
 		return nil, err
 	}
-	err = cfg.MergeWith(outputCfg, managedConfig)
+	logstashOutputCfg, err := buildLogstashOutputConfig(beatv1beta1.BeatLogstashAssociation{Beat: &params.Beat})
+	if err != nil {
+		return nil, err
+	}
+	err = cfg.MergeWith(outputCfg, logstashOutputCfg, managedConfig)
 	if err != nil {
 		return nil, err
 	}
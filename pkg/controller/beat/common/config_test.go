@@ -13,6 +13,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 
 	beatv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/beat/v1beta1"
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
@@ -92,6 +93,18 @@ func Test_buildBeatConfig(t *testing.T) {
 	withAssocWithConfig := *withAssoc.DeepCopy()
 	withAssocWithConfig.Spec.Config = userCfg
 
+	withAssocWithOutputSettings := *withAssoc.DeepCopy()
+	withAssocWithOutputSettings.Spec.ElasticsearchOutput = &commonv1.ElasticsearchOutputSettings{
+		Workers:     ptr.To[int32](2),
+		ProxyURL:    "http://proxy:3128",
+		LoadBalance: ptr.To(true),
+	}
+	outputSettingsYaml := settings.MustParseConfig([]byte(`output.elasticsearch:
+  worker: 2
+  proxy_url: http://proxy:3128
+  loadbalance: true
+`))
+
 	for _, tt := range []struct {
 		name          string
 		client        k8s.Client
@@ -154,6 +167,12 @@ func Test_buildBeatConfig(t *testing.T) {
 			managedConfig: managedCfg,
 			want:          merge(userCanonicalCfg, managedCfg, outputYaml),
 		},
+		{
+			name:   "association with output settings",
+			client: clientWithSecret,
+			beat:   withAssocWithOutputSettings,
+			want:   merge(outputYaml, outputSettingsYaml),
+		},
 		{
 			name:   "association with ca, no configs",
 			client: clientWithSecret,
@@ -240,6 +259,76 @@ func Test_buildBeatConfig(t *testing.T) {
 	}
 }
 
+func Test_buildLogstashOutputConfig(t *testing.T) {
+	newAssociation := func(ref commonv1.ObjectSelector, caProvided bool) beatv1beta1.BeatLogstashAssociation {
+		beat := beatv1beta1.Beat{
+			ObjectMeta: metav1.ObjectMeta{Name: "beat", Namespace: "ns"},
+			Spec:       beatv1beta1.BeatSpec{LogstashRef: ref},
+		}
+		assoc := beatv1beta1.BeatLogstashAssociation{Beat: &beat}
+		assoc.SetAssociationConf(&commonv1.AssociationConf{
+			AuthSecretName: commonv1.NoAuthRequiredValue,
+			CACertProvided: caProvided,
+			CASecretName:   "beat-beat-ls-ca",
+			URL:            "ls-ls-beats.ns.svc:5044",
+		})
+		return assoc
+	}
+	managedRef := commonv1.ObjectSelector{Name: "ls", Namespace: "ns", ServiceName: "ls-ls-beats"}
+
+	for _, tt := range []struct {
+		name  string
+		assoc beatv1beta1.BeatLogstashAssociation
+		want  string
+	}{
+		{
+			name:  "no association",
+			assoc: beatv1beta1.BeatLogstashAssociation{Beat: &beatv1beta1.Beat{}},
+			want:  "",
+		},
+		{
+			name:  "association without CA",
+			assoc: newAssociation(managedRef, false),
+			want: `output:
+  logstash:
+    hosts:
+    - ls-ls-beats.ns.svc:5044
+`,
+		},
+		{
+			name:  "association with the CA of the Logstash certificates, TLS not enabled by default",
+			assoc: newAssociation(managedRef, true),
+			want: `output:
+  logstash:
+    hosts:
+    - ls-ls-beats.ns.svc:5044
+    ssl:
+      certificate_authorities:
+      - /mnt/elastic-internal/logstash-certs/ca.crt
+      enabled: false
+`,
+		},
+		{
+			name:  "external association with CA",
+			assoc: newAssociation(commonv1.ObjectSelector{SecretName: "external-ls"}, true),
+			want: `output:
+  logstash:
+    hosts:
+    - ls-ls-beats.ns.svc:5044
+    ssl:
+      certificate_authorities:
+      - /mnt/elastic-internal/logstash-certs/ca.crt
+`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildLogstashOutputConfig(tt.assoc)
+			require.NoError(t, err)
+			require.Empty(t, settings.MustParseConfig([]byte(tt.want)).Diff(got, nil))
+		})
+	}
+}
+
 func TestBuildKibanaConfig(t *testing.T) {
 	secretFixture := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
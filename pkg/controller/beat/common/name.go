@@ -5,6 +5,7 @@
 package common
 
 import (
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/effectivespec"
 	common_name "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/name"
 )
 
@@ -18,3 +19,8 @@ func ConfigSecretName(typeName, name string) string {
 func Name(name, typeName string) string {
 	return namer.Suffix(name, typeName)
 }
+
+// EffectiveSpecName returns the name of the ConfigMap publishing the effective specification of a Beat.
+func EffectiveSpecName(name, typeName string) string {
+	return namer.Suffix(name, typeName, effectivespec.Suffix)
+}
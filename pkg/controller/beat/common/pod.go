@@ -200,6 +200,9 @@ func buildPodTemplate(
 	if err != nil {
 		return corev1.PodTemplateSpec{}, err // error unlikely and should have been caught during validation
 	}
+	if err := container.CheckImageCatalog(spec.Image, defaultImage, v); err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
 
 	builder := defaults.NewPodTemplateBuilder(podTemplate, spec.Type).
 		WithLabels(labels).
@@ -219,11 +222,10 @@ func buildPodTemplate(
 		if main := builder.MainContainer(); main != nil {
 			removeLogToStderrOption(main)
 		}
-		builder = builder.WithArgs("-c", ConfigMountPath)
-		return builder.PodTemplate, nil
+		return builder.WithArgs("-c", ConfigMountPath).WithPodMutationPolicy(beatv1beta1.Kind, params.Beat.Namespace).PodTemplate, nil
 	}
 
-	return builder.WithArgs("-e", "-c", ConfigMountPath).PodTemplate, nil
+	return builder.WithArgs("-e", "-c", ConfigMountPath).WithPodMutationPolicy(beatv1beta1.Kind, params.Beat.Namespace).PodTemplate, nil
 }
 
 func removeLogToStderrOption(container *corev1.Container) {
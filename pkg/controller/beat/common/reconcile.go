@@ -22,6 +22,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/daemonset"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/deployment"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/effectivespec"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/pointer"
@@ -103,6 +104,10 @@ func reconcileDeployment(rp ReconciliationParams) (int32, int32, error) {
 		return 0, 0, err
 	}
 
+	if err := effectivespec.Reconcile(rp.ctx, rp.client, &rp.beat, EffectiveSpecName(rp.beat.Name, rp.beat.Spec.Type), rp.beat.Spec, &d); err != nil {
+		return 0, 0, err
+	}
+
 	reconciled, err := deployment.Reconcile(rp.ctx, rp.client, d, &rp.beat)
 	if err != nil {
 		return 0, 0, err
@@ -126,6 +131,10 @@ func reconcileDaemonSet(rp ReconciliationParams) (int32, int32, error) {
 		return 0, 0, err
 	}
 
+	if err := effectivespec.Reconcile(rp.ctx, rp.client, &rp.beat, EffectiveSpecName(rp.beat.Name, rp.beat.Spec.Type), rp.beat.Spec, &ds); err != nil {
+		return 0, 0, err
+	}
+
 	reconciled, err := daemonset.Reconcile(rp.ctx, rp.client, ds, &rp.beat)
 	if err != nil {
 		return 0, 0, err
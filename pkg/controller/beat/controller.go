@@ -20,6 +20,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	beatv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/beat/v1beta1"
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/association"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/beat/auditbeat"
 	beatcommon "github.com/elastic/cloud-on-k8s/v2/pkg/controller/beat/common"
@@ -38,6 +39,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
 )
 
 const (
@@ -143,7 +145,10 @@ func (r *ReconcileBeat) Reconcile(ctx context.Context, request reconcile.Request
 
 	if common.IsUnmanaged(ctx, &beat) {
 		ulog.FromContext(ctx).Info("Object is currently not managed by this controller. Skipping reconciliation", "namespace", beat.Namespace, "beat_name", beat.Name)
-		return reconcile.Result{}, nil
+		return reconcile.Result{}, tracing.CaptureError(ctx, common.ReconcilePausedCondition(ctx, r.Client, &beat, &beat.Status.Conditions, true))
+	}
+	if err := common.ReconcilePausedCondition(ctx, r.Client, &beat, &beat.Status.Conditions, false); err != nil {
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
 	}
 
 	if beat.IsMarkedForDeletion() {
@@ -151,6 +156,9 @@ func (r *ReconcileBeat) Reconcile(ctx context.Context, request reconcile.Request
 	}
 
 	results, status := r.doReconcile(ctx, beat)
+	common.UpdateStandardConditions(&status.Conditions, commonv1alpha1.NewResourceState(
+		beat.Generation, string(status.Health), status.AvailableNodes, status.ExpectedNodes, beat.Spec.Version, status.Version,
+	), results)
 	statusErr := beatcommon.UpdateStatus(ctx, beat, r.Client, status)
 	if statusErr != nil {
 		if apierrors.IsConflict(statusErr) {
@@ -200,6 +208,7 @@ func (r *ReconcileBeat) validate(ctx context.Context, beat *beatv1beta1.Beat) er
 }
 
 func (r *ReconcileBeat) onDelete(ctx context.Context, obj types.NamespacedName) error {
+	metrics.DeleteResourceMetrics(ctx, beatv1beta1.Kind, obj)
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(keystore.SecureSettingsWatchName(obj))
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(common.ConfigRefWatchName(obj))
 	return reconciler.GarbageCollectSoftOwnedSecrets(ctx, r.Client, obj, beatv1beta1.Kind)
@@ -5,6 +5,11 @@
 package annotation
 
 const (
+	// ManagedAnnotation can be set to false on a resource to suspend its reconciliation by the operator.
+	ManagedAnnotation = "eck.k8s.elastic.co/managed"
+	// LegacyPauseAnnotation is the deprecated equivalent of ManagedAnnotation.
+	LegacyPauseAnnotation = "common.k8s.elastic.co/pause"
+
 	// CurrAssocStatusAnnotation describes the currently observed association status of an object.
 	CurrAssocStatusAnnotation = "association.k8s.elastic.co/current-status"
 	// PrevAssocStatusAnnotation describes the previously observed association status of an object.
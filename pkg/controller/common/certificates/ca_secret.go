@@ -32,6 +32,12 @@ func ParseCustomCASecret(s corev1.Secret) (*CA, error) {
 	return parseCAFromSecret(s, keyFileName, crtFileName)
 }
 
+// ParseIssuedCASecret parses a CA issued by cert-manager. cert-manager stores the CA certificate and its private key
+// under the tls.* keys, and the certificate of the issuer under the ca.crt key.
+func ParseIssuedCASecret(s corev1.Secret) (*CA, error) {
+	return parseCAFromSecret(s, KeyFileName, CertFileName)
+}
+
 // parseCAFromSecret internal helper func to retrieve and parse a CA stored at the given keys in a Secret.
 func parseCAFromSecret(s corev1.Secret, keyFileName string, crtFileName string) (*CA, error) {
 	// Validate private key
@@ -0,0 +1,190 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package certificates
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/name"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
+)
+
+const (
+	// CertManagerGroup is the API group of the cert-manager resources.
+	CertManagerGroup = "cert-manager.io"
+	// certManagerIssuerKind is the default kind of the cert-manager issuers.
+	certManagerIssuerKind = "Issuer"
+
+	certManagerIssuedSecretSuffix = "certs-issued"
+)
+
+// CertManagerCertificateGVK is the GroupVersionKind of the cert-manager Certificate resource. The cert-manager API is not
+// vendored, Certificates are handled as unstructured objects.
+var CertManagerCertificateGVK = schema.GroupVersionKind{Group: CertManagerGroup, Version: "v1", Kind: "Certificate"}
+
+// CertManagerSecretName returns the name of both the cert-manager Certificate reconciled for the given owner and
+// certificate type, and of the Secret in which cert-manager stores the issued certificate.
+func CertManagerSecretName(namer name.Namer, ownerName string, caType CAType) string {
+	return namer.Suffix(ownerName, string(caType), certManagerIssuedSecretSuffix)
+}
+
+// CertManagerCertificate describes the cert-manager Certificate to reconcile.
+type CertManagerCertificate struct {
+	// Name of the Certificate and of the Secret in which the issued certificate is stored.
+	Name      string
+	IssuerRef commonv1.CertManagerIssuerRef
+	// CommonName is only set for CA certificates, leaf certificates rely on SANs only.
+	CommonName  string
+	DNSNames    []string
+	IPAddresses []net.IP
+	IsCA        bool
+	Rotation    RotationParams
+}
+
+// certManagerCertificateSpec mirrors the subset of the cert-manager Certificate spec managed by the operator.
+type certManagerCertificateSpec struct {
+	SecretName     string                        `json:"secretName"`
+	SecretTemplate *certManagerSecretTemplate    `json:"secretTemplate,omitempty"`
+	CommonName     string                        `json:"commonName,omitempty"`
+	DNSNames       []string                      `json:"dnsNames,omitempty"`
+	IPAddresses    []string                      `json:"ipAddresses,omitempty"`
+	IsCA           bool                          `json:"isCA,omitempty"`
+	Duration       *metav1.Duration              `json:"duration,omitempty"`
+	RenewBefore    *metav1.Duration              `json:"renewBefore,omitempty"`
+	Usages         []string                      `json:"usages,omitempty"`
+	IssuerRef      commonv1.CertManagerIssuerRef `json:"issuerRef"`
+}
+
+type certManagerSecretTemplate struct {
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+func (c CertManagerCertificate) spec(labels map[string]string) certManagerCertificateSpec {
+	issuerRef := c.IssuerRef
+	if issuerRef.Kind == "" {
+		issuerRef.Kind = certManagerIssuerKind
+	}
+	if issuerRef.Group == "" {
+		issuerRef.Group = CertManagerGroup
+	}
+	spec := certManagerCertificateSpec{
+		SecretName:  c.Name,
+		CommonName:  c.CommonName,
+		DNSNames:    c.DNSNames,
+		IsCA:        c.IsCA,
+		IssuerRef:   issuerRef,
+		Duration:    durationOrNil(c.Rotation.Validity),
+		RenewBefore: durationOrNil(c.Rotation.RotateBefore),
+	}
+	for _, ip := range c.IPAddresses {
+		spec.IPAddresses = append(spec.IPAddresses, ip.String())
+	}
+	if !c.IsCA {
+		spec.Usages = []string{"digital signature", "key encipherment", "server auth", "client auth"}
+	}
+	if len(labels) > 0 {
+		spec.SecretTemplate = &certManagerSecretTemplate{Labels: labels}
+	}
+	return spec
+}
+
+func durationOrNil(d time.Duration) *metav1.Duration {
+	if d == 0 {
+		return nil
+	}
+	return &metav1.Duration{Duration: d}
+}
+
+func newCertManagerCertificate(namespace, name string) *unstructured.Unstructured {
+	certificate := &unstructured.Unstructured{}
+	certificate.SetGroupVersionKind(CertManagerCertificateGVK)
+	certificate.SetNamespace(namespace)
+	certificate.SetName(name)
+	return certificate
+}
+
+// ReconcileCertManagerCertificate reconciles a cert-manager Certificate owned by the given owner, and returns the Secret
+// in which cert-manager stores the issued certificate, or nil if the certificate has not been issued yet.
+// Renewals are handled by cert-manager which updates the Secret in place.
+func ReconcileCertManagerCertificate(
+	ctx context.Context,
+	c k8s.Client,
+	owner client.Object,
+	labels map[string]string,
+	certificate CertManagerCertificate,
+) (*corev1.Secret, error) {
+	spec, err := runtime.DefaultUnstructuredConverter.ToUnstructured(ptr.To(certificate.spec(labels)))
+	if err != nil {
+		return nil, err
+	}
+	expected := newCertManagerCertificate(owner.GetNamespace(), certificate.Name)
+	expected.SetLabels(labels)
+	expected.Object["spec"] = spec
+
+	reconciled := newCertManagerCertificate(owner.GetNamespace(), certificate.Name)
+	if err := reconciler.ReconcileResource(reconciler.Params{
+		Context:    ctx,
+		Client:     c,
+		Owner:      owner,
+		Expected:   expected,
+		Reconciled: reconciled,
+		NeedsUpdate: func() bool {
+			return !maps.IsSubset(labels, reconciled.GetLabels()) ||
+				!reflect.DeepEqual(expected.Object["spec"], reconciled.Object["spec"])
+		},
+		UpdateReconciled: func() {
+			reconciled.SetLabels(maps.Merge(reconciled.GetLabels(), labels))
+			reconciled.Object["spec"] = expected.Object["spec"]
+		},
+	}); err != nil {
+		return nil, err
+	}
+
+	var secret corev1.Secret
+	err = c.Get(ctx, types.NamespacedName{Namespace: owner.GetNamespace(), Name: certificate.Name}, &secret)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &secret, nil
+}
+
+// DeleteCertManagerCertificate deletes the cert-manager Certificate with the given name and the Secret holding the
+// issued certificate. The Certificate is only looked up if the Secret exists, so that the cert-manager API is never
+// accessed if cert-manager is not used.
+func DeleteCertManagerCertificate(ctx context.Context, c k8s.Client, namespace, name string) error {
+	var secret corev1.Secret
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	// delete the Certificate first, otherwise cert-manager would issue the certificate again
+	err := c.Delete(ctx, newCertManagerCertificate(namespace, name))
+	if err != nil && !apierrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return err
+	}
+	return k8s.DeleteSecretIfExists(ctx, c, k8s.ExtractNamespacedName(&secret))
+}
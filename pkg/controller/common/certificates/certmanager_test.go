@@ -0,0 +1,167 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package certificates
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func issuedSecret(t *testing.T, name string) *corev1.Secret {
+	t.Helper()
+	data := map[string][]byte{}
+	for _, file := range []string{CAFileName, CertFileName, KeyFileName} {
+		bytes, err := os.ReadFile(filepath.Join("testdata", file))
+		require.NoError(t, err)
+		data[file] = bytes
+	}
+	return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: obj.Namespace, Name: name}, Data: data}
+}
+
+func getCertManagerCertificateSpec(t *testing.T, c k8s.Client, name string) map[string]interface{} {
+	t.Helper()
+	certificate := newCertManagerCertificate(obj.Namespace, name)
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: obj.Namespace, Name: name}, certificate))
+	require.Equal(t, labels, certificate.GetLabels())
+	require.Len(t, certificate.GetOwnerReferences(), 1)
+	require.Equal(t, obj.Name, certificate.GetOwnerReferences()[0].Name)
+	spec, ok := certificate.Object["spec"].(map[string]interface{})
+	require.True(t, ok)
+	return spec
+}
+
+func TestReconcileCertManagerCertificate(t *testing.T) {
+	c := k8s.NewFakeClient()
+	name := CertManagerSecretName(esv1.ESNamer, obj.Name, HTTPCAType)
+	require.Equal(t, "es-es-http-certs-issued", name)
+	certificate := CertManagerCertificate{
+		Name:        name,
+		IssuerRef:   commonv1.CertManagerIssuerRef{Name: "ca-issuer"},
+		DNSNames:    []string{"es-es-http", "es-es-http.ns.svc"},
+		IPAddresses: []net.IP{net.ParseIP("10.0.0.1")},
+		Rotation:    rotation,
+	}
+
+	// the Certificate is created, but not issued yet
+	secret, err := ReconcileCertManagerCertificate(context.Background(), c, &obj, labels, certificate)
+	require.NoError(t, err)
+	require.Nil(t, secret)
+	require.Equal(t, map[string]interface{}{
+		"secretName":     name,
+		"secretTemplate": map[string]interface{}{"labels": map[string]interface{}{"foo": "bar"}},
+		"dnsNames":       []interface{}{"es-es-http", "es-es-http.ns.svc"},
+		"ipAddresses":    []interface{}{"10.0.0.1"},
+		"duration":       "8760h0m0s",
+		"renewBefore":    "24h0m0s",
+		"usages":         []interface{}{"digital signature", "key encipherment", "server auth", "client auth"},
+		"issuerRef":      map[string]interface{}{"name": "ca-issuer", "kind": "Issuer", "group": "cert-manager.io"},
+	}, getCertManagerCertificateSpec(t, c, name))
+
+	// cert-manager issues the certificate
+	require.NoError(t, c.Create(context.Background(), issuedSecret(t, name)))
+	secret, err = ReconcileCertManagerCertificate(context.Background(), c, &obj, labels, certificate)
+	require.NoError(t, err)
+	require.NotNil(t, secret)
+	require.Equal(t, name, secret.Name)
+
+	// the Certificate is updated with the new SANs
+	certificate.DNSNames = append(certificate.DNSNames, "es.example.com")
+	certificate.IssuerRef = commonv1.CertManagerIssuerRef{Name: "vault", Kind: "ClusterIssuer"}
+	_, err = ReconcileCertManagerCertificate(context.Background(), c, &obj, labels, certificate)
+	require.NoError(t, err)
+	spec := getCertManagerCertificateSpec(t, c, name)
+	require.Equal(t, []interface{}{"es-es-http", "es-es-http.ns.svc", "es.example.com"}, spec["dnsNames"])
+	require.Equal(t, map[string]interface{}{"name": "vault", "kind": "ClusterIssuer", "group": "cert-manager.io"}, spec["issuerRef"])
+}
+
+func TestReconcileCertManagerCertificate_CA(t *testing.T) {
+	c := k8s.NewFakeClient()
+	name := CertManagerSecretName(esv1.ESNamer, obj.Name, TransportCAType)
+	_, err := ReconcileCertManagerCertificate(context.Background(), c, &obj, nil, CertManagerCertificate{
+		Name:       name,
+		IssuerRef:  commonv1.CertManagerIssuerRef{Name: "ca-issuer", Kind: "ClusterIssuer", Group: "cert-manager.io"},
+		CommonName: "es-transport",
+		IsCA:       true,
+	})
+	require.NoError(t, err)
+
+	certificate := newCertManagerCertificate(obj.Namespace, name)
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: obj.Namespace, Name: name}, certificate))
+	require.Equal(t, map[string]interface{}{
+		"secretName": name,
+		"commonName": "es-transport",
+		"isCA":       true,
+		"issuerRef":  map[string]interface{}{"name": "ca-issuer", "kind": "ClusterIssuer", "group": "cert-manager.io"},
+	}, certificate.Object["spec"])
+}
+
+func TestDeleteCertManagerCertificate(t *testing.T) {
+	name := CertManagerSecretName(esv1.ESNamer, obj.Name, HTTPCAType)
+	certificate := newCertManagerCertificate(obj.Namespace, name)
+
+	// nothing to delete
+	c := k8s.NewFakeClient()
+	require.NoError(t, DeleteCertManagerCertificate(context.Background(), c, obj.Namespace, name))
+
+	// the Certificate and the issued Secret are deleted
+	c = k8s.NewFakeClient(certificate.DeepCopy(), issuedSecret(t, name))
+	require.NoError(t, DeleteCertManagerCertificate(context.Background(), c, obj.Namespace, name))
+	require.True(t, apierrors.IsNotFound(c.Get(context.Background(), k8s.ExtractNamespacedName(certificate), certificate.DeepCopy())))
+	require.True(t, apierrors.IsNotFound(c.Get(context.Background(), types.NamespacedName{Namespace: obj.Namespace, Name: name}, &corev1.Secret{})))
+}
+
+func TestReconcileCAAndHTTPCerts_CertManager(t *testing.T) {
+	c := k8s.NewFakeClient()
+	r := Reconciler{
+		K8sClient:      c,
+		DynamicWatches: watches.NewDynamicWatches(),
+		Owner:          &obj,
+		TLSOptions:     commonv1.TLSOptions{IssuerRef: &commonv1.CertManagerIssuerRef{Name: "ca-issuer"}},
+		Namer:          esv1.ESNamer,
+		Labels:         labels,
+		CACertRotation: rotation,
+		CertRotation:   rotation,
+	}
+	issuedSecretName := CertManagerSecretName(esv1.ESNamer, obj.Name, HTTPCAType)
+
+	// the self-signed certificate is used until cert-manager issues the certificate
+	httpCerts, results := r.ReconcileCAAndHTTPCerts(context.Background())
+	require.False(t, results.HasError())
+	issued := issuedSecret(t, issuedSecretName)
+	require.NotEqual(t, issued.Data[CertFileName], httpCerts.CertPem())
+	getCertManagerCertificateSpec(t, c, issuedSecretName)
+	// the Secret of the issued certificate is watched
+	require.Contains(t, r.DynamicWatches.Secrets.Registrations(), CertificateWatchKey(esv1.ESNamer, obj.Name))
+
+	// cert-manager issues the certificate, which is used instead of the self-signed one
+	require.NoError(t, c.Create(context.Background(), issued))
+	httpCerts, results = r.ReconcileCAAndHTTPCerts(context.Background())
+	require.False(t, results.HasError())
+	require.Equal(t, issued.Data[CertFileName], httpCerts.CertPem())
+	require.Equal(t, issued.Data[KeyFileName], httpCerts.KeyPem())
+	require.Equal(t, issued.Data[CAFileName], httpCerts.CAPem())
+
+	// going back to self-signed certificates removes the issued certificate
+	r.TLSOptions = commonv1.TLSOptions{}
+	httpCerts, results = r.ReconcileCAAndHTTPCerts(context.Background())
+	require.False(t, results.HasError())
+	require.NotEqual(t, issued.Data[CertFileName], httpCerts.CertPem())
+	require.True(t, apierrors.IsNotFound(c.Get(context.Background(), types.NamespacedName{Namespace: obj.Namespace, Name: issuedSecretName}, &corev1.Secret{})))
+	require.True(t, apierrors.IsNotFound(c.Get(context.Background(), types.NamespacedName{Namespace: obj.Namespace, Name: issuedSecretName}, newCertManagerCertificate(obj.Namespace, issuedSecretName))))
+}
@@ -4,9 +4,20 @@
 
 package certificates
 
-import "time"
+import (
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
+)
 
 const (
+	// HTTPCertificateMetric, HTTPCAMetric and TransportCAMetric identify the certificates in the expiry metrics.
+	HTTPCertificateMetric = "http"
+	HTTPCAMetric          = "http-ca"
+	TransportCAMetric     = "transport-ca"
+
 	// DefaultCertValidity makes new certificates default to a 1 year expiration
 	DefaultCertValidity = 365 * 24 * time.Hour
 	// DefaultRotateBefore defines how long before expiration a certificate
@@ -35,3 +46,13 @@ func ShouldRotateIn(now time.Time, certExpiration time.Time, certRotateBefore ti
 	}
 	return requeueIn
 }
+
+// ReportExpiry reports the expiration time of a certificate of the given kind of owner.
+func ReportExpiry(kind string, owner client.Object, certificate string, expiration time.Time) {
+	metrics.CertificateExpiryGauge.WithLabelValues(owner.GetNamespace(), owner.GetName(), kind, certificate).Set(float64(expiration.Unix()))
+}
+
+// DeleteExpiry stops reporting the expiration time of a certificate of the given kind of owner.
+func DeleteExpiry(kind string, owner client.Object, certificate string) {
+	metrics.CertificateExpiryGauge.DeleteLabelValues(owner.GetNamespace(), owner.GetName(), kind, certificate)
+}
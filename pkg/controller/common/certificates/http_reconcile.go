@@ -59,7 +59,7 @@ func (r Reconciler) ReconcileInternalHTTPCerts(ctx context.Context, ca *CA, cust
 	ownerNSN := k8s.ExtractNamespacedName(r.Owner)
 
 	watchKey := CertificateWatchKey(r.Namer, ownerNSN.Name)
-	if err := ReconcileCustomCertWatch(r.DynamicWatches, watchKey, ownerNSN, r.TLSOptions.Certificate); err != nil {
+	if err := ReconcileCustomCertWatch(r.DynamicWatches, watchKey, ownerNSN, r.certificateRef()); err != nil {
 		return nil, err
 	}
 
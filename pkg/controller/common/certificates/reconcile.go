@@ -6,6 +6,7 @@ package certificates
 
 import (
 	"context"
+	"crypto/x509"
 	"time"
 
 	"go.elastic.co/apm/v2"
@@ -57,12 +58,15 @@ func (r Reconciler) ReconcileCAAndHTTPCerts(ctx context.Context) (*CertificatesS
 
 	results := reconciler.NewResult(ctx)
 
+	kind := r.Owner.GetObjectKind().GroupVersionKind().Kind
 	if !r.TLSOptions.Enabled() && r.GarbageCollectSecrets {
+		DeleteExpiry(kind, r.Owner, HTTPCertificateMetric)
+		DeleteExpiry(kind, r.Owner, HTTPCAMetric)
 		return nil, results.WithError(r.removeCAAndHTTPCertsSecrets(ctx))
 	}
 
-	// check for custom certificates first
-	customCerts, err := validCustomCertificatesOrNil(r.K8sClient, k8s.ExtractNamespacedName(r.Owner), r.TLSOptions)
+	// check for custom certificates first, either provided by the user or issued by cert-manager
+	customCerts, err := r.customCertificatesOrNil(ctx)
 	if err != nil {
 		return nil, results.WithError(err)
 	}
@@ -96,6 +100,12 @@ func (r Reconciler) ReconcileCAAndHTTPCerts(ctx context.Context) (*CertificatesS
 		)
 	}
 
+	if httpCa != nil && httpCa.Cert != nil {
+		ReportExpiry(kind, r.Owner, HTTPCAMetric, httpCa.Cert.NotAfter)
+	} else {
+		DeleteExpiry(kind, r.Owner, HTTPCAMetric)
+	}
+
 	// reconcile http customCerts: either self-signed or user-provided
 	httpCertificates, err := r.ReconcileInternalHTTPCerts(ctx, httpCa, customCerts)
 	if err != nil {
@@ -105,6 +115,7 @@ func (r Reconciler) ReconcileCAAndHTTPCerts(ctx context.Context) (*CertificatesS
 	if err != nil {
 		return nil, results.WithError(err)
 	}
+	ReportExpiry(kind, r.Owner, HTTPCertificateMetric, primaryCert.NotAfter)
 	results.WithReconciliationState(
 		reconciler.
 			RequeueAfter(ShouldRotateIn(time.Now(), primaryCert.NotAfter, r.CertRotation.RotateBefore)).
@@ -116,8 +127,49 @@ func (r Reconciler) ReconcileCAAndHTTPCerts(ctx context.Context) (*CertificatesS
 	return httpCertificates, results
 }
 
+// customCertificatesOrNil returns the certificates to use instead of self-signed ones, or nil if there is none.
+// Certificates issued by cert-manager are only returned once cert-manager has issued them.
+func (r Reconciler) customCertificatesOrNil(ctx context.Context) (*CertificatesSecret, error) {
+	owner := k8s.ExtractNamespacedName(r.Owner)
+	issuedSecretName := CertManagerSecretName(r.Namer, owner.Name, HTTPCAType)
+	if r.TLSOptions.IssuerRef == nil {
+		// garbage collect the certificate previously issued by cert-manager, if any
+		if err := DeleteCertManagerCertificate(ctx, r.K8sClient, owner.Namespace, issuedSecretName); err != nil {
+			return nil, err
+		}
+		return validCustomCertificatesOrNil(r.K8sClient, owner, r.TLSOptions)
+	}
+
+	template := createValidatedHTTPCertificateTemplate(
+		owner, r.Namer, r.TLSOptions, r.ExtraHTTPSANs, r.Services, &x509.CertificateRequest{}, r.CertRotation.Validity,
+	)
+	secret, err := ReconcileCertManagerCertificate(ctx, r.K8sClient, r.Owner, r.Labels, CertManagerCertificate{
+		Name:        issuedSecretName,
+		IssuerRef:   *r.TLSOptions.IssuerRef,
+		DNSNames:    template.DNSNames,
+		IPAddresses: template.IPAddresses,
+		Rotation:    r.CertRotation,
+	})
+	if err != nil || secret == nil {
+		return nil, err
+	}
+	return NewCertificatesSecret(*secret)
+}
+
+// certificateRef returns a reference to the Secret holding the certificates to use instead of self-signed ones, if any.
+func (r Reconciler) certificateRef() commonv1.SecretRef {
+	if r.TLSOptions.IssuerRef != nil {
+		return commonv1.SecretRef{SecretName: CertManagerSecretName(r.Namer, r.Owner.GetName(), HTTPCAType)}
+	}
+	return r.TLSOptions.Certificate
+}
+
 func (r *Reconciler) removeCAAndHTTPCertsSecrets(ctx context.Context) error {
 	owner := k8s.ExtractNamespacedName(r.Owner)
+	// remove the certificate issued by cert-manager
+	if err := DeleteCertManagerCertificate(ctx, r.K8sClient, owner.Namespace, CertManagerSecretName(r.Namer, owner.Name, HTTPCAType)); err != nil {
+		return err
+	}
 	// remove public certs secret
 	if err := k8s.DeleteSecretIfExists(ctx, r.K8sClient,
 		types.NamespacedName{Namespace: owner.Namespace, Name: PublicCertsSecretName(r.Namer, owner.Name)},
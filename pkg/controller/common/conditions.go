@@ -0,0 +1,22 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package common
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+)
+
+// UpdateStandardConditions sets the standard conditions of a resource according to its observed state and to the
+// results of its reconciliation. The resource is stalled if it does not pass validation.
+func UpdateStandardConditions(conditions *commonv1alpha1.Conditions, state commonv1alpha1.ResourceState, results *reconciler.Results) {
+	state.Reconciled, state.ReconciliationMessage = results.IsReconciled()
+	if results.HasInvalidError() {
+		state.Stalled, state.StalledMessage = true, state.ReconciliationMessage
+	}
+	*conditions = conditions.WithStandardConditions(state, metav1.Now())
+}
@@ -0,0 +1,95 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package container
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+var (
+	// imageCatalog pins the Elastic Stack images by digest, nil if no catalog is configured.
+	imageCatalog ImageCatalog
+
+	digestPattern = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+	knownImages = []Image{
+		APMServerImage, ElasticsearchImage, KibanaImage, EnterpriseSearchImage, FilebeatImage, MetricbeatImage, HeartbeatImage,
+		AuditbeatImage, JournalbeatImage, PacketbeatImage, AgentImage, MapsImage, LogstashImage,
+	}
+)
+
+// ImageCatalog lists the digests of the Elastic Stack images available to the operator, indexed by the name of the image
+// (for example elasticsearch or kibana) and by version. Used in air-gapped environments, only the listed versions can be
+// deployed, using images pinned by digest.
+type ImageCatalog map[string]map[string]string
+
+// digest returns the digest of the given image version, and false if the version is not listed in the catalog.
+func (c ImageCatalog) digest(img Image, ver version.Version) (string, bool) {
+	digest, exists := c[img.Name()][ver.String()]
+	return digest, exists
+}
+
+// ParseImageCatalog parses and validates the given YAML image catalog.
+func ParseImageCatalog(data []byte) (ImageCatalog, error) {
+	var catalog ImageCatalog
+	if err := yaml.UnmarshalStrict(data, &catalog); err != nil {
+		return nil, fmt.Errorf("while parsing the image catalog: %w", err)
+	}
+	for name, digests := range catalog {
+		if !isKnownImage(name) {
+			return nil, fmt.Errorf("unknown image %s in the image catalog", name)
+		}
+		for ver, digest := range digests {
+			if _, err := version.Parse(ver); err != nil {
+				return nil, fmt.Errorf("invalid version %s of image %s in the image catalog: %w", ver, name, err)
+			}
+			if !digestPattern.MatchString(digest) {
+				return nil, fmt.Errorf("invalid digest %s for version %s of image %s in the image catalog, expected sha256:<64 hex characters>", digest, ver, name)
+			}
+		}
+	}
+	return catalog, nil
+}
+
+// LoadImageCatalog reads and validates the image catalog stored in the given file.
+func LoadImageCatalog(path string) (ImageCatalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("while reading the image catalog: %w", err)
+	}
+	return ParseImageCatalog(data)
+}
+
+// SetImageCatalog sets the global image catalog from which the digests of the Elastic Stack images are resolved.
+func SetImageCatalog(catalog ImageCatalog) {
+	imageCatalog = catalog
+}
+
+// CheckImageCatalog returns an error if an image catalog is configured and does not list the given version of the image.
+// Custom images are not checked against the catalog: they are fully managed by the user.
+func CheckImageCatalog(customImage string, img Image, ver version.Version) error {
+	if customImage != "" || imageCatalog == nil {
+		return nil
+	}
+	if _, exists := imageCatalog.digest(img, ver); !exists {
+		return fmt.Errorf("version %s of image %s is not listed in the image catalog of the operator", ver, img.Name())
+	}
+	return nil
+}
+
+func isKnownImage(name string) bool {
+	for _, img := range knownImages {
+		if img.Name() == name {
+			return true
+		}
+	}
+	return false
+}
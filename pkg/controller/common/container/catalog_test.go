@@ -0,0 +1,90 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package container
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+var testDigest = "sha256:" + strings.Repeat("a1", 32)
+
+func TestParseImageCatalog(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    ImageCatalog
+		wantErr string
+	}{
+		{
+			name: "valid catalog",
+			data: "elasticsearch:\n  8.15.0: " + testDigest + "\nelastic-agent:\n  8.15.0: " + testDigest + "\n",
+			want: ImageCatalog{
+				"elasticsearch": {"8.15.0": testDigest},
+				"elastic-agent": {"8.15.0": testDigest},
+			},
+		},
+		{
+			name:    "unknown image",
+			data:    "elastic-search:\n  8.15.0: " + testDigest + "\n",
+			wantErr: "unknown image elastic-search",
+		},
+		{
+			name:    "invalid version",
+			data:    "kibana:\n  latest: " + testDigest + "\n",
+			wantErr: "invalid version latest of image kibana",
+		},
+		{
+			name:    "invalid digest",
+			data:    "kibana:\n  8.15.0: 8.15.0\n",
+			wantErr: "invalid digest 8.15.0 for version 8.15.0 of image kibana",
+		},
+		{
+			name:    "invalid format",
+			data:    "kibana: 8.15.0\n",
+			wantErr: "while parsing the image catalog",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseImageCatalog([]byte(tt.data))
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestImageCatalog(t *testing.T) {
+	defer SetImageCatalog(nil)
+	SetContainerRegistry("my.registry")
+	defer SetContainerRegistry(DefaultContainerRegistry)
+
+	listed := version.MustParse("8.15.0")
+	unlisted := version.MustParse("8.15.1")
+
+	// without catalog, any version can be deployed
+	require.NoError(t, CheckImageCatalog("", ElasticsearchImage, unlisted))
+	require.Equal(t, "my.registry/elasticsearch/elasticsearch:8.15.0", ImageRepository(ElasticsearchImage, listed))
+
+	SetImageCatalog(ImageCatalog{"elasticsearch": {"8.15.0": testDigest}})
+	require.NoError(t, CheckImageCatalog("", ElasticsearchImage, listed))
+	require.Equal(t, "my.registry/elasticsearch/elasticsearch:8.15.0@"+testDigest, ImageRepository(ElasticsearchImage, listed))
+
+	// unlisted versions are refused, unless a custom image is used
+	require.EqualError(t, CheckImageCatalog("", ElasticsearchImage, unlisted),
+		"version 8.15.1 of image elasticsearch is not listed in the image catalog of the operator")
+	require.EqualError(t, CheckImageCatalog("", KibanaImage, listed),
+		"version 8.15.0 of image kibana is not listed in the image catalog of the operator")
+	require.NoError(t, CheckImageCatalog("my.registry/custom/elasticsearch:8.15.1", ElasticsearchImage, unlisted))
+	require.Equal(t, "my.registry/elasticsearch/elasticsearch:8.15.1", ImageRepository(ElasticsearchImage, unlisted))
+}
@@ -72,6 +72,7 @@ var MinMapsVersionOnARM = version.MinFor(8, 16, 0)
 // ImageRepository returns the full container image name by concatenating the current container registry and the image path with the given version.
 // A UBI suffix (-ubi8 or -ubi suffix depending on the version) is appended to the image name for the maps image,
 // or any image if the operator is configured with --ubi-only.
+// The image is pinned by digest if the given version is listed in the image catalog of the operator.
 func ImageRepository(img Image, ver version.Version) string {
 	// replace repository if defined
 	image := img
@@ -91,6 +92,9 @@ func ImageRepository(img Image, ver version.Version) string {
 		suffix += containerSuffix
 	}
 
+	if digest, exists := imageCatalog.digest(img, ver); exists {
+		return fmt.Sprintf("%s/%s%s:%s@%s", containerRegistry, image, suffix, ver, digest)
+	}
 	return fmt.Sprintf("%s/%s%s:%s", containerRegistry, image, suffix, ver)
 }
 
@@ -18,11 +18,16 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
 	logconf "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
 )
 
 // NewController creates a new controller with the given name, reconciler and parameters and registers it with the manager.
+// The reconciliations are instrumented to report per-resource metrics.
 func NewController(mgr manager.Manager, name string, r reconcile.Reconciler, p operator.Parameters) (controller.Controller, error) {
-	return controller.New(name, mgr, controller.Options{Reconciler: r, MaxConcurrentReconciles: p.MaxConcurrentReconciles})
+	return controller.New(name, mgr, controller.Options{
+		Reconciler:              metrics.InstrumentReconciler(name, r),
+		MaxConcurrentReconciles: p.MaxConcurrentReconciles,
+	})
 }
 
 // NewReconciliationContext increments iteration, creates an apm transaction and initiates the logger. Returns context
@@ -0,0 +1,107 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package defaults
+
+import (
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const ndotsOption = "ndots"
+
+var (
+	podDNSPolicy corev1.DNSPolicy
+	podDNSConfig *corev1.PodDNSConfig
+)
+
+// SetPodDNSDefaults sets the DNS policy and the DNS configuration applied by default to the Pods of all the workloads
+// managed by the operator.
+func SetPodDNSDefaults(policy corev1.DNSPolicy, config *corev1.PodDNSConfig) {
+	podDNSPolicy = policy
+	podDNSConfig = config
+}
+
+// NewPodDNSDefaults validates the DNS settings of the operator and returns the corresponding default DNS policy and
+// DNS configuration. A zero ndots value leaves the ndots option unset.
+func NewPodDNSDefaults(policy string, nameservers, searches []string, ndots int) (corev1.DNSPolicy, *corev1.PodDNSConfig, error) {
+	dnsPolicy := corev1.DNSPolicy(policy)
+	switch dnsPolicy {
+	case "", corev1.DNSClusterFirst, corev1.DNSClusterFirstWithHostNet, corev1.DNSDefault:
+	case corev1.DNSNone:
+		if len(nameservers) == 0 {
+			return "", nil, fmt.Errorf("at least one nameserver is required with the %s DNS policy", corev1.DNSNone)
+		}
+	default:
+		return "", nil, fmt.Errorf("invalid DNS policy %q", policy)
+	}
+	if ndots < 0 || ndots > 15 {
+		return "", nil, fmt.Errorf("invalid ndots %d, must be between 0 (unset) and 15", ndots)
+	}
+
+	if len(nameservers) == 0 && len(searches) == 0 && ndots == 0 {
+		return dnsPolicy, nil, nil
+	}
+	config := &corev1.PodDNSConfig{Nameservers: nameservers, Searches: searches}
+	if ndots > 0 {
+		value := strconv.Itoa(ndots)
+		config.Options = []corev1.PodDNSConfigOption{{Name: ndotsOption, Value: &value}}
+	}
+	return dnsPolicy, config, nil
+}
+
+// setDNSDefaults applies the default DNS policy and DNS configuration of the operator to the pod template.
+// The DNS policy and the DNS configuration set by the user in the pod template take precedence: nameservers and
+// search domains are only set if the user did not specify any, and options are only added if the user did not
+// specify an option with the same name.
+func (b *PodTemplateBuilder) setDNSDefaults() {
+	spec := &b.PodTemplate.Spec
+	// Pods in the host network usually need the ClusterFirstWithHostNet policy set by the user
+	if spec.DNSPolicy == "" && !spec.HostNetwork {
+		spec.DNSPolicy = podDNSPolicy
+	}
+	if podDNSConfig == nil {
+		return
+	}
+	userConfig := spec.DNSConfig != nil
+	if !userConfig {
+		spec.DNSConfig = &corev1.PodDNSConfig{}
+	}
+	// nameservers and search domains of a different DNS policy than the default one would not make sense
+	if effectiveDNSPolicy(spec.DNSPolicy) == effectiveDNSPolicy(podDNSPolicy) {
+		if len(spec.DNSConfig.Nameservers) == 0 {
+			spec.DNSConfig.Nameservers = append(spec.DNSConfig.Nameservers, podDNSConfig.Nameservers...)
+		}
+		if len(spec.DNSConfig.Searches) == 0 {
+			spec.DNSConfig.Searches = append(spec.DNSConfig.Searches, podDNSConfig.Searches...)
+		}
+	}
+	for _, option := range podDNSConfig.Options {
+		if !hasDNSOption(spec.DNSConfig.Options, option.Name) {
+			spec.DNSConfig.Options = append(spec.DNSConfig.Options, *option.DeepCopy())
+		}
+	}
+	if !userConfig && len(spec.DNSConfig.Nameservers) == 0 && len(spec.DNSConfig.Searches) == 0 && len(spec.DNSConfig.Options) == 0 {
+		spec.DNSConfig = nil
+	}
+}
+
+// effectiveDNSPolicy returns the DNS policy applied by Kubernetes when the given policy is set.
+func effectiveDNSPolicy(policy corev1.DNSPolicy) corev1.DNSPolicy {
+	if policy == "" {
+		return corev1.DNSClusterFirst
+	}
+	return policy
+}
+
+func hasDNSOption(options []corev1.PodDNSConfigOption, name string) bool {
+	for _, option := range options {
+		if option.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package defaults
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestNewPodDNSDefaults(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      string
+		nameservers []string
+		searches    []string
+		ndots       int
+		wantPolicy  corev1.DNSPolicy
+		wantConfig  *corev1.PodDNSConfig
+		wantErr     string
+	}{
+		{
+			name: "no defaults",
+		},
+		{
+			name:       "ndots only",
+			ndots:      1,
+			wantConfig: &corev1.PodDNSConfig{Options: []corev1.PodDNSConfigOption{{Name: "ndots", Value: strPtr("1")}}},
+		},
+		{
+			name:        "None policy with trimmed search domains",
+			policy:      "None",
+			nameservers: []string{"10.96.0.10"},
+			searches:    []string{"svc.cluster.local"},
+			ndots:       2,
+			wantPolicy:  corev1.DNSNone,
+			wantConfig: &corev1.PodDNSConfig{
+				Nameservers: []string{"10.96.0.10"},
+				Searches:    []string{"svc.cluster.local"},
+				Options:     []corev1.PodDNSConfigOption{{Name: "ndots", Value: strPtr("2")}},
+			},
+		},
+		{
+			name:    "None policy without nameservers",
+			policy:  "None",
+			wantErr: "at least one nameserver is required with the None DNS policy",
+		},
+		{
+			name:    "invalid policy",
+			policy:  "ClusterLast",
+			wantErr: `invalid DNS policy "ClusterLast"`,
+		},
+		{
+			name:    "invalid ndots",
+			ndots:   16,
+			wantErr: "invalid ndots 16, must be between 0 (unset) and 15",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, config, err := NewPodDNSDefaults(tt.policy, tt.nameservers, tt.searches, tt.ndots)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantPolicy, policy)
+			require.Equal(t, tt.wantConfig, config)
+		})
+	}
+}
+
+func TestPodTemplateBuilder_setDNSDefaults(t *testing.T) {
+	ndots1 := corev1.PodDNSConfigOption{Name: "ndots", Value: strPtr("1")}
+	tests := []struct {
+		name          string
+		defaultPolicy corev1.DNSPolicy
+		defaultConfig *corev1.PodDNSConfig
+		podSpec       corev1.PodSpec
+		want          corev1.PodSpec
+	}{
+		{
+			name: "no defaults",
+		},
+		{
+			name:          "defaults applied to an empty pod template",
+			defaultConfig: &corev1.PodDNSConfig{Searches: []string{"example.com"}, Options: []corev1.PodDNSConfigOption{ndots1}},
+			want:          corev1.PodSpec{DNSConfig: &corev1.PodDNSConfig{Searches: []string{"example.com"}, Options: []corev1.PodDNSConfigOption{ndots1}}},
+		},
+		{
+			name:          "user options take precedence",
+			defaultConfig: &corev1.PodDNSConfig{Options: []corev1.PodDNSConfigOption{ndots1}},
+			podSpec: corev1.PodSpec{DNSConfig: &corev1.PodDNSConfig{Options: []corev1.PodDNSConfigOption{
+				{Name: "ndots", Value: strPtr("3")}, {Name: "single-request"},
+			}}},
+			want: corev1.PodSpec{DNSConfig: &corev1.PodDNSConfig{Options: []corev1.PodDNSConfigOption{
+				{Name: "ndots", Value: strPtr("3")}, {Name: "single-request"},
+			}}},
+		},
+		{
+			name:          "user nameservers and search domains take precedence",
+			defaultPolicy: corev1.DNSNone,
+			defaultConfig: &corev1.PodDNSConfig{Nameservers: []string{"10.96.0.10"}, Searches: []string{"svc.cluster.local"}, Options: []corev1.PodDNSConfigOption{ndots1}},
+			podSpec:       corev1.PodSpec{DNSConfig: &corev1.PodDNSConfig{Searches: []string{"ns.svc.cluster.local"}}},
+			want: corev1.PodSpec{DNSPolicy: corev1.DNSNone, DNSConfig: &corev1.PodDNSConfig{
+				Nameservers: []string{"10.96.0.10"},
+				Searches:    []string{"ns.svc.cluster.local"},
+				Options:     []corev1.PodDNSConfigOption{ndots1},
+			}},
+		},
+		{
+			name:          "nameservers and search domains not applied to a different user policy",
+			defaultPolicy: corev1.DNSNone,
+			defaultConfig: &corev1.PodDNSConfig{Nameservers: []string{"10.96.0.10"}, Searches: []string{"svc.cluster.local"}, Options: []corev1.PodDNSConfigOption{ndots1}},
+			podSpec:       corev1.PodSpec{DNSPolicy: corev1.DNSClusterFirst},
+			want:          corev1.PodSpec{DNSPolicy: corev1.DNSClusterFirst, DNSConfig: &corev1.PodDNSConfig{Options: []corev1.PodDNSConfigOption{ndots1}}},
+		},
+		{
+			name:          "default policy not applied to Pods in the host network",
+			defaultPolicy: corev1.DNSClusterFirst,
+			podSpec:       corev1.PodSpec{HostNetwork: true},
+			want:          corev1.PodSpec{HostNetwork: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetPodDNSDefaults(tt.defaultPolicy, tt.defaultConfig)
+			defer SetPodDNSDefaults("", nil)
+			b := &PodTemplateBuilder{PodTemplate: corev1.PodTemplateSpec{Spec: tt.podSpec}}
+			b.setDNSDefaults()
+			require.Equal(t, tt.want, b.PodTemplate.Spec)
+		})
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package defaults
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+var imagePullSecrets []corev1.LocalObjectReference
+
+// SetImagePullSecrets sets the names of the image pull Secrets applied by default to the Pods of all the workloads
+// managed by the operator.
+func SetImagePullSecrets(names []string) {
+	imagePullSecrets = nil
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		imagePullSecrets = append(imagePullSecrets, corev1.LocalObjectReference{Name: name})
+	}
+}
+
+// setImagePullSecretsDefaults applies the default image pull Secrets of the operator to the pod template. The image
+// pull Secrets set by the user in the pod template take precedence: the defaults are only set if the user did not
+// specify any.
+func (b *PodTemplateBuilder) setImagePullSecretsDefaults() {
+	if len(b.PodTemplate.Spec.ImagePullSecrets) > 0 || len(imagePullSecrets) == 0 {
+		return
+	}
+	b.PodTemplate.Spec.ImagePullSecrets = append([]corev1.LocalObjectReference{}, imagePullSecrets...)
+}
@@ -0,0 +1,50 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package defaults
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestPodTemplateBuilder_setImagePullSecretsDefaults(t *testing.T) {
+	tests := []struct {
+		name     string
+		defaults []string
+		podSpec  corev1.PodSpec
+		want     corev1.PodSpec
+	}{
+		{
+			name: "no defaults",
+		},
+		{
+			name:     "defaults applied to an empty pod template",
+			defaults: []string{"registry-a", "", "registry-b"},
+			want:     corev1.PodSpec{ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry-a"}, {Name: "registry-b"}}},
+		},
+		{
+			name:     "user image pull Secrets take precedence",
+			defaults: []string{"registry-a"},
+			podSpec:  corev1.PodSpec{ImagePullSecrets: []corev1.LocalObjectReference{{Name: "user-registry"}}},
+			want:     corev1.PodSpec{ImagePullSecrets: []corev1.LocalObjectReference{{Name: "user-registry"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetImagePullSecrets(tt.defaults)
+			defer SetImagePullSecrets(nil)
+			b := &PodTemplateBuilder{PodTemplate: corev1.PodTemplateSpec{Spec: tt.podSpec}}
+			b.setImagePullSecretsDefaults()
+			require.Equal(t, tt.want, b.PodTemplate.Spec)
+			// the defaults are not shared between pod templates
+			if len(b.PodTemplate.Spec.ImagePullSecrets) > 0 {
+				b.PodTemplate.Spec.ImagePullSecrets[0].Name = "changed"
+				require.NotEqual(t, "changed", imagePullSecrets[0].Name)
+			}
+		})
+	}
+}
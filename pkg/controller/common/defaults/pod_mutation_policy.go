@@ -0,0 +1,205 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package defaults
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	agentv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/agent/v1alpha1"
+	apmv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/apm/v1"
+	beatv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/beat/v1beta1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	entv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/enterprisesearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	logstashv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+	emsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/maps/v1alpha1"
+	otelv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/otel/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/container"
+)
+
+var (
+	// podMutationPolicy is applied to the Pods of all the workloads managed by the operator, nil if no policy is configured.
+	podMutationPolicy *PodMutationPolicy
+
+	// podMutationKinds are the kinds of the resources whose Pods can be mutated.
+	podMutationKinds = []string{
+		esv1.Kind, kbv1.Kind, apmv1.Kind, entv1.Kind, beatv1beta1.Kind, agentv1alpha1.Kind, emsv1alpha1.Kind,
+		logstashv1alpha1.Kind, otelv1alpha1.Kind,
+	}
+)
+
+// PodMutationPolicy lists the mutations applied by the operator to the Pods of the workloads it manages, for example to
+// inject the sidecar of a service mesh or of a security agent, or to enforce company-wide scheduling constraints.
+type PodMutationPolicy struct {
+	// Rules are applied in order to the Pods of the resources they select.
+	Rules []PodMutationRule `json:"rules"`
+}
+
+// PodMutationRule mutates the Pods of the resources of the selected kinds in the selected namespaces. The specification
+// of the Pods takes precedence over the mutations: existing containers, environment variables and runtime class are
+// not overridden.
+type PodMutationRule struct {
+	// Name of the rule, reported in the errors and in the logs.
+	Name string `json:"name"`
+	// Kinds of the resources whose Pods are mutated, for example Elasticsearch or Kibana. All kinds if empty.
+	Kinds []string `json:"kinds,omitempty"`
+	// Namespaces of the resources whose Pods are mutated. All namespaces if empty.
+	Namespaces []string `json:"namespaces,omitempty"`
+	// InitContainers are appended to the init containers of the Pods, unless an init container with the same name exists.
+	InitContainers []corev1.Container `json:"initContainers,omitempty"`
+	// Containers are appended to the containers of the Pods as sidecars, unless a container with the same name exists.
+	Containers []corev1.Container `json:"containers,omitempty"`
+	// Env is added to the containers and init containers injected by the rule, unless they already define the variables.
+	Env []corev1.EnvVar `json:"env,omitempty"`
+	// ApplyEnvToAllContainers adds Env to all the containers and init containers of the Pods, including the ones running
+	// the Elastic Stack applications. Changing Env then restarts all the selected Pods.
+	ApplyEnvToAllContainers bool `json:"applyEnvToAllContainers,omitempty"`
+	// Tolerations are appended to the tolerations of the Pods, unless they already exist.
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// RuntimeClassName is set on the Pods which do not specify a runtime class.
+	RuntimeClassName *string `json:"runtimeClassName,omitempty"`
+}
+
+// selects returns true if the rule applies to the Pods of a resource of the given kind in the given namespace.
+func (r PodMutationRule) selects(kind, namespace string) bool {
+	return (len(r.Kinds) == 0 || slices.Contains(r.Kinds, kind)) &&
+		(len(r.Namespaces) == 0 || slices.Contains(r.Namespaces, namespace))
+}
+
+func (r PodMutationRule) validate() error {
+	for _, kind := range r.Kinds {
+		if !slices.Contains(podMutationKinds, kind) {
+			return fmt.Errorf("unknown kind %s, expected one of %v", kind, podMutationKinds)
+		}
+	}
+	for _, containers := range [][]corev1.Container{r.InitContainers, r.Containers} {
+		for _, c := range containers {
+			if c.Name == "" || c.Image == "" {
+				return fmt.Errorf("the name and the image of the containers are mandatory")
+			}
+		}
+	}
+	for _, env := range r.Env {
+		if env.Name == "" {
+			return fmt.Errorf("the name of the environment variables is mandatory")
+		}
+	}
+	if len(r.Env) > 0 && len(r.InitContainers) == 0 && len(r.Containers) == 0 && !r.ApplyEnvToAllContainers {
+		return fmt.Errorf("the environment variables are only added to the containers of the rule, unless applyEnvToAllContainers is set")
+	}
+	if r.RuntimeClassName != nil && *r.RuntimeClassName == "" {
+		return fmt.Errorf("the runtime class name cannot be empty")
+	}
+	return nil
+}
+
+// ParsePodMutationPolicy parses and validates the given YAML Pod mutation policy.
+func ParsePodMutationPolicy(data []byte) (*PodMutationPolicy, error) {
+	var policy PodMutationPolicy
+	if err := yaml.UnmarshalStrict(data, &policy); err != nil {
+		return nil, fmt.Errorf("while parsing the Pod mutation policy: %w", err)
+	}
+	names := make(map[string]struct{}, len(policy.Rules))
+	for _, rule := range policy.Rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("the name of the rules of the Pod mutation policy is mandatory")
+		}
+		if _, exists := names[rule.Name]; exists {
+			return nil, fmt.Errorf("duplicate rule %s in the Pod mutation policy", rule.Name)
+		}
+		names[rule.Name] = struct{}{}
+		if err := rule.validate(); err != nil {
+			return nil, fmt.Errorf("invalid rule %s in the Pod mutation policy: %w", rule.Name, err)
+		}
+	}
+	return &policy, nil
+}
+
+// LoadPodMutationPolicy reads and validates the Pod mutation policy stored in the given file.
+func LoadPodMutationPolicy(path string) (*PodMutationPolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("while reading the Pod mutation policy: %w", err)
+	}
+	return ParsePodMutationPolicy(data)
+}
+
+// SetPodMutationPolicy sets the policy applied to the Pods of all the workloads managed by the operator.
+func SetPodMutationPolicy(policy *PodMutationPolicy) {
+	podMutationPolicy = policy
+}
+
+// WithPodMutationPolicy applies the rules of the Pod mutation policy of the operator which select the given kind and
+// namespace of the resource owning the Pods. It must be called once the pod template is fully built, so that the
+// defaults of the operator are not applied to the injected containers.
+func (b *PodTemplateBuilder) WithPodMutationPolicy(kind, namespace string) *PodTemplateBuilder {
+	if podMutationPolicy == nil {
+		return b
+	}
+	spec := &b.PodTemplate.Spec
+	for _, rule := range podMutationPolicy.Rules {
+		if !rule.selects(kind, namespace) {
+			continue
+		}
+		spec.InitContainers = appendMissingContainers(spec.InitContainers, withEnv(rule.InitContainers, rule.Env))
+		spec.Containers = appendMissingContainers(spec.Containers, withEnv(rule.Containers, rule.Env))
+		// the containers of the applications are only changed on demand, as it restarts all the selected Pods
+		if rule.ApplyEnvToAllContainers {
+			for i := range spec.InitContainers {
+				container.NewDefaulter(&spec.InitContainers[i]).WithEnv(deepCopyEnv(rule.Env))
+			}
+			for i := range spec.Containers {
+				container.NewDefaulter(&spec.Containers[i]).WithEnv(deepCopyEnv(rule.Env))
+			}
+		}
+		for _, toleration := range rule.Tolerations {
+			if !slices.ContainsFunc(spec.Tolerations, func(existing corev1.Toleration) bool { return existing.MatchToleration(&toleration) }) {
+				spec.Tolerations = append(spec.Tolerations, *toleration.DeepCopy())
+			}
+		}
+		if spec.RuntimeClassName == nil && rule.RuntimeClassName != nil {
+			spec.RuntimeClassName = ptr.To(*rule.RuntimeClassName)
+		}
+	}
+	// the main container may have been moved by the append of the sidecars
+	b.setContainerDefaulter()
+	return b
+}
+
+// appendMissingContainers appends deep copies of the given containers, unless a container with the same name exists.
+func appendMissingContainers(containers []corev1.Container, toAdd []corev1.Container) []corev1.Container {
+	for _, c := range toAdd {
+		if !slices.ContainsFunc(containers, func(existing corev1.Container) bool { return existing.Name == c.Name }) {
+			containers = append(containers, *c.DeepCopy())
+		}
+	}
+	return containers
+}
+
+// withEnv returns deep copies of the given containers with the given environment variables, unless they already define
+// them.
+func withEnv(containers []corev1.Container, vars []corev1.EnvVar) []corev1.Container {
+	result := make([]corev1.Container, 0, len(containers))
+	for _, c := range containers {
+		cp := *c.DeepCopy()
+		container.NewDefaulter(&cp).WithEnv(deepCopyEnv(vars))
+		result = append(result, cp)
+	}
+	return result
+}
+
+func deepCopyEnv(vars []corev1.EnvVar) []corev1.EnvVar {
+	cp := make([]corev1.EnvVar, 0, len(vars))
+	for _, v := range vars {
+		cp = append(cp, *v.DeepCopy())
+	}
+	return cp
+}
@@ -0,0 +1,230 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package defaults
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestParsePodMutationPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    *PodMutationPolicy
+		wantErr string
+	}{
+		{
+			name: "valid policy",
+			data: `
+rules:
+- name: agent
+  kinds: [Elasticsearch, Kibana]
+  namespaces: [production]
+  containers:
+  - name: agent
+    image: agent:1.0
+  env:
+  - name: HTTPS_PROXY
+    value: http://proxy:3128
+  runtimeClassName: gvisor
+`,
+			want: &PodMutationPolicy{Rules: []PodMutationRule{{
+				Name:             "agent",
+				Kinds:            []string{"Elasticsearch", "Kibana"},
+				Namespaces:       []string{"production"},
+				Containers:       []corev1.Container{{Name: "agent", Image: "agent:1.0"}},
+				Env:              []corev1.EnvVar{{Name: "HTTPS_PROXY", Value: "http://proxy:3128"}},
+				RuntimeClassName: ptr.To("gvisor"),
+			}}},
+		},
+		{
+			name:    "unknown field",
+			data:    "rules:\n- name: a\n  sidecars: []\n",
+			wantErr: "while parsing the Pod mutation policy",
+		},
+		{
+			name:    "rule without name",
+			data:    "rules:\n- kinds: [Kibana]\n",
+			wantErr: "the name of the rules of the Pod mutation policy is mandatory",
+		},
+		{
+			name:    "duplicate rule",
+			data:    "rules:\n- name: a\n- name: a\n",
+			wantErr: "duplicate rule a in the Pod mutation policy",
+		},
+		{
+			name:    "unknown kind",
+			data:    "rules:\n- name: a\n  kinds: [Kibanana]\n",
+			wantErr: "invalid rule a in the Pod mutation policy: unknown kind Kibanana",
+		},
+		{
+			name:    "container without image",
+			data:    "rules:\n- name: a\n  initContainers:\n  - name: init\n",
+			wantErr: "invalid rule a in the Pod mutation policy: the name and the image of the containers are mandatory",
+		},
+		{
+			name:    "env without containers",
+			data:    "rules:\n- name: a\n  env:\n  - name: HTTPS_PROXY\n    value: http://proxy:3128\n",
+			wantErr: "invalid rule a in the Pod mutation policy: the environment variables are only added to the containers of the rule, unless applyEnvToAllContainers is set",
+		},
+		{
+			name: "env in all containers",
+			data: "rules:\n- name: a\n  env:\n  - name: HTTPS_PROXY\n    value: http://proxy:3128\n  applyEnvToAllContainers: true\n",
+			want: &PodMutationPolicy{Rules: []PodMutationRule{{
+				Name:                    "a",
+				Env:                     []corev1.EnvVar{{Name: "HTTPS_PROXY", Value: "http://proxy:3128"}},
+				ApplyEnvToAllContainers: true,
+			}}},
+		},
+		{
+			name:    "empty runtime class",
+			data:    "rules:\n- name: a\n  runtimeClassName: \"\"\n",
+			wantErr: "invalid rule a in the Pod mutation policy: the runtime class name cannot be empty",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePodMutationPolicy([]byte(tt.data))
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPodTemplateBuilder_WithPodMutationPolicy(t *testing.T) {
+	policy := &PodMutationPolicy{Rules: []PodMutationRule{
+		{
+			Name:           "all",
+			InitContainers: []corev1.Container{{Name: "mesh-init", Image: "mesh:1.0"}},
+			Containers:     []corev1.Container{{Name: "mesh-proxy", Image: "mesh:1.0"}},
+			Env:            []corev1.EnvVar{{Name: "PROXY", Value: "mesh"}},
+		},
+		{
+			Name:             "production",
+			Kinds:            []string{"Elasticsearch"},
+			Namespaces:       []string{"production"},
+			Tolerations:      []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "elastic", Effect: corev1.TaintEffectNoSchedule}},
+			RuntimeClassName: ptr.To("gvisor"),
+		},
+	}}
+	tests := []struct {
+		name      string
+		policy    *PodMutationPolicy
+		kind      string
+		namespace string
+		podSpec   corev1.PodSpec
+		want      corev1.PodSpec
+	}{
+		{
+			name:      "no policy",
+			kind:      "Elasticsearch",
+			namespace: "production",
+			podSpec:   corev1.PodSpec{Containers: []corev1.Container{{Name: "main"}}},
+			want:      corev1.PodSpec{Containers: []corev1.Container{{Name: "main"}}},
+		},
+		{
+			name:      "rules selecting the kind and the namespace",
+			policy:    policy,
+			kind:      "Elasticsearch",
+			namespace: "production",
+			podSpec:   corev1.PodSpec{Containers: []corev1.Container{{Name: "main"}}},
+			want: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "mesh-init", Image: "mesh:1.0", Env: []corev1.EnvVar{{Name: "PROXY", Value: "mesh"}}}},
+				Containers: []corev1.Container{
+					{Name: "main"},
+					{Name: "mesh-proxy", Image: "mesh:1.0", Env: []corev1.EnvVar{{Name: "PROXY", Value: "mesh"}}},
+				},
+				Tolerations:      []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "elastic", Effect: corev1.TaintEffectNoSchedule}},
+				RuntimeClassName: ptr.To("gvisor"),
+			},
+		},
+		{
+			name:      "rules not selecting the kind are ignored",
+			policy:    policy,
+			kind:      "Kibana",
+			namespace: "production",
+			podSpec:   corev1.PodSpec{Containers: []corev1.Container{{Name: "main"}}},
+			want: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "mesh-init", Image: "mesh:1.0", Env: []corev1.EnvVar{{Name: "PROXY", Value: "mesh"}}}},
+				Containers: []corev1.Container{
+					{Name: "main"},
+					{Name: "mesh-proxy", Image: "mesh:1.0", Env: []corev1.EnvVar{{Name: "PROXY", Value: "mesh"}}},
+				},
+			},
+		},
+		{
+			name:      "the pod template takes precedence",
+			policy:    policy,
+			kind:      "Elasticsearch",
+			namespace: "production",
+			podSpec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "main", Env: []corev1.EnvVar{{Name: "PROXY", Value: "none"}}},
+					{Name: "mesh-proxy", Image: "custom-mesh:2.0"},
+				},
+				Tolerations:      []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "elastic", Effect: corev1.TaintEffectNoSchedule}},
+				RuntimeClassName: ptr.To("kata"),
+			},
+			want: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "mesh-init", Image: "mesh:1.0", Env: []corev1.EnvVar{{Name: "PROXY", Value: "mesh"}}}},
+				Containers: []corev1.Container{
+					{Name: "main", Env: []corev1.EnvVar{{Name: "PROXY", Value: "none"}}},
+					{Name: "mesh-proxy", Image: "custom-mesh:2.0"},
+				},
+				Tolerations:      []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "elastic", Effect: corev1.TaintEffectNoSchedule}},
+				RuntimeClassName: ptr.To("kata"),
+			},
+		},
+		{
+			name: "env applied to all the containers",
+			policy: &PodMutationPolicy{Rules: []PodMutationRule{{
+				Name:                    "proxy",
+				Env:                     []corev1.EnvVar{{Name: "PROXY", Value: "mesh"}},
+				ApplyEnvToAllContainers: true,
+			}}},
+			kind:      "Elasticsearch",
+			namespace: "production",
+			podSpec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "init"}},
+				Containers: []corev1.Container{
+					{Name: "main"},
+					{Name: "sidecar", Env: []corev1.EnvVar{{Name: "PROXY", Value: "none"}}},
+				},
+			},
+			want: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "init", Env: []corev1.EnvVar{{Name: "PROXY", Value: "mesh"}}}},
+				Containers: []corev1.Container{
+					{Name: "main", Env: []corev1.EnvVar{{Name: "PROXY", Value: "mesh"}}},
+					{Name: "sidecar", Env: []corev1.EnvVar{{Name: "PROXY", Value: "none"}}},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetPodMutationPolicy(tt.policy)
+			defer SetPodMutationPolicy(nil)
+			b := &PodTemplateBuilder{PodTemplate: corev1.PodTemplateSpec{Spec: tt.podSpec}, containerName: "main"}
+			b.setContainerDefaulter()
+			b.WithPodMutationPolicy(tt.kind, tt.namespace)
+			require.Equal(t, tt.want, b.PodTemplate.Spec)
+			// the main container is still the one updated by the builder
+			require.Equal(t, "main", b.containerDefaulter.Container().Name)
+			// the policy is not shared between pod templates
+			if tt.policy != nil {
+				b.PodTemplate.Spec.InitContainers[0].Env[0].Value = "changed"
+				require.Equal(t, "mesh", tt.policy.Rules[0].Env[0].Value)
+			}
+		})
+	}
+}
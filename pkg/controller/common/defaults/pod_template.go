@@ -72,7 +72,7 @@ func (b *PodTemplateBuilder) setContainerDefaulter() {
 }
 
 // setDefaults sets up a default Container in the pod template,
-// and disables service account token auto mount.
+// disables service account token auto mount, and applies the DNS and image pull Secrets defaults of the operator.
 func (b *PodTemplateBuilder) setDefaults() *PodTemplateBuilder {
 	userContainer := b.MainContainer()
 	if userContainer == nil {
@@ -89,6 +89,9 @@ func (b *PodTemplateBuilder) setDefaults() *PodTemplateBuilder {
 		b.PodTemplate.Spec.AutomountServiceAccountToken = &varFalse
 	}
 
+	b.setDNSDefaults()
+	b.setImagePullSecretsDefaults()
+
 	return b
 }
 
@@ -130,6 +133,15 @@ func (b *PodTemplateBuilder) WithAffinity(affinity *corev1.Affinity) *PodTemplat
 	return b
 }
 
+// WithTopologySpreadConstraints sets default topology spread constraints, unless already provided in the template.
+// An empty list of constraints in the spec is not overridden.
+func (b *PodTemplateBuilder) WithTopologySpreadConstraints(constraints ...corev1.TopologySpreadConstraint) *PodTemplateBuilder {
+	if b.PodTemplate.Spec.TopologySpreadConstraints == nil {
+		b.PodTemplate.Spec.TopologySpreadConstraints = constraints
+	}
+	return b
+}
+
 // WithPorts appends the given ports to the Container ports, unless already provided in the template.
 func (b *PodTemplateBuilder) WithPorts(ports []corev1.ContainerPort) *PodTemplateBuilder {
 	b.containerDefaulter.WithPorts(ports)
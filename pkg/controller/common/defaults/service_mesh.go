@@ -0,0 +1,50 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package defaults
+
+import (
+	"strconv"
+	"strings"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+const (
+	// IstioRewriteAppHTTPProbersAnnotation makes the Istio sidecar rewrite the HTTP probes of the Pod, so that they are
+	// not rejected when mutual TLS is enforced.
+	IstioRewriteAppHTTPProbersAnnotation = "sidecar.istio.io/rewriteAppHTTPProbers"
+	// IstioProxyConfigAnnotation overrides the configuration of the Istio sidecar of the Pod.
+	IstioProxyConfigAnnotation = "proxy.istio.io/config"
+	// IstioExcludeInboundPortsAnnotation lists the inbound ports not intercepted by the Istio sidecar of the Pod.
+	IstioExcludeInboundPortsAnnotation = "traffic.sidecar.istio.io/excludeInboundPorts"
+	// IstioExcludeOutboundPortsAnnotation lists the outbound ports not intercepted by the Istio sidecar of the Pod.
+	IstioExcludeOutboundPortsAnnotation = "traffic.sidecar.istio.io/excludeOutboundPorts"
+
+	// istioHoldApplicationUntilProxyStarts delays the start of the application until the Istio sidecar is ready, so
+	// that the connections made at startup, for example to Elasticsearch, go through the mesh.
+	istioHoldApplicationUntilProxyStarts = `{"holdApplicationUntilProxyStarts": true}`
+)
+
+// WithServiceMesh annotates the Pods for the service mesh the HTTP layer is integrated with, if any. The excluded ports
+// are not intercepted by the sidecar of the mesh, for example because they are already encrypted by the application.
+// Annotations set in the pod template take precedence.
+func (b *PodTemplateBuilder) WithServiceMesh(mesh commonv1.ServiceMesh, excludedPorts ...int) *PodTemplateBuilder {
+	if mesh != commonv1.IstioServiceMesh {
+		return b
+	}
+	annotations := map[string]string{
+		IstioRewriteAppHTTPProbersAnnotation: "true",
+		IstioProxyConfigAnnotation:           istioHoldApplicationUntilProxyStarts,
+	}
+	if len(excludedPorts) > 0 {
+		ports := make([]string, 0, len(excludedPorts))
+		for _, port := range excludedPorts {
+			ports = append(ports, strconv.Itoa(port))
+		}
+		annotations[IstioExcludeInboundPortsAnnotation] = strings.Join(ports, ",")
+		annotations[IstioExcludeOutboundPortsAnnotation] = strings.Join(ports, ",")
+	}
+	return b.WithAnnotations(annotations)
+}
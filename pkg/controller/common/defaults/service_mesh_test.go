@@ -0,0 +1,68 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package defaults
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+func TestPodTemplateBuilder_WithServiceMesh(t *testing.T) {
+	tests := []struct {
+		name          string
+		mesh          commonv1.ServiceMesh
+		excludedPorts []int
+		annotations   map[string]string
+		want          map[string]string
+	}{
+		{
+			name: "no service mesh",
+			want: nil,
+		},
+		{
+			name: "istio",
+			mesh: commonv1.IstioServiceMesh,
+			want: map[string]string{
+				IstioRewriteAppHTTPProbersAnnotation: "true",
+				IstioProxyConfigAnnotation:           `{"holdApplicationUntilProxyStarts": true}`,
+			},
+		},
+		{
+			name:          "istio with excluded ports",
+			mesh:          commonv1.IstioServiceMesh,
+			excludedPorts: []int{9300, 9443},
+			want: map[string]string{
+				IstioRewriteAppHTTPProbersAnnotation: "true",
+				IstioProxyConfigAnnotation:           `{"holdApplicationUntilProxyStarts": true}`,
+				IstioExcludeInboundPortsAnnotation:   "9300,9443",
+				IstioExcludeOutboundPortsAnnotation:  "9300,9443",
+			},
+		},
+		{
+			name:          "annotations of the pod template take precedence",
+			mesh:          commonv1.IstioServiceMesh,
+			excludedPorts: []int{9300},
+			annotations:   map[string]string{IstioExcludeOutboundPortsAnnotation: "9300,443"},
+			want: map[string]string{
+				IstioRewriteAppHTTPProbersAnnotation: "true",
+				IstioProxyConfigAnnotation:           `{"holdApplicationUntilProxyStarts": true}`,
+				IstioExcludeInboundPortsAnnotation:   "9300",
+				IstioExcludeOutboundPortsAnnotation:  "9300,443",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewPodTemplateBuilder(corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}, "main")
+			b.WithServiceMesh(tt.mesh, tt.excludedPorts...)
+			require.Equal(t, tt.want, b.PodTemplate.Annotations)
+		})
+	}
+}
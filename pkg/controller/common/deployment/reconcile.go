@@ -68,8 +68,10 @@ func Reconcile(
 		Expected:   &expected,
 		Reconciled: reconciled,
 		NeedsUpdate: func() bool {
-			// compare hash of the deployment at the time it was built
-			return hash.GetTemplateHashLabel(reconciled.Labels) != hash.GetTemplateHashLabel(expected.Labels)
+			// compare hash of the deployment at the time it was built, and resume the deployment if it was paused while
+			// its Pods were resized in place
+			return hash.GetTemplateHashLabel(reconciled.Labels) != hash.GetTemplateHashLabel(expected.Labels) ||
+				reconciled.Spec.Paused != expected.Spec.Paused
 		},
 		UpdateReconciled: func() {
 			// set expected annotations and labels, but don't remove existing ones
@@ -0,0 +1,156 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package deployment
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
+	commonpod "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/pod"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
+)
+
+const (
+	// resizableTemplateHashAnnotation is the hash of the Pod template of a Deployment without the resources of its
+	// containers, to detect the templates that only differ by their resources.
+	resizableTemplateHashAnnotation = "common.k8s.elastic.co/resizable-template-hash"
+	// revisionAnnotation is the revision of a Deployment and of its ReplicaSets, set by the Deployment controller.
+	revisionAnnotation = "deployment.kubernetes.io/revision"
+)
+
+// ResizePodsInPlace resizes in place the containers of the Pods of the given Deployment when its Pod template only
+// differs from the expected one by resources that the kubelet can change without restarting the containers, rather
+// than rolling out new Pods. memoryResizable returns whether the application running in the given container can use a
+// new amount of memory without being restarted.
+// Once all the Pods are resized, the Pod template of their ReplicaSet is updated with the new resources so that the
+// Deployment controller adopts it for the expected template instead of rolling out a new ReplicaSet. It returns the
+// Deployment to reconcile, which keeps its current Pod template while the Pods are being resized, and true in that case.
+func ResizePodsInPlace(
+	ctx context.Context,
+	c k8s.Client,
+	expected appsv1.Deployment,
+	memoryResizable func(corev1.Container) bool,
+) (appsv1.Deployment, bool, error) {
+	log := ulog.FromContext(ctx)
+	expected = *expected.DeepCopy()
+	expected.Annotations = maps.Merge(expected.Annotations, map[string]string{
+		resizableTemplateHashAnnotation: resizableTemplateHash(expected.Spec.Template),
+	})
+
+	var current appsv1.Deployment
+	if err := c.Get(ctx, k8s.ExtractNamespacedName(&expected), &current); err != nil {
+		if apierrors.IsNotFound(err) {
+			return expected, false, nil
+		}
+		return expected, false, err
+	}
+	if current.Annotations[resizableTemplateHashAnnotation] != expected.Annotations[resizableTemplateHashAnnotation] ||
+		!commonpod.ResourcesChanged(current.Spec.Template.Spec, expected.Spec.Template.Spec) ||
+		!commonpod.ResizableWithoutRestart(current.Spec.Template.Spec, expected.Spec.Template.Spec, memoryResizable) ||
+		!rolledOut(current) {
+		// other changes are rolled out by the Deployment controller
+		return expected, false, nil
+	}
+	replicaSet, err := currentReplicaSet(ctx, c, current)
+	if err != nil || replicaSet == nil {
+		return expected, false, err
+	}
+
+	pods, err := k8s.PodsMatchingLabels(c, replicaSet.Namespace, replicaSet.Spec.Selector.MatchLabels)
+	if err != nil {
+		return expected, false, err
+	}
+	resizing := false
+	for _, pod := range pods {
+		switch commonpod.GetResizeStatus(pod, expected.Spec.Template.Spec) {
+		case commonpod.ResizeNotRequested:
+			log.Info("Resizing Pod in place", "namespace", pod.Namespace, "pod_name", pod.Name)
+			err := commonpod.Resize(ctx, c, pod, expected.Spec.Template.Spec)
+			if apierrors.IsInvalid(err) || apierrors.IsForbidden(err) {
+				// the resize is rejected, for example because it would change the QoS class of the Pod
+				log.Info("Pod cannot be resized in place, rolling out the Deployment", "namespace", pod.Namespace, "pod_name", pod.Name, "reason", err.Error())
+				return expected, false, nil
+			}
+			if err != nil {
+				return expected, false, err
+			}
+			resizing = true
+		case commonpod.ResizeInProgress:
+			resizing = true
+		case commonpod.ResizeNotFeasible:
+			// new Pods can be scheduled on Kubernetes nodes with enough capacity
+			return expected, false, nil
+		case commonpod.ResizeComplete:
+		}
+	}
+	if resizing {
+		kept := expected
+		kept.Spec.Template = current.Spec.Template
+		return kept, true, nil
+	}
+
+	// The Deployment is paused while the template of the ReplicaSet is updated, so that the Deployment controller does not
+	// create a ReplicaSet for the current template in the meantime. It is resumed by the update of its template.
+	if !current.Spec.Paused {
+		paused := current.DeepCopy()
+		paused.Spec.Paused = true
+		if err := c.Patch(ctx, paused, client.MergeFrom(&current)); err != nil {
+			return expected, false, err
+		}
+	}
+	resized := replicaSet.DeepCopy()
+	for i, container := range resized.Spec.Template.Spec.Containers {
+		if expectedContainer := commonpod.ContainerByName(expected.Spec.Template.Spec, container.Name); expectedContainer != nil {
+			resized.Spec.Template.Spec.Containers[i].Resources = *expectedContainer.Resources.DeepCopy()
+		}
+	}
+	if err := c.Patch(ctx, resized, client.MergeFrom(replicaSet)); err != nil {
+		return expected, false, err
+	}
+	log.Info("Pods resized in place", "namespace", expected.Namespace, "deployment_name", expected.Name)
+	return expected, false, nil
+}
+
+// resizableTemplateHash returns the hash of the given Pod template without the resources of its containers.
+func resizableTemplateHash(template corev1.PodTemplateSpec) string {
+	template = *template.DeepCopy()
+	for i := range template.Spec.Containers {
+		template.Spec.Containers[i].Resources = corev1.ResourceRequirements{}
+	}
+	return hash.HashObject(template)
+}
+
+// rolledOut returns true if all the Pods of the given Deployment run its current Pod template.
+func rolledOut(d appsv1.Deployment) bool {
+	return d.Spec.Replicas != nil &&
+		d.Status.ObservedGeneration >= d.Generation &&
+		d.Status.UpdatedReplicas == *d.Spec.Replicas &&
+		d.Status.Replicas == d.Status.UpdatedReplicas
+}
+
+// currentReplicaSet returns the ReplicaSet of the current revision of the given Deployment, or nil if it does not exist.
+func currentReplicaSet(ctx context.Context, c k8s.Client, d appsv1.Deployment) (*appsv1.ReplicaSet, error) {
+	if d.Spec.Selector == nil {
+		return nil, nil
+	}
+	var replicaSets appsv1.ReplicaSetList
+	if err := c.List(ctx, &replicaSets, client.InNamespace(d.Namespace), client.MatchingLabels(d.Spec.Selector.MatchLabels)); err != nil {
+		return nil, err
+	}
+	for i, replicaSet := range replicaSets.Items {
+		if metav1.IsControlledBy(&replicaSet, &d) && replicaSet.Annotations[revisionAnnotation] == d.Annotations[revisionAnnotation] {
+			return &replicaSets.Items[i], nil
+		}
+	}
+	return nil, nil
+}
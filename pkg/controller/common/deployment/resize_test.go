@@ -0,0 +1,257 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package deployment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	controllerscheme "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/scheme"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func resizeTemplate(cpu, memory string, env ...corev1.EnvVar) corev1.PodTemplateSpec {
+	return corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "kb"}},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name: "kibana",
+			Env:  env,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu), corev1.ResourceMemory: resource.MustParse(memory)},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(memory)},
+			},
+		}}},
+	}
+}
+
+// resizeFixtures returns a rolled out Deployment with the given template, its ReplicaSet and its Pod running with the
+// given resources.
+func resizeFixtures(template corev1.PodTemplateSpec, podCPU, podMemory string, podStatus corev1.PodStatus) (*appsv1.Deployment, *appsv1.ReplicaSet, *corev1.Pod) {
+	d := New(Params{Name: "kb", Namespace: "ns", Selector: map[string]string{"app": "kb"}, PodTemplateSpec: template, Replicas: 1})
+	d.UID = "uid"
+	d.Generation = 2
+	d.Annotations = map[string]string{revisionAnnotation: "2", resizableTemplateHashAnnotation: resizableTemplateHash(template)}
+	d.Status = appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 1, UpdatedReplicas: 1}
+
+	replicaSetLabels := map[string]string{"app": "kb", "pod-template-hash": "abc"}
+	replicaSetTemplate := *template.DeepCopy()
+	replicaSetTemplate.Labels = replicaSetLabels
+	replicaSet := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "ns",
+			Name:            "kb-abc",
+			Labels:          replicaSetLabels,
+			Annotations:     map[string]string{revisionAnnotation: "2"},
+			OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "kb", UID: "uid", Controller: ptr.To(true)}},
+		},
+		Spec: appsv1.ReplicaSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: replicaSetLabels},
+			Template: replicaSetTemplate,
+		},
+	}
+
+	podSpec := resizeTemplate(podCPU, podMemory).Spec
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb-abc-xyz", Labels: replicaSetLabels},
+		Spec:       podSpec,
+		Status:     podStatus,
+	}
+	return &d, replicaSet, pod
+}
+
+func running(cpu, memory string) corev1.PodStatus {
+	resources := resizeTemplate(cpu, memory).Spec.Containers[0].Resources
+	return corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{Name: "kibana", Resources: &resources}}}
+}
+
+func TestResizePodsInPlace(t *testing.T) {
+	fixedHeap := corev1.EnvVar{Name: "NODE_OPTIONS", Value: "--max-old-space-size=2048"}
+	memoryResizable := func(container corev1.Container) bool { return len(container.Env) > 0 }
+	tests := []struct {
+		name              string
+		current           corev1.PodTemplateSpec
+		expected          corev1.PodTemplateSpec
+		podCPU            string
+		podStatus         corev1.PodStatus
+		noDeployment      bool
+		wantResizing      bool
+		wantTemplate      corev1.PodTemplateSpec
+		wantPodCPU        string
+		wantReplicaSetCPU string
+		wantPaused        bool
+	}{
+		{
+			name:              "no Deployment yet",
+			current:           resizeTemplate("1", "2Gi"),
+			expected:          resizeTemplate("2", "2Gi"),
+			podCPU:            "1",
+			podStatus:         running("1", "2Gi"),
+			noDeployment:      true,
+			wantTemplate:      resizeTemplate("2", "2Gi"),
+			wantPodCPU:        "1",
+			wantReplicaSetCPU: "1",
+		},
+		{
+			name:              "no resources change",
+			current:           resizeTemplate("1", "2Gi"),
+			expected:          resizeTemplate("1", "2Gi"),
+			podCPU:            "1",
+			podStatus:         running("1", "2Gi"),
+			wantTemplate:      resizeTemplate("1", "2Gi"),
+			wantPodCPU:        "1",
+			wantReplicaSetCPU: "1",
+		},
+		{
+			name:              "other change: roll out the Deployment",
+			current:           resizeTemplate("1", "2Gi"),
+			expected:          resizeTemplate("2", "2Gi", corev1.EnvVar{Name: "FOO", Value: "bar"}),
+			podCPU:            "1",
+			podStatus:         running("1", "2Gi"),
+			wantTemplate:      resizeTemplate("2", "2Gi", corev1.EnvVar{Name: "FOO", Value: "bar"}),
+			wantPodCPU:        "1",
+			wantReplicaSetCPU: "1",
+		},
+		{
+			name:              "memory change with a heap sized from the memory: roll out the Deployment",
+			current:           resizeTemplate("1", "2Gi"),
+			expected:          resizeTemplate("1", "4Gi"),
+			podCPU:            "1",
+			podStatus:         running("1", "2Gi"),
+			wantTemplate:      resizeTemplate("1", "4Gi"),
+			wantPodCPU:        "1",
+			wantReplicaSetCPU: "1",
+		},
+		{
+			name:              "CPU change: resize the Pods and keep the current template",
+			current:           resizeTemplate("1", "2Gi"),
+			expected:          resizeTemplate("2", "2Gi"),
+			podCPU:            "1",
+			podStatus:         running("1", "2Gi"),
+			wantResizing:      true,
+			wantTemplate:      resizeTemplate("1", "2Gi"),
+			wantPodCPU:        "2",
+			wantReplicaSetCPU: "1",
+		},
+		{
+			name:              "memory change with a fixed heap size: resize the Pods and keep the current template",
+			current:           resizeTemplate("1", "2Gi", fixedHeap),
+			expected:          resizeTemplate("1", "4Gi", fixedHeap),
+			podCPU:            "1",
+			podStatus:         running("1", "2Gi"),
+			wantResizing:      true,
+			wantTemplate:      resizeTemplate("1", "2Gi", fixedHeap),
+			wantPodCPU:        "1",
+			wantReplicaSetCPU: "1",
+		},
+		{
+			name:              "resize in progress: wait",
+			current:           resizeTemplate("1", "2Gi"),
+			expected:          resizeTemplate("2", "2Gi"),
+			podCPU:            "2",
+			podStatus:         running("1", "2Gi"),
+			wantResizing:      true,
+			wantTemplate:      resizeTemplate("1", "2Gi"),
+			wantPodCPU:        "2",
+			wantReplicaSetCPU: "1",
+		},
+		{
+			name:     "resize not feasible: roll out the Deployment",
+			current:  resizeTemplate("1", "2Gi"),
+			expected: resizeTemplate("2", "2Gi"),
+			podCPU:   "2",
+			podStatus: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{Type: "PodResizePending", Status: corev1.ConditionTrue, Reason: "Infeasible"}},
+			},
+			wantTemplate:      resizeTemplate("2", "2Gi"),
+			wantPodCPU:        "2",
+			wantReplicaSetCPU: "1",
+		},
+		{
+			name:              "resize complete: update the ReplicaSet template while the Deployment is paused",
+			current:           resizeTemplate("1", "2Gi"),
+			expected:          resizeTemplate("2", "2Gi"),
+			podCPU:            "2",
+			podStatus:         running("2", "2Gi"),
+			wantTemplate:      resizeTemplate("2", "2Gi"),
+			wantPodCPU:        "2",
+			wantReplicaSetCPU: "2",
+			wantPaused:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, replicaSet, pod := resizeFixtures(tt.current, tt.podCPU, "2Gi", tt.podStatus)
+			c := k8s.NewFakeClient(replicaSet, pod)
+			if !tt.noDeployment {
+				c = k8s.NewFakeClient(current, replicaSet, pod)
+			}
+			expected := New(Params{Name: "kb", Namespace: "ns", Selector: map[string]string{"app": "kb"}, PodTemplateSpec: tt.expected, Replicas: 1})
+
+			toReconcile, resizing, err := ResizePodsInPlace(context.Background(), c, expected, memoryResizable)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantResizing, resizing)
+			assert.True(t, equality.Semantic.DeepEqual(tt.wantTemplate, toReconcile.Spec.Template), "unexpected template to reconcile")
+			assert.Equal(t, resizableTemplateHash(tt.expected), toReconcile.Annotations[resizableTemplateHashAnnotation])
+			assert.False(t, toReconcile.Spec.Paused)
+
+			var resizedPod corev1.Pod
+			require.NoError(t, c.Get(context.Background(), k8s.ExtractNamespacedName(pod), &resizedPod))
+			assert.Equal(t, resource.MustParse(tt.wantPodCPU), resizedPod.Spec.Containers[0].Resources.Requests[corev1.ResourceCPU])
+			var updatedReplicaSet appsv1.ReplicaSet
+			require.NoError(t, c.Get(context.Background(), k8s.ExtractNamespacedName(replicaSet), &updatedReplicaSet))
+			assert.Equal(t, resource.MustParse(tt.wantReplicaSetCPU), updatedReplicaSet.Spec.Template.Spec.Containers[0].Resources.Requests[corev1.ResourceCPU])
+			if tt.noDeployment {
+				return
+			}
+			var updatedDeployment appsv1.Deployment
+			require.NoError(t, c.Get(context.Background(), k8s.ExtractNamespacedName(current), &updatedDeployment))
+			assert.Equal(t, tt.wantPaused, updatedDeployment.Spec.Paused)
+		})
+	}
+}
+
+func TestResizePodsInPlace_ResumeDeployment(t *testing.T) {
+	controllerscheme.SetupScheme()
+	current, replicaSet, pod := resizeFixtures(resizeTemplate("1", "2Gi"), "2", "2Gi", running("2", "2Gi"))
+	c := k8s.NewFakeClient(current, replicaSet, pod)
+	owner := kbv1.Kibana{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb"}}
+	expected := New(Params{Name: "kb", Namespace: "ns", Selector: map[string]string{"app": "kb"}, PodTemplateSpec: resizeTemplate("2", "2Gi"), Replicas: 1})
+
+	toReconcile, resizing, err := ResizePodsInPlace(context.Background(), c, expected, func(corev1.Container) bool { return true })
+	require.NoError(t, err)
+	require.False(t, resizing)
+	// the template of the ReplicaSet now matches the expected template, the Deployment controller adopts it
+	var updatedReplicaSet appsv1.ReplicaSet
+	require.NoError(t, c.Get(context.Background(), k8s.ExtractNamespacedName(replicaSet), &updatedReplicaSet))
+	updatedReplicaSet.Spec.Template.Labels = expected.Spec.Template.Labels
+	require.True(t, equality.Semantic.DeepEqual(expected.Spec.Template, updatedReplicaSet.Spec.Template))
+
+	reconciled, err := Reconcile(context.Background(), c, toReconcile, &owner)
+	require.NoError(t, err)
+	require.False(t, reconciled.Spec.Paused)
+	require.True(t, equality.Semantic.DeepEqual(expected.Spec.Template, reconciled.Spec.Template))
+
+	// a Deployment left paused is resumed even if its specification did not change
+	paused := reconciled.DeepCopy()
+	paused.Spec.Paused = true
+	require.NoError(t, c.Update(context.Background(), paused))
+	reconciled, err = Reconcile(context.Background(), c, toReconcile, &owner)
+	require.NoError(t, err)
+	require.False(t, reconciled.Spec.Paused)
+	var retrieved appsv1.Deployment
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "kb"}, &retrieved))
+	require.False(t, retrieved.Spec.Paused)
+}
@@ -0,0 +1,125 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package effectivespec
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
+)
+
+const (
+	// Suffix is appended to the name of a resource to name the ConfigMap publishing its effective specification.
+	Suffix = "effective-spec"
+	// Type is the type label value of the ConfigMaps publishing the effective specification of the resources.
+	Type = "effective-spec"
+	// SpecKey is the key of the ConfigMap holding the specification of the resource.
+	SpecKey = "spec.yaml"
+)
+
+// publish enables the publication of the effective specification of the managed resources.
+var publish bool
+
+// SetPublish enables or disables the publication of the effective specification of the managed resources.
+func SetPublish(enabled bool) {
+	publish = enabled
+}
+
+// Reconcile publishes the effective specification of the given resource in the ConfigMap with the given name, or
+// deletes the ConfigMap if the publication is disabled. The ConfigMap holds the specification of the resource under the
+// spec.yaml key, and the specification of each workload built by the operator for the resource under the
+// <workload name>.yaml key: it is the specification the operator acts on, once its defaults, the policies and the
+// pod template of the resource are merged.
+func Reconcile(ctx context.Context, c k8s.Client, owner client.Object, name string, spec interface{}, workloads ...client.Object) error {
+	nsn := types.NamespacedName{Namespace: owner.GetNamespace(), Name: name}
+	if !publish {
+		return deleteConfigMap(ctx, c, nsn)
+	}
+	data, err := effectiveSpecData(spec, workloads)
+	if err != nil {
+		return err
+	}
+	expected := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: nsn.Namespace,
+			Name:      nsn.Name,
+			Labels: map[string]string{
+				commonv1.TypeLabelName: Type,
+			},
+		},
+		Data: data,
+	}
+	reconciled := &corev1.ConfigMap{}
+	return reconciler.ReconcileResource(reconciler.Params{
+		Context:    ctx,
+		Client:     c,
+		Owner:      owner,
+		Expected:   &expected,
+		Reconciled: reconciled,
+		NeedsUpdate: func() bool {
+			return !reflect.DeepEqual(expected.Data, reconciled.Data) || !maps.IsSubset(expected.Labels, reconciled.Labels)
+		},
+		UpdateReconciled: func() {
+			reconciled.Labels = maps.Merge(reconciled.Labels, expected.Labels)
+			reconciled.Data = expected.Data
+		},
+	})
+}
+
+// effectiveSpecData returns the YAML specifications of the resource and of its workloads, indexed by ConfigMap key.
+func effectiveSpecData(spec interface{}, workloads []client.Object) (map[string]string, error) {
+	data := make(map[string]string, len(workloads)+1)
+	specs := map[string]interface{}{SpecKey: spec}
+	for _, workload := range workloads {
+		key := workload.GetName() + ".yaml"
+		switch w := workload.(type) {
+		case *appsv1.StatefulSet:
+			specs[key] = w.Spec
+		case *appsv1.Deployment:
+			specs[key] = w.Spec
+		case *appsv1.DaemonSet:
+			specs[key] = w.Spec
+		default:
+			return nil, fmt.Errorf("unsupported workload %T", workload)
+		}
+	}
+	for key, s := range specs {
+		bytes, err := yaml.Marshal(s)
+		if err != nil {
+			return nil, fmt.Errorf("while serializing the effective specification %s: %w", key, err)
+		}
+		data[key] = string(bytes)
+	}
+	return data, nil
+}
+
+// deleteConfigMap deletes the ConfigMap publishing the effective specification of a resource, if it exists.
+func deleteConfigMap(ctx context.Context, c k8s.Client, nsn types.NamespacedName) error {
+	var cm corev1.ConfigMap
+	if err := c.Get(ctx, nsn, &cm); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if cm.Labels[commonv1.TypeLabelName] != Type {
+		// not created by the operator
+		return nil
+	}
+	return client.IgnoreNotFound(c.Delete(ctx, &cm))
+}
@@ -0,0 +1,91 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package effectivespec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func TestReconcile(t *testing.T) {
+	kb := kbv1.Kibana{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb", UID: "uid"},
+		Spec:       kbv1.KibanaSpec{Version: "8.15.0", Count: 1},
+	}
+	deploy := appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb-kb"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To[int32](1),
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Containers:       []corev1.Container{{Name: "kibana", Image: "kibana:8.15.0"}},
+				ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry"}},
+			}},
+		},
+	}
+	nsn := types.NamespacedName{Namespace: "ns", Name: "kb-kb-effective-spec"}
+	c := k8s.NewFakeClient(&kb)
+	ctx := context.Background()
+
+	// publication disabled: nothing is created
+	SetPublish(false)
+	require.NoError(t, Reconcile(ctx, c, &kb, nsn.Name, kb.Spec, &deploy))
+	require.True(t, apierrors.IsNotFound(c.Get(ctx, nsn, &corev1.ConfigMap{})))
+
+	// publication enabled: the specifications of the resource and of its workloads are published
+	SetPublish(true)
+	defer SetPublish(false)
+	require.NoError(t, Reconcile(ctx, c, &kb, nsn.Name, kb.Spec, &deploy))
+	var cm corev1.ConfigMap
+	require.NoError(t, c.Get(ctx, nsn, &cm))
+	require.Equal(t, Type, cm.Labels[commonv1.TypeLabelName])
+	require.Len(t, cm.OwnerReferences, 1)
+	require.Equal(t, kb.UID, cm.OwnerReferences[0].UID)
+	var spec kbv1.KibanaSpec
+	require.NoError(t, yaml.Unmarshal([]byte(cm.Data[SpecKey]), &spec))
+	require.Equal(t, kb.Spec, spec)
+	var deploySpec appsv1.DeploymentSpec
+	require.NoError(t, yaml.Unmarshal([]byte(cm.Data["kb-kb.yaml"]), &deploySpec))
+	require.Equal(t, deploy.Spec, deploySpec)
+
+	// the ConfigMap is updated with the specification
+	kb.Spec.Count = 3
+	require.NoError(t, Reconcile(ctx, c, &kb, nsn.Name, kb.Spec, &deploy))
+	require.NoError(t, c.Get(ctx, nsn, &cm))
+	require.NoError(t, yaml.Unmarshal([]byte(cm.Data[SpecKey]), &spec))
+	require.Equal(t, int32(3), spec.Count)
+
+	// unsupported workloads are rejected
+	require.Error(t, Reconcile(ctx, c, &kb, nsn.Name, kb.Spec, &corev1.Pod{}))
+
+	// publication disabled again: the ConfigMap is deleted
+	SetPublish(false)
+	require.NoError(t, Reconcile(ctx, c, &kb, nsn.Name, kb.Spec, &deploy))
+	require.True(t, apierrors.IsNotFound(c.Get(ctx, nsn, &corev1.ConfigMap{})))
+}
+
+func TestReconcile_UserConfigMap(t *testing.T) {
+	kb := kbv1.Kibana{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb"}}
+	userCM := corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb-kb-effective-spec"}}
+	c := k8s.NewFakeClient(&kb, &userCM)
+	ctx := context.Background()
+
+	// a ConfigMap not created by the operator is not deleted
+	SetPublish(false)
+	require.NoError(t, Reconcile(ctx, c, &kb, userCM.Name, kb.Spec))
+	require.NoError(t, c.Get(ctx, k8s.ExtractNamespacedName(&userCM), &corev1.ConfigMap{}))
+}
@@ -6,18 +6,70 @@ package events
 
 // Event reasons for the Elastic stack controller
 const (
+	// EventReasonCertificateRotated describes events where a certificate authority managed by the operator was rotated.
+	EventReasonCertificateRotated = "CertificateRotated"
+	// EventReasonDataMigrationStarted describes events where the operator started migrating data away from nodes to be removed.
+	EventReasonDataMigrationStarted = "DataMigrationStarted"
+	// EventReasonDataMigrationCompleted describes events where data was migrated away from a node which can now be removed.
+	EventReasonDataMigrationCompleted = "DataMigrationCompleted"
 	// EventReasonDeprecated describes events that were due to a deprecated resource being submitted by the user.
 	EventReasonDeprecated = "Deprecated"
 	// EventReasonDelayed describes events where a requested change was delayed e.g. to prevent data loss.
 	EventReasonDelayed = "Delayed"
+	// EventReasonDownscaleBlocked describes events where nodes cannot be removed yet to preserve the availability of the cluster.
+	EventReasonDownscaleBlocked = "DownscaleBlocked"
+	// EventReasonDriftDetected describes events where a resource managed by the operator in Elasticsearch no longer matches
+	// its expected state.
+	EventReasonDriftDetected = "DriftDetected"
+	// EventReasonExpired describes events where a resource reached the end of its time to live and is deleted.
+	EventReasonExpired = "Expired"
 	// EventReasonInvalidLicense describes events where a user configured an invalid license for the operator.
 	EventReasonInvalidLicense = "InvalidLicense"
+	// EventReasonLicenseApplied describes events where the operator applied a license to a cluster.
+	EventReasonLicenseApplied = "LicenseApplied"
+	// EventReasonLicenseExpired describes events where the license of a cluster has expired.
+	EventReasonLicenseExpired = "LicenseExpired"
+	// EventReasonLicenseExpiring describes events where the license of a cluster expires soon.
+	EventReasonLicenseExpiring = "LicenseExpiring"
+	// EventReasonPodDeleted describes events where the operator deleted a Pod to apply a change, for example during a
+	// rolling upgrade.
+	EventReasonPodDeleted = "PodDeleted"
+	// EventReasonScaled describes events where resources are automatically scaled by the operator.
+	EventReasonScaled = "Scaled"
+	// EventReasonSchedulingCompromise describes events where Pods were scheduled in a way that does not satisfy the
+	// preferred topology constraints, for example several master nodes on the same Kubernetes node.
+	EventReasonSchedulingCompromise = "SchedulingCompromise"
+	// EventReasonSnapshotRestored describes events where the operator restored a snapshot in a cluster.
+	EventReasonSnapshotRestored = "SnapshotRestored"
+	// EventReasonSnapshotVerificationFailed describes events where the latest snapshots of a cluster could not be verified.
+	EventReasonSnapshotVerificationFailed = "SnapshotVerificationFailed"
+	// EventReasonRestartRequired describes events where Pods are restarted to apply a change that could otherwise be
+	// applied without restarting them.
+	EventReasonRestartRequired = "RestartRequired"
+	// EventReasonRestarted describes events where the Pods of a resource are restarted on request of the user.
+	EventReasonRestarted = "Restarted"
+	// EventReasonRestored describes events where a resource managed by the operator was deleted and has been restored.
+	EventReasonRestored = "Restored"
 	// EventReasonStalled describes events where a requested change is stalled and may not make progress without user
 	// intervention. There are transient states e.g. during a nodeSet rename where shards still do not have a place to
 	// move to until the new nodes come up and Elasticsearch will report a stalled shutdown. There are however also
 	// permanent states if the new topology requested by the user does not have enough space for the shards which requires
 	// user intervention to correct the mistake.
 	EventReasonStalled = "Stalled"
+	// EventReasonTaskStarted describes events where the operator started a maintenance task requested by the user.
+	EventReasonTaskStarted = "TaskStarted"
+	// EventReasonTaskSucceeded describes events where a maintenance task requested by the user completed successfully.
+	EventReasonTaskSucceeded = "TaskSucceeded"
+	// EventReasonTaskFailed describes events where a maintenance task requested by the user failed.
+	EventReasonTaskFailed = "TaskFailed"
+	// EventReasonUnsafeBootstrap describes events where a new cluster is bootstrapped through the unsafe bootstrap procedure.
+	EventReasonUnsafeBootstrap = "UnsafeBootstrap"
+	// EventReasonUpgradeBlocked describes events where a version upgrade cannot start because of issues that must be
+	// resolved first.
+	EventReasonUpgradeBlocked = "UpgradeBlocked"
+	// EventReasonUpgradePaused describes events where a version upgrade waits for an external analysis tool to approve
+	// its next step.
+	EventReasonUpgradePaused = "UpgradePaused"
 	// EventReasonUpgraded describes events where resources are upgraded.
 	EventReasonUpgraded = "Upgraded"
 	// EventReasonUnhealthy describes events where a stack deployments health was affected negatively.
@@ -26,6 +78,11 @@ const (
 	EventReasonUnexpected = "Unexpected"
 	// EventReasonValidation describes events that were due to an invalid resource being submitted by the user.
 	EventReasonValidation = "Validation"
+	// EventReasonVolumeSnapshotsCreated describes events where the operator created VolumeSnapshots of the volumes of a
+	// cluster before restarting its nodes.
+	EventReasonVolumeSnapshotsCreated = "VolumeSnapshotsCreated"
+	// EventReasonVolumeSnapshotFailed describes events where a VolumeSnapshot created by the operator failed.
+	EventReasonVolumeSnapshotFailed = "VolumeSnapshotFailed"
 )
 
 // Event reasons for Association controllers
@@ -0,0 +1,263 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
+)
+
+const (
+	// Group is the API group of the Gateway API resources.
+	Group = "gateway.networking.k8s.io"
+	// RouteAnnotation is set on the HTTP Service of a resource to the kind of the route reconciled to expose it.
+	RouteAnnotation = "common.k8s.elastic.co/gateway-route"
+
+	gatewayKind = "Gateway"
+	serviceKind = "Service"
+)
+
+var (
+	// HTTPRouteGVK is the GroupVersionKind of the Gateway API HTTPRoute resource. The Gateway API is not vendored, routes
+	// are handled as unstructured objects.
+	HTTPRouteGVK = schema.GroupVersionKind{Group: Group, Version: "v1", Kind: "HTTPRoute"}
+	// TLSRouteGVK is the GroupVersionKind of the Gateway API TLSRoute resource.
+	TLSRouteGVK = schema.GroupVersionKind{Group: Group, Version: "v1alpha2", Kind: "TLSRoute"}
+)
+
+// routeSpec mirrors the subset of the HTTPRoute and TLSRoute specs managed by the operator. Fields defaulted by the
+// Gateway API are set explicitly, so that the reconciled routes are not updated on each reconciliation.
+type routeSpec struct {
+	ParentRefs []parentRef `json:"parentRefs"`
+	Hostnames  []string    `json:"hostnames,omitempty"`
+	Rules      []routeRule `json:"rules"`
+}
+
+type parentRef struct {
+	Group       string `json:"group"`
+	Kind        string `json:"kind"`
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace,omitempty"`
+	SectionName string `json:"sectionName,omitempty"`
+	Port        *int32 `json:"port,omitempty"`
+}
+
+type routeRule struct {
+	Matches     []httpRouteMatch `json:"matches,omitempty"`
+	BackendRefs []backendRef     `json:"backendRefs"`
+}
+
+type httpRouteMatch struct {
+	Path httpPathMatch `json:"path"`
+}
+
+type httpPathMatch struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type backendRef struct {
+	Group  string `json:"group"`
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Port   int32  `json:"port"`
+	Weight int32  `json:"weight"`
+}
+
+// routeGVK returns the kind of route exposing the HTTP layer: TLS connections are passed through to the resource which
+// terminates TLS, plain HTTP requests are routed by the Gateway.
+func routeGVK(http commonv1.HTTPConfig) schema.GroupVersionKind {
+	if http.TLSEnabled() {
+		return TLSRouteGVK
+	}
+	return HTTPRouteGVK
+}
+
+func expectedSpec(http commonv1.HTTPConfig, svc corev1.Service) routeSpec {
+	gateway := http.Gateway
+	spec := routeSpec{Hostnames: gateway.Hostnames}
+	for _, ref := range gateway.ParentRefs {
+		spec.ParentRefs = append(spec.ParentRefs, parentRef{
+			Group:       Group,
+			Kind:        gatewayKind,
+			Name:        ref.Name,
+			Namespace:   ref.Namespace,
+			SectionName: ref.SectionName,
+			Port:        ref.Port,
+		})
+	}
+	rule := routeRule{BackendRefs: []backendRef{{
+		Kind:   serviceKind,
+		Name:   svc.Name,
+		Port:   servicePort(svc, http.Protocol()),
+		Weight: 1,
+	}}}
+	if !http.TLSEnabled() {
+		rule.Matches = []httpRouteMatch{{Path: httpPathMatch{Type: "PathPrefix", Value: "/"}}}
+	}
+	spec.Rules = []routeRule{rule}
+	return spec
+}
+
+// servicePort returns the port of the Service named after the protocol of the HTTP layer, or its first port.
+func servicePort(svc corev1.Service, protocol string) int32 {
+	for _, port := range svc.Spec.Ports {
+		if port.Name == protocol {
+			return port.Port
+		}
+	}
+	if len(svc.Spec.Ports) > 0 {
+		return svc.Spec.Ports[0].Port
+	}
+	return 0
+}
+
+func newRoute(gvk schema.GroupVersionKind, namespace, name string) *unstructured.Unstructured {
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(gvk)
+	route.SetNamespace(namespace)
+	route.SetName(name)
+	return route
+}
+
+// ReconcileRoute reconciles the Gateway API route, named after the given HTTP Service, which exposes the Service through
+// the Gateways configured in the HTTP layer configuration of the owner. An HTTPRoute is reconciled if TLS is disabled on
+// the HTTP layer, a TLSRoute passing the TLS connections through to the resource otherwise. The kind of the reconciled
+// route is recorded in an annotation of the Service, so that the Gateway API is only accessed if a Gateway is configured
+// or to delete the route previously reconciled once no Gateway is configured.
+func ReconcileRoute(
+	ctx context.Context,
+	c k8s.Client,
+	owner client.Object,
+	http commonv1.HTTPConfig,
+	svc corev1.Service,
+	labels map[string]string,
+) error {
+	recorded, hasRecorded := recordedRouteGVK(svc)
+	if http.Gateway == nil {
+		if !hasRecorded {
+			return nil
+		}
+		if err := deleteRoute(ctx, c, owner, recorded, svc.Name); err != nil {
+			return err
+		}
+		return setRouteAnnotation(ctx, c, svc, "")
+	}
+	gvk := routeGVK(http)
+	// delete the route of the other kind, which exists if TLS has been enabled or disabled on the HTTP layer
+	if hasRecorded && recorded != gvk {
+		if err := deleteRoute(ctx, c, owner, recorded, svc.Name); err != nil {
+			return err
+		}
+	}
+
+	spec, err := runtime.DefaultUnstructuredConverter.ToUnstructured(ptr.To(expectedSpec(http, svc)))
+	if err != nil {
+		return err
+	}
+	expected := newRoute(gvk, svc.Namespace, svc.Name)
+	expected.SetLabels(labels)
+	expected.Object["spec"] = spec
+
+	reconciled := newRoute(gvk, svc.Namespace, svc.Name)
+	err = reconciler.ReconcileResource(reconciler.Params{
+		Context:    ctx,
+		Client:     c,
+		Owner:      owner,
+		Expected:   expected,
+		Reconciled: reconciled,
+		NeedsUpdate: func() bool {
+			return !maps.IsSubset(labels, reconciled.GetLabels()) ||
+				!reflect.DeepEqual(expected.Object["spec"], reconciled.Object["spec"])
+		},
+		UpdateReconciled: func() {
+			reconciled.SetLabels(maps.Merge(reconciled.GetLabels(), labels))
+			reconciled.Object["spec"] = expected.Object["spec"]
+		},
+	})
+	if meta.IsNoMatchError(err) {
+		return fmt.Errorf("cannot create the %s exposing %s/%s, the Gateway API CRDs are not installed: %w", gvk.Kind, svc.Namespace, svc.Name, err)
+	}
+	if err != nil {
+		return err
+	}
+	return setRouteAnnotation(ctx, c, svc, gvk.Kind)
+}
+
+// DeleteRoute deletes the Gateway API route recorded in the annotation of the given HTTP Service, if any, before the
+// Service itself is deleted.
+func DeleteRoute(ctx context.Context, c k8s.Client, owner client.Object, svc corev1.Service) error {
+	recorded, hasRecorded := recordedRouteGVK(svc)
+	if !hasRecorded {
+		return nil
+	}
+	return deleteRoute(ctx, c, owner, recorded, svc.Name)
+}
+
+// recordedRouteGVK returns the kind of the route recorded in the annotation of the given HTTP Service, and false if no
+// route has been reconciled for the Service.
+func recordedRouteGVK(svc corev1.Service) (schema.GroupVersionKind, bool) {
+	switch svc.Annotations[RouteAnnotation] {
+	case HTTPRouteGVK.Kind:
+		return HTTPRouteGVK, true
+	case TLSRouteGVK.Kind:
+		return TLSRouteGVK, true
+	default:
+		return schema.GroupVersionKind{}, false
+	}
+}
+
+// setRouteAnnotation records the given kind of route in the annotation of the given Service, or removes the annotation
+// if the kind is empty.
+func setRouteAnnotation(ctx context.Context, c k8s.Client, svc corev1.Service, kind string) error {
+	if svc.Annotations[RouteAnnotation] == kind {
+		return nil
+	}
+	annotated := svc.DeepCopy()
+	if kind == "" {
+		delete(annotated.Annotations, RouteAnnotation)
+	} else {
+		annotated.Annotations = maps.Merge(annotated.Annotations, map[string]string{RouteAnnotation: kind})
+	}
+	return c.Patch(ctx, annotated, client.MergeFrom(&svc))
+}
+
+// deleteRoute deletes the route of the given kind controlled by the given owner if it exists.
+func deleteRoute(ctx context.Context, c k8s.Client, owner client.Object, gvk schema.GroupVersionKind, name string) error {
+	var route metav1.PartialObjectMetadata
+	route.SetGroupVersionKind(gvk)
+	if err := c.Get(ctx, types.NamespacedName{Namespace: owner.GetNamespace(), Name: name}, &route); err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil
+		}
+		return err
+	}
+	if !metav1.IsControlledBy(&route, owner) {
+		// do not delete a route created by the user with the same name
+		return nil
+	}
+	err := c.Delete(ctx, newRoute(gvk, owner.GetNamespace(), name))
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
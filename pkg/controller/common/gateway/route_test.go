@@ -0,0 +1,145 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package gateway
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+var (
+	kb  = kbv1.Kibana{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb", UID: "uid"}}
+	svc = corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb-kb-http"},
+		Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{
+			{Name: "metrics", Port: 9090},
+			{Name: "https", Port: 5601},
+		}},
+	}
+	nsn    = types.NamespacedName{Namespace: "ns", Name: "kb-kb-http"}
+	labels = map[string]string{"kibana.k8s.elastic.co/name": "kb"}
+)
+
+func getRoute(c k8s.Client, gvk schema.GroupVersionKind) (*unstructured.Unstructured, error) {
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(gvk)
+	return route, c.Get(context.Background(), nsn, route)
+}
+
+func getService(t *testing.T, c k8s.Client) corev1.Service {
+	t.Helper()
+	var reconciled corev1.Service
+	require.NoError(t, c.Get(context.Background(), nsn, &reconciled))
+	return reconciled
+}
+
+func TestReconcileRoute(t *testing.T) {
+	c := k8s.NewFakeClient(&kb, svc.DeepCopy())
+	ctx := context.Background()
+	http := commonv1.HTTPConfig{Gateway: &commonv1.GatewayConfig{
+		ParentRefs: []commonv1.GatewayParentRef{{Name: "gw", Namespace: "gateways", SectionName: "tls", Port: ptr.To[int32](443)}},
+		Hostnames:  []string{"kibana.example.com"},
+	}}
+
+	// TLS enabled: a TLSRoute passes the connections through to the HTTPS port of the Service
+	require.NoError(t, ReconcileRoute(ctx, c, &kb, http, getService(t, c), labels))
+	route, err := getRoute(c, TLSRouteGVK)
+	require.NoError(t, err)
+	require.Equal(t, labels, route.GetLabels())
+	require.True(t, metav1.IsControlledBy(route, &kb))
+	require.Equal(t, map[string]any{
+		"parentRefs": []any{map[string]any{
+			"group":       Group,
+			"kind":        "Gateway",
+			"name":        "gw",
+			"namespace":   "gateways",
+			"sectionName": "tls",
+			"port":        int64(443),
+		}},
+		"hostnames": []any{"kibana.example.com"},
+		"rules": []any{map[string]any{
+			"backendRefs": []any{map[string]any{
+				"group":  "",
+				"kind":   "Service",
+				"name":   "kb-kb-http",
+				"port":   int64(5601),
+				"weight": int64(1),
+			}},
+		}},
+	}, route.Object["spec"])
+	_, err = getRoute(c, HTTPRouteGVK)
+	require.True(t, apierrors.IsNotFound(err))
+	require.Equal(t, "TLSRoute", getService(t, c).Annotations[RouteAnnotation])
+
+	// TLS disabled: the TLSRoute is replaced by an HTTPRoute
+	http.TLS = commonv1.TLSOptions{SelfSignedCertificate: &commonv1.SelfSignedCertificate{Disabled: true}}
+	require.NoError(t, ReconcileRoute(ctx, c, &kb, http, getService(t, c), labels))
+	_, err = getRoute(c, TLSRouteGVK)
+	require.True(t, apierrors.IsNotFound(err))
+	route, err = getRoute(c, HTTPRouteGVK)
+	require.NoError(t, err)
+	rules, _, err := unstructured.NestedSlice(route.Object, "spec", "rules")
+	require.NoError(t, err)
+	require.Equal(t, []any{map[string]any{"path": map[string]any{"type": "PathPrefix", "value": "/"}}}, rules[0].(map[string]any)["matches"])
+	require.Equal(t, "HTTPRoute", getService(t, c).Annotations[RouteAnnotation])
+
+	// the route is updated with the specification
+	http.Gateway.Hostnames = nil
+	require.NoError(t, ReconcileRoute(ctx, c, &kb, http, getService(t, c), labels))
+	route, err = getRoute(c, HTTPRouteGVK)
+	require.NoError(t, err)
+	_, found, err := unstructured.NestedSlice(route.Object, "spec", "hostnames")
+	require.NoError(t, err)
+	require.False(t, found)
+
+	// no Gateway: the route is deleted
+	http.Gateway = nil
+	require.NoError(t, ReconcileRoute(ctx, c, &kb, http, getService(t, c), labels))
+	_, err = getRoute(c, HTTPRouteGVK)
+	require.True(t, apierrors.IsNotFound(err))
+	require.NotContains(t, getService(t, c).Annotations, RouteAnnotation)
+}
+
+func TestReconcileRoute_NoGateway(t *testing.T) {
+	// the Gateway API is not accessed if no Gateway is configured and no route has been reconciled
+	c := k8s.NewFailingClient(errors.New("unexpected request"))
+	require.NoError(t, ReconcileRoute(context.Background(), c, &kb, commonv1.HTTPConfig{}, svc, labels))
+	require.NoError(t, DeleteRoute(context.Background(), c, &kb, svc))
+}
+
+func TestDeleteRoute_UserRoute(t *testing.T) {
+	userRoute := &unstructured.Unstructured{}
+	userRoute.SetGroupVersionKind(HTTPRouteGVK)
+	userRoute.SetNamespace(nsn.Namespace)
+	userRoute.SetName(nsn.Name)
+	c := k8s.NewFakeClient(&kb, userRoute)
+	annotated := svc.DeepCopy()
+	annotated.Annotations = map[string]string{RouteAnnotation: "HTTPRoute"}
+
+	// a route not created by the operator is not deleted
+	require.NoError(t, DeleteRoute(context.Background(), c, &kb, *annotated))
+	_, err := getRoute(c, HTTPRouteGVK)
+	require.NoError(t, err)
+}
+
+func Test_servicePort(t *testing.T) {
+	require.Equal(t, int32(5601), servicePort(svc, "https"))
+	require.Equal(t, int32(9090), servicePort(svc, "http"))
+	require.Equal(t, int32(0), servicePort(corev1.Service{}, "http"))
+}
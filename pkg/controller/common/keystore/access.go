@@ -0,0 +1,66 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package keystore
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/rbac"
+)
+
+// accessReviewer reviews the access of the resources to the secure settings Secrets they reference in other namespaces.
+var accessReviewer rbac.AccessReviewer
+
+// SetAccessReviewer sets the AccessReviewer used to check that the ServiceAccount of a resource is allowed to get the
+// secure settings Secrets it references in other namespaces. These Secrets are ignored if no AccessReviewer is set.
+func SetAccessReviewer(reviewer rbac.AccessReviewer) {
+	accessReviewer = reviewer
+}
+
+// AllowedSecretSources returns the secure settings Secrets referenced in the resource, without the Secrets in other
+// namespaces that the ServiceAccount of the resource is not allowed to get.
+func AllowedSecretSources(ctx context.Context, recorder record.EventRecorder, hasKeystore HasKeystore) ([]commonv1.NamespacedSecretSource, error) {
+	secretSources := WatchedSecretNames(hasKeystore)
+	allowed := make([]commonv1.NamespacedSecretSource, 0, len(secretSources))
+	for _, source := range secretSources {
+		if source.Namespace == hasKeystore.GetNamespace() {
+			allowed = append(allowed, source)
+			continue
+		}
+		ok, err := secretAccessAllowed(ctx, hasKeystore, source)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			msg := "Secure settings secret not allowed"
+			ulog.FromContext(ctx).Info(msg, "namespace", source.Namespace, "secret_name", source.SecretName,
+				"service_account", hasKeystore.ServiceAccountName())
+			recorder.Event(hasKeystore, corev1.EventTypeWarning, events.EventReasonValidation,
+				fmt.Sprintf("%s: %s/%s", msg, source.Namespace, source.SecretName))
+			continue
+		}
+		allowed = append(allowed, source)
+	}
+	return allowed, nil
+}
+
+func secretAccessAllowed(ctx context.Context, hasKeystore HasKeystore, source commonv1.NamespacedSecretSource) (bool, error) {
+	if accessReviewer == nil {
+		return false, nil
+	}
+	secret := &corev1.Secret{
+		TypeMeta:   metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: source.Namespace, Name: source.SecretName},
+	}
+	return accessReviewer.AccessAllowed(ctx, hasKeystore.ServiceAccountName(), hasKeystore.GetNamespace(), secret)
+}
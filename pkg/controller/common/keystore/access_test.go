@@ -0,0 +1,93 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package keystore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/rbac"
+)
+
+// fakeAccessReviewer allows the given ServiceAccount to get the Secrets of the given namespace.
+type fakeAccessReviewer struct {
+	serviceAccount string
+	namespace      string
+}
+
+func (f fakeAccessReviewer) AccessAllowed(_ context.Context, serviceAccount string, _ string, object runtime.Object) (bool, error) {
+	secret, ok := object.(*corev1.Secret)
+	if !ok || secret.Kind != "Secret" {
+		return false, nil
+	}
+	return serviceAccount == f.serviceAccount && secret.Namespace == f.namespace, nil
+}
+
+func TestAllowedSecretSources(t *testing.T) {
+	kb := kbv1.Kibana{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "kibana"},
+		Spec: kbv1.KibanaSpec{
+			ServiceAccountName: "kibana-sa",
+			SecureSettings: []commonv1.SecretSource{
+				{SecretName: "local"},
+				{SecretName: "same-namespace", Namespace: "namespace"},
+				{SecretName: "central", Namespace: "central", Entries: []commonv1.KeyToPath{{Key: "key1"}}},
+			},
+		},
+	}
+	local := []commonv1.NamespacedSecretSource{
+		{Namespace: "namespace", SecretName: "local"},
+		{Namespace: "namespace", SecretName: "same-namespace"},
+	}
+	central := commonv1.NamespacedSecretSource{Namespace: "central", SecretName: "central", Entries: []commonv1.KeyToPath{{Key: "key1"}}}
+
+	tests := []struct {
+		name      string
+		reviewer  rbac.AccessReviewer
+		want      []commonv1.NamespacedSecretSource
+		wantEvent bool
+	}{
+		{
+			name:      "no access reviewer: secrets in other namespaces are ignored",
+			reviewer:  nil,
+			want:      local,
+			wantEvent: true,
+		},
+		{
+			name:     "access allowed",
+			reviewer: fakeAccessReviewer{serviceAccount: "kibana-sa", namespace: "central"},
+			want:     []commonv1.NamespacedSecretSource{local[0], local[1], central},
+		},
+		{
+			name:      "access denied",
+			reviewer:  fakeAccessReviewer{serviceAccount: "other-sa", namespace: "central"},
+			want:      local,
+			wantEvent: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetAccessReviewer(tt.reviewer)
+			defer SetAccessReviewer(nil)
+			recorder := record.NewFakeRecorder(10)
+
+			got, err := AllowedSecretSources(context.Background(), recorder, &kb)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+			if tt.wantEvent {
+				require.Equal(t, "Warning Validation Secure settings secret not allowed: central/central", <-recorder.Events)
+			}
+			require.Empty(t, recorder.Events)
+		})
+	}
+}
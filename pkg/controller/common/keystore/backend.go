@@ -0,0 +1,133 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package keystore
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// SecretBackend reads secure settings from an external secret store, such as HashiCorp Vault or AWS Secrets Manager.
+type SecretBackend interface {
+	// Read returns the key-value pairs of the secret at the given path.
+	Read(ctx context.Context, path string) (map[string][]byte, error)
+}
+
+var (
+	// secretBackends are the external secret backends configured in the operator, by name.
+	secretBackends map[string]SecretBackend
+	// secretBackendsCache caches the secrets read from the external secret backends.
+	secretBackendsCache = newBackendCache(0)
+)
+
+// SetSecretBackends sets the external secret backends, by name, from which secure settings can be read instead of
+// Kubernetes Secrets. The secrets read from a backend are cached, and refreshed after the given interval.
+func SetSecretBackends(backends map[string]SecretBackend, refreshInterval time.Duration) {
+	secretBackends = backends
+	secretBackendsCache = newBackendCache(refreshInterval)
+}
+
+// BackendSecretSources returns the secure settings of the resource which are read from an external secret backend.
+func BackendSecretSources(hasKeystore HasKeystore) []commonv1.SecretSource {
+	var sources []commonv1.SecretSource
+	for _, s := range hasKeystore.SecureSettings() {
+		if s.Backend != "" {
+			sources = append(sources, s)
+		}
+	}
+	return sources
+}
+
+// RefreshResult returns the reconciliation state requeuing the reconciliation of the resource to refresh its secure
+// settings, if some of them are read from an external secret backend, as changes in the backends cannot be watched
+// contrary to changes in Kubernetes Secrets. It does not prevent the resource from being considered reconciled.
+func RefreshResult(hasKeystore HasKeystore) reconciler.ReconciliationState {
+	if len(BackendSecretSources(hasKeystore)) == 0 || secretBackendsCache.ttl == 0 {
+		return reconciler.ReconciliationState{}
+	}
+	return reconciler.RequeueAfter(secretBackendsCache.ttl).ReconciliationComplete()
+}
+
+// BackendSecrets reads the secure settings of the resource from the external secret backends. Each secret read from a
+// backend is returned as a Secret which is not stored in Kubernetes, projected according to its entries. Secrets from
+// backends which are not configured in the operator are ignored.
+func BackendSecrets(ctx context.Context, recorder record.EventRecorder, hasKeystore HasKeystore) ([]corev1.Secret, error) {
+	sources := BackendSecretSources(hasKeystore)
+	secrets := make([]corev1.Secret, 0, len(sources))
+	for _, source := range sources {
+		backend, exists := secretBackends[source.Backend]
+		if !exists {
+			msg := "Secure settings secret backend not configured"
+			ulog.FromContext(ctx).Info(msg, "backend", source.Backend, "secret_name", source.SecretName)
+			recorder.Event(hasKeystore, corev1.EventTypeWarning, events.EventReasonUnexpected, fmt.Sprintf("%s: %s", msg, source.Backend))
+			continue
+		}
+		data, err := secretBackendsCache.read(ctx, backend, source.Backend, source.SecretName)
+		if err != nil {
+			return nil, fmt.Errorf("while reading secure settings secret %s from %s: %w", source.SecretName, source.Backend, err)
+		}
+		secret, err := projectEntries(corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: source.SecretName}, Data: data}, source.Entries)
+		if err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, *secret)
+	}
+	return secrets, nil
+}
+
+type cachedSecret struct {
+	data     map[string][]byte
+	deadline time.Time
+}
+
+// backendCache caches the secrets read from the external secret backends until their time to live expires.
+type backendCache struct {
+	ttl     time.Duration
+	lock    sync.Mutex
+	secrets map[string]cachedSecret
+	now     func() time.Time
+}
+
+func newBackendCache(ttl time.Duration) *backendCache {
+	return &backendCache{ttl: ttl, secrets: map[string]cachedSecret{}, now: time.Now}
+}
+
+// read returns the secret at the given path of the given backend, from the cache if it has not expired yet.
+func (c *backendCache) read(ctx context.Context, backend SecretBackend, backendName, path string) (map[string][]byte, error) {
+	key := backendName + ":" + path
+	c.lock.Lock()
+	cached, exists := c.secrets[key]
+	c.lock.Unlock()
+	if exists && c.now().Before(cached.deadline) {
+		return cached.data, nil
+	}
+
+	data, err := backend.Read(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	now := c.now()
+	// forget the expired secrets, which may not be referenced anymore
+	for k, secret := range c.secrets {
+		if !now.Before(secret.deadline) {
+			delete(c.secrets, k)
+		}
+	}
+	c.secrets[key] = cachedSecret{data: data, deadline: now.Add(c.ttl)}
+	return data, nil
+}
@@ -0,0 +1,92 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package keystore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+)
+
+// fakeSecretBackend serves the secrets it holds, and counts the reads.
+type fakeSecretBackend struct {
+	secrets map[string]map[string][]byte
+	reads   int
+}
+
+func (f *fakeSecretBackend) Read(_ context.Context, path string) (map[string][]byte, error) {
+	f.reads++
+	secret, exists := f.secrets[path]
+	if !exists {
+		return nil, errors.New("not found")
+	}
+	return secret, nil
+}
+
+func TestBackendSecrets(t *testing.T) {
+	kb := kbv1.Kibana{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "kibana"},
+		Spec: kbv1.KibanaSpec{
+			SecureSettings: []commonv1.SecretSource{
+				{SecretName: "local"},
+				{SecretName: "secret/data/kibana", Backend: "vault", Entries: []commonv1.KeyToPath{{Key: "key1", Path: "path1"}}},
+				{SecretName: "kibana", Backend: "aws-secrets-manager"},
+			},
+		},
+	}
+	vault := &fakeSecretBackend{secrets: map[string]map[string][]byte{
+		"secret/data/kibana": {"key1": []byte("value1"), "key2": []byte("value2")},
+	}}
+	SetSecretBackends(map[string]SecretBackend{"vault": vault}, time.Minute)
+	defer SetSecretBackends(nil, 0)
+	now := time.Now()
+	secretBackendsCache.now = func() time.Time { return now }
+
+	require.Equal(t, []commonv1.NamespacedSecretSource{{Namespace: "namespace", SecretName: "local"}}, WatchedSecretNames(&kb))
+	require.Equal(t, reconcile.Result{RequeueAfter: time.Minute}, RefreshResult(&kb).Result)
+
+	recorder := record.NewFakeRecorder(10)
+	secrets, err := BackendSecrets(context.Background(), recorder, &kb)
+	require.NoError(t, err)
+	require.Equal(t, []corev1.Secret{{
+		ObjectMeta: metav1.ObjectMeta{Name: "secret/data/kibana"},
+		Data:       map[string][]byte{"path1": []byte("value1")},
+	}}, secrets)
+	require.Equal(t, "Warning Unexpected Secure settings secret backend not configured: aws-secrets-manager", <-recorder.Events)
+
+	// secrets are cached until they expire
+	vault.secrets["secret/data/kibana"] = map[string][]byte{"key1": []byte("rotated")}
+	secrets, err = BackendSecrets(context.Background(), recorder, &kb)
+	require.NoError(t, err)
+	require.Equal(t, []byte("value1"), secrets[0].Data["path1"])
+	require.Equal(t, 1, vault.reads)
+	now = now.Add(time.Minute)
+	secrets, err = BackendSecrets(context.Background(), recorder, &kb)
+	require.NoError(t, err)
+	require.Equal(t, []byte("rotated"), secrets[0].Data["path1"])
+	require.Equal(t, 2, vault.reads)
+
+	delete(vault.secrets, "secret/data/kibana")
+	now = now.Add(time.Minute)
+	_, err = BackendSecrets(context.Background(), recorder, &kb)
+	require.EqualError(t, err, "while reading secure settings secret secret/data/kibana from vault: not found")
+}
+
+func TestRefreshResult(t *testing.T) {
+	kb := kbv1.Kibana{Spec: kbv1.KibanaSpec{SecureSettings: []commonv1.SecretSource{{SecretName: "local"}}}}
+	SetSecretBackends(nil, time.Minute)
+	defer SetSecretBackends(nil, 0)
+	require.Equal(t, reconcile.Result{}, RefreshResult(&kb).Result)
+}
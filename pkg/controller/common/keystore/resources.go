@@ -32,14 +32,24 @@ type HasKeystore interface {
 	metav1.Object
 	runtime.Object
 	SecureSettings() []commonv1.SecretSource
+	// ServiceAccountName is used to check the access of the resource to the secure settings Secrets in other namespaces.
+	ServiceAccountName() string
 }
 
-// WatchedSecretNames returns the name of all secure settings secrets to watch.
+// WatchedSecretNames returns the name of all secure settings secrets to watch. Secrets read from external secret
+// backends are not included.
 func WatchedSecretNames(hasKeystore HasKeystore) []commonv1.NamespacedSecretSource {
 	nsns := make([]commonv1.NamespacedSecretSource, 0, len(hasKeystore.SecureSettings()))
 	for _, s := range hasKeystore.SecureSettings() {
+		if s.Backend != "" {
+			continue
+		}
+		namespace := s.Namespace
+		if namespace == "" {
+			namespace = hasKeystore.GetNamespace()
+		}
 		nsns = append(nsns, commonv1.NamespacedSecretSource{
-			Namespace:  hasKeystore.GetNamespace(),
+			Namespace:  namespace,
 			SecretName: s.SecretName,
 			Entries:    s.Entries,
 		})
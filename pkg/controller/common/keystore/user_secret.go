@@ -36,6 +36,8 @@ const secureSettingsSecretSuffix = "secure-settings"
 // The user provided secrets are then aggregated into a single secret.
 // This secret is mounted into the pods for secure settings to be injected into a keystore.
 // The user-provided secrets are watched to reconcile on any change.
+// Secrets in other namespaces are only used if the ServiceAccount of the resource is allowed to get them.
+// Secure settings can also be read from external secret backends configured in the operator.
 // The user secret resource version is returned along with the volume, so that
 // any change in the user secret leads to pod rotation.
 func secureSettingsVolume(
@@ -50,15 +52,19 @@ func secureSettingsVolume(
 	watcher := k8s.ExtractNamespacedName(hasKeystore)
 
 	// user-provided Secrets referenced in the resource
-	secretSources := WatchedSecretNames(hasKeystore)
-	// Additional sources, introduced to load remote cluster keys.
-	secretSources = append(secretSources, additionalSources...)
+	resourceSecretSources := WatchedSecretNames(hasKeystore)
 	// user-provided Secrets referenced in a StackConfigPolicy that configures the resource
 	policySecretSources, err := stackconfigpolicy.GetSecureSettingsSecretSourcesForResources(ctx, r.K8sClient(), hasKeystore, hasKeystore.GetObjectKind().GroupVersionKind().Kind)
 	if err != nil {
 		return nil, "", pkgerrors.Wrap(err, "fail to get secure settings secret sources")
 	}
-	secretSources = append(secretSources, policySecretSources...)
+	// Additional sources, introduced to load remote cluster keys, are handled like the policy ones.
+	otherSecretSources := make([]commonv1.NamespacedSecretSource, 0, len(additionalSources)+len(policySecretSources))
+	otherSecretSources = append(otherSecretSources, additionalSources...)
+	otherSecretSources = append(otherSecretSources, policySecretSources...)
+	secretSources := make([]commonv1.NamespacedSecretSource, 0, len(resourceSecretSources)+len(otherSecretSources))
+	secretSources = append(secretSources, resourceSecretSources...)
+	secretSources = append(secretSources, otherSecretSources...)
 
 	if err := watches.WatchUserProvidedNamespacedSecrets(
 		watcher,
@@ -69,10 +75,21 @@ func secureSettingsVolume(
 		return nil, "", err
 	}
 
-	userSecrets, err := retrieveUserSecrets(ctx, r.K8sClient(), r.Recorder(), hasKeystore, secretSources)
+	// Secrets referenced in the resource are watched, but only retrieved if the resource is allowed to access them
+	allowedResourceSecretSources, err := AllowedSecretSources(ctx, r.Recorder(), hasKeystore)
 	if err != nil {
 		return nil, "", err
 	}
+	userSecrets, err := retrieveUserSecrets(ctx, r.K8sClient(), r.Recorder(), hasKeystore, append(allowedResourceSecretSources, otherSecretSources...))
+	if err != nil {
+		return nil, "", err
+	}
+	// secrets read from external secret backends are aggregated with the user-provided Secrets
+	backendSecrets, err := BackendSecrets(ctx, r.Recorder(), hasKeystore)
+	if err != nil {
+		return nil, "", err
+	}
+	userSecrets = append(userSecrets, backendSecrets...)
 
 	secureSettingsSecret, err := reconcileSecureSettings(ctx, r.K8sClient(), hasKeystore, userSecrets, namer, labels)
 	if err != nil {
@@ -163,13 +180,24 @@ func retrieveUserSecret(ctx context.Context, c k8s.Client, recorder record.Event
 		return nil, false, err
 	}
 
+	projectedSecret, err := projectEntries(userSecret, secretSrc.Entries)
+	if err != nil {
+		return nil, false, err
+	}
+	return projectedSecret, true, nil
+}
+
+// projectEntries returns the subset of the given secure settings secret defined by the given entries, or the whole
+// secret if no entries are defined.
+func projectEntries(userSecret corev1.Secret, entries []commonv1.KeyToPath) (*corev1.Secret, error) {
+	secretName := userSecret.Name
 	// If no entries, return the whole user secret
-	if secretSrc.Entries == nil {
-		return &userSecret, true, nil
+	if entries == nil {
+		return &userSecret, nil
 	}
 
-	if len(secretSrc.Entries) == 0 {
-		return nil, false, pkgerrors.Errorf("set is empty in secure settings secret %s", secretName)
+	if len(entries) == 0 {
+		return nil, pkgerrors.Errorf("set is empty in secure settings secret %s", secretName)
 	}
 
 	// Else if entries is defined, return only a subset of the user secret
@@ -177,9 +205,9 @@ func retrieveUserSecret(ctx context.Context, c k8s.Client, recorder record.Event
 		ObjectMeta: userSecret.ObjectMeta,
 		Data:       map[string][]byte{},
 	}
-	for _, entry := range secretSrc.Entries {
+	for _, entry := range entries {
 		if entry.Key == "" {
-			return nil, false, pkgerrors.Errorf("key is empty in secure settings secret %s", secretName)
+			return nil, pkgerrors.Errorf("key is empty in secure settings secret %s", secretName)
 		}
 
 		newKey := entry.Path
@@ -189,13 +217,13 @@ func retrieveUserSecret(ctx context.Context, c k8s.Client, recorder record.Event
 
 		value, ok := userSecret.Data[entry.Key]
 		if !ok {
-			return nil, false, pkgerrors.Errorf("key %s not found in secure settings secret %s", entry.Key, secretName)
+			return nil, pkgerrors.Errorf("key %s not found in secure settings secret %s", entry.Key, secretName)
 		}
 
 		projectionSecret.Data[newKey] = value
 	}
 
-	return &projectionSecret, true, nil
+	return &projectionSecret, nil
 }
 
 func secureSettingsSecretName(namer name.Namer, hasKeystore HasKeystore) string {
@@ -0,0 +1,79 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package labels
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
+)
+
+var (
+	credentialsSecretLabels      map[string]string
+	credentialsSecretAnnotations map[string]string
+)
+
+// SetCredentialsSecretMetadata sets the labels and annotations added to all the Secrets holding credentials created by
+// the operator, for example to let external tools replicate them to another namespace or push them to a vault.
+func SetCredentialsSecretMetadata(labels, annotations map[string]string) {
+	credentialsSecretLabels = labels
+	credentialsSecretAnnotations = annotations
+}
+
+// NewCredentialsSecretMetadata parses and validates the given lists of key=value labels and annotations.
+func NewCredentialsSecretMetadata(labels, annotations []string) (map[string]string, map[string]string, error) {
+	parsedLabels, err := parseKeyValues(labels)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid credentials Secret labels: %w", err)
+	}
+	for k, v := range parsedLabels {
+		if errs := validation.IsValidLabelValue(v); len(errs) > 0 {
+			return nil, nil, fmt.Errorf("invalid credentials Secret labels: value of %s: %s", k, strings.Join(errs, ", "))
+		}
+	}
+	parsedAnnotations, err := parseKeyValues(annotations)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid credentials Secret annotations: %w", err)
+	}
+	return parsedLabels, parsedAnnotations, nil
+}
+
+// parseKeyValues parses a list of key=value pairs, and validates the keys as Kubernetes qualified names.
+func parseKeyValues(pairs []string) (map[string]string, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+	parsed := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("%q is not a key=value pair", pair)
+		}
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			return nil, fmt.Errorf("key %q: %s", k, strings.Join(errs, ", "))
+		}
+		parsed[k] = v
+	}
+	return parsed, nil
+}
+
+// IsCredentials returns true if the given labels describe a resource which contains some credentials.
+func IsCredentials(labels map[string]string) bool {
+	return labels[credentialsLabel] == "true"
+}
+
+// WithCredentialsSecretMetadata returns the given labels and annotations completed with the labels and annotations
+// configured for the Secrets holding credentials, if the labels describe a resource which contains some credentials.
+// Labels and annotations set by the operator take precedence. The given maps are not modified.
+func WithCredentialsSecretMetadata(labels, annotations map[string]string) (map[string]string, map[string]string) {
+	if !IsCredentials(labels) || (len(credentialsSecretLabels) == 0 && len(credentialsSecretAnnotations) == 0) {
+		return labels, annotations
+	}
+	return maps.MergePreservingExistingKeys(maps.Merge(nil, labels), credentialsSecretLabels),
+		maps.MergePreservingExistingKeys(maps.Merge(nil, annotations), credentialsSecretAnnotations)
+}
@@ -0,0 +1,83 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package labels
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCredentialsSecretMetadata(t *testing.T) {
+	tests := []struct {
+		name            string
+		labels          []string
+		annotations     []string
+		wantLabels      map[string]string
+		wantAnnotations map[string]string
+		wantErr         bool
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:            "labels and annotations",
+			labels:          []string{"replicate=true", "example.com/team=search"},
+			annotations:     []string{"reflector.v1.k8s.emberstack.com/reflection-allowed=true", "note=a=b"},
+			wantLabels:      map[string]string{"replicate": "true", "example.com/team": "search"},
+			wantAnnotations: map[string]string{"reflector.v1.k8s.emberstack.com/reflection-allowed": "true", "note": "a=b"},
+		},
+		{
+			name:    "not a key=value pair",
+			labels:  []string{"replicate"},
+			wantErr: true,
+		},
+		{
+			name:        "invalid key",
+			annotations: []string{"not a key=value"},
+			wantErr:     true,
+		},
+		{
+			name:    "invalid label value",
+			labels:  []string{"replicate=not a label value"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotLabels, gotAnnotations, err := NewCredentialsSecretMetadata(tt.labels, tt.annotations)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantLabels, gotLabels)
+			assert.Equal(t, tt.wantAnnotations, gotAnnotations)
+		})
+	}
+}
+
+func TestWithCredentialsSecretMetadata(t *testing.T) {
+	SetCredentialsSecretMetadata(
+		map[string]string{"replicate": "true", credentialsLabel: "false"},
+		map[string]string{"reflector/allowed": "true", "existing": "overridden"},
+	)
+	defer SetCredentialsSecretMetadata(nil, nil)
+
+	credentials := AddCredentialsLabel(map[string]string{"foo": "bar"})
+	annotations := map[string]string{"existing": "value"}
+	gotLabels, gotAnnotations := WithCredentialsSecretMetadata(credentials, annotations)
+	assert.Equal(t, map[string]string{"foo": "bar", credentialsLabel: "true", "replicate": "true"}, gotLabels)
+	assert.Equal(t, map[string]string{"existing": "value", "reflector/allowed": "true"}, gotAnnotations)
+	// the given maps are not modified
+	assert.Equal(t, map[string]string{"foo": "bar", credentialsLabel: "true"}, credentials)
+	assert.Equal(t, map[string]string{"existing": "value"}, annotations)
+
+	// resources without credentials are left untouched
+	gotLabels, gotAnnotations = WithCredentialsSecretMetadata(map[string]string{"foo": "bar"}, nil)
+	assert.Equal(t, map[string]string{"foo": "bar"}, gotLabels)
+	assert.Nil(t, gotAnnotations)
+}
@@ -0,0 +1,95 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package metadata
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
+)
+
+const (
+	// PropagateLabelsAnnotation lists the labels of a resource to propagate to the PersistentVolumeClaims, Services and
+	// Secrets created by the operator for this resource. Its value is a comma-separated list of label keys, where a
+	// trailing "*" matches any key with the given prefix, and "*" alone matches all the labels.
+	PropagateLabelsAnnotation = "eck.k8s.elastic.co/propagate-labels"
+	// PropagateAnnotationsAnnotation lists the annotations of a resource to propagate to the PersistentVolumeClaims,
+	// Services and Secrets created by the operator for this resource, with the same syntax as PropagateLabelsAnnotation.
+	PropagateAnnotationsAnnotation = "eck.k8s.elastic.co/propagate-annotations"
+)
+
+// reservedDomains are the domains of the keys that are never propagated, as they are set by the operator or by kubectl
+// with a meaning specific to each resource.
+var reservedDomains = []string{"k8s.elastic.co", "kubectl.kubernetes.io"}
+
+// Propagated returns the labels and annotations of the given resource selected for propagation by its propagation
+// annotations.
+func Propagated(parent metav1.Object) (map[string]string, map[string]string) {
+	parentAnnotations := parent.GetAnnotations()
+	return selected(parent.GetLabels(), parentAnnotations[PropagateLabelsAnnotation]),
+		selected(parentAnnotations, parentAnnotations[PropagateAnnotationsAnnotation])
+}
+
+// Propagate returns the given labels and annotations of a child resource, completed with the labels and annotations
+// of the parent resource selected for propagation. The labels and annotations of the child take precedence over the
+// propagated ones. The given maps are not modified.
+func Propagate(parent metav1.Object, labels, annotations map[string]string) (map[string]string, map[string]string) {
+	propagatedLabels, propagatedAnnotations := Propagated(parent)
+	if len(propagatedLabels) > 0 {
+		labels = maps.MergePreservingExistingKeys(maps.Merge(nil, labels), propagatedLabels)
+	}
+	if len(propagatedAnnotations) > 0 {
+		annotations = maps.MergePreservingExistingKeys(maps.Merge(nil, annotations), propagatedAnnotations)
+	}
+	return labels, annotations
+}
+
+// selected returns the entries whose key matches the given patterns.
+func selected(entries map[string]string, patterns string) map[string]string {
+	if patterns == "" {
+		return nil
+	}
+	var result map[string]string
+	for key, value := range entries {
+		if isReserved(key) || !matches(patterns, key) {
+			continue
+		}
+		if result == nil {
+			result = make(map[string]string)
+		}
+		result[key] = value
+	}
+	return result
+}
+
+// matches returns true if the given key matches one of the comma-separated patterns.
+func matches(patterns string, key string) bool {
+	for _, pattern := range strings.Split(patterns, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if prefix, wildcard := strings.CutSuffix(pattern, "*"); wildcard && strings.HasPrefix(key, prefix) {
+			return true
+		}
+		if pattern == key {
+			return true
+		}
+	}
+	return false
+}
+
+// isReserved returns true if the given key belongs to a reserved domain or one of its subdomains.
+func isReserved(key string) bool {
+	domain, _, hasDomain := strings.Cut(key, "/")
+	if !hasDomain {
+		return false
+	}
+	for _, reserved := range reservedDomains {
+		if domain == reserved || strings.HasSuffix(domain, "."+reserved) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package metadata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPropagated(t *testing.T) {
+	labels := map[string]string{
+		"team":                      "search",
+		"cost-center":               "42",
+		"app.kubernetes.io/part-of": "observability",
+		"elasticsearch.k8s.elastic.co/cluster-name": "es",
+	}
+	annotations := map[string]string{
+		"velero.io/backup":  "daily",
+		"velero.io/exclude": "false",
+		"kubectl.kubernetes.io/last-applied-configuration": "{}",
+		"eck.k8s.elastic.co/managed":                       "true",
+	}
+	tests := []struct {
+		name            string
+		annotations     map[string]string
+		wantLabels      map[string]string
+		wantAnnotations map[string]string
+	}{
+		{
+			name:        "no propagation by default",
+			annotations: map[string]string{},
+		},
+		{
+			name: "propagate all",
+			annotations: map[string]string{
+				PropagateLabelsAnnotation:      "*",
+				PropagateAnnotationsAnnotation: "*",
+			},
+			wantLabels: map[string]string{
+				"team":                      "search",
+				"cost-center":               "42",
+				"app.kubernetes.io/part-of": "observability",
+			},
+			wantAnnotations: map[string]string{
+				"velero.io/backup":  "daily",
+				"velero.io/exclude": "false",
+			},
+		},
+		{
+			name: "propagate selected keys and prefixes",
+			annotations: map[string]string{
+				PropagateLabelsAnnotation:      "cost-center, unknown",
+				PropagateAnnotationsAnnotation: "velero.io/*",
+			},
+			wantLabels: map[string]string{
+				"cost-center": "42",
+			},
+			wantAnnotations: map[string]string{
+				"velero.io/backup":  "daily",
+				"velero.io/exclude": "false",
+			},
+		},
+		{
+			name: "reserved keys are never propagated",
+			annotations: map[string]string{
+				PropagateLabelsAnnotation:      "elasticsearch.k8s.elastic.co/cluster-name",
+				PropagateAnnotationsAnnotation: "kubectl.kubernetes.io/last-applied-configuration,eck.k8s.elastic.co/*",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parentAnnotations := map[string]string{}
+			for k, v := range annotations {
+				parentAnnotations[k] = v
+			}
+			for k, v := range tt.annotations {
+				parentAnnotations[k] = v
+			}
+			parent := &metav1.ObjectMeta{Labels: labels, Annotations: parentAnnotations}
+			gotLabels, gotAnnotations := Propagated(parent)
+			require.Equal(t, tt.wantLabels, gotLabels)
+			require.Equal(t, tt.wantAnnotations, gotAnnotations)
+		})
+	}
+}
+
+func TestPropagate(t *testing.T) {
+	parent := &metav1.ObjectMeta{
+		Labels: map[string]string{"team": "search", "cost-center": "42"},
+		Annotations: map[string]string{
+			PropagateLabelsAnnotation:      "*",
+			PropagateAnnotationsAnnotation: "velero.io/*",
+			"velero.io/backup":             "daily",
+		},
+	}
+	childLabels := map[string]string{"team": "ingest"}
+
+	labels, annotations := Propagate(parent, childLabels, nil)
+	// the child values take precedence
+	require.Equal(t, map[string]string{"team": "ingest", "cost-center": "42"}, labels)
+	require.Equal(t, map[string]string{"velero.io/backup": "daily"}, annotations)
+	// the given maps are not modified
+	require.Equal(t, map[string]string{"team": "ingest"}, childLabels)
+
+	// nothing to propagate
+	labels, annotations = Propagate(&metav1.ObjectMeta{}, childLabels, nil)
+	require.Equal(t, childLabels, labels)
+	require.Nil(t, annotations)
+}
@@ -5,7 +5,9 @@
 package operator
 
 const (
+	AssociationTrustBundleSelectorFlag   = "association-trust-bundle-selector"
 	AutoPortForwardFlag                  = "auto-port-forward"
+	AWSSecretsManagerRegionFlag          = "aws-secrets-manager-region"
 	CADirFlag                            = "ca-dir"
 	CACertRotateBeforeFlag               = "ca-cert-rotate-before"
 	CACertValidityFlag                   = "ca-cert-validity"
@@ -15,21 +17,33 @@ const (
 	ContainerRegistryFlag                = "container-registry"
 	ContainerRepositoryFlag              = "container-repository"
 	ContainerSuffixFlag                  = "container-suffix"
+	CredentialsSecretAnnotationsFlag     = "credentials-secret-annotations"
+	CredentialsSecretLabelsFlag          = "credentials-secret-labels"
 	DebugHTTPListenFlag                  = "debug-http-listen"
+	DefaultImagePullSecretsFlag          = "default-image-pull-secrets"
 	DisableConfigWatch                   = "disable-config-watch"
 	DisableTelemetryFlag                 = "disable-telemetry"
 	DistributionChannelFlag              = "distribution-channel"
 	ElasticsearchClientTimeout           = "elasticsearch-client-timeout"
 	ElasticsearchObservationIntervalFlag = "elasticsearch-observation-interval"
+	ElasticsearchReconcileBudgetFlag     = "elasticsearch-reconcile-budget"
+	EnableClusterInfoAPIFlag             = "enable-cluster-info-api"
+	EnableDiagnosticsAPIFlag             = "enable-diagnostics-api"
+	EnableHealthSummaryFlag              = "enable-health-summary"
 	EnableLeaderElection                 = "enable-leader-election"
 	EnableTracingFlag                    = "enable-tracing"
 	EnableWebhookFlag                    = "enable-webhook"
 	EnforceRBACOnRefsFlag                = "enforce-rbac-on-refs"
+	ExpectedResourcesCacheSizeFlag       = "expected-resources-cache-size"
 	ExposedNodeLabels                    = "exposed-node-labels"
+	ExternalWebhookCertsFlag             = "external-webhook-certs"
+	ImageCatalogFlag                     = "image-catalog"
 	PasswordHashCacheSize                = "password-hash-cache-size"
+	InPlacePodResizeFlag                 = "in-place-pod-resize"
 	IPFamilyFlag                         = "ip-family"
 	KubeClientTimeout                    = "kube-client-timeout"
 	KubeClientQPS                        = "kube-client-qps"
+	LicenseExpiryWarningPeriodFlag       = "license-expiry-warning-period"
 	ManageWebhookCertsFlag               = "manage-webhook-certs"
 	MaxConcurrentReconcilesFlag          = "max-concurrent-reconciles"
 	MetricsPortFlag                      = "metrics-port"
@@ -37,11 +51,27 @@ const (
 	MetricsSecureFlag                    = "metrics-secure"
 	MetricsCertDirFlag                   = "metrics-cert-dir"
 	NamespacesFlag                       = "namespaces"
+	NamespaceQuotaMaxClustersFlag        = "namespace-quota-max-clusters"
+	NamespaceQuotaMaxMemoryFlag          = "namespace-quota-max-memory"
+	NamespaceQuotaMaxStorageFlag         = "namespace-quota-max-storage"
 	OperatorNamespaceFlag                = "operator-namespace"
+	OrchestrateNodeDrainsFlag            = "orchestrate-node-drains"
+	PodDNSNameserversFlag                = "pod-dns-nameservers"
+	PodDNSNdotsFlag                      = "pod-dns-ndots"
+	PodDNSPolicyFlag                     = "pod-dns-policy"
+	PodDNSSearchesFlag                   = "pod-dns-searches"
+	PodMutationPolicyFlag                = "pod-mutation-policy"
+	PublishClusterTrustBundlesFlag       = "publish-cluster-trust-bundles"
+	PublishEffectiveSpecFlag             = "publish-effective-spec"
+	SafeModeFlag                         = "safe-mode"
+	SecretBackendRefreshIntervalFlag     = "secret-backend-refresh-interval"
 	SetDefaultSecurityContextFlag        = "set-default-security-context"
 	TelemetryIntervalFlag                = "telemetry-interval"
 	UBIOnlyFlag                          = "ubi-only"
 	ValidateStorageClassFlag             = "validate-storage-class"
+	VaultAddressFlag                     = "vault-address"
+	VaultAuthMountPathFlag               = "vault-auth-mount-path"
+	VaultAuthRoleFlag                    = "vault-auth-role"
 	WebhookCertDirFlag                   = "webhook-cert-dir"
 	WebhookNameFlag                      = "webhook-name"
 	WebhookSecretFlag                    = "webhook-secret"
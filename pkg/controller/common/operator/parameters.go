@@ -9,6 +9,7 @@ import (
 
 	"go.elastic.co/apm/v2"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 
 	"github.com/elastic/cloud-on-k8s/v2/pkg/about"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
@@ -19,12 +20,28 @@ import (
 
 // Parameters contain parameters to create new operators.
 type Parameters struct {
+	// AssociationTrustBundleSelector selects the ClusterTrustBundles whose certificates are trusted, in addition to the
+	// CA of the referenced resource, by the associated resources. Nil if no ClusterTrustBundle is trusted.
+	AssociationTrustBundleSelector labels.Selector
 	// ElasticsearchObservationInterval is the interval between (asynchronous) observations of Elasticsearch health.
 	ElasticsearchObservationInterval time.Duration
+	// ElasticsearchReconcileBudget is the maximum time a single reconciliation of an Elasticsearch cluster can spend
+	// before yielding to other resources. Non-positive values disable the budget.
+	ElasticsearchReconcileBudget time.Duration
+	// EnableHealthSummary enables the maintenance of a ConfigMap summarizing the Elastic resources of each namespace.
+	EnableHealthSummary bool
 	// ExposedNodeLabels holds regular expressions of node labels which are allowed to be automatically set as annotations on Elasticsearch Pods.
 	ExposedNodeLabels esvalidation.NodeLabels
+	// LicenseExpiryWarningPeriod is the period before the expiration of the license of an Elasticsearch cluster during
+	// which the operator warns about the upcoming expiration.
+	LicenseExpiryWarningPeriod time.Duration
+	// NamespaceQuota defines the maximum amount of Elasticsearch resources that can be created in a single namespace.
+	NamespaceQuota esvalidation.NamespaceQuota
 	// OperatorNamespace is the control plane namespace of the operator.
 	OperatorNamespace string
+	// OrchestrateNodeDrains enables the relocation of the data of the Elasticsearch nodes scheduled on cordoned
+	// Kubernetes nodes, through the node shutdown API.
+	OrchestrateNodeDrains bool
 	// OperatorInfo is information about the operator
 	OperatorInfo about.OperatorInfo
 	// Dialer is used to create the Elasticsearch HTTP client.
@@ -44,6 +61,11 @@ type Parameters struct {
 	// SetDefaultSecurityContext enables setting the default security context
 	// with fsGroup=1000 for Elasticsearch 8.0+ Pods, and Kibana 7.10+ Pods.
 	SetDefaultSecurityContext bool
+	// InPlacePodResize enables resizing the containers of the Elasticsearch and Kibana Pods without restarting them when
+	// only their resources change.
+	InPlacePodResize bool
+	// PublishClusterTrustBundles enables the publication of the CA certificates of the managed resources as ClusterTrustBundles.
+	PublishClusterTrustBundles bool
 	// ValidateStorageClass specifies whether the operator should retrieve storage classes to verify volume expansion support.
 	// Can be disabled if cluster-wide storage class RBAC access is not available.
 	ValidateStorageClass bool
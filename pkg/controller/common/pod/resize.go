@@ -0,0 +1,174 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package pod
+
+import (
+	"context"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+const (
+	// ResizeSubresource is the subresource through which the resources of the containers of a Pod are resized.
+	ResizeSubresource = "resize"
+	// resizePendingCondition and resizeInProgressCondition report the progress of a resize from Kubernetes 1.33,
+	// they replace the resize field of the Pod status.
+	resizePendingCondition    corev1.PodConditionType = "PodResizePending"
+	resizeInProgressCondition corev1.PodConditionType = "PodResizeInProgress"
+)
+
+// ResizeStatus is the progress of the in-place resize of a Pod.
+type ResizeStatus int
+
+const (
+	// ResizeNotRequested means the resources of the Pod do not match the expected ones yet.
+	ResizeNotRequested ResizeStatus = iota
+	// ResizeInProgress means the kubelet is applying the new resources.
+	ResizeInProgress
+	// ResizeNotFeasible means the kubelet cannot apply the new resources on the current Kubernetes node.
+	ResizeNotFeasible
+	// ResizeComplete means the containers run with the new resources.
+	ResizeComplete
+)
+
+// ResizableWithoutRestart returns true if the resources of the containers of the current Pod spec can be changed to
+// the ones of the expected Pod spec by the kubelet without restarting the containers. Only CPU and memory can be
+// resized in place. memoryResizable returns whether the application running in the given container can use a new
+// amount of memory without being restarted.
+func ResizableWithoutRestart(current, expected corev1.PodSpec, memoryResizable func(corev1.Container) bool) bool {
+	if len(current.Containers) != len(expected.Containers) {
+		return false
+	}
+	for _, expectedContainer := range expected.Containers {
+		currentContainer := ContainerByName(current, expectedContainer.Name)
+		if currentContainer == nil {
+			return false
+		}
+		for _, resourceName := range changedResources(currentContainer.Resources, expectedContainer.Resources) {
+			if resourceName != corev1.ResourceCPU && resourceName != corev1.ResourceMemory {
+				return false
+			}
+			for _, policy := range expectedContainer.ResizePolicy {
+				if policy.ResourceName == resourceName && policy.RestartPolicy == corev1.RestartContainer {
+					return false
+				}
+			}
+			if resourceName == corev1.ResourceMemory && !memoryResizable(expectedContainer) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// ResourcesChanged returns true if the resources of a container of the expected Pod spec differ from the ones of the
+// current Pod spec.
+func ResourcesChanged(current, expected corev1.PodSpec) bool {
+	for _, expectedContainer := range expected.Containers {
+		currentContainer := ContainerByName(current, expectedContainer.Name)
+		if currentContainer == nil || len(changedResources(currentContainer.Resources, expectedContainer.Resources)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// changedResources returns the names of the resources whose request or limit differs between current and expected,
+// once the requests are defaulted to the limits as done by the API server.
+func changedResources(current, expected corev1.ResourceRequirements) []corev1.ResourceName {
+	current, expected = withDefaultRequests(current), withDefaultRequests(expected)
+	var changed []corev1.ResourceName
+	for _, list := range []corev1.ResourceList{current.Requests, current.Limits, expected.Requests, expected.Limits} {
+		for name := range list {
+			if slices.Contains(changed, name) {
+				continue
+			}
+			if !equalQuantity(current.Requests, expected.Requests, name) || !equalQuantity(current.Limits, expected.Limits, name) {
+				changed = append(changed, name)
+			}
+		}
+	}
+	return changed
+}
+
+func equalQuantity(current, expected corev1.ResourceList, name corev1.ResourceName) bool {
+	currentValue, inCurrent := current[name]
+	expectedValue, inExpected := expected[name]
+	return inCurrent == inExpected && currentValue.Cmp(expectedValue) == 0
+}
+
+// withDefaultRequests returns the given resources with the requests defaulted to the limits by the API server.
+func withDefaultRequests(resources corev1.ResourceRequirements) corev1.ResourceRequirements {
+	resources = *resources.DeepCopy()
+	for name, limit := range resources.Limits {
+		if _, exists := resources.Requests[name]; exists {
+			continue
+		}
+		if resources.Requests == nil {
+			resources.Requests = corev1.ResourceList{}
+		}
+		resources.Requests[name] = limit
+	}
+	return resources
+}
+
+// GetResizeStatus returns the progress of the resize of the given Pod to the resources of the expected Pod spec.
+func GetResizeStatus(pod corev1.Pod, expected corev1.PodSpec) ResizeStatus {
+	for _, container := range pod.Spec.Containers {
+		expectedContainer := ContainerByName(expected, container.Name)
+		if expectedContainer == nil || len(changedResources(container.Resources, expectedContainer.Resources)) > 0 {
+			return ResizeNotRequested
+		}
+	}
+	switch pod.Status.Resize { //nolint:staticcheck
+	case corev1.PodResizeStatusDeferred, corev1.PodResizeStatusInfeasible:
+		return ResizeNotFeasible
+	case corev1.PodResizeStatusProposed, corev1.PodResizeStatusInProgress:
+		return ResizeInProgress
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type { //nolint:exhaustive
+		case resizePendingCondition:
+			return ResizeNotFeasible
+		case resizeInProgressCondition:
+			return ResizeInProgress
+		}
+	}
+	// the kubelet reports the CPU and memory of the running containers once it has applied them
+	for _, status := range pod.Status.ContainerStatuses {
+		container := ContainerByName(pod.Spec, status.Name)
+		if status.Resources == nil || container == nil {
+			continue
+		}
+		for _, resourceName := range changedResources(*status.Resources, container.Resources) {
+			if resourceName == corev1.ResourceCPU || resourceName == corev1.ResourceMemory {
+				return ResizeInProgress
+			}
+		}
+	}
+	return ResizeComplete
+}
+
+// Resize sets the resources of the containers of the given Pod to the ones of the expected Pod spec.
+func Resize(ctx context.Context, c k8s.Client, pod corev1.Pod, expected corev1.PodSpec) error {
+	resized := pod.DeepCopy()
+	for i, container := range resized.Spec.Containers {
+		if expectedContainer := ContainerByName(expected, container.Name); expectedContainer != nil {
+			resized.Spec.Containers[i].Resources = withDefaultRequests(expectedContainer.Resources)
+		}
+	}
+	if equality.Semantic.DeepEqual(resized.Spec, pod.Spec) {
+		return nil
+	}
+	return c.SubResource(ResizeSubresource).Patch(ctx, resized, client.StrategicMergeFrom(&pod))
+}
@@ -0,0 +1,103 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package pod
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func resizeSpec(cpu, memory string, policies ...corev1.ContainerResizePolicy) corev1.PodSpec {
+	return corev1.PodSpec{Containers: []corev1.Container{{
+		Name:         "app",
+		ResizePolicy: policies,
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+			Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(memory)},
+		},
+	}}}
+}
+
+func TestResizableWithoutRestart(t *testing.T) {
+	anyMemory := func(corev1.Container) bool { return true }
+	noMemory := func(corev1.Container) bool { return false }
+	restartOnMemory := corev1.ContainerResizePolicy{ResourceName: corev1.ResourceMemory, RestartPolicy: corev1.RestartContainer}
+	withStorage := resizeSpec("1", "1Gi")
+	withStorage.Containers[0].Resources.Limits[corev1.ResourceEphemeralStorage] = resource.MustParse("1Gi")
+	renamed := resizeSpec("1", "1Gi")
+	renamed.Containers[0].Name = "other"
+
+	assert.True(t, ResizableWithoutRestart(resizeSpec("1", "1Gi"), resizeSpec("1", "1Gi"), noMemory))
+	assert.True(t, ResizableWithoutRestart(resizeSpec("1", "1Gi"), resizeSpec("2", "2Gi"), anyMemory))
+	assert.True(t, ResizableWithoutRestart(resizeSpec("1", "1Gi"), resizeSpec("2", "1Gi"), noMemory))
+	assert.False(t, ResizableWithoutRestart(resizeSpec("1", "1Gi"), resizeSpec("1", "2Gi"), noMemory))
+	assert.True(t, ResizableWithoutRestart(resizeSpec("1", "1Gi", restartOnMemory), resizeSpec("2", "1Gi", restartOnMemory), anyMemory))
+	assert.False(t, ResizableWithoutRestart(resizeSpec("1", "1Gi", restartOnMemory), resizeSpec("1", "2Gi", restartOnMemory), anyMemory))
+	assert.False(t, ResizableWithoutRestart(resizeSpec("1", "1Gi"), withStorage, anyMemory))
+	assert.False(t, ResizableWithoutRestart(resizeSpec("1", "1Gi"), renamed, anyMemory))
+}
+
+func TestGetResizeStatus(t *testing.T) {
+	// the API server defaults the requests to the limits
+	defaulted := func(cpu, memory string) corev1.PodSpec {
+		spec := resizeSpec(cpu, memory)
+		spec.Containers[0].Resources = withDefaultRequests(spec.Containers[0].Resources)
+		return spec
+	}
+	running := func(cpu, memory string) corev1.PodStatus {
+		resources := defaulted(cpu, memory).Containers[0].Resources
+		return corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{Name: "app", Resources: &resources}}}
+	}
+
+	tests := []struct {
+		name     string
+		pod      corev1.Pod
+		expected corev1.PodSpec
+		want     ResizeStatus
+	}{
+		{
+			name:     "resources not updated yet",
+			pod:      corev1.Pod{Spec: defaulted("1", "1Gi"), Status: running("1", "1Gi")},
+			expected: resizeSpec("2", "1Gi"),
+			want:     ResizeNotRequested,
+		},
+		{
+			name:     "resize in progress",
+			pod:      corev1.Pod{Spec: defaulted("2", "1Gi"), Status: running("1", "1Gi")},
+			expected: resizeSpec("2", "1Gi"),
+			want:     ResizeInProgress,
+		},
+		{
+			name: "resize in progress condition",
+			pod: corev1.Pod{Spec: defaulted("2", "1Gi"), Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{Type: resizeInProgressCondition, Status: corev1.ConditionTrue}},
+			}},
+			expected: resizeSpec("2", "1Gi"),
+			want:     ResizeInProgress,
+		},
+		{
+			name: "resize not feasible",
+			pod: corev1.Pod{Spec: defaulted("2", "1Gi"), Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{Type: resizePendingCondition, Status: corev1.ConditionTrue, Reason: "Infeasible"}},
+			}},
+			expected: resizeSpec("2", "1Gi"),
+			want:     ResizeNotFeasible,
+		},
+		{
+			name:     "resize complete, with requests defaulted to the limits",
+			pod:      corev1.Pod{Spec: defaulted("2", "1Gi"), Status: running("2", "1Gi")},
+			expected: resizeSpec("2", "1Gi"),
+			want:     ResizeComplete,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, GetResizeStatus(tt.pod, tt.expected))
+		})
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package reconciler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// budgetYieldDelay is the delay after which a resource that yielded because of an exhausted time budget is reconciled
+// again. A specific delay is used rather than a generic requeue, so that consecutive yields are not subject to the
+// exponential backoff of the work queue.
+const budgetYieldDelay = 1 * time.Second
+
+// BudgetExhausted is the reconciliation state reported when a reconciliation cooperatively yields because its time
+// budget is exhausted. The resource is requeued shortly, behind the other resources waiting for the same controller.
+var BudgetExhausted = ReconciliationState{
+	Result: reconcile.Result{RequeueAfter: budgetYieldDelay},
+}.WithReason("Reconciliation time budget exhausted, yielding to other resources")
+
+// Budget bounds the time a single reconciliation of a resource can spend before yielding the worker to other
+// resources. A nil Budget, or a Budget created with a non-positive limit, is never exhausted.
+type Budget struct {
+	deadline time.Time
+
+	// checkpoints remembers the checkpoint at which the previous reconciliations of the resource yielded, nil if the
+	// yields are not tracked.
+	checkpoints *BudgetCheckpoints
+	resource    types.NamespacedName
+	generation  int64
+	// resumeAfter is the last checkpoint at which a previous reconciliation of the same generation yielded.
+	resumeAfter int
+	// reached is the number of checkpoints reached by the current reconciliation.
+	reached int
+	yielded bool
+}
+
+// NewBudget returns a Budget which is exhausted once the given limit has elapsed, or nil if the limit is not positive.
+func NewBudget(limit time.Duration) *Budget {
+	if limit <= 0 {
+		return nil
+	}
+	return &Budget{deadline: time.Now().Add(limit)}
+}
+
+// Exhausted returns true if the time allotted to the reconciliation has elapsed.
+func (b *Budget) Exhausted() bool {
+	if b == nil {
+		return false
+	}
+	return !time.Now().Before(b.deadline)
+}
+
+// Checkpoint is called between the phases of a reconciliation, always in the same order. It returns true if the
+// reconciliation must yield at this checkpoint, which is the case if the budget is exhausted and if no previous
+// reconciliation of the same generation of the resource yielded at this checkpoint or at a later one. Since the phases
+// before the checkpoint are run again by the next reconciliation, each reconciliation goes at least one phase further
+// than the previous one, so that the resource converges even if the phases take longer than the budget.
+func (b *Budget) Checkpoint() bool {
+	if b == nil {
+		return false
+	}
+	b.reached++
+	if b.reached <= b.resumeAfter || !b.Exhausted() {
+		return false
+	}
+	b.yielded = true
+	b.checkpoints.record(b.resource, budgetCheckpoint{generation: b.generation, index: b.reached})
+	return true
+}
+
+// Done is called at the end of the reconciliation. The checkpoints of the resource are forgotten if the reconciliation
+// did not yield, so that the next reconciliation can yield at any checkpoint again.
+func (b *Budget) Done() {
+	if b == nil || b.yielded {
+		return
+	}
+	b.checkpoints.Forget(b.resource)
+}
+
+type budgetCheckpoint struct {
+	generation int64
+	index      int
+}
+
+// BudgetCheckpoints keeps track, for each resource, of the last checkpoint at which its reconciliation yielded because
+// of an exhausted time budget. It is safe for concurrent use.
+type BudgetCheckpoints struct {
+	mutex   sync.Mutex
+	yielded map[types.NamespacedName]budgetCheckpoint
+}
+
+// NewBudgetCheckpoints returns an empty BudgetCheckpoints.
+func NewBudgetCheckpoints() *BudgetCheckpoints {
+	return &BudgetCheckpoints{yielded: make(map[types.NamespacedName]budgetCheckpoint)}
+}
+
+// NewBudget returns a Budget for the reconciliation of the given generation of a resource, which does not yield again
+// at the checkpoints already passed by the previous reconciliations of the same generation. It returns nil if the limit
+// is not positive.
+func (c *BudgetCheckpoints) NewBudget(limit time.Duration, resource types.NamespacedName, generation int64) *Budget {
+	budget := NewBudget(limit)
+	if budget == nil || c == nil {
+		return budget
+	}
+	budget.checkpoints = c
+	budget.resource = resource
+	budget.generation = generation
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if last, exists := c.yielded[resource]; exists && last.generation == generation {
+		budget.resumeAfter = last.index
+	}
+	return budget
+}
+
+func (c *BudgetCheckpoints) record(resource types.NamespacedName, checkpoint budgetCheckpoint) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.yielded[resource] = checkpoint
+}
+
+// Forget removes the checkpoints of the given resource.
+func (c *BudgetCheckpoints) Forget(resource types.NamespacedName) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.yielded, resource)
+}
+
+type budgetKey struct{}
+
+// WithBudget returns a copy of the given context carrying the time budget of the current reconciliation.
+func WithBudget(ctx context.Context, budget *Budget) context.Context {
+	return context.WithValue(ctx, budgetKey{}, budget)
+}
+
+// BudgetFromContext returns the time budget of the current reconciliation, or nil if there is none.
+func BudgetFromContext(ctx context.Context) *Budget {
+	budget, _ := ctx.Value(budgetKey{}).(*Budget)
+	return budget
+}
@@ -0,0 +1,121 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package reconciler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestBudget_Exhausted(t *testing.T) {
+	tests := []struct {
+		name   string
+		budget *Budget
+		want   bool
+	}{
+		{name: "nil budget", budget: nil, want: false},
+		{name: "no limit", budget: NewBudget(0), want: false},
+		{name: "negative limit", budget: NewBudget(-1 * time.Second), want: false},
+		{name: "time left", budget: NewBudget(time.Hour), want: false},
+		{name: "deadline passed", budget: &Budget{deadline: time.Now().Add(-1 * time.Second)}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.budget.Exhausted())
+		})
+	}
+}
+
+func TestBudget_Checkpoint(t *testing.T) {
+	require.False(t, (*Budget)(nil).Checkpoint())
+	require.False(t, NewBudget(time.Hour).Checkpoint())
+
+	exhausted := &Budget{deadline: time.Now().Add(-1 * time.Second)}
+	require.True(t, exhausted.Checkpoint())
+	// without checkpoints tracking, the budget yields at every checkpoint
+	require.True(t, exhausted.Checkpoint())
+}
+
+// exhaustingReconciliation simulates a reconciliation made of the given number of phases, each of them taking longer
+// than the budget. It returns the number of phases run before yielding, and whether it yielded.
+func exhaustingReconciliation(checkpoints *BudgetCheckpoints, resource types.NamespacedName, generation int64, phases int) (int, bool) {
+	budget := checkpoints.NewBudget(time.Nanosecond, resource, generation)
+	defer budget.Done()
+	for phase := 1; phase <= phases; phase++ {
+		time.Sleep(time.Millisecond)
+		if phase < phases && budget.Checkpoint() {
+			return phase, true
+		}
+	}
+	return phases, false
+}
+
+func TestBudgetCheckpoints_Converges(t *testing.T) {
+	checkpoints := NewBudgetCheckpoints()
+	resource := types.NamespacedName{Namespace: "ns", Name: "es"}
+	phases := 4
+
+	// each reconciliation runs one more phase than the previous one, until the last one completes
+	for pass := 1; pass < phases; pass++ {
+		ran, yielded := exhaustingReconciliation(checkpoints, resource, 1, phases)
+		require.True(t, yielded)
+		require.Equal(t, pass, ran)
+	}
+	ran, yielded := exhaustingReconciliation(checkpoints, resource, 1, phases)
+	require.False(t, yielded)
+	require.Equal(t, phases, ran)
+
+	// the completed reconciliation forgot the checkpoints: the next one can yield at the first checkpoint again
+	ran, yielded = exhaustingReconciliation(checkpoints, resource, 1, phases)
+	require.True(t, yielded)
+	require.Equal(t, 1, ran)
+	ran, _ = exhaustingReconciliation(checkpoints, resource, 1, phases)
+	require.Equal(t, 2, ran)
+
+	// a new generation starts over, other resources are not affected
+	ran, _ = exhaustingReconciliation(checkpoints, resource, 2, phases)
+	require.Equal(t, 1, ran)
+	ran, _ = exhaustingReconciliation(checkpoints, types.NamespacedName{Namespace: "ns", Name: "other"}, 1, phases)
+	require.Equal(t, 1, ran)
+
+	// forgetting a deleted resource starts over
+	checkpoints.Forget(resource)
+	ran, _ = exhaustingReconciliation(checkpoints, resource, 2, phases)
+	require.Equal(t, 1, ran)
+}
+
+func TestBudgetCheckpoints_NewBudget(t *testing.T) {
+	resource := types.NamespacedName{Namespace: "ns", Name: "es"}
+	require.Nil(t, NewBudgetCheckpoints().NewBudget(0, resource, 1))
+	require.Nil(t, (*BudgetCheckpoints)(nil).NewBudget(0, resource, 1))
+	// nil checkpoints do not track the yields
+	budget := (*BudgetCheckpoints)(nil).NewBudget(time.Nanosecond, resource, 1)
+	time.Sleep(time.Millisecond)
+	require.True(t, budget.Checkpoint())
+	budget.Done()
+}
+
+func TestBudgetFromContext(t *testing.T) {
+	require.Nil(t, BudgetFromContext(context.Background()))
+
+	budget := NewBudget(time.Hour)
+	require.Same(t, budget, BudgetFromContext(WithBudget(context.Background(), budget)))
+}
+
+func TestBudgetExhausted(t *testing.T) {
+	results := NewResult(context.Background()).
+		WithReconciliationState(RequeueAfter(10 * time.Second)).
+		WithReconciliationState(BudgetExhausted)
+	res, err := results.Aggregate()
+	require.NoError(t, err)
+	require.Equal(t, budgetYieldDelay, res.RequeueAfter)
+	reconciled, reason := results.IsReconciled()
+	require.False(t, reconciled)
+	require.Equal(t, "Reconciliation time budget exhausted, yielding to other resources", reason)
+}
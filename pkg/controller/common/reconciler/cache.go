@@ -0,0 +1,94 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package reconciler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
+)
+
+// resultsCache remembers, for each resource reconciled through ReconcileResource, a hash of the expected state it was
+// last reconciled with, along with the UID and the resource version of the resource at that time.
+// If neither the expected state nor the resource have changed since then, the resource is known to be up to date and
+// its comparison and update can be skipped. This avoids unnecessary updates, for example if a resource is always
+// considered as different from the expected one because of a value defaulted by the API server.
+// The resource is still retrieved, from the informers cache, to observe its current UID and resource version: a
+// resource modified or re-created by someone else never matches the cache entry, and is reconciled again.
+var resultsCache *lru.Cache[cacheKey, cacheEntry]
+
+type cacheKey struct {
+	kind string
+	types.NamespacedName
+}
+
+type cacheEntry struct {
+	expectedHash    string
+	uid             types.UID
+	resourceVersion string
+}
+
+// EnableResultsCache enables the cache of the reconciled resources, with the given maximum number of entries.
+// The cache is disabled if size is 0 or negative. It must be called before any reconciliation is started.
+func EnableResultsCache(size int) error {
+	if size <= 0 {
+		resultsCache = nil
+		return nil
+	}
+	cache, err := lru.New[cacheKey, cacheEntry](size)
+	if err != nil {
+		return err
+	}
+	resultsCache = cache
+	return nil
+}
+
+// expectedHash returns a hash of the expected state of a resource, or an empty string if the cache is disabled.
+// A cryptographic hash function is used, as a collision would lead to a change not being applied.
+func expectedHash(expected client.Object) string {
+	if resultsCache == nil {
+		return ""
+	}
+	hasher := sha256.New()
+	hash.WriteHashObject(hasher, expected)
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+// isCachedAsReconciled returns true if the given resource, as retrieved from the API server, has already been
+// reconciled with the given expected state.
+func isCachedAsReconciled(key cacheKey, expectedHash string, reconciled client.Object) bool {
+	if resultsCache == nil {
+		return false
+	}
+	entry, exists := resultsCache.Get(key)
+	hit := exists &&
+		entry.expectedHash == expectedHash &&
+		entry.uid == reconciled.GetUID() &&
+		entry.resourceVersion == reconciled.GetResourceVersion()
+	if hit {
+		metrics.ReconcileCacheHits.WithLabelValues(key.kind).Inc()
+	} else {
+		metrics.ReconcileCacheMisses.WithLabelValues(key.kind).Inc()
+	}
+	return hit
+}
+
+// cacheAsReconciled records that the given resource has been reconciled with the given expected state.
+func cacheAsReconciled(key cacheKey, expectedHash string, reconciled client.Object) {
+	if resultsCache == nil {
+		return
+	}
+	resultsCache.Add(key, cacheEntry{
+		expectedHash:    expectedHash,
+		uid:             reconciled.GetUID(),
+		resourceVersion: reconciled.GetResourceVersion(),
+	})
+}
@@ -0,0 +1,161 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package reconciler
+
+import (
+	"context"
+	"maps"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func TestReconcileResource_ResultsCache(t *testing.T) {
+	require.NoError(t, EnableResultsCache(10))
+	t.Cleanup(func() { _ = EnableResultsCache(0) })
+
+	c := k8s.NewFakeClient()
+	needsUpdateCalls := 0
+	reconcile := func(data string) {
+		expected := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "secret"},
+			Data:       map[string][]byte{"key": []byte(data)},
+		}
+		reconciled := &corev1.Secret{}
+		require.NoError(t, ReconcileResource(Params{
+			Context:    context.Background(),
+			Client:     c,
+			Expected:   expected,
+			Reconciled: reconciled,
+			NeedsUpdate: func() bool {
+				needsUpdateCalls++
+				// always considered as different, as if a value was defaulted by the API server
+				return true
+			},
+			UpdateReconciled: func() {
+				reconciled.Data = expected.Data
+			},
+		}))
+	}
+
+	// creation
+	reconcile("a")
+	require.Equal(t, 0, needsUpdateCalls)
+	// the Secret was created with the expected state: the comparison is skipped
+	reconcile("a")
+	require.Equal(t, 0, needsUpdateCalls)
+	// the expected state changed
+	reconcile("b")
+	require.Equal(t, 1, needsUpdateCalls)
+	reconcile("b")
+	require.Equal(t, 1, needsUpdateCalls)
+
+	// the Secret was changed by someone else
+	var secret corev1.Secret
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "secret"}, &secret))
+	secret.Data["key"] = []byte("c")
+	require.NoError(t, c.Update(context.Background(), &secret))
+	reconcile("b")
+	require.Equal(t, 2, needsUpdateCalls)
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "secret"}, &secret))
+	require.Equal(t, []byte("b"), secret.Data["key"])
+	reconcile("b")
+	require.Equal(t, 2, needsUpdateCalls)
+}
+
+func TestReconcileResource_ResultsCacheOutOfBandChanges(t *testing.T) {
+	require.NoError(t, EnableResultsCache(10))
+	t.Cleanup(func() { _ = EnableResultsCache(0) })
+
+	nsn := types.NamespacedName{Namespace: "ns", Name: "secret"}
+	c := k8s.NewFakeClient()
+	needsRecreateCalls := 0
+	reconcile := func() {
+		expected := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: nsn.Namespace, Name: nsn.Name, Labels: map[string]string{"a": "b"}},
+			Data:       map[string][]byte{"key": []byte("value")},
+		}
+		reconciled := &corev1.Secret{}
+		require.NoError(t, ReconcileResource(Params{
+			Context:    context.Background(),
+			Client:     c,
+			Expected:   expected,
+			Reconciled: reconciled,
+			NeedsRecreate: func() bool {
+				needsRecreateCalls++
+				return false
+			},
+			NeedsUpdate: func() bool {
+				return !maps.Equal(expected.Labels, reconciled.Labels) || !reflect.DeepEqual(expected.Data, reconciled.Data)
+			},
+			UpdateReconciled: func() {
+				reconciled.Labels = expected.Labels
+				reconciled.Data = expected.Data
+			},
+		}))
+	}
+	requireReconciled := func() {
+		t.Helper()
+		var secret corev1.Secret
+		require.NoError(t, c.Get(context.Background(), nsn, &secret))
+		require.Equal(t, map[string]string{"a": "b"}, secret.Labels)
+		require.Equal(t, map[string][]byte{"key": []byte("value")}, secret.Data)
+	}
+
+	reconcile()
+	reconcile()
+	requireReconciled()
+	// resources which cannot be updated are always checked, even if cached as reconciled
+	require.Equal(t, 1, needsRecreateCalls)
+
+	// the metadata is modified by someone else
+	var secret corev1.Secret
+	require.NoError(t, c.Get(context.Background(), nsn, &secret))
+	secret.Labels["a"] = "c"
+	require.NoError(t, c.Update(context.Background(), &secret))
+	reconcile()
+	requireReconciled()
+
+	// the data is removed by someone else
+	require.NoError(t, c.Get(context.Background(), nsn, &secret))
+	secret.Data = nil
+	require.NoError(t, c.Update(context.Background(), &secret))
+	reconcile()
+	requireReconciled()
+
+	// the resource is re-created by someone else with a different content
+	require.NoError(t, c.Get(context.Background(), nsn, &secret))
+	require.NoError(t, c.Delete(context.Background(), &secret))
+	require.NoError(t, c.Create(context.Background(), &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: nsn.Namespace, Name: nsn.Name, UID: "other"}}))
+	reconcile()
+	requireReconciled()
+}
+
+func TestReconcileResource_ResultsCacheDisabled(t *testing.T) {
+	require.NoError(t, EnableResultsCache(0))
+
+	c := k8s.NewFakeClient(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "secret"}})
+	needsUpdateCalls := 0
+	for i := 0; i < 2; i++ {
+		require.NoError(t, ReconcileResource(Params{
+			Context:    context.Background(),
+			Client:     c,
+			Expected:   &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "secret"}},
+			Reconciled: &corev1.Secret{},
+			NeedsUpdate: func() bool {
+				needsUpdateCalls++
+				return false
+			},
+			UpdateReconciled: func() {},
+		}))
+	}
+	require.Equal(t, 2, needsUpdateCalls)
+}
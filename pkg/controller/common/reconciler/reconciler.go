@@ -12,6 +12,7 @@ import (
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -22,6 +23,9 @@ import (
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
 )
 
+// VeleroRestoreNameLabel is set by Velero on the resources it restores, with the name of the restore.
+const VeleroRestoreNameLabel = "velero.io/restore-name"
+
 // Params is a parameter object for the ReconcileResources function
 type Params struct {
 	// Context to be used in API requests
@@ -88,6 +92,8 @@ func ReconcileResource(params Params) error {
 	namespace := params.Expected.GetNamespace()
 	name := params.Expected.GetName()
 	log := ulog.FromContext(params.Context).WithValues("kind", kind, "namespace", namespace, "name", name)
+	key := cacheKey{kind: gvk.GroupKind().String(), NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}
+	expectedStateHash := expectedHash(params.Expected)
 
 	create := func() error {
 		log.Info("Creating resource")
@@ -109,6 +115,7 @@ func ReconcileResource(params Params) error {
 			return err
 		}
 		log.Info("Created resource successfully")
+		cacheAsReconciled(key, expectedStateHash, params.Reconciled)
 		return nil
 	}
 
@@ -144,9 +151,27 @@ func ReconcileResource(params Params) error {
 		return create()
 	}
 
+	// Skip the comparison with the expected state if neither the resource, as observed above, nor its expected state have
+	// changed since it was last reconciled. Any modification made by someone else changes the resource version of the
+	// resource and is compared with the expected state.
+	if isCachedAsReconciled(key, expectedStateHash, params.Reconciled) {
+		return nil
+	}
+
+	// Adopt the resource if it is still controlled by a previous incarnation of its owner, which happens when both are
+	// restored from a backup, to prevent its deletion by the garbage collector
+	adopt := false
+	if expectedOwner := metav1.GetControllerOfNoCopy(params.Expected); expectedOwner != nil {
+		adopt = k8s.IsControlledByPreviousOwner(params.Reconciled, *expectedOwner)
+	}
+	if adopt {
+		log.Info("Adopting resource controlled by a previous incarnation of its owner",
+			"owner_uid", params.Owner.GetUID(), "restore_name", params.Reconciled.GetLabels()[VeleroRestoreNameLabel])
+	}
+
 	//nolint:nestif
 	// Update if needed
-	if params.NeedsUpdate() {
+	if adopt || params.NeedsUpdate() {
 		log.Info("Updating resource")
 		if params.PreUpdate != nil {
 			if err := params.PreUpdate(); err != nil {
@@ -184,5 +209,6 @@ func ReconcileResource(params Params) error {
 		}
 		log.Info("Updated resource successfully")
 	}
+	cacheAsReconciled(key, expectedStateHash, params.Reconciled)
 	return nil
 }
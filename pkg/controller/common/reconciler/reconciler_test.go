@@ -258,6 +258,40 @@ func TestReconcileResource(t *testing.T) {
 				require.Equal(t, "newOwner", serverState.OwnerReferences[0].Name)
 			},
 		},
+		{
+			name: "Adopt resource controlled by a previous incarnation of its owner",
+			args: func() args {
+				return args{
+					Expected:   obj.DeepCopy(),
+					Reconciled: &corev1.Secret{},
+					Owner: &appsv1.Deployment{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: objectKey.Namespace,
+							Name:      "owner",
+							UID:       "restored-uid",
+						},
+					},
+					NeedsUpdate: func() bool {
+						return false
+					},
+					UpdateReconciled: noopModifier,
+				}
+			},
+			initialObjects: []client.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace:       objectKey.Namespace,
+						Name:            objectKey.Name,
+						Labels:          map[string]string{VeleroRestoreNameLabel: "restore"},
+						OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "owner", UID: "backed-up-uid", Controller: &trueVal}},
+					},
+				},
+			},
+			serverStateAssertion: func(serverState corev1.Secret) {
+				require.Len(t, serverState.OwnerReferences, 1)
+				require.Equal(t, types.UID("restored-uid"), serverState.OwnerReferences[0].UID)
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -8,6 +8,7 @@ import (
 	"context"
 	"time"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	k8serrors "k8s.io/apimachinery/pkg/util/errors"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -93,6 +94,20 @@ func (r *Results) HasError() bool {
 	return len(r.errors) > 0
 }
 
+// HasInvalidError returns true if Results contains an error reporting that the resource does not pass validation, which
+// cannot be fixed by a new reconciliation attempt.
+func (r *Results) HasInvalidError() bool {
+	if r == nil {
+		return false
+	}
+	for _, err := range r.errors {
+		if apierrors.IsInvalid(err) {
+			return true
+		}
+	}
+	return false
+}
+
 func (r *Results) HasRequeue() bool {
 	if r == nil {
 		return false
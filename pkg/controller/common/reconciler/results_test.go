@@ -12,6 +12,9 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -133,6 +136,22 @@ func TestResultsHasError(t *testing.T) {
 	require.True(t, r.HasError())
 }
 
+func TestResults_HasInvalidError(t *testing.T) {
+	r := &Results{
+		ctx: context.Background(),
+	}
+	require.False(t, r.HasInvalidError())
+
+	r = r.WithError(errors.New("some error"))
+	require.False(t, r.HasInvalidError())
+
+	invalid := apierrors.NewInvalid(schema.GroupKind{Group: "kibana.k8s.elastic.co", Kind: "Kibana"}, "kb", field.ErrorList{
+		field.Invalid(field.NewPath("spec", "version"), "7", "invalid version"),
+	})
+	r = r.WithError(errors.Wrap(invalid, "validation"))
+	require.True(t, r.HasInvalidError())
+}
+
 func TestResults_IsReconciled(t *testing.T) {
 	tests := []struct {
 		name           string
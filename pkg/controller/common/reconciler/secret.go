@@ -17,6 +17,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	policyv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/stackconfigpolicy/v1alpha1"
+	commonlabels "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/labels"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/metadata"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
@@ -28,6 +30,10 @@ const (
 	SoftOwnerNamespaceLabel = "eck.k8s.elastic.co/owner-namespace"
 	SoftOwnerNameLabel      = "eck.k8s.elastic.co/owner-name"
 	SoftOwnerKindLabel      = "eck.k8s.elastic.co/owner-kind"
+
+	// PublishedLabel is set to "true" on soft-owned secrets that the operator copies on purpose to other namespaces than
+	// the namespace of their soft owner. They are garbage collected in all namespaces.
+	PublishedLabel = "eck.k8s.elastic.co/published"
 )
 
 func WithPostUpdate(f func()) func(p *Params) {
@@ -38,7 +44,13 @@ func WithPostUpdate(f func()) func(p *Params) {
 
 // ReconcileSecret creates or updates the actual secret to match the expected one.
 // Existing annotations or labels that are not expected are preserved.
+// Secrets holding credentials also get the labels and annotations configured for them in the operator, and all secrets
+// get the labels and annotations of their owner selected for propagation.
 func ReconcileSecret(ctx context.Context, c k8s.Client, expected corev1.Secret, owner client.Object, opts ...func(*Params)) (corev1.Secret, error) {
+	expected.Labels, expected.Annotations = commonlabels.WithCredentialsSecretMetadata(expected.Labels, expected.Annotations)
+	if owner != nil {
+		expected.Labels, expected.Annotations = metadata.Propagate(owner, expected.Labels, expected.Annotations)
+	}
 	var reconciled corev1.Secret
 
 	params := Params{
@@ -116,6 +128,8 @@ func ReconcileSecretNoOwnerRef(ctx context.Context, c k8s.Client, expected corev
 
 	// don't mutate expected (no side effects), make a copy
 	expected = *expected.DeepCopy()
+	expected.Labels, expected.Annotations = commonlabels.WithCredentialsSecretMetadata(expected.Labels, expected.Annotations)
+	expected.Labels, expected.Annotations = metadata.Propagate(ownerMeta, expected.Labels, expected.Annotations)
 	expected.Labels[SoftOwnerNamespaceLabel] = ownerMeta.GetNamespace()
 	expected.Labels[SoftOwnerNameLabel] = ownerMeta.GetName()
 	expected.Labels[SoftOwnerKindLabel] = softOwner.GetObjectKind().GroupVersionKind().Kind
@@ -162,18 +176,18 @@ func GarbageCollectSoftOwnedSecrets(ctx context.Context, c k8s.Client, deletedOw
 		SoftOwnerNameLabel:      deletedOwner.Name,
 		SoftOwnerKindLabel:      ownerKind,
 	}}
-	// restrict to secrets in the parent namespace, we don't want to delete secrets users may have manually copied into
-	// other namespaces (except for kind where we control these secrets)
-	if restrictedToOwnerNamespace(ownerKind) {
-		listOpts = append(listOpts, client.InNamespace(deletedOwner.Namespace))
-	}
 	if err := c.List(ctx, &secrets, listOpts...); err != nil {
 		return err
 	}
 	for i := range secrets.Items {
 		s := secrets.Items[i]
+		// restrict to secrets in the parent namespace, we don't want to delete secrets users may have manually copied into
+		// other namespaces (except for secrets we copied or kind where we control these secrets)
+		if restrictedToOwnerNamespace(ownerKind, s.Labels) && s.Namespace != deletedOwner.Namespace {
+			continue
+		}
 		log.Info("Garbage collecting secret",
-			"namespace", deletedOwner.Namespace, "secret_name", s.Name,
+			"namespace", s.Namespace, "secret_name", s.Name,
 			"owner_name", deletedOwner.Name, "owner_kind", ownerKind)
 		err := c.Delete(ctx, &s, &client.DeleteOptions{Preconditions: &metav1.Preconditions{UID: &s.UID}})
 		if apierrors.IsNotFound(err) {
@@ -208,7 +222,7 @@ func GarbageCollectAllSoftOwnedOrphanSecrets(ctx context.Context, c k8s.Client,
 		if !referenced {
 			continue
 		}
-		if restrictedToOwnerNamespace(softOwner.Kind) && softOwner.Namespace != secret.Namespace {
+		if restrictedToOwnerNamespace(softOwner.Kind, secret.Labels) && softOwner.Namespace != secret.Namespace {
 			// Secret references an owner in a different namespace: this likely results
 			// from a "manual" copy of the secret in another namespace, not handled by the operator.
 			// We don't want to touch that secret.
@@ -240,7 +254,8 @@ func GarbageCollectAllSoftOwnedOrphanSecrets(ctx context.Context, c k8s.Client,
 	return nil
 }
 
-// restrictedToOwnerNamespace returns true if a resource should have its owner in the same namespace, based on the kind of owner.
-func restrictedToOwnerNamespace(kind string) bool {
-	return kind != policyv1alpha1.Kind
+// restrictedToOwnerNamespace returns true if a resource should have its owner in the same namespace, based on the kind of owner
+// and on whether the resource was published to other namespaces.
+func restrictedToOwnerNamespace(kind string, labels map[string]string) bool {
+	return kind != policyv1alpha1.Kind && labels[PublishedLabel] != "true"
 }
@@ -20,6 +20,8 @@ import (
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	policyv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/stackconfigpolicy/v1alpha1"
+	commonlabels "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/labels"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/metadata"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
 )
@@ -128,6 +130,49 @@ func TestReconcileSecret(t *testing.T) {
 	}
 }
 
+func TestReconcileSecret_CredentialsSecretMetadata(t *testing.T) {
+	commonlabels.SetCredentialsSecretMetadata(map[string]string{"replicate": "true"}, map[string]string{"reflector/allowed": "true"})
+	defer commonlabels.SetCredentialsSecretMetadata(nil, nil)
+
+	credentialsLabels := commonlabels.AddCredentialsLabel(map[string]string{"label1": "value1"})
+	c := k8s.NewFakeClient()
+	_, err := ReconcileSecret(context.Background(), c, *createSecret("credentials", sampleData, credentialsLabels, nil), owner)
+	require.NoError(t, err)
+	_, err = ReconcileSecret(context.Background(), c, *createSecret("other", sampleData, sampleLabels, nil), owner)
+	require.NoError(t, err)
+
+	var credentials, other corev1.Secret
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: testNamespace, Name: "credentials"}, &credentials))
+	require.Equal(t, concatMaps(credentialsLabels, map[string]string{"replicate": "true"}), credentials.Labels)
+	require.Equal(t, map[string]string{"reflector/allowed": "true"}, credentials.Annotations)
+	// the expected labels are not modified
+	require.NotContains(t, credentialsLabels, "replicate")
+
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: testNamespace, Name: "other"}, &other))
+	require.Equal(t, sampleLabels, other.Labels)
+	require.Empty(t, other.Annotations)
+}
+
+func TestReconcileSecret_PropagatedMetadata(t *testing.T) {
+	parent := owner.DeepCopy()
+	parent.Labels = map[string]string{"cost-center": "42"}
+	parent.Annotations = map[string]string{
+		metadata.PropagateLabelsAnnotation:      "*",
+		metadata.PropagateAnnotationsAnnotation: "velero.io/backup",
+		"velero.io/backup":                      "daily",
+	}
+	c := k8s.NewFakeClient()
+	_, err := ReconcileSecret(context.Background(), c, *createSecret("secret", sampleData, sampleLabels, nil), parent)
+	require.NoError(t, err)
+
+	var secret corev1.Secret
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: testNamespace, Name: "secret"}, &secret))
+	require.Equal(t, concatMaps(sampleLabels, map[string]string{"cost-center": "42"}), secret.Labels)
+	require.Equal(t, map[string]string{"velero.io/backup": "daily"}, secret.Annotations)
+	// the expected labels are not modified
+	require.NotContains(t, sampleLabels, "cost-center")
+}
+
 func concatMaps(m1 map[string]string, m2 map[string]string) map[string]string {
 	newMap := map[string]string{}
 	maps.Merge(newMap, m1)
@@ -257,6 +302,12 @@ func ownedSecret(namespace, name, ownerNs, ownerName, ownerKind string) *corev1.
 		}}}
 }
 
+func publishedSecret(namespace, name, ownerNs, ownerName, ownerKind string) *corev1.Secret {
+	secret := ownedSecret(namespace, name, ownerNs, ownerName, ownerKind)
+	secret.Labels[PublishedLabel] = "true"
+	return secret
+}
+
 func TestGarbageCollectSoftOwnedSecrets(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -332,6 +383,18 @@ func TestGarbageCollectSoftOwnedSecrets(t *testing.T) {
 				ownedSecret("ns", "secret-5", sampleOwner().Namespace, sampleOwner().Name, "another-kind"),
 			},
 		},
+		{
+			name: "gc secrets published to other namespaces",
+			existingSecrets: []client.Object{
+				ownedSecret("ns-2", "secret-1", sampleOwner().Namespace, sampleOwner().Name, sampleOwner().Kind),
+				publishedSecret("ns-2", "secret-2", sampleOwner().Namespace, sampleOwner().Name, sampleOwner().Kind),
+				publishedSecret("ns-3", "secret-2", sampleOwner().Namespace, sampleOwner().Name, sampleOwner().Kind),
+			},
+			deletedOwner: k8s.ExtractNamespacedName(sampleOwner()),
+			ownerKind:    "Secret",
+			wantObjs: []client.Object{
+				ownedSecret("ns-2", "secret-1", sampleOwner().Namespace, sampleOwner().Name, sampleOwner().Kind)},
+		},
 		{
 			name: "gc secrets pointing to a stackconfigpolicy soft owner with a different namespace",
 			existingSecrets: []client.Object{
@@ -0,0 +1,108 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package restart
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// Start starts the restart of the Pods of the given resource requested with the restart annotation, if any and if no
+// restart is already in progress. The start time is recorded in the restarted-at annotation of the resource, which is
+// propagated to its Pod templates so that the Pods are restarted by the regular orchestration of the changes of the
+// resource, and in its restart status. The status is updated immediately so that the restart is not started again if
+// the status update at the end of the reconciliation fails.
+func Start(ctx context.Context, c k8s.Client, recorder record.EventRecorder, obj commonv1.Restartable) error {
+	if obj.GetRestartStatus() != nil {
+		return nil
+	}
+	requested, exists := obj.GetAnnotations()[commonv1.RestartAnnotation]
+	if !exists {
+		return nil
+	}
+	restartType := commonv1.RestartType(requested)
+	if restartType != commonv1.RollingRestart && restartType != commonv1.FullRestart {
+		recorder.Eventf(obj, corev1.EventTypeWarning, events.EventReasonValidation, "Invalid value %q of the %s annotation, expected %s or %s",
+			requested, commonv1.RestartAnnotation, commonv1.RollingRestart, commonv1.FullRestart)
+		return nil
+	}
+
+	startTime := metav1.NewTime(time.Now().Truncate(time.Second))
+	if err := k8s.PatchAnnotations(ctx, c, obj, map[string]*string{
+		commonv1.RestartedAtAnnotation: ptr.To(startTime.UTC().Format(time.RFC3339)),
+	}); err != nil {
+		return err
+	}
+	obj.SetRestartStatus(&commonv1.RestartStatus{Type: restartType, StartTime: startTime})
+	if err := c.Status().Update(ctx, obj); err != nil {
+		return err
+	}
+	ulog.FromContext(ctx).Info("Starting restart of the Pods", "namespace", obj.GetNamespace(), "name", obj.GetName(), "type", restartType)
+	recorder.Eventf(obj, corev1.EventTypeNormal, events.EventReasonRestarted, "Starting %s restart of the Pods", restartType)
+	return nil
+}
+
+// UpdateProgress updates the restart status of the given resource with the number of the given Pods which are already
+// restarted and ready. Once all the expected Pods are restarted, the restart annotation is removed from the resource
+// and its restart status is cleared.
+func UpdateProgress(ctx context.Context, c k8s.Client, recorder record.EventRecorder, obj commonv1.Restartable, pods []corev1.Pod, expectedPods int32) error {
+	status := obj.GetRestartStatus()
+	if status == nil {
+		return nil
+	}
+	var restartedPods int32
+	for _, pod := range pods {
+		if pod.DeletionTimestamp.IsZero() && IsRestarted(obj, pod) && k8s.IsPodReady(pod) {
+			restartedPods++
+		}
+	}
+	if restartedPods < expectedPods {
+		status = status.DeepCopy()
+		status.RestartedPods, status.ExpectedPods = restartedPods, expectedPods
+		obj.SetRestartStatus(status)
+		return nil
+	}
+
+	if _, exists := obj.GetAnnotations()[commonv1.RestartAnnotation]; exists {
+		if err := k8s.PatchAnnotations(ctx, c, obj, map[string]*string{commonv1.RestartAnnotation: nil}); err != nil {
+			return err
+		}
+	}
+	obj.SetRestartStatus(nil)
+	ulog.FromContext(ctx).Info("Restart of the Pods completed", "namespace", obj.GetNamespace(), "name", obj.GetName(), "type", status.Type)
+	recorder.Eventf(obj, corev1.EventTypeNormal, events.EventReasonRestarted, "Completed %s restart of the Pods", status.Type)
+	return nil
+}
+
+// IsFull returns true if a full restart of the Pods of the given resource is in progress.
+func IsFull(obj commonv1.Restartable) bool {
+	status := obj.GetRestartStatus()
+	return status != nil && status.Type == commonv1.FullRestart
+}
+
+// PodAnnotations returns the annotations to set on the Pod templates of the given resource for its Pods to be
+// restarted when a restart is requested.
+func PodAnnotations(obj metav1.Object) map[string]string {
+	restartedAt, exists := obj.GetAnnotations()[commonv1.RestartedAtAnnotation]
+	if !exists {
+		return nil
+	}
+	return map[string]string{commonv1.RestartedAtAnnotation: restartedAt}
+}
+
+// IsRestarted returns true if the given Pod was created after the last restart requested for the given resource.
+func IsRestarted(obj metav1.Object, pod corev1.Pod) bool {
+	return pod.Annotations[commonv1.RestartedAtAnnotation] == obj.GetAnnotations()[commonv1.RestartedAtAnnotation]
+}
@@ -0,0 +1,92 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package restart
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	controllerscheme "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/scheme"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func pod(name, restartedAt string, ready bool) corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: map[string]string{commonv1.RestartedAtAnnotation: restartedAt}},
+		Status: corev1.PodStatus{Conditions: []corev1.PodCondition{
+			{Type: corev1.PodReady, Status: status},
+			{Type: corev1.ContainersReady, Status: status},
+		}},
+	}
+}
+
+func TestRestart(t *testing.T) {
+	controllerscheme.SetupScheme()
+	ctx := context.Background()
+	kb := kbv1.Kibana{ObjectMeta: metav1.ObjectMeta{
+		Namespace:   "ns",
+		Name:        "kb",
+		Annotations: map[string]string{commonv1.RestartAnnotation: "full"},
+	}}
+	c := k8s.NewFakeClient(&kb)
+	recorder := record.NewFakeRecorder(10)
+	stored := func() kbv1.Kibana {
+		var actual kbv1.Kibana
+		require.NoError(t, c.Get(ctx, k8s.ExtractNamespacedName(&kb), &actual))
+		return actual
+	}
+
+	// the restart is started and recorded in the annotations and the status
+	require.NoError(t, Start(ctx, c, recorder, &kb))
+	require.True(t, IsFull(&kb))
+	restartedAt := kb.Annotations[commonv1.RestartedAtAnnotation]
+	require.NotEmpty(t, restartedAt)
+	require.Equal(t, map[string]string{commonv1.RestartedAtAnnotation: restartedAt}, PodAnnotations(&kb))
+	actual := stored()
+	require.Equal(t, restartedAt, actual.Annotations[commonv1.RestartedAtAnnotation])
+	require.NotNil(t, actual.Status.Restart)
+	require.Equal(t, commonv1.FullRestart, actual.Status.Restart.Type)
+
+	// the restart is not started again while in progress
+	require.NoError(t, Start(ctx, c, recorder, &kb))
+	require.Equal(t, restartedAt, kb.Annotations[commonv1.RestartedAtAnnotation])
+
+	// progress is reported until all the Pods are restarted and ready
+	pods := []corev1.Pod{pod("kb-0", restartedAt, true), pod("kb-1", restartedAt, false), pod("kb-2", "", true)}
+	require.NoError(t, UpdateProgress(ctx, c, recorder, &kb, pods, 2))
+	require.Equal(t, int32(1), kb.Status.Restart.RestartedPods)
+	require.Equal(t, int32(2), kb.Status.Restart.ExpectedPods)
+
+	pods = []corev1.Pod{pod("kb-0", restartedAt, true), pod("kb-1", restartedAt, true)}
+	require.NoError(t, UpdateProgress(ctx, c, recorder, &kb, pods, 2))
+	require.Nil(t, kb.Status.Restart)
+	require.NotContains(t, stored().Annotations, commonv1.RestartAnnotation)
+	require.Equal(t, restartedAt, stored().Annotations[commonv1.RestartedAtAnnotation])
+}
+
+func TestStart_InvalidType(t *testing.T) {
+	controllerscheme.SetupScheme()
+	kb := kbv1.Kibana{ObjectMeta: metav1.ObjectMeta{
+		Namespace:   "ns",
+		Name:        "kb",
+		Annotations: map[string]string{commonv1.RestartAnnotation: "now"},
+	}}
+	recorder := record.NewFakeRecorder(10)
+	require.NoError(t, Start(context.Background(), k8s.NewFakeClient(&kb), recorder, &kb))
+	require.Nil(t, kb.Status.Restart)
+	require.NotContains(t, kb.Annotations, commonv1.RestartedAtAnnotation)
+	require.Len(t, recorder.Events, 1)
+}
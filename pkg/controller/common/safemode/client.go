@@ -0,0 +1,160 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package safemode
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
+)
+
+// statusSubResource is the only subresource the operator is allowed to update in safe mode.
+const statusSubResource = "status"
+
+// NewClient wraps the given client so that all the writes, except the updates of the status subresource, are sent
+// to the API server in dry-run mode and logged instead of being persisted.
+func NewClient(c client.Client) client.Client {
+	return &safeModeClient{Client: c}
+}
+
+type safeModeClient struct {
+	client.Client
+}
+
+var _ client.Client = &safeModeClient{}
+
+func (c *safeModeClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	c.logSkipped(ctx, "create", obj)
+	return c.Client.Create(ctx, obj, append(opts, client.DryRunAll)...)
+}
+
+func (c *safeModeClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	c.logSkipped(ctx, "update", obj)
+	return c.Client.Update(ctx, obj, append(opts, client.DryRunAll)...)
+}
+
+func (c *safeModeClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	c.logSkipped(ctx, "patch", obj)
+	return c.Client.Patch(ctx, obj, patch, append(opts, client.DryRunAll)...)
+}
+
+func (c *safeModeClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	c.logSkipped(ctx, "delete", obj)
+	return c.Client.Delete(ctx, obj, append(opts, client.DryRunAll)...)
+}
+
+func (c *safeModeClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	c.logSkipped(ctx, "deletecollection", obj)
+	return c.Client.DeleteAllOf(ctx, obj, append(opts, client.DryRunAll)...)
+}
+
+func (c *safeModeClient) SubResource(subResource string) client.SubResourceClient {
+	if subResource == statusSubResource {
+		return c.Client.SubResource(subResource)
+	}
+	return &safeModeSubResourceClient{SubResourceClient: c.Client.SubResource(subResource), parent: c, subResource: subResource}
+}
+
+func (c *safeModeClient) logSkipped(ctx context.Context, verb string, obj client.Object) {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if gvk, err := c.GroupVersionKindFor(obj); err == nil {
+		kind = gvk.Kind
+	}
+	ulog.FromContext(ctx).Info("Safe mode: skipping Kubernetes request",
+		"verb", verb, "kind", kind, "namespace", obj.GetNamespace(), "name", obj.GetName())
+	metrics.SafeModeSkippedRequests.WithLabelValues(verb, kind).Inc()
+}
+
+type safeModeSubResourceClient struct {
+	client.SubResourceClient
+	parent      *safeModeClient
+	subResource string
+}
+
+func (c *safeModeSubResourceClient) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	c.parent.logSkipped(ctx, "create "+c.subResource, obj)
+	return c.SubResourceClient.Create(ctx, obj, subResource, append(opts, client.DryRunAll)...)
+}
+
+func (c *safeModeSubResourceClient) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	c.parent.logSkipped(ctx, "update "+c.subResource, obj)
+	return c.SubResourceClient.Update(ctx, obj, append(opts, client.DryRunAll)...)
+}
+
+func (c *safeModeSubResourceClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	c.parent.logSkipped(ctx, "patch "+c.subResource, obj)
+	return c.SubResourceClient.Patch(ctx, obj, patch, append(opts, client.DryRunAll)...)
+}
+
+// reviewAPIPrefixes are the path prefixes of the APIs whose creations are reviews which are never persisted, and are
+// sent as is in safe mode.
+var reviewAPIPrefixes = []string{
+	"/apis/authentication.k8s.io/",
+	"/apis/authorization.k8s.io/",
+}
+
+// NewConfig returns a copy of the given REST configuration whose clients, such as client-go clientsets, send all the
+// writes to the API server in dry-run mode and log them instead of persisting them.
+func NewConfig(cfg *rest.Config) *rest.Config {
+	cfg = rest.CopyConfig(cfg)
+	cfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &dryRunRoundTripper{next: rt}
+	})
+	return cfg
+}
+
+type dryRunRoundTripper struct {
+	next http.RoundTripper
+}
+
+func (rt *dryRunRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isMutatingMethod(req.Method) || isReviewRequest(req) {
+		return rt.next.RoundTrip(req)
+	}
+	verb := strings.ToLower(req.Method)
+	resource := resourceFromPath(req.URL.Path)
+	ulog.FromContext(req.Context()).Info("Safe mode: skipping Kubernetes request", "verb", verb, "path", req.URL.Path)
+	metrics.SafeModeSkippedRequests.WithLabelValues(verb, resource).Inc()
+
+	dryRunReq := req.Clone(req.Context())
+	query := dryRunReq.URL.Query()
+	query.Set("dryRun", "All")
+	dryRunReq.URL.RawQuery = query.Encode()
+	return rt.next.RoundTrip(dryRunReq)
+}
+
+func isReviewRequest(req *http.Request) bool {
+	for _, prefix := range reviewAPIPrefixes {
+		if strings.HasPrefix(req.URL.Path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceFromPath returns the resource targeted by the given Kubernetes API path, for example "configmaps" for
+// /api/v1/namespaces/ns/configmaps/name.
+func resourceFromPath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	// skip /api/{version} or /apis/{group}/{version}
+	switch {
+	case len(segments) > 2 && segments[0] == "api":
+		segments = segments[2:]
+	case len(segments) > 3 && segments[0] == "apis":
+		segments = segments[3:]
+	default:
+		return path
+	}
+	if len(segments) > 2 && segments[0] == "namespaces" {
+		return segments[2]
+	}
+	return segments[0]
+}
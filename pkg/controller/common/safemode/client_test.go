@@ -0,0 +1,170 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package safemode
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func TestNewClient(t *testing.T) {
+	es := &esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
+	existing := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "existing"},
+		Data:       map[string][]byte{"key": []byte("value")},
+	}
+	c := NewClient(k8s.NewFakeClient(es, existing))
+	ctx := context.Background()
+
+	// creations are not persisted
+	created := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "created"}}
+	require.NoError(t, c.Create(ctx, created))
+	err := c.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "created"}, &corev1.Secret{})
+	require.True(t, apierrors.IsNotFound(err))
+
+	// updates are not persisted
+	updated := existing.DeepCopy()
+	updated.Data["key"] = []byte("updated")
+	require.NoError(t, c.Update(ctx, updated))
+	var secret corev1.Secret
+	require.NoError(t, c.Get(ctx, k8s.ExtractNamespacedName(existing), &secret))
+	require.Equal(t, "value", string(secret.Data["key"]))
+
+	// patches are not persisted
+	patched := secret.DeepCopy()
+	patched.Labels = map[string]string{"patched": "true"}
+	require.NoError(t, c.Patch(ctx, patched, client.MergeFrom(&secret)))
+	require.NoError(t, c.Get(ctx, k8s.ExtractNamespacedName(existing), &secret))
+	require.Empty(t, secret.Labels)
+
+	// deletions are not persisted
+	require.NoError(t, c.Delete(ctx, &secret))
+	require.NoError(t, c.Get(ctx, k8s.ExtractNamespacedName(existing), &secret))
+
+	// status updates are persisted
+	var current esv1.Elasticsearch
+	require.NoError(t, c.Get(ctx, k8s.ExtractNamespacedName(es), &current))
+	current.Status.Phase = esv1.ElasticsearchReadyPhase
+	require.NoError(t, c.Status().Update(ctx, &current))
+	require.NoError(t, c.Get(ctx, k8s.ExtractNamespacedName(es), &current))
+	require.Equal(t, esv1.ElasticsearchReadyPhase, current.Status.Phase)
+}
+
+func TestNewConfig(t *testing.T) {
+	dryRuns := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		dryRuns[r.Method+" "+r.URL.Path] = r.URL.Query().Get("dryRun")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	clientset, err := kubernetes.NewForConfig(NewConfig(&rest.Config{Host: server.URL}))
+	require.NoError(t, err)
+	ctx := context.Background()
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm"}}
+
+	_, err = clientset.CoreV1().ConfigMaps("ns").Get(ctx, "cm", metav1.GetOptions{})
+	require.NoError(t, err)
+	_, err = clientset.CoreV1().ConfigMaps("ns").Create(ctx, cm, metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = clientset.CoreV1().ConfigMaps("ns").Update(ctx, cm, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, clientset.CoreV1().ConfigMaps("ns").Delete(ctx, "cm", metav1.DeleteOptions{}))
+	_, err = clientset.AdmissionregistrationV1().ValidatingWebhookConfigurations().Patch(ctx, "webhook", types.MergePatchType, []byte(`{}`), metav1.PatchOptions{})
+	require.NoError(t, err)
+	_, err = clientset.AuthorizationV1().SubjectAccessReviews().Create(ctx, &authorizationv1.SubjectAccessReview{}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]string{
+		// reads are sent as is
+		"GET /api/v1/namespaces/ns/configmaps/cm": "",
+		// writes are sent in dry-run mode
+		"POST /api/v1/namespaces/ns/configmaps":                                               "All",
+		"PUT /api/v1/namespaces/ns/configmaps/cm":                                             "All",
+		"DELETE /api/v1/namespaces/ns/configmaps/cm":                                          "All",
+		"PATCH /apis/admissionregistration.k8s.io/v1/validatingwebhookconfigurations/webhook": "All",
+		// reviews are never persisted and are sent as is
+		"POST /apis/authorization.k8s.io/v1/subjectaccessreviews": "",
+	}, dryRuns)
+}
+
+func Test_resourceFromPath(t *testing.T) {
+	require.Equal(t, "configmaps", resourceFromPath("/api/v1/namespaces/ns/configmaps/cm"))
+	require.Equal(t, "configmaps", resourceFromPath("/api/v1/namespaces/ns/configmaps"))
+	require.Equal(t, "namespaces", resourceFromPath("/api/v1/namespaces/ns"))
+	require.Equal(t, "validatingwebhookconfigurations", resourceFromPath("/apis/admissionregistration.k8s.io/v1/validatingwebhookconfigurations/webhook"))
+	require.Equal(t, "/version", resourceFromPath("/version"))
+}
+
+func TestCheckRequest(t *testing.T) {
+	defer func() { Enabled = false }()
+
+	require.NoError(t, CheckRequest(context.Background(), "POST", "https://es:9200/_cluster/voting_config_exclusions"))
+
+	Enable()
+	require.NoError(t, CheckRequest(context.Background(), "GET", "https://es:9200/_cluster/health"))
+	for _, method := range []string{"POST", "PUT", "DELETE", "PATCH"} {
+		require.ErrorIs(t, CheckRequest(context.Background(), method, "https://es:9200/_cluster/settings"), ErrMutationRefused)
+	}
+}
+
+func TestCheckRequest_ReadOnlyPost(t *testing.T) {
+	Enabled = true
+	t.Cleanup(func() { Enabled = false })
+
+	tests := []struct {
+		method  string
+		target  string
+		refused bool
+	}{
+		{method: http.MethodGet, target: "https://es-es-http.ns.svc:9200/_cluster/health", refused: false},
+		{method: http.MethodHead, target: "/", refused: false},
+		{method: http.MethodPost, target: "https://es-es-http.ns.svc:9200/my-index/_search?size=0", refused: false},
+		{method: http.MethodPost, target: "/_search", refused: false},
+		{method: http.MethodPost, target: "/my-index/_count", refused: false},
+		{method: http.MethodPost, target: "/_security/user/_has_privileges", refused: false},
+		{method: http.MethodPost, target: "https://es-es-http.ns.svc:9200/_cluster/allocation/explain", refused: false},
+		{method: http.MethodPost, target: "/my-index/_validate/query", refused: false},
+		{method: http.MethodPost, target: "/_nodes/stats", refused: false},
+		{method: http.MethodPost, target: "/_nodes/node-1,node-2/stats/jvm", refused: false},
+		{method: http.MethodPost, target: "/_nodes/reload_secure_settings", refused: true},
+		{method: http.MethodPost, target: "/my-index/_delete_by_query", refused: true},
+		{method: http.MethodPost, target: "/my-index/_doc", refused: true},
+		{method: http.MethodPost, target: "/_security/api_key", refused: true},
+		{method: http.MethodPut, target: "/my-index/_search", refused: true},
+		{method: http.MethodPut, target: "/_cluster/settings", refused: true},
+		{method: http.MethodDelete, target: "/_search/scroll", refused: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.target, func(t *testing.T) {
+			err := CheckRequest(context.Background(), tt.method, tt.target)
+			if tt.refused {
+				require.ErrorIs(t, err, ErrMutationRefused)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+
+	// all the requests are allowed outside of safe mode
+	Enabled = false
+	require.NoError(t, CheckRequest(context.Background(), http.MethodPut, "/_cluster/settings"))
+}
@@ -0,0 +1,101 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+// Package safemode implements the read-only mode of the operator: the operator keeps observing the managed resources
+// and updating their status, but it refuses to create, update or delete any Kubernetes resource and to call any
+// mutating API of the Elastic stack applications.
+package safemode
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
+)
+
+var (
+	// Enabled indicates whether the operator runs in safe mode.
+	Enabled = false
+
+	// ErrMutationRefused is returned by the HTTP clients of the operator for the requests refused in safe mode.
+	ErrMutationRefused = errors.New("request refused, the operator runs in safe mode")
+)
+
+// Enable puts the operator in safe mode.
+func Enable() {
+	Enabled = true
+	metrics.SafeModeGauge.WithLabelValues().Set(1)
+}
+
+// CheckRequest returns ErrMutationRefused if the operator runs in safe mode and the HTTP request with the given method
+// may mutate the target application. The refused request is logged so that the intended change can be reviewed.
+func CheckRequest(ctx context.Context, method, target string) error {
+	if !Enabled || !isMutatingRequest(method, target) {
+		return nil
+	}
+	ulog.FromContext(ctx).Info("Safe mode: skipping HTTP request", "method", method, "target", target)
+	metrics.SafeModeSkippedRequests.WithLabelValues(method, "HTTPRequest").Inc()
+	return ErrMutationRefused
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
+
+// readOnlyPostEndpoints are the last path segments of the Elasticsearch APIs which use the POST method to send a request
+// body, but do not change the state of the cluster.
+var readOnlyPostEndpoints = map[string]bool{
+	"_analyze":        true,
+	"_count":          true,
+	"_field_caps":     true,
+	"_has_privileges": true,
+	"_mget":           true,
+	"_msearch":        true,
+	"_search":         true,
+}
+
+// readOnlyPostPaths are the paths, or path suffixes, of the other read-only Elasticsearch APIs using the POST method.
+var readOnlyPostPaths = []string{
+	"_cluster/allocation/explain",
+	"_search/scroll",
+	"_validate/query",
+}
+
+// isMutatingRequest returns true if the HTTP request with the given method may mutate the target application. The
+// target is either a URL or a path.
+func isMutatingRequest(method, target string) bool {
+	if !isMutatingMethod(method) {
+		return false
+	}
+	return method != http.MethodPost || !isReadOnlyPostPath(target)
+}
+
+func isReadOnlyPostPath(target string) bool {
+	path := target
+	if u, err := url.Parse(target); err == nil {
+		path = u.Path
+	}
+	path = strings.Trim(path, "/")
+	segments := strings.Split(path, "/")
+	if readOnlyPostEndpoints[segments[len(segments)-1]] {
+		return true
+	}
+	for _, readOnlyPath := range readOnlyPostPaths {
+		if path == readOnlyPath || strings.HasSuffix(path, "/"+readOnlyPath) {
+			return true
+		}
+	}
+	// the nodes statistics and usage APIs: _nodes/stats, _nodes/{node_id}/stats/{metric}, _nodes/usage...
+	return segments[0] == "_nodes" && (slices.Contains(segments, "stats") || slices.Contains(segments, "usage"))
+}
@@ -23,9 +23,15 @@ import (
 	esv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1beta1"
 	entv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/enterprisesearch/v1"
 	entv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/enterprisesearch/v1beta1"
+	esclonev1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/esclone/v1alpha1"
+	esconfigv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/esconfig/v1"
+	estaskv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/estask/v1alpha1"
+	itcv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/indextemplateclaim/v1alpha1"
 	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
 	kbv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1beta1"
 	emsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/maps/v1alpha1"
+	otelv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/otel/v1alpha1"
+	secv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/security/v1alpha1"
 	policyv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/stackconfigpolicy/v1alpha1"
 )
 
@@ -57,7 +63,13 @@ func SetupScheme() {
 		agentv1alpha1.AddToScheme,
 		emsv1alpha1.AddToScheme,
 		policyv1alpha1.AddToScheme,
+		itcv1alpha1.AddToScheme,
+		esconfigv1.AddToScheme,
+		esclonev1alpha1.AddToScheme,
+		estaskv1alpha1.AddToScheme,
+		secv1alpha1.AddToScheme,
 		logstashv1alpha1.AddToScheme,
+		otelv1alpha1.AddToScheme,
 	}
 	mustAddSchemeOnce(&addToScheme, schemes)
 }
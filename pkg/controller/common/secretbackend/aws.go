@@ -0,0 +1,268 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package secretbackend
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
+)
+
+const (
+	// AWSSecretsManagerName is the name of the AWS Secrets Manager secret backend, referenced in the secure settings of
+	// the resources.
+	AWSSecretsManagerName = "aws-secrets-manager"
+
+	awsRequestTimeout = 10 * time.Second
+	// awsCredentialsExpiryMargin is the time before their expiration at which temporary credentials are renewed.
+	awsCredentialsExpiryMargin = 5 * time.Minute
+)
+
+// AWSSecretsManagerConfig is the configuration of the AWS Secrets Manager secret backend. Static credentials are read
+// from the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN environment variables. Otherwise, temporary
+// credentials are requested to AWS STS for the role and the web identity token set in the AWS_ROLE_ARN and
+// AWS_WEB_IDENTITY_TOKEN_FILE environment variables, as configured by IAM roles for service accounts on EKS.
+type AWSSecretsManagerConfig struct {
+	// Region is the AWS region of the secrets.
+	Region string
+	// Endpoint overrides the endpoint of AWS Secrets Manager, defaults to the public endpoint of the region.
+	Endpoint string
+	// STSEndpoint overrides the endpoint of AWS STS, defaults to the public endpoint of the region.
+	STSEndpoint string
+}
+
+// awsCredentials are the credentials used to sign the requests to AWS.
+type awsCredentials struct {
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+	// expiration is zero for static credentials.
+	expiration time.Time
+}
+
+// AWSSecretsManager reads secrets from AWS Secrets Manager. Secrets must be stored as JSON objects of key-value pairs.
+type AWSSecretsManager struct {
+	config AWSSecretsManagerConfig
+	client *http.Client
+	getenv func(string) string
+	now    func() time.Time
+
+	lock        sync.Mutex
+	credentials awsCredentials
+}
+
+var _ keystore.SecretBackend = &AWSSecretsManager{}
+
+// NewAWSSecretsManager returns an AWS Secrets Manager secret backend.
+func NewAWSSecretsManager(config AWSSecretsManagerConfig) (*AWSSecretsManager, error) {
+	if config.Region == "" {
+		return nil, errors.New("the AWS region must be set")
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = fmt.Sprintf("https://secretsmanager.%s.amazonaws.com", config.Region)
+	}
+	if config.STSEndpoint == "" {
+		config.STSEndpoint = fmt.Sprintf("https://sts.%s.amazonaws.com", config.Region)
+	}
+	return &AWSSecretsManager{
+		config: config,
+		client: &http.Client{Timeout: awsRequestTimeout},
+		getenv: os.Getenv,
+		now:    time.Now,
+	}, nil
+}
+
+// Read returns the key-value pairs of the secret with the given name or ARN.
+func (a *AWSSecretsManager) Read(ctx context.Context, path string) (map[string][]byte, error) {
+	credentials, err := a.retrieveCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(map[string]string{"SecretId": path})
+	if err != nil {
+		return nil, err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, a.config.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	request.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	signAWSRequest(request, body, credentials, a.config.Region, "secretsmanager", a.now())
+
+	respBody, err := a.do(request)
+	if err != nil {
+		return nil, fmt.Errorf("while getting the value of secret %s: %w", path, err)
+	}
+	var secretValue struct {
+		SecretString *string `json:"SecretString"`
+	}
+	if err := json.Unmarshal(respBody, &secretValue); err != nil {
+		return nil, err
+	}
+	if secretValue.SecretString == nil {
+		return nil, fmt.Errorf("secret %s has no string value", path)
+	}
+	var pairs map[string]string
+	if err := json.Unmarshal([]byte(*secretValue.SecretString), &pairs); err != nil {
+		return nil, fmt.Errorf("secret %s is not a JSON object of key-value pairs", path)
+	}
+	values := make(map[string][]byte, len(pairs))
+	for k, v := range pairs {
+		values[k] = []byte(v)
+	}
+	return values, nil
+}
+
+// retrieveCredentials returns the static credentials set in the environment, or temporary credentials for the web
+// identity of the operator, which are renewed shortly before they expire.
+func (a *AWSSecretsManager) retrieveCredentials(ctx context.Context) (awsCredentials, error) {
+	if accessKeyID := a.getenv("AWS_ACCESS_KEY_ID"); accessKeyID != "" {
+		return awsCredentials{
+			accessKeyID:     accessKeyID,
+			secretAccessKey: a.getenv("AWS_SECRET_ACCESS_KEY"),
+			sessionToken:    a.getenv("AWS_SESSION_TOKEN"),
+		}, nil
+	}
+	roleARN, tokenFile := a.getenv("AWS_ROLE_ARN"), a.getenv("AWS_WEB_IDENTITY_TOKEN_FILE")
+	if roleARN == "" || tokenFile == "" {
+		return awsCredentials{}, errors.New("no AWS credentials found in the environment")
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.now().Add(awsCredentialsExpiryMargin).Before(a.credentials.expiration) {
+		return a.credentials, nil
+	}
+	token, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return awsCredentials{}, err
+	}
+	query := url.Values{}
+	query.Set("Action", "AssumeRoleWithWebIdentity")
+	query.Set("Version", "2011-06-15")
+	query.Set("RoleArn", roleARN)
+	query.Set("RoleSessionName", "eck-operator")
+	query.Set("WebIdentityToken", strings.TrimSpace(string(token)))
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, a.config.STSEndpoint, strings.NewReader(query.Encode()))
+	if err != nil {
+		return awsCredentials{}, err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	respBody, err := a.do(request)
+	if err != nil {
+		return awsCredentials{}, fmt.Errorf("while assuming role %s: %w", roleARN, err)
+	}
+	var resp struct {
+		Credentials struct {
+			AccessKeyID     string    `xml:"AccessKeyId"`
+			SecretAccessKey string    `xml:"SecretAccessKey"`
+			SessionToken    string    `xml:"SessionToken"`
+			Expiration      time.Time `xml:"Expiration"`
+		} `xml:"AssumeRoleWithWebIdentityResult>Credentials"`
+	}
+	if err := xml.Unmarshal(respBody, &resp); err != nil {
+		return awsCredentials{}, err
+	}
+	a.credentials = awsCredentials{
+		accessKeyID:     resp.Credentials.AccessKeyID,
+		secretAccessKey: resp.Credentials.SecretAccessKey,
+		sessionToken:    resp.Credentials.SessionToken,
+		expiration:      resp.Credentials.Expiration,
+	}
+	return a.credentials, nil
+}
+
+// do sends the given request and returns the body of the response, or an error if the request failed.
+func (a *AWSSecretsManager) do(request *http.Request) ([]byte, error) {
+	resp, err := a.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+// signAWSRequest signs the given request with the AWS Signature Version 4.
+func signAWSRequest(request *http.Request, body []byte, credentials awsCredentials, region, service string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	request.Header.Set("X-Amz-Date", amzDate)
+	if credentials.sessionToken != "" {
+		request.Header.Set("X-Amz-Security-Token", credentials.sessionToken)
+	}
+
+	headers := map[string]string{"host": request.URL.Host}
+	for name, values := range request.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := request.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		path,
+		request.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		hexSHA256(body),
+	}, "\n")
+
+	scope := strings.Join([]string{date, region, service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, hexSHA256([]byte(canonicalRequest))}, "\n")
+	key := []byte("AWS4" + credentials.secretAccessKey)
+	for _, part := range []string{date, region, service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	request.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		credentials.accessKeyID, scope, signedHeaders, signature))
+}
+
+func hexSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,98 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package secretbackend
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_signAWSRequest(t *testing.T) {
+	// get-vanilla example of the AWS Signature Version 4 test suite
+	request, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	require.NoError(t, err)
+	credentials := awsCredentials{accessKeyID: "AKIDEXAMPLE", secretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+	signAWSRequest(request, nil, credentials, "us-east-1", "service", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+	require.Equal(t, "20150830T123600Z", request.Header.Get("X-Amz-Date"))
+	require.Equal(t,
+		"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		request.Header.Get("Authorization"))
+}
+
+func TestAWSSecretsManager_Read(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("web-identity-token\n"), 0600))
+
+	var stsCalls int
+	sts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stsCalls++
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "AssumeRoleWithWebIdentity", r.PostForm.Get("Action"))
+		require.Equal(t, "arn:aws:iam::123456789012:role/eck", r.PostForm.Get("RoleArn"))
+		require.Equal(t, "web-identity-token", r.PostForm.Get("WebIdentityToken"))
+		_, _ = io.WriteString(w, `<AssumeRoleWithWebIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleWithWebIdentityResult>
+    <Credentials>
+      <AccessKeyId>ASIAEXAMPLE</AccessKeyId>
+      <SecretAccessKey>secret</SecretAccessKey>
+      <SessionToken>session-token</SessionToken>
+      <Expiration>2024-05-01T11:00:00Z</Expiration>
+    </Credentials>
+  </AssumeRoleWithWebIdentityResult>
+</AssumeRoleWithWebIdentityResponse>`)
+	}))
+	defer sts.Close()
+
+	secretsManager := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "secretsmanager.GetSecretValue", r.Header.Get("X-Amz-Target"))
+		require.Equal(t, "session-token", r.Header.Get("X-Amz-Security-Token"))
+		require.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=ASIAEXAMPLE/20240501/eu-west-1/secretsmanager/aws4_request, "))
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		switch body["SecretId"] {
+		case "es/snapshots":
+			_, _ = io.WriteString(w, `{"Name":"es/snapshots","SecretString":"{\"s3.client.default.access_key\":\"key\",\"s3.client.default.secret_key\":\"secret\"}"}`)
+		case "plain":
+			_, _ = io.WriteString(w, `{"Name":"plain","SecretString":"value"}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(w, `{"__type":"ResourceNotFoundException","message":"Secrets Manager can't find the specified secret."}`)
+		}
+	}))
+	defer secretsManager.Close()
+
+	backend, err := NewAWSSecretsManager(AWSSecretsManagerConfig{Region: "eu-west-1", Endpoint: secretsManager.URL, STSEndpoint: sts.URL})
+	require.NoError(t, err)
+	backend.getenv = func(name string) string {
+		return map[string]string{"AWS_ROLE_ARN": "arn:aws:iam::123456789012:role/eck", "AWS_WEB_IDENTITY_TOKEN_FILE": tokenFile}[name]
+	}
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	backend.now = func() time.Time { return now }
+
+	values, err := backend.Read(context.Background(), "es/snapshots")
+	require.NoError(t, err)
+	require.Equal(t, map[string][]byte{"s3.client.default.access_key": []byte("key"), "s3.client.default.secret_key": []byte("secret")}, values)
+
+	_, err = backend.Read(context.Background(), "plain")
+	require.EqualError(t, err, "secret plain is not a JSON object of key-value pairs")
+	_, err = backend.Read(context.Background(), "missing")
+	require.ErrorContains(t, err, "ResourceNotFoundException")
+	// credentials are reused until they are about to expire
+	require.Equal(t, 1, stsCalls)
+	now = now.Add(56 * time.Minute)
+	_, err = backend.Read(context.Background(), "es/snapshots")
+	require.NoError(t, err)
+	require.Equal(t, 2, stsCalls)
+}
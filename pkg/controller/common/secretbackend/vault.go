@@ -0,0 +1,133 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package secretbackend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
+)
+
+const (
+	// VaultName is the name of the HashiCorp Vault secret backend, referenced in the secure settings of the resources.
+	VaultName = "vault"
+	// DefaultVaultAuthMountPath is the default path of the Kubernetes auth method in Vault.
+	DefaultVaultAuthMountPath = "kubernetes"
+
+	serviceAccountTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token" //nolint:gosec
+)
+
+// VaultConfig is the configuration of the HashiCorp Vault secret backend. The standard VAULT_* environment variables,
+// such as VAULT_CACERT or VAULT_NAMESPACE, are also taken into account.
+type VaultConfig struct {
+	// Address is the address of the Vault server, defaults to the VAULT_ADDR environment variable.
+	Address string
+	// AuthMountPath is the path of the Kubernetes auth method the operator logs in with.
+	AuthMountPath string
+	// AuthRole is the role of the Kubernetes auth method the operator logs in with. The token set in the VAULT_TOKEN
+	// environment variable is used instead if empty.
+	AuthRole string
+}
+
+// Vault reads secrets from HashiCorp Vault, from both the KV version 1 and version 2 secrets engines.
+type Vault struct {
+	config    VaultConfig
+	client    *api.Client
+	tokenFile string
+
+	lock          sync.Mutex
+	tokenDeadline time.Time
+}
+
+var _ keystore.SecretBackend = &Vault{}
+
+// NewVault returns a Vault secret backend.
+func NewVault(config VaultConfig) (*Vault, error) {
+	client, err := api.NewClient(api.DefaultConfig())
+	if err != nil {
+		return nil, err
+	}
+	if config.Address != "" {
+		if err := client.SetAddress(config.Address); err != nil {
+			return nil, err
+		}
+	}
+	if config.AuthMountPath == "" {
+		config.AuthMountPath = DefaultVaultAuthMountPath
+	}
+	if config.AuthRole == "" && client.Token() == "" {
+		return nil, errors.New("either a Vault auth role or the VAULT_TOKEN environment variable must be set")
+	}
+	return &Vault{config: config, client: client, tokenFile: serviceAccountTokenFile}, nil
+}
+
+// Read returns the key-value pairs of the secret at the given path, which includes the mount path of the secrets
+// engine, for example secret/data/elasticsearch for a KV version 2 secrets engine mounted at secret.
+func (v *Vault) Read(ctx context.Context, path string) (map[string][]byte, error) {
+	if err := v.login(ctx); err != nil {
+		return nil, err
+	}
+	secret, err := v.client.Logical().ReadWithContext(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, fmt.Errorf("no secret found at %s", path)
+	}
+	data := secret.Data
+	// secrets of the KV version 2 secrets engine are nested in data, along with their metadata
+	if nested, ok := data["data"].(map[string]interface{}); ok && data["metadata"] != nil {
+		data = nested
+	}
+	values := make(map[string][]byte, len(data))
+	for k, v := range data {
+		value, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("value of %s at %s is not a string", k, path)
+		}
+		values[k] = []byte(value)
+	}
+	return values, nil
+}
+
+// login logs in with the Kubernetes auth method, using the ServiceAccount token of the operator, if an auth role is
+// configured and the current Vault token is about to expire.
+func (v *Vault) login(ctx context.Context) error {
+	if v.config.AuthRole == "" {
+		return nil
+	}
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	if time.Now().Before(v.tokenDeadline) {
+		return nil
+	}
+	jwt, err := os.ReadFile(v.tokenFile)
+	if err != nil {
+		return err
+	}
+	loginPath := fmt.Sprintf("auth/%s/login", strings.Trim(v.config.AuthMountPath, "/"))
+	resp, err := v.client.Logical().WriteWithContext(ctx, loginPath, map[string]interface{}{
+		"role": v.config.AuthRole,
+		"jwt":  strings.TrimSpace(string(jwt)),
+	})
+	if err != nil {
+		return fmt.Errorf("while logging into Vault: %w", err)
+	}
+	if resp == nil || resp.Auth == nil {
+		return errors.New("while logging into Vault: no auth info in response")
+	}
+	v.client.SetToken(resp.Auth.ClientToken)
+	// log in again once half of the lease duration of the token is elapsed
+	v.tokenDeadline = time.Now().Add(time.Duration(resp.Auth.LeaseDuration) * time.Second / 2)
+	return nil
+}
@@ -0,0 +1,76 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package secretbackend
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVault_Read(t *testing.T) {
+	t.Setenv("VAULT_TOKEN", "")
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("sa-token\n"), 0600))
+
+	var logins int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/auth/k8s/login" {
+			logins++
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			require.Equal(t, map[string]string{"role": "eck", "jwt": "sa-token"}, body)
+			_, _ = io.WriteString(w, `{"auth":{"client_token":"vault-token","lease_duration":3600}}`)
+			return
+		}
+		require.Equal(t, "vault-token", r.Header.Get("X-Vault-Token"))
+		switch r.URL.Path {
+		case "/v1/secret/data/es":
+			_, _ = io.WriteString(w, `{"data":{"data":{"s3.client.default.access_key":"key"},"metadata":{"version":2}}}`)
+		case "/v1/kv/es":
+			_, _ = io.WriteString(w, `{"data":{"s3.client.default.secret_key":"secret"}}`)
+		case "/v1/kv/invalid":
+			_, _ = io.WriteString(w, `{"data":{"count":1}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"errors":[]}`)
+		}
+	}))
+	defer server.Close()
+
+	backend, err := NewVault(VaultConfig{Address: server.URL, AuthMountPath: "/k8s/", AuthRole: "eck"})
+	require.NoError(t, err)
+	backend.tokenFile = tokenFile
+
+	values, err := backend.Read(context.Background(), "secret/data/es")
+	require.NoError(t, err)
+	require.Equal(t, map[string][]byte{"s3.client.default.access_key": []byte("key")}, values)
+	values, err = backend.Read(context.Background(), "kv/es")
+	require.NoError(t, err)
+	require.Equal(t, map[string][]byte{"s3.client.default.secret_key": []byte("secret")}, values)
+	_, err = backend.Read(context.Background(), "kv/invalid")
+	require.EqualError(t, err, "value of count at kv/invalid is not a string")
+	_, err = backend.Read(context.Background(), "kv/missing")
+	require.EqualError(t, err, "no secret found at kv/missing")
+	require.Equal(t, 1, logins)
+}
+
+func TestNewVault(t *testing.T) {
+	t.Setenv("VAULT_TOKEN", "")
+	_, err := NewVault(VaultConfig{Address: "https://vault:8200"})
+	require.EqualError(t, err, "either a Vault auth role or the VAULT_TOKEN environment variable must be set")
+
+	t.Setenv("VAULT_TOKEN", "token")
+	backend, err := NewVault(VaultConfig{Address: "https://vault:8200"})
+	require.NoError(t, err)
+	require.Equal(t, DefaultVaultAuthMountPath, backend.config.AuthMountPath)
+}
@@ -0,0 +1,136 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+// Package servicemesh reconciles the service mesh resources required by the resources whose HTTP layer is integrated
+// with a service mesh.
+package servicemesh
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
+)
+
+const (
+	// PeerAuthenticationAnnotation is set on the HTTP Service of the resources for which a PeerAuthentication has been
+	// created, so that the Istio API is only accessed to delete it if it exists.
+	PeerAuthenticationAnnotation = "common.k8s.elastic.co/istio-peer-authentication"
+
+	permissiveMode = "PERMISSIVE"
+)
+
+// PeerAuthenticationGVK is the GroupVersionKind of the Istio PeerAuthentication resource. The Istio API is not vendored,
+// PeerAuthentications are handled as unstructured objects.
+var PeerAuthenticationGVK = schema.GroupVersionKind{Group: "security.istio.io", Version: "v1", Kind: "PeerAuthentication"}
+
+func newPeerAuthentication(namespace, name string) *unstructured.Unstructured {
+	peerAuthentication := &unstructured.Unstructured{}
+	peerAuthentication.SetGroupVersionKind(PeerAuthenticationGVK)
+	peerAuthentication.SetNamespace(namespace)
+	peerAuthentication.SetName(name)
+	return peerAuthentication
+}
+
+// ReconcilePeerAuthentication ensures the Istio PeerAuthentication, named after the given HTTP Service, which sets the
+// mutual TLS mode of the HTTP port of the selected Pods to PERMISSIVE exists if the HTTP layer is integrated with Istio,
+// or that it does not exist otherwise.
+// The operator sends plain HTTP requests, which are rejected by the Istio sidecars if the STRICT mode is enforced
+// unless the operator is part of the mesh. The PERMISSIVE mode accepts them, while the clients in the mesh keep
+// connecting with mutual TLS. The mode of the other ports is not changed.
+func ReconcilePeerAuthentication(
+	ctx context.Context,
+	c k8s.Client,
+	owner client.Object,
+	http commonv1.HTTPConfig,
+	svc corev1.Service,
+	selector map[string]string,
+	port int,
+) error {
+	if http.ServiceMesh != commonv1.IstioServiceMesh {
+		return deletePeerAuthentication(ctx, c, svc)
+	}
+
+	expected := newPeerAuthentication(svc.Namespace, svc.Name)
+	expected.SetLabels(selector)
+	expected.Object["spec"] = map[string]interface{}{
+		"selector": map[string]interface{}{
+			"matchLabels": toInterfaceMap(selector),
+		},
+		"portLevelMtls": map[string]interface{}{
+			strconv.Itoa(port): map[string]interface{}{"mode": permissiveMode},
+		},
+	}
+	reconciled := newPeerAuthentication(svc.Namespace, svc.Name)
+	err := reconciler.ReconcileResource(reconciler.Params{
+		Context:    ctx,
+		Client:     c,
+		Owner:      owner,
+		Expected:   expected,
+		Reconciled: reconciled,
+		NeedsUpdate: func() bool {
+			return !maps.IsSubset(selector, reconciled.GetLabels()) ||
+				!reflect.DeepEqual(expected.Object["spec"], reconciled.Object["spec"])
+		},
+		UpdateReconciled: func() {
+			reconciled.SetLabels(maps.Merge(reconciled.GetLabels(), selector))
+			reconciled.Object["spec"] = expected.Object["spec"]
+		},
+	})
+	if meta.IsNoMatchError(err) {
+		return fmt.Errorf("cannot create the PeerAuthentication of %s/%s, the Istio CRDs are not installed: %w", svc.Namespace, svc.Name, err)
+	}
+	if err != nil {
+		return err
+	}
+	return setServiceAnnotation(ctx, c, svc, "true")
+}
+
+// deletePeerAuthentication deletes the PeerAuthentication named after the given HTTP Service, if the Service records
+// its creation.
+func deletePeerAuthentication(ctx context.Context, c k8s.Client, svc corev1.Service) error {
+	if _, exists := svc.Annotations[PeerAuthenticationAnnotation]; !exists {
+		return nil
+	}
+	err := c.Delete(ctx, newPeerAuthentication(svc.Namespace, svc.Name))
+	if err != nil && !apierrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return err
+	}
+	return setServiceAnnotation(ctx, c, svc, "")
+}
+
+// setServiceAnnotation sets the PeerAuthentication annotation of the given Service to the given value, or removes it
+// if the value is empty.
+func setServiceAnnotation(ctx context.Context, c k8s.Client, svc corev1.Service, value string) error {
+	if current, exists := svc.Annotations[PeerAuthenticationAnnotation]; current == value && exists == (value != "") {
+		return nil
+	}
+	annotated := svc.DeepCopy()
+	if value == "" {
+		delete(annotated.Annotations, PeerAuthenticationAnnotation)
+	} else {
+		annotated.Annotations = maps.Merge(annotated.Annotations, map[string]string{PeerAuthenticationAnnotation: value})
+	}
+	return c.Patch(ctx, annotated, client.MergeFrom(&svc))
+}
+
+func toInterfaceMap(m map[string]string) map[string]interface{} {
+	result := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		result[k] = v
+	}
+	return result
+}
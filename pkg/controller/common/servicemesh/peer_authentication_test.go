@@ -0,0 +1,71 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package servicemesh
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+var (
+	es       = esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es", UID: "uid"}}
+	svcKey   = types.NamespacedName{Namespace: "ns", Name: "es-es-http"}
+	selector = map[string]string{"elasticsearch.k8s.elastic.co/cluster-name": "es"}
+)
+
+func getService(t *testing.T, c k8s.Client) corev1.Service {
+	t.Helper()
+	var svc corev1.Service
+	require.NoError(t, c.Get(context.Background(), svcKey, &svc))
+	return svc
+}
+
+func TestReconcilePeerAuthentication(t *testing.T) {
+	ctx := context.Background()
+	c := k8s.NewFakeClient(&es, &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: svcKey.Namespace, Name: svcKey.Name}})
+	istio := commonv1.HTTPConfig{ServiceMesh: commonv1.IstioServiceMesh}
+
+	// STRICT mutual TLS: the HTTP port accepts the plain HTTP requests of the operator
+	require.NoError(t, ReconcilePeerAuthentication(ctx, c, &es, istio, getService(t, c), selector, 9200))
+	peerAuthentication := newPeerAuthentication(svcKey.Namespace, svcKey.Name)
+	require.NoError(t, c.Get(ctx, svcKey, peerAuthentication))
+	require.True(t, metav1.IsControlledBy(peerAuthentication, &es))
+	require.Equal(t, map[string]any{
+		"selector": map[string]any{
+			"matchLabels": map[string]any{"elasticsearch.k8s.elastic.co/cluster-name": "es"},
+		},
+		// only the HTTP port is relaxed, the mode of the other ports is inherited from the mesh or the namespace
+		"portLevelMtls": map[string]any{
+			"9200": map[string]any{"mode": "PERMISSIVE"},
+		},
+	}, peerAuthentication.Object["spec"])
+	require.Equal(t, "true", getService(t, c).Annotations[PeerAuthenticationAnnotation])
+
+	// reconciling again is a no-op
+	require.NoError(t, ReconcilePeerAuthentication(ctx, c, &es, istio, getService(t, c), selector, 9200))
+
+	// the PeerAuthentication is deleted once the HTTP layer is not integrated with Istio anymore
+	require.NoError(t, ReconcilePeerAuthentication(ctx, c, &es, commonv1.HTTPConfig{}, getService(t, c), selector, 9200))
+	require.True(t, apierrors.IsNotFound(c.Get(ctx, svcKey, newPeerAuthentication(svcKey.Namespace, svcKey.Name))))
+	require.NotContains(t, getService(t, c).Annotations, PeerAuthenticationAnnotation)
+}
+
+func TestReconcilePeerAuthentication_NoServiceMesh(t *testing.T) {
+	// the Istio API is not accessed if no PeerAuthentication has been created
+	c := k8s.NewFailingClient(errors.New("unexpected request"))
+	svc := corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: svcKey.Namespace, Name: svcKey.Name}}
+	require.NoError(t, ReconcilePeerAuthentication(context.Background(), c, &es, commonv1.HTTPConfig{}, svc, selector, 9200))
+}
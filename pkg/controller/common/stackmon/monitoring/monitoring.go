@@ -15,6 +15,7 @@ type HasMonitoring interface {
 	client.Object
 	commonv1.HasIdentityLabels
 	GetMonitoringMetricsRefs() []commonv1.ObjectSelector
+	GetMonitoringMetricsDestinations() []commonv1.MetricsDestination
 	GetMonitoringLogsRefs() []commonv1.ObjectSelector
 	MonitoringAssociation(ref commonv1.ObjectSelector) commonv1.Association
 }
@@ -80,3 +81,15 @@ func GetLogsAssociation(resource HasMonitoring) []commonv1.Association {
 	}
 	return associations
 }
+
+// GetMetricSets returns the metric sets to send to the monitoring Elasticsearch cluster referenced by ref, or nil if
+// all the metric sets must be sent.
+func GetMetricSets(resource HasMonitoring, ref commonv1.ObjectSelector) []string {
+	ref = ref.WithDefaultNamespace(resource.GetNamespace())
+	for _, destination := range resource.GetMonitoringMetricsDestinations() {
+		if destination.ElasticsearchRef.WithDefaultNamespace(resource.GetNamespace()) == ref {
+			return destination.MetricSets
+		}
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
@@ -208,3 +209,22 @@ func TestGetLogsAssociation(t *testing.T) {
 	assert.Equal(t, 0, len(GetLogsAssociation(&sampleEs)))
 	assert.Equal(t, 0, len(GetLogsAssociation(&sampleMonitoredEs)))
 }
+
+func TestGetMetricSets(t *testing.T) {
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: "default"},
+		Spec: esv1.ElasticsearchSpec{
+			Monitoring: commonv1.Monitoring{
+				Metrics: commonv1.MetricsMonitoring{
+					ElasticsearchRefs: []commonv1.ObjectSelector{{Name: "central"}, {Name: "regional", Namespace: "eu"}},
+					Destinations: []commonv1.MetricsDestination{
+						{ElasticsearchRef: commonv1.ObjectSelector{Name: "regional", Namespace: "eu"}, MetricSets: []string{"cluster_stats"}},
+					},
+				},
+			},
+		},
+	}
+	assert.Nil(t, GetMetricSets(&es, commonv1.ObjectSelector{Name: "central"}))
+	assert.Nil(t, GetMetricSets(&es, commonv1.ObjectSelector{Name: "regional"}))
+	assert.Equal(t, []string{"cluster_stats"}, GetMetricSets(&es, commonv1.ObjectSelector{Name: "regional", Namespace: "eu"}))
+}
@@ -33,10 +33,28 @@ func NewMetricBeatSidecar(
 	caVolume volume.VolumeLike,
 	baseConfig string,
 ) (BeatSidecar, error) {
+	return NewNamedMetricBeatSidecar(ctx, client, "metricbeat", resource, monitoring.GetMetricsAssociation(resource), imageVersion, caVolume, baseConfig)
+}
+
+// NewNamedMetricBeatSidecar builds a Metricbeat sidecar container named beatName sending monitoring data to the
+// Elasticsearch cluster of the given associations. It allows to run several Metricbeat sidecars in the same Pod.
+func NewNamedMetricBeatSidecar(
+	ctx context.Context,
+	client k8s.Client,
+	beatName string,
+	resource monitoring.HasMonitoring,
+	associations []commonv1.Association,
+	imageVersion semver.Version,
+	caVolume volume.VolumeLike,
+	baseConfig string,
+) (BeatSidecar, error) {
+	if err := container.CheckImageCatalog("", container.MetricbeatImage, imageVersion); err != nil {
+		return BeatSidecar{}, err
+	}
 	image := container.ImageRepository(container.MetricbeatImage, imageVersion)
 	// EmptyDir volume so that MetricBeat does not write in the container image, which allows ReadOnlyRootFilesystem: true
-	emptyDir := volume.NewEmptyDirVolume("metricbeat-data", "/usr/share/metricbeat/data")
-	return NewBeatSidecar(ctx, client, "metricbeat", image, resource, monitoring.GetMetricsAssociation(resource), baseConfig, caVolume, emptyDir)
+	emptyDir := volume.NewEmptyDirVolume(fmt.Sprintf("%s-data", beatName), "/usr/share/metricbeat/data")
+	return NewBeatSidecar(ctx, client, beatName, image, resource, associations, baseConfig, caVolume, emptyDir)
 }
 
 func NewFileBeatSidecar(ctx context.Context, client k8s.Client, resource monitoring.HasMonitoring, imageVersion string, baseConfig string, additionalVolume volume.VolumeLike) (BeatSidecar, error) {
@@ -44,6 +62,9 @@ func NewFileBeatSidecar(ctx context.Context, client k8s.Client, resource monitor
 	if err != nil {
 		return BeatSidecar{}, err // error unlikely and should have been caught during validation
 	}
+	if err := container.CheckImageCatalog("", container.FilebeatImage, v); err != nil {
+		return BeatSidecar{}, err
+	}
 	image := container.ImageRepository(container.FilebeatImage, v)
 	// EmptyDir volume so that FileBeat does not write in the container image, which allows ReadOnlyRootFilesystem: true
 	emptyDir := volume.NewEmptyDirVolume("filebeat-data", "/usr/share/filebeat/data")
@@ -6,10 +6,12 @@ package validations
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/blang/semver/v4"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/stackmon/monitoring"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 )
@@ -18,11 +20,17 @@ const (
 	UnsupportedVersionMsg       = "Unsupported version for Stack Monitoring. Required >= %s."
 	InvalidElasticsearchRefsMsg = "Only one Elasticsearch reference is supported for %s Stack Monitoring"
 
+	UnsupportedMetricsDestinationsMsg = "Metrics destinations are only supported for Elasticsearch Stack Monitoring"
+	UnknownMetricsDestinationMsg      = "Metrics destination must reference one of the metrics Elasticsearch references"
+	EmptyMetricSetsMsg                = "Metrics destination must define at least one metric set"
+
 	InvalidKibanaElasticsearchRefForStackMonitoringMsg = "Kibana must be associated to an Elasticsearch cluster through elasticsearchRef in order to enable monitoring metrics features"
 	InvalidBeatsElasticsearchRefForStackMonitoringMsg  = "Beats must be associated to an Elasticsearch cluster through elasticsearchRef in order to enable monitoring metrics features"
 )
 
 var (
+	metricsDestinationsPath = field.NewPath("spec").Child("monitoring").Child("metrics").Child("destinations")
+
 	// MinStackVersion is the minimum Stack version to enable Stack Monitoring on an Elastic Stack application..
 	// This requirement comes from the fact that we configure Elasticsearch to write logs to disk for Filebeat
 	// via the env var ES_LOG_STYLE available from this version.
@@ -32,26 +40,74 @@ var (
 // Validate validates that the resource version is supported for Stack Monitoring and that there is exactly one
 // Elasticsearch reference defined to send monitoring data when Stack Monitoring is defined
 func Validate(resource monitoring.HasMonitoring, version string, minVersion version.Version) field.ErrorList {
-	var errs field.ErrorList
-	if monitoring.IsDefined(resource) {
-		err := IsSupportedVersion(version, minVersion)
-		if err != nil {
-			finalMinStackVersion, _ := semver.FinalizeVersion(minVersion.String()) // discards prerelease suffix
-			errs = append(errs, field.Invalid(field.NewPath("spec").Child("version"), version,
-				fmt.Sprintf(UnsupportedVersionMsg, finalMinStackVersion)))
-		}
-	}
+	errs := validateVersion(resource, version, minVersion)
 	refs := resource.GetMonitoringMetricsRefs()
 	if monitoring.AreEsRefsDefined(refs) && len(refs) != 1 {
 		errs = append(errs, field.Invalid(field.NewPath("spec").Child("monitoring").Child("metrics").Child("elasticsearchRefs"),
 			refs, fmt.Sprintf(InvalidElasticsearchRefsMsg, "Metrics")))
 	}
-	refs = resource.GetMonitoringLogsRefs()
+	if len(resource.GetMonitoringMetricsDestinations()) > 0 {
+		errs = append(errs, field.Forbidden(metricsDestinationsPath, UnsupportedMetricsDestinationsMsg))
+	}
+	return append(errs, validateLogsRefs(resource)...)
+}
+
+// ValidateWithMetricsDestinations validates that the resource version is supported for Stack Monitoring, that there
+// is exactly one Elasticsearch reference defined to send logs, and that each metrics destination references a distinct
+// Elasticsearch cluster of the metrics Elasticsearch references and only uses supported metric sets.
+func ValidateWithMetricsDestinations(resource monitoring.HasMonitoring, version string, minVersion version.Version, supportedMetricSets []string) field.ErrorList {
+	errs := validateVersion(resource, version, minVersion)
+	refsPath := field.NewPath("spec").Child("monitoring").Child("metrics").Child("elasticsearchRefs")
+	refs := make(map[commonv1.ObjectSelector]bool)
+	for i, ref := range resource.GetMonitoringMetricsRefs() {
+		ref = ref.WithDefaultNamespace(resource.GetNamespace())
+		if refs[ref] {
+			errs = append(errs, field.Duplicate(refsPath.Index(i), ref))
+		}
+		refs[ref] = true
+	}
+	destinations := make(map[commonv1.ObjectSelector]bool)
+	for i, destination := range resource.GetMonitoringMetricsDestinations() {
+		path := metricsDestinationsPath.Index(i)
+		ref := destination.ElasticsearchRef.WithDefaultNamespace(resource.GetNamespace())
+		switch {
+		case !refs[ref]:
+			errs = append(errs, field.Invalid(path.Child("elasticsearchRef"), destination.ElasticsearchRef, UnknownMetricsDestinationMsg))
+		case destinations[ref]:
+			errs = append(errs, field.Duplicate(path.Child("elasticsearchRef"), destination.ElasticsearchRef))
+		}
+		destinations[ref] = true
+		if len(destination.MetricSets) == 0 {
+			errs = append(errs, field.Required(path.Child("metricSets"), EmptyMetricSetsMsg))
+		}
+		for j, metricSet := range destination.MetricSets {
+			if !slices.Contains(supportedMetricSets, metricSet) {
+				errs = append(errs, field.NotSupported(path.Child("metricSets").Index(j), metricSet, supportedMetricSets))
+			}
+		}
+	}
+	return append(errs, validateLogsRefs(resource)...)
+}
+
+func validateVersion(resource monitoring.HasMonitoring, version string, minVersion version.Version) field.ErrorList {
+	if !monitoring.IsDefined(resource) {
+		return nil
+	}
+	if err := IsSupportedVersion(version, minVersion); err != nil {
+		finalMinStackVersion, _ := semver.FinalizeVersion(minVersion.String()) // discards prerelease suffix
+		return field.ErrorList{field.Invalid(field.NewPath("spec").Child("version"), version,
+			fmt.Sprintf(UnsupportedVersionMsg, finalMinStackVersion))}
+	}
+	return nil
+}
+
+func validateLogsRefs(resource monitoring.HasMonitoring) field.ErrorList {
+	refs := resource.GetMonitoringLogsRefs()
 	if monitoring.AreEsRefsDefined(refs) && len(refs) != 1 {
-		errs = append(errs, field.Invalid(field.NewPath("spec").Child("monitoring").Child("logs").Child("elasticsearchRefs"),
-			refs, fmt.Sprintf(InvalidElasticsearchRefsMsg, "Logs")))
+		return field.ErrorList{field.Invalid(field.NewPath("spec").Child("monitoring").Child("logs").Child("elasticsearchRefs"),
+			refs, fmt.Sprintf(InvalidElasticsearchRefsMsg, "Logs"))}
 	}
-	return errs
+	return nil
 }
 
 // IsSupportedVersion returns error if the resource version is not supported for Stack Monitoring
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
@@ -76,6 +77,23 @@ func TestValidate(t *testing.T) {
 			},
 			isErr: true,
 		},
+		{
+			name: "with metrics destinations",
+			es: esv1.Elasticsearch{
+				Spec: esv1.ElasticsearchSpec{
+					Version: "7.14.0",
+					Monitoring: commonv1.Monitoring{
+						Metrics: commonv1.MetricsMonitoring{
+							ElasticsearchRefs: []commonv1.ObjectSelector{{Name: "m1", Namespace: "b"}},
+							Destinations: []commonv1.MetricsDestination{
+								{ElasticsearchRef: commonv1.ObjectSelector{Name: "m1", Namespace: "b"}, MetricSets: []string{"node_stats"}},
+							},
+						},
+					},
+				},
+			},
+			isErr: true,
+		},
 		{
 			name: "with not only one elasticsearch ref for logs",
 			es: esv1.Elasticsearch{
@@ -106,3 +124,75 @@ func TestValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateWithMetricsDestinations(t *testing.T) {
+	supported := []string{"cluster_stats", "node_stats", "shard"}
+	mkEs := func(refs []commonv1.ObjectSelector, destinations ...commonv1.MetricsDestination) esv1.Elasticsearch {
+		return esv1.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: "a"},
+			Spec: esv1.ElasticsearchSpec{
+				Version: "8.15.0",
+				Monitoring: commonv1.Monitoring{
+					Metrics: commonv1.MetricsMonitoring{ElasticsearchRefs: refs, Destinations: destinations},
+				},
+			},
+		}
+	}
+	central := commonv1.ObjectSelector{Name: "central", Namespace: "b"}
+	regional := commonv1.ObjectSelector{Name: "regional"}
+
+	tests := []struct {
+		name       string
+		es         esv1.Elasticsearch
+		wantFields []string
+	}{
+		{
+			name: "single elasticsearch ref",
+			es:   mkEs([]commonv1.ObjectSelector{central}),
+		},
+		{
+			name: "multiple elasticsearch refs with destinations",
+			es: mkEs([]commonv1.ObjectSelector{central, regional},
+				commonv1.MetricsDestination{ElasticsearchRef: commonv1.ObjectSelector{Name: "regional", Namespace: "a"}, MetricSets: []string{"cluster_stats", "node_stats"}},
+			),
+		},
+		{
+			name:       "duplicate elasticsearch refs",
+			es:         mkEs([]commonv1.ObjectSelector{regional, {Name: "regional", Namespace: "a"}}),
+			wantFields: []string{"spec.monitoring.metrics.elasticsearchRefs[1]"},
+		},
+		{
+			name: "destination not in elasticsearch refs",
+			es: mkEs([]commonv1.ObjectSelector{central},
+				commonv1.MetricsDestination{ElasticsearchRef: regional, MetricSets: []string{"shard"}},
+			),
+			wantFields: []string{"spec.monitoring.metrics.destinations[0].elasticsearchRef"},
+		},
+		{
+			name: "duplicate destinations",
+			es: mkEs([]commonv1.ObjectSelector{central},
+				commonv1.MetricsDestination{ElasticsearchRef: central, MetricSets: []string{"shard"}},
+				commonv1.MetricsDestination{ElasticsearchRef: central, MetricSets: []string{"node_stats"}},
+			),
+			wantFields: []string{"spec.monitoring.metrics.destinations[1].elasticsearchRef"},
+		},
+		{
+			name: "empty and unsupported metric sets",
+			es: mkEs([]commonv1.ObjectSelector{central, regional},
+				commonv1.MetricsDestination{ElasticsearchRef: central},
+				commonv1.MetricsDestination{ElasticsearchRef: regional, MetricSets: []string{"shard", "unknown"}},
+			),
+			wantFields: []string{"spec.monitoring.metrics.destinations[0].metricSets", "spec.monitoring.metrics.destinations[1].metricSets[1]"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateWithMetricsDestinations(&tc.es, tc.es.Spec.Version, MinStackVersion, supported)
+			fields := make([]string, 0, len(errs))
+			for _, err := range errs {
+				fields = append(fields, err.Field)
+			}
+			require.ElementsMatch(t, tc.wantFields, fields)
+		})
+	}
+}
@@ -22,6 +22,7 @@ type TestSset struct {
 	Version         string
 	Replicas        int32
 	Master          bool
+	VotingOnly      bool
 	Data            bool
 	Ingest          bool
 	Status          appsv1.StatefulSetStatus
@@ -37,6 +38,7 @@ func (t TestSset) Pods() []client.Object {
 			Name:            podName,
 			StatefulSetName: t.Name,
 			Master:          t.Master,
+			VotingOnly:      t.VotingOnly,
 			Data:            t.Data,
 			Ingest:          t.Ingest,
 			Version:         t.Version,
@@ -52,6 +54,7 @@ func (t TestSset) Build() appsv1.StatefulSet {
 		label.ClusterNameLabelName: t.ClusterName,
 	}
 	label.NodeTypesMasterLabelName.Set(t.Master, labels)
+	label.NodeTypesVotingOnlyLabelName.Set(t.VotingOnly, labels)
 	label.NodeTypesDataLabelName.Set(t.Data, labels)
 	label.NodeTypesIngestLabelName.Set(t.Ingest, labels)
 	statefulSet := appsv1.StatefulSet{
@@ -93,6 +96,7 @@ type TestPod struct {
 	Version         string
 	Revision        string
 	Master          bool
+	VotingOnly      bool
 	Data            bool
 	Ingest          bool
 	Ready           bool
@@ -109,6 +113,7 @@ func (t TestPod) Build() corev1.Pod {
 		appsv1.StatefulSetRevisionLabel: t.Revision,
 	}
 	label.NodeTypesMasterLabelName.Set(t.Master, labels)
+	label.NodeTypesVotingOnlyLabelName.Set(t.VotingOnly, labels)
 	label.NodeTypesDataLabelName.Set(t.Data, labels)
 	label.NodeTypesIngestLabelName.Set(t.Ingest, labels)
 
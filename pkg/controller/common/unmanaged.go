@@ -8,15 +8,20 @@ import (
 	"context"
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/annotation"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
 )
 
 const (
 	// ManagedAnnotation annotation
-	LegacyPauseAnnoation = "common.k8s.elastic.co/pause"
-	ManagedAnnotation    = "eck.k8s.elastic.co/managed"
+	LegacyPauseAnnoation = annotation.LegacyPauseAnnotation
+	ManagedAnnotation    = annotation.ManagedAnnotation
 )
 
 // IsUnmanaged checks if a given resource is currently unmanaged.
@@ -32,3 +37,28 @@ func IsUnmanaged(ctx context.Context, object metav1.Object) bool {
 	}
 	return exists && paused == "true"
 }
+
+// ReconcilePausedCondition sets the Paused condition of the given resource according to its managed state, and updates
+// its status if the condition changed. Resources which have never been paused are left untouched.
+// conditions must point to the conditions in the status of obj.
+func ReconcilePausedCondition(ctx context.Context, c k8s.Client, obj client.Object, conditions *commonv1alpha1.Conditions, paused bool) error {
+	expected := commonv1alpha1.Condition{
+		Type:               commonv1alpha1.PausedCondition,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+	}
+	if paused {
+		expected.Status = corev1.ConditionTrue
+		expected.Message = fmt.Sprintf("Reconciliation is suspended by the %s annotation", ManagedAnnotation)
+	}
+	index := conditions.Index(commonv1alpha1.PausedCondition)
+	switch {
+	case index < 0 && !paused:
+		return nil
+	case index >= 0 && (*conditions)[index].Status == expected.Status:
+		return nil
+	}
+	*conditions = conditions.MergeWith(expected)
+	ulog.FromContext(ctx).Info("Updating paused condition", "namespace", obj.GetNamespace(), "name", obj.GetName(), "paused", paused)
+	return c.Status().Update(ctx, obj)
+}
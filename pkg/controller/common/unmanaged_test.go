@@ -9,8 +9,13 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 )
 
 type testcase struct {
@@ -90,3 +95,39 @@ func TestUnmanagedCondition(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcilePausedCondition(t *testing.T) {
+	kb := kbv1.Kibana{ObjectMeta: v1.ObjectMeta{Namespace: "ns", Name: "kb"}}
+	c := k8s.NewFakeClient(&kb)
+	ctx := context.Background()
+	pausedCondition := func() *commonv1alpha1.Condition {
+		var actual kbv1.Kibana
+		require.NoError(t, c.Get(ctx, k8s.ExtractNamespacedName(&kb), &actual))
+		index := actual.Status.Conditions.Index(commonv1alpha1.PausedCondition)
+		if index < 0 {
+			return nil
+		}
+		return &actual.Status.Conditions[index]
+	}
+
+	// resources which have never been paused are left untouched
+	require.NoError(t, ReconcilePausedCondition(ctx, c, &kb, &kb.Status.Conditions, false))
+	require.Nil(t, pausedCondition())
+
+	require.NoError(t, ReconcilePausedCondition(ctx, c, &kb, &kb.Status.Conditions, true))
+	condition := pausedCondition()
+	require.NotNil(t, condition)
+	require.Equal(t, corev1.ConditionTrue, condition.Status)
+	require.Contains(t, condition.Message, ManagedAnnotation)
+
+	// reconciling again does not change the condition
+	transitionTime := condition.LastTransitionTime
+	require.NoError(t, ReconcilePausedCondition(ctx, c, &kb, &kb.Status.Conditions, true))
+	require.True(t, transitionTime.Equal(&pausedCondition().LastTransitionTime))
+
+	require.NoError(t, ReconcilePausedCondition(ctx, c, &kb, &kb.Status.Conditions, false))
+	condition = pausedCondition()
+	require.NotNil(t, condition)
+	require.Equal(t, corev1.ConditionFalse, condition.Status)
+	require.Empty(t, condition.Message)
+}
@@ -0,0 +1,68 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+// Package upgradegate implements the pause points of the version upgrades through which an external analysis tool, such
+// as Argo Rollouts or Flagger, approves each step of the upgrade based on its own metrics.
+package upgradegate
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// Enabled returns true if the version upgrades of the given resource are paused before each restart of its Pods.
+func Enabled(obj commonv1.UpgradeGated) bool {
+	enabled, err := strconv.ParseBool(obj.GetAnnotations()[commonv1.UpgradeGatesAnnotation])
+	return err == nil && enabled
+}
+
+// Step returns the identifier of the step of the upgrade to the given version, once the given number of Pods is upgraded.
+func Step(targetVersion string, upgradedPods int32) string {
+	return fmt.Sprintf("%s/%d", targetVersion, upgradedPods)
+}
+
+// Approved returns true if the upgrade of the given resource can proceed with the restart of its next Pods: either the
+// upgrade gates are not enabled, or the current step is approved with the upgrade-gate-approved annotation. Otherwise,
+// the step waiting for approval is recorded in the status of the resource.
+func Approved(ctx context.Context, recorder record.EventRecorder, obj commonv1.UpgradeGated, targetVersion string, upgradedPods, expectedPods int32) bool {
+	if !Enabled(obj) {
+		obj.SetUpgradeGateStatus(nil)
+		return true
+	}
+	step := Step(targetVersion, upgradedPods)
+	if obj.GetAnnotations()[commonv1.UpgradeGateApprovedAnnotation] == step {
+		obj.SetUpgradeGateStatus(nil)
+		return true
+	}
+
+	status := obj.GetUpgradeGateStatus()
+	if status == nil || status.Step != step {
+		status = &commonv1.UpgradeGateStatus{Step: step, WaitingSince: metav1.NewTime(time.Now().Truncate(time.Second))}
+		ulog.FromContext(ctx).Info("Waiting for the approval of the next step of the version upgrade",
+			"namespace", obj.GetNamespace(), "name", obj.GetName(), "step", step)
+		recorder.Eventf(obj, corev1.EventTypeNormal, events.EventReasonUpgradePaused,
+			"Upgrade to %s paused with %d/%d Pods upgraded, set the %s annotation to %s to proceed",
+			targetVersion, upgradedPods, expectedPods, commonv1.UpgradeGateApprovedAnnotation, step)
+	} else {
+		status = status.DeepCopy()
+	}
+	status.TargetVersion, status.UpgradedPods, status.ExpectedPods = targetVersion, upgradedPods, expectedPods
+	obj.SetUpgradeGateStatus(status)
+	return false
+}
+
+// Clear removes the step waiting for approval from the status of the given resource, once no upgrade is in progress.
+func Clear(obj commonv1.UpgradeGated) {
+	obj.SetUpgradeGateStatus(nil)
+}
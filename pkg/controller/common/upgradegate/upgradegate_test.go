@@ -0,0 +1,58 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package upgradegate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+)
+
+func TestApproved(t *testing.T) {
+	ctx := context.Background()
+	recorder := record.NewFakeRecorder(10)
+	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
+
+	// upgrade gates not enabled
+	es.Status.UpgradeGate = &commonv1.UpgradeGateStatus{Step: "8.16.0/0"}
+	require.True(t, Approved(ctx, recorder, &es, "8.16.0", 0, 3))
+	require.Nil(t, es.Status.UpgradeGate)
+
+	// the first step waits for approval
+	es.Annotations = map[string]string{commonv1.UpgradeGatesAnnotation: "true"}
+	require.False(t, Approved(ctx, recorder, &es, "8.16.0", 0, 3))
+	require.NotNil(t, es.Status.UpgradeGate)
+	waitingSince := es.Status.UpgradeGate.WaitingSince
+	require.Equal(t, commonv1.UpgradeGateStatus{
+		Step: "8.16.0/0", TargetVersion: "8.16.0", UpgradedPods: 0, ExpectedPods: 3, WaitingSince: waitingSince,
+	}, *es.Status.UpgradeGate)
+	require.Len(t, recorder.Events, 1)
+
+	// still waiting: the waiting time is preserved and no new event is emitted
+	require.False(t, Approved(ctx, recorder, &es, "8.16.0", 0, 3))
+	require.Equal(t, waitingSince, es.Status.UpgradeGate.WaitingSince)
+	require.Len(t, recorder.Events, 1)
+
+	// the step is approved
+	es.Annotations[commonv1.UpgradeGateApprovedAnnotation] = "8.16.0/0"
+	require.True(t, Approved(ctx, recorder, &es, "8.16.0", 0, 3))
+	require.Nil(t, es.Status.UpgradeGate)
+
+	// the next step waits for approval again
+	require.False(t, Approved(ctx, recorder, &es, "8.16.0", 1, 3))
+	require.Equal(t, "8.16.0/1", es.Status.UpgradeGate.Step)
+	require.Len(t, recorder.Events, 2)
+
+	// invalid value of the annotation
+	es.Annotations[commonv1.UpgradeGatesAnnotation] = "yes please"
+	require.True(t, Approved(ctx, recorder, &es, "8.16.0", 1, 3))
+	require.Nil(t, es.Status.UpgradeGate)
+}
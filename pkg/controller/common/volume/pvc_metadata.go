@@ -0,0 +1,46 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package volume
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/metadata"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
+)
+
+// ReconcilePVCMetadata propagates the labels and annotations of the owner selected for propagation to the
+// PersistentVolumeClaims matching the given selector. The PersistentVolumeClaims are created by the StatefulSet
+// controller from immutable VolumeClaimTemplates, so their metadata is updated in place. The propagated values take
+// precedence, other labels and annotations are preserved.
+func ReconcilePVCMetadata(ctx context.Context, c k8s.Client, owner client.Object, selector client.MatchingLabels) error {
+	labels, annotations := metadata.Propagated(owner)
+	if len(labels) == 0 && len(annotations) == 0 {
+		return nil
+	}
+	var pvcs corev1.PersistentVolumeClaimList
+	if err := c.List(ctx, &pvcs, client.InNamespace(owner.GetNamespace()), selector); err != nil {
+		return fmt.Errorf("while listing pvcs to reconcile their metadata: %w", err)
+	}
+	for i := range pvcs.Items {
+		pvc := &pvcs.Items[i]
+		if maps.IsSubset(labels, pvc.Labels) && maps.IsSubset(annotations, pvc.Annotations) {
+			continue
+		}
+		ulog.FromContext(ctx).Info("Propagating metadata to PVC", "namespace", pvc.Namespace, "pvc_name", pvc.Name)
+		pvc.Labels = maps.Merge(pvc.Labels, labels)
+		pvc.Annotations = maps.Merge(pvc.Annotations, annotations)
+		if err := c.Update(ctx, pvc); err != nil {
+			return fmt.Errorf("while updating pvc metadata: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package volume
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/metadata"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func TestReconcilePVCMetadata(t *testing.T) {
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "es",
+			Labels:    map[string]string{"cost-center": "43"},
+			Annotations: map[string]string{
+				metadata.PropagateLabelsAnnotation:      "cost-center",
+				metadata.PropagateAnnotationsAnnotation: "velero.io/*",
+				"velero.io/backup":                      "daily",
+			},
+		},
+	}
+	selector := client.MatchingLabels{"cluster": "es"}
+	pvc := func(name string, labels map[string]string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, Labels: labels}}
+	}
+	c := k8s.NewFakeClient(
+		pvc("data-es-0", map[string]string{"cluster": "es", "cost-center": "42", "user": "value"}),
+		pvc("data-other-0", map[string]string{"cluster": "other"}),
+	)
+
+	require.NoError(t, ReconcilePVCMetadata(context.Background(), c, &es, selector))
+
+	var updated corev1.PersistentVolumeClaim
+	require.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "data-es-0"}, &updated))
+	// the propagated values take precedence, other labels are preserved
+	require.Equal(t, map[string]string{"cluster": "es", "cost-center": "43", "user": "value"}, updated.Labels)
+	require.Equal(t, map[string]string{"velero.io/backup": "daily"}, updated.Annotations)
+
+	var other corev1.PersistentVolumeClaim
+	require.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: "data-other-0"}, &other))
+	require.Equal(t, map[string]string{"cluster": "other"}, other.Labels)
+	require.Empty(t, other.Annotations)
+}
@@ -5,17 +5,52 @@
 package webhook
 
 import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/annotation"
 )
 
+// HasWarnings is implemented by the resources which declare their own warning rules, for example to report the use of
+// deprecated fields or of configurations known to cause issues with the requested version.
 type HasWarnings interface {
 	GetWarnings() []string
 }
 
-func MaybeGetWarnings(object runtime.Object) []string {
-	v, ok := object.(HasWarnings)
-	if ok {
-		return v.GetWarnings()
+// WarningRule returns the warnings raised by an object. Contrary to validation errors, warnings do not reject the
+// object: they are displayed to the user when the object is applied.
+type WarningRule func(obj runtime.Object) []string
+
+// commonWarningRules apply to all the resources validated by the operator webhooks.
+var commonWarningRules = []WarningRule{
+	legacyPauseAnnotation,
+}
+
+// GetWarnings returns the warnings raised by the rules common to all resources, followed by the warnings raised by
+// the object itself if it implements HasWarnings.
+func GetWarnings(obj runtime.Object) []string {
+	var warnings []string
+	for _, rule := range commonWarningRules {
+		warnings = append(warnings, rule(obj)...)
+	}
+	if v, ok := obj.(HasWarnings); ok {
+		warnings = append(warnings, v.GetWarnings()...)
+	}
+	return warnings
+}
+
+func legacyPauseAnnotation(obj runtime.Object) []string {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil
+	}
+	if _, exists := accessor.GetAnnotations()[annotation.LegacyPauseAnnotation]; !exists {
+		return nil
 	}
-	return nil
+	return []string{fmt.Sprintf(
+		"%s %s/%s: metadata.annotations.%s: annotation is deprecated, use %s=false instead",
+		obj.GetObjectKind().GroupVersionKind().Kind, accessor.GetNamespace(), accessor.GetName(), annotation.LegacyPauseAnnotation, annotation.ManagedAnnotation,
+	)}
 }
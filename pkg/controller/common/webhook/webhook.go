@@ -81,14 +81,15 @@ func (v *validatingWebhook) Handle(ctx context.Context, req admission.Request) a
 		return admission.Allowed("")
 	}
 
-	warnings := MaybeGetWarnings(obj)
+	warnings := GetWarnings(obj)
 
 	if err := v.commonValidations(ctx, req, obj); err != nil {
 		return admission.Denied(err.Error()).WithWarnings(warnings...)
 	}
 
 	if req.Operation == admissionv1.Create {
-		_, err = obj.ValidateCreate()
+		validationWarnings, err := obj.ValidateCreate()
+		warnings = append(warnings, validationWarnings...)
 		if err != nil {
 			return admission.Denied(err.Error()).WithWarnings(warnings...)
 		}
@@ -101,7 +102,8 @@ func (v *validatingWebhook) Handle(ctx context.Context, req admission.Request) a
 			whlog.Error(err, "decoding old object from webhook request into type (%T)", oldObj)
 			return admission.Errored(http.StatusBadRequest, err).WithWarnings(warnings...)
 		}
-		_, err = obj.ValidateUpdate(oldObj)
+		validationWarnings, err := obj.ValidateUpdate(oldObj)
+		warnings = append(warnings, validationWarnings...)
 		if err != nil {
 			return admission.Denied(err.Error()).WithWarnings(warnings...)
 		}
@@ -125,6 +125,39 @@ func Test_validatingWebhook_Handle(t *testing.T) {
 			},
 			want: admission.Allowed("").WithWarnings("Agent elastic/testAgent: spec.PolicyID is empty, spec.PolicyID will become mandatory in a future release"),
 		},
+		{
+			name: "deprecated pause annotation is allowed but it should return a warning",
+			fields: fields{
+				set.Make("elastic"),
+				&agentv1alpha1.Agent{},
+			},
+			req: admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					Object: runtime.RawExtension{
+						Raw: asJSON(&agentv1alpha1.Agent{
+							TypeMeta: metav1.TypeMeta{
+								APIVersion: agentv1alpha1.GroupVersion.String(),
+								Kind:       agentv1alpha1.Kind,
+							},
+							ObjectMeta: metav1.ObjectMeta{
+								Name:      "testAgent",
+								Namespace: "elastic",
+								Annotations: map[string]string{
+									"common.k8s.elastic.co/pause": "true",
+								},
+							},
+							Spec: agentv1alpha1.AgentSpec{
+								Version:    "7.10.0",
+								Deployment: &agentv1alpha1.DeploymentSpec{},
+								PolicyID:   "a-policy",
+							},
+						}),
+					},
+				},
+			},
+			want: admission.Allowed("").WithWarnings("Agent elastic/testAgent: metadata.annotations.common.k8s.elastic.co/pause: annotation is deprecated, use eck.k8s.elastic.co/managed=false instead"),
+		},
 		{
 			name: "create agent is denied because of invalid version, and returns denied.",
 			fields: fields{
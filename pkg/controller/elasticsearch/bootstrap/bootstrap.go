@@ -8,6 +8,7 @@ import (
 	"context"
 
 	"go.elastic.co/apm/v2"
+	"k8s.io/utils/ptr"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
@@ -87,9 +88,5 @@ func annotateWithUUID(ctx context.Context, k8sClient k8s.Client, cluster *esv1.E
 		"es_name", cluster.Name,
 		"uuid", uuid,
 	)
-	if cluster.Annotations == nil {
-		cluster.Annotations = make(map[string]string)
-	}
-	cluster.Annotations[ClusterUUIDAnnotationName] = uuid
-	return k8sClient.Update(ctx, cluster)
+	return k8s.PatchAnnotations(ctx, k8sClient, cluster, map[string]*string{ClusterUUIDAnnotationName: ptr.To(uuid)})
 }
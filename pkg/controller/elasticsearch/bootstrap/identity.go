@@ -0,0 +1,137 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+
+	"go.elastic.co/apm/v2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+const (
+	// ClusterIdentityLabelName is set on the Secrets holding the identity of a cluster, so they can easily be selected
+	// by backup tools.
+	ClusterIdentityLabelName = "elasticsearch.k8s.elastic.co/cluster-identity"
+
+	// ClusterUUIDKey is the key of the cluster UUID in the cluster identity Secret.
+	ClusterUUIDKey = "cluster-uuid"
+	// ClusterNameKey is the key of the cluster name in the cluster identity Secret.
+	ClusterNameKey = "cluster-name"
+)
+
+// newClusterIdentitySecret returns the Secret holding the identity of the given bootstrapped cluster.
+// The Secret is deliberately not owned by the Elasticsearch resource so that it survives its deletion.
+func newClusterIdentitySecret(es esv1.Elasticsearch) corev1.Secret {
+	labels := label.NewLabels(k8s.ExtractNamespacedName(&es))
+	labels[ClusterIdentityLabelName] = "true"
+	return corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: es.Namespace,
+			Name:      esv1.ClusterIdentitySecret(es.Name),
+			Labels:    labels,
+		},
+		Data: map[string][]byte{
+			ClusterUUIDKey: []byte(es.Annotations[ClusterUUIDAnnotationName]),
+			ClusterNameKey: []byte(es.Name),
+		},
+	}
+}
+
+// ReconcileClusterIdentity persists the identity of the cluster in a dedicated Secret if requested through the
+// esv1.PreserveClusterIdentityAnnotation. The Secret is only written once the cluster is annotated with its UUID, and
+// never with a UUID which does not match the one reported by Elasticsearch, for example if a re-linked cluster has
+// bootstrapped a new cluster because its data could not be found.
+func ReconcileClusterIdentity(
+	ctx context.Context,
+	c k8s.Client,
+	es esv1.Elasticsearch,
+	esClient client.Client,
+	esReachable bool,
+	recorder record.EventRecorder,
+) error {
+	if !es.PreservesClusterIdentity() || !AnnotatedForBootstrap(es) {
+		return nil
+	}
+
+	span, ctx := apm.StartSpan(ctx, "reconcile_cluster_identity", tracing.SpanTypeApp)
+	defer span.End()
+
+	if esReachable {
+		clusterUUID, err := getClusterUUID(ctx, esClient)
+		if err != nil {
+			// not critical, the Secret is already up to date most of the time: just retry later
+			ulog.FromContext(ctx).V(1).Info("Cannot retrieve cluster UUID", "namespace", es.Namespace, "es_name", es.Name, "error", err)
+		} else if isUUIDValid(clusterUUID) && clusterUUID != es.Annotations[ClusterUUIDAnnotationName] {
+			recorder.Event(&es, corev1.EventTypeWarning, events.EventReasonUnexpected, fmt.Sprintf(
+				"Elasticsearch reports cluster UUID %s, but the cluster is annotated with UUID %s: not updating the cluster identity Secret %s",
+				clusterUUID, es.Annotations[ClusterUUIDAnnotationName], esv1.ClusterIdentitySecret(es.Name),
+			))
+			return nil
+		}
+	}
+
+	// no owner: the Secret must survive the deletion of the Elasticsearch resource
+	_, err := reconciler.ReconcileSecret(ctx, c, newClusterIdentitySecret(es), nil)
+	return err
+}
+
+// RelinkClusterIdentity annotates a recreated Elasticsearch resource which is not bootstrapped yet with the UUID stored
+// in the cluster identity Secret referenced by the esv1.ClusterIdentityAnnotation. The cluster is then considered
+// bootstrapped and the existing data is reused instead of bootstrapping a new cluster.
+// An error is returned if the Secret cannot be found or does not match the Elasticsearch resource, to prevent the
+// creation of any node until the user fixes the annotation.
+func RelinkClusterIdentity(ctx context.Context, c k8s.Client, es *esv1.Elasticsearch, recorder record.EventRecorder) error {
+	secretName, requested := es.ClusterIdentitySecretName()
+	if !requested || AnnotatedForBootstrap(*es) {
+		return nil
+	}
+
+	var secret corev1.Secret
+	err := c.Get(ctx, types.NamespacedName{Namespace: es.Namespace, Name: secretName}, &secret)
+	if apierrors.IsNotFound(err) {
+		err = fmt.Errorf("cluster identity Secret %s not found", secretName)
+		recorder.Event(es, corev1.EventTypeWarning, events.EventReasonValidation, err.Error())
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	clusterUUID := string(secret.Data[ClusterUUIDKey])
+	if !isUUIDValid(clusterUUID) {
+		err = fmt.Errorf("cluster identity Secret %s does not contain a valid cluster UUID", secretName)
+		recorder.Event(es, corev1.EventTypeWarning, events.EventReasonValidation, err.Error())
+		return err
+	}
+	// the cluster name is persisted in the data of the nodes, a cluster with a different name cannot reuse it
+	if clusterName := string(secret.Data[ClusterNameKey]); clusterName != es.Name {
+		err = fmt.Errorf("cluster identity Secret %s belongs to cluster %s and cannot be used by cluster %s", secretName, clusterName, es.Name)
+		recorder.Event(es, corev1.EventTypeWarning, events.EventReasonValidation, err.Error())
+		return err
+	}
+
+	ulog.FromContext(ctx).Info(
+		"Re-linking cluster to its existing identity",
+		"namespace", es.Namespace,
+		"es_name", es.Name,
+		"secret_name", secretName,
+	)
+	return annotateWithUUID(ctx, c, es, clusterUUID)
+}
@@ -0,0 +1,158 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package bootstrap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func identitySecret(clusterName, uuid string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cluster-es-cluster-identity"},
+		Data:       map[string][]byte{ClusterUUIDKey: []byte(uuid), ClusterNameKey: []byte(clusterName)},
+	}
+}
+
+func TestReconcileClusterIdentity(t *testing.T) {
+	preserved := func(annotations map[string]string) esv1.Elasticsearch {
+		es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cluster", Annotations: annotations}}
+		es.Annotations[esv1.PreserveClusterIdentityAnnotation] = "true"
+		return es
+	}
+	tests := []struct {
+		name        string
+		es          esv1.Elasticsearch
+		esClient    *fakeESClient
+		esReachable bool
+		wantSecret  *corev1.Secret
+		wantEvent   bool
+	}{
+		{
+			name:     "identity not preserved",
+			es:       *bootstrappedES(),
+			esClient: &fakeESClient{uuid: "uuid"},
+		},
+		{
+			name:     "cluster not bootstrapped yet",
+			es:       preserved(map[string]string{}),
+			esClient: &fakeESClient{},
+		},
+		{
+			name:        "cluster bootstrapped",
+			es:          preserved(map[string]string{ClusterUUIDAnnotationName: "uuid"}),
+			esClient:    &fakeESClient{uuid: "uuid"},
+			esReachable: true,
+			wantSecret:  identitySecret("cluster", "uuid"),
+		},
+		{
+			name:       "cluster bootstrapped, Elasticsearch not reachable",
+			es:         preserved(map[string]string{ClusterUUIDAnnotationName: "uuid"}),
+			esClient:   &fakeESClient{},
+			wantSecret: identitySecret("cluster", "uuid"),
+		},
+		{
+			name:        "cluster UUID mismatch",
+			es:          preserved(map[string]string{ClusterUUIDAnnotationName: "uuid"}),
+			esClient:    &fakeESClient{uuid: "other-uuid"},
+			esReachable: true,
+			wantEvent:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := k8s.NewFakeClient()
+			recorder := record.NewFakeRecorder(10)
+			err := ReconcileClusterIdentity(context.Background(), c, tt.es, tt.esClient, tt.esReachable, recorder)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantEvent, len(recorder.Events) > 0)
+
+			var secret corev1.Secret
+			err = c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "cluster-es-cluster-identity"}, &secret)
+			if tt.wantSecret == nil {
+				require.True(t, apierrors.IsNotFound(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantSecret.Data, secret.Data)
+			require.Equal(t, "true", secret.Labels[ClusterIdentityLabelName])
+			require.Empty(t, secret.OwnerReferences)
+		})
+	}
+}
+
+func TestRelinkClusterIdentity(t *testing.T) {
+	relinked := func(annotations map[string]string) *esv1.Elasticsearch {
+		annotations[esv1.ClusterIdentityAnnotation] = "cluster-es-cluster-identity"
+		return &esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cluster", Annotations: annotations}}
+	}
+	tests := []struct {
+		name           string
+		es             *esv1.Elasticsearch
+		secret         *corev1.Secret
+		wantErr        bool
+		wantAnnotation string
+	}{
+		{
+			name: "no re-linking requested",
+			es:   &esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cluster"}},
+		},
+		{
+			name:           "cluster already bootstrapped",
+			es:             relinked(map[string]string{ClusterUUIDAnnotationName: "uuid"}),
+			secret:         identitySecret("cluster", "other-uuid"),
+			wantAnnotation: "uuid",
+		},
+		{
+			name:           "re-link the cluster",
+			es:             relinked(map[string]string{}),
+			secret:         identitySecret("cluster", "uuid"),
+			wantAnnotation: "uuid",
+		},
+		{
+			name:    "Secret not found",
+			es:      relinked(map[string]string{}),
+			wantErr: true,
+		},
+		{
+			name:    "Secret without cluster UUID",
+			es:      relinked(map[string]string{}),
+			secret:  identitySecret("cluster", ""),
+			wantErr: true,
+		},
+		{
+			name:    "Secret of another cluster",
+			es:      relinked(map[string]string{}),
+			secret:  identitySecret("other-cluster", "uuid"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects := []client.Object{tt.es}
+			if tt.secret != nil {
+				objects = append(objects, tt.secret)
+			}
+			c := k8s.NewFakeClient(objects...)
+			err := RelinkClusterIdentity(context.Background(), c, tt.es, record.NewFakeRecorder(10))
+			require.Equal(t, tt.wantErr, err != nil, "RelinkClusterIdentity() error = %v", err)
+
+			var es esv1.Elasticsearch
+			require.NoError(t, c.Get(context.Background(), k8s.ExtractNamespacedName(tt.es), &es))
+			require.Equal(t, tt.wantAnnotation, es.Annotations[ClusterUUIDAnnotationName])
+		})
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/initcontainer"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
+	esvolume "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
+)
+
+const (
+	// UnsafeBootstrapPodLabelName is set on the Jobs of the unsafe bootstrap procedure with the name of the Pod
+	// whose data is processed by the Job.
+	UnsafeBootstrapPodLabelName = "elasticsearch.k8s.elastic.co/unsafe-bootstrap-pod"
+
+	unsafeBootstrapJobSuffix     = "-bootstrap"
+	unsafeBootstrapContainerName = "elasticsearch-node"
+	elasticsearchNodeToolPath    = "/usr/share/elasticsearch/bin/elasticsearch-node"
+	unsafeBootstrapCommand       = "unsafe-bootstrap"
+	detachClusterCommand         = "detach-cluster"
+)
+
+// UnsafeBootstrapJobName returns the name of the Job which runs the elasticsearch-node tool against the data of the given Pod.
+func UnsafeBootstrapJobName(podName string) string {
+	return podName + unsafeBootstrapJobSuffix
+}
+
+// ReconcileUnsafeBootstrap drives the unsafe bootstrap procedure requested by the user through the
+// esv1.UnsafeBootstrapAnnotation, to recover a cluster which has irrecoverably lost the majority of its master nodes:
+//   - all the Pods of the cluster are suspended, see expectedSuspendedPodNames in the driver
+//   - once suspended, a Job runs `elasticsearch-node unsafe-bootstrap` against the data of the requested Pod, and
+//     `elasticsearch-node detach-cluster` against the data of every other Pod
+//   - once all the Jobs have succeeded, the Jobs are deleted and the annotations are removed from the Elasticsearch
+//     resource, which resumes the Pods: the requested Pod forms a new cluster that the other Pods join.
+//
+// If a Job fails the Pods remain suspended and the Job is kept for inspection.
+// It returns true if the procedure is in progress and the reconciliation should be re-queued.
+func ReconcileUnsafeBootstrap(ctx context.Context, c k8s.Client, es *esv1.Elasticsearch, recorder record.EventRecorder) (bool, error) {
+	podName, requested := es.UnsafeBootstrapPodName()
+	if !requested {
+		return false, nil
+	}
+	log := ulog.FromContext(ctx)
+
+	statefulSets, err := sset.RetrieveActualStatefulSets(c, k8s.ExtractNamespacedName(es))
+	if err != nil {
+		return false, err
+	}
+	pods, err := statefulSets.GetActualPods(c)
+	if err != nil {
+		return false, err
+	}
+
+	if !isValidUnsafeBootstrapTarget(pods, podName) {
+		recorder.Event(es, corev1.EventTypeWarning, events.EventReasonValidation,
+			fmt.Sprintf("Cannot bootstrap a new cluster from Pod %s: it must be an existing master-eligible Pod of the cluster", podName))
+		// keep the Pods suspended until the user fixes or removes the annotation
+		return true, nil
+	}
+
+	var jobs []batchv1.Job //nolint:prealloc
+	var lostPods []string
+	for _, pod := range pods {
+		switch {
+		case initcontainer.IsSuspended(pod):
+		case pod.Spec.NodeName == "" && pod.Name != podName:
+			// the Pod cannot be scheduled, most likely because its Kubernetes node is gone
+			lostPods = append(lostPods, pod.Name)
+			continue
+		default:
+			log.V(1).Info("Waiting for Pod to be suspended before unsafe bootstrap",
+				"namespace", es.Namespace, "es_name", es.Name, "pod_name", pod.Name)
+			return true, nil
+		}
+		command := detachClusterCommand
+		if pod.Name == podName {
+			command = unsafeBootstrapCommand
+		}
+		job, hasData := newUnsafeBootstrapJob(*es, pod, command)
+		if !hasData {
+			// no persistent data to process, the node forms or joins the new cluster on its own
+			continue
+		}
+		if err := controllerutil.SetControllerReference(es, &job, scheme.Scheme); err != nil {
+			return false, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	succeeded := 0
+	for i := range jobs {
+		expected := jobs[i]
+		var actual batchv1.Job
+		err := c.Get(ctx, k8s.ExtractNamespacedName(&expected), &actual)
+		if apierrors.IsNotFound(err) {
+			log.Info("Creating unsafe bootstrap job", "namespace", es.Namespace, "es_name", es.Name,
+				"job_name", expected.Name, "command", expected.Spec.Template.Spec.Containers[0].Command)
+			if err := c.Create(ctx, &expected); err != nil {
+				return false, err
+			}
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		switch {
+		case actual.Status.Failed > 0:
+			recorder.Event(es, corev1.EventTypeWarning, events.EventReasonUnsafeBootstrap,
+				fmt.Sprintf("Unsafe bootstrap job %s failed, Pods remain suspended", actual.Name))
+			return true, nil
+		case actual.Status.Succeeded > 0:
+			succeeded++
+		}
+	}
+	if succeeded < len(jobs) {
+		log.Info("Unsafe bootstrap in progress", "namespace", es.Namespace, "es_name", es.Name,
+			"succeeded_jobs", succeeded, "expected_jobs", len(jobs))
+		return true, nil
+	}
+
+	// all Jobs have succeeded, delete them before resuming the Pods
+	for i := range jobs {
+		if err := c.Delete(ctx, &jobs[i], client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !apierrors.IsNotFound(err) {
+			return false, err
+		}
+	}
+	if err := k8s.PatchAnnotations(ctx, c, es, map[string]*string{
+		esv1.UnsafeBootstrapAnnotation:                nil,
+		esv1.UnsafeBootstrapAcknowledgementAnnotation: nil,
+	}); err != nil {
+		return false, err
+	}
+	message := fmt.Sprintf("Bootstrapped a new cluster from Pod %s", podName)
+	if len(lostPods) > 0 {
+		message += fmt.Sprintf(", Pods %s could not be detached from the former cluster", strings.Join(lostPods, ","))
+	}
+	recorder.Event(es, corev1.EventTypeNormal, events.EventReasonUnsafeBootstrap, message)
+	// requeue to resume the Pods
+	return true, nil
+}
+
+// isValidUnsafeBootstrapTarget returns true if the Pod with the given name exists and is master-eligible.
+func isValidUnsafeBootstrapTarget(pods []corev1.Pod, podName string) bool {
+	for _, pod := range pods {
+		if pod.Name == podName {
+			return label.IsMasterNode(pod) && !label.NodeTypesVotingOnlyLabelName.HasValue(true, pod.Labels)
+		}
+	}
+	return false
+}
+
+// newUnsafeBootstrapJob returns a Job which runs the given elasticsearch-node command against the data volume of the
+// given Pod, on the same Kubernetes node. It returns false if the data of the Pod is not stored in a PersistentVolumeClaim.
+func newUnsafeBootstrapJob(es esv1.Elasticsearch, pod corev1.Pod, command string) (batchv1.Job, bool) {
+	var claimName string
+	for _, v := range pod.Spec.Volumes {
+		if v.Name == esvolume.ElasticsearchDataVolumeName && v.PersistentVolumeClaim != nil {
+			claimName = v.PersistentVolumeClaim.ClaimName
+		}
+	}
+	if claimName == "" {
+		return batchv1.Job{}, false
+	}
+
+	container := corev1.Container{
+		Name:    unsafeBootstrapContainerName,
+		Command: []string{elasticsearchNodeToolPath, command, "--batch"},
+		VolumeMounts: []corev1.VolumeMount{{
+			Name:      esvolume.ElasticsearchDataVolumeName,
+			MountPath: esvolume.ElasticsearchDataMountPath,
+		}},
+	}
+	for _, c := range pod.Spec.Containers {
+		if c.Name == esv1.ElasticsearchContainerName {
+			container.Image = c.Image
+			container.ImagePullPolicy = c.ImagePullPolicy
+			container.SecurityContext = c.SecurityContext
+		}
+	}
+
+	// the Pods of the Job must not be selected as Elasticsearch Pods, don't use the labels of the cluster
+	podLabels := map[string]string{UnsafeBootstrapPodLabelName: pod.Name}
+	return batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      UnsafeBootstrapJobName(pod.Name),
+			Namespace: pod.Namespace,
+			Labels:    maps.Merge(label.NewLabels(k8s.ExtractNamespacedName(&es)), podLabels),
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: ptr.To[int32](0),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: podLabels},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					// run next to the suspended Pod which also mounts the volume
+					NodeName:                     pod.Spec.NodeName,
+					Tolerations:                  pod.Spec.Tolerations,
+					SecurityContext:              pod.Spec.SecurityContext,
+					ImagePullSecrets:             pod.Spec.ImagePullSecrets,
+					AutomountServiceAccountToken: ptr.To(false),
+					Containers:                   []corev1.Container{container},
+					Volumes: []corev1.Volume{{
+						Name: esvolume.ElasticsearchDataVolumeName,
+						VolumeSource: corev1.VolumeSource{
+							PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claimName},
+						},
+					}},
+				},
+			},
+		},
+	}, true
+}
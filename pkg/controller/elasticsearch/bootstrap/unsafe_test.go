@@ -0,0 +1,173 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package bootstrap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/statefulset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/initcontainer"
+	esvolume "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func unsafeBootstrapES(podName string) *esv1.Elasticsearch {
+	return &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster",
+			Namespace: "ns",
+			Annotations: map[string]string{
+				esv1.UnsafeBootstrapAnnotation:                podName,
+				esv1.UnsafeBootstrapAcknowledgementAnnotation: esv1.UnsafeBootstrapAcknowledgement,
+			},
+		},
+		Spec: esv1.ElasticsearchSpec{Version: "8.15.0"},
+	}
+}
+
+// unsafeBootstrapObjects returns a StatefulSet of 2 master nodes and a StatefulSet of 1 data node with their Pods.
+func unsafeBootstrapObjects(suspended bool) []client.Object {
+	masters := sset.TestSset{Namespace: "ns", Name: "cluster-es-master", ClusterName: "cluster", Replicas: 2, Master: true}
+	data := sset.TestSset{Namespace: "ns", Name: "cluster-es-data", ClusterName: "cluster", Replicas: 1, Data: true}
+	objs := []client.Object{masters.BuildPtr(), data.BuildPtr()}
+	for _, obj := range append(masters.Pods(), data.Pods()...) {
+		pod := obj.(*corev1.Pod) //nolint:forcetypeassert
+		pod.Spec.NodeName = "node-" + pod.Name
+		pod.Spec.Containers = []corev1.Container{{Name: esv1.ElasticsearchContainerName, Image: "elasticsearch:8.15.0"}}
+		pod.Spec.Volumes = []corev1.Volume{{
+			Name: esvolume.ElasticsearchDataVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "elasticsearch-data-" + pod.Name},
+			},
+		}}
+		if suspended {
+			pod.Status.InitContainerStatuses = []corev1.ContainerStatus{{
+				Name:  initcontainer.SuspendContainerName,
+				State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			}}
+		}
+		objs = append(objs, pod)
+	}
+	return objs
+}
+
+func TestReconcileUnsafeBootstrap(t *testing.T) {
+	t.Run("not requested", func(t *testing.T) {
+		es := unsafeBootstrapES("cluster-es-master-0")
+		delete(es.Annotations, esv1.UnsafeBootstrapAcknowledgementAnnotation)
+		c := k8s.NewFakeClient(append(unsafeBootstrapObjects(true), es)...)
+		inProgress, err := ReconcileUnsafeBootstrap(context.Background(), c, es, record.NewFakeRecorder(10))
+		require.NoError(t, err)
+		require.False(t, inProgress)
+		var jobs batchv1.JobList
+		require.NoError(t, c.List(context.Background(), &jobs))
+		require.Empty(t, jobs.Items)
+	})
+
+	t.Run("not a master-eligible Pod", func(t *testing.T) {
+		es := unsafeBootstrapES("cluster-es-data-0")
+		c := k8s.NewFakeClient(append(unsafeBootstrapObjects(true), es)...)
+		recorder := record.NewFakeRecorder(10)
+		inProgress, err := ReconcileUnsafeBootstrap(context.Background(), c, es, recorder)
+		require.NoError(t, err)
+		require.True(t, inProgress)
+		require.Len(t, recorder.Events, 1)
+		var jobs batchv1.JobList
+		require.NoError(t, c.List(context.Background(), &jobs))
+		require.Empty(t, jobs.Items)
+	})
+
+	t.Run("Pods not suspended yet", func(t *testing.T) {
+		es := unsafeBootstrapES("cluster-es-master-0")
+		c := k8s.NewFakeClient(append(unsafeBootstrapObjects(false), es)...)
+		inProgress, err := ReconcileUnsafeBootstrap(context.Background(), c, es, record.NewFakeRecorder(10))
+		require.NoError(t, err)
+		require.True(t, inProgress)
+		var jobs batchv1.JobList
+		require.NoError(t, c.List(context.Background(), &jobs))
+		require.Empty(t, jobs.Items)
+	})
+
+	t.Run("full procedure", func(t *testing.T) {
+		es := unsafeBootstrapES("cluster-es-master-0")
+		c := k8s.NewFakeClient(append(unsafeBootstrapObjects(true), es)...)
+		recorder := record.NewFakeRecorder(10)
+
+		// Pods are suspended: Jobs are created
+		inProgress, err := ReconcileUnsafeBootstrap(context.Background(), c, es, recorder)
+		require.NoError(t, err)
+		require.True(t, inProgress)
+		var jobs batchv1.JobList
+		require.NoError(t, c.List(context.Background(), &jobs))
+		require.Len(t, jobs.Items, 3)
+		commands := map[string]string{}
+		for _, job := range jobs.Items {
+			require.Equal(t, "node-"+job.Labels[UnsafeBootstrapPodLabelName], job.Spec.Template.Spec.NodeName)
+			commands[job.Name] = job.Spec.Template.Spec.Containers[0].Command[1]
+		}
+		require.Equal(t, map[string]string{
+			"cluster-es-master-0-bootstrap": "unsafe-bootstrap",
+			"cluster-es-master-1-bootstrap": "detach-cluster",
+			"cluster-es-data-0-bootstrap":   "detach-cluster",
+		}, commands)
+
+		// some Jobs are still running
+		for _, name := range []string{"cluster-es-master-0-bootstrap", "cluster-es-master-1-bootstrap"} {
+			var job batchv1.Job
+			require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: name}, &job))
+			job.Status.Succeeded = 1
+			require.NoError(t, c.Status().Update(context.Background(), &job))
+		}
+		inProgress, err = ReconcileUnsafeBootstrap(context.Background(), c, es, recorder)
+		require.NoError(t, err)
+		require.True(t, inProgress)
+		require.Len(t, es.Annotations, 2)
+
+		// all Jobs have succeeded: Jobs are deleted and the annotations removed
+		var job batchv1.Job
+		require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "cluster-es-data-0-bootstrap"}, &job))
+		job.Status.Succeeded = 1
+		require.NoError(t, c.Status().Update(context.Background(), &job))
+		inProgress, err = ReconcileUnsafeBootstrap(context.Background(), c, es, recorder)
+		require.NoError(t, err)
+		require.True(t, inProgress)
+		require.NoError(t, c.List(context.Background(), &jobs))
+		require.Empty(t, jobs.Items)
+		var actual esv1.Elasticsearch
+		require.NoError(t, c.Get(context.Background(), k8s.ExtractNamespacedName(es), &actual))
+		_, requested := actual.UnsafeBootstrapPodName()
+		require.False(t, requested)
+		require.Len(t, recorder.Events, 1)
+	})
+
+	t.Run("failed Job", func(t *testing.T) {
+		es := unsafeBootstrapES("cluster-es-master-0")
+		c := k8s.NewFakeClient(append(unsafeBootstrapObjects(true), es)...)
+		recorder := record.NewFakeRecorder(10)
+		_, err := ReconcileUnsafeBootstrap(context.Background(), c, es, recorder)
+		require.NoError(t, err)
+
+		var job batchv1.Job
+		require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "cluster-es-master-0-bootstrap"}, &job))
+		job.Status.Failed = 1
+		require.NoError(t, c.Status().Update(context.Background(), &job))
+		inProgress, err := ReconcileUnsafeBootstrap(context.Background(), c, es, recorder)
+		require.NoError(t, err)
+		require.True(t, inProgress)
+		require.Len(t, recorder.Events, 1)
+		// the Job is kept for inspection
+		require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "cluster-es-master-0-bootstrap"}, &job))
+	})
+}
@@ -0,0 +1,65 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package ccr
+
+import (
+	"context"
+	"strings"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
+)
+
+const (
+	// ManagedAutoFollowPatternsAnnotationName holds the list of the auto-follow patterns which have been created by
+	// the operator.
+	ManagedAutoFollowPatternsAnnotationName = "elasticsearch.k8s.elastic.co/managed-auto-follow-patterns"
+	// ManagedFollowerIndicesAnnotationName holds the list of the follower indices which have been created by the operator.
+	ManagedFollowerIndicesAnnotationName = "elasticsearch.k8s.elastic.co/managed-follower-indices"
+)
+
+// getNamesInAnnotation returns the set of names stored in the given annotation of the Elasticsearch resource.
+// If there are no names the set is empty but not nil.
+func getNamesInAnnotation(es esv1.Elasticsearch, annotation string) set.StringSet {
+	names := set.Make()
+	serialized, ok := es.Annotations[annotation]
+	if !ok || strings.TrimSpace(serialized) == "" {
+		return names
+	}
+	for _, name := range strings.Split(serialized, ",") {
+		names.Add(name)
+	}
+	return names
+}
+
+// annotateWithManagedNames stores the given sets of names in the corresponding annotations of the Elasticsearch
+// resource, only updating the resource if at least one annotation changes.
+func annotateWithManagedNames(ctx context.Context, c k8s.Client, es esv1.Elasticsearch, namesByAnnotation map[string]set.StringSet) error {
+	updated := es.DeepCopy()
+	if updated.Annotations == nil {
+		updated.Annotations = make(map[string]string)
+	}
+	changed := false
+	for annotation, names := range namesByAnnotation {
+		current, exists := updated.Annotations[annotation]
+		if names.Count() == 0 {
+			if exists {
+				delete(updated.Annotations, annotation)
+				changed = true
+			}
+			continue
+		}
+		expected := strings.Join(names.AsSortedSlice(), ",")
+		if !exists || current != expected {
+			updated.Annotations[annotation] = expected
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return c.Update(ctx, updated)
+}
@@ -0,0 +1,195 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package ccr
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"go.elastic.co/apm/v2"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
+)
+
+// defaultFollowIndexPattern is the name Elasticsearch gives to the follower indices of an auto-follow pattern if no
+// follow index pattern is specified.
+const defaultFollowIndexPattern = "{{leader_index}}"
+
+// Reconcile configures the cross-cluster replication declared in the remote clusters of the Elasticsearch spec:
+//   - auto-follow patterns are created or updated, and deleted once removed from the spec
+//   - follower indices are created, resumed if paused, and converted into regular indices once removed from the spec.
+//
+// Auto-follow patterns and follower indices created out-of-band are left untouched: the ones managed by the operator
+// are tracked in annotations on the Elasticsearch resource.
+// An error is returned if at least one item could not be reconciled, the other items are still processed.
+func Reconcile(ctx context.Context, c k8s.Client, esClient esclient.Client, es esv1.Elasticsearch) error {
+	patternsInAnnotation := getNamesInAnnotation(es, ManagedAutoFollowPatternsAnnotationName)
+	followersInAnnotation := getNamesInAnnotation(es, ManagedFollowerIndicesAnnotationName)
+	expectedPatterns, expectedFollowers := expectedReplication(es)
+	if len(expectedPatterns) == 0 && len(expectedFollowers) == 0 &&
+		patternsInAnnotation.Count() == 0 && followersInAnnotation.Count() == 0 {
+		// nothing to do, skip
+		return nil
+	}
+
+	span, ctx := apm.StartSpan(ctx, "reconcile_cross_cluster_replication", tracing.SpanTypeApp)
+	defer span.End()
+
+	patternsInES, err := esClient.GetAutoFollowPatterns(ctx)
+	if err != nil {
+		return err
+	}
+	followersInES, err := esClient.GetFollowerIndices(ctx)
+	if err != nil {
+		return err
+	}
+
+	// track the expected items before creating them, so that they are not orphaned if the annotation update fails later on
+	for name := range expectedPatterns {
+		patternsInAnnotation.Add(name)
+	}
+	for name := range expectedFollowers {
+		followersInAnnotation.Add(name)
+	}
+
+	var errs []error
+	var deletedPatterns, unfollowed []string
+	for name := range patternsInAnnotation {
+		if _, inSpec := expectedPatterns[name]; inSpec {
+			continue
+		}
+		if _, inES := patternsInES[name]; inES {
+			if err := esClient.DeleteAutoFollowPattern(ctx, name); err != nil {
+				errs = append(errs, fmt.Errorf("while deleting auto-follow pattern %s: %w", name, err))
+				continue
+			}
+			deletedPatterns = append(deletedPatterns, name)
+		}
+		// the pattern is not in Elasticsearch anymore, it does not need to be tracked
+		patternsInAnnotation.Del(name)
+	}
+	for name := range followersInAnnotation {
+		if _, inSpec := expectedFollowers[name]; inSpec {
+			continue
+		}
+		if _, inES := followersInES[name]; inES {
+			if err := esClient.UnfollowIndex(ctx, name); err != nil {
+				errs = append(errs, fmt.Errorf("while unfollowing index %s: %w", name, err))
+				continue
+			}
+			unfollowed = append(unfollowed, name)
+		}
+		// the index is not a follower index anymore, it does not need to be tracked
+		followersInAnnotation.Del(name)
+	}
+
+	if err := annotateWithManagedNames(ctx, c, es, map[string]set.StringSet{
+		ManagedAutoFollowPatternsAnnotationName: patternsInAnnotation,
+		ManagedFollowerIndicesAnnotationName:    followersInAnnotation,
+	}); err != nil {
+		return err
+	}
+
+	var updatedPatterns []string
+	for name, pattern := range expectedPatterns {
+		if current, exists := patternsInES[name]; exists && !needsUpdate(pattern, current) {
+			continue
+		}
+		if err := esClient.PutAutoFollowPattern(ctx, name, pattern); err != nil {
+			errs = append(errs, fmt.Errorf("while updating auto-follow pattern %s: %w", name, err))
+			continue
+		}
+		updatedPatterns = append(updatedPatterns, name)
+	}
+
+	var followed, resumed []string
+	for name, follower := range expectedFollowers {
+		current, exists := followersInES[name]
+		switch {
+		case !exists:
+			if err := esClient.FollowIndex(ctx, name, follower); err != nil {
+				errs = append(errs, fmt.Errorf("while following index %s: %w", name, err))
+				continue
+			}
+			followed = append(followed, name)
+		case current.RemoteCluster != follower.RemoteCluster || current.LeaderIndex != follower.LeaderIndex:
+			// the leader of a follower index cannot be changed, the follower index must be removed first
+			errs = append(errs, fmt.Errorf("follower index %s already follows index %s of remote cluster %s",
+				name, current.LeaderIndex, current.RemoteCluster))
+		case current.Status == esclient.FollowerIndexPausedStatus:
+			if err := esClient.ResumeFollowIndex(ctx, name); err != nil {
+				errs = append(errs, fmt.Errorf("while resuming follower index %s: %w", name, err))
+				continue
+			}
+			resumed = append(resumed, name)
+		}
+	}
+
+	if len(updatedPatterns)+len(deletedPatterns)+len(followed)+len(resumed)+len(unfollowed) > 0 {
+		for _, names := range [][]string{updatedPatterns, deletedPatterns, followed, resumed, unfollowed} {
+			sort.Strings(names)
+		}
+		ulog.FromContext(ctx).Info("Updated cross-cluster replication",
+			"namespace", es.Namespace,
+			"es_name", es.Name,
+			"updated_auto_follow_patterns", updatedPatterns,
+			"deleted_auto_follow_patterns", deletedPatterns,
+			"followed_indices", followed,
+			"resumed_indices", resumed,
+			"unfollowed_indices", unfollowed,
+		)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// expectedReplication returns the auto-follow patterns and the follower indices declared in the remote clusters of the
+// Elasticsearch spec, indexed by name.
+func expectedReplication(es esv1.Elasticsearch) (esclient.AutoFollowPatterns, esclient.FollowerIndices) {
+	patterns := esclient.AutoFollowPatterns{}
+	followers := esclient.FollowerIndices{}
+	for _, remoteCluster := range es.Spec.RemoteClusters {
+		if remoteCluster.Replication == nil {
+			continue
+		}
+		for _, pattern := range remoteCluster.Replication.AutoFollowPatterns {
+			patterns[pattern.Name] = esclient.AutoFollowPattern{
+				RemoteCluster:                remoteCluster.Name,
+				LeaderIndexPatterns:          pattern.LeaderIndexPatterns,
+				LeaderIndexExclusionPatterns: pattern.LeaderIndexExclusionPatterns,
+				FollowIndexPattern:           pattern.FollowIndexPattern,
+			}
+		}
+		for _, follower := range remoteCluster.Replication.FollowerIndices {
+			followers[follower.Name] = esclient.FollowerIndex{
+				RemoteCluster: remoteCluster.Name,
+				LeaderIndex:   follower.LeaderIndex,
+			}
+		}
+	}
+	return patterns, followers
+}
+
+// needsUpdate compares the expected auto-follow pattern with the one in Elasticsearch, considering default values.
+func needsUpdate(expected, current esclient.AutoFollowPattern) bool {
+	followIndexPattern := func(p esclient.AutoFollowPattern) string {
+		if p.FollowIndexPattern == "" {
+			return defaultFollowIndexPattern
+		}
+		return p.FollowIndexPattern
+	}
+	return expected.RemoteCluster != current.RemoteCluster ||
+		!slices.Equal(expected.LeaderIndexPatterns, current.LeaderIndexPatterns) ||
+		// nil and empty exclusion patterns are equal
+		!slices.Equal(expected.LeaderIndexExclusionPatterns, current.LeaderIndexExclusionPatterns) ||
+		followIndexPattern(expected) != followIndexPattern(current)
+}
@@ -0,0 +1,199 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package ccr
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+type fakeESClient struct {
+	esclient.Client
+	patterns   esclient.AutoFollowPatterns
+	followers  esclient.FollowerIndices
+	put        []string
+	deleted    []string
+	followed   []string
+	resumed    []string
+	unfollowed []string
+}
+
+func (f *fakeESClient) GetAutoFollowPatterns(_ context.Context) (esclient.AutoFollowPatterns, error) {
+	return f.patterns, nil
+}
+
+func (f *fakeESClient) PutAutoFollowPattern(_ context.Context, name string, pattern esclient.AutoFollowPattern) error {
+	f.put = append(f.put, name)
+	f.patterns[name] = pattern
+	return nil
+}
+
+func (f *fakeESClient) DeleteAutoFollowPattern(_ context.Context, name string) error {
+	f.deleted = append(f.deleted, name)
+	delete(f.patterns, name)
+	return nil
+}
+
+func (f *fakeESClient) GetFollowerIndices(_ context.Context) (esclient.FollowerIndices, error) {
+	return f.followers, nil
+}
+
+func (f *fakeESClient) FollowIndex(_ context.Context, name string, follower esclient.FollowerIndex) error {
+	f.followed = append(f.followed, name)
+	f.followers[name] = follower
+	return nil
+}
+
+func (f *fakeESClient) ResumeFollowIndex(_ context.Context, name string) error {
+	f.resumed = append(f.resumed, name)
+	return nil
+}
+
+func (f *fakeESClient) UnfollowIndex(_ context.Context, name string) error {
+	f.unfollowed = append(f.unfollowed, name)
+	delete(f.followers, name)
+	return nil
+}
+
+func newES(annotations map[string]string, replication *esv1.RemoteClusterReplication) esv1.Elasticsearch {
+	return esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es", Annotations: annotations},
+		Spec: esv1.ElasticsearchSpec{RemoteClusters: []esv1.RemoteCluster{
+			{Name: "leader", ElasticsearchRef: commonv1.LocalObjectSelector{Name: "leader"}, Replication: replication},
+		}},
+	}
+}
+
+func getAnnotations(t *testing.T, c k8s.Client) map[string]string {
+	t.Helper()
+	var es esv1.Elasticsearch
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "es"}, &es))
+	return es.Annotations
+}
+
+func TestReconcile(t *testing.T) {
+	logsPattern := esv1.AutoFollowPattern{Name: "logs", LeaderIndexPatterns: []string{"logs-*"}}
+	tests := []struct {
+		name            string
+		es              esv1.Elasticsearch
+		patterns        esclient.AutoFollowPatterns
+		followers       esclient.FollowerIndices
+		wantPut         []string
+		wantDeleted     []string
+		wantFollowed    []string
+		wantResumed     []string
+		wantUnfollowed  []string
+		wantAnnotations map[string]string
+		wantErr         string
+	}{
+		{
+			name:      "nothing to do",
+			es:        newES(nil, nil),
+			patterns:  esclient.AutoFollowPatterns{"out-of-band": {RemoteCluster: "leader"}},
+			followers: esclient.FollowerIndices{"out-of-band": {RemoteCluster: "leader"}},
+		},
+		{
+			name: "create auto-follow patterns and follower indices",
+			es: newES(nil, &esv1.RemoteClusterReplication{
+				AutoFollowPatterns: []esv1.AutoFollowPattern{logsPattern},
+				FollowerIndices:    []esv1.FollowerIndex{{Name: "metrics-copy", LeaderIndex: "metrics"}},
+			}),
+			patterns:     esclient.AutoFollowPatterns{},
+			followers:    esclient.FollowerIndices{},
+			wantPut:      []string{"logs"},
+			wantFollowed: []string{"metrics-copy"},
+			wantAnnotations: map[string]string{
+				ManagedAutoFollowPatternsAnnotationName: "logs",
+				ManagedFollowerIndicesAnnotationName:    "metrics-copy",
+			},
+		},
+		{
+			name: "up to date with default values, resume a paused follower index",
+			es: newES(
+				map[string]string{ManagedAutoFollowPatternsAnnotationName: "logs", ManagedFollowerIndicesAnnotationName: "metrics-copy"},
+				&esv1.RemoteClusterReplication{
+					AutoFollowPatterns: []esv1.AutoFollowPattern{logsPattern},
+					FollowerIndices:    []esv1.FollowerIndex{{Name: "metrics-copy", LeaderIndex: "metrics"}},
+				},
+			),
+			patterns: esclient.AutoFollowPatterns{"logs": {
+				RemoteCluster:                "leader",
+				LeaderIndexPatterns:          []string{"logs-*"},
+				LeaderIndexExclusionPatterns: []string{},
+				FollowIndexPattern:           "{{leader_index}}",
+			}},
+			followers:   esclient.FollowerIndices{"metrics-copy": {RemoteCluster: "leader", LeaderIndex: "metrics", Status: "paused"}},
+			wantResumed: []string{"metrics-copy"},
+			wantAnnotations: map[string]string{
+				ManagedAutoFollowPatternsAnnotationName: "logs",
+				ManagedFollowerIndicesAnnotationName:    "metrics-copy",
+			},
+		},
+		{
+			name: "update an auto-follow pattern",
+			es: newES(map[string]string{ManagedAutoFollowPatternsAnnotationName: "logs"}, &esv1.RemoteClusterReplication{
+				AutoFollowPatterns: []esv1.AutoFollowPattern{logsPattern},
+			}),
+			patterns:        esclient.AutoFollowPatterns{"logs": {RemoteCluster: "leader", LeaderIndexPatterns: []string{"logs-a-*"}}},
+			followers:       esclient.FollowerIndices{},
+			wantPut:         []string{"logs"},
+			wantAnnotations: map[string]string{ManagedAutoFollowPatternsAnnotationName: "logs"},
+		},
+		{
+			name: "remove managed items, keep the out-of-band ones",
+			es: newES(map[string]string{
+				ManagedAutoFollowPatternsAnnotationName: "logs,already-deleted",
+				ManagedFollowerIndicesAnnotationName:    "metrics-copy",
+			}, nil),
+			patterns: esclient.AutoFollowPatterns{"logs": {RemoteCluster: "leader"}, "out-of-band": {RemoteCluster: "leader"}},
+			followers: esclient.FollowerIndices{
+				"metrics-copy": {RemoteCluster: "leader", LeaderIndex: "metrics"},
+				"out-of-band":  {RemoteCluster: "leader", LeaderIndex: "other"},
+			},
+			wantDeleted:    []string{"logs"},
+			wantUnfollowed: []string{"metrics-copy"},
+		},
+		{
+			name: "follower index with a different leader",
+			es: newES(nil, &esv1.RemoteClusterReplication{
+				FollowerIndices: []esv1.FollowerIndex{{Name: "metrics-copy", LeaderIndex: "metrics"}},
+			}),
+			patterns:        esclient.AutoFollowPatterns{},
+			followers:       esclient.FollowerIndices{"metrics-copy": {RemoteCluster: "leader", LeaderIndex: "other"}},
+			wantAnnotations: map[string]string{ManagedFollowerIndicesAnnotationName: "metrics-copy"},
+			wantErr:         "follower index metrics-copy already follows index other of remote cluster leader",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := k8s.NewFakeClient(&tt.es)
+			esClient := &fakeESClient{patterns: tt.patterns, followers: tt.followers}
+			err := Reconcile(context.Background(), c, esClient, tt.es)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantPut, esClient.put)
+			require.Equal(t, tt.wantDeleted, esClient.deleted)
+			require.Equal(t, tt.wantFollowed, esClient.followed)
+			require.Equal(t, tt.wantResumed, esClient.resumed)
+			require.Equal(t, tt.wantUnfollowed, esClient.unfollowed)
+			annotations := getAnnotations(t, c)
+			for _, annotation := range []string{ManagedAutoFollowPatternsAnnotationName, ManagedFollowerIndicesAnnotationName} {
+				require.Equal(t, tt.wantAnnotations[annotation], annotations[annotation])
+			}
+		})
+	}
+}
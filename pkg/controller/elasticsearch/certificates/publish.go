@@ -0,0 +1,97 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package certificates
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// PublishedHTTPCertsSecretName returns the name of the copies of the public HTTP certificates Secret of the given
+// cluster. It is prefixed with the namespace of the cluster to not conflict with the copies of clusters with the same
+// name in other namespaces.
+func PublishedHTTPCertsSecretName(es esv1.Elasticsearch) string {
+	return es.Namespace + "-" + certificates.PublicCertsSecretName(esv1.ESNamer, es.Name)
+}
+
+// ReconcilePublishedHTTPCerts copies the public HTTP certificates Secret of the given cluster to the namespaces listed in
+// its PublishHTTPCertsAnnotation, and keeps the copies in sync through certificate rotations. Copies in namespaces which
+// are not listed anymore are deleted.
+// Errors in a namespace, for example because the operator does not manage it, are reported as events and do not prevent
+// the publication to the other namespaces.
+func ReconcilePublishedHTTPCerts(ctx context.Context, c k8s.Client, recorder record.EventRecorder, es esv1.Elasticsearch) error {
+	namespaces := es.PublishHTTPCertsNamespaces()
+	var errs []error
+	if len(namespaces) > 0 {
+		var publicCerts corev1.Secret
+		if err := c.Get(ctx, certificates.PublicCertsSecretRef(esv1.ESNamer, k8s.ExtractNamespacedName(&es)), &publicCerts); err != nil {
+			if apierrors.IsNotFound(err) {
+				// not reconciled yet, the cluster is reconciled again once it is
+				return nil
+			}
+			return err
+		}
+		for _, namespace := range namespaces {
+			expected := corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      PublishedHTTPCertsSecretName(es),
+					Labels:    map[string]string{reconciler.PublishedLabel: "true"},
+				},
+				Data: publicCerts.Data,
+			}
+			if _, err := reconciler.ReconcileSecretNoOwnerRef(ctx, c, expected, &es); err != nil {
+				recorder.Event(&es, corev1.EventTypeWarning, events.EventReasonUnexpected,
+					fmt.Sprintf("Failed to publish the HTTP certificates to namespace %s: %s", namespace, err.Error()))
+				errs = append(errs, err)
+			}
+		}
+	}
+	if err := deleteUnpublishedHTTPCerts(ctx, c, es, namespaces); err != nil {
+		errs = append(errs, err)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// deleteUnpublishedHTTPCerts deletes the copies of the public HTTP certificates Secret of the given cluster from the
+// namespaces which are not part of the given ones.
+func deleteUnpublishedHTTPCerts(ctx context.Context, c k8s.Client, es esv1.Elasticsearch, namespaces []string) error {
+	var secrets corev1.SecretList
+	if err := c.List(ctx, &secrets, client.MatchingLabels{
+		reconciler.SoftOwnerNamespaceLabel: es.Namespace,
+		reconciler.SoftOwnerNameLabel:      es.Name,
+		reconciler.SoftOwnerKindLabel:      esv1.Kind,
+		reconciler.PublishedLabel:          "true",
+	}); err != nil {
+		return err
+	}
+	for i := range secrets.Items {
+		secret := secrets.Items[i]
+		if secret.Name != PublishedHTTPCertsSecretName(es) || slices.Contains(namespaces, secret.Namespace) {
+			continue
+		}
+		ulog.FromContext(ctx).Info("Deleting published HTTP certificates",
+			"namespace", secret.Namespace, "secret_name", secret.Name, "es_name", es.Name)
+		if err := c.Delete(ctx, &secret, &client.DeleteOptions{Preconditions: &metav1.Preconditions{UID: &secret.UID}}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
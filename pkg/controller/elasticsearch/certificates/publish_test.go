@@ -0,0 +1,78 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package certificates
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func TestReconcilePublishedHTTPCerts(t *testing.T) {
+	es := esv1.Elasticsearch{
+		TypeMeta: metav1.TypeMeta{Kind: esv1.Kind},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es", Annotations: map[string]string{
+			esv1.PublishHTTPCertsAnnotation: "app-1,app-2",
+		}},
+	}
+	publicCerts := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es-es-http-certs-public"},
+		Data:       map[string][]byte{"ca.crt": []byte("ca"), "tls.crt": []byte("cert")},
+	}
+	// the copy of a cluster with the same name in another namespace
+	otherCopy := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "app-1", Name: "other-es-es-http-certs-public", Labels: map[string]string{
+			reconciler.SoftOwnerNamespaceLabel: "other",
+			reconciler.SoftOwnerNameLabel:      "es",
+			reconciler.SoftOwnerKindLabel:      esv1.Kind,
+			reconciler.PublishedLabel:          "true",
+		}},
+	}
+	c := k8s.NewFakeClient(&publicCerts, &otherCopy)
+	ctx := context.Background()
+	copyIn := func(namespace string) types.NamespacedName {
+		return types.NamespacedName{Namespace: namespace, Name: "ns-es-es-http-certs-public"}
+	}
+
+	// the certificates are copied to the listed namespaces
+	require.NoError(t, ReconcilePublishedHTTPCerts(ctx, c, record.NewFakeRecorder(10), es))
+	for _, namespace := range []string{"app-1", "app-2"} {
+		var published corev1.Secret
+		require.NoError(t, c.Get(ctx, copyIn(namespace), &published))
+		require.Equal(t, publicCerts.Data, published.Data)
+		require.Equal(t, "true", published.Labels[reconciler.PublishedLabel])
+		require.Equal(t, "ns", published.Labels[reconciler.SoftOwnerNamespaceLabel])
+	}
+
+	// the copies follow the rotation of the certificates
+	publicCerts.Data = map[string][]byte{"ca.crt": []byte("new-ca"), "tls.crt": []byte("new-cert")}
+	require.NoError(t, c.Update(ctx, &publicCerts))
+	require.NoError(t, ReconcilePublishedHTTPCerts(ctx, c, record.NewFakeRecorder(10), es))
+	var published corev1.Secret
+	require.NoError(t, c.Get(ctx, copyIn("app-2"), &published))
+	require.Equal(t, []byte("new-ca"), published.Data["ca.crt"])
+
+	// copies in namespaces which are not listed anymore are deleted, but not the copies of other clusters
+	es.Annotations[esv1.PublishHTTPCertsAnnotation] = "app-2"
+	require.NoError(t, ReconcilePublishedHTTPCerts(ctx, c, record.NewFakeRecorder(10), es))
+	var secrets corev1.SecretList
+	require.NoError(t, c.List(ctx, &secrets))
+	var names []types.NamespacedName
+	for _, s := range secrets.Items {
+		names = append(names, k8s.ExtractNamespacedName(&s))
+	}
+	require.ElementsMatch(t, []types.NamespacedName{
+		k8s.ExtractNamespacedName(&publicCerts), k8s.ExtractNamespacedName(&otherCopy), copyIn("app-2"),
+	}, names)
+}
@@ -7,10 +7,12 @@ package certificates
 import (
 	"context"
 	"crypto/x509"
+	"slices"
 	"time"
 
 	"go.elastic.co/apm/v2"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
@@ -52,12 +54,13 @@ func ReconcileHTTP(
 	}
 
 	// reconcile HTTP CA and cert
+	previousHTTPCA := caCertificate(ctx, driver.K8sClient(), es, certificates.HTTPCAType)
 	var httpCerts *certificates.CertificatesSecret
 	httpCerts, results = certificates.Reconciler{
 		K8sClient:      driver.K8sClient(),
 		DynamicWatches: driver.DynamicWatches(),
 		Owner:          &es,
-		TLSOptions:     es.Spec.HTTP.TLS,
+		TLSOptions:     es.Spec.HTTP.EffectiveTLS(),
 		ExtraHTTPSANs:  extraHTTPSANs,
 		Namer:          esv1.ESNamer,
 		Labels:         certsLabels,
@@ -74,6 +77,7 @@ func ReconcileHTTP(
 		k8s.MaybeEmitErrorEvent(driver.Recorder(), err, &es, events.EventReconciliationError, "Certificate reconciliation error: %v", err)
 		return nil, results
 	}
+	reportCARotation(ctx, driver, es, certificates.HTTPCAType, previousHTTPCA)
 
 	trustedHTTPCertificates, err := certificates.ParsePEMCerts(httpCerts.CertChain())
 	if err != nil {
@@ -109,6 +113,7 @@ func ReconcileTransport(
 	}
 
 	// reconcile transport CA and certs
+	previousTransportCA := caCertificate(ctx, driver.K8sClient(), es, certificates.TransportCAType)
 	transportCA, err := transport.ReconcileOrRetrieveCA(
 		ctx,
 		driver,
@@ -120,6 +125,8 @@ func ReconcileTransport(
 	if err != nil {
 		return results.WithError(err)
 	}
+	reportCARotation(ctx, driver, es, certificates.TransportCAType, previousTransportCA)
+	certificates.ReportExpiry(esv1.Kind, &es, certificates.TransportCAMetric, transportCA.Cert.NotAfter)
 	// make sure to requeue before the CA cert expires
 	results.WithReconciliationState(
 		reconciler.
@@ -127,8 +134,15 @@ func ReconcileTransport(
 			ReconciliationComplete(), // This reconciliation result should not prevent the reconciliation loop to be considered as completed in the status
 	)
 
+	// keep trusting the former CA while the certificates of the nodes are re-issued after the CA Secret was deleted
+	formerTransportCA, err := transport.GetPreviousCA(ctx, driver.K8sClient(), es)
+	if err != nil {
+		return results.WithError(err)
+	}
+	trustedCAs := slices.Concat(additionalCAs, formerTransportCA.PEM())
+
 	// reconcile transport public certs secret
-	if err := transport.ReconcileTransportCertsPublicSecret(ctx, driver.K8sClient(), es, transportCA, additionalCAs); err != nil {
+	if err := transport.ReconcileTransportCertsPublicSecret(ctx, driver.K8sClient(), es, transportCA, trustedCAs); err != nil {
 		return results.WithError(err)
 	}
 
@@ -136,11 +150,24 @@ func ReconcileTransport(
 	transportResults := transport.ReconcileTransportCertificatesSecrets(
 		ctx,
 		driver.K8sClient(),
+		driver.Recorder(),
 		transportCA,
-		additionalCAs,
+		formerTransportCA,
+		trustedCAs,
 		es,
 		certRotation,
 	)
+	if formerTransportCA != nil {
+		allReissued, _ := transportResults.IsReconciled()
+		deleted, err := transport.GarbageCollectPreviousCA(ctx, driver.K8sClient(), es, formerTransportCA, allReissued)
+		if err != nil {
+			return results.WithError(err)
+		}
+		if !deleted {
+			// move to the next phase of the rollover to the new CA in time
+			results.WithReconciliationState(reconciler.RequeueAfter(formerTransportCA.RequeueAfter(time.Now())).ReconciliationComplete())
+		}
+	}
 
 	// reconcile remote clusters certificate authorities
 	if err := remoteca.Reconcile(ctx, driver.K8sClient(), es, *transportCA); err != nil {
@@ -153,3 +180,31 @@ func ReconcileTransport(
 
 	return results
 }
+
+// caCertificate returns the certificate of the self-signed CA of the given type, or nil if there is none.
+func caCertificate(ctx context.Context, c k8s.Client, es esv1.Elasticsearch, caType certificates.CAType) *x509.Certificate {
+	var secret corev1.Secret
+	nsn := types.NamespacedName{Namespace: es.Namespace, Name: certificates.CAInternalSecretName(esv1.ESNamer, es.Name, caType)}
+	if err := c.Get(ctx, nsn, &secret); err != nil {
+		return nil
+	}
+	ca := certificates.BuildCAFromSecret(ctx, secret)
+	if ca == nil {
+		return nil
+	}
+	return ca.Cert
+}
+
+// reportCARotation emits an event if the self-signed CA of the given type was replaced by a new one during the
+// reconciliation. The creation of the first CA of a cluster is not reported.
+func reportCARotation(ctx context.Context, driver driver.Interface, es esv1.Elasticsearch, caType certificates.CAType, previous *x509.Certificate) {
+	if previous == nil {
+		return
+	}
+	current := caCertificate(ctx, driver.K8sClient(), es, caType)
+	if current == nil || current.Equal(previous) {
+		return
+	}
+	driver.Recorder().Eventf(&es, corev1.EventTypeNormal, events.EventReasonCertificateRotated,
+		"Rotated the %s CA certificate, the new certificate expires on %s", caType, current.NotAfter.UTC().Format(time.RFC3339))
+}
@@ -0,0 +1,60 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package certificates
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/driver"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func Test_reportCARotation(t *testing.T) {
+	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
+	c := k8s.NewFakeClient(&es)
+	recorder := record.NewFakeRecorder(10)
+	d := driver.TestDriver{Client: c, Watches: watches.NewDynamicWatches(), FakeRecorder: recorder}
+	rotation := certificates.RotationParams{Validity: certificates.DefaultCertValidity, RotateBefore: certificates.DefaultRotateBefore}
+	reconcileCA := func() {
+		_, err := certificates.ReconcileCAForOwner(context.Background(), c, esv1.ESNamer, &es, nil, certificates.TransportCAType, rotation)
+		require.NoError(t, err)
+	}
+
+	// the creation of the first CA is not a rotation
+	previous := caCertificate(context.Background(), c, es, certificates.TransportCAType)
+	require.Nil(t, previous)
+	reconcileCA()
+	reportCARotation(context.Background(), d, es, certificates.TransportCAType, previous)
+	require.Empty(t, recorder.Events)
+
+	// the CA is reused
+	previous = caCertificate(context.Background(), c, es, certificates.TransportCAType)
+	require.NotNil(t, previous)
+	reconcileCA()
+	reportCARotation(context.Background(), d, es, certificates.TransportCAType, previous)
+	require.Empty(t, recorder.Events)
+
+	// the CA is replaced
+	require.NoError(t, c.Delete(context.Background(), &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Namespace: es.Namespace,
+		Name:      certificates.CAInternalSecretName(esv1.ESNamer, es.Name, certificates.TransportCAType),
+	}}))
+	reconcileCA()
+	reportCARotation(context.Background(), d, es, certificates.TransportCAType, previous)
+	require.Len(t, recorder.Events, 1)
+	current := caCertificate(context.Background(), c, es, certificates.TransportCAType)
+	require.Equal(t, "Normal CertificateRotated Rotated the transport CA certificate, the new certificate expires on "+
+		current.NotAfter.UTC().Format(time.RFC3339), <-recorder.Events)
+}
@@ -6,12 +6,14 @@ package transport
 
 import (
 	"context"
+	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/driver"
@@ -24,8 +26,8 @@ func CustomTransportCertsWatchKey(es types.NamespacedName) string {
 	return esv1.ESNamer.Suffix(es.Name, "custom-transport-certs")
 }
 
-// ReconcileOrRetrieveCA either reconciles a self-signed CA generated by the operator
-// or it retrieves a user defined CA certificate.
+// ReconcileOrRetrieveCA either reconciles a self-signed CA generated by the operator, retrieves a user defined CA
+// certificate, or retrieves a CA issued by cert-manager.
 func ReconcileOrRetrieveCA(
 	ctx context.Context,
 	driver driver.Interface,
@@ -36,17 +38,51 @@ func ReconcileOrRetrieveCA(
 ) (*certificates.CA, error) {
 	esNSN := k8s.ExtractNamespacedName(&es)
 
-	// Set up a dynamic watch to re-reconcile if users change or recreate the custom certificate secret. But also run this
-	// to remove previously created watches if a user removes the custom certificate and goes back to operator generated certs.
+	certificateRef := es.Spec.Transport.TLS.Certificate
+	issuedSecretName := certificates.CertManagerSecretName(esv1.ESNamer, es.Name, certificates.TransportCAType)
+	if es.Spec.Transport.TLS.IssuerRef != nil {
+		certificateRef = commonv1.SecretRef{SecretName: issuedSecretName}
+	}
+
+	// Set up a dynamic watch to re-reconcile if users change or recreate the custom certificate secret, or if cert-manager
+	// renews the CA. But also run this to remove previously created watches if a user removes the custom certificate and
+	// goes back to operator generated certs.
 	if err := certificates.ReconcileCustomCertWatch(
 		driver.DynamicWatches(),
 		CustomTransportCertsWatchKey(esNSN),
 		esNSN,
-		es.Spec.Transport.TLS.Certificate,
+		certificateRef,
 	); err != nil {
 		return nil, err
 	}
 
+	if issuerRef := es.Spec.Transport.TLS.IssuerRef; issuerRef != nil {
+		issuedSecret, err := certificates.ReconcileCertManagerCertificate(ctx, driver.K8sClient(), &es, labels, certificates.CertManagerCertificate{
+			Name:       issuedSecretName,
+			IssuerRef:  *issuerRef,
+			CommonName: es.Name + "-" + string(certificates.TransportCAType),
+			IsCA:       true,
+			Rotation:   rotationParams,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if issuedSecret == nil {
+			// the dynamic watch on the issued Secret triggers a new reconciliation once cert-manager has issued the CA
+			return nil, fmt.Errorf("transport CA %s/%s not issued by cert-manager yet", esNSN.Namespace, issuedSecretName)
+		}
+		ca, err := certificates.ParseIssuedCASecret(*issuedSecret)
+		if err != nil {
+			driver.Recorder().Eventf(&es, corev1.EventTypeWarning, events.EventReasonValidation, err.Error())
+			return nil, err
+		}
+		return ca, nil
+	}
+	// garbage collect the CA previously issued by cert-manager, if any
+	if err := certificates.DeleteCertManagerCertificate(ctx, driver.K8sClient(), esNSN.Namespace, issuedSecretName); err != nil {
+		return nil, err
+	}
+
 	customCASecret, err := certificates.GetSecretFromRef(driver.K8sClient(), esNSN, es.Spec.Transport.TLS.Certificate)
 	if err != nil {
 		// error should already contain enough context including the name of the secret
@@ -60,7 +96,18 @@ func ReconcileOrRetrieveCA(
 			return globalCA, nil
 		}
 
-		return certificates.ReconcileCAForOwner(
+		deleted, err := isCASecretDeleted(ctx, driver.K8sClient(), es)
+		if err != nil {
+			return nil, err
+		}
+		if deleted {
+			// the private key of the former CA is lost: keep trusting its certificate while the certificates of the nodes
+			// are re-issued by the new CA, so that the nodes can keep communicating
+			if err := savePreviousCA(ctx, driver.K8sClient(), es); err != nil {
+				return nil, err
+			}
+		}
+		ca, err := certificates.ReconcileCAForOwner(
 			ctx,
 			driver.K8sClient(),
 			esv1.ESNamer,
@@ -69,6 +116,12 @@ func ReconcileOrRetrieveCA(
 			certificates.TransportCAType,
 			rotationParams,
 		)
+		if err == nil && deleted {
+			driver.Recorder().Eventf(&es, corev1.EventTypeWarning, events.EventReasonRestored,
+				"Transport CA Secret %s was deleted, re-issuing the transport certificates of all nodes with a new CA while trusting the former CA",
+				certificates.CAInternalSecretName(esv1.ESNamer, es.Name, certificates.TransportCAType))
+		}
+		return ca, err
 	}
 
 	// 2. Assuming from here on the user wants to use custom certs and has configured a secret with them.
@@ -98,3 +151,20 @@ func ReconcileOrRetrieveCA(
 
 	return ca, nil
 }
+
+// isCASecretDeleted returns true if the Secret of the self-signed transport CA does not exist although the public
+// transport certificates Secret, which is derived from the CA, does.
+func isCASecretDeleted(ctx context.Context, c k8s.Client, es esv1.Elasticsearch) (bool, error) {
+	caSecretRef := types.NamespacedName{
+		Namespace: es.Namespace,
+		Name:      certificates.CAInternalSecretName(esv1.ESNamer, es.Name, certificates.TransportCAType),
+	}
+	if err := c.Get(ctx, caSecretRef, &corev1.Secret{}); err == nil || !apierrors.IsNotFound(err) {
+		return false, err
+	}
+	err := c.Get(ctx, PublicCertsSecretRef(k8s.ExtractNamespacedName(&es)), &corev1.Secret{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
@@ -0,0 +1,92 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package transport
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/driver"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func TestReconcileOrRetrieveCA_CertManager(t *testing.T) {
+	es := *testES.DeepCopy()
+	es.Spec.Transport.TLS.IssuerRef = &commonv1.CertManagerIssuerRef{Name: "ca-issuer", Kind: "ClusterIssuer"}
+	c := k8s.NewFakeClient(&es)
+	d := driver.TestDriver{Client: c, Watches: watches.NewDynamicWatches(), FakeRecorder: record.NewFakeRecorder(10)}
+	issuedSecretName := "test-es-name-es-transport-certs-issued"
+
+	// the CA has not been issued yet
+	_, err := ReconcileOrRetrieveCA(context.Background(), d, es, nil, nil, certificates.RotationParams{})
+	require.ErrorContains(t, err, "not issued by cert-manager yet")
+	require.Contains(t, d.Watches.Secrets.Registrations(), CustomTransportCertsWatchKey(k8s.ExtractNamespacedName(&es)))
+
+	// cert-manager issues the CA
+	require.NoError(t, c.Create(context.Background(), &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: issuedSecretName},
+		Data: map[string][]byte{
+			certificates.CAFileName:   extraCA,
+			certificates.CertFileName: testRSACABytes,
+			certificates.KeyFileName:  testRSAPEMPrivateKey,
+		},
+	}))
+	ca, err := ReconcileOrRetrieveCA(context.Background(), d, es, nil, nil, certificates.RotationParams{})
+	require.NoError(t, err)
+	require.Equal(t, testRSACA.Cert.Raw, ca.Cert.Raw)
+
+	// going back to a self-signed CA removes the issued CA
+	es.Spec.Transport.TLS.IssuerRef = nil
+	ca, err = ReconcileOrRetrieveCA(context.Background(), d, es, nil, nil, certificates.RotationParams{Validity: certificates.DefaultCertValidity})
+	require.NoError(t, err)
+	require.NotEqual(t, testRSACA.Cert.Raw, ca.Cert.Raw)
+	err = c.Get(context.Background(), types.NamespacedName{Namespace: testNamespace, Name: issuedSecretName}, &corev1.Secret{})
+	require.True(t, apierrors.IsNotFound(err))
+}
+
+func TestReconcileOrRetrieveCA_Deleted(t *testing.T) {
+	es := *testES.DeepCopy()
+	c := k8s.NewFakeClient(&es)
+	recorder := record.NewFakeRecorder(10)
+	d := driver.TestDriver{Client: c, Watches: watches.NewDynamicWatches(), FakeRecorder: recorder}
+	rotation := certificates.RotationParams{Validity: certificates.DefaultCertValidity, RotateBefore: certificates.DefaultRotateBefore}
+
+	// initial creation of the CA
+	ca, err := ReconcileOrRetrieveCA(context.Background(), d, es, nil, nil, rotation)
+	require.NoError(t, err)
+	require.NoError(t, ReconcileTransportCertsPublicSecret(context.Background(), c, es, ca, nil))
+	require.Empty(t, recorder.Events)
+
+	// the CA is reused
+	reused, err := ReconcileOrRetrieveCA(context.Background(), d, es, nil, nil, rotation)
+	require.NoError(t, err)
+	require.Equal(t, ca.Cert.Raw, reused.Cert.Raw)
+	require.Empty(t, recorder.Events)
+
+	// the CA Secret is deleted: a new CA is generated and reported
+	require.NoError(t, c.Delete(context.Background(), &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Namespace: testNamespace,
+		Name:      "test-es-name-es-transport-ca-internal",
+	}}))
+	renewed, err := ReconcileOrRetrieveCA(context.Background(), d, es, nil, nil, rotation)
+	require.NoError(t, err)
+	require.NotEqual(t, ca.Cert.Raw, renewed.Cert.Raw)
+	require.Len(t, recorder.Events, 1)
+	// the former CA remains trusted during the rollover
+	previous, err := GetPreviousCA(context.Background(), c, es)
+	require.NoError(t, err)
+	require.NotNil(t, previous)
+	require.Equal(t, ca.Cert.Raw, previous.Cert.Raw)
+}
@@ -0,0 +1,32 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package transport
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
+	esvolume "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
+)
+
+// podSecretChunk returns the index of the Secret chunk holding the transport certificate of the given Pod.
+// Pods which only mount the first chunk have their certificate stored in it.
+func podSecretChunk(pod corev1.Pod) int {
+	return secretChunk(pod.Name, esvolume.HasChunkedTransportCertificates(pod.Spec))
+}
+
+// secretChunk returns the index of the Secret chunk holding the transport certificate of the Pod with the given name,
+// depending on whether the Pod mounts all the chunks or only the first one.
+func secretChunk(podName string, chunked bool) int {
+	if !chunked {
+		return 0
+	}
+	_, ordinal, err := sset.StatefulSetName(podName)
+	if err != nil {
+		return 0
+	}
+	return int(ordinal) % esv1.TransportCertificatesSecretChunks
+}
@@ -67,13 +67,18 @@ func createValidatedCertificateTemplate(
 	return &certificateTemplate, nil
 }
 
+// buildGeneralNames returns the subject alternative names of the transport certificate of the given Pod. The IP
+// addresses are omitted for Pods which have no IP yet: their certificate is re-issued once the Pod has an IP.
 func buildGeneralNames(
 	cluster esv1.Elasticsearch,
 	pod corev1.Pod,
 ) ([]certificates.GeneralName, error) {
-	podIP := net.ParseIP(pod.Status.PodIP)
-	if podIP == nil {
-		return nil, errors.Errorf("pod currently has no valid IP, found: [%s]", pod.Status.PodIP)
+	var podIP net.IP
+	if pod.Status.PodIP != "" {
+		podIP = net.ParseIP(pod.Status.PodIP)
+		if podIP == nil {
+			return nil, errors.Errorf("pod currently has no valid IP, found: [%s]", pod.Status.PodIP)
+		}
 	}
 
 	ssetName := pod.Labels[label.StatefulSetNameLabelName]
@@ -100,8 +105,24 @@ func buildGeneralNames(
 		{DNSName: fmt.Sprintf("%s.%s.svc", esv1.TransportService(cluster.Name), cluster.Namespace)},
 		// add the resolvable DNS name of the Pod as published by Elasticsearch
 		{DNSName: fmt.Sprintf("%s.%s", pod.Name, svcName)},
-		{IPAddress: netutil.IPToRFCForm(podIP)},
-		{IPAddress: netutil.IPToRFCForm(netutil.LoopbackFor(netutil.ToIPFamily(podIP.String())))},
+	}
+	if podIP != nil {
+		generalNames = append(generalNames,
+			certificates.GeneralName{IPAddress: netutil.IPToRFCForm(podIP)},
+			certificates.GeneralName{IPAddress: netutil.IPToRFCForm(netutil.LoopbackFor(netutil.ToIPFamily(podIP.String())))},
+		)
+	}
+
+	// on dual-stack clusters Elasticsearch may publish an address of the other IP family than the primary Pod IP
+	for _, ip := range pod.Status.PodIPs {
+		secondaryIP := net.ParseIP(ip.IP)
+		if podIP == nil || secondaryIP == nil || secondaryIP.Equal(podIP) {
+			continue
+		}
+		generalNames = append(generalNames,
+			certificates.GeneralName{IPAddress: netutil.IPToRFCForm(secondaryIP)},
+			certificates.GeneralName{IPAddress: netutil.IPToRFCForm(netutil.LoopbackFor(netutil.ToIPFamily(secondaryIP.String())))},
+		)
 	}
 
 	if cluster.Spec.RemoteClusterServer.Enabled {
@@ -180,6 +180,33 @@ func Test_buildGeneralNames(t *testing.T) {
 				{DNSName: "my-custom-domain"},
 			}...),
 		},
+		{
+			name: "Pod without IP yet",
+			args: args{
+				cluster: testES,
+				pod: func() corev1.Pod {
+					pod := *testPod.DeepCopy()
+					pod.Status.PodIP = ""
+					return pod
+				}(),
+			},
+			want: expectedGeneralNames[:4],
+		},
+		{
+			name: "dual-stack Pod",
+			args: args{
+				cluster: testES,
+				pod: func() corev1.Pod {
+					pod := *testPod.DeepCopy()
+					pod.Status.PodIPs = []corev1.PodIP{{IP: testIP}, {IP: "fd00::1"}}
+					return pod
+				}(),
+			},
+			want: append(expectedGeneralNames, []certificates.GeneralName{
+				{IPAddress: net.ParseIP("fd00::1")},
+				{IPAddress: net.ParseIP("::1")},
+			}...),
+		},
 		{
 			name: "custom name suffix",
 			args: args{
@@ -39,6 +39,7 @@ func ensureTransportCertificatesSecretContentsForPod(
 	secret *corev1.Secret,
 	pod corev1.Pod,
 	ca *certificates.CA,
+	previousCA *PreviousCA,
 	rotationParams certificates.RotationParams,
 ) error {
 	log := ulog.FromContext(ctx)
@@ -60,7 +61,7 @@ func ensureTransportCertificatesSecretContentsForPod(
 		secret.Data[PodKeyFileName(pod.Name)] = pemPrivateKey
 	}
 
-	if shouldIssueNewCertificate(ctx, es, *secret, pod, privateKey, ca, rotationParams.RotateBefore) {
+	if shouldIssueNewCertificate(ctx, es, *secret, pod, privateKey, ca, previousCA, rotationParams.RotateBefore) {
 		log.Info(
 			"Issuing new certificate",
 			"pod_name", pod.Name,
@@ -111,6 +112,7 @@ func shouldIssueNewCertificate(
 	pod corev1.Pod,
 	privateKey crypto.Signer,
 	ca *certificates.CA,
+	previousCA *PreviousCA,
 	certReconcileBefore time.Duration,
 ) bool {
 	log := ulog.FromContext(ctx)
@@ -142,6 +144,10 @@ func shouldIssueNewCertificate(
 
 	pool := x509.NewCertPool()
 	pool.AddCert(ca.Cert)
+	if previousCA.reuseCertificates(time.Now()) {
+		// some nodes may not trust the new CA yet
+		pool.AddCert(previousCA.Cert)
+	}
 	verifyOpts := x509.VerifyOptions{
 		DNSName:       certCommonName,
 		Roots:         pool,
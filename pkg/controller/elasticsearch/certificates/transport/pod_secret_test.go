@@ -99,6 +99,7 @@ func Test_shouldIssueNewCertificate(t *testing.T) {
 				*tt.args.pod,
 				testRSAPrivateKey,
 				testRSACA,
+				nil,
 				tt.args.rotateBefore,
 			); got != tt.want {
 				t.Errorf("shouldIssueNewCertificate() = %v, want %v", got, tt.want)
@@ -232,6 +233,7 @@ func Test_ensureTransportCertificatesSecretContentsForPod(t *testing.T) {
 				tt.secret,
 				*tt.pod,
 				testRSACA,
+				nil,
 				certificates.RotationParams{
 					Validity:     certificates.DefaultCertValidity,
 					RotateBefore: certificates.DefaultRotateBefore,
@@ -0,0 +1,128 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package transport
+
+import (
+	"context"
+	"crypto/x509"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// PreviousCAGracePeriod is the time given to the kubelets and to Elasticsearch to load an updated transport
+// certificates Secret. When the transport CA Secret is deleted, the certificates of the nodes are only re-issued by the
+// new CA once all the nodes had the time to trust the new CA, and the former CA is only removed from the trusted CAs
+// once all the nodes had the time to load their new certificate, so that the nodes can communicate during the rollover.
+var PreviousCAGracePeriod = 5 * time.Minute
+
+// PreviousCASecretRef returns the reference to the Secret holding the certificate of the former self-signed transport
+// CA, which exists while the certificates of the nodes are re-issued by a new CA after the CA Secret was deleted.
+func PreviousCASecretRef(es types.NamespacedName) types.NamespacedName {
+	return types.NamespacedName{
+		Namespace: es.Namespace,
+		Name:      esv1.ESNamer.Suffix(es.Name, string(certificates.TransportCAType), "ca-previous"),
+	}
+}
+
+// PreviousCA is the former self-signed transport CA, whose private key has been lost with the CA Secret. It remains
+// trusted by the nodes while their certificates are re-issued by the new CA.
+type PreviousCA struct {
+	Cert *x509.Certificate
+	// Since is the time at which the CA was replaced.
+	Since time.Time
+}
+
+// reuseCertificates returns true if the certificates issued by the previous CA must not be re-issued yet, because
+// some nodes may not trust the new CA yet.
+func (p *PreviousCA) reuseCertificates(now time.Time) bool {
+	return p != nil && now.Before(p.Since.Add(PreviousCAGracePeriod))
+}
+
+// expired returns true if all the nodes had the time to load a certificate issued by the new CA.
+func (p *PreviousCA) expired(now time.Time) bool {
+	return p != nil && now.After(p.Since.Add(2*PreviousCAGracePeriod))
+}
+
+// RequeueAfter returns the duration after which the rollover to the new CA moves to its next phase.
+func (p *PreviousCA) RequeueAfter(now time.Time) time.Duration {
+	if p.reuseCertificates(now) {
+		return p.Since.Add(PreviousCAGracePeriod).Sub(now)
+	}
+	return max(p.Since.Add(2*PreviousCAGracePeriod).Sub(now), time.Second)
+}
+
+// PEM returns the PEM encoded certificate of the previous CA, to be appended to the trusted CAs, or nil if there is
+// no previous CA.
+func (p *PreviousCA) PEM() []byte {
+	if p == nil {
+		return nil
+	}
+	return certificates.EncodePEMCert(p.Cert.Raw)
+}
+
+// savePreviousCA persists the certificate of the CA currently trusted by the nodes, read from the transport public
+// certificates Secret, before a new CA replaces the one whose Secret was deleted.
+func savePreviousCA(ctx context.Context, c k8s.Client, es esv1.Elasticsearch) error {
+	var publicSecret corev1.Secret
+	if err := c.Get(ctx, PublicCertsSecretRef(k8s.ExtractNamespacedName(&es)), &publicSecret); err != nil {
+		return err
+	}
+	certs, err := certificates.ParsePEMCerts(publicSecret.Data[certificates.CAFileName])
+	if err != nil || len(certs) == 0 {
+		// nothing to keep trusting, the nodes cannot communicate until they have all loaded their new certificate
+		ulog.FromContext(ctx).Info("Cannot retrieve the former transport CA certificate",
+			"namespace", es.Namespace, "es_name", es.Name, "error", err)
+		return nil
+	}
+	nsn := PreviousCASecretRef(k8s.ExtractNamespacedName(&es))
+	expected := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: nsn.Namespace,
+			Name:      nsn.Name,
+			Labels:    label.NewLabels(k8s.ExtractNamespacedName(&es)),
+		},
+		// the operator CA is always the first certificate of the bundle, followed by the additional trusted CAs
+		Data: map[string][]byte{certificates.CAFileName: certificates.EncodePEMCert(certs[0].Raw)},
+	}
+	_, err = reconciler.ReconcileSecret(ctx, c, expected, &es)
+	return err
+}
+
+// GetPreviousCA returns the former transport CA of the given cluster if the nodes are being rolled over to a new CA,
+// or nil otherwise.
+func GetPreviousCA(ctx context.Context, c k8s.Client, es esv1.Elasticsearch) (*PreviousCA, error) {
+	var secret corev1.Secret
+	if err := c.Get(ctx, PreviousCASecretRef(k8s.ExtractNamespacedName(&es)), &secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	certs, err := certificates.ParsePEMCerts(secret.Data[certificates.CAFileName])
+	if err != nil || len(certs) == 0 {
+		return nil, k8s.DeleteSecretIfExists(ctx, c, k8s.ExtractNamespacedName(&secret))
+	}
+	return &PreviousCA{Cert: certs[0], Since: secret.CreationTimestamp.Time}, nil
+}
+
+// GarbageCollectPreviousCA deletes the former transport CA once all the nodes had the time to load the certificates
+// issued by the new CA. It returns true if the previous CA has been deleted.
+func GarbageCollectPreviousCA(ctx context.Context, c k8s.Client, es esv1.Elasticsearch, previous *PreviousCA, allReissued bool) (bool, error) {
+	if !allReissued || !previous.expired(time.Now()) {
+		return false, nil
+	}
+	return true, k8s.DeleteSecretIfExists(ctx, c, PreviousCASecretRef(k8s.ExtractNamespacedName(&es)))
+}
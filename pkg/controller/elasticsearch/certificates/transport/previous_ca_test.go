@@ -0,0 +1,96 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package transport
+
+import (
+	"context"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func TestPreviousCA_rollover(t *testing.T) {
+	formerCA, err := certificates.NewSelfSignedCA(certificates.CABuilderOptions{})
+	require.NoError(t, err)
+	rotation := certificates.RotationParams{Validity: certificates.DefaultCertValidity, RotateBefore: certificates.DefaultRotateBefore}
+	ctx := context.Background()
+
+	// the Pod holds a certificate issued by the former CA
+	secret := &corev1.Secret{Data: map[string][]byte{}}
+	require.NoError(t, ensureTransportCertificatesSecretContentsForPod(ctx, testES, secret, testPod, formerCA, nil, rotation))
+	formerCert := secret.Data[PodCertFileName(testPod.Name)]
+
+	// no previous CA: the certificate is immediately re-issued by the new CA
+	s := secret.DeepCopy()
+	require.NoError(t, ensureTransportCertificatesSecretContentsForPod(ctx, testES, s, testPod, testRSACA, nil, rotation))
+	require.NotEqual(t, formerCert, s.Data[PodCertFileName(testPod.Name)])
+
+	// during the grace period, the certificate issued by the former CA is kept, as some nodes may not trust the new CA yet
+	previous := &PreviousCA{Cert: formerCA.Cert, Since: time.Now()}
+	s = secret.DeepCopy()
+	require.NoError(t, ensureTransportCertificatesSecretContentsForPod(ctx, testES, s, testPod, testRSACA, previous, rotation))
+	require.Equal(t, formerCert, s.Data[PodCertFileName(testPod.Name)])
+	require.False(t, previous.expired(time.Now()))
+	require.InDelta(t, PreviousCAGracePeriod, previous.RequeueAfter(time.Now()), float64(time.Second))
+
+	// after the grace period, the certificate is re-issued by the new CA
+	previous.Since = time.Now().Add(-PreviousCAGracePeriod - time.Minute)
+	s = secret.DeepCopy()
+	require.NoError(t, ensureTransportCertificatesSecretContentsForPod(ctx, testES, s, testPod, testRSACA, previous, rotation))
+	certs, err := certificates.ParsePEMCerts(s.Data[PodCertFileName(testPod.Name)])
+	require.NoError(t, err)
+	roots := x509.NewCertPool()
+	roots.AddCert(testRSACA.Cert)
+	_, err = certs[0].Verify(x509.VerifyOptions{Roots: roots})
+	require.NoError(t, err)
+	require.False(t, previous.expired(time.Now()))
+
+	// the former CA can be forgotten once all the nodes had the time to load their new certificate
+	previous.Since = time.Now().Add(-2*PreviousCAGracePeriod - time.Minute)
+	require.True(t, previous.expired(time.Now()))
+	require.Equal(t, time.Second, previous.RequeueAfter(time.Now()))
+}
+
+func TestGarbageCollectPreviousCA(t *testing.T) {
+	es := *testES.DeepCopy()
+	nsn := PreviousCASecretRef(k8s.ExtractNamespacedName(&es))
+	secret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: nsn.Namespace, Name: nsn.Name, CreationTimestamp: metav1.Now()},
+		Data:       map[string][]byte{certificates.CAFileName: certificates.EncodePEMCert(testRSACA.Cert.Raw)},
+	}
+	c := k8s.NewFakeClient(&secret)
+	ctx := context.Background()
+
+	previous, err := GetPreviousCA(ctx, c, es)
+	require.NoError(t, err)
+	require.Equal(t, testRSACA.Cert.Raw, previous.Cert.Raw)
+
+	// still in the grace period
+	deleted, err := GarbageCollectPreviousCA(ctx, c, es, previous, true)
+	require.NoError(t, err)
+	require.False(t, deleted)
+
+	// some certificates have not been re-issued yet
+	previous.Since = time.Now().Add(-3 * PreviousCAGracePeriod)
+	deleted, err = GarbageCollectPreviousCA(ctx, c, es, previous, false)
+	require.NoError(t, err)
+	require.False(t, deleted)
+
+	deleted, err = GarbageCollectPreviousCA(ctx, c, es, previous, true)
+	require.NoError(t, err)
+	require.True(t, deleted)
+	require.True(t, apierrors.IsNotFound(c.Get(ctx, nsn, &corev1.Secret{})))
+	previous, err = GetPreviousCA(ctx, c, es)
+	require.NoError(t, err)
+	require.Nil(t, previous)
+}
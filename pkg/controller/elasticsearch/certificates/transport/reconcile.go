@@ -8,20 +8,26 @@ import (
 	"bytes"
 	"context"
 	"reflect"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/annotation"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/statefulset"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/nodespec"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
@@ -29,12 +35,14 @@ import (
 )
 
 // ReconcileTransportCertificatesSecrets reconciles the secret containing transport certificates for all nodes in the
-// cluster.
+// cluster. Certificates issued by the previous CA, if any, are kept until all the nodes had the time to trust the new CA.
 // Secrets which are not used anymore are deleted as part of the downscale process.
 func ReconcileTransportCertificatesSecrets(
 	ctx context.Context,
 	c k8s.Client,
+	recorder record.EventRecorder,
 	ca *certificates.CA,
+	previousCA *PreviousCA,
 	additionalCAs []byte,
 	es esv1.Elasticsearch,
 	rotationParams certificates.RotationParams,
@@ -53,14 +61,32 @@ func ReconcileTransportCertificatesSecrets(
 		ssets.Add(esv1.StatefulSet(es.Name, nodeSet.Name))
 	}
 
-	for ssetName := range ssets {
-		results.WithResults(reconcileNodeSetTransportCertificatesSecrets(ctx, c, ca, additionalCAs, es, ssetName, rotationParams))
+	// certificates of the different StatefulSets are stored in distinct Secrets and can be issued concurrently
+	ssetNames := ssets.AsSlice()
+	ssetResults := make([]*reconciler.Results, len(ssetNames))
+	var group errgroup.Group
+	group.SetLimit(sset.MaxConcurrentReconciles)
+	for i, ssetName := range ssetNames {
+		group.Go(func() error {
+			ssetResults[i] = reconcileNodeSetTransportCertificatesSecrets(ctx, c, recorder, ca, previousCA, additionalCAs, es, actualStatefulSets, ssetName, rotationParams)
+			return nil
+		})
+	}
+	_ = group.Wait()
+	for _, res := range ssetResults {
+		results.WithResults(res)
 	}
 	return results
 }
 
-// DeleteStatefulSetTransportCertificate removes the Secret which contains the transport certificates of a given Statefulset.
+// DeleteStatefulSetTransportCertificate removes the Secrets which contain the transport certificates of a given Statefulset.
 func DeleteStatefulSetTransportCertificate(ctx context.Context, client k8s.Client, namespace string, ssetName string) error {
+	for chunk := 1; chunk < esv1.TransportCertificatesSecretChunks; chunk++ {
+		nsn := types.NamespacedName{Namespace: namespace, Name: esv1.StatefulSetTransportCertificatesSecretChunk(ssetName, chunk)}
+		if err := k8s.DeleteSecretIfExists(ctx, client, nsn); err != nil {
+			return err
+		}
+	}
 	secret := corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: namespace,
@@ -78,14 +104,17 @@ func DeleteLegacyTransportCertificate(ctx context.Context, client k8s.Client, es
 
 const disabledMarker = "transport.certs.disabled"
 
-// reconcileNodeSetTransportCertificatesSecrets reconciles the secret which contains the transport certificates for
+// reconcileNodeSetTransportCertificatesSecrets reconciles the Secrets which contain the transport certificates for
 // a given StatefulSet.
 func reconcileNodeSetTransportCertificatesSecrets(
 	ctx context.Context,
 	c k8s.Client,
+	recorder record.EventRecorder,
 	ca *certificates.CA,
+	previousCA *PreviousCA,
 	additionalCAs []byte,
 	es esv1.Elasticsearch,
+	actualStatefulSets sset.StatefulSetList,
 	ssetName string,
 	rotationParams certificates.RotationParams,
 ) *reconciler.Results {
@@ -99,29 +128,74 @@ func reconcileNodeSetTransportCertificatesSecrets(
 		return results.WithError(errors.WithStack(err))
 	}
 
-	secret, err := ensureTransportCertificatesSecretExists(ctx, c, es, ssetName)
-	if err != nil {
-		return results.WithError(err)
-	}
-	// defensive copy of the current secret so we can check whether we need to update later on
-	currentTransportCertificatesSecret := secret.DeepCopy()
+	// the first chunk always exists as it holds the CA, the other ones only if they hold the certificate of a Pod
+	podChunks := make(map[string]int, len(pods.Items))
+	neededChunks := make([]bool, esv1.TransportCertificatesSecretChunks)
+	neededChunks[0] = true
 	for _, pod := range pods.Items {
-		if pod.Status.PodIP == "" {
-			log.Info("Skipping pod because it has no IP yet", "namespace", pod.Namespace, "pod_name", pod.Name)
+		chunk := podSecretChunk(pod)
+		podChunks[pod.Name] = chunk
+		neededChunks[chunk] = true
+	}
+	// pre-issue the certificates of the Pods about to be created by a scale up, so that they can start without waiting
+	// for another reconciliation
+	upcoming := upcomingPods(es, ssetName, pods.Items)
+	chunked := nodespec.HasChunkedTransportCertificates(es, actualStatefulSets, ssetName)
+	for _, pod := range upcoming {
+		chunk := secretChunk(pod.Name, chunked)
+		podChunks[pod.Name] = chunk
+		neededChunks[chunk] = true
+	}
+	secrets := make([]*corev1.Secret, esv1.TransportCertificatesSecretChunks)
+	for chunk, needed := range neededChunks {
+		if !needed {
+			// garbage collect the chunks which do not hold any certificate anymore
+			nsn := types.NamespacedName{Namespace: es.Namespace, Name: esv1.StatefulSetTransportCertificatesSecretChunk(ssetName, chunk)}
+			if err := k8s.DeleteSecretIfExists(ctx, c, nsn); err != nil {
+				return results.WithError(err)
+			}
 			continue
 		}
-
+		secret, err := ensureTransportCertificatesSecretExists(ctx, c, es, ssetName, chunk)
+		if err != nil {
+			return results.WithError(err)
+		}
+		secrets[chunk] = secret
+	}
+	if isRecreated(secrets, pods.Items) {
+		// the certificates are re-issued by the existing CA below and hot-reloaded by Elasticsearch, no restart needed
+		recorder.Eventf(&es, corev1.EventTypeNormal, events.EventReasonRestored,
+			"Transport certificates Secret %s was deleted, re-issuing the certificates of the running Pods", secrets[0].Name)
+	}
+	// defensive copy of the current secrets so we can check whether we need to update later on
+	currentSecrets := make([]*corev1.Secret, esv1.TransportCertificatesSecretChunks)
+	for chunk, secret := range secrets {
+		if secret != nil {
+			currentSecrets[chunk] = secret.DeepCopy()
+		}
+	}
+	budget := reconciler.BudgetFromContext(ctx)
+	// Pods without an IP yet get a certificate without any IP address, re-issued once the Pod has an IP
+	for _, pod := range slices.Concat(pods.Items, upcoming) {
+		secret := secrets[podChunks[pod.Name]]
 		if _, disabled := pod.Annotations[esv1.TransportCertDisabledAnnotationName]; disabled {
 			delete(secret.Data, PodCertFileName(pod.Name))
 			delete(secret.Data, PodKeyFileName(pod.Name))
 			continue
 		}
 
+		issuedCert := secret.Data[PodCertFileName(pod.Name)]
 		if err := ensureTransportCertificatesSecretContentsForPod(
-			ctx, es, secret, pod, ca, rotationParams,
+			ctx, es, secret, pod, ca, previousCA, rotationParams,
 		); err != nil {
 			return results.WithError(err)
 		}
+		if !bytes.Equal(issuedCert, secret.Data[PodCertFileName(pod.Name)]) && budget.Exhausted() {
+			// Only yield once a certificate has been issued, so that each reconciliation makes progress. The certificates
+			// issued so far are persisted below, the next reconciliation resumes with the remaining Pods.
+			results.WithReconciliationState(reconciler.BudgetExhausted)
+			break
+		}
 		certCommonName := buildCertificateCommonName(pod, es)
 		cert := extractTransportCert(ctx, *secret, pod, certCommonName)
 		if cert == nil {
@@ -135,46 +209,58 @@ func reconcileNodeSetTransportCertificatesSecrets(
 		)
 	}
 
-	// remove certificates and keys for deleted pods
-	podsByName := k8s.PodsByName(pods.Items)
-	keysToPrune := make([]string, 0)
-	for secretDataKey := range secret.Data {
-		if secretDataKey == certificates.CAFileName {
-			// never remove the CA file
+	// remove certificates and keys for deleted pods, or for pods which now use another chunk
+	for chunk, secret := range secrets {
+		if secret == nil {
 			continue
 		}
+		keysToPrune := make([]string, 0)
+		for secretDataKey := range secret.Data {
+			if secretDataKey == certificates.CAFileName {
+				// never remove the CA file
+				continue
+			}
 
-		// get the pod name from the secret key name (the first segment before the ".")
-		podNameForKey := strings.SplitN(secretDataKey, ".", 2)[0]
+			// get the pod name from the secret key name (the first segment before the ".")
+			podNameForKey := strings.SplitN(secretDataKey, ".", 2)[0]
 
-		if _, ok := podsByName[podNameForKey]; !ok {
-			// pod no longer exists, so the element is safe to delete.
-			keysToPrune = append(keysToPrune, secretDataKey)
+			if podChunk, ok := podChunks[podNameForKey]; !ok || podChunk != chunk {
+				// pod no longer exists or its certificate is held by another chunk, so the element is safe to delete.
+				keysToPrune = append(keysToPrune, secretDataKey)
+			}
 		}
-	}
-	if len(keysToPrune) > 0 {
-		log.Info("Pruning keys from certificates secret", "namespace", es.Namespace, "secret_name", secret.Name, "keys", keysToPrune)
+		if len(keysToPrune) > 0 {
+			log.Info("Pruning keys from certificates secret", "namespace", es.Namespace, "secret_name", secret.Name, "keys", keysToPrune)
 
-		for _, keyToRemove := range keysToPrune {
-			delete(secret.Data, keyToRemove)
+			for _, keyToRemove := range keysToPrune {
+				delete(secret.Data, keyToRemove)
+			}
 		}
 	}
 
 	if es.Spec.Transport.TLS.SelfSignedEnabled() {
-		delete(secret.Data, disabledMarker)
+		delete(secrets[0].Data, disabledMarker)
 	} else {
 		// add a marker but leave all the old certs that might exist in the secret in place to ease the transition
 		// to the disabled state.
-		secret.Data[disabledMarker] = []byte("true") // contents is irrelevant
+		secrets[0].Data[disabledMarker] = []byte("true") // contents is irrelevant
 	}
 
-	mayBeUpdateCAFile(secret, ca, additionalCAs)
+	mayBeUpdateCAFile(secrets[0], ca, additionalCAs, holdsPodCertificates(secrets[1:]))
 
-	if !reflect.DeepEqual(secret, currentTransportCertificatesSecret) {
+	updatedChunks := make([]bool, esv1.TransportCertificatesSecretChunks)
+	for chunk, secret := range secrets {
+		if secret == nil || reflect.DeepEqual(secret, currentSecrets[chunk]) {
+			continue
+		}
 		if err := c.Update(ctx, secret); err != nil {
 			return results.WithError(err)
 		}
-		for _, pod := range pods.Items {
+		updatedChunks[chunk] = true
+	}
+	for _, pod := range pods.Items {
+		// the first chunk holds the CA which is used by all the Pods
+		if updatedChunks[0] || updatedChunks[podChunks[pod.Name]] {
 			annotation.MarkPodAsUpdated(ctx, c, pod)
 		}
 	}
@@ -182,13 +268,73 @@ func reconcileNodeSetTransportCertificatesSecrets(
 	return results
 }
 
-func mayBeUpdateCAFile(secret *corev1.Secret, ca *certificates.CA, additionalCAs []byte) {
+// upcomingPods returns placeholders for the Pods of the given StatefulSet which are expected from the specification
+// but do not exist yet. Their names are predictable, which allows issuing their transport certificates before they are
+// created.
+func upcomingPods(es esv1.Elasticsearch, ssetName string, existingPods []corev1.Pod) []corev1.Pod {
+	if !es.Spec.Transport.TLS.SelfSignedEnabled() {
+		return nil
+	}
+	var count int32
+	for _, nodeSet := range es.Spec.NodeSets {
+		if esv1.StatefulSet(es.Name, nodeSet.Name) == ssetName {
+			count = nodeSet.Count
+		}
+	}
+	existing := make(map[string]struct{}, len(existingPods))
+	for _, pod := range existingPods {
+		existing[pod.Name] = struct{}{}
+	}
+	var upcoming []corev1.Pod
+	for ordinal := int32(0); ordinal < count; ordinal++ {
+		name := statefulset.PodName(ssetName, ordinal)
+		if _, exists := existing[name]; exists {
+			continue
+		}
+		upcoming = append(upcoming, corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: es.Namespace,
+				Name:      name,
+				Labels:    map[string]string{label.StatefulSetNameLabelName: ssetName},
+			},
+		})
+	}
+	return upcoming
+}
+
+// holdsPodCertificates returns true if one of the given transport certificates Secrets is not empty.
+func holdsPodCertificates(secrets []*corev1.Secret) bool {
+	for _, secret := range secrets {
+		if secret != nil && len(secret.Data) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// isRecreated returns true if the given transport certificates Secrets do not hold any certificate although some Pods
+// are ready, which can only happen if the Secrets were deleted while the Pods were running.
+func isRecreated(secrets []*corev1.Secret, pods []corev1.Pod) bool {
+	if holdsPodCertificates(secrets) {
+		return false
+	}
+	for _, pod := range pods {
+		if k8s.IsPodReady(pod) {
+			return true
+		}
+	}
+	return false
+}
+
+// mayBeUpdateCAFile updates the CA file of the first transport certificates Secret chunk. otherChunksInUse indicates
+// whether the other chunks hold Pod certificates.
+func mayBeUpdateCAFile(secret *corev1.Secret, ca *certificates.CA, additionalCAs []byte, otherChunksInUse bool) {
 	var cas [][]byte
 
 	// if the secret contains only the marker file (and maybe an old CA) transport certs are disabled
 	// and no pod uses them anymore => we don't need the CA
 	_, transportCertsDisabled := secret.Data[disabledMarker]
-	secretContainsMarkerAndCAFile := len(secret.Data) <= 2 && transportCertsDisabled
+	secretContainsMarkerAndCAFile := len(secret.Data) <= 2 && transportCertsDisabled && !otherChunksInUse
 
 	if !secretContainsMarkerAndCAFile {
 		cas = append(cas, certificates.EncodePEMCert(ca.Cert.Raw))
@@ -209,18 +355,19 @@ func mayBeUpdateCAFile(secret *corev1.Secret, ca *certificates.CA, additionalCAs
 	}
 }
 
-// ensureTransportCertificatesSecretExists ensures the existence and labels of the Secret that at a later point
-// in time will contain the transport certificates for a nodeSet.
+// ensureTransportCertificatesSecretExists ensures the existence and labels of the Secret chunk that at a later point
+// in time will contain the transport certificates for some Pods of a nodeSet.
 func ensureTransportCertificatesSecretExists(
 	ctx context.Context,
 	c k8s.Client,
 	es esv1.Elasticsearch,
 	ssetName string,
+	chunk int,
 ) (*corev1.Secret, error) {
 	expected := corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: es.Namespace,
-			Name:      esv1.StatefulSetTransportCertificatesSecret(ssetName),
+			Name:      esv1.StatefulSetTransportCertificatesSecretChunk(ssetName, chunk),
 			Labels: map[string]string{
 				// a label showing which es these certificates belongs to
 				label.ClusterNameLabelName: es.Name,
@@ -7,7 +7,10 @@ package transport
 import (
 	"bytes"
 	"context"
+	"fmt"
+	"net"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -15,11 +18,13 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/comparison"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 )
@@ -149,7 +154,7 @@ func TestReconcileTransportCertificatesSecrets(t *testing.T) {
 			name: "Should remove any non used transport certs",
 			args: args{
 				ca: testRSACA,
-				es: newEsBuilder().addNodeSet("sset1", 2).addNodeSet("sset2", 2).build(),
+				es: newEsBuilder().addNodeSet("sset1", 1).addNodeSet("sset2", 2).build(),
 				initialObjects: []client.Object{
 					newPodBuilder().forEs(testEsName).inNodeSet("sset1").withIndex(0).withIP("1.1.1.2").build(),
 					newPodBuilder().forEs(testEsName).inNodeSet("sset2").withIndex(0).withIP("1.1.2.2").build(),
@@ -231,7 +236,7 @@ func TestReconcileTransportCertificatesSecrets(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			k8sClient := k8s.NewFakeClient(tt.args.initialObjects...)
-			got := ReconcileTransportCertificatesSecrets(context.Background(), k8sClient, tt.args.ca, tt.args.extraCA, *tt.args.es, tt.args.rotationParams)
+			got := ReconcileTransportCertificatesSecrets(context.Background(), k8sClient, record.NewFakeRecorder(10), tt.args.ca, nil, tt.args.extraCA, *tt.args.es, tt.args.rotationParams)
 			require.Equal(t, tt.wantRequeue, got.HasRequeue(), "expected requeue")
 			require.Equal(t, tt.wantErr, got.HasError(), "expected err")
 			// Check Secrets
@@ -244,6 +249,290 @@ func TestReconcileTransportCertificatesSecrets(t *testing.T) {
 	}
 }
 
+func TestReconcileTransportCertificatesSecrets_Recreated(t *testing.T) {
+	es := newEsBuilder().addNodeSet("sset1", 2).build()
+	tests := []struct {
+		name           string
+		initialObjects []client.Object
+		wantEvents     int
+	}{
+		{
+			name: "Pods are not ready yet: initial creation",
+			initialObjects: []client.Object{
+				newPodBuilder().forEs(testEsName).inNodeSet("sset1").withIndex(0).withIP("1.1.1.2").build(),
+				newPodBuilder().forEs(testEsName).inNodeSet("sset1").withIndex(1).withIP("1.1.1.3").build(),
+			},
+			wantEvents: 0,
+		},
+		{
+			name: "Pods are ready and the Secret exists",
+			initialObjects: []client.Object{
+				newPodBuilder().forEs(testEsName).inNodeSet("sset1").withIndex(0).withIP("1.1.1.2").asReady().build(),
+				newPodBuilder().forEs(testEsName).inNodeSet("sset1").withIndex(1).withIP("1.1.1.3").asReady().build(),
+				newtransportCertsSecretBuilder(testEsName, "sset1").forPodIndices(0, 1).build(),
+			},
+			wantEvents: 0,
+		},
+		{
+			name: "Pods are ready but the Secret was deleted",
+			initialObjects: []client.Object{
+				newPodBuilder().forEs(testEsName).inNodeSet("sset1").withIndex(0).withIP("1.1.1.2").asReady().build(),
+				newPodBuilder().forEs(testEsName).inNodeSet("sset1").withIndex(1).withIP("1.1.1.3").asReady().build(),
+			},
+			wantEvents: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sClient := k8s.NewFakeClient(tt.initialObjects...)
+			recorder := record.NewFakeRecorder(10)
+			got := ReconcileTransportCertificatesSecrets(context.Background(), k8sClient, recorder, testRSACA, nil, nil, *es, certificates.RotationParams{
+				Validity:     certificates.DefaultCertValidity,
+				RotateBefore: certificates.DefaultRotateBefore,
+			})
+			require.False(t, got.HasError())
+			require.Len(t, recorder.Events, tt.wantEvents)
+			// certificates are always (re-)issued for the Pods
+			var secret corev1.Secret
+			require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Namespace: testNamespace, Name: "test-es-name-es-sset1-es-transport-certs"}, &secret))
+			require.Contains(t, secret.Data, "test-es-name-es-sset1-0.tls.crt")
+			require.Contains(t, secret.Data, "test-es-name-es-sset1-1.tls.crt")
+		})
+	}
+}
+
+func TestReconcileTransportCertificatesSecrets_BudgetExhausted(t *testing.T) {
+	es := newEsBuilder().addNodeSet("sset1", 2).build()
+	k8sClient := k8s.NewFakeClient(
+		newPodBuilder().forEs(testEsName).inNodeSet("sset1").withIndex(0).withIP("1.1.1.2").build(),
+		newPodBuilder().forEs(testEsName).inNodeSet("sset1").withIndex(1).withIP("1.1.1.3").build(),
+	)
+	rotationParams := certificates.RotationParams{
+		Validity:     certificates.DefaultCertValidity,
+		RotateBefore: certificates.DefaultRotateBefore,
+	}
+
+	// the budget is already exhausted: each reconciliation issues a single certificate before yielding
+	exhausted := func() context.Context {
+		budget := reconciler.NewBudget(time.Nanosecond)
+		time.Sleep(time.Millisecond)
+		return reconciler.WithBudget(context.Background(), budget)
+	}
+	secretRef := types.NamespacedName{Namespace: testNamespace, Name: "test-es-name-es-sset1-es-transport-certs"}
+	got := ReconcileTransportCertificatesSecrets(exhausted(), k8sClient, record.NewFakeRecorder(10), testRSACA, nil, nil, *es, rotationParams)
+	require.False(t, got.HasError())
+	reconciled, reason := got.IsReconciled()
+	require.False(t, reconciled)
+	require.Equal(t, "Reconciliation time budget exhausted, yielding to other resources", reason)
+	var secret corev1.Secret
+	require.NoError(t, k8sClient.Get(context.Background(), secretRef, &secret))
+	require.Contains(t, secret.Data, "test-es-name-es-sset1-0.tls.crt")
+	require.NotContains(t, secret.Data, "test-es-name-es-sset1-1.tls.crt")
+
+	// the next reconciliation resumes with the remaining Pod, even though the budget is still exhausted
+	got = ReconcileTransportCertificatesSecrets(exhausted(), k8sClient, record.NewFakeRecorder(10), testRSACA, nil, nil, *es, rotationParams)
+	require.False(t, got.HasError())
+	require.NoError(t, k8sClient.Get(context.Background(), secretRef, &secret))
+	require.Contains(t, secret.Data, "test-es-name-es-sset1-1.tls.crt")
+
+	// all the certificates are issued, the reconciliation completes without yielding
+	got = ReconcileTransportCertificatesSecrets(exhausted(), k8sClient, record.NewFakeRecorder(10), testRSACA, nil, nil, *es, rotationParams)
+	require.False(t, got.HasError())
+	reconciled, _ = got.IsReconciled()
+	require.True(t, reconciled)
+}
+
+// failingSecretClient fails the creation of the given Secret.
+type failingSecretClient struct {
+	k8s.Client
+	secret string
+}
+
+func (c failingSecretClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if obj.GetName() == c.secret {
+		return errors.NewInternalError(fmt.Errorf("cannot create %s", c.secret))
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func TestReconcileTransportCertificatesSecrets_Concurrency(t *testing.T) {
+	builder := newEsBuilder()
+	var objects []client.Object
+	for i := 0; i < 10; i++ {
+		nodeSet := fmt.Sprintf("sset%d", i)
+		builder = builder.addNodeSet(nodeSet, 1)
+		objects = append(objects, newPodBuilder().forEs(testEsName).inNodeSet(nodeSet).withIndex(0).withIP(fmt.Sprintf("1.1.1.%d", i)).build())
+	}
+	es := builder.build()
+	rotationParams := certificates.RotationParams{
+		Validity:     certificates.DefaultCertValidity,
+		RotateBefore: certificates.DefaultRotateBefore,
+	}
+	// the certificates of a single StatefulSet cannot be persisted
+	k8sClient := failingSecretClient{Client: k8s.NewFakeClient(objects...), secret: "test-es-name-es-sset5-es-transport-certs"}
+
+	got := ReconcileTransportCertificatesSecrets(context.Background(), k8sClient, record.NewFakeRecorder(10), testRSACA, nil, nil, *es, rotationParams)
+	// the error is reported
+	require.True(t, got.HasError())
+	_, err := got.Aggregate()
+	require.ErrorContains(t, err, "cannot create test-es-name-es-sset5-es-transport-certs")
+	// but does not prevent the certificates of the other StatefulSets from being issued
+	for i := 0; i < 10; i++ {
+		var secret corev1.Secret
+		err := k8sClient.Get(context.Background(), types.NamespacedName{Namespace: testNamespace, Name: fmt.Sprintf("test-es-name-es-sset%d-es-transport-certs", i)}, &secret)
+		if i == 5 {
+			require.True(t, errors.IsNotFound(err))
+			continue
+		}
+		require.NoError(t, err)
+		require.Contains(t, secret.Data, fmt.Sprintf("test-es-name-es-sset%d-0.tls.crt", i))
+	}
+}
+
+func TestReconcileTransportCertificatesSecrets_Chunked(t *testing.T) {
+	es := newEsBuilder().addNodeSet("sset1", 2).build()
+	rotationParams := certificates.RotationParams{
+		Validity:     certificates.DefaultCertValidity,
+		RotateBefore: certificates.DefaultRotateBefore,
+	}
+	// Pod 0 still mounts the legacy Secret, Pods 1 and 17 mount all the chunks
+	legacySecret := newtransportCertsSecretBuilder(testEsName, "sset1").forPodIndices(0, 1, 2).build()
+	staleChunk := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "test-es-name-es-sset1-es-transport-certs-2"}}
+	k8sClient := k8s.NewFakeClient(
+		newPodBuilder().forEs(testEsName).inNodeSet("sset1").withIndex(0).withIP("1.1.1.2").build(),
+		newPodBuilder().forEs(testEsName).inNodeSet("sset1").withIndex(1).withIP("1.1.1.3").withChunkedSecrets().build(),
+		newPodBuilder().forEs(testEsName).inNodeSet("sset1").withIndex(17).withIP("1.1.1.4").withChunkedSecrets().build(),
+		legacySecret,
+		staleChunk,
+	)
+
+	got := ReconcileTransportCertificatesSecrets(context.Background(), k8sClient, record.NewFakeRecorder(10), testRSACA, nil, nil, *es, rotationParams)
+	require.False(t, got.HasError())
+
+	var secrets corev1.SecretList
+	require.NoError(t, k8sClient.List(context.Background(), &secrets))
+	require.Len(t, secrets.Items, 2)
+	// the first chunk holds the CA and the certificate of the Pod which still mounts the legacy Secret
+	firstChunk := getSecret(secrets, "test-es-name-es-sset1-es-transport-certs")
+	require.NotNil(t, firstChunk)
+	require.Len(t, firstChunk.Data, 3)
+	require.Equal(t, testRSACABytes, firstChunk.Data["ca.crt"])
+	require.Contains(t, firstChunk.Data, "test-es-name-es-sset1-0.tls.crt")
+	// Pods 1 and 17 share the same chunk, the certificates of Pod 2 which does not exist anymore are removed
+	secondChunk := getSecret(secrets, "test-es-name-es-sset1-es-transport-certs-1")
+	require.NotNil(t, secondChunk)
+	require.Len(t, secondChunk.Data, 4)
+	require.Contains(t, secondChunk.Data, "test-es-name-es-sset1-1.tls.crt")
+	require.Contains(t, secondChunk.Data, "test-es-name-es-sset1-17.tls.crt")
+	require.Equal(t, testEsName, secondChunk.Labels[label.ClusterNameLabelName])
+	require.Equal(t, "test-es-name-es-sset1", secondChunk.Labels[label.StatefulSetNameLabelName])
+}
+
+func TestReconcileTransportCertificatesSecrets_UpcomingPods(t *testing.T) {
+	es := newEsBuilder().addNodeSet("sset1", 3).build()
+	rotationParams := certificates.RotationParams{
+		Validity:     certificates.DefaultCertValidity,
+		RotateBefore: certificates.DefaultRotateBefore,
+	}
+	// the StatefulSet mounts a single Secret and is being scaled up from 1 to 3 nodes, Pod 1 has no IP yet
+	pod1 := newPodBuilder().forEs(testEsName).inNodeSet("sset1").withIndex(1).build()
+	k8sClient := k8s.NewFakeClient(
+		newStatefulSet(testEsName, "test-es-name-es-sset1"),
+		newPodBuilder().forEs(testEsName).inNodeSet("sset1").withIndex(0).withIP("1.1.1.2").build(),
+		pod1,
+	)
+
+	got := ReconcileTransportCertificatesSecrets(context.Background(), k8sClient, record.NewFakeRecorder(10), testRSACA, nil, nil, *es, rotationParams)
+	require.False(t, got.HasError())
+	var secrets corev1.SecretList
+	require.NoError(t, k8sClient.List(context.Background(), &secrets))
+	require.Len(t, secrets.Items, 1)
+	// the certificates of the Pods without IP and of the Pod not created yet are pre-issued without any IP address
+	secret := secrets.Items[0]
+	require.Len(t, secret.Data, 7)
+	for _, podName := range []string{"test-es-name-es-sset1-0", "test-es-name-es-sset1-1", "test-es-name-es-sset1-2"} {
+		require.Contains(t, secret.Data, PodKeyFileName(podName))
+	}
+	upcomingCert := extractTransportCert(context.Background(), secret, corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-es-name-es-sset1-2"}},
+		"test-es-name-es-sset1-2.node.test-es-name.test-namespace.es.local")
+	require.NotNil(t, upcomingCert)
+	require.Empty(t, upcomingCert.IPAddresses)
+	require.Contains(t, upcomingCert.DNSNames, "test-es-name-es-sset1-2.test-es-name-es-sset1")
+
+	// the certificate is re-issued once the Pod has an IP
+	require.NoError(t, k8sClient.Get(context.Background(), k8s.ExtractNamespacedName(pod1), pod1))
+	pod1.Status.PodIP = "1.1.1.3"
+	require.NoError(t, k8sClient.Status().Update(context.Background(), pod1))
+	got = ReconcileTransportCertificatesSecrets(context.Background(), k8sClient, record.NewFakeRecorder(10), testRSACA, nil, nil, *es, rotationParams)
+	require.False(t, got.HasError())
+	require.NoError(t, k8sClient.Get(context.Background(), k8s.ExtractNamespacedName(&secret), &secret))
+	cert := extractTransportCert(context.Background(), secret, *pod1, buildCertificateCommonName(*pod1, *es))
+	require.NotNil(t, cert)
+	require.Len(t, cert.IPAddresses, 2)
+	require.True(t, cert.IPAddresses[0].Equal(net.ParseIP("1.1.1.3")))
+
+	// the StatefulSet is scaled down: the pre-issued certificate of the Pod which was never created is removed
+	es.Spec.NodeSets[0].Count = 2
+	got = ReconcileTransportCertificatesSecrets(context.Background(), k8sClient, record.NewFakeRecorder(10), testRSACA, nil, nil, *es, rotationParams)
+	require.False(t, got.HasError())
+	require.NoError(t, k8sClient.Get(context.Background(), k8s.ExtractNamespacedName(&secret), &secret))
+	require.Len(t, secret.Data, 5)
+	require.NotContains(t, secret.Data, PodKeyFileName("test-es-name-es-sset1-2"))
+}
+
+func Test_upcomingPods(t *testing.T) {
+	existing := []corev1.Pod{*newPodBuilder().forEs(testEsName).inNodeSet("sset1").withIndex(1).build()}
+	names := func(pods []corev1.Pod) []string {
+		var names []string
+		for _, pod := range pods {
+			names = append(names, pod.Name)
+		}
+		return names
+	}
+	es := newEsBuilder().addNodeSet("sset1", 3).build()
+	upcoming := upcomingPods(*es, "test-es-name-es-sset1", existing)
+	require.Equal(t, []string{"test-es-name-es-sset1-0", "test-es-name-es-sset1-2"}, names(upcoming))
+	require.Equal(t, "test-es-name-es-sset1", upcoming[0].Labels[label.StatefulSetNameLabelName])
+	// StatefulSet removed from the specification
+	require.Empty(t, upcomingPods(*es, "test-es-name-es-sset2", existing))
+	// self-signed certificates disabled
+	require.Empty(t, upcomingPods(*newEsBuilder().addNodeSet("sset1", 3).disableTransportCerts().build(), "test-es-name-es-sset1", existing))
+}
+
+func Test_podSecretChunk(t *testing.T) {
+	tests := []struct {
+		name string
+		pod  *corev1.Pod
+		want int
+	}{
+		{
+			name: "legacy Pod",
+			pod:  newPodBuilder().forEs(testEsName).inNodeSet("sset1").withIndex(5).build(),
+			want: 0,
+		},
+		{
+			name: "first Pod",
+			pod:  newPodBuilder().forEs(testEsName).inNodeSet("sset1").withIndex(0).withChunkedSecrets().build(),
+			want: 0,
+		},
+		{
+			name: "Pod with a low ordinal",
+			pod:  newPodBuilder().forEs(testEsName).inNodeSet("sset1").withIndex(5).withChunkedSecrets().build(),
+			want: 5,
+		},
+		{
+			name: "Pod with a high ordinal",
+			pod:  newPodBuilder().forEs(testEsName).inNodeSet("sset1").withIndex(37).withChunkedSecrets().build(),
+			want: 5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, podSecretChunk(*tt.pod))
+		})
+	}
+}
+
 func TestDeleteStatefulSetTransportCertificate(t *testing.T) {
 	type args struct {
 		client   k8s.Client
@@ -272,6 +561,21 @@ func TestDeleteStatefulSetTransportCertificate(t *testing.T) {
 				assert.Nil(t, err)
 			},
 		},
+		{
+			name: "StatefulSet transport Secret chunks exist",
+			args: args{
+				client: k8s.NewFakeClient(
+					&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "test-es-name-es-sset1-es-transport-certs", Namespace: testNamespace}},
+					&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "test-es-name-es-sset1-es-transport-certs-3", Namespace: testNamespace}},
+				),
+				es:       testES,
+				ssetName: esv1.StatefulSet(testEsName, "sset1"),
+			},
+			assertErr: func(t *testing.T, err error) {
+				t.Helper()
+				assert.Nil(t, err)
+			},
+		},
 		{
 			name: "StatefulSet transport Secret does not exist",
 			args: args{
@@ -290,6 +594,9 @@ func TestDeleteStatefulSetTransportCertificate(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			err := DeleteStatefulSetTransportCertificate(context.Background(), tt.args.client, tt.args.es.Namespace, tt.args.ssetName)
 			tt.assertErr(t, err)
+			var secrets corev1.SecretList
+			require.NoError(t, tt.args.client.List(context.Background(), &secrets))
+			assert.Empty(t, secrets.Items)
 		})
 	}
 }
@@ -455,7 +762,7 @@ func Test_ensureTransportCertificateSecretExists(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := ensureTransportCertificatesSecretExists(context.Background(), tt.args.c, tt.args.owner, esv1.StatefulSet(testES.Name, "sset1"))
+			got, err := ensureTransportCertificatesSecretExists(context.Background(), tt.args.c, tt.args.owner, esv1.StatefulSet(testES.Name, "sset1"), 0)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("EnsureTransportCertificateSecretExists() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -551,7 +858,7 @@ func Test_mayBeUpdateCAFile(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mayBeUpdateCAFile(tt.args.secret, tt.args.ca, tt.args.additionalCAs)
+			mayBeUpdateCAFile(tt.args.secret, tt.args.ca, tt.args.additionalCAs, false)
 			tt.assertSecrets(t, tt.args.secret.Data)
 		})
 	}
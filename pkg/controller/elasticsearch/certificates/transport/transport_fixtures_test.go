@@ -22,6 +22,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
 	sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/statefulset"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	esvolume "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
 )
 
 const (
@@ -218,6 +219,8 @@ type podBuilder struct {
 	nodeSet     string
 	index       int
 	annotations map[string]string
+	ready       bool
+	chunked     bool
 }
 
 func newPodBuilder() *podBuilder {
@@ -249,6 +252,16 @@ func (pb *podBuilder) withAnnotations(a map[string]string) *podBuilder {
 	return pb
 }
 
+func (pb *podBuilder) asReady() *podBuilder {
+	pb.ready = true
+	return pb
+}
+
+func (pb *podBuilder) withChunkedSecrets() *podBuilder {
+	pb.chunked = true
+	return pb
+}
+
 func (pb *podBuilder) build() *corev1.Pod {
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
@@ -265,6 +278,18 @@ func (pb *podBuilder) build() *corev1.Pod {
 	if len(pb.ip) > 0 {
 		pod.Status.PodIP = pb.ip
 	}
+	if pb.chunked {
+		pod.Spec.Volumes = []corev1.Volume{{
+			Name:         esvolume.TransportCertificatesSecretVolumeName,
+			VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{}},
+		}}
+	}
+	if pb.ready {
+		pod.Status.Conditions = []corev1.PodCondition{
+			{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+			{Type: corev1.ContainersReady, Status: corev1.ConditionTrue},
+		}
+	}
 	return pod
 }
 
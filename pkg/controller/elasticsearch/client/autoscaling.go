@@ -66,6 +66,28 @@ type AutoscalingPolicyResult struct {
 	RequiredCapacity AutoscalingCapacityInfo `json:"required_capacity"`
 	CurrentCapacity  AutoscalingCapacityInfo `json:"current_capacity"`
 	CurrentNodes     []AutoscalingNodeInfo   `json:"current_nodes"`
+	// Deciders holds the capacity required by each decider of the policy.
+	Deciders map[string]AutoscalingDeciderResult `json:"deciders,omitempty"`
+}
+
+// FrozenStorageDecider is the name of the decider which computes the shared cache storage required by the
+// partially mounted searchable snapshots of the frozen tier.
+const FrozenStorageDecider = "frozen_storage"
+
+// AutoscalingDeciderResult models the capacity required by a single autoscaling decider.
+type AutoscalingDeciderResult struct {
+	RequiredCapacity AutoscalingCapacityInfo `json:"required_capacity"`
+	ReasonSummary    string                  `json:"reason_summary,omitempty"`
+}
+
+// DeciderRequiredCapacity returns the capacity required by the given decider, and false if the decider did not return
+// any result.
+func (apr AutoscalingPolicyResult) DeciderRequiredCapacity(decider string) (AutoscalingCapacityInfo, bool) {
+	result, exists := apr.Deciders[decider]
+	if !exists {
+		return AutoscalingCapacityInfo{}, false
+	}
+	return result.RequiredCapacity, true
 }
 
 // AutoscalingCapacityInfo models capacity information as received by the autoscaling Elasticsearch API.
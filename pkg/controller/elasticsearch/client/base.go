@@ -16,6 +16,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 
 	commonhttp "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/http"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/safemode"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/stringsutil"
@@ -140,6 +141,9 @@ func (c *baseClient) request(
 	if err != nil {
 		return err
 	}
+	if err := safemode.CheckRequest(ctx, method, request.URL.Redacted()); err != nil {
+		return err
+	}
 
 	// Sets headers allowing ES to distinguish between deprecated APIs used internally and by the user
 	if request.Header == nil {
@@ -0,0 +1,139 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// FollowerIndexPausedStatus is the status of a follower index which does not replicate its leader index anymore.
+const FollowerIndexPausedStatus = "paused"
+
+type CCRClient interface {
+	// GetAutoFollowPatterns returns the cross-cluster replication auto-follow patterns, indexed by name.
+	GetAutoFollowPatterns(ctx context.Context) (AutoFollowPatterns, error)
+	// PutAutoFollowPattern creates or updates an auto-follow pattern.
+	PutAutoFollowPattern(ctx context.Context, name string, pattern AutoFollowPattern) error
+	// DeleteAutoFollowPattern deletes an auto-follow pattern. Follower indices created by the pattern are left untouched.
+	DeleteAutoFollowPattern(ctx context.Context, name string) error
+	// GetFollowerIndices returns the follower indices of the cluster, indexed by name.
+	GetFollowerIndices(ctx context.Context) (FollowerIndices, error)
+	// FollowIndex creates a follower index replicating the given leader index of the given remote cluster.
+	FollowIndex(ctx context.Context, name string, follower FollowerIndex) error
+	// ResumeFollowIndex resumes the replication of a paused follower index.
+	ResumeFollowIndex(ctx context.Context, name string) error
+	// UnfollowIndex stops the replication of a follower index and converts it into a regular index.
+	// The index is briefly closed during the conversion.
+	UnfollowIndex(ctx context.Context, name string) error
+}
+
+// AutoFollowPatterns are the auto-follow patterns of the cluster, indexed by name.
+type AutoFollowPatterns map[string]AutoFollowPattern
+
+// AutoFollowPattern models the subset of an auto-follow pattern managed by the operator.
+type AutoFollowPattern struct {
+	RemoteCluster                string   `json:"remote_cluster"`
+	LeaderIndexPatterns          []string `json:"leader_index_patterns"`
+	LeaderIndexExclusionPatterns []string `json:"leader_index_exclusion_patterns,omitempty"`
+	FollowIndexPattern           string   `json:"follow_index_pattern,omitempty"`
+}
+
+// FollowerIndices are the follower indices of the cluster, indexed by name.
+type FollowerIndices map[string]FollowerIndex
+
+// FollowerIndex models the subset of a follower index description used by the operator.
+type FollowerIndex struct {
+	RemoteCluster string `json:"remote_cluster"`
+	LeaderIndex   string `json:"leader_index"`
+	Status        string `json:"status,omitempty"`
+}
+
+type autoFollowPatternsResponse struct {
+	Patterns []struct {
+		Name    string            `json:"name"`
+		Pattern AutoFollowPattern `json:"pattern"`
+	} `json:"patterns"`
+}
+
+type followerIndicesResponse struct {
+	FollowerIndices []struct {
+		FollowerIndex string `json:"follower_index"`
+		RemoteCluster string `json:"remote_cluster"`
+		LeaderIndex   string `json:"leader_index"`
+		Status        string `json:"status"`
+	} `json:"follower_indices"`
+}
+
+type followIndexRequest struct {
+	RemoteCluster string `json:"remote_cluster"`
+	LeaderIndex   string `json:"leader_index"`
+}
+
+func (c *clientV6) GetAutoFollowPatterns(ctx context.Context) (AutoFollowPatterns, error) {
+	var response autoFollowPatternsResponse
+	if err := c.get(ctx, "/_ccr/auto_follow", &response); err != nil {
+		if IsNotFound(err) {
+			// older versions of Elasticsearch return a 404 if there is no auto-follow pattern
+			return AutoFollowPatterns{}, nil
+		}
+		return nil, err
+	}
+	patterns := make(AutoFollowPatterns, len(response.Patterns))
+	for _, pattern := range response.Patterns {
+		patterns[pattern.Name] = pattern.Pattern
+	}
+	return patterns, nil
+}
+
+func (c *clientV6) PutAutoFollowPattern(ctx context.Context, name string, pattern AutoFollowPattern) error {
+	return c.put(ctx, fmt.Sprintf("/_ccr/auto_follow/%s", url.PathEscape(name)), pattern, nil)
+}
+
+func (c *clientV6) DeleteAutoFollowPattern(ctx context.Context, name string) error {
+	return c.delete(ctx, fmt.Sprintf("/_ccr/auto_follow/%s", url.PathEscape(name)))
+}
+
+func (c *clientV6) GetFollowerIndices(ctx context.Context) (FollowerIndices, error) {
+	var response followerIndicesResponse
+	if err := c.get(ctx, "/_all/_ccr/info", &response); err != nil {
+		return nil, err
+	}
+	followers := make(FollowerIndices, len(response.FollowerIndices))
+	for _, follower := range response.FollowerIndices {
+		followers[follower.FollowerIndex] = FollowerIndex{
+			RemoteCluster: follower.RemoteCluster,
+			LeaderIndex:   follower.LeaderIndex,
+			Status:        follower.Status,
+		}
+	}
+	return followers, nil
+}
+
+func (c *clientV6) FollowIndex(ctx context.Context, name string, follower FollowerIndex) error {
+	request := followIndexRequest{RemoteCluster: follower.RemoteCluster, LeaderIndex: follower.LeaderIndex}
+	return c.put(ctx, fmt.Sprintf("/%s/_ccr/follow", url.PathEscape(name)), request, nil)
+}
+
+func (c *clientV6) ResumeFollowIndex(ctx context.Context, name string) error {
+	return c.post(ctx, fmt.Sprintf("/%s/_ccr/resume_follow", url.PathEscape(name)), nil, nil)
+}
+
+func (c *clientV6) UnfollowIndex(ctx context.Context, name string) error {
+	index := url.PathEscape(name)
+	// replication must be paused and the index closed before it can be converted into a regular index
+	if err := c.post(ctx, fmt.Sprintf("/%s/_ccr/pause_follow", index), nil, nil); err != nil && !IsBadRequest(err) {
+		// a 400 is returned if the follower index is already paused
+		return err
+	}
+	if err := c.post(ctx, fmt.Sprintf("/%s/_close", index), nil, nil); err != nil {
+		return err
+	}
+	if err := c.post(ctx, fmt.Sprintf("/%s/_ccr/unfollow", index), nil, nil); err != nil {
+		return err
+	}
+	return c.post(ctx, fmt.Sprintf("/%s/_open", index), nil, nil)
+}
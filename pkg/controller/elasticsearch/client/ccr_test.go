@@ -0,0 +1,119 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+func TestClient_GetAutoFollowPatterns(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/_ccr/auto_follow", req.URL.Path)
+		return &http.Response{
+			StatusCode: 200,
+			Body: io.NopCloser(strings.NewReader(`{"patterns":[{"name":"logs","pattern":{"active":true,"remote_cluster":"leader",` +
+				`"leader_index_patterns":["logs-*"],"leader_index_exclusion_patterns":[],"follow_index_pattern":"{{leader_index}}-copy",` +
+				`"max_read_request_operation_count":5120}}]}`)),
+			Header:  make(http.Header),
+			Request: req,
+		}
+	})
+	patterns, err := testClient.GetAutoFollowPatterns(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, AutoFollowPatterns{
+		"logs": {
+			RemoteCluster:                "leader",
+			LeaderIndexPatterns:          []string{"logs-*"},
+			LeaderIndexExclusionPatterns: []string{},
+			FollowIndexPattern:           "{{leader_index}}-copy",
+		},
+	}, patterns)
+}
+
+func TestClient_GetAutoFollowPatterns_NotFound(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("6.8.0"), func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: 404,
+			Body:       io.NopCloser(strings.NewReader(`{"error":{"type":"resource_not_found_exception"},"status":404}`)),
+			Header:     make(http.Header),
+			Request:    req,
+		}
+	})
+	patterns, err := testClient.GetAutoFollowPatterns(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, patterns)
+}
+
+func TestClient_GetFollowerIndices(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/_all/_ccr/info", req.URL.Path)
+		return &http.Response{
+			StatusCode: 200,
+			Body: io.NopCloser(strings.NewReader(`{"follower_indices":[{"follower_index":"follower","remote_cluster":"leader",` +
+				`"leader_index":"logs","status":"active","parameters":{"max_read_request_operation_count":5120}}]}`)),
+			Header:  make(http.Header),
+			Request: req,
+		}
+	})
+	followers, err := testClient.GetFollowerIndices(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, FollowerIndices{
+		"follower": {RemoteCluster: "leader", LeaderIndex: "logs", Status: "active"},
+	}, followers)
+}
+
+func TestClient_FollowIndex(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPut, req.Method)
+		require.Equal(t, "/follower/_ccr/follow", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"remote_cluster":"leader","leader_index":"logs"}`, string(body))
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(strings.NewReader(`{"follow_index_created":true}`)),
+			Header:     make(http.Header),
+			Request:    req,
+		}
+	})
+	err := testClient.FollowIndex(context.Background(), "follower", FollowerIndex{RemoteCluster: "leader", LeaderIndex: "logs", Status: "paused"})
+	require.NoError(t, err)
+}
+
+func TestClient_UnfollowIndex(t *testing.T) {
+	var requests []string
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPost, req.Method)
+		requests = append(requests, req.URL.Path)
+		statusCode := 200
+		if strings.HasSuffix(req.URL.Path, "/_ccr/pause_follow") {
+			// already paused
+			statusCode = 400
+		}
+		return &http.Response{
+			StatusCode: statusCode,
+			Body:       io.NopCloser(strings.NewReader(`{"acknowledged":true}`)),
+			Header:     make(http.Header),
+			Request:    req,
+		}
+	})
+	require.NoError(t, testClient.UnfollowIndex(context.Background(), "follower"))
+	require.Equal(t, []string{
+		"/follower/_ccr/pause_follow",
+		"/follower/_close",
+		"/follower/_ccr/unfollow",
+		"/follower/_open",
+	}, requests)
+}
@@ -60,11 +60,17 @@ type Role struct {
 type Client interface {
 	AllocationSetter
 	AutoscalingClient
+	CCRClient
 	DesiredNodesClient
+	IndexTemplateClient
 	ShardLister
 	LicenseClient
+	MaintenanceClient
+	MigrationClient
 	RemoteClusterClient
+	ResourceClient
 	SecurityClient
+	SnapshotRepositoryClient
 	// Close idle connections in the underlying http client.
 	Close()
 	// Equal returns true if other can be considered as the same client.
@@ -100,6 +106,11 @@ type Client interface {
 	GetNodes(ctx context.Context) (Nodes, error)
 	// GetNodesStats calls the _nodes/stats api to return a map(nodeName -> NodeStats)
 	GetNodesStats(ctx context.Context) (NodesStats, error)
+	// GetWriteThreadPoolStats calls the _nodes/stats api to return the write thread pool statistics of each node.
+	GetWriteThreadPoolStats(ctx context.Context) (NodesStats, error)
+	// GetResourceStats calls the _nodes/stats api to return the heap, garbage collection, CPU and indexing pressure
+	// statistics of each node.
+	GetResourceStats(ctx context.Context) (NodesStats, error)
 	// ClusterBootstrappedForZen2 returns true if the cluster is relying on zen2 orchestration.
 	ClusterBootstrappedForZen2(ctx context.Context) (bool, error)
 	// AddVotingConfigExclusions sets the transient and persistent setting of the same name in cluster settings.
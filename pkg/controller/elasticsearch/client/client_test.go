@@ -342,6 +342,70 @@ func TestClientGetNodesStats(t *testing.T) {
 	require.Equal(t, "3221225472", resp.Nodes["Rt-o5-ZBQaq-Nkhhy0p7JA"].OS.CGroup.Memory.LimitInBytes)
 }
 
+func TestClientGetWriteThreadPoolStats(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, "/_nodes/_all/stats/thread_pool", req.URL.Path)
+		require.Equal(t, "nodes.*.name,nodes.*.thread_pool.write", req.URL.Query().Get("filter_path"))
+		return NewMockResponse(200, req, fixtures.WriteThreadPoolStatsSample)
+	})
+	resp, err := testClient.GetWriteThreadPoolStats(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, len(resp.Nodes))
+	node := resp.Nodes["Rt-o5-ZBQaq-Nkhhy0p7JA"]
+	require.Equal(t, "elasticsearch-sample-es-ingest-0", node.Name)
+	require.Equal(t, ThreadPoolStats{Queue: 12, Rejected: 3}, node.ThreadPool.Write)
+}
+
+func TestClientGetResourceStats(t *testing.T) {
+	for _, tt := range []struct {
+		version     string
+		wantMetrics string
+	}{
+		{version: "7.8.0", wantMetrics: "jvm,os"},
+		{version: "8.15.0", wantMetrics: "jvm,os,indexing_pressure"},
+	} {
+		t.Run(tt.version, func(t *testing.T) {
+			testClient := NewMockClient(version.MustParse(tt.version), func(req *http.Request) *http.Response {
+				require.Equal(t, "/_nodes/_all/stats/"+tt.wantMetrics, req.URL.Path)
+				return NewMockResponse(200, req, fixtures.ResourceStatsSample)
+			})
+			resp, err := testClient.GetResourceStats(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, 1, len(resp.Nodes))
+			node := resp.Nodes["Rt-o5-ZBQaq-Nkhhy0p7JA"]
+			require.Equal(t, int64(87), node.JVM.Mem.HeapUsedPercent)
+			require.Equal(t, GCCollectorStats{CollectionCount: 12, CollectionTimeInMillis: 2400}, node.JVM.GC.Collectors.Old)
+			require.Equal(t, int64(64), node.OS.CPU.Percent)
+			require.Equal(t, int64(9000), node.OS.CGroup.CPU.Stat.NumberOfTimesThrottled)
+			require.Equal(t, int64(3), node.IndexingPressure.Memory.Total.Rejections())
+		})
+	}
+}
+
+func TestClientResources(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, "/_ilm/policy/logs", req.URL.Path)
+		switch req.Method {
+		case http.MethodGet:
+			return NewMockResponse(200, req, `{"logs":{"policy":{"phases":{}}}}`)
+		case http.MethodPut:
+			body, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+			require.JSONEq(t, `{"policy":{"phases":{}}}`, string(body))
+			return NewMockResponse(200, req, `{"acknowledged":true}`)
+		default:
+			return NewMockResponse(404, req, `{}`)
+		}
+	})
+	resource, err := testClient.GetResource(context.Background(), "/_ilm/policy/logs")
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"logs": map[string]interface{}{"policy": map[string]interface{}{"phases": map[string]interface{}{}}}}, resource)
+
+	body := map[string]interface{}{"policy": map[string]interface{}{"phases": map[string]interface{}{}}}
+	require.NoError(t, testClient.ApplyResource(context.Background(), http.MethodPut, "/_ilm/policy/logs", body))
+	require.True(t, IsNotFound(testClient.ApplyResource(context.Background(), http.MethodDelete, "/_ilm/policy/logs", nil)))
+}
+
 func TestGetInfo(t *testing.T) {
 	expectedPath := "/"
 	testClient := NewMockClient(version.MustParse("6.4.1"), func(req *http.Request) *http.Response {
@@ -59,6 +59,11 @@ func (a *APIError) Error() string {
 	return fmt.Sprintf("%s: %+v", a.Status, a.ErrorResponse)
 }
 
+// IsBadRequest checks whether the error was an HTTP 400 error.
+func IsBadRequest(err error) bool {
+	return isHTTPError(err, http.StatusBadRequest)
+}
+
 // IsUnauthorized checks whether the error was an HTTP 401 error.
 func IsUnauthorized(err error) bool {
 	return isHTTPError(err, http.StatusUnauthorized)
@@ -0,0 +1,68 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+type IndexTemplateClient interface {
+	// PutIndexTemplate creates or updates a composable index template.
+	// Introduced in: Elasticsearch 7.8.0
+	PutIndexTemplate(ctx context.Context, name string, template IndexTemplate) error
+	// GetDataStream returns the data stream with the given name.
+	// Introduced in: Elasticsearch 7.9.0
+	GetDataStream(ctx context.Context, name string) (DataStream, error)
+	// CreateDataStream creates a data stream with the given name. A matching index template must exist.
+	// Introduced in: Elasticsearch 7.9.0
+	CreateDataStream(ctx context.Context, name string) error
+}
+
+// IndexTemplate models a composable index template.
+type IndexTemplate struct {
+	IndexPatterns []string                 `json:"index_patterns"`
+	DataStream    *IndexTemplateDataStream `json:"data_stream,omitempty"`
+	Priority      *int64                   `json:"priority,omitempty"`
+	Template      map[string]interface{}   `json:"template,omitempty"`
+	Meta          map[string]interface{}   `json:"_meta,omitempty"`
+}
+
+// IndexTemplateDataStream marks an index template as a data stream template.
+type IndexTemplateDataStream struct{}
+
+// DataStream models a data stream as returned by the data stream API.
+type DataStream struct {
+	Name     string `json:"name"`
+	Template string `json:"template"`
+	Status   string `json:"status"`
+}
+
+// DataStreams is the response of the data stream API.
+type DataStreams struct {
+	DataStreams []DataStream `json:"data_streams"`
+}
+
+func (c *clientV7) PutIndexTemplate(ctx context.Context, name string, template IndexTemplate) error {
+	return c.put(ctx, fmt.Sprintf("/_index_template/%s", url.PathEscape(name)), template, nil)
+}
+
+func (c *clientV7) GetDataStream(ctx context.Context, name string) (DataStream, error) {
+	var response DataStreams
+	if err := c.get(ctx, fmt.Sprintf("/_data_stream/%s", url.PathEscape(name)), &response); err != nil {
+		return DataStream{}, err
+	}
+	for _, ds := range response.DataStreams {
+		if ds.Name == name {
+			return ds, nil
+		}
+	}
+	return DataStream{}, fmt.Errorf("data stream %s not found in response", name)
+}
+
+func (c *clientV7) CreateDataStream(ctx context.Context, name string) error {
+	return c.put(ctx, fmt.Sprintf("/_data_stream/%s", url.PathEscape(name)), nil, nil)
+}
@@ -0,0 +1,89 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type MaintenanceClient interface {
+	// RetryFailedShardAllocations retries the allocation of the shards which failed to be allocated too many times.
+	RetryFailedShardAllocations(ctx context.Context) error
+	// ClearCache clears the caches of the given indices, or of all the indices if none is given.
+	ClearCache(ctx context.Context, indices []string) error
+	// StartForceMerge starts merging the segments of the given indices, or of all the indices if none is given, down
+	// to the given maximum number of segments per shard if not nil. It returns the id of the Elasticsearch task
+	// running the force merge, without waiting for its completion.
+	// Introduced in: Elasticsearch 7.7.0
+	StartForceMerge(ctx context.Context, indices []string, maxNumSegments *int32) (string, error)
+	// GetTask returns the status of the given Elasticsearch task.
+	GetTask(ctx context.Context, taskID string) (TaskStatus, error)
+	// ExecuteSLMPolicy immediately takes a snapshot with the given snapshot lifecycle management policy, and returns
+	// the name of the snapshot. It does not wait for the snapshot to complete.
+	// Introduced in: Elasticsearch 7.4.0
+	ExecuteSLMPolicy(ctx context.Context, policy string) (string, error)
+}
+
+// TaskStatus models the subset of the status of an Elasticsearch task used by the operator.
+type TaskStatus struct {
+	Completed bool `json:"completed"`
+	// Error is the error of the task if it failed.
+	Error json.RawMessage `json:"error,omitempty"`
+}
+
+type startTaskResponse struct {
+	Task string `json:"task"`
+}
+
+type executeSLMPolicyResponse struct {
+	SnapshotName string `json:"snapshot_name"`
+}
+
+// indicesPath returns the path prefix targeting the given indices, or all the indices if none is given.
+func indicesPath(indices []string) string {
+	if len(indices) == 0 {
+		return ""
+	}
+	return "/" + url.PathEscape(strings.Join(indices, ","))
+}
+
+func (c *clientV6) RetryFailedShardAllocations(ctx context.Context) error {
+	return c.post(ctx, "/_cluster/reroute?retry_failed=true", nil, nil)
+}
+
+func (c *clientV6) ClearCache(ctx context.Context, indices []string) error {
+	return c.post(ctx, indicesPath(indices)+"/_cache/clear", nil, nil)
+}
+
+func (c *clientV6) StartForceMerge(ctx context.Context, indices []string, maxNumSegments *int32) (string, error) {
+	query := url.Values{"wait_for_completion": []string{"false"}}
+	if maxNumSegments != nil {
+		query.Set("max_num_segments", strconv.Itoa(int(*maxNumSegments)))
+	}
+	var response startTaskResponse
+	if err := c.post(ctx, fmt.Sprintf("%s/_forcemerge?%s", indicesPath(indices), query.Encode()), nil, &response); err != nil {
+		return "", err
+	}
+	return response.Task, nil
+}
+
+func (c *clientV6) GetTask(ctx context.Context, taskID string) (TaskStatus, error) {
+	var status TaskStatus
+	err := c.get(ctx, "/_tasks/"+url.PathEscape(taskID), &status)
+	return status, err
+}
+
+func (c *clientV6) ExecuteSLMPolicy(ctx context.Context, policy string) (string, error) {
+	var response executeSLMPolicyResponse
+	if err := c.post(ctx, fmt.Sprintf("/_slm/policy/%s/_execute", url.PathEscape(policy)), nil, &response); err != nil {
+		return "", err
+	}
+	return response.SnapshotName, nil
+}
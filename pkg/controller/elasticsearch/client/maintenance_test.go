@@ -0,0 +1,88 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+func mockResponse(req *http.Request, body string) *http.Response {
+	return &http.Response{
+		StatusCode: 200,
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Header:     make(http.Header),
+		Request:    req,
+	}
+}
+
+func TestClient_RetryFailedShardAllocations(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, "/_cluster/reroute", req.URL.Path)
+		require.Equal(t, "true", req.URL.Query().Get("retry_failed"))
+		return mockResponse(req, `{"acknowledged":true}`)
+	})
+	require.NoError(t, testClient.RetryFailedShardAllocations(context.Background()))
+}
+
+func TestClient_ClearCache(t *testing.T) {
+	for indices, path := range map[string]string{"": "/_cache/clear", "logs-*,metrics": "/logs-*,metrics/_cache/clear"} {
+		testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+			require.Equal(t, http.MethodPost, req.Method)
+			require.Equal(t, path, req.URL.Path)
+			return mockResponse(req, `{"_shards":{"total":2,"successful":2,"failed":0}}`)
+		})
+		var list []string
+		if indices != "" {
+			list = strings.Split(indices, ",")
+		}
+		require.NoError(t, testClient.ClearCache(context.Background(), list))
+	}
+}
+
+func TestClient_StartForceMerge(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, "/logs/_forcemerge", req.URL.Path)
+		require.Equal(t, "false", req.URL.Query().Get("wait_for_completion"))
+		require.Equal(t, "1", req.URL.Query().Get("max_num_segments"))
+		return mockResponse(req, `{"task":"oTUltX4IQMOUUVeiohTt8A:12345"}`)
+	})
+	taskID, err := testClient.StartForceMerge(context.Background(), []string{"logs"}, ptr.To[int32](1))
+	require.NoError(t, err)
+	require.Equal(t, "oTUltX4IQMOUUVeiohTt8A:12345", taskID)
+}
+
+func TestClient_GetTask(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/_tasks/oTUltX4IQMOUUVeiohTt8A:12345", req.URL.Path)
+		return mockResponse(req, `{"completed":true,"task":{"action":"indices:admin/forcemerge"},"error":{"type":"index_not_found_exception"}}`)
+	})
+	status, err := testClient.GetTask(context.Background(), "oTUltX4IQMOUUVeiohTt8A:12345")
+	require.NoError(t, err)
+	require.True(t, status.Completed)
+	require.JSONEq(t, `{"type":"index_not_found_exception"}`, string(status.Error))
+}
+
+func TestClient_ExecuteSLMPolicy(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, "/_slm/policy/nightly/_execute", req.URL.Path)
+		return mockResponse(req, `{"snapshot_name":"nightly-2024.10.01-abc"}`)
+	})
+	snapshot, err := testClient.ExecuteSLMPolicy(context.Background(), "nightly")
+	require.NoError(t, err)
+	require.Equal(t, "nightly-2024.10.01-abc", snapshot)
+}
@@ -0,0 +1,145 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+const (
+	// DeprecationLevelCritical is the level of the deprecations which must be resolved before upgrading to the next major version.
+	DeprecationLevelCritical = "critical"
+
+	// SystemFeaturesNoMigrationNeeded is the migration status of system features that can be upgraded as is.
+	SystemFeaturesNoMigrationNeeded = "NO_MIGRATION_NEEDED"
+	// SystemFeaturesMigrationNeeded is the migration status of system features that must be migrated before upgrading.
+	SystemFeaturesMigrationNeeded = "MIGRATION_NEEDED"
+	// SystemFeaturesMigrationInProgress is the migration status of system features being migrated.
+	SystemFeaturesMigrationInProgress = "IN_PROGRESS"
+	// SystemFeaturesMigrationError is the migration status of system features whose migration failed.
+	SystemFeaturesMigrationError = "ERROR"
+)
+
+// systemFeaturesMinVersion is the first version of Elasticsearch exposing the system features migration API.
+var systemFeaturesMinVersion = version.MinFor(7, 16, 0)
+
+type MigrationClient interface {
+	// GetDeprecations returns the deprecated settings, features and indices in use in the cluster that must be
+	// addressed before upgrading to the next major version.
+	GetDeprecations(ctx context.Context) (Deprecations, error)
+	// GetSystemFeaturesUpgradeStatus returns whether system features must be migrated before upgrading to the next
+	// major version. Versions of Elasticsearch without the system features migration API always report that no
+	// migration is needed.
+	GetSystemFeaturesUpgradeStatus(ctx context.Context) (SystemFeaturesUpgradeStatus, error)
+}
+
+// Deprecation is an issue reported by the deprecation info API.
+type Deprecation struct {
+	Level                       string `json:"level"`
+	Message                     string `json:"message"`
+	URL                         string `json:"url,omitempty"`
+	Details                     string `json:"details,omitempty"`
+	ResolveDuringRollingUpgrade bool   `json:"resolve_during_rolling_upgrade,omitempty"`
+}
+
+// Blocking returns true if the deprecation must be resolved before starting the upgrade.
+func (d Deprecation) Blocking() bool {
+	return d.Level == DeprecationLevelCritical && !d.ResolveDuringRollingUpgrade
+}
+
+// Deprecations models the response of the deprecation info API.
+type Deprecations struct {
+	ClusterSettings []Deprecation            `json:"cluster_settings,omitempty"`
+	NodeSettings    []Deprecation            `json:"node_settings,omitempty"`
+	MLSettings      []Deprecation            `json:"ml_settings,omitempty"`
+	IndexSettings   map[string][]Deprecation `json:"index_settings,omitempty"`
+	DataStreams     map[string][]Deprecation `json:"data_streams,omitempty"`
+	ILMPolicies     map[string][]Deprecation `json:"ilm_policies,omitempty"`
+	Templates       map[string][]Deprecation `json:"templates,omitempty"`
+}
+
+// BlockingIssues returns a sorted list of human-readable descriptions of the deprecations which must be resolved
+// before starting the upgrade.
+func (d Deprecations) BlockingIssues() []string {
+	var issues []string
+	for scope, deprecations := range map[string][]Deprecation{
+		"cluster settings": d.ClusterSettings,
+		"node settings":    d.NodeSettings,
+		"ML settings":      d.MLSettings,
+	} {
+		issues = append(issues, blockingIssues(scope, deprecations)...)
+	}
+	for kind, resources := range map[string]map[string][]Deprecation{
+		"index":       d.IndexSettings,
+		"data stream": d.DataStreams,
+		"ILM policy":  d.ILMPolicies,
+		"template":    d.Templates,
+	} {
+		for name, deprecations := range resources {
+			issues = append(issues, blockingIssues(fmt.Sprintf("%s %s", kind, name), deprecations)...)
+		}
+	}
+	sort.Strings(issues)
+	return slices.Compact(issues)
+}
+
+func blockingIssues(scope string, deprecations []Deprecation) []string {
+	var issues []string
+	for _, deprecation := range deprecations {
+		if deprecation.Blocking() {
+			issues = append(issues, fmt.Sprintf("%s: %s", scope, deprecation.Message))
+		}
+	}
+	return issues
+}
+
+// SystemFeaturesUpgradeStatus models the response of the system features migration API.
+type SystemFeaturesUpgradeStatus struct {
+	MigrationStatus string                `json:"migration_status"`
+	Features        []SystemFeatureStatus `json:"features,omitempty"`
+}
+
+// SystemFeatureStatus is the migration status of a single system feature.
+type SystemFeatureStatus struct {
+	FeatureName     string `json:"feature_name"`
+	MigrationStatus string `json:"migration_status"`
+}
+
+// MigrationNeeded returns true if system features must be migrated before starting the upgrade.
+func (s SystemFeaturesUpgradeStatus) MigrationNeeded() bool {
+	return s.MigrationStatus != "" && s.MigrationStatus != SystemFeaturesNoMigrationNeeded
+}
+
+// PendingFeatures returns the names of the system features which have not been migrated yet.
+func (s SystemFeaturesUpgradeStatus) PendingFeatures() []string {
+	var features []string
+	for _, feature := range s.Features {
+		if feature.MigrationStatus != SystemFeaturesNoMigrationNeeded {
+			features = append(features, feature.FeatureName)
+		}
+	}
+	sort.Strings(features)
+	return features
+}
+
+func (c *clientV6) GetDeprecations(ctx context.Context) (Deprecations, error) {
+	var deprecations Deprecations
+	err := c.get(ctx, "/_migration/deprecations", &deprecations)
+	return deprecations, err
+}
+
+func (c *clientV6) GetSystemFeaturesUpgradeStatus(ctx context.Context) (SystemFeaturesUpgradeStatus, error) {
+	if c.version.LT(systemFeaturesMinVersion) {
+		return SystemFeaturesUpgradeStatus{MigrationStatus: SystemFeaturesNoMigrationNeeded}, nil
+	}
+	var status SystemFeaturesUpgradeStatus
+	err := c.get(ctx, "/_migration/system_features", &status)
+	return status, err
+}
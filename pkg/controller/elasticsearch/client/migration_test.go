@@ -0,0 +1,69 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+func TestClient_GetDeprecations(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.18.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/_migration/deprecations", req.URL.Path)
+		return NewMockResponse(200, req, `{
+			"cluster_settings": [{"level":"critical","message":"Cluster setting removed","url":"https://example.com"}],
+			"node_settings": [{"level":"warning","message":"Node setting deprecated"}],
+			"ml_settings": [],
+			"index_settings": {
+				"logs-1": [{"level":"critical","message":"Old index","resolve_during_rolling_upgrade":false}],
+				"logs-2": [{"level":"critical","message":"Old index"}]
+			},
+			"data_streams": {},
+			"ilm_policies": {"policy": [{"level":"critical","message":"Removed action","resolve_during_rolling_upgrade":true}]},
+			"templates": {}
+		}`)
+	})
+	deprecations, err := testClient.GetDeprecations(context.Background())
+	require.NoError(t, err)
+	require.Len(t, deprecations.ClusterSettings, 1)
+	require.Len(t, deprecations.IndexSettings, 2)
+	require.Equal(t, []string{
+		"cluster settings: Cluster setting removed",
+		"index logs-1: Old index",
+		"index logs-2: Old index",
+	}, deprecations.BlockingIssues())
+}
+
+func TestClient_GetSystemFeaturesUpgradeStatus(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.18.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/_migration/system_features", req.URL.Path)
+		return NewMockResponse(200, req, `{"migration_status":"MIGRATION_NEEDED","features":[
+			{"feature_name":"watcher","migration_status":"MIGRATION_NEEDED"},
+			{"feature_name":"async_search","migration_status":"NO_MIGRATION_NEEDED"},
+			{"feature_name":"fleet","migration_status":"IN_PROGRESS"}
+		]}`)
+	})
+	status, err := testClient.GetSystemFeaturesUpgradeStatus(context.Background())
+	require.NoError(t, err)
+	require.True(t, status.MigrationNeeded())
+	require.Equal(t, []string{"fleet", "watcher"}, status.PendingFeatures())
+}
+
+func TestClient_GetSystemFeaturesUpgradeStatus_NotAvailable(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("7.15.2"), func(req *http.Request) *http.Response {
+		t.Fatalf("unexpected request %s", req.URL.Path)
+		return nil
+	})
+	status, err := testClient.GetSystemFeaturesUpgradeStatus(context.Background())
+	require.NoError(t, err)
+	require.False(t, status.MigrationNeeded())
+}
@@ -89,9 +89,10 @@ func (n Nodes) Names() []string {
 
 // Node partially models an Elasticsearch node retrieved from /_nodes
 type Node struct {
-	Name    string   `json:"name"`
-	Version string   `json:"version"`
-	Roles   []string `json:"roles"`
+	Name       string            `json:"name"`
+	Version    string            `json:"version"`
+	Roles      []string          `json:"roles"`
+	Attributes map[string]string `json:"attributes"`
 }
 
 func (n Node) isV7OrAbove() (bool, error) {
@@ -111,8 +112,61 @@ type NodesStats struct {
 type NodeStats struct {
 	Name string `json:"name"`
 	OS   struct {
+		CPU struct {
+			Percent int64 `json:"percent"`
+		} `json:"cpu"`
 		CGroup *CGroup `json:"cgroup"`
 	} `json:"os"`
+	JVM              JVMStats `json:"jvm"`
+	IndexingPressure struct {
+		Memory struct {
+			Total IndexingPressureStats `json:"total"`
+		} `json:"memory"`
+	} `json:"indexing_pressure"`
+	ThreadPool struct {
+		Write ThreadPoolStats `json:"write"`
+	} `json:"thread_pool"`
+}
+
+// JVMStats partially models the JVM statistics of an Elasticsearch node.
+type JVMStats struct {
+	UptimeInMillis int64 `json:"uptime_in_millis"`
+	Mem            struct {
+		HeapUsedPercent int64 `json:"heap_used_percent"`
+	} `json:"mem"`
+	GC struct {
+		Collectors struct {
+			Old GCCollectorStats `json:"old"`
+		} `json:"collectors"`
+	} `json:"gc"`
+}
+
+// GCCollectorStats partially models the statistics of a garbage collector of an Elasticsearch node.
+type GCCollectorStats struct {
+	// CollectionCount is the number of collections since the node started.
+	CollectionCount int64 `json:"collection_count"`
+	// CollectionTimeInMillis is the total time spent in collections since the node started.
+	CollectionTimeInMillis int64 `json:"collection_time_in_millis"`
+}
+
+// IndexingPressureStats partially models the indexing pressure statistics of an Elasticsearch node.
+type IndexingPressureStats struct {
+	CoordinatingRejections int64 `json:"coordinating_rejections"`
+	PrimaryRejections      int64 `json:"primary_rejections"`
+	ReplicaRejections      int64 `json:"replica_rejections"`
+}
+
+// Rejections returns the number of indexing requests rejected since the node started.
+func (s IndexingPressureStats) Rejections() int64 {
+	return s.CoordinatingRejections + s.PrimaryRejections + s.ReplicaRejections
+}
+
+// ThreadPoolStats partially models the statistics of a thread pool of an Elasticsearch node.
+type ThreadPoolStats struct {
+	// Queue is the number of tasks waiting for a thread.
+	Queue int64 `json:"queue"`
+	// Rejected is the number of tasks rejected since the node started.
+	Rejected int64 `json:"rejected"`
 }
 
 type CGroup struct {
@@ -122,6 +176,10 @@ type CGroup struct {
 	CPU struct {
 		CFSPeriodMicros int `json:"cfs_period_micros"`
 		CFSQuotaMicros  int `json:"cfs_quota_micros"`
+		Stat            struct {
+			NumberOfElapsedPeriods int64 `json:"number_of_elapsed_periods"`
+			NumberOfTimesThrottled int64 `json:"number_of_times_throttled"`
+		} `json:"stat"`
 	} `json:"cpu"`
 }
 
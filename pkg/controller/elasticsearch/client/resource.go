@@ -0,0 +1,30 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+)
+
+// ResourceClient manages arbitrary Elasticsearch API resources, not covered by dedicated methods.
+type ResourceClient interface {
+	// GetResource performs a GET request on the given path and returns the decoded JSON response.
+	GetResource(ctx context.Context, path string) (interface{}, error)
+	// ApplyResource performs a request with the given method on the given path, with the given body encoded in JSON
+	// if not nil.
+	ApplyResource(ctx context.Context, method, path string, body interface{}) error
+}
+
+func (c *clientV6) GetResource(ctx context.Context, path string) (interface{}, error) {
+	var response interface{}
+	if err := c.get(ctx, path, &response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (c *clientV6) ApplyResource(ctx context.Context, method, path string, body interface{}) error {
+	return c.request(ctx, method, path, body, nil, nil)
+}
@@ -34,10 +34,41 @@ func (s *ServiceAccountCredential) Nodes() set.StringSet {
 	return result
 }
 
+// APIKeyCreateRequest is the request to create an API key with restricted privileges.
+type APIKeyCreateRequest struct {
+	Name            string                          `json:"name"`
+	Expiration      string                          `json:"expiration,omitempty"`
+	RoleDescriptors map[string]APIKeyRoleDescriptor `json:"role_descriptors,omitempty"`
+	Metadata        map[string]interface{}          `json:"metadata,omitempty"`
+}
+
+// APIKeyRoleDescriptor restricts the privileges of an API key.
+type APIKeyRoleDescriptor struct {
+	Cluster []string                `json:"cluster,omitempty"`
+	Indices []APIKeyIndexPrivileges `json:"indices,omitempty"`
+}
+
+// APIKeyIndexPrivileges are the privileges of an API key on a set of indices.
+type APIKeyIndexPrivileges struct {
+	Names      []string `json:"names"`
+	Privileges []string `json:"privileges"`
+}
+
+// APIKeyCreateResponse is the response to the creation of an API key.
+type APIKeyCreateResponse struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Expiration int64  `json:"expiration,omitempty"`
+	APIKey     string `json:"api_key"`
+	Encoded    string `json:"encoded"`
+}
+
 type SecurityClient interface {
 
 	// GetServiceAccountCredentials returns the service account credentials from the /_security/service API
 	GetServiceAccountCredentials(ctx context.Context, namespacedService string) (ServiceAccountCredential, error)
+	// CreateAPIKey creates an API key with the privileges restricted by the given request.
+	CreateAPIKey(ctx context.Context, request APIKeyCreateRequest) (APIKeyCreateResponse, error)
 }
 
 func (c *clientV6) GetServiceAccountCredentials(_ context.Context, _ string) (ServiceAccountCredential, error) {
@@ -52,3 +83,13 @@ func (c *clientV7) GetServiceAccountCredentials(ctx context.Context, namespacedS
 	}
 	return serviceAccountCredential, nil
 }
+
+func (c *clientV6) CreateAPIKey(_ context.Context, _ APIKeyCreateRequest) (APIKeyCreateResponse, error) {
+	return APIKeyCreateResponse{}, errNotSupportedInEs6x
+}
+
+func (c *clientV7) CreateAPIKey(ctx context.Context, request APIKeyCreateRequest) (APIKeyCreateResponse, error) {
+	var response APIKeyCreateResponse
+	err := c.post(ctx, "/_security/api_key", request, &response)
+	return response, err
+}
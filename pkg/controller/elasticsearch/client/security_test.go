@@ -76,3 +76,34 @@ func Test_GetServiceAccountCredentials(t *testing.T) {
 		})
 	}
 }
+
+func Test_CreateAPIKey(t *testing.T) {
+	client := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, "/_security/api_key", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t,
+			`{"name":"key","expiration":"1h","role_descriptors":{"reader":{"cluster":["monitor"],"indices":[{"names":["*"],"privileges":["read"]}]}}}`,
+			string(body),
+		)
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(strings.NewReader(`{"id":"id","name":"key","expiration":1700000000000,"api_key":"secret","encoded":"aWQ6c2VjcmV0"}`)),
+			Header:     make(http.Header),
+			Request:    req,
+		}
+	})
+	got, err := client.CreateAPIKey(context.Background(), APIKeyCreateRequest{
+		Name:       "key",
+		Expiration: "1h",
+		RoleDescriptors: map[string]APIKeyRoleDescriptor{
+			"reader": {Cluster: []string{"monitor"}, Indices: []APIKeyIndexPrivileges{{Names: []string{"*"}, Privileges: []string{"read"}}}},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, APIKeyCreateResponse{ID: "id", Name: "key", Expiration: 1700000000000, APIKey: "secret", Encoded: "aWQ6c2VjcmV0"}, got)
+
+	_, err = NewMockClient(version.MustParse("6.8.0"), nil).CreateAPIKey(context.Background(), APIKeyCreateRequest{Name: "key"})
+	require.Error(t, err)
+}
@@ -0,0 +1,163 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+type SnapshotRepositoryClient interface {
+	// GetSnapshotRepositories returns the snapshot repositories registered in the cluster, indexed by name.
+	GetSnapshotRepositories(ctx context.Context) (SnapshotRepositories, error)
+	// PutSnapshotRepository registers or updates a snapshot repository. The repository is verified on the master and
+	// data nodes before the request completes.
+	PutSnapshotRepository(ctx context.Context, name string, repository SnapshotRepository) error
+	// DeleteSnapshotRepository unregisters a snapshot repository. Snapshots stored in the repository are not deleted.
+	DeleteSnapshotRepository(ctx context.Context, name string) error
+	// VerifySnapshotRepository checks that the repository is functional on all the master and data nodes.
+	VerifySnapshotRepository(ctx context.Context, name string) error
+	// GetSnapshot returns the given snapshot of the given repository.
+	GetSnapshot(ctx context.Context, repository, snapshot string) (Snapshot, error)
+	// GetSnapshots returns all the snapshots of the given repository, ordered by start time.
+	GetSnapshots(ctx context.Context, repository string) ([]Snapshot, error)
+	// RestoreSnapshot starts the restore of the given indices of a snapshot, without the global state of the cluster.
+	// It does not wait for the restore to complete.
+	RestoreSnapshot(ctx context.Context, repository, snapshot string, indices []string) error
+	// RestoreSnapshotIndex restores an index of a snapshot under a new name, and waits for the restore to complete.
+	// The restored index has no replicas and no aliases.
+	RestoreSnapshotIndex(ctx context.Context, repository, snapshot, index, restoredIndex string) error
+	// DeleteIndex deletes the given index.
+	DeleteIndex(ctx context.Context, index string) error
+	// GetSLMPolicies returns the snapshot lifecycle management policies, indexed by name.
+	// Introduced in: Elasticsearch 7.4.0
+	GetSLMPolicies(ctx context.Context) (SLMPolicies, error)
+}
+
+// SnapshotRepositories is the response of the get snapshot repository API.
+type SnapshotRepositories map[string]SnapshotRepository
+
+// SnapshotRepository models a snapshot repository. Elasticsearch returns all the settings values as strings.
+type SnapshotRepository struct {
+	Type     string                 `json:"type"`
+	Settings map[string]interface{} `json:"settings,omitempty"`
+}
+
+// SnapshotSuccessState is the state of a snapshot which completed successfully.
+const SnapshotSuccessState = "SUCCESS"
+
+// Snapshot models the subset of a snapshot description used by the operator.
+type Snapshot struct {
+	Snapshot          string   `json:"snapshot"`
+	State             string   `json:"state"`
+	Indices           []string `json:"indices"`
+	StartTimeInMillis int64    `json:"start_time_in_millis,omitempty"`
+}
+
+// SLMPolicies is the response of the get snapshot lifecycle policy API.
+type SLMPolicies map[string]SLMPolicy
+
+// SLMPolicy models the subset of a snapshot lifecycle management policy used by the operator.
+type SLMPolicy struct {
+	Policy struct {
+		Repository string `json:"repository"`
+	} `json:"policy"`
+	LastSuccess *SLMInvocation `json:"last_success,omitempty"`
+	LastFailure *SLMInvocation `json:"last_failure,omitempty"`
+}
+
+// SLMInvocation is the outcome of a snapshot taken by a snapshot lifecycle management policy.
+type SLMInvocation struct {
+	SnapshotName string `json:"snapshot_name"`
+	// Time in milliseconds since epoch.
+	Time    int64  `json:"time"`
+	Details string `json:"details,omitempty"`
+}
+
+type snapshotsResponse struct {
+	Snapshots []Snapshot `json:"snapshots"`
+}
+
+type restoreSnapshotRequest struct {
+	Indices            string                 `json:"indices"`
+	IncludeGlobalState bool                   `json:"include_global_state"`
+	IncludeAliases     bool                   `json:"include_aliases"`
+	RenamePattern      string                 `json:"rename_pattern,omitempty"`
+	RenameReplacement  string                 `json:"rename_replacement,omitempty"`
+	IndexSettings      map[string]interface{} `json:"index_settings,omitempty"`
+}
+
+func (c *clientV6) GetSnapshotRepositories(ctx context.Context) (SnapshotRepositories, error) {
+	var repositories SnapshotRepositories
+	err := c.get(ctx, "/_snapshot", &repositories)
+	return repositories, err
+}
+
+func (c *clientV6) PutSnapshotRepository(ctx context.Context, name string, repository SnapshotRepository) error {
+	return c.put(ctx, fmt.Sprintf("/_snapshot/%s", url.PathEscape(name)), repository, nil)
+}
+
+func (c *clientV6) DeleteSnapshotRepository(ctx context.Context, name string) error {
+	return c.delete(ctx, fmt.Sprintf("/_snapshot/%s", url.PathEscape(name)))
+}
+
+func (c *clientV6) VerifySnapshotRepository(ctx context.Context, name string) error {
+	return c.post(ctx, fmt.Sprintf("/_snapshot/%s/_verify", url.PathEscape(name)), nil, nil)
+}
+
+func (c *clientV6) GetSnapshot(ctx context.Context, repository, snapshot string) (Snapshot, error) {
+	var response snapshotsResponse
+	if err := c.get(ctx, fmt.Sprintf("/_snapshot/%s/%s", url.PathEscape(repository), url.PathEscape(snapshot)), &response); err != nil {
+		return Snapshot{}, err
+	}
+	if len(response.Snapshots) != 1 {
+		return Snapshot{}, fmt.Errorf("expected 1 snapshot %s in repository %s, got %d", snapshot, repository, len(response.Snapshots))
+	}
+	return response.Snapshots[0], nil
+}
+
+func (c *clientV6) GetSnapshots(ctx context.Context, repository string) ([]Snapshot, error) {
+	var response snapshotsResponse
+	if err := c.get(ctx, fmt.Sprintf("/_snapshot/%s/_all", url.PathEscape(repository)), &response); err != nil {
+		return nil, err
+	}
+	return response.Snapshots, nil
+}
+
+func (c *clientV6) RestoreSnapshot(ctx context.Context, repository, snapshot string, indices []string) error {
+	return c.post(ctx,
+		fmt.Sprintf("/_snapshot/%s/%s/_restore", url.PathEscape(repository), url.PathEscape(snapshot)),
+		restoreSnapshotRequest{
+			Indices:        strings.Join(indices, ","),
+			IncludeAliases: true,
+		},
+		nil,
+	)
+}
+
+func (c *clientV6) RestoreSnapshotIndex(ctx context.Context, repository, snapshot, index, restoredIndex string) error {
+	return c.post(ctx,
+		fmt.Sprintf("/_snapshot/%s/%s/_restore?wait_for_completion=true", url.PathEscape(repository), url.PathEscape(snapshot)),
+		restoreSnapshotRequest{
+			Indices:           index,
+			RenamePattern:     ".+",
+			RenameReplacement: restoredIndex,
+			IndexSettings:     map[string]interface{}{"index.number_of_replicas": 0},
+		},
+		nil,
+	)
+}
+
+func (c *clientV6) DeleteIndex(ctx context.Context, index string) error {
+	return c.delete(ctx, "/"+url.PathEscape(index))
+}
+
+func (c *clientV6) GetSLMPolicies(ctx context.Context) (SLMPolicies, error) {
+	var policies SLMPolicies
+	err := c.get(ctx, "/_slm/policy", &policies)
+	return policies, err
+}
@@ -0,0 +1,171 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+func TestClient_GetSnapshotRepositories(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/_snapshot", req.URL.Path)
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(strings.NewReader(`{"backups":{"type":"s3","settings":{"bucket":"my-bucket","compress":"true"}}}`)),
+			Header:     make(http.Header),
+			Request:    req,
+		}
+	})
+	repositories, err := testClient.GetSnapshotRepositories(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, SnapshotRepositories{
+		"backups": {Type: "s3", Settings: map[string]interface{}{"bucket": "my-bucket", "compress": "true"}},
+	}, repositories)
+}
+
+func TestClient_PutSnapshotRepository(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPut, req.Method)
+		require.Equal(t, "/_snapshot/backups", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"type":"s3","settings":{"bucket":"my-bucket"}}`, string(body))
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(strings.NewReader(`{"acknowledged":true}`)),
+			Header:     make(http.Header),
+			Request:    req,
+		}
+	})
+	err := testClient.PutSnapshotRepository(context.Background(), "backups", SnapshotRepository{
+		Type:     "s3",
+		Settings: map[string]interface{}{"bucket": "my-bucket"},
+	})
+	require.NoError(t, err)
+}
+
+func TestClient_DeleteSnapshotRepository(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodDelete, req.Method)
+		require.Equal(t, "/_snapshot/backups", req.URL.Path)
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(strings.NewReader(`{"acknowledged":true}`)),
+			Header:     make(http.Header),
+			Request:    req,
+		}
+	})
+	require.NoError(t, testClient.DeleteSnapshotRepository(context.Background(), "backups"))
+}
+
+func TestClient_GetSnapshot(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/_snapshot/backups/daily-2024.10.01", req.URL.Path)
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(strings.NewReader(`{"snapshots":[{"snapshot":"daily-2024.10.01","uuid":"abc","state":"SUCCESS","indices":["logs","metrics"]}]}`)),
+			Header:     make(http.Header),
+			Request:    req,
+		}
+	})
+	snapshot, err := testClient.GetSnapshot(context.Background(), "backups", "daily-2024.10.01")
+	require.NoError(t, err)
+	require.Equal(t, Snapshot{Snapshot: "daily-2024.10.01", State: SnapshotSuccessState, Indices: []string{"logs", "metrics"}}, snapshot)
+}
+
+func TestClient_RestoreSnapshotIndex(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, "/_snapshot/backups/daily-2024.10.01/_restore", req.URL.Path)
+		require.Equal(t, "true", req.URL.Query().Get("wait_for_completion"))
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"indices": "logs",
+			"include_global_state": false,
+			"include_aliases": false,
+			"rename_pattern": ".+",
+			"rename_replacement": "restored-logs",
+			"index_settings": {"index.number_of_replicas": 0}
+		}`, string(body))
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(strings.NewReader(`{"snapshot":{"snapshot":"daily-2024.10.01","indices":["restored-logs"]}}`)),
+			Header:     make(http.Header),
+			Request:    req,
+		}
+	})
+	require.NoError(t, testClient.RestoreSnapshotIndex(context.Background(), "backups", "daily-2024.10.01", "logs", "restored-logs"))
+}
+
+func TestClient_GetSnapshots(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/_snapshot/backups/_all", req.URL.Path)
+		return &http.Response{
+			StatusCode: 200,
+			Body: io.NopCloser(strings.NewReader(`{"snapshots":[` +
+				`{"snapshot":"daily-2024.09.30","state":"SUCCESS","indices":["logs"],"start_time_in_millis":1727654400000},` +
+				`{"snapshot":"daily-2024.10.01","state":"FAILED","indices":["logs"],"start_time_in_millis":1727740800000}]}`)),
+			Header:  make(http.Header),
+			Request: req,
+		}
+	})
+	snapshots, err := testClient.GetSnapshots(context.Background(), "backups")
+	require.NoError(t, err)
+	require.Equal(t, []Snapshot{
+		{Snapshot: "daily-2024.09.30", State: SnapshotSuccessState, Indices: []string{"logs"}, StartTimeInMillis: 1727654400000},
+		{Snapshot: "daily-2024.10.01", State: "FAILED", Indices: []string{"logs"}, StartTimeInMillis: 1727740800000},
+	}, snapshots)
+}
+
+func TestClient_RestoreSnapshot(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, "/_snapshot/backups/daily-2024.10.01/_restore", req.URL.Path)
+		require.Empty(t, req.URL.Query().Get("wait_for_completion"))
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"indices": "logs-*,-.*", "include_global_state": false, "include_aliases": true}`, string(body))
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(strings.NewReader(`{"accepted":true}`)),
+			Header:     make(http.Header),
+			Request:    req,
+		}
+	})
+	require.NoError(t, testClient.RestoreSnapshot(context.Background(), "backups", "daily-2024.10.01", []string{"logs-*", "-.*"}))
+}
+
+func TestClient_GetSLMPolicies(t *testing.T) {
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/_slm/policy", req.URL.Path)
+		return &http.Response{
+			StatusCode: 200,
+			Body: io.NopCloser(strings.NewReader(`{"daily":{"version":1,"policy":{"name":"<daily-{now/d}>","repository":"backups"},` +
+				`"last_success":{"snapshot_name":"daily-2024.10.01","time":1727740800000},` +
+				`"last_failure":{"snapshot_name":"daily-2024.09.30","time":1727654400000,"details":"boom"}}}`)),
+			Header:  make(http.Header),
+			Request: req,
+		}
+	})
+	policies, err := testClient.GetSLMPolicies(context.Background())
+	require.NoError(t, err)
+	require.Len(t, policies, 1)
+	require.Equal(t, "backups", policies["daily"].Policy.Repository)
+	require.Equal(t, &SLMInvocation{SnapshotName: "daily-2024.10.01", Time: 1727740800000}, policies["daily"].LastSuccess)
+	require.Equal(t, &SLMInvocation{SnapshotName: "daily-2024.09.30", Time: 1727654400000, Details: "boom"}, policies["daily"].LastFailure)
+}
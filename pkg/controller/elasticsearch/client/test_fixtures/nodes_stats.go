@@ -77,4 +77,72 @@ const (
     }
   }
 }`
+
+	WriteThreadPoolStatsSample = `
+{
+  "nodes" : {
+    "Rt-o5-ZBQaq-Nkhhy0p7JA" : {
+      "name" : "elasticsearch-sample-es-ingest-0",
+      "thread_pool" : {
+        "write" : {
+          "threads" : 2,
+          "queue" : 12,
+          "active" : 2,
+          "rejected" : 3,
+          "largest" : 2,
+          "completed" : 1048
+        }
+      }
+    }
+  }
+}
+`
+
+	ResourceStatsSample = `
+{
+  "nodes" : {
+    "Rt-o5-ZBQaq-Nkhhy0p7JA" : {
+      "name" : "elasticsearch-sample-es-default-0",
+      "jvm" : {
+        "uptime_in_millis" : 3600000,
+        "mem" : {
+          "heap_used_percent" : 87
+        },
+        "gc" : {
+          "collectors" : {
+            "old" : {
+              "collection_count" : 12,
+              "collection_time_in_millis" : 2400
+            }
+          }
+        }
+      },
+      "os" : {
+        "cpu" : {
+          "percent" : 64
+        },
+        "cgroup" : {
+          "cpu" : {
+            "stat" : {
+              "number_of_elapsed_periods" : 36000,
+              "number_of_times_throttled" : 9000,
+              "time_throttled_nanos" : 1200000000
+            }
+          }
+        }
+      },
+      "indexing_pressure" : {
+        "memory" : {
+          "total" : {
+            "combined_coordinating_and_primary_in_bytes" : 1048576,
+            "coordinating_rejections" : 2,
+            "primary_rejections" : 1,
+            "replica_rejections" : 0
+          }
+        }
+      }
+    }
+  }
+}
+`
 )
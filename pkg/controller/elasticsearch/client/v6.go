@@ -6,6 +6,7 @@ package client
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/url"
 
@@ -18,6 +19,9 @@ import (
 
 var errNotSupportedInEs6x = errors.New("not supported in Elasticsearch 6.x")
 
+// indexingPressureStatsMinVersion is the first version of Elasticsearch reporting indexing pressure statistics.
+var indexingPressureStatsMinVersion = version.MinFor(7, 9, 0)
+
 type clientV6 struct {
 	baseClient
 }
@@ -26,6 +30,18 @@ func (c *clientV6) InvalidateCrossClusterAPIKey(context.Context, string) error {
 	return errNotSupportedInEs6x
 }
 
+func (c *clientV6) PutIndexTemplate(_ context.Context, _ string, _ IndexTemplate) error {
+	return errNotSupportedInEs6x
+}
+
+func (c *clientV6) GetDataStream(_ context.Context, _ string) (DataStream, error) {
+	return DataStream{}, errNotSupportedInEs6x
+}
+
+func (c *clientV6) CreateDataStream(_ context.Context, _ string) error {
+	return errNotSupportedInEs6x
+}
+
 func (c *clientV6) CreateCrossClusterAPIKey(_ context.Context, _ CrossClusterAPIKeyCreateRequest) (CrossClusterAPIKeyCreateResponse, error) {
 	return CrossClusterAPIKeyCreateResponse{}, errNotSupportedInEs6x
 }
@@ -138,6 +154,24 @@ func (c *clientV6) GetNodesStats(ctx context.Context) (NodesStats, error) {
 	return nodesStats, err
 }
 
+func (c *clientV6) GetWriteThreadPoolStats(ctx context.Context) (NodesStats, error) {
+	var nodesStats NodesStats
+	err := c.get(ctx, "/_nodes/_all/stats/thread_pool?filter_path=nodes.*.name,nodes.*.thread_pool.write", &nodesStats)
+	return nodesStats, err
+}
+
+func (c *clientV6) GetResourceStats(ctx context.Context) (NodesStats, error) {
+	metrics := "jvm,os"
+	filterPath := "nodes.*.name,nodes.*.jvm.uptime_in_millis,nodes.*.jvm.mem.heap_used_percent,nodes.*.jvm.gc.collectors.old,nodes.*.os.cpu.percent,nodes.*.os.cgroup.cpu.stat"
+	if c.version.GTE(indexingPressureStatsMinVersion) {
+		metrics += ",indexing_pressure"
+		filterPath += ",nodes.*.indexing_pressure.memory.total"
+	}
+	var nodesStats NodesStats
+	err := c.get(ctx, fmt.Sprintf("/_nodes/_all/stats/%s?filter_path=%s", metrics, filterPath), &nodesStats)
+	return nodesStats, err
+}
+
 func (c *clientV6) UpdateRemoteClusterSettings(ctx context.Context, settings RemoteClustersSettings) error {
 	return c.put(ctx, "/_cluster/settings", &settings, nil)
 }
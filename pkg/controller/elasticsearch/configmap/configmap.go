@@ -19,6 +19,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/nodespec"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/services"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
 )
 
 // NewConfigMapWithData constructs a new config map with the given data
@@ -35,7 +36,7 @@ func NewConfigMapWithData(cm, es types.NamespacedName, data map[string]string) c
 
 // ReconcileScriptsConfigMap reconciles a configmap containing scripts and related configuration used by
 // init containers and readiness probe.
-func ReconcileScriptsConfigMap(ctx context.Context, c k8s.Client, es esv1.Elasticsearch) error {
+func ReconcileScriptsConfigMap(ctx context.Context, c k8s.Client, es esv1.Elasticsearch, suspendedPodNames set.StringSet) error {
 	span, ctx := apm.StartSpan(ctx, "reconcile_scripts", tracing.SpanTypeApp)
 	defer span.End()
 
@@ -49,17 +50,27 @@ func ReconcileScriptsConfigMap(ctx context.Context, c k8s.Client, es esv1.Elasti
 		return err
 	}
 
+	data := map[string]string{
+		nodespec.LegacyReadinessProbeScriptConfigKey: nodespec.LegacyReadinessProbeScript,
+		nodespec.ReadinessPortProbeScriptConfigKey:   nodespec.ReadinessPortProbeScript,
+		nodespec.PreStopHookScriptConfigKey:          preStopScript,
+		initcontainer.PrepareFsScriptConfigKey:       fsScript,
+		initcontainer.SuspendScriptConfigKey:         initcontainer.SuspendScript,
+		initcontainer.SuspendedHostsFile:             initcontainer.RenderSuspendConfiguration(suspendedPodNames),
+	}
+	// only add the backup hook script if requested, to not restart the Pods of the other clusters
+	if es.HasVeleroBackupHooks() {
+		preBackupScript, err := nodespec.RenderPreBackupHookScript(services.InternalServiceURL(es))
+		if err != nil {
+			return err
+		}
+		data[nodespec.PreBackupHookScriptConfigKey] = preBackupScript
+	}
+
 	scriptsConfigMap := NewConfigMapWithData(
 		types.NamespacedName{Namespace: es.Namespace, Name: esv1.ScriptsConfigMap(es.Name)},
 		k8s.ExtractNamespacedName(&es),
-		map[string]string{
-			nodespec.LegacyReadinessProbeScriptConfigKey: nodespec.LegacyReadinessProbeScript,
-			nodespec.ReadinessPortProbeScriptConfigKey:   nodespec.ReadinessPortProbeScript,
-			nodespec.PreStopHookScriptConfigKey:          preStopScript,
-			initcontainer.PrepareFsScriptConfigKey:       fsScript,
-			initcontainer.SuspendScriptConfigKey:         initcontainer.SuspendScript,
-			initcontainer.SuspendedHostsFile:             initcontainer.RenderSuspendConfiguration(es),
-		},
+		data,
 	)
 
 	return ReconcileConfigMap(ctx, c, es, scriptsConfigMap)
@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -56,7 +57,8 @@ func HandleDownscale(
 	downscaleState := newDownscaleState(actualPods, downscaleCtx.es)
 
 	// compute the list of StatefulSet downscales and deletions to perform
-	downscales, deletions := calculateDownscales(downscaleCtx.parentCtx, *downscaleState, expectedStatefulSets, actualStatefulSets, downscaleBudgetFilter)
+	downscales, deletions := calculateDownscales(downscaleCtx.parentCtx, downscaleState, expectedStatefulSets, actualStatefulSets, downscaleBudgetFilter)
+	reportBlockedDownscales(downscaleCtx.reconcileState, downscaleState.blockedDownscales)
 
 	// remove actual StatefulSets that should not exist anymore (already downscaled to 0 in the past)
 	// this is safe thanks to expectations: we're sure 0 actual replicas means 0 corresponding pods exist
@@ -68,9 +70,24 @@ func HandleDownscale(
 	// if leaving nodes is empty this should cancel any ongoing shutdowns
 	leavingNodes := leavingNodeNames(downscales)
 	terminatingNodes := k8s.PodNames(k8s.TerminatingPods(actualPods))
+	// do not cancel the shutdowns relocating the data of ephemeral nodes during rolling upgrades
+	drainingNodes, err := ephemeralPodsToUpgrade(downscaleCtx.k8sClient, downscaleCtx.es, actualStatefulSets)
+	if err != nil {
+		return results.WithError(err)
+	}
+	terminatingNodes = append(terminatingNodes, drainingNodes...)
+	// nor the shutdowns relocating the data of the Pods scheduled on cordoned Kubernetes nodes
+	terminatingNodes = append(terminatingNodes, downscaleCtx.cordonedPods...)
 	if err := downscaleCtx.nodeShutdown.ReconcileShutdowns(downscaleCtx.parentCtx, leavingNodes, terminatingNodes); err != nil {
 		return results.WithError(err)
 	}
+	if startedNodes := nodesWithoutShutdownProgress(downscaleCtx.es, leavingNodes); len(startedNodes) > 0 {
+		downscaleCtx.reconcileState.AddEvent(
+			corev1.EventTypeNormal,
+			events.EventReasonDataMigrationStarted,
+			fmt.Sprintf("Migrating data away from nodes to be removed: %s", strings.Join(startedNodes, ", ")),
+		)
+	}
 
 	for _, downscale := range downscales {
 		// attempt the StatefulSet downscale (may or may not remove nodes)
@@ -102,7 +119,7 @@ func podsToDownscale(
 ) ([]ssetDownscale, es_sset.StatefulSetList) {
 	downscaleState := newDownscaleState(actualPods, es)
 	// compute the list of StatefulSet downscales and deletions to perform
-	return calculateDownscales(ctx, *downscaleState, expectedStatefulSets, actualStatefulSets, downscaleFilter)
+	return calculateDownscales(ctx, downscaleState, expectedStatefulSets, actualStatefulSets, downscaleFilter)
 }
 
 // deleteStatefulSets deletes the given StatefulSets along with their associated resources.
@@ -117,7 +134,7 @@ func deleteStatefulSets(ctx context.Context, toDelete es_sset.StatefulSetList, k
 
 // calculateDownscales compares expected and actual StatefulSets to return a list of StatefulSets
 // that can be downscaled (replica decrease) or deleted (no replicas).
-func calculateDownscales(ctx context.Context, state downscaleState, expectedStatefulSets es_sset.StatefulSetList, actualStatefulSets es_sset.StatefulSetList, downscaleFilter downscaleFilter) (downscales []ssetDownscale, deletions es_sset.StatefulSetList) {
+func calculateDownscales(ctx context.Context, state *downscaleState, expectedStatefulSets es_sset.StatefulSetList, actualStatefulSets es_sset.StatefulSetList, downscaleFilter downscaleFilter) (downscales []ssetDownscale, deletions es_sset.StatefulSetList) {
 	expectedStatefulSetsNames := expectedStatefulSets.Names()
 	for _, actualSset := range actualStatefulSets {
 		actualReplicas := sset.GetReplicas(actualSset)
@@ -136,7 +153,7 @@ func calculateDownscales(ctx context.Context, state downscaleState, expectedStat
 		case expectedReplicas < actualReplicas:
 			// the StatefulSet should be downscaled
 			requestedDeletes := actualReplicas - expectedReplicas
-			allowedDeletes := downscaleFilter(ctx, &state, actualSset, requestedDeletes)
+			allowedDeletes := downscaleFilter(ctx, state, actualSset, requestedDeletes)
 			if allowedDeletes == 0 {
 				continue
 			}
@@ -154,6 +171,47 @@ func calculateDownscales(ctx context.Context, state downscaleState, expectedStat
 	return downscales, deletions
 }
 
+// reportBlockedDownscales emits an event for each StatefulSet whose downscale is blocked to preserve the availability
+// of the cluster.
+func reportBlockedDownscales(reconcileState *reconcile.State, blockedDownscales map[string]string) {
+	names := make([]string, 0, len(blockedDownscales))
+	for name := range blockedDownscales {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		reconcileState.AddEvent(
+			corev1.EventTypeNormal,
+			events.EventReasonDownscaleBlocked,
+			fmt.Sprintf("Downscale of StatefulSet %s blocked: %s", name, blockedDownscales[name]),
+		)
+	}
+}
+
+// nodesWithoutShutdownProgress returns the sorted names of the given nodes for which no shutdown progress was reported
+// in the status of the cluster yet.
+func nodesWithoutShutdownProgress(es esv1.Elasticsearch, nodes []string) []string {
+	var result []string
+	for _, node := range nodes {
+		switch previousShutdownStatus(es, node) {
+		case "", esclient.ShutdownNotStarted:
+			result = append(result, node)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// previousShutdownStatus returns the shutdown status of the given node reported in the status of the cluster, if any.
+func previousShutdownStatus(es esv1.Elasticsearch, node string) esclient.ShutdownStatus {
+	for _, downscaledNode := range es.Status.InProgressOperations.DownscaleOperation.Nodes {
+		if downscaledNode.Name == node {
+			return esclient.ShutdownStatus(downscaledNode.ShutdownStatus)
+		}
+	}
+	return ""
+}
+
 type downscaleFilter func(_ context.Context, _ *downscaleState, _ appsv1.StatefulSet, _ int32) int32
 
 // noDownscaleFilter is a filter which does not remove any Pod. It can be used to compute the full list of
@@ -169,6 +227,7 @@ func downscaleBudgetFilter(ctx context.Context, state *downscaleState, actualSse
 	allowedDeletes, reason := checkDownscaleInvariants(*state, actualSset, requestedDeletes)
 	if allowedDeletes == 0 {
 		ssetLogger(ctx, actualSset).V(1).Info("Cannot downscale StatefulSet", "reason", reason)
+		state.recordBlockedDownscale(actualSset, reason)
 		return 0
 	}
 	state.recordNodeRemoval(actualSset, allowedDeletes)
@@ -244,6 +303,13 @@ func calculatePerformableDownscale(
 		case esclient.ShutdownComplete:
 			// shutdown including data migration over: allow pod to be removed
 			performableDownscale.targetReplicas--
+			if previousShutdownStatus(ctx.es, node) != esclient.ShutdownComplete {
+				ctx.reconcileState.AddEvent(
+					corev1.EventTypeNormal,
+					events.EventReasonDataMigrationCompleted,
+					fmt.Sprintf("Data migrated away from node %s, removing it", node),
+				)
+			}
 		case esclient.ShutdownStalled:
 			// shutdown stalled this can require user interaction: bubble up via event
 			ctx.reconcileState.
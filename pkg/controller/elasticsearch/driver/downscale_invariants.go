@@ -49,6 +49,8 @@ type downscaleState struct {
 	removalsAllowed *int32
 	// masterRemovalInProgress indicates whether a master node is in the process of being removed already.
 	masterRemovalInProgress bool
+	// blockedDownscales records the reason why the StatefulSets that cannot be downscaled yet are blocked, indexed by name.
+	blockedDownscales map[string]string
 }
 
 // newDownscaleState creates a new downscaleState.
@@ -67,6 +69,14 @@ func newDownscaleState(actualPods []corev1.Pod, es esv1.Elasticsearch) *downscal
 	}
 }
 
+// recordBlockedDownscale records that the given StatefulSet cannot be downscaled for the given reason.
+func (s *downscaleState) recordBlockedDownscale(statefulSet appsv1.StatefulSet, reason string) {
+	if s.blockedDownscales == nil {
+		s.blockedDownscales = map[string]string{}
+	}
+	s.blockedDownscales[statefulSet.Name] = reason
+}
+
 func calculateRemovalsAllowed(nodesReady, desiredNodes int32, maxUnavailable *int32) *int32 {
 	if maxUnavailable == nil {
 		return nil
@@ -20,6 +20,7 @@ import (
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/comparison"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/expectations"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	controllerscheme "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/scheme"
@@ -678,23 +679,49 @@ func Test_calculateDownscales(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotDownscales, gotDeletions := calculateDownscales(context.Background(), downscaleState{}, tt.expectedStatefulSets, tt.actualStatefulSets, downscaleBudgetFilter)
+			gotDownscales, gotDeletions := calculateDownscales(context.Background(), &downscaleState{}, tt.expectedStatefulSets, tt.actualStatefulSets, downscaleBudgetFilter)
 			require.Equal(t, tt.wantDownscales, gotDownscales)
 			require.Equal(t, tt.wantDeletions, gotDeletions)
 		})
 	}
 }
 
+func Test_reportBlockedDownscales(t *testing.T) {
+	state := &downscaleState{runningMasters: 1}
+	require.Equal(t, int32(0), downscaleBudgetFilter(context.Background(), state, ssetMaster3Replicas, 1))
+	require.Equal(t, int32(0), downscaleBudgetFilter(context.Background(), state, sset.TestSset{Name: "a-master", Master: true}.Build(), 1))
+
+	reconcileState := reconcile.MustNewState(esv1.Elasticsearch{})
+	reportBlockedDownscales(reconcileState, state.blockedDownscales)
+	require.Equal(t, []events.Event{
+		{EventType: corev1.EventTypeNormal, Reason: events.EventReasonDownscaleBlocked, Message: "Downscale of StatefulSet a-master blocked: " + AtLeastOneRunningMasterInvariant},
+		{EventType: corev1.EventTypeNormal, Reason: events.EventReasonDownscaleBlocked, Message: "Downscale of StatefulSet ssetMaster3Replicas blocked: " + AtLeastOneRunningMasterInvariant},
+	}, reconcileState.Events())
+}
+
+func Test_nodesWithoutShutdownProgress(t *testing.T) {
+	es := esv1.Elasticsearch{Status: esv1.ElasticsearchStatus{InProgressOperations: esv1.InProgressOperations{
+		DownscaleOperation: esv1.DownscaleOperation{Nodes: []esv1.DownscaledNode{
+			{Name: "node-1", ShutdownStatus: "NOT_STARTED"},
+			{Name: "node-2", ShutdownStatus: "IN_PROGRESS"},
+			{Name: "node-3", ShutdownStatus: "COMPLETE"},
+		}},
+	}}}
+	require.Equal(t, []string{"node-0", "node-1"}, nodesWithoutShutdownProgress(es, []string{"node-3", "node-2", "node-1", "node-0"}))
+	require.Nil(t, nodesWithoutShutdownProgress(es, []string{"node-2"}))
+}
+
 func Test_calculatePerformableDownscale(t *testing.T) {
 	type args struct {
 		ctx       downscaleContext
 		downscale ssetDownscale
 	}
 	tests := []struct {
-		name    string
-		args    args
-		want    ssetDownscale
-		wantErr bool
+		name       string
+		args       args
+		want       ssetDownscale
+		wantErr    bool
+		wantEvents []events.Event
 	}{
 		{
 			name: "no downscale planned",
@@ -718,20 +745,26 @@ func Test_calculatePerformableDownscale(t *testing.T) {
 			name: "downscale possible from 3 to 2",
 			args: args{
 				ctx: downscaleContext{
-					parentCtx:    context.Background(),
-					nodeShutdown: migration.NewShardMigration(es, &fakeESClient{}, migration.NewFakeShardLister(esclient.Shards{})),
+					parentCtx:      context.Background(),
+					reconcileState: reconcile.MustNewState(esv1.Elasticsearch{}),
+					nodeShutdown:   migration.NewShardMigration(es, &fakeESClient{}, migration.NewFakeShardLister(esclient.Shards{})),
 				},
 				downscale: ssetDownscale{
+					statefulSet:     sset.TestSset{Name: "default"}.Build(),
 					initialReplicas: 3,
 					targetReplicas:  2,
 					finalReplicas:   2,
 				},
 			},
 			want: ssetDownscale{
+				statefulSet:     sset.TestSset{Name: "default"}.Build(),
 				initialReplicas: 3,
 				targetReplicas:  2,
 				finalReplicas:   2,
 			},
+			wantEvents: []events.Event{
+				{EventType: corev1.EventTypeNormal, Reason: events.EventReasonDataMigrationCompleted, Message: "Data migrated away from node default-2, removing it"},
+			},
 		},
 		{
 			name: "downscale not possible: data migration not complete",
@@ -791,6 +824,9 @@ func Test_calculatePerformableDownscale(t *testing.T) {
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("calculatePerformableDownscale() got = %v, want %v", got, tt.want)
 			}
+			if tt.wantEvents != nil {
+				require.Equal(t, tt.wantEvents, tt.args.ctx.reconcileState.Events())
+			}
 		})
 	}
 }
@@ -31,6 +31,8 @@ type downscaleContext struct {
 	expectations   *expectations.Expectations
 	// ES cluster
 	es esv1.Elasticsearch
+	// cordonedPods are the names of the Pods scheduled on cordoned Kubernetes nodes, whose data is being relocated
+	cordonedPods []string
 
 	parentCtx context.Context
 }
@@ -45,6 +47,7 @@ func newDownscaleContext(
 	// ES cluster
 	es esv1.Elasticsearch,
 	nodeShutdown shutdown.Interface,
+	cordonedPods []string,
 ) downscaleContext {
 	return downscaleContext{
 		k8sClient:      k8sClient,
@@ -54,6 +57,7 @@ func newDownscaleContext(
 		reconcileState: reconcileState,
 		es:             es,
 		expectations:   expectations,
+		cordonedPods:   cordonedPods,
 		parentCtx:      ctx,
 	}
 }
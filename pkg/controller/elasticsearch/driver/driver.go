@@ -28,30 +28,39 @@ import (
 	commondriver "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/driver"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/expectations"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/gateway"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
 	commonlicense "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/license"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/restart"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/servicemesh"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/bootstrap"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/ccr"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/certificates"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/cleanup"
 	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/configmap"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/filesettings"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/hints"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/ingestautoscaling"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/initcontainer"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/license"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/network"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/networkpolicy"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/observer"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/remotecluster"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/securitycontext"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/services"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/settings"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/snapshotrepository"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/stackmon"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/user"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/zonespread"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/dev"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
@@ -129,16 +138,33 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 	results := reconciler.NewResult(ctx)
 	log := ulog.FromContext(ctx)
 
+	// manage one NodeSet per zone for the NodeSets spread across zones
+	d.ES = zonespread.Expand(d.ES)
+
+	// use the number of nodes decided by the operator for the ingest autoscaled NodeSets
+	d.ES = ingestautoscaling.ApplyCounts(d.ES)
+
+	// start the restart of the nodes requested by the user, if any, before their Pod templates are built
+	if err := restart.Start(ctx, d.Client, d.Recorder(), &d.ES); err != nil {
+		return results.WithError(err)
+	}
+	d.ReconcileState.UpdateRestart(d.ES.Status.Restart)
+
 	// garbage collect secrets attached to this cluster that we don't need anymore
 	if err := cleanup.DeleteOrphanedSecrets(ctx, d.Client, d.ES); err != nil {
 		return results.WithError(err)
 	}
 
-	if err := configmap.ReconcileScriptsConfigMap(ctx, d.Client, d.ES); err != nil {
+	suspendedPodNames, err := expectedSuspendedPodNames(d.Client, d.ES)
+	if err != nil {
+		return results.WithError(err)
+	}
+
+	if err := configmap.ReconcileScriptsConfigMap(ctx, d.Client, d.ES, suspendedPodNames); err != nil {
 		return results.WithError(err)
 	}
 
-	_, err := common.ReconcileService(ctx, d.Client, services.NewTransportService(d.ES), &d.ES)
+	_, err = common.ReconcileService(ctx, d.Client, services.NewTransportService(d.ES), &d.ES)
 	if err != nil {
 		return results.WithError(err)
 	}
@@ -150,6 +176,13 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 		}
 		return results.WithError(err)
 	}
+	if err := gateway.ReconcileRoute(ctx, d.Client, &d.ES, d.ES.Spec.HTTP, *externalService, d.ES.GetIdentityLabels()); err != nil {
+		return results.WithError(err)
+	}
+	// the operator must be able to reach Elasticsearch if mutual TLS is enforced by Istio
+	if err := servicemesh.ReconcilePeerAuthentication(ctx, d.Client, &d.ES, d.ES.Spec.HTTP, *externalService, d.ES.GetIdentityLabels(), network.HTTPPort); err != nil {
+		return results.WithError(err)
+	}
 
 	var internalService *corev1.Service
 	internalService, err = common.ReconcileService(ctx, d.Client, services.NewInternalService(d.ES), &d.ES)
@@ -174,12 +207,23 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 		results.WithError(k8s.DeleteResourceIfExists(ctx, d.Client, remoteClusterService))
 	}
 
+	// restrict the network traffic of the Pods to the flows required by the cluster and its associations
+	if err := networkpolicy.Reconcile(ctx, d.Client, d.ES, d.OperatorParameters.OperatorNamespace); err != nil {
+		results.WithError(err)
+	}
+
+	// re-link a recreated cluster to its existing data before any node is created
+	if err := bootstrap.RelinkClusterIdentity(ctx, d.Client, &d.ES, d.Recorder()); err != nil {
+		return results.WithError(err)
+	}
+
 	resourcesState, err := reconcile.NewResourcesStateFromAPI(d.Client, d.ES)
 	if err != nil {
 		return results.WithError(err)
 	}
 
 	warnUnsupportedDistro(resourcesState.AllPods, d.ReconcileState.Recorder)
+	warnSchedulingCompromises(d.ES, resourcesState.CurrentPods, d.ReconcileState.Recorder)
 
 	controllerUser, err := user.ReconcileUsersAndRoles(ctx, d.Client, d.ES, d.DynamicWatches(), d.Recorder(), d.OperatorParameters.PasswordHasher)
 	if err != nil {
@@ -200,6 +244,12 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 		return results
 	}
 
+	// copy the public HTTP certificates to the namespaces of the applications trusting Elasticsearch, without blocking
+	// the reconciliation of the cluster in case of error
+	if err := certificates.ReconcilePublishedHTTPCerts(ctx, d.Client, d.Recorder(), d.ES); err != nil {
+		results.WithError(err)
+	}
+
 	// start the ES observer
 	minVersion, err := version.MinInPods(resourcesState.CurrentPods, label.VersionLabelName)
 	if err != nil {
@@ -225,9 +275,12 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 		hasEndpoints,
 	)
 
+	observedHealthProbes := d.Observers.ObservedHealthProbes(k8s.ExtractNamespacedName(&d.ES))
+
 	// Always update the Elasticsearch state bits with the latest observed state.
 	d.ReconcileState.
 		UpdateClusterHealth(observedState()).         // Elasticsearch cluster health
+		UpdateHealthProbes(observedHealthProbes).     // Health probes results
 		UpdateAvailableNodes(*resourcesState).        // Available nodes
 		UpdateMinRunningVersion(ctx, *resourcesState) // Min running version
 
@@ -243,6 +296,9 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 	if res != nil && res.HasError() {
 		return results
 	}
+	if reconciler.BudgetFromContext(ctx).Checkpoint() {
+		return results.WithReconciliationState(reconciler.BudgetExhausted)
+	}
 
 	// Patch the Pods to add the expected node labels as annotations. Record the error, if any, but do not stop the
 	// reconciliation loop as we don't want to prevent other updates from being applied to the cluster.
@@ -306,7 +362,11 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 	// reconcile the Elasticsearch license (even if we assume the cluster might not respond to requests to cover the case of
 	// expired licenses where all health API responses are 403)
 	if hasEndpoints {
-		err = license.Reconcile(ctx, d.Client, d.ES, esClient, currentLicense)
+		var appliedLicense string
+		appliedLicense, err = license.Reconcile(ctx, d.Client, d.ES, esClient, currentLicense)
+		if appliedLicense != "" {
+			d.ReconcileState.AddEvent(corev1.EventTypeNormal, events.EventReasonLicenseApplied, fmt.Sprintf("Applied %s license", appliedLicense))
+		}
 		if err != nil {
 			msg := "Could not reconcile cluster license, re-queuing"
 			// only log an event if Elasticsearch is in a state where success of this API call can be expected. The API call itself
@@ -316,6 +376,8 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 				d.ReconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonUnexpected, fmt.Sprintf("%s: %s", msg, err.Error()))
 			}
 			results.WithReconciliationState(defaultRequeue.WithReason(msg))
+		} else if esReachable {
+			results.WithResults(d.reconcileLicenseExpiration(ctx, esClient, currentLicense, appliedLicense))
 		}
 	}
 
@@ -333,6 +395,41 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 		}
 	}
 
+	// reconcile snapshot repositories
+	if esReachable {
+		if err := snapshotrepository.Reconcile(ctx, d.Client, esClient, d.ES); err != nil {
+			msg := "Could not update snapshot repositories, re-queuing"
+			log.Info(msg, "err", err, "namespace", d.ES.Namespace, "es_name", d.ES.Name)
+			d.ReconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonUnexpected, fmt.Sprintf("%s: %s", msg, err.Error()))
+			results.WithReconciliationState(defaultRequeue.WithReason(msg))
+		}
+	}
+
+	// reconcile cross-cluster replication
+	if esReachable {
+		if err := ccr.Reconcile(ctx, d.Client, esClient, d.ES); err != nil {
+			msg := "Could not update cross-cluster replication, re-queuing"
+			log.Info(msg, "err", err, "namespace", d.ES.Namespace, "es_name", d.ES.Name)
+			d.ReconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonUnexpected, fmt.Sprintf("%s: %s", msg, err.Error()))
+			results.WithReconciliationState(defaultRequeue.WithReason(msg))
+		}
+	}
+
+	// verify snapshots periodically
+	if esReachable {
+		results.WithResults(d.verifySnapshots(ctx, esClient))
+	}
+
+	// adjust the number of nodes of the ingest autoscaled NodeSets
+	if esReachable {
+		results.WithResults(d.autoscaleIngestNodeSets(ctx, esClient))
+	}
+
+	// recommend resources for the Elasticsearch containers from the statistics of the nodes
+	if esReachable {
+		results.WithResults(d.recommendResources(ctx, esClient))
+	}
+
 	// Compute seed hosts based on current masters with a podIP
 	if err := settings.UpdateSeedHostsConfigMap(ctx, d.Client, d.ES, resourcesState.AllPods); err != nil {
 		return results.WithError(err)
@@ -351,7 +448,7 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 	keystoreParams.SecurityContext = &keystoreSecurityContext
 
 	// Set up a keystore with secure settings in an init container, if specified by the user.
-	// We are also using the keystore internally for the remote cluster API keys.
+	// We are also using the keystore internally for the remote cluster API keys and the secrets of the realms.
 	remoteClusterAPIKeys, err := apiKeyStoreSecretSource(ctx, &d.ES, d.Client)
 	if err != nil {
 		return results.WithError(err)
@@ -363,11 +460,12 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 		esv1.ESNamer,
 		label.NewLabels(k8s.ExtractNamespacedName(&d.ES)),
 		keystoreParams,
-		remoteClusterAPIKeys...,
+		append(remoteClusterAPIKeys, settings.RealmsSecureSettings(d.ES)...)...,
 	)
 	if err != nil {
 		return results.WithError(err)
 	}
+	results.WithReconciliationState(keystore.RefreshResult(&d.ES))
 
 	// set an annotation with the ClusterUUID, if bootstrapped
 	requeue, err := bootstrap.ReconcileClusterUUID(ctx, d.Client, &d.ES, esClient, esReachable)
@@ -378,6 +476,11 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 		results = results.WithReconciliationState(defaultRequeue.WithReason("Elasticsearch cluster UUID is not reconciled"))
 	}
 
+	// persist the cluster identity in a Secret that survives the deletion of the Elasticsearch resource, if requested
+	if err := bootstrap.ReconcileClusterIdentity(ctx, d.Client, d.ES, esClient, esReachable, d.Recorder()); err != nil {
+		results.WithError(err)
+	}
+
 	// reconcile beats config secrets if Stack Monitoring is defined
 	err = stackmon.ReconcileConfigSecrets(ctx, d.Client, d.ES)
 	if err != nil {
@@ -396,12 +499,26 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 
 	// we want to reconcile suspended Pods before we start reconciling node specs as this is considered a debugging and
 	// troubleshooting tool that does not follow the change budget restrictions
-	if err := reconcileSuspendedPods(ctx, d.Client, d.ES, d.Expectations); err != nil {
+	if err := reconcileSuspendedPods(ctx, d.Client, d.ES, suspendedPodNames, d.Expectations); err != nil {
 		return results.WithError(err)
 	}
 
+	// the unsafe bootstrap procedure runs against suspended Pods, don't touch the StatefulSets until it is over
+	unsafeBootstrapInProgress, err := bootstrap.ReconcileUnsafeBootstrap(ctx, d.Client, &d.ES, d.Recorder())
+	if err != nil {
+		return results.WithError(err)
+	}
+	if unsafeBootstrapInProgress {
+		return results.WithReconciliationState(defaultRequeue.WithReason("Unsafe bootstrap in progress"))
+	}
+
+	if reconciler.BudgetFromContext(ctx).Checkpoint() {
+		return results.WithReconciliationState(reconciler.BudgetExhausted)
+	}
+
 	// reconcile StatefulSets and nodes configuration
-	return results.WithResults(d.reconcileNodeSpecs(ctx, esReachable, esClient, d.ReconcileState, *resourcesState, keystoreResources))
+	results.WithResults(d.reconcileNodeSpecs(ctx, esReachable, esClient, d.ReconcileState, *resourcesState, keystoreResources))
+	return results.WithError(d.reconcileRestartProgress(ctx))
 }
 
 // apiKeyStoreSecretSource returns the Secret that holds the remote API keys, and which should be used as a secure settings source.
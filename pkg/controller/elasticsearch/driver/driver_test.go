@@ -165,6 +165,10 @@ func (f *fakeSecurityClient) GetServiceAccountCredentials(_ context.Context, nam
 	return serviceAccountCredential, nil
 }
 
+func (f *fakeSecurityClient) CreateAPIKey(_ context.Context, _ esclient.APIKeyCreateRequest) (esclient.APIKeyCreateResponse, error) {
+	return esclient.APIKeyCreateResponse{}, nil
+}
+
 func newFakeSecurityClient() *fakeSecurityClient {
 	return &fakeSecurityClient{
 		serviceAccountCredentials: make(map[string]esclient.ServiceAccountCredential),
@@ -0,0 +1,55 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	es_sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
+)
+
+// isEphemeralPod returns true if the given Pod belongs to one of the given ephemeral StatefulSets: its data does not
+// survive a restart and must be relocated to other nodes before the Pod is deleted.
+func isEphemeralPod(ephemeralStatefulSets set.StringSet, pod corev1.Pod) bool {
+	return ephemeralStatefulSets.Has(pod.Labels[label.StatefulSetNameLabelName])
+}
+
+// splitEphemeralPods splits the given Pods into the ones with persistent data and the ones with ephemeral data.
+func splitEphemeralPods(ephemeralStatefulSets set.StringSet, pods []corev1.Pod) ([]corev1.Pod, []corev1.Pod) {
+	var persistent, ephemeral []corev1.Pod
+	for _, pod := range pods {
+		if isEphemeralPod(ephemeralStatefulSets, pod) {
+			ephemeral = append(ephemeral, pod)
+		} else {
+			persistent = append(persistent, pod)
+		}
+	}
+	return persistent, ephemeral
+}
+
+// ephemeralPodsToUpgrade returns the names of the Pods of ephemeral NodeSets pending a rolling upgrade. The data of those
+// Pods may be in the process of being relocated through a node shutdown of type remove which must not be cancelled
+// by the downscale logic.
+func ephemeralPodsToUpgrade(c k8s.Client, es esv1.Elasticsearch, statefulSets es_sset.StatefulSetList) ([]string, error) {
+	ephemeralStatefulSets := es.EphemeralStatefulSets()
+	if ephemeralStatefulSets.Count() == 0 {
+		return nil, nil
+	}
+	var ephemeral es_sset.StatefulSetList
+	for _, statefulSet := range statefulSets {
+		if ephemeralStatefulSets.Has(statefulSet.Name) {
+			ephemeral = append(ephemeral, statefulSet)
+		}
+	}
+	pods, err := podsToUpgrade(c, ephemeral)
+	if err != nil {
+		return nil, err
+	}
+	return k8s.PodNames(pods), nil
+}
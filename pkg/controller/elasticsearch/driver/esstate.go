@@ -17,6 +17,8 @@ type ESState interface {
 	NodesInCluster(nodeNames []string) (bool, error)
 	// NodeNameToID returns a map of Elasticsearch node ID to node name.
 	NodeNameToID() (map[string]string, error)
+	// NodeAttributes returns a map of Elasticsearch node name to node attributes.
+	NodeAttributes() (map[string]map[string]string, error)
 	// ShardAllocationsEnabled returns true if shards allocation are enabled in the cluster.
 	ShardAllocationsEnabled() (bool, error)
 	// Health returns the health of the Elasticsearch cluster.
@@ -59,6 +61,8 @@ type memoizingNodes struct {
 	esClient     esclient.Client
 	ctx          context.Context
 	nodeNameToID map[string]string
+	// nodeAttributes are the attributes of the nodes, by node name
+	nodeAttributes map[string]map[string]string
 }
 
 // initialize requests Elasticsearch for nodes information, only once.
@@ -68,8 +72,10 @@ func (n *memoizingNodes) initialize() error {
 		return err
 	}
 	n.nodeNameToID = map[string]string{}
+	n.nodeAttributes = map[string]map[string]string{}
 	for id, node := range nodes.Nodes {
 		n.nodeNameToID[node.Name] = id
+		n.nodeAttributes[node.Name] = node.Attributes
 	}
 	return nil
 }
@@ -94,6 +100,14 @@ func (n *memoizingNodes) NodeNameToID() (map[string]string, error) {
 	return n.nodeNameToID, nil
 }
 
+// NodeAttributes returns the attributes of the nodes in the Elasticsearch cluster, by node name.
+func (n *memoizingNodes) NodeAttributes() (map[string]map[string]string, error) {
+	if err := initOnce(&n.once, n.initialize); err != nil {
+		return nil, err
+	}
+	return n.nodeAttributes, nil
+}
+
 // -- Shards allocation enabled
 
 // memoizingShardsAllocationEnabled provides shards allocation information.
@@ -110,7 +110,15 @@ func (f *fakeESClient) GetClusterHealthWaitForAllEvents(_ context.Context) (escl
 	return f.health, nil
 }
 
-func (f *fakeESClient) PutShutdown(_ context.Context, _ string, _ esclient.ShutdownType, _ string) error {
+func (f *fakeESClient) PutShutdown(_ context.Context, nodeID string, shutdownType esclient.ShutdownType, reason string) error {
+	if f.Shutdowns == nil {
+		f.Shutdowns = map[string]esclient.NodeShutdown{}
+	}
+	// keep the shutdowns of the test fixtures as they are
+	if _, exists := f.Shutdowns[nodeID]; exists {
+		return nil
+	}
+	f.Shutdowns[nodeID] = esclient.NodeShutdown{NodeID: nodeID, Type: string(shutdownType), Reason: reason, Status: esclient.ShutdownInProgress}
 	return nil
 }
 
@@ -125,8 +133,9 @@ func (f *fakeESClient) GetShutdown(_ context.Context, nodeID *string) (esclient.
 	return esclient.ShutdownResponse{Nodes: ns}, nil
 }
 
-func (f *fakeESClient) DeleteShutdown(_ context.Context, _ string) error {
+func (f *fakeESClient) DeleteShutdown(_ context.Context, nodeID string) error {
 	f.DeleteShutdownCalled = true
+	delete(f.Shutdowns, nodeID)
 	return nil
 }
 
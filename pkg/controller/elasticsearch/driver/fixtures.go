@@ -340,11 +340,16 @@ func (t testPod) toPodPtr() *corev1.Pod {
 }
 
 type testESState struct {
-	inCluster []string
-	health    client.Health
+	inCluster      []string
+	health         client.Health
+	nodeAttributes map[string]map[string]string
 	ESState
 }
 
+func (t *testESState) NodeAttributes() (map[string]map[string]string, error) {
+	return t.nodeAttributes, nil
+}
+
 func (t *testESState) ShardAllocationsEnabled() (bool, error) {
 	return true, nil
 }
@@ -0,0 +1,49 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/ingestautoscaling"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// autoscaleIngestNodeSets adjusts the number of nodes of the ingest autoscaled NodeSets according to the write thread
+// pool statistics of their nodes, reports the decisions in the status, and schedules the next evaluation.
+func (d *defaultDriver) autoscaleIngestNodeSets(ctx context.Context, esClient esclient.Client) *reconciler.Results {
+	results := &reconciler.Results{}
+	statuses, nextEvaluation, err := ingestautoscaling.Evaluate(ctx, esClient, d.ES, time.Now())
+	if err != nil {
+		msg := "Could not evaluate the ingest autoscaling, re-queuing"
+		ulog.FromContext(ctx).Info(msg, "err", err, "namespace", d.ES.Namespace, "es_name", d.ES.Name)
+		return results.WithReconciliationState(defaultRequeue.WithReason(msg))
+	}
+	if statuses == nil {
+		d.ReconcileState.UpdateIngestAutoscaling(nil)
+		return results
+	}
+	for _, status := range statuses {
+		previous := d.ES.Status.IngestAutoscalingStatusFor(status.NodeSet)
+		if previous == nil || previous.Count == status.Count {
+			continue
+		}
+		msg := fmt.Sprintf("Scaling NodeSet %s from %d to %d nodes based on the write thread pool statistics", status.NodeSet, previous.Count, status.Count)
+		ulog.FromContext(ctx).Info(msg, "namespace", d.ES.Namespace, "es_name", d.ES.Name)
+		d.ReconcileState.AddEvent(corev1.EventTypeNormal, events.EventReasonScaled, msg)
+	}
+	d.ReconcileState.UpdateIngestAutoscaling(statuses)
+	// apply the new number of nodes to the NodeSets reconciled next
+	d.ES.Status.IngestAutoscaling = statuses
+	d.ES = ingestautoscaling.ApplyCounts(d.ES)
+	return results.WithReconciliationState(reconciler.RequeueAfter(nextEvaluation).ReconciliationComplete())
+}
@@ -0,0 +1,90 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// reconcileLicenseExpiration reports the expiration of the license of the cluster, and schedules the next refresh of the
+// report. The license is retrieved again if a new license was just applied.
+func (d *defaultDriver) reconcileLicenseExpiration(
+	ctx context.Context,
+	esClient esclient.Client,
+	currentLicense esclient.License,
+	appliedLicense string,
+) *reconciler.Results {
+	results := &reconciler.Results{}
+	if appliedLicense != "" {
+		var err error
+		if currentLicense, err = esClient.GetLicense(ctx); err != nil {
+			msg := "Could not retrieve the applied license, re-queuing"
+			ulog.FromContext(ctx).Info(msg, "err", err, "namespace", d.ES.Namespace, "es_name", d.ES.Name)
+			return results.WithReconciliationState(defaultRequeue.WithReason(msg))
+		}
+	}
+	if refreshAfter := d.reportLicenseExpiration(currentLicense, time.Now()); refreshAfter > 0 {
+		results.WithReconciliationState(reconciler.RequeueAfter(refreshAfter).ReconciliationComplete())
+	}
+	return results
+}
+
+// reportLicenseExpiration reports the expiration of the license currently applied to the cluster as a condition. It
+// emits a warning event once a day during the warning period preceding the expiration, and once the license expired.
+// It returns the duration after which the condition must be refreshed, zero if it does not change anymore.
+func (d *defaultDriver) reportLicenseExpiration(l esclient.License, now time.Time) time.Duration {
+	if l.Type == "" {
+		return 0
+	}
+	if l.Type == string(esclient.ElasticsearchLicenseTypeBasic) || l.ExpiryDateInMillis == 0 {
+		d.ReconcileState.ReportCondition(esv1.LicenseActive, corev1.ConditionTrue, fmt.Sprintf("%s license does not expire", l.Type))
+		return 0
+	}
+
+	expiry := l.ExpiryTime().UTC()
+	remaining := expiry.Sub(now)
+	warningPeriod := d.OperatorParameters.LicenseExpiryWarningPeriod
+	var message, reason string
+	var refreshAfter time.Duration
+	switch {
+	case l.Status == "expired" || remaining <= 0:
+		message = fmt.Sprintf("%s license expired on %s", l.Type, expiry.Format(time.RFC3339))
+		reason = events.EventReasonLicenseExpired
+	case remaining <= warningPeriod:
+		message = fmt.Sprintf("%s license expires in %d day(s), on %s", l.Type, int(remaining.Hours()/24), expiry.Format(time.RFC3339))
+		reason = events.EventReasonLicenseExpiring
+		// the message changes every day, requeue to refresh it
+		refreshAfter = min(remaining, remaining%(24*time.Hour)+time.Second)
+	default:
+		message = fmt.Sprintf("%s license expires on %s", l.Type, expiry.Format(time.RFC3339))
+		if warningPeriod > 0 {
+			refreshAfter = remaining - warningPeriod
+		} else {
+			refreshAfter = remaining
+		}
+	}
+
+	status := corev1.ConditionTrue
+	if reason == events.EventReasonLicenseExpired {
+		status = corev1.ConditionFalse
+	}
+	if previous := d.ES.Status.Conditions.Index(esv1.LicenseActive); reason != "" &&
+		(previous < 0 || d.ES.Status.Conditions[previous].Message != message) {
+		// only emit an event when the message changes to not flood the event stream on each reconciliation
+		d.ReconcileState.AddEvent(corev1.EventTypeWarning, reason, message)
+	}
+	d.ReconcileState.ReportCondition(esv1.LicenseActive, status, message)
+	return refreshAfter
+}
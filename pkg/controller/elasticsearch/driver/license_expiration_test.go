@@ -0,0 +1,114 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+)
+
+func Test_defaultDriver_reportLicenseExpiration(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	license := func(typ, status string, expiry time.Time) esclient.License {
+		return esclient.License{Type: typ, Status: status, ExpiryDateInMillis: expiry.UnixMilli()}
+	}
+	tests := []struct {
+		name             string
+		license          esclient.License
+		previousMessage  string
+		wantStatus       corev1.ConditionStatus
+		wantMessage      string
+		wantEventReason  string
+		wantRefreshAfter time.Duration
+	}{
+		{
+			name:        "basic license",
+			license:     esclient.License{Type: "basic", Status: "active"},
+			wantStatus:  corev1.ConditionTrue,
+			wantMessage: "basic license does not expire",
+		},
+		{
+			name:             "license expiring after the warning period",
+			license:          license("enterprise", "active", now.Add(60*24*time.Hour)),
+			wantStatus:       corev1.ConditionTrue,
+			wantMessage:      "enterprise license expires on 2026-11-30T12:00:00Z",
+			wantRefreshAfter: 30 * 24 * time.Hour,
+		},
+		{
+			name:             "license expiring during the warning period",
+			license:          license("trial", "active", now.Add(5*24*time.Hour+3*time.Hour)),
+			wantStatus:       corev1.ConditionTrue,
+			wantMessage:      "trial license expires in 5 day(s), on 2026-10-06T15:00:00Z",
+			wantEventReason:  events.EventReasonLicenseExpiring,
+			wantRefreshAfter: 3*time.Hour + time.Second,
+		},
+		{
+			name:             "no new event if the message did not change",
+			license:          license("trial", "active", now.Add(5*24*time.Hour+3*time.Hour)),
+			previousMessage:  "trial license expires in 5 day(s), on 2026-10-06T15:00:00Z",
+			wantStatus:       corev1.ConditionTrue,
+			wantMessage:      "trial license expires in 5 day(s), on 2026-10-06T15:00:00Z",
+			wantRefreshAfter: 3*time.Hour + time.Second,
+		},
+		{
+			name:            "expired license",
+			license:         license("trial", "expired", now.Add(-time.Hour)),
+			wantStatus:      corev1.ConditionFalse,
+			wantMessage:     "trial license expired on 2026-10-01T11:00:00Z",
+			wantEventReason: events.EventReasonLicenseExpired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
+			if tt.previousMessage != "" {
+				es.Status.Conditions = commonv1alpha1.Conditions{{
+					Type:    esv1.LicenseActive,
+					Status:  corev1.ConditionTrue,
+					Message: tt.previousMessage,
+				}}
+			}
+			d := &defaultDriver{DefaultDriverParameters: DefaultDriverParameters{
+				OperatorParameters: operator.Parameters{LicenseExpiryWarningPeriod: 30 * 24 * time.Hour},
+				ES:                 es,
+				ReconcileState:     reconcile.MustNewState(es),
+			}}
+
+			refreshAfter := d.reportLicenseExpiration(tt.license, now)
+			require.Equal(t, tt.wantRefreshAfter, refreshAfter)
+
+			emitted, updated := d.ReconcileState.Apply()
+			status := es.Status
+			if updated != nil {
+				status = updated.Status
+			}
+			index := status.Conditions.Index(esv1.LicenseActive)
+			require.GreaterOrEqual(t, index, 0)
+			require.Equal(t, tt.wantStatus, status.Conditions[index].Status)
+			require.Equal(t, tt.wantMessage, status.Conditions[index].Message)
+
+			var reasons []string
+			for _, event := range emitted {
+				reasons = append(reasons, event.Reason)
+			}
+			if tt.wantEventReason == "" {
+				require.Empty(t, reasons)
+			} else {
+				require.Equal(t, []string{tt.wantEventReason}, reasons)
+			}
+		})
+	}
+}
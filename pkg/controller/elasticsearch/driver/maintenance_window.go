@@ -0,0 +1,32 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"time"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/maintenance"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// maintenanceWindowOpen returns true if the nodes of the cluster can be restarted at the given time according to its
+// maintenance windows. Otherwise, it returns the time at which the next window opens, zero if no window opens again.
+func (d *defaultDriver) maintenanceWindowOpen(ctx context.Context, now time.Time) (bool, time.Time, error) {
+	windows, err := maintenance.ParseAll(d.ES)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+	open, nextOpening := windows.Open(now)
+	if !open {
+		ulog.FromContext(ctx).Info(
+			"Delaying the restart of the nodes until the next maintenance window",
+			"namespace", d.ES.Namespace,
+			"es_name", d.ES.Name,
+			"next_window", nextOpening,
+		)
+	}
+	return open, nextOpening, nil
+}
@@ -0,0 +1,48 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+)
+
+func Test_defaultDriver_maintenanceWindowOpen(t *testing.T) {
+	// a Saturday
+	now := time.Date(2024, 3, 16, 3, 0, 0, 0, time.UTC)
+	driver := func(windows ...esv1.MaintenanceWindow) *defaultDriver {
+		return &defaultDriver{DefaultDriverParameters: DefaultDriverParameters{
+			ES: esv1.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+				Spec:       esv1.ElasticsearchSpec{MaintenanceWindows: windows},
+			},
+		}}
+	}
+	weekend := esv1.MaintenanceWindow{Schedule: "0 2 * * SAT,SUN", Duration: metav1.Duration{Duration: 2 * time.Hour}}
+	weekdays := esv1.MaintenanceWindow{Schedule: "0 2 * * MON-FRI", Duration: metav1.Duration{Duration: 2 * time.Hour}}
+
+	// nodes can be restarted at any time without maintenance windows
+	open, _, err := driver().maintenanceWindowOpen(context.Background(), now)
+	require.NoError(t, err)
+	require.True(t, open)
+
+	open, _, err = driver(weekend).maintenanceWindowOpen(context.Background(), now)
+	require.NoError(t, err)
+	require.True(t, open)
+
+	open, nextOpening, err := driver(weekdays).maintenanceWindowOpen(context.Background(), now)
+	require.NoError(t, err)
+	require.False(t, open)
+	require.Equal(t, time.Date(2024, 3, 18, 2, 0, 0, 0, time.UTC), nextOpening.UTC())
+
+	_, _, err = driver(esv1.MaintenanceWindow{Schedule: "every night"}).maintenanceWindowOpen(context.Background(), now)
+	require.Error(t, err)
+}
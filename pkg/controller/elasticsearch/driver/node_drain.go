@@ -0,0 +1,106 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/shutdown"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// nodeDrainShutdownReason is the reason of the node shutdowns relocating the data of the Pods scheduled on cordoned
+// Kubernetes nodes. It distinguishes them from the shutdowns requested for downscales and rolling upgrades.
+const nodeDrainShutdownReason = "kubernetes-node-drain"
+
+// podsOnCordonedNodes returns the names of the given Pods scheduled on a cordoned Kubernetes node.
+func podsOnCordonedNodes(ctx context.Context, c k8s.Client, pods []corev1.Pod) ([]string, error) {
+	cordoned := map[string]bool{}
+	var names []string
+	for _, pod := range pods {
+		scheduled, nodeName := isPodScheduled(&pod)
+		if !scheduled {
+			continue
+		}
+		isCordoned, checked := cordoned[nodeName]
+		if !checked {
+			var node corev1.Node
+			err := c.Get(ctx, types.NamespacedName{Name: nodeName}, &node)
+			if err != nil && !apierrors.IsNotFound(err) {
+				return nil, err
+			}
+			// a deleted Kubernetes node cannot be drained anymore
+			isCordoned = err == nil && node.Spec.Unschedulable
+			cordoned[nodeName] = isCordoned
+		}
+		if isCordoned {
+			names = append(names, pod.Name)
+		}
+	}
+	return names, nil
+}
+
+// reconcileNodeDrains requests the shutdown of the Elasticsearch nodes scheduled on cordoned Kubernetes nodes, so that
+// their data is relocated before the Pods are evicted, and cancels the shutdowns of the nodes which are not drained
+// anymore, for example because the Kubernetes node was uncordoned or the Pod is running on another Kubernetes node.
+func (d *defaultDriver) reconcileNodeDrains(
+	ctx context.Context,
+	esClient esclient.Client,
+	esState ESState,
+	actualPods []corev1.Pod,
+	cordonedPods []string,
+) error {
+	if !d.OperatorParameters.OrchestrateNodeDrains || !supportsNodeShutdown(esClient.Version()) {
+		return nil
+	}
+	nodeNameToID, err := esState.NodeNameToID()
+	if err != nil {
+		return err
+	}
+	log := ulog.FromContext(ctx).WithValues("namespace", d.ES.Namespace, "es_name", d.ES.Name)
+	nodeShutdown := shutdown.NewNodeShutdown(esClient, nodeNameToID, esclient.Remove, nodeDrainShutdownReason, log)
+
+	terminatingPods := k8s.PodNames(k8s.TerminatingPods(actualPods))
+	// the shutdowns of the Pods being evicted are cleared once they are back in the cluster
+	if err := nodeShutdown.Clear(ctx,
+		shutdown.OnlyReason(nodeDrainShutdownReason),
+		nodeShutdown.OnlyNodesInCluster,
+		nodeShutdown.OnlyNonTerminatingNodes(append(terminatingPods, cordonedPods...)),
+	); err != nil {
+		return err
+	}
+
+	var leavingPods []string
+	for _, name := range cordonedPods {
+		if _, inCluster := nodeNameToID[name]; !inCluster {
+			// the data of a node which is not a member of the cluster cannot be relocated
+			continue
+		}
+		// do not replace the shutdowns of the downscales and of the rolling upgrades
+		hasShutdown, err := nodeShutdown.HasShutdown(ctx, name)
+		if err != nil {
+			return err
+		}
+		if !hasShutdown {
+			leavingPods = append(leavingPods, name)
+		}
+	}
+	if len(leavingPods) == 0 {
+		// ReconcileShutdowns would cancel the ongoing shutdowns
+		return nil
+	}
+	log.Info("Relocating the data of the nodes scheduled on cordoned Kubernetes nodes", "nodes", leavingPods)
+	if err := nodeShutdown.ReconcileShutdowns(ctx, leavingPods, terminatingPods); err != nil {
+		return fmt.Errorf("while relocating the data of the nodes on cordoned Kubernetes nodes: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func scheduledPod(name, nodeName string) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+		Spec:       corev1.PodSpec{NodeName: nodeName},
+		Status: corev1.PodStatus{Conditions: []corev1.PodCondition{
+			{Type: corev1.PodScheduled, Status: corev1.ConditionTrue},
+		}},
+	}
+}
+
+func k8sNode(name string, unschedulable bool) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       corev1.NodeSpec{Unschedulable: unschedulable},
+	}
+}
+
+func Test_podsOnCordonedNodes(t *testing.T) {
+	c := k8s.NewFakeClient(k8sNode("node-a", true), k8sNode("node-b", false))
+	pods := []corev1.Pod{
+		scheduledPod("es-0", "node-a"),
+		scheduledPod("es-1", "node-b"),
+		scheduledPod("es-2", "node-a"),
+		// the Kubernetes node does not exist anymore
+		scheduledPod("es-3", "node-c"),
+		// not scheduled yet
+		{ObjectMeta: metav1.ObjectMeta{Name: "es-4", Namespace: "ns"}},
+	}
+	names, err := podsOnCordonedNodes(context.Background(), c, pods)
+	require.NoError(t, err)
+	require.Equal(t, []string{"es-0", "es-2"}, names)
+}
+
+func Test_defaultDriver_reconcileNodeDrains(t *testing.T) {
+	nodes := esclient.Nodes{Nodes: map[string]esclient.Node{
+		"id-0": {Name: "es-0"},
+		"id-1": {Name: "es-1"},
+		"id-2": {Name: "es-2"},
+	}}
+	tests := []struct {
+		name          string
+		disabled      bool
+		version       string
+		shutdowns     map[string]esclient.NodeShutdown
+		cordonedPods  []string
+		wantShutdowns map[string]esclient.NodeShutdown
+	}{
+		{
+			name:         "disabled: do nothing",
+			disabled:     true,
+			version:      "8.15.0",
+			cordonedPods: []string{"es-0"},
+		},
+		{
+			name:         "node shutdown not supported: do nothing",
+			version:      "7.14.0",
+			cordonedPods: []string{"es-0"},
+		},
+		{
+			name:         "request the shutdown of the nodes on cordoned Kubernetes nodes",
+			version:      "8.15.0",
+			cordonedPods: []string{"es-0", "es-2", "es-3"},
+			wantShutdowns: map[string]esclient.NodeShutdown{
+				"id-0": {NodeID: "id-0", Type: "remove", Reason: nodeDrainShutdownReason, Status: esclient.ShutdownInProgress},
+				"id-2": {NodeID: "id-2", Type: "remove", Reason: nodeDrainShutdownReason, Status: esclient.ShutdownInProgress},
+			},
+		},
+		{
+			name:    "do not replace the shutdowns of rolling upgrades and downscales",
+			version: "8.15.0",
+			shutdowns: map[string]esclient.NodeShutdown{
+				"id-0": {NodeID: "id-0", Type: "RESTART", Reason: "12345", Status: esclient.ShutdownComplete},
+				"id-1": {NodeID: "id-1", Type: "REMOVE", Reason: "12345", Status: esclient.ShutdownInProgress},
+			},
+			cordonedPods: []string{"es-0"},
+			wantShutdowns: map[string]esclient.NodeShutdown{
+				"id-0": {NodeID: "id-0", Type: "RESTART", Reason: "12345", Status: esclient.ShutdownComplete},
+				"id-1": {NodeID: "id-1", Type: "REMOVE", Reason: "12345", Status: esclient.ShutdownInProgress},
+			},
+		},
+		{
+			name:    "cancel the shutdowns of the nodes which are not drained anymore",
+			version: "8.15.0",
+			shutdowns: map[string]esclient.NodeShutdown{
+				"id-0": {NodeID: "id-0", Type: "REMOVE", Reason: nodeDrainShutdownReason, Status: esclient.ShutdownInProgress},
+				"id-1": {NodeID: "id-1", Type: "REMOVE", Reason: nodeDrainShutdownReason, Status: esclient.ShutdownInProgress},
+				"id-2": {NodeID: "id-2", Type: "REMOVE", Reason: "12345", Status: esclient.ShutdownInProgress},
+				// the node has been evicted and is not back in the cluster yet
+				"id-9": {NodeID: "id-9", Type: "REMOVE", Reason: nodeDrainShutdownReason, Status: esclient.ShutdownComplete},
+			},
+			cordonedPods: []string{"es-0"},
+			wantShutdowns: map[string]esclient.NodeShutdown{
+				"id-0": {NodeID: "id-0", Type: "REMOVE", Reason: nodeDrainShutdownReason, Status: esclient.ShutdownInProgress},
+				"id-2": {NodeID: "id-2", Type: "REMOVE", Reason: "12345", Status: esclient.ShutdownInProgress},
+				"id-9": {NodeID: "id-9", Type: "REMOVE", Reason: nodeDrainShutdownReason, Status: esclient.ShutdownComplete},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			esClient := &fakeESClient{nodes: nodes, Shutdowns: tt.shutdowns, version: version.MustParse(tt.version)}
+			d := &defaultDriver{DefaultDriverParameters: DefaultDriverParameters{
+				OperatorParameters: operator.Parameters{OrchestrateNodeDrains: !tt.disabled},
+				ES:                 esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: "ns"}},
+			}}
+			pods := []corev1.Pod{scheduledPod("es-0", "node-a"), scheduledPod("es-1", "node-b"), scheduledPod("es-2", "node-a")}
+			esState := NewMemoizingESState(context.Background(), esClient)
+			require.NoError(t, d.reconcileNodeDrains(context.Background(), esClient, esState, pods, tt.cordonedPods))
+			require.Equal(t, tt.wantShutdowns, esClient.Shutdowns)
+		})
+	}
+}
@@ -11,16 +11,20 @@ import (
 
 	"go.elastic.co/apm/v2"
 	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/effectivespec"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/statefulset"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/certificates/transport"
 	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/hints"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/nodespec"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/pdb"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
@@ -87,6 +91,15 @@ func (d *defaultDriver) reconcileNodeSpecs(
 		return results.WithError(err)
 	}
 
+	expectedStatefulSets := expectedResources.StatefulSets()
+	workloads := make([]client.Object, 0, len(expectedStatefulSets))
+	for i := range expectedStatefulSets {
+		workloads = append(workloads, &expectedStatefulSets[i])
+	}
+	if err := effectivespec.Reconcile(ctx, d.Client, &d.ES, esv1.ESNamer.Suffix(d.ES.Name, effectivespec.Suffix), d.ES.Spec, workloads...); err != nil {
+		return results.WithError(err)
+	}
+
 	if esClient.IsDesiredNodesSupported() {
 		results.WithResults(d.updateDesiredNodes(ctx, esClient, esReachable, expectedResources))
 		if results.HasError() {
@@ -138,10 +151,19 @@ func (d *defaultDriver) reconcileNodeSpecs(
 		return results.WithError(err)
 	}
 
+	if err := volume.ReconcilePVCMetadata(ctx, d.K8sClient(), &d.ES, label.NewLabelSelectorForElasticsearch(d.ES)); err != nil {
+		return results.WithError(err)
+	}
+
 	if err := GarbageCollectPVCs(ctx, d.K8sClient(), d.ES, actualStatefulSets, expectedResources.StatefulSets()); err != nil {
 		return results.WithError(err)
 	}
 
+	// Next phases are safe to resume in a later reconciliation, as expectations are checked again beforehand.
+	if reconciler.BudgetFromContext(ctx).Checkpoint() {
+		return results.WithReconciliationState(reconciler.BudgetExhausted)
+	}
+
 	// Phase 2: if there is any Pending or bootlooping Pod to upgrade, do it.
 	attempted, err := d.MaybeForceUpgrade(ctx, actualStatefulSets)
 	if err != nil || attempted {
@@ -190,6 +212,15 @@ func (d *defaultDriver) reconcileNodeSpecs(
 		return results.WithError(err)
 	}
 
+	// Pods scheduled on cordoned Kubernetes nodes are about to be evicted: their data is relocated beforehand.
+	var cordonedPods []string
+	if d.OperatorParameters.OrchestrateNodeDrains {
+		cordonedPods, err = podsOnCordonedNodes(ctx, d.Client, resourcesState.CurrentPods)
+		if err != nil {
+			return results.WithError(err)
+		}
+	}
+
 	// Phase 2: handle sset scale down.
 	// We want to safely remove nodes from the cluster, either because the sset requires less replicas,
 	// or because it should be removed entirely.
@@ -202,6 +233,7 @@ func (d *defaultDriver) reconcileNodeSpecs(
 		d.Expectations,
 		d.ES,
 		nodeShutdowns,
+		cordonedPods,
 	)
 
 	downscaleRes := HandleDownscale(downscaleCtx, expectedResources.StatefulSets(), actualStatefulSets)
@@ -210,6 +242,14 @@ func (d *defaultDriver) reconcileNodeSpecs(
 		return results
 	}
 
+	if err := d.reconcileNodeDrains(ctx, esClient, esState, resourcesState.AllPods, cordonedPods); err != nil {
+		return results.WithError(err)
+	}
+
+	if reconciler.BudgetFromContext(ctx).Checkpoint() {
+		return results.WithReconciliationState(reconciler.BudgetExhausted)
+	}
+
 	// Phase 3: handle rolling upgrades.
 	rollingUpgradesRes := d.handleUpgrades(ctx, esClient, esState, expectedResources)
 	results.WithResults(rollingUpgradesRes)
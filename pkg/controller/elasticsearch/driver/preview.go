@@ -0,0 +1,130 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/statefulset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/nodespec"
+	es_sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/validation"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+// NewImpactPreview returns a function previewing the changes the operator would apply to an Elasticsearch cluster to
+// move from its current specification to a proposed one.
+func NewImpactPreview(c k8s.Client, params operator.Parameters) validation.ImpactPreview {
+	return func(ctx context.Context, current, proposed esv1.Elasticsearch) ([]string, error) {
+		return previewImpact(ctx, c, params, current, proposed)
+	}
+}
+
+// previewImpact builds the expected StatefulSets of both specifications with the same inputs, and summarizes their
+// differences: Pods to create, remove, restart or resize, volumes to expand, and version upgrade. It does not have any
+// side effect. Changes that do not depend on the specification, such as the rotation of certificates or an update of
+// the secure settings, are not accounted for.
+func previewImpact(ctx context.Context, c k8s.Client, params operator.Parameters, current, proposed esv1.Elasticsearch) ([]string, error) {
+	actualStatefulSets, err := es_sset.RetrieveActualStatefulSets(c, k8s.ExtractNamespacedName(&current))
+	if err != nil {
+		return nil, err
+	}
+	build := func(es esv1.Elasticsearch) (es_sset.StatefulSetList, error) {
+		resources, err := nodespec.BuildExpectedResources(ctx, c, es, nil, actualStatefulSets, params.IPFamily, params.SetDefaultSecurityContext)
+		if err != nil {
+			return nil, err
+		}
+		return resources.StatefulSets(), nil
+	}
+	before, err := build(current)
+	if err != nil {
+		return nil, err
+	}
+	after, err := build(proposed)
+	if err != nil {
+		return nil, err
+	}
+
+	var impact []string
+	if current.Spec.Version != proposed.Spec.Version {
+		impact = append(impact, versionUpgradeImpact(current.Spec.Version, proposed.Spec.Version))
+	}
+	for _, expected := range after {
+		previous, exists := before.GetByName(expected.Name)
+		if !exists {
+			impact = append(impact, fmt.Sprintf("creates %d Pod(s) in the new StatefulSet %s", sset.GetReplicas(expected), expected.Name))
+			continue
+		}
+		impact = append(impact, statefulSetImpact(previous, expected, params.InPlacePodResize)...)
+	}
+	for _, previous := range before {
+		if _, exists := after.GetByName(previous.Name); !exists {
+			impact = append(impact, fmt.Sprintf("migrates the data away from the %d Pod(s) of the StatefulSet %s, then removes them", sset.GetReplicas(previous), previous.Name))
+		}
+	}
+	if len(impact) == 0 {
+		impact = append(impact, "no Pod is created, removed or restarted")
+	}
+	return impact, nil
+}
+
+func versionUpgradeImpact(from, to string) string {
+	message := fmt.Sprintf("upgrades Elasticsearch from %s to %s with a rolling restart of all the Pods", from, to)
+	fromVersion, err := version.Parse(from)
+	if err != nil {
+		return message
+	}
+	toVersion, err := version.Parse(to)
+	if err != nil {
+		return message
+	}
+	if toVersion.Major > fromVersion.Major {
+		message += ", once the upgrade pre-flight checks pass"
+	}
+	return message
+}
+
+// statefulSetImpact summarizes the changes between two versions of a StatefulSet.
+func statefulSetImpact(previous, expected appsv1.StatefulSet, inPlacePodResize bool) []string {
+	var impact []string
+	previousReplicas, expectedReplicas := sset.GetReplicas(previous), sset.GetReplicas(expected)
+	switch {
+	case expectedReplicas > previousReplicas:
+		impact = append(impact, fmt.Sprintf("scales up the StatefulSet %s from %d to %d Pod(s)", expected.Name, previousReplicas, expectedReplicas))
+	case expectedReplicas < previousReplicas:
+		impact = append(impact, fmt.Sprintf("migrates the data away from %d Pod(s) of the StatefulSet %s, then scales it down to %d Pod(s)", previousReplicas-expectedReplicas, expected.Name, expectedReplicas))
+	}
+
+	if !equality.Semantic.DeepEqual(previous.Spec.Template, expected.Spec.Template) {
+		pods := min(previousReplicas, expectedReplicas)
+		if inPlacePodResize && canResizeInPlace(previous.Spec.Template, expected.Spec.Template) {
+			impact = append(impact, fmt.Sprintf("resizes the %d Pod(s) of the StatefulSet %s in place, without restarting them", pods, expected.Name))
+		} else {
+			impact = append(impact, fmt.Sprintf("restarts the %d Pod(s) of the StatefulSet %s", pods, expected.Name))
+		}
+	}
+
+	for _, claim := range expected.Spec.VolumeClaimTemplates {
+		previousClaim := sset.GetClaim(previous.Spec.VolumeClaimTemplates, claim.Name)
+		if previousClaim == nil {
+			continue
+		}
+		previousStorage := previousClaim.Spec.Resources.Requests[corev1.ResourceStorage]
+		expectedStorage := claim.Spec.Resources.Requests[corev1.ResourceStorage]
+		if expectedStorage.Cmp(previousStorage) > 0 {
+			impact = append(impact, fmt.Sprintf("expands the %s volumes of the StatefulSet %s from %s to %s",
+				claim.Name, expected.Name, previousStorage.String(), expectedStorage.String()))
+		}
+	}
+	return impact
+}
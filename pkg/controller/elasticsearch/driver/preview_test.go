@@ -0,0 +1,91 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/settings"
+)
+
+func Test_statefulSetImpact(t *testing.T) {
+	statefulSet := func(replicas int32, template corev1.PodTemplateSpec, storage string) appsv1.StatefulSet {
+		return appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: resizeSsetName},
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: ptr.To(replicas),
+				Template: template,
+				VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{
+					ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-data"},
+					Spec: corev1.PersistentVolumeClaimSpec{Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(storage)},
+					}},
+				}},
+			},
+		}
+	}
+	fixedHeap := corev1.EnvVar{Name: settings.EnvEsJavaOpts, Value: "-Xms2g -Xmx2g"}
+	tests := []struct {
+		name             string
+		previous         appsv1.StatefulSet
+		expected         appsv1.StatefulSet
+		inPlacePodResize bool
+		want             []string
+	}{
+		{
+			name:     "no change",
+			previous: statefulSet(3, resizeTemplate("1", "4Gi"), "10Gi"),
+			expected: statefulSet(3, resizeTemplate("1", "4Gi"), "10Gi"),
+			want:     nil,
+		},
+		{
+			name:     "scale up",
+			previous: statefulSet(3, resizeTemplate("1", "4Gi"), "10Gi"),
+			expected: statefulSet(5, resizeTemplate("1", "4Gi"), "10Gi"),
+			want:     []string{"scales up the StatefulSet es-default from 3 to 5 Pod(s)"},
+		},
+		{
+			name:     "scale down and restart",
+			previous: statefulSet(3, resizeTemplate("1", "4Gi"), "10Gi"),
+			expected: statefulSet(2, resizeTemplate("1", "8Gi"), "10Gi"),
+			want: []string{
+				"migrates the data away from 1 Pod(s) of the StatefulSet es-default, then scales it down to 2 Pod(s)",
+				"restarts the 2 Pod(s) of the StatefulSet es-default",
+			},
+		},
+		{
+			name:             "resize in place",
+			previous:         statefulSet(3, resizeTemplate("1", "4Gi", fixedHeap), "10Gi"),
+			expected:         statefulSet(3, resizeTemplate("2", "4Gi", fixedHeap), "10Gi"),
+			inPlacePodResize: true,
+			want:             []string{"resizes the 3 Pod(s) of the StatefulSet es-default in place, without restarting them"},
+		},
+		{
+			name:     "volume expansion",
+			previous: statefulSet(3, resizeTemplate("1", "4Gi"), "10Gi"),
+			expected: statefulSet(3, resizeTemplate("1", "4Gi"), "20Gi"),
+			want:     []string{"expands the elasticsearch-data volumes of the StatefulSet es-default from 10Gi to 20Gi"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, statefulSetImpact(tt.previous, tt.expected, tt.inPlacePodResize))
+		})
+	}
+}
+
+func Test_versionUpgradeImpact(t *testing.T) {
+	require.Equal(t, "upgrades Elasticsearch from 8.15.0 to 8.16.0 with a rolling restart of all the Pods",
+		versionUpgradeImpact("8.15.0", "8.16.0"))
+	require.Equal(t, "upgrades Elasticsearch from 8.18.0 to 9.0.0 with a rolling restart of all the Pods, once the upgrade pre-flight checks pass",
+		versionUpgradeImpact("8.18.0", "9.0.0"))
+}
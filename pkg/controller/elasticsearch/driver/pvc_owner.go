@@ -33,10 +33,13 @@ func reconcilePVCOwnerRefs(ctx context.Context, c k8s.Client, es esv1.Elasticsea
 
 	for _, pvc := range pvcs.Items {
 		pvc := pvc
+		// references to a previous incarnation of the cluster, for example restored from a backup along with the PVCs,
+		// are always removed as they would otherwise lead to the deletion of the PVCs by the garbage collector
+		removedPreviousOwner := k8s.RemovePreviousOwners(&pvc, &es, esv1.Kind)
 		hasOwner := k8s.HasOwner(&pvc, &es)
 		switch es.Spec.VolumeClaimDeletePolicyOrDefault() {
 		case esv1.DeleteOnScaledownOnlyPolicy:
-			if !hasOwner {
+			if !hasOwner && !removedPreviousOwner {
 				continue
 			}
 			k8s.RemoveOwner(&pvc, &es)
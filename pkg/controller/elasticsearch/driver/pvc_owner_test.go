@@ -57,6 +57,12 @@ func Test_reconcilePVCOwnerRefs(t *testing.T) {
 		return &pvc
 	}
 
+	restoredPVCFixturePtr := func(name string) *corev1.PersistentVolumeClaim {
+		pvc := pvcFixture(name, "es")
+		pvc.OwnerReferences[0].UID = "previous-uid"
+		return &pvc
+	}
+
 	tests := []struct {
 		name       string
 		args       args
@@ -124,6 +130,26 @@ func Test_reconcilePVCOwnerRefs(t *testing.T) {
 			wantErr:    false,
 			wantUpdate: true,
 		},
+		{
+			name: "remove references to a previous incarnation of the cluster on DeleteOnScaledownOnlyPolicy",
+			args: args{
+				c:  k8s.NewFakeClient(restoredPVCFixturePtr("es-data-0")),
+				es: esFixture(esv1.DeleteOnScaledownOnlyPolicy),
+			},
+			want:       []corev1.PersistentVolumeClaim{pvcFixture("es-data-0")},
+			wantErr:    false,
+			wantUpdate: true,
+		},
+		{
+			name: "replace references to a previous incarnation of the cluster on DeleteOnScaledownAndClusterDeletionPolicy",
+			args: args{
+				c:  k8s.NewFakeClient(restoredPVCFixturePtr("es-data-0")),
+				es: esFixture(esv1.DeleteOnScaledownAndClusterDeletionPolicy),
+			},
+			want:       []corev1.PersistentVolumeClaim{pvcFixture("es-data-0", "es")},
+			wantErr:    false,
+			wantUpdate: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
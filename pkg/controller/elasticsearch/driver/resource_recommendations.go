@@ -0,0 +1,31 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/resourcerecommendation"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// recommendResources computes the resources recommended for the Elasticsearch containers of each NodeSet, reports them
+// in the status, and schedules the next computation. Recommendations are never applied to the NodeSets.
+func (d *defaultDriver) recommendResources(ctx context.Context, esClient esclient.Client) *reconciler.Results {
+	results := &reconciler.Results{}
+	recommendations, nextEvaluation, err := resourcerecommendation.Evaluate(ctx, esClient, d.ES)
+	if err != nil {
+		// recommendations are informational only, they do not prevent the reconciliation from completing
+		ulog.FromContext(ctx).Info("Could not compute the resource recommendations, re-queuing", "err", err, "namespace", d.ES.Namespace, "es_name", d.ES.Name)
+		return results.WithReconciliationState(defaultRequeue.ReconciliationComplete())
+	}
+	d.ReconcileState.UpdateResourceRecommendations(recommendations)
+	if recommendations == nil {
+		return results
+	}
+	return results.WithReconciliationState(reconciler.RequeueAfter(nextEvaluation).ReconciliationComplete())
+}
@@ -0,0 +1,35 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/restart"
+	es_sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+// reconcileRestartProgress reports the progress of the restart of the nodes requested with the restart annotation, if
+// any. The nodes themselves are restarted by the regular rolling upgrade, or by a full cluster restart, as their Pod
+// templates change.
+func (d *defaultDriver) reconcileRestartProgress(ctx context.Context) error {
+	if d.ES.Status.Restart == nil {
+		return nil
+	}
+	statefulSets, err := es_sset.RetrieveActualStatefulSets(d.Client, k8s.ExtractNamespacedName(&d.ES))
+	if err != nil {
+		return err
+	}
+	pods, err := statefulSets.GetActualPods(d.Client)
+	if err != nil {
+		return err
+	}
+	if err := restart.UpdateProgress(ctx, d.Client, d.Recorder(), &d.ES, pods, statefulSets.ExpectedNodeCount()); err != nil {
+		return err
+	}
+	d.ReconcileState.UpdateRestart(d.ES.Status.Restart)
+	return nil
+}
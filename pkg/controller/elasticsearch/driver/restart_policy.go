@@ -0,0 +1,54 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/restartpolicy"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// reconcileRestartSlot makes the cluster hold a restart slot of its namespace while some of its nodes must be
+// restarted, if the restart policy of the namespace applies to it, and releases the slot once all the nodes are
+// upgraded and back in the cluster. It returns true if the nodes can be restarted.
+func (d *defaultDriver) reconcileRestartSlot(
+	ctx context.Context,
+	podsToUpgrade []corev1.Pod,
+	healthyPods map[string]corev1.Pod,
+	currentPods []corev1.Pod,
+) (bool, error) {
+	if len(podsToUpgrade) == 0 {
+		if len(healthyPods) < len(currentPods) {
+			// keep the slot until the last restarted nodes are back in the cluster
+			return true, nil
+		}
+		return true, restartpolicy.ReleaseSlot(ctx, d.Client, d.ES)
+	}
+
+	policy, err := restartpolicy.Get(ctx, d.Client, d.ES.Namespace)
+	if err != nil {
+		return false, err
+	}
+	if !policy.Applies(d.ES) {
+		return true, nil
+	}
+	acquired, err := restartpolicy.AcquireSlot(ctx, d.Client, *policy, d.ES, time.Now())
+	if err != nil {
+		return false, err
+	}
+	if !acquired {
+		ulog.FromContext(ctx).Info(
+			"Delaying the restart of the nodes until a restart slot of the namespace is available",
+			"namespace", d.ES.Namespace,
+			"es_name", d.ES.Name,
+			"max_concurrent_restarts", policy.MaxConcurrentRestarts,
+		)
+	}
+	return acquired, nil
+}
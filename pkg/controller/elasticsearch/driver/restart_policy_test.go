@@ -0,0 +1,70 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/restartpolicy"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func Test_defaultDriver_reconcileRestartSlot(t *testing.T) {
+	ctx := context.Background()
+	policy := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: restartpolicy.ConfigMapName},
+		Data:       map[string]string{restartpolicy.MaxConcurrentRestartsKey: "1", restartpolicy.SelectorKey: "env=prod"},
+	}
+	c := k8s.NewFakeClient(policy)
+	driver := func(name string, labels map[string]string) *defaultDriver {
+		return &defaultDriver{DefaultDriverParameters: DefaultDriverParameters{
+			Client: c,
+			ES:     esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, Labels: labels}},
+		}}
+	}
+	prod := map[string]string{"env": "prod"}
+	pods := []corev1.Pod{scheduledPod("es-0", "node-a"), scheduledPod("es-1", "node-b")}
+	allHealthy := map[string]corev1.Pod{"es-0": pods[0], "es-1": pods[1]}
+	oneHealthy := map[string]corev1.Pod{"es-1": pods[1]}
+
+	// the first cluster covered by the policy gets the only slot
+	canRestart, err := driver("a", prod).reconcileRestartSlot(ctx, pods[:1], allHealthy, pods)
+	require.NoError(t, err)
+	require.True(t, canRestart)
+
+	// the second one has to wait
+	canRestart, err = driver("b", prod).reconcileRestartSlot(ctx, pods[:1], allHealthy, pods)
+	require.NoError(t, err)
+	require.False(t, canRestart)
+
+	// a cluster not covered by the policy restarts its nodes freely
+	canRestart, err = driver("c", nil).reconcileRestartSlot(ctx, pods[:1], allHealthy, pods)
+	require.NoError(t, err)
+	require.True(t, canRestart)
+
+	// the slot is kept until the restarted nodes are back in the cluster
+	_, err = driver("a", prod).reconcileRestartSlot(ctx, nil, oneHealthy, pods)
+	require.NoError(t, err)
+	var slots coordinationv1.LeaseList
+	require.NoError(t, c.List(ctx, &slots))
+	require.Len(t, slots.Items, 1)
+
+	// then released
+	_, err = driver("a", prod).reconcileRestartSlot(ctx, nil, allHealthy, pods)
+	require.NoError(t, err)
+	require.NoError(t, c.List(ctx, &slots))
+	require.Empty(t, slots.Items)
+
+	canRestart, err = driver("b", prod).reconcileRestartSlot(ctx, pods[:1], allHealthy, pods)
+	require.NoError(t, err)
+	require.True(t, canRestart)
+}
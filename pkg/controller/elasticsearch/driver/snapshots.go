@@ -0,0 +1,41 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/snapshotrepository"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// verifySnapshots verifies the latest snapshots of the cluster when snapshot verification is enabled and due, reports
+// the result in the status, and schedules the next verification.
+func (d *defaultDriver) verifySnapshots(ctx context.Context, esClient esclient.Client) *reconciler.Results {
+	results := &reconciler.Results{}
+	status, nextVerification, err := snapshotrepository.Verify(ctx, esClient, d.ES, time.Now())
+	if err != nil {
+		msg := "Could not verify snapshots, re-queuing"
+		ulog.FromContext(ctx).Info(msg, "err", err, "namespace", d.ES.Namespace, "es_name", d.ES.Name)
+		return results.WithReconciliationState(defaultRequeue.WithReason(msg))
+	}
+	if status == nil {
+		d.ReconcileState.UpdateSnapshotVerification(nil, false, "")
+		return results
+	}
+	verified, message := snapshotrepository.Verified(*status)
+	if !verified && status != d.ES.Status.SnapshotVerification {
+		// only emit an event for new verification results
+		d.ReconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonSnapshotVerificationFailed, message)
+	}
+	d.ReconcileState.UpdateSnapshotVerification(status, verified, message)
+	return results.WithReconciliationState(reconciler.RequeueAfter(nextVerification).ReconciliationComplete())
+}
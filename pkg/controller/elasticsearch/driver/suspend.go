@@ -7,7 +7,6 @@ package driver
 import (
 	"context"
 
-	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
@@ -17,6 +16,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
 )
 
 // reconcileSuspendedPods implements the operator side of activating the Pod suspension mechanism:
@@ -30,7 +30,7 @@ import (
 //   - If the Pod is suspended in the initContainer but should be running we update the Pods metadata to accelerate the
 //     propagation of the configMap values. This is just an optimisation and not essential for the correct operation of
 //     the feature.
-func reconcileSuspendedPods(ctx context.Context, c k8s.Client, es esv1.Elasticsearch, e *expectations.Expectations) error {
+func reconcileSuspendedPods(ctx context.Context, c k8s.Client, es esv1.Elasticsearch, suspendedPodNames set.StringSet, e *expectations.Expectations) error {
 	// let's make sure we observe any deletions in the cache to avoid redundant deletion
 	pendingPodDeletions, err := e.PendingPodDeletions()
 	if err != nil {
@@ -38,9 +38,7 @@ func reconcileSuspendedPods(ctx context.Context, c k8s.Client, es esv1.Elasticse
 	}
 	deletionsSatisfied := len(pendingPodDeletions) == 0
 
-	// suspendedPodNames as indicated by the user on the Elasticsearch resource via an annotation
-	// the corresponding configMap has already been reconciled prior to that function
-	suspendedPodNames := es.SuspendedPodNames()
+	// the configMap listing the suspendedPodNames has already been reconciled prior to that function
 
 	// all known Pods, this is mostly to fine tune the reconciliation to the current state of the Pods, see below
 	statefulSets, err := sset.RetrieveActualStatefulSets(c, k8s.ExtractNamespacedName(&es))
@@ -75,7 +73,7 @@ func reconcileSuspendedPods(ctx context.Context, c k8s.Client, es esv1.Elasticse
 					e.ExpectDeletion(pod)
 				}
 			}
-		} else if isSuspended(pod) {
+		} else if initcontainer.IsSuspended(pod) {
 			// Pod is suspended. But it should not be. Try to speed up propagation of config map entries so that it can
 			// start up again. Without this it can take minutes until the config map file in the Pod's filesystem is
 			// updated with the current state.
@@ -85,11 +83,23 @@ func reconcileSuspendedPods(ctx context.Context, c k8s.Client, es esv1.Elasticse
 	return nil
 }
 
-func isSuspended(pod corev1.Pod) bool {
-	for _, s := range pod.Status.InitContainerStatuses {
-		if s.Name == initcontainer.SuspendContainerName && s.State.Running != nil {
-			return true
-		}
+// expectedSuspendedPodNames returns the names of the Pods to suspend: the Pods indicated by the user on the Elasticsearch
+// resource via the suspend annotation, and all the Pods of the cluster while an unsafe bootstrap is requested.
+func expectedSuspendedPodNames(c k8s.Client, es esv1.Elasticsearch) (set.StringSet, error) {
+	names := es.SuspendedPodNames()
+	if _, requested := es.UnsafeBootstrapPodName(); !requested {
+		return names, nil
+	}
+	statefulSets, err := sset.RetrieveActualStatefulSets(c, k8s.ExtractNamespacedName(&es))
+	if err != nil {
+		return nil, err
+	}
+	pods, err := statefulSets.GetActualPods(c)
+	if err != nil {
+		return nil, err
+	}
+	for _, pod := range pods {
+		names.Add(pod.Name)
 	}
-	return false
+	return names, nil
 }
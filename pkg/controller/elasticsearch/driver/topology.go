@@ -0,0 +1,50 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+)
+
+// warnSchedulingCompromises emits an event for each Kubernetes node hosting several master nodes of the cluster, when
+// master nodes are only preferably spread across Kubernetes nodes: the scheduler may have ignored the soft constraints,
+// and the loss of a single Kubernetes node would then affect the availability of the cluster.
+func warnSchedulingCompromises(es esv1.Elasticsearch, pods []corev1.Pod, recorder *events.Recorder) {
+	if policy := es.Spec.TopologySpread; policy != nil &&
+		(policy.IsHard() || !slices.Contains(policy.TopologyKeysOrDefault(), esv1.HostTopologyKey)) {
+		// no soft constraint on hosts
+		return
+	}
+	mastersByHost := make(map[string][]string)
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" || !label.IsMasterNode(pod) {
+			continue
+		}
+		mastersByHost[pod.Spec.NodeName] = append(mastersByHost[pod.Spec.NodeName], pod.Name)
+	}
+	hosts := make([]string, 0, len(mastersByHost))
+	for host, masters := range mastersByHost {
+		if len(masters) > 1 {
+			hosts = append(hosts, host)
+		}
+	}
+	sort.Strings(hosts)
+	for _, host := range hosts {
+		masters := mastersByHost[host]
+		sort.Strings(masters)
+		recorder.AddEvent(corev1.EventTypeWarning, events.EventReasonSchedulingCompromise, fmt.Sprintf(
+			"Master nodes %s are scheduled on the same Kubernetes node %s", strings.Join(masters, ", "), host,
+		))
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+)
+
+func Test_warnSchedulingCompromises(t *testing.T) {
+	newPod := func(name, host string, master bool) corev1.Pod {
+		pod := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{}},
+			Spec:       corev1.PodSpec{NodeName: host},
+		}
+		label.NodeTypesMasterLabelName.Set(master, pod.Labels)
+		return pod
+	}
+	pods := []corev1.Pod{
+		newPod("master-2", "host-a", true),
+		newPod("master-0", "host-a", true),
+		newPod("master-1", "host-b", true),
+		newPod("data-0", "host-b", false),
+		newPod("master-3", "", true),
+	}
+	tests := []struct {
+		name   string
+		policy *esv1.TopologySpreadPolicy
+		want   []events.Event
+	}{
+		{
+			name: "default soft anti-affinity",
+			want: []events.Event{{
+				EventType: corev1.EventTypeWarning,
+				Reason:    events.EventReasonSchedulingCompromise,
+				Message:   "Master nodes master-0, master-2 are scheduled on the same Kubernetes node host-a",
+			}},
+		},
+		{
+			name:   "soft spread across hosts",
+			policy: &esv1.TopologySpreadPolicy{TopologyKeys: []string{esv1.ZoneTopologyKey, esv1.HostTopologyKey}},
+			want: []events.Event{{
+				EventType: corev1.EventTypeWarning,
+				Reason:    events.EventReasonSchedulingCompromise,
+				Message:   "Master nodes master-0, master-2 are scheduled on the same Kubernetes node host-a",
+			}},
+		},
+		{
+			name:   "hard spread across hosts",
+			policy: &esv1.TopologySpreadPolicy{Enforcement: esv1.HardTopologySpread},
+		},
+		{
+			name:   "soft spread across zones only",
+			policy: &esv1.TopologySpreadPolicy{TopologyKeys: []string{esv1.ZoneTopologyKey}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := events.NewRecorder()
+			warnSchedulingCompromises(esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{TopologySpread: tt.policy}}, pods, recorder)
+			require.ElementsMatch(t, tt.want, recorder.Events())
+		})
+	}
+}
@@ -7,14 +7,17 @@ package driver
 import (
 	"context"
 	"fmt"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/expectations"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/restart"
 	sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/statefulset"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
@@ -25,6 +28,7 @@ import (
 	es_sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
 )
 
 func (d *defaultDriver) handleUpgrades(
@@ -57,6 +61,17 @@ func (d *defaultDriver) handleUpgrades(
 	if err != nil {
 		return results.WithError(err)
 	}
+	if d.OperatorParameters.InPlacePodResize {
+		// Pods for which only the resources changed are resized without being restarted
+		var resizing bool
+		podsToUpgrade, resizing, err = d.resizePodsInPlace(ctx, statefulSets, podsToUpgrade)
+		if err != nil {
+			return results.WithError(err)
+		}
+		if resizing {
+			results.WithReconciliationState(defaultRequeue.WithReason("Nodes resize in progress"))
+		}
+	}
 	// Get the healthy Pods (from a K8S point of view + in the ES cluster)
 	healthyPods, err := healthyPods(d.Client, statefulSets, esState)
 	if err != nil {
@@ -69,9 +84,11 @@ func (d *defaultDriver) handleUpgrades(
 	}
 	logger := log.WithValues("namespace", d.ES.Namespace, "es_name", d.ES.Name)
 	nodeShutdown := shutdown.NewNodeShutdown(esClient, nodeNameToID, esclient.Restart, d.ES.ResourceVersion, logger)
+	// ephemeral nodes do not keep their data across restarts: it is relocated with a shutdown of type remove
+	ephemeralShutdown := shutdown.NewNodeShutdown(esClient, nodeNameToID, esclient.Remove, d.ES.ResourceVersion, logger)
 
 	// Maybe re-enable shards allocation and delete shutdowns if upgraded nodes are back into the cluster.
-	if results.WithResults(d.maybeCompleteNodeUpgrades(ctx, esClient, esState, nodeShutdown)).HasError() {
+	if results.WithResults(d.maybeCompleteNodeUpgrades(ctx, esClient, esState, nodeShutdown, ephemeralShutdown)).HasError() {
 		return results
 	}
 
@@ -81,6 +98,70 @@ func (d *defaultDriver) handleUpgrades(
 		return results.WithError(err)
 	}
 
+	// Make sure the cluster is ready for a major version upgrade before restarting the first node.
+	blocked, err := d.runUpgradePreflightChecks(ctx, esClient, currentPods)
+	if err != nil {
+		return results.WithError(err)
+	}
+	if blocked {
+		reason := fmt.Sprintf("Upgrade to %s blocked by pre-flight checks", d.ES.Spec.Version)
+		return results.WithReconciliationState(defaultRequeue.WithReason(reason))
+	}
+
+	isVersionUpgrade, err := isVersionUpgrade(d.ES)
+	if err != nil {
+		return results.WithError(err)
+	}
+
+	// Wait for an external analysis tool to approve the next step of the version upgrade, if requested.
+	if !d.upgradeGateApproved(ctx, isVersionUpgrade, podsToUpgrade, currentPods, statefulSets.ExpectedNodeCount()) {
+		// do not hold the restart slot of the namespace until the step is approved
+		if _, err := d.reconcileRestartSlot(ctx, nil, healthyPods, currentPods); err != nil {
+			return results.WithError(err)
+		}
+		reason := fmt.Sprintf("Nodes upgrade: waiting for the approval of step %s", d.ES.Status.UpgradeGate.Step)
+		return results.WithReconciliationState(defaultRequeue.WithReason(reason))
+	}
+
+	// Only restart nodes inside the maintenance windows of the cluster.
+	if len(podsToUpgrade) > 0 {
+		open, nextOpening, err := d.maintenanceWindowOpen(ctx, time.Now())
+		if err != nil {
+			return results.WithError(err)
+		}
+		if !open {
+			// do not hold the restart slot of the namespace until the window opens
+			if _, err := d.reconcileRestartSlot(ctx, nil, healthyPods, currentPods); err != nil {
+				return results.WithError(err)
+			}
+			if nextOpening.IsZero() {
+				return results.WithReconciliationState(defaultRequeue.WithReason("Nodes upgrade: no maintenance window opens"))
+			}
+			reason := fmt.Sprintf("Nodes upgrade: waiting for the maintenance window opening at %s", nextOpening.UTC().Format(time.RFC3339))
+			return results.WithReconciliationState(reconciler.RequeueAfter(time.Until(nextOpening)).WithReason(reason))
+		}
+	}
+
+	// Respect the maximum number of clusters restarting at the same time in the namespace.
+	canRestart, err := d.reconcileRestartSlot(ctx, podsToUpgrade, healthyPods, currentPods)
+	if err != nil {
+		return results.WithError(err)
+	}
+	if !canRestart {
+		return results.WithReconciliationState(defaultRequeue.WithReason("Nodes upgrade: waiting for a restart slot of the namespace restart policy"))
+	}
+
+	// Snapshot the volumes of the cluster before restarting the first node, if requested.
+	if len(podsToUpgrade) > 0 {
+		snapshotsReady, err := d.reconcileVolumeSnapshots(ctx)
+		if err != nil {
+			return results.WithError(err)
+		}
+		if !snapshotsReady {
+			return results.WithReconciliationState(defaultRequeue.WithReason("Nodes upgrade: waiting for the VolumeSnapshots to be ready to use"))
+		}
+	}
+
 	expectedMasters := expectedResources.MasterNodesNames()
 
 	// Maybe upgrade some of the nodes.
@@ -92,6 +173,7 @@ func (d *defaultDriver) handleUpgrades(
 		esClient,
 		esState,
 		nodeShutdown,
+		ephemeralShutdown,
 		expectedMasters,
 		podsToUpgrade,
 		healthyPods,
@@ -100,13 +182,9 @@ func (d *defaultDriver) handleUpgrades(
 
 	var deletedPods []corev1.Pod
 
-	isVersionUpgrade, err := isVersionUpgrade(d.ES)
-	if err != nil {
-		return results.WithError(err)
-	}
 	shouldDoFullRestartUpgrade := isNonHACluster(currentPods, expectedMasters) && isVersionUpgrade
-	if shouldDoFullRestartUpgrade {
-		// unconditional full cluster upgrade
+	if shouldDoFullRestartUpgrade || restart.IsFull(&d.ES) {
+		// unconditional full cluster upgrade, or full cluster restart requested by the user
 		deletedPods, err = run(upgrade.DeleteAll)
 	} else {
 		// regular rolling upgrade
@@ -127,21 +205,24 @@ func (d *defaultDriver) handleUpgrades(
 }
 
 type upgradeCtx struct {
-	parentCtx       context.Context
-	client          k8s.Client
-	ES              esv1.Elasticsearch
-	resourcesList   nodespec.ResourcesList
-	statefulSets    es_sset.StatefulSetList
-	esClient        esclient.Client
-	shardLister     esclient.ShardLister
-	nodeShutdown    *shutdown.NodeShutdown
-	esState         ESState
-	expectations    *expectations.Expectations
-	reconcileState  *reconcile.State
-	expectedMasters []string
-	podsToUpgrade   []corev1.Pod
-	healthyPods     map[string]corev1.Pod
-	currentPods     []corev1.Pod
+	parentCtx     context.Context
+	client        k8s.Client
+	ES            esv1.Elasticsearch
+	resourcesList nodespec.ResourcesList
+	statefulSets  es_sset.StatefulSetList
+	esClient      esclient.Client
+	shardLister   esclient.ShardLister
+	nodeShutdown  *shutdown.NodeShutdown
+	// ephemeralShutdown relocates the data of the Pods of ephemeral NodeSets before they are deleted
+	ephemeralShutdown     *shutdown.NodeShutdown
+	ephemeralStatefulSets set.StringSet
+	esState               ESState
+	expectations          *expectations.Expectations
+	reconcileState        *reconcile.State
+	expectedMasters       []string
+	podsToUpgrade         []corev1.Pod
+	healthyPods           map[string]corev1.Pod
+	currentPods           []corev1.Pod
 }
 
 func newUpgrade(
@@ -152,27 +233,30 @@ func newUpgrade(
 	esClient esclient.Client,
 	esState ESState,
 	nodeShutdown *shutdown.NodeShutdown,
+	ephemeralShutdown *shutdown.NodeShutdown,
 	expectedMaster []string,
 	podsToUpgrade []corev1.Pod,
 	healthyPods map[string]corev1.Pod,
 	currentPods []corev1.Pod,
 ) upgradeCtx {
 	return upgradeCtx{
-		parentCtx:       ctx,
-		client:          d.Client,
-		ES:              d.ES,
-		statefulSets:    statefulSets,
-		resourcesList:   resourcesList,
-		esClient:        esClient,
-		shardLister:     esClient,
-		nodeShutdown:    nodeShutdown,
-		esState:         esState,
-		expectations:    d.Expectations,
-		reconcileState:  d.ReconcileState,
-		expectedMasters: expectedMaster,
-		podsToUpgrade:   podsToUpgrade,
-		healthyPods:     healthyPods,
-		currentPods:     currentPods,
+		parentCtx:             ctx,
+		client:                d.Client,
+		ES:                    d.ES,
+		statefulSets:          statefulSets,
+		resourcesList:         resourcesList,
+		esClient:              esClient,
+		shardLister:           esClient,
+		nodeShutdown:          nodeShutdown,
+		ephemeralShutdown:     ephemeralShutdown,
+		ephemeralStatefulSets: d.ES.EphemeralStatefulSets(),
+		esState:               esState,
+		expectations:          d.Expectations,
+		reconcileState:        d.ReconcileState,
+		expectedMasters:       expectedMaster,
+		podsToUpgrade:         podsToUpgrade,
+		healthyPods:           healthyPods,
+		currentPods:           currentPods,
 	}
 }
 
@@ -240,8 +324,9 @@ func healthyPods(
 	return healthyPods, nil
 }
 
-// podsToUpgrade returns all Pods of all StatefulSets where the controller-revision-hash label compared to the sset's
-// .status.updateRevision indicates that the Pod still needs to be deleted to be recreated with the new spec.
+// podsToUpgrade returns all Pods of all StatefulSets where the controller-revision-hash label, or the revision the Pod
+// was resized to in place, compared to the sset's .status.updateRevision indicates that the Pod still needs to be
+// deleted to be recreated with the new spec.
 func podsToUpgrade(
 	client k8s.Client,
 	statefulSets es_sset.StatefulSetList,
@@ -268,7 +353,7 @@ func podsToUpgrade(
 				// Pod does not exist, continue the loop as the absence will be accounted by the deletion driver
 				continue
 			}
-			if sset.PodRevision(pod) != statefulSet.Status.UpdateRevision {
+			if podRevision(pod) != statefulSet.Status.UpdateRevision {
 				toUpgrade = append(toUpgrade, pod)
 			}
 		}
@@ -318,6 +403,7 @@ func (d *defaultDriver) maybeCompleteNodeUpgrades(
 	esClient esclient.Client,
 	esState ESState,
 	nodeShutdown *shutdown.NodeShutdown,
+	ephemeralShutdown *shutdown.NodeShutdown,
 ) *reconciler.Results {
 	results := &reconciler.Results{}
 	// Make sure all pods scheduled for upgrade have been upgraded.
@@ -355,6 +441,12 @@ func (d *defaultDriver) maybeCompleteNodeUpgrades(
 			nodeShutdown.OnlyNodesInCluster,
 			nodeShutdown.OnlyNonTerminatingNodes(terminating),
 		))
+		// ephemeral nodes join the cluster again with a new node ID once restarted: clear the completed shutdowns of
+		// type remove that relate to nodes which are no longer in the cluster.
+		results = results.WithError(ephemeralShutdown.Clear(ctx,
+			esclient.ShutdownComplete.Applies,
+			ephemeralShutdown.OnlyNodesNotInCluster,
+		))
 	}
 
 	// Make sure all nodes scheduled for upgrade are back into the cluster.
@@ -419,6 +511,9 @@ func (ctx *upgradeCtx) readyToDelete(pod corev1.Pod) (bool, error) {
 		// there is no point in trying to query the shutdown status of a Pod that is not ready
 		return true, nil
 	}
+	if isEphemeralPod(ctx.ephemeralStatefulSets, pod) {
+		return ctx.ephemeralDataRelocated(pod)
+	}
 	response, err := ctx.nodeShutdown.ShutdownStatus(ctx.parentCtx, pod.Name)
 	if err != nil {
 		return false, err
@@ -426,7 +521,53 @@ func (ctx *upgradeCtx) readyToDelete(pod corev1.Pod) (bool, error) {
 	return response.Status == esclient.ShutdownComplete, nil
 }
 
+// ephemeralDataRelocated returns true if the data of the given ephemeral Pod has been relocated to other nodes.
+func (ctx *upgradeCtx) ephemeralDataRelocated(pod corev1.Pod) (bool, error) {
+	response, err := ctx.ephemeralShutdown.ShutdownStatus(ctx.parentCtx, pod.Name)
+	if err != nil {
+		return false, err
+	}
+	switch response.Status {
+	case esclient.ShutdownComplete:
+		return true, nil
+	case esclient.ShutdownStalled:
+		// data relocation stalled this can require user interaction: bubble up via event
+		ctx.reconcileState.
+			UpdateWithPhase(esv1.ElasticsearchNodeShutdownStalledPhase).
+			AddEvent(
+				corev1.EventTypeWarning,
+				events.EventReasonStalled,
+				fmt.Sprintf("Rolling upgrade of ephemeral node %s is stalled. User intervention maybe required if this condition persists. %s", pod.Name, response.Explanation),
+			)
+	case esclient.ShutdownInProgress:
+		ctx.reconcileState.
+			UpdateWithPhase(esv1.ElasticsearchMigratingDataPhase).
+			AddEvent(
+				corev1.EventTypeNormal,
+				events.EventReasonDelayed,
+				fmt.Sprintf("Rolling upgrade of ephemeral node %s delayed by data migration. Ensure index settings allow node removal.", pod.Name),
+			)
+	case esclient.ShutdownNotStarted:
+		return false, fmt.Errorf("unexpected state, node shutdown could not be started: %s", response.Explanation)
+	}
+	return false, nil
+}
+
 func (ctx *upgradeCtx) requestNodeRestarts(podsToRestart []corev1.Pod) error {
+	persistentPods, ephemeralPods := splitEphemeralPods(ctx.ephemeralStatefulSets, podsToRestart)
+	if len(ephemeralPods) > 0 {
+		if err := ctx.reconcileShutdowns(ctx.ephemeralShutdown, ephemeralPods); err != nil {
+			return err
+		}
+		if len(persistentPods) == 0 {
+			// do not cancel ongoing restarts of persistent nodes
+			return nil
+		}
+	}
+	return ctx.reconcileShutdowns(ctx.nodeShutdown, persistentPods)
+}
+
+func (ctx *upgradeCtx) reconcileShutdowns(nodeShutdown *shutdown.NodeShutdown, podsToRestart []corev1.Pod) error {
 	var podNames []string //nolint:prealloc
 	for _, p := range podsToRestart {
 		if !k8s.IsPodReady(p) {
@@ -442,7 +583,7 @@ func (ctx *upgradeCtx) requestNodeRestarts(podsToRestart []corev1.Pod) error {
 	}
 	// Note that ReconcileShutdowns would cancel ongoing shutdowns when called with no podNames
 	// this is however not the case in the rolling upgrade logic where we exit early if no pod needs to be rotated.
-	return ctx.nodeShutdown.ReconcileShutdowns(ctx.parentCtx, podNames, k8s.PodNames(k8s.TerminatingPods(ctx.currentPods)))
+	return nodeShutdown.ReconcileShutdowns(ctx.parentCtx, podNames, k8s.PodNames(k8s.TerminatingPods(ctx.currentPods)))
 }
 
 func (ctx *upgradeCtx) prepareClusterForNodeRestart(podsToUpgrade []corev1.Pod) error {
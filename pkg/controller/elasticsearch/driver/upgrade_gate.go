@@ -0,0 +1,39 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/upgradegate"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+)
+
+// upgradeGateApproved returns true if the given Pods can be restarted according to the upgrade gates of the cluster.
+// Only the restarts of a version upgrade are paused, the step waiting for approval is reported in the status.
+func (d *defaultDriver) upgradeGateApproved(
+	ctx context.Context,
+	isVersionUpgrade bool,
+	podsToUpgrade []corev1.Pod,
+	currentPods []corev1.Pod,
+	expectedPods int32,
+) bool {
+	approved := true
+	if isVersionUpgrade && len(podsToUpgrade) > 0 {
+		var upgradedPods int32
+		for _, pod := range currentPods {
+			if pod.DeletionTimestamp.IsZero() && pod.Labels[label.VersionLabelName] == d.ES.Spec.Version {
+				upgradedPods++
+			}
+		}
+		approved = upgradegate.Approved(ctx, d.Recorder(), &d.ES, d.ES.Spec.Version, upgradedPods, expectedPods)
+	} else {
+		upgradegate.Clear(&d.ES)
+	}
+	d.ReconcileState.UpdateUpgradeGate(d.ES.Status.UpgradeGate)
+	return approved
+}
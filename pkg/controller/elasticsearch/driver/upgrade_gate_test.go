@@ -0,0 +1,72 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+)
+
+func Test_defaultDriver_upgradeGateApproved(t *testing.T) {
+	pod := func(name, version string) corev1.Pod {
+		return corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, Labels: map[string]string{label.VersionLabelName: version}}}
+	}
+	currentPods := []corev1.Pod{pod("es-0", "8.15.0"), pod("es-1", "8.14.0"), pod("es-2", "8.14.0")}
+	driver := func(annotations map[string]string) *defaultDriver {
+		es := esv1.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es", Annotations: annotations},
+			Spec:       esv1.ElasticsearchSpec{Version: "8.15.0"},
+		}
+		return &defaultDriver{DefaultDriverParameters: DefaultDriverParameters{
+			ES:             es,
+			ReconcileState: reconcile.MustNewState(es),
+			Recorder:       record.NewFakeRecorder(10),
+		}}
+	}
+	upgradeGate := func(d *defaultDriver) *commonv1.UpgradeGateStatus {
+		_, es := d.ReconcileState.Apply()
+		if es == nil {
+			return nil
+		}
+		return es.Status.UpgradeGate
+	}
+
+	// upgrades are not paused without the upgrade gates annotation
+	d := driver(nil)
+	require.True(t, d.upgradeGateApproved(context.Background(), true, currentPods[1:], currentPods, 3))
+	require.Nil(t, upgradeGate(d))
+
+	// the next step waits for approval
+	d = driver(map[string]string{commonv1.UpgradeGatesAnnotation: "true"})
+	require.False(t, d.upgradeGateApproved(context.Background(), true, currentPods[1:], currentPods, 3))
+	status := upgradeGate(d)
+	require.NotNil(t, status)
+	require.Equal(t, "8.15.0/1", status.Step)
+	require.Equal(t, int32(1), status.UpgradedPods)
+	require.Equal(t, int32(3), status.ExpectedPods)
+
+	// an approval of a previous step does not apply to the next one
+	d = driver(map[string]string{commonv1.UpgradeGatesAnnotation: "true", commonv1.UpgradeGateApprovedAnnotation: "8.15.0/0"})
+	require.False(t, d.upgradeGateApproved(context.Background(), true, currentPods[1:], currentPods, 3))
+
+	d = driver(map[string]string{commonv1.UpgradeGatesAnnotation: "true", commonv1.UpgradeGateApprovedAnnotation: "8.15.0/1"})
+	require.True(t, d.upgradeGateApproved(context.Background(), true, currentPods[1:], currentPods, 3))
+	require.Nil(t, upgradeGate(d))
+
+	// restarts unrelated to a version upgrade are never paused
+	d = driver(map[string]string{commonv1.UpgradeGatesAnnotation: "true"})
+	require.True(t, d.upgradeGateApproved(context.Background(), false, currentPods[1:], currentPods, 3))
+	require.Nil(t, upgradeGate(d))
+}
@@ -6,12 +6,14 @@ package driver
 
 import (
 	"context"
+	"fmt"
 	"sort"
 
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/expectations"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
@@ -33,12 +35,19 @@ func (ctx *upgradeCtx) Delete() ([]corev1.Pod, error) {
 		return nil, nil
 	}
 	// Get allowed deletions and check if maxUnavailable has been reached.
-	allowedDeletions, maxUnavailableReached := ctx.getAllowedDeletions()
+	allowedDeletions, maxUnavailableReached, err := ctx.getAllowedDeletions()
+	if err != nil {
+		return nil, err
+	}
 
 	// Step 1. Sort the Pods to get the ones with the higher priority
 	candidates := make([]corev1.Pod, len(ctx.podsToUpgrade)) // work on a copy in order to have no side effect
 	copy(candidates, ctx.podsToUpgrade)
 	sortCandidates(candidates)
+	if ctx.ES.Spec.UpdateStrategy.ChangeBudget.RollingRestart != nil {
+		// restart the NodeSets in parallel rather than one after the other
+		candidates = interleaveStatefulSets(candidates)
+	}
 
 	// Step 2: Apply predicates
 	predicateContext := NewPredicateContext(
@@ -135,27 +144,48 @@ func (ctx *upgradeCtx) DeleteAll() ([]corev1.Pod, error) {
 }
 
 // getAllowedDeletions returns the number of deletions that can be done and if maxUnavailable has been reached.
-func (ctx *upgradeCtx) getAllowedDeletions() (int, bool) {
+func (ctx *upgradeCtx) getAllowedDeletions() (int, bool, error) {
 	// Check if we are not over disruption budget
 	// Upscale is done, we should have the required number of Pods
 	actualPods := ctx.statefulSets.PodNames()
 	unhealthyPods := len(actualPods) - len(ctx.healthyPods)
 
-	maxUnavailable := ctx.ES.Spec.UpdateStrategy.ChangeBudget.GetMaxUnavailableOrDefault()
+	maxUnavailable, err := ctx.getMaxUnavailable()
+	if err != nil {
+		return 0, false, err
+	}
 	if maxUnavailable == nil {
 		// maxUnavailable is unbounded, we allow removing all pods
-		return len(actualPods), false
+		return len(actualPods), false, nil
 	}
 
 	allowedDeletions := int(*maxUnavailable) - unhealthyPods
 	// If maxUnavailable is reached the deletion driver still allows one unhealthy Pod to be restarted.
 	maxUnavailableReached := allowedDeletions <= 0
-	return allowedDeletions, maxUnavailableReached
+	return allowedDeletions, maxUnavailableReached, nil
+}
+
+// getMaxUnavailable returns the maximum number of Pods that can be unavailable during the rolling upgrade.
+// If a rolling restart budget is specified it replaces maxUnavailable as long as the cluster health is green.
+func (ctx *upgradeCtx) getMaxUnavailable() (*int32, error) {
+	changeBudget := ctx.ES.Spec.UpdateStrategy.ChangeBudget
+	if changeBudget.RollingRestart == nil {
+		return changeBudget.GetMaxUnavailableOrDefault(), nil
+	}
+	health, err := ctx.esState.Health()
+	if err != nil {
+		return nil, err
+	}
+	if health.Status != esv1.ElasticsearchGreenHealth {
+		return changeBudget.GetMaxUnavailableOrDefault(), nil
+	}
+	return changeBudget.GetMaxConcurrentRestartsOrDefault(), nil
 }
 
 // sortCandidates is the default sort function, masters have lower priority as
-// we want to update the data nodes first. After that pods are sorted by stateful set name
-// then reverse ordinal order
+// we want to update the data nodes first, and voting-only masters come last as they
+// cannot be elected: the master-eligible nodes are upgraded while they keep the quorum.
+// After that pods are sorted by stateful set name then reverse ordinal order
 // TODO: Add some priority to unhealthy (bootlooping) Pods
 func sortCandidates(allPods []corev1.Pod) {
 	sort.Slice(allPods, func(i, j int) bool {
@@ -168,7 +198,11 @@ func sortCandidates(allPods []corev1.Pod) {
 		if !label.IsMasterNode(pod1) && label.IsMasterNode(pod2) {
 			return true
 		}
-		// neither or both are masters, use the reverse name function
+		// check if either is a voting-only master node. voting-only masters come after the other masters
+		if label.IsVotingOnlyNode(pod1) != label.IsVotingOnlyNode(pod2) {
+			return label.IsVotingOnlyNode(pod2)
+		}
+		// same roles, use the reverse name function
 		ssetName1, ord1, err := sset.StatefulSetName(pod1.Name)
 		if err != nil {
 			return false
@@ -185,6 +219,39 @@ func sortCandidates(allPods []corev1.Pod) {
 	})
 }
 
+// interleaveStatefulSets reorders the sorted candidates so that the Pods of the different StatefulSets alternate, while
+// keeping the order of the Pods of each StatefulSet. Masters still come after all other roles.
+func interleaveStatefulSets(sortedPods []corev1.Pod) []corev1.Pod {
+	var statefulSetNames []string
+	podsByStatefulSet := make(map[string][]corev1.Pod)
+	var masters []corev1.Pod
+	for _, pod := range sortedPods {
+		if label.IsMasterNode(pod) {
+			masters = append(masters, pod)
+			continue
+		}
+		statefulSetName, _, err := sset.StatefulSetName(pod.Name)
+		if err != nil {
+			// keep the Pods in their original order
+			return sortedPods
+		}
+		if _, exists := podsByStatefulSet[statefulSetName]; !exists {
+			statefulSetNames = append(statefulSetNames, statefulSetName)
+		}
+		podsByStatefulSet[statefulSetName] = append(podsByStatefulSet[statefulSetName], pod)
+	}
+
+	result := make([]corev1.Pod, 0, len(sortedPods))
+	for i := 0; len(result) < len(sortedPods)-len(masters); i++ {
+		for _, statefulSetName := range statefulSetNames {
+			if pods := podsByStatefulSet[statefulSetName]; i < len(pods) {
+				result = append(result, pods[i])
+			}
+		}
+	}
+	return append(result, masters...)
+}
+
 // handleMasterScaleChange handles Zen updates when a type change results in the addition or the removal of a master:
 // In case of a master scale down it shares the same logic that a "traditional" scale down:
 // * We proactively set m_m_n to the value of 1 if there are 2 Zen1 masters left
@@ -236,6 +303,7 @@ func deletePod(
 	expectations.ExpectDeletion(pod)
 	// Update status
 	reconcileState.RecordDeletedNode(pod.Name, msg)
+	reconcileState.AddEvent(corev1.EventTypeNormal, events.EventReasonPodDeleted, fmt.Sprintf("%s: %s", msg, pod.Name))
 	return nil
 }
 
@@ -7,6 +7,7 @@ package driver
 import (
 	"context"
 	"sort"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -17,8 +18,10 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/nodespec"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/settings"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/stringsutil"
 )
 
@@ -558,6 +561,102 @@ var predicates = [...]Predicate{
 			return true, nil
 		},
 	},
+	{
+		// If a maximum number of NodeSets is specified in the rolling restart budget, do not restart the Pods of another
+		// NodeSet once this number of NodeSets have Pods being restarted or unavailable.
+		name: "restart_pods_of_a_limited_number_of_nodesets_at_a_time",
+		fn: func(
+			context PredicateContext,
+			candidate corev1.Pod,
+			_ []corev1.Pod,
+			_ bool,
+		) (bool, error) {
+			rollingRestart := context.es.Spec.UpdateStrategy.ChangeBudget.RollingRestart
+			if rollingRestart == nil || rollingRestart.MaxConcurrentNodeSets == nil {
+				return true, nil
+			}
+			candidateStatefulSet, _, err := sset.StatefulSetName(candidate.Name)
+			if err != nil {
+				return false, err
+			}
+			// Pods deleted in this iteration are not part of the healthy Pods anymore
+			restartingStatefulSets := set.Make()
+			for _, pod := range context.currentPods {
+				if _, healthy := context.healthyPods[pod.Name]; healthy {
+					continue
+				}
+				statefulSetName, _, err := sset.StatefulSetName(pod.Name)
+				if err != nil {
+					return false, err
+				}
+				restartingStatefulSets.Add(statefulSetName)
+			}
+			if restartingStatefulSets.Has(candidateStatefulSet) {
+				return true, nil
+			}
+			return restartingStatefulSets.Count() < int(*rollingRestart.MaxConcurrentNodeSets), nil
+		},
+	},
+	{
+		// If a zone attribute is specified in the rolling restart budget, only restart nodes of the same zone at the
+		// same time within a NodeSet. Nodes of different NodeSets, for example deployed in different availability zones,
+		// can be restarted at the same time as long as the nodes of at least one zone keep running, provided that shard
+		// allocation awareness is configured on the zone attribute for all the nodes, that the cluster health is green
+		// and that all the shards have at least one replica: a copy of the shards then remains available. Nodes with an
+		// unknown zone are restarted one at a time.
+		name: "restart_nodes_of_a_single_zone_at_a_time",
+		fn: func(
+			context PredicateContext,
+			candidate corev1.Pod,
+			deletedPods []corev1.Pod,
+			_ bool,
+		) (bool, error) {
+			rollingRestart := context.es.Spec.UpdateStrategy.ChangeBudget.RollingRestart
+			if rollingRestart == nil || rollingRestart.ZoneAttribute == "" || len(deletedPods) == 0 {
+				return true, nil
+			}
+			nodeAttributes, err := context.esState.NodeAttributes()
+			if err != nil {
+				return false, err
+			}
+			zone := nodeAttributes[candidate.Name][rollingRestart.ZoneAttribute]
+			if zone == "" {
+				return false, nil
+			}
+			candidateStatefulSet, _, err := sset.StatefulSetName(candidate.Name)
+			if err != nil {
+				return false, err
+			}
+			restartingZones := set.Make(zone)
+			for _, deletedPod := range deletedPods {
+				deletedPodZone := nodeAttributes[deletedPod.Name][rollingRestart.ZoneAttribute]
+				if deletedPodZone == "" {
+					return false, nil
+				}
+				deletedPodStatefulSet, _, err := sset.StatefulSetName(deletedPod.Name)
+				if err != nil {
+					return false, err
+				}
+				if deletedPodZone != zone && deletedPodStatefulSet == candidateStatefulSet {
+					return false, nil
+				}
+				restartingZones.Add(deletedPodZone)
+			}
+			if restartingZones.Count() == 1 {
+				return true, nil
+			}
+			if safe, err := zonesCanRestartConcurrently(context, rollingRestart.ZoneAttribute); err != nil || !safe {
+				return false, err
+			}
+			allZones := set.Make()
+			for _, attributes := range nodeAttributes {
+				if nodeZone := attributes[rollingRestart.ZoneAttribute]; nodeZone != "" {
+					allZones.Add(nodeZone)
+				}
+			}
+			return restartingZones.Count() < allZones.Count(), nil
+		},
+	},
 	{
 		name: "do_not_delete_all_members_of_a_tier",
 		fn: func(
@@ -630,6 +729,64 @@ func willBecomeMasterNode(name string, masters []string) bool {
 	return stringsutil.StringInSlice(name, masters)
 }
 
+// zonesCanRestartConcurrently returns true if the nodes of different zones can be restarted at the same time without
+// making shards unavailable: shard allocation awareness must be configured on the zone attribute for all the nodes, so
+// that the copies of a shard are spread across zones, the cluster health must be green and all the shards must have
+// at least one replica.
+func zonesCanRestartConcurrently(context PredicateContext, zoneAttribute string) (bool, error) {
+	for _, resources := range context.resourcesList {
+		if !hasAwarenessAttribute(resources.Config, zoneAttribute) {
+			return false, nil
+		}
+	}
+	health, err := context.esState.Health()
+	if err != nil {
+		return false, err
+	}
+	if health.Status != esv1.ElasticsearchGreenHealth {
+		return false, nil
+	}
+	shards, err := context.shardLister.GetShards(context.ctx)
+	if err != nil {
+		return false, err
+	}
+	replicated := set.Make()
+	for _, shard := range shards {
+		if shard.IsReplica() {
+			replicated.Add(shard.Index + "/" + shard.Shard)
+		}
+	}
+	for _, shard := range shards {
+		if !replicated.Has(shard.Index + "/" + shard.Shard) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// hasAwarenessAttribute returns true if the given attribute is one of the shard allocation awareness attributes of
+// the given node configuration.
+func hasAwarenessAttribute(cfg settings.CanonicalConfig, attribute string) bool {
+	if cfg.CanonicalConfig == nil {
+		return false
+	}
+	var awareness struct {
+		// a comma-separated string is unpacked as a single element
+		Attributes []string `config:"cluster.routing.allocation.awareness.attributes"`
+	}
+	if err := cfg.CanonicalConfig.Unpack(&awareness); err != nil {
+		return false
+	}
+	for _, attributes := range awareness.Attributes {
+		for _, a := range strings.Split(attributes, ",") {
+			if strings.TrimSpace(a) == attribute {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func conflictingShards(shards1, shards2 []client.Shard) bool {
 	for _, shards1 := range shards1 {
 		for _, shards2 := range shards2 {
@@ -11,16 +11,22 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
 	crlog "sigs.k8s.io/controller-runtime/pkg/log"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/expectations"
+	common "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/migration"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/nodespec"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/settings"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/shutdown"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
 )
 
 func Test_hasDependencyInOthers(t *testing.T) {
@@ -274,10 +280,25 @@ func TestUpgradePodsDeletion_WithNodeTypeMutations(t *testing.T) {
 		/* Zen1 checks */
 		assert.Equal(t, tt.minimumMasterNodesCalled, esClient.SetMinimumMasterNodesCalled, tt.name)
 		assert.Equal(t, tt.minimumMasterNodesCalledWith, esClient.SetMinimumMasterNodesCalledWith, tt.name)
-		assert.Equal(t, tt.recordedEvents, len(ctx.reconcileState.Events()), tt.name)
+		var podDeletedEvents, otherEvents int
+		for _, event := range ctx.reconcileState.Events() {
+			if event.Reason == events.EventReasonPodDeleted {
+				podDeletedEvents++
+			} else {
+				otherEvents++
+			}
+		}
+		assert.Equal(t, len(deleted), podDeletedEvents, tt.name)
+		assert.Equal(t, tt.recordedEvents, otherEvents, tt.name)
 	}
 }
 
+// replicatedShards is a replicated shard allocated to nodes that are not restarted in the rolling restart budget tests.
+var replicatedShards = client.Shards{
+	{Index: "replicated", Shard: "0", State: client.STARTED, NodeName: "masters-0", Type: client.Primary},
+	{Index: "replicated", Shard: "0", State: client.STARTED, NodeName: "data-c-0", Type: client.Replica},
+}
+
 func TestUpgradePodsDeletion_Delete(t *testing.T) {
 	type fields struct {
 		upgradeTestPods upgradeTestPods
@@ -288,6 +309,10 @@ func TestUpgradePodsDeletion_Delete(t *testing.T) {
 		podFilter       filter
 		esVersion       string
 		esAnnotations   map[string]string
+		ephemeral       []string
+		rollingRestart  *esv1.RollingRestartBudget
+		nodeAttributes  map[string]map[string]string
+		awareness       string
 	}
 	tests := []struct {
 		name                         string
@@ -1099,6 +1124,219 @@ func TestUpgradePodsDeletion_Delete(t *testing.T) {
 			wantErr:                      false,
 			wantShardsAllocationDisabled: true,
 		},
+		{
+			name: "Rolling restart budget: restart several nodes at a time if green",
+			fields: fields{
+				esVersion: "7.5.0",
+				upgradeTestPods: newUpgradeTestPods(
+					newTestPod("masters-0").withRoles(esv1.MasterRole).isHealthy(true).needsUpgrade(false).isInCluster(true),
+					newTestPod("data-0").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+					newTestPod("data-1").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+					newTestPod("data-2").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+					newTestPod("data-3").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+				),
+				maxUnavailable: 1,
+				rollingRestart: &esv1.RollingRestartBudget{MaxConcurrentRestarts: ptr.To[int32](3)},
+				shardLister:    migration.NewFakeShardLister(client.Shards{}),
+				health:         client.Health{Status: esv1.ElasticsearchGreenHealth},
+				podFilter:      nothing,
+			},
+			deleted:                      []string{"data-3", "data-2", "data-1"},
+			wantErr:                      false,
+			wantShardsAllocationDisabled: true,
+		},
+		{
+			name: "Rolling restart budget: fall back to maxUnavailable if not green",
+			fields: fields{
+				esVersion: "7.5.0",
+				upgradeTestPods: newUpgradeTestPods(
+					newTestPod("masters-0").withRoles(esv1.MasterRole).isHealthy(true).needsUpgrade(false).isInCluster(true),
+					newTestPod("data-0").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+					newTestPod("data-1").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+					newTestPod("data-2").withRoles(esv1.DataRole).isHealthy(false).needsUpgrade(true).isInCluster(true),
+				),
+				maxUnavailable: 2,
+				rollingRestart: &esv1.RollingRestartBudget{MaxConcurrentRestarts: ptr.To[int32](3)},
+				shardLister:    migration.NewFakeShardLister(client.Shards{}),
+				health:         client.Health{Status: esv1.ElasticsearchRedHealth},
+				podFilter:      nothing,
+			},
+			// only the unhealthy Pod can be restarted as the cluster is red, maxUnavailable is reached
+			deleted:                      []string{"data-2"},
+			wantErr:                      false,
+			wantShardsAllocationDisabled: true,
+		},
+		{
+			name: "Rolling restart budget: only restart nodes of the same zone at a time",
+			fields: fields{
+				esVersion: "7.5.0",
+				upgradeTestPods: newUpgradeTestPods(
+					newTestPod("masters-0").withRoles(esv1.MasterRole).isHealthy(true).needsUpgrade(false).isInCluster(true),
+					newTestPod("data-0").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+					newTestPod("data-1").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+					newTestPod("data-2").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+					newTestPod("data-3").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+				),
+				maxUnavailable: 1,
+				rollingRestart: &esv1.RollingRestartBudget{MaxConcurrentRestarts: ptr.To[int32](3), ZoneAttribute: "zone"},
+				nodeAttributes: map[string]map[string]string{
+					"data-0": {"zone": "europe-west1-b"},
+					"data-1": {"zone": "europe-west1-c"},
+					"data-2": {"zone": "europe-west1-b"},
+					"data-3": {"zone": "europe-west1-b"},
+				},
+				shardLister: migration.NewFakeShardLister(client.Shards{}),
+				health:      client.Health{Status: esv1.ElasticsearchGreenHealth},
+				podFilter:   nothing,
+			},
+			deleted:                      []string{"data-3", "data-2", "data-0"},
+			wantErr:                      false,
+			wantShardsAllocationDisabled: true,
+		},
+		{
+			name: "Rolling restart budget: restart a limited number of NodeSets at a time",
+			fields: fields{
+				esVersion: "7.5.0",
+				upgradeTestPods: newUpgradeTestPods(
+					newTestPod("masters-0").withRoles(esv1.MasterRole).isHealthy(true).needsUpgrade(false).isInCluster(true),
+					newTestPod("data-a-0").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+					newTestPod("data-a-1").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+					newTestPod("data-b-0").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+					newTestPod("data-b-1").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+					newTestPod("data-c-0").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+					newTestPod("data-c-1").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+				),
+				maxUnavailable: 1,
+				rollingRestart: &esv1.RollingRestartBudget{MaxConcurrentRestarts: ptr.To[int32](6), MaxConcurrentNodeSets: ptr.To[int32](2)},
+				shardLister:    migration.NewFakeShardLister(client.Shards{}),
+				health:         client.Health{Status: esv1.ElasticsearchGreenHealth},
+				podFilter:      nothing,
+			},
+			deleted:                      []string{"data-a-1", "data-b-1", "data-a-0", "data-b-0"},
+			wantErr:                      false,
+			wantShardsAllocationDisabled: true,
+		},
+		{
+			name: "Rolling restart budget: restart NodeSets of different zones at a time if another zone keeps running",
+			fields: fields{
+				esVersion: "7.5.0",
+				upgradeTestPods: newUpgradeTestPods(
+					newTestPod("masters-0").withRoles(esv1.MasterRole).isHealthy(true).needsUpgrade(false).isInCluster(true),
+					newTestPod("data-a-0").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+					newTestPod("data-a-1").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+					newTestPod("data-b-0").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+					newTestPod("data-b-1").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+					newTestPod("data-c-0").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+				),
+				maxUnavailable: 1,
+				rollingRestart: &esv1.RollingRestartBudget{MaxConcurrentRestarts: ptr.To[int32](6), ZoneAttribute: "zone"},
+				awareness:      "k8s_node_name,zone",
+				nodeAttributes: map[string]map[string]string{
+					"masters-0": {"zone": "europe-west1-b"},
+					"data-a-0":  {"zone": "europe-west1-b"},
+					"data-a-1":  {"zone": "europe-west1-b"},
+					"data-b-0":  {"zone": "europe-west1-c"},
+					"data-b-1":  {"zone": "europe-west1-c"},
+					"data-c-0":  {"zone": "europe-west1-d"},
+				},
+				shardLister: migration.NewFakeShardLister(replicatedShards),
+				health:      client.Health{Status: esv1.ElasticsearchGreenHealth},
+				podFilter:   nothing,
+			},
+			// data-c-0 is not restarted at the same time, to keep the nodes of one zone running
+			deleted:                      []string{"data-a-1", "data-b-1", "data-a-0", "data-b-0"},
+			wantErr:                      false,
+			wantShardsAllocationDisabled: true,
+		},
+		{
+			name: "Rolling restart budget: only restart a single zone at a time without shard allocation awareness on the zone attribute",
+			fields: fields{
+				esVersion: "7.5.0",
+				upgradeTestPods: newUpgradeTestPods(
+					newTestPod("masters-0").withRoles(esv1.MasterRole).isHealthy(true).needsUpgrade(false).isInCluster(true),
+					newTestPod("data-a-0").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+					newTestPod("data-a-1").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+					newTestPod("data-b-0").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+					newTestPod("data-b-1").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+					newTestPod("data-c-0").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+				),
+				maxUnavailable: 1,
+				rollingRestart: &esv1.RollingRestartBudget{MaxConcurrentRestarts: ptr.To[int32](6), ZoneAttribute: "zone"},
+				nodeAttributes: map[string]map[string]string{
+					"masters-0": {"zone": "europe-west1-b"},
+					"data-a-0":  {"zone": "europe-west1-b"},
+					"data-a-1":  {"zone": "europe-west1-b"},
+					"data-b-0":  {"zone": "europe-west1-c"},
+					"data-b-1":  {"zone": "europe-west1-c"},
+					"data-c-0":  {"zone": "europe-west1-d"},
+				},
+				shardLister: migration.NewFakeShardLister(replicatedShards),
+				health:      client.Health{Status: esv1.ElasticsearchGreenHealth},
+				podFilter:   nothing,
+			},
+			// the copies of a shard may be allocated to nodes of different zones
+			deleted:                      []string{"data-a-1", "data-a-0"},
+			wantErr:                      false,
+			wantShardsAllocationDisabled: true,
+		},
+		{
+			name: "Rolling restart budget: only restart a single zone at a time if some shards have no replica",
+			fields: fields{
+				esVersion: "7.5.0",
+				upgradeTestPods: newUpgradeTestPods(
+					newTestPod("masters-0").withRoles(esv1.MasterRole).isHealthy(true).needsUpgrade(false).isInCluster(true),
+					newTestPod("data-a-0").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+					newTestPod("data-a-1").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+					newTestPod("data-b-0").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+					newTestPod("data-b-1").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+					newTestPod("data-c-0").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+				),
+				maxUnavailable: 1,
+				rollingRestart: &esv1.RollingRestartBudget{MaxConcurrentRestarts: ptr.To[int32](6), ZoneAttribute: "zone"},
+				awareness:      "k8s_node_name,zone",
+				nodeAttributes: map[string]map[string]string{
+					"masters-0": {"zone": "europe-west1-b"},
+					"data-a-0":  {"zone": "europe-west1-b"},
+					"data-a-1":  {"zone": "europe-west1-b"},
+					"data-b-0":  {"zone": "europe-west1-c"},
+					"data-b-1":  {"zone": "europe-west1-c"},
+					"data-c-0":  {"zone": "europe-west1-d"},
+				},
+				shardLister: migration.NewFakeShardLister(append(client.Shards{
+					{Index: "unreplicated", Shard: "0", State: client.STARTED, NodeName: "masters-0", Type: client.Primary},
+				}, replicatedShards...)),
+				health:    client.Health{Status: esv1.ElasticsearchGreenHealth},
+				podFilter: nothing,
+			},
+			// the unreplicated shard would be unavailable if its node was in a restarting zone
+			deleted:                      []string{"data-a-1", "data-a-0"},
+			wantErr:                      false,
+			wantShardsAllocationDisabled: true,
+		},
+		{
+			name: "Rolling restart budget: restart a limited number of NodeSets at a time without concurrent restarts",
+			fields: fields{
+				esVersion: "7.5.0",
+				upgradeTestPods: newUpgradeTestPods(
+					newTestPod("masters-0").withRoles(esv1.MasterRole).isHealthy(true).needsUpgrade(false).isInCluster(true),
+					newTestPod("data-a-0").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+					newTestPod("data-a-1").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+					newTestPod("data-b-0").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+					newTestPod("data-b-1").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+					newTestPod("data-c-0").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+					newTestPod("data-c-1").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+				),
+				// maxUnavailable applies when maxConcurrentRestarts is not specified
+				maxUnavailable: 3,
+				rollingRestart: &esv1.RollingRestartBudget{MaxConcurrentNodeSets: ptr.To[int32](2)},
+				shardLister:    migration.NewFakeShardLister(client.Shards{}),
+				health:         client.Health{Status: esv1.ElasticsearchGreenHealth},
+				podFilter:      nothing,
+			},
+			deleted:                      []string{"data-a-1", "data-b-1", "data-a-0"},
+			wantErr:                      false,
+			wantShardsAllocationDisabled: true,
+		},
 		{
 			name: "Node shutdown API: predicates allow pod to be upgraded, but shutdown is not complete",
 			fields: fields{
@@ -1154,34 +1392,89 @@ func TestUpgradePodsDeletion_Delete(t *testing.T) {
 			wantErr:                      false,
 			wantShardsAllocationDisabled: false,
 		},
+		{
+			name: "Node shutdown API: ephemeral Pod to be upgraded, data relocation in progress",
+			fields: fields{
+				esVersion: "7.15.2",
+				upgradeTestPods: newUpgradeTestPods(
+					newTestPod("masters-0").withRoles(esv1.MasterRole).isHealthy(true).needsUpgrade(false).isInCluster(true),
+					newTestPod("data-0").inStatefulset("data").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+				),
+				shutdowns: map[string]client.NodeShutdown{
+					"data-0": {Type: "REMOVE", Status: client.ShutdownInProgress},
+				},
+				ephemeral:      []string{"data"},
+				maxUnavailable: 1,
+				shardLister:    migration.NewFakeShardLister(client.Shards{}),
+				health:         client.Health{Status: esv1.ElasticsearchGreenHealth},
+				podFilter:      nothing,
+			},
+			deleted:                      []string{},
+			wantErr:                      false,
+			wantShardsAllocationDisabled: false,
+		},
+		{
+			name: "Node shutdown API: ephemeral Pod to be upgraded, data relocated",
+			fields: fields{
+				esVersion: "7.15.2",
+				upgradeTestPods: newUpgradeTestPods(
+					newTestPod("masters-0").withRoles(esv1.MasterRole).isHealthy(true).needsUpgrade(false).isInCluster(true),
+					newTestPod("data-0").inStatefulset("data").withRoles(esv1.DataRole).isHealthy(true).needsUpgrade(true).isInCluster(true),
+				),
+				shutdowns: map[string]client.NodeShutdown{
+					"data-0": {Type: "REMOVE", Status: client.ShutdownComplete},
+				},
+				ephemeral:      []string{"data"},
+				maxUnavailable: 1,
+				shardLister:    migration.NewFakeShardLister(client.Shards{}),
+				health:         client.Health{Status: esv1.ElasticsearchGreenHealth},
+				podFilter:      nothing,
+			},
+			deleted:                      []string{"data-0"},
+			wantErr:                      false,
+			wantShardsAllocationDisabled: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			esState := &testESState{
-				inCluster: tt.fields.upgradeTestPods.podsInCluster(),
-				health:    tt.fields.health,
+				inCluster:      tt.fields.upgradeTestPods.podsInCluster(),
+				health:         tt.fields.health,
+				nodeAttributes: tt.fields.nodeAttributes,
 			}
 			esClient := &fakeESClient{version: version.MustParse(tt.fields.esVersion), Shutdowns: tt.fields.shutdowns}
 			k8sClient := k8s.NewFakeClient(
 				tt.fields.upgradeTestPods.toClientObjects(tt.fields.esVersion, tt.fields.maxUnavailable, tt.fields.podFilter, tt.fields.esAnnotations)...)
 			nodeShutdown := shutdown.NewNodeShutdown(esClient, tt.fields.upgradeTestPods.podNamesToESNodeID(), client.Restart, "", crlog.Log)
+			ephemeralShutdown := shutdown.NewNodeShutdown(esClient, tt.fields.upgradeTestPods.podNamesToESNodeID(), client.Remove, "", crlog.Log)
 			es := tt.fields.upgradeTestPods.toES(tt.fields.esVersion, tt.fields.maxUnavailable, tt.fields.esAnnotations)
+			es.Spec.UpdateStrategy.ChangeBudget.RollingRestart = tt.fields.rollingRestart
+			resourcesList := tt.fields.upgradeTestPods.toResourcesList(t)
+			if tt.fields.awareness != "" {
+				for _, resources := range resourcesList {
+					require.NoError(t, resources.Config.MergeWith(common.MustCanonicalConfig(map[string]interface{}{
+						esv1.ShardAwarenessAttributes: tt.fields.awareness,
+					})))
+				}
+			}
 			ctx := upgradeCtx{
-				parentCtx:       context.Background(),
-				reconcileState:  reconcile.MustNewState(es),
-				client:          k8sClient,
-				ES:              es,
-				resourcesList:   tt.fields.upgradeTestPods.toResourcesList(t),
-				statefulSets:    tt.fields.upgradeTestPods.toStatefulSetList(),
-				esClient:        esClient,
-				shardLister:     tt.fields.shardLister,
-				esState:         esState,
-				expectations:    expectations.NewExpectations(k8sClient),
-				expectedMasters: tt.fields.upgradeTestPods.toMasters(noMutation),
-				podsToUpgrade:   tt.fields.upgradeTestPods.toUpgrade(),
-				healthyPods:     tt.fields.upgradeTestPods.toHealthyPods(),
-				currentPods:     tt.fields.upgradeTestPods.toCurrentPods(),
-				nodeShutdown:    nodeShutdown,
+				parentCtx:             context.Background(),
+				reconcileState:        reconcile.MustNewState(es),
+				client:                k8sClient,
+				ES:                    es,
+				resourcesList:         resourcesList,
+				statefulSets:          tt.fields.upgradeTestPods.toStatefulSetList(),
+				esClient:              esClient,
+				shardLister:           tt.fields.shardLister,
+				esState:               esState,
+				expectations:          expectations.NewExpectations(k8sClient),
+				expectedMasters:       tt.fields.upgradeTestPods.toMasters(noMutation),
+				podsToUpgrade:         tt.fields.upgradeTestPods.toUpgrade(),
+				healthyPods:           tt.fields.upgradeTestPods.toHealthyPods(),
+				currentPods:           tt.fields.upgradeTestPods.toCurrentPods(),
+				nodeShutdown:          nodeShutdown,
+				ephemeralShutdown:     ephemeralShutdown,
+				ephemeralStatefulSets: set.Make(tt.fields.ephemeral...),
 			}
 
 			deleted, err := ctx.Delete()
@@ -1243,6 +1536,24 @@ func TestDeletionStrategy_SortFunction(t *testing.T) {
 			},
 			want: []string{"data-2", "data-1", "data-0", "amasters-2", "amasters-1", "amasters-0"},
 		},
+		{
+			name: "Voting-only masters last",
+			fields: fields{
+				upgradeTestPods: newUpgradeTestPods(
+					// use "avoting" rather than "voting" to ensure we are not relying on the name sort accidentally
+					newTestPod("avoting-0").withRoles(esv1.MasterRole, esv1.VotingOnlyRole).needsUpgrade(true),
+					newTestPod("masters-0").withRoles(esv1.MasterRole).needsUpgrade(true),
+					newTestPod("coordinating-0").withRoles().needsUpgrade(true),
+					newTestPod("masters-1").withRoles(esv1.MasterRole).needsUpgrade(true),
+					newTestPod("data-0").withRoles(esv1.DataRole).needsUpgrade(true),
+				),
+				esState: &testESState{
+					inCluster: []string{"avoting-0", "masters-0", "coordinating-0", "masters-1", "data-0"},
+					health:    client.Health{Status: esv1.ElasticsearchUnknownHealth},
+				},
+			},
+			want: []string{"coordinating-0", "data-0", "masters-1", "masters-0", "avoting-0"},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1258,6 +1569,100 @@ func TestDeletionStrategy_SortFunction(t *testing.T) {
 	}
 }
 
+func Test_interleaveStatefulSets(t *testing.T) {
+	toUpgrade := newUpgradeTestPods(
+		newTestPod("masters-0").withRoles(esv1.MasterRole).needsUpgrade(true),
+		newTestPod("data-a-0").withRoles(esv1.DataRole).needsUpgrade(true),
+		newTestPod("data-a-1").withRoles(esv1.DataRole).needsUpgrade(true),
+		newTestPod("data-a-2").withRoles(esv1.DataRole).needsUpgrade(true),
+		newTestPod("data-b-0").withRoles(esv1.DataRole).needsUpgrade(true),
+		newTestPod("masters-1").withRoles(esv1.MasterRole).needsUpgrade(true),
+		newTestPod("data-c-0").withRoles(esv1.DataRole).needsUpgrade(true),
+		newTestPod("data-c-1").withRoles(esv1.DataRole).needsUpgrade(true),
+	).toUpgrade()
+	sortCandidates(toUpgrade)
+	assert.Equal(t,
+		[]string{"data-a-2", "data-b-0", "data-c-1", "data-a-1", "data-c-0", "data-a-0", "masters-1", "masters-0"},
+		names(interleaveStatefulSets(toUpgrade)),
+	)
+}
+
+func Test_zonesCanRestartConcurrently(t *testing.T) {
+	resourcesWithConfig := func(cfg map[string]interface{}) nodespec.ResourcesList {
+		return nodespec.ResourcesList{
+			{Config: settings.CanonicalConfig{CanonicalConfig: common.MustCanonicalConfig(cfg)}},
+			{Config: settings.CanonicalConfig{CanonicalConfig: common.MustCanonicalConfig(map[string]interface{}{
+				esv1.ShardAwarenessAttributes: "k8s_node_name,zone",
+			})}},
+		}
+	}
+	tests := []struct {
+		name      string
+		resources nodespec.ResourcesList
+		health    esv1.ElasticsearchHealth
+		shards    client.Shards
+		want      bool
+	}{
+		{
+			name:      "awareness attributes as a string",
+			resources: resourcesWithConfig(map[string]interface{}{esv1.ShardAwarenessAttributes: "zone, rack"}),
+			health:    esv1.ElasticsearchGreenHealth,
+			shards:    replicatedShards,
+			want:      true,
+		},
+		{
+			name:      "awareness attributes as a list",
+			resources: resourcesWithConfig(map[string]interface{}{esv1.ShardAwarenessAttributes: []string{"rack", "zone"}}),
+			health:    esv1.ElasticsearchGreenHealth,
+			shards:    replicatedShards,
+			want:      true,
+		},
+		{
+			name:      "no awareness on the zone attribute for a NodeSet",
+			resources: resourcesWithConfig(map[string]interface{}{esv1.ShardAwarenessAttributes: "rack"}),
+			health:    esv1.ElasticsearchGreenHealth,
+			shards:    replicatedShards,
+			want:      false,
+		},
+		{
+			name:      "no awareness for a NodeSet",
+			resources: resourcesWithConfig(map[string]interface{}{}),
+			health:    esv1.ElasticsearchGreenHealth,
+			shards:    replicatedShards,
+			want:      false,
+		},
+		{
+			name:      "yellow health",
+			resources: resourcesWithConfig(map[string]interface{}{esv1.ShardAwarenessAttributes: "zone"}),
+			health:    esv1.ElasticsearchYellowHealth,
+			shards:    replicatedShards,
+			want:      false,
+		},
+		{
+			name:      "shard without replica",
+			resources: resourcesWithConfig(map[string]interface{}{esv1.ShardAwarenessAttributes: "zone"}),
+			health:    esv1.ElasticsearchGreenHealth,
+			shards: append(client.Shards{
+				{Index: "unreplicated", Shard: "0", State: client.STARTED, NodeName: "data-0", Type: client.Primary},
+			}, replicatedShards...),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			predicateContext := PredicateContext{
+				ctx:           context.Background(),
+				resourcesList: tt.resources,
+				esState:       &testESState{health: client.Health{Status: tt.health}},
+				shardLister:   migration.NewFakeShardLister(tt.shards),
+			}
+			got, err := zonesCanRestartConcurrently(predicateContext, "zone")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func Test_groupByPredicates(t *testing.T) {
 	type args struct {
 		fp failedPredicates
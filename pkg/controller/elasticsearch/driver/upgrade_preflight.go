@@ -0,0 +1,159 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// maxReportedPreflightIssues is the maximum number of blocking issues listed in the message of the
+// UpgradePreflightChecksPassed condition.
+const maxReportedPreflightIssues = 5
+
+// runUpgradePreflightChecks checks that the cluster can be upgraded to the next major version before the first node is
+// restarted, using the deprecation info and system features migration APIs of Elasticsearch. The outcome is reported in
+// the UpgradePreflightChecksPassed condition. It returns true if the upgrade must not start because blocking issues
+// were found, unless the upgrade is forced with spec.updateStrategy.force.
+func (d *defaultDriver) runUpgradePreflightChecks(
+	ctx context.Context,
+	esClient esclient.Client,
+	currentPods []corev1.Pod,
+) (bool, error) {
+	log := ulog.FromContext(ctx).WithValues("namespace", d.ES.Namespace, "es_name", d.ES.Name)
+
+	majorUpgrade, err := isMajorVersionUpgrade(d.ES)
+	if err != nil {
+		return false, err
+	}
+	if !majorUpgrade {
+		d.ReconcileState.RemoveCondition(esv1.UpgradePreflightChecksPassed)
+		return false, nil
+	}
+	started, err := isUpgradeStarted(currentPods, d.ES.Spec.Version)
+	if err != nil {
+		return false, err
+	}
+	if started {
+		// the checks only make sense before the first node is restarted, never interrupt an upgrade in progress
+		return false, nil
+	}
+
+	upgrade := fmt.Sprintf("upgrade from %s to %s", d.ES.Status.Version, d.ES.Spec.Version)
+	force := d.ES.Spec.UpdateStrategy.Force
+
+	issues, err := upgradePreflightIssues(ctx, esClient)
+	if err != nil {
+		d.ReconcileState.ReportCondition(
+			esv1.UpgradePreflightChecksPassed,
+			corev1.ConditionUnknown,
+			fmt.Sprintf("Failed to run the pre-flight checks of the %s: %s", upgrade, err.Error()),
+		)
+		if force {
+			log.Info("Ignoring failed upgrade pre-flight checks as the upgrade is forced", "error", err.Error())
+			return false, nil
+		}
+		return true, err
+	}
+
+	if len(issues) == 0 {
+		d.ReconcileState.ReportCondition(
+			esv1.UpgradePreflightChecksPassed,
+			corev1.ConditionTrue,
+			fmt.Sprintf("No blocking issue found for the %s", upgrade),
+		)
+		return false, nil
+	}
+
+	message := fmt.Sprintf("%d blocking issue(s) found for the %s: %s", len(issues), upgrade, summarizeIssues(issues))
+	if force {
+		message = fmt.Sprintf("%s. Proceeding as spec.updateStrategy.force is set", message)
+	}
+	if previous := d.ES.Status.Conditions.Index(esv1.UpgradePreflightChecksPassed); previous < 0 ||
+		d.ES.Status.Conditions[previous].Message != message {
+		// only emit an event when the outcome of the checks changes to not flood the event stream on each reconciliation
+		d.ReconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonUpgradeBlocked, message)
+	}
+	d.ReconcileState.ReportCondition(esv1.UpgradePreflightChecksPassed, corev1.ConditionFalse, message)
+	log.Info("Upgrade pre-flight checks found blocking issues", "issues", issues, "force", force)
+	return !force, nil
+}
+
+// upgradePreflightIssues returns the issues which must be resolved before upgrading the cluster to the next major
+// version.
+func upgradePreflightIssues(ctx context.Context, esClient esclient.Client) ([]string, error) {
+	deprecations, err := esClient.GetDeprecations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("while retrieving deprecations: %w", err)
+	}
+	issues := deprecations.BlockingIssues()
+
+	systemFeatures, err := esClient.GetSystemFeaturesUpgradeStatus(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("while retrieving system features upgrade status: %w", err)
+	}
+	if systemFeatures.MigrationNeeded() {
+		issue := fmt.Sprintf("system features migration status is %s", systemFeatures.MigrationStatus)
+		if pending := systemFeatures.PendingFeatures(); len(pending) > 0 {
+			issue = fmt.Sprintf("%s: %s", issue, strings.Join(pending, ", "))
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+// summarizeIssues lists the first issues, to keep the condition message readable.
+func summarizeIssues(issues []string) string {
+	if len(issues) <= maxReportedPreflightIssues {
+		return strings.Join(issues, "; ")
+	}
+	return fmt.Sprintf("%s; and %d more",
+		strings.Join(issues[:maxReportedPreflightIssues], "; "), len(issues)-maxReportedPreflightIssues)
+}
+
+// isMajorVersionUpgrade returns true if the specified version has a greater major version than the running one.
+func isMajorVersionUpgrade(es esv1.Elasticsearch) (bool, error) {
+	if es.Status.Version == "" {
+		// the cluster is being created
+		return false, nil
+	}
+	specVersion, err := version.Parse(es.Spec.Version)
+	if err != nil {
+		return false, err
+	}
+	statusVersion, err := version.Parse(es.Status.Version)
+	if err != nil {
+		return false, err
+	}
+	return specVersion.Major > statusVersion.Major, nil
+}
+
+// isUpgradeStarted returns true if at least one of the given Pods already runs the target version.
+func isUpgradeStarted(pods []corev1.Pod, targetVersion string) (bool, error) {
+	target, err := version.Parse(targetVersion)
+	if err != nil {
+		return false, err
+	}
+	for _, pod := range pods {
+		podVersion, err := label.ExtractVersion(pod.Labels)
+		if err != nil {
+			return false, err
+		}
+		if podVersion.GTE(target) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
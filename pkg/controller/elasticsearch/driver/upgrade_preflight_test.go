@@ -0,0 +1,227 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/statefulset"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+)
+
+type fakePreflightESClient struct {
+	esclient.Client
+	deprecations   esclient.Deprecations
+	systemFeatures esclient.SystemFeaturesUpgradeStatus
+	err            error
+	called         bool
+}
+
+func (f *fakePreflightESClient) GetDeprecations(_ context.Context) (esclient.Deprecations, error) {
+	f.called = true
+	return f.deprecations, f.err
+}
+
+func (f *fakePreflightESClient) GetSystemFeaturesUpgradeStatus(_ context.Context) (esclient.SystemFeaturesUpgradeStatus, error) {
+	return f.systemFeatures, nil
+}
+
+func Test_defaultDriver_runUpgradePreflightChecks(t *testing.T) {
+	criticalDeprecations := esclient.Deprecations{
+		ClusterSettings: []esclient.Deprecation{{Level: esclient.DeprecationLevelCritical, Message: "setting removed"}},
+		IndexSettings: map[string][]esclient.Deprecation{
+			"old-index": {{Level: esclient.DeprecationLevelCritical, Message: "index created in 7.x"}},
+			"logs":      {{Level: "warning", Message: "deprecated setting"}},
+		},
+	}
+	tests := []struct {
+		name          string
+		specVersion   string
+		statusVersion string
+		force         bool
+		conditions    commonv1alpha1.Conditions
+		podVersions   []string
+		esClient      *fakePreflightESClient
+		wantBlocked   bool
+		wantErr       bool
+		wantCalled    bool
+		wantCondition *corev1.ConditionStatus
+		wantMessage   string
+		wantEvent     bool
+	}{
+		{
+			name:          "no version upgrade: no checks",
+			specVersion:   "8.18.0",
+			statusVersion: "8.18.0",
+			podVersions:   []string{"8.18.0"},
+			esClient:      &fakePreflightESClient{deprecations: criticalDeprecations},
+		},
+		{
+			name:          "minor version upgrade: no checks, previous condition removed",
+			specVersion:   "8.19.0",
+			statusVersion: "8.18.0",
+			conditions:    commonv1alpha1.Conditions{{Type: esv1.UpgradePreflightChecksPassed, Status: corev1.ConditionFalse}},
+			podVersions:   []string{"8.18.0"},
+			esClient:      &fakePreflightESClient{deprecations: criticalDeprecations},
+		},
+		{
+			name:          "major version upgrade without blocking issue",
+			specVersion:   "9.0.0",
+			statusVersion: "8.18.0",
+			podVersions:   []string{"8.18.0", "8.18.0"},
+			esClient: &fakePreflightESClient{
+				systemFeatures: esclient.SystemFeaturesUpgradeStatus{MigrationStatus: esclient.SystemFeaturesNoMigrationNeeded},
+			},
+			wantCalled:    true,
+			wantCondition: ptr.To(corev1.ConditionTrue),
+			wantMessage:   "No blocking issue found for the upgrade from 8.18.0 to 9.0.0",
+		},
+		{
+			name:          "major version upgrade with blocking issues",
+			specVersion:   "9.0.0",
+			statusVersion: "8.18.0",
+			podVersions:   []string{"8.18.0", "8.18.0"},
+			esClient: &fakePreflightESClient{
+				deprecations: criticalDeprecations,
+				systemFeatures: esclient.SystemFeaturesUpgradeStatus{
+					MigrationStatus: esclient.SystemFeaturesMigrationNeeded,
+					Features: []esclient.SystemFeatureStatus{
+						{FeatureName: "watcher", MigrationStatus: esclient.SystemFeaturesMigrationNeeded},
+					},
+				},
+			},
+			wantBlocked:   true,
+			wantCalled:    true,
+			wantCondition: ptr.To(corev1.ConditionFalse),
+			wantMessage: "3 blocking issue(s) found for the upgrade from 8.18.0 to 9.0.0: cluster settings: setting removed; " +
+				"index old-index: index created in 7.x; system features migration status is MIGRATION_NEEDED: watcher",
+			wantEvent: true,
+		},
+		{
+			name:          "major version upgrade with blocking issues already reported: no new event",
+			specVersion:   "9.0.0",
+			statusVersion: "8.18.0",
+			conditions: commonv1alpha1.Conditions{{
+				Type:   esv1.UpgradePreflightChecksPassed,
+				Status: corev1.ConditionFalse,
+				Message: "2 blocking issue(s) found for the upgrade from 8.18.0 to 9.0.0: cluster settings: setting removed; " +
+					"index old-index: index created in 7.x",
+			}},
+			podVersions:   []string{"8.18.0"},
+			esClient:      &fakePreflightESClient{deprecations: criticalDeprecations},
+			wantBlocked:   true,
+			wantCalled:    true,
+			wantCondition: ptr.To(corev1.ConditionFalse),
+			wantMessage: "2 blocking issue(s) found for the upgrade from 8.18.0 to 9.0.0: cluster settings: setting removed; " +
+				"index old-index: index created in 7.x",
+		},
+		{
+			name:          "major version upgrade with blocking issues forced",
+			specVersion:   "9.0.0",
+			statusVersion: "8.18.0",
+			force:         true,
+			podVersions:   []string{"8.18.0"},
+			esClient:      &fakePreflightESClient{deprecations: criticalDeprecations},
+			wantCalled:    true,
+			wantCondition: ptr.To(corev1.ConditionFalse),
+			wantMessage: "2 blocking issue(s) found for the upgrade from 8.18.0 to 9.0.0: cluster settings: setting removed; " +
+				"index old-index: index created in 7.x. Proceeding as spec.updateStrategy.force is set",
+			wantEvent: true,
+		},
+		{
+			name:          "major version upgrade with failing checks",
+			specVersion:   "9.0.0",
+			statusVersion: "8.18.0",
+			podVersions:   []string{"8.18.0"},
+			esClient:      &fakePreflightESClient{err: errors.New("boom")},
+			wantBlocked:   true,
+			wantErr:       true,
+			wantCalled:    true,
+			wantCondition: ptr.To(corev1.ConditionUnknown),
+			wantMessage:   "Failed to run the pre-flight checks of the upgrade from 8.18.0 to 9.0.0: while retrieving deprecations: boom",
+		},
+		{
+			name:          "major version upgrade with failing checks forced",
+			specVersion:   "9.0.0",
+			statusVersion: "8.18.0",
+			force:         true,
+			podVersions:   []string{"8.18.0"},
+			esClient:      &fakePreflightESClient{err: errors.New("boom")},
+			wantCalled:    true,
+			wantCondition: ptr.To(corev1.ConditionUnknown),
+			wantMessage:   "Failed to run the pre-flight checks of the upgrade from 8.18.0 to 9.0.0: while retrieving deprecations: boom",
+		},
+		{
+			name:          "major version upgrade in progress: no checks, condition preserved",
+			specVersion:   "9.0.0",
+			statusVersion: "8.18.0",
+			conditions:    commonv1alpha1.Conditions{{Type: esv1.UpgradePreflightChecksPassed, Status: corev1.ConditionTrue}},
+			podVersions:   []string{"9.0.0", "8.18.0"},
+			esClient:      &fakePreflightESClient{deprecations: criticalDeprecations},
+			wantCondition: ptr.To(corev1.ConditionTrue),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{
+				Spec: esv1.ElasticsearchSpec{
+					Version:        tt.specVersion,
+					UpdateStrategy: esv1.UpdateStrategy{Force: tt.force},
+				},
+				Status: esv1.ElasticsearchStatus{Version: tt.statusVersion, Conditions: tt.conditions},
+			}
+			pods := make([]corev1.Pod, 0, len(tt.podVersions))
+			for _, v := range tt.podVersions {
+				pods = append(pods, sset.TestPod{Name: "pod", Version: v}.Build())
+			}
+			d := &defaultDriver{
+				DefaultDriverParameters: DefaultDriverParameters{
+					ES:             es,
+					ReconcileState: reconcile.MustNewState(es),
+				},
+			}
+
+			blocked, err := d.runUpgradePreflightChecks(context.Background(), tt.esClient, pods)
+			require.Equal(t, tt.wantErr, err != nil)
+			require.Equal(t, tt.wantBlocked, blocked)
+			require.Equal(t, tt.wantCalled, tt.esClient.called)
+
+			emitted, updated := d.ReconcileState.Apply()
+			status := es.Status
+			if updated != nil {
+				status = updated.Status
+			}
+			index := status.Conditions.Index(esv1.UpgradePreflightChecksPassed)
+			if tt.wantCondition == nil {
+				require.Negative(t, index)
+			} else {
+				require.GreaterOrEqual(t, index, 0)
+				require.Equal(t, *tt.wantCondition, status.Conditions[index].Status)
+				require.Equal(t, tt.wantMessage, status.Conditions[index].Message)
+			}
+			var upgradeBlockedEvents int
+			for _, e := range emitted {
+				if e.Reason == events.EventReasonUpgradeBlocked {
+					upgradeBlockedEvents++
+				}
+			}
+			require.Equal(t, tt.wantEvent, upgradeBlockedEvents == 1)
+		})
+	}
+}
+
+func ptrTo[T any](v T) *T {
+	return &v
+}
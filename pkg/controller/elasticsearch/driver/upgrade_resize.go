@@ -0,0 +1,184 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	commonpod "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/pod"
+	sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/statefulset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/settings"
+	es_sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
+)
+
+// resizedRevisionAnnotation is the StatefulSet revision whose resources a Pod was resized to in place. The Pod keeps
+// the revision label it was created with, which the StatefulSet controller relies on, but matches this revision.
+const resizedRevisionAnnotation = "elasticsearch.k8s.elastic.co/resized-revision"
+
+// resizePodsInPlace resizes in place the containers of the Pods to upgrade whose template only differs from the
+// expected one by resources that can be changed without restarting Elasticsearch. A resized Pod is annotated with the
+// revision it matches once the kubelet has applied the new resources, so that it is no longer considered for a restart.
+// It returns the Pods that still need to be restarted, and whether some Pods are being resized.
+func (d *defaultDriver) resizePodsInPlace(
+	ctx context.Context,
+	statefulSets es_sset.StatefulSetList,
+	podsToUpgrade []corev1.Pod,
+) ([]corev1.Pod, bool, error) {
+	log := ulog.FromContext(ctx)
+	toRestart := make([]corev1.Pod, 0, len(podsToUpgrade))
+	resizing := false
+	heapRestarts := set.Make()
+	for _, pod := range podsToUpgrade {
+		statefulSet, exists := statefulSets.GetByName(pod.Labels[label.StatefulSetNameLabelName])
+		if !exists {
+			toRestart = append(toRestart, pod)
+			continue
+		}
+		current, err := podTemplateAtRevision(ctx, d.Client, pod)
+		if err != nil {
+			return nil, false, err
+		}
+		expected := statefulSet.Spec.Template
+		if current == nil || !onlyResourcesDiffer(*current, expected) ||
+			!commonpod.ResizableWithoutRestart(current.Spec, expected.Spec, func(corev1.Container) bool { return true }) {
+			toRestart = append(toRestart, pod)
+			continue
+		}
+		if !commonpod.ResizableWithoutRestart(current.Spec, expected.Spec, heapFollowsMemory) {
+			// the memory changes but the heap size is derived from the memory when Elasticsearch starts
+			if !heapRestarts.Has(statefulSet.Name) {
+				heapRestarts.Add(statefulSet.Name)
+				d.Recorder().Eventf(&d.ES, corev1.EventTypeNormal, events.EventReasonRestartRequired,
+					"Restarting the Pods of StatefulSet %s to change their memory: the heap size is not set with -Xmx in %s",
+					statefulSet.Name, settings.EnvEsJavaOpts)
+			}
+			toRestart = append(toRestart, pod)
+			continue
+		}
+
+		switch commonpod.GetResizeStatus(pod, expected.Spec) {
+		case commonpod.ResizeNotRequested:
+			log.Info("Resizing Pod in place", "namespace", pod.Namespace, "es_name", d.ES.Name, "pod_name", pod.Name)
+			err := commonpod.Resize(ctx, d.Client, pod, expected.Spec)
+			if apierrors.IsInvalid(err) || apierrors.IsForbidden(err) {
+				// the resize is rejected, for example because it would change the QoS class of the Pod
+				log.Info("Pod cannot be resized in place, restarting it", "namespace", pod.Namespace, "es_name", d.ES.Name, "pod_name", pod.Name, "reason", err.Error())
+				toRestart = append(toRestart, pod)
+				continue
+			}
+			if err != nil {
+				return nil, false, err
+			}
+			resizing = true
+		case commonpod.ResizeInProgress:
+			resizing = true
+		case commonpod.ResizeNotFeasible:
+			// restarting the Pod allows it to be scheduled on a Kubernetes node with enough capacity
+			toRestart = append(toRestart, pod)
+		case commonpod.ResizeComplete:
+			if err := setResizedRevision(ctx, d.Client, pod, statefulSet.Status.UpdateRevision); err != nil {
+				return nil, false, err
+			}
+			log.Info("Pod resized in place", "namespace", pod.Namespace, "es_name", d.ES.Name, "pod_name", pod.Name)
+		}
+	}
+	return toRestart, resizing, nil
+}
+
+// podTemplateAtRevision returns the template the given Pod matches, stored in the ControllerRevision of its
+// StatefulSet, or nil if that revision does not exist anymore.
+func podTemplateAtRevision(ctx context.Context, c k8s.Client, pod corev1.Pod) (*corev1.PodTemplateSpec, error) {
+	revisionName := podRevision(pod)
+	if revisionName == "" {
+		return nil, nil
+	}
+	var revision appsv1.ControllerRevision
+	if err := c.Get(ctx, types.NamespacedName{Namespace: pod.Namespace, Name: revisionName}, &revision); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	// the StatefulSet controller stores the template as a patch of the StatefulSet spec
+	var data struct {
+		Spec struct {
+			Template corev1.PodTemplateSpec `json:"template"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(revision.Data.Raw, &data); err != nil {
+		return nil, err
+	}
+	return &data.Spec.Template, nil
+}
+
+// canResizeInPlace returns true if the given templates only differ by the CPU and memory of their containers, and if
+// the kubelet can apply the new resources without restarting Elasticsearch.
+func canResizeInPlace(current, expected corev1.PodTemplateSpec) bool {
+	return onlyResourcesDiffer(current, expected) && commonpod.ResizableWithoutRestart(current.Spec, expected.Spec, heapFollowsMemory)
+}
+
+// onlyResourcesDiffer returns true if the given templates are identical except for the resources of their containers.
+func onlyResourcesDiffer(current, expected corev1.PodTemplateSpec) bool {
+	if len(current.Spec.Containers) != len(expected.Spec.Containers) {
+		return false
+	}
+	current, expected = *current.DeepCopy(), *expected.DeepCopy()
+	for i := range current.Spec.Containers {
+		current.Spec.Containers[i].Resources = corev1.ResourceRequirements{}
+		expected.Spec.Containers[i].Resources = corev1.ResourceRequirements{}
+	}
+	return equality.Semantic.DeepEqual(current, expected)
+}
+
+// heapFollowsMemory returns true if the given container can use a new amount of memory without being restarted. The
+// JVM heap is sized when Elasticsearch starts: a change of memory requires a restart unless the heap size is set in the
+// JVM options.
+func heapFollowsMemory(container corev1.Container) bool {
+	return container.Name != esv1.ElasticsearchContainerName || hasFixedHeapSize(container)
+}
+
+// hasFixedHeapSize returns true if the maximum heap size is set in the JVM options of the given container.
+func hasFixedHeapSize(container corev1.Container) bool {
+	for _, env := range container.Env {
+		if env.Name == settings.EnvEsJavaOpts && strings.Contains(env.Value, "-Xmx") {
+			return true
+		}
+	}
+	return false
+}
+
+// podRevision returns the StatefulSet revision the given Pod matches: the revision it was resized to in place if any,
+// or the revision it was created from.
+func podRevision(pod corev1.Pod) string {
+	if revision, resized := pod.Annotations[resizedRevisionAnnotation]; resized {
+		return revision
+	}
+	return sset.PodRevision(pod)
+}
+
+// setResizedRevision records that the given Pod was resized in place to the given StatefulSet revision.
+func setResizedRevision(ctx context.Context, c k8s.Client, pod corev1.Pod, revision string) error {
+	updated := pod.DeepCopy()
+	if updated.Annotations == nil {
+		updated.Annotations = map[string]string{}
+	}
+	updated.Annotations[resizedRevisionAnnotation] = revision
+	return c.Patch(ctx, updated, client.MergeFrom(&pod))
+}
@@ -0,0 +1,223 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/settings"
+	es_sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+const (
+	resizeSsetName        = "es-default"
+	resizeCurrentRevision = "es-default-5d8f7c9b8"
+	resizeUpdateRevision  = "es-default-7b6c5d4f9"
+)
+
+func resizeTemplate(cpu, memory string, env ...corev1.EnvVar) corev1.PodTemplateSpec {
+	return corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name: esv1.ElasticsearchContainerName,
+				Env:  env,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu), corev1.ResourceMemory: resource.MustParse(memory)},
+					Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(memory)},
+				},
+			}},
+		},
+	}
+}
+
+func controllerRevision(t *testing.T, name string, template corev1.PodTemplateSpec) *appsv1.ControllerRevision {
+	t.Helper()
+	data, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{"template": template},
+	})
+	require.NoError(t, err)
+	return &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{Namespace: TestEsNamespace, Name: name},
+		Data:       runtime.RawExtension{Raw: data},
+	}
+}
+
+func resizePodFixture(template corev1.PodTemplateSpec, status corev1.PodStatus) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: TestEsNamespace,
+			Name:      resizeSsetName + "-0",
+			Labels: map[string]string{
+				appsv1.StatefulSetRevisionLabel: resizeCurrentRevision,
+				label.StatefulSetNameLabelName:  resizeSsetName,
+			},
+		},
+		Spec:   template.Spec,
+		Status: status,
+	}
+}
+
+func Test_defaultDriver_resizePodsInPlace(t *testing.T) {
+	fixedHeap := corev1.EnvVar{Name: settings.EnvEsJavaOpts, Value: "-Xms2g -Xmx2g"}
+	otherEnv := corev1.EnvVar{Name: "FOO", Value: "bar"}
+	running := func(cpu, memory string) corev1.PodStatus {
+		resources := resizeTemplate(cpu, memory).Spec.Containers[0].Resources
+		return corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{Name: esv1.ElasticsearchContainerName, Resources: &resources}}}
+	}
+
+	tests := []struct {
+		name            string
+		current         corev1.PodTemplateSpec
+		expected        corev1.PodTemplateSpec
+		pod             *corev1.Pod
+		noRevision      bool
+		wantRestart     bool
+		wantResizing    bool
+		wantResources   corev1.PodTemplateSpec
+		wantPodRevision string
+		wantEvent       bool
+	}{
+		{
+			name:            "CPU change: resize the Pod",
+			current:         resizeTemplate("1", "4Gi"),
+			expected:        resizeTemplate("2", "4Gi"),
+			pod:             resizePodFixture(resizeTemplate("1", "4Gi"), running("1", "4Gi")),
+			wantResizing:    true,
+			wantResources:   resizeTemplate("2", "4Gi"),
+			wantPodRevision: resizeCurrentRevision,
+		},
+		{
+			name:            "memory change with a heap sized from the memory: restart the Pod",
+			current:         resizeTemplate("1", "4Gi"),
+			expected:        resizeTemplate("1", "8Gi"),
+			pod:             resizePodFixture(resizeTemplate("1", "4Gi"), running("1", "4Gi")),
+			wantRestart:     true,
+			wantResources:   resizeTemplate("1", "4Gi"),
+			wantPodRevision: resizeCurrentRevision,
+			wantEvent:       true,
+		},
+		{
+			name:            "memory change with a fixed heap size: resize the Pod",
+			current:         resizeTemplate("1", "4Gi", fixedHeap),
+			expected:        resizeTemplate("1", "8Gi", fixedHeap),
+			pod:             resizePodFixture(resizeTemplate("1", "4Gi", fixedHeap), running("1", "4Gi")),
+			wantResizing:    true,
+			wantResources:   resizeTemplate("1", "8Gi", fixedHeap),
+			wantPodRevision: resizeCurrentRevision,
+		},
+		{
+			name:            "CPU change along with another change: restart the Pod",
+			current:         resizeTemplate("1", "4Gi"),
+			expected:        resizeTemplate("2", "4Gi", otherEnv),
+			pod:             resizePodFixture(resizeTemplate("1", "4Gi"), running("1", "4Gi")),
+			wantRestart:     true,
+			wantResources:   resizeTemplate("1", "4Gi"),
+			wantPodRevision: resizeCurrentRevision,
+		},
+		{
+			name:            "unknown revision: restart the Pod",
+			current:         resizeTemplate("1", "4Gi"),
+			expected:        resizeTemplate("2", "4Gi"),
+			pod:             resizePodFixture(resizeTemplate("1", "4Gi"), running("1", "4Gi")),
+			noRevision:      true,
+			wantRestart:     true,
+			wantResources:   resizeTemplate("1", "4Gi"),
+			wantPodRevision: resizeCurrentRevision,
+		},
+		{
+			name:            "resize not applied yet by the kubelet: wait",
+			current:         resizeTemplate("1", "4Gi"),
+			expected:        resizeTemplate("2", "4Gi"),
+			pod:             resizePodFixture(resizeTemplate("2", "4Gi"), running("1", "4Gi")),
+			wantResizing:    true,
+			wantResources:   resizeTemplate("2", "4Gi"),
+			wantPodRevision: resizeCurrentRevision,
+		},
+		{
+			name:     "resize not feasible on the Kubernetes node: restart the Pod",
+			current:  resizeTemplate("1", "4Gi"),
+			expected: resizeTemplate("2", "4Gi"),
+			pod: resizePodFixture(resizeTemplate("2", "4Gi"), corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{Type: "PodResizePending", Status: corev1.ConditionTrue, Reason: "Infeasible"}},
+			}),
+			wantRestart:     true,
+			wantResources:   resizeTemplate("2", "4Gi"),
+			wantPodRevision: resizeCurrentRevision,
+		},
+		{
+			name:            "resize complete: record the revision the Pod was resized to",
+			current:         resizeTemplate("1", "4Gi"),
+			expected:        resizeTemplate("2", "4Gi"),
+			pod:             resizePodFixture(resizeTemplate("2", "4Gi"), running("2", "4Gi")),
+			wantResources:   resizeTemplate("2", "4Gi"),
+			wantPodRevision: resizeUpdateRevision,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statefulSet := appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Namespace: TestEsNamespace, Name: resizeSsetName},
+				Spec:       appsv1.StatefulSetSpec{Template: tt.expected},
+				Status:     appsv1.StatefulSetStatus{UpdateRevision: resizeUpdateRevision},
+			}
+			objects := []client.Object{tt.pod, &statefulSet}
+			if !tt.noRevision {
+				objects = append(objects, controllerRevision(t, resizeCurrentRevision, tt.current))
+			}
+			c := k8s.NewFakeClient(objects...)
+			recorder := record.NewFakeRecorder(10)
+			d := &defaultDriver{DefaultDriverParameters: DefaultDriverParameters{
+				Client:   c,
+				Recorder: recorder,
+				ES:       esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: TestEsNamespace, Name: "es"}},
+			}}
+
+			toRestart, resizing, err := d.resizePodsInPlace(context.Background(), es_sset.StatefulSetList{statefulSet}, []corev1.Pod{*tt.pod})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantResizing, resizing)
+			if tt.wantRestart {
+				assert.Equal(t, []string{tt.pod.Name}, k8s.PodNames(toRestart))
+			} else {
+				assert.Empty(t, toRestart)
+			}
+
+			var pod corev1.Pod
+			require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: TestEsNamespace, Name: tt.pod.Name}, &pod))
+			// the revision label the StatefulSet controller relies on is left untouched
+			assert.Equal(t, resizeCurrentRevision, pod.Labels[appsv1.StatefulSetRevisionLabel])
+			assert.Equal(t, tt.wantPodRevision, podRevision(pod))
+			assert.True(t, equality.Semantic.DeepEqual(tt.wantResources.Spec.Containers[0].Resources, pod.Spec.Containers[0].Resources))
+			assert.Equal(t, tt.wantEvent, len(recorder.Events) > 0)
+		})
+	}
+}
+
+func Test_canResizeInPlace(t *testing.T) {
+	fixedHeap := corev1.EnvVar{Name: settings.EnvEsJavaOpts, Value: "-Xms2g -Xmx2g"}
+	withLabel := resizeTemplate("2", "4Gi")
+	withLabel.Labels = map[string]string{"a": "b"}
+
+	assert.True(t, canResizeInPlace(resizeTemplate("1", "4Gi"), resizeTemplate("2", "4Gi")))
+	assert.True(t, canResizeInPlace(resizeTemplate("1", "4Gi", fixedHeap), resizeTemplate("1", "8Gi", fixedHeap)))
+	assert.False(t, canResizeInPlace(resizeTemplate("1", "4Gi"), resizeTemplate("1", "8Gi")))
+	assert.False(t, canResizeInPlace(resizeTemplate("1", "4Gi"), withLabel))
+}
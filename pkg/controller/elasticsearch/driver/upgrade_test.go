@@ -39,6 +39,11 @@ func podWithRevision(name, revision string) *corev1.Pod {
 	}
 }
 
+func resizedPod(pod *corev1.Pod, revision string) *corev1.Pod {
+	pod.Annotations = map[string]string{resizedRevisionAnnotation: revision}
+	return pod
+}
+
 func Test_podsToUpgrade(t *testing.T) {
 	type args struct {
 		pods         []client.Object
@@ -133,6 +138,23 @@ func Test_podsToUpgrade(t *testing.T) {
 			},
 			want: []string{"masters-1"},
 		},
+		{
+			name: "Pods resized in place match the revision they were resized to",
+			args: args{
+				statefulSets: es_sset.StatefulSetList{
+					sset.TestSset{
+						Name: "masters", Namespace: TestEsNamespace, Replicas: 3, Master: true,
+						Status: appsv1.StatefulSetStatus{CurrentRevision: "rev-a", UpdateRevision: "rev-b", UpdatedReplicas: 0, Replicas: 3},
+					}.Build(),
+				},
+				pods: []client.Object{
+					resizedPod(podWithRevision("masters-0", "rev-a"), "rev-b"),
+					resizedPod(podWithRevision("masters-1", "rev-b"), "rev-c"),
+					podWithRevision("masters-2", "rev-a"),
+				},
+			},
+			want: []string{"masters-1", "masters-2"},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -467,6 +489,27 @@ func Test_defaultDriver_maybeCompleteNodeUpgrades(t *testing.T) {
 				require.False(t, reconciled)
 			},
 		},
+		{
+			name: "expectations satisfied: ephemeral node restarted with a new node ID",
+			es:   es,
+			nodesInCluster: map[string]esclient.Node{
+				"node-id-0": {Name: "es-0"},
+			},
+			shutdowns: map[string]esclient.NodeShutdown{
+				"node-id-98": {
+					NodeID: "node-id-98",
+					Type:   "REMOVE",
+					Status: "COMPLETE",
+				},
+			},
+			runtimeObjects: append(testSset.Pods(), testSset.BuildPtr()),
+			assertions: func(results *reconciler.Results, esClient *fakeESClient) {
+				require.True(t, esClient.DeleteShutdownCalled)
+				require.False(t, esClient.EnableShardAllocationCalled)
+				reconciled, _ := results.IsReconciled()
+				require.False(t, reconciled)
+			},
+		},
 		{
 			name: "not all nodes in cluster, routing disabled, left over shutdown: no calls",
 			es:   es,
@@ -570,7 +613,8 @@ func Test_defaultDriver_maybeCompleteNodeUpgrades(t *testing.T) {
 			require.NoError(t, err)
 
 			n := shutdown.NewNodeShutdown(esClient, nodeNameToID, esclient.Restart, "", crlog.Log)
-			results := d.maybeCompleteNodeUpgrades(context.Background(), esClient, esState, n)
+			e := shutdown.NewNodeShutdown(esClient, nodeNameToID, esclient.Remove, "", crlog.Log)
+			results := d.maybeCompleteNodeUpgrades(context.Background(), esClient, esState, n, e)
 			tt.assertions(results, esClient)
 		})
 	}
@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 
+	"golang.org/x/sync/errgroup"
 	appsv1 "k8s.io/api/apps/v1"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
@@ -21,6 +22,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/version/zen1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/version/zen2"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
 )
 
 type upscaleCtx struct {
@@ -64,15 +66,10 @@ func HandleUpscaleAndSpecChanges(
 	if err != nil {
 		return results, fmt.Errorf("adjust resources: %w", err)
 	}
-	// reconcile all resources
+	// Volume expansion may update the Elasticsearch resource to schedule StatefulSets recreation: handle it sequentially
+	// before reconciling the resources of the different nodeSets concurrently.
+	toRecreate := set.Make()
 	for _, res := range adjusted {
-		res := res
-		if err := settings.ReconcileConfig(ctx.parentCtx, ctx.k8sClient, ctx.es, res.StatefulSet.Name, res.Config); err != nil {
-			return results, fmt.Errorf("reconcile config: %w", err)
-		}
-		if _, err := common.ReconcileService(ctx.parentCtx, ctx.k8sClient, &res.HeadlessService, &ctx.es); err != nil {
-			return results, fmt.Errorf("reconcile service: %w", err)
-		}
 		if actualSset, exists := actualStatefulSets.GetByName(res.StatefulSet.Name); exists {
 			recreateSset, err := handleVolumeExpansion(ctx.parentCtx, ctx.k8sClient, ctx.es, res.StatefulSet, actualSset, ctx.validateStorageClass)
 			if err != nil {
@@ -81,15 +78,46 @@ func HandleUpscaleAndSpecChanges(
 			if recreateSset {
 				// The StatefulSet is scheduled for recreation: let's requeue before attempting any further spec change.
 				results.Requeue = true
-				continue
+				toRecreate.Add(res.StatefulSet.Name)
 			}
 		}
-		reconciled, err := es_sset.ReconcileStatefulSet(ctx.parentCtx, ctx.k8sClient, ctx.es, res.StatefulSet, ctx.expectations)
-		if err != nil {
-			return results, fmt.Errorf("reconcile StatefulSet: %w", err)
+	}
+
+	// reconcile all resources
+	reconciled := make([]*appsv1.StatefulSet, len(adjusted))
+	group, groupCtx := errgroup.WithContext(ctx.parentCtx)
+	group.SetLimit(es_sset.MaxConcurrentReconciles)
+	for i, res := range adjusted {
+		group.Go(func() error {
+			// don't reconcile the resources of the remaining nodeSets if another one failed or the reconciliation was cancelled
+			if err := groupCtx.Err(); err != nil {
+				return err
+			}
+			if err := settings.ReconcileConfig(groupCtx, ctx.k8sClient, ctx.es, res.StatefulSet.Name, res.Config); err != nil {
+				return fmt.Errorf("reconcile config: %w", err)
+			}
+			if _, err := common.ReconcileService(groupCtx, ctx.k8sClient, &res.HeadlessService, &ctx.es); err != nil {
+				return fmt.Errorf("reconcile service: %w", err)
+			}
+			if toRecreate.Has(res.StatefulSet.Name) {
+				return nil
+			}
+			reconciledSset, err := es_sset.ReconcileStatefulSet(groupCtx, ctx.k8sClient, ctx.es, res.StatefulSet, ctx.expectations)
+			if err != nil {
+				return fmt.Errorf("reconcile StatefulSet: %w", err)
+			}
+			reconciled[i] = &reconciledSset
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return results, err
+	}
+	// update actual with the reconciled ones for next steps to work with up-to-date information
+	for _, reconciledSset := range reconciled {
+		if reconciledSset != nil {
+			actualStatefulSets = actualStatefulSets.WithStatefulSet(*reconciledSset)
 		}
-		// update actual with the reconciled ones for next steps to work with up-to-date information
-		actualStatefulSets = actualStatefulSets.WithStatefulSet(reconciled)
 	}
 	results.ActualStatefulSets = actualStatefulSets
 	return results, nil
@@ -6,6 +6,8 @@ package driver
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"reflect"
 	"sort"
 	"sync"
@@ -16,6 +18,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -223,6 +226,89 @@ func TestHandleUpscaleAndSpecChanges(t *testing.T) {
 	comparison.RequireEqual(t, &res.ActualStatefulSets[1], &sset2)
 }
 
+// failingServiceClient fails the creation of the given Service.
+type failingServiceClient struct {
+	k8s.Client
+	service string
+}
+
+func (c failingServiceClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if _, isService := obj.(*corev1.Service); isService && obj.GetName() == c.service {
+		return errors.New("service creation failure")
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func upscaleTestResources(count int) nodespec.ResourcesList {
+	resources := make(nodespec.ResourcesList, 0, count)
+	for i := 0; i < count; i++ {
+		name := fmt.Sprintf("sset%d", i)
+		res := nodespec.Resources{
+			StatefulSet: appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name},
+				Spec: appsv1.StatefulSetSpec{
+					Replicas: ptr.To[int32](1),
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: map[string]string{string(label.NodeTypesMasterLabelName): "true"},
+						},
+					},
+				},
+			},
+			HeadlessService: corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: nodespec.HeadlessServiceName(name)}},
+		}
+		res.StatefulSet.Labels = hash.SetTemplateHashLabel(nil, res.StatefulSet.Spec)
+		resources = append(resources, res)
+	}
+	return resources
+}
+
+func TestHandleUpscaleAndSpecChanges_Concurrency(t *testing.T) {
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec:       esv1.ElasticsearchSpec{Version: "7.5.0"},
+	}
+	newCtx := func(c k8s.Client, parentCtx context.Context) upscaleCtx {
+		return upscaleCtx{
+			k8sClient:    c,
+			es:           es,
+			expectations: expectations.NewExpectations(c),
+			parentCtx:    parentCtx,
+		}
+	}
+	expectedResources := upscaleTestResources(10)
+
+	t.Run("StatefulSets are returned in the order of the expected resources", func(t *testing.T) {
+		k8sClient := k8s.NewFakeClient(&es)
+		res, err := HandleUpscaleAndSpecChanges(newCtx(k8sClient, context.Background()), es_sset.StatefulSetList{}, expectedResources)
+		require.NoError(t, err)
+		require.Len(t, res.ActualStatefulSets, len(expectedResources))
+		for i, actual := range res.ActualStatefulSets {
+			require.Equal(t, expectedResources[i].StatefulSet.Name, actual.Name)
+		}
+	})
+
+	t.Run("the failure of a nodeSet is returned", func(t *testing.T) {
+		k8sClient := failingServiceClient{Client: k8s.NewFakeClient(&es), service: nodespec.HeadlessServiceName("sset5")}
+		_, err := HandleUpscaleAndSpecChanges(newCtx(k8sClient, context.Background()), es_sset.StatefulSetList{}, expectedResources)
+		require.ErrorContains(t, err, "reconcile service: service creation failure")
+		// the StatefulSet of the failed nodeSet is not created
+		err = k8sClient.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "sset5"}, &appsv1.StatefulSet{})
+		require.True(t, apierrors.IsNotFound(err))
+	})
+
+	t.Run("nothing is reconciled once the reconciliation is cancelled", func(t *testing.T) {
+		k8sClient := k8s.NewFakeClient(&es)
+		parentCtx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := HandleUpscaleAndSpecChanges(newCtx(k8sClient, parentCtx), es_sset.StatefulSetList{}, expectedResources)
+		require.ErrorIs(t, err, context.Canceled)
+		var ssets appsv1.StatefulSetList
+		require.NoError(t, k8sClient.List(context.Background(), &ssets))
+		require.Empty(t, ssets.Items)
+	})
+}
+
 func TestHandleUpscaleAndSpecChanges_PVCResize(t *testing.T) {
 	// focus on the special case of handling PVC resize
 	es := esv1.Elasticsearch{
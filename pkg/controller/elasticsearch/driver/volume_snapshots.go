@@ -0,0 +1,178 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+const (
+	// VolumeSnapshotSetLabelName identifies the set of VolumeSnapshots a VolumeSnapshot belongs to. The VolumeSnapshots
+	// taken before a change of the cluster form a set, named after the generation of the cluster specification.
+	VolumeSnapshotSetLabelName = "elasticsearch.k8s.elastic.co/volume-snapshot-set"
+	// VolumeSnapshotPVCLabelName is the name of the PersistentVolumeClaim a VolumeSnapshot was taken from.
+	VolumeSnapshotPVCLabelName = "elasticsearch.k8s.elastic.co/volume-snapshot-pvc"
+)
+
+var (
+	// VolumeSnapshotGVK is the GroupVersionKind of the CSI VolumeSnapshot resource. The external snapshotter API is not
+	// vendored, VolumeSnapshots are handled as unstructured objects.
+	VolumeSnapshotGVK     = schema.GroupVersionKind{Group: "snapshot.storage.k8s.io", Version: "v1", Kind: "VolumeSnapshot"}
+	volumeSnapshotListGVK = VolumeSnapshotGVK.GroupVersion().WithKind("VolumeSnapshotList")
+)
+
+// reconcileVolumeSnapshots takes a VolumeSnapshot of each PersistentVolumeClaim of the cluster before the nodes are
+// restarted to apply a change, if enabled. A set of VolumeSnapshots is taken once per generation of the cluster
+// specification. It returns true once all the VolumeSnapshots of the set are ready to use, after deleting the sets
+// exceeding the retention.
+func (d *defaultDriver) reconcileVolumeSnapshots(ctx context.Context) (bool, error) {
+	policy := d.ES.Spec.VolumeSnapshots
+	if policy == nil {
+		return true, nil
+	}
+	set := strconv.FormatInt(d.ES.Generation, 10)
+
+	snapshots, err := listVolumeSnapshots(ctx, d.Client, d.ES)
+	if err != nil {
+		return false, err
+	}
+	inSet := map[string]unstructured.Unstructured{}
+	for _, snapshot := range snapshots {
+		if snapshot.GetLabels()[VolumeSnapshotSetLabelName] == set {
+			inSet[snapshot.GetLabels()[VolumeSnapshotPVCLabelName]] = snapshot
+		}
+	}
+
+	var pvcs corev1.PersistentVolumeClaimList
+	if err := d.Client.List(ctx, &pvcs, client.InNamespace(d.ES.Namespace), label.NewLabelSelectorForElasticsearch(d.ES)); err != nil {
+		return false, err
+	}
+	var created []string
+	for _, pvc := range pvcs.Items {
+		if pvc.Status.Phase != corev1.ClaimBound {
+			continue
+		}
+		if _, exists := inSet[pvc.Name]; exists {
+			continue
+		}
+		snapshot := newVolumeSnapshot(d.ES, pvc.Name, set, policy.VolumeSnapshotClassName)
+		if err := d.Client.Create(ctx, snapshot); err != nil && !apierrors.IsAlreadyExists(err) {
+			return false, err
+		}
+		created = append(created, snapshot.GetName())
+	}
+	if len(created) > 0 {
+		ulog.FromContext(ctx).Info("Created VolumeSnapshots before restarting nodes",
+			"namespace", d.ES.Namespace, "es_name", d.ES.Name, "volume_snapshots", created)
+		d.ReconcileState.AddEvent(corev1.EventTypeNormal, events.EventReasonVolumeSnapshotsCreated,
+			fmt.Sprintf("Created %d VolumeSnapshot(s) before restarting nodes", len(created)))
+		return false, nil
+	}
+
+	for _, snapshot := range inSet {
+		ready, failure := volumeSnapshotState(snapshot)
+		if failure != "" {
+			d.ReconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonVolumeSnapshotFailed,
+				fmt.Sprintf("VolumeSnapshot %s failed, delete it to retry: %s", snapshot.GetName(), failure))
+		}
+		if !ready {
+			return false, nil
+		}
+	}
+
+	return true, deleteExpiredVolumeSnapshots(ctx, d.Client, snapshots, set, policy.RetentionOrDefault())
+}
+
+func newVolumeSnapshot(es esv1.Elasticsearch, pvcName, set, className string) *unstructured.Unstructured {
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(VolumeSnapshotGVK)
+	snapshot.SetNamespace(es.Namespace)
+	snapshot.SetName(fmt.Sprintf("%s-%s", pvcName, set))
+	// VolumeSnapshots are not owned by the cluster so that they remain available to restore it once deleted
+	snapshot.SetLabels(map[string]string{
+		label.ClusterNameLabelName: es.Name,
+		VolumeSnapshotSetLabelName: set,
+		VolumeSnapshotPVCLabelName: pvcName,
+	})
+	spec := map[string]interface{}{
+		"source": map[string]interface{}{"persistentVolumeClaimName": pvcName},
+	}
+	if className != "" {
+		spec["volumeSnapshotClassName"] = className
+	}
+	snapshot.Object["spec"] = spec
+	return snapshot
+}
+
+// listVolumeSnapshots returns the VolumeSnapshots taken by the operator for the given cluster.
+func listVolumeSnapshots(ctx context.Context, c k8s.Client, es esv1.Elasticsearch) ([]unstructured.Unstructured, error) {
+	snapshots := &unstructured.UnstructuredList{}
+	snapshots.SetGroupVersionKind(volumeSnapshotListGVK)
+	err := c.List(ctx, snapshots, client.InNamespace(es.Namespace), label.NewLabelSelectorForElasticsearch(es), client.HasLabels{VolumeSnapshotSetLabelName})
+	if meta.IsNoMatchError(err) {
+		return nil, fmt.Errorf("cannot snapshot the volumes of Elasticsearch %s/%s, the VolumeSnapshot CRDs are not installed: %w", es.Namespace, es.Name, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return snapshots.Items, nil
+}
+
+// volumeSnapshotState returns whether the given VolumeSnapshot is ready to use, and the error reported by the CSI
+// driver if any.
+func volumeSnapshotState(snapshot unstructured.Unstructured) (bool, string) {
+	ready, _, _ := unstructured.NestedBool(snapshot.Object, "status", "readyToUse")
+	failure, _, _ := unstructured.NestedString(snapshot.Object, "status", "error", "message")
+	return ready, failure
+}
+
+// deleteExpiredVolumeSnapshots deletes the VolumeSnapshots of the oldest sets, to only keep the given number of sets
+// including the current one.
+func deleteExpiredVolumeSnapshots(ctx context.Context, c k8s.Client, snapshots []unstructured.Unstructured, current string, retention int) error {
+	var sets []int64
+	bySet := map[int64][]unstructured.Unstructured{}
+	for _, snapshot := range snapshots {
+		set, err := strconv.ParseInt(snapshot.GetLabels()[VolumeSnapshotSetLabelName], 10, 64)
+		if err != nil || snapshot.GetLabels()[VolumeSnapshotSetLabelName] == current {
+			// ignore sets not named by the operator, the current set is never deleted
+			continue
+		}
+		if _, exists := bySet[set]; !exists {
+			sets = append(sets, set)
+		}
+		bySet[set] = append(bySet[set], snapshot)
+	}
+	// most recent sets first
+	sort.Slice(sets, func(i, j int) bool { return sets[i] > sets[j] })
+	if len(sets) < retention {
+		return nil
+	}
+	for _, set := range sets[retention-1:] {
+		for i := range bySet[set] {
+			ulog.FromContext(ctx).Info("Deleting expired VolumeSnapshot",
+				"namespace", bySet[set][i].GetNamespace(), "volume_snapshot", bySet[set][i].GetName())
+			if err := c.Delete(ctx, &bySet[set][i]); err != nil && !apierrors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func volumeSnapshotNames(t *testing.T, c k8s.Client) []string {
+	t.Helper()
+	snapshots := &unstructured.UnstructuredList{}
+	snapshots.SetGroupVersionKind(volumeSnapshotListGVK)
+	require.NoError(t, c.List(context.Background(), snapshots))
+	names := make([]string, 0, len(snapshots.Items))
+	for _, snapshot := range snapshots.Items {
+		names = append(names, snapshot.GetName())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func Test_defaultDriver_reconcileVolumeSnapshots(t *testing.T) {
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es", Generation: 7},
+		Spec: esv1.ElasticsearchSpec{
+			VolumeSnapshots: &esv1.VolumeSnapshotPolicy{VolumeSnapshotClassName: "csi-snapclass", Retention: ptr.To[int32](2)},
+		},
+	}
+	pvc := func(name string, phase corev1.PersistentVolumeClaimPhase) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, Labels: map[string]string{label.ClusterNameLabelName: "es"}},
+			Status:     corev1.PersistentVolumeClaimStatus{Phase: phase},
+		}
+	}
+	// sets of previous changes, and a VolumeSnapshot of another cluster
+	oldSnapshot := func(cluster, set string) client.Object {
+		return newVolumeSnapshot(esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: cluster}}, "elasticsearch-data-es-default-0", set, "")
+	}
+	c := k8s.NewFakeClient(
+		pvc("elasticsearch-data-es-default-0", corev1.ClaimBound),
+		pvc("elasticsearch-data-es-default-1", corev1.ClaimBound),
+		pvc("elasticsearch-data-es-default-2", corev1.ClaimPending),
+		oldSnapshot("es", "3"),
+		oldSnapshot("es", "5"),
+		oldSnapshot("other", "1"),
+	)
+	d := &defaultDriver{DefaultDriverParameters: DefaultDriverParameters{
+		Client:         c,
+		ES:             es,
+		ReconcileState: reconcile.MustNewState(es),
+	}}
+
+	// the bound volumes are snapshotted
+	ready, err := d.reconcileVolumeSnapshots(context.Background())
+	require.NoError(t, err)
+	require.False(t, ready)
+	require.Equal(t, []string{
+		"elasticsearch-data-es-default-0-1",
+		"elasticsearch-data-es-default-0-3",
+		"elasticsearch-data-es-default-0-5",
+		"elasticsearch-data-es-default-0-7",
+		"elasticsearch-data-es-default-1-7",
+	}, volumeSnapshotNames(t, c))
+	require.Contains(t, d.ReconcileState.Events(), events.Event{
+		EventType: corev1.EventTypeNormal,
+		Reason:    events.EventReasonVolumeSnapshotsCreated,
+		Message:   "Created 2 VolumeSnapshot(s) before restarting nodes",
+	})
+	snapshot := newVolumeSnapshot(es, "elasticsearch-data-es-default-0", "7", "")
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(snapshot), snapshot))
+	className, _, _ := unstructured.NestedString(snapshot.Object, "spec", "volumeSnapshotClassName")
+	require.Equal(t, "csi-snapclass", className)
+
+	// nodes are not restarted until the VolumeSnapshots are ready to use
+	ready, err = d.reconcileVolumeSnapshots(context.Background())
+	require.NoError(t, err)
+	require.False(t, ready)
+
+	for _, pvcName := range []string{"elasticsearch-data-es-default-0", "elasticsearch-data-es-default-1"} {
+		snapshot := newVolumeSnapshot(es, pvcName, "7", "")
+		require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(snapshot), snapshot))
+		require.NoError(t, unstructured.SetNestedField(snapshot.Object, true, "status", "readyToUse"))
+		require.NoError(t, c.Status().Update(context.Background(), snapshot))
+	}
+
+	// the oldest set of the cluster is deleted once the new set is ready
+	ready, err = d.reconcileVolumeSnapshots(context.Background())
+	require.NoError(t, err)
+	require.True(t, ready)
+	require.Equal(t, []string{
+		"elasticsearch-data-es-default-0-1",
+		"elasticsearch-data-es-default-0-5",
+		"elasticsearch-data-es-default-0-7",
+		"elasticsearch-data-es-default-1-7",
+	}, volumeSnapshotNames(t, c))
+}
+
+func Test_defaultDriver_reconcileVolumeSnapshots_disabled(t *testing.T) {
+	d := &defaultDriver{DefaultDriverParameters: DefaultDriverParameters{
+		Client: k8s.NewFakeClient(),
+		ES:     esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}},
+	}}
+	ready, err := d.reconcileVolumeSnapshots(context.Background())
+	require.NoError(t, err)
+	require.True(t, ready)
+}
+
+func Test_volumeSnapshotState(t *testing.T) {
+	snapshot := unstructured.Unstructured{Object: map[string]interface{}{}}
+	ready, failure := volumeSnapshotState(snapshot)
+	require.False(t, ready)
+	require.Empty(t, failure)
+
+	snapshot.Object["status"] = map[string]interface{}{
+		"readyToUse": false,
+		"error":      map[string]interface{}{"message": "quota exceeded"},
+	}
+	ready, failure = volumeSnapshotState(snapshot)
+	require.False(t, ready)
+	require.Equal(t, "quota exceeded", failure)
+}
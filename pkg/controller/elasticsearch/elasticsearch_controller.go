@@ -13,17 +13,23 @@ import (
 	"go.elastic.co/apm/v2"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	secv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/security/v1alpha1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
@@ -39,14 +45,18 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/certificates/transport"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/driver"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	eslicense "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/license"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/observer"
 	esreconcile "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/remotecluster"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/snapshotrepository"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/user"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/validation"
 	esversion "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
 )
 
 const name = "elasticsearch-controller"
@@ -75,6 +85,8 @@ func newReconciler(mgr manager.Manager, params operator.Parameters) *ReconcileEl
 		dynamicWatches: watches.NewDynamicWatches(),
 		expectations:   expectations.NewClustersExpectations(client),
 
+		budgetCheckpoints: reconciler.NewBudgetCheckpoints(),
+
 		Parameters: params,
 	}
 }
@@ -103,6 +115,60 @@ func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileElasti
 		return err
 	}
 
+	// Watch NetworkPolicies
+	if err := c.Watch(
+		source.Kind(mgr.GetCache(), &networkingv1.NetworkPolicy{}, handler.TypedEnqueueRequestForOwner[*networkingv1.NetworkPolicy](mgr.GetScheme(), mgr.GetRESTMapper(), &esv1.Elasticsearch{}, handler.OnlyControllerOwner()))); err != nil {
+		return err
+	}
+
+	// Watch Kibana resources declaring remote clusters, configured in the Elasticsearch cluster Kibana is associated with
+	if err := c.Watch(
+		source.Kind(mgr.GetCache(), &kbv1.Kibana{}, remotecluster.KibanaEventHandler(remotecluster.PrimaryElasticsearchRefForKibana),
+			predicate.TypedGenerationChangedPredicate[*kbv1.Kibana]{},
+		)); err != nil {
+		return err
+	}
+
+	// Watch ElasticsearchUser and ElasticsearchRole resources, aggregated in the file realm of the cluster they reference
+	if err := c.Watch(
+		source.Kind(mgr.GetCache(), &secv1alpha1.ElasticsearchUser{}, handler.TypedEnqueueRequestsFromMapFunc[*secv1alpha1.ElasticsearchUser, reconcile.Request](
+			func(_ context.Context, u *secv1alpha1.ElasticsearchUser) []reconcile.Request {
+				return []reconcile.Request{{NamespacedName: u.Spec.ElasticsearchRef.NamespacedName(u.Namespace)}}
+			}),
+			predicate.TypedGenerationChangedPredicate[*secv1alpha1.ElasticsearchUser]{},
+		)); err != nil {
+		return err
+	}
+	if err := c.Watch(
+		source.Kind(mgr.GetCache(), &secv1alpha1.ElasticsearchRole{}, handler.TypedEnqueueRequestsFromMapFunc[*secv1alpha1.ElasticsearchRole, reconcile.Request](
+			func(_ context.Context, r *secv1alpha1.ElasticsearchRole) []reconcile.Request {
+				return []reconcile.Request{{NamespacedName: r.Spec.ElasticsearchRef.NamespacedName(r.Namespace)}}
+			}),
+			predicate.TypedGenerationChangedPredicate[*secv1alpha1.ElasticsearchRole]{},
+		)); err != nil {
+		return err
+	}
+
+	// Watch Kubernetes nodes being cordoned or uncordoned, to relocate the data of the Elasticsearch Pods they host
+	if r.OrchestrateNodeDrains {
+		if err := c.Watch(
+			source.Kind(mgr.GetCache(), &corev1.Node{}, handler.TypedEnqueueRequestsFromMapFunc[*corev1.Node, reconcile.Request](
+				func(ctx context.Context, node *corev1.Node) []reconcile.Request {
+					return clustersOnNode(ctx, r.Client, node.Name)
+				}),
+				predicate.TypedFuncs[*corev1.Node]{
+					CreateFunc: func(event.TypedCreateEvent[*corev1.Node]) bool { return false },
+					UpdateFunc: func(e event.TypedUpdateEvent[*corev1.Node]) bool {
+						return e.ObjectOld.Spec.Unschedulable != e.ObjectNew.Spec.Unschedulable
+					},
+					DeleteFunc:  func(event.TypedDeleteEvent[*corev1.Node]) bool { return false },
+					GenericFunc: func(event.TypedGenericEvent[*corev1.Node]) bool { return false },
+				},
+			)); err != nil {
+			return err
+		}
+	}
+
 	// Watch config maps for dynamic watches (currently used for additional CAs trust)
 	if err := c.Watch(source.Kind(mgr.GetCache(), &corev1.ConfigMap{}, r.dynamicWatches.ConfigMaps)); err != nil {
 		return err
@@ -129,6 +195,27 @@ func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileElasti
 	return c.Watch(observer.WatchClusterHealthChange(r.esObservers))
 }
 
+// clustersOnNode returns the reconcile requests of the Elasticsearch clusters with Pods scheduled on the given Kubernetes node.
+func clustersOnNode(ctx context.Context, c k8s.Client, nodeName string) []reconcile.Request {
+	var pods corev1.PodList
+	if err := c.List(ctx, &pods, client.HasLabels{label.ClusterNameLabelName}); err != nil {
+		ulog.FromContext(ctx).Error(err, "Failed to list the Elasticsearch Pods", "node", nodeName)
+		return nil
+	}
+	clusters := map[types.NamespacedName]struct{}{}
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName != nodeName {
+			continue
+		}
+		clusters[types.NamespacedName{Namespace: pod.Namespace, Name: pod.Labels[label.ClusterNameLabelName]}] = struct{}{}
+	}
+	requests := make([]reconcile.Request, 0, len(clusters))
+	for cluster := range clusters {
+		requests = append(requests, reconcile.Request{NamespacedName: cluster})
+	}
+	return requests
+}
+
 var _ reconcile.Reconciler = &ReconcileElasticsearch{}
 
 // ReconcileElasticsearch reconciles an Elasticsearch object
@@ -146,6 +233,10 @@ type ReconcileElasticsearch struct {
 	// by marking resources updates as expected, and skipping some operations if the cache is not up-to-date.
 	expectations *expectations.ClustersExpectation
 
+	// budgetCheckpoints tracks where the reconciliations which exhausted their time budget yielded, so that the next
+	// reconciliations go further.
+	budgetCheckpoints *reconciler.BudgetCheckpoints
+
 	// iteration is the number of times this controller has run its Reconcile method
 	iteration uint64
 }
@@ -165,9 +256,17 @@ func (r *ReconcileElasticsearch) Reconcile(ctx context.Context, request reconcil
 		return reconcile.Result{}, tracing.CaptureError(ctx, err)
 	}
 
+	// bound the time spent on this resource so that large clusters cannot starve the other ones
+	budget := r.budgetCheckpoints.NewBudget(r.ElasticsearchReconcileBudget, request.NamespacedName, es.Generation)
+	defer budget.Done()
+	ctx = reconciler.WithBudget(ctx, budget)
+
 	if common.IsUnmanaged(ctx, &es) {
 		log.Info("Object is currently not managed by this controller. Skipping reconciliation", "namespace", es.Namespace, "es_name", es.Name)
-		return reconcile.Result{}, nil
+		return reconcile.Result{}, tracing.CaptureError(ctx, common.ReconcilePausedCondition(ctx, r.Client, &es, &es.Status.Conditions, true))
+	}
+	if err := common.ReconcilePausedCondition(ctx, r.Client, &es, &es.Status.Conditions, false); err != nil {
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
 	}
 
 	// Remove any previous Finalizers
@@ -180,9 +279,6 @@ func (r *ReconcileElasticsearch) Reconcile(ctx context.Context, request reconcil
 		return reconcile.Result{}, tracing.CaptureError(ctx, err)
 	}
 
-	// ReconciliationComplete is initially set to True until another condition with the same type is reported.
-	state.ReportCondition(esv1.ReconciliationComplete, corev1.ConditionTrue, "")
-
 	results := r.internalReconcile(ctx, es, state)
 
 	// Update orchestration related annotations
@@ -197,12 +293,13 @@ func (r *ReconcileElasticsearch) Reconcile(ctx context.Context, request reconcil
 		}
 	}
 
-	if isReconciled, message := results.IsReconciled(); !isReconciled {
+	isReconciled, message := results.IsReconciled()
+	if !isReconciled {
 		state.UpdateWithPhase(esv1.ElasticsearchApplyingChangesPhase)
-		state.ReportCondition(esv1.ReconciliationComplete, corev1.ConditionFalse, message)
 	} else {
 		state.UpdateWithPhase(esv1.ElasticsearchReadyPhase)
 	}
+	state.UpdateStandardConditions(isReconciled, message)
 
 	// Last step of the reconciliation loop is always to update the Elasticsearch resource status.
 	err = r.updateStatus(ctx, es, state)
@@ -264,6 +361,19 @@ func (r *ReconcileElasticsearch) internalReconcile(
 		return results
 	}
 
+	if r.NamespaceQuota.IsEnabled() {
+		// quotas are enforced by the webhook, only report whether the namespace is within its quota here
+		quotaErrs, err := validation.CheckNamespaceQuota(ctx, r.Client, r.NamespaceQuota, es, nil)
+		if err != nil {
+			return results.WithError(err)
+		}
+		if len(quotaErrs) > 0 {
+			reconcileState.ReportCondition(esv1.WithinNamespaceQuota, corev1.ConditionFalse, quotaErrs.ToAggregate().Error())
+		} else {
+			reconcileState.ReportCondition(esv1.WithinNamespaceQuota, corev1.ConditionTrue, "")
+		}
+	}
+
 	err = validation.CheckForWarnings(es)
 	if err != nil {
 		log.Info(
@@ -312,8 +422,10 @@ func (r *ReconcileElasticsearch) updateStatus(
 		r.recorder.Event(&es, evt.EventType, evt.Reason, evt.Message)
 	}
 	if cluster == nil {
+		updateUpgradeMetric(es)
 		return nil
 	}
+	updateUpgradeMetric(*cluster)
 	log.V(1).Info("Updating status",
 		"iteration", atomic.LoadUint64(&r.iteration),
 		"namespace", es.Namespace,
@@ -323,6 +435,15 @@ func (r *ReconcileElasticsearch) updateStatus(
 	return common.UpdateStatus(ctx, r.Client, cluster)
 }
 
+// updateUpgradeMetric reports whether nodes of the cluster are being upgraded according to its status.
+func updateUpgradeMetric(es esv1.Elasticsearch) {
+	value := 0.0
+	if len(es.Status.InProgressOperations.UpgradeOperation.Nodes) > 0 {
+		value = 1
+	}
+	metrics.UpgradeInProgressGauge.WithLabelValues(es.Namespace, es.Name, esv1.Kind).Set(value)
+}
+
 // annotateResource adds the orchestration hints annotation to the Elasticsearch resource. The purpose of this annotation
 // is to capture additional state about aspects of the operator's orchestration of Elasticsearch resources. Currently,
 // it captures whether transient settings are in use.  Future expansion is possible if deemed necessary.
@@ -354,13 +475,18 @@ func (r *ReconcileElasticsearch) annotateResource(
 
 // onDelete garbage collect resources when an Elasticsearch cluster is deleted
 func (r *ReconcileElasticsearch) onDelete(ctx context.Context, es types.NamespacedName) error {
+	metrics.DeleteResourceMetrics(ctx, esv1.Kind, es)
 	r.expectations.RemoveCluster(es)
+	r.budgetCheckpoints.Forget(es)
 	r.esObservers.StopObserving(es)
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(keystore.SecureSettingsWatchName(es))
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(certificates.CertificateWatchKey(esv1.ESNamer, es.Name))
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(transport.CustomTransportCertsWatchKey(es))
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(user.UserProvidedRolesWatchName(es))
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(user.UserProvidedFileRealmWatchName(es))
+	r.dynamicWatches.Secrets.RemoveHandlerForKey(user.UserResourcesPasswordsWatchName(es))
 	r.dynamicWatches.ConfigMaps.RemoveHandlerForKey(transport.AdditionalCAWatchKey(es))
+	snapshotrepository.DeleteVerificationMetrics(es)
+	eslicense.DeleteMetrics(es)
 	return reconciler.GarbageCollectSoftOwnedSecrets(ctx, r.Client, es, esv1.Kind)
 }
@@ -5,8 +5,11 @@ package elasticsearch
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
@@ -18,6 +21,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/comparison"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/hints"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 )
 
@@ -99,6 +103,28 @@ var noInProgressOperations = esv1.InProgressOperations{
 	},
 }
 
+// invalidESConditions returns the standard conditions of an invalid cluster which is not running, stalled with the given
+// validation error.
+func invalidESConditions(validationErr string) commonv1alpha1.Conditions {
+	return commonv1alpha1.Conditions{
+		{Type: commonv1alpha1.ReadyCondition, Status: corev1.ConditionFalse, ObservedGeneration: 2, Reason: commonv1alpha1.UnavailableReason, Message: "Health is unknown"},
+		{Type: commonv1alpha1.ReconcilingCondition, Status: corev1.ConditionFalse, ObservedGeneration: 2, Reason: commonv1alpha1.StalledReason},
+		{Type: commonv1alpha1.StalledCondition, Status: corev1.ConditionTrue, ObservedGeneration: 2, Reason: commonv1alpha1.StalledReason, Message: validationErr},
+		{Type: commonv1alpha1.ReconciliationCompleteCondition, Status: corev1.ConditionFalse, ObservedGeneration: 2, Reason: commonv1alpha1.ReconcilingReason, Message: validationErr},
+		{Type: commonv1alpha1.DegradedCondition, Status: corev1.ConditionTrue, ObservedGeneration: 2, Reason: commonv1alpha1.DegradedReason, Message: "Health is unknown"},
+		{Type: commonv1alpha1.UpgradeInProgressCondition, Status: corev1.ConditionUnknown, ObservedGeneration: 2, Reason: commonv1alpha1.VersionUnknownReason, Message: "No running version reported"},
+	}
+}
+
+// tooLongNameErr is the validation error of a cluster with a name too long and no version.
+const tooLongNameErr = `Elasticsearch.elasticsearch.k8s.elastic.co "%s" is invalid: [metadata.name: Invalid value: "%s": ` +
+	`Elasticsearch configuration would generate resources with invalid names: name exceeds maximum allowed length of 36, ` +
+	`spec.version: Invalid value: "": Cannot parse Elasticsearch version. String format must be {major}.{minor}.{patch}[-{label}]]`
+
+// invalidVersionErr is the validation error of the testES cluster with an invalid version.
+const invalidVersionErr = `Elasticsearch.elasticsearch.k8s.elastic.co "testES" is invalid: spec.version: Invalid value: "invalid": ` +
+	`Cannot parse Elasticsearch version. String format must be {major}.{minor}.{patch}[-{label}]`
+
 func TestReconcileElasticsearch_Reconcile(t *testing.T) {
 	type k8sClientFields struct {
 		objects []client.Object
@@ -134,7 +160,10 @@ func TestReconcileElasticsearch_Reconcile(t *testing.T) {
 			expected: newBuilder("testES", "test").
 				WithGeneration(2).
 				WithAnnotations(map[string]string{common.ManagedAnnotation: "false"}).
-				WithStatus(esv1.ElasticsearchStatus{ObservedGeneration: 1}).BuildAndCopy(),
+				WithStatus(esv1.ElasticsearchStatus{
+					ObservedGeneration: 1,
+					Conditions:         commonv1alpha1.Conditions{{Type: commonv1alpha1.PausedCondition, Status: corev1.ConditionTrue, Message: "Reconciliation is suspended by the eck.k8s.elastic.co/managed annotation"}},
+				}).BuildAndCopy(),
 		},
 		{
 			name: "ES with too long name, fails initial reconcile, but has observedGeneration updated",
@@ -163,7 +192,7 @@ func TestReconcileElasticsearch_Reconcile(t *testing.T) {
 						ObservedGeneration:   2,
 						Phase:                esv1.ElasticsearchResourceInvalid,
 						Health:               esv1.ElasticsearchUnknownHealth,
-						Conditions:           commonv1alpha1.Conditions{commonv1alpha1.Condition{Type: "ReconciliationComplete", Status: "True"}},
+						Conditions:           invalidESConditions(fmt.Sprintf(tooLongNameErr, "testESwithtoolongofanamereallylongname", "testESwithtoolongofanamereallylongname")),
 						InProgressOperations: noInProgressOperations,
 					},
 				).BuildAndCopy(),
@@ -195,7 +224,7 @@ func TestReconcileElasticsearch_Reconcile(t *testing.T) {
 						ObservedGeneration:   2,
 						Phase:                esv1.ElasticsearchResourceInvalid,
 						Health:               esv1.ElasticsearchUnknownHealth,
-						Conditions:           commonv1alpha1.Conditions{commonv1alpha1.Condition{Type: "ReconciliationComplete", Status: "True"}},
+						Conditions:           invalidESConditions(fmt.Sprintf(tooLongNameErr, "testeswithtoolongofanamereallylongname", "testeswithtoolongofanamereallylongname")),
 						InProgressOperations: noInProgressOperations,
 					},
 				).BuildAndCopy(),
@@ -228,7 +257,7 @@ func TestReconcileElasticsearch_Reconcile(t *testing.T) {
 						ObservedGeneration:   2,
 						Phase:                esv1.ElasticsearchResourceInvalid,
 						Health:               esv1.ElasticsearchUnknownHealth,
-						Conditions:           commonv1alpha1.Conditions{commonv1alpha1.Condition{Type: "ReconciliationComplete", Status: "True"}},
+						Conditions:           invalidESConditions(invalidVersionErr),
 						InProgressOperations: noInProgressOperations,
 					},
 				).BuildAndCopy(),
@@ -251,3 +280,29 @@ func TestReconcileElasticsearch_Reconcile(t *testing.T) {
 		})
 	}
 }
+
+func Test_clustersOnNode(t *testing.T) {
+	pod := func(namespace, name, cluster, nodeName string) client.Object {
+		p := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec:       corev1.PodSpec{NodeName: nodeName},
+		}
+		if cluster != "" {
+			p.Labels = map[string]string{label.ClusterNameLabelName: cluster}
+		}
+		return p
+	}
+	c := k8s.NewFakeClient(
+		pod("ns1", "es-a-0", "es-a", "node-1"),
+		pod("ns1", "es-a-1", "es-a", "node-1"),
+		pod("ns1", "es-b-0", "es-b", "node-2"),
+		pod("ns2", "es-a-0", "es-a", "node-1"),
+		pod("ns1", "not-es", "", "node-1"),
+	)
+	requests := clustersOnNode(context.Background(), c, "node-1")
+	require.ElementsMatch(t, []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "es-a"}},
+		{NamespacedName: types.NamespacedName{Namespace: "ns2", Name: "es-a"}},
+	}, requests)
+	require.Empty(t, clustersOnNode(context.Background(), c, "node-3"))
+}
@@ -0,0 +1,159 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package ingestautoscaling
+
+import (
+	"context"
+	"math"
+	"slices"
+	"time"
+
+	"go.elastic.co/apm/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
+)
+
+// Enabled returns true if at least one NodeSet of the cluster is configured with ingest autoscaling.
+func Enabled(es esv1.Elasticsearch) bool {
+	return slices.ContainsFunc(es.Spec.NodeSets, func(nodeSet esv1.NodeSet) bool {
+		return nodeSet.IngestAutoscaling != nil
+	})
+}
+
+// ApplyCounts returns a copy of the given cluster in which the count of each NodeSet configured with ingest autoscaling
+// is replaced by the count reported in the status, or by the count of the specification if there is none yet, within
+// the bounds of the autoscaling configuration.
+func ApplyCounts(es esv1.Elasticsearch) esv1.Elasticsearch {
+	if !Enabled(es) {
+		return es
+	}
+	// do not mutate the NodeSets shared with the original cluster
+	es.Spec.NodeSets = slices.Clone(es.Spec.NodeSets)
+	for i, nodeSet := range es.Spec.NodeSets {
+		if nodeSet.IngestAutoscaling == nil {
+			continue
+		}
+		es.Spec.NodeSets[i].Count = currentCount(es, nodeSet)
+	}
+	return es
+}
+
+// currentCount returns the number of nodes of an ingest autoscaled NodeSet.
+func currentCount(es esv1.Elasticsearch, nodeSet esv1.NodeSet) int32 {
+	count := nodeSet.Count
+	if status := es.Status.IngestAutoscalingStatusFor(nodeSet.Name); status != nil {
+		count = status.Count
+	}
+	return nodeSet.IngestAutoscaling.Bound(count)
+}
+
+// Evaluate decides the number of nodes of each NodeSet configured with ingest autoscaling from the write thread pool
+// statistics of their nodes. It returns the status to report, and the duration after which the next evaluation is due.
+// A nil status is returned if ingest autoscaling is not enabled.
+func Evaluate(ctx context.Context, esClient esclient.Client, es esv1.Elasticsearch, now time.Time) ([]esv1.IngestAutoscalingStatus, time.Duration, error) {
+	if !Enabled(es) {
+		return nil, 0, nil
+	}
+
+	span, ctx := apm.StartSpan(ctx, "evaluate_ingest_autoscaling", tracing.SpanTypeApp)
+	defer span.End()
+
+	stats, err := esClient.GetWriteThreadPoolStats(ctx)
+	if err != nil {
+		return es.Status.IngestAutoscaling, 0, err
+	}
+
+	var statuses []esv1.IngestAutoscalingStatus //nolint:prealloc
+	var next time.Duration
+	for _, nodeSet := range es.Spec.NodeSets {
+		if nodeSet.IngestAutoscaling == nil {
+			continue
+		}
+		count := currentCount(es, nodeSet)
+		nodes := nodeSetStats(stats, esv1.StatefulSet(es.Name, nodeSet.Name), count)
+		previous := es.Status.IngestAutoscalingStatusFor(nodeSet.Name)
+		statuses = append(statuses, evaluate(*nodeSet.IngestAutoscaling, nodeSet.Name, count, previous, nodes, now))
+		if interval := nodeSet.IngestAutoscaling.IntervalOrDefault(); next == 0 || interval < next {
+			next = interval
+		}
+	}
+	return statuses, next, nil
+}
+
+// nodeSetStats returns the write thread pool statistics of the nodes of the given StatefulSet expected to be running.
+func nodeSetStats(stats esclient.NodesStats, statefulSetName string, count int32) []esclient.ThreadPoolStats {
+	var nodes []esclient.ThreadPoolStats
+	for _, node := range stats.Nodes {
+		// the node names are the names of the Pods
+		name, ordinal, err := sset.StatefulSetName(node.Name)
+		if err != nil || name != statefulSetName || ordinal >= count {
+			continue
+		}
+		nodes = append(nodes, node.ThreadPool.Write)
+	}
+	return nodes
+}
+
+// evaluate decides the number of nodes of a NodeSet. A node is added if write requests were rejected since the last
+// evaluation, or if the write queues exceed the threshold. A node is removed if no write pressure was observed during the
+// scale down delay. The number of nodes is not changed until all the expected nodes are running.
+func evaluate(
+	spec esv1.IngestAutoscaling,
+	nodeSet string,
+	count int32,
+	previous *esv1.IngestAutoscalingStatus,
+	nodes []esclient.ThreadPoolStats,
+	now time.Time,
+) esv1.IngestAutoscalingStatus {
+	var rejections, queue int64
+	for _, node := range nodes {
+		rejections += node.Rejected
+		queue += node.Queue
+	}
+	status := esv1.IngestAutoscalingStatus{NodeSet: nodeSet, Count: count, WriteRejections: rejections}
+	if previous == nil {
+		// the rejections observed before the first evaluation cannot be attributed to the current load
+		status.LastScaleTime = ptr.To(metav1.NewTime(now))
+		return status
+	}
+	status.LastScaleTime = previous.LastScaleTime
+	status.LastPressureTime = previous.LastPressureTime
+
+	// the rejection counters are reset when the nodes restart
+	newRejections := max(0, rejections-previous.WriteRejections)
+	if newRejections > 0 || queue > 0 {
+		status.LastPressureTime = ptr.To(metav1.NewTime(now))
+	}
+	if len(nodes) < int(count) {
+		// wait for the nodes to run before scaling again
+		return status
+	}
+
+	overloaded := newRejections > 0 || queue > spec.QueueThresholdOrDefault()*int64(len(nodes))
+	switch {
+	case overloaded && count < spec.MaxCount && elapsedSince(status.LastScaleTime, now) >= spec.IntervalOrDefault():
+		status.Count = count + 1
+		status.LastScaleTime = ptr.To(metav1.NewTime(now))
+	case count > spec.MinCount &&
+		elapsedSince(status.LastScaleTime, now) >= spec.ScaleDownDelayOrDefault() &&
+		elapsedSince(status.LastPressureTime, now) >= spec.ScaleDownDelayOrDefault():
+		status.Count = count - 1
+		status.LastScaleTime = ptr.To(metav1.NewTime(now))
+	}
+	return status
+}
+
+// elapsedSince returns the duration elapsed since the given time, or the maximum duration if it is not set.
+func elapsedSince(t *metav1.Time, now time.Time) time.Duration {
+	if t == nil {
+		return time.Duration(math.MaxInt64)
+	}
+	return now.Sub(t.Time)
+}
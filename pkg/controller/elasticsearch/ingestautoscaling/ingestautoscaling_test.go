@@ -0,0 +1,200 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package ingestautoscaling
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+)
+
+type fakeESClient struct {
+	esclient.Client
+	stats esclient.NodesStats
+}
+
+func (f *fakeESClient) GetWriteThreadPoolStats(_ context.Context) (esclient.NodesStats, error) {
+	return f.stats, nil
+}
+
+func nodeStats(name string, queue, rejected int64) esclient.NodeStats {
+	stats := esclient.NodeStats{Name: name}
+	stats.ThreadPool.Write = esclient.ThreadPoolStats{Queue: queue, Rejected: rejected}
+	return stats
+}
+
+func newES(count int32, statuses ...esv1.IngestAutoscalingStatus) esv1.Elasticsearch {
+	return esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec: esv1.ElasticsearchSpec{NodeSets: []esv1.NodeSet{
+			{Name: "master", Count: 3},
+			{Name: "ingest", Count: count, IngestAutoscaling: &esv1.IngestAutoscaling{MinCount: 1, MaxCount: 3}},
+		}},
+		Status: esv1.ElasticsearchStatus{IngestAutoscaling: statuses},
+	}
+}
+
+func TestApplyCounts(t *testing.T) {
+	// the spec count is used within the bounds if there is no status yet
+	es := newES(5)
+	applied := ApplyCounts(es)
+	require.Equal(t, int32(3), applied.Spec.NodeSets[0].Count)
+	require.Equal(t, int32(3), applied.Spec.NodeSets[1].Count)
+	// the original NodeSets are not mutated
+	require.Equal(t, int32(5), es.Spec.NodeSets[1].Count)
+
+	// the status count takes precedence over the spec count
+	applied = ApplyCounts(newES(1, esv1.IngestAutoscalingStatus{NodeSet: "ingest", Count: 2}))
+	require.Equal(t, int32(2), applied.Spec.NodeSets[1].Count)
+}
+
+func TestEvaluate(t *testing.T) {
+	now := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	esClient := &fakeESClient{stats: esclient.NodesStats{Nodes: map[string]esclient.NodeStats{
+		"a": nodeStats("es-es-master-0", 500, 10),
+		"b": nodeStats("es-es-ingest-0", 0, 7),
+		"c": nodeStats("es-es-ingest-1", 0, 3),
+	}}}
+
+	// disabled
+	statuses, next, err := Evaluate(context.Background(), esClient, esv1.Elasticsearch{}, now)
+	require.NoError(t, err)
+	require.Nil(t, statuses)
+	require.Zero(t, next)
+
+	// the first evaluation records the rejections of the nodes of the NodeSet
+	statuses, next, err = Evaluate(context.Background(), esClient, newES(2), now)
+	require.NoError(t, err)
+	require.Equal(t, time.Minute, next)
+	require.Equal(t, []esv1.IngestAutoscalingStatus{
+		{NodeSet: "ingest", Count: 2, WriteRejections: 10, LastScaleTime: ptr.To(metav1.NewTime(now))},
+	}, statuses)
+
+	// new rejections add a node
+	previous := esv1.IngestAutoscalingStatus{NodeSet: "ingest", Count: 2, WriteRejections: 8, LastScaleTime: ptr.To(metav1.NewTime(now.Add(-time.Hour)))}
+	statuses, _, err = Evaluate(context.Background(), esClient, newES(2, previous), now)
+	require.NoError(t, err)
+	require.Equal(t, []esv1.IngestAutoscalingStatus{
+		{NodeSet: "ingest", Count: 3, WriteRejections: 10, LastScaleTime: ptr.To(metav1.NewTime(now)), LastPressureTime: ptr.To(metav1.NewTime(now))},
+	}, statuses)
+}
+
+func Test_evaluate(t *testing.T) {
+	now := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) *metav1.Time {
+		return ptr.To(metav1.NewTime(now.Add(-d)))
+	}
+	spec := esv1.IngestAutoscaling{MinCount: 1, MaxCount: 3, QueueThreshold: ptr.To[int32](10)}
+	tests := []struct {
+		name      string
+		count     int32
+		previous  *esv1.IngestAutoscalingStatus
+		nodes     []esclient.ThreadPoolStats
+		wantCount int32
+		wantScale bool
+	}{
+		{
+			name:      "first evaluation",
+			count:     2,
+			nodes:     []esclient.ThreadPoolStats{{Queue: 100, Rejected: 10}, {}},
+			wantCount: 2,
+			wantScale: true,
+		},
+		{
+			name:      "new rejections: scale up",
+			count:     2,
+			previous:  &esv1.IngestAutoscalingStatus{Count: 2, WriteRejections: 5, LastScaleTime: ago(time.Hour)},
+			nodes:     []esclient.ThreadPoolStats{{Rejected: 6}, {}},
+			wantCount: 3,
+			wantScale: true,
+		},
+		{
+			name:      "queue above threshold: scale up",
+			count:     2,
+			previous:  &esv1.IngestAutoscalingStatus{Count: 2, LastScaleTime: ago(time.Hour)},
+			nodes:     []esclient.ThreadPoolStats{{Queue: 15}, {Queue: 6}},
+			wantCount: 3,
+			wantScale: true,
+		},
+		{
+			name:      "queue below threshold: no change",
+			count:     2,
+			previous:  &esv1.IngestAutoscalingStatus{Count: 2, LastScaleTime: ago(time.Hour)},
+			nodes:     []esclient.ThreadPoolStats{{Queue: 15}, {Queue: 5}},
+			wantCount: 2,
+		},
+		{
+			name:      "rejection counters reset: no change",
+			count:     2,
+			previous:  &esv1.IngestAutoscalingStatus{Count: 2, WriteRejections: 50, LastScaleTime: ago(time.Hour), LastPressureTime: ago(time.Minute)},
+			nodes:     []esclient.ThreadPoolStats{{Rejected: 3}, {}},
+			wantCount: 2,
+		},
+		{
+			name:      "maximum reached: no change",
+			count:     3,
+			previous:  &esv1.IngestAutoscalingStatus{Count: 3, LastScaleTime: ago(time.Hour)},
+			nodes:     []esclient.ThreadPoolStats{{Rejected: 3}, {}, {}},
+			wantCount: 3,
+		},
+		{
+			name:      "scaled up recently: no change",
+			count:     2,
+			previous:  &esv1.IngestAutoscalingStatus{Count: 2, LastScaleTime: ago(30 * time.Second)},
+			nodes:     []esclient.ThreadPoolStats{{Rejected: 3}, {}},
+			wantCount: 2,
+		},
+		{
+			name:      "nodes not running yet: no change",
+			count:     2,
+			previous:  &esv1.IngestAutoscalingStatus{Count: 2, LastScaleTime: ago(time.Hour)},
+			nodes:     []esclient.ThreadPoolStats{{Rejected: 3}},
+			wantCount: 2,
+		},
+		{
+			name:      "no pressure during the scale down delay: scale down",
+			count:     2,
+			previous:  &esv1.IngestAutoscalingStatus{Count: 2, LastScaleTime: ago(time.Hour), LastPressureTime: ago(11 * time.Minute)},
+			nodes:     []esclient.ThreadPoolStats{{}, {}},
+			wantCount: 1,
+			wantScale: true,
+		},
+		{
+			name:      "recent pressure: no change",
+			count:     2,
+			previous:  &esv1.IngestAutoscalingStatus{Count: 2, LastScaleTime: ago(time.Hour), LastPressureTime: ago(5 * time.Minute)},
+			nodes:     []esclient.ThreadPoolStats{{}, {}},
+			wantCount: 2,
+		},
+		{
+			name:      "queued tasks: no change",
+			count:     2,
+			previous:  &esv1.IngestAutoscalingStatus{Count: 2, LastScaleTime: ago(time.Hour), LastPressureTime: ago(time.Hour)},
+			nodes:     []esclient.ThreadPoolStats{{Queue: 1}, {}},
+			wantCount: 2,
+		},
+		{
+			name:      "minimum reached: no change",
+			count:     1,
+			previous:  &esv1.IngestAutoscalingStatus{Count: 1, LastScaleTime: ago(time.Hour)},
+			nodes:     []esclient.ThreadPoolStats{{}},
+			wantCount: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := evaluate(spec, "ingest", tt.count, tt.previous, tt.nodes, now)
+			require.Equal(t, tt.wantCount, status.Count)
+			require.Equal(t, tt.wantScale, status.LastScaleTime.Equal(ptr.To(metav1.NewTime(now))))
+		})
+	}
+}
@@ -7,6 +7,7 @@ package initcontainer
 import (
 	corev1 "k8s.io/api/core/v1"
 
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
 )
@@ -20,9 +21,10 @@ const (
 
 // NewInitContainers creates init containers according to the given parameters
 func NewInitContainers(
-	transportCertificatesVolume volume.SecretVolume,
+	transportCertificatesVolume volume.VolumeLike,
 	keystoreResources *keystore.Resources,
 	nodeLabelsAsAnnotations []string,
+	plugins *esv1.PluginsSpec,
 ) ([]corev1.Container, error) {
 	var containers []corev1.Container
 	prepareFsContainer, err := NewPrepareFSInitContainer(transportCertificatesVolume, nodeLabelsAsAnnotations)
@@ -31,6 +33,11 @@ func NewInitContainers(
 	}
 	containers = append(containers, prepareFsContainer)
 
+	// plugins are installed once the plugins directory has been prepared
+	if pluginsContainer, ok := NewPluginsInitContainer(plugins); ok {
+		containers = append(containers, pluginsContainer)
+	}
+
 	if keystoreResources != nil {
 		containers = append(containers, keystoreResources.InitContainer)
 	}
@@ -0,0 +1,104 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package initcontainer
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
+)
+
+const (
+	// PluginsContainerName is the name of the container that installs the Elasticsearch plugins.
+	PluginsContainerName = "elastic-internal-install-plugins"
+
+	PluginBinPath = "/usr/share/elasticsearch/bin/elasticsearch-plugin"
+)
+
+// pluginsResources are the default request and limits for the plugins init container, which runs a JVM.
+var pluginsResources = corev1.ResourceRequirements{
+	Requests: map[corev1.ResourceName]resource.Quantity{
+		corev1.ResourceMemory: resource.MustParse("256Mi"),
+		corev1.ResourceCPU:    resource.MustParse("500m"),
+	},
+	Limits: map[corev1.ResourceName]resource.Quantity{
+		corev1.ResourceMemory: resource.MustParse("256Mi"),
+		corev1.ResourceCPU:    resource.MustParse("500m"),
+	},
+}
+
+// NewPluginsInitContainer creates an init container to install the given plugins in the plugins directory shared with
+// the Elasticsearch container. It returns false if there is no plugin to install.
+func NewPluginsInitContainer(plugins *esv1.PluginsSpec) (corev1.Container, bool) {
+	if plugins == nil || len(plugins.Install) == 0 {
+		return corev1.Container{}, false
+	}
+	return corev1.Container{
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Name:            PluginsContainerName,
+		Env:             defaults.PodDownwardEnvVars(),
+		Command:         []string{"bash", "-c", RenderPluginsScript(*plugins)},
+		Resources:       pluginsResources,
+	}, true
+}
+
+// RenderPluginsScript renders the script installing the given plugins. Plugins already present in the plugins directory,
+// because they are part of the Elasticsearch image or because the init container is restarted, are not installed again.
+func RenderPluginsScript(plugins esv1.PluginsSpec) string {
+	var script strings.Builder
+	script.WriteString("set -eu\n")
+	if opts := proxyJavaOpts(plugins.Proxy); opts != "" {
+		// CLI_JAVA_OPTS is read by the Elasticsearch tools from 8.0, ES_JAVA_OPTS by older versions
+		fmt.Fprintf(&script, "export CLI_JAVA_OPTS=\"%s ${CLI_JAVA_OPTS:-}\"\n", opts)
+		fmt.Fprintf(&script, "export ES_JAVA_OPTS=\"%s ${ES_JAVA_OPTS:-}\"\n", opts)
+	}
+	for _, plugin := range plugins.Install {
+		fmt.Fprintf(&script, "if [[ -d %s ]]; then\n", shellQuote(path.Join(EsPluginsSharedVolume.ContainerMountPath, plugin.Name)))
+		fmt.Fprintf(&script, "  echo %s\n", shellQuote(fmt.Sprintf("Plugin %s already installed", plugin.Name)))
+		script.WriteString("else\n")
+		fmt.Fprintf(&script, "  %s install --batch %s\n", PluginBinPath, shellQuote(plugin.Source()))
+		script.WriteString("fi\n")
+	}
+	return script.String()
+}
+
+// proxyJavaOpts returns the JVM parameters to download the plugins through the given HTTP proxy, or an empty string if
+// no valid proxy is set.
+func proxyJavaOpts(proxy string) string {
+	if proxy == "" {
+		return ""
+	}
+	u, err := url.Parse(proxy)
+	if err != nil || u.Hostname() == "" {
+		return ""
+	}
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	var opts []string
+	for _, protocol := range []string{"http", "https"} {
+		opts = append(opts,
+			fmt.Sprintf("-D%s.proxyHost=%s", protocol, u.Hostname()),
+			fmt.Sprintf("-D%s.proxyPort=%s", protocol, port),
+		)
+	}
+	return strings.Join(opts, " ")
+}
+
+// shellQuote quotes the given string to be used as a single word in a shell script.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
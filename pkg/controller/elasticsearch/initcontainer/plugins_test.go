@@ -0,0 +1,71 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package initcontainer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+)
+
+func TestRenderPluginsScript(t *testing.T) {
+	tests := []struct {
+		name    string
+		plugins esv1.PluginsSpec
+		want    string
+	}{
+		{
+			name: "official and custom plugins",
+			plugins: esv1.PluginsSpec{Install: []esv1.Plugin{
+				{Name: "analysis-icu"},
+				{Name: "custom", URL: "https://artifacts.example.com/custom-8.15.0.zip"},
+			}},
+			want: `set -eu
+if [[ -d '/usr/share/elasticsearch/plugins/analysis-icu' ]]; then
+  echo 'Plugin analysis-icu already installed'
+else
+  /usr/share/elasticsearch/bin/elasticsearch-plugin install --batch 'analysis-icu'
+fi
+if [[ -d '/usr/share/elasticsearch/plugins/custom' ]]; then
+  echo 'Plugin custom already installed'
+else
+  /usr/share/elasticsearch/bin/elasticsearch-plugin install --batch 'https://artifacts.example.com/custom-8.15.0.zip'
+fi
+`,
+		},
+		{
+			name: "through a proxy",
+			plugins: esv1.PluginsSpec{
+				Install: []esv1.Plugin{{Name: "analysis-icu"}},
+				Proxy:   "http://proxy.example.com:3128",
+			},
+			want: `set -eu
+export CLI_JAVA_OPTS="-Dhttp.proxyHost=proxy.example.com -Dhttp.proxyPort=3128 -Dhttps.proxyHost=proxy.example.com -Dhttps.proxyPort=3128 ${CLI_JAVA_OPTS:-}"
+export ES_JAVA_OPTS="-Dhttp.proxyHost=proxy.example.com -Dhttp.proxyPort=3128 -Dhttps.proxyHost=proxy.example.com -Dhttps.proxyPort=3128 ${ES_JAVA_OPTS:-}"
+if [[ -d '/usr/share/elasticsearch/plugins/analysis-icu' ]]; then
+  echo 'Plugin analysis-icu already installed'
+else
+  /usr/share/elasticsearch/bin/elasticsearch-plugin install --batch 'analysis-icu'
+fi
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, RenderPluginsScript(tt.plugins))
+		})
+	}
+}
+
+func Test_proxyJavaOpts(t *testing.T) {
+	require.Equal(t, "", proxyJavaOpts(""))
+	require.Equal(t, "", proxyJavaOpts("http://"))
+	require.Equal(t,
+		"-Dhttp.proxyHost=proxy -Dhttp.proxyPort=443 -Dhttps.proxyHost=proxy -Dhttps.proxyPort=443",
+		proxyJavaOpts("https://proxy"),
+	)
+}
@@ -14,6 +14,7 @@ import (
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
 	esvolume "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
 )
 
 const (
@@ -30,9 +31,9 @@ sleep 10
 done
 `, SuspendedHostsFile, esv1.SuspendAnnotation)
 
-// RenderSuspendConfiguration renders the configuration used by the SuspendScript.
-func RenderSuspendConfiguration(es esv1.Elasticsearch) string {
-	names := es.SuspendedPodNames().AsSlice()
+// RenderSuspendConfiguration renders the configuration used by the SuspendScript from the names of the suspended Pods.
+func RenderSuspendConfiguration(suspendedPodNames set.StringSet) string {
+	names := suspendedPodNames.AsSlice()
 	names.Sort()
 	return strings.Join(names, "\n")
 }
@@ -46,3 +47,13 @@ func NewSuspendInitContainer() corev1.Container {
 		Command:         []string{"bash", "-c", path.Join(esvolume.ScriptsVolumeMountPath, SuspendScriptConfigKey)},
 	}
 }
+
+// IsSuspended returns true if the given Pod is currently suspended in the suspend init container.
+func IsSuspended(pod corev1.Pod) bool {
+	for _, s := range pod.Status.InitContainerStatuses {
+		if s.Name == SuspendContainerName && s.State.Running != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -7,49 +7,35 @@ package initcontainer
 import (
 	"testing"
 
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-
-	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
 )
 
 func TestRenderSuspendConfiguration(t *testing.T) {
 	tests := []struct {
-		name string
-		es   esv1.Elasticsearch
-		want string
+		name  string
+		names set.StringSet
+		want  string
 	}{
 		{
-			name: "no annotation",
-			es:   esv1.Elasticsearch{},
-			want: "",
+			name:  "no suspended Pods",
+			names: set.Make(),
+			want:  "",
 		},
 		{
-			name: "single value",
-			es: esv1.Elasticsearch{
-				ObjectMeta: metav1.ObjectMeta{
-					Annotations: map[string]string{
-						esv1.SuspendAnnotation: "pod-1",
-					},
-				},
-			},
-			want: "pod-1",
+			name:  "single value",
+			names: set.Make("pod-1"),
+			want:  "pod-1",
 		},
 		{
-			name: "multi value",
-			es: esv1.Elasticsearch{
-				ObjectMeta: metav1.ObjectMeta{
-					Annotations: map[string]string{
-						esv1.SuspendAnnotation: "pod-1,pod-2",
-					},
-				},
-			},
+			name:  "multi value",
+			names: set.Make("pod-2", "pod-1"),
 			want: `pod-1
 pod-2`,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := RenderSuspendConfiguration(tt.es); got != tt.want {
+			if got := RenderSuspendConfiguration(tt.names); got != tt.want {
 				t.Errorf("RenderSuspendConfiguration() = %v, want %v", got, tt.want)
 			}
 		})
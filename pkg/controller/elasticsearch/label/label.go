@@ -55,6 +55,11 @@ const (
 
 	HTTPSchemeLabelName = "elasticsearch.k8s.elastic.co/http-scheme"
 
+	// ZoneSpreadNodeSetLabelName is set on the Pods of a NodeSet spread across zones to the name of that NodeSet
+	ZoneSpreadNodeSetLabelName = "elasticsearch.k8s.elastic.co/zone-spread-node-set"
+	// ZoneSpreadZoneLabelName is set on the Pods of a NodeSet spread across zones to the zone of the Pods
+	ZoneSpreadZoneLabelName = "elasticsearch.k8s.elastic.co/zone"
+
 	// Type represents the Elasticsearch type
 	Type = "elasticsearch"
 )
@@ -83,6 +88,34 @@ func IsMasterNodeSet(statefulSet appsv1.StatefulSet) bool {
 	return NodeTypesMasterLabelName.HasValue(true, statefulSet.Spec.Template.Labels)
 }
 
+// IsVotingOnlyNode returns true if the pod has the voting-only node label.
+func IsVotingOnlyNode(pod corev1.Pod) bool {
+	return NodeTypesVotingOnlyLabelName.HasValue(true, pod.Labels)
+}
+
+// IsVotingOnlyNodeSet returns true if the given StatefulSet specifies voting-only master-eligible nodes.
+func IsVotingOnlyNodeSet(statefulSet appsv1.StatefulSet) bool {
+	return NodeTypesVotingOnlyLabelName.HasValue(true, statefulSet.Spec.Template.Labels)
+}
+
+// IsCoordinatingOnlyNodeSet returns true if the given StatefulSet specifies coordinating-only nodes, which are neither
+// master-eligible, nor hold data, nor run ingest, machine learning or transform tasks.
+func IsCoordinatingOnlyNodeSet(statefulSet appsv1.StatefulSet) bool {
+	if IsMasterNodeSet(statefulSet) {
+		return false
+	}
+	for _, role := range NonMasterRoles {
+		if role == NodeTypesRemoteClusterClientLabelName {
+			// remote cluster clients only coordinate the requests sent to remote clusters
+			continue
+		}
+		if role.HasValue(true, statefulSet.Spec.Template.Labels) {
+			return false
+		}
+	}
+	return true
+}
+
 // IsDataNodeSet returns true if the given StatefulSet specifies data nodes.
 func IsDataNodeSet(statefulSet appsv1.StatefulSet) bool {
 	return NodeTypesDataLabelName.HasValue(true, statefulSet.Spec.Template.Labels)
@@ -38,13 +38,20 @@ func isBasic(l esclient.License) bool {
 	return l.Type == string(esclient.ElasticsearchLicenseTypeBasic)
 }
 
+// isExpired returns true if Elasticsearch reports the license as expired
+func isExpired(l esclient.License) bool {
+	return l.Status == "expired"
+}
+
+// applyLinkedLicense applies the license linked to the cluster, or reverts the cluster to a basic license if there is
+// none. It returns the type of the license applied, or an empty string if the cluster license was left unchanged.
 func applyLinkedLicense(
 	ctx context.Context,
 	c k8s.Client,
 	esCluster types.NamespacedName,
 	updater esclient.LicenseClient,
 	currentLicense esclient.License,
-) error {
+) (string, error) {
 	// get the expected license
 	// the underlying assumption here is that either a user or a
 	// license controller has created a cluster license in the
@@ -59,14 +66,18 @@ func applyLinkedLicense(
 		&license,
 	)
 	if err != nil && !apierrors.IsNotFound(err) {
-		return err
+		return "", err
 	}
 	if err != nil && apierrors.IsNotFound(err) {
 		// no license expected, let's look at the current cluster license
 		switch {
 		case isBasic(currentLicense):
 			// nothing to do
-			return nil
+			return "", nil
+		case isTrial(currentLicense) && isExpired(currentLicense):
+			// an expired trial disables the commercial features and some of the monitoring APIs of Elasticsearch,
+			// revert to basic to keep the cluster fully functional with the basic features
+			return startBasic(ctx, updater)
 		case isTrial(currentLicense):
 			// Elasticsearch reports a trial license, but there's no ECK enterprise trial requested.
 			// This can be the case if:
@@ -76,7 +87,7 @@ func applyLinkedLicense(
 			// we tolerate it to avoid a bad user experience because trials can only be started once.
 			ulog.FromContext(ctx).V(1).Info("Preserving existing stack-level trial license",
 				"namespace", esCluster.Namespace, "es_name", esCluster.Name)
-			return nil
+			return "", nil
 		default:
 			// revert the current license to basic
 			return startBasic(ctx, updater)
@@ -85,36 +96,44 @@ func applyLinkedLicense(
 
 	bytes, err := commonlicense.FetchLicenseData(license.Data)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	var desired esclient.License
 	err = json.Unmarshal(bytes, &desired)
 	if err != nil {
-		return pkgerrors.Wrap(err, "no valid license found in license secret")
+		return "", pkgerrors.Wrap(err, "no valid license found in license secret")
 	}
 	return updateLicense(ctx, esCluster, updater, currentLicense, desired)
 }
 
-func startBasic(ctx context.Context, updater esclient.LicenseClient) error {
+func startBasic(ctx context.Context, updater esclient.LicenseClient) (string, error) {
 	_, err := updater.StartBasic(ctx)
 	if err != nil && esclient.IsForbidden(err) {
 		// ES returns 403 + acknowledged: true (which we don't parse in case of error) if we are already in basic mode
-		return nil
+		return "", nil
+	}
+	if err != nil {
+		return "", pkgerrors.Wrap(err, "failed to revert to basic")
 	}
-	return pkgerrors.Wrap(err, "failed to revert to basic")
+	return string(esclient.ElasticsearchLicenseTypeBasic), nil
 }
 
 // updateLicense make the call to Elasticsearch to set the license. This function exists mainly to facilitate testing.
+// It returns the type of the license applied, or an empty string if the license was already applied.
 func updateLicense(
 	ctx context.Context,
 	esCluster types.NamespacedName,
 	updater esclient.LicenseClient,
 	current esclient.License,
 	desired esclient.License,
-) error {
+) (string, error) {
+	if isECKManagedTrial(desired) && isTrial(current) && isExpired(current) {
+		// a trial can only be started once, revert to basic once it expired
+		return startBasic(ctx, updater)
+	}
 	if current.UID == desired.UID || (isTrial(current) && current.Type == desired.Type) {
-		return nil // we are done already applied
+		return "", nil // we are done already applied
 	}
 	request := esclient.LicenseUpdateRequest{
 		Licenses: []esclient.License{
@@ -124,22 +143,29 @@ func updateLicense(
 
 	if isECKManagedTrial(desired) {
 		// start a self-generated trial in Elasticsearch, this can only be done once.
-		return pkgerrors.Wrap(startTrial(ctx, updater, esCluster), "failed to start trial")
+		started, err := startTrial(ctx, updater, esCluster)
+		if err != nil {
+			return "", pkgerrors.Wrap(err, "failed to start trial")
+		}
+		if !started {
+			return "", nil
+		}
+		return desired.Type, nil
 	}
 
 	response, err := updater.UpdateLicense(ctx, request)
 	if err != nil {
-		return pkgerrors.Wrap(err, fmt.Sprintf("failed to update license to %s", desired.Type))
+		return "", pkgerrors.Wrap(err, fmt.Sprintf("failed to update license to %s", desired.Type))
 	}
 	if !response.IsSuccess() {
-		return pkgerrors.Errorf("failed to apply license: %s", response.LicenseStatus)
+		return "", pkgerrors.Errorf("failed to apply license: %s", response.LicenseStatus)
 	}
-	return nil
+	return desired.Type, nil
 }
 
 // startTrial starts the trial license after checking that the trial is not yet activated by directly hitting the
-// Elasticsearch API.
-func startTrial(ctx context.Context, c esclient.LicenseClient, esCluster types.NamespacedName) error {
+// Elasticsearch API. It returns true if the trial was started.
+func startTrial(ctx context.Context, c esclient.LicenseClient, esCluster types.NamespacedName) (bool, error) {
 	response, err := c.StartTrial(ctx)
 	log := ulog.FromContext(ctx)
 	if err != nil && esclient.IsForbidden(err) {
@@ -148,7 +174,7 @@ func startTrial(ctx context.Context, c esclient.LicenseClient, esCluster types.N
 			"namespace", esCluster.Namespace,
 			"name", esCluster.Name,
 		)
-		return nil
+		return false, nil
 	}
 	if response.IsSuccess() {
 		log.Info(
@@ -157,5 +183,5 @@ func startTrial(ctx context.Context, c esclient.LicenseClient, esCluster types.N
 			"name", esCluster.Name,
 		)
 	}
-	return err
+	return err == nil && response.IsSuccess(), err
 }
@@ -34,10 +34,11 @@ func Test_updateLicense(t *testing.T) {
 		desired esclient.License
 	}
 	tests := []struct {
-		name    string
-		args    args
-		reqFn   esclient.RoundTripFunc
-		wantErr bool
+		name        string
+		args        args
+		reqFn       esclient.RoundTripFunc
+		wantApplied string
+		wantErr     bool
 	}{
 		{
 			name:    "error: HTTP error",
@@ -75,21 +76,47 @@ func Test_updateLicense(t *testing.T) {
 					fixtures.LicenseUpdateResponseSample,
 				)
 			},
+			wantApplied: "enterprise",
 		},
 		{
 			name: "start a trial",
 			args: args{
+				current: esclient.License{
+					UID:  "basic-license",
+					Type: string(esclient.ElasticsearchLicenseTypeBasic),
+				},
 				desired: esclient.License{
 					Type: string(esclient.ElasticsearchLicenseTypeTrial),
 				},
 			},
 			reqFn: func(req *http.Request) *http.Response {
 				if strings.Contains(req.URL.Path, "start_trial") {
-					return esclient.NewMockResponse(200, req, `{"acknowledged": true, "trial_started": true}`)
+					return esclient.NewMockResponse(200, req, `{"acknowledged": true, "trial_was_started": true}`)
 				}
 				panic("should only call start_trial")
 			},
-			wantErr: false,
+			wantApplied: "trial",
+			wantErr:     false,
+		},
+		{
+			name: "revert an expired trial to basic",
+			args: args{
+				current: esclient.License{
+					UID:    "trial-license",
+					Type:   string(esclient.ElasticsearchLicenseTypeTrial),
+					Status: "expired",
+				},
+				desired: esclient.License{
+					Type: string(esclient.ElasticsearchLicenseTypeTrial),
+				},
+			},
+			reqFn: func(req *http.Request) *http.Response {
+				if strings.Contains(req.URL.Path, "start_basic") {
+					return esclient.NewMockResponse(200, req, `{"acknowledged": true, "basic_was_started": true}`)
+				}
+				panic("should only call start_basic")
+			},
+			wantApplied: "basic",
 		},
 		{
 			name: "short-circuit: already up to date",
@@ -109,9 +136,11 @@ func Test_updateLicense(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := esclient.NewMockClient(version.MustParse("6.8.0"), tt.reqFn)
-			if err := updateLicense(context.Background(), types.NamespacedName{}, c, tt.args.current, tt.args.desired); (err != nil) != tt.wantErr {
+			applied, err := updateLicense(context.Background(), types.NamespacedName{}, c, tt.args.current, tt.args.desired)
+			if (err != nil) != tt.wantErr {
 				t.Errorf("updateLicense() error = %v, wantErr %v", err, tt.wantErr)
 			}
+			require.Equal(t, tt.wantApplied, applied)
 		})
 	}
 }
@@ -126,12 +155,14 @@ func Test_applyLinkedLicense(t *testing.T) {
 		initialObjs      []client.Object
 		currentLicense   esclient.License
 		errors           map[client.ObjectKey]error
+		wantApplied      string
 		wantErr          bool
 		clientAssertions func(updater fakeLicenseUpdater)
 	}{
 		{
-			name:    "happy path",
-			wantErr: false,
+			name:        "happy path",
+			wantApplied: "platinum",
+			wantErr:     false,
 			initialObjs: []client.Object{
 				&corev1.Secret{
 					ObjectMeta: metav1.ObjectMeta{
@@ -149,6 +180,7 @@ func Test_applyLinkedLicense(t *testing.T) {
 		},
 		{
 			name:           "no error: no license found but stack has an enterprise license",
+			wantApplied:    "basic",
 			wantErr:        false,
 			currentLicense: esclient.License{Type: string(esclient.ElasticsearchLicenseTypeEnterprise)},
 			clientAssertions: func(updater fakeLicenseUpdater) {
@@ -171,6 +203,15 @@ func Test_applyLinkedLicense(t *testing.T) {
 				require.False(t, updater.startBasicCalled, "should not call start_basic")
 			},
 		},
+		{
+			name:           "no error: no license found, revert an expired cluster level trial to basic",
+			wantApplied:    "basic",
+			wantErr:        false,
+			currentLicense: esclient.License{Type: string(esclient.ElasticsearchLicenseTypeTrial), Status: "expired"},
+			clientAssertions: func(updater fakeLicenseUpdater) {
+				require.True(t, updater.startBasicCalled, "should call start_basic to revert the expired trial")
+			},
+		},
 		{
 			name:    "error: empty license",
 			wantErr: true,
@@ -216,15 +257,17 @@ func Test_applyLinkedLicense(t *testing.T) {
 				errors: tt.errors,
 			}
 			updater := fakeLicenseUpdater{license: tt.currentLicense}
-			if err := applyLinkedLicense(
+			applied, err := applyLinkedLicense(
 				context.Background(),
 				c,
 				clusterName,
 				&updater,
 				tt.currentLicense,
-			); (err != nil) != tt.wantErr {
+			)
+			if (err != nil) != tt.wantErr {
 				t.Errorf("applyLinkedLicense() error = %v, wantErr %v", err, tt.wantErr)
 			}
+			require.Equal(t, tt.wantApplied, applied)
 			if tt.clientAssertions != nil {
 				tt.clientAssertions(updater)
 			}
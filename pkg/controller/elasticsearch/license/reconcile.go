@@ -8,24 +8,47 @@ import (
 	"context"
 
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
 )
 
-// Reconcile reconciles the current Elasticsearch license with the desired one.
+// Reconcile reconciles the current Elasticsearch license with the desired one. It returns the type of the license
+// applied to the cluster, or an empty string if the cluster license was left unchanged.
 func Reconcile(
 	ctx context.Context,
 	c k8s.Client,
 	esCluster esv1.Elasticsearch,
 	clusterClient esclient.Client,
 	currentLicense esclient.License,
-) error {
+) (string, error) {
 	clusterName := k8s.ExtractNamespacedName(&esCluster)
+	updateMetrics(clusterName, currentLicense)
 	return applyLinkedLicense(ctx, c, clusterName, clusterClient, currentLicense)
 }
 
+// updateMetrics reports the type, the status and the expiration time of the license currently applied to the cluster,
+// if any.
+func updateMetrics(es types.NamespacedName, l esclient.License) {
+	DeleteMetrics(es)
+	if l.Type != "" {
+		metrics.LicenseInfoGauge.WithLabelValues(es.Namespace, es.Name, l.Type, l.Status).Set(1)
+	}
+	if l.ExpiryDateInMillis != 0 {
+		metrics.LicenseExpiryGauge.WithLabelValues(es.Namespace, es.Name).Set(float64(l.ExpiryTime().Unix()))
+	}
+}
+
+// DeleteMetrics removes the license metrics of the given Elasticsearch cluster.
+func DeleteMetrics(es types.NamespacedName) {
+	metrics.LicenseExpiryGauge.DeleteLabelValues(es.Namespace, es.Name)
+	metrics.LicenseInfoGauge.DeletePartialMatch(prometheus.Labels{metrics.NamespaceLabel: es.Namespace, metrics.NameLabel: es.Name})
+}
+
 // CheckElasticsearchLicense checks that Elasticsearch is licensed, which ensures that the operator is communicating
 // with a supported Elasticsearch distribution and that Elasticsearch is reachable.
 func CheckElasticsearchLicense(ctx context.Context, clusterClient esclient.LicenseClient) (esclient.License, error) {
@@ -0,0 +1,105 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+// Package maintenance evaluates the maintenance windows of Elasticsearch clusters, outside which the operator does not
+// restart the Elasticsearch nodes.
+package maintenance
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	_ "time/tzdata" // embed the time zone database, which may be missing from the operator image
+
+	"github.com/robfig/cron/v3"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+)
+
+// parser parses the standard cron expressions, with five fields, and the predefined schedules such as @daily.
+var parser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// Window is a parsed maintenance window.
+type Window struct {
+	schedule cron.Schedule
+	duration time.Duration
+	location *time.Location
+}
+
+// Windows are the parsed maintenance windows of a cluster. Nodes can be restarted at any time if there is no window.
+type Windows []Window
+
+// Parse parses the given maintenance window.
+func Parse(window esv1.MaintenanceWindow) (Window, error) {
+	if strings.HasPrefix(window.Schedule, "TZ=") || strings.HasPrefix(window.Schedule, "CRON_TZ=") {
+		return Window{}, errors.New("time zone must be set with timeZone, not in the schedule")
+	}
+	if strings.HasPrefix(window.Schedule, "@every") {
+		return Window{}, errors.New("@every schedules are not supported")
+	}
+	schedule, err := parser.Parse(window.Schedule)
+	if err != nil {
+		return Window{}, fmt.Errorf("invalid schedule: %w", err)
+	}
+	if window.Duration.Duration <= 0 {
+		return Window{}, fmt.Errorf("duration must be positive, got %s", window.Duration.Duration)
+	}
+	// an empty time zone is UTC
+	location, err := time.LoadLocation(window.TimeZone)
+	if err != nil {
+		return Window{}, fmt.Errorf("invalid time zone: %w", err)
+	}
+	parsed := Window{schedule: schedule, duration: window.Duration.Duration, location: location}
+	if parsed.schedule.Next(time.Now().In(location)).IsZero() {
+		// for example on February 30th
+		return Window{}, fmt.Errorf("schedule %q never opens the window", window.Schedule)
+	}
+	return parsed, nil
+}
+
+// ParseAll parses the maintenance windows of the given cluster.
+func ParseAll(es esv1.Elasticsearch) (Windows, error) {
+	windows := make(Windows, 0, len(es.Spec.MaintenanceWindows))
+	for i, window := range es.Spec.MaintenanceWindows {
+		parsed, err := Parse(window)
+		if err != nil {
+			return nil, fmt.Errorf("maintenance window %d: %w", i, err)
+		}
+		windows = append(windows, parsed)
+	}
+	return windows, nil
+}
+
+// Open returns true if the window is open at the given time. Otherwise, it returns the time at which it opens next.
+func (w Window) Open(t time.Time) (bool, time.Time) {
+	// the first opening after the start of the latest window which could still be open
+	opening := w.schedule.Next(t.Add(-w.duration).In(w.location))
+	if opening.IsZero() {
+		return false, opening
+	}
+	if !opening.After(t) {
+		return true, time.Time{}
+	}
+	return false, opening
+}
+
+// Open returns true if no window is defined or if one of the windows is open at the given time. Otherwise, it returns
+// the time at which the first window opens next, which is zero if no window ever opens again.
+func (ws Windows) Open(t time.Time) (bool, time.Time) {
+	if len(ws) == 0 {
+		return true, time.Time{}
+	}
+	var next time.Time
+	for _, w := range ws {
+		open, opening := w.Open(t)
+		if open {
+			return true, time.Time{}
+		}
+		if !opening.IsZero() && (next.IsZero() || opening.Before(next)) {
+			next = opening
+		}
+	}
+	return false, next
+}
@@ -0,0 +1,110 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+)
+
+func window(schedule string, duration time.Duration, timeZone string) esv1.MaintenanceWindow {
+	return esv1.MaintenanceWindow{Schedule: schedule, Duration: metav1.Duration{Duration: duration}, TimeZone: timeZone}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		window  esv1.MaintenanceWindow
+		wantErr bool
+	}{
+		{name: "cron expression", window: window("30 2 * * MON-FRI", time.Hour, "")},
+		{name: "predefined schedule", window: window("@weekly", time.Hour, "")},
+		{name: "time zone", window: window("0 22 * * *", time.Hour, "America/New_York")},
+		{name: "six fields", window: window("0 0 2 * * *", time.Hour, ""), wantErr: true},
+		{name: "invalid field", window: window("0 25 * * *", time.Hour, ""), wantErr: true},
+		{name: "time zone in the schedule", window: window("CRON_TZ=Europe/Paris 0 2 * * *", time.Hour, ""), wantErr: true},
+		{name: "every", window: window("@every 1h", time.Hour, ""), wantErr: true},
+		{name: "never opens", window: window("0 2 30 2 *", time.Hour, ""), wantErr: true},
+		{name: "negative duration", window: window("0 2 * * *", -time.Hour, ""), wantErr: true},
+		{name: "unknown time zone", window: window("0 2 * * *", time.Hour, "Europe/Atlantis"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.window)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+		})
+	}
+}
+
+func TestWindows_Open(t *testing.T) {
+	// a Friday
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		windows     []esv1.MaintenanceWindow
+		wantOpen    bool
+		wantOpening time.Time
+	}{
+		{
+			name:     "no window",
+			wantOpen: true,
+		},
+		{
+			name:     "inside the window",
+			windows:  []esv1.MaintenanceWindow{window("0 10 * * *", 4*time.Hour, "")},
+			wantOpen: true,
+		},
+		{
+			name:     "window opening now",
+			windows:  []esv1.MaintenanceWindow{window("0 12 * * *", time.Hour, "")},
+			wantOpen: true,
+		},
+		{
+			name:        "window closing now",
+			windows:     []esv1.MaintenanceWindow{window("0 10 * * *", 2*time.Hour, "")},
+			wantOpening: time.Date(2024, 3, 16, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "before the window",
+			windows:     []esv1.MaintenanceWindow{window("0 2 * * SAT,SUN", 4*time.Hour, "")},
+			wantOpening: time.Date(2024, 3, 16, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "window spanning midnight",
+			windows:  []esv1.MaintenanceWindow{window("0 22 * * THU", 16*time.Hour, "")},
+			wantOpen: true,
+		},
+		{
+			name:        "time zone",
+			windows:     []esv1.MaintenanceWindow{window("0 12 * * *", time.Hour, "Europe/Paris")},
+			wantOpening: time.Date(2024, 3, 16, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "first window to open",
+			windows:     []esv1.MaintenanceWindow{window("0 2 * * SUN", time.Hour, ""), window("0 20 * * *", time.Hour, "")},
+			wantOpening: time.Date(2024, 3, 15, 20, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "one of the windows is open",
+			windows:  []esv1.MaintenanceWindow{window("0 2 * * SUN", time.Hour, ""), window("@daily", 13*time.Hour, "")},
+			wantOpen: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			windows, err := ParseAll(esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{MaintenanceWindows: tt.windows}})
+			require.NoError(t, err)
+			open, opening := windows.Open(now)
+			assert.Equal(t, tt.wantOpen, open)
+			assert.True(t, tt.wantOpening.Equal(opening), "expected next opening %s, got %s", tt.wantOpening, opening)
+		})
+	}
+}
@@ -0,0 +1,250 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package networkpolicy
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+const (
+	httpPort  = 80
+	httpsPort = 443
+)
+
+// CiliumNetworkPolicyGVK is the GroupVersionKind of the Cilium CiliumNetworkPolicy resource. The Cilium API is not
+// vendored, CiliumNetworkPolicies are handled as unstructured objects.
+var CiliumNetworkPolicyGVK = schema.GroupVersionKind{Group: "cilium.io", Version: "v2", Kind: "CiliumNetworkPolicy"}
+
+// snapshotRepositoryFQDNs are the domain names of the object storage services used by the snapshot repository types.
+var snapshotRepositoryFQDNs = map[string][]string{
+	"s3":    {"s3.amazonaws.com", "*.s3.amazonaws.com", "s3.*.amazonaws.com", "*.s3.*.amazonaws.com"},
+	"gcs":   {"storage.googleapis.com", "oauth2.googleapis.com"},
+	"azure": {"*.blob.core.windows.net"},
+}
+
+func newCiliumNetworkPolicy(es esv1.Elasticsearch) *unstructured.Unstructured {
+	policy := &unstructured.Unstructured{}
+	policy.SetGroupVersionKind(CiliumNetworkPolicyGVK)
+	policy.SetNamespace(es.Namespace)
+	policy.SetName(esv1.NetworkPolicy(es.Name))
+	return policy
+}
+
+// reconcileCiliumNetworkPolicy ensures the CiliumNetworkPolicy allowing the egress traffic to the domain names
+// Elasticsearch connects to exists, if the Cilium CRDs are installed. Cilium enforces the NetworkPolicy of the
+// Elasticsearch Pods as well, the traffic allowed by both policies is allowed.
+func reconcileCiliumNetworkPolicy(ctx context.Context, c k8s.Client, es esv1.Elasticsearch) error {
+	expected, err := expectedCiliumNetworkPolicy(es)
+	if err != nil {
+		return err
+	}
+	reconciled := newCiliumNetworkPolicy(es)
+	err = reconciler.ReconcileResource(reconciler.Params{
+		Context:    ctx,
+		Client:     c,
+		Owner:      &es,
+		Expected:   expected,
+		Reconciled: reconciled,
+		NeedsUpdate: func() bool {
+			return hash.GetTemplateHashLabel(expected.GetLabels()) != hash.GetTemplateHashLabel(reconciled.GetLabels())
+		},
+		UpdateReconciled: func() {
+			reconciled.SetLabels(expected.GetLabels())
+			reconciled.Object["spec"] = expected.Object["spec"]
+		},
+	})
+	if meta.IsNoMatchError(err) {
+		ulog.FromContext(ctx).Info(
+			"Cilium CRDs are not installed, skipping the CiliumNetworkPolicy",
+			"namespace", es.Namespace, "es_name", es.Name,
+		)
+		return nil
+	}
+	return err
+}
+
+// deleteCiliumNetworkPolicy deletes the CiliumNetworkPolicy of the Elasticsearch Pods if it exists.
+func deleteCiliumNetworkPolicy(ctx context.Context, c k8s.Client, es esv1.Elasticsearch) error {
+	err := c.Delete(ctx, newCiliumNetworkPolicy(es))
+	if err != nil && !apierrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return err
+	}
+	return nil
+}
+
+func expectedCiliumNetworkPolicy(es esv1.Elasticsearch) (*unstructured.Unstructured, error) {
+	fqdnsByPort, err := egressFQDNs(es)
+	if err != nil {
+		return nil, err
+	}
+
+	egress := []interface{}{
+		// FQDN rules rely on the DNS lookups being inspected by the Cilium DNS proxy
+		map[string]interface{}{
+			"toEndpoints": []interface{}{
+				map[string]interface{}{
+					"matchLabels": map[string]interface{}{
+						"k8s:io.kubernetes.pod.namespace": "kube-system",
+						"k8s:k8s-app":                     "kube-dns",
+					},
+				},
+			},
+			"toPorts": []interface{}{
+				map[string]interface{}{
+					"ports": []interface{}{
+						map[string]interface{}{"port": strconv.Itoa(dnsPort), "protocol": "ANY"},
+					},
+					"rules": map[string]interface{}{
+						"dns": []interface{}{map[string]interface{}{"matchPattern": "*"}},
+					},
+				},
+			},
+		},
+	}
+	ports := make([]int, 0, len(fqdnsByPort))
+	for port := range fqdnsByPort {
+		ports = append(ports, port)
+	}
+	sort.Ints(ports)
+	for _, port := range ports {
+		fqdns := fqdnsByPort[port]
+		sort.Strings(fqdns)
+		selectors := make([]interface{}, 0, len(fqdns))
+		for _, fqdn := range fqdns {
+			if strings.Contains(fqdn, "*") {
+				selectors = append(selectors, map[string]interface{}{"matchPattern": fqdn})
+				continue
+			}
+			selectors = append(selectors, map[string]interface{}{"matchName": fqdn})
+		}
+		egress = append(egress, map[string]interface{}{
+			"toFQDNs": selectors,
+			"toPorts": []interface{}{
+				map[string]interface{}{
+					"ports": []interface{}{
+						map[string]interface{}{"port": strconv.Itoa(port), "protocol": "TCP"},
+					},
+				},
+			},
+		})
+	}
+
+	expected := newCiliumNetworkPolicy(es)
+	spec := map[string]interface{}{
+		"endpointSelector": map[string]interface{}{
+			"matchLabels": map[string]interface{}{label.ClusterNameLabelName: es.Name},
+		},
+		"egress": egress,
+	}
+	expected.Object["spec"] = spec
+	// label the CiliumNetworkPolicy with a hash of its content, for comparison purposes
+	expected.SetLabels(hash.SetTemplateHashLabel(label.NewLabels(k8s.ExtractNamespacedName(&es)), spec))
+	// set owner reference for deletion upon ES resource deletion
+	if err := controllerutil.SetControllerReference(&es, expected, scheme.Scheme); err != nil {
+		return nil, err
+	}
+	return expected, nil
+}
+
+// egressFQDNs returns, by port, the domain names of the external snapshot repositories, of the external monitoring
+// clusters and the additional domain names from the specification.
+func egressFQDNs(es esv1.Elasticsearch) (map[int][]string, error) {
+	fqdns := map[int]map[string]struct{}{}
+	add := func(port int, fqdn string) {
+		if fqdns[port] == nil {
+			fqdns[port] = map[string]struct{}{}
+		}
+		fqdns[port][fqdn] = struct{}{}
+	}
+
+	for _, fqdn := range es.Spec.NetworkPolicy.EgressFQDNs {
+		add(httpsPort, fqdn)
+	}
+
+	for _, repository := range es.Spec.SnapshotRepositories {
+		for _, fqdn := range snapshotRepositoryFQDNs[repository.Type] {
+			add(httpsPort, fqdn)
+		}
+		if repository.Type != "url" || repository.Settings == nil {
+			continue
+		}
+		// read-only URL repositories can be served by any HTTP server
+		rawURL, ok := repository.Settings.Data["url"].(string)
+		if !ok {
+			continue
+		}
+		if host, port, ok := hostAndPort(rawURL); ok {
+			add(port, host)
+		}
+	}
+
+	for _, association := range es.GetAssociations() {
+		if !association.AssociationRef().IsExternal() {
+			// Pods of the clusters managed by the operator are selected by the NetworkPolicy
+			continue
+		}
+		assocConf, err := association.AssociationConf()
+		if err != nil {
+			return nil, err
+		}
+		if !assocConf.URLIsConfigured() {
+			continue
+		}
+		if host, port, ok := hostAndPort(assocConf.GetURL()); ok {
+			add(port, host)
+		}
+	}
+
+	result := make(map[int][]string, len(fqdns))
+	for port, names := range fqdns {
+		for name := range names {
+			result[port] = append(result[port], name)
+		}
+	}
+	return result, nil
+}
+
+// hostAndPort returns the host and the port of an HTTP URL, if the host is a domain name.
+func hostAndPort(rawURL string) (string, int, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return "", 0, false
+	}
+	if net.ParseIP(u.Hostname()) != nil {
+		// IP addresses cannot be matched by FQDN rules
+		return "", 0, false
+	}
+	port := httpsPort
+	if u.Scheme == "http" {
+		port = httpPort
+	}
+	if u.Port() != "" {
+		p, err := strconv.Atoi(u.Port())
+		if err != nil {
+			return "", 0, false
+		}
+		port = p
+	}
+	return u.Hostname(), port, true
+}
@@ -0,0 +1,70 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package networkpolicy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func Test_egressFQDNs(t *testing.T) {
+	es := newES(true)
+	es.Spec.NetworkPolicy.EgressFQDNs = []string{"artifacts.example.com", "*.s3.amazonaws.com"}
+	es.Spec.SnapshotRepositories = []esv1.SnapshotRepository{
+		{Name: "s3", Type: "s3"},
+		{Name: "fs", Type: "fs", Settings: &commonv1.Config{Data: map[string]interface{}{"location": "/backups"}}},
+		{Name: "url", Type: "url", Settings: &commonv1.Config{Data: map[string]interface{}{"url": "http://snapshots.example.com:8080/es"}}},
+		{Name: "url-ip", Type: "url", Settings: &commonv1.Config{Data: map[string]interface{}{"url": "http://10.0.0.1/es"}}},
+	}
+	externalRef := commonv1.ObjectSelector{SecretName: "external-monitoring"}
+	es.Spec.Monitoring.Metrics.ElasticsearchRefs = []commonv1.ObjectSelector{externalRef, {Name: "monitoring", Namespace: "ns"}}
+	es.Annotations = map[string]string{
+		commonv1.ElasticsearchConfigAnnotationName(externalRef.WithDefaultNamespace("ns")): `{"url":"https://monitoring.example.com:9243"}`,
+	}
+
+	fqdns, err := egressFQDNs(es)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"artifacts.example.com", "s3.amazonaws.com", "*.s3.amazonaws.com", "s3.*.amazonaws.com", "*.s3.*.amazonaws.com"}, fqdns[443])
+	require.ElementsMatch(t, []string{"snapshots.example.com"}, fqdns[8080])
+	require.ElementsMatch(t, []string{"monitoring.example.com"}, fqdns[9243])
+	require.Len(t, fqdns, 3)
+}
+
+func TestReconcile_Cilium(t *testing.T) {
+	es := newES(true)
+	es.Spec.NetworkPolicy.Cilium = true
+	es.Spec.NetworkPolicy.EgressFQDNs = []string{"artifacts.example.com"}
+	c := k8s.NewFakeClient(&es)
+	key := types.NamespacedName{Namespace: "ns", Name: esv1.NetworkPolicy("es")}
+
+	// the CiliumNetworkPolicy is created along with the NetworkPolicy
+	require.NoError(t, Reconcile(context.Background(), c, es, "elastic-system"))
+	policy := newCiliumNetworkPolicy(es)
+	require.NoError(t, c.Get(context.Background(), key, policy))
+	egress := policy.Object["spec"].(map[string]interface{})["egress"].([]interface{}) //nolint:forcetypeassert
+	require.Len(t, egress, 2)
+	require.Equal(t, []interface{}{map[string]interface{}{"matchName": "artifacts.example.com"}}, egress[1].(map[string]interface{})["toFQDNs"]) //nolint:forcetypeassert
+
+	// the CiliumNetworkPolicy is deleted if not requested anymore
+	es.Spec.NetworkPolicy.Cilium = false
+	require.NoError(t, Reconcile(context.Background(), c, es, "elastic-system"))
+	require.True(t, apierrors.IsNotFound(c.Get(context.Background(), key, newCiliumNetworkPolicy(es))))
+
+	// both policies are deleted if network policies are disabled
+	es.Spec.NetworkPolicy.Cilium = true
+	require.NoError(t, Reconcile(context.Background(), c, es, "elastic-system"))
+	require.NoError(t, c.Get(context.Background(), key, newCiliumNetworkPolicy(es)))
+	es.Spec.NetworkPolicy.Enabled = false
+	require.NoError(t, Reconcile(context.Background(), c, es, "elastic-system"))
+	require.True(t, apierrors.IsNotFound(c.Get(context.Background(), key, newCiliumNetworkPolicy(es))))
+}
@@ -0,0 +1,324 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package networkpolicy
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/certificates/remoteca"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/network"
+	esuser "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/user"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+const (
+	dnsPort = 53
+
+	// remoteClusterNamespaceLabelName and remoteClusterNameLabelName are set by the remote cluster controller on the
+	// Secrets holding the CA of the remote clusters, in both directions of the trust relationship.
+	remoteClusterNamespaceLabelName = "elasticsearch.k8s.elastic.co/remote-cluster-namespace"
+	remoteClusterNameLabelName      = "elasticsearch.k8s.elastic.co/remote-cluster-name"
+)
+
+// associations lists, for each type of resource which can be associated with Elasticsearch, the labels set by the
+// association controller on the user and service account token Secrets created in the namespace of Elasticsearch, and
+// the label identifying the Pods of the associated resource.
+var associations = []struct {
+	nameLabel      string
+	namespaceLabel string
+	podLabel       string
+}{
+	{nameLabel: "agentassociation.k8s.elastic.co/name", namespaceLabel: "agentassociation.k8s.elastic.co/namespace", podLabel: "agent.k8s.elastic.co/name"},
+	{nameLabel: "apmassociation.k8s.elastic.co/name", namespaceLabel: "apmassociation.k8s.elastic.co/namespace", podLabel: "apm.k8s.elastic.co/name"},
+	{nameLabel: "beatassociation.k8s.elastic.co/name", namespaceLabel: "beatassociation.k8s.elastic.co/namespace", podLabel: "beat.k8s.elastic.co/name"},
+	{nameLabel: "entassociation.k8s.elastic.co/name", namespaceLabel: "entassociation.k8s.elastic.co/namespace", podLabel: "enterprisesearch.k8s.elastic.co/name"},
+	{nameLabel: "esassociation.k8s.elastic.co/name", namespaceLabel: "esassociation.k8s.elastic.co/namespace", podLabel: label.ClusterNameLabelName},
+	{nameLabel: "kibanaassociation.k8s.elastic.co/name", namespaceLabel: "kibanaassociation.k8s.elastic.co/namespace", podLabel: "kibana.k8s.elastic.co/name"},
+	{nameLabel: "logstashassociation.k8s.elastic.co/name", namespaceLabel: "logstashassociation.k8s.elastic.co/namespace", podLabel: "logstash.k8s.elastic.co/name"},
+	{nameLabel: "mapsassociation.k8s.elastic.co/name", namespaceLabel: "mapsassociation.k8s.elastic.co/namespace", podLabel: "maps.k8s.elastic.co/name"},
+	{nameLabel: "otelassociation.k8s.elastic.co/name", namespaceLabel: "otelassociation.k8s.elastic.co/namespace", podLabel: "otel.k8s.elastic.co/name"},
+}
+
+// Reconcile ensures the NetworkPolicy of the Elasticsearch Pods, and the CiliumNetworkPolicy if requested, match the
+// specification and the current associations, or that they do not exist if they are not enabled.
+func Reconcile(ctx context.Context, c k8s.Client, es esv1.Elasticsearch, operatorNamespace string) error {
+	if !es.NetworkPolicyEnabled() {
+		return deleteNetworkPolicy(ctx, c, es)
+	}
+	peers, err := getPeers(ctx, c, es, operatorNamespace)
+	if err != nil {
+		return err
+	}
+	expected, err := expectedNetworkPolicy(es, peers)
+	if err != nil {
+		return err
+	}
+	reconciled := &networkingv1.NetworkPolicy{}
+	if err := reconciler.ReconcileResource(
+		reconciler.Params{
+			Context:    ctx,
+			Client:     c,
+			Owner:      &es,
+			Expected:   expected,
+			Reconciled: reconciled,
+			NeedsUpdate: func() bool {
+				return hash.GetTemplateHashLabel(expected.Labels) != hash.GetTemplateHashLabel(reconciled.Labels)
+			},
+			UpdateReconciled: func() {
+				expected.DeepCopyInto(reconciled)
+			},
+		},
+	); err != nil {
+		return err
+	}
+
+	if !es.CiliumNetworkPolicyEnabled() {
+		return deleteCiliumNetworkPolicy(ctx, c, es)
+	}
+	return reconcileCiliumNetworkPolicy(ctx, c, es)
+}
+
+// deleteNetworkPolicy deletes the NetworkPolicy and the CiliumNetworkPolicy of the Elasticsearch Pods if they exist.
+func deleteNetworkPolicy(ctx context.Context, c k8s.Client, es esv1.Elasticsearch) error {
+	var policy networkingv1.NetworkPolicy
+	// get first from the cache to avoid a Delete call to the API server if the policy does not exist
+	if err := c.Get(ctx, types.NamespacedName{Namespace: es.Namespace, Name: esv1.NetworkPolicy(es.Name)}, &policy); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !metav1.IsControlledBy(&policy, &es) {
+		// do not delete a NetworkPolicy created by the user with the same name
+		return nil
+	}
+	// the CiliumNetworkPolicy is only looked up if the NetworkPolicy exists, so that the Cilium API is not accessed
+	// if network policies are not used
+	if err := deleteCiliumNetworkPolicy(ctx, c, es); err != nil {
+		return err
+	}
+	if err := c.Delete(ctx, &policy); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// peers are the Pods Elasticsearch communicates with, in addition to the Pods of the cluster.
+type peers struct {
+	// operatorNamespace is the namespace of the operator which calls the Elasticsearch API.
+	operatorNamespace string
+	// remoteClusters are the Elasticsearch clusters connected through the transport or the remote cluster protocol,
+	// in both directions.
+	remoteClusters []types.NamespacedName
+	// clients are the selectors of the Pods of the associated resources which call the Elasticsearch API.
+	clients []podSelector
+	// monitoringClusters are the Elasticsearch clusters receiving the monitoring data of the stack monitoring sidecars.
+	monitoringClusters []types.NamespacedName
+}
+
+// podSelector selects the Pods with a given label value in a namespace.
+type podSelector struct {
+	namespace string
+	label     string
+	value     string
+}
+
+// getPeers returns the peers of the Elasticsearch Pods from the Secrets created by the association and remote cluster
+// controllers in the namespace of Elasticsearch, and from the stack monitoring specification.
+func getPeers(ctx context.Context, c k8s.Client, es esv1.Elasticsearch, operatorNamespace string) (peers, error) {
+	result := peers{operatorNamespace: operatorNamespace}
+
+	var remoteCASecrets corev1.SecretList
+	if err := c.List(ctx, &remoteCASecrets, client.InNamespace(es.Namespace), remoteca.Labels(es.Name)); err != nil {
+		return peers{}, err
+	}
+	for _, secret := range remoteCASecrets.Items {
+		name, namespace := secret.Labels[remoteClusterNameLabelName], secret.Labels[remoteClusterNamespaceLabelName]
+		if name == "" || namespace == "" {
+			continue
+		}
+		result.remoteClusters = append(result.remoteClusters, types.NamespacedName{Namespace: namespace, Name: name})
+	}
+
+	clients := map[podSelector]struct{}{}
+	for _, secretType := range []string{esuser.AssociatedUserType, esuser.ServiceAccountTokenType} {
+		var secrets corev1.SecretList
+		if err := c.List(ctx, &secrets, client.InNamespace(es.Namespace), client.MatchingLabels{
+			label.ClusterNameLabelName: es.Name,
+			commonv1.TypeLabelName:     secretType,
+		}); err != nil {
+			return peers{}, err
+		}
+		for _, secret := range secrets.Items {
+			for _, association := range associations {
+				name, namespace := secret.Labels[association.nameLabel], secret.Labels[association.namespaceLabel]
+				if name == "" || namespace == "" {
+					continue
+				}
+				clients[podSelector{namespace: namespace, label: association.podLabel, value: name}] = struct{}{}
+			}
+		}
+	}
+	for selector := range clients {
+		result.clients = append(result.clients, selector)
+	}
+
+	for _, refs := range [][]commonv1.ObjectSelector{es.Spec.Monitoring.Metrics.ElasticsearchRefs, es.Spec.Monitoring.Logs.ElasticsearchRefs} {
+		for _, ref := range refs {
+			if !ref.IsDefined() || ref.IsExternal() {
+				// external clusters cannot be selected by a NetworkPolicy
+				continue
+			}
+			cluster := ref.WithDefaultNamespace(es.Namespace).NamespacedName()
+			if !slices.Contains(result.monitoringClusters, cluster) {
+				result.monitoringClusters = append(result.monitoringClusters, cluster)
+			}
+		}
+	}
+
+	result.sort()
+	return result, nil
+}
+
+// sort sorts the peers so that the NetworkPolicy does not change if the order of the Secrets changes.
+func (p *peers) sort() {
+	sort.Slice(p.remoteClusters, func(i, j int) bool {
+		return p.remoteClusters[i].String() < p.remoteClusters[j].String()
+	})
+	sort.Slice(p.monitoringClusters, func(i, j int) bool {
+		return p.monitoringClusters[i].String() < p.monitoringClusters[j].String()
+	})
+	sort.Slice(p.clients, func(i, j int) bool {
+		if p.clients[i].namespace != p.clients[j].namespace {
+			return p.clients[i].namespace < p.clients[j].namespace
+		}
+		if p.clients[i].label != p.clients[j].label {
+			return p.clients[i].label < p.clients[j].label
+		}
+		return p.clients[i].value < p.clients[j].value
+	})
+}
+
+// expectedNetworkPolicy returns the NetworkPolicy of the Elasticsearch Pods, allowing only the traffic with the given peers.
+func expectedNetworkPolicy(es esv1.Elasticsearch, p peers) (*networkingv1.NetworkPolicy, error) {
+	clusterPods := networkingv1.NetworkPolicyPeer{
+		PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{label.ClusterNameLabelName: es.Name}},
+	}
+	transportPeers := []networkingv1.NetworkPolicyPeer{clusterPods}
+	for _, remoteCluster := range p.remoteClusters {
+		transportPeers = append(transportPeers, namespacedPeer(remoteCluster.Namespace, label.ClusterNameLabelName, remoteCluster.Name))
+	}
+	ingressTransportPorts := ports(network.TransportPort)
+	if es.Spec.RemoteClusterServer.Enabled {
+		// remote clusters connect through the remote cluster server when using API keys
+		ingressTransportPorts = ports(network.TransportPort, network.RemoteClusterPort)
+	}
+	egressTransportPorts := ports(network.TransportPort)
+	if len(p.remoteClusters) > 0 {
+		egressTransportPorts = ports(network.TransportPort, network.RemoteClusterPort)
+	}
+
+	httpClients := []networkingv1.NetworkPolicyPeer{{
+		NamespaceSelector: namespaceSelector(p.operatorNamespace),
+	}}
+	for _, c := range p.clients {
+		httpClients = append(httpClients, namespacedPeer(c.namespace, c.label, c.value))
+	}
+
+	egress := []networkingv1.NetworkPolicyEgressRule{
+		{
+			Ports: []networkingv1.NetworkPolicyPort{
+				{Protocol: ptr.To(corev1.ProtocolUDP), Port: ptr.To(intstr.FromInt32(dnsPort))},
+				{Protocol: ptr.To(corev1.ProtocolTCP), Port: ptr.To(intstr.FromInt32(dnsPort))},
+			},
+		},
+		{
+			To:    transportPeers,
+			Ports: egressTransportPorts,
+		},
+	}
+	if len(p.monitoringClusters) > 0 {
+		monitoringPeers := make([]networkingv1.NetworkPolicyPeer, 0, len(p.monitoringClusters))
+		for _, cluster := range p.monitoringClusters {
+			monitoringPeers = append(monitoringPeers, namespacedPeer(cluster.Namespace, label.ClusterNameLabelName, cluster.Name))
+		}
+		egress = append(egress, networkingv1.NetworkPolicyEgressRule{
+			To:    monitoringPeers,
+			Ports: ports(network.HTTPPort),
+		})
+	}
+
+	expected := networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      esv1.NetworkPolicy(es.Name),
+			Namespace: es.Namespace,
+			Labels:    label.NewLabels(k8s.ExtractNamespacedName(&es)),
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: *clusterPods.PodSelector,
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{
+					From:  transportPeers,
+					Ports: ingressTransportPorts,
+				},
+				{
+					From:  httpClients,
+					Ports: ports(network.HTTPPort),
+				},
+			},
+			Egress:      egress,
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+		},
+	}
+	// label the NetworkPolicy with a hash of its content, for comparison purposes
+	expected.Labels = hash.SetTemplateHashLabel(expected.Labels, expected.Spec)
+	// set owner reference for deletion upon ES resource deletion
+	if err := controllerutil.SetControllerReference(&es, &expected, scheme.Scheme); err != nil {
+		return nil, err
+	}
+	return &expected, nil
+}
+
+// namespacedPeer selects the Pods with the given label value in the given namespace.
+func namespacedPeer(namespace, labelName, labelValue string) networkingv1.NetworkPolicyPeer {
+	return networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: namespaceSelector(namespace),
+		PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{labelName: labelValue}},
+	}
+}
+
+// namespaceSelector selects a namespace by name, relying on the label set by Kubernetes on all namespaces.
+func namespaceSelector(namespace string) *metav1.LabelSelector {
+	return &metav1.LabelSelector{MatchLabels: map[string]string{corev1.LabelMetadataName: namespace}}
+}
+
+func ports(ports ...int32) []networkingv1.NetworkPolicyPort {
+	result := make([]networkingv1.NetworkPolicyPort, 0, len(ports))
+	for _, port := range ports {
+		result = append(result, networkingv1.NetworkPolicyPort{
+			Protocol: ptr.To(corev1.ProtocolTCP),
+			Port:     ptr.To(intstr.FromInt32(port)),
+		})
+	}
+	return result
+}
@@ -0,0 +1,212 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package networkpolicy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/certificates/remoteca"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	esuser "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/user"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
+)
+
+func newES(enabled bool) esv1.Elasticsearch {
+	return esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: "ns", UID: "uid"},
+		Spec: esv1.ElasticsearchSpec{
+			NetworkPolicy: &esv1.NetworkPolicySpec{Enabled: enabled},
+		},
+	}
+}
+
+func associationSecret(name, secretType string, associationLabels map[string]string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "ns",
+			Labels: maps.Merge(map[string]string{
+				label.ClusterNameLabelName: "es",
+				commonv1.TypeLabelName:     secretType,
+			}, associationLabels),
+		},
+	}
+}
+
+func remoteCASecret(remoteNamespace, remoteName string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "es-" + remoteNamespace + "-" + remoteName + "-remote-ca",
+			Namespace: "ns",
+			Labels: maps.Merge(map[string]string{
+				remoteClusterNamespaceLabelName: remoteNamespace,
+				remoteClusterNameLabelName:      remoteName,
+			}, remoteca.Labels("es")),
+		},
+	}
+}
+
+func tcpPorts(ports ...int) []networkingv1.NetworkPolicyPort {
+	result := make([]networkingv1.NetworkPolicyPort, 0, len(ports))
+	for _, port := range ports {
+		result = append(result, networkingv1.NetworkPolicyPort{Protocol: ptr.To(corev1.ProtocolTCP), Port: ptr.To(intstr.FromInt(port))})
+	}
+	return result
+}
+
+func peer(namespace, labelName, labelValue string) networkingv1.NetworkPolicyPeer {
+	return networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{corev1.LabelMetadataName: namespace}},
+		PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{labelName: labelValue}},
+	}
+}
+
+var (
+	clusterPeer = networkingv1.NetworkPolicyPeer{
+		PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{label.ClusterNameLabelName: "es"}},
+	}
+	operatorPeer = networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{corev1.LabelMetadataName: "elastic-system"}},
+	}
+	dnsRule = networkingv1.NetworkPolicyEgressRule{
+		Ports: []networkingv1.NetworkPolicyPort{
+			{Protocol: ptr.To(corev1.ProtocolUDP), Port: ptr.To(intstr.FromInt(53))},
+			{Protocol: ptr.To(corev1.ProtocolTCP), Port: ptr.To(intstr.FromInt(53))},
+		},
+	}
+)
+
+func TestReconcile(t *testing.T) {
+	tests := []struct {
+		name       string
+		es         func() esv1.Elasticsearch
+		existing   []client.Object
+		wantPolicy *networkingv1.NetworkPolicySpec
+	}{
+		{
+			name:       "disabled: no NetworkPolicy",
+			es:         func() esv1.Elasticsearch { return newES(false) },
+			wantPolicy: nil,
+		},
+		{
+			name: "enabled: only the Pods of the cluster and the operator",
+			es:   func() esv1.Elasticsearch { return newES(true) },
+			wantPolicy: &networkingv1.NetworkPolicySpec{
+				PodSelector: *clusterPeer.PodSelector,
+				Ingress: []networkingv1.NetworkPolicyIngressRule{
+					{From: []networkingv1.NetworkPolicyPeer{clusterPeer}, Ports: tcpPorts(9300)},
+					{From: []networkingv1.NetworkPolicyPeer{operatorPeer}, Ports: tcpPorts(9200)},
+				},
+				Egress: []networkingv1.NetworkPolicyEgressRule{
+					dnsRule,
+					{To: []networkingv1.NetworkPolicyPeer{clusterPeer}, Ports: tcpPorts(9300)},
+				},
+				PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+			},
+		},
+		{
+			name: "enabled: associations, remote clusters and monitoring clusters",
+			es: func() esv1.Elasticsearch {
+				es := newES(true)
+				es.Spec.RemoteClusterServer.Enabled = true
+				es.Spec.Monitoring.Metrics.ElasticsearchRefs = []commonv1.ObjectSelector{{Name: "monitoring", Namespace: "observability"}, {SecretName: "external"}}
+				es.Spec.Monitoring.Logs.ElasticsearchRefs = []commonv1.ObjectSelector{{Name: "monitoring", Namespace: "observability"}}
+				return es
+			},
+			existing: []client.Object{
+				associationSecret("kb-user", esuser.ServiceAccountTokenType, map[string]string{
+					"kibanaassociation.k8s.elastic.co/name":      "kb",
+					"kibanaassociation.k8s.elastic.co/namespace": "ns",
+				}),
+				associationSecret("beat-user", esuser.AssociatedUserType, map[string]string{
+					"beatassociation.k8s.elastic.co/name":      "metricbeat",
+					"beatassociation.k8s.elastic.co/namespace": "beats",
+				}),
+				remoteCASecret("other-ns", "other-es"),
+			},
+			wantPolicy: &networkingv1.NetworkPolicySpec{
+				PodSelector: *clusterPeer.PodSelector,
+				Ingress: []networkingv1.NetworkPolicyIngressRule{
+					{
+						From:  []networkingv1.NetworkPolicyPeer{clusterPeer, peer("other-ns", label.ClusterNameLabelName, "other-es")},
+						Ports: tcpPorts(9300, 9443),
+					},
+					{
+						From: []networkingv1.NetworkPolicyPeer{
+							operatorPeer,
+							peer("beats", "beat.k8s.elastic.co/name", "metricbeat"),
+							peer("ns", "kibana.k8s.elastic.co/name", "kb"),
+						},
+						Ports: tcpPorts(9200),
+					},
+				},
+				Egress: []networkingv1.NetworkPolicyEgressRule{
+					dnsRule,
+					{
+						To:    []networkingv1.NetworkPolicyPeer{clusterPeer, peer("other-ns", label.ClusterNameLabelName, "other-es")},
+						Ports: tcpPorts(9300, 9443),
+					},
+					{
+						To:    []networkingv1.NetworkPolicyPeer{peer("observability", label.ClusterNameLabelName, "monitoring")},
+						Ports: tcpPorts(9200),
+					},
+				},
+				PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+			},
+		},
+		{
+			name: "disabled: delete the existing NetworkPolicy",
+			es:   func() esv1.Elasticsearch { return newES(false) },
+			existing: []client.Object{
+				func() client.Object {
+					es := newES(false)
+					policy := &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: "es-es-network-policy", Namespace: "ns"}}
+					require.NoError(t, controllerutil.SetControllerReference(&es, policy, scheme.Scheme))
+					return policy
+				}(),
+			},
+			wantPolicy: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := tt.es()
+			c := k8s.NewFakeClient(append(tt.existing, &es)...)
+			require.NoError(t, Reconcile(context.Background(), c, es, "elastic-system"))
+
+			var policy networkingv1.NetworkPolicy
+			err := c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: esv1.NetworkPolicy("es")}, &policy)
+			if tt.wantPolicy == nil {
+				require.True(t, apierrors.IsNotFound(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, *tt.wantPolicy, policy.Spec)
+			require.True(t, metav1.IsControlledBy(&policy, &es))
+
+			// reconciling again with the same associations does not update the NetworkPolicy
+			require.NoError(t, Reconcile(context.Background(), c, es, "elastic-system"))
+			var updated networkingv1.NetworkPolicy
+			require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: esv1.NetworkPolicy("es")}, &updated))
+			require.Equal(t, policy.ResourceVersion, updated.ResourceVersion)
+		})
+	}
+}
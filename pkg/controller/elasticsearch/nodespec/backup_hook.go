@@ -0,0 +1,75 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package nodespec
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"path/filepath"
+	"text/template"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/user"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
+)
+
+const (
+	// PreBackupHookScriptConfigKey is the key of the Velero pre-backup hook script in the scripts ConfigMap.
+	PreBackupHookScriptConfigKey = "pre-backup-hook-script.sh"
+
+	// Velero backup hook annotations, see https://velero.io/docs/main/backup-hooks/.
+	veleroPreBackupHookContainerAnnotation = "pre.hook.backup.velero.io/container"
+	veleroPreBackupHookCommandAnnotation   = "pre.hook.backup.velero.io/command"
+	veleroPreBackupHookOnErrorAnnotation   = "pre.hook.backup.velero.io/on-error"
+	veleroPreBackupHookTimeoutAnnotation   = "pre.hook.backup.velero.io/timeout"
+
+	// preBackupHookTimeout is the maximum duration of the pre-backup hook, before Velero proceeds with the backup.
+	preBackupHookTimeout = "2m"
+)
+
+var preBackupHookScriptTemplate = template.Must(template.New("pre-backup").Parse(`#!/usr/bin/env bash
+
+set -euo pipefail
+
+# This script is run by Velero in the Elasticsearch container before taking a snapshot of the volumes of the Pod.
+# It flushes all the indices, so that the data is persisted in the Lucene segments and does not depend on the
+# replay of the translog when the cluster is restored from the snapshot of its volumes.
+
+if [ ! -f "{{.PreStopUserPasswordPath}}" ]; then
+  echo "no API credentials available, cannot flush the indices" >&2
+  exit 1
+fi
+PASSWORD=$(<"{{.PreStopUserPasswordPath}}")
+
+curl -k -sS --fail -u "{{.PreStopUserName}}:${PASSWORD}" -X POST "{{.ServiceURL}}/_flush?wait_if_ongoing=true"
+`))
+
+// RenderPreBackupHookScript renders the Velero pre-backup hook script, which uses the credentials of the pre-stop user.
+func RenderPreBackupHookScript(svcURL string) (string, error) {
+	vars := map[string]string{
+		"PreStopUserName":         user.PreStopUserName,
+		"PreStopUserPasswordPath": filepath.Join(volume.PodMountedUsersSecretMountPath, user.PreStopUserName),
+		"ServiceURL":              svcURL,
+	}
+	var script bytes.Buffer
+	err := preBackupHookScriptTemplate.Execute(&script, vars)
+	return script.String(), err
+}
+
+// backupHookAnnotations returns the annotations requesting Velero to run the pre-backup hook script before taking a
+// snapshot of the volumes of the Pods. A failure of the script does not fail the backup, the volumes are still
+// consistent but the translog must be replayed on restore.
+func backupHookAnnotations(es esv1.Elasticsearch) map[string]string {
+	if !es.HasVeleroBackupHooks() {
+		return nil
+	}
+	return map[string]string{
+		veleroPreBackupHookContainerAnnotation: esv1.ElasticsearchContainerName,
+		veleroPreBackupHookCommandAnnotation:   fmt.Sprintf(`["bash", "-c", "%s"]`, path.Join(volume.ScriptsVolumeMountPath, PreBackupHookScriptConfigKey)),
+		veleroPreBackupHookOnErrorAnnotation:   "Continue",
+		veleroPreBackupHookTimeoutAnnotation:   preBackupHookTimeout,
+	}
+}
@@ -5,6 +5,7 @@
 package nodespec
 
 import (
+	"fmt"
 	"path"
 
 	corev1 "k8s.io/api/core/v1"
@@ -12,6 +13,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
@@ -70,6 +72,77 @@ func DefaultEnvVars(v version.Version, httpCfg commonv1.HTTPConfig, headlessServ
 	return defaults.ExtendPodDownwardEnvVars(vars...)
 }
 
+// publishIPFamilyEnvVars returns the environment variables needed to publish an address of the given IP family among
+// all the Pod IPs, in dual-stack environments.
+func publishIPFamilyEnvVars(publishIPFamily corev1.IPFamily) []corev1.EnvVar {
+	if publishIPFamily == "" {
+		return nil
+	}
+	return []corev1.EnvVar{
+		{Name: settings.EnvPodIPs, Value: "", ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{APIVersion: "v1", FieldPath: "status.podIPs"},
+		}},
+	}
+}
+
+// zoneAwarenessEnvVars returns the environment variable holding the zone of the Kubernetes node of the Pod, read from
+// the node label copied into the Pod annotations by the operator, if zone awareness is enabled.
+func zoneAwarenessEnvVars(es esv1.Elasticsearch) []corev1.EnvVar {
+	if !es.Spec.ZoneAwareness.IsEnabled() {
+		return nil
+	}
+	return []corev1.EnvVar{
+		{Name: settings.EnvZone, Value: "", ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{
+				APIVersion: "v1",
+				FieldPath:  fmt.Sprintf("metadata.annotations['%s']", es.Spec.ZoneAwareness.TopologyKeyOrDefault()),
+			},
+		}},
+	}
+}
+
+// defaultAffinity returns the default affinity for the Pods of the given cluster, or nil if a topology spread policy
+// replaces it.
+func defaultAffinity(es esv1.Elasticsearch) *corev1.Affinity {
+	if es.Spec.TopologySpread != nil {
+		return nil
+	}
+	return DefaultAffinity(es.Name)
+}
+
+// defaultTopologySpreadConstraints returns the topology spread constraints of the Pods of the given NodeSet, following
+// the topology spread policy of the cluster if any.
+func defaultTopologySpreadConstraints(es esv1.Elasticsearch, nodeSetName string) []corev1.TopologySpreadConstraint {
+	policy := es.Spec.TopologySpread
+	if policy == nil {
+		return nil
+	}
+	whenUnsatisfiable := corev1.ScheduleAnyway
+	var minDomains *int32
+	if policy.IsHard() {
+		whenUnsatisfiable = corev1.DoNotSchedule
+		// minDomains is only supported by the scheduler along with DoNotSchedule
+		minDomains = policy.MinDomains
+	}
+	keys := policy.TopologyKeysOrDefault()
+	constraints := make([]corev1.TopologySpreadConstraint, 0, len(keys))
+	for _, key := range keys {
+		constraints = append(constraints, corev1.TopologySpreadConstraint{
+			MaxSkew:           1,
+			TopologyKey:       key,
+			WhenUnsatisfiable: whenUnsatisfiable,
+			MinDomains:        minDomains,
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					label.ClusterNameLabelName:     es.Name,
+					label.StatefulSetNameLabelName: esv1.StatefulSet(es.Name, nodeSetName),
+				},
+			},
+		})
+	}
+	return constraints
+}
+
 // DefaultAffinity returns the default affinity for pods in a cluster.
 func DefaultAffinity(esName string) *corev1.Affinity {
 	return &corev1.Affinity{
@@ -21,6 +21,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/restart"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/initcontainer"
@@ -37,6 +38,7 @@ import (
 const (
 	defaultFsGroup                    = 1000
 	log4j2FormatMsgNoLookupsParamName = "-Dlog4j2.formatMsgNoLookups"
+	preferIPv6AddressesParamName      = "-Djava.net.preferIPv6Addresses"
 	// ConfigHashAnnotationName is an annotation used to store a hash of the Elasticsearch configuration.
 	configHashAnnotationName = "elasticsearch.k8s.elastic.co/config-hash"
 )
@@ -60,14 +62,19 @@ func BuildPodTemplateSpec(
 	keystoreResources *keystore.Resources,
 	setDefaultSecurityContext bool,
 	policyConfig PolicyConfig,
+	chunkedTransportCertificates bool,
 ) (corev1.PodTemplateSpec, error) {
 	ver, err := version.Parse(es.Spec.Version)
 	if err != nil {
 		return corev1.PodTemplateSpec{}, err
 	}
+	if err := container.CheckImageCatalog(es.Spec.Image, container.ElasticsearchImage, ver); err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
 
 	downwardAPIVolume := volume.DownwardAPI{}.WithAnnotations(es.HasDownwardNodeLabels())
-	volumes, volumeMounts := buildVolumes(es.Name, ver, nodeSet, keystoreResources, downwardAPIVolume, policyConfig.AdditionalVolumes)
+	transportCertificatesVolume := transportCertificatesVolume(esv1.StatefulSet(es.Name, nodeSet.Name), chunkedTransportCertificates)
+	volumes, volumeMounts := buildVolumes(es.Name, ver, nodeSet, es.Spec.Auth.Realms, keystoreResources, downwardAPIVolume, policyConfig.AdditionalVolumes, transportCertificatesVolume)
 
 	labels, err := buildLabels(es, cfg, nodeSet)
 	if err != nil {
@@ -78,9 +85,10 @@ func BuildPodTemplateSpec(
 
 	// now build the initContainers using the effective main container resources as an input
 	initContainers, err := initcontainer.NewInitContainers(
-		transportCertificatesVolume(esv1.StatefulSet(es.Name, nodeSet.Name)),
+		transportCertificatesVolume,
 		keystoreResources,
 		es.DownwardNodeLabels(),
+		es.Spec.Plugins,
 	)
 	if err != nil {
 		return corev1.PodTemplateSpec{}, err
@@ -123,8 +131,11 @@ func BuildPodTemplateSpec(
 		WithTerminationGracePeriod(DefaultTerminationGracePeriodSeconds).
 		WithPorts(defaultContainerPorts).
 		WithReadinessProbe(*NewReadinessProbe(ver)).
-		WithAffinity(DefaultAffinity(es.Name)).
+		WithAffinity(defaultAffinity(es)).
+		WithTopologySpreadConstraints(defaultTopologySpreadConstraints(es, nodeSet.Name)...).
 		WithEnv(DefaultEnvVars(ver, es.Spec.HTTP, headlessServiceName)...).
+		WithEnv(publishIPFamilyEnvVars(es.Spec.Transport.PublishIPFamily)...).
+		WithEnv(zoneAwarenessEnvVars(es)...).
 		WithVolumes(volumes...).
 		WithVolumeMounts(volumeMounts...).
 		WithInitContainers(initContainers...).
@@ -144,7 +155,15 @@ func BuildPodTemplateSpec(
 		enableLog4JFormatMsgNoLookups(builder)
 	}
 
-	return builder.PodTemplate, nil
+	if es.Spec.Transport.PublishIPFamily == corev1.IPv6Protocol {
+		// Elasticsearch publishes the IPv6 address among all the Pod IPs
+		prependESJavaOpt(builder, preferIPv6AddressesParamName, "true")
+	}
+
+	// the transport and remote cluster layers are encrypted by Elasticsearch and must bypass the service mesh
+	builder = builder.WithServiceMesh(es.Spec.HTTP.ServiceMesh, network.TransportPort, network.RemoteClusterPort)
+
+	return builder.WithPodMutationPolicy(esv1.Kind, es.Namespace).PodTemplate, nil
 }
 
 func getDefaultContainerPorts(es esv1.Elasticsearch) []corev1.ContainerPort {
@@ -154,7 +173,16 @@ func getDefaultContainerPorts(es esv1.Elasticsearch) []corev1.ContainerPort {
 	}
 }
 
-func transportCertificatesVolume(ssetName string) volume.SecretVolume {
+// transportCertificatesVolume returns the volume holding the transport certificates of the Pods of the given
+// StatefulSet, either spread across several Secrets or held by a single one.
+func transportCertificatesVolume(ssetName string, chunked bool) volume.VolumeLike {
+	if chunked {
+		return volume.NewProjectedSecretsVolume(
+			esv1.StatefulSetTransportCertificatesSecretChunks(ssetName),
+			esvolume.TransportCertificatesSecretVolumeName,
+			esvolume.TransportCertificatesSecretVolumeMountPath,
+		)
+	}
 	return volume.NewSecretVolumeWithMountPath(
 		esv1.StatefulSetTransportCertificatesSecret(ssetName),
 		esvolume.TransportCertificatesSecretVolumeName,
@@ -216,6 +244,11 @@ func buildAnnotations(
 		_, _ = configHash.Write([]byte(keystoreResources.Hash))
 	}
 
+	if plugins := es.Spec.PluginsToInstall(); len(plugins) > 0 {
+		// list of plugins to rotate the pod when plugins are added, removed or installed from a different source
+		hash.WriteHashObject(configHash, plugins)
+	}
+
 	if !es.Spec.Transport.TLS.SelfSignedEnabled() {
 		annotations[esv1.TransportCertDisabledAnnotationName] = "true"
 	}
@@ -223,6 +256,12 @@ func buildAnnotations(
 	// set the annotation in place
 	annotations[configHashAnnotationName] = fmt.Sprint(configHash.Sum32())
 
+	// set the start time of the last restart requested by the user, to restart the Pods when a new one is requested
+	maps.Merge(annotations, restart.PodAnnotations(&es))
+
+	// set the Velero backup hooks, if requested
+	maps.Merge(annotations, backupHookAnnotations(es))
+
 	// set policy annotations
 	maps.Merge(annotations, policyAnnotations)
 
@@ -233,7 +272,13 @@ func buildAnnotations(
 // in order to mitigate the Log4Shell vulnerability CVE-2021-44228, if it is not yet defined by the user, for
 // versions of Elasticsearch before 7.2.0.
 func enableLog4JFormatMsgNoLookups(builder *defaults.PodTemplateBuilder) {
-	log4j2Param := fmt.Sprintf("%s=true", log4j2FormatMsgNoLookupsParamName)
+	prependESJavaOpt(builder, log4j2FormatMsgNoLookupsParamName, "true")
+}
+
+// prependESJavaOpt prepends the given JVM parameter to the environment variable `ES_JAVA_OPTS` of the Elasticsearch container,
+// if it is not yet defined by the user.
+func prependESJavaOpt(builder *defaults.PodTemplateBuilder, paramName, value string) {
+	param := fmt.Sprintf("%s=%s", paramName, value)
 	for c, esContainer := range builder.PodTemplate.Spec.Containers {
 		if esContainer.Name != esv1.ElasticsearchContainerName {
 			continue
@@ -244,14 +289,14 @@ func enableLog4JFormatMsgNoLookups(builder *defaults.PodTemplateBuilder) {
 				continue
 			}
 			currentJvmOpts = envVar.Value
-			if !strings.Contains(currentJvmOpts, log4j2FormatMsgNoLookupsParamName) {
-				builder.PodTemplate.Spec.Containers[c].Env[e].Value = log4j2Param + " " + currentJvmOpts
+			if !strings.Contains(currentJvmOpts, paramName) {
+				builder.PodTemplate.Spec.Containers[c].Env[e].Value = param + " " + currentJvmOpts
 			}
 		}
 		if currentJvmOpts == "" {
 			builder.PodTemplate.Spec.Containers[c].Env = append(
 				builder.PodTemplate.Spec.Containers[c].Env,
-				corev1.EnvVar{Name: settings.EnvEsJavaOpts, Value: log4j2Param},
+				corev1.EnvVar{Name: settings.EnvEsJavaOpts, Value: param},
 			)
 		}
 	}
@@ -27,6 +27,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/initcontainer"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/settings"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 )
@@ -37,6 +38,7 @@ type esSampleBuilder struct {
 	keystoreResources       *keystore.Resources
 	transportCertsDisabled  bool
 	version                 string
+	plugins                 *esv1.PluginsSpec
 }
 
 func newEsSampleBuilder() *esSampleBuilder {
@@ -55,6 +57,7 @@ func (esb *esSampleBuilder) build() esv1.Elasticsearch {
 		es.Spec.Version = esb.version
 	}
 	es.Spec.Transport.TLS.SelfSignedCertificates = &esv1.SelfSignedTransportCertificates{Disabled: esb.transportCertsDisabled}
+	es.Spec.Plugins = esb.plugins
 	return *es
 }
 
@@ -78,6 +81,11 @@ func (esb *esSampleBuilder) withKeystoreResources(keystoreResources *keystore.Re
 	return esb
 }
 
+func (esb *esSampleBuilder) withPlugins(plugins *esv1.PluginsSpec) *esSampleBuilder {
+	esb.plugins = plugins
+	return esb
+}
+
 func (esb *esSampleBuilder) withTransportCertsDisabled(disabled bool) *esSampleBuilder {
 	esb.transportCertsDisabled = disabled
 	return esb
@@ -225,11 +233,11 @@ func TestBuildPodTemplateSpecWithDefaultSecurityContext(t *testing.T) {
 			es.Spec.Version = tt.version.String()
 			es.Spec.NodeSets[0].PodTemplate.Spec.SecurityContext = tt.userSecurityContext
 
-			cfg, err := settings.NewMergedESConfig(es.Name, tt.version, corev1.IPv4Protocol, es.Spec.HTTP, *es.Spec.NodeSets[0].Config, nil, false, false)
+			cfg, err := settings.NewMergedESConfig(es.Name, tt.version, corev1.IPv4Protocol, "", es.Spec.HTTP, nil, *es.Spec.NodeSets[0].Config, nil, nil, nil, false, false)
 			require.NoError(t, err)
 
 			client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: es.Namespace, Name: esv1.ScriptsConfigMap(es.Name)}})
-			actual, err := BuildPodTemplateSpec(context.Background(), client, es, es.Spec.NodeSets[0], cfg, nil, tt.setDefaultFSGroup, PolicyConfig{})
+			actual, err := BuildPodTemplateSpec(context.Background(), client, es, es.Spec.NodeSets[0], cfg, nil, tt.setDefaultFSGroup, PolicyConfig{}, false)
 			require.NoError(t, err)
 			require.Equal(t, tt.wantSecurityContext, actual.Spec.SecurityContext)
 		})
@@ -308,10 +316,10 @@ func TestBuildPodTemplateSpec(t *testing.T) {
 			ver, err := version.Parse(es.Spec.Version)
 			require.NoError(t, err)
 
-			cfg, err := settings.NewMergedESConfig(es.Name, ver, corev1.IPv4Protocol, es.Spec.HTTP, *nodeSet.Config, tt.args.policyConfig.ElasticsearchConfig, false, false)
+			cfg, err := settings.NewMergedESConfig(es.Name, ver, corev1.IPv4Protocol, "", es.Spec.HTTP, nil, *nodeSet.Config, nil, nil, tt.args.policyConfig.ElasticsearchConfig, false, false)
 			require.NoError(t, err)
 
-			actual, err := BuildPodTemplateSpec(context.Background(), tt.args.client, es, es.Spec.NodeSets[0], cfg, tt.args.keystoreResources, tt.args.setDefaultSecurityContext, tt.args.policyConfig)
+			actual, err := BuildPodTemplateSpec(context.Background(), tt.args.client, es, es.Spec.NodeSets[0], cfg, tt.args.keystoreResources, tt.args.setDefaultSecurityContext, tt.args.policyConfig, false)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("BuildPodTemplateSpec wantErr %v got %v", tt.wantErr, err)
 			}
@@ -332,6 +340,7 @@ func Test_buildAnnotations(t *testing.T) {
 		scriptsContent         string
 		policyAnnotations      map[string]string
 		transportCertsDisabled bool
+		plugins                *esv1.PluginsSpec
 	}
 	tests := []struct {
 		name                string
@@ -412,6 +421,24 @@ func Test_buildAnnotations(t *testing.T) {
 				"elasticsearch.k8s.elastic.co/config-hash": "3413674748",
 			},
 		},
+		{
+			name: "With plugins",
+			args: args{
+				plugins: &esv1.PluginsSpec{Install: []esv1.Plugin{{Name: "analysis-icu"}}},
+			},
+			expectedAnnotations: map[string]string{
+				"elasticsearch.k8s.elastic.co/config-hash": "2867181205",
+			},
+		},
+		{
+			name: "With another plugin source",
+			args: args{
+				plugins: &esv1.PluginsSpec{Install: []esv1.Plugin{{Name: "analysis-icu", URL: "https://artifacts.example.com/analysis-icu-7.2.0.zip"}}},
+			},
+			expectedAnnotations: map[string]string{
+				"elasticsearch.k8s.elastic.co/config-hash": "2091280496",
+			},
+		},
 		{
 			name: "With policy annotations",
 			args: args{
@@ -433,6 +460,18 @@ func Test_buildAnnotations(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "With Velero backup hooks",
+			args: args{
+				esAnnotations: map[string]string{"eck.k8s.elastic.co/velero-backup-hooks": "true"},
+			},
+			expectedAnnotations: map[string]string{
+				"pre.hook.backup.velero.io/container": "elasticsearch",
+				"pre.hook.backup.velero.io/command":   `["bash", "-c", "/mnt/elastic-internal/scripts/pre-backup-hook-script.sh"]`,
+				"pre.hook.backup.velero.io/on-error":  "Continue",
+				"pre.hook.backup.velero.io/timeout":   "2m",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -441,10 +480,11 @@ func Test_buildAnnotations(t *testing.T) {
 				withUserConfig(tt.args.cfg).
 				addEsAnnotations(tt.args.esAnnotations).
 				withTransportCertsDisabled(tt.args.transportCertsDisabled).
+				withPlugins(tt.args.plugins).
 				build()
 			ver, err := version.Parse(sampleES.Spec.Version)
 			require.NoError(t, err)
-			cfg, err := settings.NewMergedESConfig(es.Name, ver, corev1.IPv4Protocol, es.Spec.HTTP, *es.Spec.NodeSets[0].Config, nil, false, false)
+			cfg, err := settings.NewMergedESConfig(es.Name, ver, corev1.IPv4Protocol, "", es.Spec.HTTP, nil, *es.Spec.NodeSets[0].Config, nil, nil, nil, false, false)
 			require.NoError(t, err)
 			got := buildAnnotations(es, cfg, tt.args.keystoreResources, tt.args.scriptsContent, tt.args.policyAnnotations)
 
@@ -541,10 +581,10 @@ func Test_enableLog4JFormatMsgNoLookups(t *testing.T) {
 
 			ver, err := version.Parse(sampleES.Spec.Version)
 			require.NoError(t, err)
-			cfg, err := settings.NewMergedESConfig(sampleES.Name, ver, corev1.IPv4Protocol, sampleES.Spec.HTTP, *sampleES.Spec.NodeSets[0].Config, nil, false, false)
+			cfg, err := settings.NewMergedESConfig(sampleES.Name, ver, corev1.IPv4Protocol, "", sampleES.Spec.HTTP, nil, *sampleES.Spec.NodeSets[0].Config, nil, nil, nil, false, false)
 			require.NoError(t, err)
 			client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: sampleES.Namespace, Name: esv1.ScriptsConfigMap(sampleES.Name)}})
-			actual, err := BuildPodTemplateSpec(context.Background(), client, sampleES, sampleES.Spec.NodeSets[0], cfg, nil, false, PolicyConfig{})
+			actual, err := BuildPodTemplateSpec(context.Background(), client, sampleES, sampleES.Spec.NodeSets[0], cfg, nil, false, PolicyConfig{}, false)
 			require.NoError(t, err)
 
 			env := actual.Spec.Containers[1].Env
@@ -570,3 +610,171 @@ func Test_getScriptsConfigMapContent(t *testing.T) {
 	}
 	assert.Equal(t, "value1#value2#value3#value4#", getScriptsConfigMapContent(cm))
 }
+
+func TestBuildPodTemplateSpec_PublishIPFamily(t *testing.T) {
+	tt := []struct {
+		name                       string
+		publishIPFamily            corev1.IPFamily
+		userEnv                    []corev1.EnvVar
+		expectPodIPs               bool
+		expectedEsJavaOptsEnvValue string
+	}{
+		{
+			name: "single-stack by default",
+		},
+		{
+			name:            "dual-stack preferring IPv4: all the Pod IPs are exposed",
+			publishIPFamily: corev1.IPv4Protocol,
+			expectPodIPs:    true,
+		},
+		{
+			name:                       "dual-stack preferring IPv6: the JVM prefers IPv6 addresses",
+			publishIPFamily:            corev1.IPv6Protocol,
+			userEnv:                    []corev1.EnvVar{{Name: "ES_JAVA_OPTS", Value: "-Xms=42000 -Xmx=42000"}},
+			expectPodIPs:               true,
+			expectedEsJavaOptsEnvValue: "-Djava.net.preferIPv6Addresses=true -Xms=42000 -Xmx=42000",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			sampleES := newEsSampleBuilder().build()
+			sampleES.Spec.Transport.PublishIPFamily = tc.publishIPFamily
+			sampleES.Spec.NodeSets[0].PodTemplate.Spec.Containers[1].Env = tc.userEnv
+
+			ver, err := version.Parse(sampleES.Spec.Version)
+			require.NoError(t, err)
+			cfg, err := settings.NewMergedESConfig(sampleES.Name, ver, corev1.IPv4Protocol, tc.publishIPFamily, sampleES.Spec.HTTP, nil, *sampleES.Spec.NodeSets[0].Config, nil, nil, nil, false, false)
+			require.NoError(t, err)
+			client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: sampleES.Namespace, Name: esv1.ScriptsConfigMap(sampleES.Name)}})
+			actual, err := BuildPodTemplateSpec(context.Background(), client, sampleES, sampleES.Spec.NodeSets[0], cfg, nil, false, PolicyConfig{}, false)
+			require.NoError(t, err)
+
+			var podIPs *corev1.EnvVar
+			esJavaOpts := ""
+			for _, e := range actual.Spec.Containers[1].Env {
+				switch e.Name {
+				case settings.EnvPodIPs:
+					podIPs = e.DeepCopy()
+				case settings.EnvEsJavaOpts:
+					esJavaOpts = e.Value
+				}
+			}
+			assert.Equal(t, tc.expectPodIPs, podIPs != nil)
+			if tc.expectPodIPs {
+				assert.Equal(t, "status.podIPs", podIPs.ValueFrom.FieldRef.FieldPath)
+			}
+			assert.Equal(t, tc.expectedEsJavaOptsEnvValue, esJavaOpts)
+		})
+	}
+}
+
+func TestBuildPodTemplateSpec_TopologySpread(t *testing.T) {
+	tt := []struct {
+		name                string
+		policy              *esv1.TopologySpreadPolicy
+		templateConstraints []corev1.TopologySpreadConstraint
+		expectAffinity      bool
+		expectConstraints   []corev1.TopologySpreadConstraint
+	}{
+		{
+			name:           "default anti-affinity without policy",
+			expectAffinity: true,
+		},
+		{
+			name:   "soft spread across hosts by default",
+			policy: &esv1.TopologySpreadPolicy{},
+			expectConstraints: []corev1.TopologySpreadConstraint{
+				{MaxSkew: 1, TopologyKey: esv1.HostTopologyKey, WhenUnsatisfiable: corev1.ScheduleAnyway},
+			},
+		},
+		{
+			name:   "hard spread across hosts and zones",
+			policy: &esv1.TopologySpreadPolicy{Enforcement: esv1.HardTopologySpread, TopologyKeys: []string{esv1.HostTopologyKey, esv1.ZoneTopologyKey}, MinDomains: ptr.To[int32](2)},
+			expectConstraints: []corev1.TopologySpreadConstraint{
+				{MaxSkew: 1, TopologyKey: esv1.HostTopologyKey, WhenUnsatisfiable: corev1.DoNotSchedule, MinDomains: ptr.To[int32](2)},
+				{MaxSkew: 1, TopologyKey: esv1.ZoneTopologyKey, WhenUnsatisfiable: corev1.DoNotSchedule, MinDomains: ptr.To[int32](2)},
+			},
+		},
+		{
+			name:                "constraints of the Pod template take precedence",
+			policy:              &esv1.TopologySpreadPolicy{Enforcement: esv1.HardTopologySpread},
+			templateConstraints: []corev1.TopologySpreadConstraint{{MaxSkew: 2, TopologyKey: "rack", WhenUnsatisfiable: corev1.ScheduleAnyway}},
+			expectConstraints:   []corev1.TopologySpreadConstraint{{MaxSkew: 2, TopologyKey: "rack", WhenUnsatisfiable: corev1.ScheduleAnyway}},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			sampleES := newEsSampleBuilder().build()
+			sampleES.Spec.TopologySpread = tc.policy
+			sampleES.Spec.NodeSets[0].PodTemplate.Spec.Affinity = nil
+			sampleES.Spec.NodeSets[0].PodTemplate.Spec.TopologySpreadConstraints = tc.templateConstraints
+			// the default constraints select the Pods of the NodeSet
+			for i := range tc.expectConstraints {
+				if tc.templateConstraints == nil {
+					tc.expectConstraints[i].LabelSelector = &metav1.LabelSelector{MatchLabels: map[string]string{
+						label.ClusterNameLabelName:     sampleES.Name,
+						label.StatefulSetNameLabelName: esv1.StatefulSet(sampleES.Name, sampleES.Spec.NodeSets[0].Name),
+					}}
+				}
+			}
+
+			ver, err := version.Parse(sampleES.Spec.Version)
+			require.NoError(t, err)
+			cfg, err := settings.NewMergedESConfig(sampleES.Name, ver, corev1.IPv4Protocol, "", sampleES.Spec.HTTP, nil, *sampleES.Spec.NodeSets[0].Config, nil, nil, nil, false, false)
+			require.NoError(t, err)
+			client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: sampleES.Namespace, Name: esv1.ScriptsConfigMap(sampleES.Name)}})
+			actual, err := BuildPodTemplateSpec(context.Background(), client, sampleES, sampleES.Spec.NodeSets[0], cfg, nil, false, PolicyConfig{}, false)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expectAffinity, actual.Spec.Affinity != nil)
+			assert.Equal(t, tc.expectConstraints, actual.Spec.TopologySpreadConstraints)
+		})
+	}
+}
+
+func TestBuildPodTemplateSpec_ZoneAwareness(t *testing.T) {
+	tt := []struct {
+		name              string
+		zoneAwareness     *esv1.ZoneAwareness
+		expectedFieldPath string
+	}{
+		{
+			name: "disabled by default",
+		},
+		{
+			name:              "zone read from the default topology label",
+			zoneAwareness:     &esv1.ZoneAwareness{Enabled: true},
+			expectedFieldPath: "metadata.annotations['topology.kubernetes.io/zone']",
+		},
+		{
+			name:              "zone read from a custom topology label",
+			zoneAwareness:     &esv1.ZoneAwareness{Enabled: true, TopologyKey: "example.com/zone"},
+			expectedFieldPath: "metadata.annotations['example.com/zone']",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			sampleES := newEsSampleBuilder().build()
+			sampleES.Spec.ZoneAwareness = tc.zoneAwareness
+
+			ver, err := version.Parse(sampleES.Spec.Version)
+			require.NoError(t, err)
+			cfg, err := settings.NewMergedESConfig(sampleES.Name, ver, corev1.IPv4Protocol, "", sampleES.Spec.HTTP, nil, *sampleES.Spec.NodeSets[0].Config, nil, tc.zoneAwareness, nil, false, false)
+			require.NoError(t, err)
+			client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: sampleES.Namespace, Name: esv1.ScriptsConfigMap(sampleES.Name)}})
+			actual, err := BuildPodTemplateSpec(context.Background(), client, sampleES, sampleES.Spec.NodeSets[0], cfg, nil, false, PolicyConfig{}, false)
+			require.NoError(t, err)
+
+			fieldPath := ""
+			for _, e := range actual.Spec.Containers[1].Env {
+				if e.Name == settings.EnvZone {
+					fieldPath = e.ValueFrom.FieldRef.FieldPath
+				}
+			}
+			assert.Equal(t, tc.expectedFieldPath, fieldPath)
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 
+	"golang.org/x/sync/errgroup"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 
@@ -62,8 +63,6 @@ func BuildExpectedResources(
 	ipFamily corev1.IPFamily,
 	setDefaultSecurityContext bool,
 ) (ResourcesList, error) {
-	nodesResources := make(ResourcesList, 0, len(es.Spec.NodeSets))
-
 	ver, err := version.Parse(es.Spec.Version)
 	if err != nil {
 		return nil, err
@@ -75,31 +74,45 @@ func BuildExpectedResources(
 		return nil, err
 	}
 
-	for _, nodeSpec := range es.Spec.NodeSets {
-		// build es config
-		userCfg := commonv1.Config{}
-		if nodeSpec.Config != nil {
-			userCfg = *nodeSpec.Config
-		}
-		cfg, err := settings.NewMergedESConfig(es.Name, ver, ipFamily, es.Spec.HTTP, userCfg, policyConfig.ElasticsearchConfig, es.Spec.RemoteClusterServer.Enabled, es.HasRemoteClusterAPIKey())
-		if err != nil {
-			return nil, err
-		}
+	// the resources of each nodeSet are built concurrently, but returned in the order of the nodeSets in the spec
+	nodesResources := make(ResourcesList, len(es.Spec.NodeSets))
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(es_sset.MaxConcurrentReconciles)
+	for i, nodeSpec := range es.Spec.NodeSets {
+		group.Go(func() error {
+			// don't build the resources of the remaining nodeSets if another one failed or the reconciliation was cancelled
+			if err := groupCtx.Err(); err != nil {
+				return err
+			}
+			// build es config
+			userCfg := commonv1.Config{}
+			if nodeSpec.Config != nil {
+				userCfg = *nodeSpec.Config
+			}
+			cfg, err := settings.NewMergedESConfig(es.Name, ver, ipFamily, es.Spec.Transport.PublishIPFamily, es.Spec.HTTP, es.Spec.Auth.Realms, userCfg, nodeSpec.Tuning, es.Spec.ZoneAwareness, policyConfig.ElasticsearchConfig, es.Spec.RemoteClusterServer.Enabled, es.HasRemoteClusterAPIKey())
+			if err != nil {
+				return err
+			}
 
-		// build stateful set and associated headless service
-		statefulSet, err := BuildStatefulSet(ctx, client, es, nodeSpec, cfg, keystoreResources, existingStatefulSets, setDefaultSecurityContext, policyConfig)
-		if err != nil {
-			return nil, err
-		}
-		headlessSvc := HeadlessService(&es, statefulSet.Name)
+			// build stateful set and associated headless service
+			statefulSet, err := BuildStatefulSet(groupCtx, client, es, nodeSpec, cfg, keystoreResources, existingStatefulSets, setDefaultSecurityContext, policyConfig)
+			if err != nil {
+				return err
+			}
+			headlessSvc := HeadlessService(&es, statefulSet.Name)
 
-		nodesResources = append(nodesResources, Resources{
-			NodeSet:         nodeSpec.Name,
-			StatefulSet:     statefulSet,
-			HeadlessService: headlessSvc,
-			Config:          cfg,
+			nodesResources[i] = Resources{
+				NodeSet:         nodeSpec.Name,
+				StatefulSet:     statefulSet,
+				HeadlessService: headlessSvc,
+				Config:          cfg,
+			}
+			return nil
 		})
 	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
 
 	return nodesResources, nil
 }
@@ -5,10 +5,19 @@
 package nodespec
 
 import (
+	"context"
+	"fmt"
 	"reflect"
 	"testing"
 
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/statefulset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 )
 
 func TestResourcesList_MasterNodesNames(t *testing.T) {
@@ -43,3 +52,62 @@ func TestResourcesList_MasterNodesNames(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildExpectedResources(t *testing.T) {
+	es := *sampleES.DeepCopy()
+	nodeSets := make([]esv1.NodeSet, 0, 10)
+	for i := 0; i < 10; i++ {
+		nodeSet := *sampleES.Spec.NodeSets[0].DeepCopy()
+		nodeSet.Name = fmt.Sprintf("nodeset-%d", i)
+		nodeSets = append(nodeSets, nodeSet)
+	}
+	es.Spec.NodeSets = nodeSets
+	client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: es.Namespace, Name: esv1.ScriptsConfigMap(es.Name)}})
+
+	resources, err := BuildExpectedResources(context.Background(), client, es, nil, nil, corev1.IPv4Protocol, false)
+	require.NoError(t, err)
+	// resources are built concurrently but returned in the order of the nodeSets
+	require.Len(t, resources, len(nodeSets))
+	for i, res := range resources {
+		require.Equal(t, nodeSets[i].Name, res.NodeSet)
+		require.Equal(t, esv1.StatefulSet(es.Name, nodeSets[i].Name), res.StatefulSet.Name)
+		require.Equal(t, esv1.StatefulSet(es.Name, nodeSets[i].Name), res.HeadlessService.Name)
+	}
+
+	// the resources are built again in the same order
+	again, err := BuildExpectedResources(context.Background(), client, es, nil, nil, corev1.IPv4Protocol, false)
+	require.NoError(t, err)
+	for i := range resources {
+		require.Equal(t, resources[i].NodeSet, again[i].NodeSet)
+		require.Equal(t, resources[i].StatefulSet.Name, again[i].StatefulSet.Name)
+	}
+}
+
+func TestBuildExpectedResources_Error(t *testing.T) {
+	es := *sampleES.DeepCopy()
+	nodeSets := make([]esv1.NodeSet, 0, 10)
+	for i := 0; i < 10; i++ {
+		nodeSet := *sampleES.Spec.NodeSets[0].DeepCopy()
+		nodeSet.Name = fmt.Sprintf("nodeset-%d", i)
+		nodeSets = append(nodeSets, nodeSet)
+	}
+	// the configuration of a single nodeSet is invalid
+	nodeSets[5].Config = &commonv1.Config{Data: map[string]any{"node": "a", "node.store": "b"}}
+	es.Spec.NodeSets = nodeSets
+	client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: es.Namespace, Name: esv1.ScriptsConfigMap(es.Name)}})
+
+	resources, err := BuildExpectedResources(context.Background(), client, es, nil, nil, corev1.IPv4Protocol, false)
+	require.ErrorContains(t, err, "in field 'node'")
+	require.Nil(t, resources)
+}
+
+func TestBuildExpectedResources_Cancelled(t *testing.T) {
+	es := *sampleES.DeepCopy()
+	client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: es.Namespace, Name: esv1.ScriptsConfigMap(es.Name)}})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	resources, err := BuildExpectedResources(ctx, client, es, nil, nil, corev1.IPv4Protocol, false)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Nil(t, resources)
+}
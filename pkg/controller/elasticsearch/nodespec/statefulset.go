@@ -84,15 +84,18 @@ func BuildStatefulSet(
 	// ssetSelector is used to match the sset pods
 	ssetSelector := label.NewStatefulSetLabels(k8s.ExtractNamespacedName(&es), statefulSetName)
 
-	// add default PVCs to the node spec only if no user defined PVCs exist
-	nodeSet.VolumeClaimTemplates = defaults.AppendDefaultPVCs(
-		nodeSet.VolumeClaimTemplates,
-		nodeSet.PodTemplate.Spec,
-		esvolume.DefaultVolumeClaimTemplates...,
-	)
+	// add default PVCs to the node spec only if no user defined PVCs exist and the data is meant to be persisted
+	if !nodeSet.Ephemeral {
+		nodeSet.VolumeClaimTemplates = defaults.AppendDefaultPVCs(
+			nodeSet.VolumeClaimTemplates,
+			nodeSet.PodTemplate.Spec,
+			esvolume.DefaultVolumeClaimTemplates...,
+		)
+	}
 
 	// build pod template
-	podTemplate, err := BuildPodTemplateSpec(ctx, client, es, nodeSet, cfg, keystoreResources, setDefaultSecurityContext, policyConfig)
+	chunkedTransportCertificates := HasChunkedTransportCertificates(es, existingStatefulSets, statefulSetName)
+	podTemplate, err := BuildPodTemplateSpec(ctx, client, es, nodeSet, cfg, keystoreResources, setDefaultSecurityContext, policyConfig, chunkedTransportCertificates)
 	if err != nil {
 		return appsv1.StatefulSet{}, err
 	}
@@ -174,3 +177,14 @@ func UpdateReplicas(statefulSet *appsv1.StatefulSet, replicas *int32) {
 	statefulSet.Spec.Replicas = replicas
 	statefulSet.Labels = hash.SetTemplateHashLabel(statefulSet.Labels, statefulSet.Spec)
 }
+
+// HasChunkedTransportCertificates returns true if the transport certificates of the Pods of the given StatefulSet are
+// spread across several Secrets. Existing StatefulSets mounting a single Secret keep doing so to avoid restarting their
+// Pods, unless the migration is requested through an annotation of the Elasticsearch resource.
+func HasChunkedTransportCertificates(es esv1.Elasticsearch, existingStatefulSets es_sset.StatefulSetList, statefulSetName string) bool {
+	existingSset, exists := existingStatefulSets.GetByName(statefulSetName)
+	if !exists || es.HasChunkedTransportCertificatesMigration() {
+		return true
+	}
+	return esvolume.HasChunkedTransportCertificates(existingSset.Spec.Template.Spec)
+}
@@ -8,11 +8,13 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	controllerscheme "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/scheme"
+	es_sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
 )
 
 func Test_setVolumeClaimsControllerReference(t *testing.T) {
@@ -133,3 +135,52 @@ func Test_setVolumeClaimsControllerReference(t *testing.T) {
 		})
 	}
 }
+
+func TestHasChunkedTransportCertificates(t *testing.T) {
+	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Name: "es1", Namespace: "default"}}
+	migratedES := *es.DeepCopy()
+	migratedES.Annotations = map[string]string{esv1.ChunkedTransportCertificatesAnnotation: "true"}
+	ssetWithTransportVolume := func(chunked bool) appsv1.StatefulSet {
+		return appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "es1-es-default", Namespace: "default"},
+			Spec: appsv1.StatefulSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Volumes: []corev1.Volume{transportCertificatesVolume("es1-es-default", chunked).Volume()},
+			}}},
+		}
+	}
+	tests := []struct {
+		name                 string
+		es                   esv1.Elasticsearch
+		existingStatefulSets es_sset.StatefulSetList
+		want                 bool
+	}{
+		{
+			name: "new StatefulSet",
+			es:   es,
+			want: true,
+		},
+		{
+			name:                 "existing StatefulSet with a single Secret",
+			es:                   es,
+			existingStatefulSets: es_sset.StatefulSetList{ssetWithTransportVolume(false)},
+			want:                 false,
+		},
+		{
+			name:                 "existing StatefulSet with a single Secret to migrate",
+			es:                   migratedES,
+			existingStatefulSets: es_sset.StatefulSetList{ssetWithTransportVolume(false)},
+			want:                 true,
+		},
+		{
+			name:                 "existing StatefulSet with chunked Secrets",
+			es:                   es,
+			existingStatefulSets: es_sset.StatefulSetList{ssetWithTransportVolume(true)},
+			want:                 true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, HasChunkedTransportCertificates(tt.es, tt.existingStatefulSets, "es1-es-default"))
+		})
+	}
+}
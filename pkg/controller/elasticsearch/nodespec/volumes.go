@@ -23,9 +23,11 @@ func buildVolumes(
 	esName string,
 	version version.Version,
 	nodeSpec esv1.NodeSet,
+	realms []esv1.Realm,
 	keystoreResources *keystore.Resources,
 	downwardAPIVolume volume.DownwardAPI,
 	additionalMountsFromPolicy []volume.VolumeLike,
+	transportCertificatesVolume volume.VolumeLike,
 ) ([]corev1.Volume, []corev1.VolumeMount) {
 	configVolume := settings.ConfigSecretVolume(esv1.StatefulSet(esName, nodeSpec.Name))
 	probeSecret := volume.NewSelectiveSecretVolumeWithMountPath(
@@ -37,7 +39,6 @@ func buildVolumes(
 		esvolume.HTTPCertificatesSecretVolumeName,
 		esvolume.HTTPCertificatesSecretVolumeMountPath,
 	)
-	transportCertificatesVolume := transportCertificatesVolume(esv1.StatefulSet(esName, nodeSpec.Name))
 	remoteCertificateAuthoritiesVolume := volume.NewSecretVolumeWithMountPath(
 		esv1.RemoteCaSecretName(esName),
 		esvolume.RemoteCertificateAuthoritiesSecretVolumeName,
@@ -79,6 +80,11 @@ func buildVolumes(
 		})
 	}
 
+	if nodeSpec.Ephemeral {
+		// ephemeral NodeSets have no claims, default to an emptyDir data volume unless specified differently in the pod template
+		persistentVolumes = append(persistentVolumes, esvolume.DefaultEphemeralDataVolume)
+	}
+
 	volumes := persistentVolumes
 	volumes = append(
 		volumes, // includes the data volume, unless specified differently in the pod template
@@ -121,6 +127,12 @@ func buildVolumes(
 		volumeMounts = append(volumeMounts, fileSettingsVolume.VolumeMount())
 	}
 
+	// files referenced by the realms
+	if realmsVolume, exists := settings.RealmsVolume(realms); exists {
+		volumes = append(volumes, realmsVolume.Volume())
+		volumeMounts = append(volumeMounts, realmsVolume.VolumeMount())
+	}
+
 	// additional volumes from stack config policy
 	for _, volume := range additionalMountsFromPolicy {
 		volumes = append(volumes, volume.Volume())
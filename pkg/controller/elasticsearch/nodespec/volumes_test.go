@@ -68,6 +68,12 @@ func Test_BuildVolumes_DataVolumeMountPath(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "with ephemeral data",
+			nodeSpec: esv1.NodeSet{
+				Ephemeral: true,
+			},
+		},
 		{
 			name: "with user provided data hostpath volume",
 			nodeSpec: esv1.NodeSet{
@@ -90,7 +96,7 @@ func Test_BuildVolumes_DataVolumeMountPath(t *testing.T) {
 
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
-			_, volumeMounts := buildVolumes("esname", version.MustParse("8.8.0"), tc.nodeSpec, nil, volume.DownwardAPI{}, []volume.VolumeLike{})
+			_, volumeMounts := buildVolumes("esname", version.MustParse("8.8.0"), tc.nodeSpec, nil, nil, volume.DownwardAPI{}, []volume.VolumeLike{}, transportCertificatesVolume(esv1.StatefulSet("esname", tc.nodeSpec.Name), true))
 			assert.True(t, contains(volumeMounts, "elasticsearch-data", "/usr/share/elasticsearch/data"))
 		})
 	}
@@ -104,3 +110,11 @@ func contains(volumeMounts []corev1.VolumeMount, volumeMountName, volumeMountPat
 	}
 	return false
 }
+
+func Test_BuildVolumes_EphemeralDataVolume(t *testing.T) {
+	volumes, _ := buildVolumes("esname", version.MustParse("8.8.0"), esv1.NodeSet{Ephemeral: true}, nil, nil, volume.DownwardAPI{}, []volume.VolumeLike{}, transportCertificatesVolume(esv1.StatefulSet("esname", ""), true))
+	assert.Contains(t, volumes, esvolume.DefaultEphemeralDataVolume)
+	for _, v := range volumes {
+		assert.Nil(t, v.PersistentVolumeClaim, "ephemeral NodeSets should not use persistent volume claims")
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package observer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/safemode"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
+)
+
+// runHealthProbes sends the requests of the given health probes to Elasticsearch, and returns their results.
+func runHealthProbes(ctx context.Context, cluster types.NamespacedName, esClient esclient.Client, probes []esv1.HealthProbe) []esv1.HealthProbeStatus {
+	// remove the metrics of the probes which do not exist anymore
+	deleteHealthProbeMetrics(cluster)
+	if len(probes) == 0 {
+		return nil
+	}
+	results := make([]esv1.HealthProbeStatus, 0, len(probes))
+	for _, probe := range probes {
+		if err := safemode.CheckRequest(ctx, probe.MethodOrDefault(), probe.Path); err != nil {
+			// probes which may write to the cluster are not run in safe mode
+			continue
+		}
+		start := time.Now()
+		err := runHealthProbe(ctx, esClient, probe)
+		duration := time.Since(start)
+		result := esv1.HealthProbeStatus{Name: probe.Name, Succeeded: true}
+		switch {
+		case err != nil:
+			result.Succeeded, result.Message = false, err.Error()
+		case probe.MaxLatency != nil && duration > probe.MaxLatency.Duration:
+			result.Succeeded, result.Message = false, fmt.Sprintf("request took %s, more than the maximum latency of %s", duration.Round(time.Millisecond), probe.MaxLatency.Duration)
+		}
+		results = append(results, result)
+
+		value := 0.0
+		if result.Succeeded {
+			value = 1
+		}
+		metrics.HealthProbeSucceededGauge.WithLabelValues(cluster.Namespace, cluster.Name, probe.Name).Set(value)
+		metrics.HealthProbeDurationGauge.WithLabelValues(cluster.Namespace, cluster.Name, probe.Name).Set(duration.Seconds())
+	}
+	return results
+}
+
+// runHealthProbe sends the request of the given health probe to Elasticsearch. It returns an error if the request
+// fails or if Elasticsearch does not respond with a 2xx status code.
+func runHealthProbe(ctx context.Context, esClient esclient.Client, probe esv1.HealthProbe) error {
+	var body io.Reader = http.NoBody
+	if probe.Body != "" {
+		body = strings.NewReader(probe.Body)
+	}
+	request, err := http.NewRequestWithContext(ctx, probe.MethodOrDefault(), probe.Path, body)
+	if err != nil {
+		return err
+	}
+	response, err := esClient.Request(ctx, request)
+	if response != nil && response.Body != nil {
+		// the content of the response is not relevant, only its status
+		_, _ = io.Copy(io.Discard, response.Body)
+		response.Body.Close()
+	}
+	return err
+}
+
+// healthProbesChanged returns true if a probe succeeded in one of the given results and failed in the other.
+func healthProbesChanged(previous, current []esv1.HealthProbeStatus) bool {
+	if len(previous) != len(current) {
+		return true
+	}
+	for i := range previous {
+		if previous[i].Name != current[i].Name || previous[i].Succeeded != current[i].Succeeded {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteHealthProbeMetrics removes the health probe metrics of the given Elasticsearch cluster.
+func deleteHealthProbeMetrics(cluster types.NamespacedName) {
+	labels := prometheus.Labels{metrics.NamespaceLabel: cluster.Namespace, metrics.NameLabel: cluster.Name}
+	metrics.HealthProbeSucceededGauge.DeletePartialMatch(labels)
+	metrics.HealthProbeDurationGauge.DeletePartialMatch(labels)
+}
@@ -0,0 +1,135 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package observer
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+)
+
+// fakeProbedEsClient returns a green health, and responds to the other requests with the status code returned by
+// the given function.
+func fakeProbedEsClient(statusCode func(req *http.Request) int) client.Client {
+	return client.NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		if req.URL.Path == "/_cluster/health" {
+			return client.NewMockResponse(http.StatusOK, req, `{"status":"green"}`)
+		}
+		return client.NewMockResponse(statusCode(req), req, `{}`)
+	})
+}
+
+func Test_runHealthProbes(t *testing.T) {
+	var method, path, body string
+	esClient := fakeProbedEsClient(func(req *http.Request) int {
+		method, path = req.Method, req.URL.RequestURI()
+		data, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		body = string(data)
+		if req.URL.Path == "/missing/_search" {
+			return http.StatusNotFound
+		}
+		return http.StatusOK
+	})
+
+	tests := []struct {
+		name          string
+		probe         esv1.HealthProbe
+		wantMethod    string
+		wantPath      string
+		wantBody      string
+		wantSucceeded bool
+	}{
+		{
+			name:          "search on a canary index",
+			probe:         esv1.HealthProbe{Name: "search", Path: "/canary/_search?size=1"},
+			wantMethod:    http.MethodGet,
+			wantPath:      "/canary/_search?size=1",
+			wantSucceeded: true,
+		},
+		{
+			name:          "ingest a document",
+			probe:         esv1.HealthProbe{Name: "ingest", Method: http.MethodPut, Path: "/canary/_doc/1?refresh=true", Body: `{"probe":true}`},
+			wantMethod:    http.MethodPut,
+			wantPath:      "/canary/_doc/1?refresh=true",
+			wantBody:      `{"probe":true}`,
+			wantSucceeded: true,
+		},
+		{
+			name:       "error response",
+			probe:      esv1.HealthProbe{Name: "missing", Path: "/missing/_search"},
+			wantMethod: http.MethodGet,
+			wantPath:   "/missing/_search",
+		},
+		{
+			name:       "response slower than the maximum latency",
+			probe:      esv1.HealthProbe{Name: "slow", Path: "/canary/_search", MaxLatency: &metav1.Duration{Duration: time.Nanosecond}},
+			wantMethod: http.MethodGet,
+			wantPath:   "/canary/_search",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := runHealthProbes(context.Background(), cluster("es"), esClient, []esv1.HealthProbe{tt.probe})
+			require.Len(t, results, 1)
+			assert.Equal(t, tt.probe.Name, results[0].Name)
+			assert.Equal(t, tt.wantSucceeded, results[0].Succeeded)
+			assert.Equal(t, tt.wantSucceeded, results[0].Message == "")
+			assert.Equal(t, tt.wantMethod, method)
+			assert.Equal(t, tt.wantPath, path)
+			assert.Equal(t, tt.wantBody, body)
+		})
+	}
+}
+
+func TestObserver_observeHealthProbes(t *testing.T) {
+	var failing atomic.Bool
+	esClient := fakeProbedEsClient(func(_ *http.Request) int {
+		if failing.Load() {
+			return http.StatusServiceUnavailable
+		}
+		return http.StatusOK
+	})
+	changes := int32(0)
+	observer := NewObserver(cluster("es"), esClient, Settings{}, nil)
+	observer.onHealthProbesChange = func(_ types.NamespacedName) {
+		atomic.AddInt32(&changes, 1)
+	}
+	observer.SetHealthProbes([]esv1.HealthProbe{{Name: "search", Path: "/canary/_search"}})
+
+	observer.observe(context.Background())
+	require.Equal(t, esv1.ElasticsearchGreenHealth, observer.LastHealth())
+	require.Equal(t, []esv1.HealthProbeStatus{{Name: "search", Succeeded: true}}, observer.LastHealthProbes())
+	require.Equal(t, int32(1), atomic.LoadInt32(&changes))
+
+	// same results: no change notified
+	observer.observe(context.Background())
+	require.Equal(t, int32(1), atomic.LoadInt32(&changes))
+
+	failing.Store(true)
+	observer.observe(context.Background())
+	require.Equal(t, esv1.ElasticsearchGreenHealth, observer.LastHealth())
+	require.Len(t, observer.LastHealthProbes(), 1)
+	require.False(t, observer.LastHealthProbes()[0].Succeeded)
+	require.Equal(t, int32(2), atomic.LoadInt32(&changes))
+
+	// probes removed from the spec
+	observer.SetHealthProbes(nil)
+	observer.observe(context.Background())
+	require.Empty(t, observer.LastHealthProbes())
+	require.Equal(t, int32(3), atomic.LoadInt32(&changes))
+}
@@ -30,7 +30,8 @@ type Manager struct {
 	observerLock    sync.RWMutex
 	observers       map[types.NamespacedName]*Observer
 	listenerLock    sync.RWMutex
-	listeners       []OnObservation // invoked on each observation event
+	listeners       []OnObservation        // invoked on each observation event
+	probeListeners  []OnHealthProbesChange // invoked when a health probe starts or stops failing
 	tracer          *apm.Tracer
 }
 
@@ -57,6 +58,15 @@ func (m *Manager) ObservedStateResolver(
 	}
 }
 
+// ObservedHealthProbes returns the results of the health probes run during the last observation of the given cluster.
+func (m *Manager) ObservedHealthProbes(cluster types.NamespacedName) []esv1.HealthProbeStatus {
+	observer, exists := m.getObserver(cluster)
+	if !exists {
+		return nil
+	}
+	return observer.LastHealthProbes()
+}
+
 func (m *Manager) getObserver(key types.NamespacedName) (*Observer, bool) {
 	m.observerLock.RLock()
 	defer m.observerLock.RUnlock()
@@ -77,6 +87,7 @@ func (m *Manager) Observe(ctx context.Context, cluster esv1.Elasticsearch, esCli
 	var esClient client.Client
 	if exists {
 		esClient = esClientProvider(observer.esClient)
+		observer.SetHealthProbes(cluster.Spec.HealthProbes)
 	} else {
 		esClient = esClientProvider(nil)
 	}
@@ -96,6 +107,7 @@ func (m *Manager) Observe(ctx context.Context, cluster esv1.Elasticsearch, esCli
 		return observer
 	}
 
+	observer.SetHealthProbes(cluster.Spec.HealthProbes)
 	if !exists && isServiceReady {
 		// there was no existing observer and Service is ready: let's try an initial synchronous observation
 		observer.observe(ctx)
@@ -127,6 +139,7 @@ func (m *Manager) createOrReplaceObserver(ctx context.Context, cluster types.Nam
 		delete(m.observers, cluster)
 	}
 	observer = NewObserver(cluster, esClient, settings, m.notifyListeners)
+	observer.onHealthProbesChange = m.notifyHealthProbesListeners
 	m.observers[cluster] = observer
 	return observer
 }
@@ -191,6 +204,23 @@ func (m *Manager) notifyListeners(cluster types.NamespacedName, previousState, n
 	}
 }
 
+// AddHealthProbesListener adds the given listener to the list of listeners notified
+// when a health probe starts or stops failing.
+func (m *Manager) AddHealthProbesListener(listener OnHealthProbesChange) {
+	m.listenerLock.Lock()
+	defer m.listenerLock.Unlock()
+	m.probeListeners = append(m.probeListeners, listener)
+}
+
+// notifyHealthProbesListeners notifies all listeners that a health probe started or stopped failing.
+func (m *Manager) notifyHealthProbesListeners(cluster types.NamespacedName) {
+	m.listenerLock.RLock()
+	defer m.listenerLock.RUnlock()
+	for _, l := range m.probeListeners {
+		l(cluster)
+	}
+}
+
 func (m *Manager) StopObserving(key types.NamespacedName) {
 	log.Info("Stopping observer", "namespace", key.Namespace, "es_name", key.Name)
 	m.observerLock.Lock()
@@ -200,4 +230,5 @@ func (m *Manager) StopObserving(key types.NamespacedName) {
 		observer.Stop()
 		delete(m.observers, key)
 	}
+	deleteHealthProbeMetrics(key)
 }
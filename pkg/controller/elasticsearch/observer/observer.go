@@ -35,6 +35,9 @@ const defaultObservationTimeout = 10 * time.Second
 // OnObservation is a function that gets executed when a new state is observed
 type OnObservation func(cluster types.NamespacedName, previousHealth, newHealth esv1.ElasticsearchHealth)
 
+// OnHealthProbesChange is a function that gets executed when a health probe starts or stops failing
+type OnHealthProbesChange func(cluster types.NamespacedName)
+
 // Observer regularly requests an ES endpoint for cluster state,
 // in a thread-safe way
 type Observer struct {
@@ -47,6 +50,10 @@ type Observer struct {
 	onObservation OnObservation
 	lastHealth    esv1.ElasticsearchHealth
 	mutex         sync.RWMutex
+
+	healthProbes         []esv1.HealthProbe
+	lastHealthProbes     []esv1.HealthProbeStatus
+	onHealthProbesChange OnHealthProbesChange
 }
 
 // NewObserver creates and starts an Observer
@@ -106,6 +113,27 @@ func (o *Observer) LastHealth() esv1.ElasticsearchHealth {
 	return o.lastHealth
 }
 
+// HealthProbes returns the health probes run along with each observation
+func (o *Observer) HealthProbes() []esv1.HealthProbe {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+	return o.healthProbes
+}
+
+// SetHealthProbes sets the health probes run along with each observation
+func (o *Observer) SetHealthProbes(probes []esv1.HealthProbe) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.healthProbes = probes
+}
+
+// LastHealthProbes returns the results of the health probes run during the last observation
+func (o *Observer) LastHealthProbes() []esv1.HealthProbeStatus {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+	return o.lastHealthProbes
+}
+
 // observe retrieves the current ES state, executes onObservation,
 // and stores the new state
 func (o *Observer) observe(ctx context.Context) {
@@ -123,10 +151,22 @@ func (o *Observer) observe(ctx context.Context) {
 	ulog.FromContext(ctx).V(1).Info("Retrieving cluster health", "es_name", o.cluster.Name, "namespace", o.cluster.Namespace)
 
 	newHealth := retrieveHealth(ctx, o.cluster, o.esClient)
+	// probes are only relevant if the cluster responds to requests, an unknown health already reflects the opposite
+	var newHealthProbes []esv1.HealthProbeStatus
+	if newHealth != esv1.ElasticsearchUnknownHealth {
+		newHealthProbes = runHealthProbes(ctx, o.cluster, o.esClient, o.HealthProbes())
+	} else {
+		deleteHealthProbeMetrics(o.cluster)
+	}
 	if o.onObservation != nil {
 		o.onObservation(o.cluster, o.LastHealth(), newHealth)
 	}
+	previousHealthProbes := o.LastHealthProbes()
 	o.updateHealth(newHealth)
+	o.updateHealthProbes(newHealthProbes)
+	if o.onHealthProbesChange != nil && healthProbesChanged(previousHealthProbes, newHealthProbes) {
+		o.onHealthProbesChange(o.cluster)
+	}
 }
 
 func (o *Observer) updateHealth(newHealth esv1.ElasticsearchHealth) {
@@ -135,6 +175,12 @@ func (o *Observer) updateHealth(newHealth esv1.ElasticsearchHealth) {
 	o.lastHealth = newHealth
 }
 
+func (o *Observer) updateHealthProbes(newHealthProbes []esv1.HealthProbeStatus) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.lastHealthProbes = newHealthProbes
+}
+
 func nonNegativeTimeout(observationInterval time.Duration) time.Duration {
 	// if the observation interval is not positive async observations are disabled
 	if observationInterval <= 0 {
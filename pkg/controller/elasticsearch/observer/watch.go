@@ -15,11 +15,12 @@ import (
 )
 
 // WatchClusterHealthChange returns a Source fed with generic events targeting clusters
-// whose health has changed between 2 observations.
+// whose health or health probes results have changed between 2 observations.
 // Aimed to be used for triggering a reconciliation.
 func WatchClusterHealthChange(m *Manager) source.Source {
 	evtChan := make(chan event.TypedGenericEvent[*esv1.Elasticsearch])
 	m.AddObservationListener(healthChangeListener(evtChan))
+	m.AddHealthProbesListener(healthProbesChangeListener(evtChan))
 	// Each event in Source will be consumed and turned into
 	// a reconciliation request.
 	//
@@ -48,3 +49,16 @@ func healthChangeListener(reconciliation chan event.TypedGenericEvent[*esv1.Elas
 		reconciliation <- evt
 	}
 }
+
+// healthProbesChangeListener returns an OnHealthProbesChange listener that feeds a generic
+// event when a health probe of a cluster starts or stops failing.
+func healthProbesChangeListener(reconciliation chan event.TypedGenericEvent[*esv1.Elasticsearch]) OnHealthProbesChange {
+	return func(cluster types.NamespacedName) {
+		reconciliation <- event.TypedGenericEvent[*esv1.Elasticsearch]{
+			Object: &esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{
+				Namespace: cluster.Namespace,
+				Name:      cluster.Name,
+			}},
+		}
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package pdb
+
+import (
+	"context"
+
+	policyv1 "k8s.io/api/policy/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
+)
+
+// reconcileNodeSetPDBs ensures that a PodDisruptionBudget exists for each NodeSet of the cluster, unless disabled in the
+// NodeSet spec. The default PDB of the cluster and the PDBs of the NodeSets which no longer exist are deleted.
+func reconcileNodeSetPDBs(ctx context.Context, k8sClient k8s.Client, es esv1.Elasticsearch, statefulSets sset.StatefulSetList) error {
+	// the default PDB would also select the Pods of the NodeSets, which prevents any eviction
+	if err := deleteDefaultPDB(ctx, k8sClient, es); err != nil {
+		return err
+	}
+
+	expected, err := expectedNodeSetPDBs(es, statefulSets)
+	if err != nil {
+		return err
+	}
+	names := set.Make()
+	for i := range expected {
+		if err := reconcilePDB(ctx, k8sClient, es, &expected[i]); err != nil {
+			return err
+		}
+		names.Add(expected[i].Name)
+	}
+	return deleteNodeSetPDBs(ctx, k8sClient, es, names)
+}
+
+// expectedNodeSetPDBs returns the PDBs of the NodeSets according to the given ES spec.
+func expectedNodeSetPDBs(es esv1.Elasticsearch, statefulSets sset.StatefulSetList) ([]policyv1.PodDisruptionBudget, error) {
+	pdbs := make([]policyv1.PodDisruptionBudget, 0, len(es.Spec.NodeSets))
+	for _, nodeSet := range es.Spec.NodeSets {
+		template := nodeSet.PodDisruptionBudget.DeepCopy()
+		if template.IsDisabled() {
+			continue
+		}
+		if template == nil {
+			template = &commonv1.PodDisruptionBudgetTemplate{}
+		}
+		statefulSetName := esv1.StatefulSet(es.Name, nodeSet.Name)
+
+		expected := policyv1.PodDisruptionBudget{
+			ObjectMeta: template.ObjectMeta,
+		}
+		// inherit user-provided ObjectMeta, but set our own name & namespace
+		expected.Name = esv1.NodeSetPodDisruptionBudget(es.Name, nodeSet.Name)
+		expected.Namespace = es.Namespace
+		// and append our labels
+		expected.Labels = maps.MergePreservingExistingKeys(
+			expected.Labels,
+			label.NewStatefulSetLabels(k8s.ExtractNamespacedName(&es), statefulSetName),
+		)
+		// set owner reference for deletion upon ES resource deletion
+		if err := controllerutil.SetControllerReference(&es, &expected, scheme.Scheme); err != nil {
+			return nil, err
+		}
+
+		expected.Spec = template.Spec
+		if expected.Spec.Selector == nil {
+			// match all pods of this NodeSet
+			expected.Spec.Selector = &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					label.ClusterNameLabelName:     es.Name,
+					label.StatefulSetNameLabelName: statefulSetName,
+				},
+			}
+		}
+		if expected.Spec.MaxUnavailable == nil && expected.Spec.MinAvailable == nil {
+			// the selector matches the Pods of a single StatefulSet, MaxUnavailable can be used
+			maxUnavailable := intstr.FromInt32(allowedNodeSetDisruptions(es, statefulSets, statefulSetName))
+			expected.Spec.MaxUnavailable = &maxUnavailable
+		}
+
+		pdbs = append(pdbs, expected)
+	}
+	return pdbs, nil
+}
+
+// allowedNodeSetDisruptions returns the number of Pods of a NodeSet that we allow to be disrupted while keeping the
+// cluster healthy.
+func allowedNodeSetDisruptions(es esv1.Elasticsearch, actualSsets sset.StatefulSetList, statefulSetName string) int32 {
+	statefulSet, exists := actualSsets.GetByName(statefulSetName)
+	if !exists {
+		// the NodeSet does not have any Pod yet
+		return 0
+	}
+	if actualSsets.ExpectedNodeCount() == 1 {
+		// single node cluster (not highly-available)
+		// allow the node to be disrupted to ensure K8s nodes operations can be performed
+		return 1
+	}
+	if label.IsCoordinatingOnlyNodeSet(statefulSet) {
+		// Coordinating-only nodes hold no data and do not take part in master elections: disrupting one of them does
+		// not affect the health of the cluster.
+		return 1
+	}
+	if es.Status.Health != esv1.ElasticsearchGreenHealth {
+		// A non-green cluster may become red if we disrupt one node, don't allow it.
+		return 0
+	}
+	if label.IsMasterNodeSet(statefulSet) && !label.IsVotingOnlyNodeSet(statefulSet) &&
+		actualSsets.ExpectedElectableMasterNodesCount() == 1 {
+		// This NodeSet holds the single master of the cluster, voting-only nodes cannot be elected in its place,
+		// don't allow it to be removed.
+		return 0
+	}
+	if label.IsDataNodeSet(statefulSet) && actualSsets.ExpectedDataNodesCount() == 1 {
+		// This NodeSet holds the single data node of the cluster, don't allow it to be removed.
+		return 0
+	}
+	if label.IsIngestNodeSet(statefulSet) && actualSsets.ExpectedIngestNodesCount() == 1 {
+		// This NodeSet holds the single ingest node of the cluster, don't allow it to be removed.
+		return 0
+	}
+	// Allow one pod (only) of the NodeSet to be disrupted on a healthy cluster.
+	return 1
+}
+
+// deleteNodeSetPDBs deletes the PDBs of the NodeSets of the cluster, except the ones in the given set of names.
+func deleteNodeSetPDBs(ctx context.Context, k8sClient k8s.Client, es esv1.Elasticsearch, keep set.StringSet) error {
+	v1Available, err := isPDBV1Available(k8sClient)
+	if err != nil {
+		return err
+	}
+	var pdbs []client.Object
+	if v1Available {
+		var list policyv1.PodDisruptionBudgetList
+		if err := k8sClient.List(ctx, &list, client.InNamespace(es.Namespace), label.NewLabelSelectorForElasticsearch(es)); err != nil {
+			return err
+		}
+		for i := range list.Items {
+			pdbs = append(pdbs, &list.Items[i])
+		}
+	} else {
+		var list policyv1beta1.PodDisruptionBudgetList
+		if err := k8sClient.List(ctx, &list, client.InNamespace(es.Namespace), label.NewLabelSelectorForElasticsearch(es)); err != nil {
+			return err
+		}
+		for i := range list.Items {
+			pdbs = append(pdbs, &list.Items[i])
+		}
+	}
+
+	for _, pdb := range pdbs {
+		// the default PDB of the cluster is not labeled with a StatefulSet name
+		if _, isNodeSetPDB := pdb.GetLabels()[label.StatefulSetNameLabelName]; !isNodeSetPDB || keep.Has(pdb.GetName()) {
+			continue
+		}
+		if !metav1.IsControlledBy(pdb, &es) {
+			continue
+		}
+		if err := k8sClient.Delete(ctx, pdb); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package pdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/statefulset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	es_sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
+)
+
+func nodeSetPDB(nodeSet string, maxUnavailable int) *policyv1.PodDisruptionBudget {
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      esv1.NodeSetPodDisruptionBudget("cluster", nodeSet),
+			Namespace: "ns",
+			Labels: map[string]string{
+				label.ClusterNameLabelName:     "cluster",
+				label.StatefulSetNameLabelName: esv1.StatefulSet("cluster", nodeSet),
+				commonv1.TypeLabelName:         label.Type,
+			},
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MaxUnavailable: intStrPtr(intstr.FromInt32(int32(maxUnavailable))),
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					label.ClusterNameLabelName:     "cluster",
+					label.StatefulSetNameLabelName: esv1.StatefulSet("cluster", nodeSet),
+				},
+			},
+		},
+	}
+}
+
+func TestReconcile_NodeSets(t *testing.T) {
+	statefulSets := es_sset.StatefulSetList{
+		sset.TestSset{Name: esv1.StatefulSet("cluster", "master"), ClusterName: "cluster", Replicas: 3, Master: true}.Build(),
+		sset.TestSset{Name: esv1.StatefulSet("cluster", "data-a"), ClusterName: "cluster", Replicas: 3, Data: true}.Build(),
+		sset.TestSset{Name: esv1.StatefulSet("cluster", "data-b"), ClusterName: "cluster", Replicas: 3, Data: true}.Build(),
+	}
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "ns"},
+		Spec: esv1.ElasticsearchSpec{NodeSets: []esv1.NodeSet{
+			{Name: "master"},
+			{Name: "data-a", PodDisruptionBudget: &commonv1.PodDisruptionBudgetTemplate{
+				Spec: policyv1.PodDisruptionBudgetSpec{MaxUnavailable: intStrPtr(intstr.FromInt32(2))},
+			}},
+			{Name: "data-b", PodDisruptionBudget: &commonv1.PodDisruptionBudgetTemplate{}},
+		}},
+		Status: esv1.ElasticsearchStatus{Health: esv1.ElasticsearchGreenHealth},
+	}
+	defaultPDB := &policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{
+		Name:      esv1.DefaultPodDisruptionBudget("cluster"),
+		Namespace: "ns",
+		Labels:    map[string]string{label.ClusterNameLabelName: "cluster"},
+	}}
+	// PDB of a NodeSet which has been removed from the spec
+	removedPDB := withOwnerRef(nodeSetPDB("removed", 1), es)
+	// PDB of a NodeSet which has been disabled in the spec
+	disabledPDB := withOwnerRef(nodeSetPDB("data-b", 1), es)
+
+	restMapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{{Group: "policy", Version: "v1"}})
+	restMapper.Add(schema.GroupVersionKind{Group: "policy", Version: "v1", Kind: "PodDisruptionBudget"}, meta.RESTScopeNamespace)
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(clientgoscheme.Scheme).
+		WithRESTMapper(restMapper).
+		WithObjects(defaultPDB, removedPDB, disabledPDB).Build()
+
+	require.NoError(t, Reconcile(context.Background(), k8sClient, es, statefulSets))
+
+	var pdbs policyv1.PodDisruptionBudgetList
+	require.NoError(t, k8sClient.List(context.Background(), &pdbs, client.InNamespace("ns")))
+	names := make([]string, 0, len(pdbs.Items))
+	for _, pdb := range pdbs.Items {
+		names = append(names, pdb.Name)
+	}
+	require.ElementsMatch(t, []string{"cluster-es-master-default", "cluster-es-data-a-default"}, names)
+
+	// switching back to the default PDB deletes the PDBs of the NodeSets
+	es.Spec.NodeSets = []esv1.NodeSet{{Name: "master"}, {Name: "data-a"}, {Name: "data-b"}}
+	require.NoError(t, Reconcile(context.Background(), k8sClient, es, statefulSets))
+	require.NoError(t, k8sClient.List(context.Background(), &pdbs, client.InNamespace("ns")))
+	require.Len(t, pdbs.Items, 1)
+	require.Equal(t, esv1.DefaultPodDisruptionBudget("cluster"), pdbs.Items[0].Name)
+}
+
+func Test_expectedNodeSetPDBs(t *testing.T) {
+	statefulSets := es_sset.StatefulSetList{
+		sset.TestSset{Name: esv1.StatefulSet("cluster", "master"), ClusterName: "cluster", Replicas: 3, Master: true}.Build(),
+		sset.TestSset{Name: esv1.StatefulSet("cluster", "data"), ClusterName: "cluster", Replicas: 6, Data: true}.Build(),
+	}
+	tests := []struct {
+		name     string
+		nodeSets []esv1.NodeSet
+		want     []*policyv1.PodDisruptionBudget
+	}{
+		{
+			name:     "default PDB for the NodeSets without template",
+			nodeSets: []esv1.NodeSet{{Name: "master"}, {Name: "data", PodDisruptionBudget: &commonv1.PodDisruptionBudgetTemplate{}}},
+			want:     []*policyv1.PodDisruptionBudget{nodeSetPDB("master", 1)},
+		},
+		{
+			name: "user-provided maxUnavailable with the default selector",
+			nodeSets: []esv1.NodeSet{
+				{Name: "master"},
+				{Name: "data", PodDisruptionBudget: &commonv1.PodDisruptionBudgetTemplate{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"a": "b"}},
+					Spec:       policyv1.PodDisruptionBudgetSpec{MaxUnavailable: intStrPtr(intstr.FromInt32(2))},
+				}},
+			},
+			want: []*policyv1.PodDisruptionBudget{
+				nodeSetPDB("master", 1),
+				func() *policyv1.PodDisruptionBudget {
+					pdb := nodeSetPDB("data", 2)
+					pdb.Labels["a"] = "b"
+					return pdb
+				}(),
+			},
+		},
+		{
+			name: "user-provided minAvailable and selector",
+			nodeSets: []esv1.NodeSet{
+				{Name: "master", PodDisruptionBudget: &commonv1.PodDisruptionBudgetTemplate{
+					Spec: policyv1.PodDisruptionBudgetSpec{
+						MinAvailable: intStrPtr(intstr.FromInt32(2)),
+						Selector:     &metav1.LabelSelector{MatchLabels: map[string]string{"c": "d"}},
+					},
+				}},
+			},
+			want: []*policyv1.PodDisruptionBudget{
+				func() *policyv1.PodDisruptionBudget {
+					pdb := nodeSetPDB("master", 0)
+					pdb.Spec = policyv1.PodDisruptionBudgetSpec{
+						MinAvailable: intStrPtr(intstr.FromInt32(2)),
+						Selector:     &metav1.LabelSelector{MatchLabels: map[string]string{"c": "d"}},
+					}
+					return pdb
+				}(),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "ns"},
+				Spec:       esv1.ElasticsearchSpec{NodeSets: tt.nodeSets},
+				Status:     esv1.ElasticsearchStatus{Health: esv1.ElasticsearchGreenHealth},
+			}
+			got, err := expectedNodeSetPDBs(es, statefulSets)
+			require.NoError(t, err)
+			want := make([]policyv1.PodDisruptionBudget, len(tt.want))
+			for i := range tt.want {
+				want[i] = *withOwnerRef(tt.want[i], es)
+			}
+			require.Equal(t, want, got)
+		})
+	}
+}
+
+func Test_allowedNodeSetDisruptions(t *testing.T) {
+	green := esv1.Elasticsearch{Status: esv1.ElasticsearchStatus{Health: esv1.ElasticsearchGreenHealth}}
+	tests := []struct {
+		name        string
+		es          esv1.Elasticsearch
+		actualSsets es_sset.StatefulSetList
+		want        int32
+	}{
+		{
+			name:        "StatefulSet does not exist yet: no disruption allowed",
+			es:          green,
+			actualSsets: es_sset.StatefulSetList{sset.TestSset{Name: "other", Replicas: 3, Master: true, Data: true}.Build()},
+			want:        0,
+		},
+		{
+			name:        "single-node cluster (not high-available): 1 disruption allowed",
+			es:          esv1.Elasticsearch{},
+			actualSsets: es_sset.StatefulSetList{sset.TestSset{Name: "sset", Replicas: 1, Master: true, Data: true}.Build()},
+			want:        1,
+		},
+		{
+			name:        "yellow health: no disruption allowed",
+			es:          esv1.Elasticsearch{Status: esv1.ElasticsearchStatus{Health: esv1.ElasticsearchYellowHealth}},
+			actualSsets: es_sset.StatefulSetList{sset.TestSset{Name: "sset", Replicas: 3, Master: true, Data: true}.Build()},
+			want:        0,
+		},
+		{
+			name: "green health but the NodeSet holds the only master: 0 disruption allowed",
+			es:   green,
+			actualSsets: es_sset.StatefulSetList{
+				sset.TestSset{Name: "sset", Replicas: 1, Master: true}.Build(),
+				sset.TestSset{Name: "data", Replicas: 3, Data: true}.Build(),
+			},
+			want: 0,
+		},
+		{
+			name: "green health and another NodeSet holds the only master: 1 disruption allowed",
+			es:   green,
+			actualSsets: es_sset.StatefulSetList{
+				sset.TestSset{Name: "master", Replicas: 1, Master: true}.Build(),
+				sset.TestSset{Name: "sset", Replicas: 3, Data: true, Ingest: true}.Build(),
+			},
+			want: 1,
+		},
+		{
+			name: "green health but the NodeSet holds the only master eligible for election: 0 disruption allowed",
+			es:   green,
+			actualSsets: es_sset.StatefulSetList{
+				sset.TestSset{Name: "sset", Replicas: 1, Master: true}.Build(),
+				sset.TestSset{Name: "voting-only", Replicas: 2, Master: true, VotingOnly: true}.Build(),
+				sset.TestSset{Name: "data", Replicas: 3, Data: true, Ingest: true}.Build(),
+			},
+			want: 0,
+		},
+		{
+			name: "green health and the NodeSet holds voting-only masters: 1 disruption allowed",
+			es:   green,
+			actualSsets: es_sset.StatefulSetList{
+				sset.TestSset{Name: "master", Replicas: 2, Master: true}.Build(),
+				sset.TestSset{Name: "sset", Replicas: 1, Master: true, VotingOnly: true}.Build(),
+				sset.TestSset{Name: "data", Replicas: 3, Data: true, Ingest: true}.Build(),
+			},
+			want: 1,
+		},
+		{
+			name: "yellow health but the NodeSet holds coordinating-only nodes: 1 disruption allowed",
+			es:   esv1.Elasticsearch{Status: esv1.ElasticsearchStatus{Health: esv1.ElasticsearchYellowHealth}},
+			actualSsets: es_sset.StatefulSetList{
+				sset.TestSset{Name: "master", Replicas: 3, Master: true, Data: true, Ingest: true}.Build(),
+				sset.TestSset{Name: "sset", Replicas: 2}.Build(),
+			},
+			want: 1,
+		},
+		{
+			name: "green health but the NodeSet holds the only ingest node: 0 disruption allowed",
+			es:   green,
+			actualSsets: es_sset.StatefulSetList{
+				sset.TestSset{Name: "master", Replicas: 3, Master: true, Data: true}.Build(),
+				sset.TestSset{Name: "sset", Replicas: 1, Ingest: true}.Build(),
+			},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := allowedNodeSetDisruptions(tt.es, tt.actualSsets, "sset"); got != tt.want {
+				t.Errorf("allowedNodeSetDisruptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
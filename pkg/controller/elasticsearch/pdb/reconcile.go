@@ -29,15 +29,29 @@ import (
 // Reconcile ensures that a PodDisruptionBudget exists for this cluster, inheriting the spec content.
 // The default PDB we setup dynamically adapts MinAvailable to the number of nodes in the cluster.
 // If the spec has disabled the default PDB, it will ensure none exist.
+// If at least one NodeSet specifies its own PDB, one PDB is reconciled per NodeSet instead.
 func Reconcile(ctx context.Context, k8sClient k8s.Client, es esv1.Elasticsearch, statefulSets sset.StatefulSetList) error {
+	if esv1.NodeSetList(es.Spec.NodeSets).HasPodDisruptionBudget() {
+		return reconcileNodeSetPDBs(ctx, k8sClient, es, statefulSets)
+	}
+
 	expected, err := expectedPDB(es, statefulSets)
 	if err != nil {
 		return err
 	}
 	if expected == nil {
-		return deleteDefaultPDB(ctx, k8sClient, es)
+		if err := deleteDefaultPDB(ctx, k8sClient, es); err != nil {
+			return err
+		}
+	} else if err := reconcilePDB(ctx, k8sClient, es, expected); err != nil {
+		return err
 	}
+	// PDBs of the NodeSets may remain from a previous specification
+	return deleteNodeSetPDBs(ctx, k8sClient, es, nil)
+}
 
+// reconcilePDB creates or updates the expected PDB.
+func reconcilePDB(ctx context.Context, k8sClient k8s.Client, es esv1.Elasticsearch, expected *policyv1.PodDisruptionBudget) error {
 	// label the PDB with a hash of its content, for comparison purposes
 	expected.Labels = hash.SetTemplateHashLabel(expected.Labels, expected)
 
@@ -195,8 +209,9 @@ func allowedDisruptions(es esv1.Elasticsearch, actualSsets sset.StatefulSetList)
 		// The health information we're using here may be out-of-date, that's best effort.
 		return 0
 	}
-	if actualSsets.ExpectedMasterNodesCount() == 1 {
+	if actualSsets.ExpectedElectableMasterNodesCount() == 1 {
 		// There's a risk the single master of the cluster gets removed, don't allow it.
+		// Voting-only master nodes are not accounted for since they cannot be elected in its place.
 		return 0
 	}
 	if actualSsets.ExpectedDataNodesCount() == 1 {
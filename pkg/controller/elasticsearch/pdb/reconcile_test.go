@@ -345,6 +345,18 @@ func Test_allowedDisruptions(t *testing.T) {
 			},
 			want: 0,
 		},
+		{
+			name: "green health but only 1 master eligible for election: 0 disruption allowed",
+			args: args{
+				es: esv1.Elasticsearch{Status: esv1.ElasticsearchStatus{Health: esv1.ElasticsearchGreenHealth}},
+				actualSsets: es_sset.StatefulSetList{
+					sset.TestSset{Replicas: 1, Master: true, Data: false}.Build(),
+					sset.TestSset{Replicas: 2, Master: true, VotingOnly: true, Data: false}.Build(),
+					sset.TestSset{Replicas: 3, Master: false, Data: true, Ingest: true}.Build(),
+				},
+			},
+			want: 0,
+		},
 		{
 			name: "green health but only 1 data node: 0 disruption allowed",
 			args: args{
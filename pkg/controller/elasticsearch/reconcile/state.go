@@ -8,13 +8,19 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"slices"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/hints"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/ingestautoscaling"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
 )
@@ -27,6 +33,8 @@ type State struct {
 	cluster esv1.Elasticsearch
 	status  esv1.ElasticsearchStatus
 	hints   hints.OrchestrationsHints
+	// invalidMessage explains why the cluster is invalid, if it is.
+	invalidMessage string
 }
 
 // NewState creates a new reconcile state based on the given cluster
@@ -101,6 +109,18 @@ func (s *State) UpdateClusterHealth(clusterHealth esv1.ElasticsearchHealth) *Sta
 	return s
 }
 
+// UpdateHealthProbes records the results of the health probes of the cluster, ignoring the probes removed from its spec
+// since the last observation.
+func (s *State) UpdateHealthProbes(results []esv1.HealthProbeStatus) *State {
+	s.status.HealthProbes = nil
+	for _, result := range results {
+		if slices.ContainsFunc(s.cluster.Spec.HealthProbes, func(probe esv1.HealthProbe) bool { return probe.Name == result.Name }) {
+			s.status.HealthProbes = append(s.status.HealthProbes, result)
+		}
+	}
+	return s
+}
+
 func (s *State) UpdateWithPhase(
 	phase esv1.ElasticsearchOrchestrationPhase,
 ) *State {
@@ -163,10 +183,88 @@ func (s *State) UpdateMinRunningVersion(
 	return s
 }
 
+// UpdateSnapshotVerification records the result of the last snapshot verification, and reports it as a condition.
+func (s *State) UpdateSnapshotVerification(status *esv1.SnapshotVerificationStatus, verified bool, message string) {
+	s.status.SnapshotVerification = status
+	if status == nil {
+		s.RemoveCondition(esv1.SnapshotsVerified)
+		return
+	}
+	conditionStatus := corev1.ConditionTrue
+	if !verified {
+		conditionStatus = corev1.ConditionFalse
+	}
+	s.ReportCondition(esv1.SnapshotsVerified, conditionStatus, message)
+}
+
+// RemoveCondition removes the condition of the given type from the status, when it is no longer relevant.
+func (s *State) RemoveCondition(conditionType commonv1alpha1.ConditionType) {
+	s.status.Conditions = slices.DeleteFunc(s.status.Conditions, func(condition commonv1alpha1.Condition) bool {
+		return condition.Type == conditionType
+	})
+}
+
+// UpdateIngestAutoscaling records the state of the ingest autoscaled NodeSets.
+func (s *State) UpdateIngestAutoscaling(statuses []esv1.IngestAutoscalingStatus) {
+	s.status.IngestAutoscaling = statuses
+}
+
+// UpdateResourceRecommendations records the resources recommended for the Elasticsearch containers of each NodeSet.
+func (s *State) UpdateResourceRecommendations(recommendations []esv1.ResourceRecommendation) {
+	s.status.ResourceRecommendations = recommendations
+}
+
+// UpdateRestart records the status of the restart of the Pods requested with the restart annotation.
+func (s *State) UpdateRestart(restart *commonv1.RestartStatus) {
+	s.status.Restart = restart
+}
+
+// UpdateUpgradeGate records the status of the step of the version upgrade waiting for approval, if any.
+func (s *State) UpdateUpgradeGate(upgradeGate *commonv1.UpgradeGateStatus) {
+	s.status.UpgradeGate = upgradeGate
+}
+
+// UpdateStandardConditions sets the standard conditions of the cluster from its health, its health probes, its nodes,
+// its versions and the result of the reconciliation. The cluster is ready as long as its health is green or yellow, and
+// stalled if it is invalid.
+func (s *State) UpdateStandardConditions(reconciled bool, reconciliationMessage string) {
+	es := s.cluster
+	es.Status.IngestAutoscaling = s.status.IngestAutoscaling
+	s.status.ExpectedNodes = ingestautoscaling.ApplyCounts(es).Spec.NodeCount()
+	state := commonv1alpha1.NewResourceState(
+		s.cluster.Generation, string(s.status.Health), s.status.AvailableNodes, s.status.ExpectedNodes, s.cluster.Spec.Version, s.status.Version,
+	)
+	state.Reconciled, state.ReconciliationMessage = reconciled, reconciliationMessage
+	if failedProbes := s.failedHealthProbes(); len(failedProbes) > 0 {
+		message := fmt.Sprintf("Health probes failed: %s", strings.Join(failedProbes, ", "))
+		if state.Degraded {
+			message = state.DegradedMessage + ". " + message
+		}
+		state.Degraded, state.DegradedMessage = true, message
+	}
+	if s.invalidMessage != "" {
+		state.Reconciled, state.ReconciliationMessage = false, s.invalidMessage
+		state.Stalled, state.StalledMessage = true, s.invalidMessage
+	}
+	s.status.Conditions = s.status.Conditions.WithStandardConditions(state, metav1.Now())
+}
+
+// failedHealthProbes returns the names of the health probes which failed during the last observation.
+func (s *State) failedHealthProbes() []string {
+	var failed []string
+	for _, result := range s.status.HealthProbes {
+		if !result.Succeeded {
+			failed = append(failed, result.Name)
+		}
+	}
+	return failed
+}
+
 // UpdateElasticsearchInvalidWithEvent is a convenient method to set the phase to esv1.ElasticsearchResourceInvalid
 // and generate an event at the same time.
 func (s *State) UpdateElasticsearchInvalidWithEvent(msg string) {
 	s.status.Phase = esv1.ElasticsearchResourceInvalid
+	s.invalidMessage = msg
 	s.AddEvent(corev1.EventTypeWarning, events.EventReasonValidation, msg)
 }
 
@@ -519,3 +519,76 @@ func TestState_UpdateWithPhase(t *testing.T) {
 		})
 	}
 }
+
+func TestState_UpdateHealthProbes(t *testing.T) {
+	cluster := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{HealthProbes: []esv1.HealthProbe{
+		{Name: "search", Path: "/canary/_search"},
+		{Name: "ingest", Method: "POST", Path: "/canary/_doc"},
+	}}}
+	tests := []struct {
+		name            string
+		health          esv1.ElasticsearchHealth
+		results         []esv1.HealthProbeStatus
+		wantResults     []esv1.HealthProbeStatus
+		wantDegraded    corev1.ConditionStatus
+		wantDegradedMsg string
+	}{
+		{
+			name:         "all probes succeeded",
+			health:       esv1.ElasticsearchGreenHealth,
+			results:      []esv1.HealthProbeStatus{{Name: "search", Succeeded: true}, {Name: "ingest", Succeeded: true}},
+			wantResults:  []esv1.HealthProbeStatus{{Name: "search", Succeeded: true}, {Name: "ingest", Succeeded: true}},
+			wantDegraded: corev1.ConditionFalse,
+		},
+		{
+			name:            "a probe failed on a green cluster",
+			health:          esv1.ElasticsearchGreenHealth,
+			results:         []esv1.HealthProbeStatus{{Name: "search", Succeeded: true}, {Name: "ingest", Message: "503 Service Unavailable"}},
+			wantResults:     []esv1.HealthProbeStatus{{Name: "search", Succeeded: true}, {Name: "ingest", Message: "503 Service Unavailable"}},
+			wantDegraded:    corev1.ConditionTrue,
+			wantDegradedMsg: "Health probes failed: ingest",
+		},
+		{
+			name:            "probes failed on a yellow cluster",
+			health:          esv1.ElasticsearchYellowHealth,
+			results:         []esv1.HealthProbeStatus{{Name: "search"}, {Name: "ingest"}},
+			wantResults:     []esv1.HealthProbeStatus{{Name: "search"}, {Name: "ingest"}},
+			wantDegraded:    corev1.ConditionTrue,
+			wantDegradedMsg: "Health is yellow. Health probes failed: search, ingest",
+		},
+		{
+			name:         "results of probes removed from the spec are ignored",
+			health:       esv1.ElasticsearchGreenHealth,
+			results:      []esv1.HealthProbeStatus{{Name: "search", Succeeded: true}, {Name: "removed"}},
+			wantResults:  []esv1.HealthProbeStatus{{Name: "search", Succeeded: true}},
+			wantDegraded: corev1.ConditionFalse,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := MustNewState(cluster)
+			s.UpdateClusterHealth(tt.health).UpdateHealthProbes(tt.results)
+			s.UpdateStandardConditions(true, "")
+			assert.Equal(t, tt.wantResults, s.status.HealthProbes)
+			index := s.status.Conditions.Index(commonv1alpha1.DegradedCondition)
+			assert.GreaterOrEqual(t, index, 0)
+			assert.Equal(t, tt.wantDegraded, s.status.Conditions[index].Status)
+			assert.Equal(t, tt.wantDegradedMsg, s.status.Conditions[index].Message)
+		})
+	}
+}
+
+func TestState_UpdateStandardConditions_ExpectedNodes(t *testing.T) {
+	cluster := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{NodeSets: []esv1.NodeSet{
+		{Name: "master", Count: 3},
+		{Name: "data", Count: 2},
+	}}}
+	s := MustNewState(cluster)
+	s.status.AvailableNodes = 4
+	s.UpdateClusterHealth(esv1.ElasticsearchGreenHealth)
+	s.UpdateStandardConditions(true, "")
+	assert.Equal(t, int32(5), s.status.ExpectedNodes)
+	index := s.status.Conditions.Index(commonv1alpha1.DegradedCondition)
+	assert.GreaterOrEqual(t, index, 0)
+	assert.Equal(t, "4 out of 5 instances available", s.status.Conditions[index].Message)
+}
@@ -9,6 +9,8 @@ import (
 	"sort"
 	"strings"
 
+	"k8s.io/utils/ptr"
+
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 )
@@ -42,24 +44,19 @@ func annotateWithCreatedRemoteClusters(ctx context.Context, c k8s.Client, es esv
 
 		// if the annotation exists, delete it
 		if _, ok := es.Annotations[ManagedRemoteClustersAnnotationName]; ok {
-			delete(es.Annotations, ManagedRemoteClustersAnnotationName)
-			return c.Update(ctx, &es)
+			return k8s.PatchAnnotations(ctx, c, &es, map[string]*string{ManagedRemoteClustersAnnotationName: nil})
 		}
 
 		return nil
 	}
 
-	if es.Annotations == nil {
-		es.Annotations = make(map[string]string)
-	}
-
 	annotation := make([]string, 0, len(remoteClusters))
 	for remoteCluster := range remoteClusters {
 		annotation = append(annotation, remoteCluster)
 	}
 
 	sort.Strings(annotation)
-	es.Annotations[ManagedRemoteClustersAnnotationName] = strings.Join(annotation, ",")
-
-	return c.Update(ctx, &es)
+	return k8s.PatchAnnotations(ctx, c, &es, map[string]*string{
+		ManagedRemoteClustersAnnotationName: ptr.To(strings.Join(annotation, ",")),
+	})
 }
@@ -36,7 +36,11 @@ func UpdateSettings(
 	licenseChecker license.Checker,
 	es esv1.Elasticsearch,
 ) (bool, error) {
-	remoteClustersInSpec := getRemoteClustersInSpec(es)
+	remoteClusters, err := RemoteClusters(ctx, c, es)
+	if err != nil {
+		return true, err
+	}
+	remoteClustersInSpec := getRemoteClustersInSpec(es, remoteClusters)
 	isRemoteClustersSpec := len(remoteClustersInSpec) > 0
 	_, isRemoteClustersAnnotation := es.Annotations[ManagedRemoteClustersAnnotationName]
 
@@ -166,11 +170,12 @@ func getRemoteClustersInElasticsearch(ctx context.Context, esClient esclient.Cli
 	return remoteClustersInEs, nil
 }
 
-// getRemoteClustersInSpec returns a map with the expected remote clusters as declared by the user in the Elasticsearch specification.
+// getRemoteClustersInSpec returns a map with the expected remote clusters as declared by the user in the Elasticsearch specification,
+// or in the Kibana resources associated with the cluster.
 // A map is returned here because it will be used to quickly compare with the ones that are new or missing.
-func getRemoteClustersInSpec(es esv1.Elasticsearch) map[string]esv1.RemoteCluster {
+func getRemoteClustersInSpec(es esv1.Elasticsearch, declared []esv1.RemoteCluster) map[string]esv1.RemoteCluster {
 	remoteClusters := make(map[string]esv1.RemoteCluster)
-	for _, remoteCluster := range es.Spec.RemoteClusters {
+	for _, remoteCluster := range declared {
 		if !remoteCluster.ElasticsearchRef.IsDefined() {
 			continue
 		}
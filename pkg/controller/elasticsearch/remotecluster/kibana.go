@@ -0,0 +1,137 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package remotecluster
+
+import (
+	"context"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+// KibanaRemoteClusters returns the remote clusters declared in Kibana resources, indexed by the Elasticsearch cluster
+// each Kibana is associated with. Elasticsearch references are resolved relatively to the namespace of the Kibana resource.
+func KibanaRemoteClusters(ctx context.Context, c k8s.Client) (map[types.NamespacedName][]esv1.RemoteCluster, error) {
+	var kibanas kbv1.KibanaList
+	if err := c.List(ctx, &kibanas); err != nil {
+		return nil, err
+	}
+	// sort Kibana resources to consistently pick the same remote cluster if the same name is declared in several Kibanas
+	sort.Slice(kibanas.Items, func(i, j int) bool {
+		return k8s.ExtractNamespacedName(&kibanas.Items[i]).String() < k8s.ExtractNamespacedName(&kibanas.Items[j]).String()
+	})
+	remoteClusters := make(map[types.NamespacedName][]esv1.RemoteCluster)
+	for _, kb := range kibanas.Items {
+		refs := ElasticsearchRefsForKibana(kb)
+		if len(refs) == 0 {
+			continue
+		}
+		es := refs[0]
+		for _, remoteCluster := range kb.Spec.RemoteClusters {
+			remoteClusters[es] = append(remoteClusters[es], esv1.RemoteCluster{
+				Name:             remoteCluster.Name,
+				ElasticsearchRef: remoteCluster.ElasticsearchRef.WithDefaultNamespace(kb.Namespace),
+			})
+		}
+	}
+	return remoteClusters, nil
+}
+
+// WithKibanaRemoteClusters returns the remote clusters declared in the spec of the given Elasticsearch cluster, followed
+// by the ones declared in the Kibana resources associated with it. Remote clusters declared in Kibana are ignored if
+// their name is already in use.
+func WithKibanaRemoteClusters(es esv1.Elasticsearch, kibanaRemoteClusters map[types.NamespacedName][]esv1.RemoteCluster) []esv1.RemoteCluster {
+	fromKibana := kibanaRemoteClusters[k8s.ExtractNamespacedName(&es)]
+	if len(fromKibana) == 0 {
+		return es.Spec.RemoteClusters
+	}
+	remoteClusters := make([]esv1.RemoteCluster, 0, len(es.Spec.RemoteClusters)+len(fromKibana))
+	remoteClusters = append(remoteClusters, es.Spec.RemoteClusters...)
+	names := make(map[string]struct{}, len(remoteClusters))
+	for _, remoteCluster := range remoteClusters {
+		names[remoteCluster.Name] = struct{}{}
+	}
+	for _, remoteCluster := range fromKibana {
+		if _, exists := names[remoteCluster.Name]; exists {
+			continue
+		}
+		names[remoteCluster.Name] = struct{}{}
+		remoteClusters = append(remoteClusters, remoteCluster)
+	}
+	return remoteClusters
+}
+
+// RemoteClusters returns the remote clusters of the given Elasticsearch cluster, including the ones declared in the
+// Kibana resources associated with it.
+func RemoteClusters(ctx context.Context, c k8s.Client, es esv1.Elasticsearch) ([]esv1.RemoteCluster, error) {
+	kibanaRemoteClusters, err := KibanaRemoteClusters(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	return WithKibanaRemoteClusters(es, kibanaRemoteClusters), nil
+}
+
+// ElasticsearchRefsForKibana returns the Elasticsearch clusters involved in the remote clusters declared in the given
+// Kibana: the cluster Kibana is associated with, in which the remote clusters are configured, followed by the remote ones.
+func ElasticsearchRefsForKibana(kb kbv1.Kibana) []types.NamespacedName {
+	if len(kb.Spec.RemoteClusters) == 0 || !kb.Spec.ElasticsearchRef.IsDefined() || kb.Spec.ElasticsearchRef.IsExternal() {
+		return nil
+	}
+	refs := make([]types.NamespacedName, 0, len(kb.Spec.RemoteClusters)+1)
+	refs = append(refs, kb.Spec.ElasticsearchRef.WithDefaultNamespace(kb.Namespace).NamespacedName())
+	for _, remoteCluster := range kb.Spec.RemoteClusters {
+		refs = append(refs, remoteCluster.ElasticsearchRef.WithDefaultNamespace(kb.Namespace).NamespacedName())
+	}
+	return refs
+}
+
+// PrimaryElasticsearchRefForKibana returns the Elasticsearch cluster in which the remote clusters declared in the given
+// Kibana are configured, if any.
+func PrimaryElasticsearchRefForKibana(kb kbv1.Kibana) []types.NamespacedName {
+	refs := ElasticsearchRefsForKibana(kb)
+	if len(refs) == 0 {
+		return nil
+	}
+	return refs[:1]
+}
+
+// KibanaEventHandler returns an event handler enqueuing the Elasticsearch clusters returned by refs for the Kibana
+// resources declaring remote clusters. Both the old and the new version of an updated Kibana are considered, so that
+// the clusters which are not involved anymore, because the remote clusters or the elasticsearchRef changed, are also
+// reconciled to remove the remote cluster settings and the trusted certificates.
+func KibanaEventHandler(refs func(kbv1.Kibana) []types.NamespacedName) handler.TypedEventHandler[*kbv1.Kibana, reconcile.Request] {
+	enqueue := func(q workqueue.TypedRateLimitingInterface[reconcile.Request], kibanas ...*kbv1.Kibana) {
+		for _, kb := range kibanas {
+			if kb == nil {
+				continue
+			}
+			for _, ref := range refs(*kb) {
+				q.Add(reconcile.Request{NamespacedName: ref})
+			}
+		}
+	}
+	return handler.TypedFuncs[*kbv1.Kibana, reconcile.Request]{
+		CreateFunc: func(_ context.Context, e event.TypedCreateEvent[*kbv1.Kibana], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(q, e.Object)
+		},
+		UpdateFunc: func(_ context.Context, e event.TypedUpdateEvent[*kbv1.Kibana], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(q, e.ObjectOld, e.ObjectNew)
+		},
+		DeleteFunc: func(_ context.Context, e event.TypedDeleteEvent[*kbv1.Kibana], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(q, e.Object)
+		},
+		GenericFunc: func(_ context.Context, e event.TypedGenericEvent[*kbv1.Kibana], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(q, e.Object)
+		},
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package remotecluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func kibanaWithRemoteClusters(namespace, name string, esRef commonv1.ObjectSelector, remoteClusters ...kbv1.RemoteCluster) *kbv1.Kibana {
+	return &kbv1.Kibana{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       kbv1.KibanaSpec{ElasticsearchRef: esRef, RemoteClusters: remoteClusters},
+	}
+}
+
+func TestRemoteClusters(t *testing.T) {
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "central", Name: "es"},
+		Spec: esv1.ElasticsearchSpec{RemoteClusters: []esv1.RemoteCluster{
+			{Name: "spoke-1", ElasticsearchRef: commonv1.LocalObjectSelector{Name: "spoke-1"}},
+		}},
+	}
+	c := k8s.NewFakeClient(
+		// associated with central/es, spoke-1 is already declared in the Elasticsearch spec
+		kibanaWithRemoteClusters("central", "kb-a", commonv1.ObjectSelector{Name: "es"},
+			kbv1.RemoteCluster{Name: "spoke-1", ElasticsearchRef: commonv1.LocalObjectSelector{Name: "other"}},
+			kbv1.RemoteCluster{Name: "spoke-2", ElasticsearchRef: commonv1.LocalObjectSelector{Namespace: "spokes", Name: "spoke-2"}},
+		),
+		// associated with central/es from another namespace, references are relative to the Kibana namespace
+		kibanaWithRemoteClusters("team", "kb-b", commonv1.ObjectSelector{Namespace: "central", Name: "es"},
+			kbv1.RemoteCluster{Name: "spoke-2", ElasticsearchRef: commonv1.LocalObjectSelector{Name: "ignored"}},
+			kbv1.RemoteCluster{Name: "spoke-3", ElasticsearchRef: commonv1.LocalObjectSelector{Name: "spoke-3"}},
+		),
+		// not associated with central/es
+		kibanaWithRemoteClusters("central", "kb-c", commonv1.ObjectSelector{Name: "other-es"},
+			kbv1.RemoteCluster{Name: "spoke-4", ElasticsearchRef: commonv1.LocalObjectSelector{Name: "spoke-4"}},
+		),
+		// external Elasticsearch cluster
+		kibanaWithRemoteClusters("central", "kb-d", commonv1.ObjectSelector{SecretName: "es"},
+			kbv1.RemoteCluster{Name: "spoke-5", ElasticsearchRef: commonv1.LocalObjectSelector{Name: "spoke-5"}},
+		),
+	)
+
+	remoteClusters, err := RemoteClusters(context.Background(), c, es)
+	require.NoError(t, err)
+	require.Equal(t, []esv1.RemoteCluster{
+		{Name: "spoke-1", ElasticsearchRef: commonv1.LocalObjectSelector{Name: "spoke-1"}},
+		{Name: "spoke-2", ElasticsearchRef: commonv1.LocalObjectSelector{Namespace: "spokes", Name: "spoke-2"}},
+		{Name: "spoke-3", ElasticsearchRef: commonv1.LocalObjectSelector{Namespace: "team", Name: "spoke-3"}},
+	}, remoteClusters)
+
+	// the spec is returned as is if there are no remote clusters declared in Kibana
+	es.Name = "no-kibana"
+	remoteClusters, err = RemoteClusters(context.Background(), c, es)
+	require.NoError(t, err)
+	require.Equal(t, es.Spec.RemoteClusters, remoteClusters)
+}
+
+func TestElasticsearchRefsForKibana(t *testing.T) {
+	require.Nil(t, ElasticsearchRefsForKibana(*kibanaWithRemoteClusters("ns", "kb", commonv1.ObjectSelector{Name: "es"})))
+	require.Nil(t, ElasticsearchRefsForKibana(*kibanaWithRemoteClusters("ns", "kb", commonv1.ObjectSelector{},
+		kbv1.RemoteCluster{Name: "spoke", ElasticsearchRef: commonv1.LocalObjectSelector{Name: "spoke"}},
+	)))
+	require.Equal(t,
+		[]types.NamespacedName{{Namespace: "central", Name: "es"}, {Namespace: "ns", Name: "spoke-1"}, {Namespace: "spokes", Name: "spoke-2"}},
+		ElasticsearchRefsForKibana(*kibanaWithRemoteClusters("ns", "kb", commonv1.ObjectSelector{Namespace: "central", Name: "es"},
+			kbv1.RemoteCluster{Name: "spoke-1", ElasticsearchRef: commonv1.LocalObjectSelector{Name: "spoke-1"}},
+			kbv1.RemoteCluster{Name: "spoke-2", ElasticsearchRef: commonv1.LocalObjectSelector{Namespace: "spokes", Name: "spoke-2"}},
+		)),
+	)
+}
+
+func TestKibanaEventHandler(t *testing.T) {
+	old := kibanaWithRemoteClusters("ns", "kb", commonv1.ObjectSelector{Name: "es"},
+		kbv1.RemoteCluster{Name: "spoke-1", ElasticsearchRef: commonv1.LocalObjectSelector{Name: "spoke-1"}},
+	)
+	// remote clusters removed and associated with another cluster
+	updated := kibanaWithRemoteClusters("ns", "kb", commonv1.ObjectSelector{Name: "other-es"},
+		kbv1.RemoteCluster{Name: "spoke-2", ElasticsearchRef: commonv1.LocalObjectSelector{Name: "spoke-2"}},
+	)
+	tests := []struct {
+		name string
+		refs func(kbv1.Kibana) []types.NamespacedName
+		want []string
+	}{
+		{
+			name: "all the clusters involved",
+			refs: ElasticsearchRefsForKibana,
+			want: []string{"ns/es", "ns/other-es", "ns/spoke-1", "ns/spoke-2"},
+		},
+		{
+			name: "primary clusters only",
+			refs: PrimaryElasticsearchRefForKibana,
+			want: []string{"ns/es", "ns/other-es"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+			defer q.ShutDown()
+			KibanaEventHandler(tt.refs).Update(context.Background(), event.TypedUpdateEvent[*kbv1.Kibana]{ObjectOld: old, ObjectNew: updated}, q)
+			var got []string
+			for q.Len() > 0 {
+				request, _ := q.Get()
+				got = append(got, request.String())
+				q.Done(request)
+			}
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,240 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package resourcerecommendation
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"go.elastic.co/apm/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/pod"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/nodespec"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
+)
+
+const (
+	// heapPressurePercent is the heap usage above which more memory is recommended.
+	heapPressurePercent = 85
+	// heapIdlePercent is the heap usage below which less memory is recommended.
+	heapIdlePercent = 40
+	// gcPressureRatio is the share of time spent in old garbage collections above which more memory is recommended.
+	gcPressureRatio = 0.05
+	// gcIdleRatio is the share of time spent in old garbage collections below which less memory may be recommended.
+	gcIdleRatio = 0.01
+	// cpuThrottlingPressureRatio is the share of throttled CPU periods above which more CPU is recommended.
+	cpuThrottlingPressureRatio = 0.25
+	// cpuThrottlingIdleRatio is the share of throttled CPU periods below which less CPU may be recommended.
+	cpuThrottlingIdleRatio = 0.01
+	// cpuIdlePercent is the CPU usage below which less CPU is recommended.
+	cpuIdlePercent = 20
+
+	increaseFactor = 1.5
+	decreaseFactor = 0.75
+
+	memoryGranularityBytes = 256 * 1024 * 1024
+	cpuGranularityMillis   = 100
+)
+
+var (
+	minMemory = nodespec.DefaultMemoryLimits
+	minCPU    = resource.MustParse("500m")
+)
+
+// Enabled returns true if the resource recommendations are enabled for the cluster.
+func Enabled(es esv1.Elasticsearch) bool {
+	return es.Spec.ResourceRecommendations != nil
+}
+
+// Evaluate computes the resources recommended for the Elasticsearch containers of each NodeSet from the statistics of
+// their nodes. It returns the recommendations to report, and the duration after which the next computation is due.
+// Nil recommendations are returned if the resource recommendations are not enabled.
+func Evaluate(ctx context.Context, esClient esclient.Client, es esv1.Elasticsearch) ([]esv1.ResourceRecommendation, time.Duration, error) {
+	if !Enabled(es) {
+		return nil, 0, nil
+	}
+
+	span, ctx := apm.StartSpan(ctx, "evaluate_resource_recommendations", tracing.SpanTypeApp)
+	defer span.End()
+
+	stats, err := esClient.GetResourceStats(ctx)
+	if err != nil {
+		return es.Status.ResourceRecommendations, 0, err
+	}
+
+	recommendations := make([]esv1.ResourceRecommendation, 0, len(es.Spec.NodeSets))
+	for _, nodeSet := range es.Spec.NodeSets {
+		nodes := nodeSetStats(stats, esv1.StatefulSet(es.Name, nodeSet.Name), nodeSet.Count)
+		previous := es.Status.ResourceRecommendationFor(nodeSet.Name)
+		recommendations = append(recommendations, recommend(nodeSet.Name, nodeSet.Count, currentResources(nodeSet), previous, nodes))
+	}
+	return recommendations, es.Spec.ResourceRecommendations.IntervalOrDefault(), nil
+}
+
+// currentResources returns the resources of the Elasticsearch container of the given NodeSet.
+func currentResources(nodeSet esv1.NodeSet) corev1.ResourceRequirements {
+	container := pod.ContainerByName(nodeSet.PodTemplate.Spec, esv1.ElasticsearchContainerName)
+	if container == nil || (container.Resources.Requests == nil && container.Resources.Limits == nil) {
+		return nodespec.DefaultResources
+	}
+	return container.Resources
+}
+
+// nodeSetStats returns the statistics of the nodes of the given StatefulSet expected to be running.
+func nodeSetStats(stats esclient.NodesStats, statefulSetName string, count int32) []esclient.NodeStats {
+	var nodes []esclient.NodeStats
+	for _, node := range stats.Nodes {
+		// the node names are the names of the Pods
+		name, ordinal, err := sset.StatefulSetName(node.Name)
+		if err != nil || name != statefulSetName || ordinal >= count {
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// pressure is the highest pressure observed on the nodes of a NodeSet.
+type pressure struct {
+	heapUsedPercent    int64
+	gcTimeRatio        float64
+	cpuPercent         int64
+	cpuThrottledRatio  float64
+	indexingRejections int64
+}
+
+// observe aggregates the statistics of the given nodes. The garbage collection and CPU throttling ratios are computed
+// since the start of each node.
+func observe(nodes []esclient.NodeStats) pressure {
+	var p pressure
+	for _, node := range nodes {
+		p.heapUsedPercent = max(p.heapUsedPercent, node.JVM.Mem.HeapUsedPercent)
+		if node.JVM.UptimeInMillis > 0 {
+			p.gcTimeRatio = max(p.gcTimeRatio, float64(node.JVM.GC.Collectors.Old.CollectionTimeInMillis)/float64(node.JVM.UptimeInMillis))
+		}
+		p.cpuPercent = max(p.cpuPercent, node.OS.CPU.Percent)
+		if cgroup := node.OS.CGroup; cgroup != nil && cgroup.CPU.Stat.NumberOfElapsedPeriods > 0 {
+			p.cpuThrottledRatio = max(p.cpuThrottledRatio, float64(cgroup.CPU.Stat.NumberOfTimesThrottled)/float64(cgroup.CPU.Stat.NumberOfElapsedPeriods))
+		}
+		p.indexingRejections += node.IndexingPressure.Memory.Total.Rejections()
+	}
+	return p
+}
+
+// recommend computes the resources recommended for the Elasticsearch containers of a NodeSet. More memory is recommended
+// under heap, garbage collection or indexing pressure, and more CPU when the containers are throttled. Less of them is
+// recommended when the nodes are mostly idle. The previous recommendation is kept until all the expected nodes are
+// running.
+func recommend(
+	nodeSet string,
+	count int32,
+	current corev1.ResourceRequirements,
+	previous *esv1.ResourceRecommendation,
+	nodes []esclient.NodeStats,
+) esv1.ResourceRecommendation {
+	p := observe(nodes)
+	recommendation := esv1.ResourceRecommendation{NodeSet: nodeSet, IndexingPressureRejections: p.indexingRejections}
+	if len(nodes) == 0 || len(nodes) < int(count) {
+		if previous != nil {
+			recommendation.Memory, recommendation.CPU, recommendation.Reasons = previous.Memory, previous.CPU, previous.Reasons
+		}
+		return recommendation
+	}
+	// the rejections observed before the first computation cannot be attributed to the current load, and the
+	// rejection counters are reset when the nodes restart
+	var newRejections int64
+	if previous != nil {
+		newRejections = max(0, p.indexingRejections-previous.IndexingPressureRejections)
+	}
+
+	if memory, ok := currentQuantity(current, corev1.ResourceMemory); ok {
+		var reasons []string
+		if p.heapUsedPercent >= heapPressurePercent {
+			reasons = append(reasons, fmt.Sprintf("heap usage reached %d%%", p.heapUsedPercent))
+		}
+		if p.gcTimeRatio >= gcPressureRatio {
+			reasons = append(reasons, fmt.Sprintf("old garbage collections took %.0f%% of the time", p.gcTimeRatio*100))
+		}
+		if newRejections > 0 {
+			reasons = append(reasons, fmt.Sprintf("%d indexing requests were rejected because of indexing pressure", newRejections))
+		}
+		recommended := memory
+		switch {
+		case len(reasons) > 0:
+			recommended = scaleMemory(memory, increaseFactor)
+		case p.heapUsedPercent < heapIdlePercent && p.gcTimeRatio < gcIdleRatio:
+			recommended = minQuantity(memory, maxQuantity(minMemory, scaleMemory(memory, decreaseFactor)))
+			reasons = append(reasons, fmt.Sprintf("heap usage stayed below %d%%", heapIdlePercent))
+		}
+		recommendation.Memory = &recommended
+		if recommended.Cmp(memory) != 0 {
+			recommendation.Reasons = append(recommendation.Reasons, reasons...)
+		}
+	}
+
+	if cpu, ok := currentQuantity(current, corev1.ResourceCPU); ok {
+		var reason string
+		recommended := cpu
+		switch {
+		case p.cpuThrottledRatio >= cpuThrottlingPressureRatio:
+			recommended = scaleCPU(cpu, increaseFactor)
+			reason = fmt.Sprintf("CPU was throttled during %.0f%% of the periods", p.cpuThrottledRatio*100)
+		case p.cpuPercent < cpuIdlePercent && p.cpuThrottledRatio < cpuThrottlingIdleRatio:
+			recommended = minQuantity(cpu, maxQuantity(minCPU, scaleCPU(cpu, decreaseFactor)))
+			reason = fmt.Sprintf("CPU usage stayed below %d%%", cpuIdlePercent)
+		}
+		recommendation.CPU = &recommended
+		if recommended.Cmp(cpu) != 0 {
+			recommendation.Reasons = append(recommendation.Reasons, reason)
+		}
+	}
+	return recommendation
+}
+
+// currentQuantity returns the limit of the given resource, or its request if there is no limit.
+func currentQuantity(resources corev1.ResourceRequirements, name corev1.ResourceName) (resource.Quantity, bool) {
+	if quantity, ok := resources.Limits[name]; ok {
+		return quantity, true
+	}
+	quantity, ok := resources.Requests[name]
+	return quantity, ok
+}
+
+// scaleMemory multiplies the given memory quantity by the given factor, rounded up to a multiple of 256Mi.
+func scaleMemory(memory resource.Quantity, factor float64) resource.Quantity {
+	bytes := roundUp(float64(memory.Value())*factor, memoryGranularityBytes)
+	return *resource.NewQuantity(bytes, resource.BinarySI)
+}
+
+// scaleCPU multiplies the given CPU quantity by the given factor, rounded up to a multiple of 100m.
+func scaleCPU(cpu resource.Quantity, factor float64) resource.Quantity {
+	millis := roundUp(float64(cpu.MilliValue())*factor, cpuGranularityMillis)
+	return *resource.NewMilliQuantity(millis, resource.DecimalSI)
+}
+
+func roundUp(value float64, granularity int64) int64 {
+	return int64(math.Ceil(value/float64(granularity))) * granularity
+}
+
+func maxQuantity(a, b resource.Quantity) resource.Quantity {
+	if a.Cmp(b) > 0 {
+		return a
+	}
+	return b
+}
+
+func minQuantity(a, b resource.Quantity) resource.Quantity {
+	if a.Cmp(b) < 0 {
+		return a
+	}
+	return b
+}
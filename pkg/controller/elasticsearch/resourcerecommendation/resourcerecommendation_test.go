@@ -0,0 +1,222 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package resourcerecommendation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+)
+
+type fakeESClient struct {
+	esclient.Client
+	stats esclient.NodesStats
+}
+
+func (f *fakeESClient) GetResourceStats(_ context.Context) (esclient.NodesStats, error) {
+	return f.stats, nil
+}
+
+type nodeStatsBuilder struct {
+	stats esclient.NodeStats
+}
+
+func newNodeStats(name string) *nodeStatsBuilder {
+	b := &nodeStatsBuilder{stats: esclient.NodeStats{Name: name}}
+	b.stats.JVM.UptimeInMillis = 3600000
+	b.stats.JVM.Mem.HeapUsedPercent = 60
+	b.stats.OS.CPU.Percent = 50
+	return b
+}
+
+func (b *nodeStatsBuilder) withHeap(percent int64) *nodeStatsBuilder {
+	b.stats.JVM.Mem.HeapUsedPercent = percent
+	return b
+}
+
+func (b *nodeStatsBuilder) withOldGCTime(millis int64) *nodeStatsBuilder {
+	b.stats.JVM.GC.Collectors.Old.CollectionTimeInMillis = millis
+	return b
+}
+
+func (b *nodeStatsBuilder) withCPU(percent int64) *nodeStatsBuilder {
+	b.stats.OS.CPU.Percent = percent
+	return b
+}
+
+func (b *nodeStatsBuilder) withThrottling(throttled, elapsed int64) *nodeStatsBuilder {
+	b.stats.OS.CGroup = &esclient.CGroup{}
+	b.stats.OS.CGroup.CPU.Stat.NumberOfTimesThrottled = throttled
+	b.stats.OS.CGroup.CPU.Stat.NumberOfElapsedPeriods = elapsed
+	return b
+}
+
+func (b *nodeStatsBuilder) withRejections(rejections int64) *nodeStatsBuilder {
+	b.stats.IndexingPressure.Memory.Total.PrimaryRejections = rejections
+	return b
+}
+
+func (b *nodeStatsBuilder) build() esclient.NodeStats {
+	return b.stats
+}
+
+func resources(memory, cpu string) corev1.ResourceRequirements {
+	return corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(memory), corev1.ResourceCPU: resource.MustParse(cpu)},
+		Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(memory)},
+	}
+}
+
+func quantity(value string) *resource.Quantity {
+	return ptr.To(resource.MustParse(value))
+}
+
+func Test_recommend(t *testing.T) {
+	tests := []struct {
+		name     string
+		count    int32
+		previous *esv1.ResourceRecommendation
+		nodes    []esclient.NodeStats
+		want     esv1.ResourceRecommendation
+	}{
+		{
+			name:  "balanced usage",
+			count: 2,
+			nodes: []esclient.NodeStats{newNodeStats("a").build(), newNodeStats("b").build()},
+			want:  esv1.ResourceRecommendation{NodeSet: "default", Memory: quantity("4Gi"), CPU: quantity("2")},
+		},
+		{
+			name:  "heap pressure on one node",
+			count: 2,
+			nodes: []esclient.NodeStats{newNodeStats("a").build(), newNodeStats("b").withHeap(91).build()},
+			want: esv1.ResourceRecommendation{NodeSet: "default", Memory: quantity("6Gi"), CPU: quantity("2"),
+				Reasons: []string{"heap usage reached 91%"}},
+		},
+		{
+			name:  "garbage collection pressure",
+			count: 1,
+			nodes: []esclient.NodeStats{newNodeStats("a").withOldGCTime(360000).build()},
+			want: esv1.ResourceRecommendation{NodeSet: "default", Memory: quantity("6Gi"), CPU: quantity("2"),
+				Reasons: []string{"old garbage collections took 10% of the time"}},
+		},
+		{
+			name:     "new indexing pressure rejections",
+			count:    1,
+			previous: &esv1.ResourceRecommendation{NodeSet: "default", IndexingPressureRejections: 10},
+			nodes:    []esclient.NodeStats{newNodeStats("a").withRejections(15).build()},
+			want: esv1.ResourceRecommendation{NodeSet: "default", Memory: quantity("6Gi"), CPU: quantity("2"), IndexingPressureRejections: 15,
+				Reasons: []string{"5 indexing requests were rejected because of indexing pressure"}},
+		},
+		{
+			name:  "rejections before the first computation are ignored",
+			count: 1,
+			nodes: []esclient.NodeStats{newNodeStats("a").withRejections(15).build()},
+			want:  esv1.ResourceRecommendation{NodeSet: "default", Memory: quantity("4Gi"), CPU: quantity("2"), IndexingPressureRejections: 15},
+		},
+		{
+			name:  "CPU throttling",
+			count: 1,
+			nodes: []esclient.NodeStats{newNodeStats("a").withThrottling(400, 1000).build()},
+			want: esv1.ResourceRecommendation{NodeSet: "default", Memory: quantity("4Gi"), CPU: quantity("3"),
+				Reasons: []string{"CPU was throttled during 40% of the periods"}},
+		},
+		{
+			name:  "idle nodes",
+			count: 1,
+			nodes: []esclient.NodeStats{newNodeStats("a").withHeap(20).withCPU(5).build()},
+			want: esv1.ResourceRecommendation{NodeSet: "default", Memory: quantity("3Gi"), CPU: quantity("1500m"),
+				Reasons: []string{"heap usage stayed below 40%", "CPU usage stayed below 20%"}},
+		},
+		{
+			name:     "missing nodes: keep the previous recommendation",
+			count:    2,
+			previous: &esv1.ResourceRecommendation{NodeSet: "default", Memory: quantity("6Gi"), Reasons: []string{"heap usage reached 91%"}},
+			nodes:    []esclient.NodeStats{newNodeStats("a").build()},
+			want:     esv1.ResourceRecommendation{NodeSet: "default", Memory: quantity("6Gi"), Reasons: []string{"heap usage reached 91%"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := recommend("default", tt.count, resources("4Gi", "2"), tt.previous, tt.nodes)
+			require.Equal(t, tt.want.NodeSet, got.NodeSet)
+			requireEqualQuantity(t, tt.want.Memory, got.Memory)
+			requireEqualQuantity(t, tt.want.CPU, got.CPU)
+			require.Equal(t, tt.want.Reasons, got.Reasons)
+			require.Equal(t, tt.want.IndexingPressureRejections, got.IndexingPressureRejections)
+		})
+	}
+}
+
+func Test_recommend_minimumResources(t *testing.T) {
+	// idle nodes already below the minimum resources
+	got := recommend("default", 1, resources("1Gi", "200m"), nil, []esclient.NodeStats{newNodeStats("a").withHeap(20).withCPU(5).build()})
+	requireEqualQuantity(t, quantity("1Gi"), got.Memory)
+	requireEqualQuantity(t, quantity("200m"), got.CPU)
+	require.Empty(t, got.Reasons)
+
+	// idle nodes close to the minimum resources
+	got = recommend("default", 1, resources("2304Mi", "600m"), nil, []esclient.NodeStats{newNodeStats("a").withHeap(20).withCPU(5).build()})
+	requireEqualQuantity(t, quantity("2Gi"), got.Memory)
+	requireEqualQuantity(t, quantity("500m"), got.CPU)
+}
+
+func requireEqualQuantity(t *testing.T, want, got *resource.Quantity) {
+	t.Helper()
+	if want == nil {
+		require.Nil(t, got)
+		return
+	}
+	require.NotNil(t, got)
+	require.Zero(t, want.Cmp(*got), "want %s, got %s", want.String(), got.String())
+}
+
+func TestEvaluate(t *testing.T) {
+	esClient := &fakeESClient{stats: esclient.NodesStats{Nodes: map[string]esclient.NodeStats{
+		"a": newNodeStats("es-es-master-0").build(),
+		"b": newNodeStats("es-es-data-0").withHeap(90).build(),
+		"c": newNodeStats("es-es-data-1").build(),
+		// node of a StatefulSet being scaled down
+		"d": newNodeStats("es-es-data-2").withHeap(99).build(),
+	}}}
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec: esv1.ElasticsearchSpec{NodeSets: []esv1.NodeSet{
+			{Name: "master", Count: 1},
+			{Name: "data", Count: 2, PodTemplate: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{
+				{Name: esv1.ElasticsearchContainerName, Resources: resources("8Gi", "4")},
+			}}}},
+		}},
+	}
+
+	// disabled
+	recommendations, next, err := Evaluate(context.Background(), esClient, es)
+	require.NoError(t, err)
+	require.Nil(t, recommendations)
+	require.Zero(t, next)
+
+	es.Spec.ResourceRecommendations = &esv1.ResourceRecommendations{Interval: &metav1.Duration{Duration: time.Hour}}
+	recommendations, next, err = Evaluate(context.Background(), esClient, es)
+	require.NoError(t, err)
+	require.Equal(t, time.Hour, next)
+	require.Len(t, recommendations, 2)
+	// default resources, without any CPU
+	require.Equal(t, "master", recommendations[0].NodeSet)
+	requireEqualQuantity(t, quantity("2Gi"), recommendations[0].Memory)
+	require.Nil(t, recommendations[0].CPU)
+	require.Empty(t, recommendations[0].Reasons)
+	require.Equal(t, "data", recommendations[1].NodeSet)
+	requireEqualQuantity(t, quantity("12Gi"), recommendations[1].Memory)
+	requireEqualQuantity(t, quantity("4"), recommendations[1].CPU)
+	require.Equal(t, []string{"heap usage reached 90%"}, recommendations[1].Reasons)
+}
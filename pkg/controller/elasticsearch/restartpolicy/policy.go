@@ -0,0 +1,146 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+// Package restartpolicy limits the number of Elasticsearch clusters of a namespace restarting their nodes at the same
+// time, as configured in the restart policy ConfigMap of the namespace. Each restarting cluster holds a restart slot,
+// materialized by a Lease, for the duration of its rolling restart.
+package restartpolicy
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // embed the time zone database, which may be missing from the operator image
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+const (
+	// ConfigMapName is the name of the ConfigMap holding the restart policy of a namespace.
+	ConfigMapName = "elastic-restart-policy"
+
+	// MaxConcurrentRestartsKey is the maximum number of clusters restarting their nodes at the same time.
+	MaxConcurrentRestartsKey = "maxConcurrentRestarts"
+	// SelectorKey is an optional label selector restricting the policy to the matching Elasticsearch clusters.
+	SelectorKey = "selector"
+	// WindowKey is an optional daily time window, formatted as HH:MM-HH:MM, outside which no restart can start.
+	WindowKey = "window"
+	// TimeZoneKey is the IANA time zone of the window, UTC by default.
+	TimeZoneKey = "timeZone"
+)
+
+// Policy limits the number of Elasticsearch clusters of a namespace restarting their nodes at the same time.
+type Policy struct {
+	MaxConcurrentRestarts int
+	Selector              labels.Selector
+	// Window is the daily time window during which restarts can start, nil if restarts can start at any time.
+	Window *Window
+}
+
+// Window is a daily time window in a given time zone.
+type Window struct {
+	// Start and End are the offsets of the bounds of the window from midnight.
+	Start    time.Duration
+	End      time.Duration
+	Location *time.Location
+}
+
+// Contains returns true if the given time is part of the window. A nil window contains any time.
+func (w *Window) Contains(t time.Time) bool {
+	if w == nil {
+		return true
+	}
+	local := t.In(w.Location)
+	offset := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute
+	if w.Start <= w.End {
+		return offset >= w.Start && offset < w.End
+	}
+	// the window spans midnight
+	return offset >= w.Start || offset < w.End
+}
+
+// Get returns the restart policy of the given namespace, or nil if the namespace does not have one.
+func Get(ctx context.Context, c k8s.Client, namespace string) (*Policy, error) {
+	var cm corev1.ConfigMap
+	err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ConfigMapName}, &cm)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	policy, err := Parse(cm.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid restart policy %s/%s: %w", namespace, ConfigMapName, err)
+	}
+	return policy, nil
+}
+
+// Parse returns the restart policy defined by the data of a restart policy ConfigMap.
+func Parse(data map[string]string) (*Policy, error) {
+	maxConcurrentRestarts, err := strconv.Atoi(data[MaxConcurrentRestartsKey])
+	if err != nil || maxConcurrentRestarts < 1 {
+		return nil, fmt.Errorf("%s must be a positive integer, got %q", MaxConcurrentRestartsKey, data[MaxConcurrentRestartsKey])
+	}
+	selector, err := labels.Parse(data[SelectorKey])
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", SelectorKey, err)
+	}
+	window, err := parseWindow(data[WindowKey], data[TimeZoneKey])
+	if err != nil {
+		return nil, err
+	}
+	return &Policy{MaxConcurrentRestarts: maxConcurrentRestarts, Selector: selector, Window: window}, nil
+}
+
+func parseWindow(window, timeZone string) (*Window, error) {
+	if window == "" {
+		if timeZone != "" {
+			return nil, fmt.Errorf("%s requires %s", TimeZoneKey, WindowKey)
+		}
+		return nil, nil
+	}
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", TimeZoneKey, err)
+	}
+	start, end, found := strings.Cut(window, "-")
+	if !found {
+		return nil, fmt.Errorf("%s must be formatted as HH:MM-HH:MM, got %q", WindowKey, window)
+	}
+	startOffset, err := parseTimeOfDay(start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", WindowKey, err)
+	}
+	endOffset, err := parseTimeOfDay(end)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", WindowKey, err)
+	}
+	if startOffset == endOffset {
+		return nil, fmt.Errorf("%s cannot be empty, got %q", WindowKey, window)
+	}
+	return &Window{Start: startOffset, End: endOffset, Location: location}, nil
+}
+
+// parseTimeOfDay returns the offset from midnight of a time of day formatted as HH:MM.
+func parseTimeOfDay(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Applies returns true if the policy limits the restarts of the given cluster.
+func (p *Policy) Applies(es esv1.Elasticsearch) bool {
+	return p != nil && p.Selector.Matches(labels.Set(es.Labels))
+}
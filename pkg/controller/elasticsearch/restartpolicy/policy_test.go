@@ -0,0 +1,163 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package restartpolicy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       map[string]string
+		wantErr    bool
+		wantMax    int
+		wantWindow bool
+	}{
+		{
+			name:    "max concurrent restarts only",
+			data:    map[string]string{MaxConcurrentRestartsKey: "2"},
+			wantMax: 2,
+		},
+		{
+			name:       "selector and window",
+			data:       map[string]string{MaxConcurrentRestartsKey: "1", SelectorKey: "env=prod", WindowKey: "22:00-06:00", TimeZoneKey: "Europe/Paris"},
+			wantMax:    1,
+			wantWindow: true,
+		},
+		{
+			name:    "missing max concurrent restarts",
+			data:    map[string]string{SelectorKey: "env=prod"},
+			wantErr: true,
+		},
+		{
+			name:    "zero max concurrent restarts",
+			data:    map[string]string{MaxConcurrentRestartsKey: "0"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid selector",
+			data:    map[string]string{MaxConcurrentRestartsKey: "1", SelectorKey: "env in prod"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid window",
+			data:    map[string]string{MaxConcurrentRestartsKey: "1", WindowKey: "22h-6h"},
+			wantErr: true,
+		},
+		{
+			name:    "empty window",
+			data:    map[string]string{MaxConcurrentRestartsKey: "1", WindowKey: "22:00-22:00"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown time zone",
+			data:    map[string]string{MaxConcurrentRestartsKey: "1", WindowKey: "22:00-06:00", TimeZoneKey: "Mars/Olympus"},
+			wantErr: true,
+		},
+		{
+			name:    "time zone without window",
+			data:    map[string]string{MaxConcurrentRestartsKey: "1", TimeZoneKey: "Europe/Paris"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := Parse(tt.data)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantMax, policy.MaxConcurrentRestarts)
+			require.Equal(t, tt.wantWindow, policy.Window != nil)
+		})
+	}
+}
+
+func TestWindow_Contains(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	daytime := &Window{Start: 9 * time.Hour, End: 17*time.Hour + 30*time.Minute, Location: time.UTC}
+	overnight := &Window{Start: 22 * time.Hour, End: 6 * time.Hour, Location: paris}
+	tests := []struct {
+		name   string
+		window *Window
+		time   time.Time
+		want   bool
+	}{
+		{
+			name:   "no window",
+			window: nil,
+			time:   time.Date(2024, 1, 1, 3, 0, 0, 0, time.UTC),
+			want:   true,
+		},
+		{
+			name:   "within a daytime window",
+			window: daytime,
+			time:   time.Date(2024, 1, 1, 17, 29, 0, 0, time.UTC),
+			want:   true,
+		},
+		{
+			name:   "end of a daytime window",
+			window: daytime,
+			time:   time.Date(2024, 1, 1, 17, 30, 0, 0, time.UTC),
+			want:   false,
+		},
+		{
+			name:   "within an overnight window after midnight in its time zone",
+			window: overnight,
+			// 03:00 in Paris
+			time: time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC),
+			want: true,
+		},
+		{
+			name:   "within an overnight window in its time zone but not in UTC",
+			window: overnight,
+			// 23:30 in Paris during summer time
+			time: time.Date(2024, 7, 1, 21, 30, 0, 0, time.UTC),
+			want: true,
+		},
+		{
+			name:   "outside an overnight window in its time zone",
+			window: overnight,
+			// 07:00 in Paris
+			time: time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.window.Contains(tt.time))
+		})
+	}
+}
+
+func TestGet(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: ConfigMapName},
+		Data:       map[string]string{MaxConcurrentRestartsKey: "1", SelectorKey: "env=prod"},
+	}
+	c := k8s.NewFakeClient(cm)
+
+	policy, err := Get(context.Background(), c, "other")
+	require.NoError(t, err)
+	require.Nil(t, policy)
+	require.False(t, policy.Applies(esv1.Elasticsearch{}))
+
+	policy, err = Get(context.Background(), c, "ns")
+	require.NoError(t, err)
+	require.True(t, policy.Applies(esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"env": "prod"}}}))
+	require.False(t, policy.Applies(esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"env": "dev"}}}))
+}
@@ -0,0 +1,144 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package restartpolicy
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+const (
+	// SlotLabelName is set on the Leases materializing the restart slots of a namespace.
+	SlotLabelName = "elasticsearch.k8s.elastic.co/restart-slot"
+
+	slotNamePrefix = "elastic-restart-slot-"
+
+	// slotDuration is the duration after which a slot which was not renewed by its holder can be taken by another
+	// cluster, for example if the holder was deleted during its restart.
+	slotDuration = 10 * time.Minute
+)
+
+// SlotName returns the name of the Lease materializing the restart slot with the given index.
+func SlotName(index int) string {
+	return fmt.Sprintf("%s%d", slotNamePrefix, index)
+}
+
+// AcquireSlot makes the given cluster hold a restart slot of its namespace, if it does not hold one already. A slot can
+// only be acquired during the time window of the policy, but a slot already held is renewed at any time so that an
+// ongoing restart is never interrupted. It returns true if the cluster holds a slot and can restart its nodes.
+func AcquireSlot(ctx context.Context, c k8s.Client, policy Policy, es esv1.Elasticsearch, now time.Time) (bool, error) {
+	slots, err := listSlots(ctx, c, es.Namespace)
+	if err != nil {
+		return false, err
+	}
+	for i := range slots {
+		if holder(slots[i]) == es.Name {
+			return true, renew(ctx, c, &slots[i], now)
+		}
+	}
+	if !policy.Window.Contains(now) {
+		return false, nil
+	}
+
+	slotsByName := make(map[string]coordinationv1.Lease, len(slots))
+	for _, slot := range slots {
+		slotsByName[slot.Name] = slot
+	}
+	for i := 0; i < policy.MaxConcurrentRestarts; i++ {
+		slot, exists := slotsByName[SlotName(i)]
+		if !exists {
+			slot = coordinationv1.Lease{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: es.Namespace,
+					Name:      SlotName(i),
+					Labels:    map[string]string{SlotLabelName: "true"},
+				},
+			}
+			hold(&slot, es.Name, now)
+			err := c.Create(ctx, &slot)
+			if apierrors.IsAlreadyExists(err) {
+				// acquired in the meantime by another cluster
+				continue
+			}
+			return err == nil, err
+		}
+		if !isFree(slot, now) {
+			continue
+		}
+		hold(&slot, es.Name, now)
+		err := c.Update(ctx, &slot)
+		if apierrors.IsConflict(err) {
+			// acquired in the meantime by another cluster
+			continue
+		}
+		return err == nil, err
+	}
+	return false, nil
+}
+
+// ReleaseSlot releases the restart slot held by the given cluster, if any.
+func ReleaseSlot(ctx context.Context, c k8s.Client, es esv1.Elasticsearch) error {
+	slots, err := listSlots(ctx, c, es.Namespace)
+	if err != nil {
+		return err
+	}
+	for i := range slots {
+		if holder(slots[i]) != es.Name {
+			continue
+		}
+		if err := c.Delete(ctx, &slots[i]); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func listSlots(ctx context.Context, c k8s.Client, namespace string) ([]coordinationv1.Lease, error) {
+	var leases coordinationv1.LeaseList
+	if err := c.List(ctx, &leases, client.InNamespace(namespace), client.HasLabels{SlotLabelName}); err != nil {
+		return nil, err
+	}
+	return leases.Items, nil
+}
+
+func holder(slot coordinationv1.Lease) string {
+	return ptr.Deref(slot.Spec.HolderIdentity, "")
+}
+
+// isFree returns true if the slot is not held, or if its holder did not renew it in time.
+func isFree(slot coordinationv1.Lease, now time.Time) bool {
+	if holder(slot) == "" || slot.Spec.RenewTime == nil {
+		return true
+	}
+	duration := time.Duration(ptr.Deref(slot.Spec.LeaseDurationSeconds, 0)) * time.Second
+	return slot.Spec.RenewTime.Add(duration).Before(now)
+}
+
+func hold(slot *coordinationv1.Lease, holder string, now time.Time) {
+	slot.Spec.HolderIdentity = ptr.To(holder)
+	slot.Spec.LeaseDurationSeconds = ptr.To(int32(slotDuration.Seconds()))
+	slot.Spec.AcquireTime = &metav1.MicroTime{Time: now}
+	slot.Spec.RenewTime = &metav1.MicroTime{Time: now}
+}
+
+// renew extends the duration of a held slot, at most every quarter of the slot duration to limit the API calls.
+func renew(ctx context.Context, c k8s.Client, slot *coordinationv1.Lease, now time.Time) error {
+	if slot.Spec.RenewTime != nil && now.Sub(slot.Spec.RenewTime.Time) < slotDuration/4 {
+		return nil
+	}
+	slot.Spec.LeaseDurationSeconds = ptr.To(int32(slotDuration.Seconds()))
+	slot.Spec.RenewTime = &metav1.MicroTime{Time: now}
+	return c.Update(ctx, slot)
+}
@@ -0,0 +1,100 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package restartpolicy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func TestAcquireSlot(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 23, 0, 0, 0, time.UTC)
+	cluster := func(name string) esv1.Elasticsearch {
+		return esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name}}
+	}
+	policy := Policy{MaxConcurrentRestarts: 1, Selector: labels.Everything()}
+	c := k8s.NewFakeClient()
+
+	// the first cluster takes the only slot
+	acquired, err := AcquireSlot(ctx, c, policy, cluster("a"), now)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	// the second cluster must wait
+	acquired, err = AcquireSlot(ctx, c, policy, cluster("b"), now.Add(time.Minute))
+	require.NoError(t, err)
+	require.False(t, acquired)
+
+	// the first cluster keeps and renews its slot, even outside of the window
+	policy.Window = &Window{Start: 22 * time.Hour, End: 23 * time.Hour, Location: time.UTC}
+	acquired, err = AcquireSlot(ctx, c, policy, cluster("a"), now.Add(5*time.Minute))
+	require.NoError(t, err)
+	require.True(t, acquired)
+	var slot coordinationv1.Lease
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "ns", Name: SlotName(0)}, &slot))
+	require.Equal(t, now.Add(5*time.Minute), slot.Spec.RenewTime.Time.UTC())
+
+	// the released slot cannot be acquired outside of the window
+	require.NoError(t, ReleaseSlot(ctx, c, cluster("a")))
+	acquired, err = AcquireSlot(ctx, c, policy, cluster("b"), now.Add(6*time.Minute))
+	require.NoError(t, err)
+	require.False(t, acquired)
+
+	// but it can be acquired within the window
+	acquired, err = AcquireSlot(ctx, c, policy, cluster("b"), now.Add(23*time.Hour))
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	// a slot which is not renewed in time can be taken by another cluster
+	acquired, err = AcquireSlot(ctx, c, policy, cluster("c"), now.Add(23*time.Hour+5*time.Minute))
+	require.NoError(t, err)
+	require.False(t, acquired)
+	acquired, err = AcquireSlot(ctx, c, policy, cluster("c"), now.Add(23*time.Hour+11*time.Minute))
+	require.NoError(t, err)
+	require.True(t, acquired)
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "ns", Name: SlotName(0)}, &slot))
+	require.Equal(t, "c", *slot.Spec.HolderIdentity)
+}
+
+func TestAcquireSlot_MaxConcurrentRestarts(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	policy := Policy{MaxConcurrentRestarts: 2, Selector: labels.Everything()}
+	c := k8s.NewFakeClient()
+	for _, tt := range []struct {
+		name string
+		want bool
+	}{
+		{name: "a", want: true},
+		{name: "b", want: true},
+		{name: "c", want: false},
+	} {
+		acquired, err := AcquireSlot(ctx, c, policy, esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: tt.name}}, now)
+		require.NoError(t, err)
+		require.Equal(t, tt.want, acquired, tt.name)
+	}
+
+	// slots are not shared across namespaces
+	acquired, err := AcquireSlot(ctx, c, policy, esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "c"}}, now)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	// releasing a slot which is not held is a no-op
+	require.NoError(t, ReleaseSlot(ctx, c, esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "c"}}))
+	var slots coordinationv1.LeaseList
+	require.NoError(t, c.List(ctx, &slots))
+	require.Len(t, slots.Items, 3)
+}
@@ -17,6 +17,9 @@ const (
 	// to be referenced in ES configuration file
 	EnvPodName   = "POD_NAME"
 	EnvPodIP     = "POD_IP"
+	EnvPodIPs    = "POD_IPS"
 	EnvNodeName  = "NODE_NAME"
 	EnvNamespace = "NAMESPACE"
+	// EnvZone holds the zone of the Kubernetes node, copied from its topology label if zone awareness is enabled
+	EnvZone = "ZONE"
 )
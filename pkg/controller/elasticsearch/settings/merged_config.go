@@ -20,10 +20,10 @@ import (
 	netutil "github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
 )
 
-// the name of the ES attribute indicating the pod's current k8s node
-const nodeAttrK8sNodeName = "k8s_node_name"
+// NodeAttrK8sNodeName is the name of the ES attribute indicating the pod's current k8s node
+const NodeAttrK8sNodeName = "k8s_node_name"
 
-var nodeAttrNodeName = fmt.Sprintf("%s.%s", esv1.NodeAttr, nodeAttrK8sNodeName)
+var nodeAttrNodeName = fmt.Sprintf("%s.%s", esv1.NodeAttr, NodeAttrK8sNodeName)
 
 // NewMergedESConfig merges user provided Elasticsearch configuration with configuration derived from the given
 // parameters. The user provided config overrides have precedence over the ECK config.
@@ -31,8 +31,12 @@ func NewMergedESConfig(
 	clusterName string,
 	ver version.Version,
 	ipFamily corev1.IPFamily,
+	publishIPFamily corev1.IPFamily,
 	httpConfig commonv1.HTTPConfig,
+	realms []esv1.Realm,
 	userConfig commonv1.Config,
+	tuning *esv1.NodeSetTuning,
+	zoneAwareness *esv1.ZoneAwareness,
 	esConfigFromStackConfigPolicy *common.CanonicalConfig,
 	remoteClusterServerEnabled, remoteClusterClientEnabled bool,
 ) (CanonicalConfig, error) {
@@ -40,11 +44,19 @@ func NewMergedESConfig(
 	if err != nil {
 		return CanonicalConfig{}, err
 	}
+	// the settings of the tuning fields take precedence over the same settings in the user configuration
+	tuningCfg, err := common.NewCanonicalConfigFrom(tuning.Settings())
+	if err != nil {
+		return CanonicalConfig{}, err
+	}
 
-	config := baseConfig(clusterName, ver, ipFamily, remoteClusterServerEnabled).CanonicalConfig
+	config := baseConfig(clusterName, ver, ipFamily, publishIPFamily, remoteClusterServerEnabled).CanonicalConfig
 	err = config.MergeWith(
+		zoneAwarenessConfig(zoneAwareness).CanonicalConfig,
 		xpackConfig(ver, httpConfig, remoteClusterServerEnabled, remoteClusterClientEnabled).CanonicalConfig,
+		realmsConfig(realms).CanonicalConfig,
 		userCfg,
+		tuningCfg,
 		esConfigFromStackConfigPolicy,
 	)
 	if err != nil {
@@ -54,7 +66,7 @@ func NewMergedESConfig(
 }
 
 // baseConfig returns the base ES configuration to apply for the given cluster
-func baseConfig(clusterName string, ver version.Version, ipFamily corev1.IPFamily, remoteClusterServerEnabled bool) *CanonicalConfig {
+func baseConfig(clusterName string, ver version.Version, ipFamily, publishIPFamily corev1.IPFamily, remoteClusterServerEnabled bool) *CanonicalConfig {
 	cfg := map[string]interface{}{
 		// derive node name dynamically from the pod name, injected as env var
 		esv1.NodeName:    "${" + EnvPodName + "}",
@@ -66,13 +78,19 @@ func baseConfig(clusterName string, ver version.Version, ipFamily corev1.IPFamil
 		esv1.NetworkHost:        "0",
 
 		// allow ES to be aware of k8s node the pod is running on when allocating shards
-		esv1.ShardAwarenessAttributes: nodeAttrK8sNodeName,
+		esv1.ShardAwarenessAttributes: NodeAttrK8sNodeName,
 		nodeAttrNodeName:              "${" + EnvNodeName + "}",
 
 		esv1.PathData: volume.ElasticsearchDataMountPath,
 		esv1.PathLogs: volume.ElasticsearchLogsMountPath,
 	}
 
+	if publishIPFamily != "" {
+		// let Elasticsearch pick the address of the preferred family among all the Pod IPs, IPv4 unless the JVM is
+		// configured to prefer IPv6 addresses
+		cfg[esv1.NetworkPublishHost] = "${" + EnvPodIPs + "}"
+	}
+
 	if remoteClusterServerEnabled {
 		cfg[esv1.RemoteClusterEnabled] = "true"
 		cfg[esv1.RemoteClusterPublishHost] = "${" + EnvPodName + "}.${" + HeadlessServiceName + "}.${" + EnvNamespace + "}.svc"
@@ -97,6 +115,19 @@ func baseConfig(clusterName string, ver version.Version, ipFamily corev1.IPFamil
 	return &CanonicalConfig{common.MustCanonicalConfig(cfg)}
 }
 
+// zoneAwarenessConfig returns the node attribute holding the zone of the node, injected as env var, and the shard
+// allocation awareness attributes including it, if zone awareness is enabled.
+func zoneAwarenessConfig(zoneAwareness *esv1.ZoneAwareness) *CanonicalConfig {
+	if !zoneAwareness.IsEnabled() {
+		return &CanonicalConfig{common.NewCanonicalConfig()}
+	}
+	attribute := zoneAwareness.AttributeOrDefault()
+	return &CanonicalConfig{common.MustCanonicalConfig(map[string]interface{}{
+		esv1.NodeAttr + "." + attribute: "${" + EnvZone + "}",
+		esv1.ShardAwarenessAttributes:   NodeAttrK8sNodeName + "," + attribute,
+	})}
+}
+
 // xpackConfig returns the configuration bit related to XPack settings
 func xpackConfig(ver version.Version, httpCfg commonv1.HTTPConfig, remoteClusterServerEnabled, remoteClusterClientEnabled bool) *CanonicalConfig {
 	// enable x-pack security, including TLS
@@ -107,7 +138,7 @@ func xpackConfig(ver version.Version, httpCfg commonv1.HTTPConfig, remoteCluster
 		esv1.XPackSecurityTransportSslVerificationMode: "certificate",
 
 		// x-pack security http settings
-		esv1.XPackSecurityHttpSslEnabled:     httpCfg.TLS.Enabled(),
+		esv1.XPackSecurityHttpSslEnabled:     httpCfg.TLSEnabled(),
 		esv1.XPackSecurityHttpSslKey:         path.Join(volume.HTTPCertificatesSecretVolumeMountPath, certificates.KeyFileName),
 		esv1.XPackSecurityHttpSslCertificate: path.Join(volume.HTTPCertificatesSecretVolumeMountPath, certificates.CertFileName),
 
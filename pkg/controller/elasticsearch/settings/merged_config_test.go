@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
@@ -45,12 +46,71 @@ func TestNewMergedESConfig(t *testing.T) {
 		name                       string
 		version                    string
 		ipFamily                   corev1.IPFamily
+		publishIPFamily            corev1.IPFamily
 		remoteClusterServerEnabled bool
 		remoteClusterClientEnabled bool
 		cfgData                    map[string]interface{}
+		tuning                     *esv1.NodeSetTuning
+		zoneAwareness              *esv1.ZoneAwareness
 		policyCfgData              *common.CanonicalConfig
 		assert                     func(cfg CanonicalConfig)
 	}{
+		{
+			name:     "Tuning settings take precedence over the user config",
+			version:  "8.15.0",
+			ipFamily: corev1.IPv4Protocol,
+			cfgData: map[string]interface{}{
+				esv1.SearchMaxBuckets: 1000,
+				nodeML:                false,
+			},
+			tuning: &esv1.NodeSetTuning{
+				ThreadPools:      map[string]esv1.ThreadPoolSettings{"write": {Size: ptr.To[int32](4)}},
+				CircuitBreakers:  &esv1.CircuitBreakerSettings{Request: "40%"},
+				SearchMaxBuckets: ptr.To[int32](20000),
+			},
+			assert: func(cfg CanonicalConfig) {
+				for key, expected := range map[string]string{
+					esv1.SearchMaxBuckets:                   "20000",
+					esv1.ThreadPoolSetting("write", "size"): "4",
+					esv1.IndicesBreakerRequestLimit:         "40%",
+					nodeML:                                  "false",
+				} {
+					actual, err := cfg.String(key)
+					require.NoError(t, err)
+					require.Equal(t, expected, actual, key)
+				}
+			},
+		},
+		{
+			name:          "Zone awareness sets the zone attribute and the awareness attributes",
+			version:       "8.15.0",
+			ipFamily:      corev1.IPv4Protocol,
+			cfgData:       map[string]interface{}{},
+			zoneAwareness: &esv1.ZoneAwareness{Enabled: true},
+			assert: func(cfg CanonicalConfig) {
+				zone, err := cfg.String("node.attr.zone")
+				require.NoError(t, err)
+				require.Equal(t, "${ZONE}", zone)
+				attributes, err := cfg.String(esv1.ShardAwarenessAttributes)
+				require.NoError(t, err)
+				require.Equal(t, "k8s_node_name,zone", attributes)
+			},
+		},
+		{
+			name:     "User awareness attributes take precedence over zone awareness",
+			version:  "8.15.0",
+			ipFamily: corev1.IPv4Protocol,
+			cfgData: map[string]interface{}{
+				esv1.ShardAwarenessAttributes: "rack",
+			},
+			zoneAwareness: &esv1.ZoneAwareness{Enabled: true, Attribute: "az"},
+			assert: func(cfg CanonicalConfig) {
+				attributes, err := cfg.String(esv1.ShardAwarenessAttributes)
+				require.NoError(t, err)
+				require.Equal(t, "rack", attributes)
+				require.Equal(t, 1, len(cfg.HasKeys([]string{"node.attr.az"})))
+			},
+		},
 		{
 			name:     "No remote cluster client or server by default",
 			version:  "8.15.0",
@@ -322,12 +382,27 @@ func TestNewMergedESConfig(t *testing.T) {
 				require.Equal(t, "[${POD_IP}]", esCfg.Network.PublishHost)
 			},
 		},
+		{
+			name:            "dual-stack: publish host is picked among all the Pod IPs",
+			version:         "8.15.0",
+			ipFamily:        corev1.IPv4Protocol,
+			publishIPFamily: corev1.IPv6Protocol,
+			cfgData:         map[string]interface{}{},
+			assert: func(cfg CanonicalConfig) {
+				cfgBytes, err := cfg.Render()
+				require.NoError(t, err)
+				esCfg := &elasticsearchCfg{}
+				require.NoError(t, yaml.Unmarshal(cfgBytes, &esCfg))
+				require.Equal(t, "${POD_IPS}", esCfg.Network.PublishHost)
+				require.Equal(t, "${POD_NAME}.${HEADLESS_SERVICE_NAME}.${NAMESPACE}.svc", esCfg.HTTP.PublishHost)
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			ver, err := version.Parse(tt.version)
 			require.NoError(t, err)
-			cfg, err := NewMergedESConfig("clusterName", ver, tt.ipFamily, commonv1.HTTPConfig{}, commonv1.Config{Data: tt.cfgData}, tt.policyCfgData, tt.remoteClusterServerEnabled, tt.remoteClusterClientEnabled)
+			cfg, err := NewMergedESConfig("clusterName", ver, tt.ipFamily, tt.publishIPFamily, commonv1.HTTPConfig{}, nil, commonv1.Config{Data: tt.cfgData}, tt.tuning, tt.zoneAwareness, tt.policyCfgData, tt.remoteClusterServerEnabled, tt.remoteClusterClientEnabled)
 			require.NoError(t, err)
 			tt.assert(cfg)
 		})
@@ -0,0 +1,197 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package settings
+
+import (
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	common "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
+	commonvolume "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
+)
+
+const (
+	realmsSettingsPrefix = "xpack.security.authc.realms"
+
+	// file names of the files referenced by the realms, in the directory of each realm in the realms volume
+	idpMetadataFileName            = "idp-metadata.xml"
+	certificateAuthoritiesFileName = "ca.crt"
+
+	// default claim and attribute holding the principal of the users, which Elasticsearch requires
+	defaultOIDCPrincipalClaim     = "sub"
+	defaultSAMLPrincipalAttribute = "nameid"
+	defaultOIDCResponseType       = "code"
+)
+
+// realmSetting returns the name of the given setting of the given realm.
+func realmSetting(realm esv1.Realm, setting string) string {
+	return realmsSettingsPrefix + "." + realm.Type() + "." + realm.Name + "." + setting
+}
+
+// realmFilePath returns the path of the given file of the given realm in the realms volume.
+func realmFilePath(realm esv1.Realm, fileName string) string {
+	return path.Join(realm.Name, fileName)
+}
+
+// realmsConfig renders the Elasticsearch configuration of the given realms. Secure settings and files are not part of the
+// configuration but reference the keystore and the realms volume.
+func realmsConfig(realms []esv1.Realm) *CanonicalConfig {
+	cfg := map[string]interface{}{}
+	for _, realm := range realms {
+		if realm.Type() == "" {
+			continue
+		}
+		set := func(setting string, value interface{}) {
+			cfg[realmSetting(realm, setting)] = value
+		}
+		setIfNotEmpty := func(setting string, value string) {
+			if value != "" {
+				set(setting, value)
+			}
+		}
+		setUserProperties := func(prefix string, props esv1.RealmUserProperties, defaultPrincipal string) {
+			principal := props.Principal
+			if principal == "" {
+				principal = defaultPrincipal
+			}
+			set(prefix+".principal", principal)
+			setIfNotEmpty(prefix+".groups", props.Groups)
+			setIfNotEmpty(prefix+".name", props.Name)
+			setIfNotEmpty(prefix+".mail", props.Mail)
+		}
+
+		set("order", realm.Order)
+		switch {
+		case realm.OIDC != nil:
+			oidc := realm.OIDC
+			set("op.issuer", oidc.Issuer)
+			set("op.authorization_endpoint", oidc.AuthorizationEndpoint)
+			setIfNotEmpty("op.token_endpoint", oidc.TokenEndpoint)
+			setIfNotEmpty("op.userinfo_endpoint", oidc.UserinfoEndpoint)
+			setIfNotEmpty("op.endsession_endpoint", oidc.EndSessionEndpoint)
+			set("op.jwkset_path", oidc.JWKSetPath)
+			set("rp.client_id", oidc.ClientID)
+			set("rp.redirect_uri", oidc.RedirectURI)
+			setIfNotEmpty("rp.post_logout_redirect_uri", oidc.PostLogoutRedirectURI)
+			responseType := oidc.ResponseType
+			if responseType == "" {
+				responseType = defaultOIDCResponseType
+			}
+			set("rp.response_type", responseType)
+			if len(oidc.RequestedScopes) > 0 {
+				set("rp.requested_scopes", oidc.RequestedScopes)
+			}
+			setUserProperties("claims", oidc.Claims, defaultOIDCPrincipalClaim)
+		case realm.SAML != nil:
+			saml := realm.SAML
+			set("idp.entity_id", saml.IdPEntityID)
+			metadataPath := saml.IdPMetadataURL
+			if saml.IdPMetadata != nil {
+				metadataPath = path.Join(volume.RealmsVolumeMountPath, realmFilePath(realm, idpMetadataFileName))
+			}
+			set("idp.metadata.path", metadataPath)
+			set("sp.entity_id", saml.SPEntityID)
+			set("sp.acs", saml.SPACS)
+			setIfNotEmpty("sp.logout", saml.SPLogout)
+			setUserProperties("attributes", saml.Attributes, defaultSAMLPrincipalAttribute)
+		case realm.LDAP != nil:
+			ldap := realm.LDAP
+			set("url", ldap.URLs)
+			setIfNotEmpty("bind_dn", ldap.BindDN)
+			setIfNotEmpty("user_search.base_dn", ldap.UserSearchBaseDN)
+			setIfNotEmpty("user_search.filter", ldap.UserSearchFilter)
+			if len(ldap.UserDNTemplates) > 0 {
+				set("user_dn_templates", ldap.UserDNTemplates)
+			}
+			setIfNotEmpty("group_search.base_dn", ldap.GroupSearchBaseDN)
+			if ldap.CertificateAuthorities != nil {
+				set("ssl.certificate_authorities", []string{
+					path.Join(volume.RealmsVolumeMountPath, realmFilePath(realm, certificateAuthoritiesFileName)),
+				})
+			}
+		}
+	}
+	return &CanonicalConfig{common.MustCanonicalConfig(cfg)}
+}
+
+// RealmsSecureSettings returns the sources of the secure settings of the realms of the given Elasticsearch cluster, to be
+// added to its keystore under the name of the realm settings.
+func RealmsSecureSettings(es esv1.Elasticsearch) []commonv1.NamespacedSecretSource {
+	var sources []commonv1.NamespacedSecretSource
+	add := func(realm esv1.Realm, setting string, ref esv1.SecretKeyRef) {
+		sources = append(sources, commonv1.NamespacedSecretSource{
+			Namespace:  es.Namespace,
+			SecretName: ref.SecretName,
+			Entries:    []commonv1.KeyToPath{{Key: ref.Key, Path: realmSetting(realm, setting)}},
+		})
+	}
+	for _, realm := range es.Spec.Auth.Realms {
+		switch {
+		case realm.OIDC != nil:
+			add(realm, "rp.client_secret", realm.OIDC.ClientSecret)
+		case realm.LDAP != nil && realm.LDAP.BindPassword != nil:
+			add(realm, "secure_bind_password", *realm.LDAP.BindPassword)
+		}
+	}
+	return sources
+}
+
+// RealmsVolume returns the volume projecting the files referenced by the given realms in the Elasticsearch
+// configuration directory. It returns false if no realm references a file.
+func RealmsVolume(realms []esv1.Realm) (commonvolume.VolumeLike, bool) {
+	var sources []corev1.VolumeProjection
+	add := func(realm esv1.Realm, fileName string, ref esv1.SecretKeyRef) {
+		sources = append(sources, corev1.VolumeProjection{
+			Secret: &corev1.SecretProjection{
+				LocalObjectReference: corev1.LocalObjectReference{Name: ref.SecretName},
+				Items:                []corev1.KeyToPath{{Key: ref.Key, Path: realmFilePath(realm, fileName)}},
+			},
+		})
+	}
+	for _, realm := range realms {
+		switch {
+		case realm.SAML != nil && realm.SAML.IdPMetadata != nil:
+			add(realm, idpMetadataFileName, *realm.SAML.IdPMetadata)
+		case realm.LDAP != nil && realm.LDAP.CertificateAuthorities != nil:
+			add(realm, certificateAuthoritiesFileName, *realm.LDAP.CertificateAuthorities)
+		}
+	}
+	if len(sources) == 0 {
+		return nil, false
+	}
+	return realmsVolume{sources: sources}, true
+}
+
+// realmsVolume is a projected volume of the Secrets referenced by the realms.
+type realmsVolume struct {
+	sources []corev1.VolumeProjection
+}
+
+var _ commonvolume.VolumeLike = realmsVolume{}
+
+func (v realmsVolume) Name() string {
+	return volume.RealmsVolumeName
+}
+
+func (v realmsVolume) Volume() corev1.Volume {
+	return corev1.Volume{
+		Name: volume.RealmsVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{Sources: v.sources},
+		},
+	}
+}
+
+func (v realmsVolume) VolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      volume.RealmsVolumeName,
+		MountPath: volume.RealmsVolumeMountPath,
+		ReadOnly:  true,
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package settings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+var sampleRealms = []esv1.Realm{
+	{
+		Name:  "oidc1",
+		Order: 2,
+		OIDC: &esv1.OIDCRealm{
+			Issuer:                "https://op.example.com",
+			AuthorizationEndpoint: "https://op.example.com/authorize",
+			TokenEndpoint:         "https://op.example.com/token",
+			JWKSetPath:            "https://op.example.com/jwks.json",
+			ClientID:              "elasticsearch",
+			ClientSecret:          esv1.SecretKeyRef{SecretName: "oidc", Key: "client-secret"},
+			RedirectURI:           "https://kibana.example.com/api/security/oidc/callback",
+			RequestedScopes:       []string{"email"},
+			Claims:                esv1.RealmUserProperties{Groups: "groups"},
+		},
+	},
+	{
+		Name:  "saml1",
+		Order: 3,
+		SAML: &esv1.SAMLRealm{
+			IdPEntityID: "https://idp.example.com",
+			IdPMetadata: &esv1.SecretKeyRef{SecretName: "saml", Key: "metadata.xml"},
+			SPEntityID:  "https://kibana.example.com",
+			SPACS:       "https://kibana.example.com/api/security/saml/callback",
+			Attributes:  esv1.RealmUserProperties{Principal: "uid"},
+		},
+	},
+	{
+		Name:  "ldap1",
+		Order: 4,
+		LDAP: &esv1.LDAPRealm{
+			URLs:                   []string{"ldaps://ldap.example.com:636"},
+			BindDN:                 "cn=admin,dc=example,dc=com",
+			BindPassword:           &esv1.SecretKeyRef{SecretName: "ldap", Key: "password"},
+			UserSearchBaseDN:       "dc=example,dc=com",
+			CertificateAuthorities: &esv1.SecretKeyRef{SecretName: "ldap", Key: "ca.crt"},
+		},
+	},
+}
+
+func Test_realmsConfig(t *testing.T) {
+	cfg, err := realmsConfig(sampleRealms).Render()
+	require.NoError(t, err)
+	require.Equal(t, `xpack:
+    security:
+        authc:
+            realms:
+                ldap:
+                    ldap1:
+                        bind_dn: cn=admin,dc=example,dc=com
+                        order: 4
+                        ssl:
+                            certificate_authorities:
+                                - /usr/share/elasticsearch/config/realms/ldap1/ca.crt
+                        url:
+                            - ldaps://ldap.example.com:636
+                        user_search:
+                            base_dn: dc=example,dc=com
+                oidc:
+                    oidc1:
+                        claims:
+                            groups: groups
+                            principal: sub
+                        op:
+                            authorization_endpoint: https://op.example.com/authorize
+                            issuer: https://op.example.com
+                            jwkset_path: https://op.example.com/jwks.json
+                            token_endpoint: https://op.example.com/token
+                        order: 2
+                        rp:
+                            client_id: elasticsearch
+                            redirect_uri: https://kibana.example.com/api/security/oidc/callback
+                            requested_scopes:
+                                - email
+                            response_type: code
+                saml:
+                    saml1:
+                        attributes:
+                            principal: uid
+                        idp:
+                            entity_id: https://idp.example.com
+                            metadata:
+                                path: /usr/share/elasticsearch/config/realms/saml1/idp-metadata.xml
+                        order: 3
+                        sp:
+                            acs: https://kibana.example.com/api/security/saml/callback
+                            entity_id: https://kibana.example.com
+`, string(cfg))
+}
+
+func TestNewMergedESConfig_Realms(t *testing.T) {
+	// the realms configuration can be overridden by the user configuration
+	userConfig := commonv1.Config{Data: map[string]interface{}{
+		"xpack.security.authc.realms.oidc.oidc1.rp.response_type": "id_token",
+	}}
+	cfg, err := NewMergedESConfig("clusterName", version.MustParse("8.15.0"), corev1.IPv4Protocol, "", commonv1.HTTPConfig{}, sampleRealms, userConfig, nil, nil, nil, false, false)
+	require.NoError(t, err)
+	responseType, err := cfg.String("xpack.security.authc.realms.oidc.oidc1.rp.response_type")
+	require.NoError(t, err)
+	require.Equal(t, "id_token", responseType)
+	order, err := cfg.String("xpack.security.authc.realms.ldap.ldap1.order")
+	require.NoError(t, err)
+	require.Equal(t, "4", order)
+}
+
+func TestRealmsSecureSettings(t *testing.T) {
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec:       esv1.ElasticsearchSpec{Auth: esv1.Auth{Realms: sampleRealms}},
+	}
+	require.Equal(t, []commonv1.NamespacedSecretSource{
+		{
+			Namespace:  "ns",
+			SecretName: "oidc",
+			Entries:    []commonv1.KeyToPath{{Key: "client-secret", Path: "xpack.security.authc.realms.oidc.oidc1.rp.client_secret"}},
+		},
+		{
+			Namespace:  "ns",
+			SecretName: "ldap",
+			Entries:    []commonv1.KeyToPath{{Key: "password", Path: "xpack.security.authc.realms.ldap.ldap1.secure_bind_password"}},
+		},
+	}, RealmsSecureSettings(es))
+}
+
+func TestRealmsVolume(t *testing.T) {
+	_, exists := RealmsVolume(sampleRealms[:1])
+	require.False(t, exists)
+
+	realmsVolume, exists := RealmsVolume(sampleRealms)
+	require.True(t, exists)
+	require.Equal(t, corev1.Volume{
+		Name: "elastic-internal-realms",
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{Sources: []corev1.VolumeProjection{
+				{Secret: &corev1.SecretProjection{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "saml"},
+					Items:                []corev1.KeyToPath{{Key: "metadata.xml", Path: "saml1/idp-metadata.xml"}},
+				}},
+				{Secret: &corev1.SecretProjection{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "ldap"},
+					Items:                []corev1.KeyToPath{{Key: "ca.crt", Path: "ldap1/ca.crt"}},
+				}},
+			}},
+		},
+	}, realmsVolume.Volume())
+	require.Equal(t, "/usr/share/elasticsearch/config/realms", realmsVolume.VolumeMount().MountPath)
+}
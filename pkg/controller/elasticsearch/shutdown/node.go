@@ -137,6 +137,20 @@ func (ns *NodeShutdown) ShutdownStatus(ctx context.Context, podName string) (Nod
 	}, nil
 }
 
+// HasShutdown returns true if a shutdown of any type is registered for the given node. Elasticsearch only allows one
+// shutdown per node: requesting a shutdown of another type replaces the existing one.
+func (ns *NodeShutdown) HasShutdown(ctx context.Context, podName string) (bool, error) {
+	if err := ns.initOnce(ctx); err != nil {
+		return false, err
+	}
+	nodeID, err := ns.lookupNodeID(podName)
+	if err != nil {
+		return false, err
+	}
+	_, exists := ns.shutdowns[nodeID]
+	return exists, nil
+}
+
 func logStatus(logger logr.Logger, podName string, shutdown esclient.NodeShutdown) {
 	switch shutdown.Status {
 	case esclient.ShutdownComplete:
@@ -158,6 +172,11 @@ func (ns *NodeShutdown) OnlyNodesInCluster(s esclient.NodeShutdown) bool {
 	return ns.nodeInCluster(s.NodeID)
 }
 
+// OnlyNodesNotInCluster is a predicate to limit the shutdowns to delete to nodes that are no longer in the cluster.
+func (ns *NodeShutdown) OnlyNodesNotInCluster(s esclient.NodeShutdown) bool {
+	return !ns.nodeInCluster(s.NodeID)
+}
+
 // OnlyNonTerminatingNodes is a function to generate a predicate to delete only those shutdowns which don't affect currently terminating Pods/ES nodes.
 func (ns *NodeShutdown) OnlyNonTerminatingNodes(terminatingNodes []string) func(s esclient.NodeShutdown) bool {
 	terminatingNodeIDs := map[string]bool{}
@@ -169,6 +188,13 @@ func (ns *NodeShutdown) OnlyNonTerminatingNodes(terminatingNodes []string) func(
 	}
 }
 
+// OnlyReason is a function to generate a predicate to delete only the shutdowns requested with the given reason.
+func OnlyReason(reason string) ClearCondition {
+	return func(s esclient.NodeShutdown) bool {
+		return s.Reason == reason
+	}
+}
+
 // Clear deletes shutdown requests matching the type of the NodeShutdown field typ and the given optional status.
 // Depending on the progress of the shutdown in question this means either a cancellation of the shutdown or a clean-up
 // after shutdown completion.
@@ -194,6 +194,30 @@ func TestNodeShutdown_Clear(t *testing.T) {
 			wantErr:    false,
 			wantDelete: true,
 		},
+		{
+			name:    "Should not delete shutdowns requested for another reason",
+			fixture: shutdownFixture,
+			args: args{
+				typ: esclient.Remove,
+				conditions: func(_ *NodeShutdown) []ClearCondition {
+					return []ClearCondition{OnlyReason("node-drain")}
+				},
+			},
+			wantErr:    false,
+			wantDelete: false,
+		},
+		{
+			name:    "Should delete shutdowns requested for the given reason",
+			fixture: shutdownFixture,
+			args: args{
+				typ: esclient.Remove,
+				conditions: func(_ *NodeShutdown) []ClearCondition {
+					return []ClearCondition{OnlyReason("111800357")}
+				},
+			},
+			wantErr:    false,
+			wantDelete: true,
+		},
 		{
 			name: "Should bubble up errors",
 			args: args{
@@ -0,0 +1,65 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package snapshotrepository
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+const (
+	// ManagedSnapshotRepositoriesAnnotationName holds the list of the snapshot repositories which have been registered
+	// by the operator.
+	ManagedSnapshotRepositoriesAnnotationName = "elasticsearch.k8s.elastic.co/managed-snapshot-repositories"
+)
+
+// getRepositoriesInAnnotation returns the set of snapshot repositories that may have been registered in Elasticsearch by
+// the operator. If there are no repositories the map is empty but not nil.
+func getRepositoriesInAnnotation(es esv1.Elasticsearch) map[string]struct{} {
+	repositories := make(map[string]struct{})
+	serializedRepositories, ok := es.Annotations[ManagedSnapshotRepositoriesAnnotationName]
+	if !ok || strings.TrimSpace(serializedRepositories) == "" {
+		return repositories
+	}
+	for _, repository := range strings.Split(serializedRepositories, ",") {
+		repositories[repository] = struct{}{}
+	}
+	return repositories
+}
+
+// annotateWithManagedRepositories stores the given set of repositories in the annotation of the Elasticsearch resource,
+// only updating the resource if the annotation changes.
+func annotateWithManagedRepositories(ctx context.Context, c k8s.Client, es esv1.Elasticsearch, repositories map[string]struct{}) error {
+	current, exists := es.Annotations[ManagedSnapshotRepositoriesAnnotationName]
+	if len(repositories) == 0 {
+		if !exists {
+			return nil
+		}
+		updated := es.DeepCopy()
+		delete(updated.Annotations, ManagedSnapshotRepositoriesAnnotationName)
+		return c.Update(ctx, updated)
+	}
+
+	annotation := make([]string, 0, len(repositories))
+	for repository := range repositories {
+		annotation = append(annotation, repository)
+	}
+	sort.Strings(annotation)
+	expected := strings.Join(annotation, ",")
+	if exists && current == expected {
+		return nil
+	}
+
+	updated := es.DeepCopy()
+	if updated.Annotations == nil {
+		updated.Annotations = make(map[string]string)
+	}
+	updated.Annotations[ManagedSnapshotRepositoriesAnnotationName] = expected
+	return c.Update(ctx, updated)
+}
@@ -0,0 +1,149 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package snapshotrepository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.elastic.co/apm/v2"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// Reconcile registers the snapshot repositories declared in the Elasticsearch spec, and unregisters the ones which were
+// previously declared in the spec but have been removed since. Repositories registered out-of-band are left untouched:
+// the repositories managed by the operator are tracked in an annotation on the Elasticsearch resource.
+// Repositories are only updated if their type or settings differ from the ones in Elasticsearch, since registering a
+// repository triggers its verification on all the nodes.
+// An error is returned if at least one repository could not be reconciled, the other repositories are still processed.
+func Reconcile(ctx context.Context, c k8s.Client, esClient esclient.Client, es esv1.Elasticsearch) error {
+	repositoriesInAnnotation := getRepositoriesInAnnotation(es)
+	if len(es.Spec.SnapshotRepositories) == 0 && len(repositoriesInAnnotation) == 0 {
+		// nothing to do, skip
+		return nil
+	}
+
+	span, ctx := apm.StartSpan(ctx, "reconcile_snapshot_repositories", tracing.SpanTypeApp)
+	defer span.End()
+
+	repositoriesInES, err := esClient.GetSnapshotRepositories(ctx)
+	if err != nil {
+		return err
+	}
+
+	expected := make(map[string]esclient.SnapshotRepository, len(es.Spec.SnapshotRepositories))
+	for _, repository := range es.Spec.SnapshotRepositories {
+		settings := map[string]interface{}{}
+		if repository.Settings != nil && repository.Settings.Data != nil {
+			settings = repository.Settings.Data
+		}
+		expected[repository.Name] = esclient.SnapshotRepository{Type: repository.Type, Settings: settings}
+		// track the repository before registering it, so that it is not orphaned if the annotation update fails later on
+		repositoriesInAnnotation[repository.Name] = struct{}{}
+	}
+
+	var errs []error
+	var deleted []string
+	for name := range repositoriesInAnnotation {
+		if _, inSpec := expected[name]; inSpec {
+			continue
+		}
+		if _, inES := repositoriesInES[name]; inES {
+			if err := esClient.DeleteSnapshotRepository(ctx, name); err != nil {
+				errs = append(errs, fmt.Errorf("while deleting snapshot repository %s: %w", name, err))
+				continue
+			}
+			deleted = append(deleted, name)
+		}
+		// the repository is not in Elasticsearch anymore, it does not need to be tracked
+		delete(repositoriesInAnnotation, name)
+	}
+
+	if err := annotateWithManagedRepositories(ctx, c, es, repositoriesInAnnotation); err != nil {
+		return err
+	}
+
+	var updated []string
+	for name, repository := range expected {
+		if current, exists := repositoriesInES[name]; exists && !needsUpdate(repository, current) {
+			continue
+		}
+		if err := esClient.PutSnapshotRepository(ctx, name, repository); err != nil {
+			errs = append(errs, fmt.Errorf("while registering snapshot repository %s: %w", name, err))
+			continue
+		}
+		updated = append(updated, name)
+	}
+
+	if len(updated) > 0 || len(deleted) > 0 {
+		sort.Strings(updated)
+		sort.Strings(deleted)
+		ulog.FromContext(ctx).Info("Updated snapshot repositories",
+			"namespace", es.Namespace,
+			"es_name", es.Name,
+			"updated_snapshot_repositories", updated,
+			"deleted_snapshot_repositories", deleted,
+		)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// needsUpdate compares the expected repository with the one registered in Elasticsearch. Elasticsearch returns the
+// settings with string values, and with dotted keys if they were provided that way: both sides are normalized to
+// flattened string values before being compared.
+func needsUpdate(expected, current esclient.SnapshotRepository) bool {
+	if expected.Type != current.Type {
+		return true
+	}
+	return !reflect.DeepEqual(flatten(expected.Settings), flatten(current.Settings))
+}
+
+// flatten returns the given settings with dotted keys and string values.
+func flatten(settings map[string]interface{}) map[string]string {
+	flattened := make(map[string]string)
+	flattenInto("", settings, flattened)
+	return flattened
+}
+
+func flattenInto(prefix string, settings map[string]interface{}, into map[string]string) {
+	for key, value := range settings {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, isMap := value.(map[string]interface{}); isMap {
+			flattenInto(key, nested, into)
+			continue
+		}
+		into[key] = stringValue(value)
+	}
+}
+
+func stringValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		// avoid the exponent notation fmt uses for large numbers, Elasticsearch returns plain numbers
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			values = append(values, stringValue(item))
+		}
+		return "[" + strings.Join(values, ",") + "]"
+	default:
+		return fmt.Sprint(v)
+	}
+}
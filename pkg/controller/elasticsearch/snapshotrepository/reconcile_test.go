@@ -0,0 +1,164 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package snapshotrepository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+type fakeESClient struct {
+	esclient.Client
+	repositories esclient.SnapshotRepositories
+	putErr       error
+	put          []string
+	deleted      []string
+}
+
+func (f *fakeESClient) GetSnapshotRepositories(_ context.Context) (esclient.SnapshotRepositories, error) {
+	return f.repositories, nil
+}
+
+func (f *fakeESClient) PutSnapshotRepository(_ context.Context, name string, repository esclient.SnapshotRepository) error {
+	if f.putErr != nil {
+		return f.putErr
+	}
+	f.put = append(f.put, name)
+	f.repositories[name] = repository
+	return nil
+}
+
+func (f *fakeESClient) DeleteSnapshotRepository(_ context.Context, name string) error {
+	f.deleted = append(f.deleted, name)
+	delete(f.repositories, name)
+	return nil
+}
+
+func newES(annotation string, repositories ...esv1.SnapshotRepository) esv1.Elasticsearch {
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec:       esv1.ElasticsearchSpec{SnapshotRepositories: repositories},
+	}
+	if annotation != "" {
+		es.Annotations = map[string]string{ManagedSnapshotRepositoriesAnnotationName: annotation}
+	}
+	return es
+}
+
+func s3Repository(name string, settings map[string]interface{}) esv1.SnapshotRepository {
+	config := commonv1.NewConfig(settings)
+	return esv1.SnapshotRepository{Name: name, Type: "s3", Settings: &config}
+}
+
+func getAnnotation(t *testing.T, c k8s.Client) (string, bool) {
+	t.Helper()
+	var es esv1.Elasticsearch
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "es"}, &es))
+	annotation, exists := es.Annotations[ManagedSnapshotRepositoriesAnnotationName]
+	return annotation, exists
+}
+
+func TestReconcile(t *testing.T) {
+	tests := []struct {
+		name             string
+		es               esv1.Elasticsearch
+		inES             esclient.SnapshotRepositories
+		wantPut          []string
+		wantDeleted      []string
+		wantAnnotation   string
+		wantNoAnnotation bool
+		wantInES         []string
+	}{
+		{
+			name:             "nothing to do",
+			es:               newES(""),
+			inES:             esclient.SnapshotRepositories{"out-of-band": {Type: "fs"}},
+			wantNoAnnotation: true,
+			wantInES:         []string{"out-of-band"},
+		},
+		{
+			name:           "register a new repository",
+			es:             newES("", s3Repository("backups", map[string]interface{}{"bucket": "my-bucket"})),
+			inES:           esclient.SnapshotRepositories{"out-of-band": {Type: "fs"}},
+			wantPut:        []string{"backups"},
+			wantAnnotation: "backups",
+			wantInES:       []string{"backups", "out-of-band"},
+		},
+		{
+			name: "repository already up to date, with settings returned as flattened strings",
+			es: newES("backups", s3Repository("backups", map[string]interface{}{
+				"bucket": "my-bucket", "compress": true, "max_restore_bytes_per_sec": float64(40000000), "client": map[string]interface{}{"name": "default"},
+			})),
+			inES: esclient.SnapshotRepositories{"backups": {Type: "s3", Settings: map[string]interface{}{
+				"bucket": "my-bucket", "compress": "true", "max_restore_bytes_per_sec": "40000000", "client.name": "default",
+			}}},
+			wantAnnotation: "backups",
+			wantInES:       []string{"backups"},
+		},
+		{
+			name: "update a repository with different settings",
+			es:   newES("backups", s3Repository("backups", map[string]interface{}{"bucket": "other-bucket"})),
+			inES: esclient.SnapshotRepositories{"backups": {Type: "s3", Settings: map[string]interface{}{
+				"bucket": "my-bucket",
+			}}},
+			wantPut:        []string{"backups"},
+			wantAnnotation: "backups",
+			wantInES:       []string{"backups"},
+		},
+		{
+			name:             "remove a managed repository, keep the out-of-band one",
+			es:               newES("backups"),
+			inES:             esclient.SnapshotRepositories{"backups": {Type: "s3"}, "out-of-band": {Type: "fs"}},
+			wantDeleted:      []string{"backups"},
+			wantNoAnnotation: true,
+			wantInES:         []string{"out-of-band"},
+		},
+		{
+			name:           "stop tracking a managed repository already removed from Elasticsearch",
+			es:             newES("backups,old", s3Repository("backups", nil)),
+			inES:           esclient.SnapshotRepositories{"backups": {Type: "s3"}},
+			wantAnnotation: "backups",
+			wantInES:       []string{"backups"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := k8s.NewFakeClient(&tt.es)
+			esClient := &fakeESClient{repositories: tt.inES}
+			require.NoError(t, Reconcile(context.Background(), c, esClient, tt.es))
+			require.Equal(t, tt.wantPut, esClient.put)
+			require.Equal(t, tt.wantDeleted, esClient.deleted)
+			annotation, exists := getAnnotation(t, c)
+			require.Equal(t, !tt.wantNoAnnotation, exists)
+			require.Equal(t, tt.wantAnnotation, annotation)
+			var inES []string
+			for name := range esClient.repositories {
+				inES = append(inES, name)
+			}
+			require.ElementsMatch(t, tt.wantInES, inES)
+		})
+	}
+}
+
+func TestReconcile_Error(t *testing.T) {
+	es := newES("", s3Repository("backups", map[string]interface{}{"bucket": "my-bucket"}))
+	c := k8s.NewFakeClient(&es)
+	esClient := &fakeESClient{repositories: esclient.SnapshotRepositories{}, putErr: errors.New("repository verification exception")}
+	err := Reconcile(context.Background(), c, esClient, es)
+	require.ErrorContains(t, err, "while registering snapshot repository backups: repository verification exception")
+	// the repository is tracked even though it could not be registered yet
+	annotation, _ := getAnnotation(t, c)
+	require.Equal(t, "backups", annotation)
+}
@@ -0,0 +1,188 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package snapshotrepository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.elastic.co/apm/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
+)
+
+// RestoredIndexPrefix is the prefix of the temporary indices restored to verify snapshots.
+const RestoredIndexPrefix = "eck-snapshot-verification-"
+
+// Verify verifies the latest snapshot of each snapshot lifecycle management policy, if a verification is due according
+// to the verification interval and to the time of the last verification reported in the status.
+// It returns the verification status to report, and the duration after which the next verification is due.
+// A nil status is returned if snapshot verification is disabled.
+// Verification failures are reported in the status, an error is only returned if the policies cannot be retrieved.
+func Verify(ctx context.Context, esClient esclient.Client, es esv1.Elasticsearch, now time.Time) (*esv1.SnapshotVerificationStatus, time.Duration, error) {
+	spec := es.Spec.SnapshotVerification
+	if spec == nil {
+		DeleteVerificationMetrics(k8s.ExtractNamespacedName(&es))
+		return nil, 0, nil
+	}
+	interval := spec.IntervalOrDefault()
+	current := es.Status.SnapshotVerification
+	if current != nil {
+		if next := current.LastVerificationTime.Add(interval); now.Before(next) {
+			return current, next.Sub(now), nil
+		}
+	}
+
+	span, ctx := apm.StartSpan(ctx, "verify_snapshots", tracing.SpanTypeApp)
+	defer span.End()
+
+	policies, err := esClient.GetSLMPolicies(ctx)
+	if err != nil {
+		return current, 0, err
+	}
+	names := make([]string, 0, len(policies))
+	for name := range policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	status := &esv1.SnapshotVerificationStatus{LastVerificationTime: metav1.NewTime(now)}
+	// repositories are verified once, even if they are used by several policies
+	verifiedRepositories := make(map[string]error)
+	for _, name := range names {
+		result := verifyPolicy(ctx, esClient, name, policies[name], spec.RestoreSample, verifiedRepositories)
+		if !result.Verified {
+			ulog.FromContext(ctx).Info("Snapshot verification failed",
+				"namespace", es.Namespace, "es_name", es.Name, "policy", name, "message", result.Message)
+		}
+		status.Policies = append(status.Policies, result)
+	}
+	updateVerificationMetrics(es, status)
+	return status, interval, nil
+}
+
+// Verified returns true if the latest snapshot of all the policies was verified successfully, and a message
+// summarizing the failures otherwise.
+func Verified(status esv1.SnapshotVerificationStatus) (bool, string) {
+	var failures []string
+	for _, policy := range status.Policies {
+		if !policy.Verified {
+			failures = append(failures, fmt.Sprintf("%s: %s", policy.Policy, policy.Message))
+		}
+	}
+	if len(failures) > 0 {
+		return false, strings.Join(failures, "; ")
+	}
+	if len(status.Policies) == 0 {
+		return true, "No snapshot lifecycle management policy to verify"
+	}
+	return true, fmt.Sprintf("Latest snapshot of %d snapshot lifecycle management policies verified", len(status.Policies))
+}
+
+func verifyPolicy(
+	ctx context.Context,
+	esClient esclient.Client,
+	name string,
+	policy esclient.SLMPolicy,
+	restoreSample bool,
+	verifiedRepositories map[string]error,
+) esv1.SnapshotPolicyVerification {
+	repository := policy.Policy.Repository
+	result := esv1.SnapshotPolicyVerification{Policy: name, Repository: repository}
+	if policy.LastSuccess == nil {
+		result.Message = "no successful snapshot"
+		return result
+	}
+	result.Snapshot = policy.LastSuccess.SnapshotName
+	if policy.LastFailure != nil && policy.LastFailure.Time > policy.LastSuccess.Time {
+		result.Message = fmt.Sprintf("latest snapshot %s failed: %s", policy.LastFailure.SnapshotName, policy.LastFailure.Details)
+		return result
+	}
+
+	repositoryErr, verified := verifiedRepositories[repository]
+	if !verified {
+		repositoryErr = esClient.VerifySnapshotRepository(ctx, repository)
+		verifiedRepositories[repository] = repositoryErr
+	}
+	if repositoryErr != nil {
+		result.Message = fmt.Sprintf("repository %s verification failed: %s", repository, repositoryErr.Error())
+		return result
+	}
+
+	snapshot, err := esClient.GetSnapshot(ctx, repository, result.Snapshot)
+	if err != nil {
+		result.Message = fmt.Sprintf("cannot get snapshot %s: %s", result.Snapshot, err.Error())
+		return result
+	}
+	if snapshot.State != esclient.SnapshotSuccessState {
+		result.Message = fmt.Sprintf("snapshot %s is in state %s", result.Snapshot, snapshot.State)
+		return result
+	}
+
+	if restoreSample {
+		if err := restoreSampleIndex(ctx, esClient, repository, snapshot); err != nil {
+			result.Message = fmt.Sprintf("cannot restore a sample of snapshot %s: %s", result.Snapshot, err.Error())
+			return result
+		}
+	}
+	result.Verified = true
+	return result
+}
+
+// restoreSampleIndex restores an index of the given snapshot into a temporary index, then deletes it.
+// System and hidden indices are not restored, apart from data stream backing indices.
+func restoreSampleIndex(ctx context.Context, esClient esclient.Client, repository string, snapshot esclient.Snapshot) error {
+	var index string
+	for _, candidate := range snapshot.Indices {
+		if !strings.HasPrefix(candidate, ".") || strings.HasPrefix(candidate, ".ds-") {
+			index = candidate
+			break
+		}
+	}
+	if index == "" {
+		// nothing to restore
+		return nil
+	}
+	restoredIndex := RestoredIndexPrefix + strings.TrimPrefix(index, ".")
+	// remove any leftover of an interrupted verification
+	if err := esClient.DeleteIndex(ctx, restoredIndex); err != nil && !esclient.IsNotFound(err) {
+		return err
+	}
+	if err := esClient.RestoreSnapshotIndex(ctx, repository, snapshot.Snapshot, index, restoredIndex); err != nil {
+		return err
+	}
+	return esClient.DeleteIndex(ctx, restoredIndex)
+}
+
+func updateVerificationMetrics(es esv1.Elasticsearch, status *esv1.SnapshotVerificationStatus) {
+	// remove the policies which do not exist anymore
+	metrics.SnapshotVerifiedGauge.DeletePartialMatch(prometheus.Labels{metrics.NamespaceLabel: es.Namespace, metrics.NameLabel: es.Name})
+	for _, policy := range status.Policies {
+		value := 0.0
+		if policy.Verified {
+			value = 1
+		}
+		metrics.SnapshotVerifiedGauge.WithLabelValues(es.Namespace, es.Name, policy.Policy).Set(value)
+	}
+	metrics.SnapshotVerificationTimestampGauge.WithLabelValues(es.Namespace, es.Name).Set(float64(status.LastVerificationTime.Unix()))
+}
+
+// DeleteVerificationMetrics removes the snapshot verification metrics of the given Elasticsearch cluster.
+func DeleteVerificationMetrics(es types.NamespacedName) {
+	labels := prometheus.Labels{metrics.NamespaceLabel: es.Namespace, metrics.NameLabel: es.Name}
+	metrics.SnapshotVerifiedGauge.DeletePartialMatch(labels)
+	metrics.SnapshotVerificationTimestampGauge.DeletePartialMatch(labels)
+}
@@ -0,0 +1,163 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package snapshotrepository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
+)
+
+type fakeVerificationClient struct {
+	esclient.Client
+	policies          esclient.SLMPolicies
+	snapshots         map[string]esclient.Snapshot
+	invalidRepository string
+	restoreErr        error
+
+	verifiedRepositories []string
+	restored             []string
+	deletedIndices       []string
+}
+
+func (f *fakeVerificationClient) GetSLMPolicies(_ context.Context) (esclient.SLMPolicies, error) {
+	return f.policies, nil
+}
+
+func (f *fakeVerificationClient) VerifySnapshotRepository(_ context.Context, name string) error {
+	f.verifiedRepositories = append(f.verifiedRepositories, name)
+	if name == f.invalidRepository {
+		return errors.New("repository_verification_exception")
+	}
+	return nil
+}
+
+func (f *fakeVerificationClient) GetSnapshot(_ context.Context, _, snapshot string) (esclient.Snapshot, error) {
+	return f.snapshots[snapshot], nil
+}
+
+func (f *fakeVerificationClient) RestoreSnapshotIndex(_ context.Context, _, _, index, restoredIndex string) error {
+	if f.restoreErr != nil {
+		return f.restoreErr
+	}
+	f.restored = append(f.restored, index+"->"+restoredIndex)
+	return nil
+}
+
+func (f *fakeVerificationClient) DeleteIndex(_ context.Context, index string) error {
+	f.deletedIndices = append(f.deletedIndices, index)
+	return nil
+}
+
+func slmPolicy(repository string, lastSuccess, lastFailure *esclient.SLMInvocation) esclient.SLMPolicy {
+	policy := esclient.SLMPolicy{LastSuccess: lastSuccess, LastFailure: lastFailure}
+	policy.Policy.Repository = repository
+	return policy
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	esClient := &fakeVerificationClient{
+		policies: esclient.SLMPolicies{
+			"daily": slmPolicy("backups",
+				&esclient.SLMInvocation{SnapshotName: "daily-2", Time: 2000},
+				&esclient.SLMInvocation{SnapshotName: "daily-1", Time: 1000, Details: "boom"},
+			),
+			"hourly": slmPolicy("backups",
+				&esclient.SLMInvocation{SnapshotName: "hourly-1", Time: 1000},
+				&esclient.SLMInvocation{SnapshotName: "hourly-2", Time: 2000, Details: "shard failure"},
+			),
+			"monthly": slmPolicy("backups", nil, nil),
+			"weekly":  slmPolicy("archive", &esclient.SLMInvocation{SnapshotName: "weekly-1", Time: 1000}, nil),
+			"yearly":  slmPolicy("backups", &esclient.SLMInvocation{SnapshotName: "yearly-1", Time: 1000}, nil),
+		},
+		snapshots: map[string]esclient.Snapshot{
+			"daily-2":  {Snapshot: "daily-2", State: esclient.SnapshotSuccessState, Indices: []string{".security-7", ".ds-logs-2024.10.01-000001", "metrics"}},
+			"yearly-1": {Snapshot: "yearly-1", State: "PARTIAL"},
+		},
+		invalidRepository: "archive",
+	}
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec:       esv1.ElasticsearchSpec{SnapshotVerification: &esv1.SnapshotVerification{RestoreSample: true}},
+	}
+
+	status, next, err := Verify(context.Background(), esClient, es, now)
+	require.NoError(t, err)
+	require.Equal(t, esv1.DefaultSnapshotVerificationInterval, next)
+	require.Equal(t, &esv1.SnapshotVerificationStatus{
+		LastVerificationTime: metav1.NewTime(now),
+		Policies: []esv1.SnapshotPolicyVerification{
+			{Policy: "daily", Repository: "backups", Snapshot: "daily-2", Verified: true},
+			{Policy: "hourly", Repository: "backups", Snapshot: "hourly-1", Message: "latest snapshot hourly-2 failed: shard failure"},
+			{Policy: "monthly", Repository: "backups", Message: "no successful snapshot"},
+			{Policy: "weekly", Repository: "archive", Snapshot: "weekly-1", Message: "repository archive verification failed: repository_verification_exception"},
+			{Policy: "yearly", Repository: "backups", Snapshot: "yearly-1", Message: "snapshot yearly-1 is in state PARTIAL"},
+		},
+	}, status)
+	// repositories are only verified once
+	require.Equal(t, []string{"backups", "archive"}, esClient.verifiedRepositories)
+	// the first data stream backing index is restored then deleted
+	require.Equal(t, []string{".ds-logs-2024.10.01-000001->eck-snapshot-verification-ds-logs-2024.10.01-000001"}, esClient.restored)
+	require.Equal(t, []string{"eck-snapshot-verification-ds-logs-2024.10.01-000001", "eck-snapshot-verification-ds-logs-2024.10.01-000001"}, esClient.deletedIndices)
+
+	verified, message := Verified(*status)
+	require.False(t, verified)
+	require.Contains(t, message, "monthly: no successful snapshot")
+
+	require.Equal(t, 1.0, testutil.ToFloat64(metrics.SnapshotVerifiedGauge.WithLabelValues("ns", "es", "daily")))
+	require.Equal(t, 0.0, testutil.ToFloat64(metrics.SnapshotVerifiedGauge.WithLabelValues("ns", "es", "weekly")))
+	require.Equal(t, float64(now.Unix()), testutil.ToFloat64(metrics.SnapshotVerificationTimestampGauge.WithLabelValues("ns", "es")))
+
+	// the next verification is not due yet
+	es.Status.SnapshotVerification = status
+	esClient.verifiedRepositories = nil
+	current, next, err := Verify(context.Background(), esClient, es, now.Add(time.Hour))
+	require.NoError(t, err)
+	require.Same(t, status, current)
+	require.Equal(t, 23*time.Hour, next)
+	require.Empty(t, esClient.verifiedRepositories)
+
+	// disabling verification removes the status and the metrics
+	es.Spec.SnapshotVerification = nil
+	current, _, err = Verify(context.Background(), esClient, es, now.Add(time.Hour))
+	require.NoError(t, err)
+	require.Nil(t, current)
+	require.Equal(t, 0, testutil.CollectAndCount(metrics.SnapshotVerificationTimestampGauge))
+}
+
+func TestVerify_RestoreFailure(t *testing.T) {
+	esClient := &fakeVerificationClient{
+		policies: esclient.SLMPolicies{
+			"daily": slmPolicy("backups", &esclient.SLMInvocation{SnapshotName: "daily-1", Time: 1000}, nil),
+		},
+		snapshots: map[string]esclient.Snapshot{
+			"daily-1": {Snapshot: "daily-1", State: esclient.SnapshotSuccessState, Indices: []string{"logs"}},
+		},
+		restoreErr: errors.New("snapshot_restore_exception"),
+	}
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "restore-failure"},
+		Spec:       esv1.ElasticsearchSpec{SnapshotVerification: &esv1.SnapshotVerification{RestoreSample: true, Interval: &metav1.Duration{Duration: time.Hour}}},
+	}
+	status, next, err := Verify(context.Background(), esClient, es, time.Now())
+	require.NoError(t, err)
+	require.Equal(t, time.Hour, next)
+	require.Equal(t, []esv1.SnapshotPolicyVerification{{
+		Policy: "daily", Repository: "backups", Snapshot: "daily-1",
+		Message: "cannot restore a sample of snapshot daily-1: snapshot_restore_exception",
+	}}, status.Policies)
+	DeleteVerificationMetrics(types.NamespacedName{Namespace: "ns", Name: "restore-failure"})
+}
@@ -100,6 +100,18 @@ func (l StatefulSetList) ExpectedMasterNodesCount() int32 {
 	return count
 }
 
+// ExpectedElectableMasterNodesCount returns the number of master nodes expected from the StatefulSetList which can be
+// elected as master, that is excluding the voting-only master nodes.
+func (l StatefulSetList) ExpectedElectableMasterNodesCount() int32 {
+	count := int32(0)
+	for _, s := range l {
+		if label.IsMasterNodeSet(s) && !label.IsVotingOnlyNodeSet(s) {
+			count += sset.GetReplicas(s)
+		}
+	}
+	return count
+}
+
 // ExpectedDataNodesCount returns the number of data nodes expected from the StatefulSetList.
 func (l StatefulSetList) ExpectedDataNodesCount() int32 {
 	count := int32(0)
@@ -18,6 +18,10 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
 )
 
+// MaxConcurrentReconciles is the maximum number of StatefulSets of a single cluster whose resources are reconciled
+// concurrently.
+const MaxConcurrentReconciles = 4
+
 // ReconcileStatefulSet creates or updates the expected StatefulSet.
 func ReconcileStatefulSet(ctx context.Context, c k8s.Client, es esv1.Elasticsearch, expected appsv1.StatefulSet, expectations *expectations.Expectations) (appsv1.StatefulSet, error) {
 	podTemplateValidator := statefulset.NewPodTemplateValidator(ctx, c, &es, expected)
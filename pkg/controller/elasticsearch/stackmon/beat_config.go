@@ -35,13 +35,15 @@ func ReconcileConfigSecrets(ctx context.Context, client k8s.Client, es esv1.Elas
 	}
 
 	if monitoring.IsMetricsDefined(&es) {
-		b, err := Metricbeat(ctx, client, es)
+		metricbeats, err := Metricbeats(ctx, client, es)
 		if err != nil {
 			return err
 		}
 
-		if _, err := reconciler.ReconcileSecret(ctx, client, b.ConfigSecret, &es); err != nil {
-			return err
+		for _, b := range metricbeats {
+			if _, err := reconciler.ReconcileSecret(ctx, client, b.ConfigSecret, &es); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -0,0 +1,52 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package metricsets
+
+import (
+	"slices"
+
+	"github.com/blang/semver/v4"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+// Supported are the metric sets of the Metricbeat Elasticsearch module collected by default.
+// https://www.elastic.co/guide/en/beats/metricbeat/current/metricbeat-module-elasticsearch.html
+var Supported = []string{
+	"ccr",
+	"cluster_stats",
+	"enrich",
+	"index",
+	"index_recovery",
+	"index_summary",
+	"ingest_pipeline",
+	"ml_job",
+	"node",
+	"node_stats",
+	"pending_tasks",
+	"shard",
+}
+
+// metricSetsMinVersion holds the minimum Elasticsearch version required by some metric sets.
+var metricSetsMinVersion = map[string]semver.Version{
+	"ingest_pipeline": version.From(8, 7, 0),
+}
+
+// Select returns the metric sets to collect for the given Elasticsearch version, restricted to the selected ones
+// unless selected is empty. Metric sets not supported by the Elasticsearch version are ignored. The default order is
+// preserved to produce a stable configuration.
+func Select(v semver.Version, selected []string) []string {
+	result := make([]string, 0, len(Supported))
+	for _, metricSet := range Supported {
+		if len(selected) > 0 && !slices.Contains(selected, metricSet) {
+			continue
+		}
+		if minVersion, exists := metricSetsMinVersion[metricSet]; exists && v.LT(minVersion) {
+			continue
+		}
+		result = append(result, metricSet)
+	}
+	return result
+}
@@ -0,0 +1,51 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package metricsets
+
+import (
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+func TestSelect(t *testing.T) {
+	tests := []struct {
+		name     string
+		version  semver.Version
+		selected []string
+		want     []string
+	}{
+		{
+			name:    "all metric sets",
+			version: version.From(8, 16, 0),
+			want:    Supported,
+		},
+		{
+			name:    "all metric sets supported before 8.7.0",
+			version: version.From(8, 6, 0),
+			want:    []string{"ccr", "cluster_stats", "enrich", "index", "index_recovery", "index_summary", "ml_job", "node", "node_stats", "pending_tasks", "shard"},
+		},
+		{
+			name:     "selected metric sets in the default order",
+			version:  version.From(8, 16, 0),
+			selected: []string{"node_stats", "cluster_stats"},
+			want:     []string{"cluster_stats", "node_stats"},
+		},
+		{
+			name:     "selected metric set not supported by the version",
+			version:  version.From(8, 6, 0),
+			selected: []string{"ingest_pipeline", "cluster_stats"},
+			want:     []string{"cluster_stats"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Select(tt.version, tt.selected))
+		})
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/network"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/securitycontext"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/stackmon/metricsets"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/user"
 	esvolume "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
@@ -29,46 +30,71 @@ const (
 	cfgHashAnnotation = "elasticsearch.k8s.elastic.co/monitoring-config-hash"
 )
 
-func Metricbeat(ctx context.Context, client k8s.Client, es esv1.Elasticsearch) (stackmon.BeatSidecar, error) {
+// metricbeatTemplateParams are the parameters to render the Metricbeat configuration template.
+type metricbeatTemplateParams struct {
+	stackmon.TemplateParams
+	MetricSets []string
+}
+
+// Metricbeats returns one Metricbeat sidecar per monitoring Elasticsearch cluster, as Beats support a single output.
+// The first sidecar keeps the name of the single sidecar used before multiple destinations were supported, to avoid
+// renaming the container of existing clusters.
+func Metricbeats(ctx context.Context, client k8s.Client, es esv1.Elasticsearch) ([]stackmon.BeatSidecar, error) {
 	username := user.MonitoringUserName
 	password, err := user.GetMonitoringUserPassword(client, k8s.ExtractNamespacedName(&es))
 	if err != nil {
-		return stackmon.BeatSidecar{}, err
+		return nil, err
 	}
 
 	v, err := version.Parse(es.Spec.Version)
 	if err != nil {
-		return stackmon.BeatSidecar{}, err
+		return nil, err
 	}
 
-	caVolume, err := stackmon.CAVolume(client, k8s.ExtractNamespacedName(&es), esv1.ESNamer, commonv1.EsMonitoringAssociationType, es.Spec.HTTP.TLS.Enabled())
+	caVolume, err := stackmon.CAVolume(client, k8s.ExtractNamespacedName(&es), esv1.ESNamer, commonv1.EsMonitoringAssociationType, es.Spec.HTTP.TLSEnabled())
 	if err != nil {
-		return stackmon.BeatSidecar{}, err
+		return nil, err
 	}
 
-	input := stackmon.TemplateParams{
-		URL:      fmt.Sprintf("%s://localhost:%d", es.Spec.HTTP.Protocol(), network.HTTPPort),
-		Username: username,
-		Password: password,
-		IsSSL:    es.Spec.HTTP.TLS.Enabled(),
-		CAVolume: caVolume,
-	}
+	sidecars := make([]stackmon.BeatSidecar, 0, len(es.GetMonitoringMetricsRefs()))
+	for i, ref := range es.GetMonitoringMetricsRefs() {
+		if !ref.IsDefined() {
+			continue
+		}
+		input := metricbeatTemplateParams{
+			TemplateParams: stackmon.TemplateParams{
+				URL:      fmt.Sprintf("%s://localhost:%d", es.Spec.HTTP.Protocol(), network.HTTPPort),
+				Username: username,
+				Password: password,
+				IsSSL:    es.Spec.HTTP.TLSEnabled(),
+				CAVolume: caVolume,
+			},
+			MetricSets: metricsets.Select(v, monitoring.GetMetricSets(&es, ref)),
+		}
 
-	cfg, err := stackmon.RenderTemplate(v, metricbeatConfigTemplate, input)
-	if err != nil {
-		return stackmon.BeatSidecar{}, err
-	}
+		cfg, err := stackmon.RenderTemplate(v, metricbeatConfigTemplate, input)
+		if err != nil {
+			return nil, err
+		}
 
-	metricbeat, err := stackmon.NewMetricBeatSidecar(ctx, client, &es, v, caVolume, cfg)
-	if err != nil {
-		return stackmon.BeatSidecar{}, err
+		metricbeat, err := stackmon.NewNamedMetricBeatSidecar(
+			ctx, client, metricbeatName(i), &es, []commonv1.Association{es.MonitoringAssociation(ref)}, v, caVolume, cfg,
+		)
+		if err != nil {
+			return nil, err
+		}
+		metricbeat.Container.SecurityContext = securitycontext.DefaultBeatSecurityContext(v)
+		sidecars = append(sidecars, metricbeat)
 	}
-	ver, err := version.Parse(es.Spec.Version)
-	if err != nil {
-		return stackmon.BeatSidecar{}, err
+	return sidecars, nil
+}
+
+// metricbeatName returns the name of the Metricbeat sidecar sending monitoring data to the i-th monitoring cluster.
+func metricbeatName(i int) string {
+	if i == 0 {
+		return "metricbeat"
 	}
-	metricbeat.Container.SecurityContext = securitycontext.DefaultBeatSecurityContext(ver)
-	return metricbeat, nil
+	return fmt.Sprintf("metricbeat-%d", i)
 }
 
 func Filebeat(ctx context.Context, client k8s.Client, es esv1.Elasticsearch) (stackmon.BeatSidecar, error) {
@@ -99,14 +125,16 @@ func WithMonitoring(ctx context.Context, client k8s.Client, builder *defaults.Po
 	volumes := make([]corev1.Volume, 0)
 
 	if monitoring.IsMetricsDefined(&es) {
-		b, err := Metricbeat(ctx, client, es)
+		metricbeats, err := Metricbeats(ctx, client, es)
 		if err != nil {
 			return nil, err
 		}
 
-		volumes = append(volumes, b.Volumes...)
-		builder.WithContainers(b.Container)
-		configHash.Write(b.ConfigHash.Sum(nil))
+		for _, b := range metricbeats {
+			volumes = append(volumes, b.Volumes...)
+			builder.WithContainers(b.Container)
+			configHash.Write(b.ConfigHash.Sum(nil))
+		}
 	}
 
 	if monitoring.IsLogsDefined(&es) {
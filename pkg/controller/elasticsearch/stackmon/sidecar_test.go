@@ -15,6 +15,7 @@ import (
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
@@ -23,6 +24,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/stackmon/monitoring"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/stackmon/metricsets"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 )
 
@@ -144,12 +146,13 @@ func TestMetricbeatConfig(t *testing.T) {
 		"/mount",
 	)
 	type args struct {
-		URL      string
-		Username string
-		Password string
-		IsSSL    bool
-		CAVolume volume.VolumeLike
-		Version  semver.Version
+		URL        string
+		Username   string
+		Password   string
+		IsSSL      bool
+		CAVolume   volume.VolumeLike
+		Version    semver.Version
+		MetricSets []string
 	}
 	tests := []struct {
 		name string
@@ -211,9 +214,83 @@ func TestMetricbeatConfig(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			tt.args.MetricSets = metricsets.Select(tt.args.Version, nil)
 			cfg, err := stackmon.RenderTemplate(tt.args.Version, metricbeatConfigTemplate, tt.args)
 			require.NoError(t, err)
 			snaps.MatchSnapshot(t, cfg)
 		})
 	}
 }
+
+func TestMetricbeats(t *testing.T) {
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Name: "sample", Namespace: "aerospace"},
+		Spec: esv1.ElasticsearchSpec{
+			Version: "8.16.0",
+			Monitoring: commonv1.Monitoring{
+				Metrics: commonv1.MetricsMonitoring{
+					ElasticsearchRefs: []commonv1.ObjectSelector{
+						{Name: "central", Namespace: "observability"},
+						{Name: "regional", Namespace: "observability"},
+					},
+					Destinations: []commonv1.MetricsDestination{{
+						ElasticsearchRef: commonv1.ObjectSelector{Name: "regional", Namespace: "observability"},
+						MetricSets:       []string{"node_stats", "cluster_stats"},
+					}},
+				},
+			},
+		},
+	}
+	objects := []client.Object{
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "sample-es-internal-users", Namespace: "aerospace"},
+			Data:       map[string][]byte{"elastic-internal-monitoring": []byte("1234567890")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "sample-es-http-certs-public", Namespace: "aerospace"},
+			Data:       map[string][]byte{"ca.crt": []byte("7H1515N074r341C3r71F1C473")},
+		},
+	}
+	for _, name := range []string{"central", "regional"} {
+		secretName := "sample-observability-" + name + "-beat-es-mon-user"
+		objects = append(objects, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: "aerospace"},
+			Data:       map[string][]byte{"aerospace-" + secretName: []byte("1234567890")},
+		})
+		es.MonitoringAssociation(commonv1.ObjectSelector{Name: name, Namespace: "observability"}).SetAssociationConf(&commonv1.AssociationConf{
+			AuthSecretName: secretName,
+			AuthSecretKey:  "aerospace-" + secretName,
+			URL:            "https://" + name + "-es-http.observability.svc:9200",
+			Version:        "8.16.0",
+		})
+	}
+
+	sidecars, err := Metricbeats(context.Background(), k8s.NewFakeClient(objects...), es)
+	require.NoError(t, err)
+	require.Len(t, sidecars, 2)
+
+	// the first sidecar sends all the metric sets to the central cluster
+	assert.Equal(t, "metricbeat", sidecars[0].Container.Name)
+	assert.Equal(t, "sample-es-monitoring-metricbeat-config", sidecars[0].ConfigSecret.Name)
+	centralCfg := string(sidecars[0].ConfigSecret.Data["metricbeat.yml"])
+	assert.Contains(t, centralCfg, "https://central-es-http.observability.svc:9200")
+	assert.Contains(t, centralCfg, "- shard")
+
+	// the second sidecar only sends the selected metric sets to the regional cluster
+	assert.Equal(t, "metricbeat-1", sidecars[1].Container.Name)
+	assert.Equal(t, "sample-es-monitoring-metricbeat-1-config", sidecars[1].ConfigSecret.Name)
+	regionalCfg := string(sidecars[1].ConfigSecret.Data["metricbeat-1.yml"])
+	assert.Contains(t, regionalCfg, "https://regional-es-http.observability.svc:9200")
+	assert.Contains(t, regionalCfg, "metricsets:\n            - cluster_stats\n            - node_stats\n          module")
+	assert.NotContains(t, regionalCfg, "- shard")
+
+	// each sidecar has its own data volume
+	volumeNames := map[string]bool{}
+	for _, sidecar := range sidecars {
+		for _, v := range sidecar.Volumes {
+			volumeNames[v.Name] = true
+		}
+	}
+	assert.True(t, volumeNames["metricbeat-data"])
+	assert.True(t, volumeNames["metricbeat-1-data"])
+}
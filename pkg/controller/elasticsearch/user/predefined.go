@@ -7,15 +7,18 @@ package user
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/labels"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
@@ -47,6 +50,7 @@ func reconcileElasticUser(
 	es esv1.Elasticsearch,
 	existingFileRealm,
 	userProvidedFileRealm filerealm.Realm,
+	recorder record.EventRecorder,
 	passwordHasher cryptutil.PasswordHasher,
 ) (users, error) {
 	if es.Spec.Auth.DisableElasticUser {
@@ -73,6 +77,7 @@ func reconcileElasticUser(
 		// Don't set an ownerRef for the elastic user secret, likely to be copied into different namespaces.
 		// See https://github.com/elastic/cloud-on-k8s/issues/3986.
 		false,
+		recorder,
 		passwordHasher,
 	)
 }
@@ -83,6 +88,7 @@ func reconcileInternalUsers(
 	c k8s.Client,
 	es esv1.Elasticsearch,
 	existingFileRealm filerealm.Realm,
+	recorder record.EventRecorder,
 	passwordHasher cryptutil.PasswordHasher,
 ) (users, error) {
 	users := users{
@@ -116,6 +122,7 @@ func reconcileInternalUsers(
 		users,
 		esv1.InternalUsersSecret(es.Name),
 		true,
+		recorder,
 		passwordHasher,
 	)
 }
@@ -134,6 +141,8 @@ func setRolesForUser(userName string, users []user, roles []string) error {
 
 // reconcilePredefinedUsers reconciles a secret with the given name holding the given users.
 // It attempts to reuse passwords from pre-existing secrets, and reuse hashes from pre-existing file realms.
+// An event is emitted if the passwords of users already present in the file realm have been lost, most likely because
+// the secret was deleted, and have to be regenerated.
 func reconcilePredefinedUsers(
 	ctx context.Context,
 	c k8s.Client,
@@ -142,16 +151,27 @@ func reconcilePredefinedUsers(
 	users users,
 	secretName string,
 	setOwnerRef bool,
+	recorder record.EventRecorder,
 	passwordHasher cryptutil.PasswordHasher,
 ) (users, error) {
 	secretNsn := types.NamespacedName{Namespace: es.Namespace, Name: secretName}
 
 	// build users, reusing existing passwords and bcrypt hashes if possible
-	var err error
-	users, err = reuseOrGeneratePassword(c, users, secretNsn)
+	users, generated, err := reuseOrGeneratePassword(c, users, secretNsn)
 	if err != nil {
 		return nil, err
 	}
+	var lost []string
+	for _, name := range generated {
+		if existingFileRealm.PasswordHashForUser(name) != nil {
+			lost = append(lost, name)
+		}
+	}
+	if len(lost) > 0 {
+		// the file realm is updated with the new passwords and hot-reloaded by Elasticsearch, no restart needed
+		recorder.Eventf(&es, corev1.EventTypeNormal, events.EventReasonRestored,
+			"Passwords of users %s were deleted from Secret %s, generating new passwords", strings.Join(lost, ","), secretName)
+	}
 	users, err = reuseOrGenerateHashes(users, existingFileRealm, passwordHasher)
 	if err != nil {
 		return nil, err
@@ -181,12 +201,12 @@ func reconcilePredefinedUsers(
 }
 
 // reuseOrGeneratePassword updates the users with existing passwords reused from the existing K8s secret,
-// or generates new passwords.
-func reuseOrGeneratePassword(c k8s.Client, users users, secretRef types.NamespacedName) (users, error) {
+// or generates new passwords. It also returns the names of the users whose password has been generated.
+func reuseOrGeneratePassword(c k8s.Client, users users, secretRef types.NamespacedName) (users, []string, error) {
 	var secret corev1.Secret
 	err := c.Get(context.Background(), secretRef, &secret)
 	if err != nil && !apierrors.IsNotFound(err) {
-		return nil, err
+		return nil, nil, err
 	}
 	// default to an empty secret
 	if apierrors.IsNotFound(err) {
@@ -196,14 +216,16 @@ func reuseOrGeneratePassword(c k8s.Client, users users, secretRef types.Namespac
 		secret.Data = map[string][]byte{}
 	}
 	// either reuse the password or generate a new one
+	var generated []string
 	for i, u := range users {
 		if password, exists := secret.Data[u.Name]; exists {
 			users[i].Password = password
 		} else {
 			users[i].Password = common.FixedLengthRandomPasswordBytes()
+			generated = append(generated, u.Name)
 		}
 	}
-	return users, nil
+	return users, generated, nil
 }
 
 // reuseOrGenerateHashes updates the users with existing hashes from the given file realm, or generates new ones.
@@ -13,6 +13,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
@@ -26,6 +27,7 @@ func Test_reconcileElasticUser(t *testing.T) {
 		name              string
 		existingSecrets   []client.Object
 		existingFileRealm filerealm.Realm
+		wantEvents        int
 		assertions        func(t *testing.T, u users)
 	}{
 		{
@@ -47,6 +49,8 @@ func Test_reconcileElasticUser(t *testing.T) {
 				},
 			},
 			existingFileRealm: filerealm.New().WithUser(ElasticUserName, []byte("$2a$10$lwsLdS0ZSyUv73WNdaRaTe8X9oeft4BoqjxtNHHH7LP7m1YImnvr6")),
+			// the password of an existing user has been lost
+			wantEvents: 1,
 			assertions: func(t *testing.T, u users) {
 				t.Helper()
 				// password should be regenerated
@@ -110,8 +114,10 @@ func Test_reconcileElasticUser(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := k8s.NewFakeClient(tt.existingSecrets...)
-			got, err := reconcileElasticUser(context.Background(), c, es, tt.existingFileRealm, filerealm.New(), testPasswordHasher)
+			recorder := record.NewFakeRecorder(10)
+			got, err := reconcileElasticUser(context.Background(), c, es, tt.existingFileRealm, filerealm.New(), recorder, testPasswordHasher)
 			require.NoError(t, err)
+			require.Len(t, recorder.Events, tt.wantEvents)
 			// check returned user
 			require.Len(t, got, 1)
 			user := got[0]
@@ -155,7 +161,7 @@ func Test_reconcileElasticUser_conditionalCreation(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := k8s.NewFakeClient()
-			got, err := reconcileElasticUser(context.Background(), c, es, filerealm.New(), tt.userFileReam, testPasswordHasher)
+			got, err := reconcileElasticUser(context.Background(), c, es, filerealm.New(), tt.userFileReam, record.NewFakeRecorder(10), testPasswordHasher)
 			require.NoError(t, err)
 			// check returned user
 			wantLen := 1
@@ -309,7 +315,7 @@ func Test_reconcileInternalUsers(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := k8s.NewFakeClient(tt.existingSecrets...)
-			got, err := reconcileInternalUsers(context.Background(), c, tt.es(), tt.existingFileRealm, testPasswordHasher)
+			got, err := reconcileInternalUsers(context.Background(), c, tt.es(), tt.existingFileRealm, record.NewFakeRecorder(10), testPasswordHasher)
 			require.True(t, ((err != nil) == tt.errorExpected), "error expected: %v, got: %v", tt.errorExpected, err)
 			if tt.errorExpected {
 				return
@@ -34,9 +34,13 @@ import (
 // - predefined users include the controller user, the probe user, and the public-facing elastic user
 // - associated users come from resource associations (eg. Kibana or APMServer)
 // - user-provided users from file realms referenced in the Elasticsearch spec
+// - ElasticsearchUser resources referencing the cluster
 // Roles are aggregated from:
 // - predefined roles (for the probe user)
 // - user-provided roles referenced in the Elasticsearch spec
+// - ElasticsearchRole resources referencing the cluster
+// Users and roles of ElasticsearchUser and ElasticsearchRole resources which are deleted are removed from the secret,
+// and the status of the remaining resources is updated once the secret is reconciled.
 func ReconcileUsersAndRoles(
 	ctx context.Context,
 	c k8s.Client,
@@ -57,6 +61,12 @@ func ReconcileUsersAndRoles(
 	if err != nil {
 		return esclient.BasicAuth{}, err
 	}
+	resources, err := retrieveSecurityResources(ctx, c, es, fileRealm, roles, watched, passwordHasher)
+	if err != nil {
+		return esclient.BasicAuth{}, err
+	}
+	roles = roles.MergeWith(resources.roles)
+	fileRealm = fileRealm.MergeWith(resources.fileRealm)
 
 	// reconcile the service accounts
 	saTokens, err := GetServiceAccountTokens(c, es)
@@ -68,6 +78,9 @@ func ReconcileUsersAndRoles(
 	if err := reconcileRolesFileRealmSecret(ctx, c, es, roles, fileRealm, saTokens); err != nil {
 		return esclient.BasicAuth{}, err
 	}
+	if err := resources.updateStatuses(ctx, c, recorder); err != nil {
+		return esclient.BasicAuth{}, err
+	}
 
 	// return the controller user for next reconciliation steps to interact with Elasticsearch
 	return controllerUser, nil
@@ -106,11 +119,11 @@ func aggregateFileRealm(
 	}
 
 	// reconcile predefined users
-	elasticUser, err := reconcileElasticUser(ctx, c, es, existingFileRealm, userProvidedFileRealm, passwordHasher)
+	elasticUser, err := reconcileElasticUser(ctx, c, es, existingFileRealm, userProvidedFileRealm, recorder, passwordHasher)
 	if err != nil {
 		return filerealm.Realm{}, esclient.BasicAuth{}, err
 	}
-	internalUsers, err := reconcileInternalUsers(ctx, c, es, existingFileRealm, passwordHasher)
+	internalUsers, err := reconcileInternalUsers(ctx, c, es, existingFileRealm, recorder, passwordHasher)
 	if err != nil {
 		return filerealm.Realm{}, esclient.BasicAuth{}, err
 	}
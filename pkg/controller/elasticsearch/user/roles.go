@@ -54,6 +54,9 @@ const (
 
 	LogstashUserRole = "eck_logstash_user_role"
 
+	// OTelCollectorUserRole is the name of the role used by OpenTelemetry collectors to export data to Elasticsearch
+	OTelCollectorUserRole = "eck_otel_collector_user_role"
+
 	// V70 indicates version 7.0
 	V70 = "v70"
 
@@ -107,8 +110,12 @@ var (
 	}
 	// PredefinedRoles to create for internal needs.
 	PredefinedRoles = RolesFileContent{
-		ProbeUserRole:     esclient.Role{Cluster: []string{"monitor"}},
-		ClusterManageRole: esclient.Role{Cluster: []string{"manage"}},
+		ProbeUserRole: esclient.Role{Cluster: []string{"monitor"}},
+		ClusterManageRole: esclient.Role{
+			Cluster: []string{"manage"},
+			// flush the indices from the Velero pre-backup hook
+			Indices: []esclient.IndexRole{{Names: []string{"*"}, Privileges: []string{"maintenance"}}},
+		},
 		DiagnosticsUserRoleV80: esclient.Role{
 			Cluster:      []string{"monitor", "monitor_snapshot", "manage", "read_ilm", "manage_security"},
 			Indices:      diagnosticsRoleIndices,
@@ -244,6 +251,15 @@ var (
 				},
 			},
 		},
+		OTelCollectorUserRole: esclient.Role{
+			Cluster: []string{"monitor"},
+			Indices: []esclient.IndexRole{
+				{
+					Names:      []string{"logs-*", "metrics-*", "traces-*"},
+					Privileges: []string{"auto_configure", "create_doc"},
+				},
+			},
+		},
 	}
 
 	// Additional index permissions for Beats users
@@ -0,0 +1,261 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package user
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	secv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/security/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/user/filerealm"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/cryptutil"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// UserResourcesPasswordsWatchName returns the watch registered for the Secrets holding the passwords of the
+// ElasticsearchUser resources of the given cluster.
+func UserResourcesPasswordsWatchName(es types.NamespacedName) string {
+	return fmt.Sprintf("%s-%s-user-resources-passwords", es.Namespace, es.Name)
+}
+
+// securityResources holds the users and roles defined by the ElasticsearchUser and ElasticsearchRole resources
+// referencing a cluster, along with the expected status of each resource.
+type securityResources struct {
+	fileRealm filerealm.Realm
+	roles     RolesFileContent
+
+	users        []secv1alpha1.ElasticsearchUser
+	userStatuses []secv1alpha1.SyncStatus
+	roleObjects  []secv1alpha1.ElasticsearchRole
+	roleStatuses []secv1alpha1.SyncStatus
+}
+
+// sortByCreation sorts the given resources from the oldest to the most recent, so that the oldest resource wins if
+// several resources define the same user or role.
+func sortByCreation[T any](items []T, meta func(T) metav1.ObjectMeta) {
+	sort.SliceStable(items, func(i, j int) bool {
+		mi, mj := meta(items[i]), meta(items[j])
+		if !mi.CreationTimestamp.Equal(&mj.CreationTimestamp) {
+			return mi.CreationTimestamp.Before(&mj.CreationTimestamp)
+		}
+		return mi.Name < mj.Name
+	})
+}
+
+// retrieveSecurityResources returns the users and roles defined by the ElasticsearchUser and ElasticsearchRole
+// resources in the namespace of the given cluster which reference it. Resources defining a user or a role which
+// already exists in the given file realm or roles, which are managed by ECK or provided through the Elasticsearch
+// spec, are not applied and marked as invalid.
+// The Secrets holding the passwords of the users are watched for future reconciliations to be triggered on any change.
+func retrieveSecurityResources(
+	ctx context.Context,
+	c k8s.Client,
+	es esv1.Elasticsearch,
+	fileRealm filerealm.Realm,
+	roles RolesFileContent,
+	watched watches.DynamicWatches,
+	passwordHasher cryptutil.PasswordHasher,
+) (securityResources, error) {
+	resources := securityResources{fileRealm: filerealm.New(), roles: make(RolesFileContent)}
+
+	var roleList secv1alpha1.ElasticsearchRoleList
+	if err := c.List(ctx, &roleList, client.InNamespace(es.Namespace)); err != nil {
+		return securityResources{}, err
+	}
+	for _, role := range roleList.Items {
+		if role.Spec.ElasticsearchRef.Name == es.Name {
+			resources.roleObjects = append(resources.roleObjects, role)
+		}
+	}
+	sortByCreation(resources.roleObjects, func(r secv1alpha1.ElasticsearchRole) metav1.ObjectMeta { return r.ObjectMeta })
+	for i := range resources.roleObjects {
+		role := &resources.roleObjects[i]
+		name := role.EffectiveRoleName()
+		_, reserved := roles[name]
+		_, defined := resources.roles[name]
+		err := validUserOrRoleName(name)
+		switch {
+		case err != nil:
+		case reserved:
+			err = fmt.Errorf("role %s is managed by ECK or defined in the Elasticsearch spec", name)
+		case defined:
+			err = fmt.Errorf("role %s is already defined by another ElasticsearchRole", name)
+		}
+		resources.roleStatuses = append(resources.roleStatuses, syncStatus(role.Generation, err))
+		if err == nil {
+			definition := role.Spec.Definition.Data
+			if definition == nil {
+				definition = map[string]interface{}{}
+			}
+			resources.roles[name] = definition
+		}
+	}
+
+	var userList secv1alpha1.ElasticsearchUserList
+	if err := c.List(ctx, &userList, client.InNamespace(es.Namespace)); err != nil {
+		return securityResources{}, err
+	}
+	for _, u := range userList.Items {
+		if u.Spec.ElasticsearchRef.Name == es.Name {
+			resources.users = append(resources.users, u)
+		}
+	}
+	sortByCreation(resources.users, func(u secv1alpha1.ElasticsearchUser) metav1.ObjectMeta { return u.ObjectMeta })
+
+	esKey := k8s.ExtractNamespacedName(&es)
+	secretNames := make([]string, 0, len(resources.users))
+	for _, u := range resources.users {
+		secretNames = append(secretNames, u.Spec.PasswordSecretRef.SecretName)
+	}
+	if err := watches.WatchUserProvidedSecrets(esKey, watched, UserResourcesPasswordsWatchName(esKey), secretNames); err != nil {
+		return securityResources{}, err
+	}
+
+	// retrieve the existing file realm to reuse the password hashes if possible
+	existing, err := getExistingFileRealm(c, es)
+	if err != nil && apierrors.IsNotFound(err) {
+		existing = filerealm.New()
+	} else if err != nil {
+		return securityResources{}, err
+	}
+	reserved := fileRealm.UserNames()
+	for i := range resources.users {
+		u := &resources.users[i]
+		fromResource, err := userFromResource(ctx, c, *u, reserved, resources.fileRealm.UserNames(), existing, passwordHasher)
+		if err != nil && !isInvalidUserResource(err) {
+			return securityResources{}, err
+		}
+		resources.userStatuses = append(resources.userStatuses, syncStatus(u.Generation, err))
+		if err == nil {
+			resources.fileRealm = resources.fileRealm.MergeWith(fromResource.fileRealm())
+		}
+	}
+	return resources, nil
+}
+
+// invalidUserResourceError is returned when an ElasticsearchUser cannot be applied until it or its password Secret
+// is updated.
+type invalidUserResourceError struct {
+	error
+}
+
+func isInvalidUserResource(err error) bool {
+	_, ok := err.(invalidUserResourceError) //nolint:errorlint
+	return ok
+}
+
+// userFromResource returns the user defined by the given ElasticsearchUser.
+func userFromResource(
+	ctx context.Context,
+	c k8s.Client,
+	resource secv1alpha1.ElasticsearchUser,
+	reserved []string,
+	defined []string,
+	existing filerealm.Realm,
+	passwordHasher cryptutil.PasswordHasher,
+) (user, error) {
+	name := resource.EffectiveUsername()
+	if slices.Contains(reserved, name) {
+		return user{}, invalidUserResourceError{fmt.Errorf("user %s is managed by ECK or defined in the Elasticsearch spec", name)}
+	}
+	if slices.Contains(defined, name) {
+		return user{}, invalidUserResourceError{fmt.Errorf("user %s is already defined by another ElasticsearchUser", name)}
+	}
+
+	ref := resource.Spec.PasswordSecretRef
+	var secret corev1.Secret
+	if err := c.Get(ctx, types.NamespacedName{Namespace: resource.Namespace, Name: ref.SecretName}, &secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return user{}, invalidUserResourceError{fmt.Errorf("password secret %s not found", ref.SecretName)}
+		}
+		return user{}, err
+	}
+	password, ok := secret.Data[ref.SecretKey()]
+	if !ok {
+		return user{}, invalidUserResourceError{fmt.Errorf("key %s not found in password secret %s", ref.SecretKey(), ref.SecretName)}
+	}
+
+	u := user{Name: name, Password: password, Roles: resource.Spec.Roles}
+	if err := u.Validate(); err != nil {
+		return user{}, invalidUserResourceError{err}
+	}
+	passwordHash, err := passwordHasher.ReuseOrGenerateHash(u.Password, existing.PasswordHashForUser(u.Name))
+	if err != nil {
+		return user{}, err
+	}
+	u.PasswordHash = passwordHash
+	return u, nil
+}
+
+// syncStatus returns the status of a resource given the error preventing it from being applied, if any.
+func syncStatus(generation int64, err error) secv1alpha1.SyncStatus {
+	if err != nil {
+		return secv1alpha1.SyncStatus{Phase: secv1alpha1.InvalidPhase, Message: err.Error(), ObservedGeneration: generation}
+	}
+	return secv1alpha1.SyncStatus{Phase: secv1alpha1.ReadyPhase, ObservedGeneration: generation}
+}
+
+// updateStatuses updates the status of the ElasticsearchUser and ElasticsearchRole resources once the file realm and
+// the roles have been applied. A warning event is emitted for the resources becoming invalid.
+func (r securityResources) updateStatuses(ctx context.Context, c k8s.Client, recorder record.EventRecorder) error {
+	for i := range r.roleObjects {
+		role := r.roleObjects[i]
+		status := withConditions(r.roleStatuses[i], role.Status)
+		if reflect.DeepEqual(role.Status, status) {
+			continue
+		}
+		role.Status = status
+		if err := updateStatus(ctx, c, recorder, &role, role.Status); err != nil {
+			return err
+		}
+	}
+	for i := range r.users {
+		u := r.users[i]
+		status := withConditions(r.userStatuses[i], u.Status)
+		if reflect.DeepEqual(u.Status, status) {
+			continue
+		}
+		u.Status = status
+		if err := updateStatus(ctx, c, recorder, &u, u.Status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withConditions returns the given status with its conditions set, preserving the transition times of the conditions of
+// the previous status which did not change.
+func withConditions(status, previous secv1alpha1.SyncStatus) secv1alpha1.SyncStatus {
+	status.Conditions = previous.Conditions.WithReadinessConditions(status.ResourceState(), metav1.Now())
+	return status
+}
+
+func updateStatus(ctx context.Context, c k8s.Client, recorder record.EventRecorder, obj client.Object, status secv1alpha1.SyncStatus) error {
+	if status.Phase == secv1alpha1.InvalidPhase {
+		recorder.Event(obj, corev1.EventTypeWarning, events.EventReasonValidation, status.Message)
+	}
+	ulog.FromContext(ctx).V(1).Info("Updating status", "kind", obj.GetObjectKind().GroupVersionKind().Kind,
+		"namespace", obj.GetNamespace(), "name", obj.GetName())
+	err := c.Status().Update(ctx, obj)
+	if apierrors.IsNotFound(err) {
+		// the resource has been deleted in the meantime, the cluster is reconciled again to remove it
+		return nil
+	}
+	return err
+}
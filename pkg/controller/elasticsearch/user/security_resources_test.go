@@ -0,0 +1,143 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	secv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/security/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/user/filerealm"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func TestReconcileUsersAndRoles_SecurityResources(t *testing.T) {
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec:       esv1.ElasticsearchSpec{Version: "8.15.0"},
+	}
+	ref := secv1alpha1.ElasticsearchRef{Name: "es"}
+	now := metav1.Now()
+	later := metav1.NewTime(now.Add(time.Minute))
+	newRole := func(name string, created metav1.Time, spec secv1alpha1.ElasticsearchRoleSpec) *secv1alpha1.ElasticsearchRole {
+		return &secv1alpha1.ElasticsearchRole{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, Generation: 1, CreationTimestamp: created},
+			Spec:       spec,
+		}
+	}
+	newUser := func(name string, spec secv1alpha1.ElasticsearchUserSpec) *secv1alpha1.ElasticsearchUser {
+		return &secv1alpha1.ElasticsearchUser{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, Generation: 1, CreationTimestamp: now},
+			Spec:       spec,
+		}
+	}
+	readers := commonv1.Config{Data: map[string]interface{}{"cluster": []interface{}{"monitor"}}}
+	objects := []client.Object{
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "alice-password"},
+			Data:       map[string][]byte{"password": []byte("alice-password")},
+		},
+		newRole("readers", now, secv1alpha1.ElasticsearchRoleSpec{ElasticsearchRef: ref, Definition: readers}),
+		// defines the same role as an older resource
+		newRole("readers-copy", later, secv1alpha1.ElasticsearchRoleSpec{ElasticsearchRef: ref, RoleName: "readers", Definition: readers}),
+		// conflicts with a predefined role
+		newRole("probe", now, secv1alpha1.ElasticsearchRoleSpec{ElasticsearchRef: ref, RoleName: ProbeUserRole, Definition: readers}),
+		// references another cluster
+		newRole("other", now, secv1alpha1.ElasticsearchRoleSpec{ElasticsearchRef: secv1alpha1.ElasticsearchRef{Name: "other"}, Definition: readers}),
+		newUser("alice", secv1alpha1.ElasticsearchUserSpec{
+			ElasticsearchRef:  ref,
+			PasswordSecretRef: secv1alpha1.PasswordSecretRef{SecretName: "alice-password"},
+			Roles:             []string{"readers"},
+		}),
+		// the password secret does not exist
+		newUser("bob", secv1alpha1.ElasticsearchUserSpec{
+			ElasticsearchRef:  ref,
+			PasswordSecretRef: secv1alpha1.PasswordSecretRef{SecretName: "bob-password"},
+		}),
+		// conflicts with a predefined user
+		newUser("admin", secv1alpha1.ElasticsearchUserSpec{
+			ElasticsearchRef:  ref,
+			Username:          ElasticUserName,
+			PasswordSecretRef: secv1alpha1.PasswordSecretRef{SecretName: "alice-password"},
+		}),
+	}
+	c := k8s.NewFakeClient(objects...)
+	ctx := context.Background()
+
+	_, err := ReconcileUsersAndRoles(ctx, c, es, initDynamicWatches(), record.NewFakeRecorder(10), testPasswordHasher)
+	require.NoError(t, err)
+
+	var secret corev1.Secret
+	require.NoError(t, c.Get(ctx, RolesFileRealmSecretKey(es), &secret))
+	realm, err := filerealm.FromSecret(secret)
+	require.NoError(t, err)
+	require.Contains(t, realm.UserNames(), "alice")
+	require.NotContains(t, realm.UserNames(), "bob")
+	require.Contains(t, string(secret.Data[filerealm.UsersRolesFile]), "readers:alice")
+	roles, err := parseRolesFileContent(secret.Data[RolesFile])
+	require.NoError(t, err)
+	require.EqualValues(t, map[string]interface{}{"cluster": []interface{}{"monitor"}}, roles["readers"])
+
+	expectedPhases := map[string]secv1alpha1.SyncPhase{
+		"readers":      secv1alpha1.ReadyPhase,
+		"readers-copy": secv1alpha1.InvalidPhase,
+		"probe":        secv1alpha1.InvalidPhase,
+		"other":        "",
+	}
+	for name, phase := range expectedPhases {
+		var role secv1alpha1.ElasticsearchRole
+		require.NoError(t, c.Get(ctx, k8s.ExtractNamespacedName(newRole(name, now, secv1alpha1.ElasticsearchRoleSpec{})), &role))
+		require.Equal(t, phase, role.Status.Phase, name)
+	}
+	expectedPhases = map[string]secv1alpha1.SyncPhase{
+		"alice": secv1alpha1.ReadyPhase,
+		"bob":   secv1alpha1.InvalidPhase,
+		"admin": secv1alpha1.InvalidPhase,
+	}
+	for name, phase := range expectedPhases {
+		var u secv1alpha1.ElasticsearchUser
+		require.NoError(t, c.Get(ctx, k8s.ExtractNamespacedName(newUser(name, secv1alpha1.ElasticsearchUserSpec{})), &u))
+		require.Equal(t, phase, u.Status.Phase, name)
+		require.Equal(t, int64(1), u.Status.ObservedGeneration)
+		stalled := u.Status.Conditions[u.Status.Conditions.Index(commonv1alpha1.StalledCondition)]
+		require.Equal(t, phase == secv1alpha1.InvalidPhase, stalled.Status == corev1.ConditionTrue, name)
+	}
+
+	// the password hash is reused as long as the password does not change
+	aliceHash := realm.PasswordHashForUser("alice")
+	_, err = ReconcileUsersAndRoles(ctx, c, es, initDynamicWatches(), record.NewFakeRecorder(10), testPasswordHasher)
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, RolesFileRealmSecretKey(es), &secret))
+	realm, err = filerealm.FromSecret(secret)
+	require.NoError(t, err)
+	require.Equal(t, aliceHash, realm.PasswordHashForUser("alice"))
+
+	// users and roles of deleted resources are removed from the file realm
+	require.NoError(t, c.Delete(ctx, newUser("alice", secv1alpha1.ElasticsearchUserSpec{})))
+	require.NoError(t, c.Delete(ctx, newRole("readers", now, secv1alpha1.ElasticsearchRoleSpec{})))
+	_, err = ReconcileUsersAndRoles(ctx, c, es, initDynamicWatches(), record.NewFakeRecorder(10), testPasswordHasher)
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, RolesFileRealmSecretKey(es), &secret))
+	realm, err = filerealm.FromSecret(secret)
+	require.NoError(t, err)
+	require.NotContains(t, realm.UserNames(), "alice")
+	roles, err = parseRolesFileContent(secret.Data[RolesFile])
+	require.NoError(t, err)
+	// the role of the newer resource is now applied
+	require.Contains(t, roles, "readers")
+	var readersCopy secv1alpha1.ElasticsearchRole
+	require.NoError(t, c.Get(ctx, k8s.ExtractNamespacedName(newRole("readers-copy", now, secv1alpha1.ElasticsearchRoleSpec{})), &readersCopy))
+	require.Equal(t, secv1alpha1.ReadyPhase, readersCopy.Status.Phase)
+}
@@ -0,0 +1,175 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package validation
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+const (
+	maxClustersExceededMsg = "Namespace quota exceeded: at most %d Elasticsearch clusters are allowed per namespace"
+	maxMemoryExceededMsg   = "Namespace quota exceeded: Elasticsearch clusters would use %s of memory in the namespace, the quota is %s"
+	maxStorageExceededMsg  = "Namespace quota exceeded: Elasticsearch clusters would use %s of storage in the namespace, the quota is %s"
+)
+
+// defaultMemoryLimits is the memory assumed for an Elasticsearch container without resource requirements.
+// It must be kept in sync with nodespec.DefaultMemoryLimits which cannot be imported here.
+var defaultMemoryLimits = resource.MustParse("2Gi")
+
+// NamespaceQuota defines the maximum amount of Elasticsearch resources that can be created in a single namespace.
+// A zero value means that the corresponding resource is not limited.
+type NamespaceQuota struct {
+	MaxClusters int
+	MaxMemory   *resource.Quantity
+	MaxStorage  *resource.Quantity
+}
+
+// NewNamespaceQuota returns a NamespaceQuota from the operator flags. Empty quantities mean no limit.
+func NewNamespaceQuota(maxClusters int, maxMemory, maxStorage string) (NamespaceQuota, error) {
+	quota := NamespaceQuota{MaxClusters: maxClusters}
+	if maxClusters < 0 {
+		return NamespaceQuota{}, fmt.Errorf("maximum number of clusters per namespace cannot be negative: %d", maxClusters)
+	}
+	for _, q := range []struct {
+		value  string
+		target **resource.Quantity
+	}{
+		{value: maxMemory, target: &quota.MaxMemory},
+		{value: maxStorage, target: &quota.MaxStorage},
+	} {
+		if q.value == "" {
+			continue
+		}
+		parsed, err := resource.ParseQuantity(q.value)
+		if err != nil {
+			return NamespaceQuota{}, fmt.Errorf("invalid namespace quota %q: %w", q.value, err)
+		}
+		*q.target = &parsed
+	}
+	return quota, nil
+}
+
+// IsEnabled returns true if at least one resource is limited.
+func (q NamespaceQuota) IsEnabled() bool {
+	return q.MaxClusters > 0 || q.MaxMemory != nil || q.MaxStorage != nil
+}
+
+// resourceUsage is the amount of resources used by Elasticsearch clusters.
+type resourceUsage struct {
+	clusters int
+	memory   resource.Quantity
+	storage  resource.Quantity
+}
+
+func (u *resourceUsage) add(other resourceUsage) {
+	u.clusters += other.clusters
+	u.memory.Add(other.memory)
+	u.storage.Add(other.storage)
+}
+
+// elasticsearchUsage returns the memory and storage requested by all the nodes of an Elasticsearch cluster.
+func elasticsearchUsage(es esv1.Elasticsearch) resourceUsage {
+	usage := resourceUsage{clusters: 1}
+	for _, nodeSet := range es.Spec.NodeSets {
+		memory := nodeMemory(nodeSet.PodTemplate.Spec.Containers)
+		storage := nodeStorage(nodeSet.VolumeClaimTemplates)
+		for i := int32(0); i < nodeSet.Count; i++ {
+			usage.memory.Add(memory)
+			usage.storage.Add(storage)
+		}
+	}
+	return usage
+}
+
+// nodeMemory returns the memory limit of the Elasticsearch container, falling back to the memory request and to the
+// default memory limit.
+func nodeMemory(containers []corev1.Container) resource.Quantity {
+	for _, c := range containers {
+		if c.Name != esv1.ElasticsearchContainerName {
+			continue
+		}
+		if limit, exists := c.Resources.Limits[corev1.ResourceMemory]; exists {
+			return limit
+		}
+		if request, exists := c.Resources.Requests[corev1.ResourceMemory]; exists {
+			return request
+		}
+	}
+	return defaultMemoryLimits
+}
+
+// nodeStorage returns the storage requested by the volume claim templates of a node, falling back to the default data volume claim.
+func nodeStorage(claims []corev1.PersistentVolumeClaim) resource.Quantity {
+	if len(claims) == 0 {
+		claims = []corev1.PersistentVolumeClaim{volume.DefaultDataVolumeClaim}
+	}
+	var total resource.Quantity
+	for _, claim := range claims {
+		if storage, exists := claim.Spec.Resources.Requests[corev1.ResourceStorage]; exists {
+			total.Add(storage)
+		}
+	}
+	return total
+}
+
+// namespaceUsage returns the resources used by the Elasticsearch clusters of a namespace, except the one named excluded.
+func namespaceUsage(ctx context.Context, c k8s.Client, namespace string, excluded string) (resourceUsage, error) {
+	var esList esv1.ElasticsearchList
+	if err := c.List(ctx, &esList, client.InNamespace(namespace)); err != nil {
+		return resourceUsage{}, err
+	}
+	var usage resourceUsage
+	for _, es := range esList.Items {
+		if es.Name == excluded {
+			continue
+		}
+		usage.add(elasticsearchUsage(es))
+	}
+	return usage, nil
+}
+
+// CheckNamespaceQuota verifies that the proposed Elasticsearch cluster fits in the quota of its namespace. current is
+// nil when the cluster is being created. Updates of a cluster are only rejected if they increase the usage of a
+// resource above the quota, so that clusters created before the quota was lowered can still be managed.
+func CheckNamespaceQuota(ctx context.Context, c k8s.Client, quota NamespaceQuota, proposed esv1.Elasticsearch, current *esv1.Elasticsearch) (field.ErrorList, error) {
+	if !quota.IsEnabled() {
+		return nil, nil
+	}
+	usage, err := namespaceUsage(ctx, c, proposed.Namespace, proposed.Name)
+	if err != nil {
+		return nil, err
+	}
+	proposedUsage := elasticsearchUsage(proposed)
+	var currentUsage *resourceUsage
+	if current != nil {
+		u := elasticsearchUsage(*current)
+		currentUsage = &u
+	}
+	usage.add(proposedUsage)
+
+	var errs field.ErrorList
+	if quota.MaxClusters > 0 && usage.clusters > quota.MaxClusters && currentUsage == nil {
+		errs = append(errs, field.Forbidden(field.NewPath("metadata").Child("namespace"), fmt.Sprintf(maxClustersExceededMsg, quota.MaxClusters)))
+	}
+	if quota.MaxMemory != nil && usage.memory.Cmp(*quota.MaxMemory) > 0 &&
+		(currentUsage == nil || proposedUsage.memory.Cmp(currentUsage.memory) > 0) {
+		errs = append(errs, field.Forbidden(field.NewPath("spec").Child("nodeSets"), fmt.Sprintf(maxMemoryExceededMsg, usage.memory.String(), quota.MaxMemory.String())))
+	}
+	if quota.MaxStorage != nil && usage.storage.Cmp(*quota.MaxStorage) > 0 &&
+		(currentUsage == nil || proposedUsage.storage.Cmp(currentUsage.storage) > 0) {
+		errs = append(errs, field.Forbidden(field.NewPath("spec").Child("nodeSets"), fmt.Sprintf(maxStorageExceededMsg, usage.storage.String(), quota.MaxStorage.String())))
+	}
+	return errs, nil
+}
@@ -0,0 +1,36 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package validation_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/nodespec"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/validation"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+// TestCheckNamespaceQuota_DefaultMemoryLimits makes sure the memory assumed by the namespace quota for Elasticsearch
+// containers without resource requirements matches the default applied by the operator.
+func TestCheckNamespaceQuota_DefaultMemoryLimits(t *testing.T) {
+	quota := validation.NamespaceQuota{MaxMemory: &nodespec.DefaultMemoryLimits}
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec:       esv1.ElasticsearchSpec{NodeSets: []esv1.NodeSet{{Name: "default", Count: 1}}},
+	}
+	errs, err := validation.CheckNamespaceQuota(context.Background(), k8s.NewFakeClient(), quota, es, nil)
+	require.NoError(t, err)
+	require.Empty(t, errs)
+
+	es.Spec.NodeSets[0].Count = 2
+	errs, err = validation.CheckNamespaceQuota(context.Background(), k8s.NewFakeClient(), quota, es, nil)
+	require.NoError(t, err)
+	require.Len(t, errs, 1)
+}
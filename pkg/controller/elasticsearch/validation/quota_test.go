@@ -0,0 +1,136 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package validation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func TestNewNamespaceQuota(t *testing.T) {
+	quota, err := NewNamespaceQuota(0, "", "")
+	require.NoError(t, err)
+	assert.False(t, quota.IsEnabled())
+
+	quota, err = NewNamespaceQuota(2, "16Gi", "1Ti")
+	require.NoError(t, err)
+	assert.True(t, quota.IsEnabled())
+	assert.Equal(t, 2, quota.MaxClusters)
+	assert.Equal(t, resource.MustParse("16Gi"), *quota.MaxMemory)
+	assert.Equal(t, resource.MustParse("1Ti"), *quota.MaxStorage)
+
+	_, err = NewNamespaceQuota(-1, "", "")
+	require.Error(t, err)
+	_, err = NewNamespaceQuota(0, "lots", "")
+	require.Error(t, err)
+}
+
+func mkQuotaES(name string, count int32, memory string, storage string) esv1.Elasticsearch {
+	nodeSet := esv1.NodeSet{Name: "default", Count: count}
+	if memory != "" {
+		nodeSet.PodTemplate.Spec.Containers = []corev1.Container{{
+			Name: esv1.ElasticsearchContainerName,
+			Resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(memory)},
+			},
+		}}
+	}
+	if storage != "" {
+		nodeSet.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-data"},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(storage)},
+				},
+			},
+		}}
+	}
+	return esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name},
+		Spec:       esv1.ElasticsearchSpec{NodeSets: []esv1.NodeSet{nodeSet}},
+	}
+}
+
+func Test_elasticsearchUsage(t *testing.T) {
+	usage := elasticsearchUsage(mkQuotaES("es", 3, "", ""))
+	assert.Equal(t, 1, usage.clusters)
+	assert.True(t, resource.MustParse("6Gi").Equal(usage.memory))
+	assert.True(t, resource.MustParse("3Gi").Equal(usage.storage))
+
+	usage = elasticsearchUsage(mkQuotaES("es", 2, "4Gi", "100Gi"))
+	assert.True(t, resource.MustParse("8Gi").Equal(usage.memory))
+	assert.True(t, resource.MustParse("200Gi").Equal(usage.storage))
+}
+
+func TestCheckNamespaceQuota(t *testing.T) {
+	existing := mkQuotaES("existing", 2, "4Gi", "100Gi")
+	other := mkQuotaES("other", 10, "4Gi", "100Gi")
+	other.Namespace = "other-ns"
+	memoryQuota := resource.MustParse("16Gi")
+	storageQuota := resource.MustParse("500Gi")
+
+	tests := []struct {
+		name     string
+		quota    NamespaceQuota
+		proposed esv1.Elasticsearch
+		current  *esv1.Elasticsearch
+		wantErrs []string
+	}{
+		{
+			name:     "no quota",
+			proposed: mkQuotaES("new", 100, "64Gi", "10Ti"),
+		},
+		{
+			name:     "within quota",
+			quota:    NamespaceQuota{MaxClusters: 2, MaxMemory: &memoryQuota, MaxStorage: &storageQuota},
+			proposed: mkQuotaES("new", 2, "4Gi", "100Gi"),
+		},
+		{
+			name:     "too many clusters",
+			quota:    NamespaceQuota{MaxClusters: 1},
+			proposed: mkQuotaES("new", 1, "", ""),
+			wantErrs: []string{"at most 1 Elasticsearch clusters"},
+		},
+		{
+			name:     "too much memory and storage",
+			quota:    NamespaceQuota{MaxMemory: &memoryQuota, MaxStorage: &storageQuota},
+			proposed: mkQuotaES("new", 3, "4Gi", "200Gi"),
+			wantErrs: []string{"would use 20Gi of memory", "would use 800Gi of storage"},
+		},
+		{
+			name:     "update increasing usage above quota",
+			quota:    NamespaceQuota{MaxMemory: &memoryQuota},
+			proposed: mkQuotaES("existing", 5, "4Gi", "100Gi"),
+			current:  &existing,
+			wantErrs: []string{"would use 20Gi of memory"},
+		},
+		{
+			name:     "update not increasing usage of a namespace above quota",
+			quota:    NamespaceQuota{MaxClusters: 1, MaxMemory: &memoryQuota},
+			proposed: mkQuotaES("existing", 5, "4Gi", "100Gi"),
+			current:  func() *esv1.Elasticsearch { es := mkQuotaES("existing", 5, "4Gi", "100Gi"); return &es }(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := k8s.NewFakeClient(&existing, &other)
+			errs, err := CheckNamespaceQuota(context.Background(), c, tt.quota, tt.proposed, tt.current)
+			require.NoError(t, err)
+			require.Len(t, errs, len(tt.wantErrs))
+			for i, want := range tt.wantErrs {
+				assert.Contains(t, errs[i].Error(), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	common "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/settings"
+)
+
+var (
+	// breakerPercentageRegexp matches the circuit breaker limits expressed as a percentage of the JVM heap.
+	breakerPercentageRegexp = regexp.MustCompile(`^(\d+(?:\.\d+)?)%$`)
+	// breakerByteSizeRegexp matches the circuit breaker limits expressed as a byte size.
+	breakerByteSizeRegexp = regexp.MustCompile(`(?i)^(\d+(?:\.\d+)?)\s*(b|kb|mb|gb|tb|pb)$`)
+	// xmxRegexp matches the JVM option setting the maximum heap size.
+	xmxRegexp = regexp.MustCompile(`(?:^|\s)-Xmx(\d+)([kKmMgG]?)(?:\s|$)`)
+
+	byteSizeUnits = map[string]float64{
+		"b":  1,
+		"kb": 1 << 10,
+		"mb": 1 << 20,
+		"gb": 1 << 30,
+		"tb": 1 << 40,
+		"pb": 1 << 50,
+	}
+
+	// maxHeapSize is the maximum heap size set by Elasticsearch when it sizes the heap from the available memory,
+	// to keep using compressed object pointers.
+	maxHeapSize = resource.MustParse("31Gi")
+)
+
+const (
+	// defaultTotalBreakerLimit is the default limit of the parent circuit breaker when it accounts for the real memory usage.
+	defaultTotalBreakerLimit = "95%"
+	// defaultTotalBreakerLimitWithoutRealMemory is the default limit of the parent circuit breaker otherwise.
+	defaultTotalBreakerLimitWithoutRealMemory = "70%"
+)
+
+// validTuning checks the tuning settings of the NodeSets: thread pool names, thread pool sizes against the CPU
+// limit, circuit breaker limits against the JVM heap and against each other, and settings also set in the config.
+func validTuning(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	for i, ns := range es.Spec.NodeSets {
+		if ns.Tuning == nil {
+			continue
+		}
+		path := field.NewPath("spec").Child("nodeSets").Index(i).Child("tuning")
+		errs = append(errs, validThreadPools(ns, path.Child("threadPools"))...)
+		errs = append(errs, validCircuitBreakers(ns, path.Child("circuitBreakers"))...)
+		errs = append(errs, noDuplicateTuningSettings(ns, i)...)
+	}
+	return errs
+}
+
+func validThreadPools(ns esv1.NodeSet, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	for name, pool := range ns.Tuning.ThreadPools {
+		if !slices.Contains(esv1.FixedThreadPools, name) {
+			errs = append(errs, field.NotSupported(path.Key(name), name, esv1.FixedThreadPools))
+			continue
+		}
+		if name != "write" || pool.Size == nil {
+			continue
+		}
+		cpu, hasLimit := containerCPULimit(ns.PodTemplate.Spec.Containers)
+		if !hasLimit {
+			continue
+		}
+		// Elasticsearch refuses to start if the write thread pool is larger than the allocated processors plus one
+		maxSize := int64(math.Ceil(float64(cpu.MilliValue())/1000)) + 1
+		if int64(*pool.Size) > maxSize {
+			errs = append(errs, field.Invalid(path.Key(name).Child("size"), *pool.Size, fmt.Sprintf(writeThreadPoolSizeErrMsg, maxSize)))
+		}
+	}
+	return errs
+}
+
+func validCircuitBreakers(ns esv1.NodeSet, path *field.Path) field.ErrorList {
+	breakers := ns.Tuning.CircuitBreakers
+	if breakers == nil {
+		return nil
+	}
+	heap := estimatedHeapSize(ns.PodTemplate.Spec.Containers)
+
+	var errs field.ErrorList
+	limits := map[string]string{
+		"total":            breakers.Total,
+		"request":          breakers.Request,
+		"fielddata":        breakers.Fielddata,
+		"inFlightRequests": breakers.InFlightRequests,
+	}
+	bytes := map[string]int64{}
+	for _, name := range []string{"total", "request", "fielddata", "inFlightRequests"} {
+		limit := limits[name]
+		if limit == "" {
+			continue
+		}
+		limitBytes, err := breakerLimitBytes(limit, heap)
+		if err != nil {
+			errs = append(errs, field.Invalid(path.Child(name), limit, err.Error()))
+			continue
+		}
+		if limitBytes > heap.Value() {
+			errs = append(errs, field.Invalid(path.Child(name), limit, fmt.Sprintf(breakerAboveHeapErrMsg, heap.String())))
+			continue
+		}
+		bytes[name] = limitBytes
+	}
+
+	total, exists := bytes["total"]
+	if !exists {
+		if breakers.Total != "" {
+			// the parent limit is invalid, and already reported
+			return errs
+		}
+		defaultLimit := defaultTotalBreakerLimit
+		if breakers.UseRealMemory != nil && !*breakers.UseRealMemory {
+			defaultLimit = defaultTotalBreakerLimitWithoutRealMemory
+		}
+		total, _ = breakerLimitBytes(defaultLimit, heap)
+	}
+	for _, name := range []string{"request", "fielddata", "inFlightRequests"} {
+		if limitBytes, exists := bytes[name]; exists && limitBytes > total {
+			errs = append(errs, field.Invalid(path.Child(name), limits[name], childBreakerAboveTotalErrMsg))
+		}
+	}
+	return errs
+}
+
+// breakerLimitBytes returns the number of bytes of a circuit breaker limit given as a percentage of the heap or as a
+// byte size.
+func breakerLimitBytes(limit string, heap resource.Quantity) (int64, error) {
+	if match := breakerPercentageRegexp.FindStringSubmatch(limit); match != nil {
+		percentage, err := strconv.ParseFloat(match[1], 64)
+		if err != nil || percentage > 100 {
+			return 0, errors.New(invalidBreakerLimitErrMsg)
+		}
+		return int64(float64(heap.Value()) * percentage / 100), nil
+	}
+	if match := breakerByteSizeRegexp.FindStringSubmatch(limit); match != nil {
+		size, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			return 0, errors.New(invalidBreakerLimitErrMsg)
+		}
+		return int64(size * byteSizeUnits[strings.ToLower(match[2])]), nil
+	}
+	return 0, errors.New(invalidBreakerLimitErrMsg)
+}
+
+// estimatedHeapSize returns the maximum heap size set in the JVM options of the Elasticsearch container, or the heap
+// size Elasticsearch derives from the memory of the container.
+func estimatedHeapSize(containers []corev1.Container) resource.Quantity {
+	for _, c := range containers {
+		if c.Name != esv1.ElasticsearchContainerName {
+			continue
+		}
+		for _, env := range c.Env {
+			if env.Name != settings.EnvEsJavaOpts {
+				continue
+			}
+			if match := xmxRegexp.FindStringSubmatch(env.Value); match != nil {
+				suffix := map[string]string{"": "", "k": "Ki", "m": "Mi", "g": "Gi"}[strings.ToLower(match[2])]
+				if heap, err := resource.ParseQuantity(match[1] + suffix); err == nil {
+					return heap
+				}
+			}
+		}
+	}
+	memory := nodeMemory(containers)
+	heap := resource.NewQuantity(memory.Value()/2, resource.BinarySI)
+	if heap.Cmp(maxHeapSize) > 0 {
+		return maxHeapSize
+	}
+	return *heap
+}
+
+// containerCPULimit returns the CPU limit of the Elasticsearch container, if any.
+func containerCPULimit(containers []corev1.Container) (resource.Quantity, bool) {
+	for _, c := range containers {
+		if c.Name != esv1.ElasticsearchContainerName {
+			continue
+		}
+		limit, exists := c.Resources.Limits[corev1.ResourceCPU]
+		return limit, exists
+	}
+	return resource.Quantity{}, false
+}
+
+// noDuplicateTuningSettings checks that the settings set by the tuning fields are not also set in the NodeSet config,
+// where they would be silently overridden.
+func noDuplicateTuningSettings(ns esv1.NodeSet, index int) field.ErrorList {
+	if ns.Config == nil {
+		return nil
+	}
+	cfg, err := common.NewCanonicalConfigFrom(ns.Config.Data)
+	if err != nil {
+		// reported by the other config validations
+		return nil
+	}
+	tuningSettings := ns.Tuning.Settings()
+	names := make([]string, 0, len(tuningSettings))
+	for name := range tuningSettings {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var errs field.ErrorList
+	for _, name := range cfg.HasKeys(names) {
+		errs = append(errs, field.Forbidden(
+			field.NewPath("spec").Child("nodeSets").Index(index).Child("config").Child(name),
+			duplicateTuningSettingErrMsg,
+		))
+	}
+	return errs
+}
@@ -0,0 +1,166 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+)
+
+func Test_validTuning(t *testing.T) {
+	esContainer := func(memory, cpu, javaOpts string) corev1.Container {
+		c := corev1.Container{Name: esv1.ElasticsearchContainerName, Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{}}}
+		if memory != "" {
+			c.Resources.Limits[corev1.ResourceMemory] = resource.MustParse(memory)
+		}
+		if cpu != "" {
+			c.Resources.Limits[corev1.ResourceCPU] = resource.MustParse(cpu)
+		}
+		if javaOpts != "" {
+			c.Env = []corev1.EnvVar{{Name: "ES_JAVA_OPTS", Value: javaOpts}}
+		}
+		return c
+	}
+	tests := []struct {
+		name         string
+		tuning       *esv1.NodeSetTuning
+		container    corev1.Container
+		config       map[string]interface{}
+		expectErrors int
+	}{
+		{
+			name:         "no tuning: OK",
+			expectErrors: 0,
+		},
+		{
+			name: "supported thread pools: OK",
+			tuning: &esv1.NodeSetTuning{ThreadPools: map[string]esv1.ThreadPoolSettings{
+				"search": {Size: ptr.To[int32](13), QueueSize: ptr.To[int32](2000)},
+				"write":  {QueueSize: ptr.To[int32](-1)},
+			}},
+			expectErrors: 0,
+		},
+		{
+			name: "unknown thread pool: NOT OK",
+			tuning: &esv1.NodeSetTuning{ThreadPools: map[string]esv1.ThreadPoolSettings{
+				"wirte": {Size: ptr.To[int32](4)},
+			}},
+			expectErrors: 1,
+		},
+		{
+			name: "write thread pool within the CPU limit: OK",
+			tuning: &esv1.NodeSetTuning{ThreadPools: map[string]esv1.ThreadPoolSettings{
+				"write": {Size: ptr.To[int32](3)},
+			}},
+			container:    esContainer("", "1500m", ""),
+			expectErrors: 0,
+		},
+		{
+			name: "write thread pool above the CPU limit: NOT OK",
+			tuning: &esv1.NodeSetTuning{ThreadPools: map[string]esv1.ThreadPoolSettings{
+				"write": {Size: ptr.To[int32](4)},
+			}},
+			container:    esContainer("", "1500m", ""),
+			expectErrors: 1,
+		},
+		{
+			name: "write thread pool without CPU limit: OK",
+			tuning: &esv1.NodeSetTuning{ThreadPools: map[string]esv1.ThreadPoolSettings{
+				"write": {Size: ptr.To[int32](32)},
+			}},
+			expectErrors: 0,
+		},
+		{
+			name: "valid circuit breaker limits: OK",
+			tuning: &esv1.NodeSetTuning{CircuitBreakers: &esv1.CircuitBreakerSettings{
+				Total:            "90%",
+				Request:          "512mb",
+				Fielddata:        "40.5%",
+				InFlightRequests: "1GB",
+			}},
+			container:    esContainer("4Gi", "", ""),
+			expectErrors: 0,
+		},
+		{
+			name: "malformed circuit breaker limits: NOT OK",
+			tuning: &esv1.NodeSetTuning{CircuitBreakers: &esv1.CircuitBreakerSettings{
+				Total:   "90",
+				Request: "120%",
+			}},
+			expectErrors: 2,
+		},
+		{
+			name: "circuit breaker limit above the heap derived from the memory limit: NOT OK",
+			tuning: &esv1.NodeSetTuning{CircuitBreakers: &esv1.CircuitBreakerSettings{
+				Total: "3gb",
+			}},
+			container:    esContainer("4Gi", "", ""),
+			expectErrors: 1,
+		},
+		{
+			name: "circuit breaker limit within the heap set in the JVM options: OK",
+			tuning: &esv1.NodeSetTuning{CircuitBreakers: &esv1.CircuitBreakerSettings{
+				Total: "3gb",
+			}},
+			container:    esContainer("8Gi", "", "-Xms4g -Xmx4g"),
+			expectErrors: 0,
+		},
+		{
+			name: "child circuit breaker above the parent circuit breaker: NOT OK",
+			tuning: &esv1.NodeSetTuning{CircuitBreakers: &esv1.CircuitBreakerSettings{
+				Total:     "50%",
+				Fielddata: "60%",
+			}},
+			expectErrors: 1,
+		},
+		{
+			name: "child circuit breaker above the default parent limit without real memory: NOT OK",
+			tuning: &esv1.NodeSetTuning{CircuitBreakers: &esv1.CircuitBreakerSettings{
+				UseRealMemory: ptr.To(false),
+				Request:       "80%",
+			}},
+			expectErrors: 1,
+		},
+		{
+			name: "child circuit breaker within the default parent limit: OK",
+			tuning: &esv1.NodeSetTuning{CircuitBreakers: &esv1.CircuitBreakerSettings{
+				Request: "80%",
+			}},
+			expectErrors: 0,
+		},
+		{
+			name:         "setting also set in the config: NOT OK",
+			tuning:       &esv1.NodeSetTuning{SearchMaxBuckets: ptr.To[int32](20000)},
+			config:       map[string]interface{}{"search": map[string]interface{}{"max_buckets": 10000}},
+			expectErrors: 1,
+		},
+		{
+			name:         "other settings in the config: OK",
+			tuning:       &esv1.NodeSetTuning{SearchMaxBuckets: ptr.To[int32](20000)},
+			config:       map[string]interface{}{"thread_pool.write.size": 2},
+			expectErrors: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeSet := esv1.NodeSet{Name: "default", Tuning: tt.tuning}
+			if tt.container.Name != "" {
+				nodeSet.PodTemplate.Spec.Containers = []corev1.Container{tt.container}
+			}
+			if tt.config != nil {
+				nodeSet.Config = &commonv1.Config{Data: tt.config}
+			}
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{NodeSets: []esv1.NodeSet{nodeSet}}}
+			assert.Len(t, validTuning(es), tt.expectErrors)
+		})
+	}
+}
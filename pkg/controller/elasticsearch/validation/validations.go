@@ -8,8 +8,12 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"net/url"
+	"regexp"
+	"slices"
 	"strings"
 
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
@@ -17,30 +21,108 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/license"
 	stackmon "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/stackmon/validations"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/maintenance"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/stackmon/metricsets"
 	esversion "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/zonespread"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
 	netutil "github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
 )
 
 const (
-	cfgInvalidMsg                          = "Configuration invalid"
-	duplicateNodeSets                      = "NodeSet names must be unique"
-	invalidNamesErrMsg                     = "Elasticsearch configuration would generate resources with invalid names"
-	invalidSanIPErrMsg                     = "Invalid SAN IP address. Must be a valid IPv4 address"
-	masterRequiredMsg                      = "Elasticsearch needs to have at least one master node"
-	mixedRoleConfigMsg                     = "Detected a combination of node.roles and %s. Use only node.roles"
-	noDowngradesMsg                        = "Downgrades are not supported"
-	nodeRolesInOldVersionMsg               = "node.roles setting is not available in this version of Elasticsearch"
-	parseStoredVersionErrMsg               = "Cannot parse current Elasticsearch version. String format must be {major}.{minor}.{patch}[-{label}]"
-	parseVersionErrMsg                     = "Cannot parse Elasticsearch version. String format must be {major}.{minor}.{patch}[-{label}]"
-	pvcNotMountedErrMsg                    = "volume claim declared but volume not mounted in any container. Note that the Elasticsearch data volume should be named 'elasticsearch-data'"
-	unsupportedConfigErrMsg                = "Configuration setting is reserved for internal use. User-configured use is unsupported"
-	unsupportedUpgradeMsg                  = "Unsupported version upgrade path. Check the Elasticsearch documentation for supported upgrade paths."
-	unsupportedVersionMsg                  = "Unsupported version"
-	notAllowedNodesLabelMsg                = "Node label not in the exposed node labels list"
-	unsupportedClientAuthenticationMsg     = "Mandatory client authentication is not supported"
-	autoscalingAnnotationUnsupportedErrMsg = "autoscaling annotation is no longer supported"
+	breakerAboveHeapErrMsg                  = "Circuit breaker limit exceeds the estimated JVM heap size of %s"
+	cfgInvalidMsg                           = "Configuration invalid"
+	childBreakerAboveTotalErrMsg            = "Circuit breaker limit exceeds the limit of the parent circuit breaker"
+	deprecatedNodeRoleSettingMsg            = "Setting is deprecated from version 7.9.0 and removed in version 8.0.0, use node.roles instead"
+	duplicateAutoFollowPatternsErrMsg       = "Auto-follow pattern names must be unique across remote clusters"
+	duplicateFollowerIndicesErrMsg          = "Follower index names must be unique across remote clusters"
+	duplicateHealthProbesErrMsg             = "Health probe names must be unique"
+	duplicateNodeSets                       = "NodeSet names must be unique"
+	duplicatePluginsErrMsg                  = "Plugin names must be unique"
+	duplicateRealmsErrMsg                   = "Realm names must be unique"
+	duplicateTuningSettingErrMsg            = "Setting is already set in the NodeSet tuning, remove it from the config"
+	duplicateSnapshotRepositoriesErrMsg     = "Snapshot repository names must be unique"
+	duplicateTopologyKeysErrMsg             = "Topology keys must be unique"
+	duplicateZonesErrMsg                    = "Zone names must be unique"
+	ephemeralImmutableErrMsg                = "Ephemeral cannot be changed for an existing NodeSet. Rename the NodeSet to migrate its data"
+	ephemeralUnsupportedVersionErrMsg       = "Ephemeral NodeSets require the Elasticsearch node shutdown API, available from version %s"
+	ephemeralWithClaimsErrMsg               = "Ephemeral NodeSets cannot declare volume claim templates"
+	exclusionPatternsVersionErrMsg          = "Leader index exclusion patterns require version %s or later"
+	ingestAutoscalingBoundsErrMsg           = "minCount must be lower than or equal to maxCount"
+	ingestAutoscalingRolesErrMsg            = "Ingest autoscaling requires an ingest-only NodeSet, with node.roles set to [\"ingest\"]"
+	invalidBreakerLimitErrMsg               = "Circuit breaker limit must be a percentage of the JVM heap of at most 100%, or a byte size such as 512mb"
+	invalidNamesErrMsg                      = "Elasticsearch configuration would generate resources with invalid names"
+	invalidPluginNameErrMsg                 = "Plugin names must consist of lower case alphanumeric characters, '-' or '_', and start with an alphanumeric character"
+	invalidPluginURLErrMsg                  = "Plugin URL must be an absolute http, https or file URL"
+	invalidPluginsProxyErrMsg               = "Plugins proxy must be an http or https URL with a host"
+	invalidPublishNamespaceErrMsg           = "HTTP certificates can only be published to valid namespace names"
+	invalidRealmNameErrMsg                  = "Realm names must consist of alphanumeric characters, '-' or '_'"
+	invalidRealmTypeErrMsg                  = "Exactly one of oidc, saml or ldap must be set"
+	invalidSAMLMetadataErrMsg               = "Exactly one of idpMetadataURL or idpMetadata must be set"
+	invalidSanIPErrMsg                      = "Invalid SAN IP address. Must be a valid IPv4 address"
+	issuerRefWithCertificateErrMsg          = "issuerRef and certificate cannot be both specified, use one or the other"
+	licenseSecretNameRequiredErrMsg         = "licenseSecretRef requires a secretName"
+	masterRequiredMsg                       = "Elasticsearch needs to have at least one master node"
+	mixedRoleConfigMsg                      = "Detected a combination of node.roles and %s. Use only node.roles"
+	moduleAsPluginErrMsg                    = "Plugin is shipped as a module from Elasticsearch version %s and cannot be installed"
+	minDomainsWithSoftSpreadErrMsg          = "minDomains can only be set with the hard topology spread enforcement"
+	minDomainsAboveCountErrMsg              = "NodeSet count is lower than the topology spread minDomains: its Pods cannot be spread across the minimum number of domains"
+	noDowngradesMsg                         = "Downgrades are not supported"
+	nodeRolesInOldVersionMsg                = "node.roles setting is not available in this version of Elasticsearch"
+	nodeSetPDBWithClusterPDBErrMsg          = "NodeSet PodDisruptionBudgets cannot be combined with spec.podDisruptionBudget"
+	parseStoredVersionErrMsg                = "Cannot parse current Elasticsearch version. String format must be {major}.{minor}.{patch}[-{label}]"
+	parseVersionErrMsg                      = "Cannot parse Elasticsearch version. String format must be {major}.{minor}.{patch}[-{label}]"
+	realmUnsupportedVersionErrMsg           = "%s realms require version %s or later"
+	reservedRealmNameErrMsg                 = "Realm name is reserved for the built-in realms configured by the operator"
+	serviceMeshWithCertificatesErrMsg       = "HTTP certificates cannot be specified when the HTTP traffic is encrypted by a service mesh"
+	pvcNotMountedErrMsg                     = "volume claim declared but volume not mounted in any container. Note that the Elasticsearch data volume should be named 'elasticsearch-data'"
+	writeThreadPoolSizeErrMsg               = "Write thread pool size must be at most %d: the allocated processors derived from the CPU limit, plus one"
+	unsafeBootstrapUnsupportedVersionErrMsg = "Unsafe bootstrap requires the elasticsearch-node tool, available from version %s"
+	unsupportedConfigErrMsg                 = "Configuration setting is reserved for internal use. User-configured use is unsupported"
+	unsupportedUpgradeMsg                   = "Unsupported version upgrade path. Check the Elasticsearch documentation for supported upgrade paths."
+	unsupportedVersionMsg                   = "Unsupported version"
+	votingOnlyWithoutMasterErrMsg           = "The voting_only role requires the master role"
+	zoneAttributeInConfigErrMsg             = "Setting is set by the operator to the zone of the nodes, remove it from the config"
+	zoneSpreadWithIngestAutoscalingErrMsg   = "Zone spread cannot be combined with ingest autoscaling"
+	zoneStorageClassWithEphemeralErrMsg     = "Storage classes cannot be set for the zones of an ephemeral NodeSet"
+	notAllowedNodesLabelMsg                 = "Node label not in the exposed node labels list"
+	unsupportedClientAuthenticationMsg      = "Mandatory client authentication is not supported"
+	autoscalingAnnotationUnsupportedErrMsg  = "autoscaling annotation is no longer supported"
+	coordinatingOnlyWithoutDataMsg          = "Coordinating-only nodes require data nodes in the cluster to route the requests to"
+	singleElectableMasterMsg                = "Voting-only nodes cannot be elected as master: with a single master-eligible node that is not voting-only, the cluster cannot tolerate its loss"
+	twoMasterEligibleNodesMsg               = "A cluster with two master-eligible nodes cannot tolerate the loss of any of them, consider adding a voting-only node"
+)
+
+var (
+	// pluginNameRegexp matches the names of the plugins which can be installed with the elasticsearch-plugin tool.
+	pluginNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+	// realmNameRegexp matches the names of the realms which can be part of the name of a setting.
+	realmNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+	// reservedRealmNames are the names of the file and native realms configured by the operator.
+	reservedRealmNames = []string{"file1", "native1"}
+
+	// realmsMinVersions are the minimum versions of Elasticsearch supporting each type of realm configured with
+	// the 7.x realm settings syntax.
+	realmsMinVersions = map[string]version.Version{
+		esv1.OIDCRealmType: version.From(7, 2, 0),
+		esv1.SAMLRealmType: version.From(7, 0, 0),
+		esv1.LDAPRealmType: version.From(7, 0, 0),
+	}
+
+	// modulesVersions are the versions of Elasticsearch from which former plugins are shipped as modules in the
+	// Elasticsearch distribution, and can no longer be installed.
+	modulesVersions = map[string]version.Version{
+		"x-pack":            version.From(6, 3, 0),
+		"ingest-geoip":      version.From(6, 6, 0),
+		"ingest-user-agent": version.From(6, 6, 0),
+		"repository-azure":  version.From(8, 0, 0),
+		"repository-gcs":    version.From(8, 0, 0),
+		"repository-s3":     version.From(8, 0, 0),
+		"ingest-attachment": version.From(8, 4, 0),
+	}
 )
 
 type validation func(esv1.Elasticsearch) field.ErrorList
@@ -52,6 +134,7 @@ func updateValidations(ctx context.Context, k8sClient k8s.Client, validateStorag
 	return []updateValidation{
 		noDowngrades,
 		validUpgradePath,
+		noEphemeralModification,
 		func(current esv1.Elasticsearch, proposed esv1.Elasticsearch) field.ErrorList {
 			return validPVCModification(ctx, current, proposed, k8sClient, validateStorageClass)
 		},
@@ -69,8 +152,26 @@ func validations(ctx context.Context, checker license.Checker, exposedNodeLabels
 		hasCorrectNodeRoles,
 		supportedVersion,
 		validSanIP,
+		validCertificateSources,
+		validServiceMesh,
+		validSnapshotRepositories,
+		validSnapshotVerification,
+		validHealthProbes,
+		validMaintenanceWindows,
+		validLicenseSecretRef,
+		validCrossClusterReplication,
 		validAutoscalingConfiguration,
 		validPVCNaming,
+		validEphemeralNodeSets,
+		validIngestAutoscaling,
+		validPodDisruptionBudgets,
+		validTopologySpread,
+		validTuning,
+		validZoneSpread,
+		validPlugins,
+		validRealms,
+		validPublishHTTPCerts,
+		validUnsafeBootstrap,
 		validMonitoring,
 		validAssociations,
 		supportsRemoteClusterUsingAPIKey,
@@ -86,6 +187,11 @@ func validNodeLabels(proposed esv1.Elasticsearch, exposedNodeLabels NodeLabels)
 		if exposedNodeLabels.IsAllowed(nodeLabel) {
 			continue
 		}
+		// the topology label of the zone awareness is explicitly requested in the spec, it does not need to be exposed
+		// by the operator configuration
+		if proposed.Spec.ZoneAwareness.IsEnabled() && nodeLabel == proposed.Spec.ZoneAwareness.TopologyKeyOrDefault() {
+			continue
+		}
 		errs = append(
 			errs,
 			field.Invalid(
@@ -98,6 +204,169 @@ func validNodeLabels(proposed esv1.Elasticsearch, exposedNodeLabels NodeLabels)
 	return errs
 }
 
+// validPodDisruptionBudgets checks that the PodDisruptionBudgets of the NodeSets are not combined with a
+// PodDisruptionBudget for the whole cluster, which would select the same Pods.
+func validPodDisruptionBudgets(proposed esv1.Elasticsearch) field.ErrorList {
+	if proposed.Spec.PodDisruptionBudget == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	for i, ns := range proposed.Spec.NodeSets {
+		if ns.PodDisruptionBudget == nil {
+			continue
+		}
+		errs = append(errs, field.Forbidden(
+			field.NewPath("spec").Child("nodeSets").Index(i).Child("podDisruptionBudget"),
+			nodeSetPDBWithClusterPDBErrMsg,
+		))
+	}
+	return errs
+}
+
+// validTopologySpread checks that the topology keys of the topology spread policy are valid node label keys, and that
+// the count of each NodeSet allows its Pods to be spread across the minimum number of domains.
+func validTopologySpread(proposed esv1.Elasticsearch) field.ErrorList {
+	policy := proposed.Spec.TopologySpread
+	if policy == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	path := field.NewPath("spec").Child("topologySpread")
+	keys := make(map[string]struct{}, len(policy.TopologyKeys))
+	for i, key := range policy.TopologyKeys {
+		for _, msg := range utilvalidation.IsQualifiedName(key) {
+			errs = append(errs, field.Invalid(path.Child("topologyKeys").Index(i), key, msg))
+		}
+		if _, found := keys[key]; found {
+			errs = append(errs, field.Invalid(path.Child("topologyKeys").Index(i), key, duplicateTopologyKeysErrMsg))
+		}
+		keys[key] = struct{}{}
+	}
+	if policy.MinDomains == nil {
+		return errs
+	}
+	if !policy.IsHard() {
+		return append(errs, field.Invalid(path.Child("minDomains"), *policy.MinDomains, minDomainsWithSoftSpreadErrMsg))
+	}
+	for i, ns := range proposed.Spec.NodeSets {
+		if ns.Count > 0 && ns.Count < *policy.MinDomains {
+			errs = append(errs, field.Invalid(field.NewPath("spec").Child("nodeSets").Index(i).Child("count"), ns.Count, minDomainsAboveCountErrMsg))
+		}
+	}
+	return errs
+}
+
+// validPlugins checks that the plugins to install have unique and valid names and sources, and that they are not
+// shipped as modules with the version of Elasticsearch.
+func validPlugins(proposed esv1.Elasticsearch) field.ErrorList {
+	plugins := proposed.Spec.Plugins
+	if plugins == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	path := field.NewPath("spec").Child("plugins")
+	if plugins.Proxy != "" {
+		if u, err := url.Parse(plugins.Proxy); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+			errs = append(errs, field.Invalid(path.Child("proxy"), plugins.Proxy, invalidPluginsProxyErrMsg))
+		}
+	}
+	// invalid versions are reported by the version validation
+	ver, verErr := version.Parse(proposed.Spec.Version)
+	names := make(map[string]struct{}, len(plugins.Install))
+	for i, plugin := range plugins.Install {
+		pluginPath := path.Child("install").Index(i)
+		if !pluginNameRegexp.MatchString(plugin.Name) {
+			errs = append(errs, field.Invalid(pluginPath.Child("name"), plugin.Name, invalidPluginNameErrMsg))
+		}
+		if _, found := names[plugin.Name]; found {
+			errs = append(errs, field.Invalid(pluginPath.Child("name"), plugin.Name, duplicatePluginsErrMsg))
+		}
+		names[plugin.Name] = struct{}{}
+		if since, isModule := modulesVersions[plugin.Name]; isModule && verErr == nil && ver.GTE(since) {
+			errs = append(errs, field.Invalid(pluginPath.Child("name"), plugin.Name, fmt.Sprintf(moduleAsPluginErrMsg, since)))
+		}
+		if plugin.URL == "" {
+			continue
+		}
+		if u, err := url.Parse(plugin.URL); err != nil || !u.IsAbs() || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "file") {
+			errs = append(errs, field.Invalid(pluginPath.Child("url"), plugin.URL, invalidPluginURLErrMsg))
+		}
+	}
+	return errs
+}
+
+// validRealms checks that the realms are of a single type supported by the version of Elasticsearch, have unique names
+// which can be used in settings, and reference the metadata of SAML identity providers once.
+func validRealms(proposed esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	path := field.NewPath("spec").Child("auth", "realms")
+	// invalid versions are reported by the version validation
+	ver, verErr := version.Parse(proposed.Spec.Version)
+	names := make(map[string]struct{}, len(proposed.Spec.Auth.Realms))
+	for i, realm := range proposed.Spec.Auth.Realms {
+		realmPath := path.Index(i)
+		switch {
+		case !realmNameRegexp.MatchString(realm.Name):
+			errs = append(errs, field.Invalid(realmPath.Child("name"), realm.Name, invalidRealmNameErrMsg))
+		case slices.Contains(reservedRealmNames, realm.Name):
+			errs = append(errs, field.Invalid(realmPath.Child("name"), realm.Name, reservedRealmNameErrMsg))
+		}
+		if _, found := names[realm.Name]; found {
+			errs = append(errs, field.Invalid(realmPath.Child("name"), realm.Name, duplicateRealmsErrMsg))
+		}
+		names[realm.Name] = struct{}{}
+
+		types := 0
+		for _, isSet := range []bool{realm.OIDC != nil, realm.SAML != nil, realm.LDAP != nil} {
+			if isSet {
+				types++
+			}
+		}
+		if types != 1 {
+			errs = append(errs, field.Invalid(realmPath, realm.Name, invalidRealmTypeErrMsg))
+			continue
+		}
+		if minVersion := realmsMinVersions[realm.Type()]; verErr == nil && ver.LT(minVersion) {
+			errs = append(errs, field.Forbidden(realmPath.Child(realm.Type()), fmt.Sprintf(realmUnsupportedVersionErrMsg, strings.ToUpper(realm.Type()), minVersion)))
+		}
+		if realm.SAML != nil && (realm.SAML.IdPMetadataURL == "") == (realm.SAML.IdPMetadata == nil) {
+			errs = append(errs, field.Invalid(realmPath.Child("saml"), realm.Name, invalidSAMLMetadataErrMsg))
+		}
+	}
+	return errs
+}
+
+// validPublishHTTPCerts checks that the HTTP certificates are published to valid namespace names.
+func validPublishHTTPCerts(proposed esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	for _, namespace := range proposed.PublishHTTPCertsNamespaces() {
+		if len(utilvalidation.IsDNS1123Label(namespace)) > 0 {
+			errs = append(errs, field.Invalid(
+				field.NewPath("metadata").Child("annotations", esv1.PublishHTTPCertsAnnotation),
+				namespace,
+				invalidPublishNamespaceErrMsg,
+			))
+		}
+	}
+	return errs
+}
+
+// validUnsafeBootstrap checks that the unsafe bootstrap annotation is only used with versions of Elasticsearch which
+// ship the elasticsearch-node tool.
+func validUnsafeBootstrap(proposed esv1.Elasticsearch) field.ErrorList {
+	if _, exists := proposed.Annotations[esv1.UnsafeBootstrapAnnotation]; !exists {
+		return nil
+	}
+	// invalid versions are reported by the version validation
+	if v, err := version.Parse(proposed.Spec.Version); err == nil && v.LT(version.From(7, 0, 0)) {
+		return field.ErrorList{field.Forbidden(
+			field.NewPath("metadata").Child("annotations", esv1.UnsafeBootstrapAnnotation),
+			fmt.Sprintf(unsafeBootstrapUnsupportedVersionErrMsg, "7.0.0"),
+		)}
+	}
+	return nil
+}
+
 func check(es esv1.Elasticsearch, validations []validation) field.ErrorList {
 	var errs field.ErrorList
 	for _, val := range validations {
@@ -116,7 +385,8 @@ func noUnknownFields(es esv1.Elasticsearch) field.ErrorList {
 // validName checks whether the name is valid.
 func validName(es esv1.Elasticsearch) field.ErrorList {
 	var errs field.ErrorList
-	if err := esv1.ValidateNames(es); err != nil {
+	// the NodeSets spread across zones are validated through the name of the NodeSet of each zone
+	if err := esv1.ValidateNames(zonespread.Expand(es)); err != nil {
 		errs = append(errs, field.Invalid(field.NewPath("metadata").Child("name"), es.Name, fmt.Sprintf("%s: %s", invalidNamesErrMsg, err)))
 	}
 	return errs
@@ -170,6 +440,7 @@ func supportsRemoteClusterUsingAPIKey(es esv1.Elasticsearch) field.ErrorList {
 // The rules are:
 // There must be at least one master node.
 // node.roles are only supported on Elasticsearch 7.9.0 and above
+// voting-only nodes must be master-eligible
 func hasCorrectNodeRoles(es esv1.Elasticsearch) field.ErrorList {
 	v, err := version.Parse(es.Spec.Version)
 	if err != nil {
@@ -205,6 +476,11 @@ func hasCorrectNodeRoles(es esv1.Elasticsearch) field.ErrorList {
 			errs = append(errs, field.Forbidden(confField(i), fmt.Sprintf(mixedRoleConfigMsg, strings.Join(nodeRoleAttrs, ","))))
 		}
 
+		// check that voting-only nodes are master-eligible, Elasticsearch refuses to start otherwise
+		if cfg.Node.IsConfiguredWithRole(esv1.VotingOnlyRole) && !cfg.Node.IsConfiguredWithRole(esv1.MasterRole) {
+			errs = append(errs, field.Invalid(confField(i), ns.Config, votingOnlyWithoutMasterErrMsg))
+		}
+
 		// Check if this nodeSet has the master role.
 		seenMaster = seenMaster || (cfg.Node.IsConfiguredWithRole(esv1.MasterRole) && !cfg.Node.IsConfiguredWithRole(esv1.VotingOnlyRole) && ns.Count > 0)
 	}
@@ -216,6 +492,32 @@ func hasCorrectNodeRoles(es esv1.Elasticsearch) field.ErrorList {
 	return errs
 }
 
+// validIngestAutoscaling checks that ingest autoscaling is only configured on ingest-only NodeSets, with valid bounds.
+func validIngestAutoscaling(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	v, err := version.Parse(es.Spec.Version)
+	if err != nil {
+		// the version is validated separately
+		return errs
+	}
+	for i, ns := range es.Spec.NodeSets {
+		if ns.IngestAutoscaling == nil {
+			continue
+		}
+		path := field.NewPath("spec").Child("nodeSets").Index(i).Child("ingestAutoscaling")
+		if ns.IngestAutoscaling.MinCount > ns.IngestAutoscaling.MaxCount {
+			errs = append(errs, field.Invalid(path.Child("minCount"), ns.IngestAutoscaling.MinCount, ingestAutoscalingBoundsErrMsg))
+		}
+		cfg := esv1.ElasticsearchSettings{}
+		// invalid configurations are reported by the node roles validation
+		if err := esv1.UnpackConfig(ns.Config, v, &cfg); err == nil &&
+			(cfg.Node == nil || !slices.Equal(cfg.Node.Roles, []string{string(esv1.IngestRole)})) {
+			errs = append(errs, field.Invalid(path, ns.Name, ingestAutoscalingRolesErrMsg))
+		}
+	}
+	return errs
+}
+
 func getNodeRoleAttrs(cfg esv1.ElasticsearchSettings) []string {
 	var nodeRoleAttrs []string
 
@@ -269,6 +571,135 @@ func validSanIP(es esv1.Elasticsearch) field.ErrorList {
 	return errs
 }
 
+// validCertificateSources checks that certificates are not both provided by the user and issued by cert-manager.
+func validCertificateSources(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	if httpTLS := es.Spec.HTTP.TLS; httpTLS.IssuerRef != nil && httpTLS.Certificate.SecretName != "" {
+		errs = append(errs, field.Invalid(field.NewPath("spec").Child("http", "tls", "issuerRef"), httpTLS.IssuerRef.Name, issuerRefWithCertificateErrMsg))
+	}
+	if transportTLS := es.Spec.Transport.TLS; transportTLS.IssuerRef != nil && transportTLS.Certificate.SecretName != "" {
+		errs = append(errs, field.Invalid(field.NewPath("spec").Child("transport", "tls", "issuerRef"), transportTLS.IssuerRef.Name, issuerRefWithCertificateErrMsg))
+	}
+	return errs
+}
+
+// validServiceMesh checks that no HTTP certificate is specified when the HTTP traffic is encrypted by a service mesh.
+func validServiceMesh(es esv1.Elasticsearch) field.ErrorList {
+	if es.Spec.HTTP.ServiceMesh == "" {
+		return nil
+	}
+	var errs field.ErrorList
+	if es.Spec.HTTP.TLS.Certificate.SecretName != "" {
+		errs = append(errs, field.Forbidden(field.NewPath("spec").Child("http", "tls", "certificate"), serviceMeshWithCertificatesErrMsg))
+	}
+	if es.Spec.HTTP.TLS.IssuerRef != nil {
+		errs = append(errs, field.Forbidden(field.NewPath("spec").Child("http", "tls", "issuerRef"), serviceMeshWithCertificatesErrMsg))
+	}
+	return errs
+}
+
+// validSnapshotVerification checks that snapshot verification is only enabled on versions supporting it.
+func validSnapshotVerification(es esv1.Elasticsearch) field.ErrorList {
+	if es.Spec.SnapshotVerification == nil {
+		return nil
+	}
+	ver, err := version.Parse(es.Spec.Version)
+	if err != nil {
+		return field.ErrorList{field.Invalid(field.NewPath("spec").Child("version"), es.Spec.Version, parseVersionErrMsg)}
+	}
+	if ver.LT(esv1.SnapshotVerificationMinVersion) {
+		return field.ErrorList{field.Invalid(
+			field.NewPath("spec").Child("snapshotVerification"),
+			es.Spec.Version,
+			fmt.Sprintf("minimum required version for snapshot verification is %s but desired version is %s", esv1.SnapshotVerificationMinVersion, es.Spec.Version),
+		)}
+	}
+	return nil
+}
+
+// validSnapshotRepositories checks that snapshot repositories are not declared twice, since they are registered by name.
+func validSnapshotRepositories(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	names := make(map[string]struct{}, len(es.Spec.SnapshotRepositories))
+	for i, repository := range es.Spec.SnapshotRepositories {
+		if _, found := names[repository.Name]; found {
+			errs = append(errs, field.Invalid(field.NewPath("spec").Child("snapshotRepositories").Index(i).Child("name"), repository.Name, duplicateSnapshotRepositoriesErrMsg))
+		}
+		names[repository.Name] = struct{}{}
+	}
+	return errs
+}
+
+// validHealthProbes checks that health probes are not declared twice, since their results are reported by name.
+func validHealthProbes(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	names := make(map[string]struct{}, len(es.Spec.HealthProbes))
+	for i, probe := range es.Spec.HealthProbes {
+		if _, found := names[probe.Name]; found {
+			errs = append(errs, field.Invalid(field.NewPath("spec").Child("healthProbes").Index(i).Child("name"), probe.Name, duplicateHealthProbesErrMsg))
+		}
+		names[probe.Name] = struct{}{}
+	}
+	return errs
+}
+
+// validMaintenanceWindows checks that the schedules, durations and time zones of the maintenance windows can be parsed.
+func validMaintenanceWindows(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	for i, window := range es.Spec.MaintenanceWindows {
+		if _, err := maintenance.Parse(window); err != nil {
+			errs = append(errs, field.Invalid(field.NewPath("spec").Child("maintenanceWindows").Index(i), window.Schedule, err.Error()))
+		}
+	}
+	return errs
+}
+
+// validLicenseSecretRef checks that the license secret reference names a secret.
+func validLicenseSecretRef(es esv1.Elasticsearch) field.ErrorList {
+	if es.Spec.LicenseSecretRef == nil || es.Spec.LicenseSecretRef.SecretName != "" {
+		return nil
+	}
+	return field.ErrorList{field.Required(field.NewPath("spec").Child("licenseSecretRef", "secretName"), licenseSecretNameRequiredErrMsg)}
+}
+
+// validCrossClusterReplication checks that the auto-follow patterns and the follower indices declared in the remote
+// clusters have unique names, and that exclusion patterns are supported by the Elasticsearch version.
+func validCrossClusterReplication(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	ver, err := version.Parse(es.Spec.Version)
+	if err != nil {
+		// the version is validated separately
+		return errs
+	}
+	patterns := make(map[string]struct{})
+	followers := make(map[string]struct{})
+	for i, remoteCluster := range es.Spec.RemoteClusters {
+		if remoteCluster.Replication == nil {
+			continue
+		}
+		path := field.NewPath("spec").Child("remoteClusters").Index(i).Child("replication")
+		for j, pattern := range remoteCluster.Replication.AutoFollowPatterns {
+			if _, found := patterns[pattern.Name]; found {
+				errs = append(errs, field.Invalid(path.Child("autoFollowPatterns").Index(j).Child("name"), pattern.Name, duplicateAutoFollowPatternsErrMsg))
+			}
+			patterns[pattern.Name] = struct{}{}
+			if len(pattern.LeaderIndexExclusionPatterns) > 0 && !ver.GTE(esv1.AutoFollowExclusionPatternsMinVersion) {
+				errs = append(errs, field.Forbidden(
+					path.Child("autoFollowPatterns").Index(j).Child("leaderIndexExclusionPatterns"),
+					fmt.Sprintf(exclusionPatternsVersionErrMsg, esv1.AutoFollowExclusionPatternsMinVersion),
+				))
+			}
+		}
+		for j, follower := range remoteCluster.Replication.FollowerIndices {
+			if _, found := followers[follower.Name]; found {
+				errs = append(errs, field.Invalid(path.Child("followerIndices").Index(j).Child("name"), follower.Name, duplicateFollowerIndicesErrMsg))
+			}
+			followers[follower.Name] = struct{}{}
+		}
+	}
+	return errs
+}
+
 func checkNodeSetNameUniqueness(es esv1.Elasticsearch) field.ErrorList {
 	var errs field.ErrorList
 	nodeSets := es.Spec.NodeSets
@@ -361,7 +792,7 @@ func currentVersion(current esv1.Elasticsearch) (version.Version, *field.Error)
 }
 
 func validMonitoring(es esv1.Elasticsearch) field.ErrorList {
-	return stackmon.Validate(&es, es.Spec.Version, stackmon.MinStackVersion)
+	return stackmon.ValidateWithMetricsDestinations(&es, es.Spec.Version, stackmon.MinStackVersion, metricsets.Supported)
 }
 
 func validAssociations(es esv1.Elasticsearch) field.ErrorList {
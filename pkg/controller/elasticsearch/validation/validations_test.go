@@ -5,11 +5,14 @@
 package validation
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
@@ -149,6 +152,11 @@ func Test_hasCorrectNodeRoles(t *testing.T) {
 			es:           esWithRoles("7.6.0", 1, m{esv1.NodeRoles: []esv1.NodeRole{esv1.MasterRole}}, m{esv1.NodeRoles: []esv1.NodeRole{esv1.DataRole}}),
 			expectErrors: true,
 		},
+		{
+			name:         "voting-only without master role",
+			es:           esWithRoles("7.9.0", 3, m{esv1.NodeRoles: []esv1.NodeRole{esv1.MasterRole, esv1.DataRole}}, m{esv1.NodeRoles: []esv1.NodeRole{esv1.VotingOnlyRole}}),
+			expectErrors: true,
+		},
 		{
 			name: "valid configuration (node attributes)",
 			es:   esWithRoles("7.6.0", 3, m{esv1.NodeMaster: "true", esv1.NodeData: "true"}, m{esv1.NodeData: "true"}),
@@ -416,6 +424,666 @@ func Test_validSanIP(t *testing.T) {
 	}
 }
 
+func Test_validCertificateSources(t *testing.T) {
+	issuerRef := &commonv1.CertManagerIssuerRef{Name: "ca-issuer"}
+	certificate := commonv1.SecretRef{SecretName: "my-cert"}
+	tests := []struct {
+		name         string
+		es           esv1.Elasticsearch
+		expectErrors int
+	}{
+		{
+			name:         "no certificate source: OK",
+			es:           esv1.Elasticsearch{},
+			expectErrors: 0,
+		},
+		{
+			name: "issuers only: OK",
+			es: esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{
+				HTTP:      commonv1.HTTPConfig{TLS: commonv1.TLSOptions{IssuerRef: issuerRef}},
+				Transport: esv1.TransportConfig{TLS: esv1.TransportTLSOptions{IssuerRef: issuerRef}},
+			}},
+			expectErrors: 0,
+		},
+		{
+			name: "issuer for HTTP, user-provided CA for transport: OK",
+			es: esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{
+				HTTP:      commonv1.HTTPConfig{TLS: commonv1.TLSOptions{IssuerRef: issuerRef}},
+				Transport: esv1.TransportConfig{TLS: esv1.TransportTLSOptions{Certificate: certificate}},
+			}},
+			expectErrors: 0,
+		},
+		{
+			name: "issuer and certificate for HTTP and transport: NOT OK",
+			es: esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{
+				HTTP:      commonv1.HTTPConfig{TLS: commonv1.TLSOptions{IssuerRef: issuerRef, Certificate: certificate}},
+				Transport: esv1.TransportConfig{TLS: esv1.TransportTLSOptions{IssuerRef: issuerRef, Certificate: certificate}},
+			}},
+			expectErrors: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Len(t, validCertificateSources(tt.es), tt.expectErrors)
+		})
+	}
+}
+
+func Test_validServiceMesh(t *testing.T) {
+	tests := []struct {
+		name         string
+		http         commonv1.HTTPConfig
+		expectErrors int
+	}{
+		{
+			name:         "no service mesh: OK",
+			http:         commonv1.HTTPConfig{TLS: commonv1.TLSOptions{Certificate: commonv1.SecretRef{SecretName: "my-cert"}}},
+			expectErrors: 0,
+		},
+		{
+			name:         "istio: OK",
+			http:         commonv1.HTTPConfig{ServiceMesh: commonv1.IstioServiceMesh},
+			expectErrors: 0,
+		},
+		{
+			name: "istio with custom certificates: NOT OK",
+			http: commonv1.HTTPConfig{
+				ServiceMesh: commonv1.IstioServiceMesh,
+				TLS: commonv1.TLSOptions{
+					Certificate: commonv1.SecretRef{SecretName: "my-cert"},
+					IssuerRef:   &commonv1.CertManagerIssuerRef{Name: "ca-issuer"},
+				},
+			},
+			expectErrors: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{HTTP: tt.http}}
+			assert.Len(t, validServiceMesh(es), tt.expectErrors)
+		})
+	}
+}
+
+func Test_validSnapshotRepositories(t *testing.T) {
+	tests := []struct {
+		name         string
+		repositories []esv1.SnapshotRepository
+		expectErrors int
+	}{
+		{
+			name:         "no repositories: OK",
+			expectErrors: 0,
+		},
+		{
+			name:         "unique names: OK",
+			repositories: []esv1.SnapshotRepository{{Name: "backups", Type: "s3"}, {Name: "archive", Type: "gcs"}},
+			expectErrors: 0,
+		},
+		{
+			name:         "duplicate names: NOT OK",
+			repositories: []esv1.SnapshotRepository{{Name: "backups", Type: "s3"}, {Name: "backups", Type: "gcs"}},
+			expectErrors: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{SnapshotRepositories: tt.repositories}}
+			assert.Len(t, validSnapshotRepositories(es), tt.expectErrors)
+		})
+	}
+}
+
+func Test_validHealthProbes(t *testing.T) {
+	tests := []struct {
+		name         string
+		probes       []esv1.HealthProbe
+		expectErrors int
+	}{
+		{
+			name:         "no probes: OK",
+			expectErrors: 0,
+		},
+		{
+			name:         "unique names: OK",
+			probes:       []esv1.HealthProbe{{Name: "search", Path: "/canary/_search"}, {Name: "ingest", Method: "POST", Path: "/canary/_doc"}},
+			expectErrors: 0,
+		},
+		{
+			name:         "duplicate names: NOT OK",
+			probes:       []esv1.HealthProbe{{Name: "search", Path: "/canary/_search"}, {Name: "search", Path: "/other/_search"}},
+			expectErrors: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{HealthProbes: tt.probes}}
+			assert.Len(t, validHealthProbes(es), tt.expectErrors)
+		})
+	}
+}
+
+func Test_validMaintenanceWindows(t *testing.T) {
+	tests := []struct {
+		name         string
+		windows      []esv1.MaintenanceWindow
+		expectErrors int
+	}{
+		{
+			name:         "no windows: OK",
+			expectErrors: 0,
+		},
+		{
+			name: "valid windows: OK",
+			windows: []esv1.MaintenanceWindow{
+				{Schedule: "0 2 * * SAT,SUN", Duration: metav1.Duration{Duration: 4 * time.Hour}, TimeZone: "Europe/Paris"},
+				{Schedule: "@daily", Duration: metav1.Duration{Duration: 30 * time.Minute}},
+			},
+			expectErrors: 0,
+		},
+		{
+			name: "invalid schedule, duration and time zone: NOT OK",
+			windows: []esv1.MaintenanceWindow{
+				{Schedule: "0 2 * *", Duration: metav1.Duration{Duration: time.Hour}},
+				{Schedule: "0 2 * * *", Duration: metav1.Duration{Duration: 0}},
+				{Schedule: "0 2 * * *", Duration: metav1.Duration{Duration: time.Hour}, TimeZone: "Mars/Olympus_Mons"},
+			},
+			expectErrors: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{MaintenanceWindows: tt.windows}}
+			assert.Len(t, validMaintenanceWindows(es), tt.expectErrors)
+		})
+	}
+}
+
+func Test_validLicenseSecretRef(t *testing.T) {
+	tests := []struct {
+		name         string
+		ref          *commonv1.SecretRef
+		expectErrors int
+	}{
+		{
+			name:         "no reference: OK",
+			expectErrors: 0,
+		},
+		{
+			name:         "secret name: OK",
+			ref:          &commonv1.SecretRef{SecretName: "license"},
+			expectErrors: 0,
+		},
+		{
+			name:         "no secret name: NOT OK",
+			ref:          &commonv1.SecretRef{},
+			expectErrors: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{LicenseSecretRef: tt.ref}}
+			assert.Len(t, validLicenseSecretRef(es), tt.expectErrors)
+		})
+	}
+}
+
+func Test_validCrossClusterReplication(t *testing.T) {
+	tests := []struct {
+		name           string
+		version        string
+		remoteClusters []esv1.RemoteCluster
+		expectErrors   int
+	}{
+		{
+			name:           "no replication: OK",
+			version:        "8.15.0",
+			remoteClusters: []esv1.RemoteCluster{{Name: "leader"}},
+			expectErrors:   0,
+		},
+		{
+			name:    "unique names: OK",
+			version: "8.15.0",
+			remoteClusters: []esv1.RemoteCluster{
+				{Name: "leader-a", Replication: &esv1.RemoteClusterReplication{
+					AutoFollowPatterns: []esv1.AutoFollowPattern{{Name: "logs-a", LeaderIndexPatterns: []string{"logs-*"}, LeaderIndexExclusionPatterns: []string{"logs-tmp-*"}}},
+					FollowerIndices:    []esv1.FollowerIndex{{Name: "metrics-a", LeaderIndex: "metrics"}},
+				}},
+				{Name: "leader-b", Replication: &esv1.RemoteClusterReplication{
+					AutoFollowPatterns: []esv1.AutoFollowPattern{{Name: "logs-b", LeaderIndexPatterns: []string{"logs-*"}}},
+					FollowerIndices:    []esv1.FollowerIndex{{Name: "metrics-b", LeaderIndex: "metrics"}},
+				}},
+			},
+			expectErrors: 0,
+		},
+		{
+			name:    "duplicate names across remote clusters: NOT OK",
+			version: "8.15.0",
+			remoteClusters: []esv1.RemoteCluster{
+				{Name: "leader-a", Replication: &esv1.RemoteClusterReplication{
+					AutoFollowPatterns: []esv1.AutoFollowPattern{{Name: "logs", LeaderIndexPatterns: []string{"logs-*"}}},
+					FollowerIndices:    []esv1.FollowerIndex{{Name: "metrics", LeaderIndex: "metrics"}},
+				}},
+				{Name: "leader-b", Replication: &esv1.RemoteClusterReplication{
+					AutoFollowPatterns: []esv1.AutoFollowPattern{{Name: "logs", LeaderIndexPatterns: []string{"logs-*"}}},
+					FollowerIndices:    []esv1.FollowerIndex{{Name: "metrics", LeaderIndex: "metrics"}},
+				}},
+			},
+			expectErrors: 2,
+		},
+		{
+			name:    "exclusion patterns with an unsupported version: NOT OK",
+			version: "7.13.0",
+			remoteClusters: []esv1.RemoteCluster{
+				{Name: "leader", Replication: &esv1.RemoteClusterReplication{
+					AutoFollowPatterns: []esv1.AutoFollowPattern{{Name: "logs", LeaderIndexPatterns: []string{"logs-*"}, LeaderIndexExclusionPatterns: []string{"logs-tmp-*"}}},
+				}},
+			},
+			expectErrors: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: tt.version, RemoteClusters: tt.remoteClusters}}
+			assert.Len(t, validCrossClusterReplication(es), tt.expectErrors)
+		})
+	}
+}
+
+func Test_validSnapshotVerification(t *testing.T) {
+	tests := []struct {
+		name         string
+		version      string
+		verification *esv1.SnapshotVerification
+		expectErrors int
+	}{
+		{
+			name:         "disabled: OK",
+			version:      "6.8.0",
+			expectErrors: 0,
+		},
+		{
+			name:         "supported version: OK",
+			version:      "7.4.0",
+			verification: &esv1.SnapshotVerification{RestoreSample: true},
+			expectErrors: 0,
+		},
+		{
+			name:         "unsupported version: NOT OK",
+			version:      "7.3.2",
+			verification: &esv1.SnapshotVerification{},
+			expectErrors: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: tt.version, SnapshotVerification: tt.verification}}
+			assert.Len(t, validSnapshotVerification(es), tt.expectErrors)
+		})
+	}
+}
+
+func Test_validIngestAutoscaling(t *testing.T) {
+	ingestOnly := &commonv1.Config{Data: map[string]interface{}{"node.roles": []interface{}{"ingest"}}}
+	tests := []struct {
+		name         string
+		config       *commonv1.Config
+		autoscaling  *esv1.IngestAutoscaling
+		expectErrors int
+	}{
+		{
+			name:         "disabled: OK",
+			expectErrors: 0,
+		},
+		{
+			name:         "ingest-only NodeSet: OK",
+			config:       ingestOnly,
+			autoscaling:  &esv1.IngestAutoscaling{MinCount: 1, MaxCount: 3},
+			expectErrors: 0,
+		},
+		{
+			name:         "min count above max count: NOT OK",
+			config:       ingestOnly,
+			autoscaling:  &esv1.IngestAutoscaling{MinCount: 4, MaxCount: 3},
+			expectErrors: 1,
+		},
+		{
+			name:         "default roles: NOT OK",
+			autoscaling:  &esv1.IngestAutoscaling{MinCount: 1, MaxCount: 3},
+			expectErrors: 1,
+		},
+		{
+			name:         "ingest and data roles: NOT OK",
+			config:       &commonv1.Config{Data: map[string]interface{}{"node.roles": []interface{}{"ingest", "data"}}},
+			autoscaling:  &esv1.IngestAutoscaling{MinCount: 1, MaxCount: 3},
+			expectErrors: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{
+				Version:  "8.15.0",
+				NodeSets: []esv1.NodeSet{{Name: "ingest", Count: 1, Config: tt.config, IngestAutoscaling: tt.autoscaling}},
+			}}
+			assert.Len(t, validIngestAutoscaling(es), tt.expectErrors)
+		})
+	}
+}
+
+func Test_validTopologySpread(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       *esv1.TopologySpreadPolicy
+		counts       []int32
+		expectErrors int
+	}{
+		{
+			name:         "default: OK",
+			counts:       []int32{3},
+			expectErrors: 0,
+		},
+		{
+			name:         "soft spread across hosts and zones: OK",
+			policy:       &esv1.TopologySpreadPolicy{TopologyKeys: []string{esv1.HostTopologyKey, esv1.ZoneTopologyKey}},
+			counts:       []int32{1, 3},
+			expectErrors: 0,
+		},
+		{
+			name:         "hard spread across at least 3 zones: OK",
+			policy:       &esv1.TopologySpreadPolicy{Enforcement: esv1.HardTopologySpread, TopologyKeys: []string{esv1.ZoneTopologyKey}, MinDomains: ptr.To[int32](3)},
+			counts:       []int32{3, 6, 0},
+			expectErrors: 0,
+		},
+		{
+			name:         "invalid and duplicate topology keys: NOT OK",
+			policy:       &esv1.TopologySpreadPolicy{TopologyKeys: []string{"invalid key", esv1.ZoneTopologyKey, esv1.ZoneTopologyKey}},
+			counts:       []int32{3},
+			expectErrors: 2,
+		},
+		{
+			name:         "minDomains with soft spread: NOT OK",
+			policy:       &esv1.TopologySpreadPolicy{TopologyKeys: []string{esv1.ZoneTopologyKey}, MinDomains: ptr.To[int32](3)},
+			counts:       []int32{3},
+			expectErrors: 1,
+		},
+		{
+			name:         "NodeSet count lower than minDomains: NOT OK",
+			policy:       &esv1.TopologySpreadPolicy{Enforcement: esv1.HardTopologySpread, TopologyKeys: []string{esv1.ZoneTopologyKey}, MinDomains: ptr.To[int32](3)},
+			counts:       []int32{3, 2, 1},
+			expectErrors: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{TopologySpread: tt.policy}}
+			for i, count := range tt.counts {
+				es.Spec.NodeSets = append(es.Spec.NodeSets, esv1.NodeSet{Name: fmt.Sprintf("nodeset-%d", i), Count: count})
+			}
+			assert.Len(t, validTopologySpread(es), tt.expectErrors)
+		})
+	}
+}
+
+func Test_validPlugins(t *testing.T) {
+	tests := []struct {
+		name         string
+		version      string
+		plugins      *esv1.PluginsSpec
+		expectErrors int
+	}{
+		{
+			name:         "no plugins: OK",
+			version:      "8.15.0",
+			expectErrors: 0,
+		},
+		{
+			name:    "official and custom plugins through a proxy: OK",
+			version: "8.15.0",
+			plugins: &esv1.PluginsSpec{
+				Install: []esv1.Plugin{
+					{Name: "analysis-icu"},
+					{Name: "custom_plugin", URL: "https://artifacts.example.com/custom_plugin-8.15.0.zip"},
+					{Name: "analysis-kuromoji", URL: "file:///mnt/plugins/analysis-kuromoji-8.15.0.zip"},
+				},
+				Proxy: "http://proxy.example.com:3128",
+			},
+			expectErrors: 0,
+		},
+		{
+			name:    "plugin shipped as a module in a later version: OK",
+			version: "7.17.0",
+			plugins: &esv1.PluginsSpec{
+				Install: []esv1.Plugin{{Name: "repository-s3"}},
+			},
+			expectErrors: 0,
+		},
+		{
+			name:    "plugin shipped as a module: NOT OK",
+			version: "8.15.0",
+			plugins: &esv1.PluginsSpec{
+				Install: []esv1.Plugin{{Name: "repository-s3"}, {Name: "ingest-attachment"}},
+			},
+			expectErrors: 2,
+		},
+		{
+			name:    "invalid and duplicate names: NOT OK",
+			version: "8.15.0",
+			plugins: &esv1.PluginsSpec{
+				Install: []esv1.Plugin{{Name: "analysis-icu"}, {Name: "analysis-icu"}, {Name: "Analysis ICU"}, {Name: ""}},
+			},
+			expectErrors: 3,
+		},
+		{
+			name:    "invalid URLs and proxy: NOT OK",
+			version: "8.15.0",
+			plugins: &esv1.PluginsSpec{
+				Install: []esv1.Plugin{
+					{Name: "analysis-icu", URL: "analysis-icu-8.15.0.zip"},
+					{Name: "analysis-kuromoji", URL: "ftp://artifacts.example.com/analysis-kuromoji-8.15.0.zip"},
+				},
+				Proxy: "proxy.example.com:3128",
+			},
+			expectErrors: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: tt.version, Plugins: tt.plugins}}
+			assert.Len(t, validPlugins(es), tt.expectErrors)
+		})
+	}
+}
+
+func Test_validRealms(t *testing.T) {
+	oidc := &esv1.OIDCRealm{Issuer: "https://op.example.com", ClientID: "elasticsearch"}
+	samlWithURL := &esv1.SAMLRealm{IdPEntityID: "https://idp.example.com", IdPMetadataURL: "https://idp.example.com/metadata.xml"}
+	ldap := &esv1.LDAPRealm{URLs: []string{"ldaps://ldap.example.com:636"}}
+	tests := []struct {
+		name         string
+		version      string
+		realms       []esv1.Realm
+		expectErrors int
+	}{
+		{
+			name:         "no realms: OK",
+			version:      "8.15.0",
+			expectErrors: 0,
+		},
+		{
+			name:    "one realm of each type: OK",
+			version: "8.15.0",
+			realms: []esv1.Realm{
+				{Name: "oidc1", Order: 2, OIDC: oidc},
+				{Name: "saml1", Order: 3, SAML: samlWithURL},
+				{Name: "ldap_1", Order: 4, LDAP: ldap},
+			},
+			expectErrors: 0,
+		},
+		{
+			name:    "SAML metadata from a secret: OK",
+			version: "8.15.0",
+			realms: []esv1.Realm{
+				{Name: "saml1", SAML: &esv1.SAMLRealm{IdPMetadata: &esv1.SecretKeyRef{SecretName: "idp", Key: "metadata.xml"}}},
+			},
+			expectErrors: 0,
+		},
+		{
+			name:    "no or several types: NOT OK",
+			version: "8.15.0",
+			realms: []esv1.Realm{
+				{Name: "none"},
+				{Name: "both", OIDC: oidc, LDAP: ldap},
+			},
+			expectErrors: 2,
+		},
+		{
+			name:    "invalid, reserved and duplicate names: NOT OK",
+			version: "8.15.0",
+			realms: []esv1.Realm{
+				{Name: "oidc1", OIDC: oidc},
+				{Name: "oidc1", LDAP: ldap},
+				{Name: "my.realm", LDAP: ldap},
+				{Name: "native1", LDAP: ldap},
+			},
+			expectErrors: 3,
+		},
+		{
+			name:    "missing or ambiguous SAML metadata: NOT OK",
+			version: "8.15.0",
+			realms: []esv1.Realm{
+				{Name: "saml1", SAML: &esv1.SAMLRealm{}},
+				{Name: "saml2", SAML: &esv1.SAMLRealm{
+					IdPMetadataURL: "https://idp.example.com/metadata.xml",
+					IdPMetadata:    &esv1.SecretKeyRef{SecretName: "idp", Key: "metadata.xml"},
+				}},
+			},
+			expectErrors: 2,
+		},
+		{
+			name:    "unsupported versions: NOT OK",
+			version: "7.1.0",
+			realms: []esv1.Realm{
+				{Name: "oidc1", OIDC: oidc},
+				{Name: "saml1", SAML: samlWithURL},
+			},
+			expectErrors: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: tt.version, Auth: esv1.Auth{Realms: tt.realms}}}
+			assert.Len(t, validRealms(es), tt.expectErrors)
+		})
+	}
+}
+
+func Test_validPublishHTTPCerts(t *testing.T) {
+	tests := []struct {
+		name         string
+		annotation   *string
+		expectErrors int
+	}{
+		{
+			name:         "no annotation: OK",
+			expectErrors: 0,
+		},
+		{
+			name:         "valid namespaces: OK",
+			annotation:   ptr.To("app-1, app-2,"),
+			expectErrors: 0,
+		},
+		{
+			name:         "invalid namespaces: NOT OK",
+			annotation:   ptr.To("app-1,App_2,app.3"),
+			expectErrors: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
+			if tt.annotation != nil {
+				es.Annotations = map[string]string{esv1.PublishHTTPCertsAnnotation: *tt.annotation}
+			}
+			assert.Len(t, validPublishHTTPCerts(es), tt.expectErrors)
+		})
+	}
+}
+
+func Test_validPodDisruptionBudgets(t *testing.T) {
+	tests := []struct {
+		name         string
+		clusterPDB   *commonv1.PodDisruptionBudgetTemplate
+		nodeSetPDB   *commonv1.PodDisruptionBudgetTemplate
+		expectErrors int
+	}{
+		{
+			name:         "default PDB: OK",
+			expectErrors: 0,
+		},
+		{
+			name:         "NodeSet PDB: OK",
+			nodeSetPDB:   &commonv1.PodDisruptionBudgetTemplate{},
+			expectErrors: 0,
+		},
+		{
+			name:         "cluster PDB: OK",
+			clusterPDB:   &commonv1.PodDisruptionBudgetTemplate{},
+			expectErrors: 0,
+		},
+		{
+			name:         "NodeSet PDB and cluster PDB: NOT OK",
+			clusterPDB:   &commonv1.PodDisruptionBudgetTemplate{},
+			nodeSetPDB:   &commonv1.PodDisruptionBudgetTemplate{},
+			expectErrors: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{
+				PodDisruptionBudget: tt.clusterPDB,
+				NodeSets:            []esv1.NodeSet{{Name: "default"}, {Name: "other", PodDisruptionBudget: tt.nodeSetPDB}},
+			}}
+			assert.Len(t, validPodDisruptionBudgets(es), tt.expectErrors)
+		})
+	}
+}
+
+func Test_validUnsafeBootstrap(t *testing.T) {
+	tests := []struct {
+		name         string
+		version      string
+		annotations  map[string]string
+		expectErrors int
+	}{
+		{
+			name:         "no annotation: OK",
+			version:      "6.8.0",
+			expectErrors: 0,
+		},
+		{
+			name:         "annotation with 7.x: OK",
+			version:      "7.17.0",
+			annotations:  map[string]string{esv1.UnsafeBootstrapAnnotation: "cluster-es-default-0"},
+			expectErrors: 0,
+		},
+		{
+			name:         "annotation with 6.x: NOT OK",
+			version:      "6.8.0",
+			annotations:  map[string]string{esv1.UnsafeBootstrapAnnotation: "cluster-es-default-0"},
+			expectErrors: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations},
+				Spec:       esv1.ElasticsearchSpec{Version: tt.version},
+			}
+			assert.Len(t, validUnsafeBootstrap(es), tt.expectErrors)
+		})
+	}
+}
+
 func TestValidation_noDowngrades(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -595,6 +1263,26 @@ func Test_validNodeLabels(t *testing.T) {
 				exposedNodeLabels: []string{"topology.kubernetes.io/*", "failure-domain.beta.kubernetes.io/*"},
 			},
 		},
+		{
+			name: "Zone awareness topology label not exposed by the operator",
+			args: args{
+				proposed: esv1.Elasticsearch{
+					Spec: esv1.ElasticsearchSpec{ZoneAwareness: &esv1.ZoneAwareness{Enabled: true}},
+				},
+			},
+		},
+		{
+			name: "Zone awareness does not allow the other node labels",
+			args: args{
+				proposed: esv1.Elasticsearch{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: map[string]string{esv1.DownwardNodeLabelsAnnotation: "failure-domain.beta.kubernetes.io/zone"},
+					},
+					Spec: esv1.ElasticsearchSpec{ZoneAwareness: &esv1.ZoneAwareness{Enabled: true}},
+				},
+			},
+			expectErrors: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
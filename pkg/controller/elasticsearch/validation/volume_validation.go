@@ -6,6 +6,7 @@ package validation
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/autoscaling"
 	volumevalidations "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume/validations"
@@ -19,6 +20,8 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/shutdown"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
@@ -43,6 +46,51 @@ func validPVCNaming(proposed esv1.Elasticsearch) field.ErrorList {
 	return errs
 }
 
+// validEphemeralNodeSets ensures ephemeral NodeSets do not declare claims and that the Elasticsearch version supports the
+// node shutdown API, which is used to relocate the data of ephemeral nodes before they are restarted.
+func validEphemeralNodeSets(proposed esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	for i, ns := range proposed.Spec.NodeSets {
+		if !ns.Ephemeral {
+			continue
+		}
+		if len(ns.VolumeClaimTemplates) > 0 {
+			errs = append(errs, field.Invalid(
+				field.NewPath("spec").Child("nodeSet").Index(i).Child("volumeClaimTemplates"),
+				ns.VolumeClaimTemplates,
+				ephemeralWithClaimsErrMsg,
+			))
+		}
+		// invalid versions are reported by the version validation
+		if v, err := version.Parse(proposed.Spec.Version); err == nil && v.LT(shutdown.MinVersion) {
+			errs = append(errs, field.Invalid(
+				field.NewPath("spec").Child("nodeSet").Index(i).Child("ephemeral"),
+				ns.Ephemeral,
+				fmt.Sprintf(ephemeralUnsupportedVersionErrMsg, version.WithoutPre(shutdown.MinVersion)),
+			))
+		}
+	}
+	return errs
+}
+
+// noEphemeralModification ensures existing NodeSets are not switched from or to ephemeral data, which would require to
+// recreate the StatefulSet with different claims.
+func noEphemeralModification(current, proposed esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	for i, proposedNodeSet := range proposed.Spec.NodeSets {
+		currentNodeSet := getNodeSet(proposedNodeSet.Name, current)
+		if currentNodeSet == nil || currentNodeSet.Ephemeral == proposedNodeSet.Ephemeral {
+			continue
+		}
+		errs = append(errs, field.Invalid(
+			field.NewPath("spec").Child("nodeSet").Index(i).Child("ephemeral"),
+			proposedNodeSet.Ephemeral,
+			ephemeralImmutableErrMsg,
+		))
+	}
+	return errs
+}
+
 func unmountedClaims(ns esv1.NodeSet) []corev1.PersistentVolumeClaim {
 	templates := ns.VolumeClaimTemplates
 	for _, c := range ns.PodTemplate.Spec.Containers {
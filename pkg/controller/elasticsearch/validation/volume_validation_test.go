@@ -298,3 +298,100 @@ func Test_validPVCNaming(t *testing.T) {
 		})
 	}
 }
+
+func Test_validEphemeralNodeSets(t *testing.T) {
+	esFixture := func(version string, nodeSet esv1.NodeSet) esv1.Elasticsearch {
+		return esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: version, NodeSets: []esv1.NodeSet{nodeSet}}}
+	}
+	tests := []struct {
+		name    string
+		es      esv1.Elasticsearch
+		wantErr string
+	}{
+		{
+			name: "regular NodeSet with claims is OK",
+			es: esFixture("8.16.0", esv1.NodeSet{
+				Name:                 "default",
+				VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-data"}}},
+			}),
+		},
+		{
+			name: "ephemeral NodeSet without claims is OK",
+			es:   esFixture("8.16.0", esv1.NodeSet{Name: "default", Ephemeral: true}),
+		},
+		{
+			name: "ephemeral NodeSet with claims is NOK",
+			es: esFixture("8.16.0", esv1.NodeSet{
+				Name:                 "default",
+				Ephemeral:            true,
+				VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-data"}}},
+			}),
+			wantErr: ephemeralWithClaimsErrMsg,
+		},
+		{
+			name:    "ephemeral NodeSet without node shutdown support is NOK",
+			es:      esFixture("7.10.0", esv1.NodeSet{Name: "default", Ephemeral: true}),
+			wantErr: "Ephemeral NodeSets require the Elasticsearch node shutdown API, available from version 7.15.2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validEphemeralNodeSets(tt.es)
+			if tt.wantErr == "" {
+				require.Empty(t, got)
+				return
+			}
+			require.Len(t, got, 1)
+			require.Equal(t, tt.wantErr, got[0].Detail)
+		})
+	}
+}
+
+func Test_noEphemeralModification(t *testing.T) {
+	es := func(ephemeral bool, nodeSetNames ...string) esv1.Elasticsearch {
+		es := esv1.Elasticsearch{}
+		for _, name := range nodeSetNames {
+			es.Spec.NodeSets = append(es.Spec.NodeSets, esv1.NodeSet{Name: name, Ephemeral: ephemeral})
+		}
+		return es
+	}
+	tests := []struct {
+		name     string
+		current  esv1.Elasticsearch
+		proposed esv1.Elasticsearch
+		wantErr  bool
+	}{
+		{
+			name:     "no change is OK",
+			current:  es(true, "default"),
+			proposed: es(true, "default"),
+		},
+		{
+			name:     "new ephemeral NodeSet is OK",
+			current:  es(false, "default"),
+			proposed: es(true, "other"),
+		},
+		{
+			name:     "switching an existing NodeSet to ephemeral is NOK",
+			current:  es(false, "default"),
+			proposed: es(true, "default"),
+			wantErr:  true,
+		},
+		{
+			name:     "switching an existing NodeSet to persistent is NOK",
+			current:  es(true, "default"),
+			proposed: es(false, "default"),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := noEphemeralModification(tt.current, tt.proposed)
+			if tt.wantErr {
+				require.NotEmpty(t, got)
+			} else {
+				require.Empty(t, got)
+			}
+		})
+	}
+}
@@ -7,12 +7,16 @@ package validation
 import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	common "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 )
 
 var warnings = []validation{
 	noUnsupportedSettings,
+	noDeprecatedNodeRoleSettings,
+	noUnsafeTopology,
 }
 
 func noUnsupportedSettings(es esv1.Elasticsearch) field.ErrorList {
@@ -56,6 +60,73 @@ func validateClientAuthentication(config *common.CanonicalConfig, index int) fie
 	return errs
 }
 
+// noDeprecatedNodeRoleSettings reports the legacy node role settings, deprecated in favor of node.roles.
+func noDeprecatedNodeRoleSettings(es esv1.Elasticsearch) field.ErrorList {
+	v, err := version.Parse(es.Spec.Version)
+	if err != nil || !v.GTE(version.From(7, 9, 0)) {
+		return nil
+	}
+	var errs field.ErrorList
+	for i, nodeSet := range es.Spec.NodeSets {
+		cfg := esv1.ElasticsearchSettings{}
+		if err := esv1.UnpackConfig(nodeSet.Config, v, &cfg); err != nil {
+			// invalid configurations are reported by the validation checks
+			continue
+		}
+		for _, setting := range getNodeRoleAttrs(cfg) {
+			errs = append(errs, field.Forbidden(field.NewPath("spec").Child("nodeSets").Index(i).Child("config").Child(setting), deprecatedNodeRoleSettingMsg))
+		}
+	}
+	return errs
+}
+
+// noUnsafeTopology reports the topologies where the cluster cannot tolerate the loss of a master-eligible node, and the
+// coordinating-only nodes without any data node to route the requests to.
+func noUnsafeTopology(es esv1.Elasticsearch) field.ErrorList {
+	v, err := version.Parse(es.Spec.Version)
+	if err != nil {
+		return nil
+	}
+	var electableMasters, votingOnlyMasters, dataNodes, coordinatingOnlyNodes int32
+	for _, nodeSet := range es.Spec.NodeSets {
+		cfg := esv1.ElasticsearchSettings{}
+		if err := esv1.UnpackConfig(nodeSet.Config, v, &cfg); err != nil {
+			// invalid configurations are reported by the validation checks
+			continue
+		}
+		switch {
+		case cfg.Node.IsConfiguredWithRole(esv1.MasterRole) && cfg.Node.IsConfiguredWithRole(esv1.VotingOnlyRole):
+			votingOnlyMasters += nodeSet.Count
+		case cfg.Node.IsConfiguredWithRole(esv1.MasterRole):
+			electableMasters += nodeSet.Count
+		}
+		if cfg.Node.CanContainData() {
+			dataNodes += nodeSet.Count
+		}
+		if cfg.Node.IsCoordinatingOnly() {
+			coordinatingOnlyNodes += nodeSet.Count
+		}
+	}
+
+	var errs field.ErrorList
+	path := field.NewPath("spec").Child("nodeSets")
+	switch {
+	case electableMasters == 1 && votingOnlyMasters > 0:
+		errs = append(errs, field.Invalid(path, electableMasters, singleElectableMasterMsg))
+	case electableMasters+votingOnlyMasters == 2:
+		errs = append(errs, field.Invalid(path, electableMasters+votingOnlyMasters, twoMasterEligibleNodesMsg))
+	}
+	if coordinatingOnlyNodes > 0 && dataNodes == 0 {
+		errs = append(errs, field.Invalid(path, coordinatingOnlyNodes, coordinatingOnlyWithoutDataMsg))
+	}
+	return errs
+}
+
+// Warnings returns the admission warnings raised by the Elasticsearch specification.
+func Warnings(es esv1.Elasticsearch) []string {
+	return commonv1.ToWarnings(esv1.Kind, &es, check(es, warnings))
+}
+
 func CheckForWarnings(es esv1.Elasticsearch) error {
 	warnings := check(es, warnings)
 	if len(warnings) > 0 {
@@ -7,6 +7,9 @@ package validation
 import (
 	"testing"
 
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 )
@@ -171,3 +174,100 @@ func Test_noUnsupportedSettings(t *testing.T) {
 		})
 	}
 }
+
+func Test_noDeprecatedNodeRoleSettings(t *testing.T) {
+	withConfig := func(version string, cfg map[string]interface{}) esv1.Elasticsearch {
+		return esv1.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+			Spec: esv1.ElasticsearchSpec{
+				Version:  version,
+				NodeSets: []esv1.NodeSet{{Config: &commonv1.Config{Data: cfg}, Count: 1}},
+			},
+		}
+	}
+	tests := []struct {
+		name string
+		es   esv1.Elasticsearch
+		want []string
+	}{
+		{
+			name: "no config",
+			es:   es("7.17.0"),
+		},
+		{
+			name: "node.roles",
+			es:   withConfig("7.17.0", map[string]interface{}{esv1.NodeRoles: []string{"master"}}),
+		},
+		{
+			name: "legacy settings before their deprecation",
+			es:   withConfig("7.8.0", map[string]interface{}{esv1.NodeMaster: true}),
+		},
+		{
+			name: "legacy settings after their deprecation",
+			es:   withConfig("7.17.0", map[string]interface{}{esv1.NodeMaster: true, esv1.NodeData: false}),
+			want: []string{
+				"Elasticsearch ns/es: spec.nodeSets[0].config.node.data: " + deprecatedNodeRoleSettingMsg,
+				"Elasticsearch ns/es: spec.nodeSets[0].config.node.master: " + deprecatedNodeRoleSettingMsg,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, commonv1.ToWarnings(esv1.Kind, &tt.es, noDeprecatedNodeRoleSettings(tt.es)))
+		})
+	}
+}
+
+func Test_noUnsafeTopology(t *testing.T) {
+	nodeSet := func(count int32, roles ...esv1.NodeRole) esv1.NodeSet {
+		return esv1.NodeSet{Count: count, Config: &commonv1.Config{Data: map[string]interface{}{esv1.NodeRoles: roles}}}
+	}
+	withNodeSets := func(nodeSets ...esv1.NodeSet) esv1.Elasticsearch {
+		return esv1.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+			Spec:       esv1.ElasticsearchSpec{Version: "8.15.0", NodeSets: nodeSets},
+		}
+	}
+	tests := []struct {
+		name string
+		es   esv1.Elasticsearch
+		want []string
+	}{
+		{
+			name: "default roles",
+			es:   withNodeSets(esv1.NodeSet{Count: 3}),
+		},
+		{
+			name: "single node",
+			es:   withNodeSets(esv1.NodeSet{Count: 1}),
+		},
+		{
+			name: "two master-eligible nodes and a voting-only node",
+			es:   withNodeSets(nodeSet(2, esv1.MasterRole, esv1.DataRole), nodeSet(1, esv1.MasterRole, esv1.VotingOnlyRole)),
+		},
+		{
+			name: "two master-eligible nodes",
+			es:   withNodeSets(nodeSet(2, esv1.MasterRole, esv1.DataRole)),
+			want: []string{"Elasticsearch ns/es: spec.nodeSets: " + twoMasterEligibleNodesMsg},
+		},
+		{
+			name: "single electable master with voting-only nodes",
+			es:   withNodeSets(nodeSet(1, esv1.MasterRole), nodeSet(2, esv1.MasterRole, esv1.VotingOnlyRole), nodeSet(3, esv1.DataRole)),
+			want: []string{"Elasticsearch ns/es: spec.nodeSets: " + singleElectableMasterMsg},
+		},
+		{
+			name: "coordinating-only nodes with data nodes",
+			es:   withNodeSets(nodeSet(3, esv1.MasterRole, esv1.DataRole), nodeSet(2)),
+		},
+		{
+			name: "coordinating-only nodes without data nodes",
+			es:   withNodeSets(nodeSet(3, esv1.MasterRole), nodeSet(2)),
+			want: []string{"Elasticsearch ns/es: spec.nodeSets: " + coordinatingOnlyWithoutDataMsg},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, commonv1.ToWarnings(esv1.Kind, &tt.es, noUnsafeTopology(tt.es)))
+		})
+	}
+}
@@ -6,6 +6,7 @@ package validation
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 
 	admissionv1 "k8s.io/api/admission/v1"
@@ -18,6 +19,7 @@ import (
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/license"
+	commonwebhook "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/webhook"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
@@ -27,19 +29,29 @@ import (
 
 const (
 	webhookPath = "/validate-elasticsearch-k8s-elastic-co-v1-elasticsearch"
+
+	// impactPreviewPrefix prefixes the warnings returned on dry-run updates to preview their impact.
+	impactPreviewPrefix = "Dry-run impact preview: the operator "
 )
 
+// ImpactPreview returns a summary of the changes the operator would apply to an Elasticsearch cluster to move from its
+// current specification to the proposed one, without any side effect.
+type ImpactPreview func(ctx context.Context, current, proposed esv1.Elasticsearch) ([]string, error)
+
 var eslog = ulog.Log.WithName("es-validation")
 
-// RegisterWebhook will register the Elasticsearch validating webhook.
-func RegisterWebhook(mgr ctrl.Manager, validateStorageClass bool, exposedNodeLabels NodeLabels, licenseChecker license.Checker, managedNamespaces []string) {
+// RegisterWebhook will register the Elasticsearch validating webhook. The impact of the updates submitted in dry-run mode
+// is previewed with the given ImpactPreview, if any.
+func RegisterWebhook(mgr ctrl.Manager, validateStorageClass bool, exposedNodeLabels NodeLabels, namespaceQuota NamespaceQuota, licenseChecker license.Checker, managedNamespaces []string, impactPreview ImpactPreview) {
 	wh := &validatingWebhook{
 		client:               mgr.GetClient(),
 		decoder:              admission.NewDecoder(mgr.GetScheme()),
 		validateStorageClass: validateStorageClass,
 		exposedNodeLabels:    exposedNodeLabels,
+		namespaceQuota:       namespaceQuota,
 		licenseChecker:       licenseChecker,
 		managedNamespaces:    set.Make(managedNamespaces...),
+		impactPreview:        impactPreview,
 	}
 	eslog.Info("Registering Elasticsearch validating webhook", "path", webhookPath)
 	mgr.GetWebhookServer().Register(webhookPath, &webhook.Admission{Handler: wh})
@@ -50,15 +62,33 @@ type validatingWebhook struct {
 	decoder              admission.Decoder
 	validateStorageClass bool
 	exposedNodeLabels    NodeLabels
+	namespaceQuota       NamespaceQuota
 	licenseChecker       license.Checker
 	managedNamespaces    set.StringSet
+	impactPreview        ImpactPreview
 }
 
 func (wh *validatingWebhook) validateCreate(ctx context.Context, es esv1.Elasticsearch) error {
 	eslog.V(1).Info("validate create", "name", es.Name)
+	if err := wh.validateNamespaceQuota(ctx, es, nil); err != nil {
+		return err
+	}
 	return ValidateElasticsearch(ctx, es, wh.licenseChecker, wh.exposedNodeLabels)
 }
 
+func (wh *validatingWebhook) validateNamespaceQuota(ctx context.Context, proposed esv1.Elasticsearch, current *esv1.Elasticsearch) error {
+	errs, err := CheckNamespaceQuota(ctx, wh.client, wh.namespaceQuota, proposed, current)
+	if err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return apierrors.NewInvalid(
+			schema.GroupKind{Group: "elasticsearch.k8s.elastic.co", Kind: esv1.Kind},
+			proposed.Name, errs)
+	}
+	return nil
+}
+
 func (wh *validatingWebhook) validateUpdate(ctx context.Context, prev esv1.Elasticsearch, curr esv1.Elasticsearch) error {
 	eslog.V(1).Info("validate update", "name", curr.Name)
 	var errs field.ErrorList
@@ -72,6 +102,9 @@ func (wh *validatingWebhook) validateUpdate(ctx context.Context, prev esv1.Elast
 			schema.GroupKind{Group: "elasticsearch.k8s.elastic.co", Kind: esv1.Kind},
 			curr.Name, errs)
 	}
+	if err := wh.validateNamespaceQuota(ctx, curr, &prev); err != nil {
+		return err
+	}
 	return ValidateElasticsearch(ctx, curr, wh.licenseChecker, wh.exposedNodeLabels)
 }
 
@@ -90,10 +123,12 @@ func (wh *validatingWebhook) Handle(ctx context.Context, req admission.Request)
 		return admission.Allowed("")
 	}
 
+	warnings := append(commonwebhook.GetWarnings(es), Warnings(*es)...)
+
 	if req.Operation == admissionv1.Create {
 		err = wh.validateCreate(ctx, *es)
 		if err != nil {
-			return admission.Denied(err.Error())
+			return admission.Denied(err.Error()).WithWarnings(warnings...)
 		}
 	}
 
@@ -101,16 +136,37 @@ func (wh *validatingWebhook) Handle(ctx context.Context, req admission.Request)
 		oldObj := &esv1.Elasticsearch{}
 		err = wh.decoder.DecodeRaw(req.OldObject, oldObj)
 		if err != nil {
-			return admission.Errored(http.StatusBadRequest, err)
+			return admission.Errored(http.StatusBadRequest, err).WithWarnings(warnings...)
 		}
 
 		err = wh.validateUpdate(ctx, *oldObj, *es)
 		if err != nil {
-			return admission.Denied(err.Error())
+			return admission.Denied(err.Error()).WithWarnings(warnings...)
+		}
+
+		if req.DryRun != nil && *req.DryRun {
+			warnings = append(warnings, wh.previewImpact(ctx, *oldObj, *es)...)
 		}
 	}
 
-	return admission.Allowed("")
+	return admission.Allowed("").WithWarnings(warnings...)
+}
+
+// previewImpact returns the impact of an update as warnings, so that it is displayed by kubectl for dry-run requests.
+func (wh *validatingWebhook) previewImpact(ctx context.Context, current, proposed esv1.Elasticsearch) []string {
+	if wh.impactPreview == nil {
+		return nil
+	}
+	impact, err := wh.impactPreview(ctx, current, proposed)
+	if err != nil {
+		eslog.V(1).Info("Failed to preview the impact of the update", "namespace", proposed.Namespace, "name", proposed.Name, "error", err.Error())
+		return []string{fmt.Sprintf("Dry-run impact preview unavailable: %s", err.Error())}
+	}
+	previews := make([]string, 0, len(impact))
+	for _, change := range impact {
+		previews = append(previews, impactPreviewPrefix+change)
+	}
+	return previews
 }
 
 // ValidateElasticsearch validates an Elasticsearch instance against a set of validation funcs.
@@ -7,12 +7,15 @@ package validation
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	admissionv1 "k8s.io/api/admission/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
@@ -33,6 +36,7 @@ func Test_validatingWebhook_Handle(t *testing.T) {
 	type fields struct {
 		client               k8s.Client
 		validateStorageClass bool
+		namespaceQuota       NamespaceQuota
 	}
 	type args struct {
 		req admission.Request
@@ -145,6 +149,56 @@ func Test_validatingWebhook_Handle(t *testing.T) {
 			},
 			want: admission.Denied(noDowngradesMsg),
 		},
+		{
+			name: "reject creation exceeding the namespace quota",
+			fields: fields{
+				client: k8s.NewFakeClient(&esv1.Elasticsearch{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "existing"},
+					Spec:       esv1.ElasticsearchSpec{Version: "7.9.0", NodeSets: []esv1.NodeSet{{Name: "set1", Count: 1}}},
+				}),
+				namespaceQuota: NamespaceQuota{MaxClusters: 1},
+			},
+			args: args{
+				req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					Object: runtime.RawExtension{
+						Raw: asJSON(&esv1.Elasticsearch{
+							ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "name"},
+							Spec:       esv1.ElasticsearchSpec{Version: "7.9.0", NodeSets: []esv1.NodeSet{{Name: "set1", Count: 3}}},
+						}),
+					}},
+				},
+			},
+			want: admission.Denied("Namespace quota exceeded: at most 1 Elasticsearch clusters are allowed per namespace"),
+		},
+		{
+			name: "accept update of a cluster in a namespace at quota",
+			fields: fields{
+				client: k8s.NewFakeClient(&esv1.Elasticsearch{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "name"},
+					Spec:       esv1.ElasticsearchSpec{Version: "7.9.0", NodeSets: []esv1.NodeSet{{Name: "set1", Count: 3}}},
+				}),
+				namespaceQuota: NamespaceQuota{MaxClusters: 1},
+			},
+			args: args{
+				req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Update,
+					OldObject: runtime.RawExtension{
+						Raw: asJSON(&esv1.Elasticsearch{
+							ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "name"},
+							Spec:       esv1.ElasticsearchSpec{Version: "7.9.0", NodeSets: []esv1.NodeSet{{Name: "set1", Count: 3}}},
+						}),
+					},
+					Object: runtime.RawExtension{
+						Raw: asJSON(&esv1.Elasticsearch{
+							ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "name"},
+							Spec:       esv1.ElasticsearchSpec{Version: "7.9.1", NodeSets: []esv1.NodeSet{{Name: "set1", Count: 3}}},
+						}),
+					},
+				}},
+			},
+			want: admission.Allowed(""),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -152,6 +206,7 @@ func Test_validatingWebhook_Handle(t *testing.T) {
 				client:               tt.fields.client,
 				decoder:              decoder,
 				validateStorageClass: tt.fields.validateStorageClass,
+				namespaceQuota:       tt.fields.namespaceQuota,
 				managedNamespaces:    set.Make("ns"),
 			}
 			got := wh.Handle(context.Background(), tt.args.req)
@@ -162,3 +217,45 @@ func Test_validatingWebhook_Handle(t *testing.T) {
 		})
 	}
 }
+
+func Test_validatingWebhook_Handle_DryRunImpactPreview(t *testing.T) {
+	es := func(count int32) runtime.RawExtension {
+		return runtime.RawExtension{Raw: asJSON(&esv1.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "name"},
+			Spec:       esv1.ElasticsearchSpec{Version: "7.9.0", NodeSets: []esv1.NodeSet{{Name: "set1", Count: count}}},
+		})}
+	}
+	request := func(dryRun bool) admission.Request {
+		return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Update,
+			OldObject: es(3),
+			Object:    es(4),
+			DryRun:    ptr.To(dryRun),
+		}}
+	}
+	wh := &validatingWebhook{
+		client:            k8s.NewFakeClient(),
+		decoder:           admission.NewDecoder(k8s.Scheme()),
+		managedNamespaces: set.Make("ns"),
+		impactPreview: func(_ context.Context, current, proposed esv1.Elasticsearch) ([]string, error) {
+			return []string{fmt.Sprintf("scales up from %d to %d Pod(s)", current.Spec.NodeSets[0].Count, proposed.Spec.NodeSets[0].Count)}, nil
+		},
+	}
+
+	got := wh.Handle(context.Background(), request(true))
+	require.True(t, got.Allowed)
+	require.Equal(t, []string{"Dry-run impact preview: the operator scales up from 3 to 4 Pod(s)"}, got.Warnings)
+
+	// the impact is only previewed for dry-run requests
+	got = wh.Handle(context.Background(), request(false))
+	require.True(t, got.Allowed)
+	require.Empty(t, got.Warnings)
+
+	// a failure to preview the impact does not reject the request
+	wh.impactPreview = func(context.Context, esv1.Elasticsearch, esv1.Elasticsearch) ([]string, error) {
+		return nil, errors.New("boom")
+	}
+	got = wh.Handle(context.Background(), request(true))
+	require.True(t, got.Allowed)
+	require.Equal(t, []string{"Dry-run impact preview unavailable: boom"}, got.Warnings)
+}
@@ -0,0 +1,56 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package validation
+
+import (
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	common "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/zonespread"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
+)
+
+// validZoneSpread checks the NodeSets spread across zones: zones must be unique, the zone node attribute is managed by
+// the operator, and the spread cannot be combined with ingest autoscaling which manages the count of a single NodeSet.
+// The names of the NodeSets of each zone are checked along with the other resource names.
+func validZoneSpread(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	for i, ns := range es.Spec.NodeSets {
+		if ns.ZoneSpread == nil {
+			continue
+		}
+		path := field.NewPath("spec").Child("nodeSets").Index(i)
+		if ns.IngestAutoscaling != nil {
+			errs = append(errs, field.Forbidden(path.Child("zoneSpread"), zoneSpreadWithIngestAutoscalingErrMsg))
+		}
+
+		zones := set.Make()
+		for j, zone := range ns.ZoneSpread.Zones {
+			zonePath := path.Child("zoneSpread").Child("zones").Index(j)
+			if zones.Has(zone.Name) {
+				errs = append(errs, field.Invalid(zonePath.Child("name"), zone.Name, duplicateZonesErrMsg))
+			}
+			zones.Add(zone.Name)
+			if ns.Ephemeral && zone.StorageClassName != nil {
+				errs = append(errs, field.Forbidden(zonePath.Child("storageClassName"), zoneStorageClassWithEphemeralErrMsg))
+			}
+		}
+
+		if ns.Config == nil {
+			continue
+		}
+		cfg, err := common.NewCanonicalConfigFrom(ns.Config.Data)
+		if err != nil {
+			// reported by the other config validations
+			continue
+		}
+		attribute := zonespread.NodeAttributeSetting(*ns.ZoneSpread)
+		if len(cfg.HasKeys([]string{attribute})) > 0 {
+			errs = append(errs, field.Forbidden(path.Child("config").Child(attribute), zoneAttributeInConfigErrMsg))
+		}
+	}
+	return errs
+}
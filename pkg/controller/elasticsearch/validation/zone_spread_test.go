@@ -0,0 +1,118 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+)
+
+func Test_validZoneSpread(t *testing.T) {
+	zones := func(names ...string) *esv1.ZoneSpread {
+		spread := &esv1.ZoneSpread{}
+		for _, name := range names {
+			spread.Zones = append(spread.Zones, esv1.NodeSetZone{Name: name})
+		}
+		return spread
+	}
+	tests := []struct {
+		name         string
+		nodeSet      esv1.NodeSet
+		expectErrors int
+	}{
+		{
+			name:         "no zone spread: OK",
+			nodeSet:      esv1.NodeSet{Name: "default"},
+			expectErrors: 0,
+		},
+		{
+			name:         "distinct zones: OK",
+			nodeSet:      esv1.NodeSet{Name: "default", ZoneSpread: zones("a", "b", "c")},
+			expectErrors: 0,
+		},
+		{
+			name:         "duplicate zones: NOT OK",
+			nodeSet:      esv1.NodeSet{Name: "default", ZoneSpread: zones("a", "b", "a")},
+			expectErrors: 1,
+		},
+		{
+			name: "with ingest autoscaling: NOT OK",
+			nodeSet: esv1.NodeSet{
+				Name:              "ingest",
+				ZoneSpread:        zones("a", "b"),
+				IngestAutoscaling: &esv1.IngestAutoscaling{MinCount: 1, MaxCount: 3},
+			},
+			expectErrors: 1,
+		},
+		{
+			name: "storage class of an ephemeral NodeSet: NOT OK",
+			nodeSet: esv1.NodeSet{
+				Name:      "default",
+				Ephemeral: true,
+				ZoneSpread: &esv1.ZoneSpread{Zones: []esv1.NodeSetZone{
+					{Name: "a", StorageClassName: ptr.To("ssd-a")},
+					{Name: "b"},
+				}},
+			},
+			expectErrors: 1,
+		},
+		{
+			name: "zone attribute in the config: NOT OK",
+			nodeSet: esv1.NodeSet{
+				Name:       "default",
+				ZoneSpread: zones("a", "b"),
+				Config:     &commonv1.Config{Data: map[string]interface{}{"node": map[string]interface{}{"attr.zone": "a"}}},
+			},
+			expectErrors: 1,
+		},
+		{
+			name: "other node attribute in the config: OK",
+			nodeSet: esv1.NodeSet{
+				Name:       "default",
+				ZoneSpread: zones("a", "b"),
+				Config:     &commonv1.Config{Data: map[string]interface{}{"node.attr.rack": "r1"}},
+			},
+			expectErrors: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{NodeSets: []esv1.NodeSet{tt.nodeSet}}}
+			assert.Len(t, validZoneSpread(es), tt.expectErrors)
+		})
+	}
+}
+
+func Test_validName_ZoneSpread(t *testing.T) {
+	nodeSets := func(nodeSets ...esv1.NodeSet) esv1.Elasticsearch {
+		return esv1.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "es"},
+			Spec:       esv1.ElasticsearchSpec{NodeSets: nodeSets},
+		}
+	}
+	// NodeSet names derived from the zone names
+	assert.Empty(t, validName(nodeSets(esv1.NodeSet{
+		Name:       "data",
+		Count:      3,
+		ZoneSpread: &esv1.ZoneSpread{Zones: []esv1.NodeSetZone{{Name: "us-east-1a"}, {Name: "us-east-1b"}}},
+	})))
+	// NodeSet of a zone conflicting with another NodeSet
+	assert.Len(t, validName(nodeSets(
+		esv1.NodeSet{Name: "data-a", Count: 1},
+		esv1.NodeSet{Name: "data", Count: 3, ZoneSpread: &esv1.ZoneSpread{Zones: []esv1.NodeSetZone{{Name: "zone-a", Suffix: "a"}}}},
+	)), 1)
+	// NodeSet name exceeding the maximum length once suffixed with the zone
+	assert.Len(t, validName(nodeSets(esv1.NodeSet{
+		Name:       "data",
+		Count:      3,
+		ZoneSpread: &esv1.ZoneSpread{Zones: []esv1.NodeSetZone{{Name: "a-particularly-long-zone-name-that-does-not-fit-in-a-label-1a"}}},
+	})), 1)
+}
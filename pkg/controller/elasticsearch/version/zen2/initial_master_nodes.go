@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	pkgerrors "github.com/pkg/errors"
+	"k8s.io/utils/ptr"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	sset "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/statefulset"
@@ -107,8 +108,7 @@ func RemoveZen2BootstrapAnnotation(ctx context.Context, k8sClient k8s.Client, es
 		"es_name", es.Name,
 	)
 	// remove the annotation to indicate we're done with zen2 bootstrapping
-	delete(es.Annotations, initialMasterNodesAnnotation)
-	return false, k8sClient.Update(ctx, &es)
+	return false, k8s.PatchAnnotations(ctx, k8sClient, &es, map[string]*string{initialMasterNodesAnnotation: nil})
 }
 
 // patchInitialMasterNodesConfig mutates the configuration of zen2-compatible master nodes
@@ -190,11 +190,9 @@ func getInitialMasterNodesAnnotation(es esv1.Elasticsearch) []string {
 }
 
 // setInitialMasterNodesAnnotation sets initialMasterNodesAnnotation on the given es resource to initialMasterNodes,
-// and updates the es resource in the apiserver.
+// and patches the es resource in the apiserver.
 func setInitialMasterNodesAnnotation(ctx context.Context, k8sClient k8s.Client, es esv1.Elasticsearch, initialMasterNodes []string) error {
-	if es.Annotations == nil {
-		es.Annotations = map[string]string{}
-	}
-	es.Annotations[initialMasterNodesAnnotation] = strings.Join(initialMasterNodes, ",")
-	return k8sClient.Update(ctx, &es)
+	return k8s.PatchAnnotations(ctx, k8sClient, &es, map[string]*string{
+		initialMasterNodesAnnotation: ptr.To(strings.Join(initialMasterNodes, ",")),
+	})
 }
@@ -35,6 +35,14 @@ var (
 		MountPath: ElasticsearchDataMountPath,
 	}
 
+	// DefaultEphemeralDataVolume is the default EmptyDir data volume for Elasticsearch pods of ephemeral NodeSets.
+	DefaultEphemeralDataVolume = corev1.Volume{
+		Name: ElasticsearchDataVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	}
+
 	// DefaultVolumeClaimTemplates is the default volume claim templates for Elasticsearch pods
 	DefaultVolumeClaimTemplates = []corev1.PersistentVolumeClaim{DefaultDataVolumeClaim}
 
@@ -23,6 +23,9 @@ const (
 	XPackFileRealmVolumeName      = "elastic-internal-xpack-file-realm"
 	XPackFileRealmVolumeMountPath = "/mnt/elastic-internal/xpack-file-realm"
 
+	RealmsVolumeName      = "elastic-internal-realms"
+	RealmsVolumeMountPath = "/usr/share/elasticsearch/config/realms"
+
 	UnicastHostsVolumeName      = "elastic-internal-unicast-hosts"
 	UnicastHostsVolumeMountPath = "/mnt/elastic-internal/unicast-hosts"
 	UnicastHostsFile            = "unicast_hosts.txt"
@@ -0,0 +1,20 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package volume
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// HasChunkedTransportCertificates returns true if the given Pod spec mounts the transport certificates of all the
+// Secret chunks of its StatefulSet, false if it only mounts the first one.
+func HasChunkedTransportCertificates(podSpec corev1.PodSpec) bool {
+	for _, v := range podSpec.Volumes {
+		if v.Name == TransportCertificatesSecretVolumeName {
+			return v.Projected != nil
+		}
+	}
+	return false
+}
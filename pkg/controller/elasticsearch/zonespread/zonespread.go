@@ -0,0 +1,167 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package zonespread
+
+import (
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	common "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/settings"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
+)
+
+// Enabled returns true if at least one NodeSet of the cluster is spread across zones.
+func Enabled(es esv1.Elasticsearch) bool {
+	return slices.ContainsFunc(es.Spec.NodeSets, func(nodeSet esv1.NodeSet) bool {
+		return nodeSet.ZoneSpread != nil
+	})
+}
+
+// NodeSetName returns the name of the NodeSet of the given zone of a NodeSet spread across zones.
+func NodeSetName(nodeSetName string, zone esv1.NodeSetZone) string {
+	return nodeSetName + "-" + zone.SuffixOrDefault()
+}
+
+// NodeAttributeSetting returns the name of the setting holding the zone of the nodes of a NodeSet spread across zones.
+func NodeAttributeSetting(spread esv1.ZoneSpread) string {
+	return esv1.NodeAttr + "." + spread.AwarenessAttributeOrDefault()
+}
+
+// ZoneCount returns the number of nodes of the zone with the given index, out of the given total number of nodes
+// evenly distributed across the given number of zones. The first zones get the remaining nodes.
+func ZoneCount(count int32, zones int, index int) int32 {
+	zoneCount := count / int32(zones)
+	if int32(index) < count%int32(zones) {
+		zoneCount++
+	}
+	return zoneCount
+}
+
+// Expand returns a copy of the given cluster in which each NodeSet spread across zones is replaced by one NodeSet per
+// zone. The awareness attributes of the NodeSets spread across zones are set on all the NodeSets of the cluster, unless
+// already specified in their config, so that the elected master allocates the copies of each shard to different zones.
+func Expand(es esv1.Elasticsearch) esv1.Elasticsearch {
+	if !Enabled(es) {
+		return es
+	}
+	attributes := awarenessAttributes(es)
+	nodeSets := make([]esv1.NodeSet, 0, len(es.Spec.NodeSets))
+	for _, nodeSet := range es.Spec.NodeSets {
+		if nodeSet.ZoneSpread == nil {
+			nodeSet = *nodeSet.DeepCopy()
+			withSettings(&nodeSet, map[string]interface{}{esv1.ShardAwarenessAttributes: attributes})
+			nodeSets = append(nodeSets, nodeSet)
+			continue
+		}
+		for i := range nodeSet.ZoneSpread.Zones {
+			nodeSets = append(nodeSets, zoneNodeSet(es, nodeSet, i, attributes))
+		}
+	}
+	es.Spec.NodeSets = nodeSets
+	return es
+}
+
+// awarenessAttributes returns the comma-separated node attributes of the NodeSets spread across zones, preceded by the
+// default attribute holding the Kubernetes node of the Pods.
+func awarenessAttributes(es esv1.Elasticsearch) string {
+	attributes := []string{settings.NodeAttrK8sNodeName}
+	for _, nodeSet := range es.Spec.NodeSets {
+		if nodeSet.ZoneSpread == nil {
+			continue
+		}
+		if attribute := nodeSet.ZoneSpread.AwarenessAttributeOrDefault(); !slices.Contains(attributes, attribute) {
+			attributes = append(attributes, attribute)
+		}
+	}
+	return strings.Join(attributes, ",")
+}
+
+// zoneNodeSet returns the NodeSet of the zone with the given index of a NodeSet spread across zones.
+func zoneNodeSet(es esv1.Elasticsearch, spreadNodeSet esv1.NodeSet, index int, attributes string) esv1.NodeSet {
+	spread := spreadNodeSet.ZoneSpread
+	zone := spread.Zones[index]
+
+	nodeSet := *spreadNodeSet.DeepCopy()
+	nodeSet.ZoneSpread = nil
+	nodeSet.Name = NodeSetName(spreadNodeSet.Name, zone)
+	nodeSet.Count = ZoneCount(spreadNodeSet.Count, len(spread.Zones), index)
+
+	withSettings(&nodeSet, map[string]interface{}{
+		NodeAttributeSetting(*spread): zone.Name,
+		esv1.ShardAwarenessAttributes: attributes,
+	})
+
+	podTemplate := &nodeSet.PodTemplate
+	if podTemplate.Labels == nil {
+		podTemplate.Labels = map[string]string{}
+	}
+	podTemplate.Labels[label.ZoneSpreadNodeSetLabelName] = spreadNodeSet.Name
+	podTemplate.Labels[label.ZoneSpreadZoneLabelName] = zone.Name
+	if podTemplate.Spec.NodeSelector == nil {
+		podTemplate.Spec.NodeSelector = map[string]string{}
+	}
+	podTemplate.Spec.NodeSelector[spread.TopologyKeyOrDefault()] = zone.Name
+	if podTemplate.Spec.TopologySpreadConstraints == nil {
+		podTemplate.Spec.TopologySpreadConstraints = hostSpreadConstraints(es, nodeSet.Name)
+	}
+
+	if zone.StorageClassName != nil {
+		if len(nodeSet.VolumeClaimTemplates) == 0 && !nodeSet.Ephemeral {
+			nodeSet.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{*volume.DefaultDataVolumeClaim.DeepCopy()}
+		}
+		for i := range nodeSet.VolumeClaimTemplates {
+			nodeSet.VolumeClaimTemplates[i].Spec.StorageClassName = zone.StorageClassName
+		}
+	}
+	return nodeSet
+}
+
+// hostSpreadConstraints spreads the Pods of the NodeSet of a zone across the hosts of the zone, following the
+// enforcement of the topology spread policy of the cluster if any.
+func hostSpreadConstraints(es esv1.Elasticsearch, nodeSetName string) []corev1.TopologySpreadConstraint {
+	whenUnsatisfiable := corev1.ScheduleAnyway
+	if es.Spec.TopologySpread != nil && es.Spec.TopologySpread.IsHard() {
+		whenUnsatisfiable = corev1.DoNotSchedule
+	}
+	return []corev1.TopologySpreadConstraint{
+		{
+			MaxSkew:           1,
+			TopologyKey:       esv1.HostTopologyKey,
+			WhenUnsatisfiable: whenUnsatisfiable,
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					label.ClusterNameLabelName:     es.Name,
+					label.StatefulSetNameLabelName: esv1.StatefulSet(es.Name, nodeSetName),
+				},
+			},
+		},
+	}
+}
+
+// withSettings sets the given settings in the config of the given copy of a NodeSet, unless they are already specified.
+func withSettings(nodeSet *esv1.NodeSet, settings map[string]interface{}) {
+	var cfg *common.CanonicalConfig
+	if nodeSet.Config == nil {
+		nodeSet.Config = &commonv1.Config{}
+	} else if parsed, err := common.NewCanonicalConfigFrom(nodeSet.Config.Data); err == nil {
+		cfg = parsed
+	}
+	if nodeSet.Config.Data == nil {
+		nodeSet.Config.Data = map[string]interface{}{}
+	}
+	for name, value := range settings {
+		if cfg != nil && len(cfg.HasKeys([]string{name})) > 0 {
+			continue
+		}
+		nodeSet.Config.Data[name] = value
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package zonespread
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
+)
+
+func TestZoneCount(t *testing.T) {
+	tests := []struct {
+		name  string
+		count int32
+		zones int
+		want  []int32
+	}{
+		{name: "even distribution", count: 6, zones: 3, want: []int32{2, 2, 2}},
+		{name: "remaining nodes in the first zones", count: 5, zones: 3, want: []int32{2, 2, 1}},
+		{name: "fewer nodes than zones", count: 1, zones: 3, want: []int32{1, 0, 0}},
+		{name: "no nodes", count: 0, zones: 2, want: []int32{0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make([]int32, tt.zones)
+			for i := range got {
+				got[i] = ZoneCount(tt.count, tt.zones, i)
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExpand(t *testing.T) {
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec: esv1.ElasticsearchSpec{NodeSets: []esv1.NodeSet{
+			{
+				Name:   "master",
+				Count:  3,
+				Config: &commonv1.Config{Data: map[string]interface{}{"node.roles": []interface{}{"master"}}},
+			},
+			{
+				Name:   "data",
+				Count:  5,
+				Config: &commonv1.Config{Data: map[string]interface{}{"node.roles": []interface{}{"data"}}},
+				PodTemplate: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "search"}},
+				},
+				ZoneSpread: &esv1.ZoneSpread{Zones: []esv1.NodeSetZone{
+					{Name: "europe-west1-b", Suffix: "b", StorageClassName: ptr.To("ssd-b")},
+					{Name: "europe-west1-c", Suffix: "c"},
+				}},
+			},
+			{
+				Name: "ml",
+				// awareness attributes already configured by the user
+				Config: &commonv1.Config{Data: map[string]interface{}{"cluster": map[string]interface{}{
+					"routing.allocation.awareness.attributes": "rack",
+				}}},
+			},
+		}},
+	}
+	original := es.DeepCopy()
+
+	expanded := Expand(es)
+	// the original cluster is not mutated
+	require.Equal(t, original, &es)
+
+	nodeSets := expanded.Spec.NodeSets
+	require.Len(t, nodeSets, 4)
+	require.Equal(t, []string{"master", "data-b", "data-c", "ml"},
+		[]string{nodeSets[0].Name, nodeSets[1].Name, nodeSets[2].Name, nodeSets[3].Name})
+
+	// awareness attributes are set on all the NodeSets, unless already configured
+	require.Equal(t, "k8s_node_name,zone", nodeSets[0].Config.Data[esv1.ShardAwarenessAttributes])
+	require.NotContains(t, nodeSets[3].Config.Data, esv1.ShardAwarenessAttributes)
+
+	dataB, dataC := nodeSets[1], nodeSets[2]
+	require.Nil(t, dataB.ZoneSpread)
+	require.Equal(t, int32(3), dataB.Count)
+	require.Equal(t, int32(2), dataC.Count)
+	require.Equal(t, map[string]interface{}{
+		"node.roles":                  []interface{}{"data"},
+		"node.attr.zone":              "europe-west1-b",
+		esv1.ShardAwarenessAttributes: "k8s_node_name,zone",
+	}, dataB.Config.Data)
+	require.Equal(t, "europe-west1-c", dataC.Config.Data["node.attr.zone"])
+	require.Equal(t, map[string]string{
+		"app":                            "search",
+		label.ZoneSpreadNodeSetLabelName: "data",
+		label.ZoneSpreadZoneLabelName:    "europe-west1-b",
+	}, dataB.PodTemplate.Labels)
+	require.Equal(t, map[string]string{esv1.ZoneTopologyKey: "europe-west1-b"}, dataB.PodTemplate.Spec.NodeSelector)
+	require.Equal(t, []corev1.TopologySpreadConstraint{
+		{
+			MaxSkew:           1,
+			TopologyKey:       esv1.HostTopologyKey,
+			WhenUnsatisfiable: corev1.ScheduleAnyway,
+			LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{
+				label.ClusterNameLabelName:     "es",
+				label.StatefulSetNameLabelName: "es-es-data-b",
+			}},
+		},
+	}, dataB.PodTemplate.Spec.TopologySpreadConstraints)
+
+	// the default data volume claim is materialized to set the storage class of the zone
+	require.Len(t, dataB.VolumeClaimTemplates, 1)
+	require.Equal(t, volume.ElasticsearchDataVolumeName, dataB.VolumeClaimTemplates[0].Name)
+	require.Equal(t, ptr.To("ssd-b"), dataB.VolumeClaimTemplates[0].Spec.StorageClassName)
+	require.Empty(t, dataC.VolumeClaimTemplates)
+}
+
+func TestExpand_Disabled(t *testing.T) {
+	es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{NodeSets: []esv1.NodeSet{{Name: "default", Count: 3}}}}
+	require.Equal(t, es, Expand(es))
+}
+
+func TestExpand_CustomAttributeAndTopologyKey(t *testing.T) {
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Name: "es"},
+		Spec: esv1.ElasticsearchSpec{
+			TopologySpread: &esv1.TopologySpreadPolicy{Enforcement: esv1.HardTopologySpread},
+			NodeSets: []esv1.NodeSet{{
+				Name:  "default",
+				Count: 2,
+				PodTemplate: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+					NodeSelector: map[string]string{"disktype": "ssd"},
+				}},
+				ZoneSpread: &esv1.ZoneSpread{
+					Zones:              []esv1.NodeSetZone{{Name: "a"}, {Name: "b"}},
+					TopologyKey:        "example.com/zone",
+					AwarenessAttribute: "az",
+				},
+			}},
+		},
+	}
+	nodeSets := Expand(es).Spec.NodeSets
+	require.Len(t, nodeSets, 2)
+	require.Equal(t, "default-a", nodeSets[0].Name)
+	require.Equal(t, int32(1), nodeSets[0].Count)
+	require.Equal(t, "a", nodeSets[0].Config.Data["node.attr.az"])
+	require.Equal(t, "k8s_node_name,az", nodeSets[0].Config.Data[esv1.ShardAwarenessAttributes])
+	require.Equal(t, map[string]string{"disktype": "ssd", "example.com/zone": "a"}, nodeSets[0].PodTemplate.Spec.NodeSelector)
+	require.Equal(t, corev1.DoNotSchedule, nodeSets[0].PodTemplate.Spec.TopologySpreadConstraints[0].WhenUnsatisfiable)
+}
@@ -336,7 +336,7 @@ func associationConfig(ctx context.Context, c k8s.Client, ent entv1.EnterpriseSe
 }
 
 func tlsConfig(ent entv1.EnterpriseSearch) *settings.CanonicalConfig {
-	if !ent.Spec.HTTP.TLS.Enabled() {
+	if !ent.Spec.HTTP.TLSEnabled() {
 		return settings.NewCanonicalConfig()
 	}
 	certsDir := certificates.HTTPCertSecretVolume(entv1.Namer, ent.Name).VolumeMount().MountPath
@@ -12,6 +12,7 @@ import (
 
 	entv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/enterprisesearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/deployment"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/effectivespec"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
 )
@@ -29,6 +30,9 @@ func (r *ReconcileEnterpriseSearch) reconcileDeployment(
 		return appsv1.Deployment{}, err
 	}
 	deploy := deployment.New(deployParams)
+	if err := effectivespec.Reconcile(ctx, r.K8sClient(), &ent, entv1.Namer.Suffix(ent.Name, effectivespec.Suffix), ent.Spec, &deploy); err != nil {
+		return appsv1.Deployment{}, err
+	}
 	return deployment.Reconcile(ctx, r.K8sClient(), deploy, &ent)
 }
 
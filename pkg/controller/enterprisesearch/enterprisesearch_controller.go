@@ -31,6 +31,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/driver"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/gateway"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
@@ -38,6 +39,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
 )
 
 const (
@@ -157,10 +159,14 @@ func (r *ReconcileEnterpriseSearch) Reconcile(ctx context.Context, request recon
 
 	if common.IsUnmanaged(ctx, &ent) {
 		ulog.FromContext(ctx).Info("Object is currently not managed by this controller. Skipping reconciliation", "namespace", ent.Namespace, "ent_name", ent.Name)
-		return reconcile.Result{}, nil
+		return reconcile.Result{}, tracing.CaptureError(ctx, common.ReconcilePausedCondition(ctx, r.Client, &ent, &ent.Status.Conditions, true))
+	}
+	if err := common.ReconcilePausedCondition(ctx, r.Client, &ent, &ent.Status.Conditions, false); err != nil {
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
 	}
 
 	results, status := r.doReconcile(ctx, ent)
+	common.UpdateStandardConditions(&status.Conditions, status.ResourceState(ent.Generation, ent.Spec.Version), results)
 	if err := r.updateStatus(ctx, ent, status); err != nil {
 		if apierrors.IsConflict(err) {
 			return results.WithResult(reconcile.Result{Requeue: true}).Aggregate()
@@ -171,6 +177,7 @@ func (r *ReconcileEnterpriseSearch) Reconcile(ctx context.Context, request recon
 }
 
 func (r *ReconcileEnterpriseSearch) onDelete(ctx context.Context, obj types.NamespacedName) error {
+	metrics.DeleteResourceMetrics(ctx, entv1.Kind, obj)
 	// Clean up watches
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(common.ConfigRefWatchName(obj))
 	// Clean up watches set on custom http tls certificates
@@ -199,12 +206,15 @@ func (r *ReconcileEnterpriseSearch) doReconcile(ctx context.Context, ent entv1.E
 	if err != nil {
 		return results.WithError(err), status
 	}
+	if err := gateway.ReconcileRoute(ctx, r.Client, &ent, ent.Spec.HTTP, *svc, ent.GetIdentityLabels()); err != nil {
+		return results.WithError(err), status
+	}
 
 	_, results = certificates.Reconciler{
 		K8sClient:             r.K8sClient(),
 		DynamicWatches:        r.DynamicWatches(),
 		Owner:                 &ent,
-		TLSOptions:            ent.Spec.HTTP.TLS,
+		TLSOptions:            ent.Spec.HTTP.EffectiveTLS(),
 		Namer:                 entv1.Namer,
 		Labels:                ent.GetIdentityLabels(),
 		Services:              []corev1.Service{*svc},
@@ -345,7 +355,7 @@ func buildConfigHash(c k8s.Client, ent entv1.EnterpriseSearch, configSecret core
 	_, _ = configHash.Write(configSecret.Data[ReadinessProbeFilename])
 
 	// - in the Enterprise Search TLS certificates
-	if ent.Spec.HTTP.TLS.Enabled() {
+	if ent.Spec.HTTP.TLSEnabled() {
 		var tlsCertSecret corev1.Secret
 		tlsSecretKey := types.NamespacedName{Namespace: ent.Namespace, Name: certificates.InternalCertsSecretName(entv1.Namer, ent.Name)}
 		if err := c.Get(context.Background(), tlsSecretKey, &tlsCertSecret); err != nil {
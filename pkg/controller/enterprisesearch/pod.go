@@ -69,6 +69,9 @@ func newPodSpec(ent entv1.EnterpriseSearch, configHash string) (corev1.PodTempla
 	if err != nil {
 		return corev1.PodTemplateSpec{}, err // error unlikely and should have been caught during validation
 	}
+	if err := container.CheckImageCatalog(ent.Spec.Image, container.EnterpriseSearchImage, v); err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
 
 	builder := defaults.NewPodTemplateBuilder(ent.Spec.PodTemplate, entv1.EnterpriseSearchContainerName).
 		WithAnnotations(annotations).
@@ -87,7 +90,7 @@ func newPodSpec(ent entv1.EnterpriseSearch, configHash string) (corev1.PodTempla
 	}
 	builder = withHTTPCertsVolume(builder, ent)
 
-	return builder.PodTemplate, nil
+	return builder.WithServiceMesh(ent.Spec.HTTP.ServiceMesh).WithPodMutationPolicy(entv1.Kind, ent.Namespace).PodTemplate, nil
 }
 
 func withESCertsVolume(builder *defaults.PodTemplateBuilder, ent entv1.EnterpriseSearch) (*defaults.PodTemplateBuilder, error) {
@@ -109,7 +112,7 @@ func withESCertsVolume(builder *defaults.PodTemplateBuilder, ent entv1.Enterpris
 }
 
 func withHTTPCertsVolume(builder *defaults.PodTemplateBuilder, ent entv1.EnterpriseSearch) *defaults.PodTemplateBuilder {
-	if !ent.Spec.HTTP.TLS.Enabled() {
+	if !ent.Spec.HTTP.TLSEnabled() {
 		return builder
 	}
 	vol := certificates.HTTPCertSecretVolume(entv1.Namer, ent.Name)
@@ -27,6 +27,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
 	commonhttp "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/http"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/safemode"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
@@ -169,7 +170,7 @@ func (r *VersionUpgrade) setReadOnlyMode(ctx context.Context, enabled bool) erro
 	if httpClient == nil {
 		// build an HTTP client to reach the Enterprise Search service
 		var tlsCerts []*x509.Certificate
-		if r.ent.Spec.HTTP.TLS.Enabled() {
+		if r.ent.Spec.HTTP.TLSEnabled() {
 			var err error
 			tlsCerts, err = r.retrieveTLSCerts()
 			if err != nil {
@@ -224,6 +225,9 @@ func (r *VersionUpgrade) readOnlyModeRequest(ctx context.Context, enabled bool)
 	}
 
 	url := stringsutil.Concat(r.serviceURL(), ReadOnlyModeAPIPath)
+	if err := safemode.CheckRequest(ctx, http.MethodPut, url); err != nil {
+		return nil, err
+	}
 
 	body := bytes.NewBuffer([]byte(fmt.Sprintf("{\"enabled\": %t}", enabled)))
 
@@ -0,0 +1,406 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package esclone
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"time"
+
+	"go.elastic.co/apm/v2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	esclonev1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/esclone/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
+	commonesclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/esclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
+)
+
+const (
+	controllerName = "esclone-controller"
+)
+
+var (
+	defaultRequeue = reconcile.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+)
+
+// Add creates a new ElasticsearchClone Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, params operator.Parameters) error {
+	r := newReconciler(mgr, params)
+	c, err := common.NewController(mgr, controllerName, r, params)
+	if err != nil {
+		return err
+	}
+	return addWatches(mgr, c, r)
+}
+
+// newReconciler returns a new reconcile.Reconciler of ElasticsearchClone.
+func newReconciler(mgr manager.Manager, params operator.Parameters) *ReconcileElasticsearchClone {
+	return &ReconcileElasticsearchClone{
+		Client:           mgr.GetClient(),
+		esClientProvider: commonesclient.NewClient,
+		recorder:         mgr.GetEventRecorderFor(controllerName),
+		params:           params,
+		now:              time.Now,
+	}
+}
+
+func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileElasticsearchClone) error {
+	// watch for changes to ElasticsearchClone
+	if err := c.Watch(source.Kind(mgr.GetCache(), &esclonev1alpha1.ElasticsearchClone{}, &handler.TypedEnqueueRequestForObject[*esclonev1alpha1.ElasticsearchClone]{})); err != nil {
+		return err
+	}
+
+	// watch for changes to Elasticsearch, to reconcile the ElasticsearchClones owning it or cloning it
+	return c.Watch(source.Kind[client.Object](mgr.GetCache(), &esv1.Elasticsearch{}, reconcileRequestsForElasticsearch(r.Client)))
+}
+
+// reconcileRequestsForElasticsearch returns the requests to reconcile the ElasticsearchClone owning an Elasticsearch
+// cluster, and the ElasticsearchClones waiting for it to be available to be provisioned.
+func reconcileRequestsForElasticsearch(clnt k8s.Client) handler.TypedEventHandler[client.Object, reconcile.Request] {
+	return handler.TypedEnqueueRequestsFromMapFunc[client.Object](func(ctx context.Context, es client.Object) []reconcile.Request {
+		var requests []reconcile.Request
+		if owner := metav1.GetControllerOf(es); owner != nil && owner.Kind == esclonev1alpha1.Kind {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: es.GetNamespace(), Name: owner.Name}})
+		}
+		var clones esclonev1alpha1.ElasticsearchCloneList
+		if err := clnt.List(ctx, &clones, client.InNamespace(es.GetNamespace())); err != nil {
+			ulog.Log.Error(err, "Fail to list ElasticsearchCloneList while watching Elasticsearch")
+			return requests
+		}
+		for _, clone := range clones.Items {
+			if clone.Spec.SourceRef.Name != es.GetName() || clone.Status.RestoreStartTime != nil {
+				continue
+			}
+			requests = append(requests, reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(&clone)})
+		}
+		return requests
+	})
+}
+
+var _ reconcile.Reconciler = &ReconcileElasticsearchClone{}
+
+// ReconcileElasticsearchClone reconciles an ElasticsearchClone object
+type ReconcileElasticsearchClone struct {
+	k8s.Client
+	esClientProvider commonesclient.Provider
+	recorder         record.EventRecorder
+	params           operator.Parameters
+	now              func() time.Time
+	// iteration is the number of times this controller has run its Reconcile method
+	iteration uint64
+}
+
+// Reconcile reads that state of the cluster for an ElasticsearchClone object and makes sure its Elasticsearch cluster
+// exists and holds the restored snapshot, until the clone expires and is deleted along with its Elasticsearch cluster.
+func (r *ReconcileElasticsearchClone) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	ctx = common.NewReconciliationContext(ctx, &r.iteration, r.params.Tracer, controllerName, "esclone_name", request)
+	defer common.LogReconciliationRun(ulog.FromContext(ctx))()
+	defer tracing.EndContextTransaction(ctx)
+
+	var clone esclonev1alpha1.ElasticsearchClone
+	if err := r.Client.Get(ctx, request.NamespacedName, &clone); err != nil {
+		if apierrors.IsNotFound(err) {
+			// the Elasticsearch cluster of the clone is garbage collected
+			metrics.ForgetReconciledResource(ctx)
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
+	}
+
+	// skip unmanaged resources
+	if common.IsUnmanaged(ctx, &clone) {
+		ulog.FromContext(ctx).Info("Object is currently not managed by this controller. Skipping reconciliation")
+		return reconcile.Result{}, tracing.CaptureError(ctx, common.ReconcilePausedCondition(ctx, r.Client, &clone, &clone.Status.Conditions, true))
+	}
+	if err := common.ReconcilePausedCondition(ctx, r.Client, &clone, &clone.Status.Conditions, false); err != nil {
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
+	}
+
+	if clone.IsMarkedForDeletion() {
+		return reconcile.Result{}, nil
+	}
+
+	if !r.now().Before(clone.ExpirationTime()) {
+		ulog.FromContext(ctx).Info("Deleting expired ElasticsearchClone", "namespace", clone.Namespace, "esclone_name", clone.Name)
+		r.recorder.Eventf(&clone, corev1.EventTypeNormal, events.EventReasonExpired, "ElasticsearchClone expired after %s", clone.Spec.TTLOrDefault())
+		return reconcile.Result{}, tracing.CaptureError(ctx, client.IgnoreNotFound(r.Client.Delete(ctx, &clone)))
+	}
+
+	result, status, err := r.doReconcile(ctx, clone)
+	if err != nil {
+		status.Phase = esclonev1alpha1.ErrorPhase
+		status.Message = err.Error()
+	}
+
+	if updateErr := r.updateStatus(ctx, clone, status); updateErr != nil {
+		if apierrors.IsConflict(updateErr) {
+			return reconcile.Result{Requeue: true}, nil
+		}
+		return reconcile.Result{}, tracing.CaptureError(ctx, updateErr)
+	}
+	return r.untilExpiration(clone, result), tracing.CaptureError(ctx, err)
+}
+
+func (r *ReconcileElasticsearchClone) doReconcile(ctx context.Context, clone esclonev1alpha1.ElasticsearchClone) (reconcile.Result, esclonev1alpha1.ElasticsearchCloneStatus, error) {
+	status := *clone.Status.DeepCopy()
+	status.ObservedGeneration = clone.Generation
+	status.ExpirationTime = &metav1.Time{Time: clone.ExpirationTime()}
+
+	// run validation in case the webhook is disabled
+	if err := clone.Validate(); err != nil {
+		r.recorder.Eventf(&clone, corev1.EventTypeWarning, events.EventReasonValidation, err.Error())
+		status.Phase = esclonev1alpha1.InvalidPhase
+		status.Message = err.Error()
+		// the resource must be updated by the user, no need to requeue
+		return reconcile.Result{}, status, nil
+	}
+
+	es, pending, err := r.reconcileElasticsearch(ctx, clone, &status)
+	if err != nil || pending {
+		return defaultRequeue, status, err
+	}
+
+	if status.RestoreCompletionTime != nil {
+		status.Phase = esclonev1alpha1.ReadyPhase
+		status.Message = ""
+		return reconcile.Result{}, status, nil
+	}
+
+	if es.Status.Phase != esv1.ElasticsearchReadyPhase ||
+		(es.Status.Health != esv1.ElasticsearchGreenHealth && es.Status.Health != esv1.ElasticsearchYellowHealth) {
+		status.Phase = esclonev1alpha1.ProvisioningPhase
+		status.Message = fmt.Sprintf("Elasticsearch %s/%s is not available yet", es.Namespace, es.Name)
+		return defaultRequeue, status, nil
+	}
+
+	esClient, err := r.esClientProvider(ctx, r.Client, r.params.Dialer, es)
+	if err != nil {
+		return reconcile.Result{}, status, err
+	}
+	defer esClient.Close()
+
+	return r.reconcileRestore(ctx, esClient, clone, &status)
+}
+
+// reconcileElasticsearch creates the Elasticsearch cluster of the clone from the spec of the source cluster, then keeps
+// its sizing up-to-date with the clone. It returns true if the cluster cannot be created yet.
+func (r *ReconcileElasticsearchClone) reconcileElasticsearch(
+	ctx context.Context,
+	clone esclonev1alpha1.ElasticsearchClone,
+	status *esclonev1alpha1.ElasticsearchCloneStatus,
+) (esv1.Elasticsearch, bool, error) {
+	var expected esv1.Elasticsearch
+	var actual esv1.Elasticsearch
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: clone.Namespace, Name: clone.Name}, &actual)
+	switch {
+	case err == nil:
+		if !metav1.IsControlledBy(&actual, &clone) {
+			return esv1.Elasticsearch{}, false, fmt.Errorf("elasticsearch %s/%s already exists and is not managed by the ElasticsearchClone", actual.Namespace, actual.Name)
+		}
+		// the spec of the source cluster is only used at creation, further changes are not propagated to the clone
+		expected = *actual.DeepCopy()
+		withSizing(clone, &expected.Spec)
+	case apierrors.IsNotFound(err):
+		source, pending, err := r.getSource(ctx, clone, status)
+		if err != nil || pending {
+			return esv1.Elasticsearch{}, pending, err
+		}
+		if status.Snapshot == "" {
+			snapshot, pending, err := r.resolveSnapshot(ctx, clone, source, status)
+			if err != nil || pending {
+				return esv1.Elasticsearch{}, pending, err
+			}
+			status.Snapshot = snapshot
+		}
+		expected = newCloneElasticsearch(clone, source)
+	default:
+		return esv1.Elasticsearch{}, false, err
+	}
+
+	var reconciled esv1.Elasticsearch
+	err = reconciler.ReconcileResource(reconciler.Params{
+		Context:    ctx,
+		Client:     r.Client,
+		Owner:      &clone,
+		Expected:   &expected,
+		Reconciled: &reconciled,
+		NeedsUpdate: func() bool {
+			return !reflect.DeepEqual(expected.Spec, reconciled.Spec)
+		},
+		UpdateReconciled: func() {
+			expected.Spec.DeepCopyInto(&reconciled.Spec)
+		},
+	})
+	return reconciled, false, err
+}
+
+// getSource returns the source cluster of the clone, and true if it is not available yet.
+func (r *ReconcileElasticsearchClone) getSource(
+	ctx context.Context,
+	clone esclonev1alpha1.ElasticsearchClone,
+	status *esclonev1alpha1.ElasticsearchCloneStatus,
+) (esv1.Elasticsearch, bool, error) {
+	sourceRef := clone.SourceRef()
+	var source esv1.Elasticsearch
+	if err := r.Client.Get(ctx, sourceRef, &source); err != nil {
+		if apierrors.IsNotFound(err) {
+			status.Phase = esclonev1alpha1.PendingPhase
+			status.Message = fmt.Sprintf("Elasticsearch %s/%s does not exist", sourceRef.Namespace, sourceRef.Name)
+			return esv1.Elasticsearch{}, true, nil
+		}
+		return esv1.Elasticsearch{}, false, err
+	}
+	if !hasRepository(source, clone.Spec.Repository) {
+		status.Phase = esclonev1alpha1.PendingPhase
+		status.Message = fmt.Sprintf("Snapshot repository %s is not declared in Elasticsearch %s/%s", clone.Spec.Repository, source.Namespace, source.Name)
+		return esv1.Elasticsearch{}, true, nil
+	}
+	return source, false, nil
+}
+
+// resolveSnapshot returns the snapshot to restore in the clone: the snapshot of the spec if any, otherwise the latest
+// successful snapshot of the repository, retrieved from the source cluster. It returns true if the snapshot cannot be
+// resolved yet.
+func (r *ReconcileElasticsearchClone) resolveSnapshot(
+	ctx context.Context,
+	clone esclonev1alpha1.ElasticsearchClone,
+	source esv1.Elasticsearch,
+	status *esclonev1alpha1.ElasticsearchCloneStatus,
+) (string, bool, error) {
+	if clone.Spec.Snapshot != "" {
+		return clone.Spec.Snapshot, false, nil
+	}
+	if source.Status.Health != esv1.ElasticsearchGreenHealth && source.Status.Health != esv1.ElasticsearchYellowHealth {
+		ulog.FromContext(ctx).V(1).Info("Source Elasticsearch cluster not available yet, requeuing", "es_namespace", source.Namespace, "es_name", source.Name)
+		status.Phase = esclonev1alpha1.PendingPhase
+		status.Message = fmt.Sprintf("Elasticsearch %s/%s is not available", source.Namespace, source.Name)
+		return "", true, nil
+	}
+	sourceClient, err := r.esClientProvider(ctx, r.Client, r.params.Dialer, source)
+	if err != nil {
+		return "", false, err
+	}
+	defer sourceClient.Close()
+	snapshots, err := sourceClient.GetSnapshots(ctx, clone.Spec.Repository)
+	if err != nil {
+		return "", false, err
+	}
+	snapshot, exists := latestSuccessfulSnapshot(snapshots)
+	if !exists {
+		status.Phase = esclonev1alpha1.PendingPhase
+		status.Message = fmt.Sprintf("No successful snapshot in repository %s", clone.Spec.Repository)
+		return "", true, nil
+	}
+	return snapshot, false, nil
+}
+
+// latestSuccessfulSnapshot returns the name of the most recent successful snapshot.
+func latestSuccessfulSnapshot(snapshots []esclient.Snapshot) (string, bool) {
+	var latest *esclient.Snapshot
+	for i := range snapshots {
+		if snapshots[i].State != esclient.SnapshotSuccessState {
+			continue
+		}
+		if latest == nil || snapshots[i].StartTimeInMillis >= latest.StartTimeInMillis {
+			latest = &snapshots[i]
+		}
+	}
+	if latest == nil {
+		return "", false
+	}
+	return latest.Snapshot, true
+}
+
+// reconcileRestore starts the restore of the snapshot in the clone once the repository is registered, then waits for
+// all the restored shards to be initialized.
+func (r *ReconcileElasticsearchClone) reconcileRestore(
+	ctx context.Context,
+	esClient esclient.Client,
+	clone esclonev1alpha1.ElasticsearchClone,
+	status *esclonev1alpha1.ElasticsearchCloneStatus,
+) (reconcile.Result, esclonev1alpha1.ElasticsearchCloneStatus, error) {
+	if status.RestoreStartTime == nil {
+		repositories, err := esClient.GetSnapshotRepositories(ctx)
+		if err != nil {
+			return reconcile.Result{}, *status, err
+		}
+		if _, exists := repositories[clone.Spec.Repository]; !exists {
+			status.Phase = esclonev1alpha1.ProvisioningPhase
+			status.Message = fmt.Sprintf("Waiting for snapshot repository %s to be registered", clone.Spec.Repository)
+			return defaultRequeue, *status, nil
+		}
+		ulog.FromContext(ctx).Info("Restoring snapshot", "namespace", clone.Namespace, "esclone_name", clone.Name, "repository", clone.Spec.Repository, "snapshot", status.Snapshot)
+		if err := esClient.RestoreSnapshot(ctx, clone.Spec.Repository, status.Snapshot, clone.Spec.IndicesOrDefault()); err != nil {
+			return reconcile.Result{}, *status, err
+		}
+		status.RestoreStartTime = &metav1.Time{Time: r.now()}
+		status.Phase = esclonev1alpha1.RestoringPhase
+		status.Message = fmt.Sprintf("Restoring snapshot %s", status.Snapshot)
+		return defaultRequeue, *status, nil
+	}
+
+	health, err := esClient.GetClusterHealthWaitForAllEvents(ctx)
+	if err != nil {
+		return reconcile.Result{}, *status, err
+	}
+	if health.Status == esv1.ElasticsearchRedHealth || health.HasShardActivity() {
+		status.Phase = esclonev1alpha1.RestoringPhase
+		status.Message = fmt.Sprintf("Restoring snapshot %s", status.Snapshot)
+		return defaultRequeue, *status, nil
+	}
+	status.RestoreCompletionTime = &metav1.Time{Time: r.now()}
+	status.Phase = esclonev1alpha1.ReadyPhase
+	status.Message = ""
+	r.recorder.Eventf(&clone, corev1.EventTypeNormal, events.EventReasonSnapshotRestored,
+		"Restored snapshot %s of repository %s", status.Snapshot, clone.Spec.Repository)
+	return reconcile.Result{}, *status, nil
+}
+
+// untilExpiration makes sure the clone is reconciled again when it expires.
+func (r *ReconcileElasticsearchClone) untilExpiration(clone esclonev1alpha1.ElasticsearchClone, result reconcile.Result) reconcile.Result {
+	untilExpiration := clone.ExpirationTime().Sub(r.now())
+	if result.RequeueAfter == 0 || untilExpiration < result.RequeueAfter {
+		result.RequeueAfter = untilExpiration
+	}
+	return result
+}
+
+func (r *ReconcileElasticsearchClone) updateStatus(ctx context.Context, clone esclonev1alpha1.ElasticsearchClone, status esclonev1alpha1.ElasticsearchCloneStatus) error {
+	span, _ := apm.StartSpan(ctx, "update_status", tracing.SpanTypeApp)
+	defer span.End()
+
+	status.Conditions = clone.Status.Conditions.WithReadinessConditions(status.ResourceState(), metav1.Now())
+	if reflect.DeepEqual(status, clone.Status) {
+		return nil // nothing to do
+	}
+	ulog.FromContext(ctx).V(1).Info("Updating status",
+		"iteration", atomic.LoadUint64(&r.iteration),
+		"status", status,
+	)
+	clone.Status = status
+	return common.UpdateStatus(ctx, r.Client, &clone)
+}
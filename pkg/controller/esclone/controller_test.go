@@ -0,0 +1,268 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package esclone
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	esclonev1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/esclone/v1alpha1"
+	commonesclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/esclient"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+var creationTime = time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+
+type restoreRequest struct {
+	repository string
+	snapshot   string
+	indices    []string
+}
+
+type fakeEsClient struct {
+	esclient.Client
+
+	snapshots    []esclient.Snapshot
+	repositories esclient.SnapshotRepositories
+	health       esclient.Health
+	restores     []restoreRequest
+}
+
+func (c *fakeEsClient) provider() commonesclient.Provider {
+	return func(_ context.Context, _ k8s.Client, _ net.Dialer, _ esv1.Elasticsearch) (esclient.Client, error) {
+		return c, nil
+	}
+}
+
+func (c *fakeEsClient) GetSnapshots(_ context.Context, _ string) ([]esclient.Snapshot, error) {
+	return c.snapshots, nil
+}
+
+func (c *fakeEsClient) GetSnapshotRepositories(_ context.Context) (esclient.SnapshotRepositories, error) {
+	return c.repositories, nil
+}
+
+func (c *fakeEsClient) RestoreSnapshot(_ context.Context, repository, snapshot string, indices []string) error {
+	c.restores = append(c.restores, restoreRequest{repository: repository, snapshot: snapshot, indices: indices})
+	return nil
+}
+
+func (c *fakeEsClient) GetClusterHealthWaitForAllEvents(_ context.Context) (esclient.Health, error) {
+	return c.health, nil
+}
+
+func (c *fakeEsClient) Close() {}
+
+func mkClone() *esclonev1alpha1.ElasticsearchClone {
+	return &esclonev1alpha1.ElasticsearchClone{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "preview", Generation: 1, CreationTimestamp: metav1.NewTime(creationTime)},
+		Spec: esclonev1alpha1.ElasticsearchCloneSpec{
+			SourceRef:  esclonev1alpha1.SourceRef{Name: "search"},
+			Repository: "backups",
+		},
+	}
+}
+
+func mkSource(health esv1.ElasticsearchHealth) *esv1.Elasticsearch {
+	return &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "search"},
+		Spec: esv1.ElasticsearchSpec{
+			Version:              "8.15.0",
+			NodeSets:             []esv1.NodeSet{{Name: "default", Count: 3}},
+			SnapshotRepositories: []esv1.SnapshotRepository{{Name: "backups", Type: "s3"}},
+		},
+		Status: esv1.ElasticsearchStatus{Health: health},
+	}
+}
+
+func newTestReconciler(esClient *fakeEsClient, now time.Time, objects ...client.Object) *ReconcileElasticsearchClone {
+	return &ReconcileElasticsearchClone{
+		Client:           k8s.NewFakeClient(objects...),
+		esClientProvider: esClient.provider(),
+		recorder:         record.NewFakeRecorder(100),
+		now:              func() time.Time { return now },
+	}
+}
+
+func reconcileClone(t *testing.T, r *ReconcileElasticsearchClone) (reconcile.Result, esclonev1alpha1.ElasticsearchClone) {
+	t.Helper()
+	nsn := types.NamespacedName{Namespace: "ns", Name: "preview"}
+	result, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: nsn})
+	require.NoError(t, err)
+	var updated esclonev1alpha1.ElasticsearchClone
+	require.NoError(t, r.Client.Get(context.Background(), nsn, &updated))
+	return result, updated
+}
+
+func TestReconcileElasticsearchClone_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	esClient := &fakeEsClient{
+		snapshots: []esclient.Snapshot{
+			{Snapshot: "nightly-2024.09.29", State: esclient.SnapshotSuccessState, StartTimeInMillis: 1727568000000},
+			{Snapshot: "nightly-2024.09.30", State: esclient.SnapshotSuccessState, StartTimeInMillis: 1727654400000},
+			{Snapshot: "nightly-2024.10.01", State: "FAILED", StartTimeInMillis: 1727740800000},
+		},
+	}
+	r := newTestReconciler(esClient, creationTime.Add(time.Hour), mkClone(), mkSource(esv1.ElasticsearchGreenHealth))
+
+	// the Elasticsearch cluster of the clone is created from the latest successful snapshot
+	_, clone := reconcileClone(t, r)
+	require.Equal(t, esclonev1alpha1.ProvisioningPhase, clone.Status.Phase)
+	require.Equal(t, "nightly-2024.09.30", clone.Status.Snapshot)
+	require.Equal(t, creationTime.Add(esclonev1alpha1.DefaultTTL), clone.Status.ExpirationTime.Time.UTC())
+	var es esv1.Elasticsearch
+	require.NoError(t, r.Client.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "preview"}, &es))
+	require.True(t, metav1.IsControlledBy(&es, &clone))
+	require.Equal(t, int32(1), es.Spec.NodeSets[0].Count)
+
+	// the snapshot is restored once the cluster is available and the repository registered
+	es.Status = esv1.ElasticsearchStatus{Phase: esv1.ElasticsearchReadyPhase, Health: esv1.ElasticsearchGreenHealth}
+	require.NoError(t, r.Client.Status().Update(ctx, &es))
+	_, clone = reconcileClone(t, r)
+	require.Equal(t, esclonev1alpha1.ProvisioningPhase, clone.Status.Phase)
+	require.Contains(t, clone.Status.Message, "Waiting for snapshot repository backups")
+	require.Empty(t, esClient.restores)
+
+	esClient.repositories = esclient.SnapshotRepositories{"backups": {Type: "s3"}}
+	esClient.health = esclient.Health{Status: esv1.ElasticsearchYellowHealth, InitializingShards: 2}
+	_, clone = reconcileClone(t, r)
+	require.Equal(t, esclonev1alpha1.RestoringPhase, clone.Status.Phase)
+	require.NotNil(t, clone.Status.RestoreStartTime)
+	require.Equal(t, []restoreRequest{{repository: "backups", snapshot: "nightly-2024.09.30", indices: esclonev1alpha1.DefaultIndices}}, esClient.restores)
+
+	// the restore completes once all the shards are initialized
+	_, clone = reconcileClone(t, r)
+	require.Equal(t, esclonev1alpha1.RestoringPhase, clone.Status.Phase)
+	esClient.health = esclient.Health{Status: esv1.ElasticsearchGreenHealth}
+	result, clone := reconcileClone(t, r)
+	require.Equal(t, esclonev1alpha1.ReadyPhase, clone.Status.Phase)
+	require.NotNil(t, clone.Status.RestoreCompletionTime)
+	require.Len(t, esClient.restores, 1)
+	// the clone is reconciled again when it expires
+	require.Equal(t, esclonev1alpha1.DefaultTTL-time.Hour, result.RequeueAfter)
+
+	// the sizing of the clone can be updated
+	clone.Spec.NodeCount = ptr.To[int32](2)
+	require.NoError(t, r.Client.Update(ctx, &clone))
+	_, _ = reconcileClone(t, r)
+	require.NoError(t, r.Client.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "preview"}, &es))
+	require.Equal(t, int32(2), es.Spec.NodeSets[0].Count)
+
+	// the clone is deleted once expired
+	r.now = func() time.Time { return creationTime.Add(esclonev1alpha1.DefaultTTL) }
+	_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "preview"}})
+	require.NoError(t, err)
+	require.Error(t, r.Client.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "preview"}, &clone))
+}
+
+func TestReconcileElasticsearchClone_Pending(t *testing.T) {
+	withSnapshot := mkClone()
+	withSnapshot.Spec.Snapshot = "nightly-2024.09.29"
+	withoutRepository := mkSource(esv1.ElasticsearchGreenHealth)
+	withoutRepository.Spec.SnapshotRepositories = nil
+	notOwned := &esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "preview"}}
+
+	tests := []struct {
+		name               string
+		objects            []client.Object
+		snapshots          []esclient.Snapshot
+		wantPhase          esclonev1alpha1.ClonePhase
+		wantSnapshot       string
+		wantMessageContent string
+	}{
+		{
+			name:               "source cluster does not exist",
+			objects:            []client.Object{mkClone()},
+			wantPhase:          esclonev1alpha1.PendingPhase,
+			wantMessageContent: "Elasticsearch ns/search does not exist",
+		},
+		{
+			name:               "repository not declared in the source cluster",
+			objects:            []client.Object{mkClone(), withoutRepository},
+			wantPhase:          esclonev1alpha1.PendingPhase,
+			wantMessageContent: "Snapshot repository backups is not declared",
+		},
+		{
+			name:               "source cluster not available",
+			objects:            []client.Object{mkClone(), mkSource(esv1.ElasticsearchRedHealth)},
+			wantPhase:          esclonev1alpha1.PendingPhase,
+			wantMessageContent: "is not available",
+		},
+		{
+			name:               "no successful snapshot",
+			objects:            []client.Object{mkClone(), mkSource(esv1.ElasticsearchGreenHealth)},
+			snapshots:          []esclient.Snapshot{{Snapshot: "nightly-2024.10.01", State: "FAILED"}},
+			wantPhase:          esclonev1alpha1.PendingPhase,
+			wantMessageContent: "No successful snapshot in repository backups",
+		},
+		{
+			name:         "snapshot of the spec does not require the source cluster to be available",
+			objects:      []client.Object{withSnapshot, mkSource(esv1.ElasticsearchRedHealth)},
+			wantPhase:    esclonev1alpha1.ProvisioningPhase,
+			wantSnapshot: "nightly-2024.09.29",
+		},
+		{
+			name:               "Elasticsearch cluster with the same name not managed by the clone",
+			objects:            []client.Object{mkClone(), mkSource(esv1.ElasticsearchGreenHealth), notOwned},
+			wantPhase:          esclonev1alpha1.ErrorPhase,
+			wantMessageContent: "already exists and is not managed by the ElasticsearchClone",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestReconciler(&fakeEsClient{snapshots: tt.snapshots}, creationTime, tt.objects...)
+			nsn := types.NamespacedName{Namespace: "ns", Name: "preview"}
+			_, _ = r.Reconcile(context.Background(), reconcile.Request{NamespacedName: nsn})
+			var clone esclonev1alpha1.ElasticsearchClone
+			require.NoError(t, r.Client.Get(context.Background(), nsn, &clone))
+			assert.Equal(t, tt.wantPhase, clone.Status.Phase)
+			assert.Equal(t, tt.wantSnapshot, clone.Status.Snapshot)
+			assert.Contains(t, clone.Status.Message, tt.wantMessageContent)
+		})
+	}
+}
+
+func Test_newCloneElasticsearch(t *testing.T) {
+	source := mkSource(esv1.ElasticsearchGreenHealth)
+	source.Annotations = map[string]string{esv1.DownwardNodeLabelsAnnotation: "topology.kubernetes.io/zone"}
+	source.Spec.HTTP.TLS.Certificate = commonv1.SecretRef{SecretName: "search-http-cert"}
+	source.Spec.RemoteClusters = []esv1.RemoteCluster{{Name: "other"}}
+	source.Spec.SnapshotRepositories = append(source.Spec.SnapshotRepositories, esv1.SnapshotRepository{Name: "archive", Type: "gcs"})
+	source.Spec.NodeSets = append(source.Spec.NodeSets,
+		esv1.NodeSet{Name: "ingest", Count: 2, IngestAutoscaling: &esv1.IngestAutoscaling{MinCount: 2, MaxCount: 6}},
+		esv1.NodeSet{Name: "ml", Count: 0},
+	)
+	clone := mkClone()
+
+	es := newCloneElasticsearch(*clone, *source)
+	require.Equal(t, "preview", es.Name)
+	require.Equal(t, map[string]string{esclonev1alpha1.CloneNameLabelName: "preview"}, es.Labels)
+	require.Empty(t, es.Annotations)
+	require.Empty(t, es.Spec.HTTP.TLS.Certificate.SecretName)
+	require.Empty(t, es.Spec.RemoteClusters)
+	require.Equal(t, esv1.DeleteOnScaledownAndClusterDeletionPolicy, es.Spec.VolumeClaimDeletePolicy)
+	require.Equal(t, []esv1.SnapshotRepository{
+		{Name: "backups", Type: "s3", Settings: &commonv1.Config{Data: map[string]interface{}{"readonly": "true"}}},
+	}, es.Spec.SnapshotRepositories)
+	require.Equal(t, []int32{1, 1, 0}, []int32{es.Spec.NodeSets[0].Count, es.Spec.NodeSets[1].Count, es.Spec.NodeSets[2].Count})
+	require.Nil(t, es.Spec.NodeSets[1].IngestAutoscaling)
+	// the source cluster is not mutated
+	require.Empty(t, source.Spec.SnapshotRepositories[0].Settings)
+	require.Equal(t, int32(3), source.Spec.NodeSets[0].Count)
+}
@@ -0,0 +1,122 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package esclone
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	esclonev1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/esclone/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
+)
+
+// readOnlySetting prevents the clone from writing to the repository of the source cluster.
+const readOnlySetting = "readonly"
+
+// hasRepository returns true if the given snapshot repository is declared in the spec of the given cluster.
+func hasRepository(es esv1.Elasticsearch, name string) bool {
+	for _, repository := range es.Spec.SnapshotRepositories {
+		if repository.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// newCloneElasticsearch returns the Elasticsearch cluster of the given clone, derived from the spec of the source
+// cluster. The cluster is renamed after the clone, and stripped of the settings bound to the identity of the source
+// cluster: annotations, custom certificates, remote clusters, monitoring and snapshot repositories other than the one
+// holding the snapshot, registered as read-only.
+func newCloneElasticsearch(clone esclonev1alpha1.ElasticsearchClone, source esv1.Elasticsearch) esv1.Elasticsearch {
+	spec := *source.Spec.DeepCopy()
+	spec.HTTP.TLS.Certificate = commonv1.SecretRef{}
+	spec.Transport.TLS.Certificate = commonv1.SecretRef{}
+	spec.RemoteClusters = nil
+	spec.Monitoring = commonv1.Monitoring{}
+	spec.SnapshotVerification = nil
+	// volumes are deleted along with the clone once it expires
+	spec.VolumeClaimDeletePolicy = esv1.DeleteOnScaledownAndClusterDeletionPolicy
+
+	spec.SnapshotRepositories = nil
+	for _, repository := range source.Spec.SnapshotRepositories {
+		if repository.Name != clone.Spec.Repository {
+			continue
+		}
+		repository = *repository.DeepCopy()
+		if repository.Settings == nil {
+			repository.Settings = &commonv1.Config{}
+		}
+		if repository.Settings.Data == nil {
+			repository.Settings.Data = map[string]interface{}{}
+		}
+		repository.Settings.Data[readOnlySetting] = "true"
+		spec.SnapshotRepositories = append(spec.SnapshotRepositories, repository)
+	}
+
+	for i := range spec.NodeSets {
+		// the count of the NodeSets is set by the clone
+		spec.NodeSets[i].IngestAutoscaling = nil
+	}
+
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: clone.Namespace,
+			Name:      clone.Name,
+			Labels:    map[string]string{esclonev1alpha1.CloneNameLabelName: clone.Name},
+		},
+		Spec: spec,
+	}
+	withSizing(clone, &es.Spec)
+	return es
+}
+
+// withSizing applies the sizing of the clone to the given spec: the count of the NodeSets with nodes, the resources
+// of the Elasticsearch containers and the size of the data volumes.
+func withSizing(clone esclonev1alpha1.ElasticsearchClone, spec *esv1.ElasticsearchSpec) {
+	for i := range spec.NodeSets {
+		nodeSet := &spec.NodeSets[i]
+		if nodeSet.Count > 0 {
+			nodeSet.Count = clone.Spec.NodeCountOrDefault()
+		}
+		if clone.Spec.Resources != nil {
+			withResources(&nodeSet.PodTemplate, *clone.Spec.Resources)
+		}
+		if clone.Spec.StorageSize != nil && !nodeSet.Ephemeral {
+			withStorageSize(nodeSet, *clone.Spec.StorageSize)
+		}
+	}
+}
+
+func withResources(podTemplate *corev1.PodTemplateSpec, resources corev1.ResourceRequirements) {
+	for i := range podTemplate.Spec.Containers {
+		if podTemplate.Spec.Containers[i].Name == esv1.ElasticsearchContainerName {
+			podTemplate.Spec.Containers[i].Resources = *resources.DeepCopy()
+			return
+		}
+	}
+	podTemplate.Spec.Containers = append(podTemplate.Spec.Containers, corev1.Container{
+		Name:      esv1.ElasticsearchContainerName,
+		Resources: *resources.DeepCopy(),
+	})
+}
+
+func withStorageSize(nodeSet *esv1.NodeSet, size resource.Quantity) {
+	if len(nodeSet.VolumeClaimTemplates) == 0 {
+		nodeSet.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{*volume.DefaultDataVolumeClaim.DeepCopy()}
+	}
+	for i := range nodeSet.VolumeClaimTemplates {
+		claim := &nodeSet.VolumeClaimTemplates[i]
+		if claim.Name != volume.ElasticsearchDataVolumeName {
+			continue
+		}
+		if claim.Spec.Resources.Requests == nil {
+			claim.Spec.Resources.Requests = corev1.ResourceList{}
+		}
+		claim.Spec.Resources.Requests[corev1.ResourceStorage] = size
+	}
+}
@@ -0,0 +1,245 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package esconfig
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.elastic.co/apm/v2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	esconfigv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/esconfig/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
+	commonesclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/esclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/rbac"
+)
+
+const (
+	controllerName = "esconfig-controller"
+)
+
+var (
+	defaultRequeue = reconcile.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+)
+
+// Add creates a new ElasticsearchConfig Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, accessReviewer rbac.AccessReviewer, params operator.Parameters) error {
+	r := newReconciler(mgr, accessReviewer, params)
+	c, err := common.NewController(mgr, controllerName, r, params)
+	if err != nil {
+		return err
+	}
+	return addWatches(mgr, c, r)
+}
+
+// newReconciler returns a new reconcile.Reconciler of ElasticsearchConfig.
+func newReconciler(mgr manager.Manager, accessReviewer rbac.AccessReviewer, params operator.Parameters) *ReconcileElasticsearchConfig {
+	return &ReconcileElasticsearchConfig{
+		Client:           mgr.GetClient(),
+		accessReviewer:   accessReviewer,
+		esClientProvider: commonesclient.NewClient,
+		recorder:         mgr.GetEventRecorderFor(controllerName),
+		params:           params,
+	}
+}
+
+func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileElasticsearchConfig) error {
+	// watch for changes to ElasticsearchConfig
+	if err := c.Watch(source.Kind(mgr.GetCache(), &esconfigv1.ElasticsearchConfig{}, &handler.TypedEnqueueRequestForObject[*esconfigv1.ElasticsearchConfig]{})); err != nil {
+		return err
+	}
+
+	// watch for changes to Elasticsearch and reconcile the ElasticsearchConfigs referencing it
+	return c.Watch(source.Kind[client.Object](mgr.GetCache(), &esv1.Elasticsearch{}, reconcileRequestsForElasticsearch(r.Client)))
+}
+
+// reconcileRequestsForElasticsearch returns the requests to reconcile all ElasticsearchConfigs referencing an Elasticsearch cluster.
+func reconcileRequestsForElasticsearch(clnt k8s.Client) handler.TypedEventHandler[client.Object, reconcile.Request] {
+	return handler.TypedEnqueueRequestsFromMapFunc[client.Object](func(ctx context.Context, es client.Object) []reconcile.Request {
+		var configs esconfigv1.ElasticsearchConfigList
+		if err := clnt.List(ctx, &configs); err != nil {
+			ulog.Log.Error(err, "Fail to list ElasticsearchConfigList while watching Elasticsearch")
+			return nil
+		}
+		esNsn := k8s.ExtractNamespacedName(es)
+		requests := make([]reconcile.Request, 0)
+		for _, config := range configs.Items {
+			if config.ElasticsearchRef().NamespacedName() != esNsn {
+				continue
+			}
+			config := config
+			requests = append(requests, reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(&config)})
+		}
+		return requests
+	})
+}
+
+var _ reconcile.Reconciler = &ReconcileElasticsearchConfig{}
+
+// ReconcileElasticsearchConfig reconciles an ElasticsearchConfig object
+type ReconcileElasticsearchConfig struct {
+	k8s.Client
+	accessReviewer   rbac.AccessReviewer
+	esClientProvider commonesclient.Provider
+	recorder         record.EventRecorder
+	params           operator.Parameters
+	// iteration is the number of times this controller has run its Reconcile method
+	iteration uint64
+}
+
+// Reconcile reads that state of the cluster for an ElasticsearchConfig object and makes sure the operations it declares
+// are applied in the referenced Elasticsearch cluster.
+// Deleting an ElasticsearchConfig does not revert its operations: the resources they created are retained.
+func (r *ReconcileElasticsearchConfig) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	ctx = common.NewReconciliationContext(ctx, &r.iteration, r.params.Tracer, controllerName, "esconfig_name", request)
+	defer common.LogReconciliationRun(ulog.FromContext(ctx))()
+	defer tracing.EndContextTransaction(ctx)
+
+	var config esconfigv1.ElasticsearchConfig
+	if err := r.Client.Get(ctx, request.NamespacedName, &config); err != nil {
+		if apierrors.IsNotFound(err) {
+			// the resources created in Elasticsearch are retained
+			metrics.ForgetReconciledResource(ctx)
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
+	}
+
+	// skip unmanaged resources
+	if common.IsUnmanaged(ctx, &config) {
+		ulog.FromContext(ctx).Info("Object is currently not managed by this controller. Skipping reconciliation")
+		return reconcile.Result{}, tracing.CaptureError(ctx, common.ReconcilePausedCondition(ctx, r.Client, &config, &config.Status.Conditions, true))
+	}
+	if err := common.ReconcilePausedCondition(ctx, r.Client, &config, &config.Status.Conditions, false); err != nil {
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
+	}
+
+	if config.IsMarkedForDeletion() {
+		return reconcile.Result{}, nil
+	}
+
+	result, status, err := r.doReconcile(ctx, config)
+	if err != nil {
+		status.Phase = esconfigv1.ErrorPhase
+		status.Message = err.Error()
+	}
+
+	if updateErr := r.updateStatus(ctx, config, status); updateErr != nil {
+		if apierrors.IsConflict(updateErr) {
+			return reconcile.Result{Requeue: true}, nil
+		}
+		return reconcile.Result{}, tracing.CaptureError(ctx, updateErr)
+	}
+	return result, tracing.CaptureError(ctx, err)
+}
+
+func (r *ReconcileElasticsearchConfig) doReconcile(ctx context.Context, config esconfigv1.ElasticsearchConfig) (reconcile.Result, esconfigv1.ElasticsearchConfigStatus, error) {
+	log := ulog.FromContext(ctx)
+	// keep the status of the operations until they are reconciled again
+	status := esconfigv1.ElasticsearchConfigStatus{ObservedGeneration: config.Generation, Operations: config.Status.Operations}
+
+	// run validation in case the webhook is disabled
+	if err := config.Validate(); err != nil {
+		r.recorder.Eventf(&config, corev1.EventTypeWarning, events.EventReasonValidation, err.Error())
+		status.Phase = esconfigv1.InvalidPhase
+		status.Message = err.Error()
+		// the resource must be updated by the user, no need to requeue
+		return reconcile.Result{}, status, nil
+	}
+	// cannot fail once validated
+	operations, err := config.Spec.OrderedOperations()
+	if err != nil {
+		return reconcile.Result{}, status, err
+	}
+
+	esRef := config.ElasticsearchRef()
+	var es esv1.Elasticsearch
+	if err := r.Client.Get(ctx, esRef.NamespacedName(), &es); err != nil {
+		if apierrors.IsNotFound(err) {
+			status.Phase = esconfigv1.PendingPhase
+			status.Message = fmt.Sprintf("Elasticsearch %s/%s does not exist", esRef.Namespace, esRef.Name)
+			// the resource is reconciled again when the Elasticsearch resource is created
+			return reconcile.Result{}, status, nil
+		}
+		return reconcile.Result{}, status, err
+	}
+
+	allowed, err := r.accessReviewer.AccessAllowed(ctx, config.Spec.ServiceAccountName, config.Namespace, &es)
+	if err != nil {
+		return reconcile.Result{}, status, err
+	}
+	if !allowed {
+		msg := fmt.Sprintf("ElasticsearchConfig %s/%s is not allowed to reference Elasticsearch %s/%s", config.Namespace, config.Name, es.Namespace, es.Name)
+		r.recorder.Event(&config, corev1.EventTypeWarning, events.EventAssociationError, msg)
+		status.Phase = esconfigv1.InvalidPhase
+		status.Message = msg
+		return defaultRequeue, status, nil
+	}
+
+	if es.Status.Health != esv1.ElasticsearchGreenHealth && es.Status.Health != esv1.ElasticsearchYellowHealth {
+		log.V(1).Info("Elasticsearch cluster not available yet, requeuing", "es_namespace", es.Namespace, "es_name", es.Name)
+		status.Phase = esconfigv1.PendingPhase
+		status.Message = fmt.Sprintf("Elasticsearch %s/%s is not available", es.Namespace, es.Name)
+		return defaultRequeue, status, nil
+	}
+
+	esClient, err := r.esClientProvider(ctx, r.Client, r.params.Dialer, es)
+	if err != nil {
+		return reconcile.Result{}, status, err
+	}
+	defer esClient.Close()
+
+	status.Operations = r.reconcileOperations(ctx, esClient, config, operations)
+	status.Phase = esconfigv1.ReadyPhase
+	status.Message = ""
+	if failed := failedOperations(status.Operations); len(failed) > 0 {
+		status.Phase = esconfigv1.ErrorPhase
+		status.Message = fmt.Sprintf("Failed to apply operations %s", strings.Join(failed, ", "))
+		return defaultRequeue, status, nil
+	}
+	if hasExpectedState(operations) {
+		// compare the state of Elasticsearch with the expected state of the operations on a regular basis
+		return reconcile.Result{RequeueAfter: config.Spec.DriftDetectionIntervalOrDefault()}, status, nil
+	}
+	return reconcile.Result{}, status, nil
+}
+
+func (r *ReconcileElasticsearchConfig) updateStatus(ctx context.Context, config esconfigv1.ElasticsearchConfig, status esconfigv1.ElasticsearchConfigStatus) error {
+	span, _ := apm.StartSpan(ctx, "update_status", tracing.SpanTypeApp)
+	defer span.End()
+
+	status.Conditions = config.Status.Conditions.WithReadinessConditions(status.ResourceState(), metav1.Now())
+	if reflect.DeepEqual(status, config.Status) {
+		return nil // nothing to do
+	}
+	ulog.FromContext(ctx).V(1).Info("Updating status",
+		"iteration", atomic.LoadUint64(&r.iteration),
+		"status", status,
+	)
+	config.Status = status
+	return common.UpdateStatus(ctx, r.Client, &config)
+}
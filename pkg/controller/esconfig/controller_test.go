@@ -0,0 +1,419 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package esconfig
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	esconfigv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/esconfig/v1"
+	commonesclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/esclient"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/rbac"
+)
+
+type request struct {
+	method string
+	path   string
+}
+
+// fakeEsClient stores the resources of the PUT requests by path.
+type fakeEsClient struct {
+	esclient.Client
+
+	resources map[string]interface{}
+	failures  map[string]error
+	requests  []request
+}
+
+func newFakeEsClient() *fakeEsClient {
+	return &fakeEsClient{resources: map[string]interface{}{}, failures: map[string]error{}}
+}
+
+func (c *fakeEsClient) provider() commonesclient.Provider {
+	return func(_ context.Context, _ k8s.Client, _ net.Dialer, _ esv1.Elasticsearch) (esclient.Client, error) {
+		return c, nil
+	}
+}
+
+func (c *fakeEsClient) GetResource(_ context.Context, path string) (interface{}, error) {
+	resource, exists := c.resources[path]
+	if !exists {
+		return nil, &esclient.APIError{StatusCode: http.StatusNotFound}
+	}
+	return resource, nil
+}
+
+func (c *fakeEsClient) ApplyResource(_ context.Context, method, path string, body interface{}) error {
+	c.requests = append(c.requests, request{method: method, path: path})
+	if err := c.failures[path]; err != nil {
+		return err
+	}
+	switch method {
+	case http.MethodDelete:
+		if _, exists := c.resources[path]; !exists {
+			return &esclient.APIError{StatusCode: http.StatusNotFound}
+		}
+		delete(c.resources, path)
+	default:
+		c.resources[path] = body
+	}
+	return nil
+}
+
+func (c *fakeEsClient) Close() {}
+
+type fakeAccessReviewer struct {
+	allowed bool
+}
+
+func (f fakeAccessReviewer) AccessAllowed(_ context.Context, _ string, _ string, _ runtime.Object) (bool, error) {
+	return f.allowed, nil
+}
+
+func mkConfig(operations ...esconfigv1.Operation) *esconfigv1.ElasticsearchConfig {
+	return &esconfigv1.ElasticsearchConfig{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "config", Generation: 1},
+		Spec: esconfigv1.ElasticsearchConfigSpec{
+			ElasticsearchRef: commonv1.ObjectSelector{Name: "es"},
+			Operations:       operations,
+		},
+	}
+}
+
+func mkElasticsearch(health esv1.ElasticsearchHealth) *esv1.Elasticsearch {
+	return &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec:       esv1.ElasticsearchSpec{Version: "8.15.0"},
+		Status:     esv1.ElasticsearchStatus{Health: health},
+	}
+}
+
+func body(data map[string]interface{}) *commonv1.Config {
+	return &commonv1.Config{Data: data}
+}
+
+var (
+	policyOp = esconfigv1.Operation{
+		Name:          "policy",
+		Method:        http.MethodPut,
+		Path:          "/_ilm/policy/logs",
+		Body:          body(map[string]interface{}{"policy": map[string]interface{}{"phases": map[string]interface{}{}}}),
+		ExpectedState: &esconfigv1.ExpectedState{Body: body(map[string]interface{}{"policy": map[string]interface{}{}})},
+	}
+	templateOp = esconfigv1.Operation{
+		Name:      "template",
+		Method:    http.MethodPut,
+		Path:      "/_index_template/logs",
+		Body:      body(map[string]interface{}{"index_patterns": []interface{}{"logs-*"}}),
+		DependsOn: []string{"policy"},
+	}
+)
+
+func reconcileConfig(t *testing.T, r *ReconcileElasticsearchConfig) (reconcile.Result, esconfigv1.ElasticsearchConfig) {
+	t.Helper()
+	nsn := types.NamespacedName{Namespace: "ns", Name: "config"}
+	result, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: nsn})
+	require.NoError(t, err)
+	var updated esconfigv1.ElasticsearchConfig
+	require.NoError(t, r.Client.Get(context.Background(), nsn, &updated))
+	return result, updated
+}
+
+func TestReconcileElasticsearchConfig_Reconcile(t *testing.T) {
+	tests := []struct {
+		name               string
+		objects            []client.Object
+		accessReviewer     rbac.AccessReviewer
+		failures           map[string]error
+		wantPhase          esconfigv1.ConfigPhase
+		wantResult         reconcile.Result
+		wantOperations     map[string]esconfigv1.OperationPhase
+		wantRequests       []request
+		wantMessageContent string
+	}{
+		{
+			name:           "operations are applied in order",
+			objects:        []client.Object{mkConfig(templateOp, policyOp), mkElasticsearch(esv1.ElasticsearchGreenHealth)},
+			accessReviewer: rbac.NewPermissiveAccessReviewer(),
+			wantPhase:      esconfigv1.ReadyPhase,
+			wantResult:     reconcile.Result{RequeueAfter: esconfigv1.DefaultDriftDetectionInterval},
+			wantOperations: map[string]esconfigv1.OperationPhase{
+				"template": esconfigv1.OperationAppliedPhase,
+				"policy":   esconfigv1.OperationAppliedPhase,
+			},
+			wantRequests: []request{{http.MethodPut, "/_ilm/policy/logs"}, {http.MethodPut, "/_index_template/logs"}},
+		},
+		{
+			name:           "failed operation blocks the operations depending on it",
+			objects:        []client.Object{mkConfig(policyOp, templateOp), mkElasticsearch(esv1.ElasticsearchGreenHealth)},
+			accessReviewer: rbac.NewPermissiveAccessReviewer(),
+			failures:       map[string]error{"/_ilm/policy/logs": errors.New("boom")},
+			wantPhase:      esconfigv1.ErrorPhase,
+			wantResult:     defaultRequeue,
+			wantOperations: map[string]esconfigv1.OperationPhase{
+				"policy":   esconfigv1.OperationFailedPhase,
+				"template": esconfigv1.OperationPendingPhase,
+			},
+			wantRequests:       []request{{http.MethodPut, "/_ilm/policy/logs"}},
+			wantMessageContent: "Failed to apply operations policy",
+		},
+		{
+			name:               "Elasticsearch does not exist",
+			objects:            []client.Object{mkConfig(policyOp)},
+			accessReviewer:     rbac.NewPermissiveAccessReviewer(),
+			wantPhase:          esconfigv1.PendingPhase,
+			wantMessageContent: "does not exist",
+		},
+		{
+			name:               "Elasticsearch not available",
+			objects:            []client.Object{mkConfig(policyOp), mkElasticsearch(esv1.ElasticsearchUnknownHealth)},
+			accessReviewer:     rbac.NewPermissiveAccessReviewer(),
+			wantPhase:          esconfigv1.PendingPhase,
+			wantResult:         defaultRequeue,
+			wantMessageContent: "is not available",
+		},
+		{
+			name:               "access not allowed",
+			objects:            []client.Object{mkConfig(policyOp), mkElasticsearch(esv1.ElasticsearchGreenHealth)},
+			accessReviewer:     fakeAccessReviewer{allowed: false},
+			wantPhase:          esconfigv1.InvalidPhase,
+			wantResult:         defaultRequeue,
+			wantMessageContent: "is not allowed to reference",
+		},
+		{
+			name:               "invalid dependencies",
+			objects:            []client.Object{mkConfig(templateOp), mkElasticsearch(esv1.ElasticsearchGreenHealth)},
+			accessReviewer:     rbac.NewPermissiveAccessReviewer(),
+			wantPhase:          esconfigv1.InvalidPhase,
+			wantMessageContent: "operation template depends on unknown operation policy",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			esClient := newFakeEsClient()
+			if tt.failures != nil {
+				esClient.failures = tt.failures
+			}
+			r := &ReconcileElasticsearchConfig{
+				Client:           k8s.NewFakeClient(tt.objects...),
+				accessReviewer:   tt.accessReviewer,
+				esClientProvider: esClient.provider(),
+				recorder:         record.NewFakeRecorder(10),
+			}
+			result, updated := reconcileConfig(t, r)
+			assert.Equal(t, tt.wantResult, result)
+			assert.Equal(t, tt.wantPhase, updated.Status.Phase)
+			assert.Equal(t, int64(1), updated.Status.ObservedGeneration)
+			assert.Contains(t, updated.Status.Message, tt.wantMessageContent)
+			assertReadinessConditions(t, updated.Status)
+			assert.Equal(t, tt.wantRequests, esClient.requests)
+			operations := make(map[string]esconfigv1.OperationPhase, len(updated.Status.Operations))
+			for _, op := range updated.Status.Operations {
+				operations[op.Name] = op.Phase
+			}
+			if tt.wantOperations == nil {
+				tt.wantOperations = map[string]esconfigv1.OperationPhase{}
+			}
+			assert.Equal(t, tt.wantOperations, operations)
+		})
+	}
+}
+
+func TestReconcileElasticsearchConfig_TemplatesAndAliases(t *testing.T) {
+	config := mkConfig(policyOp)
+	config.Spec.IndexTemplates = esconfigv1.IndexTemplates{
+		ComponentTemplates: body(map[string]interface{}{
+			"logs-settings": map[string]interface{}{"template": map[string]interface{}{"settings": map[string]interface{}{"number_of_shards": float64(1)}}},
+		}),
+		ComposableIndexTemplates: body(map[string]interface{}{
+			"logs": map[string]interface{}{"index_patterns": []interface{}{"logs-*"}, "composed_of": []interface{}{"logs-settings"}},
+		}),
+	}
+	config.Spec.Aliases = body(map[string]interface{}{
+		"logs-current": map[string]interface{}{"index": "logs-2024.10", "is_write_index": true},
+	})
+	esClient := newFakeEsClient()
+	r := &ReconcileElasticsearchConfig{
+		Client:           k8s.NewFakeClient(config, mkElasticsearch(esv1.ElasticsearchGreenHealth)),
+		accessReviewer:   rbac.NewPermissiveAccessReviewer(),
+		esClientProvider: esClient.provider(),
+		recorder:         record.NewFakeRecorder(10),
+	}
+
+	_, updated := reconcileConfig(t, r)
+	require.Equal(t, esconfigv1.ReadyPhase, updated.Status.Phase, updated.Status.Message)
+	require.Equal(t, []request{
+		{http.MethodPut, "/_component_template/logs-settings"},
+		{http.MethodPut, "/_index_template/logs"},
+		{http.MethodPut, "/_ilm/policy/logs"},
+		{http.MethodPost, "/_aliases"},
+	}, esClient.requests)
+	require.Equal(t, map[string]interface{}{
+		"actions": []interface{}{map[string]interface{}{"add": map[string]interface{}{"alias": "logs-current", "index": "logs-2024.10", "is_write_index": true}}},
+	}, esClient.resources["/_aliases"])
+	names := make([]string, 0, len(updated.Status.Operations))
+	for _, op := range updated.Status.Operations {
+		names = append(names, op.Name)
+	}
+	require.Equal(t, []string{"component-template/logs-settings", "index-template/logs", "policy", "alias/logs-current"}, names)
+}
+
+func TestReconcileElasticsearchConfig_DriftDetection(t *testing.T) {
+	deleteOp := esconfigv1.Operation{Name: "legacy", Method: http.MethodDelete, Path: "/_template/legacy"}
+	esClient := newFakeEsClient()
+	esClient.resources["/_template/legacy"] = map[string]interface{}{}
+	recorder := record.NewFakeRecorder(10)
+	r := &ReconcileElasticsearchConfig{
+		Client:           k8s.NewFakeClient(mkConfig(policyOp, templateOp, deleteOp), mkElasticsearch(esv1.ElasticsearchGreenHealth)),
+		accessReviewer:   rbac.NewPermissiveAccessReviewer(),
+		esClientProvider: esClient.provider(),
+		recorder:         recorder,
+	}
+
+	// all the operations are applied
+	_, updated := reconcileConfig(t, r)
+	require.Equal(t, esconfigv1.ReadyPhase, updated.Status.Phase)
+	require.Len(t, esClient.requests, 3)
+	require.NotContains(t, esClient.resources, "/_template/legacy")
+	require.NotNil(t, updated.Status.OperationStatus("policy").LastAppliedTime)
+
+	// nothing is applied again as long as Elasticsearch is in the expected state
+	_, updated = reconcileConfig(t, r)
+	require.Equal(t, esconfigv1.ReadyPhase, updated.Status.Phase)
+	require.Len(t, esClient.requests, 3)
+	require.Empty(t, recorder.Events)
+
+	// the ILM policy is deleted by a user: it is applied again
+	delete(esClient.resources, "/_ilm/policy/logs")
+	_, updated = reconcileConfig(t, r)
+	require.Equal(t, esconfigv1.ReadyPhase, updated.Status.Phase)
+	require.Equal(t, []request{{http.MethodPut, "/_ilm/policy/logs"}}, esClient.requests[3:])
+	require.NotNil(t, updated.Status.OperationStatus("policy").LastDriftTime)
+	require.Nil(t, updated.Status.OperationStatus("template").LastDriftTime)
+	require.Equal(t, "Warning DriftDetected Elasticsearch drifted from the expected state of operation policy, applying it again", <-recorder.Events)
+
+	// the index template is changed: it is applied again, the deleted template is not deleted again
+	updated.Spec.Operations[1].Body.Data["priority"] = float64(200)
+	require.NoError(t, r.Client.Update(context.Background(), &updated))
+	_, updated = reconcileConfig(t, r)
+	require.Equal(t, esconfigv1.ReadyPhase, updated.Status.Phase)
+	require.Equal(t, []request{{http.MethodPut, "/_index_template/logs"}}, esClient.requests[4:])
+	require.Equal(t, map[string]interface{}{"index_patterns": []interface{}{"logs-*"}, "priority": float64(200)}, esClient.resources["/_index_template/logs"])
+}
+
+func Test_isInExpectedState(t *testing.T) {
+	esClient := newFakeEsClient()
+	esClient.resources["/_ilm/policy/logs"] = map[string]interface{}{
+		"logs": map[string]interface{}{
+			"version": float64(1),
+			"policy":  map[string]interface{}{"phases": map[string]interface{}{"hot": map[string]interface{}{"min_age": "0ms"}}},
+		},
+	}
+	tests := []struct {
+		name  string
+		op    esconfigv1.Operation
+		want  bool
+		state *esconfigv1.ExpectedState
+	}{
+		{
+			name:  "resource exists",
+			op:    esconfigv1.Operation{Method: http.MethodPut, Path: "/_ilm/policy/logs"},
+			state: &esconfigv1.ExpectedState{},
+			want:  true,
+		},
+		{
+			name:  "resource does not exist",
+			op:    esconfigv1.Operation{Method: http.MethodPut, Path: "/_ilm/policy/other"},
+			state: &esconfigv1.ExpectedState{},
+			want:  false,
+		},
+		{
+			name: "body is a subset of the response",
+			op:   esconfigv1.Operation{Method: http.MethodPut, Path: "/_ilm/policy/logs"},
+			state: &esconfigv1.ExpectedState{Body: body(map[string]interface{}{
+				"logs": map[string]interface{}{"policy": map[string]interface{}{"phases": map[string]interface{}{"hot": map[string]interface{}{}}}},
+			})},
+			want: true,
+		},
+		{
+			name: "body is not a subset of the response",
+			op:   esconfigv1.Operation{Method: http.MethodPut, Path: "/_ilm/policy/logs"},
+			state: &esconfigv1.ExpectedState{Body: body(map[string]interface{}{
+				"logs": map[string]interface{}{"version": 2},
+			})},
+			want: false,
+		},
+		{
+			name:  "expected state path",
+			op:    esconfigv1.Operation{Method: http.MethodPost, Path: "/_ilm/policy/logs/_update"},
+			state: &esconfigv1.ExpectedState{Path: "/_ilm/policy/logs", Body: body(map[string]interface{}{"logs": map[string]interface{}{"version": 1}})},
+			want:  true,
+		},
+		{
+			name:  "deleted resource",
+			op:    esconfigv1.Operation{Method: http.MethodDelete, Path: "/_ilm/policy/other"},
+			state: &esconfigv1.ExpectedState{},
+			want:  true,
+		},
+		{
+			name:  "resource to delete",
+			op:    esconfigv1.Operation{Method: http.MethodDelete, Path: "/_ilm/policy/logs"},
+			state: &esconfigv1.ExpectedState{},
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.op.ExpectedState = tt.state
+			got, err := isInExpectedState(context.Background(), esClient, tt.op)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReconcileElasticsearchConfig_ReconcileDeletedConfig(t *testing.T) {
+	esClient := newFakeEsClient()
+	r := &ReconcileElasticsearchConfig{
+		Client:           k8s.NewFakeClient(mkElasticsearch(esv1.ElasticsearchGreenHealth)),
+		accessReviewer:   rbac.NewPermissiveAccessReviewer(),
+		esClientProvider: esClient.provider(),
+		recorder:         record.NewFakeRecorder(10),
+	}
+	result, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "config"}})
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{}, result)
+	assert.Empty(t, esClient.requests)
+}
+
+// assertReadinessConditions checks that the Ready and Stalled conditions are consistent with the phase.
+func assertReadinessConditions(t *testing.T, status esconfigv1.ElasticsearchConfigStatus) {
+	t.Helper()
+	conditionStatus := func(conditionType commonv1alpha1.ConditionType) corev1.ConditionStatus {
+		index := status.Conditions.Index(conditionType)
+		require.GreaterOrEqual(t, index, 0, "missing condition %s", conditionType)
+		return status.Conditions[index].Status
+	}
+	assert.Equal(t, status.Phase == esconfigv1.ReadyPhase, conditionStatus(commonv1alpha1.ReadyCondition) == corev1.ConditionTrue)
+	assert.Equal(t, status.Phase == esconfigv1.InvalidPhase, conditionStatus(commonv1alpha1.StalledCondition) == corev1.ConditionTrue)
+}
@@ -0,0 +1,208 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package esconfig
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"go.elastic.co/apm/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esconfigv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/esconfig/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// reconcileOperations applies the given operations, in order, and returns their status in the order of AllOperations.
+// Operations are skipped while the operations they depend on are not applied.
+func (r *ReconcileElasticsearchConfig) reconcileOperations(
+	ctx context.Context,
+	esClient esclient.Client,
+	config esconfigv1.ElasticsearchConfig,
+	operations []esconfigv1.Operation,
+) []esconfigv1.OperationStatus {
+	span, ctx := apm.StartSpan(ctx, "reconcile_operations", tracing.SpanTypeApp)
+	defer span.End()
+
+	statuses := make(map[string]esconfigv1.OperationStatus, len(operations))
+	for _, op := range operations {
+		var waitingFor []string
+		for _, dependency := range op.DependsOn {
+			if statuses[dependency].Phase != esconfigv1.OperationAppliedPhase {
+				waitingFor = append(waitingFor, dependency)
+			}
+		}
+		if len(waitingFor) > 0 {
+			status := previousStatus(config, op.Name)
+			status.Phase = esconfigv1.OperationPendingPhase
+			status.Message = fmt.Sprintf("Waiting for operations %s", strings.Join(waitingFor, ", "))
+			statuses[op.Name] = status
+			continue
+		}
+		statuses[op.Name] = r.reconcileOperation(ctx, esClient, config, op)
+	}
+
+	result := make([]esconfigv1.OperationStatus, 0, len(operations))
+	for _, op := range config.Spec.AllOperations() {
+		result = append(result, statuses[op.Name])
+	}
+	return result
+}
+
+// reconcileOperation applies a single operation if needed and returns its status:
+//   - operations with an expected state are applied if Elasticsearch does not match it
+//   - operations without an expected state are applied if they were never applied as they are currently declared
+func (r *ReconcileElasticsearchConfig) reconcileOperation(
+	ctx context.Context,
+	esClient esclient.Client,
+	config esconfigv1.ElasticsearchConfig,
+	op esconfigv1.Operation,
+) esconfigv1.OperationStatus {
+	log := ulog.FromContext(ctx)
+	previous := config.Status.OperationStatus(op.Name)
+	status := previousStatus(config, op.Name)
+	status.Message = ""
+	opHash := hash.HashObject(op)
+
+	alreadyApplied := previous != nil && previous.Phase == esconfigv1.OperationAppliedPhase && previous.Hash == opHash
+	if op.ExpectedState == nil {
+		if alreadyApplied {
+			return status
+		}
+	} else {
+		inSync, err := isInExpectedState(ctx, esClient, op)
+		if err != nil {
+			status.Phase = esconfigv1.OperationFailedPhase
+			status.Message = fmt.Sprintf("Failed to get the state of the operation: %s", err.Error())
+			return status
+		}
+		if inSync {
+			status.Phase = esconfigv1.OperationAppliedPhase
+			status.Hash = opHash
+			return status
+		}
+		if alreadyApplied {
+			now := metav1.Now()
+			status.LastDriftTime = &now
+			log.Info("Elasticsearch drifted from the expected state of the operation", "operation", op.Name)
+			r.recorder.Eventf(&config, corev1.EventTypeWarning, events.EventReasonDriftDetected,
+				"Elasticsearch drifted from the expected state of operation %s, applying it again", op.Name)
+		}
+	}
+
+	log.Info("Applying operation", "operation", op.Name, "method", op.Method, "path", op.Path)
+	if err := applyOperation(ctx, esClient, op); err != nil {
+		r.recorder.Eventf(&config, corev1.EventTypeWarning, events.EventReconciliationError, "Failed to apply operation %s: %s", op.Name, err.Error())
+		status.Phase = esconfigv1.OperationFailedPhase
+		status.Message = err.Error()
+		return status
+	}
+	now := metav1.Now()
+	status.Phase = esconfigv1.OperationAppliedPhase
+	status.Hash = opHash
+	status.LastAppliedTime = &now
+	return status
+}
+
+// previousStatus returns a copy of the current status of the operation with the given name.
+func previousStatus(config esconfigv1.ElasticsearchConfig, name string) esconfigv1.OperationStatus {
+	if previous := config.Status.OperationStatus(name); previous != nil {
+		return *previous.DeepCopy()
+	}
+	return esconfigv1.OperationStatus{Name: name}
+}
+
+// isInExpectedState returns true if the state of Elasticsearch matches the expected state of the operation.
+func isInExpectedState(ctx context.Context, esClient esclient.Client, op esconfigv1.Operation) (bool, error) {
+	path := op.ExpectedState.Path
+	if path == "" {
+		path = op.Path
+	}
+	actual, err := esClient.GetResource(ctx, path)
+	if err != nil {
+		if esclient.IsNotFound(err) {
+			return op.Method == http.MethodDelete, nil
+		}
+		return false, err
+	}
+	if op.Method == http.MethodDelete {
+		return false, nil
+	}
+	if op.ExpectedState.Body == nil {
+		// the resource exists
+		return true, nil
+	}
+	actualMap, ok := actual.(map[string]interface{})
+	if !ok {
+		return false, nil
+	}
+	// deep copying the body normalizes its values the same way as the decoded response of Elasticsearch
+	return isSubset(op.ExpectedState.Body.DeepCopy().Data, actualMap), nil
+}
+
+// applyOperation sends the request of the operation to Elasticsearch. Deleting a resource which does not exist is not an error.
+func applyOperation(ctx context.Context, esClient esclient.Client, op esconfigv1.Operation) error {
+	var body interface{}
+	if op.Body != nil {
+		body = op.Body.Data
+	}
+	err := esClient.ApplyResource(ctx, op.Method, op.Path, body)
+	if err != nil && op.Method == http.MethodDelete && esclient.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// isSubset returns true if all the keys of expected exist in actual with the same values, nested objects being
+// compared recursively.
+func isSubset(expected, actual map[string]interface{}) bool {
+	for key, expectedValue := range expected {
+		actualValue, exists := actual[key]
+		if !exists {
+			return false
+		}
+		expectedMap, expectedIsMap := expectedValue.(map[string]interface{})
+		actualMap, actualIsMap := actualValue.(map[string]interface{})
+		if expectedIsMap && actualIsMap {
+			if !isSubset(expectedMap, actualMap) {
+				return false
+			}
+			continue
+		}
+		if !reflect.DeepEqual(expectedValue, actualValue) {
+			return false
+		}
+	}
+	return true
+}
+
+// failedOperations returns the names of the operations which could not be applied.
+func failedOperations(statuses []esconfigv1.OperationStatus) []string {
+	var failed []string
+	for _, status := range statuses {
+		if status.Phase == esconfigv1.OperationFailedPhase {
+			failed = append(failed, status.Name)
+		}
+	}
+	return failed
+}
+
+// hasExpectedState returns true if at least one of the operations declares an expected state.
+func hasExpectedState(operations []esconfigv1.Operation) bool {
+	for _, op := range operations {
+		if op.ExpectedState != nil {
+			return true
+		}
+	}
+	return false
+}
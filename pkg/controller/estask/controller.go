@@ -0,0 +1,242 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package estask
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"time"
+
+	"go.elastic.co/apm/v2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	estaskv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/estask/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
+	commonesclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/esclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
+)
+
+const (
+	controllerName = "estask-controller"
+)
+
+var (
+	defaultRequeue = reconcile.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+)
+
+// Add creates a new ElasticsearchTask Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, params operator.Parameters) error {
+	r := newReconciler(mgr, params)
+	c, err := common.NewController(mgr, controllerName, r, params)
+	if err != nil {
+		return err
+	}
+	return addWatches(mgr, c, r)
+}
+
+// newReconciler returns a new reconcile.Reconciler of ElasticsearchTask.
+func newReconciler(mgr manager.Manager, params operator.Parameters) *ReconcileElasticsearchTask {
+	return &ReconcileElasticsearchTask{
+		Client:           mgr.GetClient(),
+		esClientProvider: commonesclient.NewClient,
+		recorder:         mgr.GetEventRecorderFor(controllerName),
+		params:           params,
+		now:              time.Now,
+	}
+}
+
+func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileElasticsearchTask) error {
+	// watch for changes to ElasticsearchTask
+	if err := c.Watch(source.Kind(mgr.GetCache(), &estaskv1alpha1.ElasticsearchTask{}, &handler.TypedEnqueueRequestForObject[*estaskv1alpha1.ElasticsearchTask]{})); err != nil {
+		return err
+	}
+
+	// watch for changes to Elasticsearch, to start the tasks waiting for their cluster to be available
+	return c.Watch(source.Kind[client.Object](mgr.GetCache(), &esv1.Elasticsearch{}, reconcileRequestsForElasticsearch(r.Client)))
+}
+
+// reconcileRequestsForElasticsearch returns the requests to reconcile the ElasticsearchTasks of an Elasticsearch
+// cluster which are not started yet.
+func reconcileRequestsForElasticsearch(clnt k8s.Client) handler.TypedEventHandler[client.Object, reconcile.Request] {
+	return handler.TypedEnqueueRequestsFromMapFunc[client.Object](func(ctx context.Context, es client.Object) []reconcile.Request {
+		var tasks estaskv1alpha1.ElasticsearchTaskList
+		if err := clnt.List(ctx, &tasks, client.InNamespace(es.GetNamespace())); err != nil {
+			ulog.Log.Error(err, "Fail to list ElasticsearchTaskList while watching Elasticsearch")
+			return nil
+		}
+		var requests []reconcile.Request
+		for _, task := range tasks.Items {
+			if task.Spec.ElasticsearchRef.Name != es.GetName() || task.Status.StartTime != nil {
+				continue
+			}
+			requests = append(requests, reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(&task)})
+		}
+		return requests
+	})
+}
+
+var _ reconcile.Reconciler = &ReconcileElasticsearchTask{}
+
+// ReconcileElasticsearchTask reconciles an ElasticsearchTask object
+type ReconcileElasticsearchTask struct {
+	k8s.Client
+	esClientProvider commonesclient.Provider
+	recorder         record.EventRecorder
+	params           operator.Parameters
+	now              func() time.Time
+	// iteration is the number of times this controller has run its Reconcile method
+	iteration uint64
+}
+
+// Reconcile reads that state of the cluster for an ElasticsearchTask object and runs its operation once on the
+// Elasticsearch cluster, then records the outcome in its status. Completed tasks are kept as a record of the operations
+// run on the cluster until they are deleted.
+func (r *ReconcileElasticsearchTask) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	ctx = common.NewReconciliationContext(ctx, &r.iteration, r.params.Tracer, controllerName, "estask_name", request)
+	defer common.LogReconciliationRun(ulog.FromContext(ctx))()
+	defer tracing.EndContextTransaction(ctx)
+
+	var task estaskv1alpha1.ElasticsearchTask
+	if err := r.Client.Get(ctx, request.NamespacedName, &task); err != nil {
+		if apierrors.IsNotFound(err) {
+			metrics.ForgetReconciledResource(ctx)
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
+	}
+
+	// skip unmanaged resources
+	if common.IsUnmanaged(ctx, &task) {
+		ulog.FromContext(ctx).Info("Object is currently not managed by this controller. Skipping reconciliation")
+		return reconcile.Result{}, tracing.CaptureError(ctx, common.ReconcilePausedCondition(ctx, r.Client, &task, &task.Status.Conditions, true))
+	}
+	if err := common.ReconcilePausedCondition(ctx, r.Client, &task, &task.Status.Conditions, false); err != nil {
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
+	}
+
+	if task.IsMarkedForDeletion() || task.Status.IsCompleted() {
+		return reconcile.Result{}, nil
+	}
+
+	result, status, err := r.doReconcile(ctx, &task)
+	if err != nil {
+		// errors not related to the outcome of the task, such as the cluster being unreachable, are retried
+		status.Message = err.Error()
+	}
+
+	if updateErr := r.updateStatus(ctx, &task, status); updateErr != nil {
+		if apierrors.IsConflict(updateErr) {
+			return reconcile.Result{Requeue: true}, nil
+		}
+		return reconcile.Result{}, tracing.CaptureError(ctx, updateErr)
+	}
+	return result, tracing.CaptureError(ctx, err)
+}
+
+func (r *ReconcileElasticsearchTask) doReconcile(ctx context.Context, task *estaskv1alpha1.ElasticsearchTask) (reconcile.Result, estaskv1alpha1.ElasticsearchTaskStatus, error) {
+	status := *task.Status.DeepCopy()
+	status.ObservedGeneration = task.Generation
+
+	// run validation in case the webhook is disabled
+	if err := task.Validate(); err != nil {
+		r.recorder.Eventf(task, corev1.EventTypeWarning, events.EventReasonValidation, err.Error())
+		status.Phase = estaskv1alpha1.InvalidPhase
+		status.Message = err.Error()
+		// the resource must be updated by the user, no need to requeue
+		return reconcile.Result{}, status, nil
+	}
+
+	esRef := task.ElasticsearchRef()
+	var es esv1.Elasticsearch
+	if err := r.Client.Get(ctx, esRef, &es); err != nil {
+		if apierrors.IsNotFound(err) {
+			status.Phase = estaskv1alpha1.PendingPhase
+			status.Message = fmt.Sprintf("Elasticsearch %s/%s does not exist", esRef.Namespace, esRef.Name)
+			return defaultRequeue, status, nil
+		}
+		return reconcile.Result{}, status, err
+	}
+	// a red cluster is available to run tasks, such as retrying the allocation of its shards
+	if es.Status.Health == "" || es.Status.Health == esv1.ElasticsearchUnknownHealth {
+		status.Phase = estaskv1alpha1.PendingPhase
+		status.Message = fmt.Sprintf("Elasticsearch %s/%s is not available", es.Namespace, es.Name)
+		return defaultRequeue, status, nil
+	}
+
+	esClient, err := r.esClientProvider(ctx, r.Client, r.params.Dialer, es)
+	if err != nil {
+		return reconcile.Result{}, status, err
+	}
+	defer esClient.Close()
+
+	if status.Phase == estaskv1alpha1.RunningPhase && status.ElasticsearchTaskID != "" {
+		return r.reconcileRunningTask(ctx, esClient, task, &es, &status)
+	}
+	if status.Phase == estaskv1alpha1.RunningPhase && task.Spec.Type == estaskv1alpha1.SnapshotNowTask {
+		// the operator stopped before recording the outcome of the task, which is not idempotent
+		return reconcile.Result{}, r.complete(ctx, task, &es, status, fmt.Errorf("the outcome of the task is unknown as the operator stopped while running it")), nil
+	}
+	return r.startTask(ctx, esClient, task, &es, &status)
+}
+
+// complete records the outcome of the task in the given status, which is returned.
+func (r *ReconcileElasticsearchTask) complete(
+	ctx context.Context,
+	task *estaskv1alpha1.ElasticsearchTask,
+	es *esv1.Elasticsearch,
+	status estaskv1alpha1.ElasticsearchTaskStatus,
+	taskErr error,
+) estaskv1alpha1.ElasticsearchTaskStatus {
+	status.CompletionTime = &metav1.Time{Time: r.now()}
+	log := ulog.FromContext(ctx).WithValues("namespace", task.Namespace, "estask_name", task.Name, "type", task.Spec.Type, "es_name", es.Name)
+	if taskErr != nil {
+		status.Phase = estaskv1alpha1.FailedPhase
+		status.Message = taskErr.Error()
+		log.Info("Elasticsearch task failed", "error", taskErr.Error())
+		r.recorder.Eventf(task, corev1.EventTypeWarning, events.EventReasonTaskFailed, "%s task failed: %s", task.Spec.Type, taskErr.Error())
+		r.recorder.Eventf(es, corev1.EventTypeWarning, events.EventReasonTaskFailed, "%s task requested by ElasticsearchTask %s failed", task.Spec.Type, task.Name)
+		return status
+	}
+	status.Phase = estaskv1alpha1.SucceededPhase
+	status.Message = ""
+	log.Info("Elasticsearch task succeeded")
+	r.recorder.Eventf(task, corev1.EventTypeNormal, events.EventReasonTaskSucceeded, "%s task succeeded", task.Spec.Type)
+	r.recorder.Eventf(es, corev1.EventTypeNormal, events.EventReasonTaskSucceeded, "%s task requested by ElasticsearchTask %s succeeded", task.Spec.Type, task.Name)
+	return status
+}
+
+func (r *ReconcileElasticsearchTask) updateStatus(ctx context.Context, task *estaskv1alpha1.ElasticsearchTask, status estaskv1alpha1.ElasticsearchTaskStatus) error {
+	span, _ := apm.StartSpan(ctx, "update_status", tracing.SpanTypeApp)
+	defer span.End()
+
+	status.Conditions = task.Status.Conditions.WithReadinessConditions(status.ResourceState(), metav1.Now())
+	if reflect.DeepEqual(status, task.Status) {
+		return nil // nothing to do
+	}
+	ulog.FromContext(ctx).V(1).Info("Updating status",
+		"iteration", atomic.LoadUint64(&r.iteration),
+		"status", status,
+	)
+	task.Status = status
+	return common.UpdateStatus(ctx, r.Client, task)
+}
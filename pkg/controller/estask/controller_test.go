@@ -0,0 +1,283 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package estask
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	estaskv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/estask/v1alpha1"
+	commonesclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/esclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+var now = time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+
+type fakeEsClient struct {
+	esclient.Client
+
+	version    version.Version
+	err        error
+	calls      []string
+	taskStatus esclient.TaskStatus
+}
+
+func (c *fakeEsClient) provider() commonesclient.Provider {
+	return func(_ context.Context, _ k8s.Client, _ net.Dialer, _ esv1.Elasticsearch) (esclient.Client, error) {
+		return c, nil
+	}
+}
+
+func (c *fakeEsClient) Version() version.Version {
+	return c.version
+}
+
+func (c *fakeEsClient) RetryFailedShardAllocations(_ context.Context) error {
+	c.calls = append(c.calls, "reroute")
+	return c.err
+}
+
+func (c *fakeEsClient) ClearCache(_ context.Context, _ []string) error {
+	c.calls = append(c.calls, "clear-cache")
+	return c.err
+}
+
+func (c *fakeEsClient) StartForceMerge(_ context.Context, _ []string, _ *int32) (string, error) {
+	c.calls = append(c.calls, "forcemerge")
+	return "node:1", c.err
+}
+
+func (c *fakeEsClient) GetTask(_ context.Context, _ string) (esclient.TaskStatus, error) {
+	return c.taskStatus, c.err
+}
+
+func (c *fakeEsClient) ExecuteSLMPolicy(_ context.Context, _ string) (string, error) {
+	c.calls = append(c.calls, "slm")
+	return "nightly-snap-2024.10.01", c.err
+}
+
+func (c *fakeEsClient) Close() {}
+
+func mkTask(taskType estaskv1alpha1.TaskType) *estaskv1alpha1.ElasticsearchTask {
+	task := &estaskv1alpha1.ElasticsearchTask{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "task", Generation: 1},
+		Spec: estaskv1alpha1.ElasticsearchTaskSpec{
+			ElasticsearchRef: estaskv1alpha1.ElasticsearchRef{Name: "es"},
+			Type:             taskType,
+		},
+	}
+	if taskType == estaskv1alpha1.SnapshotNowTask {
+		task.Spec.Policy = "nightly"
+	}
+	return task
+}
+
+func mkES(health esv1.ElasticsearchHealth) *esv1.Elasticsearch {
+	return &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Status:     esv1.ElasticsearchStatus{Health: health},
+	}
+}
+
+func newTestReconciler(esClient *fakeEsClient, objects ...client.Object) *ReconcileElasticsearchTask {
+	return &ReconcileElasticsearchTask{
+		Client:           k8s.NewFakeClient(objects...),
+		esClientProvider: esClient.provider(),
+		recorder:         record.NewFakeRecorder(100),
+		now:              func() time.Time { return now },
+	}
+}
+
+func TestReconcileElasticsearchTask(t *testing.T) {
+	badRequest := &esclient.APIError{StatusCode: 400}
+	tests := []struct {
+		name        string
+		task        *estaskv1alpha1.ElasticsearchTask
+		es          *esv1.Elasticsearch
+		esClient    *fakeEsClient
+		wantPhase   estaskv1alpha1.TaskPhase
+		wantCalls   []string
+		wantRequeue bool
+		wantErr     bool
+		wantStatus  func(t *testing.T, status estaskv1alpha1.ElasticsearchTaskStatus)
+	}{
+		{
+			name:        "Elasticsearch does not exist",
+			task:        mkTask(estaskv1alpha1.RetryFailedAllocationsTask),
+			esClient:    &fakeEsClient{},
+			wantPhase:   estaskv1alpha1.PendingPhase,
+			wantRequeue: true,
+		},
+		{
+			name:        "Elasticsearch not available",
+			task:        mkTask(estaskv1alpha1.RetryFailedAllocationsTask),
+			es:          mkES(esv1.ElasticsearchUnknownHealth),
+			esClient:    &fakeEsClient{},
+			wantPhase:   estaskv1alpha1.PendingPhase,
+			wantRequeue: true,
+		},
+		{
+			name:      "retry failed allocations on a red cluster",
+			task:      mkTask(estaskv1alpha1.RetryFailedAllocationsTask),
+			es:        mkES(esv1.ElasticsearchRedHealth),
+			esClient:  &fakeEsClient{version: version.MustParse("8.15.0")},
+			wantPhase: estaskv1alpha1.SucceededPhase,
+			wantCalls: []string{"reroute"},
+			wantStatus: func(t *testing.T, status estaskv1alpha1.ElasticsearchTaskStatus) {
+				t.Helper()
+				require.Equal(t, now, status.StartTime.Time.UTC())
+				require.Equal(t, now, status.CompletionTime.Time.UTC())
+			},
+		},
+		{
+			name: "invalid task",
+			task: func() *estaskv1alpha1.ElasticsearchTask {
+				t := mkTask(estaskv1alpha1.ClearCacheTask)
+				t.Spec.Policy = "nightly"
+				return t
+			}(),
+			es:        mkES(esv1.ElasticsearchGreenHealth),
+			esClient:  &fakeEsClient{version: version.MustParse("8.15.0")},
+			wantPhase: estaskv1alpha1.InvalidPhase,
+		},
+		{
+			name:      "Elasticsearch rejects the request",
+			task:      mkTask(estaskv1alpha1.ClearCacheTask),
+			es:        mkES(esv1.ElasticsearchGreenHealth),
+			esClient:  &fakeEsClient{version: version.MustParse("8.15.0"), err: badRequest},
+			wantPhase: estaskv1alpha1.FailedPhase,
+			wantCalls: []string{"clear-cache"},
+		},
+		{
+			name:      "Elasticsearch unreachable",
+			task:      mkTask(estaskv1alpha1.ClearCacheTask),
+			es:        mkES(esv1.ElasticsearchGreenHealth),
+			esClient:  &fakeEsClient{version: version.MustParse("8.15.0"), err: errors.New("connection refused")},
+			wantPhase: estaskv1alpha1.RunningPhase,
+			wantCalls: []string{"clear-cache"},
+			wantErr:   true,
+		},
+		{
+			name:      "snapshot now",
+			task:      mkTask(estaskv1alpha1.SnapshotNowTask),
+			es:        mkES(esv1.ElasticsearchGreenHealth),
+			esClient:  &fakeEsClient{version: version.MustParse("8.15.0")},
+			wantPhase: estaskv1alpha1.SucceededPhase,
+			wantCalls: []string{"slm"},
+			wantStatus: func(t *testing.T, status estaskv1alpha1.ElasticsearchTaskStatus) {
+				t.Helper()
+				require.Equal(t, "nightly-snap-2024.10.01", status.Snapshot)
+			},
+		},
+		{
+			name: "snapshot now interrupted",
+			task: func() *estaskv1alpha1.ElasticsearchTask {
+				t := mkTask(estaskv1alpha1.SnapshotNowTask)
+				t.Status.Phase = estaskv1alpha1.RunningPhase
+				return t
+			}(),
+			es:        mkES(esv1.ElasticsearchGreenHealth),
+			esClient:  &fakeEsClient{version: version.MustParse("8.15.0")},
+			wantPhase: estaskv1alpha1.FailedPhase,
+		},
+		{
+			name:      "snapshot now not supported",
+			task:      mkTask(estaskv1alpha1.SnapshotNowTask),
+			es:        mkES(esv1.ElasticsearchGreenHealth),
+			esClient:  &fakeEsClient{version: version.MustParse("7.3.2")},
+			wantPhase: estaskv1alpha1.FailedPhase,
+		},
+		{
+			name:        "force merge started",
+			task:        mkTask(estaskv1alpha1.ForceMergeTask),
+			es:          mkES(esv1.ElasticsearchGreenHealth),
+			esClient:    &fakeEsClient{version: version.MustParse("8.15.0")},
+			wantPhase:   estaskv1alpha1.RunningPhase,
+			wantCalls:   []string{"forcemerge"},
+			wantRequeue: true,
+			wantStatus: func(t *testing.T, status estaskv1alpha1.ElasticsearchTaskStatus) {
+				t.Helper()
+				require.Equal(t, "node:1", status.ElasticsearchTaskID)
+				require.Nil(t, status.CompletionTime)
+			},
+		},
+		{
+			name: "force merge failed",
+			task: func() *estaskv1alpha1.ElasticsearchTask {
+				t := mkTask(estaskv1alpha1.ForceMergeTask)
+				t.Status.Phase = estaskv1alpha1.RunningPhase
+				t.Status.ElasticsearchTaskID = "node:1"
+				return t
+			}(),
+			es: mkES(esv1.ElasticsearchGreenHealth),
+			esClient: &fakeEsClient{
+				version:    version.MustParse("8.15.0"),
+				taskStatus: esclient.TaskStatus{Completed: true, Error: json.RawMessage(`{"type":"index_not_found_exception"}`)},
+			},
+			wantPhase: estaskv1alpha1.FailedPhase,
+			wantStatus: func(t *testing.T, status estaskv1alpha1.ElasticsearchTaskStatus) {
+				t.Helper()
+				require.Contains(t, status.Message, "index_not_found_exception")
+			},
+		},
+		{
+			name: "force merge completed",
+			task: func() *estaskv1alpha1.ElasticsearchTask {
+				t := mkTask(estaskv1alpha1.ForceMergeTask)
+				t.Status.Phase = estaskv1alpha1.RunningPhase
+				t.Status.ElasticsearchTaskID = "node:1"
+				return t
+			}(),
+			es:        mkES(esv1.ElasticsearchGreenHealth),
+			esClient:  &fakeEsClient{version: version.MustParse("8.15.0"), taskStatus: esclient.TaskStatus{Completed: true}},
+			wantPhase: estaskv1alpha1.SucceededPhase,
+		},
+		{
+			name: "completed tasks are not run again",
+			task: func() *estaskv1alpha1.ElasticsearchTask {
+				t := mkTask(estaskv1alpha1.ClearCacheTask)
+				t.Status.Phase = estaskv1alpha1.SucceededPhase
+				return t
+			}(),
+			es:        mkES(esv1.ElasticsearchGreenHealth),
+			esClient:  &fakeEsClient{version: version.MustParse("8.15.0")},
+			wantPhase: estaskv1alpha1.SucceededPhase,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects := []client.Object{tt.task}
+			if tt.es != nil {
+				objects = append(objects, tt.es)
+			}
+			r := newTestReconciler(tt.esClient, objects...)
+			nsn := types.NamespacedName{Namespace: "ns", Name: "task"}
+			result, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: nsn})
+			var task estaskv1alpha1.ElasticsearchTask
+			require.NoError(t, r.Client.Get(context.Background(), nsn, &task))
+			require.Equal(t, tt.wantErr, err != nil, err)
+			require.Equal(t, tt.wantRequeue, result.RequeueAfter > 0)
+			require.Equal(t, tt.wantPhase, task.Status.Phase, task.Status.Message)
+			require.Equal(t, tt.wantCalls, tt.esClient.calls)
+			if tt.wantStatus != nil {
+				tt.wantStatus(t, task.Status)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package estask
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	estaskv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/estask/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// minVersions are the minimum Elasticsearch versions supporting the task types which are not supported by all the
+// versions managed by the operator.
+var minVersions = map[estaskv1alpha1.TaskType]version.Version{
+	estaskv1alpha1.ForceMergeTask:  version.MinFor(7, 7, 0),
+	estaskv1alpha1.SnapshotNowTask: version.MinFor(7, 4, 0),
+}
+
+// startTask runs the operation of the task on the Elasticsearch cluster. The task is recorded as running before the
+// operation is started, so that an operation which is not idempotent is never run twice.
+func (r *ReconcileElasticsearchTask) startTask(
+	ctx context.Context,
+	esClient esclient.Client,
+	task *estaskv1alpha1.ElasticsearchTask,
+	es *esv1.Elasticsearch,
+	status *estaskv1alpha1.ElasticsearchTaskStatus,
+) (reconcile.Result, estaskv1alpha1.ElasticsearchTaskStatus, error) {
+	if minVersion, ok := minVersions[task.Spec.Type]; ok && esClient.Version().LT(minVersion) {
+		return reconcile.Result{}, r.complete(ctx, task, es, *status,
+			fmt.Errorf("%s tasks require Elasticsearch %s or later, cluster runs %s", task.Spec.Type, minVersion, esClient.Version())), nil
+	}
+
+	status.Phase = estaskv1alpha1.RunningPhase
+	status.Message = ""
+	status.StartTime = &metav1.Time{Time: r.now()}
+	if err := r.updateStatus(ctx, task, *status); err != nil {
+		return reconcile.Result{}, *status, err
+	}
+	ulog.FromContext(ctx).Info("Starting Elasticsearch task",
+		"namespace", task.Namespace, "estask_name", task.Name, "type", task.Spec.Type, "es_name", es.Name)
+	r.recorder.Eventf(task, corev1.EventTypeNormal, events.EventReasonTaskStarted, "%s task started on Elasticsearch %s", task.Spec.Type, es.Name)
+	r.recorder.Eventf(es, corev1.EventTypeNormal, events.EventReasonTaskStarted, "%s task requested by ElasticsearchTask %s started", task.Spec.Type, task.Name)
+
+	var err error
+	switch task.Spec.Type {
+	case estaskv1alpha1.RetryFailedAllocationsTask:
+		err = esClient.RetryFailedShardAllocations(ctx)
+	case estaskv1alpha1.ClearCacheTask:
+		err = esClient.ClearCache(ctx, task.Spec.Indices)
+	case estaskv1alpha1.ForceMergeTask:
+		var taskID string
+		taskID, err = esClient.StartForceMerge(ctx, task.Spec.Indices, task.Spec.MaxNumSegments)
+		if err == nil {
+			// the force merge runs in the background in Elasticsearch, wait for its completion
+			status.ElasticsearchTaskID = taskID
+			return defaultRequeue, *status, nil
+		}
+	case estaskv1alpha1.SnapshotNowTask:
+		status.Snapshot, err = esClient.ExecuteSLMPolicy(ctx, task.Spec.Policy)
+	}
+	if err != nil && !esclient.Is4xx(err) {
+		// the request may not have reached Elasticsearch, retry it
+		return reconcile.Result{}, *status, err
+	}
+	return reconcile.Result{}, r.complete(ctx, task, es, *status, err), nil
+}
+
+// reconcileRunningTask checks the completion of the Elasticsearch task started by the given task.
+func (r *ReconcileElasticsearchTask) reconcileRunningTask(
+	ctx context.Context,
+	esClient esclient.Client,
+	task *estaskv1alpha1.ElasticsearchTask,
+	es *esv1.Elasticsearch,
+	status *estaskv1alpha1.ElasticsearchTaskStatus,
+) (reconcile.Result, estaskv1alpha1.ElasticsearchTaskStatus, error) {
+	taskStatus, err := esClient.GetTask(ctx, status.ElasticsearchTaskID)
+	if err != nil {
+		if esclient.IsNotFound(err) {
+			// the task is forgotten by Elasticsearch, for example after the restart of the node running it
+			return reconcile.Result{}, r.complete(ctx, task, es, *status,
+				fmt.Errorf("task %s not found in Elasticsearch, its outcome is unknown", status.ElasticsearchTaskID)), nil
+		}
+		return reconcile.Result{}, *status, err
+	}
+	if !taskStatus.Completed {
+		return defaultRequeue, *status, nil
+	}
+	if len(taskStatus.Error) > 0 {
+		return reconcile.Result{}, r.complete(ctx, task, es, *status,
+			fmt.Errorf("task %s failed in Elasticsearch: %s", status.ElasticsearchTaskID, string(taskStatus.Error))), nil
+	}
+	return reconcile.Result{}, r.complete(ctx, task, es, *status, nil), nil
+}
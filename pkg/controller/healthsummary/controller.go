@@ -0,0 +1,174 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package healthsummary
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	agentv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/agent/v1alpha1"
+	apmv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/apm/v1"
+	beatv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/beat/v1beta1"
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	entv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/enterprisesearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	logstashv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+	emsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/maps/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+const (
+	name = "healthsummary-controller"
+
+	// ConfigMapName is the name of the ConfigMap summarizing the Elastic resources of a namespace.
+	ConfigMapName = "elastic-health-summary"
+	// Type is the type label value of the health summary ConfigMaps.
+	Type = "health-summary"
+	// ResourcesKey is the key of the ConfigMap holding the JSON list of the summaries of the Elastic resources.
+	ResourcesKey = "resources.json"
+)
+
+// Add creates a new health summary controller and adds it to the manager.
+func Add(mgr manager.Manager, params operator.Parameters) error {
+	r := &ReconcileHealthSummary{
+		Client:     mgr.GetClient(),
+		Parameters: params,
+	}
+	c, err := common.NewController(mgr, name, r, params)
+	if err != nil {
+		return err
+	}
+	return addWatches(mgr, c)
+}
+
+// addWatches enqueues the summary of the namespace of any Elastic resource, or of the summary itself, which changed.
+func addWatches(mgr manager.Manager, c controller.Controller) error {
+	for _, src := range []source.Source{
+		source.Kind(mgr.GetCache(), &esv1.Elasticsearch{}, handler.TypedEnqueueRequestsFromMapFunc(toSummaryRequest[*esv1.Elasticsearch])),
+		source.Kind(mgr.GetCache(), &kbv1.Kibana{}, handler.TypedEnqueueRequestsFromMapFunc(toSummaryRequest[*kbv1.Kibana])),
+		source.Kind(mgr.GetCache(), &apmv1.ApmServer{}, handler.TypedEnqueueRequestsFromMapFunc(toSummaryRequest[*apmv1.ApmServer])),
+		source.Kind(mgr.GetCache(), &entv1.EnterpriseSearch{}, handler.TypedEnqueueRequestsFromMapFunc(toSummaryRequest[*entv1.EnterpriseSearch])),
+		source.Kind(mgr.GetCache(), &beatv1beta1.Beat{}, handler.TypedEnqueueRequestsFromMapFunc(toSummaryRequest[*beatv1beta1.Beat])),
+		source.Kind(mgr.GetCache(), &agentv1alpha1.Agent{}, handler.TypedEnqueueRequestsFromMapFunc(toSummaryRequest[*agentv1alpha1.Agent])),
+		source.Kind(mgr.GetCache(), &emsv1alpha1.ElasticMapsServer{}, handler.TypedEnqueueRequestsFromMapFunc(toSummaryRequest[*emsv1alpha1.ElasticMapsServer])),
+		source.Kind(mgr.GetCache(), &logstashv1alpha1.Logstash{}, handler.TypedEnqueueRequestsFromMapFunc(toSummaryRequest[*logstashv1alpha1.Logstash])),
+		// restore the summary if it is modified or deleted
+		source.Kind(mgr.GetCache(), &corev1.ConfigMap{}, handler.TypedEnqueueRequestsFromMapFunc(
+			func(ctx context.Context, cm *corev1.ConfigMap) []reconcile.Request {
+				if cm.Name != ConfigMapName {
+					return nil
+				}
+				return toSummaryRequest(ctx, cm)
+			},
+		)),
+	} {
+		if err := c.Watch(src); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func toSummaryRequest[T client.Object](_ context.Context, obj T) []reconcile.Request {
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: ConfigMapName}}}
+}
+
+var _ reconcile.Reconciler = &ReconcileHealthSummary{}
+
+// ReconcileHealthSummary maintains, in each namespace, a ConfigMap listing the version, health and phase of the Elastic
+// resources of the namespace, and reports them as Prometheus info metrics.
+type ReconcileHealthSummary struct {
+	k8s.Client
+	operator.Parameters
+
+	// iteration is the number of times this controller has run its Reconcile method
+	iteration uint64
+}
+
+// Reconcile updates the health summary of the namespace of the request.
+func (r *ReconcileHealthSummary) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	ctx = common.NewReconciliationContext(ctx, &r.iteration, r.Tracer, name, "configmap_name", request)
+	defer common.LogReconciliationRun(ulog.FromContext(ctx))()
+	defer tracing.EndContextTransaction(ctx)
+
+	summaries, err := summarize(ctx, r.Client, request.Namespace)
+	if err != nil {
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
+	}
+	reportAsMetrics(request.Namespace, summaries)
+
+	if len(summaries) == 0 {
+		return reconcile.Result{}, tracing.CaptureError(ctx, deleteSummary(ctx, r.Client, request.NamespacedName))
+	}
+	return reconcile.Result{}, tracing.CaptureError(ctx, reconcileSummary(ctx, r.Client, request.NamespacedName, summaries))
+}
+
+// reconcileSummary creates or updates the ConfigMap holding the given summaries.
+func reconcileSummary(ctx context.Context, c k8s.Client, nsn types.NamespacedName, summaries []ResourceSummary) error {
+	resources, err := json.Marshal(summaries)
+	if err != nil {
+		return err
+	}
+	expected := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: nsn.Namespace,
+			Name:      nsn.Name,
+			Labels: map[string]string{
+				commonv1.TypeLabelName: Type,
+			},
+		},
+		Data: map[string]string{
+			ResourcesKey: string(resources),
+		},
+	}
+	reconciled := &corev1.ConfigMap{}
+	return reconciler.ReconcileResource(reconciler.Params{
+		Context:    ctx,
+		Client:     c,
+		Expected:   &expected,
+		Reconciled: reconciled,
+		NeedsUpdate: func() bool {
+			return !reflect.DeepEqual(expected.Data, reconciled.Data)
+		},
+		UpdateReconciled: func() {
+			reconciled.Data = expected.Data
+		},
+	})
+}
+
+// deleteSummary deletes the ConfigMap of a namespace which does not hold any Elastic resource anymore.
+func deleteSummary(ctx context.Context, c k8s.Client, nsn types.NamespacedName) error {
+	var cm corev1.ConfigMap
+	if err := c.Get(ctx, nsn, &cm); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if cm.Labels[commonv1.TypeLabelName] != Type {
+		// not created by the operator
+		return nil
+	}
+	ulog.FromContext(ctx).Info("Deleting health summary", "namespace", nsn.Namespace, "configmap_name", nsn.Name)
+	return client.IgnoreNotFound(c.Delete(ctx, &cm))
+}
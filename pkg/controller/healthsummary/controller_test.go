@@ -0,0 +1,101 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package healthsummary
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
+)
+
+func request(namespace string) reconcile.Request {
+	return reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: ConfigMapName}}
+}
+
+func TestReconcileHealthSummary_Reconcile(t *testing.T) {
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "es"},
+		Status: esv1.ElasticsearchStatus{
+			Version: "8.15.0",
+			Health:  esv1.ElasticsearchGreenHealth,
+			Phase:   esv1.ElasticsearchReadyPhase,
+		},
+	}
+	kb := kbv1.Kibana{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "kb"},
+		Status: kbv1.KibanaStatus{DeploymentStatus: commonv1.DeploymentStatus{
+			Version: "8.15.0",
+			Health:  commonv1.RedHealth,
+		}},
+	}
+	other := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns2", Name: "other"}}
+	c := k8s.NewFakeClient(&es, &kb, &other)
+	r := &ReconcileHealthSummary{Client: c}
+	ctx := context.Background()
+
+	// the summary lists the resources of the namespace
+	_, err := r.Reconcile(ctx, request("ns1"))
+	require.NoError(t, err)
+	var cm corev1.ConfigMap
+	require.NoError(t, c.Get(ctx, request("ns1").NamespacedName, &cm))
+	require.Equal(t, Type, cm.Labels[commonv1.TypeLabelName])
+	var summaries []ResourceSummary
+	require.NoError(t, json.Unmarshal([]byte(cm.Data[ResourcesKey]), &summaries))
+	require.Equal(t, []ResourceSummary{
+		{Kind: esv1.Kind, Name: "es", Version: "8.15.0", Health: "green", Phase: "Ready"},
+		{Kind: kbv1.Kind, Name: "kb", Version: "8.15.0", Health: "red"},
+	}, summaries)
+	require.NoError(t, testutil.CollectAndCompare(metrics.ResourceInfoGauge, strings.NewReader(`
+# HELP elastic_resource_info Information about an Elastic resource managed by the operator, the value is always 1
+# TYPE elastic_resource_info gauge
+elastic_resource_info{health="green",kind="Elasticsearch",name="es",namespace="ns1",phase="Ready",version="8.15.0"} 1
+elastic_resource_info{health="red",kind="Kibana",name="kb",namespace="ns1",phase="",version="8.15.0"} 1
+`)))
+
+	// the summary and the metrics follow the status of the resources
+	kb.Status.Health = commonv1.GreenHealth
+	require.NoError(t, c.Status().Update(ctx, &kb))
+	_, err = r.Reconcile(ctx, request("ns1"))
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, request("ns1").NamespacedName, &cm))
+	require.Contains(t, cm.Data[ResourcesKey], `{"kind":"Kibana","name":"kb","version":"8.15.0","health":"green"}`)
+	require.Equal(t, 2, testutil.CollectAndCount(metrics.ResourceInfoGauge, "elastic_resource_info"))
+
+	// the summary is deleted with the last resource of the namespace
+	require.NoError(t, c.Delete(ctx, &es))
+	require.NoError(t, c.Delete(ctx, &kb))
+	_, err = r.Reconcile(ctx, request("ns1"))
+	require.NoError(t, err)
+	require.True(t, apierrors.IsNotFound(c.Get(ctx, request("ns1").NamespacedName, &cm)))
+	require.Equal(t, 0, testutil.CollectAndCount(metrics.ResourceInfoGauge, "elastic_resource_info"))
+}
+
+func Test_deleteSummary(t *testing.T) {
+	userConfigMap := corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: ConfigMapName}}
+	c := k8s.NewFakeClient(&userConfigMap)
+	nsn := types.NamespacedName{Namespace: "ns", Name: ConfigMapName}
+
+	// ConfigMaps not created by the operator are left untouched
+	require.NoError(t, deleteSummary(context.Background(), c, nsn))
+	require.NoError(t, c.Get(context.Background(), nsn, &corev1.ConfigMap{}))
+
+	// nothing to delete
+	require.NoError(t, deleteSummary(context.Background(), k8s.NewFakeClient(), nsn))
+}
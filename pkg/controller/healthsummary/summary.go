@@ -0,0 +1,142 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package healthsummary
+
+import (
+	"context"
+	"sort"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	agentv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/agent/v1alpha1"
+	apmv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/apm/v1"
+	beatv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/beat/v1beta1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	entv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/enterprisesearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	logstashv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+	emsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/maps/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
+)
+
+// ResourceSummary is the observed state of an Elastic resource, as reported in its status.
+type ResourceSummary struct {
+	Kind    string `json:"kind"`
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	Health  string `json:"health,omitempty"`
+	Phase   string `json:"phase,omitempty"`
+}
+
+// summarize returns the summaries of all the Elastic resources of the given namespace, sorted by kind and name.
+func summarize(ctx context.Context, c k8s.Client, namespace string) ([]ResourceSummary, error) {
+	var summaries []ResourceSummary
+	inNamespace := client.InNamespace(namespace)
+
+	var esList esv1.ElasticsearchList
+	if err := c.List(ctx, &esList, inNamespace); err != nil {
+		return nil, err
+	}
+	for _, es := range esList.Items {
+		summaries = append(summaries, ResourceSummary{
+			Kind: esv1.Kind, Name: es.Name, Version: es.Status.Version, Health: string(es.Status.Health), Phase: string(es.Status.Phase),
+		})
+	}
+
+	var kbList kbv1.KibanaList
+	if err := c.List(ctx, &kbList, inNamespace); err != nil {
+		return nil, err
+	}
+	for _, kb := range kbList.Items {
+		summaries = append(summaries, ResourceSummary{
+			Kind: kbv1.Kind, Name: kb.Name, Version: kb.Status.Version, Health: string(kb.Status.Health),
+		})
+	}
+
+	var apmList apmv1.ApmServerList
+	if err := c.List(ctx, &apmList, inNamespace); err != nil {
+		return nil, err
+	}
+	for _, apm := range apmList.Items {
+		summaries = append(summaries, ResourceSummary{
+			Kind: apmv1.Kind, Name: apm.Name, Version: apm.Status.Version, Health: string(apm.Status.Health),
+		})
+	}
+
+	var entList entv1.EnterpriseSearchList
+	if err := c.List(ctx, &entList, inNamespace); err != nil {
+		return nil, err
+	}
+	for _, ent := range entList.Items {
+		summaries = append(summaries, ResourceSummary{
+			Kind: entv1.Kind, Name: ent.Name, Version: ent.Status.Version, Health: string(ent.Status.Health),
+		})
+	}
+
+	var beatList beatv1beta1.BeatList
+	if err := c.List(ctx, &beatList, inNamespace); err != nil {
+		return nil, err
+	}
+	for _, beat := range beatList.Items {
+		summaries = append(summaries, ResourceSummary{
+			Kind: beatv1beta1.Kind, Name: beat.Name, Version: beat.Status.Version, Health: string(beat.Status.Health),
+		})
+	}
+
+	var agentList agentv1alpha1.AgentList
+	if err := c.List(ctx, &agentList, inNamespace); err != nil {
+		return nil, err
+	}
+	for _, agent := range agentList.Items {
+		summaries = append(summaries, ResourceSummary{
+			Kind: agentv1alpha1.Kind, Name: agent.Name, Version: agent.Status.Version, Health: string(agent.Status.Health),
+		})
+	}
+
+	var emsList emsv1alpha1.ElasticMapsServerList
+	if err := c.List(ctx, &emsList, inNamespace); err != nil {
+		return nil, err
+	}
+	for _, ems := range emsList.Items {
+		summaries = append(summaries, ResourceSummary{
+			Kind: emsv1alpha1.Kind, Name: ems.Name, Version: ems.Status.Version, Health: string(ems.Status.Health),
+		})
+	}
+
+	var logstashList logstashv1alpha1.LogstashList
+	if err := c.List(ctx, &logstashList, inNamespace); err != nil {
+		return nil, err
+	}
+	for _, logstash := range logstashList.Items {
+		summaries = append(summaries, ResourceSummary{
+			Kind: logstashv1alpha1.Kind, Name: logstash.Name, Version: logstash.Status.Version, Health: string(logstash.Status.Health),
+		})
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Kind != summaries[j].Kind {
+			return summaries[i].Kind < summaries[j].Kind
+		}
+		return summaries[i].Name < summaries[j].Name
+	})
+	return summaries, nil
+}
+
+// reportAsMetrics replaces the info metrics of the resources of the given namespace with the given summaries.
+func reportAsMetrics(namespace string, summaries []ResourceSummary) {
+	metrics.ResourceInfoGauge.DeletePartialMatch(prometheus.Labels{metrics.NamespaceLabel: namespace})
+	for _, s := range summaries {
+		metrics.ResourceInfoGauge.With(prometheus.Labels{
+			metrics.NamespaceLabel: namespace,
+			metrics.NameLabel:      s.Name,
+			metrics.KindLabel:      s.Kind,
+			metrics.VersionLabel:   s.Version,
+			metrics.HealthLabel:    s.Health,
+			metrics.PhaseLabel:     s.Phase,
+		}).Set(1)
+	}
+}
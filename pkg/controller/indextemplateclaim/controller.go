@@ -0,0 +1,333 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package indextemplateclaim
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"time"
+
+	"go.elastic.co/apm/v2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	itcv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/indextemplateclaim/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
+	commonesclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/esclient"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/rbac"
+)
+
+const (
+	controllerName = "indextemplateclaim-controller"
+
+	// managedByMetaValue is stored in the _meta of the index templates created by the operator.
+	managedByMetaValue = "eck"
+)
+
+var (
+	defaultRequeue = reconcile.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+)
+
+// Add creates a new IndexTemplateClaim Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, accessReviewer rbac.AccessReviewer, params operator.Parameters) error {
+	r := newReconciler(mgr, accessReviewer, params)
+	c, err := common.NewController(mgr, controllerName, r, params)
+	if err != nil {
+		return err
+	}
+	return addWatches(mgr, c, r)
+}
+
+// newReconciler returns a new reconcile.Reconciler of IndexTemplateClaim.
+func newReconciler(mgr manager.Manager, accessReviewer rbac.AccessReviewer, params operator.Parameters) *ReconcileIndexTemplateClaim {
+	return &ReconcileIndexTemplateClaim{
+		Client:           mgr.GetClient(),
+		accessReviewer:   accessReviewer,
+		esClientProvider: commonesclient.NewClient,
+		recorder:         mgr.GetEventRecorderFor(controllerName),
+		params:           params,
+	}
+}
+
+func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileIndexTemplateClaim) error {
+	// watch for changes to IndexTemplateClaim
+	if err := c.Watch(source.Kind(mgr.GetCache(), &itcv1alpha1.IndexTemplateClaim{}, &handler.TypedEnqueueRequestForObject[*itcv1alpha1.IndexTemplateClaim]{})); err != nil {
+		return err
+	}
+
+	// watch for deleted IndexTemplateClaims and reconcile the claims of the same namespace referencing the same
+	// Elasticsearch cluster, which may now fit in the claims quota or own the data stream of the deleted claim
+	if err := c.Watch(source.Kind(mgr.GetCache(), &itcv1alpha1.IndexTemplateClaim{}, reconcileRequestsForDeletedClaim(r.Client))); err != nil {
+		return err
+	}
+
+	// watch for changes to Elasticsearch and reconcile the claims referencing it
+	return c.Watch(source.Kind[client.Object](mgr.GetCache(), &esv1.Elasticsearch{}, reconcileRequestsForElasticsearch(r.Client)))
+}
+
+// reconcileRequestsForElasticsearch returns the requests to reconcile all IndexTemplateClaims referencing an Elasticsearch cluster.
+func reconcileRequestsForElasticsearch(clnt k8s.Client) handler.TypedEventHandler[client.Object, reconcile.Request] {
+	return handler.TypedEnqueueRequestsFromMapFunc[client.Object](func(ctx context.Context, es client.Object) []reconcile.Request {
+		var claims itcv1alpha1.IndexTemplateClaimList
+		if err := clnt.List(ctx, &claims); err != nil {
+			ulog.Log.Error(err, "Fail to list IndexTemplateClaimList while watching Elasticsearch")
+			return nil
+		}
+		esNsn := k8s.ExtractNamespacedName(es)
+		requests := make([]reconcile.Request, 0)
+		for _, claim := range claims.Items {
+			if claim.ElasticsearchRef().NamespacedName() != esNsn {
+				continue
+			}
+			claim := claim
+			requests = append(requests, reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(&claim)})
+		}
+		return requests
+	})
+}
+
+// reconcileRequestsForDeletedClaim returns the requests to reconcile the claims of the namespace of a deleted
+// IndexTemplateClaim which reference the same Elasticsearch cluster.
+func reconcileRequestsForDeletedClaim(clnt k8s.Client) handler.TypedEventHandler[*itcv1alpha1.IndexTemplateClaim, reconcile.Request] {
+	return handler.TypedFuncs[*itcv1alpha1.IndexTemplateClaim, reconcile.Request]{
+		DeleteFunc: func(ctx context.Context, e event.TypedDeleteEvent[*itcv1alpha1.IndexTemplateClaim], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			siblings, err := siblingClaims(ctx, clnt, *e.Object)
+			if err != nil {
+				ulog.Log.Error(err, "Fail to list IndexTemplateClaimList while watching IndexTemplateClaim deletions")
+				return
+			}
+			for _, sibling := range siblings {
+				if sibling.Name == e.Object.Name {
+					continue
+				}
+				q.Add(reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(&sibling)})
+			}
+		},
+	}
+}
+
+var _ reconcile.Reconciler = &ReconcileIndexTemplateClaim{}
+
+// ReconcileIndexTemplateClaim reconciles an IndexTemplateClaim object
+type ReconcileIndexTemplateClaim struct {
+	k8s.Client
+	accessReviewer   rbac.AccessReviewer
+	esClientProvider commonesclient.Provider
+	recorder         record.EventRecorder
+	params           operator.Parameters
+	// iteration is the number of times this controller has run its Reconcile method
+	iteration uint64
+}
+
+// Reconcile reads that state of the cluster for an IndexTemplateClaim object and makes sure the index template and the
+// data stream it describes exist in the referenced Elasticsearch cluster.
+// Deleting an IndexTemplateClaim does not delete the index template nor the data stream to avoid any data loss.
+func (r *ReconcileIndexTemplateClaim) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	ctx = common.NewReconciliationContext(ctx, &r.iteration, r.params.Tracer, controllerName, "claim_name", request)
+	defer common.LogReconciliationRun(ulog.FromContext(ctx))()
+	defer tracing.EndContextTransaction(ctx)
+
+	var claim itcv1alpha1.IndexTemplateClaim
+	if err := r.Client.Get(ctx, request.NamespacedName, &claim); err != nil {
+		if apierrors.IsNotFound(err) {
+			// the index template and the data stream are retained
+			metrics.ForgetReconciledResource(ctx)
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
+	}
+
+	// skip unmanaged resources
+	if common.IsUnmanaged(ctx, &claim) {
+		ulog.FromContext(ctx).Info("Object is currently not managed by this controller. Skipping reconciliation")
+		return reconcile.Result{}, tracing.CaptureError(ctx, common.ReconcilePausedCondition(ctx, r.Client, &claim, &claim.Status.Conditions, true))
+	}
+	if err := common.ReconcilePausedCondition(ctx, r.Client, &claim, &claim.Status.Conditions, false); err != nil {
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
+	}
+
+	if claim.IsMarkedForDeletion() {
+		return reconcile.Result{}, nil
+	}
+
+	result, status, err := r.doReconcile(ctx, claim)
+	if err != nil {
+		status.Phase = itcv1alpha1.ErrorPhase
+		status.Message = err.Error()
+	}
+
+	if updateErr := r.updateStatus(ctx, claim, status); updateErr != nil {
+		if apierrors.IsConflict(updateErr) {
+			return reconcile.Result{Requeue: true}, nil
+		}
+		return reconcile.Result{}, tracing.CaptureError(ctx, updateErr)
+	}
+	return result, tracing.CaptureError(ctx, err)
+}
+
+func (r *ReconcileIndexTemplateClaim) doReconcile(ctx context.Context, claim itcv1alpha1.IndexTemplateClaim) (reconcile.Result, itcv1alpha1.IndexTemplateClaimStatus, error) {
+	log := ulog.FromContext(ctx)
+	status := itcv1alpha1.IndexTemplateClaimStatus{ObservedGeneration: claim.Generation}
+
+	// run validation in case the webhook is disabled
+	if err := claim.Validate(); err != nil {
+		r.recorder.Eventf(&claim, corev1.EventTypeWarning, events.EventReasonValidation, err.Error())
+		status.Phase = itcv1alpha1.InvalidPhase
+		status.Message = err.Error()
+		// the claim must be updated by the user, no need to requeue
+		return reconcile.Result{}, status, nil
+	}
+
+	esRef := claim.ElasticsearchRef()
+	var es esv1.Elasticsearch
+	if err := r.Client.Get(ctx, esRef.NamespacedName(), &es); err != nil {
+		if apierrors.IsNotFound(err) {
+			status.Phase = itcv1alpha1.PendingPhase
+			status.Message = fmt.Sprintf("Elasticsearch %s/%s does not exist", esRef.Namespace, esRef.Name)
+			// the claim is reconciled again when the Elasticsearch resource is created
+			return reconcile.Result{}, status, nil
+		}
+		return reconcile.Result{}, status, err
+	}
+
+	allowed, err := r.accessReviewer.AccessAllowed(ctx, claim.Spec.ServiceAccountName, claim.Namespace, &es)
+	if err != nil {
+		return reconcile.Result{}, status, err
+	}
+	if !allowed {
+		msg := fmt.Sprintf("IndexTemplateClaim %s/%s is not allowed to reference Elasticsearch %s/%s", claim.Namespace, claim.Name, es.Namespace, es.Name)
+		r.recorder.Event(&claim, corev1.EventTypeWarning, events.EventAssociationError, msg)
+		status.Phase = itcv1alpha1.InvalidPhase
+		status.Message = msg
+		return defaultRequeue, status, nil
+	}
+
+	duplicate, err := olderDataStreamClaim(ctx, r.Client, claim)
+	if err != nil {
+		return reconcile.Result{}, status, err
+	}
+	if duplicate != nil {
+		msg := fmt.Sprintf("data stream %s is already claimed by IndexTemplateClaim %s/%s", claim.Spec.DataStream, duplicate.Namespace, duplicate.Name)
+		r.recorder.Event(&claim, corev1.EventTypeWarning, events.EventReasonValidation, msg)
+		status.Phase = itcv1alpha1.InvalidPhase
+		status.Message = msg
+		// the claim is reconciled again when the other claim is deleted
+		return reconcile.Result{}, status, nil
+	}
+
+	quotaMsg, err := checkQuotas(ctx, r.Client, claim, es)
+	if err != nil {
+		return reconcile.Result{}, status, err
+	}
+	if quotaMsg != "" {
+		r.recorder.Event(&claim, corev1.EventTypeWarning, events.EventReasonValidation, quotaMsg)
+		status.Phase = itcv1alpha1.QuotaExceededPhase
+		status.Message = quotaMsg
+		// quotas are annotations on the Elasticsearch resource, which is watched, or depend on the older claims, whose
+		// deletion triggers a reconciliation
+		return reconcile.Result{}, status, nil
+	}
+
+	if es.Status.Health != esv1.ElasticsearchGreenHealth && es.Status.Health != esv1.ElasticsearchYellowHealth {
+		log.V(1).Info("Elasticsearch cluster not available yet, requeuing", "es_namespace", es.Namespace, "es_name", es.Name)
+		status.Phase = itcv1alpha1.PendingPhase
+		status.Message = fmt.Sprintf("Elasticsearch %s/%s is not available", es.Namespace, es.Name)
+		return defaultRequeue, status, nil
+	}
+
+	esClient, err := r.esClientProvider(ctx, r.Client, r.params.Dialer, es)
+	if err != nil {
+		return reconcile.Result{}, status, err
+	}
+	defer esClient.Close()
+
+	if err := reconcileDataStream(ctx, esClient, claim); err != nil {
+		r.recorder.Eventf(&claim, corev1.EventTypeWarning, events.EventReconciliationError, "Failed to apply data stream %s: %s", claim.Spec.DataStream, err.Error())
+		status.Phase = itcv1alpha1.ErrorPhase
+		status.Message = err.Error()
+		return defaultRequeue, status, nil
+	}
+
+	status.Phase = itcv1alpha1.ReadyPhase
+	return reconcile.Result{}, status, nil
+}
+
+// expectedIndexTemplate returns the index template to create in Elasticsearch for the given claim.
+func expectedIndexTemplate(claim itcv1alpha1.IndexTemplateClaim) esclient.IndexTemplate {
+	priority := claim.EffectivePriority()
+	template := esclient.IndexTemplate{
+		IndexPatterns: []string{claim.Spec.DataStream},
+		DataStream:    &esclient.IndexTemplateDataStream{},
+		Priority:      &priority,
+		Meta: map[string]interface{}{
+			"managed_by": managedByMetaValue,
+			"claim": map[string]interface{}{
+				"namespace": claim.Namespace,
+				"name":      claim.Name,
+			},
+		},
+	}
+	if claim.Spec.Template != nil {
+		template.Template = claim.Spec.Template.Data
+	}
+	return template
+}
+
+// reconcileDataStream creates or updates the index template of the claim and creates the data stream if it does not exist yet.
+func reconcileDataStream(ctx context.Context, esClient esclient.Client, claim itcv1alpha1.IndexTemplateClaim) error {
+	span, ctx := apm.StartSpan(ctx, "reconcile_data_stream", tracing.SpanTypeApp)
+	defer span.End()
+
+	name := claim.Spec.DataStream
+	if err := esClient.PutIndexTemplate(ctx, name, expectedIndexTemplate(claim)); err != nil {
+		return err
+	}
+	_, err := esClient.GetDataStream(ctx, name)
+	if err == nil {
+		return nil
+	}
+	if !esclient.IsNotFound(err) {
+		return err
+	}
+	ulog.FromContext(ctx).Info("Creating data stream", "data_stream", name)
+	return esClient.CreateDataStream(ctx, name)
+}
+
+func (r *ReconcileIndexTemplateClaim) updateStatus(ctx context.Context, claim itcv1alpha1.IndexTemplateClaim, status itcv1alpha1.IndexTemplateClaimStatus) error {
+	span, _ := apm.StartSpan(ctx, "update_status", tracing.SpanTypeApp)
+	defer span.End()
+
+	status.Conditions = claim.Status.Conditions.WithReadinessConditions(status.ResourceState(), metav1.Now())
+	if reflect.DeepEqual(status, claim.Status) {
+		return nil // nothing to do
+	}
+	ulog.FromContext(ctx).V(1).Info("Updating status",
+		"iteration", atomic.LoadUint64(&r.iteration),
+		"status", status,
+	)
+	claim.Status = status
+	return common.UpdateStatus(ctx, r.Client, &claim)
+}
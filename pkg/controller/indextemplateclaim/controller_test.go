@@ -0,0 +1,316 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package indextemplateclaim
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	itcv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/indextemplateclaim/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
+	commonesclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/esclient"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/rbac"
+)
+
+type fakeEsClient struct {
+	esclient.Client
+
+	templates   map[string]esclient.IndexTemplate
+	dataStreams map[string]bool
+}
+
+func newFakeEsClient(dataStreams ...string) *fakeEsClient {
+	c := &fakeEsClient{templates: map[string]esclient.IndexTemplate{}, dataStreams: map[string]bool{}}
+	for _, ds := range dataStreams {
+		c.dataStreams[ds] = true
+	}
+	return c
+}
+
+func (c *fakeEsClient) provider() commonesclient.Provider {
+	return func(_ context.Context, _ k8s.Client, _ net.Dialer, _ esv1.Elasticsearch) (esclient.Client, error) {
+		return c, nil
+	}
+}
+
+func (c *fakeEsClient) PutIndexTemplate(_ context.Context, name string, template esclient.IndexTemplate) error {
+	c.templates[name] = template
+	return nil
+}
+
+func (c *fakeEsClient) GetDataStream(_ context.Context, name string) (esclient.DataStream, error) {
+	if !c.dataStreams[name] {
+		return esclient.DataStream{}, &esclient.APIError{StatusCode: http.StatusNotFound}
+	}
+	return esclient.DataStream{Name: name}, nil
+}
+
+func (c *fakeEsClient) CreateDataStream(_ context.Context, name string) error {
+	c.dataStreams[name] = true
+	return nil
+}
+
+func (c *fakeEsClient) Close() {}
+
+type fakeAccessReviewer struct {
+	allowed bool
+}
+
+func (f fakeAccessReviewer) AccessAllowed(_ context.Context, _ string, _ string, _ runtime.Object) (bool, error) {
+	return f.allowed, nil
+}
+
+func mkClaim(name, dataStream string, creation int64) *itcv1alpha1.IndexTemplateClaim {
+	return &itcv1alpha1.IndexTemplateClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "team-a",
+			Name:              name,
+			Generation:        1,
+			CreationTimestamp: metav1.Unix(creation, 0),
+		},
+		Spec: itcv1alpha1.IndexTemplateClaimSpec{
+			ElasticsearchRef: commonv1.ObjectSelector{Namespace: "elastic", Name: "central"},
+			DataStream:       dataStream,
+			Template: &commonv1.Config{Data: map[string]interface{}{
+				"settings": map[string]interface{}{"index.number_of_shards": 2},
+			}},
+		},
+	}
+}
+
+func mkElasticsearch(health esv1.ElasticsearchHealth, annotations map[string]string) *esv1.Elasticsearch {
+	return &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "elastic", Name: "central", Annotations: annotations},
+		Spec:       esv1.ElasticsearchSpec{Version: "8.15.0"},
+		Status:     esv1.ElasticsearchStatus{Health: health},
+	}
+}
+
+func TestReconcileIndexTemplateClaim_Reconcile(t *testing.T) {
+	claim := mkClaim("logs", "team-a-logs", 1)
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "logs"}}
+
+	tests := []struct {
+		name               string
+		objects            []client.Object
+		accessReviewer     rbac.AccessReviewer
+		esClient           *fakeEsClient
+		wantPhase          itcv1alpha1.ClaimPhase
+		wantRequeue        bool
+		wantTemplate       bool
+		wantDataStream     bool
+		wantMessageContent string
+	}{
+		{
+			name:           "claim and data stream are created",
+			objects:        []client.Object{claim.DeepCopy(), mkElasticsearch(esv1.ElasticsearchGreenHealth, nil)},
+			accessReviewer: rbac.NewPermissiveAccessReviewer(),
+			esClient:       newFakeEsClient(),
+			wantPhase:      itcv1alpha1.ReadyPhase,
+			wantTemplate:   true,
+			wantDataStream: true,
+		},
+		{
+			name:           "existing data stream is not recreated",
+			objects:        []client.Object{claim.DeepCopy(), mkElasticsearch(esv1.ElasticsearchYellowHealth, nil)},
+			accessReviewer: rbac.NewPermissiveAccessReviewer(),
+			esClient:       newFakeEsClient("team-a-logs"),
+			wantPhase:      itcv1alpha1.ReadyPhase,
+			wantTemplate:   true,
+			wantDataStream: true,
+		},
+		{
+			name:               "Elasticsearch does not exist",
+			objects:            []client.Object{claim.DeepCopy()},
+			accessReviewer:     rbac.NewPermissiveAccessReviewer(),
+			esClient:           newFakeEsClient(),
+			wantPhase:          itcv1alpha1.PendingPhase,
+			wantMessageContent: "does not exist",
+		},
+		{
+			name:               "Elasticsearch not available",
+			objects:            []client.Object{claim.DeepCopy(), mkElasticsearch(esv1.ElasticsearchUnknownHealth, nil)},
+			accessReviewer:     rbac.NewPermissiveAccessReviewer(),
+			esClient:           newFakeEsClient(),
+			wantPhase:          itcv1alpha1.PendingPhase,
+			wantRequeue:        true,
+			wantMessageContent: "is not available",
+		},
+		{
+			name:               "access not allowed",
+			objects:            []client.Object{claim.DeepCopy(), mkElasticsearch(esv1.ElasticsearchGreenHealth, nil)},
+			accessReviewer:     fakeAccessReviewer{allowed: false},
+			esClient:           newFakeEsClient(),
+			wantPhase:          itcv1alpha1.InvalidPhase,
+			wantRequeue:        true,
+			wantMessageContent: "is not allowed to reference",
+		},
+		{
+			name: "shards quota exceeded",
+			objects: []client.Object{claim.DeepCopy(), mkElasticsearch(esv1.ElasticsearchGreenHealth, map[string]string{
+				itcv1alpha1.MaxShardsPerClaimAnnotation: "2",
+			})},
+			accessReviewer:     rbac.NewPermissiveAccessReviewer(),
+			esClient:           newFakeEsClient(),
+			wantPhase:          itcv1alpha1.QuotaExceededPhase,
+			wantMessageContent: "exceeding the quota of 2 shards per claim",
+		},
+		{
+			name: "claims quota exceeded",
+			objects: []client.Object{claim.DeepCopy(), mkClaim("older", "team-a-older", 0), mkElasticsearch(esv1.ElasticsearchGreenHealth, map[string]string{
+				itcv1alpha1.MaxClaimsPerNamespaceAnnotation: "1",
+			})},
+			accessReviewer:     rbac.NewPermissiveAccessReviewer(),
+			esClient:           newFakeEsClient(),
+			wantPhase:          itcv1alpha1.QuotaExceededPhase,
+			wantMessageContent: "exceeds the quota of 1 claims",
+		},
+		{
+			name:               "data stream claimed by an older claim",
+			objects:            []client.Object{claim.DeepCopy(), mkClaim("older", "team-a-logs", 0), mkElasticsearch(esv1.ElasticsearchGreenHealth, nil)},
+			accessReviewer:     rbac.NewPermissiveAccessReviewer(),
+			esClient:           newFakeEsClient(),
+			wantPhase:          itcv1alpha1.InvalidPhase,
+			wantMessageContent: "data stream team-a-logs is already claimed by IndexTemplateClaim team-a/older",
+		},
+		{
+			name:           "data stream also claimed by a newer claim",
+			objects:        []client.Object{claim.DeepCopy(), mkClaim("newer", "team-a-logs", 2), mkElasticsearch(esv1.ElasticsearchGreenHealth, nil)},
+			accessReviewer: rbac.NewPermissiveAccessReviewer(),
+			esClient:       newFakeEsClient(),
+			wantPhase:      itcv1alpha1.ReadyPhase,
+			wantTemplate:   true,
+			wantDataStream: true,
+		},
+		{
+			name: "invalid claim",
+			objects: func() []client.Object {
+				invalid := claim.DeepCopy()
+				invalid.Spec.DataStream = "logs"
+				return []client.Object{invalid, mkElasticsearch(esv1.ElasticsearchGreenHealth, nil)}
+			}(),
+			accessReviewer:     rbac.NewPermissiveAccessReviewer(),
+			esClient:           newFakeEsClient(),
+			wantPhase:          itcv1alpha1.InvalidPhase,
+			wantMessageContent: "data stream name must start with",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ReconcileIndexTemplateClaim{
+				Client:           k8s.NewFakeClient(tt.objects...),
+				accessReviewer:   tt.accessReviewer,
+				esClientProvider: tt.esClient.provider(),
+				recorder:         record.NewFakeRecorder(10),
+			}
+			result, err := r.Reconcile(context.Background(), request)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRequeue, result.Requeue)
+
+			var updated itcv1alpha1.IndexTemplateClaim
+			require.NoError(t, r.Client.Get(context.Background(), request.NamespacedName, &updated))
+			assert.Equal(t, tt.wantPhase, updated.Status.Phase)
+			assert.Equal(t, int64(1), updated.Status.ObservedGeneration)
+			assert.Contains(t, updated.Status.Message, tt.wantMessageContent)
+
+			template, exists := tt.esClient.templates["team-a-logs"]
+			assert.Equal(t, tt.wantTemplate, exists)
+			if exists {
+				assert.Equal(t, []string{"team-a-logs"}, template.IndexPatterns)
+				assert.NotNil(t, template.DataStream)
+				assert.Equal(t, itcv1alpha1.DefaultPriority, *template.Priority)
+				assert.Equal(t, managedByMetaValue, template.Meta["managed_by"])
+			}
+			assert.Equal(t, tt.wantDataStream, tt.esClient.dataStreams["team-a-logs"])
+		})
+	}
+}
+
+func TestReconcileIndexTemplateClaim_ReconcileDeletedClaim(t *testing.T) {
+	esClient := newFakeEsClient()
+	r := &ReconcileIndexTemplateClaim{
+		Client:           k8s.NewFakeClient(mkElasticsearch(esv1.ElasticsearchGreenHealth, nil)),
+		accessReviewer:   rbac.NewPermissiveAccessReviewer(),
+		esClientProvider: esClient.provider(),
+		recorder:         record.NewFakeRecorder(10),
+	}
+	result, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "logs"}})
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{}, result)
+	assert.Empty(t, esClient.templates)
+}
+
+func TestReconcileIndexTemplateClaim_ReconcileUnmanagedClaim(t *testing.T) {
+	claim := mkClaim("logs", "team-a-logs", 1)
+	claim.Annotations = map[string]string{common.ManagedAnnotation: "false"}
+	esClient := newFakeEsClient()
+	r := &ReconcileIndexTemplateClaim{
+		Client:           k8s.NewFakeClient(claim, mkElasticsearch(esv1.ElasticsearchGreenHealth, nil)),
+		accessReviewer:   rbac.NewPermissiveAccessReviewer(),
+		esClientProvider: esClient.provider(),
+		recorder:         record.NewFakeRecorder(10),
+	}
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "logs"}}
+	pausedStatus := func() corev1.ConditionStatus {
+		var updated itcv1alpha1.IndexTemplateClaim
+		require.NoError(t, r.Client.Get(context.Background(), request.NamespacedName, &updated))
+		return updated.Status.Conditions[updated.Status.Conditions.Index(commonv1alpha1.PausedCondition)].Status
+	}
+
+	// the claim is not applied while paused
+	_, err := r.Reconcile(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, corev1.ConditionTrue, pausedStatus())
+	assert.Empty(t, esClient.templates)
+
+	// the claim is applied once resumed
+	var resumed itcv1alpha1.IndexTemplateClaim
+	require.NoError(t, r.Client.Get(context.Background(), request.NamespacedName, &resumed))
+	resumed.Annotations = nil
+	require.NoError(t, r.Client.Update(context.Background(), &resumed))
+	_, err = r.Reconcile(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, corev1.ConditionFalse, pausedStatus())
+	assert.Contains(t, esClient.templates, "team-a-logs")
+}
+
+func Test_reconcileRequestsForDeletedClaim(t *testing.T) {
+	deleted := mkClaim("deleted", "team-a-logs", 0)
+	otherCluster := mkClaim("other", "team-a-other", 5)
+	otherCluster.Spec.ElasticsearchRef.Name = "other"
+	otherNamespace := mkClaim("elsewhere", "team-b-logs", 5)
+	otherNamespace.Namespace = "team-b"
+	c := k8s.NewFakeClient(mkClaim("logs", "team-a-logs", 1), mkClaim("metrics", "team-a-metrics", 2), otherCluster, otherNamespace)
+
+	q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer q.ShutDown()
+	reconcileRequestsForDeletedClaim(c).Delete(context.Background(), event.TypedDeleteEvent[*itcv1alpha1.IndexTemplateClaim]{Object: deleted}, q)
+	var got []string
+	for q.Len() > 0 {
+		request, _ := q.Get()
+		got = append(got, request.String())
+		q.Done(request)
+	}
+	require.ElementsMatch(t, []string{"team-a/logs", "team-a/metrics"}, got)
+}
@@ -0,0 +1,180 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package indextemplateclaim
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	itcv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/indextemplateclaim/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+const (
+	// defaultNumberOfShards and defaultNumberOfReplicas are the Elasticsearch defaults used when the template of a
+	// claim does not specify them.
+	defaultNumberOfShards   = 1
+	defaultNumberOfReplicas = 1
+)
+
+// indexShardSettings holds the shard related settings of an index template. Both the `index.` prefixed and the
+// short form of the settings are accepted by Elasticsearch.
+type indexShardSettings struct {
+	Index struct {
+		NumberOfShards   *int `config:"number_of_shards"`
+		NumberOfReplicas *int `config:"number_of_replicas"`
+	} `config:"index"`
+	NumberOfShards   *int `config:"number_of_shards"`
+	NumberOfReplicas *int `config:"number_of_replicas"`
+}
+
+// totalShards returns the number of shards, primaries and replicas, of a backing index created from the template of
+// the given claim.
+func totalShards(claim itcv1alpha1.IndexTemplateClaim) (int, error) {
+	shards, replicas := defaultNumberOfShards, defaultNumberOfReplicas
+	if claim.Spec.Template == nil {
+		return shards * (1 + replicas), nil
+	}
+	rawSettings, exists := claim.Spec.Template.Data["settings"]
+	if !exists {
+		return shards * (1 + replicas), nil
+	}
+	settingsMap, ok := rawSettings.(map[string]interface{})
+	if !ok {
+		return 0, fmt.Errorf("template settings must be an object, got %T", rawSettings)
+	}
+	cfg, err := settings.NewCanonicalConfigFrom(settingsMap)
+	if err != nil {
+		return 0, err
+	}
+	var s indexShardSettings
+	if err := cfg.Unpack(&s); err != nil {
+		return 0, err
+	}
+	for _, v := range []*int{s.NumberOfShards, s.Index.NumberOfShards} {
+		if v != nil {
+			shards = *v
+		}
+	}
+	for _, v := range []*int{s.NumberOfReplicas, s.Index.NumberOfReplicas} {
+		if v != nil {
+			replicas = *v
+		}
+	}
+	return shards * (1 + replicas), nil
+}
+
+// quotaFromAnnotation returns the value of a quota annotation set on the Elasticsearch resource, or false if no
+// quota is defined.
+func quotaFromAnnotation(es esv1.Elasticsearch, annotation string) (int, bool, error) {
+	value, exists := es.Annotations[annotation]
+	if !exists {
+		return 0, false, nil
+	}
+	quota, err := strconv.Atoi(value)
+	if err != nil || quota < 0 {
+		return 0, false, fmt.Errorf("invalid value %q for annotation %s on Elasticsearch %s/%s", value, annotation, es.Namespace, es.Name)
+	}
+	return quota, true, nil
+}
+
+// checkQuotas returns a non-empty message if the claim exceeds one of the quotas defined on the Elasticsearch cluster.
+func checkQuotas(ctx context.Context, c k8s.Client, claim itcv1alpha1.IndexTemplateClaim, es esv1.Elasticsearch) (string, error) {
+	maxShards, exists, err := quotaFromAnnotation(es, itcv1alpha1.MaxShardsPerClaimAnnotation)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		shards, err := totalShards(claim)
+		if err != nil {
+			return "", err
+		}
+		if shards > maxShards {
+			return fmt.Sprintf("template requires %d shards per backing index, exceeding the quota of %d shards per claim", shards, maxShards), nil
+		}
+	}
+
+	maxClaims, exists, err := quotaFromAnnotation(es, itcv1alpha1.MaxClaimsPerNamespaceAnnotation)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		rank, err := claimRank(ctx, c, claim)
+		if err != nil {
+			return "", err
+		}
+		if rank > maxClaims {
+			return fmt.Sprintf("namespace %s exceeds the quota of %d claims for Elasticsearch %s/%s", claim.Namespace, maxClaims, es.Namespace, es.Name), nil
+		}
+	}
+	return "", nil
+}
+
+// claimRank returns the 1-based position of the claim among the claims of its namespace referencing the same
+// Elasticsearch cluster, ordered by creation time. Older claims are served first so that creating a new claim never
+// evicts an existing one.
+func claimRank(ctx context.Context, c k8s.Client, claim itcv1alpha1.IndexTemplateClaim) (int, error) {
+	candidates, err := siblingClaims(ctx, c, claim)
+	if err != nil {
+		return 0, err
+	}
+	for i, item := range candidates {
+		if item.Name == claim.Name {
+			return i + 1, nil
+		}
+	}
+	// the claim is not in the cache yet, it will be the most recent one
+	return len(candidates) + 1, nil
+}
+
+// olderDataStreamClaim returns the oldest claim of the namespace of the given claim which declares the same data stream
+// in the same Elasticsearch cluster and was created before it, or nil if there is none.
+func olderDataStreamClaim(ctx context.Context, c k8s.Client, claim itcv1alpha1.IndexTemplateClaim) (*itcv1alpha1.IndexTemplateClaim, error) {
+	candidates, err := siblingClaims(ctx, c, claim)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range candidates {
+		if item.Name == claim.Name {
+			return nil, nil
+		}
+		if item.Spec.DataStream == claim.Spec.DataStream {
+			return &item, nil
+		}
+	}
+	// the claim is not in the cache yet, it will be the most recent one
+	return nil, nil
+}
+
+// siblingClaims returns the claims of the namespace of the given claim, including itself if it is in the cache, which
+// reference the same Elasticsearch cluster and are not being deleted, ordered by creation time.
+func siblingClaims(ctx context.Context, c k8s.Client, claim itcv1alpha1.IndexTemplateClaim) ([]itcv1alpha1.IndexTemplateClaim, error) {
+	var claims itcv1alpha1.IndexTemplateClaimList
+	if err := c.List(ctx, &claims, client.InNamespace(claim.Namespace)); err != nil {
+		return nil, err
+	}
+	esRef := claim.ElasticsearchRef()
+	candidates := make([]itcv1alpha1.IndexTemplateClaim, 0, len(claims.Items))
+	for _, item := range claims.Items {
+		if item.ElasticsearchRef() != esRef || item.IsMarkedForDeletion() {
+			continue
+		}
+		candidates = append(candidates, item)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		ti, tj := candidates[i].CreationTimestamp, candidates[j].CreationTimestamp
+		if ti.Equal(&tj) {
+			return candidates[i].Name < candidates[j].Name
+		}
+		return ti.Before(&tj)
+	})
+	return candidates, nil
+}
@@ -0,0 +1,117 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package indextemplateclaim
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func Test_totalShards(t *testing.T) {
+	tests := []struct {
+		name     string
+		template *commonv1.Config
+		want     int
+		wantErr  bool
+	}{
+		{
+			name: "no template: Elasticsearch defaults",
+			want: 2,
+		},
+		{
+			name:     "no settings: Elasticsearch defaults",
+			template: &commonv1.Config{Data: map[string]interface{}{"mappings": map[string]interface{}{}}},
+			want:     2,
+		},
+		{
+			name: "flat index settings",
+			template: &commonv1.Config{Data: map[string]interface{}{
+				"settings": map[string]interface{}{"index.number_of_shards": 3, "index.number_of_replicas": 2},
+			}},
+			want: 9,
+		},
+		{
+			name: "nested index settings",
+			template: &commonv1.Config{Data: map[string]interface{}{
+				"settings": map[string]interface{}{"index": map[string]interface{}{"number_of_shards": 2, "number_of_replicas": 0}},
+			}},
+			want: 2,
+		},
+		{
+			name: "short settings",
+			template: &commonv1.Config{Data: map[string]interface{}{
+				"settings": map[string]interface{}{"number_of_shards": 4},
+			}},
+			want: 8,
+		},
+		{
+			name: "invalid settings",
+			template: &commonv1.Config{Data: map[string]interface{}{
+				"settings": "shards",
+			}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claim := mkClaim("claim", "team-a-claim", 0)
+			claim.Spec.Template = tt.template
+			got, err := totalShards(*claim)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_claimRank(t *testing.T) {
+	oldest := mkClaim("oldest", "team-a-oldest", 0)
+	middle := mkClaim("middle", "team-a-middle", 10)
+	newest := mkClaim("newest", "team-a-newest", 20)
+	otherCluster := mkClaim("other", "team-a-other", 5)
+	otherCluster.Spec.ElasticsearchRef.Name = "other"
+	deleted := mkClaim("deleted", "team-a-deleted", 1)
+	now := metav1.Now()
+	deleted.DeletionTimestamp = &now
+	deleted.Finalizers = []string{"test"}
+
+	c := k8s.NewFakeClient(newest, middle, oldest, otherCluster, deleted)
+	for name, want := range map[string]int{"oldest": 1, "middle": 2, "newest": 3} {
+		claim := mkClaim(name, "team-a-"+name, 0)
+		got, err := claimRank(context.Background(), c, *claim)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, name)
+	}
+	// claim not in the cache yet
+	got, err := claimRank(context.Background(), c, *mkClaim("unknown", "team-a-unknown", 30))
+	require.NoError(t, err)
+	assert.Equal(t, 4, got)
+}
+
+func Test_quotaFromAnnotation(t *testing.T) {
+	es := mkElasticsearch(esv1.ElasticsearchGreenHealth, map[string]string{"valid": "3", "invalid": "-1"})
+	quota, exists, err := quotaFromAnnotation(*es, "valid")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, 3, quota)
+
+	_, exists, err = quotaFromAnnotation(*es, "missing")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	_, _, err = quotaFromAnnotation(*es, "invalid")
+	require.Error(t, err)
+}
@@ -279,7 +279,7 @@ func baseSettings(kb *kbv1.Kibana, ipFamily corev1.IPFamily) (map[string]interfa
 }
 
 func kibanaTLSSettings(kb kbv1.Kibana) map[string]interface{} {
-	if !kb.Spec.HTTP.TLS.Enabled() {
+	if !kb.Spec.HTTP.TLSEnabled() {
 		return nil
 	}
 	return map[string]interface{}{
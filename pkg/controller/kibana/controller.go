@@ -33,8 +33,10 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
 	kblabel "github.com/elastic/cloud-on-k8s/v2/pkg/controller/kibana/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/kibanaconfig"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
 )
 
 const (
@@ -102,7 +104,12 @@ func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileKibana
 	}
 
 	// dynamically watch referenced secrets to connect to Elasticsearch
-	return c.Watch(source.Kind(mgr.GetCache(), &corev1.Secret{}, r.dynamicWatches.Secrets))
+	if err := c.Watch(source.Kind(mgr.GetCache(), &corev1.Secret{}, r.dynamicWatches.Secrets)); err != nil {
+		return err
+	}
+
+	// dynamically watch config maps referenced in the provisioning spec
+	return c.Watch(source.Kind(mgr.GetCache(), &corev1.ConfigMap{}, r.dynamicWatches.ConfigMaps))
 }
 
 var _ reconcile.Reconciler = &ReconcileKibana{}
@@ -143,7 +150,10 @@ func (r *ReconcileKibana) Reconcile(ctx context.Context, request reconcile.Reque
 
 	if common.IsUnmanaged(ctx, &kb) {
 		ulog.FromContext(ctx).Info("Object is currently not managed by this controller. Skipping reconciliation", "namespace", kb.Namespace, "kibana_name", kb.Name)
-		return reconcile.Result{}, nil
+		return reconcile.Result{}, tracing.CaptureError(ctx, common.ReconcilePausedCondition(ctx, r.Client, &kb, &kb.Status.Conditions, true))
+	}
+	if err := common.ReconcilePausedCondition(ctx, r.Client, &kb, &kb.Status.Conditions, false); err != nil {
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
 	}
 
 	// Remove any previous Finalizers
@@ -163,10 +173,12 @@ func (r *ReconcileKibana) Reconcile(ctx context.Context, request reconcile.Reque
 func (r *ReconcileKibana) doReconcile(ctx context.Context, request reconcile.Request, kb *kbv1.Kibana) (result reconcile.Result, err error) {
 	state := NewState(request, kb)
 	log := ulog.FromContext(ctx)
+	results := reconciler.NewResult(ctx)
 	// defer the updating of status to ensure that the status is updated regardless of the outcome of the reconciliation.
 	// note that this deferred function is modifying the return values, which are named return values, which allows this
 	// to function properly.
 	defer func() {
+		common.UpdateStandardConditions(&state.Kibana.Status.Conditions, state.Kibana.Status.ResourceState(kb.Generation, kb.Spec.Version), results.WithError(err))
 		statusErr := r.updateStatus(ctx, state)
 		if statusErr != nil && apierrors.IsConflict(statusErr) {
 			log.V(1).Info("Conflict while updating status", "namespace", kb.Namespace, "kibana_name", kb.Name)
@@ -192,7 +204,7 @@ func (r *ReconcileKibana) doReconcile(ctx context.Context, request reconcile.Req
 		return reconcile.Result{}, tracing.CaptureError(ctx, err)
 	}
 
-	results := driver.Reconcile(ctx, &state, kb, r.params)
+	results = driver.Reconcile(ctx, &state, kb, r.params)
 
 	result, err = results.WithError(err).Aggregate()
 	k8s.MaybeEmitErrorEvent(r.recorder, err, kb, events.EventReconciliationError, "Reconciliation error: %v", err)
@@ -233,10 +245,14 @@ func (r *ReconcileKibana) updateStatus(ctx context.Context, state State) error {
 }
 
 func (r *ReconcileKibana) onDelete(ctx context.Context, obj types.NamespacedName) error {
+	metrics.DeleteResourceMetrics(ctx, kbv1.Kind, obj)
 	// Clean up watches set on secure settings
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(keystore.SecureSettingsWatchName(obj))
 	// Clean up watches set on custom http tls certificates
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(certificates.CertificateWatchKey(kbv1.KBNamer, obj.Name))
+	// Clean up watches set on provisioning content
+	r.dynamicWatches.ConfigMaps.RemoveHandlerForKey(kibanaconfig.WatchName(obj))
+	r.dynamicWatches.Secrets.RemoveHandlerForKey(kibanaconfig.WatchName(obj))
 	return reconciler.GarbageCollectSoftOwnedSecrets(ctx, r.Client, obj, kbv1.Kind)
 }
 
@@ -254,5 +270,9 @@ type State struct {
 func NewState(request reconcile.Request, kb *kbv1.Kibana) State {
 	newState := State{Request: request, Kibana: kb, originalKibana: kb.DeepCopy()}
 	newState.Kibana.Status.ObservedGeneration = kb.Generation
+	newState.Kibana.Status.RemoteClusters = nil
+	for _, remoteCluster := range kb.Spec.RemoteClusters {
+		newState.Kibana.Status.RemoteClusters = append(newState.Kibana.Status.RemoteClusters, remoteCluster.Name)
+	}
 	return newState
 }
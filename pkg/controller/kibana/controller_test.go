@@ -21,6 +21,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	kibanav1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
@@ -87,16 +88,21 @@ func TestReconcileKibana_Reconcile(t *testing.T) {
 				var kibana kibanav1.Kibana
 				err := f.Client.Get(context.Background(), types.NamespacedName{Namespace: "test", Name: "test-kibana"}, &kibana)
 				require.NoError(t, err)
-				require.Equal(t, kibanav1.KibanaStatus{
+				require.Empty(t, cmp.Diff(kibanav1.KibanaStatus{
 					DeploymentStatus: commonv1.DeploymentStatus{
 						Selector:       "",
 						Count:          0,
 						AvailableNodes: 0,
 						Version:        "",
 						Health:         commonv1.DeploymentHealth(""),
+						Conditions: commonv1alpha1.Conditions{{
+							Type:    commonv1alpha1.PausedCondition,
+							Status:  corev1.ConditionTrue,
+							Message: "Reconciliation is suspended by the eck.k8s.elastic.co/managed annotation",
+						}},
 					},
 					ObservedGeneration: 1,
-				}, kibana.Status)
+				}, kibana.Status, cmpopts.IgnoreTypes(metav1.Time{})))
 			},
 		},
 		{
@@ -115,16 +121,17 @@ func TestReconcileKibana_Reconcile(t *testing.T) {
 				err := f.Client.Get(context.Background(), types.NamespacedName{Namespace: "test", Name: "test-kibana"}, &kibana)
 				require.NoError(t, err)
 				require.Len(t, kibana.ObjectMeta.Finalizers, 0)
-				require.Equal(t, kibanav1.KibanaStatus{
+				require.Empty(t, cmp.Diff(kibanav1.KibanaStatus{
 					DeploymentStatus: commonv1.DeploymentStatus{
 						Selector:       "common.k8s.elastic.co/type=kibana,kibana.k8s.elastic.co/name=test-kibana",
 						Count:          0,
 						AvailableNodes: 0,
 						Version:        "",
 						Health:         commonv1.RedHealth,
+						Conditions:     notRunningConditions("red", ""),
 					},
 					ObservedGeneration: 2,
-				}, kibana.Status)
+				}, kibana.Status, cmpopts.IgnoreTypes(metav1.Time{})))
 			},
 		},
 		{
@@ -148,16 +155,17 @@ func TestReconcileKibana_Reconcile(t *testing.T) {
 				var kibana kibanav1.Kibana
 				err := f.Client.Get(context.Background(), types.NamespacedName{Namespace: "test", Name: "superlongkibananamecausesvalidationissues"}, &kibana)
 				require.NoError(t, err)
-				require.Equal(t, kibanav1.KibanaStatus{
+				require.Empty(t, cmp.Diff(kibanav1.KibanaStatus{
 					DeploymentStatus: commonv1.DeploymentStatus{
 						Selector:       "",
 						Count:          0,
 						AvailableNodes: 0,
 						Version:        "",
 						Health:         commonv1.DeploymentHealth(""),
+						Conditions:     notRunningConditions("unknown", `Kibana.kibana.k8s.elastic.co "superlongkibananamecausesvalidationissues" is invalid: metadata.name: Too long: may not be more than 36 bytes`),
 					},
 					ObservedGeneration: 2,
-				}, kibana.Status)
+				}, kibana.Status, cmpopts.IgnoreTypes(metav1.Time{})))
 			},
 		},
 		{
@@ -213,16 +221,17 @@ func TestReconcileKibana_Reconcile(t *testing.T) {
 				var kibana kibanav1.Kibana
 				err := f.Client.Get(context.Background(), types.NamespacedName{Namespace: "test", Name: "test-kibana"}, &kibana)
 				require.NoError(t, err)
-				require.Equal(t, kibanav1.KibanaStatus{
+				require.Empty(t, cmp.Diff(kibanav1.KibanaStatus{
 					DeploymentStatus: commonv1.DeploymentStatus{
 						Selector:       "common.k8s.elastic.co/type=kibana,kibana.k8s.elastic.co/name=test-kibana",
 						Count:          0,
 						AvailableNodes: 0,
 						Version:        "",
 						Health:         commonv1.RedHealth,
+						Conditions:     notRunningConditions("red", ""),
 					},
 					ObservedGeneration: 2,
-				}, kibana.Status)
+				}, kibana.Status, cmpopts.IgnoreTypes(metav1.Time{})))
 			},
 		},
 		{
@@ -262,16 +271,17 @@ func TestReconcileKibana_Reconcile(t *testing.T) {
 				var kibana kibanav1.Kibana
 				err := f.Client.Get(context.Background(), types.NamespacedName{Namespace: "test", Name: "test-kibana"}, &kibana)
 				require.NoError(t, err)
-				require.Equal(t, kibanav1.KibanaStatus{
+				require.Empty(t, cmp.Diff(kibanav1.KibanaStatus{
 					DeploymentStatus: commonv1.DeploymentStatus{
 						Selector:       "",
 						Count:          0,
 						AvailableNodes: 0,
 						Version:        "",
 						Health:         commonv1.DeploymentHealth(""),
+						Conditions:     notRunningConditions("unknown", ""),
 					},
 					ObservedGeneration: 2,
-				}, kibana.Status)
+				}, kibana.Status, cmpopts.IgnoreTypes(metav1.Time{})))
 			},
 		},
 		{
@@ -319,16 +329,17 @@ func TestReconcileKibana_Reconcile(t *testing.T) {
 				var kibana kibanav1.Kibana
 				err := f.Client.Get(context.Background(), types.NamespacedName{Namespace: "test", Name: "test-kibana"}, &kibana)
 				require.NoError(t, err)
-				require.Equal(t, kibanav1.KibanaStatus{
+				require.Empty(t, cmp.Diff(kibanav1.KibanaStatus{
 					DeploymentStatus: commonv1.DeploymentStatus{
 						Selector:       "common.k8s.elastic.co/type=kibana,kibana.k8s.elastic.co/name=test-kibana",
 						Count:          0,
 						AvailableNodes: 0,
 						Version:        "",
 						Health:         commonv1.RedHealth,
+						Conditions:     notRunningConditions("red", ""),
 					},
 					ObservedGeneration: 2,
-				}, kibana.Status)
+				}, kibana.Status, cmpopts.IgnoreTypes(metav1.Time{})))
 			},
 		},
 	}
@@ -440,3 +451,24 @@ func (k *k8sFailingStatusUpdateClient) Status() client.StatusWriter {
 func (sw *k8sFailingStatusWriter) Update(_ context.Context, _ client.Object, _ ...client.SubResourceUpdateOption) error {
 	return errors.New("internal error")
 }
+
+// notRunningConditions returns the standard conditions of a Kibana at generation 2 which is not running yet, stalled
+// with the given validation error if any.
+func notRunningConditions(health string, validationErr string) commonv1alpha1.Conditions {
+	reconciling := commonv1alpha1.Condition{Type: commonv1alpha1.ReconcilingCondition, Status: corev1.ConditionTrue, ObservedGeneration: 2, Reason: commonv1alpha1.ReconcilingReason, Message: "Health is " + health}
+	stalled := commonv1alpha1.Condition{Type: commonv1alpha1.StalledCondition, Status: corev1.ConditionFalse, ObservedGeneration: 2, Reason: commonv1alpha1.ProgressingReason}
+	reconciled := commonv1alpha1.Condition{Type: commonv1alpha1.ReconciliationCompleteCondition, Status: corev1.ConditionTrue, ObservedGeneration: 2, Reason: commonv1alpha1.ReconciledReason}
+	if validationErr != "" {
+		reconciling = commonv1alpha1.Condition{Type: commonv1alpha1.ReconcilingCondition, Status: corev1.ConditionFalse, ObservedGeneration: 2, Reason: commonv1alpha1.StalledReason}
+		stalled = commonv1alpha1.Condition{Type: commonv1alpha1.StalledCondition, Status: corev1.ConditionTrue, ObservedGeneration: 2, Reason: commonv1alpha1.StalledReason, Message: validationErr}
+		reconciled = commonv1alpha1.Condition{Type: commonv1alpha1.ReconciliationCompleteCondition, Status: corev1.ConditionFalse, ObservedGeneration: 2, Reason: commonv1alpha1.ReconcilingReason, Message: validationErr}
+	}
+	return commonv1alpha1.Conditions{
+		{Type: commonv1alpha1.ReadyCondition, Status: corev1.ConditionFalse, ObservedGeneration: 2, Reason: commonv1alpha1.UnavailableReason, Message: "Health is " + health},
+		reconciling,
+		stalled,
+		reconciled,
+		{Type: commonv1alpha1.DegradedCondition, Status: corev1.ConditionTrue, ObservedGeneration: 2, Reason: commonv1alpha1.DegradedReason, Message: "Health is " + health},
+		{Type: commonv1alpha1.UpgradeInProgressCondition, Status: corev1.ConditionUnknown, ObservedGeneration: 2, Reason: commonv1alpha1.VersionUnknownReason, Message: "No running version reported"},
+	}
+}
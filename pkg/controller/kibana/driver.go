@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"hash/fnv"
+	"time"
 
 	pkgerrors "github.com/pkg/errors"
 	"go.elastic.co/apm/v2"
@@ -25,17 +26,22 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/deployment"
 	driver2 "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/driver"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/effectivespec"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/gateway"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/restart"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/upgradegate"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	commonvolume "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
 	kblabel "github.com/elastic/cloud-on-k8s/v2/pkg/controller/kibana/label"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/kibana/network"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/kibana/stackmon"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/kibanaconfig"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
@@ -121,12 +127,15 @@ func (d *driver) Reconcile(
 		// TODO: consider updating some status here?
 		return results.WithError(err)
 	}
+	if err := gateway.ReconcileRoute(ctx, d.client, kb, kb.Spec.HTTP, *svc, kb.GetIdentityLabels()); err != nil {
+		return results.WithError(err)
+	}
 
 	_, results = certificates.Reconciler{
 		K8sClient:             d.K8sClient(),
 		DynamicWatches:        d.DynamicWatches(),
 		Owner:                 kb,
-		TLSOptions:            kb.Spec.HTTP.TLS,
+		TLSOptions:            kb.Spec.HTTP.EffectiveTLS(),
 		Namer:                 kbv1.KBNamer,
 		Labels:                kb.GetIdentityLabels(),
 		Services:              []corev1.Service{*svc},
@@ -178,12 +187,42 @@ func (d *driver) Reconcile(
 	span, _ := apm.StartSpan(ctx, "reconcile_deployment", tracing.SpanTypeApp)
 	defer span.End()
 
+	// start the restart of the Pods requested by the user, if any, before their template is built
+	if err := restart.Start(ctx, d.client, d.recorder, kb); err != nil {
+		return results.WithError(err)
+	}
+
+	// hold the version upgrade until an external analysis tool approves it, if requested
+	approved, err := d.upgradeGateApproved(ctx, kb)
+	if err != nil {
+		return results.WithError(err)
+	}
+	if !approved {
+		reason := fmt.Sprintf("Kibana upgrade: waiting for the approval of step %s", kb.Status.UpgradeGate.Step)
+		return results.WithReconciliationState(reconciler.RequeueAfter(30 * time.Second).WithReason(reason))
+	}
+
 	deploymentParams, err := d.deploymentParams(ctx, kb, kibanaPolicyCfg.PodAnnotations, basePath, params.SetDefaultSecurityContext)
 	if err != nil {
 		return results.WithError(err)
 	}
+	results.WithReconciliationState(keystore.RefreshResult(kb))
 
 	expectedDp := deployment.New(deploymentParams)
+	if err := effectivespec.Reconcile(ctx, d.client, kb, kbv1.KBNamer.Suffix(kb.Name, effectivespec.Suffix), kb.Spec, &expectedDp); err != nil {
+		return results.WithError(err)
+	}
+	if params.InPlacePodResize {
+		// Pods for which only the resources changed are resized without being replaced
+		var resizing bool
+		expectedDp, resizing, err = deployment.ResizePodsInPlace(ctx, d.client, expectedDp, d.heapFollowsMemory(kb))
+		if err != nil {
+			return results.WithError(err)
+		}
+		if resizing {
+			results.WithReconciliationState(reconciler.RequeueAfter(10 * time.Second).WithReason("Kibana Pods resize in progress"))
+		}
+	}
 	reconciledDp, err := deployment.Reconcile(ctx, d.client, expectedDp, kb)
 	if err != nil {
 		return results.WithError(err)
@@ -198,14 +237,53 @@ func (d *driver) Reconcile(
 		return results.WithError(err)
 	}
 	state.Kibana.Status.DeploymentStatus = deploymentStatus
+	if err := restart.UpdateProgress(ctx, d.client, d.recorder, kb, existingPods, kb.Spec.Count); err != nil {
+		return results.WithError(err)
+	}
 
-	return results
+	return results.WithResults(kibanaconfig.Reconcile(ctx, kibanaconfig.Params{
+		Client:         d.client,
+		DynamicWatches: d.dynamicWatches,
+		Recorder:       d.recorder,
+		Dialer:         params.Dialer,
+		APIProvider:    kibanaconfig.NewAPI,
+		Kibana:         state.Kibana,
+		BasePath:       basePath,
+	}))
+}
+
+// upgradeGateApproved returns true if the Pods of the given Kibana can be upgraded to its version according to its
+// upgrade gates. Kibana Pods are all replaced at once during version upgrades: the upgrade is only paused before it starts.
+func (d *driver) upgradeGateApproved(ctx context.Context, kb *kbv1.Kibana) (bool, error) {
+	pods, err := k8s.PodsMatchingLabels(d.client, kb.Namespace, map[string]string{kblabel.KibanaNameLabelName: kb.Name})
+	if err != nil {
+		return false, err
+	}
+	var upgradedPods int32
+	versionUpgrade := false
+	for _, pod := range pods {
+		if pod.Labels[kblabel.KibanaVersionLabelName] == kb.Spec.Version {
+			upgradedPods++
+		} else {
+			versionUpgrade = true
+		}
+	}
+	if !versionUpgrade || upgradedPods > 0 {
+		upgradegate.Clear(kb)
+		return true, nil
+	}
+	return upgradegate.Approved(ctx, d.recorder, kb, kb.Spec.Version, upgradedPods, kb.Spec.Count), nil
 }
 
 // getStrategyType decides which deployment strategy (RollingUpdate or Recreate) to use based on whether the version
 // upgrade is in progress. Kibana does not support a smooth rolling upgrade from one version to another:
 // running multiple versions simultaneously may lead to concurrency bugs and data corruption.
+// Recreate is also used for the full restarts requested by the user.
 func (d *driver) getStrategyType(kb *kbv1.Kibana) (appsv1.DeploymentStrategyType, error) {
+	if restart.IsFull(kb) {
+		return appsv1.RecreateDeploymentStrategyType, nil
+	}
+
 	var pods corev1.PodList
 	var labels client.MatchingLabels = map[string]string{kblabel.KibanaNameLabelName: kb.Name}
 	if err := d.client.List(context.Background(), &pods, client.InNamespace(kb.Namespace), labels); err != nil {
@@ -265,7 +343,7 @@ func (d *driver) deploymentParams(ctx context.Context, kb *kbv1.Kibana, policyAn
 		return deployment.Params{}, err
 	}
 
-	if kb.Spec.HTTP.TLS.Enabled() {
+	if kb.Spec.HTTP.TLSEnabled() {
 		// fetch the secret to calculate the checksum
 		var httpCerts corev1.Secret
 		err := d.client.Get(ctx, types.NamespacedName{
@@ -292,6 +370,9 @@ func (d *driver) deploymentParams(ctx context.Context, kb *kbv1.Kibana, policyAn
 	// changes, which will trigger a rolling update)
 	kibanaPodSpec.Annotations[configHashAnnotationName] = fmt.Sprint(configHash.Sum32())
 
+	// add the start time of the last restart requested by the user, to restart the Pods when a new one is requested
+	kibanaPodSpec.Annotations = maps.Merge(kibanaPodSpec.Annotations, restart.PodAnnotations(kb))
+
 	// add additional annotations related to the StackConfigPolicy
 	kibanaPodSpec.Annotations = maps.Merge(kibanaPodSpec.Annotations, policyAnnotations)
 
@@ -334,7 +415,7 @@ func (d *driver) buildVolumes(kb *kbv1.Kibana) ([]commonvolume.VolumeLike, error
 		volumes = append(volumes, entCertsVolume)
 	}
 
-	if kb.Spec.HTTP.TLS.Enabled() {
+	if kb.Spec.HTTP.TLSEnabled() {
 		httpCertsVolume := certificates.HTTPCertSecretVolume(kbv1.KBNamer, kb.Name)
 		volumes = append(volumes, httpCertsVolume)
 	}
@@ -78,6 +78,7 @@ func Test_getStrategyType(t *testing.T) {
 		expectedKbName  string
 		expectedVersion string
 		initialObjects  []client.Object
+		restart         *commonv1.RestartStatus
 		clientError     bool
 		wantErr         bool
 		wantStrategy    appsv1.DeploymentStrategyType
@@ -158,6 +159,22 @@ func Test_getStrategyType(t *testing.T) {
 			wantErr:         false,
 			wantStrategy:    appsv1.RecreateDeploymentStrategyType,
 		},
+		{
+			name:            "Versions match - full restart in progress",
+			expectedVersion: "7.4.0",
+			expectedKbName:  "test",
+			initialObjects:  getPods("test", 3, "7.4.0"),
+			restart:         &commonv1.RestartStatus{Type: commonv1.FullRestart},
+			wantStrategy:    appsv1.RecreateDeploymentStrategyType,
+		},
+		{
+			name:            "Versions match - rolling restart in progress",
+			expectedVersion: "7.4.0",
+			expectedKbName:  "test",
+			initialObjects:  getPods("test", 3, "7.4.0"),
+			restart:         &commonv1.RestartStatus{Type: commonv1.RollingRestart},
+			wantStrategy:    appsv1.RollingUpdateDeploymentStrategyType,
+		},
 		{
 			name:            "Client error",
 			expectedVersion: "7.4.0",
@@ -176,6 +193,7 @@ func Test_getStrategyType(t *testing.T) {
 			kb := kibanaFixture()
 			kb.Name = tt.expectedKbName
 			kb.Spec.Version = tt.expectedVersion
+			kb.Status.Restart = tt.restart
 
 			client := k8s.NewFakeClient(tt.initialObjects...)
 			if tt.clientError {
@@ -196,6 +214,71 @@ func Test_getStrategyType(t *testing.T) {
 	}
 }
 
+func Test_upgradeGateApproved(t *testing.T) {
+	kbPod := func(name, version string) client.Object {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+			Labels:    map[string]string{kblabel.KibanaNameLabelName: "kb", kblabel.KibanaVersionLabelName: version},
+		}}
+	}
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		pods         []client.Object
+		wantApproved bool
+		wantStep     string
+	}{
+		{
+			name:         "upgrade gates not enabled",
+			pods:         []client.Object{kbPod("kb-0", "8.15.0")},
+			wantApproved: true,
+		},
+		{
+			name:         "no version upgrade",
+			annotations:  map[string]string{commonv1.UpgradeGatesAnnotation: "true"},
+			pods:         []client.Object{kbPod("kb-0", "8.16.0")},
+			wantApproved: true,
+		},
+		{
+			name:        "version upgrade waiting for approval",
+			annotations: map[string]string{commonv1.UpgradeGatesAnnotation: "true"},
+			pods:        []client.Object{kbPod("kb-0", "8.15.0"), kbPod("kb-1", "8.15.0")},
+			wantStep:    "8.16.0/0",
+		},
+		{
+			name:         "version upgrade approved",
+			annotations:  map[string]string{commonv1.UpgradeGatesAnnotation: "true", commonv1.UpgradeGateApprovedAnnotation: "8.16.0/0"},
+			pods:         []client.Object{kbPod("kb-0", "8.15.0"), kbPod("kb-1", "8.15.0")},
+			wantApproved: true,
+		},
+		{
+			name:         "version upgrade in progress",
+			annotations:  map[string]string{commonv1.UpgradeGatesAnnotation: "true"},
+			pods:         []client.Object{kbPod("kb-0", "8.15.0"), kbPod("kb-1", "8.16.0")},
+			wantApproved: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kb := &kbv1.Kibana{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kb", Annotations: tt.annotations},
+				Spec:       kbv1.KibanaSpec{Version: "8.16.0", Count: 2},
+			}
+			d := &driver{client: k8s.NewFakeClient(tt.pods...), recorder: record.NewFakeRecorder(10)}
+			approved, err := d.upgradeGateApproved(context.Background(), kb)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantApproved, approved)
+			if tt.wantStep == "" {
+				require.Nil(t, kb.Status.UpgradeGate)
+				return
+			}
+			require.Equal(t, tt.wantStep, kb.Status.UpgradeGate.Step)
+			require.Equal(t, int32(2), kb.Status.UpgradeGate.ExpectedPods)
+		})
+	}
+}
+
 func TestDriverDeploymentParams(t *testing.T) {
 	type args struct {
 		kb                            func() *kbv1.Kibana
@@ -126,13 +126,16 @@ func NewPodTemplateSpec(
 	if err != nil {
 		return corev1.PodTemplateSpec{}, err // error unlikely and should have been caught during validation
 	}
+	if err := container.CheckImageCatalog(kb.Spec.Image, container.KibanaImage, v); err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
 
 	builder := defaults.NewPodTemplateBuilder(kb.Spec.PodTemplate, kbv1.KibanaContainerName).
 		WithResources(DefaultResources).
 		WithLabels(labels).
 		WithAnnotations(DefaultAnnotations).
 		WithDockerImage(kb.Spec.Image, container.ImageRepository(container.KibanaImage, v)).
-		WithReadinessProbe(readinessProbe(kb.Spec.HTTP.TLS.Enabled(), basePath)).
+		WithReadinessProbe(readinessProbe(kb.Spec.HTTP.TLSEnabled(), basePath)).
 		WithPorts(ports).
 		WithInitContainers(initConfigContainer(kb))
 
@@ -164,7 +167,7 @@ func NewPodTemplateSpec(
 		return corev1.PodTemplateSpec{}, err
 	}
 
-	return builder.WithInitContainerDefaults().PodTemplate, nil
+	return builder.WithInitContainerDefaults().WithServiceMesh(kb.Spec.HTTP.ServiceMesh).WithPodMutationPolicy(kbv1.Kind, kb.Namespace).PodTemplate, nil
 }
 
 // GetKibanaContainer returns the Kibana container from the given podSpec.
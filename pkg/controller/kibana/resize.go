@@ -0,0 +1,38 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kibana
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+)
+
+// heapFollowsMemory returns a function indicating whether the given container of the Kibana Pods can use a new amount
+// of memory without being restarted. The Node.js heap is sized when Kibana starts: a change of memory requires a
+// restart unless the heap size is set in the Node options. An event reports the Pods restarted for that reason.
+func (d *driver) heapFollowsMemory(kb *kbv1.Kibana) func(corev1.Container) bool {
+	return func(container corev1.Container) bool {
+		if container.Name != kbv1.KibanaContainerName || hasFixedHeapSize(container) {
+			return true
+		}
+		d.recorder.Eventf(kb, corev1.EventTypeNormal, events.EventReasonRestartRequired,
+			"Restarting the Kibana Pods to change their memory: the heap size is not set with --max-old-space-size in %s", EnvNodeOptions)
+		return false
+	}
+}
+
+// hasFixedHeapSize returns true if the maximum heap size is set in the Node options of the given container.
+func hasFixedHeapSize(container corev1.Container) bool {
+	for _, env := range container.Env {
+		if env.Name == EnvNodeOptions && strings.Contains(env.Value, "--max-old-space-size") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,32 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kibana
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+)
+
+func Test_driver_heapFollowsMemory(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	d := &driver{recorder: recorder}
+	heapFollowsMemory := d.heapFollowsMemory(&kbv1.Kibana{})
+
+	assert.True(t, heapFollowsMemory(corev1.Container{Name: "sidecar"}))
+	assert.True(t, heapFollowsMemory(corev1.Container{
+		Name: kbv1.KibanaContainerName,
+		Env:  []corev1.EnvVar{{Name: EnvNodeOptions, Value: "--max-old-space-size=2048"}},
+	}))
+	assert.Empty(t, recorder.Events)
+
+	// the heap is sized from the memory when Kibana starts
+	assert.False(t, heapFollowsMemory(corev1.Container{Name: kbv1.KibanaContainerName}))
+	assert.Len(t, recorder.Events, 1)
+}
@@ -65,7 +65,7 @@ func Metricbeat(ctx context.Context, client k8s.Client, kb kbv1.Kibana, basePath
 	if err != nil {
 		return stackmon.BeatSidecar{}, err // error unlikely and should have been caught during validation
 	}
-	caVol, err := stackmon.CAVolume(client, k8s.ExtractNamespacedName(&kb), kbv1.KBNamer, commonv1.KbMonitoringAssociationType, kb.Spec.HTTP.TLS.Enabled())
+	caVol, err := stackmon.CAVolume(client, k8s.ExtractNamespacedName(&kb), kbv1.KBNamer, commonv1.KbMonitoringAssociationType, kb.Spec.HTTP.TLSEnabled())
 	if err != nil {
 		return stackmon.BeatSidecar{}, err
 	}
@@ -80,7 +80,7 @@ func Metricbeat(ctx context.Context, client k8s.Client, kb kbv1.Kibana, basePath
 			Username: username,
 			Password: password,
 			URL:      fmt.Sprintf("%s://localhost:%d", kb.Spec.HTTP.Protocol(), network.HTTPPort), // Metricbeat in the sidecar connects to the monitored resource using `localhost`
-			IsSSL:    kb.Spec.HTTP.TLS.Enabled(),                                                  // enable SSL configuration based on whether the monitored resource has TLS enabled
+			IsSSL:    kb.Spec.HTTP.TLSEnabled(),                                                   // enable SSL configuration based on whether the monitored resource has TLS enabled
 			CAVolume: caVol,
 		},
 		BasePath: basePath,
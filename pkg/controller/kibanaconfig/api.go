@@ -0,0 +1,224 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kibanaconfig
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.elastic.co/apm/module/apmhttp/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/association"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	commonhttp "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/http"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/safemode"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/user"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/stringsutil"
+)
+
+// API is the subset of the Kibana API used to provision content.
+type API interface {
+	// UpsertSpace creates the space with the given identifier, or updates it if it already exists.
+	UpsertSpace(ctx context.Context, id string, space json.RawMessage) error
+	// PutRole creates or updates the role with the given name.
+	PutRole(ctx context.Context, name string, role json.RawMessage) error
+	// ImportSavedObjects imports an NDJSON export of saved objects into the given space, overwriting existing objects.
+	ImportSavedObjects(ctx context.Context, space, fileName string, ndjson []byte) error
+	// Close releases the resources held by the API client.
+	Close()
+}
+
+// APIProvider returns an API client for the given Kibana.
+type APIProvider func(ctx context.Context, c k8s.Client, dialer net.Dialer, kb kbv1.Kibana, basePath string) (API, error)
+
+// importResponse is the response of the saved objects import API.
+type importResponse struct {
+	Success      bool `json:"success"`
+	SuccessCount int  `json:"successCount"`
+	Errors       []struct {
+		ID    string `json:"id"`
+		Type  string `json:"type"`
+		Error struct {
+			Type string `json:"type"`
+		} `json:"error"`
+	} `json:"errors"`
+}
+
+type kibanaAPI struct {
+	client   *http.Client
+	endpoint string
+	username string
+	password string
+}
+
+var _ API = kibanaAPI{}
+
+// NewAPI returns a client of the Kibana API of the given Kibana. Requests are authenticated with the operator
+// user of the Elasticsearch cluster referenced by Kibana.
+func NewAPI(ctx context.Context, c k8s.Client, dialer net.Dialer, kb kbv1.Kibana, basePath string) (API, error) {
+	esRef := kb.Spec.ElasticsearchRef.WithDefaultNamespace(kb.Namespace)
+	if !esRef.IsDefined() || esRef.IsExternal() {
+		return nil, errors.New("provisioning requires an elasticsearchRef to a cluster managed by ECK")
+	}
+	var usersSecret corev1.Secret
+	key := types.NamespacedName{Namespace: esRef.Namespace, Name: esv1.InternalUsersSecret(esRef.Name)}
+	if err := c.Get(ctx, key, &usersSecret); err != nil {
+		return nil, err
+	}
+	password, ok := usersSecret.Data[user.ControllerUserName]
+	if !ok {
+		return nil, fmt.Errorf("controller user %s not found in Secret %s", user.ControllerUserName, key)
+	}
+
+	var caCerts []*x509.Certificate
+	if kb.Spec.HTTP.TLSEnabled() {
+		var caSecret corev1.Secret
+		key := types.NamespacedName{Namespace: kb.Namespace, Name: certificates.PublicCertsSecretName(kbv1.KBNamer, kb.Name)}
+		if err := c.Get(ctx, key, &caSecret); err != nil {
+			return nil, err
+		}
+		trustedCerts, ok := caSecret.Data[certificates.CertFileName]
+		if !ok {
+			return nil, fmt.Errorf("%s not found in Secret %s", certificates.CertFileName, key)
+		}
+		certs, err := certificates.ParsePEMCerts(trustedCerts)
+		if err != nil {
+			return nil, err
+		}
+		caCerts = certs
+	}
+
+	endpoint, err := association.ServiceURL(c, types.NamespacedName{Namespace: kb.Namespace, Name: kbv1.HTTPService(kb.Name)}, kb.Spec.HTTP.Protocol(), basePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return kibanaAPI{
+		client: apmhttp.WrapClient(
+			commonhttp.Client(dialer, caCerts, 60*time.Second),
+			apmhttp.WithClientRequestName(tracing.RequestName),
+			apmhttp.WithClientSpanType("external.kibana"),
+		),
+		endpoint: endpoint,
+		username: user.ControllerUserName,
+		password: string(password),
+	}, nil
+}
+
+func (k kibanaAPI) request(ctx context.Context, method, path, contentType string, body io.Reader, responseObj interface{}) error {
+	request, err := http.NewRequestWithContext(ctx, method, stringsutil.Concat(k.endpoint, path), body)
+	if err != nil {
+		return err
+	}
+	if err := safemode.CheckRequest(ctx, method, request.URL.Redacted()); err != nil {
+		return err
+	}
+	request.Header.Set(commonhttp.InternalProductRequestHeaderKey, commonhttp.InternalProductRequestHeaderValue)
+	request.Header.Set("kbn-xsrf", "true")
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+	request.SetBasicAuth(k.username, k.password)
+
+	ulog.FromContext(ctx).V(1).Info(
+		"Kibana API HTTP request",
+		"method", request.Method,
+		"url", request.URL.Redacted(),
+	)
+
+	resp, err := k.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := commonhttp.MaybeAPIError(resp); err != nil {
+		return err
+	}
+	if responseObj != nil {
+		if err := json.NewDecoder(resp.Body).Decode(responseObj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (k kibanaAPI) requestJSON(ctx context.Context, method, path string, document json.RawMessage) error {
+	var body io.Reader = http.NoBody
+	if document != nil {
+		body = bytes.NewReader(document)
+	}
+	return k.request(ctx, method, path, "application/json", body, nil)
+}
+
+func (k kibanaAPI) UpsertSpace(ctx context.Context, id string, space json.RawMessage) error {
+	path := fmt.Sprintf("/api/spaces/space/%s", url.PathEscape(id))
+	err := k.requestJSON(ctx, http.MethodGet, path, nil)
+	if commonhttp.IsNotFound(err) {
+		return k.requestJSON(ctx, http.MethodPost, "/api/spaces/space", space)
+	}
+	if err != nil {
+		return err
+	}
+	return k.requestJSON(ctx, http.MethodPut, path, space)
+}
+
+func (k kibanaAPI) PutRole(ctx context.Context, name string, role json.RawMessage) error {
+	return k.requestJSON(ctx, http.MethodPut, fmt.Sprintf("/api/security/role/%s", url.PathEscape(name)), role)
+}
+
+func (k kibanaAPI) ImportSavedObjects(ctx context.Context, space, fileName string, ndjson []byte) error {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", fileName)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(ndjson); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	path := "/api/saved_objects/_import?overwrite=true"
+	if space != "" && space != DefaultSpace {
+		path = fmt.Sprintf("/s/%s%s", url.PathEscape(space), path)
+	}
+	var response importResponse
+	if err := k.request(ctx, http.MethodPost, path, writer.FormDataContentType(), &body, &response); err != nil {
+		return err
+	}
+	if !response.Success {
+		failures := make([]string, 0, len(response.Errors))
+		for _, e := range response.Errors {
+			failures = append(failures, fmt.Sprintf("%s/%s: %s", e.Type, e.ID, e.Error.Type))
+		}
+		return fmt.Errorf("%d saved objects imported from %s, failed to import [%s]", response.SuccessCount, fileName, strings.Join(failures, ", "))
+	}
+	return nil
+}
+
+func (k kibanaAPI) Close() {
+	k.client.CloseIdleConnections()
+}
@@ -0,0 +1,116 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kibanaconfig
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAPI(t *testing.T, handler http.HandlerFunc) kibanaAPI {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "true", r.Header.Get("kbn-xsrf"))
+		username, password, ok := r.BasicAuth()
+		require.True(t, ok)
+		require.Equal(t, "elastic-internal", username)
+		require.Equal(t, "secret", password)
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return kibanaAPI{client: server.Client(), endpoint: server.URL, username: "elastic-internal", password: "secret"}
+}
+
+func TestKibanaAPI_UpsertSpace(t *testing.T) {
+	for _, tt := range []struct {
+		name         string
+		exists       bool
+		wantRequests []string
+	}{
+		{
+			name:         "create a missing space",
+			wantRequests: []string{"GET /api/spaces/space/team-a", "POST /api/spaces/space"},
+		},
+		{
+			name:         "update an existing space",
+			exists:       true,
+			wantRequests: []string{"GET /api/spaces/space/team-a", "PUT /api/spaces/space/team-a"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			api := newTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method+" "+r.URL.Path)
+				if r.Method == http.MethodGet {
+					if !tt.exists {
+						w.WriteHeader(http.StatusNotFound)
+					}
+					return
+				}
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				require.JSONEq(t, `{"id":"team-a","name":"Team A"}`, string(body))
+			})
+			require.NoError(t, api.UpsertSpace(context.Background(), "team-a", []byte(`{"id":"team-a","name":"Team A"}`)))
+			require.Equal(t, tt.wantRequests, requests)
+		})
+	}
+}
+
+func TestKibanaAPI_ImportSavedObjects(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		space    string
+		response string
+		wantPath string
+		wantErr  string
+	}{
+		{
+			name:     "import into the default space",
+			response: `{"success":true,"successCount":1}`,
+			wantPath: "/api/saved_objects/_import",
+		},
+		{
+			name:     "import into a space",
+			space:    "team-a",
+			response: `{"success":true,"successCount":1}`,
+			wantPath: "/s/team-a/api/saved_objects/_import",
+		},
+		{
+			name:     "partial import failure",
+			response: `{"success":false,"successCount":0,"errors":[{"id":"overview","type":"dashboard","error":{"type":"missing_references"}}]}`,
+			wantPath: "/api/saved_objects/_import",
+			wantErr:  "0 saved objects imported from overview.ndjson, failed to import [dashboard/overview: missing_references]",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, http.MethodPost, r.Method)
+				require.Equal(t, tt.wantPath, r.URL.Path)
+				require.Equal(t, "true", r.URL.Query().Get("overwrite"))
+				file, header, err := r.FormFile("file")
+				require.NoError(t, err)
+				require.Equal(t, "overview.ndjson", header.Filename)
+				data, err := io.ReadAll(file)
+				require.NoError(t, err)
+				require.Equal(t, `{"type":"dashboard","id":"overview"}`, string(data))
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tt.response))
+			})
+			err := api.ImportSavedObjects(context.Background(), tt.space, "overview.ndjson", []byte(`{"type":"dashboard","id":"overview"}`))
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
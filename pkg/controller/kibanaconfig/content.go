@@ -0,0 +1,150 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kibanaconfig
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+const (
+	// DefaultSpace is the identifier of the Kibana default space.
+	DefaultSpace = "default"
+
+	jsonExtension   = ".json"
+	ndjsonExtension = ".ndjson"
+)
+
+// document is an entry of a ConfigMap or a Secret referenced in the provisioning spec.
+type document struct {
+	// name identifies the document in Kibana: space identifier, role name, or saved objects file name.
+	name string
+	// space is the space saved objects are imported into.
+	space string
+	data  []byte
+}
+
+// content is the content declared in the provisioning spec of a Kibana, in the order it is imported.
+type content struct {
+	spaces       []document
+	roles        []document
+	savedObjects []document
+}
+
+// hash returns a hash of the content, which changes whenever a document is added, removed or updated.
+func (c content) hash() string {
+	h := fnv.New32a()
+	for kind, documents := range [][]document{c.spaces, c.roles, c.savedObjects} {
+		for _, d := range documents {
+			_, _ = fmt.Fprintf(h, "%d/%s/%s/%d:", kind, d.space, d.name, len(d.data))
+			_, _ = h.Write(d.data)
+		}
+	}
+	return fmt.Sprint(h.Sum32())
+}
+
+// sourceName returns a human-readable name of a ConfigMap or a Secret referenced in the provisioning spec.
+func sourceName(source kbv1.ProvisioningSource) string {
+	if source.ConfigMapName != "" {
+		return "ConfigMap " + source.ConfigMapName
+	}
+	return "Secret " + source.SecretName
+}
+
+// getSourceData returns the entries of a ConfigMap or a Secret referenced in the provisioning spec, sorted by key.
+func getSourceData(ctx context.Context, c k8s.Client, namespace string, source kbv1.ProvisioningSource) ([]string, map[string][]byte, error) {
+	data := map[string][]byte{}
+	if source.ConfigMapName != "" {
+		var configMap corev1.ConfigMap
+		if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: source.ConfigMapName}, &configMap); err != nil {
+			return nil, nil, err
+		}
+		for k, v := range configMap.Data {
+			data[k] = []byte(v)
+		}
+		for k, v := range configMap.BinaryData {
+			data[k] = v
+		}
+	} else {
+		var secret corev1.Secret
+		if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: source.SecretName}, &secret); err != nil {
+			return nil, nil, err
+		}
+		data = secret.Data
+	}
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys, data, nil
+}
+
+// loadContent retrieves the content declared in the provisioning spec of the given Kibana.
+func loadContent(ctx context.Context, c k8s.Client, kb kbv1.Kibana) (content, error) {
+	var result content
+	provisioning := kb.Spec.Provisioning
+	if !provisioning.IsDefined() {
+		return result, nil
+	}
+
+	for _, source := range provisioning.Spaces {
+		keys, data, err := getSourceData(ctx, c, kb.Namespace, source)
+		if err != nil {
+			return content{}, err
+		}
+		for _, key := range keys {
+			var space struct {
+				ID string `json:"id"`
+			}
+			if err := json.Unmarshal(data[key], &space); err != nil {
+				return content{}, fmt.Errorf("invalid space %s in %s: %w", key, sourceName(source), err)
+			}
+			if space.ID == "" {
+				return content{}, fmt.Errorf("invalid space %s in %s: id is missing", key, sourceName(source))
+			}
+			result.spaces = append(result.spaces, document{name: space.ID, data: data[key]})
+		}
+	}
+
+	for _, source := range provisioning.Roles {
+		keys, data, err := getSourceData(ctx, c, kb.Namespace, source)
+		if err != nil {
+			return content{}, err
+		}
+		for _, key := range keys {
+			if !json.Valid(data[key]) {
+				return content{}, fmt.Errorf("invalid role %s in %s: not a JSON document", key, sourceName(source))
+			}
+			result.roles = append(result.roles, document{name: strings.TrimSuffix(key, jsonExtension), data: data[key]})
+		}
+	}
+
+	for _, source := range provisioning.SavedObjects {
+		keys, data, err := getSourceData(ctx, c, kb.Namespace, source.ProvisioningSource)
+		if err != nil {
+			return content{}, err
+		}
+		for _, key := range keys {
+			// the saved objects import API only accepts files with the .ndjson extension
+			fileName := key
+			if !strings.HasSuffix(fileName, ndjsonExtension) {
+				fileName += ndjsonExtension
+			}
+			result.savedObjects = append(result.savedObjects, document{name: fileName, space: source.Space, data: data[key]})
+		}
+	}
+	return result, nil
+}
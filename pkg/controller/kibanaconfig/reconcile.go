@@ -0,0 +1,151 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kibanaconfig
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pkgerrors "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+// unavailableRequeue is the delay before checking again whether Kibana is available to import content.
+const unavailableRequeue = 10 * time.Second
+
+// WatchName returns the name of the watches registered on the ConfigMaps and Secrets referenced in the provisioning
+// spec of the given Kibana.
+func WatchName(kb types.NamespacedName) string {
+	return fmt.Sprintf("%s-%s-provisioning", kb.Namespace, kb.Name)
+}
+
+// Params are the parameters to provision content into Kibana.
+type Params struct {
+	Client         k8s.Client
+	DynamicWatches watches.DynamicWatches
+	Recorder       record.EventRecorder
+	Dialer         net.Dialer
+	APIProvider    APIProvider
+	// Kibana is the Kibana to provision, its status is updated with the hash of the imported content.
+	Kibana *kbv1.Kibana
+	// BasePath is the path under which Kibana is served.
+	BasePath string
+}
+
+// Reconcile imports the content declared in the provisioning spec of Kibana once Kibana is available. Content is
+// imported again only when its hash differs from the hash recorded in the status of Kibana.
+func Reconcile(ctx context.Context, params Params) *reconciler.Results {
+	results := reconciler.NewResult(ctx)
+	kb := params.Kibana
+
+	if err := reconcileWatches(params); err != nil {
+		return results.WithError(err)
+	}
+	if !kb.Spec.Provisioning.IsDefined() {
+		kb.Status.ProvisioningHash = ""
+		return results
+	}
+
+	content, err := loadContent(ctx, params.Client, *kb)
+	if err != nil {
+		return results.WithError(pkgerrors.Wrap(err, "while loading Kibana provisioning content"))
+	}
+	hash := content.hash()
+	if hash == kb.Status.ProvisioningHash {
+		return results
+	}
+
+	log := ulog.FromContext(ctx)
+	if kb.Status.Health != commonv1.GreenHealth {
+		log.Info("Delaying Kibana provisioning as Kibana is not available yet", "namespace", kb.Namespace, "kibana_name", kb.Name)
+		return results.WithResult(reconcile.Result{RequeueAfter: unavailableRequeue})
+	}
+
+	api, err := params.APIProvider(ctx, params.Client, params.Dialer, *kb, params.BasePath)
+	if err != nil {
+		return results.WithError(err)
+	}
+	defer api.Close()
+
+	if err := importContent(ctx, api, content); err != nil {
+		params.Recorder.Eventf(kb, corev1.EventTypeWarning, events.EventReasonUnexpected, "Failed to provision Kibana: %s", err.Error())
+		return results.WithError(pkgerrors.Wrap(err, "while provisioning Kibana"))
+	}
+
+	log.Info("Kibana provisioned", "namespace", kb.Namespace, "kibana_name", kb.Name,
+		"spaces", len(content.spaces), "roles", len(content.roles), "saved_objects_files", len(content.savedObjects))
+	kb.Status.ProvisioningHash = hash
+	return results
+}
+
+// importContent imports spaces first, then roles which may grant privileges on those spaces, then saved objects
+// which may be imported into those spaces.
+func importContent(ctx context.Context, api API, content content) error {
+	for _, space := range content.spaces {
+		if err := api.UpsertSpace(ctx, space.name, space.data); err != nil {
+			return pkgerrors.Wrapf(err, "space %s", space.name)
+		}
+	}
+	for _, role := range content.roles {
+		if err := api.PutRole(ctx, role.name, role.data); err != nil {
+			return pkgerrors.Wrapf(err, "role %s", role.name)
+		}
+	}
+	for _, savedObjects := range content.savedObjects {
+		if err := api.ImportSavedObjects(ctx, savedObjects.space, savedObjects.name, savedObjects.data); err != nil {
+			return pkgerrors.Wrapf(err, "saved objects %s", savedObjects.name)
+		}
+	}
+	return nil
+}
+
+// reconcileWatches watches the ConfigMaps and Secrets referenced in the provisioning spec, to import their content
+// again when it changes.
+func reconcileWatches(params Params) error {
+	kb := params.Kibana
+	kbNsn := k8s.ExtractNamespacedName(kb)
+	watchName := WatchName(kbNsn)
+
+	var configMaps []types.NamespacedName
+	var secrets []string
+	if kb.Spec.Provisioning != nil {
+		sources := append(append([]kbv1.ProvisioningSource{}, kb.Spec.Provisioning.Spaces...), kb.Spec.Provisioning.Roles...)
+		for _, savedObjects := range kb.Spec.Provisioning.SavedObjects {
+			sources = append(sources, savedObjects.ProvisioningSource)
+		}
+		for _, source := range sources {
+			if source.ConfigMapName != "" {
+				configMaps = append(configMaps, types.NamespacedName{Namespace: kb.Namespace, Name: source.ConfigMapName})
+			}
+			if source.SecretName != "" {
+				secrets = append(secrets, source.SecretName)
+			}
+		}
+	}
+
+	if len(configMaps) == 0 {
+		params.DynamicWatches.ConfigMaps.RemoveHandlerForKey(watchName)
+	} else if err := params.DynamicWatches.ConfigMaps.AddHandler(watches.NamedWatch[*corev1.ConfigMap]{
+		Name:    watchName,
+		Watched: configMaps,
+		Watcher: kbNsn,
+	}); err != nil {
+		return err
+	}
+	return watches.WatchUserProvidedSecrets(kbNsn, params.DynamicWatches, watchName, secrets)
+}
@@ -0,0 +1,201 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kibanaconfig
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+type fakeAPI struct {
+	calls []string
+	err   error
+}
+
+func (f *fakeAPI) UpsertSpace(_ context.Context, id string, _ json.RawMessage) error {
+	f.calls = append(f.calls, "space "+id)
+	return f.err
+}
+
+func (f *fakeAPI) PutRole(_ context.Context, name string, _ json.RawMessage) error {
+	f.calls = append(f.calls, "role "+name)
+	return f.err
+}
+
+func (f *fakeAPI) ImportSavedObjects(_ context.Context, space, fileName string, _ []byte) error {
+	f.calls = append(f.calls, "saved objects "+space+"/"+fileName)
+	return f.err
+}
+
+func (f *fakeAPI) Close() {}
+
+func (f *fakeAPI) provider(_ context.Context, _ k8s.Client, _ net.Dialer, _ kbv1.Kibana, _ string) (API, error) {
+	return f, nil
+}
+
+var (
+	spacesConfigMap = &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "spaces"},
+		Data: map[string]string{
+			"team-b.json": `{"id":"team-b","name":"Team B"}`,
+			"team-a.json": `{"id":"team-a","name":"Team A"}`,
+		},
+	}
+	rolesSecret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "roles"},
+		Data: map[string][]byte{
+			"team-a-viewer.json": []byte(`{"kibana":[{"base":["read"],"spaces":["team-a"]}]}`),
+		},
+	}
+	dashboardsConfigMap = &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "dashboards"},
+		Data: map[string]string{
+			"overview": `{"type":"dashboard","id":"overview","attributes":{"title":"Overview"}}`,
+		},
+	}
+)
+
+func provisionedKibana() *kbv1.Kibana {
+	return &kbv1.Kibana{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb"},
+		Spec: kbv1.KibanaSpec{
+			ElasticsearchRef: commonv1.ObjectSelector{Name: "es"},
+			Provisioning: &kbv1.ProvisioningSpec{
+				Spaces: []kbv1.ProvisioningSource{{ConfigMapName: "spaces"}},
+				Roles:  []kbv1.ProvisioningSource{{SecretName: "roles"}},
+				SavedObjects: []kbv1.SavedObjectsSource{
+					{ProvisioningSource: kbv1.ProvisioningSource{ConfigMapName: "dashboards"}, Space: "team-a"},
+				},
+			},
+		},
+		Status: kbv1.KibanaStatus{DeploymentStatus: commonv1.DeploymentStatus{Health: commonv1.GreenHealth}},
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	kb := provisionedKibana()
+	c := k8s.NewFakeClient(spacesConfigMap.DeepCopy(), rolesSecret.DeepCopy(), dashboardsConfigMap.DeepCopy())
+	api := &fakeAPI{}
+	params := Params{
+		Client:         c,
+		DynamicWatches: watches.NewDynamicWatches(),
+		Recorder:       record.NewFakeRecorder(10),
+		APIProvider:    api.provider,
+		Kibana:         kb,
+	}
+
+	// Kibana is not available yet
+	kb.Status.Health = commonv1.RedHealth
+	results := Reconcile(context.Background(), params)
+	require.False(t, results.HasError())
+	require.True(t, results.HasRequeue())
+	require.Empty(t, api.calls)
+	require.Empty(t, kb.Status.ProvisioningHash)
+	// referenced ConfigMaps and Secrets are watched
+	watchName := WatchName(types.NamespacedName{Namespace: "ns", Name: "kb"})
+	require.Equal(t, []string{watchName}, params.DynamicWatches.ConfigMaps.Registrations())
+	require.Equal(t, []string{watchName}, params.DynamicWatches.Secrets.Registrations())
+
+	// content is imported in order once Kibana is available
+	kb.Status.Health = commonv1.GreenHealth
+	results = Reconcile(context.Background(), params)
+	require.False(t, results.HasError())
+	require.False(t, results.HasRequeue())
+	require.Equal(t, []string{
+		"space team-a",
+		"space team-b",
+		"role team-a-viewer",
+		"saved objects team-a/overview.ndjson",
+	}, api.calls)
+	hash := kb.Status.ProvisioningHash
+	require.NotEmpty(t, hash)
+
+	// content is not imported again if it did not change
+	api.calls = nil
+	results = Reconcile(context.Background(), params)
+	require.False(t, results.HasError())
+	require.Empty(t, api.calls)
+
+	// content is imported again when it changes
+	var dashboards corev1.ConfigMap
+	require.NoError(t, c.Get(context.Background(), k8s.ExtractNamespacedName(dashboardsConfigMap), &dashboards))
+	dashboards.Data["overview"] = `{"type":"dashboard","id":"overview","attributes":{"title":"New overview"}}`
+	require.NoError(t, c.Update(context.Background(), &dashboards))
+	results = Reconcile(context.Background(), params)
+	require.False(t, results.HasError())
+	require.Len(t, api.calls, 4)
+	require.NotEqual(t, hash, kb.Status.ProvisioningHash)
+
+	// provisioning is removed from the spec
+	kb.Spec.Provisioning = nil
+	results = Reconcile(context.Background(), params)
+	require.False(t, results.HasError())
+	require.Empty(t, kb.Status.ProvisioningHash)
+	require.Empty(t, params.DynamicWatches.ConfigMaps.Registrations())
+	require.Empty(t, params.DynamicWatches.Secrets.Registrations())
+}
+
+func TestReconcile_Errors(t *testing.T) {
+	t.Run("missing ConfigMap", func(t *testing.T) {
+		kb := provisionedKibana()
+		api := &fakeAPI{}
+		results := Reconcile(context.Background(), Params{
+			Client:         k8s.NewFakeClient(rolesSecret.DeepCopy(), dashboardsConfigMap.DeepCopy()),
+			DynamicWatches: watches.NewDynamicWatches(),
+			Recorder:       record.NewFakeRecorder(10),
+			APIProvider:    api.provider,
+			Kibana:         kb,
+		})
+		require.True(t, results.HasError())
+		require.Empty(t, api.calls)
+	})
+	t.Run("invalid space", func(t *testing.T) {
+		kb := provisionedKibana()
+		spaces := spacesConfigMap.DeepCopy()
+		spaces.Data["team-c.json"] = `{"name":"Team C"}`
+		api := &fakeAPI{}
+		results := Reconcile(context.Background(), Params{
+			Client:         k8s.NewFakeClient(spaces, rolesSecret.DeepCopy(), dashboardsConfigMap.DeepCopy()),
+			DynamicWatches: watches.NewDynamicWatches(),
+			Recorder:       record.NewFakeRecorder(10),
+			APIProvider:    api.provider,
+			Kibana:         kb,
+		})
+		_, err := results.Aggregate()
+		require.ErrorContains(t, err, "invalid space team-c.json in ConfigMap spaces: id is missing")
+		require.Empty(t, api.calls)
+	})
+	t.Run("import failure", func(t *testing.T) {
+		kb := provisionedKibana()
+		api := &fakeAPI{err: errors.New("boom")}
+		recorder := record.NewFakeRecorder(10)
+		results := Reconcile(context.Background(), Params{
+			Client:         k8s.NewFakeClient(spacesConfigMap.DeepCopy(), rolesSecret.DeepCopy(), dashboardsConfigMap.DeepCopy()),
+			DynamicWatches: watches.NewDynamicWatches(),
+			Recorder:       recorder,
+			APIProvider:    api.provider,
+			Kibana:         kb,
+		})
+		_, err := results.Aggregate()
+		require.ErrorContains(t, err, "while provisioning Kibana: space team-a: boom")
+		require.Equal(t, []string{"space team-a"}, api.calls)
+		require.Empty(t, kb.Status.ProvisioningHash)
+		require.Len(t, recorder.Events, 1)
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"time"
 
+	pkgerrors "github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -36,6 +37,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
 )
 
 const (
@@ -117,7 +119,13 @@ func addWatches(mgr manager.Manager, c controller.Controller, k8sClient k8s.Clie
 	if err := c.Watch(source.Kind(mgr.GetCache(), &corev1.Secret{},
 		handler.TypedEnqueueRequestsFromMapFunc[*corev1.Secret](func(ctx context.Context, secret *corev1.Secret) []reconcile.Request {
 			if !license.IsOperatorLicense(*secret) {
-				return nil
+				// the secret may be the license of specific clusters
+				rs, err := reconcileRequestsForReferencingClusters(k8sClient, *secret)
+				if err != nil {
+					log.Error(err, "failed to list affected clusters in cluster license watch")
+					return nil
+				}
+				return rs
 			}
 
 			// if a license is added/modified we want to update for potentially all clusters managed by this instance
@@ -161,6 +169,31 @@ func (r *ReconcileLicenses) findLicense(ctx context.Context, c k8s.Client, check
 	return license.BestMatch(ctx, minVersion, licenseList, valid)
 }
 
+// findClusterLicense tries to find the best Elastic stack license available in the Enterprise license referenced in the
+// spec of the given cluster. It returns an error if the referenced license cannot be read, in which case the license of
+// the cluster is left untouched.
+func (r *ReconcileLicenses) findClusterLicense(ctx context.Context, cluster esv1.Elasticsearch, minVersion *version.Version) (esclient.License, string, bool, error) {
+	var secret corev1.Secret
+	nsn := types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Spec.LicenseSecretRef.SecretName}
+	if err := r.Get(ctx, nsn, &secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.recorder.Eventf(&cluster, corev1.EventTypeWarning, events.EventReasonInvalidLicense, "License secret %s not found", nsn.Name)
+		}
+		return esclient.License{}, "", false, err
+	}
+	enterpriseLicense, err := license.ParseEnterpriseLicense(secret.Data)
+	if err != nil {
+		err = license.NewError(&secret, pkgerrors.Wrapf(err, "while parsing license in %v", nsn))
+		recordInvalidLicenseEvents([]error{err}, r.recorder)
+		return esclient.License{}, "", false, err
+	}
+	valid := func(l license.EnterpriseLicense) (bool, error) {
+		return r.checker.Valid(ctx, l)
+	}
+	matchingSpec, parent, found := license.BestMatch(ctx, minVersion, []license.EnterpriseLicense{enterpriseLicense}, valid)
+	return matchingSpec, parent, found, nil
+}
+
 func recordInvalidLicenseEvents(errs []error, recorder record.EventRecorder) {
 	for _, err := range errs {
 		var licenseErr *license.Error
@@ -215,7 +248,17 @@ func (r *ReconcileLicenses) reconcileClusterLicense(ctx context.Context, cluster
 	if err != nil {
 		return noResult, true, err
 	}
-	matchingSpec, parent, found := r.findLicense(ctx, r, r.checker, minVersion)
+	var matchingSpec esclient.License
+	var parent string
+	var found bool
+	if cluster.Spec.LicenseSecretRef != nil {
+		matchingSpec, parent, found, err = r.findClusterLicense(ctx, cluster, minVersion)
+		if err != nil {
+			return noResult, true, err
+		}
+	} else {
+		matchingSpec, parent, found = r.findLicense(ctx, r, r.checker, minVersion)
+	}
 	if !found {
 		// no matching license found, delete cluster level license if it exists to revert to basic
 		clusterLicenseNSN := types.NamespacedName{Namespace: cluster.Namespace, Name: esv1.LicenseSecretName(cluster.Name)}
@@ -259,6 +302,7 @@ func (r *ReconcileLicenses) reconcileInternal(ctx context.Context, request recon
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			// nothing to do no cluster
+			metrics.ForgetReconciledResource(ctx)
 			return res
 		}
 		return res.WithError(err)
@@ -15,9 +15,11 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	crclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	commonlicense "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/license"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
@@ -118,6 +120,25 @@ func enterpriseLicense(t *testing.T, licenseType client.ElasticsearchLicenseType
 	}
 }
 
+var clusterWithLicenseRef = &esv1.Elasticsearch{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:      "cluster",
+		Namespace: "namespace",
+	},
+	Spec: esv1.ElasticsearchSpec{
+		Version:          "8.0.0",
+		LicenseSecretRef: &commonv1.SecretRef{SecretName: "cluster-license"},
+	},
+}
+
+// referencedLicense returns an Enterprise license referenced by clusterWithLicenseRef.
+func referencedLicense(t *testing.T, licenseType client.ElasticsearchLicenseType, expired bool) *corev1.Secret {
+	t.Helper()
+	secret := enterpriseLicense(t, licenseType, 1, expired)
+	secret.ObjectMeta = metav1.ObjectMeta{Name: "cluster-license", Namespace: "namespace"}
+	return secret
+}
+
 func TestReconcileLicenses_reconcileInternal(t *testing.T) {
 	tests := []struct {
 		name               string
@@ -185,13 +206,60 @@ func TestReconcileLicenses_reconcileInternal(t *testing.T) {
 			wantRequeue:        false,
 			wantRequeueAfter:   false,
 		},
+		{
+			name:    "referenced license",
+			cluster: clusterWithLicenseRef,
+			k8sResources: []crclient.Object{
+				referencedLicense(t, client.ElasticsearchLicenseTypePlatinum, false),
+				clusterWithLicenseRef,
+			},
+			wantErr:            "",
+			wantClusterLicense: true,
+			wantRequeue:        false,
+			wantRequeueAfter:   true,
+		},
+		{
+			name:    "referenced license expired: operator license not applied",
+			cluster: clusterWithLicenseRef,
+			k8sResources: []crclient.Object{
+				enterpriseLicense(t, client.ElasticsearchLicenseTypePlatinum, 1, false),
+				referencedLicense(t, client.ElasticsearchLicenseTypePlatinum, true),
+				clusterWithLicenseRef,
+			},
+			wantErr:            "",
+			wantClusterLicense: false,
+			wantRequeue:        false,
+			wantRequeueAfter:   false,
+		},
+		{
+			name:    "referenced license not found: error",
+			cluster: clusterWithLicenseRef,
+			k8sResources: []crclient.Object{
+				enterpriseLicense(t, client.ElasticsearchLicenseTypePlatinum, 1, false),
+				clusterWithLicenseRef,
+			},
+			wantErr: `secrets "cluster-license" not found`,
+		},
+		{
+			name:    "invalid referenced license: error",
+			cluster: clusterWithLicenseRef,
+			k8sResources: []crclient.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "cluster-license", Namespace: "namespace"},
+					Data:       map[string][]byte{commonlicense.FileName: []byte("{}")},
+				},
+				clusterWithLicenseRef,
+			},
+			wantErr: "while parsing license in namespace/cluster-license: [] license [] is not an enterprise license. Only orchestration licenses of type enterprise or enterprise_trial are supported",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			client := k8s.NewFakeClient(tt.k8sResources...)
 			r := &ReconcileLicenses{
-				Client:  client,
-				checker: commonlicense.MockLicenseChecker{EnterpriseEnabled: true},
+				Client:   client,
+				checker:  commonlicense.MockLicenseChecker{EnterpriseEnabled: true},
+				recorder: record.NewFakeRecorder(10),
 			}
 			nsn := k8s.ExtractNamespacedName(tt.cluster)
 			res, err := r.reconcileInternal(context.Background(), reconcile.Request{NamespacedName: nsn}).Aggregate()
@@ -8,7 +8,9 @@ import (
 	"context"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
@@ -34,3 +36,19 @@ func reconcileRequestsForAllClusters(c k8s.Client, log logr.Logger) ([]reconcile
 	}
 	return requests, nil
 }
+
+// reconcileRequestsForReferencingClusters returns a reconcile request for each cluster referencing the given secret as
+// its license.
+func reconcileRequestsForReferencingClusters(c k8s.Client, secret corev1.Secret) ([]reconcile.Request, error) {
+	var clusters esv1.ElasticsearchList
+	if err := c.List(context.Background(), &clusters, client.InNamespace(secret.Namespace)); err != nil {
+		return nil, err
+	}
+	var requests []reconcile.Request
+	for _, cl := range clusters.Items {
+		if cl.Spec.LicenseSecretRef != nil && cl.Spec.LicenseSecretRef.SecretName == secret.Name {
+			requests = append(requests, reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(&cl)})
+		}
+	}
+	return requests, nil
+}
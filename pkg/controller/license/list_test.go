@@ -10,11 +10,13 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 )
@@ -93,3 +95,27 @@ func Test_listAffectedLicenses(t *testing.T) {
 		})
 	}
 }
+
+func Test_reconcileRequestsForReferencingClusters(t *testing.T) {
+	withRef := func(namespace, name, secretName string) *esv1.Elasticsearch {
+		return &esv1.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec:       esv1.ElasticsearchSpec{LicenseSecretRef: &commonv1.SecretRef{SecretName: secretName}},
+		}
+	}
+	client := k8s.NewFakeClient(
+		withRef("default", "a", "license"),
+		withRef("default", "b", "other-license"),
+		withRef("other", "c", "license"),
+		&esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "d"}},
+	)
+	secret := corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "license"}}
+	got, err := reconcileRequestsForReferencingClusters(client, secret)
+	if err != nil {
+		t.Fatalf("reconcileRequestsForReferencingClusters() error = %v", err)
+	}
+	want := []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "default", Name: "a"}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("reconcileRequestsForReferencingClusters() = %v, want %v", got, want)
+	}
+}
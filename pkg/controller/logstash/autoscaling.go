@@ -0,0 +1,104 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package logstash
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	logstashv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash/autoscaling"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash/labels"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// reconcileAutoscaling updates the autoscaling status of Logstash with the number of Pods recommended for the current
+// load of its pipelines. The recommendation is applied to the spec once the status has been updated.
+func reconcileAutoscaling(params Params, status logstashv1alpha1.LogstashStatus) (*reconciler.Results, logstashv1alpha1.LogstashStatus) {
+	defer tracing.Span(&params.Context)()
+	results := reconciler.NewResult(params.Context)
+
+	spec := params.Logstash.Spec.Autoscaling
+	if spec == nil {
+		status.Autoscaling = nil
+		return results, status
+	}
+
+	autoscalingStatus := logstashv1alpha1.LogstashAutoscalingStatus{}
+	if status.Autoscaling != nil {
+		autoscalingStatus = *status.Autoscaling.DeepCopy()
+	}
+
+	// Only observe the pipelines once all the Pods are ready, to not scale again before a previous scaling operation
+	// has taken effect. The number of Pods is still brought within the bounds in the meantime.
+	var metrics autoscaling.Metrics
+	if status.ExpectedNodes == params.Logstash.Spec.Count && status.AvailableNodes == params.Logstash.Spec.Count {
+		observed, err := observePipelines(params)
+		if err != nil {
+			return results.WithError(err), status
+		}
+		metrics = observed
+		autoscalingStatus.QueueEvents = metrics.QueueEvents
+		autoscalingStatus.EventLatency = nil
+		if metrics.EventLatency != nil {
+			autoscalingStatus.EventLatency = &metav1.Duration{Duration: metrics.EventLatency.Round(time.Millisecond)}
+		}
+	}
+
+	now := time.Now()
+	desired := autoscaling.Recommend(*spec, params.Logstash.Spec.Count, metrics, autoscalingStatus.LastScaleTime, now)
+	if desired != params.Logstash.Spec.Count {
+		autoscalingStatus.LastScaleTime = &metav1.Time{Time: now}
+	}
+	autoscalingStatus.DesiredReplicas = desired
+	status.Autoscaling = &autoscalingStatus
+
+	// metrics are sampled periodically, without preventing Logstash from being considered reconciled
+	return results.WithReconciliationState(reconciler.RequeueAfter(autoscaling.SamplingPeriod).ReconciliationComplete()), status
+}
+
+// observePipelines returns the metrics of the pipelines of the ready Logstash Pods.
+func observePipelines(params Params) (autoscaling.Metrics, error) {
+	pods, err := k8s.PodsMatchingLabels(params.Client, params.Logstash.Namespace, map[string]string{labels.NameLabelName: params.Logstash.Name})
+	if err != nil {
+		return autoscaling.Metrics{}, err
+	}
+	readyPods := make([]corev1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if k8s.IsPodReady(pod) && pod.Status.PodIP != "" {
+			readyPods = append(readyPods, pod)
+		}
+	}
+	statsClient, err := autoscaling.NewStatsClient(params.Context, params.Client, params.OperatorParams.Dialer, params.Logstash, params.APIServerConfig)
+	if err != nil {
+		return autoscaling.Metrics{}, err
+	}
+	return params.AutoscalingObserver.Observe(params.Context, statsClient, k8s.ExtractNamespacedName(&params.Logstash), readyPods, time.Now())
+}
+
+// scale sets the number of Pods of Logstash to the number recommended by the autoscaler, if any.
+func scale(ctx context.Context, c k8s.Client, recorder record.EventRecorder, logstash logstashv1alpha1.Logstash, status logstashv1alpha1.LogstashStatus) error {
+	if logstash.Spec.Autoscaling == nil || status.Autoscaling == nil || status.Autoscaling.DesiredReplicas == logstash.Spec.Count {
+		return nil
+	}
+	from, to := logstash.Spec.Count, status.Autoscaling.DesiredReplicas
+	ulog.FromContext(ctx).Info("Scaling Logstash", "namespace", logstash.Namespace, "ls_name", logstash.Name, "from", from, "to", to)
+	patch := client.MergeFrom(logstash.DeepCopy())
+	logstash.Spec.Count = to
+	if err := c.Patch(ctx, &logstash, patch); err != nil {
+		return err
+	}
+	recorder.Eventf(&logstash, corev1.EventTypeNormal, events.EventReasonScaled, "Scaled Logstash from %d to %d Pods", from, to)
+	return nil
+}
@@ -0,0 +1,126 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package autoscaling
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// SamplingPeriod is the minimum duration between two retrievals of the statistics of the Pods of a Logstash.
+const SamplingPeriod = 15 * time.Second
+
+// Metrics describe the load of the pipelines of a Logstash, averaged over its Pods.
+type Metrics struct {
+	// QueueEvents is the average number of events waiting in the persisted queues of the pipelines of a Pod.
+	// It is nil if no pipeline uses a persisted queue.
+	QueueEvents *int64
+	// EventLatency is the average time spent by an event in the filters and outputs of the pipelines since the
+	// previous observation. It is nil if no event was processed, or if there is no previous observation.
+	EventLatency *time.Duration
+}
+
+// Observer keeps track of the statistics last retrieved from the Pods of each Logstash, as the events counters
+// reported by Logstash are cumulative: latencies are computed from the difference between two observations.
+type Observer struct {
+	lock         sync.Mutex
+	observations map[types.NamespacedName]observation
+}
+
+type observation struct {
+	time    time.Time
+	pods    map[string]podSample
+	metrics Metrics
+}
+
+// podSample holds the cumulative events counters of all the pipelines of a Pod.
+type podSample struct {
+	uid              types.UID
+	eventsOut        int64
+	durationInMillis int64
+}
+
+// NewObserver returns an Observer with no observation.
+func NewObserver() *Observer {
+	return &Observer{observations: make(map[types.NamespacedName]observation)}
+}
+
+// Observe retrieves the statistics of the pipelines of the given Pods of a Logstash and returns the resulting metrics.
+// Statistics are retrieved at most once per SamplingPeriod for a given Logstash, the metrics of the last observation
+// are returned in between. Pods whose statistics cannot be retrieved are ignored, unless none of them can be.
+func (o *Observer) Observe(ctx context.Context, client StatsClient, ls types.NamespacedName, pods []corev1.Pod, now time.Time) (Metrics, error) {
+	o.lock.Lock()
+	previous, exists := o.observations[ls]
+	o.lock.Unlock()
+	if exists && now.Sub(previous.time) < SamplingPeriod {
+		return previous.metrics, nil
+	}
+
+	current := observation{time: now, pods: make(map[string]podSample, len(pods))}
+	var errs []error
+	var queueEvents, queues, eventsOut, durationInMillis int64
+	for _, pod := range pods {
+		stats, err := client.PipelinesStats(ctx, pod)
+		if err != nil {
+			ulog.FromContext(ctx).V(1).Info("Failed to retrieve Logstash pipelines stats", "namespace", pod.Namespace, "pod_name", pod.Name, "error", err.Error())
+			errs = append(errs, err)
+			continue
+		}
+		sample := podSample{uid: pod.UID}
+		var podQueueEvents int64
+		podHasQueue := false
+		for _, pipeline := range stats.Pipelines {
+			sample.eventsOut += pipeline.Events.Out
+			sample.durationInMillis += pipeline.Events.DurationInMillis
+			if pipeline.Queue.Type == persistedQueueType {
+				podQueueEvents += pipeline.Queue.EventsCount
+				podHasQueue = true
+			}
+		}
+		if podHasQueue {
+			queueEvents += podQueueEvents
+			queues++
+		}
+		current.pods[pod.Name] = sample
+
+		// counters are reset when Logstash restarts: only compare samples of the same Pod with increasing counters
+		if prev, ok := previous.pods[pod.Name]; ok && prev.uid == sample.uid &&
+			prev.eventsOut <= sample.eventsOut && prev.durationInMillis <= sample.durationInMillis {
+			eventsOut += sample.eventsOut - prev.eventsOut
+			durationInMillis += sample.durationInMillis - prev.durationInMillis
+		}
+	}
+	if len(pods) > 0 && len(errs) == len(pods) {
+		return Metrics{}, utilerrors.NewAggregate(errs)
+	}
+
+	if queues > 0 {
+		avg := queueEvents / queues
+		current.metrics.QueueEvents = &avg
+	}
+	if eventsOut > 0 {
+		latency := time.Duration(durationInMillis) * time.Millisecond / time.Duration(eventsOut)
+		current.metrics.EventLatency = &latency
+	}
+
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.observations[ls] = current
+	return current.metrics, nil
+}
+
+// Forget removes the observations of the given Logstash.
+func (o *Observer) Forget(ls types.NamespacedName) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	delete(o.observations, ls)
+}
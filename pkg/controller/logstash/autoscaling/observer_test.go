@@ -0,0 +1,89 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package autoscaling
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+type fakeStatsClient map[string]PipelinesStats
+
+func (f fakeStatsClient) PipelinesStats(_ context.Context, pod corev1.Pod) (PipelinesStats, error) {
+	stats, ok := f[pod.Name]
+	if !ok {
+		return PipelinesStats{}, errors.New("connection refused")
+	}
+	return stats, nil
+}
+
+func pipelinesStats(out, durationInMillis int64, queue QueueStats) PipelinesStats {
+	return PipelinesStats{Pipelines: map[string]PipelineStats{
+		"main": {Events: EventsStats{Out: out, DurationInMillis: durationInMillis}, Queue: queue},
+	}}
+}
+
+func pod(name string, uid types.UID) corev1.Pod {
+	return corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, UID: uid}}
+}
+
+func TestObserver_Observe(t *testing.T) {
+	ctx := context.Background()
+	ls := types.NamespacedName{Namespace: "ns", Name: "ls"}
+	pods := []corev1.Pod{pod("ls-0", "uid-0"), pod("ls-1", "uid-1")}
+	now := time.Now()
+	o := NewObserver()
+
+	// first observation: no latency can be computed yet
+	metrics, err := o.Observe(ctx, fakeStatsClient{
+		"ls-0": pipelinesStats(1000, 10000, QueueStats{Type: persistedQueueType, EventsCount: 100}),
+		"ls-1": pipelinesStats(1000, 10000, QueueStats{Type: persistedQueueType, EventsCount: 300}),
+	}, ls, pods, now)
+	require.NoError(t, err)
+	require.Equal(t, Metrics{QueueEvents: ptr.To[int64](200)}, metrics)
+
+	// within the sampling period, the previous metrics are returned
+	metrics, err = o.Observe(ctx, fakeStatsClient{}, ls, pods, now.Add(time.Second))
+	require.NoError(t, err)
+	require.Equal(t, Metrics{QueueEvents: ptr.To[int64](200)}, metrics)
+
+	// second observation: 200 events processed in 6000ms overall
+	now = now.Add(SamplingPeriod)
+	metrics, err = o.Observe(ctx, fakeStatsClient{
+		"ls-0": pipelinesStats(1100, 12000, QueueStats{Type: persistedQueueType, EventsCount: 0}),
+		"ls-1": pipelinesStats(1100, 14000, QueueStats{Type: persistedQueueType, EventsCount: 0}),
+	}, ls, pods, now)
+	require.NoError(t, err)
+	require.Equal(t, Metrics{QueueEvents: ptr.To[int64](0), EventLatency: ptr.To(30 * time.Millisecond)}, metrics)
+
+	// ls-1 was recreated and its counters were reset, ls-0 cannot be reached
+	now = now.Add(SamplingPeriod)
+	metrics, err = o.Observe(ctx, fakeStatsClient{
+		"ls-1": pipelinesStats(10, 100, QueueStats{Type: "memory"}),
+	}, ls, []corev1.Pod{pods[0], pod("ls-1", "uid-2")}, now)
+	require.NoError(t, err)
+	require.Equal(t, Metrics{}, metrics)
+
+	// no Pod can be reached
+	now = now.Add(SamplingPeriod)
+	_, err = o.Observe(ctx, fakeStatsClient{}, ls, pods, now)
+	require.Error(t, err)
+
+	// forgotten observations are not used to compute latencies
+	o.Forget(ls)
+	metrics, err = o.Observe(ctx, fakeStatsClient{
+		"ls-1": pipelinesStats(20, 200, QueueStats{Type: "memory"}),
+	}, ls, []corev1.Pod{pod("ls-1", "uid-2")}, now)
+	require.NoError(t, err)
+	require.Equal(t, Metrics{}, metrics)
+}
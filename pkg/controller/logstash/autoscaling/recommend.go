@@ -0,0 +1,68 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package autoscaling
+
+import (
+	"math"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	logstashv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+)
+
+const (
+	// DefaultScaleDownStabilizationWindow is the default minimum duration since the last scaling operation before the
+	// number of Pods can be decreased.
+	DefaultScaleDownStabilizationWindow = 5 * time.Minute
+
+	// tolerance is the relative difference between the observed metrics and their targets under which the number of
+	// Pods is not changed, to avoid flapping around the targets.
+	tolerance = 0.1
+)
+
+// Recommend returns the number of Pods required to bring the given metrics to their targets, within the bounds of
+// the autoscaling specification. As with the Kubernetes HorizontalPodAutoscaler, the current number of Pods is scaled
+// proportionally to the ratio between the observed metrics and their targets, the highest ratio winning.
+// The number of Pods is not decreased before the scale down stabilization window has elapsed since lastScaleTime.
+func Recommend(spec logstashv1alpha1.LogstashAutoscalingSpec, current int32, metrics Metrics, lastScaleTime *metav1.Time, now time.Time) int32 {
+	desired := current
+	if ratio, ok := usageRatio(spec, metrics); ok && math.Abs(ratio-1) > tolerance {
+		desired = int32(math.Ceil(float64(current) * ratio))
+	}
+
+	window := DefaultScaleDownStabilizationWindow
+	if spec.ScaleDownStabilizationWindow != nil {
+		window = spec.ScaleDownStabilizationWindow.Duration
+	}
+	if desired < current && lastScaleTime != nil && now.Sub(lastScaleTime.Time) < window {
+		desired = current
+	}
+
+	switch {
+	case desired < spec.MinReplicas:
+		return spec.MinReplicas
+	case desired > spec.MaxReplicas:
+		return spec.MaxReplicas
+	default:
+		return desired
+	}
+}
+
+// usageRatio returns the highest ratio between the observed metrics and their targets, or false if no metric with a
+// target has been observed.
+func usageRatio(spec logstashv1alpha1.LogstashAutoscalingSpec, metrics Metrics) (float64, bool) {
+	var ratio float64
+	observed := false
+	if spec.TargetQueueEvents != nil && *spec.TargetQueueEvents > 0 && metrics.QueueEvents != nil {
+		ratio = math.Max(ratio, float64(*metrics.QueueEvents)/float64(*spec.TargetQueueEvents))
+		observed = true
+	}
+	if spec.TargetEventLatency != nil && spec.TargetEventLatency.Duration > 0 && metrics.EventLatency != nil {
+		ratio = math.Max(ratio, float64(*metrics.EventLatency)/float64(spec.TargetEventLatency.Duration))
+		observed = true
+	}
+	return ratio, observed
+}
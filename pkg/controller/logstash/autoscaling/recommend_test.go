@@ -0,0 +1,159 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package autoscaling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	logstashv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+)
+
+func TestRecommend(t *testing.T) {
+	now := time.Now()
+	spec := logstashv1alpha1.LogstashAutoscalingSpec{
+		MinReplicas:        2,
+		MaxReplicas:        10,
+		TargetQueueEvents:  ptr.To[int64](1000),
+		TargetEventLatency: &metav1.Duration{Duration: 100 * time.Millisecond},
+	}
+	tests := []struct {
+		name          string
+		spec          logstashv1alpha1.LogstashAutoscalingSpec
+		current       int32
+		metrics       Metrics
+		lastScaleTime *metav1.Time
+		want          int32
+	}{
+		{
+			name:    "no metrics: keep the current number of Pods",
+			spec:    spec,
+			current: 3,
+			metrics: Metrics{},
+			want:    3,
+		},
+		{
+			name:    "no metrics: scale up to the lower bound",
+			spec:    spec,
+			current: 1,
+			metrics: Metrics{},
+			want:    2,
+		},
+		{
+			name:          "no metrics: scale down to the upper bound, regardless of the stabilization window",
+			spec:          spec,
+			current:       12,
+			metrics:       Metrics{},
+			lastScaleTime: &metav1.Time{Time: now},
+			want:          10,
+		},
+		{
+			name:    "queue events above target",
+			spec:    spec,
+			current: 3,
+			metrics: Metrics{QueueEvents: ptr.To[int64](2000)},
+			want:    6,
+		},
+		{
+			name:    "latency above target",
+			spec:    spec,
+			current: 3,
+			metrics: Metrics{EventLatency: ptr.To(150 * time.Millisecond)},
+			want:    5,
+		},
+		{
+			name:    "the highest ratio wins",
+			spec:    spec,
+			current: 3,
+			metrics: Metrics{QueueEvents: ptr.To[int64](500), EventLatency: ptr.To(300 * time.Millisecond)},
+			want:    9,
+		},
+		{
+			name:    "within tolerance",
+			spec:    spec,
+			current: 4,
+			metrics: Metrics{QueueEvents: ptr.To[int64](1050), EventLatency: ptr.To(95 * time.Millisecond)},
+			want:    4,
+		},
+		{
+			name:    "scale up is capped by the upper bound",
+			spec:    spec,
+			current: 4,
+			metrics: Metrics{QueueEvents: ptr.To[int64](10000)},
+			want:    10,
+		},
+		{
+			name:    "scale down",
+			spec:    spec,
+			current: 8,
+			metrics: Metrics{QueueEvents: ptr.To[int64](100), EventLatency: ptr.To(50 * time.Millisecond)},
+			want:    4,
+		},
+		{
+			name:    "scale down is capped by the lower bound",
+			spec:    spec,
+			current: 8,
+			metrics: Metrics{QueueEvents: ptr.To[int64](0), EventLatency: ptr.To(10 * time.Millisecond)},
+			want:    2,
+		},
+		{
+			name:          "scale down within the stabilization window",
+			spec:          spec,
+			current:       8,
+			metrics:       Metrics{QueueEvents: ptr.To[int64](100), EventLatency: ptr.To(50 * time.Millisecond)},
+			lastScaleTime: &metav1.Time{Time: now.Add(-1 * time.Minute)},
+			want:          8,
+		},
+		{
+			name:          "scale down after the stabilization window",
+			spec:          spec,
+			current:       8,
+			metrics:       Metrics{QueueEvents: ptr.To[int64](100), EventLatency: ptr.To(50 * time.Millisecond)},
+			lastScaleTime: &metav1.Time{Time: now.Add(-10 * time.Minute)},
+			want:          4,
+		},
+		{
+			name: "scale down after a custom stabilization window",
+			spec: logstashv1alpha1.LogstashAutoscalingSpec{
+				MinReplicas:                  1,
+				MaxReplicas:                  10,
+				TargetQueueEvents:            ptr.To[int64](1000),
+				ScaleDownStabilizationWindow: &metav1.Duration{Duration: 30 * time.Second},
+			},
+			current:       8,
+			metrics:       Metrics{QueueEvents: ptr.To[int64](100)},
+			lastScaleTime: &metav1.Time{Time: now.Add(-1 * time.Minute)},
+			want:          1,
+		},
+		{
+			name:          "scale up within the stabilization window",
+			spec:          spec,
+			current:       3,
+			metrics:       Metrics{QueueEvents: ptr.To[int64](2000)},
+			lastScaleTime: &metav1.Time{Time: now},
+			want:          6,
+		},
+		{
+			name: "metric without target is ignored",
+			spec: logstashv1alpha1.LogstashAutoscalingSpec{
+				MinReplicas:       1,
+				MaxReplicas:       10,
+				TargetQueueEvents: ptr.To[int64](1000),
+			},
+			current: 3,
+			metrics: Metrics{EventLatency: ptr.To(time.Second)},
+			want:    3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, Recommend(tt.spec, tt.current, tt.metrics, tt.lastScaleTime, now))
+		})
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package autoscaling
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	logstashv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	commonhttp "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/http"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash/configs"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash/network"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	utilsnet "github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+const (
+	persistedQueueType = "persisted"
+	statsTimeout       = 5 * time.Second
+)
+
+// PipelinesStats is the subset of the response of the Logstash node stats API for pipelines used to autoscale Logstash
+// and to check the health of its pipelines.
+type PipelinesStats struct {
+	Pipelines map[string]PipelineStats `json:"pipelines"`
+}
+
+// PipelineStats are the statistics of a single Logstash pipeline.
+type PipelineStats struct {
+	Events  EventsStats  `json:"events"`
+	Queue   QueueStats   `json:"queue"`
+	Reloads ReloadsStats `json:"reloads"`
+}
+
+// EventsStats are the cumulative counters of the events processed by a pipeline since Logstash started.
+type EventsStats struct {
+	Out              int64 `json:"out"`
+	DurationInMillis int64 `json:"duration_in_millis"`
+}
+
+// QueueStats describe the queue of a pipeline.
+type QueueStats struct {
+	Type        string `json:"type"`
+	EventsCount int64  `json:"events_count"`
+}
+
+// ReloadsStats describe the attempts to start or reload a pipeline.
+type ReloadsStats struct {
+	Failures             int64        `json:"failures"`
+	LastError            *ReloadError `json:"last_error"`
+	LastSuccessTimestamp *string      `json:"last_success_timestamp"`
+	LastFailureTimestamp *string      `json:"last_failure_timestamp"`
+}
+
+// ReloadError is the error of the last failed attempt to start or reload a pipeline.
+type ReloadError struct {
+	Message string `json:"message"`
+}
+
+// Failed returns true if the last attempt to start or reload the pipeline failed, for example because of an invalid
+// configuration or of a missing plugin. Failures followed by a successful reload are ignored.
+func (r ReloadsStats) Failed() bool {
+	if r.Failures == 0 || r.LastError == nil {
+		return false
+	}
+	if r.LastSuccessTimestamp == nil || r.LastFailureTimestamp == nil {
+		return true
+	}
+	lastSuccess, err := time.Parse(time.RFC3339, *r.LastSuccessTimestamp)
+	if err != nil {
+		return true
+	}
+	lastFailure, err := time.Parse(time.RFC3339, *r.LastFailureTimestamp)
+	if err != nil {
+		return true
+	}
+	return lastFailure.After(lastSuccess)
+}
+
+// StatsClient retrieves the statistics of the pipelines of a Logstash Pod.
+type StatsClient interface {
+	PipelinesStats(ctx context.Context, pod corev1.Pod) (PipelinesStats, error)
+}
+
+type statsClient struct {
+	client   *http.Client
+	scheme   string
+	username string
+	password string
+}
+
+var _ StatsClient = statsClient{}
+
+// NewStatsClient returns a StatsClient targeting the API server of the Pods of the given Logstash, configured with
+// the given resolved API server configuration.
+func NewStatsClient(ctx context.Context, c k8s.Client, dialer utilsnet.Dialer, ls logstashv1alpha1.Logstash, apiServer configs.APIServer) (StatsClient, error) {
+	scheme := "http"
+	var caCerts []*x509.Certificate
+	if apiServer.UseTLS() {
+		scheme = "https"
+		if ls.APIServerTLSOptions().Enabled() {
+			var caSecret corev1.Secret
+			key := types.NamespacedName{Namespace: ls.Namespace, Name: certificates.PublicCertsSecretName(logstashv1alpha1.Namer, ls.Name)}
+			if err := c.Get(ctx, key, &caSecret); err != nil {
+				return nil, err
+			}
+			trustedCerts, ok := caSecret.Data[certificates.CertFileName]
+			if !ok {
+				return nil, fmt.Errorf("%s not found in Secret %s", certificates.CertFileName, key)
+			}
+			certs, err := certificates.ParsePEMCerts(trustedCerts)
+			if err != nil {
+				return nil, err
+			}
+			caCerts = certs
+		}
+	}
+	return statsClient{
+		client:   commonhttp.Client(dialer, caCerts, statsTimeout),
+		scheme:   scheme,
+		username: apiServer.Username,
+		password: apiServer.Password,
+	}, nil
+}
+
+// PipelinesStats retrieves the statistics of the pipelines of the given Pod, through its IP address.
+func (s statsClient) PipelinesStats(ctx context.Context, pod corev1.Pod) (PipelinesStats, error) {
+	var stats PipelinesStats
+	url := fmt.Sprintf("%s://%s/_node/stats/pipelines", s.scheme, net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(network.HTTPPort)))
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return stats, err
+	}
+	if s.username != "" {
+		request.SetBasicAuth(s.username, s.password)
+	}
+	resp, err := s.client.Do(request)
+	if err != nil {
+		return stats, err
+	}
+	defer resp.Body.Close()
+	if err := commonhttp.MaybeAPIError(resp); err != nil {
+		return stats, err
+	}
+	err = json.NewDecoder(resp.Body).Decode(&stats)
+	return stats, err
+}
@@ -0,0 +1,87 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package logstash
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+
+	logstashv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash/autoscaling"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func Test_reconcileAutoscaling(t *testing.T) {
+	logstash := logstashv1alpha1.Logstash{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ls"},
+		Spec: logstashv1alpha1.LogstashSpec{
+			Count: 1,
+			Autoscaling: &logstashv1alpha1.LogstashAutoscalingSpec{
+				MinReplicas:       2,
+				MaxReplicas:       4,
+				TargetQueueEvents: ptr.To[int64](1000),
+			},
+		},
+	}
+	params := Params{
+		Context:             context.Background(),
+		Client:              k8s.NewFakeClient(),
+		Logstash:            logstash,
+		AutoscalingObserver: autoscaling.NewObserver(),
+	}
+
+	// Pods are not all ready: the number of Pods is only brought within the bounds
+	results, status := reconcileAutoscaling(params, logstashv1alpha1.LogstashStatus{ExpectedNodes: 1})
+	require.False(t, results.HasError())
+	require.NotNil(t, status.Autoscaling)
+	require.Equal(t, int32(2), status.Autoscaling.DesiredReplicas)
+	require.NotNil(t, status.Autoscaling.LastScaleTime)
+	// metrics are sampled again later, without preventing Logstash from being considered reconciled
+	require.True(t, results.HasRequeue())
+	reconciled, _ := results.IsReconciled()
+	require.True(t, reconciled)
+
+	// autoscaling disabled
+	params.Logstash.Spec.Autoscaling = nil
+	results, status = reconcileAutoscaling(params, status)
+	require.False(t, results.HasError())
+	require.Nil(t, status.Autoscaling)
+}
+
+func Test_scale(t *testing.T) {
+	logstash := logstashv1alpha1.Logstash{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ls"},
+		Spec: logstashv1alpha1.LogstashSpec{
+			Count: 2,
+			Autoscaling: &logstashv1alpha1.LogstashAutoscalingSpec{
+				MinReplicas: 1,
+				MaxReplicas: 4,
+			},
+		},
+	}
+	c := k8s.NewFakeClient(&logstash)
+	recorder := record.NewFakeRecorder(10)
+
+	// nothing to do
+	require.NoError(t, scale(context.Background(), c, recorder, logstash, logstashv1alpha1.LogstashStatus{}))
+	require.NoError(t, scale(context.Background(), c, recorder, logstash, logstashv1alpha1.LogstashStatus{
+		Autoscaling: &logstashv1alpha1.LogstashAutoscalingStatus{DesiredReplicas: 2},
+	}))
+	require.Empty(t, recorder.Events)
+
+	// scale up
+	require.NoError(t, scale(context.Background(), c, recorder, logstash, logstashv1alpha1.LogstashStatus{
+		Autoscaling: &logstashv1alpha1.LogstashAutoscalingStatus{DesiredReplicas: 3},
+	}))
+	var actual logstashv1alpha1.Logstash
+	require.NoError(t, c.Get(context.Background(), k8s.ExtractNamespacedName(&logstash), &actual))
+	require.Equal(t, int32(3), actual.Spec.Count)
+	require.Len(t, recorder.Events, 1)
+}
@@ -16,6 +16,7 @@ import (
 
 	logstashv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/labels"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/pod"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
@@ -72,6 +73,16 @@ func reconcileConfig(params Params, svcUseTLS bool, configHash hash.Hash) (*sett
 		expected.Data[APIKeystorePassEnv] = []byte(apiServerConfig.KeystorePassword)
 	}
 
+	// store the decoded API keys of the Elasticsearch associations for the Pods to reference,
+	// so that they are not exposed in plain text either
+	apiKeys, err := esAPIKeys(params, getEsAssociations(params))
+	if err != nil {
+		return nil, configs.APIServer{}, err
+	}
+	for envName, apiKey := range apiKeys {
+		expected.Data[envName] = apiKey
+	}
+
 	if _, err = reconciler.ReconcileSecret(params.Context, params.Client, expected, &params.Logstash); err != nil {
 		return nil, configs.APIServer{}, err
 	}
@@ -118,6 +129,13 @@ func defaultConfig() *settings.CanonicalConfig {
 	return settings.MustCanonicalConfig(settingsMap)
 }
 
+// reloadsPipelinesAutomatically returns true if Logstash periodically checks the pipelines for changes and reloads them
+// without being restarted.
+func reloadsPipelinesAutomatically(cfg *settings.CanonicalConfig) bool {
+	reload, err := cfg.String("config.reload.automatic")
+	return err == nil && reload == "true"
+}
+
 func tlsConfig(useTLS bool) *settings.CanonicalConfig {
 	if !useTLS {
 		return nil
@@ -212,9 +230,13 @@ func getKeystoreEnvKeyValues(params Params) (map[string]string, error) {
 	}
 
 	// from keystore SecureSettings
-	for _, ss := range params.Logstash.SecureSettings() {
+	secretSources, err := keystore.AllowedSecretSources(params.Context, params.EventRecorder, &params.Logstash)
+	if err != nil {
+		return nil, err
+	}
+	for _, ss := range secretSources {
 		secret := corev1.Secret{}
-		nsn := types.NamespacedName{Name: ss.SecretName, Namespace: params.Logstash.Namespace}
+		nsn := types.NamespacedName{Name: ss.SecretName, Namespace: ss.Namespace}
 		if err := params.Client.Get(params.Context, nsn, &secret); err != nil {
 			return nil, err
 		}
@@ -223,6 +245,15 @@ func getKeystoreEnvKeyValues(params Params) (map[string]string, error) {
 			data[key] = string(value)
 		}
 	}
+	backendSecrets, err := keystore.BackendSecrets(params.Context, params.EventRecorder, &params.Logstash)
+	if err != nil {
+		return nil, err
+	}
+	for _, secret := range backendSecrets {
+		for key, value := range secret.Data {
+			data[key] = string(value)
+		}
+	}
 
 	return data, nil
 }
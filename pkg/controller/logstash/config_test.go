@@ -17,6 +17,7 @@ import (
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash/configs"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
@@ -218,6 +219,36 @@ func logstashWithConfigRef(name string, cfg *commonv1.Config) v1alpha1.Logstash
 	}
 }
 
+func Test_reloadsPipelinesAutomatically(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  map[string]interface{}
+		want bool
+	}{
+		{
+			name: "default config",
+			want: true,
+		},
+		{
+			name: "automatic reload disabled by the user",
+			cfg:  map[string]interface{}{"config.reload.automatic": false},
+			want: false,
+		},
+		{
+			name: "automatic reload enabled by the user",
+			cfg:  map[string]interface{}{"config.reload.automatic": "true"},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaultConfig()
+			require.NoError(t, cfg.MergeWith(settings.MustCanonicalConfig(tt.cfg)))
+			require.Equal(t, tt.want, reloadsPipelinesAutomatically(cfg))
+		})
+	}
+}
+
 func Test_checkTLSConfig(t *testing.T) {
 	tests := []struct {
 		name    string
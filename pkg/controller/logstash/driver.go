@@ -22,9 +22,12 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/restart"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/statefulset"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash/autoscaling"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash/configs"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash/labels"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash/stackmon"
@@ -52,6 +55,9 @@ type Params struct {
 	// Expectations control some expectations set on resources in the cache, in order to
 	// avoid doing certain operations if the cache hasn't seen an up-to-date resource yet.
 	Expectations *expectations.Expectations
+
+	// AutoscalingObserver keeps track of the pipelines statistics used to autoscale Logstash.
+	AutoscalingObserver *autoscaling.Observer
 }
 
 // K8sClient returns the Kubernetes client.
@@ -85,19 +91,21 @@ func newStatus(logstash logstashv1alpha1.Logstash) logstashv1alpha1.LogstashStat
 	return status
 }
 
-func internalReconcile(params Params) (*reconciler.Results, logstashv1alpha1.LogstashStatus) {
+// internalReconcile reconciles the resources of Logstash and returns its new status, along with a message describing
+// the pipelines which failed to start or to reload, empty if there is none.
+func internalReconcile(params Params) (*reconciler.Results, logstashv1alpha1.LogstashStatus, string) {
 	defer tracing.Span(&params.Context)()
 	results := reconciler.NewResult(params.Context)
 
 	// ensure that the label used by expectations is set as it is not in place if the logstash
 	// resource was created with ECK < 2.12
 	if err := ensureSTSNameLabelIsSetOnPods(params); err != nil {
-		return results.WithError(err), params.Status
+		return results.WithError(err), params.Status, ""
 	}
 
 	_, apiSvc, err := reconcileServices(params)
 	if err != nil {
-		return results.WithError(err), params.Status
+		return results.WithError(err), params.Status, ""
 	}
 
 	apiSvcTLS := params.Logstash.APIServerTLSOptions()
@@ -118,41 +126,62 @@ func internalReconcile(params Params) (*reconciler.Results, logstashv1alpha1.Log
 	if results.HasError() {
 		_, err := results.Aggregate()
 		k8s.MaybeEmitErrorEvent(params.Recorder(), err, &params.Logstash, events.EventReconciliationError, "Certificate reconciliation error: %v", err)
-		return results, params.Status
+		return results, params.Status, ""
 	}
 
 	configHash := fnv.New32a()
 
-	if _, params.APIServerConfig, err = reconcileConfig(params, apiSvcTLS.Enabled(), configHash); err != nil {
-		return results.WithError(err), params.Status
+	var cfg *settings.CanonicalConfig
+	if cfg, params.APIServerConfig, err = reconcileConfig(params, apiSvcTLS.Enabled(), configHash); err != nil {
+		return results.WithError(err), params.Status, ""
 	}
 
 	// reconcile beats config secrets if Stack Monitoring is defined
 	if err := stackmon.ReconcileConfigSecrets(params.Context, params.Client, params.Logstash, params.APIServerConfig); err != nil {
-		return results.WithError(err), params.Status
+		return results.WithError(err), params.Status, ""
+	}
+
+	if err := reconcilePrometheusExporter(params, configHash); err != nil {
+		return results.WithError(err), params.Status, ""
 	}
 
-	// We intentionally DO NOT pass the configHash here. We don't want to consider the pipeline definitions in the
-	// hash of the config to ensure that a pipeline change does not automatically trigger a restart
-	// of the pod, but allows Logstash's automatic reload of pipelines to take place
-	if err := reconcilePipeline(params); err != nil {
-		return results.WithError(err), params.Status
+	// We don't want to consider the pipeline definitions in the hash of the config to ensure that a pipeline change
+	// does not automatically trigger a restart of the pod, but allows Logstash's automatic reload of pipelines to take
+	// place. Pods are only restarted on pipeline changes if the automatic reload has been disabled by the user.
+	if err := reconcilePipeline(params, reloadsPipelinesAutomatically(cfg), configHash); err != nil {
+		return results.WithError(err), params.Status, ""
 	}
 
 	params.Logstash.Spec.VolumeClaimTemplates = volume.AppendDefaultPVCs(params.Logstash.Spec.VolumeClaimTemplates,
 		params.Logstash.Spec.PodTemplate.Spec)
 
 	if keystoreResources, err := reconcileKeystore(params, configHash); err != nil {
-		return results.WithError(err), params.Status
+		return results.WithError(err), params.Status, ""
 	} else if keystoreResources != nil {
 		params.KeystoreResources = keystoreResources
 	}
+	results.WithReconciliationState(keystore.RefreshResult(&params.Logstash))
+
+	// start the restart of the Pods requested by the user, if any, before their template is built
+	if err := restart.Start(params.Context, params.Client, params.Recorder(), &params.Logstash); err != nil {
+		return results.WithError(err), params.Status, ""
+	}
+	params.Status.Restart = params.Logstash.Status.Restart
 
 	podTemplate, err := buildPodTemplate(params, configHash)
 	if err != nil {
-		return results.WithError(err), params.Status
+		return results.WithError(err), params.Status, ""
+	}
+	results, status := reconcileStatefulSet(params, podTemplate)
+	if results.HasError() {
+		return results, status, ""
+	}
+	if status, err = reconcileRestart(params, status); err != nil {
+		return results.WithError(err), status, ""
 	}
-	return reconcileStatefulSet(params, podTemplate)
+	pipelinesResults, status, pipelinesMessage := reconcilePipelinesHealth(params, status)
+	autoscalingResults, status := reconcileAutoscaling(params, status)
+	return results.WithResults(pipelinesResults).WithResults(autoscalingResults), status, pipelinesMessage
 }
 
 // expectationsSatisfied checks that resources in our local cache match what we expect.
@@ -5,7 +5,9 @@
 package logstash
 
 import (
+	"encoding/base64"
 	"errors"
+	"fmt"
 	"path/filepath"
 	"strings"
 
@@ -18,6 +20,9 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash/volume"
 )
 
+// esAPIKeyEnvSuffix is the suffix of the environment variable holding the API key of an Elasticsearch association.
+const esAPIKeyEnvSuffix = "_ES_API_KEY" //nolint:gosec
+
 func buildEnv(params Params, esAssociations []commonv1.Association) ([]corev1.EnvVar, error) {
 	var envs []corev1.EnvVar //nolint:prealloc
 	for _, assoc := range esAssociations {
@@ -39,18 +44,34 @@ func buildEnv(params Params, esAssociations []commonv1.Association) ([]corev1.En
 		normalizedClusterName := normalize(clusterName)
 
 		envs = append(envs, createEnvVar(normalizedClusterName+"_ES_HOSTS", assocConf.GetURL()))
-		envs = append(envs, createEnvVar(normalizedClusterName+"_ES_USER", credentials.Username))
-		envs = append(envs, corev1.EnvVar{
-			Name: normalizedClusterName + "_ES_PASSWORD",
-			ValueFrom: &corev1.EnvVarSource{
-				SecretKeyRef: &corev1.SecretKeySelector{
-					LocalObjectReference: corev1.LocalObjectReference{
-						Name: assocConf.AuthSecretName,
+		if credentials.APIKey != "" {
+			// the decoded API key is stored in the config Secret, see esAPIKeys
+			apiKeyEnvName := normalizedClusterName + esAPIKeyEnvSuffix
+			envs = append(envs, corev1.EnvVar{
+				Name: apiKeyEnvName,
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: v1alpha1.ConfigSecretName(params.Logstash.Name),
+						},
+						Key: apiKeyEnvName,
+					},
+				},
+			})
+		} else {
+			envs = append(envs, createEnvVar(normalizedClusterName+"_ES_USER", credentials.Username))
+			envs = append(envs, corev1.EnvVar{
+				Name: normalizedClusterName + "_ES_PASSWORD",
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: assocConf.AuthSecretName,
+						},
+						Key: assocConf.AuthSecretKey,
 					},
-					Key: assocConf.AuthSecretKey,
 				},
-			},
-		})
+			})
+		}
 
 		if assocConf.GetCACertProvided() {
 			caPath := filepath.Join(volume.CertificatesDir(assoc), certificates.CAFileName)
@@ -61,6 +82,32 @@ func buildEnv(params Params, esAssociations []commonv1.Association) ([]corev1.En
 	return envs, nil
 }
 
+// esAPIKeys returns the API keys of the Elasticsearch associations authenticating with an API key, decoded to the
+// id:api_key format expected by the Elasticsearch plugins of Logstash, indexed by the name of the environment variable
+// exposing them.
+func esAPIKeys(params Params, esAssociations []commonv1.Association) (map[string][]byte, error) {
+	apiKeys := make(map[string][]byte)
+	for _, assoc := range esAssociations {
+		credentials, err := association.ElasticsearchAuthSettings(params.Context, params.Client, assoc)
+		if err != nil {
+			return nil, err
+		}
+		if credentials.APIKey == "" {
+			continue
+		}
+		decodedAPIKey, err := base64.StdEncoding.DecodeString(credentials.APIKey)
+		if err != nil {
+			return nil, fmt.Errorf("error at decoding apikey for Elasticsearch association %s: %w", assoc.AssociationRef().NameOrSecretName(), err)
+		}
+		clusterName, err := getClusterName(assoc)
+		if err != nil {
+			return nil, err
+		}
+		apiKeys[normalize(clusterName)+esAPIKeyEnvSuffix] = decodedAPIKey
+	}
+	return apiKeys, nil
+}
+
 func getClusterName(assoc commonv1.Association) (string, error) {
 	lses, ok := assoc.(*v1alpha1.LogstashESAssociation)
 	if !ok {
@@ -6,6 +6,7 @@ package logstash
 
 import (
 	"context"
+	"encoding/base64"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -32,8 +33,17 @@ func Test_getEnvVars(t *testing.T) {
 		},
 	}
 
+	fakeExternalEsAPIKeySecret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "external-cloud-es-api-key-ref", Namespace: "default"},
+		Data: map[string][]byte{
+			"url":     []byte("https://some.gcp.cloud.es.io"),
+			"api-key": []byte(base64.StdEncoding.EncodeToString([]byte("id:key"))),
+		},
+	}
+
 	params := Params{
 		Logstash: logstashv1alpha1.Logstash{
+			ObjectMeta: metav1.ObjectMeta{Name: "logstash-sample", Namespace: "default"},
 			Spec: logstashv1alpha1.LogstashSpec{
 				ElasticsearchRefs: []logstashv1alpha1.ElasticsearchCluster{
 					{
@@ -43,7 +53,7 @@ func Test_getEnvVars(t *testing.T) {
 				},
 			},
 		},
-		Client:  k8s.NewFakeClient(&fakeLogstashUserSecret, &fakeExternalEsSecret),
+		Client:  k8s.NewFakeClient(&fakeLogstashUserSecret, &fakeExternalEsSecret, &fakeExternalEsAPIKeySecret),
 		Context: context.Background(),
 	}
 
@@ -152,6 +162,33 @@ func Test_getEnvVars(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:   "es ref with secretName and api key",
+			params: params,
+			setAssocConfs: func(assocs []commonv1.Association) {
+				assocs[0].SetAssociationConf(&commonv1.AssociationConf{
+					AuthSecretName: "external-cloud-es-api-key-ref",
+					AuthSecretKey:  "api-key",
+					IsAPIKey:       true,
+					URL:            "https://some.gcp.cloud.es.io",
+					Version:        "8.7.0",
+				})
+				assocs[0].SetNamespace("default")
+			},
+			wantEnvs: []corev1.EnvVar{
+				{Name: "PRODUCTION_ES_HOSTS", Value: "https://some.gcp.cloud.es.io"},
+				{Name: "PRODUCTION_ES_API_KEY",
+					ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{
+								Name: "logstash-sample-ls-config",
+							},
+							Key: "PRODUCTION_ES_API_KEY",
+						},
+					},
+				},
+			},
+		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
 			assocs := tt.params.Logstash.GetAssociations()
@@ -162,3 +199,53 @@ func Test_getEnvVars(t *testing.T) {
 		})
 	}
 }
+
+func Test_esAPIKeys(t *testing.T) {
+	params := Params{
+		Logstash: logstashv1alpha1.Logstash{
+			ObjectMeta: metav1.ObjectMeta{Name: "logstash-sample", Namespace: "default"},
+			Spec: logstashv1alpha1.LogstashSpec{
+				ElasticsearchRefs: []logstashv1alpha1.ElasticsearchCluster{
+					{ObjectSelector: commonv1.ObjectSelector{Name: "elasticsearch-sample", Namespace: "default"}, ClusterName: "production"},
+					{ObjectSelector: commonv1.ObjectSelector{SecretName: "external-cloud-es-api-key-ref"}, ClusterName: "cloud"},
+				},
+			},
+		},
+		Client: k8s.NewFakeClient(
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "logstash-sample-default-elasticsearch-sample-logstash-user", Namespace: "default"},
+				Data:       map[string][]byte{"default-logstash-sample-default-elasticsearch-sample-logstash-user": []byte("1234567890")},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "external-cloud-es-api-key-ref", Namespace: "default"},
+				Data: map[string][]byte{
+					"url":     []byte("https://some.gcp.cloud.es.io"),
+					"api-key": []byte(base64.StdEncoding.EncodeToString([]byte("id:key"))),
+				},
+			},
+		),
+		Context: context.Background(),
+	}
+	assocs := params.Logstash.GetAssociations()
+	assocs[0].SetAssociationConf(&commonv1.AssociationConf{
+		AuthSecretName: "logstash-sample-default-elasticsearch-sample-logstash-user",
+		AuthSecretKey:  "default-logstash-sample-default-elasticsearch-sample-logstash-user",
+		URL:            "https://elasticsearch-sample-es-http.default.svc:9200",
+		Version:        "8.7.0",
+	})
+	assocs[1].SetAssociationConf(&commonv1.AssociationConf{
+		AuthSecretName: "external-cloud-es-api-key-ref",
+		AuthSecretKey:  "api-key",
+		IsAPIKey:       true,
+		URL:            "https://some.gcp.cloud.es.io",
+		Version:        "8.7.0",
+	})
+	for _, assoc := range assocs {
+		assoc.SetNamespace("default")
+	}
+
+	apiKeys, err := esAPIKeys(params, assocs)
+	require.NoError(t, err)
+	// only the association authenticating with an API key is returned, with its decoded API key
+	require.Equal(t, map[string][]byte{"CLOUD_ES_API_KEY": []byte("id:key")}, apiKeys)
+}
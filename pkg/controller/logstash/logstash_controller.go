@@ -18,6 +18,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
 	logstashv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/association"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
@@ -29,11 +30,13 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash/autoscaling"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash/labels"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash/pipelines"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash/validation"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
 )
 
 const (
@@ -55,11 +58,12 @@ func Add(mgr manager.Manager, params operator.Parameters) error {
 func newReconciler(mgr manager.Manager, params operator.Parameters) *ReconcileLogstash {
 	client := mgr.GetClient()
 	return &ReconcileLogstash{
-		Client:         client,
-		recorder:       mgr.GetEventRecorderFor(controllerName),
-		dynamicWatches: watches.NewDynamicWatches(),
-		Parameters:     params,
-		expectations:   expectations.NewClustersExpectations(client),
+		Client:              client,
+		recorder:            mgr.GetEventRecorderFor(controllerName),
+		dynamicWatches:      watches.NewDynamicWatches(),
+		Parameters:          params,
+		expectations:        expectations.NewClustersExpectations(client),
+		autoscalingObserver: autoscaling.NewObserver(),
 	}
 }
 
@@ -119,6 +123,8 @@ type ReconcileLogstash struct {
 	// iteration is the number of times this controller has run its Reconcile method
 	iteration    uint64
 	expectations *expectations.ClustersExpectation
+	// autoscalingObserver keeps track of the pipelines statistics of the autoscaled Logstash resources
+	autoscalingObserver *autoscaling.Observer
 }
 
 // Reconcile reads that state of the cluster for a Logstash object and makes changes based on the state read
@@ -143,14 +149,27 @@ func (r *ReconcileLogstash) Reconcile(ctx context.Context, request reconcile.Req
 
 	if common.IsUnmanaged(ctx, logstash) {
 		ulog.FromContext(ctx).Info("Object is currently not managed by this controller. Skipping reconciliation")
-		return reconcile.Result{}, nil
+		return reconcile.Result{}, tracing.CaptureError(ctx, common.ReconcilePausedCondition(ctx, r.Client, logstash, &logstash.Status.Conditions, true))
+	}
+	if err := common.ReconcilePausedCondition(ctx, r.Client, logstash, &logstash.Status.Conditions, false); err != nil {
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
 	}
 
 	if logstash.IsMarkedForDeletion() {
 		return reconcile.Result{}, nil
 	}
 
-	results, status := r.doReconcile(ctx, *logstash)
+	results, status, pipelinesMessage := r.doReconcile(ctx, *logstash)
+	state := commonv1alpha1.NewResourceState(
+		logstash.Generation, string(status.Health), status.AvailableNodes, status.ExpectedNodes, logstash.Spec.Version, status.Version,
+	)
+	if pipelinesMessage != "" {
+		if state.Degraded {
+			pipelinesMessage = state.DegradedMessage + ". " + pipelinesMessage
+		}
+		state.Degraded, state.DegradedMessage = true, pipelinesMessage
+	}
+	common.UpdateStandardConditions(&status.Conditions, state, results)
 	logger := ulog.FromContext(ctx)
 
 	err := updateStatus(ctx, *logstash, r.Client, status)
@@ -160,11 +179,13 @@ func (r *ReconcileLogstash) Reconcile(ctx context.Context, request reconcile.Req
 			return reconcile.Result{Requeue: true}, nil
 		}
 		k8s.MaybeEmitErrorEvent(r.recorder, err, logstash, events.EventReconciliationError, "Reconciliation error: %v", err)
+	} else {
+		err = scale(ctx, r.Client, r.recorder, *logstash, status)
 	}
 	return results.WithError(err).Aggregate()
 }
 
-func (r *ReconcileLogstash) doReconcile(ctx context.Context, logstash logstashv1alpha1.Logstash) (*reconciler.Results, logstashv1alpha1.LogstashStatus) {
+func (r *ReconcileLogstash) doReconcile(ctx context.Context, logstash logstashv1alpha1.Logstash) (*reconciler.Results, logstashv1alpha1.LogstashStatus, string) {
 	defer tracing.Span(&ctx)()
 	logger := ulog.FromContext(ctx)
 	results := reconciler.NewResult(ctx)
@@ -172,39 +193,40 @@ func (r *ReconcileLogstash) doReconcile(ctx context.Context, logstash logstashv1
 
 	areAssocsConfigured, err := association.AreConfiguredIfSet(ctx, logstash.GetAssociations(), r.recorder)
 	if err != nil {
-		return results.WithError(err), status
+		return results.WithError(err), status, ""
 	}
 	if !areAssocsConfigured {
-		return results, status
+		return results, status, ""
 	}
 
 	logstashVersion, err := version.Parse(logstash.Spec.Version)
 	if err != nil {
-		return results.WithError(err), status
+		return results.WithError(err), status, ""
 	}
 	assocAllowed, err := association.AllowVersion(logstashVersion, &logstash, logger, r.recorder)
 	if err != nil {
-		return results.WithError(err), status
+		return results.WithError(err), status, ""
 	}
 	if !assocAllowed {
-		return results, status // will eventually retry
+		return results, status, "" // will eventually retry
 	}
 
 	// Run basic validations as a fallback in case webhook is disabled.
 	if err := r.validate(ctx, logstash); err != nil {
 		results = results.WithError(err)
-		return results, status
+		return results, status, ""
 	}
 
 	return internalReconcile(Params{
-		Context:        ctx,
-		Client:         r.Client,
-		EventRecorder:  r.recorder,
-		Watches:        r.dynamicWatches,
-		Logstash:       logstash,
-		Status:         status,
-		OperatorParams: r.Parameters,
-		Expectations:   r.expectations.ForCluster(k8s.ExtractNamespacedName(&logstash)),
+		Context:             ctx,
+		Client:              r.Client,
+		EventRecorder:       r.recorder,
+		Watches:             r.dynamicWatches,
+		Logstash:            logstash,
+		Status:              status,
+		OperatorParams:      r.Parameters,
+		Expectations:        r.expectations.ForCluster(k8s.ExtractNamespacedName(&logstash)),
+		AutoscalingObserver: r.autoscalingObserver,
 	})
 }
 
@@ -221,7 +243,9 @@ func (r *ReconcileLogstash) validate(ctx context.Context, logstash logstashv1alp
 }
 
 func (r *ReconcileLogstash) onDelete(ctx context.Context, obj types.NamespacedName) error {
+	metrics.DeleteResourceMetrics(ctx, logstashv1alpha1.Kind, obj)
 	r.expectations.RemoveCluster(obj)
+	r.autoscalingObserver.Forget(obj)
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(keystore.SecureSettingsWatchName(obj))
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(common.ConfigRefWatchName(obj))
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(pipelines.RefWatchName(obj))
@@ -27,12 +27,14 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
 	logstashv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/comparison"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/expectations"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash/autoscaling"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash/labels"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 )
@@ -53,14 +55,25 @@ var (
 func newReconcileLogstash(objs ...client.Object) *ReconcileLogstash {
 	client := k8s.NewFakeClient(objs...)
 	r := &ReconcileLogstash{
-		Client:         client,
-		recorder:       record.NewFakeRecorder(100),
-		dynamicWatches: watches.NewDynamicWatches(),
-		expectations:   expectations.NewClustersExpectations(client),
+		Client:              client,
+		recorder:            record.NewFakeRecorder(100),
+		dynamicWatches:      watches.NewDynamicWatches(),
+		expectations:        expectations.NewClustersExpectations(client),
+		autoscalingObserver: autoscaling.NewObserver(),
 	}
 	return r
 }
 
+// readyConditions are the standard conditions of a running Logstash at generation 2.
+var readyConditions = commonv1alpha1.Conditions{
+	{Type: commonv1alpha1.ReadyCondition, Status: corev1.ConditionTrue, ObservedGeneration: 2, Reason: commonv1alpha1.AvailableReason},
+	{Type: commonv1alpha1.ReconcilingCondition, Status: corev1.ConditionFalse, ObservedGeneration: 2, Reason: commonv1alpha1.ReconciledReason},
+	{Type: commonv1alpha1.StalledCondition, Status: corev1.ConditionFalse, ObservedGeneration: 2, Reason: commonv1alpha1.ProgressingReason},
+	{Type: commonv1alpha1.ReconciliationCompleteCondition, Status: corev1.ConditionTrue, ObservedGeneration: 2, Reason: commonv1alpha1.ReconciledReason},
+	{Type: commonv1alpha1.DegradedCondition, Status: corev1.ConditionFalse, ObservedGeneration: 2, Reason: commonv1alpha1.HealthyReason},
+	{Type: commonv1alpha1.UpgradeInProgressCondition, Status: corev1.ConditionFalse, ObservedGeneration: 2, Reason: commonv1alpha1.UpToDateReason},
+}
+
 func TestReconcileLogstash_Reconcile(t *testing.T) {
 	defaultLabels := (&logstashv1alpha1.Logstash{ObjectMeta: metav1.ObjectMeta{Name: "testLogstash"}}).GetIdentityLabels()
 	tests := []struct {
@@ -113,6 +126,7 @@ func TestReconcileLogstash_Reconcile(t *testing.T) {
 				},
 				Status: logstashv1alpha1.LogstashStatus{
 					ObservedGeneration: 1,
+					Conditions:         commonv1alpha1.Conditions{{Type: commonv1alpha1.PausedCondition, Status: corev1.ConditionTrue, Message: "Reconciliation is suspended by the eck.k8s.elastic.co/managed annotation"}},
 				},
 			},
 			expectedObjects: []expectedObject{},
@@ -147,6 +161,14 @@ func TestReconcileLogstash_Reconcile(t *testing.T) {
 				},
 				Status: logstashv1alpha1.LogstashStatus{
 					ObservedGeneration: 2,
+					Conditions: commonv1alpha1.Conditions{
+						{Type: commonv1alpha1.ReadyCondition, Status: corev1.ConditionFalse, ObservedGeneration: 2, Reason: commonv1alpha1.UnavailableReason, Message: "Health is unknown"},
+						{Type: commonv1alpha1.ReconcilingCondition, Status: corev1.ConditionTrue, ObservedGeneration: 2, Reason: commonv1alpha1.ReconcilingReason, Message: "Version string empty"},
+						{Type: commonv1alpha1.StalledCondition, Status: corev1.ConditionFalse, ObservedGeneration: 2, Reason: commonv1alpha1.ProgressingReason},
+						{Type: commonv1alpha1.ReconciliationCompleteCondition, Status: corev1.ConditionFalse, ObservedGeneration: 2, Reason: commonv1alpha1.ReconcilingReason, Message: "Version string empty"},
+						{Type: commonv1alpha1.DegradedCondition, Status: corev1.ConditionTrue, ObservedGeneration: 2, Reason: commonv1alpha1.DegradedReason, Message: "Health is unknown"},
+						{Type: commonv1alpha1.UpgradeInProgressCondition, Status: corev1.ConditionUnknown, ObservedGeneration: 2, Reason: commonv1alpha1.VersionUnknownReason, Message: "No running version reported"},
+					},
 				},
 			},
 			expectedObjects: []expectedObject{},
@@ -249,6 +271,7 @@ func TestReconcileLogstash_Reconcile(t *testing.T) {
 					AvailableNodes:     1,
 					Health:             logstashv1alpha1.LogstashGreenHealth,
 					ObservedGeneration: 2,
+					Conditions:         readyConditions,
 					Selector:           "common.k8s.elastic.co/type=logstash,logstash.k8s.elastic.co/name=testLogstash",
 				},
 			},
@@ -375,6 +398,7 @@ func TestReconcileLogstash_Reconcile(t *testing.T) {
 					AvailableNodes:     1,
 					Health:             logstashv1alpha1.LogstashGreenHealth,
 					ObservedGeneration: 2,
+					Conditions:         readyConditions,
 					Selector:           "common.k8s.elastic.co/type=logstash,logstash.k8s.elastic.co/name=testLogstash",
 				},
 			},
@@ -498,6 +522,7 @@ func TestReconcileLogstash_Reconcile(t *testing.T) {
 					AvailableNodes:     1,
 					Health:             logstashv1alpha1.LogstashGreenHealth,
 					ObservedGeneration: 2,
+					Conditions:         readyConditions,
 					Selector:           "common.k8s.elastic.co/type=logstash,logstash.k8s.elastic.co/name=testLogstash",
 				},
 			},
@@ -607,7 +632,10 @@ func TestReconcileLogstash_Reconcile(t *testing.T) {
 					AvailableNodes:     1,
 					Health:             logstashv1alpha1.LogstashGreenHealth,
 					ObservedGeneration: 2,
-					Selector:           "common.k8s.elastic.co/type=logstash,logstash.k8s.elastic.co/name=testLogstash",
+					Conditions: append(readyConditions[:5:5], commonv1alpha1.Condition{
+						Type: commonv1alpha1.UpgradeInProgressCondition, Status: corev1.ConditionUnknown, ObservedGeneration: 2, Reason: commonv1alpha1.VersionUnknownReason, Message: "No running version reported",
+					}),
+					Selector: "common.k8s.elastic.co/type=logstash,logstash.k8s.elastic.co/name=testLogstash",
 				},
 			},
 			wantErr: false,
@@ -713,7 +741,11 @@ func TestReconcileLogstash_Resize(t *testing.T) {
 				ss.UID = uuid.NewUUID()
 				_ = r.Client.Update(ctx, &ss)
 
-				// Final pass of the reconciler  deletes the logstash annotations
+				// Final pass of the reconciler  deletes the logstash annotations, which conflicts with the update of the
+				// status reporting the completed reconciliation
+				result, err = r.Reconcile(ctx, request)
+				require.NoError(t, err)
+				require.Equal(t, reconcile.Result{Requeue: true}, result)
 				result, err = r.Reconcile(ctx, request)
 				require.NoError(t, err)
 				require.Equal(t, reconcile.Result{Requeue: false}, result)
@@ -5,6 +5,8 @@
 package logstash
 
 import (
+	"hash"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -23,7 +25,9 @@ const (
 	PipelineFileName = "pipelines.yml"
 )
 
-func reconcilePipeline(params Params) error {
+// reconcilePipeline reconciles the Secret holding the pipelines of Logstash. The pipelines are written to the config
+// hash, to restart the Pods on changes, only if Logstash cannot reload them automatically.
+func reconcilePipeline(params Params, reloadAutomatically bool, configHash hash.Hash) error {
 	defer tracing.Span(&params.Context)()
 
 	cfgBytes, err := buildPipeline(params)
@@ -52,6 +56,10 @@ func reconcilePipeline(params Params) error {
 	); err != nil {
 		return err
 	}
+
+	if !reloadAutomatically {
+		_, _ = configHash.Write(cfgBytes)
+	}
 	return nil
 }
 
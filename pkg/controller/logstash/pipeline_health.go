@@ -0,0 +1,153 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package logstash
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	logstashv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash/autoscaling"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash/labels"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// pipelinesCheckPeriod is the period at which the pipelines of the ready Logstash Pods are checked.
+const pipelinesCheckPeriod = time.Minute
+
+// pipelineFailure describes a pipeline which failed to start or to reload on some Pods.
+type pipelineFailure struct {
+	// id is the ID of the pipeline.
+	id string
+	// pods are the names of the Pods the pipeline failed on.
+	pods []string
+	// message is the error message reported by one of the Pods.
+	message string
+}
+
+// pipelinesHealth is the result of the check of the pipelines of the ready Logstash Pods.
+type pipelinesHealth struct {
+	// checkedPods is the number of Pods whose pipelines statistics could be retrieved.
+	checkedPods int
+	// failedPods is the number of Pods with at least one failed pipeline.
+	failedPods int
+	// failures are the failed pipelines, sorted by ID.
+	failures []pipelineFailure
+}
+
+// reconcilePipelinesHealth checks the pipelines of the ready Logstash Pods through the node stats API. Pipelines which
+// failed to start or to reload, for example because of an invalid configuration or of a missing plugin, are reported
+// in Warning events and lower the health of Logstash: the health is yellow if some of the Pods have failed pipelines,
+// and red if all of them have. It also returns a message describing the failed pipelines, empty if there is none.
+func reconcilePipelinesHealth(params Params, status logstashv1alpha1.LogstashStatus) (*reconciler.Results, logstashv1alpha1.LogstashStatus, string) {
+	defer tracing.Span(&params.Context)()
+	results := reconciler.NewResult(params.Context)
+
+	pods, err := k8s.PodsMatchingLabels(params.Client, params.Logstash.Namespace, map[string]string{labels.NameLabelName: params.Logstash.Name})
+	if err != nil {
+		return results.WithError(err), status, ""
+	}
+	readyPods := make([]corev1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if k8s.IsPodReady(pod) && pod.Status.PodIP != "" {
+			readyPods = append(readyPods, pod)
+		}
+	}
+	if len(readyPods) == 0 {
+		return results, status, ""
+	}
+
+	statsClient, err := autoscaling.NewStatsClient(params.Context, params.Client, params.OperatorParams.Dialer, params.Logstash, params.APIServerConfig)
+	if err != nil {
+		return results.WithError(err), status, ""
+	}
+	health := checkPipelines(params.Context, statsClient, readyPods)
+	// pipelines are checked again periodically, as they may fail at any time when they are reloaded
+	results.WithReconciliationState(reconciler.RequeueAfter(pipelinesCheckPeriod).ReconciliationComplete())
+	if len(health.failures) == 0 {
+		return results, status, ""
+	}
+
+	for _, failure := range health.failures {
+		params.Recorder().Eventf(&params.Logstash, corev1.EventTypeWarning, events.EventReasonUnhealthy,
+			"Pipeline %s failed on %d out of %d Pods: %s", failure.id, len(failure.pods), health.checkedPods, failure.message)
+	}
+	status.Health = withPipelinesHealth(status.Health, health)
+	return results, status, health.message()
+}
+
+// checkPipelines retrieves the statistics of the pipelines of the given Pods and returns the pipelines which failed.
+// Pods whose statistics cannot be retrieved are ignored.
+func checkPipelines(ctx context.Context, client autoscaling.StatsClient, pods []corev1.Pod) pipelinesHealth {
+	var health pipelinesHealth
+	failures := map[string]*pipelineFailure{}
+	for _, pod := range pods {
+		stats, err := client.PipelinesStats(ctx, pod)
+		if err != nil {
+			ulog.FromContext(ctx).V(1).Info("Failed to retrieve Logstash pipelines stats", "namespace", pod.Namespace, "pod_name", pod.Name, "error", err.Error())
+			continue
+		}
+		health.checkedPods++
+		podFailed := false
+		for id, pipeline := range stats.Pipelines {
+			if !pipeline.Reloads.Failed() {
+				continue
+			}
+			podFailed = true
+			failure, exists := failures[id]
+			if !exists {
+				failure = &pipelineFailure{id: id, message: pipeline.Reloads.LastError.Message}
+				failures[id] = failure
+			}
+			failure.pods = append(failure.pods, pod.Name)
+		}
+		if podFailed {
+			health.failedPods++
+		}
+	}
+	for _, failure := range failures {
+		health.failures = append(health.failures, *failure)
+	}
+	sort.Slice(health.failures, func(i, j int) bool {
+		return health.failures[i].id < health.failures[j].id
+	})
+	return health
+}
+
+// message describes the failed pipelines, to be reported in the Degraded condition of Logstash.
+func (h pipelinesHealth) message() string {
+	if len(h.failures) == 0 {
+		return ""
+	}
+	ids := make([]string, 0, len(h.failures))
+	for _, failure := range h.failures {
+		ids = append(ids, failure.id)
+	}
+	return fmt.Sprintf("Pipelines failed on %d out of %d Pods: %s", h.failedPods, h.checkedPods, strings.Join(ids, ", "))
+}
+
+// withPipelinesHealth returns the given health lowered according to the failed pipelines: red if all the checked Pods
+// have failed pipelines, yellow if only some of them have.
+func withPipelinesHealth(health logstashv1alpha1.LogstashHealth, pipelines pipelinesHealth) logstashv1alpha1.LogstashHealth {
+	switch {
+	case pipelines.failedPods == 0:
+		return health
+	case pipelines.failedPods == pipelines.checkedPods:
+		return logstashv1alpha1.LogstashRedHealth
+	case health == logstashv1alpha1.LogstashGreenHealth:
+		return logstashv1alpha1.LogstashYellowHealth
+	default:
+		return health
+	}
+}
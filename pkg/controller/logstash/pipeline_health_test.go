@@ -0,0 +1,113 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package logstash
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	logstashv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash/autoscaling"
+)
+
+type fakeStatsClient map[string]autoscaling.PipelinesStats
+
+func (f fakeStatsClient) PipelinesStats(_ context.Context, pod corev1.Pod) (autoscaling.PipelinesStats, error) {
+	stats, ok := f[pod.Name]
+	if !ok {
+		return autoscaling.PipelinesStats{}, errors.New("connection refused")
+	}
+	return stats, nil
+}
+
+func failedReloads(message string, lastSuccess, lastFailure *string) autoscaling.ReloadsStats {
+	return autoscaling.ReloadsStats{
+		Failures:             1,
+		LastError:            &autoscaling.ReloadError{Message: message},
+		LastSuccessTimestamp: lastSuccess,
+		LastFailureTimestamp: lastFailure,
+	}
+}
+
+func Test_checkPipelines(t *testing.T) {
+	pods := []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "ls-0"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "ls-1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "ls-2"}},
+	}
+	client := fakeStatsClient{
+		"ls-0": {Pipelines: map[string]autoscaling.PipelineStats{
+			"main":  {Reloads: failedReloads("Couldn't find any input plugin named 'kafka2'", nil, ptr.To("2024-05-01T10:00:00.000Z"))},
+			"beats": {},
+		}},
+		"ls-1": {Pipelines: map[string]autoscaling.PipelineStats{
+			"main":  {Reloads: failedReloads("Expected one of [ \\t\\r\\n]", nil, ptr.To("2024-05-01T10:00:00.000Z"))},
+			"beats": {Reloads: failedReloads("Expected one of #, {", ptr.To("2024-05-01T10:00:00.000Z"), ptr.To("2024-05-01T11:00:00.000Z"))},
+		}},
+		// failure fixed by a later reload
+		"ls-2": {Pipelines: map[string]autoscaling.PipelineStats{
+			"main": {Reloads: failedReloads("Expected one of #, {", ptr.To("2024-05-01T11:00:00.000Z"), ptr.To("2024-05-01T10:00:00.000Z"))},
+		}},
+	}
+
+	health := checkPipelines(context.Background(), client, append(pods, corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "ls-3"}}))
+	require.Equal(t, pipelinesHealth{
+		checkedPods: 3,
+		failedPods:  2,
+		failures: []pipelineFailure{
+			{id: "beats", pods: []string{"ls-1"}, message: "Expected one of #, {"},
+			{id: "main", pods: []string{"ls-0", "ls-1"}, message: "Couldn't find any input plugin named 'kafka2'"},
+		},
+	}, health)
+	require.Equal(t, "Pipelines failed on 2 out of 3 Pods: beats, main", health.message())
+
+	require.Equal(t, pipelinesHealth{checkedPods: 1}, checkPipelines(context.Background(), client, pods[2:]))
+	require.Empty(t, pipelinesHealth{checkedPods: 1}.message())
+}
+
+func Test_withPipelinesHealth(t *testing.T) {
+	tests := []struct {
+		name      string
+		health    logstashv1alpha1.LogstashHealth
+		pipelines pipelinesHealth
+		want      logstashv1alpha1.LogstashHealth
+	}{
+		{
+			name:      "no failed pipelines",
+			health:    logstashv1alpha1.LogstashGreenHealth,
+			pipelines: pipelinesHealth{checkedPods: 2},
+			want:      logstashv1alpha1.LogstashGreenHealth,
+		},
+		{
+			name:      "pipelines failed on some Pods",
+			health:    logstashv1alpha1.LogstashGreenHealth,
+			pipelines: pipelinesHealth{checkedPods: 2, failedPods: 1},
+			want:      logstashv1alpha1.LogstashYellowHealth,
+		},
+		{
+			name:      "pipelines failed on some Pods of an unhealthy Logstash",
+			health:    logstashv1alpha1.LogstashRedHealth,
+			pipelines: pipelinesHealth{checkedPods: 2, failedPods: 1},
+			want:      logstashv1alpha1.LogstashRedHealth,
+		},
+		{
+			name:      "pipelines failed on all Pods",
+			health:    logstashv1alpha1.LogstashGreenHealth,
+			pipelines: pipelinesHealth{checkedPods: 2, failedPods: 2},
+			want:      logstashv1alpha1.LogstashRedHealth,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, withPipelinesHealth(tt.health, tt.pipelines))
+		})
+	}
+}
@@ -6,6 +6,7 @@ package logstash
 
 import (
 	"context"
+	"hash/fnv"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -122,3 +123,31 @@ func Test_buildPipeline(t *testing.T) {
 		})
 	}
 }
+
+func Test_reconcilePipeline_configHash(t *testing.T) {
+	newParams := func(pipeline string) Params {
+		return Params{
+			Context:       context.Background(),
+			Client:        k8s.NewFakeClient(),
+			EventRecorder: &record.FakeRecorder{},
+			Watches:       watches.NewDynamicWatches(),
+			Logstash: logstashv1alpha1.Logstash{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ls"},
+				Spec: logstashv1alpha1.LogstashSpec{
+					Pipelines: []commonv1.Config{{Data: map[string]interface{}{"pipeline.id": pipeline}}},
+				},
+			},
+		}
+	}
+	configHash := func(pipeline string, reloadAutomatically bool) uint32 {
+		h := fnv.New32a()
+		require.NoError(t, reconcilePipeline(newParams(pipeline), reloadAutomatically, h))
+		return h.Sum32()
+	}
+
+	// pipelines are reloaded by Logstash, changing them must not restart the Pods
+	require.Equal(t, fnv.New32a().Sum32(), configHash("main", true))
+	require.Equal(t, configHash("main", true), configHash("other", true))
+	// pipelines cannot be reloaded, changing them must restart the Pods
+	require.NotEqual(t, configHash("main", false), configHash("other", false))
+}
@@ -0,0 +1,96 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package logstash
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	logstashv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash/volume"
+)
+
+const (
+	// InstallPluginsContainerName is the name of the container that installs the Logstash plugins.
+	InstallPluginsContainerName = "logstash-internal-install-plugins"
+)
+
+// pluginsResources are the default request and limits for the plugins init container, which runs a JVM.
+var pluginsResources = corev1.ResourceRequirements{
+	Requests: map[corev1.ResourceName]resource.Quantity{
+		corev1.ResourceMemory: resource.MustParse("1Gi"),
+		corev1.ResourceCPU:    resource.MustParse("1"),
+	},
+	Limits: map[corev1.ResourceName]resource.Quantity{
+		corev1.ResourceMemory: resource.MustParse("1Gi"),
+		corev1.ResourceCPU:    resource.MustParse("1"),
+	},
+}
+
+// installPluginsContainer returns an init container that installs the plugins of the given Logstash in a copy of the
+// Logstash home directory, which replaces the Logstash home directory of the image in the Logstash container.
+// It returns false if there is no plugin to install.
+func installPluginsContainer(ls logstashv1alpha1.Logstash) (corev1.Container, bool) {
+	if !ls.Spec.HasPlugins() {
+		return corev1.Container{}, false
+	}
+	return corev1.Container{
+		// Image will be inherited from pod template defaults
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Name:            InstallPluginsContainerName,
+		Command:         []string{"/usr/bin/env", "bash", "-c", renderPluginsScript(*ls.Spec.Plugins)},
+		VolumeMounts:    []corev1.VolumeMount{volume.PluginsSharedVolume.InitContainerVolumeMount()},
+		Resources:       pluginsResources,
+	}, true
+}
+
+// renderPluginsScript renders the script installing the given plugins. The Logstash home directory is first copied to
+// the plugins volume, then the plugins are installed with the logstash-plugin tool of the copy.
+// The installation is skipped if the plugins are already installed, when the init container is restarted.
+func renderPluginsScript(plugins logstashv1alpha1.LogstashPluginsSpec) string {
+	home := volume.InitContainerPluginsVolumeMountPath
+	pluginBin := path.Join(home, "bin", "logstash-plugin")
+	initializedFlag := path.Join(home, "elastic-internal-install-plugins.ok")
+
+	var script strings.Builder
+	script.WriteString("set -eu\n")
+	fmt.Fprintf(&script, "if [[ -f %s ]]; then\n", initializedFlag)
+	script.WriteString("  echo \"Logstash plugins already installed.\"\n")
+	script.WriteString("  exit 0\n")
+	script.WriteString("fi\n")
+	fmt.Fprintf(&script, "cp -a %s/. %s\n", volume.LogstashHomePath, home)
+	if plugins.OfflinePack != "" {
+		fmt.Fprintf(&script, "%s install %s\n", pluginBin, shellQuote(plugins.OfflinePack))
+	}
+	for _, plugin := range plugins.Install {
+		args := []string{pluginBin, "install"}
+		if plugin.Path != "" {
+			// the plugin is not published in the RubyGems repository
+			args = append(args, "--no-verify", shellQuote(plugin.Path))
+		} else {
+			if plugin.Version != "" {
+				args = append(args, "--version", shellQuote(plugin.Version))
+			}
+			if plugins.OfflinePack != "" {
+				// only use the gems of the offline pack, for air-gapped environments
+				args = append(args, "--local")
+			}
+			args = append(args, shellQuote(plugin.Name))
+		}
+		script.WriteString(strings.Join(args, " ") + "\n")
+	}
+	fmt.Fprintf(&script, "touch %s\n", initializedFlag)
+	script.WriteString("echo \"Logstash plugins successfully installed.\"\n")
+	return script.String()
+}
+
+// shellQuote quotes the given string to be used as a single word in a shell script.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
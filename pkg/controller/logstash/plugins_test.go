@@ -0,0 +1,79 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package logstash
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	logstashv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+)
+
+func Test_renderPluginsScript(t *testing.T) {
+	tests := []struct {
+		name    string
+		plugins logstashv1alpha1.LogstashPluginsSpec
+		want    string
+	}{
+		{
+			name: "plugins from RubyGems and from gem files",
+			plugins: logstashv1alpha1.LogstashPluginsSpec{
+				Install: []logstashv1alpha1.LogstashPlugin{
+					{Name: "logstash-output-kafka"},
+					{Name: "logstash-input-http", Version: "3.8.0"},
+					{Name: "logstash-filter-custom", Path: "/mnt/plugins/logstash-filter-custom-1.0.0.gem"},
+				},
+			},
+			want: `set -eu
+if [[ -f /mnt/elastic-internal/logstash-home/elastic-internal-install-plugins.ok ]]; then
+  echo "Logstash plugins already installed."
+  exit 0
+fi
+cp -a /usr/share/logstash/. /mnt/elastic-internal/logstash-home
+/mnt/elastic-internal/logstash-home/bin/logstash-plugin install 'logstash-output-kafka'
+/mnt/elastic-internal/logstash-home/bin/logstash-plugin install --version '3.8.0' 'logstash-input-http'
+/mnt/elastic-internal/logstash-home/bin/logstash-plugin install --no-verify '/mnt/plugins/logstash-filter-custom-1.0.0.gem'
+touch /mnt/elastic-internal/logstash-home/elastic-internal-install-plugins.ok
+echo "Logstash plugins successfully installed."
+`,
+		},
+		{
+			name: "offline pack",
+			plugins: logstashv1alpha1.LogstashPluginsSpec{
+				Install:     []logstashv1alpha1.LogstashPlugin{{Name: "logstash-output-kafka"}},
+				OfflinePack: "file:///mnt/plugins/logstash-offline-plugins-8.15.0.zip",
+			},
+			want: `set -eu
+if [[ -f /mnt/elastic-internal/logstash-home/elastic-internal-install-plugins.ok ]]; then
+  echo "Logstash plugins already installed."
+  exit 0
+fi
+cp -a /usr/share/logstash/. /mnt/elastic-internal/logstash-home
+/mnt/elastic-internal/logstash-home/bin/logstash-plugin install 'file:///mnt/plugins/logstash-offline-plugins-8.15.0.zip'
+/mnt/elastic-internal/logstash-home/bin/logstash-plugin install --local 'logstash-output-kafka'
+touch /mnt/elastic-internal/logstash-home/elastic-internal-install-plugins.ok
+echo "Logstash plugins successfully installed."
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, renderPluginsScript(tt.plugins))
+		})
+	}
+}
+
+func Test_installPluginsContainer(t *testing.T) {
+	_, ok := installPluginsContainer(logstashv1alpha1.Logstash{})
+	assert.False(t, ok)
+	_, ok = installPluginsContainer(logstashv1alpha1.Logstash{Spec: logstashv1alpha1.LogstashSpec{Plugins: &logstashv1alpha1.LogstashPluginsSpec{}}})
+	assert.False(t, ok)
+	container, ok := installPluginsContainer(logstashv1alpha1.Logstash{Spec: logstashv1alpha1.LogstashSpec{
+		Plugins: &logstashv1alpha1.LogstashPluginsSpec{OfflinePack: "file:///mnt/plugins/pack.zip"},
+	}})
+	assert.True(t, ok)
+	assert.Equal(t, InstallPluginsContainerName, container.Name)
+}
@@ -22,6 +22,8 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/container"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
+	commonhash "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/restart"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash/network"
@@ -85,12 +87,19 @@ func buildPodTemplate(params Params, configHash hash.Hash32) (corev1.PodTemplate
 		return corev1.PodTemplateSpec{}, err
 	}
 
+	if spec.HasPlugins() {
+		// plugins to install, to rotate the Pods when plugins are added, removed or installed from a different source
+		commonhash.WriteHashObject(configHash, spec.Plugins)
+	}
+
 	labels := maps.Merge(params.Logstash.GetPodIdentityLabels(), map[string]string{
 		VersionLabelName: spec.Version})
 
 	annotations := map[string]string{
 		ConfigHashAnnotationName: fmt.Sprint(configHash.Sum32()),
 	}
+	// start time of the last restart requested by the user, to restart the Pods when a new one is requested
+	annotations = maps.Merge(annotations, restart.PodAnnotations(&params.Logstash))
 
 	ports := getDefaultContainerPorts()
 
@@ -100,10 +109,19 @@ func buildPodTemplate(params Params, configHash hash.Hash32) (corev1.PodTemplate
 			WithInitContainers(params.KeystoreResources.InitContainer)
 	}
 
+	if pluginsContainer, ok := installPluginsContainer(params.Logstash); ok {
+		builder = builder.
+			WithVolumeLikes(volume.PluginsSharedVolume).
+			WithInitContainers(pluginsContainer)
+	}
+
 	v, err := version.Parse(spec.Version)
 	if err != nil {
 		return corev1.PodTemplateSpec{}, err // error unlikely and should have been caught during validation
 	}
+	if err := container.CheckImageCatalog(spec.Image, container.LogstashImage, v); err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
 
 	builder = builder.
 		WithResources(DefaultResources).
@@ -124,8 +142,9 @@ func buildPodTemplate(params Params, configHash hash.Hash32) (corev1.PodTemplate
 	if err != nil {
 		return corev1.PodTemplateSpec{}, err
 	}
+	builder = withPrometheusExporter(builder, params.Logstash, params.APIServerConfig)
 
-	return builder.PodTemplate, nil
+	return builder.WithPodMutationPolicy(logstashv1alpha1.Kind, params.Logstash.Namespace).PodTemplate, nil
 }
 
 func getDefaultContainerPorts() []corev1.ContainerPort {
@@ -136,19 +155,12 @@ func getDefaultContainerPorts() []corev1.ContainerPort {
 
 // readinessProbe is the readiness probe for the Logstash container
 func readinessProbe(params Params) corev1.Probe {
-	logstash := params.Logstash
-
 	var scheme = corev1.URISchemeHTTP
 	if params.APIServerConfig.UseTLS() {
 		scheme = corev1.URISchemeHTTPS
 	}
 
-	var port = network.HTTPPort
-	for _, service := range logstash.Spec.Services {
-		if service.Name == LogstashAPIServiceName && len(service.Service.Spec.Ports) > 0 {
-			port = int(service.Service.Spec.Ports[0].Port)
-		}
-	}
+	port := apiPort(params.Logstash)
 
 	probe := corev1.Probe{
 		FailureThreshold:    3,
@@ -70,6 +70,30 @@ func TestNewPodTemplateSpec(t *testing.T) {
 				assert.NotEmpty(t, logstashContainer.Ports)
 			},
 		},
+		{
+			name: "with plugins",
+			logstash: logstashv1alpha1.Logstash{
+				ObjectMeta: meta,
+				Spec: logstashv1alpha1.LogstashSpec{
+					Version: "8.6.1",
+					Plugins: &logstashv1alpha1.LogstashPluginsSpec{
+						Install: []logstashv1alpha1.LogstashPlugin{{Name: "logstash-output-kafka"}},
+					},
+				},
+			},
+			apiServerConfig: GetDefaultAPIServer(),
+			assertions: func(pod corev1.PodTemplateSpec) {
+				assert.Len(t, pod.Spec.InitContainers, 2)
+				assert.Len(t, pod.Spec.Volumes, 6)
+				logstashContainer := GetLogstashContainer(pod.Spec)
+				require.NotNil(t, logstashContainer)
+				assert.Equal(t, 6, len(logstashContainer.VolumeMounts))
+				assert.Contains(t, logstashContainer.VolumeMounts, corev1.VolumeMount{
+					Name:      "elastic-internal-logstash-plugins",
+					MountPath: "/usr/share/logstash",
+				})
+			},
+		},
 		{
 			name: "with custom image",
 			logstash: logstashv1alpha1.Logstash{
@@ -0,0 +1,247 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package logstash
+
+import (
+	"context"
+	"fmt"
+	"hash"
+	"net/url"
+	"reflect"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	logstashv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash/configs"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash/labels"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash/network"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
+)
+
+const (
+	// PrometheusExporterContainerName is the name of the container exposing the Logstash metrics in the Prometheus format.
+	PrometheusExporterContainerName = "prometheus-exporter"
+	// DefaultPrometheusExporterImage is the Logstash Prometheus exporter image used if none is specified.
+	DefaultPrometheusExporterImage = "kuskoman/logstash-exporter:v1.9.0"
+	// DefaultPrometheusExporterPort is the port on which the exporter exposes the metrics if none is specified.
+	DefaultPrometheusExporterPort int32 = 9198
+
+	prometheusPortName = "metrics"
+	// prometheusExporterURLKey is the key of the exporter Secret holding the URL of the Logstash API, which may contain
+	// the credentials of the API.
+	prometheusExporterURLKey = "LOGSTASH_URL"
+)
+
+// ServiceMonitorGVK is the GroupVersionKind of the Prometheus operator ServiceMonitor resource. The Prometheus operator
+// API is not vendored, ServiceMonitors are handled as unstructured objects.
+var ServiceMonitorGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"}
+
+func prometheusExporterPort(prometheus *logstashv1alpha1.PrometheusMonitoring) int32 {
+	if prometheus.Port == 0 {
+		return DefaultPrometheusExporterPort
+	}
+	return prometheus.Port
+}
+
+// prometheusExporterURL returns the URL of the Logstash API read by the exporter, including the API credentials if any.
+func prometheusExporterURL(apiServer configs.APIServer, port int) string {
+	scheme := "http"
+	if apiServer.UseTLS() {
+		scheme = "https"
+	}
+	apiURL := url.URL{Scheme: scheme, Host: fmt.Sprintf("localhost:%d", port)}
+	if apiServer.Username != "" {
+		apiURL.User = url.UserPassword(apiServer.Username, apiServer.Password)
+	}
+	return apiURL.String()
+}
+
+// reconcilePrometheusExporter reconciles the Secret and the Service of the Prometheus exporter, and the ServiceMonitor
+// selecting the Service if requested. The resources are deleted if the exporter is not enabled anymore.
+func reconcilePrometheusExporter(params Params, configHash hash.Hash) error {
+	ls := params.Logstash
+	prometheus := ls.Spec.Monitoring.Prometheus
+	if prometheus == nil {
+		return deletePrometheusExporterResources(params.Context, params.Client, ls)
+	}
+
+	exporterURL := prometheusExporterURL(params.APIServerConfig, apiPort(ls))
+	secret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ls.Namespace,
+			Name:      logstashv1alpha1.PrometheusExporterSecretName(ls.Name),
+			Labels:    labels.NewLabels(ls),
+		},
+		Data: map[string][]byte{prometheusExporterURLKey: []byte(exporterURL)},
+	}
+	if _, err := reconciler.ReconcileSecret(params.Context, params.Client, secret, &ls); err != nil {
+		return err
+	}
+	// restart the Pods if the credentials of the Logstash API change as the exporter reads them at startup only
+	_, _ = configHash.Write([]byte(exporterURL))
+
+	svc := newPrometheusService(ls)
+	if err := reconcileService(params, svc); err != nil {
+		return err
+	}
+
+	if prometheus.ServiceMonitor == nil {
+		return deleteServiceMonitor(params.Context, params.Client, ls)
+	}
+	return reconcileServiceMonitor(params.Context, params.Client, ls, *prometheus.ServiceMonitor)
+}
+
+func newPrometheusService(ls logstashv1alpha1.Logstash) *corev1.Service {
+	svc := corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ls.Namespace,
+			Name:      logstashv1alpha1.PrometheusServiceName(ls.Name),
+		},
+		Spec: corev1.ServiceSpec{ClusterIP: "None"},
+	}
+	lsLabels := labels.NewLabels(ls)
+	ports := []corev1.ServicePort{
+		{
+			Name:     prometheusPortName,
+			Protocol: corev1.ProtocolTCP,
+			Port:     prometheusExporterPort(ls.Spec.Monitoring.Prometheus),
+		},
+	}
+	return defaults.SetServiceDefaults(&svc, lsLabels, lsLabels, ports)
+}
+
+func newServiceMonitor(namespace, name string) *unstructured.Unstructured {
+	serviceMonitor := &unstructured.Unstructured{}
+	serviceMonitor.SetGroupVersionKind(ServiceMonitorGVK)
+	serviceMonitor.SetNamespace(namespace)
+	serviceMonitor.SetName(name)
+	return serviceMonitor
+}
+
+func reconcileServiceMonitor(ctx context.Context, c k8s.Client, ls logstashv1alpha1.Logstash, spec logstashv1alpha1.ServiceMonitor) error {
+	endpoint := map[string]interface{}{"port": prometheusPortName}
+	if spec.Interval != "" {
+		endpoint["interval"] = spec.Interval
+	}
+	expectedLabels := maps.Merge(labels.NewLabels(ls), spec.Labels)
+	expected := newServiceMonitor(ls.Namespace, logstashv1alpha1.PrometheusServiceName(ls.Name))
+	expected.SetLabels(expectedLabels)
+	expected.Object["spec"] = map[string]interface{}{
+		"selector": map[string]interface{}{
+			"matchLabels": toInterfaceMap(labels.NewLabels(ls)),
+		},
+		"endpoints": []interface{}{endpoint},
+	}
+
+	reconciled := newServiceMonitor(ls.Namespace, logstashv1alpha1.PrometheusServiceName(ls.Name))
+	err := reconciler.ReconcileResource(reconciler.Params{
+		Context:    ctx,
+		Client:     c,
+		Owner:      &ls,
+		Expected:   expected,
+		Reconciled: reconciled,
+		NeedsUpdate: func() bool {
+			return !maps.IsSubset(expectedLabels, reconciled.GetLabels()) ||
+				!reflect.DeepEqual(expected.Object["spec"], reconciled.Object["spec"])
+		},
+		UpdateReconciled: func() {
+			reconciled.SetLabels(maps.Merge(reconciled.GetLabels(), expectedLabels))
+			reconciled.Object["spec"] = expected.Object["spec"]
+		},
+	})
+	if meta.IsNoMatchError(err) {
+		return fmt.Errorf("cannot create the ServiceMonitor of Logstash %s/%s, the Prometheus operator CRDs are not installed: %w", ls.Namespace, ls.Name, err)
+	}
+	return err
+}
+
+// deletePrometheusExporterResources deletes the resources of the Prometheus exporter. The ServiceMonitor is only looked up
+// if the Service exists, so that the Prometheus operator API is never accessed if the exporter is not used.
+func deletePrometheusExporterResources(ctx context.Context, c k8s.Client, ls logstashv1alpha1.Logstash) error {
+	var svc corev1.Service
+	err := c.Get(ctx, types.NamespacedName{Namespace: ls.Namespace, Name: logstashv1alpha1.PrometheusServiceName(ls.Name)}, &svc)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := deleteServiceMonitor(ctx, c, ls); err != nil {
+		return err
+	}
+	secretKey := types.NamespacedName{Namespace: ls.Namespace, Name: logstashv1alpha1.PrometheusExporterSecretName(ls.Name)}
+	if err := k8s.DeleteSecretIfExists(ctx, c, secretKey); err != nil {
+		return err
+	}
+	if err := c.Delete(ctx, &svc); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func deleteServiceMonitor(ctx context.Context, c k8s.Client, ls logstashv1alpha1.Logstash) error {
+	err := c.Delete(ctx, newServiceMonitor(ls.Namespace, logstashv1alpha1.PrometheusServiceName(ls.Name)))
+	if err != nil && !apierrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return err
+	}
+	return nil
+}
+
+// withPrometheusExporter adds the Prometheus exporter sidecar container to the Logstash Pod template if enabled.
+func withPrometheusExporter(builder *defaults.PodTemplateBuilder, ls logstashv1alpha1.Logstash, apiServer configs.APIServer) *defaults.PodTemplateBuilder {
+	prometheus := ls.Spec.Monitoring.Prometheus
+	if prometheus == nil {
+		return builder
+	}
+	image := prometheus.Image
+	if image == "" {
+		image = DefaultPrometheusExporterImage
+	}
+	port := prometheusExporterPort(prometheus)
+	return builder.WithContainers(corev1.Container{
+		Name:  PrometheusExporterContainerName,
+		Image: image,
+		Ports: []corev1.ContainerPort{{Name: prometheusPortName, ContainerPort: port, Protocol: corev1.ProtocolTCP}},
+		Env: []corev1.EnvVar{
+			{Name: prometheusExporterURLKey, ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: logstashv1alpha1.PrometheusExporterSecretName(ls.Name)},
+				Key:                  prometheusExporterURLKey,
+			}}},
+			{Name: "PORT", Value: strconv.Itoa(int(port))},
+			// The certificate of the Logstash API is most likely not issued for localhost, the exporter does not verify it
+			// as the requests do not leave the Pod.
+			{Name: "HTTP_INSECURE", Value: strconv.FormatBool(apiServer.UseTLS())},
+		},
+	})
+}
+
+// apiPort returns the port of the Logstash API, which may be customized through the API Service.
+func apiPort(ls logstashv1alpha1.Logstash) int {
+	port := network.HTTPPort
+	for _, service := range ls.Spec.Services {
+		if service.Name == LogstashAPIServiceName && len(service.Service.Spec.Ports) > 0 {
+			port = int(service.Service.Spec.Ports[0].Port)
+		}
+	}
+	return port
+}
+
+func toInterfaceMap(m map[string]string) map[string]interface{} {
+	result := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		result[k] = v
+	}
+	return result
+}
@@ -24,8 +24,10 @@ import (
 
 	logstashv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/effectivespec"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	commonvolume "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash/labels"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash/sset"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash/volume"
@@ -100,12 +102,19 @@ func reconcileStatefulSet(params Params, podTemplate corev1.PodTemplateSpec) (*r
 	if err := controllerutil.SetControllerReference(&params.Logstash, &expected, scheme.Scheme); err != nil {
 		return results.WithError(err), params.Status
 	}
+	if err := effectivespec.Reconcile(params.Context, params.Client, &params.Logstash, logstashv1alpha1.Namer.Suffix(params.Logstash.Name, effectivespec.Suffix), params.Logstash.Spec, &expected); err != nil {
+		return results.WithError(err), params.Status
+	}
 	reconciled, err := sset.Reconcile(params.Context, params.Client, expected, params.Logstash, params.Expectations)
 
 	if err != nil {
 		return results.WithError(err), params.Status
 	}
 
+	if err := commonvolume.ReconcilePVCMetadata(params.Context, params.Client, &params.Logstash, labels.NewLabelSelectorForLogstash(params.Logstash)); err != nil {
+		return results.WithError(err), params.Status
+	}
+
 	var status logstashv1alpha1.LogstashStatus
 
 	if status, err = calculateStatus(&params, reconciled); err != nil {
@@ -0,0 +1,58 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package logstash
+
+import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	logstashv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/restart"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash/labels"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// reconcileRestart orchestrates the restart of the Pods requested with the restart annotation, if any, and returns the
+// status updated with its progress. During a rolling restart the Pods are replaced one at a time by the StatefulSet
+// controller as their template changes. During a full restart the Pods which are not restarted yet are all deleted
+// at once, once the StatefulSet controller has observed the new template.
+func reconcileRestart(params Params, status logstashv1alpha1.LogstashStatus) (logstashv1alpha1.LogstashStatus, error) {
+	defer tracing.Span(&params.Context)()
+	if params.Logstash.Status.Restart == nil {
+		return status, nil
+	}
+
+	pods, err := k8s.PodsMatchingLabels(params.Client, params.Logstash.Namespace, map[string]string{labels.NameLabelName: params.Logstash.Name})
+	if err != nil {
+		return status, err
+	}
+	if restart.IsFull(&params.Logstash) {
+		sts, err := retrieveActualStatefulSet(params.Client, params.Logstash)
+		if err != nil {
+			return status, err
+		}
+		if sts.Status.ObservedGeneration >= sts.Generation {
+			for _, pod := range pods {
+				if !pod.DeletionTimestamp.IsZero() || restart.IsRestarted(&params.Logstash, pod) {
+					continue
+				}
+				ulog.FromContext(params.Context).Info("Deleting Pod for full restart", "namespace", pod.Namespace, "pod_name", pod.Name)
+				opts := client.DeleteOptions{Preconditions: &metav1.Preconditions{UID: &pod.UID}}
+				if err := params.Client.Delete(params.Context, &pod, &opts); err != nil && !apierrors.IsNotFound(err) {
+					return status, err
+				}
+			}
+		}
+	}
+
+	if err := restart.UpdateProgress(params.Context, params.Client, params.Recorder(), &params.Logstash, pods, params.Logstash.Spec.Count); err != nil {
+		return status, err
+	}
+	status.Restart = params.Logstash.Status.Restart
+	return status, nil
+}
@@ -7,6 +7,7 @@ package validation
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
@@ -48,6 +49,8 @@ func validations() []validation {
 		checkESRefsNamed,
 		checkAssociations,
 		checkSinglePipelineSource,
+		checkAutoscaling,
+		checkPlugins,
 	}
 }
 
@@ -102,6 +105,57 @@ func checkSinglePipelineSource(a *lsv1alpha1.Logstash) field.ErrorList {
 	return nil
 }
 
+func checkAutoscaling(l *lsv1alpha1.Logstash) field.ErrorList {
+	spec := l.Spec.Autoscaling
+	if spec == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	path := field.NewPath("spec").Child("autoscaling")
+	if spec.MinReplicas < 1 {
+		errs = append(errs, field.Invalid(path.Child("minReplicas"), spec.MinReplicas, "minReplicas must be greater than 0"))
+	}
+	if spec.MaxReplicas < spec.MinReplicas {
+		errs = append(errs, field.Invalid(path.Child("maxReplicas"), spec.MaxReplicas, "maxReplicas must be greater than or equal to minReplicas"))
+	}
+	if spec.TargetQueueEvents == nil && spec.TargetEventLatency == nil {
+		errs = append(errs, field.Required(path, "Specify at least one of [`targetQueueEvents`, `targetEventLatency`]"))
+	}
+	if spec.TargetQueueEvents != nil && *spec.TargetQueueEvents < 1 {
+		errs = append(errs, field.Invalid(path.Child("targetQueueEvents"), *spec.TargetQueueEvents, "targetQueueEvents must be greater than 0"))
+	}
+	if spec.TargetEventLatency != nil && spec.TargetEventLatency.Duration <= 0 {
+		errs = append(errs, field.Invalid(path.Child("targetEventLatency"), spec.TargetEventLatency.Duration.String(), "targetEventLatency must be greater than 0"))
+	}
+	return errs
+}
+
+func checkPlugins(l *lsv1alpha1.Logstash) field.ErrorList {
+	spec := l.Spec.Plugins
+	if spec == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	path := field.NewPath("spec").Child("plugins")
+	if spec.OfflinePack != "" && !strings.HasPrefix(spec.OfflinePack, "file://") {
+		errs = append(errs, field.Invalid(path.Child("offlinePack"), spec.OfflinePack, "offlinePack must be a file:// URL"))
+	}
+	for i, plugin := range spec.Install {
+		pluginPath := path.Child("install").Index(i)
+		if plugin.Name == "" {
+			errs = append(errs, field.Required(pluginPath.Child("name"), "plugin name is required"))
+		}
+		if plugin.Version != "" && plugin.Path != "" {
+			msg := "Specify at most one of [`version`, `path`], not both"
+			errs = append(errs,
+				field.Forbidden(pluginPath.Child("version"), msg),
+				field.Forbidden(pluginPath.Child("path"), msg),
+			)
+		}
+	}
+	return errs
+}
+
 func checkESRefsNamed(l *lsv1alpha1.Logstash) field.ErrorList {
 	var errorList field.ErrorList
 	for i, esRef := range l.Spec.ElasticsearchRefs {
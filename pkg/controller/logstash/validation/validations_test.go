@@ -6,6 +6,7 @@ package validation
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -199,6 +200,136 @@ func Test_checkSinglePipelineSource(t *testing.T) {
 	}
 }
 
+func Test_checkAutoscaling(t *testing.T) {
+	targetQueueEvents := int64(1000)
+	tests := []struct {
+		name        string
+		autoscaling *lsv1alpha1.LogstashAutoscalingSpec
+		wantErrs    int
+	}{
+		{
+			name:        "autoscaling disabled",
+			autoscaling: nil,
+			wantErrs:    0,
+		},
+		{
+			name: "valid",
+			autoscaling: &lsv1alpha1.LogstashAutoscalingSpec{
+				MinReplicas:        1,
+				MaxReplicas:        5,
+				TargetQueueEvents:  &targetQueueEvents,
+				TargetEventLatency: &metav1.Duration{Duration: 100 * time.Millisecond},
+			},
+			wantErrs: 0,
+		},
+		{
+			name: "min equals max",
+			autoscaling: &lsv1alpha1.LogstashAutoscalingSpec{
+				MinReplicas:       3,
+				MaxReplicas:       3,
+				TargetQueueEvents: &targetQueueEvents,
+			},
+			wantErrs: 0,
+		},
+		{
+			name: "min lower than 1",
+			autoscaling: &lsv1alpha1.LogstashAutoscalingSpec{
+				MinReplicas:       0,
+				MaxReplicas:       3,
+				TargetQueueEvents: &targetQueueEvents,
+			},
+			wantErrs: 1,
+		},
+		{
+			name: "max lower than min",
+			autoscaling: &lsv1alpha1.LogstashAutoscalingSpec{
+				MinReplicas:       3,
+				MaxReplicas:       2,
+				TargetQueueEvents: &targetQueueEvents,
+			},
+			wantErrs: 1,
+		},
+		{
+			name: "no target",
+			autoscaling: &lsv1alpha1.LogstashAutoscalingSpec{
+				MinReplicas: 1,
+				MaxReplicas: 2,
+			},
+			wantErrs: 1,
+		},
+		{
+			name: "invalid targets",
+			autoscaling: &lsv1alpha1.LogstashAutoscalingSpec{
+				MinReplicas:        1,
+				MaxReplicas:        2,
+				TargetQueueEvents:  new(int64),
+				TargetEventLatency: &metav1.Duration{},
+			},
+			wantErrs: 2,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := checkAutoscaling(&lsv1alpha1.Logstash{Spec: lsv1alpha1.LogstashSpec{Autoscaling: tc.autoscaling}})
+			assert.Len(t, got, tc.wantErrs)
+		})
+	}
+}
+
+func Test_checkPlugins(t *testing.T) {
+	tests := []struct {
+		name     string
+		plugins  *lsv1alpha1.LogstashPluginsSpec
+		wantErrs int
+	}{
+		{
+			name:     "no plugins",
+			plugins:  nil,
+			wantErrs: 0,
+		},
+		{
+			name: "valid",
+			plugins: &lsv1alpha1.LogstashPluginsSpec{
+				Install: []lsv1alpha1.LogstashPlugin{
+					{Name: "logstash-output-kafka", Version: "11.3.4"},
+					{Name: "logstash-filter-custom", Path: "/mnt/plugins/logstash-filter-custom-1.0.0.gem"},
+				},
+				OfflinePack: "file:///mnt/plugins/logstash-offline-plugins-8.15.0.zip",
+			},
+			wantErrs: 0,
+		},
+		{
+			name: "missing name",
+			plugins: &lsv1alpha1.LogstashPluginsSpec{
+				Install: []lsv1alpha1.LogstashPlugin{{Version: "11.3.4"}},
+			},
+			wantErrs: 1,
+		},
+		{
+			name: "both version and path",
+			plugins: &lsv1alpha1.LogstashPluginsSpec{
+				Install: []lsv1alpha1.LogstashPlugin{{Name: "logstash-filter-custom", Version: "1.0.0", Path: "/mnt/plugins/logstash-filter-custom-1.0.0.gem"}},
+			},
+			wantErrs: 2,
+		},
+		{
+			name: "offline pack not a file URL",
+			plugins: &lsv1alpha1.LogstashPluginsSpec{
+				OfflinePack: "https://artifacts.example.com/logstash-offline-plugins-8.15.0.zip",
+			},
+			wantErrs: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := checkPlugins(&lsv1alpha1.Logstash{Spec: lsv1alpha1.LogstashSpec{Plugins: tc.plugins}})
+			assert.Len(t, got, tc.wantErrs)
+		})
+	}
+}
+
 func Test_checkSupportedVersion(t *testing.T) {
 	for _, tt := range []struct {
 		name    string
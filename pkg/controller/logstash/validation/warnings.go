@@ -0,0 +1,32 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package validation
+
+import (
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	lsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+)
+
+const (
+	legacyMonitoringMsg = "Legacy internal collection of monitoring data is deprecated, use spec.monitoring instead"
+)
+
+var warnings = []validation{
+	noLegacyMonitoringSettings,
+}
+
+func noLegacyMonitoringSettings(l *lsv1alpha1.Logstash) field.ErrorList {
+	return commonv1.CheckConfigSettings(field.NewPath("spec").Child("config"), l.Spec.Config, []string{
+		"xpack.monitoring.enabled",
+		"xpack.monitoring.elasticsearch.hosts",
+	}, legacyMonitoringMsg)
+}
+
+// Warnings returns the admission warnings raised by the Logstash specification.
+func Warnings(ls *lsv1alpha1.Logstash) []string {
+	return commonv1.ToWarnings(lsv1alpha1.Kind, ls, check(ls, warnings))
+}
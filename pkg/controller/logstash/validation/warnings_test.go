@@ -0,0 +1,45 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	lsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+)
+
+func TestWarnings(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *commonv1.Config
+		want   []string
+	}{
+		{
+			name: "no config",
+		},
+		{
+			name:   "monitoring settings",
+			config: &commonv1.Config{Data: map[string]interface{}{"monitoring.enabled": false}},
+		},
+		{
+			name:   "legacy monitoring settings",
+			config: &commonv1.Config{Data: map[string]interface{}{"xpack.monitoring.enabled": true}},
+			want:   []string{"Logstash test/ls: spec.config.xpack.monitoring.enabled: " + legacyMonitoringMsg},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ls := lsv1alpha1.Logstash{
+				ObjectMeta: metav1.ObjectMeta{Name: "ls", Namespace: "test"},
+				Spec:       lsv1alpha1.LogstashSpec{Config: tt.config},
+			}
+			assert.Equal(t, tt.want, Warnings(&ls))
+		})
+	}
+}
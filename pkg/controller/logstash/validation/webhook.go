@@ -17,6 +17,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	lsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+	commonwebhook "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/webhook"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
@@ -85,10 +86,12 @@ func (wh *validatingWebhook) Handle(ctx context.Context, req admission.Request)
 		return admission.Allowed("")
 	}
 
+	warnings := append(commonwebhook.GetWarnings(ls), Warnings(ls)...)
+
 	if req.Operation == admissionv1.Create {
 		err = wh.ValidateCreate(ls)
 		if err != nil {
-			return admission.Denied(err.Error())
+			return admission.Denied(err.Error()).WithWarnings(warnings...)
 		}
 	}
 
@@ -96,16 +99,16 @@ func (wh *validatingWebhook) Handle(ctx context.Context, req admission.Request)
 		oldObj := &lsv1alpha1.Logstash{}
 		err = wh.decoder.DecodeRaw(req.OldObject, oldObj)
 		if err != nil {
-			return admission.Errored(http.StatusBadRequest, err)
+			return admission.Errored(http.StatusBadRequest, err).WithWarnings(warnings...)
 		}
 
 		err = wh.ValidateUpdate(ctx, oldObj, ls)
 		if err != nil {
-			return admission.Denied(err.Error())
+			return admission.Denied(err.Error()).WithWarnings(warnings...)
 		}
 	}
 
-	return admission.Allowed("")
+	return admission.Allowed("").WithWarnings(warnings...)
 }
 
 // ValidateLogstash validates an Logstash instance against a set of validation funcs.
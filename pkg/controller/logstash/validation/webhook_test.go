@@ -116,9 +116,9 @@ func Test_webhook_Create(t *testing.T) {
 								},
 								Spec: v1alpha1.LogstashSpec{
 									Version: "8.12.0",
-									Monitoring: commonv1.Monitoring{
+									Monitoring: v1alpha1.LogstashMonitoring{Monitoring: commonv1.Monitoring{
 										Metrics: commonv1.MetricsMonitoring{ElasticsearchRefs: []commonv1.ObjectSelector{{Name: "esmonname", Namespace: "esmonns"}}},
-									},
+									}},
 								},
 							},
 						),
@@ -144,10 +144,10 @@ func Test_webhook_Create(t *testing.T) {
 								},
 								Spec: v1alpha1.LogstashSpec{
 									Version: "8.12.0",
-									Monitoring: commonv1.Monitoring{
+									Monitoring: v1alpha1.LogstashMonitoring{Monitoring: commonv1.Monitoring{
 										Metrics: commonv1.MetricsMonitoring{ElasticsearchRefs: []commonv1.ObjectSelector{{SecretName: "es1monname"}}},
 										Logs:    commonv1.LogsMonitoring{ElasticsearchRefs: []commonv1.ObjectSelector{{SecretName: "es2monname"}}},
-									},
+									}},
 								},
 							},
 						),
@@ -173,10 +173,10 @@ func Test_webhook_Create(t *testing.T) {
 								},
 								Spec: v1alpha1.LogstashSpec{
 									Version: "8.12.0",
-									Monitoring: commonv1.Monitoring{
+									Monitoring: v1alpha1.LogstashMonitoring{Monitoring: commonv1.Monitoring{
 										Metrics: commonv1.MetricsMonitoring{ElasticsearchRefs: []commonv1.ObjectSelector{{SecretName: "es1monname", Name: "xx"}}},
 										Logs:    commonv1.LogsMonitoring{ElasticsearchRefs: []commonv1.ObjectSelector{{SecretName: "es2monname"}}},
-									},
+									}},
 								},
 							},
 						),
@@ -202,10 +202,10 @@ func Test_webhook_Create(t *testing.T) {
 								},
 								Spec: v1alpha1.LogstashSpec{
 									Version: "8.12.0",
-									Monitoring: commonv1.Monitoring{
+									Monitoring: v1alpha1.LogstashMonitoring{Monitoring: commonv1.Monitoring{
 										Metrics: commonv1.MetricsMonitoring{ElasticsearchRefs: []commonv1.ObjectSelector{{SecretName: "es1monname"}}},
 										Logs:    commonv1.LogsMonitoring{ElasticsearchRefs: []commonv1.ObjectSelector{{SecretName: "es2monname", ServiceName: "xx"}}},
-									},
+									}},
 								},
 							},
 						),
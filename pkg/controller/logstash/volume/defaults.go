@@ -21,6 +21,14 @@ var (
 		ContainerMountPath:     ConfigMountPath,
 	}
 
+	// PluginsSharedVolume contains the copy of the Logstash home directory in which the plugins are installed by the
+	// init container, it replaces the Logstash home directory of the docker container
+	PluginsSharedVolume = volume.SharedVolume{
+		VolumeName:             PluginsVolumeName,
+		InitContainerMountPath: InitContainerPluginsVolumeMountPath,
+		ContainerMountPath:     LogstashHomePath,
+	}
+
 	DefaultPersistentVolumeSize = resource.MustParse("1.5Gi")
 
 	// DefaultDataVolumeClaim is the default data volume claim for Logstash pods.
@@ -21,4 +21,10 @@ const (
 	InternalConfigVolumeMountPath   = "/mnt/elastic-internal/logstash-config"
 	InternalPipelineVolumeName      = "elastic-internal-logstash-pipeline"
 	InternalPipelineVolumeMountPath = "/mnt/elastic-internal/logstash-pipeline"
+
+	// LogstashHomePath is the Logstash home directory in the Logstash image.
+	LogstashHomePath = "/usr/share/logstash"
+	// PluginsVolumeName is a volume which contains the copy of the Logstash home directory in which the plugins are installed.
+	PluginsVolumeName                   = "elastic-internal-logstash-plugins"
+	InitContainerPluginsVolumeMountPath = "/mnt/elastic-internal/logstash-home"
 )
@@ -99,7 +99,7 @@ func defaultConfig(ipFamily corev1.IPFamily) *settings.CanonicalConfig {
 }
 
 func tlsConfig(ems emsv1alpha1.ElasticMapsServer) *settings.CanonicalConfig {
-	if !ems.Spec.HTTP.TLS.Enabled() {
+	if !ems.Spec.HTTP.TLSEnabled() {
 		return settings.NewCanonicalConfig()
 	}
 	return settings.MustCanonicalConfig(map[string]interface{}{
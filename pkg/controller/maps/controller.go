@@ -32,7 +32,9 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/deployment"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/driver"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/effectivespec"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/gateway"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/license"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
@@ -42,6 +44,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
 )
 
 const (
@@ -163,7 +166,10 @@ func (r *ReconcileMapsServer) Reconcile(ctx context.Context, request reconcile.R
 
 	if common.IsUnmanaged(ctx, &ems) {
 		ulog.FromContext(ctx).Info("Object is currently not managed by this controller. Skipping reconciliation", "namespace", ems.Namespace, "maps_name", ems.Name)
-		return reconcile.Result{}, nil
+		return reconcile.Result{}, tracing.CaptureError(ctx, common.ReconcilePausedCondition(ctx, r.Client, &ems, &ems.Status.Conditions, true))
+	}
+	if err := common.ReconcilePausedCondition(ctx, r.Client, &ems, &ems.Status.Conditions, false); err != nil {
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
 	}
 
 	// MapsServer will be deleted nothing to do other than remove the watches
@@ -173,6 +179,7 @@ func (r *ReconcileMapsServer) Reconcile(ctx context.Context, request reconcile.R
 
 	// main reconciliation logic
 	results, status := r.doReconcile(ctx, ems)
+	common.UpdateStandardConditions(&status.Conditions, status.ResourceState(ems.Generation, ems.Spec.Version), results)
 	if err := r.updateStatus(ctx, ems, status); err != nil {
 		if apierrors.IsConflict(err) {
 			return results.WithResult(reconcile.Result{Requeue: true}).Aggregate()
@@ -217,12 +224,15 @@ func (r *ReconcileMapsServer) doReconcile(ctx context.Context, ems emsv1alpha1.E
 	if err != nil {
 		return results.WithError(err), status
 	}
+	if err := gateway.ReconcileRoute(ctx, r.Client, &ems, ems.Spec.HTTP, *svc, ems.GetIdentityLabels()); err != nil {
+		return results.WithError(err), status
+	}
 
 	_, results = certificates.Reconciler{
 		K8sClient:             r.K8sClient(),
 		DynamicWatches:        r.DynamicWatches(),
 		Owner:                 &ems,
-		TLSOptions:            ems.Spec.HTTP.TLS,
+		TLSOptions:            ems.Spec.HTTP.EffectiveTLS(),
 		Namer:                 EMSNamer,
 		Labels:                ems.GetIdentityLabels(),
 		Services:              []corev1.Service{*svc},
@@ -323,7 +333,7 @@ func buildConfigHash(c k8s.Client, ems emsv1alpha1.ElasticMapsServer, configSecr
 	_, _ = configHash.Write(configSecret.Data[ConfigFilename])
 
 	// - in the Elastic Maps Server TLS certificates
-	if ems.Spec.HTTP.TLS.Enabled() {
+	if ems.Spec.HTTP.TLSEnabled() {
 		var tlsCertSecret corev1.Secret
 		tlsSecretKey := types.NamespacedName{Namespace: ems.Namespace, Name: certificates.InternalCertsSecretName(EMSNamer, ems.Name)}
 		if err := c.Get(context.Background(), tlsSecretKey, &tlsCertSecret); err != nil {
@@ -355,6 +365,9 @@ func (r *ReconcileMapsServer) reconcileDeployment(
 		return appsv1.Deployment{}, err
 	}
 	deploy := deployment.New(deployParams)
+	if err := effectivespec.Reconcile(ctx, r.K8sClient(), &ems, EMSNamer.Suffix(ems.Name, effectivespec.Suffix), ems.Spec, &deploy); err != nil {
+		return appsv1.Deployment{}, err
+	}
 	return deployment.Reconcile(ctx, r.K8sClient(), deploy, &ems)
 }
 
@@ -415,6 +428,7 @@ func (r *ReconcileMapsServer) updateStatus(ctx context.Context, ems emsv1alpha1.
 }
 
 func (r *ReconcileMapsServer) onDelete(ctx context.Context, obj types.NamespacedName) error {
+	metrics.DeleteResourceMetrics(ctx, emsv1alpha1.Kind, obj)
 	// Clean up watches set on custom http tls certificates
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(certificates.CertificateWatchKey(EMSNamer, obj.Name))
 	// same for the configRef secret
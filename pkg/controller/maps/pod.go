@@ -72,12 +72,15 @@ func newPodSpec(ems emsv1alpha1.ElasticMapsServer, configHash string) (corev1.Po
 	if err != nil {
 		return corev1.PodTemplateSpec{}, err // error unlikely and should have been caught during validation
 	}
+	if err := container.CheckImageCatalog(ems.Spec.Image, container.MapsImage, v); err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
 
 	builder := defaults.NewPodTemplateBuilder(ems.Spec.PodTemplate, emsv1alpha1.MapsContainerName).
 		WithAnnotations(annotations).
 		WithResources(DefaultResources).
 		WithDockerImage(ems.Spec.Image, container.ImageRepository(container.MapsImage, v)).
-		WithReadinessProbe(readinessProbe(ems.Spec.HTTP.TLS.Enabled())).
+		WithReadinessProbe(readinessProbe(ems.Spec.HTTP.TLSEnabled())).
 		WithPorts(defaultContainerPorts).
 		WithVolumes(cfgVolume.Volume(), logsVolume.Volume()).
 		WithVolumeMounts(cfgVolume.VolumeMount(), logsVolume.VolumeMount()).
@@ -98,7 +101,7 @@ func newPodSpec(ems emsv1alpha1.ElasticMapsServer, configHash string) (corev1.Po
 		builder = builder.WithEnv(corev1.EnvVar{Name: "ELASTICSEARCH_PREVALIDATED", Value: "true"})
 	}
 
-	return builder.PodTemplate, nil
+	return builder.WithServiceMesh(ems.Spec.HTTP.ServiceMesh).WithPodMutationPolicy(emsv1alpha1.Kind, ems.Namespace).PodTemplate, nil
 }
 
 func withESCertsVolume(builder *defaults.PodTemplateBuilder, ems emsv1alpha1.ElasticMapsServer) (*defaults.PodTemplateBuilder, error) {
@@ -120,7 +123,7 @@ func withESCertsVolume(builder *defaults.PodTemplateBuilder, ems emsv1alpha1.Ela
 }
 
 func withHTTPCertsVolume(builder *defaults.PodTemplateBuilder, ems emsv1alpha1.ElasticMapsServer) *defaults.PodTemplateBuilder {
-	if !ems.Spec.HTTP.TLS.Enabled() {
+	if !ems.Spec.HTTP.TLSEnabled() {
 		return builder
 	}
 	vol := certificates.HTTPCertSecretVolume(EMSNamer, ems.Name)
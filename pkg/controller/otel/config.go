@@ -0,0 +1,242 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package otel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strconv"
+
+	"dario.cat/mergo"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	otelv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/otel/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/apmserver"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/association"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	commonlabels "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/labels"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	utilsnet "github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+const (
+	ESCertsPath     = "/mnt/elastic-internal/es-certs"
+	ApmCertsPath    = "/mnt/elastic-internal/apm-certs"
+	ConfigFilename  = "config.yaml"
+	ConfigMountPath = "/etc/otel"
+
+	// names of the components configured by the operator
+	otlpReceiver          = "otlp"
+	batchProcessor        = "batch"
+	healthCheckExtension  = "health_check"
+	elasticsearchExporter = "elasticsearch"
+	apmExporter           = "otlp/apm"
+)
+
+func configSecretVolume(collector otelv1alpha1.OpenTelemetryCollector) volume.SecretVolume {
+	return volume.NewSecretVolumeWithMountPath(Config(collector.Name), "config", ConfigMountPath)
+}
+
+func reconcileConfig(ctx context.Context, c k8s.Client, collector otelv1alpha1.OpenTelemetryCollector, ipFamily corev1.IPFamily) (corev1.Secret, error) {
+	cfg, err := newConfig(ctx, c, collector, ipFamily)
+	if err != nil {
+		return corev1.Secret{}, err
+	}
+
+	cfgBytes, err := yaml.Marshal(cfg)
+	if err != nil {
+		return corev1.Secret{}, err
+	}
+
+	// Reconcile the configuration in a secret, as it contains the credentials of the associations
+	expectedConfigSecret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: collector.Namespace,
+			Name:      Config(collector.Name),
+			Labels:    commonlabels.AddCredentialsLabel(collector.GetIdentityLabels()),
+		},
+		Data: map[string][]byte{
+			ConfigFilename: cfgBytes,
+		},
+	}
+
+	return reconciler.ReconcileSecret(ctx, c, expectedConfigSecret, &collector)
+}
+
+// newConfig returns the configuration of the collector: the default configuration, the exporters to the referenced
+// Elasticsearch cluster and APM Server, and the user configuration which is merged last and takes precedence.
+// Unlike the configuration of the other Elastic Stack applications, maps are merged recursively but lists and dotted
+// keys are kept as is, as they are common in the OpenTelemetry collector configuration.
+func newConfig(ctx context.Context, c k8s.Client, collector otelv1alpha1.OpenTelemetryCollector, ipFamily corev1.IPFamily) (map[string]interface{}, error) {
+	cfg := defaultConfig(ipFamily)
+
+	assocCfg, err := associationsConfig(ctx, c, collector)
+	if err != nil {
+		return nil, err
+	}
+	if err := mergo.Merge(&cfg, assocCfg, mergo.WithOverride); err != nil {
+		return nil, err
+	}
+
+	if collector.Spec.Config != nil {
+		// the user configuration is deep copied so the merge does not alter the spec
+		userCfg := collector.Spec.Config.DeepCopy().Data
+		if err := mergo.Merge(&cfg, userCfg, mergo.WithOverride); err != nil {
+			return nil, err
+		}
+	}
+	return cfg, nil
+}
+
+func endpoint(ipFamily corev1.IPFamily, port int) string {
+	return net.JoinHostPort(utilsnet.InAddrAnyFor(ipFamily).String(), strconv.Itoa(port))
+}
+
+func defaultConfig(ipFamily corev1.IPFamily) map[string]interface{} {
+	return map[string]interface{}{
+		"receivers": map[string]interface{}{
+			otlpReceiver: map[string]interface{}{
+				"protocols": map[string]interface{}{
+					"grpc": map[string]interface{}{"endpoint": endpoint(ipFamily, GRPCPort)},
+					"http": map[string]interface{}{"endpoint": endpoint(ipFamily, HTTPPort)},
+				},
+			},
+		},
+		"processors": map[string]interface{}{
+			batchProcessor: map[string]interface{}{},
+		},
+		"extensions": map[string]interface{}{
+			healthCheckExtension: map[string]interface{}{"endpoint": endpoint(ipFamily, HealthCheckPort)},
+		},
+		"service": map[string]interface{}{
+			"extensions": []interface{}{healthCheckExtension},
+		},
+	}
+}
+
+// associationsConfig returns the exporters to the referenced Elasticsearch cluster and APM Server, and the pipelines
+// sending the OTLP data to them. Traces are sent to the APM Server if any, logs and metrics to Elasticsearch if any.
+func associationsConfig(ctx context.Context, c k8s.Client, collector otelv1alpha1.OpenTelemetryCollector) (map[string]interface{}, error) {
+	exporters := map[string]interface{}{}
+	for _, assoc := range collector.GetAssociations() {
+		assocConf, err := assoc.AssociationConf()
+		if err != nil {
+			return nil, err
+		}
+		if !assocConf.IsConfigured() {
+			continue
+		}
+		switch assoc.AssociationType() {
+		case commonv1.ElasticsearchAssociationType:
+			exporter, err := elasticsearchExporterConfig(ctx, c, assoc, assocConf)
+			if err != nil {
+				return nil, err
+			}
+			exporters[elasticsearchExporter] = exporter
+		case commonv1.ApmServerAssociationType:
+			exporter, err := apmExporterConfig(ctx, c, assoc, assocConf)
+			if err != nil {
+				return nil, err
+			}
+			exporters[apmExporter] = exporter
+		}
+	}
+	if len(exporters) == 0 {
+		return map[string]interface{}{}, nil
+	}
+
+	pipeline := func(preferred, fallback string) map[string]interface{} {
+		exporter := preferred
+		if _, exists := exporters[preferred]; !exists {
+			exporter = fallback
+		}
+		return map[string]interface{}{
+			"receivers":  []interface{}{otlpReceiver},
+			"processors": []interface{}{batchProcessor},
+			"exporters":  []interface{}{exporter},
+		}
+	}
+	return map[string]interface{}{
+		"exporters": exporters,
+		"service": map[string]interface{}{
+			"pipelines": map[string]interface{}{
+				"traces":  pipeline(apmExporter, elasticsearchExporter),
+				"metrics": pipeline(elasticsearchExporter, apmExporter),
+				"logs":    pipeline(elasticsearchExporter, apmExporter),
+			},
+		},
+	}, nil
+}
+
+func elasticsearchExporterConfig(ctx context.Context, c k8s.Client, assoc commonv1.Association, assocConf *commonv1.AssociationConf) (map[string]interface{}, error) {
+	credentials, err := association.ElasticsearchAuthSettings(ctx, c, assoc)
+	if err != nil {
+		return nil, err
+	}
+	exporter := map[string]interface{}{
+		"endpoints": []interface{}{assocConf.GetURL()},
+	}
+	if credentials.APIKey != "" {
+		exporter["api_key"] = credentials.APIKey
+	} else {
+		exporter["user"] = credentials.Username
+		exporter["password"] = credentials.Password
+	}
+	if assocConf.GetCACertProvided() {
+		exporter["tls"] = map[string]interface{}{"ca_file": filepath.Join(ESCertsPath, certificates.CAFileName)}
+	}
+	return exporter, nil
+}
+
+func apmExporterConfig(ctx context.Context, c k8s.Client, assoc commonv1.Association, assocConf *commonv1.AssociationConf) (map[string]interface{}, error) {
+	authorization, err := apmAuthorizationHeader(ctx, c, assoc, assocConf)
+	if err != nil {
+		return nil, err
+	}
+	exporter := map[string]interface{}{
+		"endpoint": assocConf.GetURL(),
+		"headers":  map[string]interface{}{"Authorization": authorization},
+	}
+	if assocConf.GetCACertProvided() {
+		exporter["tls"] = map[string]interface{}{"ca_file": filepath.Join(ApmCertsPath, certificates.CAFileName)}
+	}
+	return exporter, nil
+}
+
+// apmAuthorizationHeader returns the value of the Authorization header used to send data to the APM Server: the secret
+// token of APM Servers managed by ECK, copied in the namespace of the collector by the association controller, or the
+// API key of external APM Servers.
+func apmAuthorizationHeader(ctx context.Context, c k8s.Client, assoc commonv1.Association, assocConf *commonv1.AssociationConf) (string, error) {
+	if !assocConf.NoAuthRequired() {
+		credentials, err := association.ElasticsearchAuthSettings(ctx, c, assoc)
+		if err != nil {
+			return "", err
+		}
+		if credentials.APIKey == "" {
+			return "", errors.New("an API key is required to authenticate to an APM Server not managed by ECK")
+		}
+		return "ApiKey " + credentials.APIKey, nil
+	}
+
+	var secret corev1.Secret
+	key := types.NamespacedName{Namespace: assoc.GetNamespace(), Name: apmserver.SecretToken(assoc.AssociationRef().Name)}
+	if err := c.Get(ctx, key, &secret); err != nil {
+		return "", err
+	}
+	token, exists := secret.Data[apmserver.SecretTokenKey]
+	if !exists {
+		return "", fmt.Errorf("no %s in secret %s", apmserver.SecretTokenKey, key)
+	}
+	return "Bearer " + string(token), nil
+}
@@ -0,0 +1,325 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package otel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	otelv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/otel/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+var (
+	esAssocConf = commonv1.AssociationConf{
+		AuthSecretName: "otel-otel-user",
+		AuthSecretKey:  "ns-otel-otel-user",
+		CACertProvided: true,
+		CASecretName:   "otel-otel-es-ca",
+		URL:            "https://es-es-http.ns.svc:9200",
+	}
+	apmAssocConf = commonv1.AssociationConf{
+		AuthSecretName: commonv1.NoAuthRequiredValue,
+		CACertProvided: true,
+		CASecretName:   "otel-otel-apm-ca",
+		URL:            "https://apm-apm-http.ns.svc:8200",
+	}
+	esUserSecret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "otel-otel-user"},
+		Data:       map[string][]byte{"ns-otel-otel-user": []byte("password")},
+	}
+	apmTokenSecret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "apm-apm-token"},
+		Data:       map[string][]byte{"secret-token": []byte("token")},
+	}
+)
+
+// collectorWithAssociations returns a collector referencing an Elasticsearch cluster and an APM Server if their
+// association conf is given.
+func collectorWithAssociations(esConf, apmConf *commonv1.AssociationConf, cfg *commonv1.Config) otelv1alpha1.OpenTelemetryCollector {
+	collector := otelv1alpha1.OpenTelemetryCollector{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "otel"},
+		Spec:       otelv1alpha1.CollectorSpec{Version: "8.17.0", Config: cfg},
+	}
+	if esConf != nil {
+		collector.Spec.ElasticsearchRef = commonv1.ObjectSelector{Name: "es"}
+		otelv1alpha1.NewCollectorEsAssociation(&collector).SetAssociationConf(esConf)
+	}
+	if apmConf != nil {
+		collector.Spec.ApmServerRef = commonv1.ObjectSelector{Name: "apm"}
+		otelv1alpha1.NewCollectorApmAssociation(&collector).SetAssociationConf(apmConf)
+	}
+	return collector
+}
+
+func Test_newConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		runtimeObjs []client.Object
+		collector   otelv1alpha1.OpenTelemetryCollector
+		ipFamily    corev1.IPFamily
+		want        string
+		wantErr     bool
+	}{
+		{
+			name:      "no association",
+			collector: collectorWithAssociations(nil, nil, nil),
+			ipFamily:  corev1.IPv4Protocol,
+			want: `extensions:
+  health_check:
+    endpoint: 0.0.0.0:13133
+processors:
+  batch: {}
+receivers:
+  otlp:
+    protocols:
+      grpc:
+        endpoint: 0.0.0.0:4317
+      http:
+        endpoint: 0.0.0.0:4318
+service:
+  extensions:
+  - health_check
+`,
+		},
+		{
+			name:        "Elasticsearch and APM Server",
+			runtimeObjs: []client.Object{esUserSecret, apmTokenSecret},
+			collector:   collectorWithAssociations(&esAssocConf, &apmAssocConf, nil),
+			ipFamily:    corev1.IPv6Protocol,
+			want: `exporters:
+  elasticsearch:
+    endpoints:
+    - https://es-es-http.ns.svc:9200
+    password: password
+    tls:
+      ca_file: /mnt/elastic-internal/es-certs/ca.crt
+    user: ns-otel-otel-user
+  otlp/apm:
+    endpoint: https://apm-apm-http.ns.svc:8200
+    headers:
+      Authorization: Bearer token
+    tls:
+      ca_file: /mnt/elastic-internal/apm-certs/ca.crt
+extensions:
+  health_check:
+    endpoint: '[::]:13133'
+processors:
+  batch: {}
+receivers:
+  otlp:
+    protocols:
+      grpc:
+        endpoint: '[::]:4317'
+      http:
+        endpoint: '[::]:4318'
+service:
+  extensions:
+  - health_check
+  pipelines:
+    logs:
+      exporters:
+      - elasticsearch
+      processors:
+      - batch
+      receivers:
+      - otlp
+    metrics:
+      exporters:
+      - elasticsearch
+      processors:
+      - batch
+      receivers:
+      - otlp
+    traces:
+      exporters:
+      - otlp/apm
+      processors:
+      - batch
+      receivers:
+      - otlp
+`,
+		},
+		{
+			name:        "APM Server only",
+			runtimeObjs: []client.Object{apmTokenSecret},
+			collector: collectorWithAssociations(nil, &commonv1.AssociationConf{
+				AuthSecretName: commonv1.NoAuthRequiredValue,
+				CASecretName:   "otel-otel-apm-ca",
+				URL:            "http://apm-apm-http.ns.svc:8200",
+			}, nil),
+			ipFamily: corev1.IPv4Protocol,
+			want: `exporters:
+  otlp/apm:
+    endpoint: http://apm-apm-http.ns.svc:8200
+    headers:
+      Authorization: Bearer token
+extensions:
+  health_check:
+    endpoint: 0.0.0.0:13133
+processors:
+  batch: {}
+receivers:
+  otlp:
+    protocols:
+      grpc:
+        endpoint: 0.0.0.0:4317
+      http:
+        endpoint: 0.0.0.0:4318
+service:
+  extensions:
+  - health_check
+  pipelines:
+    logs:
+      exporters:
+      - otlp/apm
+      processors:
+      - batch
+      receivers:
+      - otlp
+    metrics:
+      exporters:
+      - otlp/apm
+      processors:
+      - batch
+      receivers:
+      - otlp
+    traces:
+      exporters:
+      - otlp/apm
+      processors:
+      - batch
+      receivers:
+      - otlp
+`,
+		},
+		{
+			name:        "user config takes precedence",
+			runtimeObjs: []client.Object{esUserSecret},
+			collector: collectorWithAssociations(&esAssocConf, nil, &commonv1.Config{Data: map[string]interface{}{
+				"exporters": map[string]interface{}{
+					"elasticsearch": map[string]interface{}{"mapping": map[string]interface{}{"mode": "otel"}},
+				},
+				"processors": map[string]interface{}{
+					"resource": map[string]interface{}{
+						"attributes": []interface{}{map[string]interface{}{"key": "k8s.cluster.name", "value": "prod", "action": "upsert"}},
+					},
+				},
+				"service": map[string]interface{}{
+					"pipelines": map[string]interface{}{
+						"logs": map[string]interface{}{"processors": []interface{}{"resource", "batch"}},
+					},
+				},
+			}}),
+			ipFamily: corev1.IPv4Protocol,
+			want: `exporters:
+  elasticsearch:
+    endpoints:
+    - https://es-es-http.ns.svc:9200
+    mapping:
+      mode: otel
+    password: password
+    tls:
+      ca_file: /mnt/elastic-internal/es-certs/ca.crt
+    user: ns-otel-otel-user
+extensions:
+  health_check:
+    endpoint: 0.0.0.0:13133
+processors:
+  batch: {}
+  resource:
+    attributes:
+    - action: upsert
+      key: k8s.cluster.name
+      value: prod
+receivers:
+  otlp:
+    protocols:
+      grpc:
+        endpoint: 0.0.0.0:4317
+      http:
+        endpoint: 0.0.0.0:4318
+service:
+  extensions:
+  - health_check
+  pipelines:
+    logs:
+      exporters:
+      - elasticsearch
+      processors:
+      - resource
+      - batch
+      receivers:
+      - otlp
+    metrics:
+      exporters:
+      - elasticsearch
+      processors:
+      - batch
+      receivers:
+      - otlp
+    traces:
+      exporters:
+      - elasticsearch
+      processors:
+      - batch
+      receivers:
+      - otlp
+`,
+		},
+		{
+			name:      "missing APM Server secret token",
+			collector: collectorWithAssociations(nil, &apmAssocConf, nil),
+			ipFamily:  corev1.IPv4Protocol,
+			wantErr:   true,
+		},
+		{
+			name: "external APM Server without API key",
+			runtimeObjs: []client.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "external-apm"},
+				Data:       map[string][]byte{"password": []byte("password")},
+			}},
+			collector: collectorWithAssociations(nil, &commonv1.AssociationConf{
+				AuthSecretName: "external-apm",
+				AuthSecretKey:  "password",
+				URL:            "https://apm.example.com:443",
+			}, nil),
+			ipFamily: corev1.IPv4Protocol,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newConfig(context.Background(), k8s.NewFakeClient(tt.runtimeObjs...), tt.collector, tt.ipFamily)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			rendered, err := yaml.Marshal(got)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, string(rendered))
+		})
+	}
+}
+
+func Test_newConfig_doesNotAlterSpec(t *testing.T) {
+	userCfg := &commonv1.Config{Data: map[string]interface{}{
+		"processors": map[string]interface{}{"memory_limiter": map[string]interface{}{"limit_percentage": 80}},
+	}}
+	collector := collectorWithAssociations(nil, nil, userCfg)
+	_, err := newConfig(context.Background(), k8s.NewFakeClient(), collector, corev1.IPv4Protocol)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"processors": map[string]interface{}{"memory_limiter": map[string]interface{}{"limit_percentage": 80}},
+	}, collector.Spec.Config.Data)
+}
@@ -0,0 +1,356 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package otel
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"sync/atomic"
+
+	"go.elastic.co/apm/v2"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	otelv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/otel/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/association"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
+	commonassociation "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/association"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/deployment"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/effectivespec"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
+)
+
+const (
+	controllerName = "otel-controller"
+)
+
+// Add creates a new OpenTelemetryCollector Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, params operator.Parameters) error {
+	reconciler := newReconciler(mgr, params)
+	c, err := common.NewController(mgr, controllerName, reconciler, params)
+	if err != nil {
+		return err
+	}
+	return addWatches(mgr, c, reconciler)
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, params operator.Parameters) *ReconcileOpenTelemetryCollector {
+	client := mgr.GetClient()
+	return &ReconcileOpenTelemetryCollector{
+		Client:     client,
+		recorder:   mgr.GetEventRecorderFor(controllerName),
+		Parameters: params,
+	}
+}
+
+func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileOpenTelemetryCollector) error {
+	// Watch for changes to OpenTelemetryCollector
+	if err := c.Watch(source.Kind(mgr.GetCache(), &otelv1alpha1.OpenTelemetryCollector{}, &handler.TypedEnqueueRequestForObject[*otelv1alpha1.OpenTelemetryCollector]{})); err != nil {
+		return err
+	}
+
+	// Watch deployments
+	if err := c.Watch(source.Kind(mgr.GetCache(), &appsv1.Deployment{}, handler.TypedEnqueueRequestForOwner[*appsv1.Deployment](
+		mgr.GetScheme(), mgr.GetRESTMapper(),
+		&otelv1alpha1.OpenTelemetryCollector{}, handler.OnlyControllerOwner(),
+	))); err != nil {
+		return err
+	}
+
+	// Watch Pods, to ensure `status.version` and version upgrades are correctly reconciled on any change.
+	// Watching Deployments only may lead to missing some events.
+	if err := watches.WatchPods(mgr, c, otelv1alpha1.NameLabelName); err != nil {
+		return err
+	}
+
+	// Watch services
+	if err := c.Watch(source.Kind(mgr.GetCache(), &corev1.Service{}, handler.TypedEnqueueRequestForOwner[*corev1.Service](
+		mgr.GetScheme(), mgr.GetRESTMapper(),
+		&otelv1alpha1.OpenTelemetryCollector{}, handler.OnlyControllerOwner(),
+	))); err != nil {
+		return err
+	}
+
+	// Watch owned and soft-owned secrets
+	if err := c.Watch(source.Kind(mgr.GetCache(), &corev1.Secret{}, handler.TypedEnqueueRequestForOwner[*corev1.Secret](
+		mgr.GetScheme(), mgr.GetRESTMapper(),
+		&otelv1alpha1.OpenTelemetryCollector{}, handler.OnlyControllerOwner(),
+	))); err != nil {
+		return err
+	}
+	return watches.WatchSoftOwnedSecrets(mgr, c, otelv1alpha1.Kind)
+}
+
+var _ reconcile.Reconciler = &ReconcileOpenTelemetryCollector{}
+
+// ReconcileOpenTelemetryCollector reconciles an OpenTelemetryCollector object
+type ReconcileOpenTelemetryCollector struct {
+	k8s.Client
+	operator.Parameters
+	recorder record.EventRecorder
+	// iteration is the number of times this controller has run its Reconcile method
+	iteration uint64
+}
+
+// Reconcile reads that state of the cluster for an OpenTelemetryCollector object and makes changes based on the state read and what is
+// in the OpenTelemetryCollector.Spec
+func (r *ReconcileOpenTelemetryCollector) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	ctx = common.NewReconciliationContext(ctx, &r.iteration, r.Tracer, controllerName, "otel_name", request)
+	defer common.LogReconciliationRun(ulog.FromContext(ctx))()
+	defer tracing.EndContextTransaction(ctx)
+
+	var collector otelv1alpha1.OpenTelemetryCollector
+	if err := r.Client.Get(ctx, request.NamespacedName, &collector); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, r.onDelete(ctx, request.NamespacedName)
+		}
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
+	}
+
+	if common.IsUnmanaged(ctx, &collector) {
+		ulog.FromContext(ctx).Info("Object is currently not managed by this controller. Skipping reconciliation", "namespace", collector.Namespace, "otel_name", collector.Name)
+		return reconcile.Result{}, tracing.CaptureError(ctx, common.ReconcilePausedCondition(ctx, r.Client, &collector, &collector.Status.Conditions, true))
+	}
+	if err := common.ReconcilePausedCondition(ctx, r.Client, &collector, &collector.Status.Conditions, false); err != nil {
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
+	}
+
+	// the collector will be deleted, nothing to do other than cleaning up
+	if collector.IsMarkedForDeletion() {
+		return reconcile.Result{}, r.onDelete(ctx, k8s.ExtractNamespacedName(&collector))
+	}
+
+	// main reconciliation logic
+	results, status := r.doReconcile(ctx, collector)
+	if err := r.updateStatus(ctx, collector, status); err != nil {
+		if apierrors.IsConflict(err) {
+			return results.WithResult(reconcile.Result{Requeue: true}).Aggregate()
+		}
+		results.WithError(err)
+	}
+	return results.Aggregate()
+}
+
+func (r *ReconcileOpenTelemetryCollector) doReconcile(ctx context.Context, collector otelv1alpha1.OpenTelemetryCollector) (*reconciler.Results, otelv1alpha1.CollectorStatus) {
+	log := ulog.FromContext(ctx)
+	results := reconciler.NewResult(ctx)
+	status := newStatus(collector)
+
+	areAssocsConfigured, err := association.AreConfiguredIfSet(ctx, collector.GetAssociations(), r.recorder)
+	if err != nil {
+		return results.WithError(err), status
+	}
+	if !areAssocsConfigured {
+		return results, status
+	}
+
+	// Run validation in case the webhook is disabled
+	if err := r.validate(ctx, collector); err != nil {
+		return results.WithError(err), status
+	}
+
+	if _, err := common.ReconcileService(ctx, r.Client, NewService(collector), &collector); err != nil {
+		return results.WithError(err), status
+	}
+
+	collectorVersion, err := version.Parse(collector.Spec.Version)
+	if err != nil {
+		return results.WithError(err), status
+	}
+	assocAllowed, err := association.AllowVersion(collectorVersion, &collector, log, r.recorder)
+	if err != nil {
+		return results.WithError(err), status
+	}
+	if !assocAllowed {
+		// will eventually retry once the referenced resources are updated
+		return results, status
+	}
+
+	configSecret, err := reconcileConfig(ctx, r.Client, collector, r.IPFamily)
+	if err != nil {
+		return results.WithError(err), status
+	}
+
+	// build a hash of various inputs to rotate Pods on any change
+	configHash, err := buildConfigHash(r.Client, collector, configSecret)
+	if err != nil {
+		return results.WithError(fmt.Errorf("build config hash: %w", err)), status
+	}
+
+	deploy, err := r.reconcileDeployment(ctx, collector, configHash)
+	if err != nil {
+		return results.WithError(fmt.Errorf("reconcile deployment: %w", err)), status
+	}
+
+	status, err = r.getStatus(ctx, collector, deploy)
+	if err != nil {
+		return results.WithError(fmt.Errorf("calculating status: %w", err)), status
+	}
+
+	return results, status
+}
+
+func newStatus(collector otelv1alpha1.OpenTelemetryCollector) otelv1alpha1.CollectorStatus {
+	status := collector.Status
+	status.ObservedGeneration = collector.Generation
+	return status
+}
+
+func (r *ReconcileOpenTelemetryCollector) validate(ctx context.Context, collector otelv1alpha1.OpenTelemetryCollector) error {
+	span, vctx := apm.StartSpan(ctx, "validate", tracing.SpanTypeApp)
+	defer span.End()
+
+	if _, err := collector.ValidateCreate(); err != nil {
+		ulog.FromContext(ctx).Error(err, "Validation failed")
+		k8s.MaybeEmitErrorEvent(r.recorder, err, &collector, events.EventReasonValidation, err.Error())
+		return tracing.CaptureError(vctx, err)
+	}
+
+	return nil
+}
+
+// NewService returns the Service exposing the OTLP receivers of the collector.
+func NewService(collector otelv1alpha1.OpenTelemetryCollector) *corev1.Service {
+	svc := corev1.Service{
+		ObjectMeta: collector.Spec.Service.ObjectMeta,
+		Spec:       collector.Spec.Service.Spec,
+	}
+
+	svc.ObjectMeta.Namespace = collector.Namespace
+	svc.ObjectMeta.Name = Service(collector.Name)
+
+	labels := collector.GetIdentityLabels()
+	ports := []corev1.ServicePort{
+		{
+			Name:     "otlp-grpc",
+			Protocol: corev1.ProtocolTCP,
+			Port:     GRPCPort,
+		},
+		{
+			Name:     "otlp-http",
+			Protocol: corev1.ProtocolTCP,
+			Port:     HTTPPort,
+		},
+	}
+
+	return defaults.SetServiceDefaults(&svc, labels, labels, ports)
+}
+
+func buildConfigHash(c k8s.Client, collector otelv1alpha1.OpenTelemetryCollector, configSecret corev1.Secret) (string, error) {
+	// build a hash of various settings to rotate the Pod on any change
+	configHash := fnv.New32a()
+
+	// - in the collector configuration file content
+	_, _ = configHash.Write(configSecret.Data[ConfigFilename])
+
+	// - in the associated Elasticsearch and APM Server TLS certificates
+	if err := commonassociation.WriteAssocsToConfigHash(c, collector.GetAssociations(), configHash); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprint(configHash.Sum32()), nil
+}
+
+func (r *ReconcileOpenTelemetryCollector) reconcileDeployment(
+	ctx context.Context,
+	collector otelv1alpha1.OpenTelemetryCollector,
+	configHash string,
+) (appsv1.Deployment, error) {
+	span, _ := apm.StartSpan(ctx, "reconcile_deployment", tracing.SpanTypeApp)
+	defer span.End()
+
+	deployParams, err := deploymentParams(collector, configHash)
+	if err != nil {
+		return appsv1.Deployment{}, err
+	}
+	deploy := deployment.New(deployParams)
+	if err := effectivespec.Reconcile(ctx, r.Client, &collector, Namer.Suffix(collector.Name, effectivespec.Suffix), collector.Spec, &deploy); err != nil {
+		return appsv1.Deployment{}, err
+	}
+	return deployment.Reconcile(ctx, r.Client, deploy, &collector)
+}
+
+func deploymentParams(collector otelv1alpha1.OpenTelemetryCollector, configHash string) (deployment.Params, error) {
+	podSpec, err := newPodSpec(collector, configHash)
+	if err != nil {
+		return deployment.Params{}, err
+	}
+
+	deploymentLabels := collector.GetIdentityLabels()
+
+	podLabels := maps.Merge(collector.GetIdentityLabels(), versionLabels(collector))
+	// merge with user-provided labels
+	podSpec.Labels = maps.MergePreservingExistingKeys(podSpec.Labels, podLabels)
+
+	return deployment.Params{
+		Name:                 Deployment(collector.Name),
+		Namespace:            collector.Namespace,
+		Replicas:             collector.Spec.Count,
+		Selector:             deploymentLabels,
+		Labels:               deploymentLabels,
+		PodTemplateSpec:      podSpec,
+		RevisionHistoryLimit: collector.Spec.RevisionHistoryLimit,
+		Strategy:             appsv1.DeploymentStrategy{Type: appsv1.RollingUpdateDeploymentStrategyType},
+	}, nil
+}
+
+func (r *ReconcileOpenTelemetryCollector) getStatus(ctx context.Context, collector otelv1alpha1.OpenTelemetryCollector, deploy appsv1.Deployment) (otelv1alpha1.CollectorStatus, error) {
+	status := newStatus(collector)
+	pods, err := k8s.PodsMatchingLabels(r.Client, collector.Namespace, map[string]string{otelv1alpha1.NameLabelName: collector.Name})
+	if err != nil {
+		return status, err
+	}
+	deploymentStatus, err := common.DeploymentStatus(ctx, collector.Status.DeploymentStatus, deploy, pods, versionLabelName)
+	if err != nil {
+		return status, err
+	}
+	status.DeploymentStatus = deploymentStatus
+
+	return status, nil
+}
+
+func (r *ReconcileOpenTelemetryCollector) updateStatus(ctx context.Context, collector otelv1alpha1.OpenTelemetryCollector, status otelv1alpha1.CollectorStatus) error {
+	if reflect.DeepEqual(status, collector.Status) {
+		return nil // nothing to do
+	}
+	if status.IsDegraded(collector.Status.DeploymentStatus) {
+		r.recorder.Event(&collector, corev1.EventTypeWarning, events.EventReasonUnhealthy, "OpenTelemetry collector health degraded")
+	}
+	ulog.FromContext(ctx).V(1).Info("Updating status",
+		"iteration", atomic.LoadUint64(&r.iteration),
+		"namespace", collector.Namespace,
+		"otel_name", collector.Name,
+		"status", status,
+	)
+	collector.Status = status
+	return common.UpdateStatus(ctx, r.Client, &collector)
+}
+
+func (r *ReconcileOpenTelemetryCollector) onDelete(ctx context.Context, obj types.NamespacedName) error {
+	metrics.DeleteResourceMetrics(ctx, otelv1alpha1.Kind, obj)
+	return reconciler.GarbageCollectSoftOwnedSecrets(ctx, r.Client, obj, otelv1alpha1.Kind)
+}
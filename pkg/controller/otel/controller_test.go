@@ -0,0 +1,90 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package otel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	otelv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/otel/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func TestReconcileOpenTelemetryCollector_Reconcile(t *testing.T) {
+	collector := otelv1alpha1.OpenTelemetryCollector{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "otel", Generation: 2},
+		Spec: otelv1alpha1.CollectorSpec{
+			Version:              "8.17.0",
+			Count:                2,
+			RevisionHistoryLimit: ptr.To[int32](3),
+		},
+	}
+	r := ReconcileOpenTelemetryCollector{
+		Client:   k8s.NewFakeClient(&collector),
+		recorder: record.NewFakeRecorder(10),
+	}
+	nsn := types.NamespacedName{Namespace: "ns", Name: "otel"}
+	_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: nsn})
+	require.NoError(t, err)
+
+	var svc corev1.Service
+	require.NoError(t, r.Client.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "otel-otel-otlp"}, &svc))
+	require.Len(t, svc.Spec.Ports, 2)
+
+	var cfg corev1.Secret
+	require.NoError(t, r.Client.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "otel-otel-config"}, &cfg))
+	require.Contains(t, cfg.Data, ConfigFilename)
+
+	var deploy appsv1.Deployment
+	require.NoError(t, r.Client.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "otel-otel"}, &deploy))
+	require.Equal(t, int32(2), *deploy.Spec.Replicas)
+	require.Equal(t, int32(3), *deploy.Spec.RevisionHistoryLimit)
+	require.Equal(t, "8.17.0", deploy.Spec.Template.Labels[versionLabelName])
+
+	var updated otelv1alpha1.OpenTelemetryCollector
+	require.NoError(t, r.Client.Get(context.Background(), nsn, &updated))
+	require.Equal(t, int64(2), updated.Status.ObservedGeneration)
+}
+
+func Test_newPodSpec(t *testing.T) {
+	collector := collectorWithAssociations(&esAssocConf, &apmAssocConf, nil)
+	podSpec, err := newPodSpec(collector, "1234")
+	require.NoError(t, err)
+
+	require.Equal(t, "1234", podSpec.Annotations[configHashAnnotationName])
+	require.Len(t, podSpec.Spec.Containers, 1)
+	c := podSpec.Spec.Containers[0]
+	require.Equal(t, "docker.elastic.co/beats/elastic-agent:8.17.0", c.Image)
+	require.Equal(t, []string{collectorBinary}, c.Command)
+	require.Equal(t, []string{"otel", "--config=/etc/otel/config.yaml"}, c.Args)
+
+	volumes := map[string]string{}
+	for _, v := range podSpec.Spec.Volumes {
+		volumes[v.Name] = v.Secret.SecretName
+	}
+	require.Equal(t, map[string]string{
+		"config":    "otel-otel-config",
+		"es-certs":  "otel-otel-es-ca",
+		"apm-certs": "otel-otel-apm-ca",
+	}, volumes)
+
+	// no CA to mount without association
+	podSpec, err = newPodSpec(collectorWithAssociations(nil, &commonv1.AssociationConf{
+		AuthSecretName: commonv1.NoAuthRequiredValue,
+		URL:            "http://apm-apm-http.ns.svc:8200",
+	}, nil), "1234")
+	require.NoError(t, err)
+	require.Len(t, podSpec.Spec.Volumes, 1)
+}
@@ -0,0 +1,20 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package otel
+
+import (
+	otelv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/otel/v1alpha1"
+)
+
+const (
+	// versionLabelName used to propagate the collector version from the spec to the pods
+	versionLabelName = "otel.k8s.elastic.co/version"
+)
+
+func versionLabels(collector otelv1alpha1.OpenTelemetryCollector) map[string]string {
+	return map[string]string{
+		versionLabelName: collector.Spec.Version,
+	}
+}
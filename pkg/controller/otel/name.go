@@ -0,0 +1,27 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package otel
+
+import "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/name"
+
+const (
+	serviceSuffix = "otlp"
+	configSuffix  = "config"
+)
+
+// Namer is a Namer that is configured with the defaults for resources related to an OpenTelemetry collector resource.
+var Namer = name.NewNamer("otel")
+
+func Service(collectorName string) string {
+	return Namer.Suffix(collectorName, serviceSuffix)
+}
+
+func Deployment(collectorName string) string {
+	return Namer.Suffix(collectorName)
+}
+
+func Config(collectorName string) string {
+	return Namer.Suffix(collectorName, configSuffix)
+}
@@ -0,0 +1,132 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package otel
+
+import (
+	"path/filepath"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	otelv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/otel/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/association"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/container"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
+)
+
+const (
+	GRPCPort                 = 4317
+	HTTPPort                 = 4318
+	HealthCheckPort          = 13133
+	configHashAnnotationName = "otel.k8s.elastic.co/config-hash"
+	// collectorBinary runs the EDOT collector with the otel sub-command
+	collectorBinary = "/usr/share/elastic-agent/elastic-agent"
+)
+
+var (
+	DefaultMemoryLimits = resource.MustParse("512Mi")
+	DefaultResources    = corev1.ResourceRequirements{
+		Requests: map[corev1.ResourceName]resource.Quantity{
+			corev1.ResourceMemory: DefaultMemoryLimits,
+		},
+		Limits: map[corev1.ResourceName]resource.Quantity{
+			corev1.ResourceMemory: DefaultMemoryLimits,
+		},
+	}
+)
+
+// readinessProbe is the readiness probe for the collector container, served by the health check extension.
+func readinessProbe() corev1.Probe {
+	return corev1.Probe{
+		FailureThreshold:    3,
+		InitialDelaySeconds: 10,
+		PeriodSeconds:       10,
+		SuccessThreshold:    1,
+		TimeoutSeconds:      5,
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Port:   intstr.FromInt(HealthCheckPort),
+				Path:   "/",
+				Scheme: corev1.URISchemeHTTP,
+			},
+		},
+	}
+}
+
+func newPodSpec(collector otelv1alpha1.OpenTelemetryCollector, configHash string) (corev1.PodTemplateSpec, error) {
+	// ensure the Pod gets rotated on config change
+	annotations := map[string]string{configHashAnnotationName: configHash}
+
+	defaultContainerPorts := []corev1.ContainerPort{
+		{Name: "otlp-grpc", ContainerPort: int32(GRPCPort), Protocol: corev1.ProtocolTCP},
+		{Name: "otlp-http", ContainerPort: int32(HTTPPort), Protocol: corev1.ProtocolTCP},
+	}
+
+	cfgVolume := configSecretVolume(collector)
+
+	v, err := version.Parse(collector.Spec.Version)
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err // error unlikely and should have been caught during validation
+	}
+	if err := container.CheckImageCatalog(collector.Spec.Image, container.AgentImage, v); err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
+
+	builder := defaults.NewPodTemplateBuilder(collector.Spec.PodTemplate, otelv1alpha1.CollectorContainerName).
+		WithAnnotations(annotations).
+		WithResources(DefaultResources).
+		WithDockerImage(collector.Spec.Image, container.ImageRepository(container.AgentImage, v)).
+		WithCommand([]string{collectorBinary}).
+		WithArgs("otel", "--config="+filepath.Join(ConfigMountPath, ConfigFilename)).
+		WithReadinessProbe(readinessProbe()).
+		WithPorts(defaultContainerPorts).
+		WithVolumes(cfgVolume.Volume()).
+		WithVolumeMounts(cfgVolume.VolumeMount()).
+		WithInitContainerDefaults()
+
+	for _, certs := range []struct {
+		associationType commonv1.AssociationType
+		volumeName      string
+		mountPath       string
+	}{
+		{associationType: commonv1.ElasticsearchAssociationType, volumeName: "es-certs", mountPath: ESCertsPath},
+		{associationType: commonv1.ApmServerAssociationType, volumeName: "apm-certs", mountPath: ApmCertsPath},
+	} {
+		builder, err = withCertsVolume(builder, collector, certs.associationType, certs.volumeName, certs.mountPath)
+		if err != nil {
+			return corev1.PodTemplateSpec{}, err
+		}
+	}
+
+	return builder.WithPodMutationPolicy(otelv1alpha1.Kind, collector.Namespace).PodTemplate, nil
+}
+
+// withCertsVolume mounts the CA of the association of the given type, if any.
+func withCertsVolume(
+	builder *defaults.PodTemplateBuilder,
+	collector otelv1alpha1.OpenTelemetryCollector,
+	associationType commonv1.AssociationType,
+	volumeName, mountPath string,
+) (*defaults.PodTemplateBuilder, error) {
+	assoc, err := association.SingleAssociationOfType(collector.GetAssociations(), associationType)
+	if err != nil || assoc == nil {
+		return builder, err
+	}
+	assocConf, err := assoc.AssociationConf()
+	if err != nil {
+		return nil, err
+	}
+	if !assocConf.CAIsConfigured() {
+		return builder, nil
+	}
+	vol := volume.NewSecretVolumeWithMountPath(assocConf.GetCASecretName(), volumeName, mountPath)
+	return builder.
+		WithVolumes(vol.Volume()).
+		WithVolumeMounts(vol.VolumeMount()), nil
+}
@@ -32,10 +32,12 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/certificates/remoteca"
 	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	esremotecluster "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/remotecluster"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/services"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/remotecluster/keystore"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/rbac"
 )
 
@@ -103,6 +105,7 @@ func (r *ReconcileRemoteClusters) Reconcile(ctx context.Context, request reconci
 	err := r.Get(ctx, request.NamespacedName, &es)
 	if err != nil {
 		if errors.IsNotFound(err) {
+			metrics.ForgetReconciledResource(ctx)
 			r.keystoreProvider.ForgetCluster(request.NamespacedName)
 			return deleteAllRemoteCa(ctx, r, request.NamespacedName)
 		}
@@ -359,8 +362,14 @@ func getExpectedRemoteClientsFor(
 	defer span.End()
 	expectedRemoteClusters := make(map[types.NamespacedName][]esv1.RemoteCluster)
 
+	// Remote clusters can also be declared in the Kibana resources associated with an Elasticsearch cluster
+	kibanaRemoteClusters, err := esremotecluster.KibanaRemoteClusters(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
 	// AddKey remote clusters declared in the Spec
-	for _, remoteCluster := range associatedEs.Spec.RemoteClusters {
+	for _, remoteCluster := range esremotecluster.WithKibanaRemoteClusters(*associatedEs, kibanaRemoteClusters) {
 		if !remoteCluster.ElasticsearchRef.IsDefined() {
 			continue
 		}
@@ -376,7 +385,7 @@ func getExpectedRemoteClientsFor(
 	// Seek for Elasticsearch resources where this cluster is declared as a remote cluster
 	for _, es := range list.Items {
 		es := es
-		for _, remoteCluster := range es.Spec.RemoteClusters {
+		for _, remoteCluster := range esremotecluster.WithKibanaRemoteClusters(es, kibanaRemoteClusters) {
 			if !remoteCluster.ElasticsearchRef.IsDefined() {
 				continue
 			}
@@ -24,7 +24,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/license"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
@@ -93,6 +95,44 @@ func TestRemoteCluster_Reconcile(t *testing.T) {
 			want:    reconcile.Result{},
 			wantErr: false,
 		},
+		{
+			name: "Remote cluster declared in Kibana ns1/kb, associated with ns1/es1 -> ns2/es2",
+			fields: fields{
+				clusters: slices.Concat(
+					newClusterBuilder("ns1", "es1", "7.0.0").build(),
+					newClusterBuilder("ns2", "es2", "7.0.0").build(),
+					[]client.Object{
+						&kbv1.Kibana{
+							ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "kb"},
+							Spec: kbv1.KibanaSpec{
+								ElasticsearchRef: commonv1.ObjectSelector{Name: "es1"},
+								RemoteClusters: []kbv1.RemoteCluster{
+									{Name: "spoke", ElasticsearchRef: commonv1.LocalObjectSelector{Namespace: "ns2", Name: "es2"}},
+								},
+							},
+						},
+						fakePublicCa("ns1", "es1"),
+						fakePublicCa("ns2", "es2"),
+					},
+				),
+				accessReviewer: &fakeAccessReviewer{allowed: true},
+				licenseChecker: license.MockLicenseChecker{EnterpriseEnabled: true},
+			},
+			args: args{
+				request: reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      "es2",
+						Namespace: "ns2",
+					},
+				},
+			},
+			expectedCASecrets: []*corev1.Secret{
+				remoteCa("ns1", "es1", "ns2", "es2"),
+				remoteCa("ns2", "es2", "ns1", "es1"),
+			},
+			want:    reconcile.Result{},
+			wantErr: false,
+		},
 		{
 			name: "[No API Keys] Bi-directional remote cluster ns1/es1 <-> ns2/es2",
 			fields: fields{
@@ -18,14 +18,17 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/certificates/remoteca"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/certificates/transport"
+	esremotecluster "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/remotecluster"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
 )
 
@@ -55,6 +58,18 @@ func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileRemote
 		return err
 	}
 
+	// Emit changes to remote clusters declared in Kibana resources, to the Elasticsearch clusters involved.
+	if err := c.Watch(
+		source.Kind(
+			mgr.GetCache(),
+			&kbv1.Kibana{},
+			esremotecluster.KibanaEventHandler(esremotecluster.ElasticsearchRefsForKibana),
+			predicate.TypedGenerationChangedPredicate[*kbv1.Kibana]{},
+		),
+	); err != nil {
+		return err
+	}
+
 	// Watch Secrets that contain:
 	//  * Remote certificate authorities managed by this controller.
 	//  * API keys
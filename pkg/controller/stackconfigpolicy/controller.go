@@ -7,8 +7,10 @@ package stackconfigpolicy
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"reflect"
 	"regexp"
+	"slices"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -46,6 +48,7 @@ import (
 	kblabel "github.com/elastic/cloud-on-k8s/v2/pkg/controller/kibana/label"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
 )
 
 const (
@@ -54,6 +57,9 @@ const (
 
 var (
 	defaultRequeue = reconcile.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+	// snapshotLifecyclePoliciesCheckPeriod is the period at which the snapshot lifecycle policies of the configured
+	// Elasticsearch clusters are compared with the declared ones.
+	snapshotLifecyclePoliciesCheckPeriod = 5 * time.Minute
 )
 
 // Add creates a new StackConfigPolicy Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
@@ -173,7 +179,10 @@ func (r *ReconcileStackConfigPolicy) Reconcile(ctx context.Context, request reco
 	// skip unmanaged resources
 	if common.IsUnmanaged(ctx, &policy) {
 		ulog.FromContext(ctx).Info("Object is currently not managed by this controller. Skipping reconciliation")
-		return reconcile.Result{}, nil
+		return reconcile.Result{}, tracing.CaptureError(ctx, common.ReconcilePausedCondition(ctx, r.Client, &policy, &policy.Status.Conditions, true))
+	}
+	if err := common.ReconcilePausedCondition(ctx, r.Client, &policy, &policy.Status.Conditions, false); err != nil {
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
 	}
 
 	// the StackConfigPolicy will be deleted nothing to do other than remove the watches
@@ -362,8 +371,8 @@ func (r *ReconcileStackConfigPolicy) reconcileElasticsearchResources(ctx context
 			return results.WithError(err), status
 		}
 
-		// get /_cluster/state to get the Settings currently configured in ES
-		currentSettings, err := r.getClusterStateFileSettings(ctx, es)
+		// get /_cluster/state to get the Settings currently configured in ES, and the SLM policies if some are declared
+		currentSettings, currentSLMPolicies, err := r.getElasticsearchState(ctx, es, policy)
 		if err != nil {
 			err = status.AddPolicyErrorFor(esNsn, policyv1alpha1.UnknownPhase, err.Error(), policyv1alpha1.ElasticsearchResourceType)
 			if err != nil {
@@ -373,8 +382,32 @@ func (r *ReconcileStackConfigPolicy) reconcileElasticsearchResources(ctx context
 			results.WithResult(defaultRequeue)
 		}
 
+		resourceStatus := newElasticsearchResourceStatus(currentSettings, expectedVersion)
+		if currentSLMPolicies != nil {
+			resourceStatus.SnapshotLifecyclePolicies = newSnapshotLifecyclePoliciesStatus(policy.Spec.Elasticsearch.SnapshotLifecyclePolicies, currentSLMPolicies)
+			if resourceStatus.CurrentVersion == expectedVersion && resourceStatus.SnapshotLifecyclePolicies.HasDrifted() {
+				err = fmt.Errorf("drift detected: snapshot lifecycle policies %s not found in Elasticsearch %s/%s",
+					strings.Join(resourceStatus.SnapshotLifecyclePolicies.Missing, ", "), es.Namespace, es.Name)
+				r.recorder.Eventf(&policy, corev1.EventTypeWarning, events.EventReasonUnexpected, err.Error())
+			}
+			// SLM policies can be deleted or created outside the policy, check them periodically
+			results.WithResult(reconcile.Result{RequeueAfter: snapshotLifecyclePoliciesCheckPeriod})
+		}
+
+		// aliases cannot be declared in the file settings, they are added through the API once the file settings
+		// holding the index templates of the indices they may point to are applied
+		if policy.Spec.Elasticsearch.Aliases != nil && resourceStatus.CurrentVersion == expectedVersion && resourceStatus.Error.Message == "" {
+			if err := r.addAliases(ctx, es, policy.Spec.Elasticsearch.Aliases); err != nil {
+				resourceStatus.Error = policyv1alpha1.PolicyStatusError{
+					Version: expectedVersion,
+					Message: fmt.Sprintf("failed to add aliases: %s", err.Error()),
+				}
+				results.WithResult(defaultRequeue)
+			}
+		}
+
 		// update the ES resource status for this ES
-		err = status.UpdateResourceStatusPhase(esNsn, newElasticsearchResourceStatus(currentSettings, expectedVersion), configAndSecretMountsApplied, policyv1alpha1.ElasticsearchResourceType)
+		err = status.UpdateResourceStatusPhase(esNsn, resourceStatus, configAndSecretMountsApplied, policyv1alpha1.ElasticsearchResourceType)
 		if err != nil {
 			return results.WithError(err), status
 		}
@@ -445,10 +478,10 @@ func (r *ReconcileStackConfigPolicy) reconcileKibanaResources(ctx context.Contex
 			continue
 		}
 
-		// Create the Secret that holds the Kibana configuration.
-		if policy.Spec.Kibana.Config != nil {
-			// Only add to configured resources if Kibana config is set.
-			// This will help clean up the config secret if config gets removed from the stack config policy.
+		// Create the Secret that holds the Kibana configuration and the references to the secure settings.
+		if policy.Spec.Kibana.Config != nil || len(policy.Spec.Kibana.SecureSettings) > 0 {
+			// Only add to configured resources if Kibana config or secure settings are set.
+			// This will help clean up the config secret if they get removed from the stack config policy.
 			configuredResources[kibanaNsn] = kibana
 			expectedConfigSecret, err := newKibanaConfigSecret(policy, kibana)
 			if err != nil {
@@ -493,6 +526,30 @@ func newElasticsearchResourceStatus(currentSettings esclient.FileSettings, expec
 	return status
 }
 
+// newSnapshotLifecyclePoliciesStatus compares the declared snapshot lifecycle policies with the ones existing in Elasticsearch.
+func newSnapshotLifecyclePoliciesStatus(declared *commonv1.Config, current esclient.SLMPolicies) *policyv1alpha1.SnapshotLifecyclePoliciesStatus {
+	status := &policyv1alpha1.SnapshotLifecyclePoliciesStatus{}
+	var declaredPolicies map[string]interface{}
+	if declared != nil {
+		declaredPolicies = declared.Data
+	}
+	for name := range declaredPolicies {
+		if _, exists := current[name]; exists {
+			status.Managed++
+			continue
+		}
+		status.Missing = append(status.Missing, name)
+	}
+	for name := range current {
+		if _, exists := declaredPolicies[name]; !exists {
+			status.Unmanaged = append(status.Unmanaged, name)
+		}
+	}
+	slices.Sort(status.Missing)
+	slices.Sort(status.Unmanaged)
+	return status
+}
+
 var (
 	matchTabsAtSpaces         = regexp.MustCompile("[\t]+at\\s")
 	matchTripleDotsNumberMore = regexp.MustCompile("... [0-9]+ more")
@@ -529,6 +586,7 @@ func (r *ReconcileStackConfigPolicy) updateStatus(ctx context.Context, scp polic
 	span, _ := apm.StartSpan(ctx, "update_status", tracing.SpanTypeApp)
 	defer span.End()
 
+	status.Conditions = scp.Status.Conditions.WithReadinessConditions(status.ResourceState(), metav1.Now())
 	if reflect.DeepEqual(status, scp.Status) {
 		return nil // nothing to do
 	}
@@ -545,6 +603,7 @@ func (r *ReconcileStackConfigPolicy) updateStatus(ctx context.Context, scp polic
 
 func (r *ReconcileStackConfigPolicy) onDelete(ctx context.Context, obj types.NamespacedName) error {
 	defer tracing.Span(&ctx)()
+	metrics.DeleteResourceMetrics(ctx, policyv1alpha1.Kind, obj)
 	// Remove dynamic watches on secrets
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(additionalSecretMountsWatcherName(obj))
 	// Send empty resource type so that we reset/delete secrets for configured elasticsearch and kibana clusters
@@ -706,23 +765,71 @@ func deleteOrphanSoftOwnedSecrets(
 	return nil
 }
 
-// getClusterStateFileSettings gets the file based settings currently configured in an Elasticsearch by calling the /_cluster/state API.
-func (r *ReconcileStackConfigPolicy) getClusterStateFileSettings(ctx context.Context, es esv1.Elasticsearch) (esclient.FileSettings, error) {
+// getElasticsearchState gets the file based settings currently configured in an Elasticsearch by calling the /_cluster/state API.
+// If the policy declares snapshot lifecycle policies, it also returns the SLM policies existing in Elasticsearch.
+func (r *ReconcileStackConfigPolicy) getElasticsearchState(ctx context.Context, es esv1.Elasticsearch, policy policyv1alpha1.StackConfigPolicy) (esclient.FileSettings, esclient.SLMPolicies, error) {
 	span, _ := apm.StartSpan(ctx, "get_cluster_state", tracing.SpanTypeApp)
 	defer span.End()
 
 	esClient, err := r.esClientProvider(ctx, r.Client, r.params.Dialer, es)
 	if err != nil {
-		return esclient.FileSettings{}, err
+		return esclient.FileSettings{}, nil, err
 	}
 	defer esClient.Close()
 
 	clusterState, err := esClient.GetClusterState(ctx)
 	if err != nil {
-		return esclient.FileSettings{}, err
+		return esclient.FileSettings{}, nil, err
 	}
+	fileSettings := clusterState.Metadata.ReservedState.FileSettings
 
-	return clusterState.Metadata.ReservedState.FileSettings, nil
+	if policy.Spec.Elasticsearch.SnapshotLifecyclePolicies == nil {
+		return fileSettings, nil, nil
+	}
+	slmPolicies, err := esClient.GetSLMPolicies(ctx)
+	if err != nil {
+		return fileSettings, nil, err
+	}
+	if slmPolicies == nil {
+		slmPolicies = esclient.SLMPolicies{}
+	}
+	return fileSettings, slmPolicies, nil
+}
+
+// addAliases adds the given aliases to Elasticsearch in a single request. Adding an existing alias updates it.
+func (r *ReconcileStackConfigPolicy) addAliases(ctx context.Context, es esv1.Elasticsearch, aliases *commonv1.Config) error {
+	span, _ := apm.StartSpan(ctx, "add_aliases", tracing.SpanTypeApp)
+	defer span.End()
+
+	if len(aliases.Data) == 0 {
+		return nil
+	}
+	esClient, err := r.esClientProvider(ctx, r.Client, r.params.Dialer, es)
+	if err != nil {
+		return err
+	}
+	defer esClient.Close()
+	return esClient.ApplyResource(ctx, http.MethodPost, "/_aliases", aliasActions(aliases))
+}
+
+// aliasActions returns the body of the request adding the given aliases, keyed by name, in the order of their names.
+func aliasActions(aliases *commonv1.Config) map[string]interface{} {
+	names := make([]string, 0, len(aliases.Data))
+	for name := range aliases.Data {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	actions := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		action := map[string]interface{}{"alias": name}
+		if definition, ok := aliases.Data[name].(map[string]interface{}); ok {
+			for key, value := range definition {
+				action[key] = value
+			}
+		}
+		actions = append(actions, map[string]interface{}{"add": action})
+	}
+	return map[string]interface{}{"actions": actions}
 }
 
 func (r *ReconcileStackConfigPolicy) addDynamicWatchesOnAdditionalSecretMounts(policy policyv1alpha1.StackConfigPolicy) error {
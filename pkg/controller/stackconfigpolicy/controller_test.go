@@ -42,7 +42,11 @@ type fakeEsClient struct {
 	esclient.Client
 
 	fileSettings esclient.FileSettings
+	slmPolicies  esclient.SLMPolicies
 	err          error
+	// applied records the bodies of the requests applying resources, by path
+	applied  map[string]interface{}
+	applyErr error
 }
 
 var fakeClientProvider = func(fileSettings esclient.FileSettings, err error) commonesclient.Provider {
@@ -55,6 +59,30 @@ var fakeClientProvider = func(fileSettings esclient.FileSettings, err error) com
 	}
 }
 
+var fakeClientProviderWithSLMPolicies = func(fileSettings esclient.FileSettings, slmPolicies esclient.SLMPolicies) commonesclient.Provider {
+	return func(ctx context.Context, c k8s.Client, dialer net.Dialer, es esv1.Elasticsearch) (esclient.Client, error) {
+		return fakeEsClient{fileSettings: fileSettings, slmPolicies: slmPolicies}, nil
+	}
+}
+
+var fakeClientProviderWithApplied = func(fileSettings esclient.FileSettings, applied map[string]interface{}, applyErr error) commonesclient.Provider {
+	return func(ctx context.Context, c k8s.Client, dialer net.Dialer, es esv1.Elasticsearch) (esclient.Client, error) {
+		return fakeEsClient{fileSettings: fileSettings, applied: applied, applyErr: applyErr}, nil
+	}
+}
+
+func (c fakeEsClient) ApplyResource(_ context.Context, _, path string, body interface{}) error {
+	if c.applyErr != nil {
+		return c.applyErr
+	}
+	c.applied[path] = body
+	return nil
+}
+
+func (c fakeEsClient) GetSLMPolicies(_ context.Context) (esclient.SLMPolicies, error) {
+	return c.slmPolicies, c.err
+}
+
 func (c fakeEsClient) GetClusterState(_ context.Context) (esclient.ClusterState, error) {
 	if c.err != nil {
 		return esclient.ClusterState{}, c.err
@@ -220,6 +248,23 @@ func TestReconcileStackConfigPolicy_Reconcile(t *testing.T) {
 		"indices.recovery.max_bytes_per_sec": "43mb",
 	}}
 
+	slmPolicyFixture := policyFixture.DeepCopy()
+	slmPolicyFixture.Spec.Elasticsearch.SnapshotLifecyclePolicies = &commonv1.Config{Data: map[string]interface{}{
+		"daily":  map[string]interface{}{"schedule": "0 30 1 * * ?", "repository": "backups"},
+		"weekly": map[string]interface{}{"schedule": "0 30 1 ? * SUN", "repository": "backups"},
+	}}
+	slmSecretFixture, _, err := filesettings.NewSettingsSecret(42, k8s.ExtractNamespacedName(&esFixture), nil, slmPolicyFixture)
+	assert.NoError(t, err)
+
+	aliasesPolicyFixture := policyFixture.DeepCopy()
+	aliasesPolicyFixture.Spec.Elasticsearch.Aliases = &commonv1.Config{Data: map[string]interface{}{
+		"logs-current": map[string]interface{}{"index": "logs-2024.10", "is_write_index": true},
+		"logs":         map[string]interface{}{"indices": []interface{}{"logs-*"}},
+	}}
+	aliasesSecretFixture, _, err := filesettings.NewSettingsSecret(42, k8s.ExtractNamespacedName(&esFixture), nil, aliasesPolicyFixture)
+	assert.NoError(t, err)
+	appliedAliases := map[string]interface{}{}
+
 	orphanEsFixture := esFixture.DeepCopy()
 	orphanEsFixture.Name = "another-es"
 	orphanEsFixture.Labels["label"] = "another"
@@ -470,6 +515,66 @@ func TestReconcileStackConfigPolicy_Reconcile(t *testing.T) {
 			wantRequeue:      true,
 			wantRequeueAfter: true,
 		},
+		{
+			name: "Declared SLM policies not found in Elasticsearch: drift is reported",
+			args: args{
+				client:         k8s.NewFakeClient(slmPolicyFixture, &esFixture, &slmSecretFixture, secretMountsSecretFixture, esPodFixture),
+				licenseChecker: &license.MockLicenseChecker{EnterpriseEnabled: true},
+				esClientProvider: fakeClientProviderWithSLMPolicies(clusterStateFileSettingsFixture(42, nil), esclient.SLMPolicies{
+					"daily":  {},
+					"manual": {},
+				}),
+			},
+			post: func(r ReconcileStackConfigPolicy, recorder record.FakeRecorder) {
+				policy := r.getPolicy(t, nsnFixture)
+				assert.Equal(t, 1, policy.Status.Resources)
+				assert.Equal(t, 1, policy.Status.Errors)
+				assert.Equal(t, policyv1alpha1.ErrorPhase, policy.Status.Phase)
+				assert.Equal(t, &policyv1alpha1.SnapshotLifecyclePoliciesStatus{
+					Managed:   1,
+					Missing:   []string{"weekly"},
+					Unmanaged: []string{"manual"},
+				}, policy.Status.Details["elasticsearch"]["ns/test-es"].SnapshotLifecyclePolicies)
+				assert.Contains(t, fetchEvents(&recorder), "Warning Unexpected drift detected: snapshot lifecycle policies weekly not found in Elasticsearch ns/test-es")
+			},
+			wantErr:          false,
+			wantRequeue:      true,
+			wantRequeueAfter: true,
+		},
+		{
+			name: "Aliases are added once the settings are applied",
+			args: args{
+				client:           k8s.NewFakeClient(aliasesPolicyFixture, &esFixture, &aliasesSecretFixture, secretMountsSecretFixture, esPodFixture),
+				licenseChecker:   &license.MockLicenseChecker{EnterpriseEnabled: true},
+				esClientProvider: fakeClientProviderWithApplied(clusterStateFileSettingsFixture(42, nil), appliedAliases, nil),
+			},
+			post: func(r ReconcileStackConfigPolicy, recorder record.FakeRecorder) {
+				policy := r.getPolicy(t, nsnFixture)
+				assert.Equal(t, policyv1alpha1.ReadyPhase, policy.Status.Phase)
+				assert.Equal(t, map[string]interface{}{"actions": []interface{}{
+					map[string]interface{}{"add": map[string]interface{}{"alias": "logs", "indices": []interface{}{"logs-*"}}},
+					map[string]interface{}{"add": map[string]interface{}{"alias": "logs-current", "index": "logs-2024.10", "is_write_index": true}},
+				}}, appliedAliases["/_aliases"])
+			},
+			wantErr: false,
+		},
+		{
+			name: "Aliases cannot be added",
+			args: args{
+				client:           k8s.NewFakeClient(aliasesPolicyFixture, &esFixture, &aliasesSecretFixture, secretMountsSecretFixture, esPodFixture),
+				licenseChecker:   &license.MockLicenseChecker{EnterpriseEnabled: true},
+				esClientProvider: fakeClientProviderWithApplied(clusterStateFileSettingsFixture(42, nil), nil, errors.New("index_not_found_exception")),
+			},
+			post: func(r ReconcileStackConfigPolicy, recorder record.FakeRecorder) {
+				policy := r.getPolicy(t, nsnFixture)
+				assert.Equal(t, 1, policy.Status.Errors)
+				assert.Equal(t, policyv1alpha1.ErrorPhase, policy.Status.Phase)
+				assert.Equal(t, "failed to add aliases: index_not_found_exception", policy.Status.Details["elasticsearch"]["ns/test-es"].Error.Message)
+			},
+			wantErr:          false,
+			wantRequeue:      true,
+			wantRequeueAfter: true,
+		},
 		{
 			name: "Current settings are wrong",
 			args: args{
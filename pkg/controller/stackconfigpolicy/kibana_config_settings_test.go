@@ -10,7 +10,10 @@ import (
 
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	kibanav1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
@@ -368,3 +371,39 @@ func MkKibanaConfigSecret(namespace string, owningPolicyName string, owningPolic
 		},
 	}
 }
+
+func TestReconcileStackConfigPolicy_reconcileKibanaResources_secureSettingsOnly(t *testing.T) {
+	kb := kibanav1.Kibana{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "test-kb", Labels: map[string]string{"label": "test"}}}
+	policy := policyv1alpha1.StackConfigPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "test-policy"},
+		Spec: policyv1alpha1.StackConfigPolicySpec{
+			ResourceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"label": "test"}},
+			Kibana: policyv1alpha1.KibanaConfigPolicySpec{
+				SecureSettings: []commonv1.SecretSource{{SecretName: "kibana-secure-settings"}},
+			},
+		},
+	}
+	r := ReconcileStackConfigPolicy{
+		Client:   k8s.NewFakeClient(&kb, &policy, mkKibanaPod("ns", true, "")),
+		recorder: record.NewFakeRecorder(10),
+	}
+
+	results, status := r.reconcileKibanaResources(context.Background(), policy, policyv1alpha1.NewStatus(policy))
+	_, err := results.Aggregate()
+	require.NoError(t, err)
+	require.Equal(t, policyv1alpha1.ReadyPhase, status.Details["kibana"]["ns/test-kb"].Phase)
+
+	// the secure settings are referenced in the policy config Secret, even without any Kibana config
+	var secret corev1.Secret
+	require.NoError(t, r.Client.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "test-kb-kb-policy-config"}, &secret))
+	require.JSONEq(t, `[{"namespace":"ns","secretName":"kibana-secure-settings"}]`, secret.Annotations["policy.k8s.elastic.co/secure-settings-secrets"])
+	require.Empty(t, secret.Data[KibanaConfigKey])
+
+	// the Secret is deleted once the secure settings are removed from the policy
+	policy.Spec.Kibana.SecureSettings = nil
+	results, _ = r.reconcileKibanaResources(context.Background(), policy, policyv1alpha1.NewStatus(policy))
+	_, err = results.Aggregate()
+	require.NoError(t, err)
+	err = r.Client.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "test-kb-kb-policy-config"}, &secret)
+	require.True(t, apierrors.IsNotFound(err))
+}
@@ -0,0 +1,62 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package trustbundle
+
+import (
+	"bytes"
+	"context"
+	"sort"
+
+	certificatesv1alpha1 "k8s.io/api/certificates/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+// IsAPIAvailable returns true if the ClusterTrustBundle API is served by the Kubernetes cluster. It is in alpha and
+// must be enabled through the ClusterTrustBundle feature gate and the certificates.k8s.io/v1alpha1 runtime config.
+func IsAPIAvailable(clientset kubernetes.Interface) (bool, error) {
+	resources, err := clientset.Discovery().ServerResourcesForGroupVersion(certificatesv1alpha1.SchemeGroupVersion.String())
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Name == "clustertrustbundles" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// TrustedCertificates returns the concatenated PEM certificates of the ClusterTrustBundles matching the given selector,
+// ordered by bundle name. It returns nil if the selector is nil.
+func TrustedCertificates(ctx context.Context, c k8s.Client, selector labels.Selector) ([]byte, error) {
+	if selector == nil {
+		return nil, nil
+	}
+	var bundles certificatesv1alpha1.ClusterTrustBundleList
+	if err := c.List(ctx, &bundles, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, err
+	}
+	sort.Slice(bundles.Items, func(i, j int) bool {
+		return bundles.Items[i].Name < bundles.Items[j].Name
+	})
+	var pem bytes.Buffer
+	for _, bundle := range bundles.Items {
+		trustBundle := bytes.TrimSpace([]byte(bundle.Spec.TrustBundle))
+		if len(trustBundle) == 0 {
+			continue
+		}
+		pem.Write(trustBundle)
+		pem.WriteByte('\n')
+	}
+	return pem.Bytes(), nil
+}
@@ -0,0 +1,181 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package trustbundle
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	certificatesv1alpha1 "k8s.io/api/certificates/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
+)
+
+const (
+	name = "trustbundle-controller"
+
+	// Type is the type label value of the ClusterTrustBundles published by the operator.
+	Type = "ca-trust-bundle"
+	// SourceNamespaceLabel is the label holding the namespace of the Secret a ClusterTrustBundle is published from.
+	SourceNamespaceLabel = "eck.k8s.elastic.co/trust-bundle-source-namespace"
+	// SourceNameLabel is the label holding the name of the Secret a ClusterTrustBundle is published from.
+	SourceNameLabel = "eck.k8s.elastic.co/trust-bundle-source-name"
+
+	publicCertsSecretSuffix = "-certs-public"
+)
+
+// Add creates a new trust bundle controller and adds it to the manager.
+func Add(mgr manager.Manager, params operator.Parameters) error {
+	r := &ReconcileTrustBundle{
+		Client:     mgr.GetClient(),
+		Parameters: params,
+	}
+	c, err := common.NewController(mgr, name, r, params)
+	if err != nil {
+		return err
+	}
+	return addWatches(mgr, c)
+}
+
+// addWatches enqueues the public certificates Secrets which changed, as well as the Secrets from which the modified or
+// deleted ClusterTrustBundles are published.
+func addWatches(mgr manager.Manager, c controller.Controller) error {
+	if err := c.Watch(source.Kind(mgr.GetCache(), &corev1.Secret{}, handler.TypedEnqueueRequestsFromMapFunc(
+		func(_ context.Context, secret *corev1.Secret) []reconcile.Request {
+			if !IsPublicCertsSecret(*secret) {
+				return nil
+			}
+			return []reconcile.Request{{NamespacedName: k8s.ExtractNamespacedName(secret)}}
+		},
+	))); err != nil {
+		return err
+	}
+	return c.Watch(source.Kind(mgr.GetCache(), &certificatesv1alpha1.ClusterTrustBundle{}, handler.TypedEnqueueRequestsFromMapFunc(
+		func(_ context.Context, bundle *certificatesv1alpha1.ClusterTrustBundle) []reconcile.Request {
+			if bundle.Labels[commonv1.TypeLabelName] != Type {
+				return nil
+			}
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{
+				Namespace: bundle.Labels[SourceNamespaceLabel],
+				Name:      bundle.Labels[SourceNameLabel],
+			}}}
+		},
+	)))
+}
+
+// IsPublicCertsSecret returns true if the given Secret holds the public certificates of a resource managed by the
+// operator, from which the CA certificate can be published. Copies of these Secrets published to other namespaces are
+// ignored.
+func IsPublicCertsSecret(secret corev1.Secret) bool {
+	return strings.HasSuffix(secret.Name, publicCertsSecretSuffix) &&
+		secret.Labels[reconciler.SoftOwnerKindLabel] != "" &&
+		secret.Labels[reconciler.PublishedLabel] != "true"
+}
+
+// BundleName returns the name of the ClusterTrustBundle holding the CA certificate of the given public certificates
+// Secret. For example, the CA of the Secret ns/es-es-http-certs-public is published as eck.ns.es-es-http-ca.
+func BundleName(secret types.NamespacedName) string {
+	return fmt.Sprintf("eck.%s.%s-ca", secret.Namespace, strings.TrimSuffix(secret.Name, publicCertsSecretSuffix))
+}
+
+var _ reconcile.Reconciler = &ReconcileTrustBundle{}
+
+// ReconcileTrustBundle publishes the CA certificates of the resources managed by the operator as ClusterTrustBundles,
+// so that any workload of the Kubernetes cluster can trust them through a projected volume.
+type ReconcileTrustBundle struct {
+	k8s.Client
+	operator.Parameters
+
+	// iteration is the number of times this controller has run its Reconcile method
+	iteration uint64
+}
+
+// Reconcile publishes the CA certificate of the public certificates Secret of the request, or removes the
+// corresponding ClusterTrustBundle if the Secret or its CA certificate does not exist anymore.
+func (r *ReconcileTrustBundle) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	ctx = common.NewReconciliationContext(ctx, &r.iteration, r.Tracer, name, "secret_name", request)
+	defer common.LogReconciliationRun(ulog.FromContext(ctx))()
+	defer tracing.EndContextTransaction(ctx)
+
+	var secret corev1.Secret
+	if err := r.Get(ctx, request.NamespacedName, &secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			metrics.ForgetReconciledResource(ctx)
+			return reconcile.Result{}, tracing.CaptureError(ctx, deleteBundle(ctx, r.Client, request.NamespacedName))
+		}
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
+	}
+	ca := secret.Data[certificates.CAFileName]
+	if !IsPublicCertsSecret(secret) || len(ca) == 0 {
+		return reconcile.Result{}, tracing.CaptureError(ctx, deleteBundle(ctx, r.Client, request.NamespacedName))
+	}
+	return reconcile.Result{}, tracing.CaptureError(ctx, reconcileBundle(ctx, r.Client, request.NamespacedName, string(ca)))
+}
+
+// reconcileBundle creates or updates the ClusterTrustBundle holding the given CA certificate.
+func reconcileBundle(ctx context.Context, c k8s.Client, secret types.NamespacedName, ca string) error {
+	expected := certificatesv1alpha1.ClusterTrustBundle{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: BundleName(secret),
+			Labels: map[string]string{
+				commonv1.TypeLabelName: Type,
+				SourceNamespaceLabel:   secret.Namespace,
+				SourceNameLabel:        secret.Name,
+			},
+		},
+		Spec: certificatesv1alpha1.ClusterTrustBundleSpec{
+			TrustBundle: ca,
+		},
+	}
+	reconciled := &certificatesv1alpha1.ClusterTrustBundle{}
+	return reconciler.ReconcileResource(reconciler.Params{
+		Context:    ctx,
+		Client:     c,
+		Expected:   &expected,
+		Reconciled: reconciled,
+		NeedsUpdate: func() bool {
+			return reconciled.Spec.TrustBundle != expected.Spec.TrustBundle
+		},
+		UpdateReconciled: func() {
+			reconciled.Spec.TrustBundle = expected.Spec.TrustBundle
+		},
+	})
+}
+
+// deleteBundle deletes the ClusterTrustBundle published from the given Secret, if any.
+func deleteBundle(ctx context.Context, c k8s.Client, secret types.NamespacedName) error {
+	var bundle certificatesv1alpha1.ClusterTrustBundle
+	if err := c.Get(ctx, types.NamespacedName{Name: BundleName(secret)}, &bundle); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if bundle.Labels[commonv1.TypeLabelName] != Type {
+		// not created by the operator
+		return nil
+	}
+	ulog.FromContext(ctx).Info("Deleting cluster trust bundle", "name", bundle.Name)
+	return client.IgnoreNotFound(c.Delete(ctx, &bundle))
+}
@@ -0,0 +1,141 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package trustbundle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	certificatesv1alpha1 "k8s.io/api/certificates/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func publicCertsSecret(namespace, name string, ca string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+			Labels: map[string]string{
+				reconciler.SoftOwnerKindLabel:      "Elasticsearch",
+				reconciler.SoftOwnerNamespaceLabel: namespace,
+				reconciler.SoftOwnerNameLabel:      "es",
+			},
+		},
+		Data: map[string][]byte{
+			certificates.CertFileName: []byte("cert"),
+			certificates.CAFileName:   []byte(ca),
+		},
+	}
+}
+
+func TestIsPublicCertsSecret(t *testing.T) {
+	published := publicCertsSecret("other", "es-es-http-certs-public", "ca")
+	published.Labels[reconciler.PublishedLabel] = "true"
+	notOwned := publicCertsSecret("ns", "es-es-http-certs-public", "ca")
+	notOwned.Labels = nil
+
+	require.True(t, IsPublicCertsSecret(*publicCertsSecret("ns", "es-es-http-certs-public", "ca")))
+	require.True(t, IsPublicCertsSecret(*publicCertsSecret("ns", "es-es-transport-certs-public", "ca")))
+	require.False(t, IsPublicCertsSecret(*publicCertsSecret("ns", "es-es-http-certs-internal", "ca")))
+	require.False(t, IsPublicCertsSecret(*published))
+	require.False(t, IsPublicCertsSecret(*notOwned))
+}
+
+func TestReconcileTrustBundle_Reconcile(t *testing.T) {
+	secret := publicCertsSecret("ns", "es-es-http-certs-public", "ca-1")
+	secretKey := k8s.ExtractNamespacedName(secret)
+	bundleKey := types.NamespacedName{Name: "eck.ns.es-es-http-ca"}
+	c := k8s.NewFakeClient(secret)
+	r := &ReconcileTrustBundle{Client: c}
+	ctx := context.Background()
+	request := reconcile.Request{NamespacedName: secretKey}
+
+	// the CA is published
+	_, err := r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	var bundle certificatesv1alpha1.ClusterTrustBundle
+	require.NoError(t, c.Get(ctx, bundleKey, &bundle))
+	require.Equal(t, "ca-1", bundle.Spec.TrustBundle)
+	require.Empty(t, bundle.Spec.SignerName)
+	require.Equal(t, Type, bundle.Labels[commonv1.TypeLabelName])
+	require.Equal(t, "ns", bundle.Labels[SourceNamespaceLabel])
+	require.Equal(t, "es-es-http-certs-public", bundle.Labels[SourceNameLabel])
+
+	// the bundle is updated when the CA is rotated
+	secret.Data[certificates.CAFileName] = []byte("ca-2")
+	require.NoError(t, c.Update(ctx, secret))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, bundleKey, &bundle))
+	require.Equal(t, "ca-2", bundle.Spec.TrustBundle)
+
+	// the bundle is deleted with the Secret
+	require.NoError(t, c.Delete(ctx, secret))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.True(t, apierrors.IsNotFound(c.Get(ctx, bundleKey, &bundle)))
+
+	// bundles not created by the operator are left untouched
+	userBundle := certificatesv1alpha1.ClusterTrustBundle{
+		ObjectMeta: metav1.ObjectMeta{Name: bundleKey.Name},
+		Spec:       certificatesv1alpha1.ClusterTrustBundleSpec{TrustBundle: "user-ca"},
+	}
+	require.NoError(t, c.Create(ctx, &userBundle))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, bundleKey, &bundle))
+}
+
+func TestReconcileTrustBundle_Reconcile_NoCA(t *testing.T) {
+	// certificates provided by the user without CA
+	secret := publicCertsSecret("ns", "kb-kb-http-certs-public", "")
+	existing := &certificatesv1alpha1.ClusterTrustBundle{
+		ObjectMeta: metav1.ObjectMeta{Name: "eck.ns.kb-kb-http-ca", Labels: map[string]string{commonv1.TypeLabelName: Type}},
+		Spec:       certificatesv1alpha1.ClusterTrustBundleSpec{TrustBundle: "previous-ca"},
+	}
+	c := k8s.NewFakeClient(secret, existing)
+	r := &ReconcileTrustBundle{Client: c}
+	_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(secret)})
+	require.NoError(t, err)
+	var bundles certificatesv1alpha1.ClusterTrustBundleList
+	require.NoError(t, c.List(context.Background(), &bundles))
+	require.Empty(t, bundles.Items)
+}
+
+func TestTrustedCertificates(t *testing.T) {
+	trusted := map[string]string{"trusted": "true"}
+	c := k8s.NewFakeClient(
+		&certificatesv1alpha1.ClusterTrustBundle{
+			ObjectMeta: metav1.ObjectMeta{Name: "b", Labels: trusted},
+			Spec:       certificatesv1alpha1.ClusterTrustBundleSpec{TrustBundle: "ca-b\n\n"},
+		},
+		&certificatesv1alpha1.ClusterTrustBundle{
+			ObjectMeta: metav1.ObjectMeta{Name: "a", Labels: trusted},
+			Spec:       certificatesv1alpha1.ClusterTrustBundleSpec{TrustBundle: "ca-a"},
+		},
+		&certificatesv1alpha1.ClusterTrustBundle{
+			ObjectMeta: metav1.ObjectMeta{Name: "c"},
+			Spec:       certificatesv1alpha1.ClusterTrustBundleSpec{TrustBundle: "ca-c"},
+		},
+	)
+	pem, err := TrustedCertificates(context.Background(), c, labels.SelectorFromSet(trusted))
+	require.NoError(t, err)
+	require.Equal(t, "ca-a\nca-b\n", string(pem))
+
+	pem, err = TrustedCertificates(context.Background(), c, nil)
+	require.NoError(t, err)
+	require.Nil(t, pem)
+}
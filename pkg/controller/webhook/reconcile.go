@@ -5,9 +5,11 @@
 package webhook
 
 import (
+	"bytes"
 	"context"
 	"time"
 
+	pkgerrors "github.com/pkg/errors"
 	"go.elastic.co/apm/v2"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -28,6 +30,10 @@ type Params struct {
 
 	// Certificate options
 	Rotation certificates.RotationParams
+
+	// ExternalCerts is true if the certificates in the webhook Secret are issued by a third party such as cert-manager.
+	// Only the CA bundle of the webhook configuration is then reconciled.
+	ExternalCerts bool
 }
 
 // ReconcileResources reconciles the certificates used by the webhook client and the webhook server.
@@ -43,6 +49,10 @@ func (w *Params) ReconcileResources(ctx context.Context, clientset kubernetes.In
 		return err
 	}
 
+	if w.ExternalCerts {
+		return w.reconcileCABundle(ctx, webhookServerSecret, webhookConfiguration)
+	}
+
 	// check if we need to renew the certificates used in the resources
 	if w.shouldRenewCertificates(ctx, webhookServerSecret, webhookConfiguration.webhooks()) {
 		ulog.FromContext(ctx).Info(
@@ -74,6 +84,27 @@ func (w *Params) ReconcileResources(ctx context.Context, clientset kubernetes.In
 	return nil
 }
 
+// reconcileCABundle updates the CA bundle of the webhook configuration with the CA of the certificates issued by a third party.
+func (w *Params) reconcileCABundle(ctx context.Context, webhookServerSecret *corev1.Secret, webhookConfiguration AdmissionControllerInterface) error {
+	caCert := webhookServerSecret.Data[certificates.CAFileName]
+	if len(caCert) == 0 {
+		return pkgerrors.Errorf("cannot find %s in webhook secret %s/%s", certificates.CAFileName, w.Namespace, w.SecretName)
+	}
+	for _, webhook := range webhookConfiguration.webhooks() {
+		if bytes.Equal(webhook.caBundle, caCert) {
+			continue
+		}
+		ulog.FromContext(ctx).Info(
+			"Updating webhook CA bundle",
+			"webhook", w.Name,
+			"secret_namespace", webhookServerSecret.Namespace,
+			"secret_name", webhookServerSecret.Name,
+		)
+		return webhookConfiguration.updateCABundle(caCert)
+	}
+	return nil
+}
+
 // updateOperatorPods updates a specific annotation on the pods to speed up secret propagation.
 func updateOperatorPods(ctx context.Context, clientset kubernetes.Interface, operatorNamespace string) {
 	// Get all the pods that are related to control-plane label.
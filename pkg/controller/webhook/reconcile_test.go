@@ -116,6 +116,61 @@ func TestParams_ReconcileResources(t *testing.T) {
 	verifyCertificates(t, caBundle, webhookServerSecret.Data["tls.crt"])
 }
 
+func TestParams_ReconcileResources_ExternalCerts(t *testing.T) {
+	w := Params{
+		Name:          "elastic-webhook.k8s.elastic.co",
+		Namespace:     "elastic-system",
+		SecretName:    "elastic-webhook-server-cert",
+		ExternalCerts: true,
+	}
+	ctx := context.Background()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "elastic-system",
+			Name:      "elastic-webhook-server-cert",
+		},
+		// certificates not issued yet
+		Data: map[string][]byte{},
+	}
+	clientset := fake.NewSimpleClientset(
+		secret,
+		&v1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "elastic-webhook.k8s.elastic.co",
+			},
+			Webhooks: []v1.ValidatingWebhook{
+				{Name: "elastic-es-validation-v1.k8s.elastic.co"},
+				{Name: "elastic-kb-validation-v1.k8s.elastic.co"},
+			},
+		},
+	)
+	clientset.Resources = []*metav1.APIResourceList{{GroupVersion: "admissionregistration.k8s.io/v1"}}
+
+	wh, err := w.NewAdmissionControllerInterface(ctx, clientset)
+	assert.NoError(t, err)
+	assert.ErrorContains(t, w.ReconcileResources(ctx, clientset, wh), "cannot find ca.crt in webhook secret")
+
+	// certificates issued by cert-manager
+	secret.Data = map[string][]byte{
+		certificates.CAFileName:   []byte("issuer-ca"),
+		certificates.CertFileName: []byte("cert"),
+		certificates.KeyFileName:  []byte("key"),
+	}
+	_, err = clientset.CoreV1().Secrets(w.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	assert.NoError(t, w.ReconcileResources(ctx, clientset, wh))
+
+	// the CA bundle is updated, the Secret is left untouched
+	webhookConfiguration, err := clientset.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(ctx, w.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	for _, webhook := range webhookConfiguration.Webhooks {
+		assert.Equal(t, []byte("issuer-ca"), webhook.ClientConfig.CABundle)
+	}
+	webhookServerSecret, err := clientset.CoreV1().Secrets(w.Namespace).Get(ctx, w.SecretName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, secret.Data, webhookServerSecret.Data)
+}
+
 func verifyCertificates(t *testing.T, rootCert []byte, serverCert []byte) {
 	t.Helper()
 	ca := x509.NewCertPool()
@@ -69,6 +69,10 @@ func (r *ReconcileWebhookResources) reconcileInternal(ctx context.Context) *reco
 	if err := r.webhookParams.ReconcileResources(ctx, r.clientset, wh); err != nil {
 		return res.WithError(err)
 	}
+	if r.webhookParams.ExternalCerts {
+		// certificates are rotated by their issuer, changes are caught by the watch on the Secret
+		return res
+	}
 
 	// Get the latest content of the webhook CA
 	webhookServerSecret, err := r.clientset.CoreV1().Secrets(r.webhookParams.Namespace).Get(ctx, r.webhookParams.SecretName, metav1.GetOptions{})
@@ -0,0 +1,385 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+// Package diagnostics implements an API, served by the operator webhook server, returning a support diagnostics bundle
+// for a namespace: the Elastic resources, the metadata of the Secrets, the events, the health and allocation
+// explanations of the Elasticsearch clusters and the operator logs. It allows collecting diagnostics without installing
+// the separate eck-diagnostics tool, for example in air-gapped environments.
+package diagnostics
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	agentv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/agent/v1alpha1"
+	apmv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/apm/v1"
+	autoscalingv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/autoscaling/v1alpha1"
+	beatv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/beat/v1beta1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	entv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/enterprisesearch/v1"
+	esclonev1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/esclone/v1alpha1"
+	esconfigv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/esconfig/v1"
+	estaskv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/estask/v1alpha1"
+	itcv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/indextemplateclaim/v1alpha1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	lsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+	emsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/maps/v1alpha1"
+	otelv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/otel/v1alpha1"
+	securityv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/security/v1alpha1"
+	policyv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/stackconfigpolicy/v1alpha1"
+	commonesclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/esclient"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/services"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/rbac"
+)
+
+const (
+	// Path is the path prefix under which the API is registered in the webhook server.
+	Path = "/diagnostics/"
+
+	// operatorLogsTailLines is the number of lines of the operator logs included in the bundle.
+	operatorLogsTailLines = 10000
+	// redacted replaces the values of the Secrets and of the annotations which may hold them.
+	redacted = "REDACTED"
+	// lastAppliedConfigAnnotation holds the whole object, including the data of the Secrets, when applied with kubectl.
+	lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+)
+
+// resourceKinds are the kinds of the Elastic resources included in the bundle.
+var resourceKinds = []schema.GroupVersionKind{
+	esv1.GroupVersion.WithKind(esv1.Kind),
+	kbv1.GroupVersion.WithKind(kbv1.Kind),
+	apmv1.GroupVersion.WithKind(apmv1.Kind),
+	entv1.GroupVersion.WithKind(entv1.Kind),
+	beatv1beta1.GroupVersion.WithKind(beatv1beta1.Kind),
+	agentv1alpha1.GroupVersion.WithKind(agentv1alpha1.Kind),
+	emsv1alpha1.GroupVersion.WithKind(emsv1alpha1.Kind),
+	lsv1alpha1.GroupVersion.WithKind(lsv1alpha1.Kind),
+	otelv1alpha1.GroupVersion.WithKind(otelv1alpha1.Kind),
+	autoscalingv1alpha1.GroupVersion.WithKind(autoscalingv1alpha1.Kind),
+	policyv1alpha1.GroupVersion.WithKind(policyv1alpha1.Kind),
+	esclonev1alpha1.GroupVersion.WithKind(esclonev1alpha1.Kind),
+	esconfigv1.GroupVersion.WithKind(esconfigv1.Kind),
+	estaskv1alpha1.GroupVersion.WithKind(estaskv1alpha1.Kind),
+	itcv1alpha1.GroupVersion.WithKind(itcv1alpha1.Kind),
+	securityv1alpha1.GroupVersion.WithKind(securityv1alpha1.RoleKind),
+	securityv1alpha1.GroupVersion.WithKind(securityv1alpha1.UserKind),
+}
+
+// elasticsearchRequests are the Elasticsearch APIs called for each selected cluster, by name of the file holding the
+// response in the bundle.
+var elasticsearchRequests = []struct {
+	file string
+	path string
+}{
+	{file: "cluster-health.json", path: "/_cluster/health"},
+	{file: "allocation-explain.json", path: "/_cluster/allocation/explain"},
+}
+
+// SecretMetadata is the redacted representation of a Secret in the bundle.
+type SecretMetadata struct {
+	Name        string            `json:"name"`
+	Type        corev1.SecretType `json:"type,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Owners      []string          `json:"owners,omitempty"`
+	// Keys maps the keys of the Secret to the size of their value, in bytes.
+	Keys map[string]int `json:"keys,omitempty"`
+}
+
+// Handler serves the diagnostics API.
+type Handler struct {
+	client            k8s.Client
+	clientset         kubernetes.Interface
+	dialer            net.Dialer
+	esClientProvider  commonesclient.Provider
+	operatorNamespace string
+	operatorPod       string
+	now               func() time.Time
+	mux               *http.ServeMux
+}
+
+// NewHandler returns a new Handler. The clientset is used to review the tokens and the permissions of the callers, and
+// to retrieve the logs of the given operator Pod.
+func NewHandler(client k8s.Client, clientset kubernetes.Interface, dialer net.Dialer, operatorNamespace, operatorPod string) *Handler {
+	h := &Handler{
+		client:            client,
+		clientset:         clientset,
+		dialer:            dialer,
+		esClientProvider:  commonesclient.NewClient,
+		operatorNamespace: operatorNamespace,
+		operatorPod:       operatorPod,
+		now:               time.Now,
+		mux:               http.NewServeMux(),
+	}
+	h.mux.HandleFunc("GET "+Path+"v1/namespaces/{namespace}", h.getBundle)
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// getBundle returns the diagnostics bundle of a namespace, as a zip archive, to the callers allowed to list the Secrets
+// of the namespace. The operator logs are only included if the caller is also allowed to get them.
+func (h *Handler) getBundle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	namespace := r.PathValue("namespace")
+	log := ulog.FromContext(ctx).WithValues("namespace", namespace)
+
+	user, err := rbac.AuthenticateRequest(ctx, h.clientset, r)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, err)
+		return
+	}
+	allowed, err := rbac.UserAllowed(ctx, h.clientset, user, authorizationv1.ResourceAttributes{
+		Namespace: namespace,
+		Verb:      "list",
+		Resource:  "secrets",
+	})
+	if err != nil {
+		log.Error(err, "Failed to review the access to the diagnostics", "user", user.Username)
+		writeError(w, http.StatusInternalServerError, errors.New("failed to review access"))
+		return
+	}
+	if !allowed {
+		writeError(w, http.StatusForbidden, fmt.Errorf("user %s cannot list secrets in namespace %s", user.Username, namespace))
+		return
+	}
+
+	bundle, err := h.bundle(ctx, user, namespace, selectedClusters(r))
+	if err != nil {
+		log.Error(err, "Failed to collect the diagnostics")
+		writeError(w, http.StatusInternalServerError, errors.New("failed to collect the diagnostics"))
+		return
+	}
+	log.V(1).Info("Serving diagnostics", "user", user.Username)
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="eck-diagnostics-%s-%s.zip"`, namespace, h.now().UTC().Format("20060102-150405")))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(bundle)
+}
+
+// selectedClusters returns the names of the Elasticsearch clusters selected with the elasticsearch query parameter,
+// which can be repeated or hold a comma separated list. All the clusters are selected if empty.
+func selectedClusters(r *http.Request) map[string]bool {
+	selected := map[string]bool{}
+	for _, value := range r.URL.Query()["elasticsearch"] {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				selected[name] = true
+			}
+		}
+	}
+	return selected
+}
+
+// bundle collects the diagnostics of the given namespace in a zip archive.
+func (h *Handler) bundle(ctx context.Context, user authenticationv1.UserInfo, namespace string, clusters map[string]bool) ([]byte, error) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+
+	for _, gvk := range resourceKinds {
+		var list unstructured.UnstructuredList
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := h.client.List(ctx, &list, client.InNamespace(namespace)); err != nil {
+			return nil, fmt.Errorf("failed to list %s resources: %w", gvk.Kind, err)
+		}
+		if len(list.Items) == 0 {
+			continue
+		}
+		if err := writeYAML(archive, "resources/"+strings.ToLower(gvk.Kind)+".yaml", list.Items); err != nil {
+			return nil, err
+		}
+	}
+
+	var secrets corev1.SecretList
+	if err := h.client.List(ctx, &secrets, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	if err := writeYAML(archive, "secrets.yaml", redactSecrets(secrets.Items)); err != nil {
+		return nil, err
+	}
+
+	var events corev1.EventList
+	if err := h.client.List(ctx, &events, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	if err := writeYAML(archive, "events.yaml", events.Items); err != nil {
+		return nil, err
+	}
+
+	if err := h.writeElasticsearchDiagnostics(ctx, archive, namespace, clusters); err != nil {
+		return nil, err
+	}
+	if err := h.writeOperatorLogs(ctx, archive, user); err != nil {
+		return nil, err
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// redactSecrets returns the metadata of the given Secrets, without their data.
+func redactSecrets(secrets []corev1.Secret) []SecretMetadata {
+	result := make([]SecretMetadata, 0, len(secrets))
+	for _, secret := range secrets {
+		metadata := SecretMetadata{
+			Name:        secret.Name,
+			Type:        secret.Type,
+			Labels:      secret.Labels,
+			Annotations: secret.Annotations,
+		}
+		if _, exists := secret.Annotations[lastAppliedConfigAnnotation]; exists {
+			metadata.Annotations = make(map[string]string, len(secret.Annotations))
+			for k, v := range secret.Annotations {
+				metadata.Annotations[k] = v
+			}
+			metadata.Annotations[lastAppliedConfigAnnotation] = redacted
+		}
+		for _, owner := range secret.OwnerReferences {
+			metadata.Owners = append(metadata.Owners, owner.Kind+"/"+owner.Name)
+		}
+		if len(secret.Data) > 0 {
+			metadata.Keys = make(map[string]int, len(secret.Data))
+			for k, v := range secret.Data {
+				metadata.Keys[k] = len(v)
+			}
+		}
+		result = append(result, metadata)
+	}
+	return result
+}
+
+// writeElasticsearchDiagnostics writes the responses of the Elasticsearch APIs for the selected clusters of the given
+// namespace. Failures to reach Elasticsearch are recorded in the bundle rather than returned.
+func (h *Handler) writeElasticsearchDiagnostics(ctx context.Context, archive *zip.Writer, namespace string, clusters map[string]bool) error {
+	var esList esv1.ElasticsearchList
+	if err := h.client.List(ctx, &esList, client.InNamespace(namespace)); err != nil {
+		return err
+	}
+	for _, es := range esList.Items {
+		if len(clusters) > 0 && !clusters[es.Name] {
+			continue
+		}
+		dir := "elasticsearch/" + es.Name + "/"
+		if !services.NewElasticsearchURLProvider(es, h.client).HasEndpoints() {
+			if err := writeFile(archive, dir+"error.txt", []byte("Elasticsearch is not ready yet\n")); err != nil {
+				return err
+			}
+			continue
+		}
+		esClient, err := h.esClientProvider(ctx, h.client, h.dialer, es)
+		if err != nil {
+			if err := writeFile(archive, dir+"error.txt", []byte(err.Error()+"\n")); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, request := range elasticsearchRequests {
+			file := request.file
+			body, err := elasticsearchRequest(ctx, esClient, request.path)
+			if err != nil {
+				file = strings.TrimSuffix(file, ".json") + ".error.txt"
+				body = []byte(err.Error() + "\n")
+			}
+			if err := writeFile(archive, dir+file, body); err != nil {
+				esClient.Close()
+				return err
+			}
+		}
+		esClient.Close()
+	}
+	return nil
+}
+
+// elasticsearchRequest sends a GET request to the given Elasticsearch API and returns the body of the response.
+func elasticsearchRequest(ctx context.Context, esClient esclient.Client, path string) ([]byte, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, path, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	response, err := esClient.Request(ctx, request)
+	if response != nil && response.Body != nil {
+		defer response.Body.Close()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(response.Body)
+}
+
+// writeOperatorLogs writes the logs of the operator Pod, if the given user is allowed to get them.
+func (h *Handler) writeOperatorLogs(ctx context.Context, archive *zip.Writer, user authenticationv1.UserInfo) error {
+	if h.operatorPod == "" {
+		return nil
+	}
+	allowed, err := rbac.UserAllowed(ctx, h.clientset, user, authorizationv1.ResourceAttributes{
+		Namespace:   h.operatorNamespace,
+		Verb:        "get",
+		Resource:    "pods",
+		Subresource: "log",
+		Name:        h.operatorPod,
+	})
+	if err != nil {
+		return err
+	}
+	file := "operator/" + h.operatorPod + ".log"
+	if !allowed {
+		return writeFile(archive, file, []byte(fmt.Sprintf("user %s cannot get the logs of the operator\n", user.Username)))
+	}
+	logs, err := h.clientset.CoreV1().Pods(h.operatorNamespace).
+		GetLogs(h.operatorPod, &corev1.PodLogOptions{TailLines: ptr.To[int64](operatorLogsTailLines)}).
+		DoRaw(ctx)
+	if err != nil {
+		logs = []byte(fmt.Sprintf("failed to get the logs of the operator: %s\n", err))
+	}
+	return writeFile(archive, file, logs)
+}
+
+func writeYAML(archive *zip.Writer, name string, obj interface{}) error {
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	return writeFile(archive, name, data)
+}
+
+func writeFile(archive *zip.Writer, name string, data []byte) error {
+	w, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
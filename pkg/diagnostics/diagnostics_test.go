@@ -0,0 +1,235 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package diagnostics
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+const (
+	adminUser  = "system:serviceaccount:apps:admin"
+	viewerUser = "system:serviceaccount:apps:viewer"
+)
+
+type fakeESClient struct {
+	esclient.Client
+}
+
+func (f *fakeESClient) Request(_ context.Context, r *http.Request) (*http.Response, error) {
+	if r.URL.Path == "/_cluster/health" {
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"status":"green"}`))}, nil
+	}
+	return nil, errors.New("unable to find any unassigned shards to explain")
+}
+
+func (f *fakeESClient) Close() {}
+
+// fakeClientset authenticates the "admin" and "viewer" tokens. Both users can list the Secrets of the "ns" namespace,
+// only the admin user can get the logs of the operator.
+func fakeClientset() *fake.Clientset {
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().DeepCopyObject().(*authenticationv1.TokenReview) //nolint:forcetypeassert
+		switch review.Spec.Token {
+		case "admin":
+			review.Status.Authenticated = true
+			review.Status.User = authenticationv1.UserInfo{Username: adminUser}
+		case "viewer":
+			review.Status.Authenticated = true
+			review.Status.User = authenticationv1.UserInfo{Username: viewerUser}
+		}
+		return true, review, nil
+	})
+	clientset.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().DeepCopyObject().(*authorizationv1.SubjectAccessReview) //nolint:forcetypeassert
+		attributes := review.Spec.ResourceAttributes
+		switch {
+		case attributes.Resource == "secrets" && attributes.Verb == "list" && attributes.Namespace == "ns":
+			review.Status.Allowed = true
+		case attributes.Resource == "pods" && attributes.Subresource == "log" && attributes.Namespace == "elastic-system":
+			review.Status.Allowed = review.Spec.User == adminUser
+		}
+		return true, review, nil
+	})
+	return clientset
+}
+
+func readBundle(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	files := make(map[string]string, len(archive.File))
+	for _, file := range archive.File {
+		f, err := file.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(f)
+		require.NoError(t, err)
+		f.Close()
+		files[file.Name] = string(content)
+	}
+	return files
+}
+
+func TestHandler(t *testing.T) {
+	objects := []crclient.Object{
+		&esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}},
+		&esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "not-ready"}},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es-es-default-0", Labels: map[string]string{label.ClusterNameLabelName: "es"}},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+		&kbv1.Kibana{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb"}},
+		&kbv1.Kibana{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "kb-other-ns"}},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es-es-elastic-user"},
+			Data:       map[string][]byte{"elastic": []byte("s3cr3t")},
+		},
+		&corev1.Event{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "event"}, Reason: "Unhealthy"},
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		token      string
+		wantStatus int
+		wantFiles  []string
+	}{
+		{
+			name:       "missing token",
+			path:       "/diagnostics/v1/namespaces/ns",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "not allowed",
+			path:       "/diagnostics/v1/namespaces/other",
+			token:      "admin",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "all clusters",
+			path:       "/diagnostics/v1/namespaces/ns",
+			token:      "admin",
+			wantStatus: http.StatusOK,
+			wantFiles: []string{
+				"resources/elasticsearch.yaml",
+				"resources/kibana.yaml",
+				"secrets.yaml",
+				"events.yaml",
+				"elasticsearch/es/cluster-health.json",
+				"elasticsearch/es/allocation-explain.error.txt",
+				"elasticsearch/not-ready/error.txt",
+				"operator/elastic-operator-0.log",
+			},
+		},
+		{
+			name:       "selected cluster without access to the operator logs",
+			path:       "/diagnostics/v1/namespaces/ns?elasticsearch=es",
+			token:      "viewer",
+			wantStatus: http.StatusOK,
+			wantFiles: []string{
+				"resources/elasticsearch.yaml",
+				"resources/kibana.yaml",
+				"secrets.yaml",
+				"events.yaml",
+				"elasticsearch/es/cluster-health.json",
+				"elasticsearch/es/allocation-explain.error.txt",
+				"operator/elastic-operator-0.log",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(k8s.NewFakeClient(objects...), fakeClientset(), nil, "elastic-system", "elastic-operator-0")
+			h.esClientProvider = func(_ context.Context, _ k8s.Client, _ net.Dialer, _ esv1.Elasticsearch) (esclient.Client, error) {
+				return &fakeESClient{}, nil
+			}
+			h.now = func() time.Time { return time.Date(2024, 11, 14, 10, 0, 0, 0, time.UTC) }
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			if tt.wantFiles == nil {
+				return
+			}
+			require.Equal(t, "application/zip", rec.Header().Get("Content-Type"))
+			require.Equal(t, `attachment; filename="eck-diagnostics-ns-20241114-100000.zip"`, rec.Header().Get("Content-Disposition"))
+
+			files := readBundle(t, rec.Body.Bytes())
+			names := make([]string, 0, len(files))
+			for name := range files {
+				names = append(names, name)
+			}
+			require.ElementsMatch(t, tt.wantFiles, names)
+			require.NotContains(t, files["resources/kibana.yaml"], "kb-other-ns")
+			require.NotContains(t, files["secrets.yaml"], "s3cr3t")
+			require.Contains(t, files["secrets.yaml"], "elastic: 6")
+			require.Contains(t, files["events.yaml"], "reason: Unhealthy")
+			require.Equal(t, `{"status":"green"}`, files["elasticsearch/es/cluster-health.json"])
+			if tt.token == "admin" {
+				require.Equal(t, "fake logs", files["operator/elastic-operator-0.log"])
+			} else {
+				require.Equal(t, "user "+viewerUser+" cannot get the logs of the operator\n", files["operator/elastic-operator-0.log"])
+			}
+		})
+	}
+}
+
+func Test_redactSecrets(t *testing.T) {
+	secrets := []corev1.Secret{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "applied",
+				Annotations:     map[string]string{lastAppliedConfigAnnotation: `{"data":{"password":"czNjcjN0"}}`, "other": "value"},
+				OwnerReferences: []metav1.OwnerReference{{Kind: "Elasticsearch", Name: "es"}},
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: map[string][]byte{"password": []byte("s3cr3t"), "empty": {}},
+		},
+		{ObjectMeta: metav1.ObjectMeta{Name: "no-data"}},
+	}
+	require.Equal(t, []SecretMetadata{
+		{
+			Name:        "applied",
+			Type:        corev1.SecretTypeOpaque,
+			Annotations: map[string]string{lastAppliedConfigAnnotation: redacted, "other": "value"},
+			Owners:      []string{"Elasticsearch/es"},
+			Keys:        map[string]int{"password": 6, "empty": 0},
+		},
+		{Name: "no-data"},
+	}, redactSecrets(secrets))
+	// the original Secrets are left untouched
+	require.Equal(t, `{"data":{"password":"czNjcjN0"}}`, secrets[0].Annotations[lastAppliedConfigAnnotation])
+}
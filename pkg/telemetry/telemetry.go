@@ -472,6 +472,7 @@ type stackConfigPolicyStats struct {
 		IngestPipelinesCount           int `json:"ingest_pipelines_count"`
 		ComponentTemplatesCount        int `json:"component_templates_count"`
 		ComposableIndexTemplatesCount  int `json:"composable_index_templates_count"`
+		AliasesCount                   int `json:"aliases_count"`
 	} `json:"settings"`
 }
 
@@ -510,6 +511,9 @@ func scpStats(k8sClient k8s.Client, managedNamespaces []string) (string, interfa
 			if scp.Spec.Elasticsearch.IndexTemplates.ComposableIndexTemplates != nil {
 				stats.Settings.ComposableIndexTemplatesCount += len(scp.Spec.Elasticsearch.IndexTemplates.ComposableIndexTemplates.Data)
 			}
+			if scp.Spec.Elasticsearch.Aliases != nil {
+				stats.Settings.AliasesCount += len(scp.Spec.Elasticsearch.Aliases.Data)
+			}
 		}
 	}
 	return "stackconfigpolicies", stats, nil
@@ -234,10 +234,10 @@ func TestNewReporter(t *testing.T) {
 			},
 			Spec: logstashv1alpha1.LogstashSpec{
 				Count: 3,
-				Monitoring: commonv1.Monitoring{
+				Monitoring: logstashv1alpha1.LogstashMonitoring{Monitoring: commonv1.Monitoring{
 					Logs:    commonv1.LogsMonitoring{ElasticsearchRefs: []commonv1.ObjectSelector{{Name: "monitoring"}}},
 					Metrics: commonv1.MetricsMonitoring{ElasticsearchRefs: []commonv1.ObjectSelector{{Name: "monitoring"}}},
-				},
+				}},
 				Pipelines: []commonv1.Config{
 					{Data: map[string]interface{}{"pipeline.id": "main"}},
 				},
@@ -498,6 +498,7 @@ func TestNewReporter(t *testing.T) {
       configured_resources_count: 15
       resource_count: 2
       settings:
+        aliases_count: 0
         cluster_settings_count: 1
         component_templates_count: 0
         composable_index_templates_count: 0
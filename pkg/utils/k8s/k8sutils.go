@@ -6,6 +6,7 @@ package k8s
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net"
 
@@ -245,6 +246,25 @@ func DeleteResourceIfExists(ctx context.Context, c Client, obj client.Object) er
 	return nil
 }
 
+// PatchAnnotations sets the given annotations on the given object, a nil value removing the annotation. The object is
+// patched through a copy, and only its annotations and its resource version are updated, so that in-memory changes
+// made to the rest of the object, for example to its spec, are neither persisted nor reverted.
+func PatchAnnotations(ctx context.Context, c Client, obj client.Object, annotations map[string]*string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": annotations},
+	})
+	if err != nil {
+		return err
+	}
+	patched := DeepCopyObject(obj)
+	if err := c.Patch(ctx, patched, client.RawPatch(types.MergePatchType, patch)); err != nil {
+		return err
+	}
+	obj.SetAnnotations(patched.GetAnnotations())
+	obj.SetResourceVersion(patched.GetResourceVersion())
+	return nil
+}
+
 // PodsMatchingLabels returns Pods from the given namespace matching the given labels.
 func PodsMatchingLabels(c Client, namespace string, labels map[string]string) ([]corev1.Pod, error) {
 	var pods corev1.PodList
@@ -5,6 +5,7 @@
 package k8s
 
 import (
+	"context"
 	"net"
 	"reflect"
 	"testing"
@@ -16,6 +17,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	netutil "github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
@@ -283,6 +285,28 @@ func TestObjectExists(t *testing.T) {
 	}
 }
 
+func TestPatchAnnotations(t *testing.T) {
+	ctx := context.Background()
+	c := NewFakeClient(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm", Annotations: map[string]string{"a": "1", "b": "2"}},
+		Data:       map[string]string{"key": "value"},
+	})
+	var cm corev1.ConfigMap
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "cm"}, &cm))
+	// in-memory change which must not be persisted
+	cm.Data["key"] = "changed"
+
+	require.NoError(t, PatchAnnotations(ctx, c, &cm, map[string]*string{"a": nil, "c": ptr.To("3")}))
+	require.Equal(t, map[string]string{"b": "2", "c": "3"}, cm.Annotations)
+	require.Equal(t, "changed", cm.Data["key"])
+
+	var actual corev1.ConfigMap
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "cm"}, &actual))
+	require.Equal(t, map[string]string{"b": "2", "c": "3"}, actual.Annotations)
+	require.Equal(t, "value", actual.Data["key"])
+	require.Equal(t, actual.ResourceVersion, cm.ResourceVersion)
+}
+
 func TestGetSecretEntriesCount(t *testing.T) {
 	secretFixture := corev1.Secret{Data: map[string][]byte{
 		"a": nil,
@@ -53,3 +53,29 @@ func FindOwner(resource, owner metav1.Object) (found bool, index int) {
 	}
 	return false, 0
 }
+
+// IsControlledByPreviousOwner returns true if the controller reference of the given resource has the kind and name of
+// the given owner reference, but a different UID. The reference then points to a previous incarnation of the owner,
+// for example when both resources were restored from a backup and the owner was recreated with a new UID.
+func IsControlledByPreviousOwner(resource metav1.Object, owner metav1.OwnerReference) bool {
+	ref := metav1.GetControllerOfNoCopy(resource)
+	return ref != nil && ref.Kind == owner.Kind && ref.Name == owner.Name && ref.UID != owner.UID
+}
+
+// RemovePreviousOwners removes the references to previous incarnations of the given owner, with the same kind and name
+// but a different UID. It returns true if at least one reference was removed.
+func RemovePreviousOwners(resource, owner metav1.Object, ownerKind string) bool {
+	owners := resource.GetOwnerReferences()
+	kept := make([]metav1.OwnerReference, 0, len(owners))
+	for _, ref := range owners {
+		if ref.Kind == ownerKind && ref.Name == owner.GetName() && ref.UID != owner.GetUID() {
+			continue
+		}
+		kept = append(kept, ref)
+	}
+	if len(kept) == len(owners) {
+		return false
+	}
+	resource.SetOwnerReferences(kept)
+	return true
+}
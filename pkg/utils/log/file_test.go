@@ -0,0 +1,66 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_rotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "operator.log")
+	f, err := newRotatingFile(path, 10)
+	require.NoError(t, err)
+
+	_, err = f.Write([]byte("aaaaaa\n"))
+	require.NoError(t, err)
+	_, err = f.Write([]byte("bbb\n"))
+	require.NoError(t, err)
+
+	// the second line exceeds the maximum size: the first one has been rotated
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "bbb\n", string(content))
+	rotated, err := os.ReadFile(path + rotatedLogFileSuffix)
+	require.NoError(t, err)
+	require.Equal(t, "aaaaaa\n", string(rotated))
+
+	// lines bigger than the maximum size are still written
+	_, err = f.Write([]byte("cccccccccccc\n"))
+	require.NoError(t, err)
+	content, err = os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "cccccccccccc\n", string(content))
+
+	// reopening the file appends to it
+	f, err = newRotatingFile(path, 100)
+	require.NoError(t, err)
+	_, err = f.Write([]byte("dd\n"))
+	require.NoError(t, err)
+	content, err = os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "cccccccccccc\ndd\n", string(content))
+}
+
+func TestSetLogFile(t *testing.T) {
+	defer func() {
+		fileWriter = nil
+		ChangeVerbosity(0)
+	}()
+	path := filepath.Join(t.TempDir(), "operator.log")
+	require.NoError(t, SetLogFile(path))
+	ChangeVerbosity(0)
+	Log.Info("hello")
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(content), `"message":"hello"`)
+
+	require.NoError(t, SetLogFile(""))
+	require.Nil(t, fileWriter)
+}
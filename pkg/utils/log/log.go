@@ -7,6 +7,7 @@ package log
 import (
 	"context"
 	"flag"
+	"io"
 	"os"
 	"strconv"
 
@@ -28,6 +29,7 @@ const (
 	EcsVersion     = "1.4.0"
 	EcsServiceType = "eck"
 	FlagName       = "log-verbosity"
+	LogFileFlag    = "log-file"
 
 	testLogLevelEnvVar = "ECK_TEST_LOG_LEVEL"
 )
@@ -43,11 +45,37 @@ func init() {
 	}
 }
 
-var verbosity = flag.Int(FlagName, 0, "Verbosity level of logs (-2=Error, -1=Warn, 0=Info, >0=Debug)")
+var (
+	verbosity = flag.Int(FlagName, 0, "Verbosity level of logs (-2=Error, -1=Warn, 0=Info, >0=Debug)")
+	_         = flag.String(LogFileFlag, "", "Path to a file where logs are written in addition to the standard error output, rotated when exceeding 10MB")
+
+	// fileWriter is the optional log file logs are written to in addition to the standard error output.
+	fileWriter io.Writer
+)
 
 // BindFlags attaches logging flags to the given flag set.
 func BindFlags(flags *pflag.FlagSet) {
 	flags.AddGoFlag(flag.Lookup("log-verbosity"))
+	flags.AddGoFlag(flag.Lookup(LogFileFlag))
+}
+
+// SetLogFile configures the logger to also write logs to the file at the given path, for example for a log shipper
+// running in a sidecar container. An empty path disables writing logs to a file.
+// It must be called before the logger is initialized or its verbosity is changed to take effect.
+func SetLogFile(path string) error {
+	if path == "" {
+		fileWriter = nil
+		return nil
+	}
+	if f, ok := fileWriter.(*rotatingFile); ok && f.path == path {
+		return nil
+	}
+	f, err := newRotatingFile(path, maxLogFileSize)
+	if err != nil {
+		return err
+	}
+	fileWriter = f
+	return nil
 }
 
 // InitLogger initializes the global logger informed by the value of log-verbosity flag.
@@ -106,9 +134,14 @@ func setLogger(v *int) {
 			))
 	}
 
+	var dest io.Writer = os.Stderr
+	if fileWriter != nil {
+		dest = io.MultiWriter(os.Stderr, fileWriter)
+	}
+
 	stackTraceLevel := zap.NewAtomicLevelAt(zapcore.ErrorLevel)
 	crlog.SetLogger(crzap.New(func(o *crzap.Options) {
-		o.DestWriter = os.Stderr
+		o.DestWriter = dest
 		o.Development = dev.Enabled
 		o.Level = &zapLevel
 		o.StacktraceLevel = &stackTraceLevel
@@ -13,13 +13,28 @@ import (
 )
 
 const (
-	namespace          = "elastic"
-	LeaderKey          = "leader"
-	licensingSubsystem = "licensing"
+	namespace           = "elastic"
+	LeaderKey           = "leader"
+	licensingSubsystem  = "licensing"
+	esSubsystem         = "elasticsearch"
+	reconcilerSubsystem = "reconciler"
 
 	LicenseLevelLabel      = "license_level"
 	OperatorNamespaceLabel = "operator_namespace"
 	UUIDLabel              = "uuid"
+	NamespaceLabel         = "namespace"
+	NameLabel              = "name"
+	SnapshotPolicyLabel    = "policy"
+	HealthProbeLabel       = "probe"
+	KindLabel              = "kind"
+	VersionLabel           = "version"
+	HealthLabel            = "health"
+	PhaseLabel             = "phase"
+	VerbLabel              = "verb"
+	ControllerLabel        = "controller"
+	CertificateLabel       = "certificate"
+	LicenseTypeLabel       = "license_type"
+	LicenseStatusLabel     = "license_status"
 )
 
 var (
@@ -92,6 +107,126 @@ var (
 		Name:      "memory_gibibytes_logstash",
 		Help:      "Memory used by Logstash in GiB",
 	}, []string{LicenseLevelLabel}))
+
+	// ResourceInfoGauge reports the version, health and phase of each Elastic resource managed by the operator.
+	ResourceInfoGauge = registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "resource_info",
+		Help:      "Information about an Elastic resource managed by the operator, the value is always 1",
+	}, []string{NamespaceLabel, NameLabel, KindLabel, VersionLabel, HealthLabel, PhaseLabel}))
+
+	// SnapshotVerifiedGauge reports whether the latest snapshot of an Elasticsearch SLM policy was verified successfully.
+	SnapshotVerifiedGauge = registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: esSubsystem,
+		Name:      "snapshot_verified",
+		Help:      "Whether the latest snapshot of the snapshot lifecycle management policy was verified successfully (1) or not (0)",
+	}, []string{NamespaceLabel, NameLabel, SnapshotPolicyLabel}))
+
+	// SnapshotVerificationTimestampGauge reports the time of the last snapshot verification of an Elasticsearch cluster.
+	SnapshotVerificationTimestampGauge = registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: esSubsystem,
+		Name:      "snapshot_verification_timestamp_seconds",
+		Help:      "Time of the last snapshot verification, in seconds since epoch",
+	}, []string{NamespaceLabel, NameLabel}))
+
+	// HealthProbeSucceededGauge reports whether the last run of a health probe of an Elasticsearch cluster succeeded.
+	HealthProbeSucceededGauge = registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: esSubsystem,
+		Name:      "health_probe_succeeded",
+		Help:      "Whether the last run of the health probe succeeded (1) or not (0)",
+	}, []string{NamespaceLabel, NameLabel, HealthProbeLabel}))
+
+	// HealthProbeDurationGauge reports the duration of the last run of a health probe of an Elasticsearch cluster.
+	HealthProbeDurationGauge = registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: esSubsystem,
+		Name:      "health_probe_duration_seconds",
+		Help:      "Duration of the last run of the health probe in seconds",
+	}, []string{NamespaceLabel, NameLabel, HealthProbeLabel}))
+
+	// LastSuccessfulReconcileGauge reports the time of the last reconciliation of a resource which did not return an error.
+	LastSuccessfulReconcileGauge = registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: reconcilerSubsystem,
+		Name:      "last_success_timestamp_seconds",
+		Help:      "Time of the last successful reconciliation of the resource, in seconds since epoch",
+	}, []string{ControllerLabel, NamespaceLabel, NameLabel}))
+
+	// UpgradeInProgressGauge reports whether the nodes of a resource are being upgraded by the operator.
+	UpgradeInProgressGauge = registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "upgrade_in_progress",
+		Help:      "Whether the nodes of the resource are being upgraded by the operator (1) or not (0)",
+	}, []string{NamespaceLabel, NameLabel, KindLabel}))
+
+	// CertificateExpiryGauge reports the expiration time of the certificates managed by the operator for a resource.
+	CertificateExpiryGauge = registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "certificate_expiry_timestamp_seconds",
+		Help:      "Expiration time of the certificate used by the resource, in seconds since epoch",
+	}, []string{NamespaceLabel, NameLabel, KindLabel, CertificateLabel}))
+
+	// LicenseExpiryGauge reports the expiration time of the license applied to an Elasticsearch cluster.
+	LicenseExpiryGauge = registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: esSubsystem,
+		Name:      "license_expiry_timestamp_seconds",
+		Help:      "Expiration time of the license applied to the Elasticsearch cluster, in seconds since epoch",
+	}, []string{NamespaceLabel, NameLabel}))
+
+	// LicenseInfoGauge reports the type and status of the license applied to an Elasticsearch cluster.
+	LicenseInfoGauge = registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: esSubsystem,
+		Name:      "license_info",
+		Help:      "Type and status of the license applied to the Elasticsearch cluster, always 1",
+	}, []string{NamespaceLabel, NameLabel, LicenseTypeLabel, LicenseStatusLabel}))
+
+	// SafeModeGauge reports whether the operator runs in safe mode.
+	SafeModeGauge = registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "safe_mode",
+		Help:      "Whether the operator runs in safe mode (1) or not (0)",
+	}, nil))
+)
+
+var (
+	// ReconcileCacheHits counts the reconciliations of resources skipped because the resources were known to be up to date.
+	ReconcileCacheHits = registerCounter(prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: reconcilerSubsystem,
+		Name:      "cache_hits_total",
+		Help:      "Number of reconciliations of resources skipped because the resources were already up to date",
+	}, []string{KindLabel}))
+
+	// ReconcileCacheMisses counts the reconciliations of resources which required a comparison with the expected state.
+	ReconcileCacheMisses = registerCounter(prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: reconcilerSubsystem,
+		Name:      "cache_misses_total",
+		Help:      "Number of reconciliations of resources which required a comparison with the expected state",
+	}, []string{KindLabel}))
+
+	// SafeModeSkippedRequests counts the requests which were not performed because the operator runs in safe mode.
+	SafeModeSkippedRequests = registerCounter(prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "safe_mode_skipped_requests_total",
+		Help:      "Number of mutating requests skipped because the operator runs in safe mode",
+	}, []string{VerbLabel, KindLabel}))
+)
+
+var (
+	// ReconcileDurationHistogram reports the duration of the reconciliations of each resource.
+	ReconcileDurationHistogram = registerHistogram(prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: reconcilerSubsystem,
+		Name:      "reconcile_duration_seconds",
+		Help:      "Duration of the reconciliations of the resource in seconds",
+		Buckets:   []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1.0, 2.5, 5.0, 10.0, 30.0, 60.0, 120.0, 300.0},
+	}, []string{ControllerLabel, NamespaceLabel, NameLabel}))
 )
 
 func registerGauge(gauge *prometheus.GaugeVec) *prometheus.GaugeVec {
@@ -107,3 +242,31 @@ func registerGauge(gauge *prometheus.GaugeVec) *prometheus.GaugeVec {
 
 	return gauge
 }
+
+func registerCounter(counter *prometheus.CounterVec) *prometheus.CounterVec {
+	err := crmetrics.Registry.Register(counter)
+	if err != nil {
+		existsErr := new(prometheus.AlreadyRegisteredError)
+		if errors.As(err, &existsErr) {
+			return existsErr.ExistingCollector.(*prometheus.CounterVec) //nolint:forcetypeassert
+		}
+
+		panic(fmt.Errorf("failed to register counter: %w", err))
+	}
+
+	return counter
+}
+
+func registerHistogram(histogram *prometheus.HistogramVec) *prometheus.HistogramVec {
+	err := crmetrics.Registry.Register(histogram)
+	if err != nil {
+		existsErr := new(prometheus.AlreadyRegisteredError)
+		if errors.As(err, &existsErr) {
+			return existsErr.ExistingCollector.(*prometheus.HistogramVec) //nolint:forcetypeassert
+		}
+
+		panic(fmt.Errorf("failed to register histogram: %w", err))
+	}
+
+	return histogram
+}
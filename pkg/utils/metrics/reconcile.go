@@ -0,0 +1,69 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package metrics
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type reconciliationKey struct{}
+
+// reconciliation tracks whether the reconciled resource was deleted during the reconciliation.
+type reconciliation struct {
+	forgotten atomic.Bool
+}
+
+// instrumentedReconciler reports the duration and the time of the last successful reconciliation of each resource.
+type instrumentedReconciler struct {
+	controllerName string
+	reconcile.Reconciler
+}
+
+// InstrumentReconciler returns a reconciler reporting per-resource metrics about the reconciliations of r. The metrics
+// of a resource are removed once ForgetReconciledResource has been called during its reconciliation.
+func InstrumentReconciler(controllerName string, r reconcile.Reconciler) reconcile.Reconciler {
+	return instrumentedReconciler{controllerName: controllerName, Reconciler: r}
+}
+
+func (r instrumentedReconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	state := &reconciliation{}
+	start := time.Now()
+	result, err := r.Reconciler.Reconcile(context.WithValue(ctx, reconciliationKey{}, state), request)
+
+	labels := prometheus.Labels{ControllerLabel: r.controllerName, NamespaceLabel: request.Namespace, NameLabel: request.Name}
+	if state.forgotten.Load() {
+		ReconcileDurationHistogram.Delete(labels)
+		LastSuccessfulReconcileGauge.Delete(labels)
+		return result, err
+	}
+	ReconcileDurationHistogram.With(labels).Observe(time.Since(start).Seconds())
+	if err == nil {
+		LastSuccessfulReconcileGauge.With(labels).SetToCurrentTime()
+	}
+	return result, err
+}
+
+// ForgetReconciledResource stops reporting the reconciliation metrics of the resource being reconciled, to be called
+// once the resource has been deleted. It is a no-op if the reconciler is not instrumented.
+func ForgetReconciledResource(ctx context.Context) {
+	if state, ok := ctx.Value(reconciliationKey{}).(*reconciliation); ok {
+		state.forgotten.Store(true)
+	}
+}
+
+// DeleteResourceMetrics deletes all the metrics reported for a deleted resource of the given kind, including the
+// reconciliation metrics if called during the reconciliation of the resource.
+func DeleteResourceMetrics(ctx context.Context, kind string, resource types.NamespacedName) {
+	ForgetReconciledResource(ctx)
+	labels := prometheus.Labels{NamespaceLabel: resource.Namespace, NameLabel: resource.Name, KindLabel: kind}
+	CertificateExpiryGauge.DeletePartialMatch(labels)
+	UpgradeInProgressGauge.DeletePartialMatch(labels)
+}
@@ -0,0 +1,55 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestInstrumentReconciler(t *testing.T) {
+	var (
+		reconcileErr error
+		deleted      bool
+	)
+	r := InstrumentReconciler("test-controller", reconcile.Func(func(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+		if deleted {
+			DeleteResourceMetrics(ctx, "Test", request.NamespacedName)
+		}
+		return reconcile.Result{}, reconcileErr
+	}))
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "test"}}
+	countSeries := func() int {
+		return testutil.CollectAndCount(ReconcileDurationHistogram) + testutil.CollectAndCount(LastSuccessfulReconcileGauge)
+	}
+
+	// a failed reconciliation is observed but not reported as successful
+	reconcileErr = errors.New("failure")
+	_, err := r.Reconcile(context.Background(), request)
+	require.Error(t, err)
+	require.Equal(t, 1, testutil.CollectAndCount(ReconcileDurationHistogram))
+	require.Equal(t, 0, testutil.CollectAndCount(LastSuccessfulReconcileGauge))
+
+	// a successful reconciliation is reported
+	reconcileErr = nil
+	_, err = r.Reconcile(context.Background(), request)
+	require.NoError(t, err)
+	require.Equal(t, 2, countSeries())
+	require.NotZero(t, testutil.ToFloat64(LastSuccessfulReconcileGauge.WithLabelValues("test-controller", "ns", "test")))
+
+	// the metrics are deleted with the resource
+	CertificateExpiryGauge.WithLabelValues("ns", "test", "Test", "http").Set(1)
+	deleted = true
+	_, err = r.Reconcile(context.Background(), request)
+	require.NoError(t, err)
+	require.Equal(t, 0, countSeries())
+	require.Equal(t, 0, testutil.CollectAndCount(CertificateExpiryGauge))
+}
@@ -0,0 +1,53 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package rbac
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	authorizationapi "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// secretsVerbs are the verbs the operator needs on Secrets in every managed namespace.
+var secretsVerbs = []string{"get", "list", "watch", "create", "update", "patch", "delete"}
+
+// CheckSecretsAccess verifies that the operator is allowed to manage Secrets in each of the given namespaces, or
+// in all namespaces if none is given. This detects early a missing namespaced Role when access to Secrets is not
+// granted cluster-wide but on a per managed namespace basis.
+func CheckSecretsAccess(ctx context.Context, client kubernetes.Interface, namespaces []string) error {
+	if len(namespaces) == 0 {
+		// all namespaces
+		namespaces = []string{metav1.NamespaceAll}
+	}
+	var missing []string
+	for _, ns := range namespaces {
+		for _, verb := range secretsVerbs {
+			review := &authorizationapi.SelfSubjectAccessReview{
+				Spec: authorizationapi.SelfSubjectAccessReviewSpec{
+					ResourceAttributes: &authorizationapi.ResourceAttributes{
+						Namespace: ns,
+						Verb:      verb,
+						Resource:  "secrets",
+					},
+				},
+			}
+			review, err := client.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+			if err != nil {
+				return err
+			}
+			if !review.Status.Allowed || review.Status.Denied {
+				missing = append(missing, fmt.Sprintf("%s secrets in namespace %q", verb, ns))
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("operator is not allowed to %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
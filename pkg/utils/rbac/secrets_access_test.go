@@ -0,0 +1,93 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package rbac
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	authorizationapi "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// fakeSelfSubjectAccessReviews returns a client allowing access to Secrets in the given namespaces only.
+func fakeSelfSubjectAccessReviews(allowedNamespaces ...string) *fake.Clientset {
+	allowed := map[string]bool{}
+	for _, ns := range allowedNamespaces {
+		allowed[ns] = true
+	}
+	fakeClient := fake.NewSimpleClientset()
+	fakeClient.PrependReactor(
+		"create",
+		"selfsubjectaccessreviews",
+		func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+			object := action.(k8stesting.CreateAction).GetObject().DeepCopyObject() //nolint:forcetypeassert
+			if review, ok := object.(*authorizationapi.SelfSubjectAccessReview); ok {
+				review.Status.Allowed = allowed[review.Spec.ResourceAttributes.Namespace]
+			}
+			return true, object, nil
+		},
+	)
+	return fakeClient
+}
+
+func TestCheckSecretsAccess(t *testing.T) {
+	tests := []struct {
+		name         string
+		client       *fake.Clientset
+		namespaces   []string
+		wantErr      bool
+		wantContains string
+	}{
+		{
+			name:       "allowed in all managed namespaces",
+			client:     fakeSelfSubjectAccessReviews("ns-a", "ns-b"),
+			namespaces: []string{"ns-a", "ns-b"},
+		},
+		{
+			name:         "missing access in one managed namespace",
+			client:       fakeSelfSubjectAccessReviews("ns-a"),
+			namespaces:   []string{"ns-a", "ns-b"},
+			wantErr:      true,
+			wantContains: `get secrets in namespace "ns-b"`,
+		},
+		{
+			name:   "allowed cluster-wide",
+			client: fakeSelfSubjectAccessReviews(""),
+		},
+		{
+			name:    "missing cluster-wide access",
+			client:  fakeSelfSubjectAccessReviews("ns-a"),
+			wantErr: true,
+		},
+		{
+			name: "access review error",
+			client: func() *fake.Clientset {
+				c := fake.NewSimpleClientset()
+				c.PrependReactor("create", "selfsubjectaccessreviews", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+					return true, nil, errors.New("boom")
+				})
+				return c
+			}(),
+			namespaces: []string{"ns-a"},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckSecretsAccess(context.Background(), tt.client, tt.namespaces)
+			if !tt.wantErr {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.wantContains)
+		})
+	}
+}
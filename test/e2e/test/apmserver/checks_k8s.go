@@ -7,6 +7,7 @@ package apmserver
 import (
 	"context"
 	"fmt"
+	"reflect"
 
 	apmv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/apm/v1"
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
@@ -142,7 +143,7 @@ func CheckStatus(b Builder, k *test.K8sClient) test.Step {
 					Health:         "green",
 				},
 			}
-			if as.Status != expected {
+			if !reflect.DeepEqual(as.Status, expected) {
 				return fmt.Errorf("expected status %+v but got %+v", expected, as.Status)
 			}
 			return nil
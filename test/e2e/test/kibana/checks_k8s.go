@@ -7,6 +7,7 @@ package kibana
 import (
 	"context"
 	"fmt"
+	"reflect"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
@@ -155,7 +156,7 @@ func CheckStatus(b Builder, k *test.K8sClient) test.Step {
 					Health:         "green",
 				},
 			}
-			if kb.Status.DeploymentStatus != expected.DeploymentStatus {
+			if !reflect.DeepEqual(kb.Status.DeploymentStatus, expected.DeploymentStatus) {
 				return fmt.Errorf("expected status %+v but got %+v", expected, kb.Status)
 			}
 			return nil
@@ -8,6 +8,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/apis/maps/v1alpha1"
@@ -120,7 +121,7 @@ func CheckStatus(b Builder, k *test.K8sClient) test.Step {
 				},
 				AssociationStatus: "",
 			}
-			if ems.Status != expected {
+			if !reflect.DeepEqual(ems.Status, expected) {
 				return fmt.Errorf("expected status %+v but got %+v", expected, ems.Status)
 			}
 			return nil